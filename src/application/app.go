@@ -7,12 +7,14 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"agent-collab/src/domain/agent"
 	"agent-collab/src/domain/ctxsync"
 	"agent-collab/src/domain/event"
 	"agent-collab/src/domain/interest"
 	"agent-collab/src/domain/lock"
+	"agent-collab/src/domain/presence"
 	"agent-collab/src/domain/token"
 	"agent-collab/src/infrastructure/crypto"
 	"agent-collab/src/infrastructure/embedding"
@@ -52,14 +54,46 @@ type App struct {
 	syncManager   *ctxsync.SyncManager
 	tokenTracker  *token.Tracker
 	agentRegistry *agent.Registry
+	presence      *presence.Tracker
 
 	// Global cluster services
 	interestMgr *interest.Manager
 	eventRouter *event.Router
 	eventBridge *libp2p.EventBridge
 
+	// Callbacks
+	onForceRelease   func(*lock.ForceReleaseNotice)
+	onLockIdle       func(*lock.IdleNotice)
+	onLockRenewal    func(*lock.RenewalNotice)
+	onPartition      func(*lock.PartitionEvent)
+	onLockReconciled func(*lock.ReconcileResult)
+	onBudgetExceeded func(*token.BudgetExceeded)
+	onConfigReloaded func(*ReloadResult)
+	onPeerBanned     func(*libp2p.Ban)
+
+	// Message processing latency
+	procMetrics *ProcessingMetrics
+
+	// Recent context shares, for duplicate suppression
+	shares *shareDedup
+	// Causal ordering of context shares
+	causal *causalShares
+	// Serializes relevance feedback read-modify-writes
+	feedbackMu sync.Mutex
+	// Serializes writes of the ban list
+	bansMu sync.Mutex
+	// Last successful vector store probe
+	vectorProbes probeHistory
+	// Locks restored at load, announced to peers once started
+	restoredLocks []*lock.SemanticLock
+	// Context shard topics subscribed for this node's interests
+	contextShards *contextShards
+	// Token pricing sources merged into the tracker's prices
+	pricing *tokenPricing
+
 	// State
 	running bool
+	closed  bool
 	ctx     context.Context
 	cancel  context.CancelFunc
 }
@@ -79,8 +113,14 @@ func New(cfg *Config) (*App, error) {
 	logger := logging.New(os.Stdout, "info").Component("app")
 
 	return &App{
-		config: cfg,
-		logger: logger,
+		config:      cfg,
+		logger:      logger,
+		procMetrics: NewProcessingMetrics(),
+		shares:      newShareDedup(),
+		causal:      newCausalShares(),
+
+		contextShards: newContextShards(),
+		pricing:       newTokenPricing(),
 	}, nil
 }
 
@@ -110,6 +150,17 @@ func (a *App) InitializeWithOptions(ctx context.Context, opts *InitializeOptions
 	}
 	a.keyPair = keyPair
 
+	// The creator is the cluster's root and grants itself admin
+	a.config.ClusterRoot = keyPair.PeerID.String()
+	if a.config.Role == "" && !a.config.Observer {
+		a.config.Role = string(RoleAdmin)
+	}
+	if a.config.Role == string(RoleAdmin) {
+		if a.config.RoleCredential, err = crypto.IssueRoleGrant(keyPair, opts.ProjectName, string(RoleAdmin)); err != nil {
+			return nil, err
+		}
+	}
+
 	// 키 저장
 	keyPath := filepath.Join(a.config.DataDir, "key.json")
 	if err := crypto.SaveKeyPair(keyPair, keyPath); err != nil {
@@ -128,6 +179,23 @@ func (a *App) InitializeWithOptions(ctx context.Context, opts *InitializeOptions
 	// 3. libp2p 노드 생성 (global cluster - no projectID)
 	nodeConfig := libp2p.DefaultConfig()
 	nodeConfig.PrivateKey = keyPair.PrivateKey
+	nodeConfig.CompressionThreshold = a.config.CompressionThreshold
+	if nodeConfig.PEXConfig, err = a.config.PEXConfig(); err != nil {
+		return nil, err
+	}
+	if nodeConfig.NATConfig, err = a.config.NATConfig(); err != nil {
+		return nil, err
+	}
+	if nodeConfig.BanConfig, err = a.config.BanConfig(); err != nil {
+		return nil, err
+	}
+	if nodeConfig.GossipThrottle, err = a.config.GossipThrottleConfig(); err != nil {
+		return nil, err
+	}
+	if nodeConfig.LockPush, err = a.config.LockPushConfig(); err != nil {
+		return nil, err
+	}
+	nodeConfig.LocalityConfig = a.config.LocalityConfig()
 
 	node, err := libp2p.NewNode(ctx, nodeConfig)
 	if err != nil {
@@ -139,6 +207,7 @@ func (a *App) InitializeWithOptions(ctx context.Context, opts *InitializeOptions
 	nodeIDStr := a.node.ID().String()
 	a.lockService = lock.NewLockService(ctx, nodeIDStr, opts.ProjectName+"-agent")
 	a.syncManager = ctxsync.NewSyncManager(nodeIDStr, opts.ProjectName+"-agent")
+	a.applyObserverMode()
 
 	// 5. Initialize Phase 3 components
 	if err := a.initPhase3Components(nodeIDStr, opts.ProjectName+"-agent"); err != nil {
@@ -206,8 +275,9 @@ func (a *App) initializeWireGuard(ctx context.Context, opts *InitializeOptions)
 	bootstrapper := NewWireGuardBootstrapper(a.config.DataDir, a.config.WireGuard, a.logger)
 
 	result, err := bootstrapper.Bootstrap(ctx, &BootstrapOptions{
-		ListenPort: opts.WireGuardPort,
-		Subnet:     opts.Subnet,
+		ListenPort:    opts.WireGuardPort,
+		Subnet:        opts.Subnet,
+		ExpectedPeers: opts.ExpectedPeers,
 	})
 	if err != nil {
 		return nil, err
@@ -264,6 +334,23 @@ func (a *App) LoadFromConfig(ctx context.Context) error {
 	// Create libp2p node with saved listen addresses (global cluster - no projectID)
 	nodeConfig := libp2p.DefaultConfig()
 	nodeConfig.PrivateKey = keyPair.PrivateKey
+	nodeConfig.CompressionThreshold = a.config.CompressionThreshold
+	if nodeConfig.PEXConfig, err = a.config.PEXConfig(); err != nil {
+		return err
+	}
+	if nodeConfig.NATConfig, err = a.config.NATConfig(); err != nil {
+		return err
+	}
+	if nodeConfig.BanConfig, err = a.config.BanConfig(); err != nil {
+		return err
+	}
+	if nodeConfig.GossipThrottle, err = a.config.GossipThrottleConfig(); err != nil {
+		return err
+	}
+	if nodeConfig.LockPush, err = a.config.LockPushConfig(); err != nil {
+		return err
+	}
+	nodeConfig.LocalityConfig = a.config.LocalityConfig()
 
 	// Use saved listen addresses if available (to keep same ports)
 	if len(a.config.ListenAddrs) > 0 {
@@ -296,6 +383,8 @@ func (a *App) LoadFromConfig(ctx context.Context) error {
 	agentID := a.config.ProjectName + "-agent"
 	a.lockService = lock.NewLockService(ctx, nodeIDStr, agentID)
 	a.syncManager = ctxsync.NewSyncManager(nodeIDStr, agentID)
+	a.applyObserverMode()
+	a.rehydrateLocks()
 
 	// Initialize Phase 3 components
 	if err := a.initPhase3Components(nodeIDStr, agentID); err != nil {
@@ -323,13 +412,36 @@ func (a *App) Join(ctx context.Context, tokenStr string) (*JoinResult, error) {
 		return nil, fmt.Errorf("invalid invite token: %w", err)
 	}
 
-	// Check token expiration
-	if tok.IsExpired() {
+	// Check token expiration, tolerating clock skew between nodes
+	skew, err := a.config.ClockSkew()
+	if err != nil {
+		return nil, err
+	}
+	if tok.ExpiredAt(time.Now(), skew) {
 		return nil, fmt.Errorf("invite token has expired")
 	}
 
 	a.config.ProjectName = tok.ProjectName
 
+	// The inviter may restrict what this node's agents can do; admin
+	// must come with a grant signed by the cluster's root
+	a.config.ClusterRoot = tok.RootID()
+	if a.config.Role == "" && !a.config.Observer {
+		role := RoleAgent
+		if tok.Role != "" {
+			if role, err = ParseRole(tok.Role); err != nil {
+				return nil, fmt.Errorf("invalid invite token: %w", err)
+			}
+		}
+		if role == RoleAdmin {
+			if err := verifyAdminGrant(tok.Grant, a.config.ClusterRoot, tok.ProjectName); err != nil {
+				return nil, fmt.Errorf("invalid invite token: %w", err)
+			}
+			a.config.RoleCredential = tok.Grant
+		}
+		a.config.Role = string(role)
+	}
+
 	// 2. 키 생성 또는 로드
 	keyPath := filepath.Join(a.config.DataDir, "key.json")
 	keyPair, err := crypto.LoadKeyPair(keyPath)
@@ -377,6 +489,23 @@ func (a *App) Join(ctx context.Context, tokenStr string) (*JoinResult, error) {
 	// 5. libp2p 노드 생성 (global cluster - no projectID)
 	nodeConfig := libp2p.DefaultConfig()
 	nodeConfig.PrivateKey = keyPair.PrivateKey
+	nodeConfig.CompressionThreshold = a.config.CompressionThreshold
+	if nodeConfig.PEXConfig, err = a.config.PEXConfig(); err != nil {
+		return nil, err
+	}
+	if nodeConfig.NATConfig, err = a.config.NATConfig(); err != nil {
+		return nil, err
+	}
+	if nodeConfig.BanConfig, err = a.config.BanConfig(); err != nil {
+		return nil, err
+	}
+	if nodeConfig.GossipThrottle, err = a.config.GossipThrottleConfig(); err != nil {
+		return nil, err
+	}
+	if nodeConfig.LockPush, err = a.config.LockPushConfig(); err != nil {
+		return nil, err
+	}
+	nodeConfig.LocalityConfig = a.config.LocalityConfig()
 	nodeConfig.BootstrapPeers = bootstrapPeers
 
 	node, err := libp2p.NewNode(ctx, nodeConfig)
@@ -389,6 +518,7 @@ func (a *App) Join(ctx context.Context, tokenStr string) (*JoinResult, error) {
 	nodeIDStr := a.node.ID().String()
 	a.lockService = lock.NewLockService(ctx, nodeIDStr, tok.ProjectName+"-agent")
 	a.syncManager = ctxsync.NewSyncManager(nodeIDStr, tok.ProjectName+"-agent")
+	a.applyObserverMode()
 
 	// 7. Initialize Phase 3 components
 	if err := a.initPhase3Components(nodeIDStr, tok.ProjectName+"-agent"); err != nil {
@@ -463,10 +593,103 @@ func (a *App) Start() error {
 		return fmt.Errorf("app is not initialized")
 	}
 
+	if _, err := libp2p.ParseTopicScope(a.config.TopicScope); err != nil {
+		return err
+	}
+	if _, err := ParseContextGranularity(a.config.ContextGranularity); err != nil {
+		return err
+	}
+	if _, err := a.config.ReadinessSlowThreshold(); err != nil {
+		return err
+	}
+	if a.config.ContextShardDepth < 0 {
+		return fmt.Errorf("invalid context_shard_depth %d: must not be negative", a.config.ContextShardDepth)
+	}
+	if err := validatePricingConfig(a.config); err != nil {
+		return err
+	}
+	if a.config.LockSymbolPromotion < 0 {
+		return fmt.Errorf("invalid lock_symbol_promotion %d: must not be negative", a.config.LockSymbolPromotion)
+	}
+	languageRules, err := a.config.LanguageRules()
+	if err != nil {
+		return err
+	}
+	idleConfig, err := a.config.LockIdleConfig()
+	if err != nil {
+		return err
+	}
+	renewalConfig, err := a.config.LockRenewalConfig()
+	if err != nil {
+		return err
+	}
+	maxLease, err := a.config.LockMaxLeaseDuration()
+	if err != nil {
+		return err
+	}
+	rateLimit, err := a.config.LockRateLimitConfig()
+	if err != nil {
+		return err
+	}
+	conflictPolicy, err := lock.NewConflictPolicy(a.config.ConflictPolicyConfig(), a.tokenBudgetUsed)
+	if err != nil {
+		return fmt.Errorf("invalid conflict_policy: %w", err)
+	}
+	if a.config.Role != "" {
+		if _, err := ParseRole(a.config.Role); err != nil {
+			return fmt.Errorf("invalid role: %w", err)
+		}
+	}
+	waitOrder, err := lock.ParseWaitOrder(a.config.LockWaitOrder)
+	if err != nil {
+		return fmt.Errorf("invalid lock_wait_order: %w", err)
+	}
+	negotiationBuckets, err := a.config.NegotiationResolutionBuckets()
+	if err != nil {
+		return err
+	}
+	if _, err := a.config.ShareDedupDuration(); err != nil {
+		return err
+	}
+	collectionTTLs, err := a.config.CollectionTTLDurations()
+	if err != nil {
+		return err
+	}
+	if a.config.DeltaCollection != "" {
+		if err := vector.ValidateCollectionName(a.config.DeltaCollection); err != nil {
+			return fmt.Errorf("invalid delta_collection: %w", err)
+		}
+	}
+	deltaRetention, err := a.config.DeltaRetentionDuration()
+	if err != nil {
+		return err
+	}
+	slaConfig, err := a.config.LatencySLAConfig()
+	if err != nil {
+		return err
+	}
+	budgetConfig, err := a.config.TokenBudgetConfig()
+	if err != nil {
+		return err
+	}
+	autoShare, err := a.config.AutoShareConfig()
+	if err != nil {
+		return err
+	}
+
+	// 저장된 피어 차단 복원 (부트스트랩 전에 적용)
+	a.node.SetBanHandler(a.handlePeerBanned)
+	if n, err := a.restoreBans(); err != nil {
+		a.logger.Warn("failed to restore peer bans", "error", err)
+	} else if n > 0 {
+		a.logger.Info("restored peer bans", "count", n)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	a.ctx = ctx
 	a.cancel = cancel
 	a.running = true
+	a.closed = false
 
 	// Bootstrap to peers if configured
 	if len(a.config.Bootstrap) > 0 && a.config.BootstrapPeer != "" {
@@ -495,19 +718,103 @@ func (a *App) Start() error {
 		}
 	}
 
+	// 확장자 없는 파일의 언어 매핑 (위에서 검증됨)
+	if len(languageRules) > 0 {
+		if err := a.syncManager.SetLanguageRules(languageRules); err != nil {
+			a.logger.Warn("failed to apply language rules", "error", err)
+		}
+	}
+
+	// 압축된 델타 로그 복원 및 압축 후 저장
+	compaction := ctxsync.DefaultCompactionConfig()
+	compaction.Retention = deltaRetention
+	a.syncManager.SetCompactionConfig(compaction)
+	if n, err := a.restoreSyncState(); err != nil {
+		a.logger.Warn("failed to restore sync state", "error", err)
+	} else if n > 0 {
+		a.logger.Info("restored sync deltas", "count", n)
+	}
+	a.syncManager.SetCompactedFn(func(state *ctxsync.SyncResponse) {
+		if err := a.persistSyncState(state); err != nil {
+			a.logger.Warn("failed to persist sync state", "error", err)
+		}
+	})
+
 	// 동기화 관리자 시작
 	a.syncManager.Start(ctx)
 
+	// 파일 저장 자동 공유
+	if autoShare != nil {
+		if err := a.syncManager.StartAutoShare(ctx, *autoShare); err != nil {
+			a.logger.Warn("failed to start auto share", "root", autoShare.Root, "error", err)
+		} else {
+			a.logger.Info("auto sharing saved files", "root", a.syncManager.AutoShareRoot())
+		}
+	}
+
 	// 메시지 핸들러 설정
 	a.setupMessageHandlers()
 
-	// 글로벌 토픽 구독
-	if err := a.node.SubscribeGlobalTopics(ctx); err != nil {
+	// 유휴 락 자동 해제
+	if a.lockService != nil {
+		a.lockService.SetIdleHandler(a.handleLockIdle)
+		a.lockService.SetIdleConfig(idleConfig)
+		a.lockService.SetRenewalHandler(a.handleLockRenewal)
+		a.lockService.SetRenewalConfig(renewalConfig)
+		a.lockService.SetMaxLease(maxLease)
+		a.lockService.SetRateLimit(rateLimit)
+		a.lockService.SetConflictPolicy(conflictPolicy)
+		a.lockService.SetWaitOrder(waitOrder)
+		if a.config.EmbedLockedRegions {
+			a.lockService.SetAcquiredHandler(a.embedLockedRegion)
+			a.lockService.SetReleasedHandler(a.dropLockedRegion)
+		}
+		if a.config.LockSymbolPromotion > 0 {
+			resolve, err := symbolResolver(languageRules)
+			if err != nil {
+				return err
+			}
+			a.lockService.SetSymbolPromotion(a.config.LockSymbolPromotion, resolve)
+		}
+		if negotiationBuckets != nil {
+			if err := a.lockService.SetNegotiationBuckets(negotiationBuckets); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 락 이력 감사 로그
+	a.startLockAudit(ctx)
+
+	// 컬렉션 TTL 적용 및 만료 문서 정리
+	if err := a.applyCollectionTTLs(collectionTTLs); err != nil {
+		return fmt.Errorf("failed to apply collection_ttls: %w", err)
+	}
+	go a.expireCollections(ctx, CollectionExpiryInterval)
+
+	// 토큰 예산 한도
+	if budgetConfig.Enabled() {
+		a.setupTokenBudget(budgetConfig)
+	}
+
+	// 토큰 단가표 적용 및 원격 단가표 갱신
+	a.applyTokenPricing(a.config)
+	go a.refreshTokenPricing(ctx, PricingRefreshInterval)
+
+	// 피어 지연 SLA 경보
+	if qm := a.node.QualityMonitor(); qm != nil {
+		qm.SetLatencySLA(slaConfig)
+		qm.OnLatencyAlert(a.handleLatencyAlert)
+	}
+
+	// 설정된 범위(글로벌/프로젝트)의 토픽 구독
+	if err := a.node.SubscribeTopics(ctx, a.topics()); err != nil {
 		return fmt.Errorf("failed to subscribe topics: %w", err)
 	}
 
 	// Start event bridge for P2P event routing
 	if a.eventBridge != nil {
+		a.eventBridge.SetTopics(a.topics())
 		if err := a.eventBridge.Start(ctx); err != nil {
 			return fmt.Errorf("failed to start event bridge: %w", err)
 		}
@@ -516,10 +823,44 @@ func (a *App) Start() error {
 	// Start message processing goroutines
 	go a.processLockMessages(ctx)
 	go a.processContextMessages(ctx)
+	// 관심 영역에 맞는 컨텍스트 샤드 토픽 구독
+	a.startContextShards(ctx)
+	go a.processPresenceMessages(ctx)
+	// 관찰자 노드는 피어에게 관찰자임을 알림
+	if a.IsObserver() {
+		go a.announceObserver(ctx)
+	}
+	if push := a.node.LockPush(); push != nil {
+		push.SetSkip(a.isObserverPeer)
+	}
+
+	// config.json 변경 시 설정 재적용
+	go a.watchConfig(ctx, ConfigWatchInterval)
+
+	// 파티션 감지 및 복구 후 락 조정
+	go a.watchPartitions(ctx, lock.NewPartitionDetector(lock.DefaultPartitionConfig()))
+
+	// 이전 종료 시 진행 중이던 락 협상 복원
+	if n, err := a.restoreNegotiations(); err != nil {
+		a.logger.Warn("failed to restore lock negotiations", "error", err)
+	} else if n > 0 {
+		a.logger.Info("restored lock negotiations", "count", n)
+	}
+
+	// 재시작 전 보유하던 락 재공지 및 락 상태 저장
+	a.persistLocks()
 
 	return nil
 }
 
+// topics returns the P2P topic names for the configured topic scope.
+// Publishers, subscriptions and message processors all go through it so
+// they cannot drift apart. The scope is validated in Start.
+func (a *App) topics() libp2p.TopicSet {
+	scope, _ := libp2p.ParseTopicScope(a.config.TopicScope)
+	return libp2p.NewTopicSet(scope, a.config.ProjectName)
+}
+
 // Stop stops the application.
 func (a *App) Stop() error {
 	a.mu.Lock()
@@ -529,22 +870,59 @@ func (a *App) Stop() error {
 		return nil
 	}
 
+	a.shutdown()
+	return nil
+}
+
+// Close releases every resource held by the app, including those created
+// by LoadFromConfig or Join when the app was never started.
+func (a *App) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return nil
+	}
+
+	a.shutdown()
+	return nil
+}
+
+// shutdown stops all services (must be called with a.mu held).
+func (a *App) shutdown() {
+	// Let peers drop this node's locks now rather than on timeout
+	a.announceShutdown()
+
 	if a.cancel != nil {
 		a.cancel()
 	}
 
 	if a.lockService != nil {
+		// Keep pending negotiations so a restart can resume them
+		if a.running {
+			if err := a.persistNegotiations(); err != nil {
+				a.logger.Warn("failed to persist lock negotiations", "error", err)
+			}
+		}
 		a.lockService.Close()
 	}
 
 	if a.syncManager != nil {
 		a.syncManager.Stop()
+		if a.running {
+			if err := a.persistSyncState(a.syncManager.Export()); err != nil {
+				a.logger.Warn("failed to persist sync state", "error", err)
+			}
+		}
 	}
 
 	// Stop event bridge
 	if a.eventBridge != nil {
 		a.eventBridge.Stop()
 	}
+	if a.eventRouter != nil {
+		a.eventRouter.Stop()
+	}
 
 	// Close Phase 3 components
 	if a.tokenTracker != nil {
@@ -571,7 +949,19 @@ func (a *App) Stop() error {
 	}
 
 	a.running = false
-	return nil
+	a.closed = true
+}
+
+// IsObserver returns whether the node runs in observer mode.
+func (a *App) IsObserver() bool {
+	return a.Role() == RoleObserver
+}
+
+// applyObserverMode propagates observer mode to the domain services.
+func (a *App) applyObserverMode() {
+	if a.IsObserver() && a.lockService != nil {
+		a.lockService.SetObserver(true)
+	}
 }
 
 // Ensure libp2pcrypto is used
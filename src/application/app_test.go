@@ -2,12 +2,15 @@ package application_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"agent-collab/src/application"
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/crypto"
 )
 
 func TestNew_DefaultConfig(t *testing.T) {
@@ -328,6 +331,43 @@ func TestApp_GetStatus_Initialized(t *testing.T) {
 	}
 }
 
+func TestApp_ObserverMode(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "app-observer-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	config := &application.Config{
+		DataDir:    tmpDir,
+		ListenPort: 0,
+		Observer:   true,
+	}
+
+	app, err := application.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := app.Initialize(ctx, "observer-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	defer app.Stop()
+
+	if !app.GetStatus().Observer {
+		t.Error("Status should report observer mode")
+	}
+
+	if !app.LockService().IsObserver() {
+		t.Error("LockService should be in observer mode")
+	}
+
+	if err := app.BroadcastContext("main.go", "content", nil, nil); !errors.Is(err, application.ErrObserverMode) {
+		t.Errorf("BroadcastContext error = %v, expected ErrObserverMode", err)
+	}
+}
+
 func TestApp_Services_AfterInit(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "app-services-*")
 	if err != nil {
@@ -469,3 +509,117 @@ func TestDefaultConfig(t *testing.T) {
 		t.Errorf("ListenPort = %d, expected 0", config.ListenPort)
 	}
 }
+
+func TestConfig_ClockSkew(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", crypto.DefaultClockSkew, false},
+		{"0", 0, false},
+		{"2m", 2 * time.Minute, false},
+		{"-1s", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := (&application.Config{TokenClockSkew: tt.value}).ClockSkew()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ClockSkew(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_AutoShareConfig(t *testing.T) {
+	if cfg, err := (&application.Config{AutoShareRoot: "/tmp"}).AutoShareConfig(); cfg != nil || err != nil {
+		t.Errorf("expected auto share off by default, got %+v, %v", cfg, err)
+	}
+
+	root := t.TempDir()
+	cfg, err := (&application.Config{AutoShare: true, AutoShareRoot: root, AutoShareDebounce: "2s", AutoShareIgnore: []string{"*.gen.go"}}).AutoShareConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Root != root || cfg.Debounce != 2*time.Second || len(cfg.Ignore) != 1 {
+		t.Errorf("unexpected auto share config %+v", cfg)
+	}
+
+	for _, debounce := range []string{"soon", "0s", "-1s"} {
+		if _, err := (&application.Config{AutoShare: true, AutoShareDebounce: debounce}).AutoShareConfig(); err == nil {
+			t.Errorf("expected auto_share_debounce %q rejected", debounce)
+		}
+	}
+}
+
+func TestApp_Join_RejectsTokenBeyondClockSkew(t *testing.T) {
+	app, err := application.New(&application.Config{DataDir: t.TempDir(), TokenClockSkew: "30s"})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	tok, _ := crypto.NewInviteTokenWithTTL([]string{"/ip4/127.0.0.1/tcp/4001"}, "skew-test", "creator", -time.Minute)
+	encoded, _ := tok.Encode()
+
+	if _, err := app.Join(context.Background(), encoded); err == nil || err.Error() != "invite token has expired" {
+		t.Errorf("expected expired token error, got %v", err)
+	}
+}
+
+func TestApp_Stop_PersistsPendingNegotiations(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+
+	app, err := application.New(&application.Config{DataDir: tmpDir})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(ctx, "drain-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := app.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+
+	// An overlapping request leaves a negotiation pending
+	lockService := app.LockService()
+	req := &lock.AcquireLockRequest{TargetType: lock.TargetFile, FilePath: "main.go", StartLine: 1, EndLine: 50, Intention: "refactor"}
+	if _, err := lockService.AcquireLock(ctx, req); err != nil {
+		t.Fatalf("first lock failed: %v", err)
+	}
+	req = &lock.AcquireLockRequest{TargetType: lock.TargetFile, FilePath: "main.go", StartLine: 10, EndLine: 20, Intention: "fix bug"}
+	if _, err := lockService.AcquireLock(ctx, req); err == nil {
+		t.Fatal("overlapping lock should start a negotiation")
+	}
+	pending := lockService.ListActiveNegotiations()
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending negotiation, got %d", len(pending))
+	}
+
+	app.Stop()
+
+	path := filepath.Join(tmpDir, "negotiations.json")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("pending negotiations should be persisted on stop: %v", err)
+	}
+
+	// A restarted node resumes the negotiation
+	restarted, err := application.New(&application.Config{DataDir: tmpDir})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if err := restarted.LoadFromConfig(ctx); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if err := restarted.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	defer restarted.Stop()
+
+	resumed := restarted.LockService().ListActiveNegotiations()
+	if len(resumed) != 1 || resumed[0].ID != pending[0].ID {
+		t.Fatalf("expected negotiation %s to be restored, got %d sessions", pending[0].ID, len(resumed))
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("negotiations file should be removed once restored")
+	}
+}
@@ -0,0 +1,140 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"agent-collab/src/infrastructure/network/libp2p"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// bansFile holds the peer bans in effect, so they survive a restart.
+const bansFile = "bans.json"
+
+// BanPeer bans a peer for duration (0 until it is unbanned) and
+// disconnects it. The peer can no longer connect and its messages are
+// dropped.
+func (a *App) BanPeer(peerID, reason, operator string, duration time.Duration) (*libp2p.Ban, error) {
+	if a.node == nil {
+		return nil, fmt.Errorf("app is not initialized")
+	}
+	id, err := peer.Decode(peerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer ID: %w", err)
+	}
+	if id == a.node.ID() {
+		return nil, fmt.Errorf("cannot ban this node")
+	}
+	if duration < 0 {
+		return nil, fmt.Errorf("invalid ban duration %s: must not be negative", duration)
+	}
+	return a.node.BanPeer(id, reason, operator, duration), nil
+}
+
+// UnbanPeer lifts a peer's ban.
+func (a *App) UnbanPeer(peerID string) error {
+	if a.node == nil {
+		return fmt.Errorf("app is not initialized")
+	}
+	id, err := peer.Decode(peerID)
+	if err != nil {
+		return fmt.Errorf("invalid peer ID: %w", err)
+	}
+	if !a.node.UnbanPeer(id) {
+		return fmt.Errorf("peer %s is not banned", peerID)
+	}
+	a.logger.Info("peer unbanned", "peer_id", peerID)
+	if err := a.persistBans(); err != nil {
+		a.logger.Warn("failed to save peer bans", "error", err)
+	}
+	return nil
+}
+
+// ListBans returns the peer bans in effect, oldest first.
+func (a *App) ListBans() []*libp2p.Ban {
+	if a.node == nil {
+		return nil
+	}
+	return a.node.BanList().Bans()
+}
+
+// handlePeerBanned logs and saves a ban and calls the handler.
+func (a *App) handlePeerBanned(ban *libp2p.Ban) {
+	a.logger.Warn("peer banned",
+		"peer_id", ban.PeerID.String(),
+		"reason", ban.Reason,
+		"operator", ban.Operator,
+		"auto", ban.Auto,
+		"expires_at", ban.ExpiresAt)
+	if err := a.persistBans(); err != nil {
+		a.logger.Warn("failed to save peer bans", "error", err)
+	}
+
+	a.mu.RLock()
+	handler := a.onPeerBanned
+	a.mu.RUnlock()
+	if handler != nil {
+		handler(ban)
+	}
+}
+
+// SetPeerBannedHandler sets the callback invoked when a peer is banned,
+// by an operator or for misbehaving.
+func (a *App) SetPeerBannedHandler(handler func(*libp2p.Ban)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onPeerBanned = handler
+}
+
+// persistBans writes the bans in effect to the data directory.
+func (a *App) persistBans() error {
+	if a.node == nil || a.config.DataDir == "" {
+		return nil
+	}
+	a.bansMu.Lock()
+	defer a.bansMu.Unlock()
+
+	path := filepath.Join(a.config.DataDir, bansFile)
+	bans := a.node.BanList().Bans()
+	if len(bans) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(bans, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// restoreBans reinstates the bans saved before the last shutdown that
+// have not expired.
+func (a *App) restoreBans() (int, error) {
+	if a.node == nil || a.config.DataDir == "" {
+		return 0, nil
+	}
+
+	path := filepath.Join(a.config.DataDir, bansFile)
+	// #nosec G304 - path is the fixed bans file in the data directory
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var bans []*libp2p.Ban
+	if err := json.Unmarshal(data, &bans); err != nil {
+		return 0, err
+	}
+	return a.node.BanList().Restore(bans), nil
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"time"
+
+	"agent-collab/src/domain/event"
+	"agent-collab/src/domain/token"
+)
+
+// setupTokenBudget enforces the configured token limits on this node,
+// counting usage persisted earlier this month towards them.
+func (a *App) setupTokenBudget(cfg token.BudgetConfig) {
+	if a.tokenTracker == nil {
+		return
+	}
+
+	budget := token.NewBudget(cfg)
+	// Reported off the recording goroutine, which may be an embedding call
+	// made under the app lock
+	budget.OnExceeded(func(exceeded *token.BudgetExceeded) {
+		go a.handleBudgetExceeded(exceeded)
+	})
+	a.tokenTracker.SetBudget(budget)
+	if cfg.Project.Daily > 0 {
+		a.tokenTracker.SetDailyLimit(cfg.Project.Daily)
+	}
+
+	if a.metricsStore == nil {
+		return
+	}
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	records, err := a.metricsStore.LoadRange(monthStart, now)
+	if err != nil {
+		a.logger.Warn("failed to load token usage for budget", "error", err)
+		return
+	}
+	var today, thisMonth int64
+	for _, record := range records {
+		thisMonth += record.Tokens
+		if !record.Timestamp.Before(dayStart) {
+			today += record.Tokens
+		}
+	}
+	a.tokenTracker.SeedBudget(today, thisMonth)
+}
+
+// handleBudgetExceeded logs a spent token budget and publishes it to the
+// event router.
+func (a *App) handleBudgetExceeded(exceeded *token.BudgetExceeded) {
+	a.logger.Component("token-budget").Warn("token budget exceeded",
+		"scope", exceeded.Scope,
+		"agent_id", exceeded.AgentID,
+		"period", exceeded.Period,
+		"limit", exceeded.Limit,
+		"used", exceeded.Used,
+		"action", exceeded.Action)
+
+	if router := a.eventRouter; router != nil {
+		sourceID, sourceName := a.eventSource()
+		err := router.Publish(a.ctx, event.NewBudgetExceededEvent(sourceID, sourceName, &event.BudgetPayload{
+			Scope:   exceeded.Scope,
+			AgentID: exceeded.AgentID,
+			Period:  exceeded.Period,
+			Limit:   exceeded.Limit,
+			Used:    exceeded.Used,
+			Action:  string(exceeded.Action),
+		}))
+		if err != nil {
+			a.logger.Debug("failed to broadcast budget event", "error", err)
+		}
+	}
+
+	a.mu.RLock()
+	handler := a.onBudgetExceeded
+	a.mu.RUnlock()
+	if handler != nil {
+		handler(exceeded)
+	}
+}
+
+// SetBudgetExceededHandler sets the callback invoked when this node first
+// spends one of its token budgets in a period.
+func (a *App) SetBudgetExceededHandler(handler func(*token.BudgetExceeded)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onBudgetExceeded = handler
+}
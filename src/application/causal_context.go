@@ -0,0 +1,66 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"agent-collab/src/domain/ctxsync"
+)
+
+// causalShares orders context shares by vector clock. The clock counts
+// shares this node sent and applied; every outgoing share carries it so
+// receivers can hold shares until what they build on has arrived.
+type causalShares struct {
+	mu     sync.Mutex
+	clock  *ctxsync.VectorClock
+	buffer *ctxsync.CausalBuffer[*ContextMessage]
+}
+
+func newCausalShares() *causalShares {
+	return &causalShares{
+		clock:  ctxsync.NewVectorClock(),
+		buffer: ctxsync.NewCausalBuffer[*ContextMessage](ctxsync.DefaultCausalBufferSize, ctxsync.DefaultCausalTimeout),
+	}
+}
+
+// stamp advances the clock for a share sent by nodeID and returns a copy
+// to attach to it.
+func (c *causalShares) stamp(nodeID string) *ctxsync.VectorClock {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock.Increment(nodeID)
+	return c.clock.Clone()
+}
+
+// receive buffers a peer's share and returns the shares now ready to be
+// applied, in causal order.
+func (c *causalShares) receive(msg *ContextMessage, now time.Time) ([]*ContextMessage, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ready, dropped := c.buffer.Receive(msg.SourceID, msg.Clock, msg, now)
+	for _, m := range ready {
+		c.clock.Merge(m.Clock)
+	}
+	return ready, dropped
+}
+
+// receiveSharedContext applies a peer's share once every share it causally
+// depends on has been applied. Shares without a clock, from older nodes,
+// are applied as they arrive.
+func (a *App) receiveSharedContext(ctx context.Context, msg *ContextMessage) {
+	if msg.Clock == nil || a.causal == nil {
+		a.handleSharedContext(ctx, msg)
+		return
+	}
+
+	ready, dropped := a.causal.receive(msg, time.Now())
+	if dropped > 0 {
+		a.logger.Component("context-handler").Warn("dropped shared context waiting for causal dependencies",
+			"count", dropped)
+	}
+	for _, m := range ready {
+		a.handleSharedContext(ctx, m)
+	}
+}
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+
+	"agent-collab/src/domain/ctxsync"
+	"agent-collab/src/infrastructure/storage/vector"
+	"agent-collab/src/pkg/logging"
+)
+
+// recordingStore records the order shared context is stored in.
+type recordingStore struct {
+	vector.Store
+	mu       sync.Mutex
+	contents []string
+}
+
+func (s *recordingStore) Insert(doc *vector.Document) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contents = append(s.contents, doc.Content)
+	return nil
+}
+
+func (s *recordingStore) Flush() error { return nil }
+
+func (s *recordingStore) stored() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.contents...)
+}
+
+func sharedContextMessage(t *testing.T, source, content string, clock map[string]uint64) []byte {
+	t.Helper()
+	data, err := json.Marshal(ContextMessage{
+		Type:      "shared_context",
+		FilePath:  "auth/login.go",
+		Content:   content,
+		Embedding: []float32{1, 0},
+		SourceID:  source,
+		Clock:     ctxsync.FromMap(clock),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestApp_SharedContextAppliedInCausalOrder(t *testing.T) {
+	store := &recordingStore{}
+	a := &App{
+		logger:      logging.New(io.Discard, "error"),
+		vectorStore: store,
+		procMetrics: NewProcessingMetrics(),
+		causal:      newCausalShares(),
+	}
+	ctx := context.Background()
+
+	a.handleSingleContextMessage(ctx, sharedContextMessage(t, "node-a", "a1: add rate limiter", map[string]uint64{"node-a": 1}), "node-a")
+	a.handleSingleContextMessage(ctx, sharedContextMessage(t, "node-b", "b1: docs", map[string]uint64{"node-b": 1}), "node-b")
+
+	// node-b replies to a2 before a2 reaches us
+	a.handleSingleContextMessage(ctx, sharedContextMessage(t, "node-b", "b2: use a2's limiter", map[string]uint64{"node-a": 2, "node-b": 2}), "node-b")
+	if got := store.stored(); len(got) != 2 {
+		t.Fatalf("b2 should wait for a2, stored %v", got)
+	}
+
+	a.handleSingleContextMessage(ctx, sharedContextMessage(t, "node-a", "a2: tune limiter", map[string]uint64{"node-a": 2, "node-b": 1}), "node-a")
+	want := []string{"a1: add rate limiter", "b1: docs", "a2: tune limiter", "b2: use a2's limiter"}
+	if got := store.stored(); !reflect.DeepEqual(got, want) {
+		t.Errorf("shares applied out of causal order:\n got %v\nwant %v", got, want)
+	}
+
+	// A replayed share is not stored again
+	a.handleSingleContextMessage(ctx, sharedContextMessage(t, "node-a", "a2: tune limiter", map[string]uint64{"node-a": 2, "node-b": 1}), "node-a")
+	if got := store.stored(); len(got) != 4 {
+		t.Errorf("replayed share should be ignored, stored %v", got)
+	}
+
+	// Our next share builds on everything applied
+	if clock := a.causal.stamp("node-c").ToMap(); !reflect.DeepEqual(clock, map[string]uint64{"node-a": 2, "node-b": 2, "node-c": 1}) {
+		t.Errorf("outgoing clock should include applied shares, got %v", clock)
+	}
+}
+
+func TestApp_SharedContextWithoutClock(t *testing.T) {
+	store := &recordingStore{}
+	a := &App{
+		logger:      logging.New(io.Discard, "error"),
+		vectorStore: store,
+		procMetrics: NewProcessingMetrics(),
+		causal:      newCausalShares(),
+	}
+
+	// Shares from nodes that predate vector clocks apply immediately
+	data, _ := json.Marshal(ContextMessage{Type: "shared_context", FilePath: "x.go", Content: "legacy", Embedding: []float32{1}, SourceID: "old"})
+	a.handleSingleContextMessage(context.Background(), data, "old")
+	if got := store.stored(); len(got) != 1 {
+		t.Errorf("legacy share should be stored, got %v", got)
+	}
+}
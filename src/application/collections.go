@@ -0,0 +1,160 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// CollectionExpiryInterval is how often documents past their collection's
+// TTL are removed.
+const CollectionExpiryInterval = time.Minute
+
+// ErrCollectionInUse is returned when deleting a collection the node
+// stores documents in.
+var ErrCollectionInUse = errors.New("collection is in use")
+
+// ListCollections returns the stats of every vector collection, by name.
+func (a *App) ListCollections() ([]*vector.CollectionStats, error) {
+	store := a.VectorStore()
+	if store == nil {
+		return nil, fmt.Errorf("vector store not initialized")
+	}
+	names, err := store.ListCollections()
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+
+	collections := make([]*vector.CollectionStats, 0, len(names))
+	for _, name := range names {
+		stats, err := store.GetCollectionStats(name)
+		if err != nil {
+			continue // deleted meanwhile
+		}
+		collections = append(collections, stats)
+	}
+	return collections, nil
+}
+
+// CreateCollection creates a vector collection. dimension 0 uses the
+// embedding service's; ttl 0 keeps documents until they are deleted.
+func (a *App) CreateCollection(name string, dimension int, ttl time.Duration) (*vector.CollectionStats, error) {
+	store := a.VectorStore()
+	if store == nil {
+		return nil, fmt.Errorf("vector store not initialized")
+	}
+	if dimension < 0 {
+		return nil, fmt.Errorf("dimension must not be negative: %d", dimension)
+	}
+	if err := vector.ValidateCollectionName(name); err != nil {
+		return nil, err
+	}
+	if ttl != 0 {
+		if _, ok := store.(vector.ExpiringStore); !ok {
+			return nil, fmt.Errorf("vector store does not support collection TTLs")
+		}
+	}
+
+	if err := store.CreateCollection(name, a.collectionDimension(dimension)); err != nil {
+		return nil, err
+	}
+	if ttl != 0 {
+		if err := store.(vector.ExpiringStore).SetCollectionTTL(name, ttl); err != nil {
+			return nil, err
+		}
+	}
+	return store.GetCollectionStats(name)
+}
+
+// DeleteCollection deletes a vector collection and its documents. The
+// default collection and the configured delta collection cannot be
+// deleted.
+func (a *App) DeleteCollection(name string) error {
+	store := a.VectorStore()
+	if store == nil {
+		return fmt.Errorf("vector store not initialized")
+	}
+	if name == vector.DefaultCollection || name == a.config.DeltaCollectionName() {
+		return fmt.Errorf("%w: %s", ErrCollectionInUse, name)
+	}
+	return store.DeleteCollection(name)
+}
+
+// SetCollectionTTL changes how long a collection keeps documents after
+// their last update. 0 keeps them until they are deleted.
+func (a *App) SetCollectionTTL(name string, ttl time.Duration) error {
+	store, ok := a.VectorStore().(vector.ExpiringStore)
+	if !ok {
+		return fmt.Errorf("vector store does not support collection TTLs")
+	}
+	return store.SetCollectionTTL(name, ttl)
+}
+
+// applyCollectionTTLs sets the configured collection TTLs, creating the
+// collections that do not exist yet.
+func (a *App) applyCollectionTTLs(ttls map[string]time.Duration) error {
+	if len(ttls) == 0 || a.vectorStore == nil {
+		return nil
+	}
+	store, ok := a.vectorStore.(vector.ExpiringStore)
+	if !ok {
+		return fmt.Errorf("vector store does not support collection TTLs")
+	}
+	existing, err := a.vectorStore.ListCollections()
+	if err != nil {
+		return err
+	}
+	for name, ttl := range ttls {
+		if !slices.Contains(existing, name) {
+			if err := a.vectorStore.CreateCollection(name, a.collectionDimension(0)); err != nil {
+				return err
+			}
+		}
+		if err := store.SetCollectionTTL(name, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectionDimension returns the dimension for a new collection, the
+// embedding service's when none is given.
+func (a *App) collectionDimension(dimension int) int {
+	if dimension == 0 && a.embedService != nil {
+		return a.embedService.Dimension()
+	}
+	return dimension
+}
+
+// expireCollections removes documents past their collection's TTL every
+// interval until ctx is done.
+func (a *App) expireCollections(ctx context.Context, interval time.Duration) {
+	store, ok := a.vectorStore.(vector.ExpiringStore)
+	if !ok {
+		return
+	}
+	log := a.logger.Component("vector-store")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			expired, err := store.ExpireDocuments(now)
+			if err != nil {
+				log.Error("failed to expire documents", "error", err)
+				continue
+			}
+			for name, count := range expired {
+				log.Info("expired documents", "collection", name, "count", count)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package application
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+func TestApp_CollectionLifecycle(t *testing.T) {
+	store, err := vector.NewMemoryStore(t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{config: &Config{DeltaCollection: "file-deltas"}, vectorStore: store}
+
+	if err := a.applyCollectionTTLs(map[string]time.Duration{"file-deltas": 72 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := a.CreateCollection("arch-notes", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Dimension != 2 || stats.TTL != 0 {
+		t.Errorf("expected the store dimension and no TTL, got %+v", stats)
+	}
+	if _, err := a.CreateCollection("../escape", 0, 0); err == nil {
+		t.Error("expected an invalid collection name to be rejected")
+	}
+
+	collections, err := a.ListCollections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collections) != 2 || collections[0].Name != "arch-notes" || collections[1].Name != "file-deltas" {
+		t.Fatalf("expected arch-notes and file-deltas, got %+v", collections)
+	}
+	if collections[1].TTL != 72*time.Hour {
+		t.Errorf("expected the configured TTL on file-deltas, got %v", collections[1].TTL)
+	}
+
+	for _, name := range []string{vector.DefaultCollection, "file-deltas"} {
+		if err := a.DeleteCollection(name); !errors.Is(err, ErrCollectionInUse) {
+			t.Errorf("deleting %s: expected ErrCollectionInUse, got %v", name, err)
+		}
+	}
+	if err := a.DeleteCollection("arch-notes"); err != nil {
+		t.Fatal(err)
+	}
+	if names, _ := store.ListCollections(); len(names) != 1 {
+		t.Errorf("expected only file-deltas to remain, got %v", names)
+	}
+}
@@ -1,8 +1,25 @@
 package application
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"agent-collab/src/domain/ast"
+	"agent-collab/src/domain/ctxsync"
+	"agent-collab/src/domain/interest"
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/domain/token"
+	"agent-collab/src/infrastructure/crypto"
+	"agent-collab/src/infrastructure/embedding"
+	"agent-collab/src/infrastructure/network/libp2p"
+	"agent-collab/src/infrastructure/storage/vector"
+
+	"github.com/libp2p/go-libp2p/core/peer"
 )
 
 // Config는 애플리케이션 설정입니다.
@@ -14,10 +31,847 @@ type Config struct {
 	Bootstrap     []string `json:"bootstrap"`                // Bootstrap peer 주소들
 	BootstrapPeer string   `json:"bootstrap_peer,omitempty"` // Bootstrap peer ID
 
+	// Observer nodes receive events and status but never lock, vote, or share context
+	Observer bool `json:"observer,omitempty"`
+
+	// Role limits what the node's agents may do through the daemon:
+	// "admin", "agent" or "observer". Set on init (admin) and join (from
+	// the invite token, else agent); empty means agent. Admin also needs
+	// a RoleCredential granted by ClusterRoot.
+	Role string `json:"role,omitempty"`
+
+	// ClusterRoot is the peer ID of the node that created the cluster.
+	// It signs admin grants, which peers check before honouring
+	// operator operations such as force release.
+	ClusterRoot string `json:"cluster_root,omitempty"`
+
+	// RoleCredential is the admin grant this node received from
+	// ClusterRoot, on init or through its invite token.
+	RoleCredential *crypto.RoleCredential `json:"role_credential,omitempty"`
+
+	// TopicScope selects cluster-wide ("global", default) or per-project
+	// ("project") P2P topics. Every node in a cluster must use the same scope.
+	TopicScope string `json:"topic_scope,omitempty"`
+
+	// ContextShardDepth shards context sync by directory: deltas and
+	// shares are published to one topic per leading directory, down to
+	// this many levels, and nodes subscribe only to the shards covering
+	// their interests. 0 (default) keeps a single context topic. Every
+	// node in a cluster must use the same depth.
+	ContextShardDepth int `json:"context_shard_depth,omitempty"`
+
+	// TokenClockSkew is how far past its expiry an invite token is still
+	// accepted on join, to tolerate clock differences between nodes
+	// (default 30s). "0" enforces the expiry exactly.
+	TokenClockSkew string `json:"token_clock_skew,omitempty"`
+
+	// DisableShutdownNotice stops the node from telling peers it is
+	// shutting down; they then notice only when its locks time out.
+	DisableShutdownNotice bool `json:"disable_shutdown_notice,omitempty"`
+
+	// AllowUnsignedMessages accepts lock and context messages without a
+	// signature, from nodes that predate message signing. Enable it only
+	// while upgrading a cluster: unsigned messages can speak for any node.
+	AllowUnsignedMessages bool `json:"allow_unsigned_messages,omitempty"`
+
+	// LockIdleWindow enables auto-release of locks held without activity
+	// (edits or renewals) for this long, e.g. "15m". Empty disables it.
+	LockIdleWindow string `json:"lock_idle_window,omitempty"`
+	// LockIdleGrace is how long the holder has after the idle warning before
+	// the lock is released (default 1m).
+	LockIdleGrace string `json:"lock_idle_grace,omitempty"`
+	// LockRenewalReminder reminds the holder to renew a lock once this
+	// fraction of its lease has elapsed, e.g. 0.8. 0 disables it.
+	LockRenewalReminder float64 `json:"lock_renewal_reminder,omitempty"`
+	// ConflictPolicy decides lock conflicts without negotiation: "manual"
+	// (default, agents negotiate and unresolved conflicts escalate),
+	// "fifo", "priority_wins" or "cost_aware".
+	ConflictPolicy string `json:"conflict_policy,omitempty"`
+	// ConflictPriorities ranks agents by node ID or name for priority_wins
+	// and cost_aware; higher wins and unlisted agents rank 0.
+	ConflictPriorities map[string]int `json:"conflict_priorities,omitempty"`
+	// ConflictBudgetThreshold is the share of the daily token budget after
+	// which cost_aware yields (default 0.8).
+	ConflictBudgetThreshold float64 `json:"conflict_budget_threshold,omitempty"`
+	// LockWaitOrder orders agents waiting for a locked region
+	// (wait_for_lock): "fifo" (default) or "priority", by the priority
+	// each request gives.
+	LockWaitOrder string `json:"lock_wait_order,omitempty"`
+	// LockMaxLease caps how long a lock can be kept through renewals,
+	// counted from acquisition, e.g. "2h". Empty means no limit.
+	LockMaxLease string `json:"lock_max_lease,omitempty"`
+	// LockRateLimit is how many lock requests per second each peer may
+	// make (default 10), in bursts of up to LockRateBurst (default 20).
+	LockRateLimit float64 `json:"lock_rate_limit,omitempty"`
+	LockRateBurst int     `json:"lock_rate_burst,omitempty"`
+	// LockRateAdaptive scales each peer's limit by its conflict record:
+	// every conflict halves it, down to LockRateAdaptiveMin (default 0.25)
+	// times the base, and requests without conflicts raise it again, up to
+	// LockRateAdaptiveMax (default 2) times.
+	LockRateAdaptive    bool    `json:"lock_rate_adaptive,omitempty"`
+	LockRateAdaptiveMin float64 `json:"lock_rate_adaptive_min,omitempty"`
+	LockRateAdaptiveMax float64 `json:"lock_rate_adaptive_max,omitempty"`
+	// NegotiationBuckets are the upper bounds of the negotiation
+	// time-to-resolution histogram, in increasing order, e.g.
+	// ["500ms", "5s", "30s"]. Empty uses the defaults (100ms to 1m).
+	NegotiationBuckets []string `json:"negotiation_buckets,omitempty"`
+
+	// MetricsAddr also serves the daemon's Prometheus metrics over TCP at
+	// this address, e.g. "127.0.0.1:9464", for scrapers that cannot reach
+	// the daemon socket. Empty disables it.
+	MetricsAddr string `json:"metrics_addr,omitempty"`
+
+	// APIAddr serves the dashboard API over TCP at this address, e.g.
+	// "127.0.0.1:9465": /api/v1/ws streams daemon events over a WebSocket.
+	// Empty disables it.
+	APIAddr string `json:"api_addr,omitempty"`
+	// APIAllowedOrigins are the browser origins, besides the API's own,
+	// allowed to open the event stream, e.g. ["http://localhost:3000"].
+	// "*" allows any origin.
+	APIAllowedOrigins []string `json:"api_allowed_origins,omitempty"`
+
+	// ShareDedupWindow suppresses sharing the same content for the same
+	// file again within this window, e.g. "10m" (default). "0" disables it.
+	ShareDedupWindow string `json:"share_dedup_window,omitempty"`
+
+	// ContextGranularity selects how shared file changes are embedded for
+	// search: "file" (default), "symbol" (one document per changed symbol)
+	// or "both".
+	ContextGranularity string `json:"context_granularity,omitempty"`
+
+	// DeltaCollection is the vector collection file changes from peers and
+	// auto share are stored in, to keep them apart from shared notes.
+	// Empty uses "default".
+	DeltaCollection string `json:"delta_collection,omitempty"`
+	// CollectionTTLs remove the documents of a collection once they have
+	// not been updated for the given duration, e.g. {"file-deltas": "72h"}.
+	// Missing collections are created at startup.
+	CollectionTTLs map[string]string `json:"collection_ttls,omitempty"`
+
+	// DeltaRetention is how long sync deltas stay in the log before they
+	// are folded into the compacted checkpoint, e.g. "24h" (default). "0"
+	// keeps them until the log fills up.
+	DeltaRetention string `json:"delta_retention,omitempty"`
+
+	// AutoShare watches the project for saved files and shares their
+	// changes (deltas to peers, embeddings for search) without the agent
+	// calling share_context. AutoShareRoot is the project directory
+	// (default: the daemon's working directory). Paths matched by the
+	// project's .gitignore files or by AutoShareIgnore, gitignore-style
+	// patterns applied after them, are skipped. AutoShareDebounce is how
+	// long to wait after the last save (default 500ms).
+	AutoShare         bool     `json:"auto_share,omitempty"`
+	AutoShareRoot     string   `json:"auto_share_root,omitempty"`
+	AutoShareIgnore   []string `json:"auto_share_ignore,omitempty"`
+	AutoShareDebounce string   `json:"auto_share_debounce,omitempty"`
+
+	// EmbedLockedRegions embeds the current content of a region into the
+	// vector store, tagged "in_progress", when this node locks it, so other
+	// agents find active work by search. The document is removed when the
+	// lock goes away.
+	EmbedLockedRegions bool `json:"embed_locked_regions,omitempty"`
+
+	// LockSymbolPromotion turns the n-th region lock this node takes inside
+	// the same function, method or type within 30 minutes into a lock on
+	// that symbol, whose range then follows the symbol as the file is
+	// edited. 0 (default) disables promotion.
+	LockSymbolPromotion int `json:"lock_symbol_promotion,omitempty"`
+
+	// LanguageFilenames maps file name patterns to a parser language for
+	// files whose extension says nothing, e.g. {"*.tmpl": "go",
+	// "Jenkinsfile": "generic"}. Exact names win over glob patterns, which
+	// are tried in sorted order, all before the built-in rules.
+	LanguageFilenames map[string]string `json:"language_filenames,omitempty"`
+
+	// EmbeddingProvider selects the embedding provider: "openai",
+	// "ollama", "anthropic", "google", "mock" (default) or "auto" to use
+	// the first one whose API key or server is found. EmbeddingModel and
+	// EmbeddingDimension override the provider's default model; known
+	// models need no dimension. EmbeddingAPIKey falls back to the
+	// provider's environment variable (OPENAI_API_KEY, ...) and
+	// EmbeddingBaseURL points at another endpoint, such as a remote Ollama
+	// server or an OpenAI-compatible API.
+	EmbeddingProvider  string `json:"embedding_provider,omitempty"`
+	EmbeddingModel     string `json:"embedding_model,omitempty"`
+	EmbeddingDimension int    `json:"embedding_dimension,omitempty"`
+	EmbeddingAPIKey    string `json:"embedding_api_key,omitempty"`
+	EmbeddingBaseURL   string `json:"embedding_base_url,omitempty"`
+
+	// EmbeddingBatchSize caps the texts sent per provider call (default
+	// 100). EmbeddingMaxRetries is how often a rate limited or failed call
+	// is retried with exponential backoff (default 3; negative disables).
+	EmbeddingBatchSize  int `json:"embedding_batch_size,omitempty"`
+	EmbeddingMaxRetries int `json:"embedding_max_retries,omitempty"`
+
+	// EmbeddingMode is "symmetric" (default) or "asymmetric", for models
+	// that embed search queries and stored documents differently. The
+	// prefixes override the model's default instructions in asymmetric mode.
+	EmbeddingMode           string `json:"embedding_mode,omitempty"`
+	EmbeddingQueryPrefix    string `json:"embedding_query_prefix,omitempty"`
+	EmbeddingDocumentPrefix string `json:"embedding_document_prefix,omitempty"`
+
+	// EmbeddingSummary condenses shared context longer than
+	// EmbeddingSummaryTokens (default 256) before it is embedded: "off"
+	// (default), "template" to keep the header, declarations and headings
+	// locally, or "llm" to have a chat model summarize it. The LLM provider
+	// ("openai", "anthropic", "ollama" or "mock") defaults to the embedding
+	// provider, and its API key to embedding_api_key when they match, then
+	// the provider's environment variable. Summary tokens are tracked under
+	// the "summarization" category.
+	EmbeddingSummary         string `json:"embedding_summary,omitempty"`
+	EmbeddingSummaryTokens   int    `json:"embedding_summary_tokens,omitempty"`
+	EmbeddingSummaryProvider string `json:"embedding_summary_provider,omitempty"`
+	EmbeddingSummaryModel    string `json:"embedding_summary_model,omitempty"`
+	EmbeddingSummaryAPIKey   string `json:"embedding_summary_api_key,omitempty"`
+	EmbeddingSummaryBaseURL  string `json:"embedding_summary_base_url,omitempty"`
+
+	// MaxInFlightEmbeddings bounds concurrent embedding provider calls
+	// (default 8; negative is unbounded). EmbeddingOverloadPolicy is
+	// "queue" (default) to wait for a slot, shedding once
+	// MaxQueuedEmbeddings calls (default 64) are waiting, or "shed" to drop
+	// excess work immediately.
+	MaxInFlightEmbeddings   int    `json:"max_inflight_embeddings,omitempty"`
+	EmbeddingOverloadPolicy string `json:"embedding_overload_policy,omitempty"`
+	MaxQueuedEmbeddings     int    `json:"max_queued_embeddings,omitempty"`
+
+	// TokenBudgetDaily and TokenBudgetMonthly are hard limits on the
+	// tokens this node uses per calendar day and month; 0 is unlimited.
+	// AgentTokenBudgets limits agents by node ID or name, "*" applying to
+	// every agent without an entry. A spent budget blocks embedding calls
+	// and context shares: TokenBudgetAction "reject" (default) refuses
+	// them until the period ends, "throttle" lets one through per
+	// TokenBudgetThrottleInterval (default 1m).
+	TokenBudgetDaily            int64                         `json:"token_budget_daily,omitempty"`
+	TokenBudgetMonthly          int64                         `json:"token_budget_monthly,omitempty"`
+	AgentTokenBudgets           map[string]token.BudgetLimits `json:"agent_token_budgets,omitempty"`
+	TokenBudgetAction           string                        `json:"token_budget_action,omitempty"`
+	TokenBudgetThrottleInterval string                        `json:"token_budget_throttle_interval,omitempty"`
+
+	// TokenPricing prices models in USD per 1M input and output tokens,
+	// keyed by model or "provider/model", e.g. {"gpt-4o": {"input": 2.5,
+	// "output": 10}}. It overrides the built-in prices and those fetched
+	// from TokenPricingURL, a JSON table in the same format re-read daily.
+	TokenPricing    token.Pricing `json:"token_pricing,omitempty"`
+	TokenPricingURL string        `json:"token_pricing_url,omitempty"`
+
+	// MaxDiffBytes caps the file diff carried in a context delta. Larger
+	// diffs are sent as a hash and summary that peers fetch on demand.
+	// 0 uses the default (64 KiB); a negative value always sends full diffs.
+	MaxDiffBytes int `json:"max_diff_bytes,omitempty"`
+
+	// CompressionThreshold is the message size in bytes below which P2P
+	// messages are sent uncompressed. 0 uses the default (1 KiB); a
+	// negative value disables compression.
+	CompressionThreshold int `json:"compression_threshold,omitempty"`
+
+	// PeerLatencySLA marks a peer degraded and raises a warning event when
+	// every RTT sample stays above it for PeerLatencySLAWindow (default 2m),
+	// e.g. "200ms". Empty disables it. PeerLatencySLAs overrides the
+	// threshold per peer ID.
+	PeerLatencySLA       string            `json:"peer_latency_sla,omitempty"`
+	PeerLatencySLAWindow string            `json:"peer_latency_sla_window,omitempty"`
+	PeerLatencySLAs      map[string]string `json:"peer_latency_slas,omitempty"`
+
+	// DisablePeerExchange turns off peer exchange (PEX), where connected
+	// peers share samples of the peers they know so a node bootstrapped to
+	// one peer finds the rest. PeerExchangeInterval (default 30s) and
+	// PeerExchangeSampleSize (default 16) tune how often and how much.
+	DisablePeerExchange    bool   `json:"disable_peer_exchange,omitempty"`
+	PeerExchangeInterval   string `json:"peer_exchange_interval,omitempty"`
+	PeerExchangeSampleSize int    `json:"peer_exchange_sample_size,omitempty"`
+
+	// AutoBanThreshold is the misbehavior score at which a peer is banned
+	// for AutoBanDuration (default 100 and 1h); a malformed message scores
+	// 20 and each message beyond the peer's rate limit 1. Negative disables
+	// automatic bans. PeerMessageRate and PeerMessageBurst set how many
+	// pubsub messages per second a peer may publish (default 50, bursts of
+	// 200); a negative rate disables the limit.
+	AutoBanThreshold float64 `json:"auto_ban_threshold,omitempty"`
+	AutoBanDuration  string  `json:"auto_ban_duration,omitempty"`
+	PeerMessageRate  float64 `json:"peer_message_rate,omitempty"`
+	PeerMessageBurst int     `json:"peer_message_burst,omitempty"`
+
+	// RelayPeers are circuit relays, as multiaddrs ending in /p2p/<id>,
+	// tried before super peers and other relay-capable peers when this
+	// node is behind a NAT it cannot be reached through. DisableAutoRelay
+	// stops it reserving relay slots; DisableRelayService stops it
+	// relaying for others when it is publicly reachable.
+	RelayPeers          []string `json:"relay_peers,omitempty"`
+	DisableAutoRelay    bool     `json:"disable_auto_relay,omitempty"`
+	DisableRelayService bool     `json:"disable_relay_service,omitempty"`
+
+	// GossipThrottle keeps context sync gossip off the WAN: peers in far
+	// regions leave the topic's mesh and receive the messages as batches
+	// sent to one gateway per region, every GossipThrottleDelay (default
+	// 500ms). Region names this node's region for locality clustering;
+	// peers are otherwise classified by RTT.
+	GossipThrottle      bool   `json:"gossip_throttle,omitempty"`
+	GossipThrottleDelay string `json:"gossip_throttle_delay,omitempty"`
+	Region              string `json:"region,omitempty"`
+
+	// Lock intents, acquires and releases are also pushed straight to each
+	// peer, highest quality score first, and retried toward peers that do
+	// not acknowledge them up to LockPushAttempts times (default 4).
+	// DisableLockPush leaves lock messages to gossip alone.
+	DisableLockPush  bool `json:"disable_lock_push,omitempty"`
+	LockPushAttempts int  `json:"lock_push_attempts,omitempty"`
+
+	// Readiness gates when the node reports ready. ReadinessMinPeers
+	// requires that many connected peers (default 0). The embedding
+	// provider must pass its health check unless ReadinessSkipEmbedding is
+	// set. ReadinessRequireWritableStore also requires the vector store to
+	// accept writes.
+	ReadinessMinPeers             int  `json:"readiness_min_peers,omitempty"`
+	ReadinessSkipEmbedding        bool `json:"readiness_skip_embedding,omitempty"`
+	ReadinessRequireWritableStore bool `json:"readiness_require_writable_store,omitempty"`
+
+	// ReadinessSlowProbe is the probe latency above which verbose readiness
+	// reports a working subsystem as slow, e.g. "500ms" (default 1s).
+	ReadinessSlowProbe string `json:"readiness_slow_probe,omitempty"`
+
+	// InterestProfiles are named default interest patterns, e.g. per role.
+	// A profile may extend others. InterestProfile selects this agent's
+	// profile; AGENT_COLLAB_INTEREST_PROFILE overrides it.
+	InterestProfiles interest.Profiles `json:"interest_profiles,omitempty"`
+	InterestProfile  string            `json:"interest_profile,omitempty"`
+
 	// WireGuard VPN settings
 	WireGuard *WireGuardConfig `json:"wireguard,omitempty"`
 }
 
+// ClockSkew parses the allowed invite token clock skew.
+func (c *Config) ClockSkew() (time.Duration, error) {
+	if c.TokenClockSkew == "" {
+		return crypto.DefaultClockSkew, nil
+	}
+	skew, err := time.ParseDuration(c.TokenClockSkew)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token_clock_skew: %w", err)
+	}
+	if skew < 0 {
+		return 0, fmt.Errorf("invalid token_clock_skew: %s is negative", skew)
+	}
+	return skew, nil
+}
+
+// ReadinessSlowThreshold parses the slow probe threshold.
+func (c *Config) ReadinessSlowThreshold() (time.Duration, error) {
+	if c.ReadinessSlowProbe == "" {
+		return DefaultSlowProbe, nil
+	}
+	d, err := time.ParseDuration(c.ReadinessSlowProbe)
+	if err != nil {
+		return 0, fmt.Errorf("invalid readiness_slow_probe: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid readiness_slow_probe %s: must be positive", d)
+	}
+	return d, nil
+}
+
+// LockIdleConfig parses the idle lock settings.
+func (c *Config) LockIdleConfig() (lock.IdleConfig, error) {
+	cfg := lock.DefaultIdleConfig()
+	if c.LockIdleWindow != "" {
+		window, err := time.ParseDuration(c.LockIdleWindow)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid lock_idle_window: %w", err)
+		}
+		cfg.Window = window
+	}
+	if c.LockIdleGrace != "" {
+		grace, err := time.ParseDuration(c.LockIdleGrace)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid lock_idle_grace: %w", err)
+		}
+		cfg.Grace = grace
+	}
+	return cfg, nil
+}
+
+// LockRenewalConfig validates the lock renewal reminder settings.
+func (c *Config) LockRenewalConfig() (lock.RenewalConfig, error) {
+	cfg := lock.DefaultRenewalConfig()
+	if c.LockRenewalReminder < 0 || c.LockRenewalReminder >= 1 {
+		return cfg, fmt.Errorf("invalid lock_renewal_reminder %v: must be a fraction between 0 and 1", c.LockRenewalReminder)
+	}
+	cfg.Threshold = c.LockRenewalReminder
+	return cfg, nil
+}
+
+// ConflictPolicyConfig returns the lock conflict policy settings.
+func (c *Config) ConflictPolicyConfig() lock.PolicyConfig {
+	return lock.PolicyConfig{
+		Policy:          lock.PolicyType(c.ConflictPolicy),
+		Priorities:      c.ConflictPriorities,
+		BudgetThreshold: c.ConflictBudgetThreshold,
+	}
+}
+
+// LockMaxLeaseDuration parses the lock lease limit (0 = no limit).
+func (c *Config) LockMaxLeaseDuration() (time.Duration, error) {
+	if c.LockMaxLease == "" {
+		return 0, nil
+	}
+	maxLease, err := time.ParseDuration(c.LockMaxLease)
+	if err != nil {
+		return 0, fmt.Errorf("invalid lock_max_lease: %w", err)
+	}
+	if maxLease < 0 {
+		return 0, fmt.Errorf("invalid lock_max_lease %s: must not be negative", c.LockMaxLease)
+	}
+	return maxLease, nil
+}
+
+// LockRateLimitConfig builds the per-peer lock request rate limit.
+func (c *Config) LockRateLimitConfig() (*lock.RateLimitConfig, error) {
+	cfg := lock.DefaultRateLimitConfig()
+	if c.LockRateLimit < 0 {
+		return nil, fmt.Errorf("invalid lock_rate_limit %g: must not be negative", c.LockRateLimit)
+	}
+	if c.LockRateLimit > 0 {
+		cfg.Rate = c.LockRateLimit
+	}
+	if c.LockRateBurst < 0 {
+		return nil, fmt.Errorf("invalid lock_rate_burst %d: must not be negative", c.LockRateBurst)
+	}
+	if c.LockRateBurst > 0 {
+		cfg.Burst = c.LockRateBurst
+	}
+	cfg.Adaptive = c.LockRateAdaptive
+	if c.LockRateAdaptiveMin < 0 || c.LockRateAdaptiveMin > 1 {
+		return nil, fmt.Errorf("invalid lock_rate_adaptive_min %g: must be between 0 and 1", c.LockRateAdaptiveMin)
+	}
+	if c.LockRateAdaptiveMin > 0 {
+		cfg.AdaptiveMin = c.LockRateAdaptiveMin
+	}
+	if c.LockRateAdaptiveMax != 0 && c.LockRateAdaptiveMax < 1 {
+		return nil, fmt.Errorf("invalid lock_rate_adaptive_max %g: must be at least 1", c.LockRateAdaptiveMax)
+	}
+	if c.LockRateAdaptiveMax > 0 {
+		cfg.AdaptiveMax = c.LockRateAdaptiveMax
+	}
+	return cfg, nil
+}
+
+// TokenBudgetConfig parses the token budget settings.
+func (c *Config) TokenBudgetConfig() (token.BudgetConfig, error) {
+	cfg := token.BudgetConfig{
+		Project: token.BudgetLimits{
+			Daily:   c.TokenBudgetDaily,
+			Monthly: c.TokenBudgetMonthly,
+		},
+		Agents: c.AgentTokenBudgets,
+		Action: token.BudgetAction(c.TokenBudgetAction),
+	}
+	if c.TokenBudgetThrottleInterval != "" {
+		interval, err := time.ParseDuration(c.TokenBudgetThrottleInterval)
+		if err != nil {
+			return token.BudgetConfig{}, fmt.Errorf("invalid token_budget_throttle_interval: %w", err)
+		}
+		if interval <= 0 {
+			return token.BudgetConfig{}, fmt.Errorf("invalid token_budget_throttle_interval %s: must be positive", c.TokenBudgetThrottleInterval)
+		}
+		cfg.ThrottleInterval = interval
+	}
+	if err := cfg.Validate(); err != nil {
+		return token.BudgetConfig{}, fmt.Errorf("invalid token budget: %w", err)
+	}
+	return cfg, nil
+}
+
+// NegotiationResolutionBuckets parses the negotiation histogram buckets.
+// It returns nil when the defaults should be used.
+func (c *Config) NegotiationResolutionBuckets() ([]time.Duration, error) {
+	if len(c.NegotiationBuckets) == 0 {
+		return nil, nil
+	}
+	bounds := make([]time.Duration, len(c.NegotiationBuckets))
+	for i, v := range c.NegotiationBuckets {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid negotiation_buckets: %w", err)
+		}
+		bounds[i] = d
+	}
+	if err := lock.ValidateResolutionBuckets(bounds); err != nil {
+		return nil, fmt.Errorf("invalid negotiation_buckets: %w", err)
+	}
+	return bounds, nil
+}
+
+// ShareDedupDuration parses the context share dedup window.
+func (c *Config) ShareDedupDuration() (time.Duration, error) {
+	if c.ShareDedupWindow == "" {
+		return DefaultShareDedupWindow, nil
+	}
+	window, err := time.ParseDuration(c.ShareDedupWindow)
+	if err != nil {
+		return 0, fmt.Errorf("invalid share_dedup_window: %w", err)
+	}
+	return window, nil
+}
+
+// DeltaRetentionDuration parses the sync delta retention window.
+func (c *Config) DeltaRetentionDuration() (time.Duration, error) {
+	if c.DeltaRetention == "" {
+		return ctxsync.DefaultDeltaRetention, nil
+	}
+	retention, err := time.ParseDuration(c.DeltaRetention)
+	if err != nil || retention < 0 {
+		return 0, fmt.Errorf("invalid delta_retention: %q", c.DeltaRetention)
+	}
+	return retention, nil
+}
+
+// DeltaCollectionName returns the vector collection file changes are
+// stored in.
+func (c *Config) DeltaCollectionName() string {
+	if c == nil || c.DeltaCollection == "" {
+		return vector.DefaultCollection
+	}
+	return c.DeltaCollection
+}
+
+// CollectionTTLDurations parses collection_ttls by collection name.
+func (c *Config) CollectionTTLDurations() (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration, len(c.CollectionTTLs))
+	for name, raw := range c.CollectionTTLs {
+		if err := vector.ValidateCollectionName(name); err != nil {
+			return nil, fmt.Errorf("invalid collection_ttls: %w", err)
+		}
+		ttl, err := time.ParseDuration(raw)
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("invalid collection_ttls for %s: %q", name, raw)
+		}
+		ttls[name] = ttl
+	}
+	return ttls, nil
+}
+
+// AutoShareConfig parses the auto share settings; nil when auto share is
+// off.
+func (c *Config) AutoShareConfig() (*ctxsync.AutoShareConfig, error) {
+	if !c.AutoShare {
+		return nil, nil
+	}
+
+	cfg := &ctxsync.AutoShareConfig{
+		Root:     c.AutoShareRoot,
+		Ignore:   c.AutoShareIgnore,
+		Debounce: ctxsync.DefaultAutoShareDebounce,
+	}
+	if cfg.Root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("auto_share_root not set: %w", err)
+		}
+		cfg.Root = wd
+	}
+	if c.AutoShareDebounce != "" {
+		debounce, err := time.ParseDuration(c.AutoShareDebounce)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auto_share_debounce: %w", err)
+		}
+		if debounce <= 0 {
+			return nil, fmt.Errorf("invalid auto_share_debounce %s: must be positive", debounce)
+		}
+		cfg.Debounce = debounce
+	}
+	if _, err := ctxsync.NewIgnoreMatcher(cfg.Ignore); err != nil {
+		return nil, fmt.Errorf("invalid auto_share_ignore: %w", err)
+	}
+	return cfg, nil
+}
+
+// LanguageRules parses language_filenames into parser rules, exact file
+// names first and glob patterns after them in sorted order.
+func (c *Config) LanguageRules() ([]ast.FilenameRule, error) {
+	patterns := make([]string, 0, len(c.LanguageFilenames))
+	for pattern := range c.LanguageFilenames {
+		patterns = append(patterns, pattern)
+	}
+	slices.SortFunc(patterns, func(a, b string) int {
+		aGlob, bGlob := strings.ContainsAny(a, "*?["), strings.ContainsAny(b, "*?[")
+		if aGlob != bGlob {
+			if aGlob {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a, b)
+	})
+
+	rules := make([]ast.FilenameRule, 0, len(patterns))
+	for _, pattern := range patterns {
+		lang, err := ast.ParseLanguage(c.LanguageFilenames[pattern])
+		if err != nil {
+			return nil, fmt.Errorf("invalid language_filenames entry %q: %w", pattern, err)
+		}
+		rules = append(rules, ast.FilenameRule{Pattern: pattern, Language: lang})
+	}
+	if _, err := ast.NewLanguageDetector(rules); err != nil {
+		return nil, fmt.Errorf("invalid language_filenames: %w", err)
+	}
+	return rules, nil
+}
+
+// LatencySLAConfig parses the peer latency SLA settings.
+func (c *Config) LatencySLAConfig() (libp2p.LatencySLAConfig, error) {
+	cfg := libp2p.LatencySLAConfig{Window: libp2p.DefaultLatencySLAWindow}
+	if c.PeerLatencySLA != "" {
+		sla, err := time.ParseDuration(c.PeerLatencySLA)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid peer_latency_sla: %w", err)
+		}
+		cfg.Default = sla
+	}
+	if c.PeerLatencySLAWindow != "" {
+		window, err := time.ParseDuration(c.PeerLatencySLAWindow)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid peer_latency_sla_window: %w", err)
+		}
+		cfg.Window = window
+	}
+	for id, v := range c.PeerLatencySLAs {
+		peerID, err := peer.Decode(id)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid peer in peer_latency_slas: %w", err)
+		}
+		sla, err := time.ParseDuration(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid peer_latency_slas for %s: %w", id, err)
+		}
+		if cfg.Peers == nil {
+			cfg.Peers = make(map[peer.ID]time.Duration)
+		}
+		cfg.Peers[peerID] = sla
+	}
+	return cfg, nil
+}
+
+// NATConfig parses the NAT traversal and relay settings.
+func (c *Config) NATConfig() (*libp2p.NATConfig, error) {
+	cfg := libp2p.DefaultNATConfig()
+	cfg.DisableAutoRelay = c.DisableAutoRelay
+	cfg.DisableRelayService = c.DisableRelayService
+	for _, addr := range c.RelayPeers {
+		info, err := peer.AddrInfoFromString(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid relay_peers entry %q: %w", addr, err)
+		}
+		cfg.StaticRelays = append(cfg.StaticRelays, *info)
+	}
+	return &cfg, nil
+}
+
+// BanConfig parses the peer ban settings.
+func (c *Config) BanConfig() (*libp2p.BanConfig, error) {
+	cfg := libp2p.DefaultBanConfig()
+	if c.AutoBanThreshold < 0 {
+		cfg.AutoBanThreshold = 0
+	} else if c.AutoBanThreshold > 0 {
+		cfg.AutoBanThreshold = c.AutoBanThreshold
+	}
+	if c.AutoBanDuration != "" {
+		d, err := time.ParseDuration(c.AutoBanDuration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auto_ban_duration: %w", err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid auto_ban_duration: must be positive")
+		}
+		cfg.AutoBanDuration = d
+	}
+	if c.PeerMessageRate < 0 {
+		cfg.MessageRate = 0
+	} else if c.PeerMessageRate > 0 {
+		cfg.MessageRate = c.PeerMessageRate
+	}
+	if c.PeerMessageBurst < 0 {
+		return nil, fmt.Errorf("invalid peer_message_burst: must not be negative")
+	}
+	if c.PeerMessageBurst > 0 {
+		cfg.MessageBurst = c.PeerMessageBurst
+	}
+	return &cfg, nil
+}
+
+// LocalityConfig returns the locality clustering settings, or nil to
+// leave clustering to the node's defaults.
+func (c *Config) LocalityConfig() *libp2p.LocalityConfig {
+	if c.Region == "" {
+		return nil
+	}
+	cfg := libp2p.DefaultLocalityConfig()
+	cfg.MyRegion = c.Region
+	return &cfg
+}
+
+// GossipThrottleConfig parses the gossip throttle settings. It returns nil
+// when throttling is disabled.
+func (c *Config) GossipThrottleConfig() (*libp2p.GossipThrottleConfig, error) {
+	if !c.GossipThrottle {
+		return nil, nil
+	}
+	cfg := libp2p.DefaultGossipThrottleConfig()
+	if c.GossipThrottleDelay != "" {
+		d, err := time.ParseDuration(c.GossipThrottleDelay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gossip_throttle_delay: %w", err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid gossip_throttle_delay: must be positive")
+		}
+		cfg.BatchDelay = d
+	}
+	return &cfg, nil
+}
+
+// LockPushConfig returns the lock push settings, or nil when lock push is
+// disabled.
+func (c *Config) LockPushConfig() (*libp2p.LockPushConfig, error) {
+	if c.DisableLockPush {
+		return nil, nil
+	}
+	if c.LockPushAttempts < 0 {
+		return nil, fmt.Errorf("invalid lock_push_attempts %d: must not be negative", c.LockPushAttempts)
+	}
+	cfg := libp2p.DefaultLockPushConfig()
+	if c.LockPushAttempts > 0 {
+		cfg.Attempts = c.LockPushAttempts
+	}
+	return &cfg, nil
+}
+
+// PEXConfig parses the peer exchange settings. It returns nil when peer
+// exchange is disabled.
+func (c *Config) PEXConfig() (*libp2p.PEXConfig, error) {
+	if c.DisablePeerExchange {
+		return nil, nil
+	}
+	cfg := libp2p.DefaultPEXConfig()
+	if c.PeerExchangeInterval != "" {
+		interval, err := time.ParseDuration(c.PeerExchangeInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer_exchange_interval: %w", err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid peer_exchange_interval: must be positive")
+		}
+		cfg.Interval = interval
+	}
+	if c.PeerExchangeSampleSize < 0 {
+		return nil, fmt.Errorf("invalid peer_exchange_sample_size: must not be negative")
+	}
+	if c.PeerExchangeSampleSize > 0 {
+		cfg.SampleSize = c.PeerExchangeSampleSize
+	}
+	return &cfg, nil
+}
+
+// EmbeddingServiceConfig builds the embedding service configuration.
+func (c *Config) EmbeddingServiceConfig() (*embedding.Config, error) {
+	provider, err := embedding.ParseProvider(c.EmbeddingProvider)
+	if err != nil {
+		return nil, err
+	}
+	cfg := embedding.ConfigFor(provider)
+
+	if c.EmbeddingModel != "" {
+		cfg.Model = c.EmbeddingModel
+		if dim, ok := embedding.KnownDimension(c.EmbeddingModel); ok {
+			cfg.Dimension = dim
+		}
+	}
+	if c.EmbeddingDimension < 0 {
+		return nil, fmt.Errorf("invalid embedding_dimension %d: must not be negative", c.EmbeddingDimension)
+	}
+	if c.EmbeddingDimension > 0 {
+		cfg.Dimension = c.EmbeddingDimension
+	}
+	if c.EmbeddingAPIKey != "" {
+		cfg.APIKey = c.EmbeddingAPIKey
+	}
+	if c.EmbeddingBaseURL != "" {
+		cfg.BaseURL = strings.TrimRight(c.EmbeddingBaseURL, "/")
+	}
+	if c.EmbeddingBatchSize < 0 {
+		return nil, fmt.Errorf("invalid embedding_batch_size %d: must not be negative", c.EmbeddingBatchSize)
+	}
+	if c.EmbeddingBatchSize > 0 {
+		cfg.BatchSize = c.EmbeddingBatchSize
+	}
+	if c.EmbeddingMaxRetries != 0 {
+		cfg.MaxRetries = max(c.EmbeddingMaxRetries, 0)
+	}
+
+	if cfg.Mode, err = embedding.ParseMode(c.EmbeddingMode); err != nil {
+		return nil, err
+	}
+	cfg.QueryPrefix = c.EmbeddingQueryPrefix
+	cfg.DocumentPrefix = c.EmbeddingDocumentPrefix
+	if cfg.Concurrency, err = c.EmbeddingConcurrency(); err != nil {
+		return nil, err
+	}
+	if cfg.Summary, err = c.EmbeddingSummaryConfig(provider); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// EmbeddingSummaryConfig parses the summarization stage run before
+// documents are embedded by embeddingProvider.
+func (c *Config) EmbeddingSummaryConfig(embeddingProvider embedding.Provider) (embedding.SummaryConfig, error) {
+	var cfg embedding.SummaryConfig
+	mode, err := embedding.ParseSummaryMode(c.EmbeddingSummary)
+	if err != nil {
+		return cfg, err
+	}
+	if c.EmbeddingSummaryTokens < 0 {
+		return cfg, fmt.Errorf("invalid embedding_summary_tokens %d: must not be negative", c.EmbeddingSummaryTokens)
+	}
+	cfg.Mode = mode
+	cfg.MaxTokens = c.EmbeddingSummaryTokens
+	if mode != embedding.SummaryLLM {
+		return cfg, nil
+	}
+
+	cfg.Provider = embeddingProvider
+	if c.EmbeddingSummaryProvider != "" {
+		if cfg.Provider, err = embedding.ParseProvider(c.EmbeddingSummaryProvider); err != nil {
+			return cfg, fmt.Errorf("invalid embedding_summary_provider: %w", err)
+		}
+	}
+	cfg.Model = c.EmbeddingSummaryModel
+	cfg.APIKey = c.EmbeddingSummaryAPIKey
+	if cfg.APIKey == "" && cfg.Provider == embeddingProvider {
+		cfg.APIKey = c.EmbeddingAPIKey
+	}
+	cfg.BaseURL = c.EmbeddingSummaryBaseURL
+	if _, err := embedding.NewSummarizer(cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// EmbeddingConcurrency parses the embedding concurrency bounds.
+func (c *Config) EmbeddingConcurrency() (embedding.ConcurrencyLimit, error) {
+	limit := embedding.DefaultConcurrencyLimit()
+	policy, err := embedding.ParseOverloadPolicy(c.EmbeddingOverloadPolicy)
+	if err != nil {
+		return limit, err
+	}
+	limit.Policy = policy
+	if c.MaxInFlightEmbeddings != 0 {
+		limit.MaxInFlight = max(c.MaxInFlightEmbeddings, 0)
+	}
+	if c.MaxQueuedEmbeddings != 0 {
+		limit.MaxQueued = max(c.MaxQueuedEmbeddings, 0)
+	}
+	return limit, nil
+}
+
 // WireGuardConfig holds WireGuard VPN configuration.
 type WireGuardConfig struct {
 	Enabled             bool   `json:"enabled"`
@@ -26,6 +880,10 @@ type WireGuardConfig struct {
 	MTU                 int    `json:"mtu"`
 	PersistentKeepalive int    `json:"persistent_keepalive"`
 	InterfaceName       string `json:"interface_name"`
+	ExpectedPeers       int    `json:"expected_peers,omitempty"`
+	// RetireReleasedIPs keeps IPs of departed peers out of the free pool;
+	// by default they are reclaimed
+	RetireReleasedIPs bool `json:"retire_released_ips,omitempty"`
 }
 
 // DefaultWireGuardConfig returns default WireGuard configuration.
@@ -56,10 +914,27 @@ func DefaultConfig() *Config {
 	}
 }
 
+// LoadConfig reads the saved config.json from dataDir without starting an
+// app, for commands that inspect the configuration while no daemon runs.
+func LoadConfig(dataDir string) (*Config, error) {
+	configPath := filepath.Join(dataDir, "config.json")
+	// #nosec G304 - configPath is constructed from the data directory, not user input
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	cfg := &Config{DataDir: dataDir}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return cfg, nil
+}
+
 // InitializeOptions holds options for cluster initialization.
 type InitializeOptions struct {
 	ProjectName     string
 	EnableWireGuard bool
 	WireGuardPort   int
 	Subnet          string
+	ExpectedPeers   int
 }
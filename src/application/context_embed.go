@@ -0,0 +1,173 @@
+package application
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"agent-collab/src/domain/ast"
+	"agent-collab/src/domain/ctxsync"
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// ContextGranularity selects which documents a file change is embedded as.
+type ContextGranularity string
+
+const (
+	// ContextGranularityFile embeds one document per file change (default).
+	ContextGranularityFile ContextGranularity = "file"
+	// ContextGranularitySymbol embeds one document per changed symbol.
+	ContextGranularitySymbol ContextGranularity = "symbol"
+	// ContextGranularityBoth embeds the file document and the symbol documents.
+	ContextGranularityBoth ContextGranularity = "both"
+)
+
+const (
+	// snippetContextLines is how many lines around a symbol go into its snippet.
+	snippetContextLines = 2
+	// snippetMaxLines caps the snippet of large symbols.
+	snippetMaxLines = 40
+)
+
+// ParseContextGranularity parses a granularity name; empty means file.
+func ParseContextGranularity(s string) (ContextGranularity, error) {
+	switch ContextGranularity(s) {
+	case "", ContextGranularityFile:
+		return ContextGranularityFile, nil
+	case ContextGranularitySymbol, ContextGranularityBoth:
+		return ContextGranularity(s), nil
+	default:
+		return "", fmt.Errorf("unknown context granularity %q (want %q, %q or %q)",
+			s, ContextGranularityFile, ContextGranularitySymbol, ContextGranularityBoth)
+	}
+}
+
+func (g ContextGranularity) includesFile() bool {
+	return g != ContextGranularitySymbol
+}
+
+func (g ContextGranularity) includesSymbols() bool {
+	return g == ContextGranularitySymbol || g == ContextGranularityBoth
+}
+
+// deltaDocuments builds the documents (without embeddings) for a file change
+// delta at the given granularity. Symbol documents carry the file path and
+// delta ID so search hits can be traced back to the change.
+func deltaDocuments(delta *ctxsync.Delta, granularity ContextGranularity) []*vector.Document {
+	filePath := delta.Payload.FilePath
+	baseMetadata := func() map[string]any {
+		return map[string]any{
+			"source_id":   delta.SourceID,
+			"source_name": delta.SourceName,
+			"delta_id":    delta.ID,
+			"file_path":   filePath,
+			"timestamp":   delta.Timestamp,
+			"type":        "delta_sync",
+		}
+	}
+
+	var diffs []*ast.SymbolDiff
+	if delta.Payload.FileDiff != nil {
+		diffs = delta.Payload.FileDiff.Diffs
+	}
+
+	var docs []*vector.Document
+
+	if granularity.includesFile() {
+		content := fmt.Sprintf("File change: %s from %s", filePath, delta.SourceName)
+		for _, d := range diffs {
+			if d.Symbol != nil {
+				content += fmt.Sprintf("\n%s %s: %s", d.Type, d.Symbol.Type, d.Symbol.Name)
+			}
+		}
+		// Large diffs arrive as a summary of the changed symbols
+		if summary := delta.Payload.DiffSummary; len(diffs) == 0 && summary != nil {
+			for _, s := range summary.Symbols {
+				content += "\n" + s
+			}
+		}
+
+		metadata := baseMetadata()
+		metadata["granularity"] = string(ContextGranularityFile)
+		docs = append(docs, &vector.Document{
+			Content:  content,
+			FilePath: filePath,
+			Metadata: metadata,
+		})
+	}
+
+	if granularity.includesSymbols() {
+		for _, d := range diffs {
+			sym := d.Symbol
+			if sym == nil {
+				continue
+			}
+
+			content := fmt.Sprintf("Symbol change: %s %s %s in %s from %s",
+				d.Type, sym.Type, sym.Name, filePath, delta.SourceName)
+			if d.Type != ast.DiffRemoved {
+				if snippet := readSnippet(filePath, sym.StartLine, sym.EndLine); snippet != "" {
+					content += "\n" + snippet
+				}
+			}
+
+			metadata := baseMetadata()
+			metadata["granularity"] = string(ContextGranularitySymbol)
+			metadata["change_type"] = string(d.Type)
+			docs = append(docs, &vector.Document{
+				Content:    content,
+				FilePath:   filePath,
+				StartLine:  sym.StartLine,
+				EndLine:    sym.EndLine,
+				SymbolType: string(sym.Type),
+				SymbolName: sym.Name,
+				Metadata:   metadata,
+			})
+		}
+	}
+
+	return docs
+}
+
+// readSnippet returns the lines of a symbol plus a little surrounding
+// context, or "" if the file cannot be read (e.g. the change came from a
+// peer whose checkout differs).
+func readSnippet(filePath string, startLine, endLine int) string {
+	if startLine <= 0 || endLine < startLine {
+		return ""
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	from := max(1, startLine-snippetContextLines)
+	to := min(endLine+snippetContextLines, from+snippetMaxLines-1)
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan() && line <= to; line++ {
+		if line >= from {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// embedContent embeds a document for collection, reusing the embedding the
+// vector store already holds for identical content.
+func (a *App) embedContent(ctx context.Context, collection, content string) ([]float32, error) {
+	if collection == "" {
+		collection = vector.DefaultCollection
+	}
+	if cache, ok := a.vectorStore.(vector.EmbeddingCache); ok {
+		if embedding, ok := cache.SharedEmbedding(collection, content); ok {
+			return embedding, nil
+		}
+	}
+	return a.embedService.EmbedDocument(ctx, content)
+}
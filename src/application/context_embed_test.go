@@ -0,0 +1,106 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"agent-collab/src/domain/ast"
+	"agent-collab/src/domain/ctxsync"
+)
+
+func newSymbolDelta(t *testing.T) *ctxsync.Delta {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), "service.go")
+	src := "package svc\n\nfunc Start() {\n\trun()\n}\n\nfunc Stop() {\n\thalt()\n}\n"
+	if err := os.WriteFile(filePath, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	delta := ctxsync.NewDelta(ctxsync.DeltaFileChange, "peer-1", "Agent", ctxsync.NewVectorClock())
+	delta.Payload.FilePath = filePath
+	delta.Payload.FileDiff = &ast.FileDiff{
+		FilePath: filePath,
+		Diffs: []*ast.SymbolDiff{
+			{Type: ast.DiffModified, Symbol: &ast.Symbol{Type: ast.SymbolFunction, Name: "Start", StartLine: 3, EndLine: 5}},
+			{Type: ast.DiffAdded, Symbol: &ast.Symbol{Type: ast.SymbolFunction, Name: "Stop", StartLine: 7, EndLine: 9}},
+		},
+	}
+	return delta
+}
+
+func TestDeltaDocuments_SymbolGranularity(t *testing.T) {
+	delta := newSymbolDelta(t)
+
+	docs := deltaDocuments(delta, ContextGranularitySymbol)
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 symbol documents, got %d", len(docs))
+	}
+
+	want := []struct {
+		name, change, body string
+		start, end         int
+	}{
+		{"Start", "modified", "run()", 3, 5},
+		{"Stop", "added", "halt()", 7, 9},
+	}
+	for i, w := range want {
+		doc := docs[i]
+		if doc.SymbolName != w.name || doc.SymbolType != "function" {
+			t.Errorf("doc %d: expected function %s, got %s %s", i, w.name, doc.SymbolType, doc.SymbolName)
+		}
+		if doc.StartLine != w.start || doc.EndLine != w.end {
+			t.Errorf("doc %d: expected lines %d-%d, got %d-%d", i, w.start, w.end, doc.StartLine, doc.EndLine)
+		}
+		if doc.FilePath != delta.Payload.FilePath || doc.Metadata["file_path"] != delta.Payload.FilePath {
+			t.Errorf("doc %d: expected file path %s, got %s", i, delta.Payload.FilePath, doc.FilePath)
+		}
+		if doc.Metadata["delta_id"] != delta.ID {
+			t.Errorf("doc %d: expected delta_id %s, got %v", i, delta.ID, doc.Metadata["delta_id"])
+		}
+		if doc.Metadata["granularity"] != "symbol" || doc.Metadata["change_type"] != w.change {
+			t.Errorf("doc %d: unexpected metadata %v", i, doc.Metadata)
+		}
+		if !strings.Contains(doc.Content, w.body) {
+			t.Errorf("doc %d: expected snippet with %q, got %q", i, w.body, doc.Content)
+		}
+	}
+}
+
+func TestDeltaDocuments_FileAndBoth(t *testing.T) {
+	delta := newSymbolDelta(t)
+
+	files := deltaDocuments(delta, ContextGranularityFile)
+	if len(files) != 1 || files[0].SymbolName != "" || files[0].Metadata["granularity"] != "file" {
+		t.Fatalf("expected a single file document, got %+v", files)
+	}
+
+	both := deltaDocuments(delta, ContextGranularityBoth)
+	if len(both) != 3 {
+		t.Fatalf("expected file + 2 symbol documents, got %d", len(both))
+	}
+}
+
+func TestDeltaDocuments_MissingFileKeepsSymbolDocs(t *testing.T) {
+	delta := newSymbolDelta(t)
+	delta.Payload.FilePath = "/does/not/exist.go"
+
+	docs := deltaDocuments(delta, ContextGranularitySymbol)
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 symbol documents without a snippet, got %d", len(docs))
+	}
+	if strings.Contains(docs[0].Content, "\n") {
+		t.Errorf("expected no snippet for unreadable file, got %q", docs[0].Content)
+	}
+}
+
+func TestParseContextGranularity(t *testing.T) {
+	if g, err := ParseContextGranularity(""); err != nil || g != ContextGranularityFile {
+		t.Errorf("empty should default to file, got %q, %v", g, err)
+	}
+	if _, err := ParseContextGranularity("line"); err == nil {
+		t.Error("expected error for unknown granularity")
+	}
+}
@@ -0,0 +1,139 @@
+package application
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"agent-collab/src/domain/interest"
+)
+
+// contextShards tracks the context shard topics this node subscribes to,
+// each with its own message processor.
+type contextShards struct {
+	mu     sync.Mutex
+	topics map[string]context.CancelFunc
+}
+
+func newContextShards() *contextShards {
+	return &contextShards{topics: make(map[string]context.CancelFunc)}
+}
+
+// Topics returns the subscribed context shard topics, sorted.
+func (c *contextShards) Topics() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	topics := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		topics = append(topics, topic)
+	}
+	slices.Sort(topics)
+	return topics
+}
+
+// ContextShardTopics returns the context shard topics this node currently
+// subscribes to; empty when context_shard_depth is not set.
+func (a *App) ContextShardTopics() []string {
+	return a.contextShards.Topics()
+}
+
+// startContextShards subscribes to the context shards covering this node's
+// interests and follows interest changes, when context_shard_depth is set.
+func (a *App) startContextShards(ctx context.Context) {
+	if a.config.ContextShardDepth <= 0 || a.interestMgr == nil {
+		return
+	}
+
+	a.interestMgr.OnChange(func(change interest.InterestChange) {
+		if change.Interest != nil && change.Interest.Remote {
+			return
+		}
+		a.resubscribeContextShards(ctx)
+	})
+	a.resubscribeContextShards(ctx)
+	go func() {
+		<-ctx.Done()
+		a.contextShards.mu.Lock()
+		defer a.contextShards.mu.Unlock()
+		for topic, cancel := range a.contextShards.topics {
+			cancel()
+			delete(a.contextShards.topics, topic)
+		}
+	}()
+}
+
+// resubscribeContextShards brings the shard subscriptions in line with the
+// shards the interests cover: every shard for broad interests, else one
+// topic per shard.
+func (a *App) resubscribeContextShards(ctx context.Context) {
+	if ctx.Err() != nil {
+		return
+	}
+	log := a.logger.Component("context-shards")
+	topics := a.topics()
+
+	shards, all := a.interestMgr.Shards(a.config.ContextShardDepth)
+	want := make(map[string]bool)
+	if all {
+		want[topics.ContextAll()] = true
+	} else {
+		for _, shard := range shards {
+			want[topics.ContextShard(shard)] = true
+		}
+	}
+
+	c := a.contextShards
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for topic, cancel := range c.topics {
+		if !want[topic] {
+			// Stop the processor before its subscription goes away
+			cancel()
+			a.node.Unsubscribe(topic)
+			delete(c.topics, topic)
+			log.Info("left context shard", "topic", topic)
+		}
+	}
+	for topic := range want {
+		if _, ok := c.topics[topic]; ok {
+			continue
+		}
+		if _, err := a.node.Subscribe(topic); err != nil {
+			log.Warn("failed to join context shard", "topic", topic, "error", err)
+			continue
+		}
+		shardCtx, cancel := context.WithCancel(ctx)
+		c.topics[topic] = cancel
+		go a.processContextTopic(shardCtx, topic)
+		log.Info("joined context shard", "topic", topic)
+	}
+}
+
+// publishContext publishes a context message about filePath. With sharding
+// it goes to each shard of the path and to the all-shards topic; without,
+// or for messages about no file, to the context sync topic every node
+// subscribes to.
+func (a *App) publishContext(filePath string, data []byte) error {
+	topics := a.topics()
+	depth := a.config.ContextShardDepth
+	if depth <= 0 || filePath == "" {
+		return a.publishSigned(a.ctx, topics.ContextSync(), data)
+	}
+
+	signed, err := a.node.SignMessage(data)
+	if err != nil {
+		return err
+	}
+	targets := []string{topics.ContextAll()}
+	for _, shard := range interest.ShardsForPath(filePath, depth) {
+		targets = append(targets, topics.ContextShard(shard))
+	}
+	for _, topic := range targets {
+		if err := a.node.Publish(a.ctx, topic, signed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package application_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"agent-collab/src/application"
+	"agent-collab/src/domain/interest"
+)
+
+func TestApp_ContextShardsFollowInterests(t *testing.T) {
+	t.Setenv("AGENT_COLLAB_INTERESTS", "")
+	app, err := application.New(&application.Config{DataDir: t.TempDir(), ContextShardDepth: 1})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(context.Background(), "shard-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := app.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	t.Cleanup(func() { app.Stop() })
+
+	// Without interests the node needs every shard
+	if got := app.ContextShardTopics(); !slices.Equal(got, []string{"/agent-collab/context/all"}) {
+		t.Fatalf("expected the all-shards topic, got %v", got)
+	}
+
+	mgr := app.InterestManager()
+	auth := interest.NewInterest("agent-1", "Claude", []string{"src/auth/**", "docs/*.md"})
+	if err := mgr.Register(auth); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/agent-collab/context/shard/docs", "/agent-collab/context/shard/src"}
+	if got := app.ContextShardTopics(); !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if err := app.BroadcastContext("src/auth/login.go", "uses bcrypt", nil, nil); err != nil {
+		t.Errorf("BroadcastContext: %v", err)
+	}
+
+	if err := mgr.Unregister(auth.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.ContextShardTopics(); !slices.Equal(got, []string{"/agent-collab/context/all"}) {
+		t.Errorf("expected the all-shards topic after the interest was removed, got %v", got)
+	}
+}
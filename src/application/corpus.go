@@ -0,0 +1,269 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// CorpusVersion is the format written by ExportCorpus. ImportCorpus
+// rejects corpora from a newer version.
+const CorpusVersion = 1
+
+// corpusBatchSize is how many imported documents are inserted at once.
+const corpusBatchSize = 100
+
+// CorpusHeader is the first line of a corpus file.
+type CorpusHeader struct {
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	NodeID      string    `json:"node_id,omitempty"`
+	ProjectName string    `json:"project_name,omitempty"`
+
+	// Provider, Model and Dimension describe the embeddings in the
+	// corpus. An importing node with a different model embeds the
+	// documents again.
+	Provider  string `json:"provider,omitempty"`
+	Model     string `json:"model,omitempty"`
+	Dimension int    `json:"dimension,omitempty"`
+}
+
+// CorpusSummary describes a corpus file. For ImportCorpus the counts are
+// what the corpus added to this node.
+type CorpusSummary struct {
+	Path        string    `json:"path"`
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	Model       string    `json:"model,omitempty"`
+	Collections int       `json:"collections"`
+	Documents   int       `json:"documents"`
+
+	// Skipped counts documents the node already held. Reembedded counts
+	// documents embedded again with the local model.
+	Skipped    int `json:"skipped,omitempty"`
+	Reembedded int `json:"reembedded,omitempty"`
+}
+
+// ExportCorpus writes the shared context documents, with their embeddings
+// and metadata, to a JSON Lines file at path: a CorpusHeader line followed
+// by one vector.Document per line. An empty collection exports every
+// collection. Existing files are not overwritten.
+func (a *App) ExportCorpus(path, collection string) (*CorpusSummary, error) {
+	if a.vectorStore == nil {
+		return nil, fmt.Errorf("vector store not initialized")
+	}
+	lister, ok := a.vectorStore.(vector.DocumentLister)
+	if !ok {
+		return nil, fmt.Errorf("vector store cannot list documents")
+	}
+
+	names := []string{collection}
+	if collection == "" {
+		var err error
+		if names, err = a.vectorStore.ListCollections(); err != nil {
+			return nil, fmt.Errorf("failed to list collections: %w", err)
+		}
+	}
+
+	header := &CorpusHeader{
+		Version:     CorpusVersion,
+		CreatedAt:   time.Now(),
+		ProjectName: a.config.ProjectName,
+	}
+	if a.node != nil {
+		header.NodeID = a.node.ID().String()
+	}
+	if a.embedService != nil {
+		header.Provider = string(a.embedService.Provider())
+		header.Model = a.embedService.Model()
+		header.Dimension = a.embedService.Dimension()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create corpus dir: %w", err)
+	}
+	summary := &CorpusSummary{
+		Path:      path,
+		Version:   header.Version,
+		CreatedAt: header.CreatedAt,
+		Model:     header.Model,
+	}
+	err := writeCorpus(path, func(enc *json.Encoder) error {
+		if err := enc.Encode(header); err != nil {
+			return err
+		}
+		for _, name := range names {
+			docs, err := lister.ListDocuments(name)
+			if err != nil {
+				return fmt.Errorf("failed to read collection %s: %w", name, err)
+			}
+			if len(docs) > 0 {
+				summary.Collections++
+			}
+			for _, doc := range docs {
+				doc.Collection = name
+				if err := enc.Encode(doc); err != nil {
+					return err
+				}
+				summary.Documents++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// writeCorpus creates path and hands write a JSON encoder for it. The file
+// is removed if writing fails.
+func writeCorpus(path string, write func(*json.Encoder) error) (err error) {
+	// #nosec G304 - path is chosen by the operator exporting the corpus
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create corpus: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	if err := write(json.NewEncoder(f)); err != nil {
+		return fmt.Errorf("failed to write corpus: %w", err)
+	}
+	return nil
+}
+
+// ImportCorpus loads a corpus written by ExportCorpus into the vector
+// store. Documents the store already holds are skipped. Documents are
+// embedded again with the local model when the corpus was embedded with a
+// different model or dimension, or when reembed is set; otherwise their
+// embeddings are used as they are.
+func (a *App) ImportCorpus(ctx context.Context, path string, reembed bool) (*CorpusSummary, error) {
+	if a.IsObserver() {
+		return nil, ErrObserverMode
+	}
+	if a.vectorStore == nil {
+		return nil, fmt.Errorf("vector store not initialized")
+	}
+
+	// #nosec G304 - path is chosen by the operator importing the corpus
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open corpus: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	var header CorpusHeader
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("failed to read corpus header: %w", err)
+	}
+	switch {
+	case header.Version == 0:
+		return nil, errors.New("corpus has no version")
+	case header.Version > CorpusVersion:
+		return nil, fmt.Errorf("corpus version %d is newer than supported version %d", header.Version, CorpusVersion)
+	}
+
+	if a.embedService != nil && (header.Model != a.embedService.Model() || header.Dimension != a.embedService.Dimension()) {
+		reembed = true
+	}
+	if reembed && a.embedService == nil {
+		return nil, fmt.Errorf("embedding service not initialized")
+	}
+
+	existing, err := a.vectorStore.ListCollections()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	known := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		known[name] = true
+	}
+
+	summary := &CorpusSummary{
+		Path:      path,
+		Version:   header.Version,
+		CreatedAt: header.CreatedAt,
+		Model:     header.Model,
+	}
+	touched := make(map[string]bool)
+	var batch []*vector.Document
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := a.vectorStore.InsertBatch(batch); err != nil {
+			return fmt.Errorf("failed to import documents: %w", err)
+		}
+		summary.Documents += len(batch)
+		batch = nil
+		return nil
+	}
+
+	for line := 2; ; line++ {
+		var doc vector.Document
+		if err := dec.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return summary, fmt.Errorf("failed to read corpus line %d: %w", line, err)
+		}
+		if doc.ID == "" {
+			continue
+		}
+		if doc.Collection == "" {
+			doc.Collection = vector.DefaultCollection
+		}
+		if _, err := a.vectorStore.Get(doc.Collection, doc.ID); err == nil {
+			summary.Skipped++
+			continue
+		}
+
+		if reembed {
+			embedding, err := a.embedContent(ctx, doc.Collection, doc.Content)
+			if err != nil {
+				return summary, fmt.Errorf("failed to embed document %s: %w", doc.ID, err)
+			}
+			doc.Embedding = embedding
+			summary.Reembedded++
+		}
+
+		if !known[doc.Collection] {
+			if err := a.vectorStore.CreateCollection(doc.Collection, len(doc.Embedding)); err != nil {
+				return summary, fmt.Errorf("failed to create collection %s: %w", doc.Collection, err)
+			}
+			known[doc.Collection] = true
+		}
+		if !touched[doc.Collection] {
+			touched[doc.Collection] = true
+			summary.Collections++
+		}
+
+		batch = append(batch, &doc)
+		if len(batch) >= corpusBatchSize {
+			if err := flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return summary, err
+	}
+	if err := a.vectorStore.Flush(); err != nil {
+		return summary, fmt.Errorf("flush failed: %w", err)
+	}
+	return summary, nil
+}
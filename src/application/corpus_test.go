@@ -0,0 +1,104 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-collab/src/application"
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+func TestApp_ExportImportCorpus(t *testing.T) {
+	ctx := context.Background()
+	source := newSnapshotApp(t)
+	dim := source.EmbeddingService().Dimension()
+
+	for _, doc := range []*vector.Document{
+		{ID: "doc-1", Collection: "default", Content: "func Login() error", Embedding: make([]float32, dim), FilePath: "auth/login.go"},
+		{ID: "doc-2", Collection: "default", Content: "func Logout() error", Embedding: make([]float32, dim), Metadata: map[string]any{"branch": "main"}},
+	} {
+		if err := source.VectorStore().Insert(doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "out", "corpus.jsonl")
+	exported, err := source.ExportCorpus(path, "")
+	if err != nil {
+		t.Fatalf("ExportCorpus: %v", err)
+	}
+	if exported.Documents != 2 || exported.Collections != 1 {
+		t.Errorf("unexpected export summary: %+v", exported)
+	}
+	if _, err := source.ExportCorpus(path, ""); err == nil {
+		t.Error("expected an existing corpus not to be overwritten")
+	}
+
+	target := newSnapshotApp(t)
+	if err := target.VectorStore().Insert(&vector.Document{
+		ID: "doc-1", Collection: "default", Content: "func Login() error", Embedding: make([]float32, dim),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := target.ImportCorpus(ctx, path, false)
+	if err != nil {
+		t.Fatalf("ImportCorpus: %v", err)
+	}
+	if imported.Documents != 1 || imported.Skipped != 1 || imported.Reembedded != 0 {
+		t.Errorf("unexpected import summary: %+v", imported)
+	}
+	doc, err := target.VectorStore().Get("default", "doc-2")
+	if err != nil {
+		t.Fatalf("expected the missing document to be imported: %v", err)
+	}
+	if doc.Metadata["branch"] != "main" || len(doc.Embedding) != dim {
+		t.Errorf("expected metadata and embedding to be kept, got %+v", doc)
+	}
+}
+
+func TestApp_ImportCorpusReembedsOtherModels(t *testing.T) {
+	app := newSnapshotApp(t)
+
+	path := filepath.Join(t.TempDir(), "corpus.jsonl")
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.Encode(application.CorpusHeader{Version: application.CorpusVersion, CreatedAt: time.Now(), Model: "other-model", Dimension: 3})
+	enc.Encode(vector.Document{ID: "doc-1", Collection: "notes", Content: "retry with backoff", Embedding: []float32{0.1, 0.2, 0.3}})
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := app.ImportCorpus(context.Background(), path, false)
+	if err != nil {
+		t.Fatalf("ImportCorpus: %v", err)
+	}
+	if imported.Reembedded != 1 || imported.Collections != 1 {
+		t.Errorf("unexpected import summary: %+v", imported)
+	}
+	doc, err := app.VectorStore().Get("notes", "doc-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Embedding) != app.EmbeddingService().Dimension() {
+		t.Errorf("expected the local model's dimension, got %d", len(doc.Embedding))
+	}
+}
+
+func TestApp_ImportCorpusRejectsNewerVersion(t *testing.T) {
+	app := newSnapshotApp(t)
+
+	path := filepath.Join(t.TempDir(), "corpus.jsonl")
+	data, _ := json.Marshal(application.CorpusHeader{Version: application.CorpusVersion + 1})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.ImportCorpus(context.Background(), path, false); err == nil {
+		t.Error("expected a newer corpus version to be rejected")
+	}
+}
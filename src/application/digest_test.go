@@ -0,0 +1,55 @@
+package application_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"agent-collab/src/application"
+	"agent-collab/src/domain/event"
+)
+
+func TestApp_DigestCondensesWithContextSummarizer(t *testing.T) {
+	app, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(context.Background(), "digest-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	t.Cleanup(func() { app.Stop() })
+
+	since := time.Now().Add(-time.Minute)
+	ctx := context.Background()
+	router := app.EventRouter()
+	for i := 0; i < 200; i++ {
+		ev := event.NewFileChangeEvent(fmt.Sprintf("agent-%d", i), fmt.Sprintf("agent %d", i),
+			fmt.Sprintf("src/pkg%d/file%d.go", i, i),
+			&event.FileChangePayload{ChangeType: "modify", Summary: "refactored the request handling"})
+		if err := router.PublishLocal(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plain, err := router.Digest(ctx, since, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Condensed != "" {
+		t.Errorf("expected no condensed text without condense, got %q", plain.Condensed)
+	}
+
+	// Summarization is off by default, so the local template summary is used
+	digest, err := router.Digest(ctx, since, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest.Condensed == "" {
+		t.Fatal("expected condense to produce a condensed digest")
+	}
+	if len(digest.Condensed) >= len(digest.Format()) {
+		t.Errorf("expected the condensed digest (%d bytes) to be shorter than the full one (%d bytes)",
+			len(digest.Condensed), len(digest.Format()))
+	}
+}
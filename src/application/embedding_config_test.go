@@ -0,0 +1,69 @@
+package application
+
+import (
+	"testing"
+
+	"agent-collab/src/infrastructure/embedding"
+)
+
+func TestConfig_EmbeddingServiceConfig(t *testing.T) {
+	cfg, err := (&Config{}).EmbeddingServiceConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Provider != embedding.ProviderMock {
+		t.Errorf("expected the mock provider by default, got %s", cfg.Provider)
+	}
+
+	cfg, err = (&Config{
+		EmbeddingProvider:   "ollama",
+		EmbeddingModel:      "mxbai-embed-large",
+		EmbeddingBaseURL:    "http://gpu-box:11434/",
+		EmbeddingBatchSize:  16,
+		EmbeddingMaxRetries: -1,
+	}).EmbeddingServiceConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Provider != embedding.ProviderOllama || cfg.Model != "mxbai-embed-large" || cfg.Dimension != 1024 {
+		t.Errorf("expected ollama with the model's known dimension, got %+v", cfg)
+	}
+	if cfg.BaseURL != "http://gpu-box:11434" || cfg.BatchSize != 16 || cfg.MaxRetries != 0 {
+		t.Errorf("expected endpoint, batch size and disabled retries to apply, got %+v", cfg)
+	}
+
+	cfg, err = (&Config{EmbeddingProvider: "openai", EmbeddingAPIKey: "sk-test", EmbeddingModel: "custom-embed", EmbeddingDimension: 256}).EmbeddingServiceConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIKey != "sk-test" || cfg.Dimension != 256 || cfg.MaxRetries != 3 {
+		t.Errorf("expected the configured key and dimension with default retries, got %+v", cfg)
+	}
+
+	if _, err := (&Config{EmbeddingProvider: "cohere"}).EmbeddingServiceConfig(); err == nil {
+		t.Error("expected an unknown provider to be rejected")
+	}
+}
+
+func TestConfig_EmbeddingSummaryConfig(t *testing.T) {
+	cfg, err := (&Config{EmbeddingProvider: "openai", EmbeddingAPIKey: "sk-test", EmbeddingSummary: "llm", EmbeddingSummaryTokens: 128}).EmbeddingServiceConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Summary.Mode != embedding.SummaryLLM || cfg.Summary.MaxTokens != 128 {
+		t.Errorf("expected llm summaries of 128 tokens, got %+v", cfg.Summary)
+	}
+	if cfg.Summary.Provider != embedding.ProviderOpenAI || cfg.Summary.APIKey != "sk-test" {
+		t.Errorf("expected the embedding provider and key to be reused, got %+v", cfg.Summary)
+	}
+
+	for _, bad := range []*Config{
+		{EmbeddingSummary: "abstract"},
+		{EmbeddingSummary: "template", EmbeddingSummaryTokens: -1},
+		{EmbeddingProvider: "google", EmbeddingSummary: "llm"},
+	} {
+		if _, err := bad.EmbeddingServiceConfig(); err == nil {
+			t.Errorf("expected %+v to be rejected", bad)
+		}
+	}
+}
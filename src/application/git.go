@@ -0,0 +1,60 @@
+package application
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+
+	"agent-collab/src/infrastructure/git"
+)
+
+// Metadata keys recording the git branch and commit context was shared on.
+const (
+	MetaGitBranch = "git_branch"
+	MetaGitCommit = "git_commit"
+)
+
+// BranchFilter returns the vector search filter matching context shared on
+// branch, or nil for any branch.
+func BranchFilter(branch string) map[string]any {
+	if branch == "" {
+		return nil
+	}
+	return map[string]any{MetaGitBranch: branch}
+}
+
+// withGitHead returns metadata tagged with the branch and commit checked
+// out in the repository holding filePath, keeping tags the caller set.
+// Relative paths are resolved against auto_share_root or the working
+// directory. Outside a repository metadata is returned as is.
+func (a *App) withGitHead(metadata map[string]any, filePath string) map[string]any {
+	dir := a.config.AutoShareRoot
+	if filepath.IsAbs(filePath) {
+		dir = filepath.Dir(filePath)
+	}
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return metadata
+		}
+		dir = wd
+	}
+	repo, err := git.Open(dir)
+	if err != nil {
+		return metadata
+	}
+	head := repo.Head()
+	if head.Branch == "" && head.Commit == "" {
+		return metadata
+	}
+
+	tagged := make(map[string]any, len(metadata)+2)
+	maps.Copy(tagged, metadata)
+	if _, ok := tagged[MetaGitBranch]; !ok && head.Branch != "" {
+		tagged[MetaGitBranch] = head.Branch
+	}
+	if _, ok := tagged[MetaGitCommit]; !ok && head.Commit != "" {
+		tagged[MetaGitCommit] = head.Commit
+	}
+	return tagged
+}
@@ -0,0 +1,63 @@
+package application_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"agent-collab/src/application"
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+func TestApp_ShareContextRecordsGitBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	file := filepath.Join(repo, "auth.go")
+	if err := os.WriteFile(file, []byte("package auth\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=feature/login"},
+		{"-c", "user.email=dev@example.com", "-c", "user.name=dev", "commit", "--quiet", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	app := startSharingApp(t, "")
+	ctx := context.Background()
+	result, err := app.ShareContext(ctx, file, "Switched login to bcrypt", nil)
+	if err != nil {
+		t.Fatalf("share failed: %v", err)
+	}
+	doc, err := app.VectorStore().Get("default", result.DocumentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Metadata[application.MetaGitBranch] != "feature/login" {
+		t.Errorf("expected the branch to be recorded, got %v", doc.Metadata)
+	}
+	if commit, _ := doc.Metadata[application.MetaGitCommit].(string); len(commit) != 40 {
+		t.Errorf("expected the commit to be recorded, got %v", doc.Metadata)
+	}
+
+	for branch, want := range map[string]int{"feature/login": 1, "main": 0} {
+		results, err := app.VectorStore().Search(result.Embedding, &vector.SearchOptions{
+			TopK:    10,
+			Filters: application.BranchFilter(branch),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != want {
+			t.Errorf("branch %q: expected %d results, got %d", branch, want, len(results))
+		}
+	}
+}
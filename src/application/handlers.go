@@ -4,9 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"agent-collab/src/domain/ctxsync"
+	"agent-collab/src/domain/event"
 	"agent-collab/src/domain/lock"
+	"agent-collab/src/domain/presence"
+	"agent-collab/src/infrastructure/network/libp2p"
 	"agent-collab/src/infrastructure/storage/vector"
 )
 
@@ -14,22 +19,54 @@ import (
 func (a *App) setupMessageHandlers() {
 	// 락 서비스 브로드캐스트 설정
 	a.lockService.SetBroadcastFn(func(msg any) error {
+		if release, ok := msg.(lock.ReleaseMessage); ok && release.Force != nil && release.Force.PreemptedBy == nil {
+			msg = ReleaseMessageWrapper{Type: release.Type, LockID: release.LockID, Force: release.Force, Operator: a.operatorProof()}
+		}
 		data, err := json.Marshal(msg)
 		if err != nil {
 			return err
 		}
-		topicName := "/agent-collab/" + a.config.ProjectName + "/lock"
-		return a.node.Publish(a.ctx, topicName, data)
+		return a.publishSigned(a.ctx, lockTopicFor(a.topics(), data), data)
 	})
 
 	// 동기화 관리자 브로드캐스트 설정
 	a.syncManager.SetBroadcastFn(func(delta *ctxsync.Delta) error {
+		a.syncSymbolLocks(delta)
+		// Auto shared changes are also searchable on this node; peers embed
+		// the delta when they receive it
+		if a.config.AutoShare {
+			go a.storeDeltaInVectorDB(a.ctx, delta)
+		}
 		data, err := json.Marshal(delta)
 		if err != nil {
 			return err
 		}
-		topicName := "/agent-collab/" + a.config.ProjectName + "/context"
-		return a.node.Publish(a.ctx, topicName, data)
+		filePath := ""
+		if delta.Payload != nil {
+			filePath = delta.Payload.FilePath
+		}
+		return a.publishContext(filePath, data)
+	})
+
+	// 큰 diff 요청/응답 전송 설정
+	a.syncManager.SetDiffRequestFn(func(msg any) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		return a.publishSigned(a.ctx, a.topics().ContextSync(), data)
+	})
+	if a.config.MaxDiffBytes != 0 {
+		a.syncManager.SetMaxDiffBytes(a.config.MaxDiffBytes)
+	}
+
+	// 현재 편집 위치 브로드캐스트 설정
+	a.presence.SetBroadcastFn(func(msg any) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		return a.node.Publish(a.ctx, a.topics().Presence(), data)
 	})
 
 	// 충돌 핸들러 설정
@@ -39,15 +76,75 @@ func (a *App) setupMessageHandlers() {
 			"requested_by", conflict.RequestedLock.HolderName,
 			"conflicting_with", conflict.ConflictingLock.HolderName,
 			"overlap_type", conflict.OverlapType)
+		a.recordLockConflict(conflict)
 		return nil
 	})
 
 	a.syncManager.SetConflictHandler(func(conflict *ctxsync.Conflict) error {
+		if r := conflict.Resolution; r != nil {
+			conflictLog.Warn("concurrent modification conflict",
+				"file_path", conflict.FilePath,
+				"winner", r.Winner.SourceName,
+				"winner_hash", r.Winner.Hash,
+				"reason", r.Reason)
+			return nil
+		}
 		conflictLog.Warn("concurrent modification conflict", "file_path", conflict.FilePath)
 		return nil
 	})
 }
 
+// recordLockConflict stores a lock conflict in the event log so it shows
+// up in digests and the conflict heatmap. It runs while the negotiator
+// holds its lock, so delivery happens in the background.
+func (a *App) recordLockConflict(conflict *lock.LockConflict) {
+	router := a.eventRouter
+	if router == nil || conflict.RequestedLock.Target == nil {
+		return
+	}
+
+	holder := conflict.ConflictingLock.HolderName
+	if holder == "" {
+		holder = conflict.ConflictingLock.HolderID
+	}
+	e := event.NewLockConflictEvent(conflict.RequestedLock.HolderID, conflict.RequestedLock.HolderName,
+		conflict.RequestedLock.Target.FilePath, &event.LockConflictPayload{
+			RequestedLockID:   conflict.RequestedLock.ID,
+			ConflictingLockID: conflict.ConflictingLock.ID,
+			OverlapType:       conflict.OverlapType,
+			ConflictingHolder: holder,
+		})
+	go router.PublishLocal(a.ctx, e)
+}
+
+// handleLatencyAlert logs a peer crossing its latency SLA and publishes it
+// as a warning event.
+func (a *App) handleLatencyAlert(alert libp2p.LatencyAlert) {
+	log := a.logger.Component("peer-quality")
+	payload := &event.WarningPayload{Level: "info"}
+	if alert.Degraded {
+		log.Warn("peer latency above SLA",
+			"peer_id", alert.PeerID.String(),
+			"rtt", alert.RTT,
+			"sla", alert.SLA,
+			"since", alert.Since)
+		payload.Level = "warning"
+		payload.Message = fmt.Sprintf("peer %s latency above SLA", alert.PeerID.ShortString())
+		payload.Details = fmt.Sprintf("rtt %s > sla %s since %s", alert.RTT, alert.SLA, alert.Since.Format(time.RFC3339))
+	} else {
+		log.Info("peer latency back within SLA", "peer_id", alert.PeerID.String(), "rtt", alert.RTT)
+		payload.Message = fmt.Sprintf("peer %s latency back within SLA", alert.PeerID.ShortString())
+		payload.Details = fmt.Sprintf("rtt %s", alert.RTT)
+	}
+
+	router := a.eventRouter
+	if router == nil {
+		return
+	}
+	sourceID, sourceName := a.eventSource()
+	router.PublishLocal(a.ctx, event.NewWarningEvent(sourceID, sourceName, payload))
+}
+
 // LockMessageBase is a base type for determining message type.
 type LockMessageBase struct {
 	Type string `json:"type"`
@@ -66,29 +163,82 @@ type AcquireMessageWrapper struct {
 }
 
 // ReleaseMessageWrapper matches the format from lock.ReleaseMessage.
+// Operator force releases also carry the operator's admin proof.
 type ReleaseMessageWrapper struct {
-	Type   string `json:"type"`
-	LockID string `json:"lock_id"`
+	Type     string                   `json:"type"`
+	LockID   string                   `json:"lock_id"`
+	Force    *lock.ForceReleaseNotice `json:"force,omitempty"`
+	Operator *OperatorProof           `json:"operator,omitempty"`
+}
+
+// lockTopicFor returns the lock topic a lock message is published on.
+// Intents and releases have their own topics; everything else, including
+// recovery requests, goes to the acquire topic.
+func lockTopicFor(topics libp2p.TopicSet, data []byte) string {
+	var base LockMessageBase
+	_ = json.Unmarshal(data, &base)
+
+	switch base.Type {
+	case "lock_intent":
+		return topics.LockIntent()
+	case "lock_released":
+		return topics.LockRelease()
+	default:
+		return topics.LockAcquire()
+	}
 }
 
 // processLockMessages processes incoming lock messages from P2P network.
 func (a *App) processLockMessages(ctx context.Context) {
-	topicName := "/agent-collab/" + a.config.ProjectName + "/lock"
+	log := a.logger.Component("lock-processor")
+	var wg sync.WaitGroup
+	for _, topicName := range a.topics().LockTopics() {
+		processor := NewMessageProcessor(
+			a.node,
+			topicName,
+			func(_ context.Context, data []byte) {
+				if payload, signer, ok := a.openMessage(data, log); ok {
+					a.handleSingleLockMessage(payload, signer)
+				}
+			},
+			log,
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			processor.Run(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// processPresenceMessages applies active edit announcements from peers.
+func (a *App) processPresenceMessages(ctx context.Context) {
+	log := a.logger.Component("presence-processor")
 	processor := NewMessageProcessor(
 		a.node,
-		topicName,
+		a.topics().Presence(),
 		func(_ context.Context, data []byte) {
-			a.handleSingleLockMessage(data)
+			var msg presence.Message
+			if UnmarshalMessage(data, &msg, "presence message", log) != UnmarshalOK {
+				return
+			}
+			a.presence.HandleRemote(&msg)
 		},
-		a.logger.Component("lock-processor"),
+		log,
 	)
 	processor.Run(ctx)
 }
 
-// handleSingleLockMessage processes a single lock message
-func (a *App) handleSingleLockMessage(data []byte) {
+// handleSingleLockMessage processes a single lock message. signer is the
+// node that signed it, or empty for an unsigned message allowed by
+// allow_unsigned_messages; messages speaking for another node are dropped.
+func (a *App) handleSingleLockMessage(data []byte, signer string) {
 	log := a.logger.Component("lock-handler")
 
+	start := time.Now()
+	defer a.procMetrics.ObserveSince(StageLockTotal, start)
+
 	var baseMsg LockMessageBase
 	if UnmarshalMessage(data, &baseMsg, "lock message type", log) != UnmarshalOK {
 		return
@@ -100,33 +250,257 @@ func (a *App) handleSingleLockMessage(data []byte) {
 		if UnmarshalMessagePtr(data, &msg, func(m *IntentMessageWrapper) *lock.LockIntent { return m.Intent }, "lock intent", log) != UnmarshalOK {
 			return
 		}
+		if msg.Intent.Lock == nil || !signedBy(signer, msg.Intent.Lock.HolderID, "lock intent", log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageLockDecode, start)
 		if err := a.lockService.HandleRemoteLockIntent(msg.Intent); err != nil {
 			log.Error("failed to handle lock intent", "error", err)
 		}
+		a.procMetrics.ObserveSince(StageLockApply, applyStart)
 
 	case "lock_acquired":
 		var msg AcquireMessageWrapper
 		if UnmarshalMessagePtr(data, &msg, func(m *AcquireMessageWrapper) *lock.SemanticLock { return m.Lock }, "acquired lock", log) != UnmarshalOK {
 			return
 		}
+		if !signedBy(signer, msg.Lock.HolderID, "acquired lock", log) {
+			return
+		}
+		// Only the current holder re-announces a lock under its ID
+		if held, err := a.lockService.GetLock(msg.Lock.ID); err == nil && !signedBy(signer, held.HolderID, "acquired lock", log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageLockDecode, start)
 		if err := a.lockService.HandleRemoteLockAcquired(msg.Lock); err != nil {
 			log.Error("failed to handle lock acquired", "error", err)
 		}
+		a.procMetrics.ObserveSince(StageLockApply, applyStart)
 
 	case "lock_released":
 		var msg ReleaseMessageWrapper
 		if UnmarshalMessage(data, &msg, "lock release", log) != UnmarshalOK {
 			return
 		}
-		if err := a.lockService.HandleRemoteLockReleased(msg.LockID); err != nil {
+		// Only the holder releases a lock; admins and conflict policies
+		// break locks held by other nodes with a force release
+		if msg.Force == nil {
+			if held, err := a.lockService.GetLock(msg.LockID); err == nil && !signedBy(signer, held.HolderID, "lock release", log) {
+				return
+			}
+		} else if !a.forceReleaseAllowed(&msg, signer, log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageLockDecode, start)
+		if msg.Force != nil {
+			a.handleForceRelease(msg.Force)
+		} else if err := a.lockService.HandleRemoteLockReleased(msg.LockID); err != nil {
 			log.Error("failed to handle lock released", "error", err)
 		}
+		a.procMetrics.ObserveSince(StageLockApply, applyStart)
+
+	case "lock_renewed":
+		var msg lock.RenewMessage
+		if UnmarshalMessage(data, &msg, "lock renewal", log) != UnmarshalOK {
+			return
+		}
+		if !signedBy(signer, msg.HolderID, "lock renewal", log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageLockDecode, start)
+		a.lockService.HandleRemoteLockRenewed(&msg)
+		a.procMetrics.ObserveSince(StageLockApply, applyStart)
+
+	case "negotiation_cancelled":
+		var msg lock.NegotiationCancelMessage
+		if UnmarshalMessage(data, &msg, "negotiation cancel", log) != UnmarshalOK {
+			return
+		}
+		if !signedBy(signer, msg.RequesterID, "negotiation cancel", log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageLockDecode, start)
+		if a.lockService.HandleRemoteNegotiationCancelled(&msg) {
+			log.Info("negotiation cancelled by requester", "session_id", msg.SessionID, "requester", msg.RequesterID)
+		}
+		a.procMetrics.ObserveSince(StageLockApply, applyStart)
+
+	case "node_shutting_down":
+		var msg NodeShutdownMessage
+		if UnmarshalMessage(data, &msg, "node shutdown", log) != UnmarshalOK {
+			return
+		}
+		if !signedBy(signer, msg.NodeID, "node shutdown", log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageLockDecode, start)
+		a.handleNodeShutdown(&msg)
+		a.procMetrics.ObserveSince(StageLockApply, applyStart)
+
+	case "node_observer":
+		var msg NodeObserverMessage
+		if UnmarshalMessage(data, &msg, "node observer", log) != UnmarshalOK {
+			return
+		}
+		if !signedBy(signer, msg.NodeID, "node observer", log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageLockDecode, start)
+		a.handleNodeObserver(&msg)
+		a.procMetrics.ObserveSince(StageLockApply, applyStart)
+
+	case "lock_state_request":
+		var msg lock.LockStateRequest
+		if UnmarshalMessage(data, &msg, "lock state request", log) != UnmarshalOK {
+			return
+		}
+		if !signedBy(signer, msg.RequestorID, "lock state request", log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageLockDecode, start)
+		result, err := a.lockService.HandleLockStateRequest(&msg)
+		if err != nil {
+			log.Error("failed to answer lock state request", "error", err)
+		}
+		a.handleLockReconciled(result)
+		a.procMetrics.ObserveSince(StageLockApply, applyStart)
+
+	case "lock_state_response":
+		var msg lock.LockStateResponse
+		if UnmarshalMessage(data, &msg, "lock state response", log) != UnmarshalOK {
+			return
+		}
+		if !signedBy(signer, msg.ResponderID, "lock state response", log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageLockDecode, start)
+		a.handleLockReconciled(a.lockService.HandleLockStateResponse(&msg))
+		a.procMetrics.ObserveSince(StageLockApply, applyStart)
 
 	default:
 		log.Warn("unknown lock message type", "type", baseMsg.Type)
 	}
 }
 
+// forceReleaseAllowed reports whether a force release may be applied. It
+// must be signed: policy releases by the holder of the preempting lock,
+// which this node's policy must agree wins, and operator releases by an
+// admin proving a grant from the cluster's root.
+func (a *App) forceReleaseAllowed(msg *ReleaseMessageWrapper, signer string, log Logger) bool {
+	if signer == "" {
+		log.Warn("rejected unsigned force release", "lock_id", msg.LockID)
+		return false
+	}
+	if msg.Force.LockID != msg.LockID {
+		log.Warn("rejected force release for a different lock", "lock_id", msg.LockID, "notice_lock_id", msg.Force.LockID)
+		return false
+	}
+
+	if preempting := msg.Force.PreemptedBy; preempting != nil {
+		if !signedBy(signer, preempting.HolderID, "policy release", log) {
+			return false
+		}
+		if err := a.lockService.CheckPreemption(msg.Force); err != nil {
+			log.Warn("rejected policy release", "lock_id", msg.LockID, "signer", signer, "error", err)
+			return false
+		}
+		return true
+	}
+
+	if err := a.verifyOperator(msg.Operator, signer); err != nil {
+		log.Warn("rejected force release from a non-admin", "lock_id", msg.LockID, "signer", signer, "error", err)
+		return false
+	}
+	return true
+}
+
+// handleForceRelease drops a lock broken by an operator on another node and
+// notifies the local handler, which matters most when this node was the holder.
+func (a *App) handleForceRelease(notice *lock.ForceReleaseNotice) {
+	log := a.logger.Component("lock-handler")
+
+	if err := a.lockService.HandleRemoteForceRelease(notice); err != nil {
+		log.Error("failed to handle force release", "error", err)
+		return
+	}
+	log.Warn("lock force released by operator",
+		"lock_id", notice.LockID,
+		"holder", notice.HolderName,
+		"operator", notice.Operator,
+		"reason", notice.Reason)
+
+	a.mu.RLock()
+	handler := a.onForceRelease
+	a.mu.RUnlock()
+	if handler != nil {
+		handler(notice)
+	}
+}
+
+// SetForceReleaseHandler sets the callback invoked when a peer force releases a lock.
+func (a *App) SetForceReleaseHandler(handler func(*lock.ForceReleaseNotice)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onForceRelease = handler
+}
+
+// handleLockIdle logs idle lock warnings and releases and notifies the local
+// handler so the holder learns about them.
+func (a *App) handleLockIdle(notice *lock.IdleNotice) {
+	log := a.logger.Component("lock-handler")
+	if notice.Released {
+		log.Warn("idle lock released",
+			"lock_id", notice.LockID,
+			"target", notice.Target,
+			"idle_for", notice.IdleFor)
+	} else {
+		log.Warn("lock is idle and will be released",
+			"lock_id", notice.LockID,
+			"target", notice.Target,
+			"idle_for", notice.IdleFor,
+			"release_at", notice.ReleaseAt)
+	}
+
+	a.mu.RLock()
+	handler := a.onLockIdle
+	a.mu.RUnlock()
+	if handler != nil {
+		handler(notice)
+	}
+}
+
+// SetLockIdleHandler sets the callback invoked when a lock held by this node
+// is warned about or released for inactivity.
+func (a *App) SetLockIdleHandler(handler func(*lock.IdleNotice)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onLockIdle = handler
+}
+
+// handleLockRenewal logs lease renewal reminders and notifies the local
+// handler so the holder can renew in time.
+func (a *App) handleLockRenewal(notice *lock.RenewalNotice) {
+	a.logger.Component("lock-handler").Info("lock lease is about to expire",
+		"lock_id", notice.LockID,
+		"target", notice.Target,
+		"remaining", notice.Remaining)
+
+	a.mu.RLock()
+	handler := a.onLockRenewal
+	a.mu.RUnlock()
+	if handler != nil {
+		handler(notice)
+	}
+}
+
+// SetLockRenewalHandler sets the callback invoked when a lock held by this
+// node is due for renewal.
+func (a *App) SetLockRenewalHandler(handler func(*lock.RenewalNotice)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onLockRenewal = handler
+}
+
 // ContextMessageBase is used to determine the message type.
 type ContextMessageBase struct {
 	Type string `json:"type"`
@@ -134,20 +508,34 @@ type ContextMessageBase struct {
 
 // processContextMessages processes incoming context sync messages from P2P network.
 func (a *App) processContextMessages(ctx context.Context) {
-	topicName := "/agent-collab/" + a.config.ProjectName + "/context"
+	a.processContextTopic(ctx, a.topics().ContextSync())
+}
+
+// processContextTopic processes context messages arriving on topicName,
+// the context sync topic or a context shard.
+func (a *App) processContextTopic(ctx context.Context, topicName string) {
+	log := a.logger.Component("context-processor")
 	processor := NewMessageProcessor(
 		a.node,
 		topicName,
-		a.handleSingleContextMessage,
-		a.logger.Component("context-processor"),
+		func(ctx context.Context, data []byte) {
+			if payload, signer, ok := a.openMessage(data, log); ok {
+				a.handleSingleContextMessage(ctx, payload, signer)
+			}
+		},
+		log,
 	)
 	processor.Run(ctx)
 }
 
-// handleSingleContextMessage processes a single context message
-func (a *App) handleSingleContextMessage(ctx context.Context, data []byte) {
+// handleSingleContextMessage processes a single context message. signer is
+// as for handleSingleLockMessage.
+func (a *App) handleSingleContextMessage(ctx context.Context, data []byte, signer string) {
 	log := a.logger.Component("context-handler")
 
+	start := time.Now()
+	defer a.procMetrics.ObserveSince(StageContextTotal, start)
+
 	var baseMsg ContextMessageBase
 	if UnmarshalMessage(data, &baseMsg, "context message type", log) != UnmarshalOK {
 		return
@@ -159,7 +547,42 @@ func (a *App) handleSingleContextMessage(ctx context.Context, data []byte) {
 		if UnmarshalMessage(data, &ctxMsg, "shared context", log) != UnmarshalOK {
 			return
 		}
-		a.handleSharedContext(ctx, &ctxMsg)
+		if !signedBy(signer, ctxMsg.SourceID, "shared context", log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageContextDecode, start)
+		a.receiveSharedContext(ctx, &ctxMsg)
+		a.procMetrics.ObserveSince(StageContextApply, applyStart)
+
+	case ctxsync.MsgDiffRequest:
+		var req ctxsync.DiffRequest
+		if UnmarshalMessage(data, &req, "diff request", log) != UnmarshalOK {
+			return
+		}
+		if !signedBy(signer, req.RequestorID, "diff request", log) {
+			return
+		}
+		if resp := a.syncManager.HandleDiffRequest(&req); resp != nil {
+			respData, err := json.Marshal(resp)
+			if err != nil {
+				return
+			}
+			if err := a.publishSigned(a.ctx, a.topics().ContextSync(), respData); err != nil {
+				log.Error("failed to send diff", "error", err, "diff_hash", req.DiffHash)
+			}
+		}
+
+	case ctxsync.MsgDiffResponse:
+		var resp ctxsync.DiffResponse
+		if UnmarshalMessage(data, &resp, "diff response", log) != UnmarshalOK {
+			return
+		}
+		if !signedBy(signer, resp.ResponderID, "diff response", log) {
+			return
+		}
+		if err := a.syncManager.HandleDiffResponse(&resp); err != nil {
+			log.Warn("rejected diff response", "error", err)
+		}
 
 	default:
 		// Assume it's a Delta message (for backward compatibility)
@@ -167,13 +590,24 @@ func (a *App) handleSingleContextMessage(ctx context.Context, data []byte) {
 		if UnmarshalMessage(data, &delta, "delta", log) != UnmarshalOK {
 			return
 		}
+		if !signedBy(signer, delta.SourceID, "delta", log) {
+			return
+		}
+		applyStart := a.procMetrics.ObserveSince(StageContextDecode, start)
 
 		if err := a.syncManager.ReceiveDelta(&delta); err != nil {
 			log.Error("failed to handle delta", "error", err)
 		}
 
 		// Also store in VectorDB if it's a file change with content
-		a.storeDeltaInVectorDB(ctx, &delta)
+		if a.needsFullDiff(&delta) {
+			// The fetch is answered on this same topic, so it must not
+			// block the processor.
+			go a.fetchDiffAndStore(ctx, &delta)
+		} else {
+			a.storeDeltaInVectorDB(ctx, &delta)
+		}
+		a.procMetrics.ObserveSince(StageContextApply, applyStart)
 	}
 }
 
@@ -189,7 +623,9 @@ func (a *App) handleSharedContext(ctx context.Context, msg *ContextMessage) {
 	embedding := msg.Embedding
 	if len(embedding) == 0 && a.embedService != nil && msg.Content != "" {
 		var err error
-		embedding, err = a.embedService.Embed(ctx, msg.Content)
+		embedStart := time.Now()
+		embedding, err = a.embedContent(ctx, "", msg.Content)
+		a.procMetrics.ObserveSince(StageContextEmbed, embedStart)
 		if err != nil {
 			log.Error("failed to generate embedding for shared context", "error", err)
 			return
@@ -198,10 +634,11 @@ func (a *App) handleSharedContext(ctx context.Context, msg *ContextMessage) {
 
 	// Create and store document
 	doc := &vector.Document{
-		Content:   msg.Content,
-		Embedding: embedding,
-		FilePath:  msg.FilePath,
-		Metadata:  msg.Metadata,
+		Content:    msg.Content,
+		Embedding:  embedding,
+		FilePath:   msg.FilePath,
+		Metadata:   msg.Metadata,
+		Provenance: sharedProvenance(msg, time.Now()),
 	}
 	if doc.Metadata == nil {
 		doc.Metadata = make(map[string]any)
@@ -224,6 +661,38 @@ func (a *App) handleSharedContext(ctx context.Context, msg *ContextMessage) {
 	log.Info("received shared context", "source_id", msg.SourceID, "file_path", msg.FilePath)
 }
 
+// diffFetchTimeout bounds how long a peer waits for a summarized diff.
+const diffFetchTimeout = 10 * time.Second
+
+// needsFullDiff reports whether a summarized delta must be expanded before
+// it can be embedded, which is only the case for symbol granularity.
+func (a *App) needsFullDiff(delta *ctxsync.Delta) bool {
+	if delta.Payload == nil || delta.Payload.DiffSummary == nil || a.vectorStore == nil {
+		return false
+	}
+	granularity, err := ParseContextGranularity(a.config.ContextGranularity)
+	return err == nil && granularity.includesSymbols()
+}
+
+// fetchDiffAndStore fetches the full diff of a summarized delta from peers
+// and stores it in VectorDB. It falls back to the summary on failure.
+func (a *App) fetchDiffAndStore(ctx context.Context, delta *ctxsync.Delta) {
+	fetchCtx, cancel := context.WithTimeout(ctx, diffFetchTimeout)
+	defer cancel()
+
+	diff, err := a.syncManager.FetchDiff(fetchCtx, delta)
+	if err != nil {
+		a.logger.Component("context-handler").Warn("failed to fetch full diff", "error", err, "file_path", delta.Payload.FilePath)
+	} else {
+		expanded := *delta
+		payload := *delta.Payload
+		payload.FileDiff = diff
+		expanded.Payload = &payload
+		delta = &expanded
+	}
+	a.storeDeltaInVectorDB(ctx, delta)
+}
+
 // storeDeltaInVectorDB stores delta content in VectorDB for search.
 func (a *App) storeDeltaInVectorDB(ctx context.Context, delta *ctxsync.Delta) {
 	log := a.logger.Component("vector-store")
@@ -237,43 +706,36 @@ func (a *App) storeDeltaInVectorDB(ctx context.Context, delta *ctxsync.Delta) {
 		return
 	}
 
-	// Build content description from delta info
-	content := fmt.Sprintf("File change: %s from %s",
-		delta.Payload.FilePath, delta.SourceName)
-
-	// Add symbol info if available
-	if delta.Payload.FileDiff != nil {
-		for _, d := range delta.Payload.FileDiff.Diffs {
-			if d.Symbol != nil {
-				content += fmt.Sprintf("\n%s %s: %s",
-					d.Type, d.Symbol.Type, d.Symbol.Name)
-			}
-		}
-	}
-
-	// Generate embedding
-	embedding, err := a.embedService.Embed(ctx, content)
+	granularity, err := ParseContextGranularity(a.config.ContextGranularity)
 	if err != nil {
-		log.Error("failed to generate embedding for delta", "error", err, "file_path", delta.Payload.FilePath)
-		return
+		granularity = ContextGranularityFile
 	}
 
-	// Create and store document
-	doc := &vector.Document{
-		Content:   content,
-		Embedding: embedding,
-		FilePath:  delta.Payload.FilePath,
-		Metadata: map[string]any{
-			"source_id":   delta.SourceID,
-			"source_name": delta.SourceName,
-			"delta_id":    delta.ID,
-			"timestamp":   delta.Timestamp,
-			"type":        "delta_sync",
-		},
-	}
+	stored := 0
+	for _, doc := range deltaDocuments(delta, granularity) {
+		doc.Collection = a.config.DeltaCollectionName()
+		// Generate embedding
+		embedStart := time.Now()
+		embedding, err := a.embedContent(ctx, doc.Collection, doc.Content)
+		a.procMetrics.ObserveSince(StageContextEmbed, embedStart)
+		if err != nil {
+			log.Error("failed to generate embedding for delta", "error", err, "file_path", delta.Payload.FilePath, "symbol", doc.SymbolName)
+			continue
+		}
+		doc.Embedding = embedding
+		doc.Provenance = &vector.Provenance{
+			SourceID:   delta.SourceID,
+			SourceName: delta.SourceName,
+			SharedAt:   delta.Timestamp,
+		}
 
-	if err := a.vectorStore.Insert(doc); err != nil {
-		log.Error("failed to store delta in VectorDB", "error", err, "file_path", delta.Payload.FilePath)
+		if err := a.vectorStore.Insert(doc); err != nil {
+			log.Error("failed to store delta in VectorDB", "error", err, "file_path", delta.Payload.FilePath, "symbol", doc.SymbolName)
+			continue
+		}
+		stored++
+	}
+	if stored == 0 {
 		return
 	}
 
@@ -293,21 +755,40 @@ type ContextMessage struct {
 	Embedding []float32      `json:"embedding,omitempty"`
 	Metadata  map[string]any `json:"metadata,omitempty"`
 	SourceID  string         `json:"source_id"`
+
+	// Clock orders shares causally: the sender's shares and the peer
+	// shares it had applied when sending this one.
+	Clock *ctxsync.VectorClock `json:"clock,omitempty"`
+
+	// Provenance carries the sender's name, share time and the documents
+	// the share was derived from.
+	Provenance *vector.Provenance `json:"provenance,omitempty"`
 }
 
 // BroadcastContext broadcasts shared context to all peers.
 func (a *App) BroadcastContext(filePath, content string, embedding []float32, metadata map[string]any) error {
+	return a.broadcastContext(filePath, content, embedding, metadata, nil)
+}
+
+func (a *App) broadcastContext(filePath, content string, embedding []float32, metadata map[string]any, provenance *vector.Provenance) error {
 	if a.node == nil {
 		return fmt.Errorf("node not initialized")
 	}
+	if a.IsObserver() {
+		return ErrObserverMode
+	}
 
 	msg := ContextMessage{
-		Type:      "shared_context",
-		FilePath:  filePath,
-		Content:   content,
-		Embedding: embedding,
-		Metadata:  metadata,
-		SourceID:  a.node.ID().String(),
+		Type:       "shared_context",
+		FilePath:   filePath,
+		Content:    content,
+		Embedding:  embedding,
+		Metadata:   metadata,
+		SourceID:   a.node.ID().String(),
+		Provenance: provenance,
+	}
+	if a.causal != nil {
+		msg.Clock = a.causal.stamp(msg.SourceID)
 	}
 
 	data, err := json.Marshal(msg)
@@ -315,6 +796,5 @@ func (a *App) BroadcastContext(filePath, content string, embedding []float32, me
 		return err
 	}
 
-	topicName := "/agent-collab/" + a.config.ProjectName + "/context"
-	return a.node.Publish(a.ctx, topicName, data)
+	return a.publishContext(filePath, data)
 }
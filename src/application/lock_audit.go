@@ -0,0 +1,65 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/storage/metrics"
+)
+
+// lockAuditBacklog bounds the lock history entries waiting to be written
+// to the audit log.
+const lockAuditBacklog = 1024
+
+// startLockAudit persists every lock history entry (acquires, releases,
+// conflicts, negotiation outcomes) to the metrics store until ctx is done.
+// Entries are recorded with the lock store locked, so they are written in
+// the background, in order.
+func (a *App) startLockAudit(ctx context.Context) {
+	if a.lockService == nil || a.metricsStore == nil {
+		return
+	}
+
+	entries := make(chan *lock.HistoryEntry, lockAuditBacklog)
+	a.lockService.SetHistoryHandler(func(entry *lock.HistoryEntry) {
+		select {
+		case entries <- entry:
+		default:
+			a.logger.Warn("lock audit backlog full, dropping entry",
+				"action", entry.Action, "lock_id", entry.LockID)
+		}
+	})
+
+	save := func(entry *lock.HistoryEntry) {
+		if err := a.metricsStore.SaveLockEntry(entry); err != nil {
+			a.logger.Warn("failed to write lock audit entry", "error", err)
+		}
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				// Write what is already queued
+				for {
+					select {
+					case entry := <-entries:
+						save(entry)
+					default:
+						return
+					}
+				}
+			case entry := <-entries:
+				save(entry)
+			}
+		}
+	}()
+}
+
+// LockHistory queries the lock audit log, newest first.
+func (a *App) LockHistory(q metrics.LockHistoryQuery) ([]*lock.HistoryEntry, error) {
+	if a.metricsStore == nil {
+		return nil, fmt.Errorf("metrics store not initialized")
+	}
+	return a.metricsStore.LockHistory(q)
+}
@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// lockEmbedTimeout bounds how long embedding a locked region may delay the
+// acquire that triggered it.
+const lockEmbedTimeout = 10 * time.Second
+
+// inProgressDocType tags documents describing work under an active lock.
+const inProgressDocType = "in_progress"
+
+// inProgressDocID is the document a lock's region is embedded as.
+func inProgressDocID(lockID string) string {
+	return "in-progress-" + lockID
+}
+
+// inProgressDocument builds the document (without embedding) describing the
+// region a lock covers, so searches surface work other agents have under way.
+func inProgressDocument(l *lock.SemanticLock) *vector.Document {
+	target := l.Target
+	content := fmt.Sprintf("In progress: %s by %s in %s", l.Intention, l.HolderName, target.String())
+	if snippet := readSnippet(target.FilePath, target.StartLine, target.EndLine); snippet != "" {
+		content += "\n" + snippet
+	}
+
+	return &vector.Document{
+		ID:         inProgressDocID(l.ID),
+		Content:    content,
+		FilePath:   target.FilePath,
+		StartLine:  target.StartLine,
+		EndLine:    target.EndLine,
+		SymbolName: target.Name,
+		Metadata: map[string]any{
+			"type":        inProgressDocType,
+			"lock_id":     l.ID,
+			"holder_id":   l.HolderID,
+			"holder_name": l.HolderName,
+			"intention":   l.Intention,
+			"timestamp":   l.AcquiredAt,
+		},
+		Provenance: &vector.Provenance{
+			SourceID:   l.HolderID,
+			SourceName: l.HolderName,
+			SharedAt:   l.AcquiredAt,
+		},
+	}
+}
+
+// embedLockedRegion stores the current content of a newly acquired lock's
+// region in the vector store.
+func (a *App) embedLockedRegion(l *lock.SemanticLock) {
+	if a.vectorStore == nil || a.embedService == nil || l.Target == nil {
+		return
+	}
+	log := a.logger.Component("vector-store")
+
+	ctx, cancel := context.WithTimeout(a.ctx, lockEmbedTimeout)
+	defer cancel()
+
+	doc := inProgressDocument(l)
+	embedding, err := a.embedContent(ctx, doc.Collection, doc.Content)
+	if err != nil {
+		log.Warn("failed to embed locked region", "error", err, "lock_id", l.ID)
+		return
+	}
+	doc.Embedding = embedding
+
+	if err := a.vectorStore.Insert(doc); err != nil {
+		log.Warn("failed to store locked region", "error", err, "lock_id", l.ID)
+		return
+	}
+	if err := a.vectorStore.Flush(); err != nil {
+		log.Warn("failed to flush VectorDB", "error", err)
+	}
+}
+
+// dropLockedRegion removes the in-progress document of a lock that left the
+// store. Locks that were never embedded are skipped.
+func (a *App) dropLockedRegion(l *lock.SemanticLock) {
+	if a.vectorStore == nil {
+		return
+	}
+	id := inProgressDocID(l.ID)
+	if _, err := a.vectorStore.Get("default", id); err != nil {
+		return
+	}
+	if err := a.vectorStore.Delete("default", id); err != nil {
+		a.logger.Component("vector-store").Warn("failed to remove in-progress document", "error", err, "lock_id", l.ID)
+	}
+}
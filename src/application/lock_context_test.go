@@ -0,0 +1,85 @@
+package application_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"agent-collab/src/application"
+	"agent-collab/src/domain/lock"
+)
+
+func TestApp_EmbedLockedRegions(t *testing.T) {
+	ctx := context.Background()
+	app, err := application.New(&application.Config{DataDir: t.TempDir(), EmbedLockedRegions: true})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(ctx, "inprogress-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := app.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	t.Cleanup(func() { app.Stop() })
+
+	path := filepath.Join(t.TempDir(), "auth.go")
+	source := "package auth\n\nfunc Login(user string) error {\n\treturn nil\n}\n"
+	if err := os.WriteFile(path, []byte(source), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := app.LockService().AcquireLock(ctx, &lock.AcquireLockRequest{
+		TargetType: lock.TargetFile,
+		FilePath:   path,
+		StartLine:  3,
+		EndLine:    5,
+		Intention:  "add rate limiting",
+	})
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+
+	docID := "in-progress-" + result.Lock.ID
+	doc, err := app.VectorStore().Get("default", docID)
+	if err != nil {
+		t.Fatalf("expected in-progress document after acquire: %v", err)
+	}
+	if doc.Metadata["type"] != "in_progress" || doc.Metadata["lock_id"] != result.Lock.ID {
+		t.Errorf("unexpected metadata: %+v", doc.Metadata)
+	}
+	if !strings.Contains(doc.Content, "func Login") || !strings.Contains(doc.Content, "add rate limiting") {
+		t.Errorf("expected locked region and intention in content, got %q", doc.Content)
+	}
+	if len(doc.Embedding) == 0 {
+		t.Error("in-progress document should be embedded")
+	}
+
+	if err := app.LockService().ReleaseLock(ctx, result.Lock.ID); err != nil {
+		t.Fatalf("ReleaseLock: %v", err)
+	}
+	if _, err := app.VectorStore().Get("default", docID); err == nil {
+		t.Error("in-progress document should be removed on release")
+	}
+}
+
+func TestApp_EmbedLockedRegions_DisabledByDefault(t *testing.T) {
+	ctx := context.Background()
+	app := startSharingApp(t, "")
+
+	result, err := app.LockService().AcquireLock(ctx, &lock.AcquireLockRequest{
+		TargetType: lock.TargetFile,
+		FilePath:   "main.go",
+		StartLine:  1,
+		EndLine:    10,
+		Intention:  "refactor",
+	})
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+	if _, err := app.VectorStore().Get("default", "in-progress-"+result.Lock.ID); err == nil {
+		t.Error("locked regions should not be embedded unless enabled")
+	}
+}
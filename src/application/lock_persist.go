@@ -0,0 +1,116 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"agent-collab/src/domain/lock"
+)
+
+// locksFile holds the locks this node held when it last saved them, so a
+// restarted daemon keeps them instead of silently releasing them while
+// peers still honour them.
+const locksFile = "locks.json"
+
+// fileLockPersister persists locks as a JSON file. The file is replaced
+// atomically so a crash mid-write keeps the last one.
+type fileLockPersister struct {
+	path string
+}
+
+// newFileLockPersister returns a persister writing to the locks file in dir.
+func newFileLockPersister(dir string) *fileLockPersister {
+	return &fileLockPersister{path: filepath.Join(dir, locksFile)}
+}
+
+// Save implements lock.LockPersister.
+func (p *fileLockPersister) Save(locks []*lock.SemanticLock) error {
+	if len(locks) == 0 {
+		if err := os.Remove(p.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(locks)
+	if err != nil {
+		return err
+	}
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
+
+// Load implements lock.LockPersister.
+func (p *fileLockPersister) Load() ([]*lock.SemanticLock, error) {
+	// #nosec G304 - path is the fixed locks file in the data directory
+	data, err := os.ReadFile(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var locks []*lock.SemanticLock
+	if err := json.Unmarshal(data, &locks); err != nil {
+		return nil, err
+	}
+	return locks, nil
+}
+
+// lockPersister returns the persister for this node's locks, or nil when
+// there is no data directory or the node never holds locks.
+func (a *App) lockPersister() lock.LockPersister {
+	if a.lockService == nil || a.config.DataDir == "" || a.IsObserver() {
+		return nil
+	}
+	return newFileLockPersister(a.config.DataDir)
+}
+
+// rehydrateLocks restores the locks this node held before a restart. They
+// are announced to peers once Start sets up broadcasting.
+func (a *App) rehydrateLocks() {
+	p := a.lockPersister()
+	if p == nil {
+		return
+	}
+	restored, err := a.lockService.RehydrateLocks(p)
+	if err != nil {
+		a.logger.Warn("failed to restore locks", "error", err)
+		return
+	}
+	if len(restored) > 0 {
+		a.logger.Info("restored locks", "count", len(restored))
+	}
+	a.restoredLocks = restored
+}
+
+// persistLocks keeps the locks file in step with the lock table and
+// re-broadcasts locks restored by rehydrateLocks with their original
+// fencing tokens (must be called with a.mu held, after the message
+// handlers are set up).
+func (a *App) persistLocks() {
+	p := a.lockPersister()
+	if p == nil {
+		return
+	}
+	a.lockService.SetPersister(a.ctx, p, func(err error) {
+		a.logger.Warn("failed to persist locks", "error", err)
+	})
+
+	restored := a.restoredLocks
+	a.restoredLocks = nil
+	if len(restored) == 0 {
+		return
+	}
+	if n, err := a.lockService.AnnounceLocks(restored); err != nil {
+		a.logger.Warn("failed to announce restored locks", "error", err)
+	} else if n > 0 {
+		a.logger.Info("announced restored locks", "count", n)
+	}
+}
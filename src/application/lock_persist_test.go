@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/pkg/logging"
+)
+
+func TestApp_LocksSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	svc := lock.NewLockService(ctx, "node-a", "A")
+	a := &App{config: &Config{DataDir: dir}, lockService: svc, logger: logging.New(io.Discard, "error"), ctx: ctx}
+	a.persistLocks()
+
+	result, err := svc.AcquireLock(ctx, &lock.AcquireLockRequest{
+		TargetType: lock.TargetFile,
+		FilePath:   "main.go",
+		StartLine:  1,
+		EndLine:    20,
+		Intention:  "refactor",
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	svc.Close()
+
+	restarted := &App{
+		config:      &Config{DataDir: dir},
+		lockService: lock.NewLockService(ctx, "node-a", "A"),
+		logger:      logging.New(io.Discard, "error"),
+		ctx:         ctx,
+	}
+	defer restarted.lockService.Close()
+	restarted.rehydrateLocks()
+
+	got, err := restarted.lockService.GetLock(result.Lock.ID)
+	if err != nil {
+		t.Fatalf("expected the lock to be restored: %v", err)
+	}
+	if got.FencingToken != result.Lock.FencingToken {
+		t.Errorf("FencingToken = %d, want %d", got.FencingToken, result.Lock.FencingToken)
+	}
+	if len(restarted.restoredLocks) != 1 {
+		t.Errorf("expected the restored lock to be queued for announcement, got %d", len(restarted.restoredLocks))
+	}
+
+	// Releasing the last lock removes the file
+	restarted.persistLocks()
+	if err := restarted.lockService.ReleaseLock(ctx, got.ID); err != nil {
+		t.Fatal(err)
+	}
+	restarted.lockService.Close()
+	if _, err := os.Stat(filepath.Join(dir, locksFile)); !os.IsNotExist(err) {
+		t.Errorf("expected the locks file to be removed, got %v", err)
+	}
+}
@@ -72,6 +72,9 @@ func NewMessageProcessor(node *libp2p.Node, topicName string, handler MessageHan
 }
 
 // Run starts the message processing loop. Blocks until context is cancelled.
+// Messages a gateway relayed from a far region, and lock messages pushed
+// directly by the publisher, arrive outside the subscription and are
+// processed alongside it.
 func (p *MessageProcessor) Run(ctx context.Context) {
 	sub := p.node.GetSubscription(p.topicName)
 	if sub == nil {
@@ -79,6 +82,13 @@ func (p *MessageProcessor) Run(ctx context.Context) {
 		return
 	}
 
+	if relayed := p.node.RelayedMessages(p.topicName); relayed != nil {
+		go p.runRelayed(ctx, relayed)
+	}
+	if pushed := p.node.PushedMessages(p.topicName); pushed != nil {
+		go p.runRelayed(ctx, pushed)
+	}
+
 	for {
 		msg, err := sub.Next(ctx)
 		if err != nil {
@@ -93,23 +103,46 @@ func (p *MessageProcessor) Run(ctx context.Context) {
 		if msg.ReceivedFrom == p.node.ID() {
 			continue
 		}
-
-		// Decompress message if needed
-		data, err := libp2p.DecompressMessage(msg.Data)
-		if err != nil {
-			// Try raw data for backward compatibility
-			data = msg.Data
-		}
-
-		// Handle batch or single message
-		messages, err := libp2p.UnbatchMessage(data)
-		if err != nil {
-			p.logger.Error("failed to unbatch message", "error", err, "topic", p.topicName)
+		// Skip messages already pushed to us directly
+		if !p.node.FirstDelivery(p.topicName, msg.Data) {
 			continue
 		}
 
-		for _, msgData := range messages {
-			p.handler(ctx, msgData)
+		p.process(ctx, msg.Data)
+	}
+}
+
+// runRelayed processes relayed or pushed messages until context is
+// cancelled.
+func (p *MessageProcessor) runRelayed(ctx context.Context, relayed <-chan []byte) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case raw := <-relayed:
+			p.process(ctx, raw)
 		}
 	}
 }
+
+// process decompresses and unbatches a message as published and hands
+// each payload to the handler.
+func (p *MessageProcessor) process(ctx context.Context, raw []byte) {
+	// Decompress message if needed
+	data, err := libp2p.DecompressMessage(raw)
+	if err != nil {
+		// Try raw data for backward compatibility
+		data = raw
+	}
+
+	// Handle batch or single message
+	messages, err := libp2p.UnbatchMessage(data)
+	if err != nil {
+		p.logger.Error("failed to unbatch message", "error", err, "topic", p.topicName)
+		return
+	}
+
+	for _, msgData := range messages {
+		p.handler(ctx, msgData)
+	}
+}
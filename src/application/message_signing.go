@@ -0,0 +1,50 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"agent-collab/src/infrastructure/network/libp2p"
+)
+
+// publishSigned signs a lock or context message with the node's key and
+// publishes it, so peers can check that it speaks for this node.
+func (a *App) publishSigned(ctx context.Context, topic string, data []byte) error {
+	signed, err := a.node.SignMessage(data)
+	if err != nil {
+		return err
+	}
+	return a.node.Publish(ctx, topic, signed)
+}
+
+// openMessage verifies the signature of a received lock or context message
+// and returns the message and the node that signed it. Unsigned messages
+// from nodes that predate signing are accepted with an empty signer only
+// when allow_unsigned_messages is set.
+func (a *App) openMessage(data []byte, log Logger) ([]byte, string, bool) {
+	payload, signer, err := libp2p.OpenSignedMessage(data)
+	switch {
+	case err == nil:
+		return payload, signer.String(), true
+	case errors.Is(err, libp2p.ErrUnsignedMessage):
+		if a.config != nil && a.config.AllowUnsignedMessages {
+			return payload, "", true
+		}
+		log.Warn("rejected unsigned message")
+		return nil, "", false
+	default:
+		log.Warn("rejected message with invalid signature", "error", err)
+		return nil, "", false
+	}
+}
+
+// signedBy reports whether a message claiming to come from claimed was
+// signed by that node. An empty signer is an unsigned message that
+// openMessage already allowed.
+func signedBy(signer, claimed, msgType string, log Logger) bool {
+	if signer == "" || signer == claimed {
+		return true
+	}
+	log.Warn("rejected "+msgType+" signed by another node", "signer", signer, "claimed", claimed)
+	return false
+}
@@ -0,0 +1,250 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/crypto"
+	"agent-collab/src/infrastructure/network/libp2p"
+	"agent-collab/src/pkg/logging"
+
+	libp2pcrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+func TestApp_RejectsReleaseForgedByAnotherNode(t *testing.T) {
+	ctx := context.Background()
+	lockService := lock.NewLockService(ctx, "node-a", "A")
+	defer lockService.Close()
+
+	a := &App{
+		logger:      logging.New(io.Discard, "error"),
+		lockService: lockService,
+		procMetrics: NewProcessingMetrics(),
+	}
+
+	target := &lock.SemanticTarget{Type: lock.TargetFile, FilePath: "/test/forged.go", StartLine: 1, EndLine: 5}
+	held, err := lock.NewSemanticLockSafe(target, "node-b", "bob", "editing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lockService.HandleRemoteLockAcquired(held); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(lock.ReleaseMessage{Type: "lock_released", LockID: held.ID})
+	a.handleSingleLockMessage(data, "node-c")
+	if _, err := lockService.GetLock(held.ID); err != nil {
+		t.Fatalf("a release signed by another node should be ignored, got %v", err)
+	}
+
+	a.handleSingleLockMessage(data, "node-b")
+	if _, err := lockService.GetLock(held.ID); err == nil {
+		t.Error("the holder's release should drop the lock")
+	}
+}
+
+func TestApp_RejectsAcquireOfAnotherNodesLockID(t *testing.T) {
+	ctx := context.Background()
+	lockService := lock.NewLockService(ctx, "node-a", "A")
+	defer lockService.Close()
+
+	a := &App{
+		logger:      logging.New(io.Discard, "error"),
+		lockService: lockService,
+		procMetrics: NewProcessingMetrics(),
+	}
+
+	result, err := lockService.AcquireLock(ctx, &lock.AcquireLockRequest{
+		TargetType: lock.TargetFile,
+		FilePath:   "/test/owned.go",
+		StartLine:  1,
+		EndLine:    20,
+		Intention:  "editing",
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("acquire failed: %v %+v", err, result)
+	}
+	held := result.Lock
+
+	// node-c signs its message, and claims the lock ID as its own
+	forged := *held
+	forged.HolderID, forged.HolderName = "node-c", "carol"
+	forged.Target = &lock.SemanticTarget{Type: lock.TargetFile, FilePath: "/test/owned.go", StartLine: 100, EndLine: 120}
+	data, _ := json.Marshal(lock.AcquireMessage{Type: "lock_acquired", Lock: &forged})
+	a.handleSingleLockMessage(data, "node-c")
+
+	current, err := lockService.GetLock(held.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.HolderID != "node-a" || current.Target.StartLine != 1 || current.Target.EndLine != 20 {
+		t.Errorf("expected node-a to keep its lock on lines 1-20, got %s at %d-%d",
+			current.HolderID, current.Target.StartLine, current.Target.EndLine)
+	}
+}
+
+func TestApp_ForceReleaseNeedsAdminProof(t *testing.T) {
+	ctx := context.Background()
+	lockService := lock.NewLockService(ctx, "node-a", "A")
+	defer lockService.Close()
+
+	root, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	grant, err := crypto.IssueRoleGrant(root, "proj", string(RoleAdmin))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &App{
+		config:      &Config{ProjectName: "proj", ClusterRoot: root.PeerID.String()},
+		logger:      logging.New(io.Discard, "error"),
+		lockService: lockService,
+		procMetrics: NewProcessingMetrics(),
+	}
+
+	target := &lock.SemanticTarget{Type: lock.TargetFile, FilePath: "/test/force.go", StartLine: 1, EndLine: 5}
+	held, err := lock.NewSemanticLockSafe(target, "node-b", "bob", "editing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lockService.HandleRemoteLockAcquired(held); err != nil {
+		t.Fatal(err)
+	}
+
+	release := func(op *OperatorProof) []byte {
+		data, _ := json.Marshal(ReleaseMessageWrapper{
+			Type:   "lock_released",
+			LockID: held.ID,
+			Force: &lock.ForceReleaseNotice{
+				LockID:   held.ID,
+				HolderID: held.HolderID,
+				Reason:   "stuck",
+				Operator: "mallory",
+			},
+			Operator: op,
+		})
+		return data
+	}
+	stillHeld := func(msg string) {
+		t.Helper()
+		if _, err := lockService.GetLock(held.ID); err != nil {
+			t.Fatalf("%s should be dropped, got %v", msg, err)
+		}
+	}
+
+	a.handleSingleLockMessage(release(nil), "")
+	stillHeld("an unsigned force release")
+
+	a.handleSingleLockMessage(release(nil), "node-c")
+	stillHeld("a force release without an admin proof")
+
+	proof, err := grant.Prove("node-d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.handleSingleLockMessage(release(&OperatorProof{Grant: grant.Grant, Proof: proof}), "node-c")
+	stillHeld("a force release replaying another admin's proof")
+
+	other, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	selfGranted, err := crypto.IssueRoleGrant(other, "proj", string(RoleAdmin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, _ = selfGranted.Prove("node-c")
+	a.handleSingleLockMessage(release(&OperatorProof{Grant: selfGranted.Grant, Proof: proof}), "node-c")
+	stillHeld("a force release with a self-issued grant")
+
+	proof, _ = grant.Prove("node-d")
+	a.handleSingleLockMessage(release(&OperatorProof{Grant: grant.Grant, Proof: proof}), "node-d")
+	if _, err := lockService.GetLock(held.ID); err == nil {
+		t.Error("an admin's force release should drop the lock")
+	}
+}
+
+func TestApp_PolicyReleaseNeedsAgreeingPolicy(t *testing.T) {
+	ctx := context.Background()
+	lockService := lock.NewLockService(ctx, "node-a", "A")
+	defer lockService.Close()
+
+	a := &App{
+		config:      &Config{},
+		logger:      logging.New(io.Discard, "error"),
+		lockService: lockService,
+		procMetrics: NewProcessingMetrics(),
+	}
+
+	target := &lock.SemanticTarget{Type: lock.TargetFile, FilePath: "/test/policy.go", StartLine: 1, EndLine: 5}
+	held, err := lock.NewSemanticLockSafe(target, "node-b", "bob", "editing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lockService.HandleRemoteLockAcquired(held); err != nil {
+		t.Fatal(err)
+	}
+	requested, err := lock.NewSemanticLockSafe(target, "node-c", "carol", "editing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(ReleaseMessageWrapper{
+		Type:   "lock_released",
+		LockID: held.ID,
+		Force:  &lock.ForceReleaseNotice{LockID: held.ID, HolderID: held.HolderID, Operator: "policy:priority_wins", PreemptedBy: requested},
+	})
+
+	// Without a policy here, no preemption is legitimate
+	a.handleSingleLockMessage(data, "node-c")
+	if _, err := lockService.GetLock(held.ID); err != nil {
+		t.Fatalf("a policy release this node's policy disagrees with should be dropped, got %v", err)
+	}
+
+	lockService.SetConflictPolicy(lock.PriorityPolicy{Priorities: map[string]int{"node-c": 2}})
+	a.handleSingleLockMessage(data, "node-b")
+	if _, err := lockService.GetLock(held.ID); err != nil {
+		t.Fatalf("a policy release not signed by the preempting holder should be dropped, got %v", err)
+	}
+
+	a.handleSingleLockMessage(data, "node-c")
+	if _, err := lockService.GetLock(held.ID); err == nil {
+		t.Error("a policy release the local policy agrees with should drop the lock")
+	}
+}
+
+func TestApp_OpenMessage(t *testing.T) {
+	key, _, err := libp2pcrypto.GenerateKeyPair(libp2pcrypto.Ed25519, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := peer.IDFromPrivateKey(key)
+	msg := []byte(`{"type":"node_shutting_down","node_id":"` + id.String() + `"}`)
+
+	a := &App{config: &Config{}, logger: logging.New(io.Discard, "error")}
+	log := a.logger.Component("test")
+
+	signed, err := libp2p.SignMessage(key, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, signer, ok := a.openMessage(signed, log)
+	if !ok || signer != id.String() || string(payload) != string(msg) {
+		t.Errorf("expected the signed message from %s, got %q from %q (ok=%v)", id, payload, signer, ok)
+	}
+
+	if _, _, ok := a.openMessage(msg, log); ok {
+		t.Error("unsigned messages should be rejected by default")
+	}
+
+	a.config.AllowUnsignedMessages = true
+	if payload, signer, ok := a.openMessage(msg, log); !ok || signer != "" || string(payload) != string(msg) {
+		t.Errorf("unsigned messages should pass without a signer when allowed, got %q from %q", payload, signer)
+	}
+}
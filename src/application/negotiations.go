@@ -0,0 +1,61 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"agent-collab/src/domain/lock"
+)
+
+// negotiationsFile holds lock negotiations still pending at shutdown.
+const negotiationsFile = "negotiations.json"
+
+// persistNegotiations writes pending lock negotiations to the data
+// directory so the node can resume them after a restart.
+func (a *App) persistNegotiations() error {
+	if a.lockService == nil || a.config.DataDir == "" {
+		return nil
+	}
+
+	path := filepath.Join(a.config.DataDir, negotiationsFile)
+	pending := a.lockService.ListActiveNegotiations()
+	if len(pending) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(pending, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// restoreNegotiations resumes negotiations persisted at the last shutdown.
+// The file is removed once read so sessions are restored only once.
+func (a *App) restoreNegotiations() (int, error) {
+	if a.lockService == nil || a.config.DataDir == "" {
+		return 0, nil
+	}
+
+	path := filepath.Join(a.config.DataDir, negotiationsFile)
+	// #nosec G304 - path is the fixed negotiations file in the data directory
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(path)
+
+	var sessions []*lock.NegotiationSession
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return 0, err
+	}
+	return a.lockService.RestoreNegotiations(sessions), nil
+}
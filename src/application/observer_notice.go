@@ -0,0 +1,56 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// ObserverAnnounceInterval is how often an observer node repeats its
+// announcement, so peers that joined since learn about it.
+const ObserverAnnounceInterval = time.Minute
+
+// NodeObserverMessage tells peers a node runs in observer mode. They stop
+// counting its votes and leave it out of partition quorum and lock pushes.
+type NodeObserverMessage struct {
+	Type   string `json:"type"` // "node_observer"
+	NodeID string `json:"node_id"`
+}
+
+// announceObserver tells peers this node is an observer, now and every
+// ObserverAnnounceInterval until ctx is done.
+func (a *App) announceObserver(ctx context.Context) {
+	data, err := json.Marshal(NodeObserverMessage{Type: "node_observer", NodeID: a.node.ID().String()})
+	if err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(ObserverAnnounceInterval)
+	defer ticker.Stop()
+	for {
+		if err := a.publishSigned(ctx, lockTopicFor(a.topics(), data), data); err != nil && ctx.Err() == nil {
+			a.logger.Warn("failed to announce observer mode", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleNodeObserver records a peer that announced observer mode.
+func (a *App) handleNodeObserver(msg *NodeObserverMessage) {
+	if a.lockService.IsRegisteredObserver(msg.NodeID) {
+		return
+	}
+	a.lockService.RegisterObserver(msg.NodeID)
+	a.logger.Component("lock-handler").Info("peer is an observer", "node_id", msg.NodeID)
+}
+
+// isObserverPeer reports whether a peer announced observer mode.
+func (a *App) isObserverPeer(id peer.ID) bool {
+	return a.lockService != nil && a.lockService.IsRegisteredObserver(id.String())
+}
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"testing"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/pkg/logging"
+
+	libp2pcrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+func TestApp_ObserverAnnouncementExcludesPeer(t *testing.T) {
+	ctx := context.Background()
+	locks := lock.NewLockService(ctx, "node-a", "A")
+	defer locks.Close()
+
+	a := &App{
+		logger:      logging.New(io.Discard, "error"),
+		lockService: locks,
+		procMetrics: NewProcessingMetrics(),
+	}
+
+	var ids []peer.ID
+	for range 2 {
+		key, _, err := libp2pcrypto.GenerateKeyPair(libp2pcrypto.Ed25519, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, _ := peer.IDFromPrivateKey(key)
+		ids = append(ids, id)
+	}
+	observer, member := ids[0], ids[1]
+
+	data, _ := json.Marshal(NodeObserverMessage{Type: "node_observer", NodeID: observer.String()})
+
+	// Another node cannot mark a peer as an observer
+	a.handleSingleLockMessage(data, member.String())
+	if locks.IsRegisteredObserver(observer.String()) {
+		t.Fatal("an observer announcement signed by another node should be ignored")
+	}
+
+	a.handleSingleLockMessage(data, observer.String())
+	if !locks.IsRegisteredObserver(observer.String()) {
+		t.Fatal("expected the announcing node to be registered as an observer")
+	}
+	if peers := a.quorumPeers(ids); !slices.Equal(peers, []string{member.String()}) {
+		t.Errorf("expected only the participating peer to count for quorum, got %v", peers)
+	}
+
+	// A departing observer is forgotten
+	data, _ = json.Marshal(NodeShutdownMessage{Type: "node_shutting_down", NodeID: observer.String()})
+	a.handleSingleLockMessage(data, observer.String())
+	if locks.IsRegisteredObserver(observer.String()) {
+		t.Error("expected the departed observer to be forgotten")
+	}
+}
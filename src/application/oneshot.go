@@ -0,0 +1,36 @@
+package application
+
+import (
+	"context"
+	"fmt"
+)
+
+// RunOnce starts the app from the saved configuration, runs action and
+// stops the app before returning, whether or not the action succeeded.
+// It backs the CLI's one-shot commands, which must not leave a node running.
+func RunOnce(ctx context.Context, cfg *Config, action func(ctx context.Context, app *App) error) (err error) {
+	app, err := New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create app: %w", err)
+	}
+
+	// The node's pubsub and DHT workers live until this context ends
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// LoadFromConfig may leave a node behind on failure, so always close
+	defer func() {
+		if closeErr := app.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to stop app: %w", closeErr)
+		}
+	}()
+
+	if err := app.LoadFromConfig(ctx); err != nil {
+		return err
+	}
+	if err := app.Start(); err != nil {
+		return fmt.Errorf("failed to start app: %w", err)
+	}
+
+	return action(ctx, app)
+}
@@ -0,0 +1,148 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-collab/src/application"
+)
+
+// initCluster creates a cluster config in a temp dir and closes the app.
+func initCluster(t *testing.T, project string) *application.Config {
+	t.Helper()
+
+	config := &application.Config{DataDir: t.TempDir()}
+	app, err := application.New(config)
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(context.Background(), project); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	// Close, not Stop: the app was never started, so Stop leaves its node open
+	if err := app.Close(); err != nil {
+		t.Fatalf("Failed to close app: %v", err)
+	}
+	return &application.Config{DataDir: config.DataDir}
+}
+
+// goroutineStacks returns the stacks of all running goroutines keyed by
+// goroutine ID. IDs are never reused, so a goroutine missing from an
+// earlier snapshot was started after it.
+func goroutineStacks() map[string]string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := make(map[string]string)
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		// Each stack starts with "goroutine <id> [<state>]:"
+		header, _, _ := strings.Cut(stack, "\n")
+		if fields := strings.Fields(header); len(fields) >= 2 && fields[0] == "goroutine" {
+			stacks[fields[1]] = stack
+		}
+	}
+	return stacks
+}
+
+// ignoredLeaks are functions of goroutines that may outlive the app
+// without leaking: libp2p's UPnP discovery cannot be cancelled and ends on
+// its own SSDP search timeout.
+var ignoredLeaks = []string{
+	"github.com/koron/go-ssdp.Search(",
+}
+
+// verifyNoLeaks fails the test if goroutines started after the baseline
+// snapshot are still running once they had time to exit, like
+// goleak.VerifyNone with goleak.IgnoreCurrent and goleak.IgnoreAnyFunction.
+func verifyNoLeaks(t *testing.T, baseline map[string]string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var leaked []string
+		for id, stack := range goroutineStacks() {
+			if _, ok := baseline[id]; !ok && !slices.ContainsFunc(ignoredLeaks, func(fn string) bool {
+				return strings.Contains(stack, fn)
+			}) {
+				leaked = append(leaked, stack)
+			}
+		}
+		if len(leaked) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines leaked:\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestRunOnce_PerformsActionAndStops(t *testing.T) {
+	config := initCluster(t, "oneshot-cluster")
+	baseline := goroutineStacks()
+
+	var project string
+	var lockCount = -1
+	err := application.RunOnce(context.Background(), config, func(ctx context.Context, app *application.App) error {
+		status := app.GetStatus()
+		if !status.Running {
+			t.Error("app should be running during the action")
+		}
+		project = status.ProjectName
+		lockCount = status.LockCount
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+
+	if project != "oneshot-cluster" || lockCount != 0 {
+		t.Errorf("action saw project=%q locks=%d", project, lockCount)
+	}
+	verifyNoLeaks(t, baseline)
+}
+
+func TestRunOnce_StopsOnActionError(t *testing.T) {
+	config := initCluster(t, "oneshot-error")
+	baseline := goroutineStacks()
+
+	wantErr := errors.New("action failed")
+	var running *application.App
+	err := application.RunOnce(context.Background(), config, func(ctx context.Context, app *application.App) error {
+		running = app
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected action error, got %v", err)
+	}
+	if running.GetStatus().Running {
+		t.Error("app should be stopped after RunOnce returns")
+	}
+	verifyNoLeaks(t, baseline)
+}
+
+func TestRunOnce_NoConfig(t *testing.T) {
+	called := false
+	err := application.RunOnce(context.Background(), &application.Config{DataDir: t.TempDir()}, func(ctx context.Context, app *application.App) error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Error("expected error without a saved config")
+	}
+	if called {
+		t.Error("action should not run without a config")
+	}
+}
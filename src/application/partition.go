@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/network/libp2p"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// watchPartitions observes connectivity and, when the node heals from a
+// partition, starts lock reconciliation with the peers it lost.
+func (a *App) watchPartitions(ctx context.Context, detector *lock.PartitionDetector) {
+	ticker := time.NewTicker(detector.Config().CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if event := detector.Observe(a.connectivity(), now); event != nil {
+				a.handlePartitionEvent(event)
+			}
+		}
+	}
+}
+
+// connectivity snapshots the peers this node reaches.
+func (a *App) connectivity() lock.Connectivity {
+	c := lock.Connectivity{Peers: a.quorumPeers(a.node.ConnectedPeers())}
+	if tm := a.node.TopologyManager(); tm != nil && tm.GetRole() == libp2p.RoleLeaf {
+		c.Leaf = true
+		for _, p := range tm.GetMySuperPeers() {
+			c.SuperPeers = append(c.SuperPeers, p.String())
+		}
+	}
+	return c
+}
+
+// quorumPeers returns the peers that count as cluster members for quorum,
+// leaving out observers.
+func (a *App) quorumPeers(peers []peer.ID) []string {
+	var members []string
+	for _, p := range peers {
+		if !a.isObserverPeer(p) {
+			members = append(members, p.String())
+		}
+	}
+	return members
+}
+
+// handlePartitionEvent logs partition changes, starts reconciliation on heal
+// and notifies the local handler.
+func (a *App) handlePartitionEvent(event *lock.PartitionEvent) {
+	log := a.logger.Component("partition")
+
+	if event.State == lock.PartitionPartitioned {
+		log.Warn("network partition detected",
+			"reason", event.Reason,
+			"reachable", event.Reachable,
+			"members", event.Members)
+	} else {
+		log.Info("network partition healed, reconciling locks",
+			"partitioned_for", event.At.Sub(event.Since).Round(time.Second),
+			"reachable", event.Reachable,
+			"members", event.Members)
+		if a.lockService != nil {
+			if err := a.lockService.RequestLockState(event.Since); err != nil {
+				log.Error("failed to request lock state", "error", err)
+			}
+		}
+	}
+
+	a.mu.RLock()
+	handler := a.onPartition
+	a.mu.RUnlock()
+	if handler != nil {
+		handler(event)
+	}
+}
+
+// handleLockReconciled logs the outcome of a reconciliation pass and
+// notifies the local handler, which matters most when this node lost locks.
+func (a *App) handleLockReconciled(result *lock.ReconcileResult) {
+	if result == nil || !result.Changed() {
+		return
+	}
+
+	log := a.logger.Component("partition")
+	for _, session := range result.Sessions {
+		log.Info("partition conflict resolved",
+			"session_id", session.ID,
+			"winner", session.Resolution.WinnerLock.ID,
+			"loser", session.Resolution.LoserLock.ID,
+			"message", session.Resolution.Message)
+	}
+	for _, lost := range result.Lost {
+		log.Warn("lock lost in partition reconciliation",
+			"lock_id", lost.ID,
+			"target", lost.Target.String())
+	}
+
+	a.mu.RLock()
+	handler := a.onLockReconciled
+	a.mu.RUnlock()
+	if handler != nil {
+		handler(result)
+	}
+}
+
+// SetPartitionHandler sets the callback invoked when this node detects a
+// network partition or heals from one.
+func (a *App) SetPartitionHandler(handler func(*lock.PartitionEvent)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onPartition = handler
+}
+
+// SetLockReconciledHandler sets the callback invoked when reconciliation
+// with a peer changed local lock state.
+func (a *App) SetLockReconciledHandler(handler func(*lock.ReconcileResult)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onLockReconciled = handler
+}
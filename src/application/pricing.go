@@ -0,0 +1,140 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"agent-collab/src/domain/token"
+)
+
+// PricingRefreshInterval is how often token_pricing_url is re-fetched.
+const PricingRefreshInterval = 24 * time.Hour
+
+// maxPricingBytes caps the pricing table read from token_pricing_url.
+const maxPricingBytes = 1 << 20
+
+// tokenPricing holds the pricing sources merged into the token tracker's
+// prices: the built-in table, then the remote table, then config.json.
+type tokenPricing struct {
+	mu      sync.Mutex
+	url     string
+	remote  token.Pricing
+	local   token.Pricing
+	refresh chan struct{}
+}
+
+func newTokenPricing() *tokenPricing {
+	return &tokenPricing{refresh: make(chan struct{}, 1)}
+}
+
+// validatePricingConfig checks token_pricing and token_pricing_url.
+func validatePricingConfig(cfg *Config) error {
+	if err := cfg.TokenPricing.Validate(); err != nil {
+		return fmt.Errorf("invalid token_pricing: %w", err)
+	}
+	if cfg.TokenPricingURL != "" {
+		u, err := url.Parse(cfg.TokenPricingURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid token_pricing_url %q: must be an http(s) URL", cfg.TokenPricingURL)
+		}
+	}
+	return nil
+}
+
+// applyTokenPricing sets the configured prices on the token tracker and,
+// when token_pricing_url changed, drops the old remote prices and fetches
+// the new ones.
+func (a *App) applyTokenPricing(cfg *Config) {
+	p := a.pricing
+	p.mu.Lock()
+	p.local = cfg.TokenPricing
+	if p.url != cfg.TokenPricingURL {
+		p.url = cfg.TokenPricingURL
+		p.remote = nil
+		select {
+		case p.refresh <- struct{}{}:
+		default:
+		}
+	}
+	p.mu.Unlock()
+	a.updateTrackerPricing()
+}
+
+// updateTrackerPricing merges the pricing sources into the tracker.
+func (a *App) updateTrackerPricing() {
+	if a.tokenTracker == nil {
+		return
+	}
+	p := a.pricing
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	a.tokenTracker.SetPricing(token.DefaultPricing().Merge(p.remote).Merge(p.local))
+}
+
+// refreshTokenPricing fetches token_pricing_url at start, every
+// PricingRefreshInterval and whenever the URL changes. A failed fetch
+// keeps the prices in use.
+func (a *App) refreshTokenPricing(ctx context.Context, interval time.Duration) {
+	log := a.logger.Component("token-pricing")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		a.pricing.mu.Lock()
+		pricingURL := a.pricing.url
+		a.pricing.mu.Unlock()
+
+		if pricingURL != "" {
+			remote, err := fetchPricing(ctx, pricingURL)
+			if err != nil {
+				log.Warn("failed to fetch token pricing", "url", pricingURL, "error", err)
+			} else {
+				a.pricing.mu.Lock()
+				// The URL may have changed while fetching
+				if a.pricing.url == pricingURL {
+					a.pricing.remote = remote
+				}
+				a.pricing.mu.Unlock()
+				a.updateTrackerPricing()
+				log.Info("token pricing updated", "url", pricingURL, "models", len(remote))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-a.pricing.refresh:
+		}
+	}
+}
+
+// fetchPricing downloads a pricing table in the token_pricing format.
+func fetchPricing(ctx context.Context, pricingURL string) (token.Pricing, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pricingURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pricing URL returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPricingBytes))
+	if err != nil {
+		return nil, err
+	}
+	return token.ParsePricing(data)
+}
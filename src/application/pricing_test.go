@@ -0,0 +1,74 @@
+package application_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"agent-collab/src/application"
+	"agent-collab/src/domain/token"
+)
+
+func TestApp_TokenPricingMergesRemoteAndConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"gpt-4o": {"input": 2.5, "output": 10}, "embed": {"input": 0.5}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	app, err := application.New(&application.Config{
+		DataDir:         t.TempDir(),
+		TokenPricingURL: srv.URL,
+		TokenPricing:    token.Pricing{"embed": {Input: 0.1}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(context.Background(), "pricing-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := app.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	t.Cleanup(func() { app.Stop() })
+
+	tracker := app.TokenTracker()
+	deadline := time.Now().Add(5 * time.Second)
+	for tracker.Pricing().Lookup("", "gpt-4o").Output != 10 {
+		if time.Now().After(deadline) {
+			t.Fatal("remote pricing was not applied")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// config.json takes precedence over the remote table
+	if got := tracker.Pricing().Lookup("", "embed"); got.Input != 0.1 {
+		t.Errorf("expected the configured embed price, got %+v", got)
+	}
+	// Built-in prices remain for models neither lists
+	if got := tracker.Pricing().Lookup("", "text-embedding-3-small"); got.Input != 0.02 {
+		t.Errorf("expected the built-in price, got %+v", got)
+	}
+}
+
+func TestApp_StartRejectsInvalidTokenPricing(t *testing.T) {
+	for name, cfg := range map[string]*application.Config{
+		"negative price": {TokenPricing: token.Pricing{"gpt-4o": {Input: -1}}},
+		"bad url":        {TokenPricingURL: "ftp://prices"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg.DataDir = t.TempDir()
+			app, err := application.New(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create app: %v", err)
+			}
+			if _, err := app.Initialize(context.Background(), "pricing-test"); err != nil {
+				t.Fatalf("Failed to initialize: %v", err)
+			}
+			t.Cleanup(func() { app.Stop() })
+			if err := app.Start(); err == nil {
+				t.Error("expected Start to fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package application
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Message processing stages recorded by ProcessingMetrics.
+const (
+	StageLockDecode    = "lock.decode"
+	StageLockApply     = "lock.apply"
+	StageLockTotal     = "lock.total"
+	StageContextDecode = "context.decode"
+	StageContextApply  = "context.apply" // includes context.embed
+	StageContextEmbed  = "context.embed"
+	StageContextTotal  = "context.total"
+)
+
+// latencyWindow is the number of recent samples kept for percentiles.
+const latencyWindow = 1000
+
+// latencyBuckets are the upper bounds of the histogram buckets.
+var latencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// LatencyHistogram records latency samples in fixed buckets and keeps a
+// window of recent samples for percentiles.
+type LatencyHistogram struct {
+	mu      sync.Mutex
+	samples []time.Duration // ring buffer of recent samples
+	next    int
+	count   int64
+	sum     time.Duration
+	max     time.Duration
+	buckets []int64 // one per latencyBuckets entry plus +Inf
+}
+
+// NewLatencyHistogram creates an empty histogram.
+func NewLatencyHistogram() *LatencyHistogram {
+	return &LatencyHistogram{
+		samples: make([]time.Duration, 0, latencyWindow),
+		buckets: make([]int64, len(latencyBuckets)+1),
+	}
+}
+
+// Observe records a latency sample.
+func (h *LatencyHistogram) Observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) < latencyWindow {
+		h.samples = append(h.samples, d)
+	} else {
+		h.samples[h.next] = d
+		h.next = (h.next + 1) % latencyWindow
+	}
+
+	h.count++
+	h.sum += d
+	h.max = max(h.max, d)
+
+	i := sort.Search(len(latencyBuckets), func(i int) bool { return d <= latencyBuckets[i] })
+	h.buckets[i]++
+}
+
+// LatencySnapshot is a point-in-time view of a histogram.
+// Percentiles cover the most recent samples only.
+type LatencySnapshot struct {
+	Count   int64           `json:"count"`
+	Mean    time.Duration   `json:"mean"`
+	P50     time.Duration   `json:"p50"`
+	P95     time.Duration   `json:"p95"`
+	P99     time.Duration   `json:"p99"`
+	Max     time.Duration   `json:"max"`
+	Buckets []LatencyBucket `json:"buckets"`
+}
+
+// LatencyBucket is a cumulative histogram bucket.
+type LatencyBucket struct {
+	LE    string `json:"le"` // upper bound, "+Inf" for the last bucket
+	Count int64  `json:"count"`
+}
+
+// Snapshot returns the current histogram state.
+func (h *LatencyHistogram) Snapshot() LatencySnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	snap := LatencySnapshot{
+		Count:   h.count,
+		Max:     h.max,
+		Buckets: make([]LatencyBucket, len(h.buckets)),
+	}
+	if h.count > 0 {
+		snap.Mean = h.sum / time.Duration(h.count)
+	}
+
+	var cumulative int64
+	for i, n := range h.buckets {
+		cumulative += n
+		le := "+Inf"
+		if i < len(latencyBuckets) {
+			le = latencyBuckets[i].String()
+		}
+		snap.Buckets[i] = LatencyBucket{LE: le, Count: cumulative}
+	}
+
+	if len(h.samples) > 0 {
+		sorted := make([]time.Duration, len(h.samples))
+		copy(sorted, h.samples)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		snap.P50 = latencyPercentile(sorted, 50)
+		snap.P95 = latencyPercentile(sorted, 95)
+		snap.P99 = latencyPercentile(sorted, 99)
+	}
+
+	return snap
+}
+
+// latencyPercentile returns the nearest-rank percentile of sorted samples.
+func latencyPercentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (len(sorted)*p + 99) / 100
+	return sorted[max(rank-1, 0)]
+}
+
+// ProcessingMetrics tracks how long incoming P2P messages take to handle,
+// broken down by stage.
+type ProcessingMetrics struct {
+	mu     sync.RWMutex
+	stages map[string]*LatencyHistogram
+}
+
+// NewProcessingMetrics creates an empty metrics collector.
+func NewProcessingMetrics() *ProcessingMetrics {
+	return &ProcessingMetrics{stages: make(map[string]*LatencyHistogram)}
+}
+
+// Observe records a latency sample for stage.
+func (m *ProcessingMetrics) Observe(stage string, d time.Duration) {
+	m.mu.RLock()
+	h, ok := m.stages[stage]
+	m.mu.RUnlock()
+
+	if !ok {
+		m.mu.Lock()
+		if h, ok = m.stages[stage]; !ok {
+			h = NewLatencyHistogram()
+			m.stages[stage] = h
+		}
+		m.mu.Unlock()
+	}
+	h.Observe(d)
+}
+
+// ObserveSince records the time elapsed since start for stage and returns
+// the current time so consecutive stages can be chained.
+func (m *ProcessingMetrics) ObserveSince(stage string, start time.Time) time.Time {
+	now := time.Now()
+	m.Observe(stage, now.Sub(start))
+	return now
+}
+
+// Snapshot returns the latency snapshot of every recorded stage.
+func (m *ProcessingMetrics) Snapshot() map[string]LatencySnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snap := make(map[string]LatencySnapshot, len(m.stages))
+	for stage, h := range m.stages {
+		snap[stage] = h.Snapshot()
+	}
+	return snap
+}
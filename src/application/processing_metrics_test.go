@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"agent-collab/src/domain/ctxsync"
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/pkg/logging"
+)
+
+func TestLatencyHistogram_Percentiles(t *testing.T) {
+	h := NewLatencyHistogram()
+	// Insert out of order to make sure samples are sorted
+	for i := 100; i >= 1; i-- {
+		h.Observe(time.Duration(i) * time.Millisecond)
+	}
+
+	snap := h.Snapshot()
+	if snap.Count != 100 {
+		t.Fatalf("Count = %d, want 100", snap.Count)
+	}
+
+	checks := map[string][2]time.Duration{
+		"p50":  {snap.P50, 50 * time.Millisecond},
+		"p95":  {snap.P95, 95 * time.Millisecond},
+		"p99":  {snap.P99, 99 * time.Millisecond},
+		"max":  {snap.Max, 100 * time.Millisecond},
+		"mean": {snap.Mean, 50500 * time.Microsecond},
+	}
+	for name, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("%s = %v, want %v", name, c[0], c[1])
+		}
+	}
+}
+
+func TestLatencyHistogram_CumulativeBuckets(t *testing.T) {
+	h := NewLatencyHistogram()
+	h.Observe(500 * time.Microsecond)
+	h.Observe(3 * time.Millisecond)
+	h.Observe(10 * time.Second)
+
+	want := map[string]int64{"1ms": 1, "5ms": 2, "5s": 2, "+Inf": 3}
+	for _, b := range h.Snapshot().Buckets {
+		if n, ok := want[b.LE]; ok && b.Count != n {
+			t.Errorf("bucket le=%s count = %d, want %d", b.LE, b.Count, n)
+		}
+	}
+}
+
+func TestLatencyHistogram_WindowKeepsRecentSamples(t *testing.T) {
+	h := NewLatencyHistogram()
+	for i := 0; i < latencyWindow; i++ {
+		h.Observe(time.Second)
+	}
+	for i := 0; i < latencyWindow; i++ {
+		h.Observe(time.Millisecond)
+	}
+
+	snap := h.Snapshot()
+	if snap.Count != 2*latencyWindow {
+		t.Errorf("Count = %d, want %d", snap.Count, 2*latencyWindow)
+	}
+	if snap.P99 != time.Millisecond {
+		t.Errorf("P99 = %v, want old samples evicted", snap.P99)
+	}
+	if snap.Max != time.Second {
+		t.Errorf("Max = %v, want all-time max", snap.Max)
+	}
+}
+
+func TestApp_HandleLockMessageRecordsLatency(t *testing.T) {
+	ctx := context.Background()
+	lockService := lock.NewLockService(ctx, "node-a", "A")
+	defer lockService.Close()
+
+	a := &App{
+		logger:      logging.New(io.Discard, "error"),
+		lockService: lockService,
+		procMetrics: NewProcessingMetrics(),
+	}
+
+	data, _ := json.Marshal(lock.ReleaseMessage{Type: "lock_released", LockID: "lock-unknown"})
+	a.handleSingleLockMessage(data, "node-b")
+
+	snap := a.ProcessingMetrics().Snapshot()
+	for _, stage := range []string{StageLockDecode, StageLockApply, StageLockTotal} {
+		if snap[stage].Count != 1 {
+			t.Errorf("expected one %s sample, got %d", stage, snap[stage].Count)
+		}
+	}
+
+	// Malformed messages still count towards total time but not apply
+	a.handleSingleLockMessage([]byte("not json"), "node-b")
+	snap = a.ProcessingMetrics().Snapshot()
+	if snap[StageLockTotal].Count != 2 || snap[StageLockApply].Count != 1 {
+		t.Errorf("unexpected counts after malformed message: total=%d apply=%d",
+			snap[StageLockTotal].Count, snap[StageLockApply].Count)
+	}
+}
+
+func TestApp_HandleContextMessageRecordsLatency(t *testing.T) {
+	a := &App{
+		logger:      logging.New(io.Discard, "error"),
+		syncManager: ctxsync.NewSyncManager("node-a", "A"),
+		procMetrics: NewProcessingMetrics(),
+	}
+
+	delta := ctxsync.NewAgentStatusDelta("node-b", "B", ctxsync.NewVectorClock(), "node-b", "online")
+	data, _ := json.Marshal(delta)
+	a.handleSingleContextMessage(context.Background(), data, "node-b")
+
+	snap := a.ProcessingMetrics().Snapshot()
+	for _, stage := range []string{StageContextDecode, StageContextApply, StageContextTotal} {
+		if snap[stage].Count != 1 {
+			t.Errorf("expected one %s sample, got %d", stage, snap[stage].Count)
+		}
+	}
+	if _, ok := snap[StageContextEmbed]; ok {
+		t.Error("no embedding should be recorded without an embedding service")
+	}
+}
@@ -0,0 +1,99 @@
+package application
+
+import (
+	"fmt"
+	"time"
+
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// maxProvenanceChain bounds how many documents a provenance walk visits.
+const maxProvenanceChain = 64
+
+// ProvenanceEntry is one document in a provenance chain.
+type ProvenanceEntry struct {
+	DocumentID string    `json:"document_id"`
+	FilePath   string    `json:"file_path,omitempty"`
+	SourceID   string    `json:"source_id,omitempty"`
+	SourceName string    `json:"source_name,omitempty"`
+	SharedAt   time.Time `json:"shared_at,omitzero"`
+	ParentIDs  []string  `json:"parent_ids,omitempty"`
+	// Missing is set for a parent that is not in the local store, e.g.
+	// because it was shared before this node joined.
+	Missing bool `json:"missing,omitempty"`
+}
+
+// GetProvenance returns the provenance chain of a document: the document
+// itself followed by its ancestors, breadth first, each visited once.
+func (a *App) GetProvenance(documentID string) ([]ProvenanceEntry, error) {
+	store := a.VectorStore()
+	if store == nil {
+		return nil, fmt.Errorf("vector store not initialized")
+	}
+	if documentID == "" {
+		return nil, fmt.Errorf("document_id is required")
+	}
+
+	doc, err := vector.GetFromAny(store, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []ProvenanceEntry{provenanceEntry(doc)}
+	visited := map[string]bool{documentID: true}
+	queue := append([]string(nil), chain[0].ParentIDs...)
+
+	for len(queue) > 0 && len(chain) < maxProvenanceChain {
+		id := queue[0]
+		queue = queue[1:]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		parent, err := vector.GetFromAny(store, id)
+		if err != nil {
+			chain = append(chain, ProvenanceEntry{DocumentID: id, Missing: true})
+			continue
+		}
+		entry := provenanceEntry(parent)
+		chain = append(chain, entry)
+		queue = append(queue, entry.ParentIDs...)
+	}
+	return chain, nil
+}
+
+// provenanceEntry flattens a document's provenance. Documents stored
+// before provenance was recorded fall back to their metadata.
+func provenanceEntry(doc *vector.Document) ProvenanceEntry {
+	entry := ProvenanceEntry{
+		DocumentID: doc.ID,
+		FilePath:   doc.FilePath,
+		SharedAt:   doc.CreatedAt,
+	}
+	if p := doc.Provenance; p != nil {
+		entry.SourceID = p.SourceID
+		entry.SourceName = p.SourceName
+		entry.ParentIDs = p.ParentIDs
+		if !p.SharedAt.IsZero() {
+			entry.SharedAt = p.SharedAt
+		}
+	} else if sourceID, ok := doc.Metadata["source_id"].(string); ok {
+		entry.SourceID = sourceID
+	}
+	return entry
+}
+
+// sharedProvenance builds the provenance of a peer's share. The sender is
+// always the message source; the share time falls back to receipt time.
+func sharedProvenance(msg *ContextMessage, now time.Time) *vector.Provenance {
+	p := &vector.Provenance{SourceID: msg.SourceID, SharedAt: now}
+	if msg.Provenance != nil {
+		p.SourceName = msg.Provenance.SourceName
+		p.ParentIDs = msg.Provenance.ParentIDs
+		if !msg.Provenance.SharedAt.IsZero() {
+			p.SharedAt = msg.Provenance.SharedAt
+		}
+	}
+	return p
+}
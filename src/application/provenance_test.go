@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"agent-collab/src/infrastructure/storage/vector"
+	"agent-collab/src/pkg/logging"
+)
+
+// capturingStore remembers the last document inserted.
+type capturingStore struct {
+	*vector.MemoryStore
+	mu   sync.Mutex
+	last *vector.Document
+}
+
+func (s *capturingStore) Insert(doc *vector.Document) error {
+	if err := s.MemoryStore.Insert(doc); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last = doc
+	return nil
+}
+
+func TestApp_PeerShareKeepsProvenance(t *testing.T) {
+	mem, err := vector.NewMemoryStore(t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &capturingStore{MemoryStore: mem}
+	a := &App{
+		logger:      logging.New(io.Discard, "error"),
+		vectorStore: store,
+		procMetrics: NewProcessingMetrics(),
+		causal:      newCausalShares(),
+	}
+
+	// A parent we already have and one shared before we joined
+	if err := mem.Insert(&vector.Document{ID: "doc-a", Content: "a", Embedding: []float32{1, 0}, Metadata: map[string]any{"source_id": "node-old"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	sharedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	data, _ := json.Marshal(ContextMessage{
+		Type:      "shared_context",
+		FilePath:  "auth/login.go",
+		Content:   "b builds on a",
+		Embedding: []float32{0, 1},
+		SourceID:  "node-b",
+		Provenance: &vector.Provenance{
+			// A forged source is ignored in favour of the message sender
+			SourceID:   "node-x",
+			SourceName: "bob",
+			SharedAt:   sharedAt,
+			ParentIDs:  []string{"doc-a", "doc-gone"},
+		},
+	})
+	a.handleSingleContextMessage(context.Background(), data, "node-b")
+
+	store.mu.Lock()
+	doc := store.last
+	store.mu.Unlock()
+	if doc == nil {
+		t.Fatal("peer share should be stored")
+	}
+
+	chain, err := a.GetProvenance(doc.ID)
+	if err != nil {
+		t.Fatalf("GetProvenance failed: %v", err)
+	}
+	if len(chain) != 3 {
+		t.Fatalf("expected share, known parent and missing parent, got %+v", chain)
+	}
+	if got := chain[0]; got.SourceID != "node-b" || got.SourceName != "bob" || !got.SharedAt.Equal(sharedAt) {
+		t.Errorf("share should keep sender provenance, got %+v", got)
+	}
+	if got := chain[1]; got.DocumentID != "doc-a" || got.SourceID != "node-old" || got.Missing {
+		t.Errorf("known parent should fall back to metadata source, got %+v", got)
+	}
+	if got := chain[2]; got.DocumentID != "doc-gone" || !got.Missing {
+		t.Errorf("unknown parent should be marked missing, got %+v", got)
+	}
+}
+
+func TestApp_GetProvenanceStopsOnCycles(t *testing.T) {
+	mem, err := vector.NewMemoryStore(t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{vectorStore: mem}
+	for id, parent := range map[string]string{"a": "b", "b": "a"} {
+		doc := &vector.Document{ID: id, Content: id, Embedding: []float32{1}, Provenance: &vector.Provenance{ParentIDs: []string{parent}}}
+		if err := mem.Insert(doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chain, err := a.GetProvenance("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 {
+		t.Errorf("each document should appear once, got %+v", chain)
+	}
+}
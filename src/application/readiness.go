@@ -0,0 +1,212 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"agent-collab/src/infrastructure/embedding"
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// Readiness check names.
+const (
+	ReadinessRunning     = "running"
+	ReadinessPeers       = "peers"
+	ReadinessEmbedding   = "embedding"
+	ReadinessVectorStore = "vector_store"
+)
+
+// Subsystem states reported by verbose readiness.
+const (
+	SubsystemUp   = "up"
+	SubsystemSlow = "slow"
+	SubsystemDown = "down"
+)
+
+// DefaultSlowProbe is the probe latency above which a working subsystem is
+// reported slow.
+const DefaultSlowProbe = time.Second
+
+// SubsystemHealth is the detailed state of one dependency: whether its
+// probe passed, how long it took and when it last worked.
+type SubsystemHealth struct {
+	Name        string    `json:"name"`
+	State       string    `json:"state"`
+	LatencyMs   int64     `json:"latency_ms"`
+	LastSuccess time.Time `json:"last_success,omitzero"`
+	Message     string    `json:"message,omitempty"`
+}
+
+// probeHistory remembers when a subsystem probe last succeeded.
+type probeHistory struct {
+	mu     sync.Mutex
+	lastOK time.Time
+}
+
+// record notes a probe outcome and returns the last success.
+func (p *probeHistory) record(ok bool, at time.Time) time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ok {
+		p.lastOK = at
+	}
+	return p.lastOK
+}
+
+// ReadinessCheck is the outcome of one readiness condition.
+type ReadinessCheck struct {
+	Name    string `json:"name"`
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
+}
+
+// Readiness reports whether the node can serve requests, with the outcome
+// of every configured condition.
+type Readiness struct {
+	Ready     bool                    `json:"ready"`
+	Checks    []ReadinessCheck        `json:"checks"`
+	Embedding *embedding.HealthStatus `json:"embedding,omitempty"`
+	// Subsystems is only filled by CheckReadinessVerbose.
+	Subsystems []SubsystemHealth `json:"subsystems,omitempty"`
+}
+
+// CheckReadiness evaluates the readiness conditions. The node must be
+// running; the configured peer count, embedding health and vector store
+// writability are checked on top, so orchestrators do not route traffic
+// to a half-initialized daemon.
+func (a *App) CheckReadiness(ctx context.Context) *Readiness {
+	return a.checkReadiness(ctx, false)
+}
+
+// CheckReadinessVerbose evaluates the readiness conditions like
+// CheckReadiness and also probes the embedding provider and vector store,
+// reporting each with its latency so slow-but-up dependencies show.
+func (a *App) CheckReadinessVerbose(ctx context.Context) *Readiness {
+	return a.checkReadiness(ctx, true)
+}
+
+func (a *App) checkReadiness(ctx context.Context, verbose bool) *Readiness {
+	a.mu.RLock()
+	running := a.running
+	node := a.node
+	embedService := a.embedService
+	vectorStore := a.vectorStore
+	a.mu.RUnlock()
+
+	r := &Readiness{Ready: true}
+	add := func(name string, ready bool, message string) {
+		r.Checks = append(r.Checks, ReadinessCheck{Name: name, Ready: ready, Message: message})
+		r.Ready = r.Ready && ready
+	}
+
+	if running {
+		add(ReadinessRunning, true, "")
+	} else {
+		add(ReadinessRunning, false, "node is not running")
+	}
+
+	if minPeers := a.config.ReadinessMinPeers; minPeers > 0 {
+		peers := 0
+		if node != nil {
+			peers = len(node.ConnectedPeers())
+		}
+		add(ReadinessPeers, peers >= minPeers, fmt.Sprintf("%d/%d peers connected", peers, minPeers))
+	}
+
+	if embedService != nil {
+		r.Embedding = embedService.HealthCheck(ctx)
+		if !a.config.ReadinessSkipEmbedding {
+			add(ReadinessEmbedding, r.Embedding.Healthy, r.Embedding.Message)
+		}
+	}
+
+	var storeErr error
+	var storeLatency time.Duration
+	if a.config.ReadinessRequireWritableStore || verbose {
+		start := time.Now()
+		storeErr = probeVectorStore(vectorStore, a.config.DataDir)
+		storeLatency = time.Since(start)
+	}
+	if a.config.ReadinessRequireWritableStore {
+		if storeErr != nil {
+			add(ReadinessVectorStore, false, storeErr.Error())
+		} else {
+			add(ReadinessVectorStore, true, "")
+		}
+	}
+
+	if verbose {
+		slow, err := a.config.ReadinessSlowThreshold()
+		if err != nil {
+			slow = DefaultSlowProbe
+		}
+		if e := r.Embedding; e != nil {
+			lastSuccess := e.LastSuccess
+			if e.Probed && e.Healthy {
+				lastSuccess = e.CheckedAt
+			}
+			latency := time.Duration(e.LatencyMs) * time.Millisecond
+			r.Subsystems = append(r.Subsystems, subsystemHealth(ReadinessEmbedding, e.Healthy, latency, slow, lastSuccess, e.Message))
+		}
+		now := time.Now()
+		message := ""
+		if storeErr != nil {
+			message = storeErr.Error()
+		}
+		lastSuccess := a.vectorProbes.record(storeErr == nil, now)
+		r.Subsystems = append(r.Subsystems, subsystemHealth(ReadinessVectorStore, storeErr == nil, storeLatency, slow, lastSuccess, message))
+	}
+
+	return r
+}
+
+// subsystemHealth rates a probe outcome against the slow threshold.
+func subsystemHealth(name string, ok bool, latency, slow time.Duration, lastSuccess time.Time, message string) SubsystemHealth {
+	h := SubsystemHealth{
+		Name:        name,
+		State:       SubsystemUp,
+		LatencyMs:   latency.Milliseconds(),
+		LastSuccess: lastSuccess,
+		Message:     message,
+	}
+	switch {
+	case !ok:
+		h.State = SubsystemDown
+	case latency > slow:
+		h.State = SubsystemSlow
+		if h.Message == "" {
+			h.Message = fmt.Sprintf("probe took %s (slow above %s)", latency.Round(time.Millisecond), slow)
+		}
+	}
+	return h
+}
+
+// probeVectorStore checks that the vector store can persist data by
+// flushing it and writing a scratch file in its data directory.
+func probeVectorStore(store vector.Store, dir string) error {
+	if store == nil {
+		return fmt.Errorf("vector store not initialized")
+	}
+	if err := store.Flush(); err != nil {
+		return fmt.Errorf("flush failed: %w", err)
+	}
+
+	f, err := os.CreateTemp(dir, ".readiness-*")
+	if err != nil {
+		return fmt.Errorf("data directory not writable: %w", err)
+	}
+	name := f.Name()
+	_, werr := f.Write([]byte("ok"))
+	cerr := f.Close()
+	os.Remove(name)
+	if werr != nil {
+		return fmt.Errorf("data directory not writable: %w", werr)
+	}
+	if cerr != nil {
+		return fmt.Errorf("data directory not writable: %w", cerr)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"agent-collab/src/infrastructure/embedding"
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// probedProvider is an embedding provider with a controllable health probe.
+type probedProvider struct {
+	err   error
+	delay time.Duration
+}
+
+func (p *probedProvider) Name() embedding.Provider { return embedding.ProviderOllama }
+func (p *probedProvider) Embed(ctx context.Context, texts []string) ([][]float32, int, error) {
+	return nil, 0, p.err
+}
+func (p *probedProvider) Dimension() int            { return 2 }
+func (p *probedProvider) Model() string             { return "probe" }
+func (p *probedProvider) SupportsModel(string) bool { return true }
+func (p *probedProvider) HealthCheck(ctx context.Context) error {
+	time.Sleep(p.delay)
+	return p.err
+}
+
+func readinessCheck(t *testing.T, r *Readiness, name string) ReadinessCheck {
+	t.Helper()
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("readiness has no %q check: %+v", name, r.Checks)
+	return ReadinessCheck{}
+}
+
+func TestApp_ReadinessRequiresRunning(t *testing.T) {
+	a := &App{config: &Config{}}
+	if r := a.CheckReadiness(context.Background()); r.Ready || readinessCheck(t, r, ReadinessRunning).Ready {
+		t.Errorf("stopped node should not be ready: %+v", r)
+	}
+
+	a.running = true
+	if r := a.CheckReadiness(context.Background()); !r.Ready || len(r.Checks) != 1 {
+		t.Errorf("running node without extra conditions should be ready: %+v", r)
+	}
+}
+
+func TestApp_ReadinessMinPeers(t *testing.T) {
+	a := &App{config: &Config{ReadinessMinPeers: 1}, running: true}
+	r := a.CheckReadiness(context.Background())
+	if r.Ready || readinessCheck(t, r, ReadinessPeers).Ready {
+		t.Errorf("node without peers should not be ready: %+v", r)
+	}
+	if msg := readinessCheck(t, r, ReadinessPeers).Message; msg != "0/1 peers connected" {
+		t.Errorf("unexpected peer message: %q", msg)
+	}
+}
+
+func TestApp_ReadinessEmbedding(t *testing.T) {
+	provider := &probedProvider{}
+	a := &App{config: &Config{}, running: true, embedService: embedding.NewServiceWithProvider(provider)}
+
+	if r := a.CheckReadiness(context.Background()); !r.Ready || !readinessCheck(t, r, ReadinessEmbedding).Ready {
+		t.Errorf("healthy provider should be ready: %+v", r)
+	}
+
+	provider.err = errors.New("connection refused")
+	r := a.CheckReadiness(context.Background())
+	if r.Ready || readinessCheck(t, r, ReadinessEmbedding).Message != "connection refused" {
+		t.Errorf("unhealthy provider should block readiness: %+v", r)
+	}
+
+	// Skipping the embedding condition still reports the provider health
+	a.config.ReadinessSkipEmbedding = true
+	if r := a.CheckReadiness(context.Background()); !r.Ready || r.Embedding == nil || r.Embedding.Healthy {
+		t.Errorf("skipped embedding check should not block readiness: %+v", r)
+	}
+}
+
+func TestApp_ReadinessWritableStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := vector.NewMemoryStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{config: &Config{DataDir: dir, ReadinessRequireWritableStore: true}, running: true, vectorStore: store}
+
+	before, _ := os.ReadDir(dir)
+	if r := a.CheckReadiness(context.Background()); !r.Ready || !readinessCheck(t, r, ReadinessVectorStore).Ready {
+		t.Errorf("writable store should be ready: %+v", r)
+	}
+	if after, _ := os.ReadDir(dir); len(after) != len(before) {
+		t.Errorf("probe should leave no files behind, %d -> %d entries", len(before), len(after))
+	}
+
+	// A data directory that cannot hold files
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	a.config.DataDir = filepath.Join(blocker, "data")
+	if r := a.CheckReadiness(context.Background()); r.Ready || readinessCheck(t, r, ReadinessVectorStore).Ready {
+		t.Errorf("unwritable store should not be ready: %+v", r)
+	}
+
+	a.vectorStore = nil
+	if r := a.CheckReadiness(context.Background()); r.Ready {
+		t.Errorf("missing store should not be ready: %+v", r)
+	}
+}
+
+func subsystem(t *testing.T, r *Readiness, name string) SubsystemHealth {
+	t.Helper()
+	for _, sub := range r.Subsystems {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	t.Fatalf("readiness has no %q subsystem: %+v", name, r.Subsystems)
+	return SubsystemHealth{}
+}
+
+func TestApp_ReadinessVerboseReportsSlowEmbedding(t *testing.T) {
+	dir := t.TempDir()
+	store, err := vector.NewMemoryStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &probedProvider{delay: 30 * time.Millisecond}
+	a := &App{
+		config:       &Config{DataDir: dir, ReadinessSlowProbe: "10ms"},
+		running:      true,
+		embedService: embedding.NewServiceWithProvider(provider),
+		vectorStore:  store,
+	}
+
+	if r := a.CheckReadiness(context.Background()); len(r.Subsystems) != 0 {
+		t.Errorf("subsystems are only reported when verbose: %+v", r.Subsystems)
+	}
+
+	r := a.CheckReadinessVerbose(context.Background())
+	emb := subsystem(t, r, ReadinessEmbedding)
+	if emb.State != SubsystemSlow || emb.LatencyMs < 30 || emb.LastSuccess.IsZero() {
+		t.Errorf("slow probe should be reported slow with its latency: %+v", emb)
+	}
+	if !r.Ready {
+		t.Errorf("a slow provider is still up and ready: %+v", r)
+	}
+	if vs := subsystem(t, r, ReadinessVectorStore); vs.State == SubsystemDown || vs.LastSuccess.IsZero() {
+		t.Errorf("writable store should be up: %+v", vs)
+	}
+
+	// A failing probe is down, keeping the last success
+	provider.err = errors.New("connection refused")
+	provider.delay = 0
+	r = a.CheckReadinessVerbose(context.Background())
+	emb = subsystem(t, r, ReadinessEmbedding)
+	if emb.State != SubsystemDown || emb.Message != "connection refused" {
+		t.Errorf("failing probe should be down: %+v", emb)
+	}
+
+	a.vectorStore = nil
+	first := subsystem(t, r, ReadinessVectorStore).LastSuccess
+	vs := subsystem(t, a.CheckReadinessVerbose(context.Background()), ReadinessVectorStore)
+	if vs.State != SubsystemDown || !vs.LastSuccess.Equal(first) {
+		t.Errorf("missing store should be down and keep its last success %v: %+v", first, vs)
+	}
+}
@@ -0,0 +1,51 @@
+package application
+
+import (
+	"fmt"
+
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// ContextRating is the relevance feedback recorded for a document.
+type ContextRating struct {
+	DocumentID string  `json:"document_id"`
+	Useful     int     `json:"useful"`
+	NotUseful  int     `json:"not_useful"`
+	Boost      float32 `json:"boost"`
+}
+
+// RateContext records whether a search result was useful. Useful documents
+// rank higher in later searches and unhelpful ones lower.
+func (a *App) RateContext(documentID string, useful bool) (*ContextRating, error) {
+	store := a.VectorStore()
+	if store == nil {
+		return nil, fmt.Errorf("vector store not initialized")
+	}
+	if documentID == "" {
+		return nil, fmt.Errorf("document_id is required")
+	}
+
+	a.feedbackMu.Lock()
+	defer a.feedbackMu.Unlock()
+
+	doc, err := vector.GetFromAny(store, documentID)
+	if err != nil {
+		return nil, err
+	}
+	rated := vector.WithFeedback(doc, useful)
+	if err := store.Insert(rated); err != nil {
+		return nil, fmt.Errorf("failed to record feedback: %w", err)
+	}
+	if err := store.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to persist feedback: %w", err)
+	}
+
+	usefulCount, _ := rated.Metadata[vector.MetaUsefulCount].(float64)
+	notUsefulCount, _ := rated.Metadata[vector.MetaNotUsefulCount].(float64)
+	return &ContextRating{
+		DocumentID: documentID,
+		Useful:     int(usefulCount),
+		NotUseful:  int(notUsefulCount),
+		Boost:      vector.RelevanceBoost(rated),
+	}, nil
+}
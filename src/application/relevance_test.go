@@ -0,0 +1,51 @@
+package application
+
+import (
+	"testing"
+
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+func TestApp_RateContextPersistsAndReranks(t *testing.T) {
+	dir := t.TempDir()
+	store, err := vector.NewMemoryStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"helpful", "noise"} {
+		if err := store.Insert(&vector.Document{ID: id, Content: id, Embedding: []float32{1, 0}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := &App{vectorStore: store}
+
+	for range 2 {
+		if _, err := a.RateContext("helpful", true); err != nil {
+			t.Fatalf("RateContext: %v", err)
+		}
+	}
+	rating, err := a.RateContext("noise", false)
+	if err != nil {
+		t.Fatalf("RateContext: %v", err)
+	}
+	if rating.NotUseful != 1 || rating.Useful != 0 || rating.Boost >= 0 {
+		t.Errorf("unexpected rating: %+v", rating)
+	}
+
+	// Feedback survives a restart and ranks the helpful document first
+	reopened, err := vector.NewMemoryStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := reopened.Search([]float32{1, 0}, &vector.SearchOptions{TopK: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Document.ID != "helpful" || results[1].Document.ID != "noise" {
+		t.Errorf("expected helpful before noise, got %+v", results)
+	}
+
+	if _, err := a.RateContext("missing", true); err == nil {
+		t.Error("rating an unknown document should fail")
+	}
+}
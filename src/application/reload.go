@@ -0,0 +1,303 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"agent-collab/src/domain/interest"
+)
+
+// ConfigWatchInterval is how often a running app checks config.json for
+// changes to reload.
+const ConfigWatchInterval = 5 * time.Second
+
+// Settings applied live by ReloadConfig, by json key. Any other changed
+// setting takes effect on the next start.
+var (
+	rateLimitKeys = []string{
+		"lock_rate_limit", "lock_rate_burst", "lock_rate_adaptive",
+		"lock_rate_adaptive_min", "lock_rate_adaptive_max",
+	}
+	interestKeys  = []string{"interest_profiles", "interest_profile"}
+	embeddingKeys = []string{
+		"embedding_provider", "embedding_model", "embedding_dimension",
+		"embedding_api_key", "embedding_base_url", "embedding_batch_size",
+		"embedding_max_retries", "embedding_mode", "embedding_query_prefix",
+		"embedding_document_prefix", "max_inflight_embeddings",
+		"embedding_overload_policy", "max_queued_embeddings",
+		"embedding_summary", "embedding_summary_tokens",
+		"embedding_summary_provider", "embedding_summary_model",
+		"embedding_summary_api_key", "embedding_summary_base_url",
+	}
+	topologyKeys = []string{
+		"peer_latency_sla", "peer_latency_sla_window", "peer_latency_slas",
+		"peer_exchange_interval", "peer_exchange_sample_size",
+	}
+	pricingKeys = []string{"token_pricing", "token_pricing_url"}
+)
+
+// ReloadResult reports what a configuration reload changed.
+type ReloadResult struct {
+	// Applied lists the changed settings now in effect.
+	Applied []string `json:"applied,omitempty"`
+	// RestartRequired lists changed settings that take effect only after
+	// the daemon restarts.
+	RestartRequired []string `json:"restart_required,omitempty"`
+}
+
+// Changed reports whether the reload found any changed setting.
+func (r *ReloadResult) Changed() bool {
+	return len(r.Applied) > 0 || len(r.RestartRequired) > 0
+}
+
+// ReloadConfig re-reads config.json and applies changed lock rate limits,
+// interest profiles, embedding settings, topology parameters (peer
+// latency SLA, peer exchange) and token pricing without a restart, so
+// held locks survive. The new configuration is validated first; if any of
+// it is invalid nothing is applied. Changing the embedding dimension is refused, since
+// stored vectors could no longer be searched.
+func (a *App) ReloadConfig() (*ReloadResult, error) {
+	result, err := a.reloadConfig()
+	if err != nil {
+		a.logger.Warn("config reload failed", "error", err)
+		return nil, err
+	}
+	if result.Changed() {
+		a.handleConfigReloaded(result)
+	}
+	return result, nil
+}
+
+func (a *App) reloadConfig() (*ReloadResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.running {
+		return nil, fmt.Errorf("app is not running")
+	}
+
+	next, err := a.readConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	changed, err := changedConfigKeys(a.config, next)
+	if err != nil {
+		return nil, err
+	}
+	changes := func(keys []string) bool {
+		return slices.ContainsFunc(keys, func(k string) bool { return slices.Contains(changed, k) })
+	}
+
+	// Validate everything before applying anything
+	rateLimit, err := next.LockRateLimitConfig()
+	if err != nil {
+		return nil, err
+	}
+	embedConfig, err := next.EmbeddingServiceConfig()
+	if err != nil {
+		return nil, err
+	}
+	if changes(embeddingKeys) && a.embedService != nil && embedConfig.Dimension != a.embedService.Dimension() {
+		return nil, fmt.Errorf("embedding dimension cannot change from %d to %d without re-indexing", a.embedService.Dimension(), embedConfig.Dimension)
+	}
+	slaConfig, err := next.LatencySLAConfig()
+	if err != nil {
+		return nil, err
+	}
+	pexConfig, err := next.PEXConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePricingConfig(next); err != nil {
+		return nil, err
+	}
+	profile := os.Getenv(interest.EnvInterestProfile)
+	if profile == "" {
+		profile = next.InterestProfile
+	}
+	if profile != "" {
+		if _, _, err := next.InterestProfiles.Resolve(profile); err != nil {
+			return nil, fmt.Errorf("invalid interest profile: %w", err)
+		}
+	}
+
+	if changes(embeddingKeys) && a.embedService != nil {
+		if err := a.embedService.Reconfigure(embedConfig); err != nil {
+			return nil, fmt.Errorf("invalid embedding config: %w", err)
+		}
+	}
+	if changes(interestKeys) && a.interestMgr != nil {
+		if err := a.replaceConfiguredInterests(next); err != nil {
+			return nil, fmt.Errorf("failed to re-register interests: %w", err)
+		}
+	}
+	if changes(rateLimitKeys) && a.lockService != nil {
+		a.lockService.SetRateLimit(rateLimit)
+	}
+	if changes(topologyKeys) {
+		if qm := a.node.QualityMonitor(); qm != nil {
+			qm.SetLatencySLA(slaConfig)
+		}
+		if px := a.node.PeerExchange(); px != nil && pexConfig != nil {
+			px.SetConfig(*pexConfig)
+		}
+	}
+
+	if changes(pricingKeys) {
+		a.applyTokenPricing(next)
+	}
+
+	result := &ReloadResult{}
+	live := slices.Concat(rateLimitKeys, interestKeys, embeddingKeys, topologyKeys, pricingKeys)
+	for _, key := range changed {
+		if slices.Contains(live, key) {
+			result.Applied = append(result.Applied, key)
+		} else {
+			result.RestartRequired = append(result.RestartRequired, key)
+		}
+	}
+	copyConfigKeys(a.config, next, live)
+	return result, nil
+}
+
+// readConfigFile reads config.json from the data directory.
+func (a *App) readConfigFile() (*Config, error) {
+	return LoadConfig(a.config.DataDir)
+}
+
+// replaceConfiguredInterests replaces the interests registered from the
+// previous configuration with those of next.
+func (a *App) replaceConfiguredInterests(next *Config) error {
+	nodeID := a.node.ID().String()
+	agentName := os.Getenv("AGENT_NAME")
+	if agentName == "" {
+		agentName = a.config.ProjectName + "-agent"
+	}
+
+	registered, err := interest.ReplaceFromConfig(a.interestMgr, nodeID, agentName,
+		next.InterestProfiles, next.InterestProfile)
+	if err != nil {
+		return err
+	}
+	if registered != nil {
+		a.logger.Info("Re-registered interests",
+			"agent_id", nodeID,
+			"profile", registered.Metadata["profile"],
+			"patterns", registered.Patterns)
+	}
+	return nil
+}
+
+// changedConfigKeys returns the sorted json keys whose values differ
+// between two configurations.
+func changedConfigKeys(current, next *Config) ([]string, error) {
+	before, err := configFields(current)
+	if err != nil {
+		return nil, err
+	}
+	after, err := configFields(next)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for key, value := range after {
+		if !bytes.Equal(before[key], value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	slices.Sort(changed)
+	return changed, nil
+}
+
+func configFields(cfg *Config) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// copyConfigKeys copies the fields with the given json keys from src to
+// dst.
+func copyConfigKeys(dst, src *Config, keys []string) {
+	dv, sv := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+	for i := range dv.NumField() {
+		name, _, _ := strings.Cut(dv.Type().Field(i).Tag.Get("json"), ",")
+		if slices.Contains(keys, name) {
+			dv.Field(i).Set(sv.Field(i))
+		}
+	}
+}
+
+// watchConfig reloads the configuration whenever config.json changes,
+// until ctx is done.
+func (a *App) watchConfig(ctx context.Context, interval time.Duration) {
+	configPath := filepath.Join(a.config.DataDir, "config.json")
+	modTime := func() time.Time {
+		info, err := os.Stat(configPath)
+		if err != nil {
+			return time.Time{}
+		}
+		return info.ModTime()
+	}
+
+	last := modTime()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := modTime()
+			if current.IsZero() || current.Equal(last) {
+				continue
+			}
+			last = current
+			// Failures are logged by ReloadConfig; the running
+			// configuration stays in effect
+			_, _ = a.ReloadConfig()
+		}
+	}
+}
+
+// handleConfigReloaded logs a reload and calls the handler.
+func (a *App) handleConfigReloaded(result *ReloadResult) {
+	a.logger.Info("config reloaded",
+		"applied", result.Applied,
+		"restart_required", result.RestartRequired)
+
+	a.mu.RLock()
+	handler := a.onConfigReloaded
+	a.mu.RUnlock()
+	if handler != nil {
+		handler(result)
+	}
+}
+
+// SetConfigReloadedHandler sets the callback invoked when a reload
+// changes any setting.
+func (a *App) SetConfigReloadedHandler(handler func(*ReloadResult)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onConfigReloaded = handler
+}
@@ -0,0 +1,136 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"agent-collab/src/application"
+)
+
+// editConfig rewrites the app's config.json with edit applied.
+func editConfig(t *testing.T, app *application.App, edit func(map[string]any)) {
+	t.Helper()
+
+	path := filepath.Join(app.Config().DataDir, "config.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	edit(fields)
+	if data, err = json.Marshal(fields); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApp_ReloadConfig_AppliesLiveSettings(t *testing.T) {
+	app, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(context.Background(), "reload-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := app.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	t.Cleanup(func() { app.Stop() })
+
+	var reloaded *application.ReloadResult
+	app.SetConfigReloadedHandler(func(result *application.ReloadResult) { reloaded = result })
+
+	result, err := app.ReloadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed() || reloaded != nil {
+		t.Fatalf("expected no changes for an untouched config, got %+v", result)
+	}
+
+	editConfig(t, app, func(fields map[string]any) {
+		fields["lock_rate_limit"] = 2
+		fields["lock_rate_burst"] = 4
+		fields["interest_profiles"] = map[string]any{"backend": map[string]any{"patterns": []string{"api/**"}}}
+		fields["interest_profile"] = "backend"
+		fields["peer_exchange_interval"] = "1m"
+		fields["topic_scope"] = "project"
+	})
+	result, err = app.ReloadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"interest_profile", "interest_profiles", "lock_rate_burst", "lock_rate_limit", "peer_exchange_interval"} {
+		if !slices.Contains(result.Applied, key) {
+			t.Errorf("expected %s applied, got %v", key, result.Applied)
+		}
+	}
+	if !slices.Equal(result.RestartRequired, []string{"topic_scope"}) {
+		t.Errorf("expected topic_scope to need a restart, got %v", result.RestartRequired)
+	}
+	if reloaded != result {
+		t.Error("expected the reloaded handler to receive the result")
+	}
+
+	if stats := app.LockService().RateLimitStats(); stats.Rate != 2 || stats.Burst != 4 {
+		t.Errorf("expected the new lock rate limit, got %+v", stats)
+	}
+	if matches := app.InterestManager().Match("api/handler.go"); len(matches) != 1 {
+		t.Errorf("expected the backend profile to match, got %d matches", len(matches))
+	}
+	if px := app.Node().PeerExchange(); px != nil && px.Config().Interval != time.Minute {
+		t.Errorf("expected the new peer exchange interval, got %s", px.Config().Interval)
+	}
+	if app.Config().TopicScope != "" {
+		t.Errorf("restart-only settings should not change the running config, got %q", app.Config().TopicScope)
+	}
+}
+
+func TestApp_ReloadConfig_RejectsInvalidConfig(t *testing.T) {
+	app, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(context.Background(), "reload-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if _, err := app.ReloadConfig(); err == nil {
+		t.Error("expected reload to fail before the app starts")
+	}
+	if err := app.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	t.Cleanup(func() { app.Stop() })
+
+	// A bad setting anywhere rejects the whole reload
+	editConfig(t, app, func(fields map[string]any) {
+		fields["lock_rate_limit"] = 2
+		fields["interest_profile"] = "missing"
+	})
+	if _, err := app.ReloadConfig(); err == nil {
+		t.Fatal("expected an unknown interest profile to be rejected")
+	}
+	if stats := app.LockService().RateLimitStats(); stats.Rate == 2 {
+		t.Error("a rejected reload should not apply any setting")
+	}
+
+	// Stored vectors would no longer be searchable
+	editConfig(t, app, func(fields map[string]any) {
+		delete(fields, "interest_profile")
+		fields["embedding_dimension"] = app.EmbeddingService().Dimension() + 1
+	})
+	if _, err := app.ReloadConfig(); err == nil {
+		t.Error("expected an embedding dimension change to be rejected")
+	}
+}
@@ -0,0 +1,133 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+
+	"agent-collab/src/infrastructure/crypto"
+)
+
+// Role decides which daemon operations a node's agents may perform.
+type Role string
+
+const (
+	// RoleAdmin may also run operator operations such as force release
+	// and snapshots. Nodes that create a cluster are admins.
+	RoleAdmin Role = "admin"
+	// RoleAgent locks, shares context and releases its own locks. Nodes
+	// joining without a role claim are agents.
+	RoleAgent Role = "agent"
+	// RoleObserver may only list and watch.
+	RoleObserver Role = "observer"
+)
+
+// ParseRole parses a role name.
+func ParseRole(s string) (Role, error) {
+	switch Role(s) {
+	case RoleAdmin, RoleAgent, RoleObserver:
+		return Role(s), nil
+	default:
+		return "", fmt.Errorf("unknown role %q (want admin, agent or observer)", s)
+	}
+}
+
+// Permission is a class of daemon operations.
+type Permission string
+
+const (
+	// PermRead covers listing, searching and watching.
+	PermRead Permission = "read"
+	// PermWrite covers locking and sharing context.
+	PermWrite Permission = "write"
+	// PermOperate covers operator operations that affect other nodes'
+	// work or the node's whole state.
+	PermOperate Permission = "operate"
+)
+
+// ErrPermissionDenied is returned when the node's role does not allow an
+// operation.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Allows reports whether the role grants p.
+func (r Role) Allows(p Permission) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleAgent:
+		return p == PermRead || p == PermWrite
+	case RoleObserver:
+		return p == PermRead
+	default:
+		return false
+	}
+}
+
+// Role returns the node's role. Observer mode always means RoleObserver,
+// and admin holds only while the node has a valid admin grant; anything
+// else, including configs written before roles existed, is an agent.
+func (a *App) Role() Role {
+	if a.config.Observer {
+		return RoleObserver
+	}
+	switch Role(a.config.Role) {
+	case RoleObserver:
+		return RoleObserver
+	case RoleAdmin:
+		if verifyAdminGrant(a.config.RoleCredential, a.config.ClusterRoot, a.config.ProjectName) == nil {
+			return RoleAdmin
+		}
+	}
+	return RoleAgent
+}
+
+// verifyAdminGrant checks that cred grants admin in project and was
+// signed by the cluster's root.
+func verifyAdminGrant(cred *crypto.RoleCredential, root, project string) error {
+	if cred == nil || cred.Grant == nil {
+		return fmt.Errorf("%w: no admin grant", crypto.ErrInvalidGrant)
+	}
+	if cred.Grant.Role != string(RoleAdmin) || cred.Grant.Project != project {
+		return fmt.Errorf("%w: not an admin grant for %s", crypto.ErrInvalidGrant, project)
+	}
+	return cred.Grant.Verify(root)
+}
+
+// Authorize returns ErrPermissionDenied unless the node's role grants p.
+func (a *App) Authorize(p Permission) error {
+	if role := a.Role(); !role.Allows(p) {
+		return fmt.Errorf("%w: %s role cannot %s", ErrPermissionDenied, role, p)
+	}
+	return nil
+}
+
+// OperatorProof shows peers that the node sending an operator message is
+// an admin: its grant from the cluster's root and a signature binding the
+// grant to the sender's peer ID.
+type OperatorProof struct {
+	Grant *crypto.RoleGrant `json:"grant"`
+	Proof []byte            `json:"proof"`
+}
+
+// operatorProof returns this node's admin proof, or nil unless it is an admin.
+func (a *App) operatorProof() *OperatorProof {
+	if a.Role() != RoleAdmin || a.node == nil {
+		return nil
+	}
+	cred := a.config.RoleCredential
+	proof, err := cred.Prove(a.node.ID().String())
+	if err != nil {
+		return nil
+	}
+	return &OperatorProof{Grant: cred.Grant, Proof: proof}
+}
+
+// verifyOperator checks that op proves signer is an admin of this cluster.
+func (a *App) verifyOperator(op *OperatorProof, signer string) error {
+	if op == nil {
+		return fmt.Errorf("%w: no operator proof", crypto.ErrInvalidGrant)
+	}
+	if err := verifyAdminGrant(&crypto.RoleCredential{Grant: op.Grant}, a.config.ClusterRoot, a.config.ProjectName); err != nil {
+		return err
+	}
+	return op.Grant.VerifyHolder(signer, op.Proof)
+}
@@ -0,0 +1,139 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"agent-collab/src/application"
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/crypto"
+)
+
+func TestApp_RoleDefaults(t *testing.T) {
+	app, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Initialize(context.Background(), "role-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if role := app.Role(); role != application.RoleAdmin {
+		t.Errorf("expected the cluster creator to be admin, got %s", role)
+	}
+
+	upgraded, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role := upgraded.Role(); role != application.RoleAgent {
+		t.Errorf("expected a config without a role to be an agent, got %s", role)
+	}
+
+	claimed, err := application.New(&application.Config{DataDir: t.TempDir(), Role: string(application.RoleAdmin)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role := claimed.Role(); role != application.RoleAgent {
+		t.Errorf("expected an admin claim without a grant to be an agent, got %s", role)
+	}
+
+	observer, err := application.New(&application.Config{DataDir: t.TempDir(), Observer: true, Role: string(application.RoleAdmin)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role := observer.Role(); role != application.RoleObserver {
+		t.Errorf("expected observer mode to win over the role, got %s", role)
+	}
+}
+
+func TestApp_ObserverRoleCannotLock(t *testing.T) {
+	app, err := application.New(&application.Config{DataDir: t.TempDir(), Role: string(application.RoleObserver)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Initialize(context.Background(), "role-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	if !app.IsObserver() {
+		t.Error("the observer role should put the node in observer mode")
+	}
+	if err := app.Authorize(application.PermWrite); !errors.Is(err, application.ErrPermissionDenied) {
+		t.Errorf("expected observers to be denied writes, got %v", err)
+	}
+	_, err = app.LockService().AcquireLock(context.Background(), &lock.AcquireLockRequest{
+		TargetType: lock.TargetFile,
+		FilePath:   "main.go",
+		StartLine:  1,
+		EndLine:    10,
+		Intention:  "edit",
+	})
+	if !errors.Is(err, lock.ErrObserverMode) {
+		t.Errorf("expected the lock service to refuse observers, got %v", err)
+	}
+}
+
+func TestApp_InviteTokenCarriesRole(t *testing.T) {
+	app, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.Initialize(context.Background(), "role-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	t.Cleanup(func() { app.Stop() })
+
+	encoded, err := app.CreateInviteTokenForRole(application.RoleObserver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok, _, err := crypto.DecodeAnyToken(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.Role != string(application.RoleObserver) {
+		t.Errorf("expected the observer claim in the token, got %q", tok.Role)
+	}
+}
+
+func TestApp_AdminInviteCarriesGrant(t *testing.T) {
+	app, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := app.Initialize(context.Background(), "role-test")
+	if err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	t.Cleanup(func() { app.Stop() })
+
+	encoded, err := app.CreateInviteTokenForRole(application.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok, _, err := crypto.DecodeAnyToken(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.RootID() != result.NodeID {
+		t.Errorf("expected the creator as cluster root, got %q", tok.RootID())
+	}
+	if tok.Grant == nil || tok.Grant.Grant.Verify(result.NodeID) != nil {
+		t.Fatal("expected an admin grant signed by the cluster root")
+	}
+
+	// An admin claim stripped of its grant is refused on join
+	tok.Grant = nil
+	forged, err := tok.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	joiner, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := joiner.Join(context.Background(), forged); !errors.Is(err, crypto.ErrInvalidGrant) {
+		t.Errorf("expected an ungranted admin token to be rejected, got %v", err)
+	}
+}
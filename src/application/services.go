@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 
 	"agent-collab/src/domain/agent"
 	"agent-collab/src/domain/ctxsync"
 	"agent-collab/src/domain/event"
 	"agent-collab/src/domain/interest"
 	"agent-collab/src/domain/lock"
+	"agent-collab/src/domain/presence"
 	"agent-collab/src/domain/token"
 	"agent-collab/src/infrastructure/crypto"
 	"agent-collab/src/infrastructure/embedding"
@@ -27,6 +29,8 @@ func (a *App) GetStatus() *Status {
 	status := &Status{
 		Running:     a.running,
 		ProjectName: a.config.ProjectName,
+		Observer:    a.IsObserver(),
+		Role:        string(a.Role()),
 	}
 
 	if a.node != nil {
@@ -37,12 +41,16 @@ func (a *App) GetStatus() *Status {
 			status.Addresses[i] = addr.String()
 		}
 		status.PeerCount = len(a.node.ConnectedPeers())
+		if lm := a.node.LocalityManager(); lm != nil {
+			status.Regions = regionStatuses(lm.Regions(), lm.GetMyRegion())
+		}
 	}
 
 	if a.lockService != nil {
 		stats := a.lockService.GetStats()
 		status.LockCount = stats.TotalLocks
 		status.MyLockCount = stats.MyLocks
+		status.Negotiations = a.lockService.NegotiationMetrics()
 	}
 
 	if a.syncManager != nil {
@@ -60,8 +68,11 @@ func (a *App) GetStatus() *Status {
 	}
 
 	if a.vectorStore != nil {
-		if stats, err := a.vectorStore.GetCollectionStats("default"); err == nil {
-			status.EmbeddingCount = stats.Count
+		names, _ := a.vectorStore.ListCollections()
+		for _, name := range names {
+			if stats, err := a.vectorStore.GetCollectionStats(name); err == nil {
+				status.EmbeddingCount += stats.Count
+			}
 		}
 	}
 
@@ -78,11 +89,51 @@ func (a *App) GetStatus() *Status {
 	return status
 }
 
+// regionStatuses converts locality clusters into the status breakdown,
+// this node's region first.
+func regionStatuses(clusters []libp2p.LocalityCluster, myRegion string) []RegionStatus {
+	if len(clusters) == 0 {
+		return nil
+	}
+
+	regions := make([]RegionStatus, 0, len(clusters))
+	for _, c := range clusters {
+		regions = append(regions, RegionStatus{
+			Region:      c.Region,
+			PeerCount:   c.PeerCount,
+			AverageRTT:  c.AverageRTT,
+			GatewayPeer: c.GatewayPeer.String(),
+			Local:       c.Region == myRegion,
+		})
+	}
+	slices.SortStableFunc(regions, func(a, b RegionStatus) int {
+		switch {
+		case a.Local == b.Local:
+			return 0
+		case a.Local:
+			return -1
+		default:
+			return 1
+		}
+	})
+	return regions
+}
+
 // LockService는 락 서비스를 반환합니다.
 func (a *App) LockService() *lock.LockService {
 	return a.lockService
 }
 
+// Presence returns the active edits tracker.
+func (a *App) Presence() *presence.Tracker {
+	return a.presence
+}
+
+// ProcessingMetrics returns the message processing latency metrics.
+func (a *App) ProcessingMetrics() *ProcessingMetrics {
+	return a.procMetrics
+}
+
 // SyncManager는 동기화 관리자를 반환합니다.
 func (a *App) SyncManager() *ctxsync.SyncManager {
 	return a.syncManager
@@ -108,6 +159,15 @@ func (a *App) TokenTracker() *token.Tracker {
 	return a.tokenTracker
 }
 
+// tokenBudgetUsed returns the share of today's token budget used so far.
+func (a *App) tokenBudgetUsed() float64 {
+	tracker := a.TokenTracker()
+	if tracker == nil {
+		return 0
+	}
+	return tracker.GetMetrics().UsagePercent() / 100
+}
+
 // VectorStore returns the vector store.
 func (a *App) VectorStore() vector.Store {
 	return a.vectorStore
@@ -148,6 +208,9 @@ func (a *App) initPhase3Components(nodeID, nodeName string) error {
 	// Initialize token tracker
 	a.tokenTracker = token.NewTracker(nodeID, nodeName)
 
+	// Initialize presence tracker (ephemeral, never persisted)
+	a.presence = presence.NewTracker(nodeID, nodeName, presence.DefaultTTL)
+
 	// Initialize metrics store
 	metricsStore, err := metrics.NewStore(a.config.DataDir)
 	if err != nil {
@@ -167,15 +230,18 @@ func (a *App) initPhase3Components(nodeID, nodeName string) error {
 	}
 	a.vectorStore = vectorStore
 
-	// Initialize embedding service
-	embedConfig := embedding.DefaultConfig()
-	embedConfig.Provider = embedding.ProviderMock // Use mock by default
+	// Initialize embedding service (mock unless a provider is configured);
+	// provider calls are recorded as embedding token usage
+	embedConfig, err := a.config.EmbeddingServiceConfig()
+	if err != nil {
+		return err
+	}
 	a.embedService = embedding.NewService(embedConfig)
 	a.embedService.SetTokenTracker(a.tokenTracker)
 
 	// Wire embedding function to vector store
 	a.vectorStore.(*vector.MemoryStore).SetEmbeddingFunction(func(text string) ([]float32, error) {
-		return a.embedService.Embed(context.Background(), text)
+		return a.embedService.EmbedQuery(context.Background(), text)
 	})
 
 	// Initialize agent registry
@@ -188,18 +254,24 @@ func (a *App) initPhase3Components(nodeID, nodeName string) error {
 		NodeName:    nodeName,
 		VectorStore: vector.NewPortsAdapter(a.vectorStore),
 	})
+	// Digests are condensed with the context summarizer
+	a.eventRouter.SetSummarizer(a.embedService)
 
 	// Create event bridge for P2P integration
 	a.eventBridge = libp2p.NewEventBridge(a.node, a.eventRouter)
-	a.eventBridge.SetInterestManager(a.interestMgr)
+	// Observers filter events by their interests but do not announce them
+	if !a.IsObserver() {
+		a.eventBridge.SetInterestManager(a.interestMgr)
+	}
 
-	// Register interests from environment variable
+	// Register interests from profile and environment
 	a.registerInterestsFromEnv(nodeID, nodeName)
 
 	return nil
 }
 
-// registerInterestsFromEnv registers interests from AGENT_COLLAB_INTERESTS environment variable.
+// registerInterestsFromEnv registers interests from the configured interest
+// profile and the AGENT_COLLAB_INTERESTS environment variable.
 func (a *App) registerInterestsFromEnv(nodeID, nodeName string) {
 	if a.interestMgr == nil {
 		return
@@ -211,20 +283,21 @@ func (a *App) registerInterestsFromEnv(nodeID, nodeName string) {
 		agentName = nodeName
 	}
 
-	// Register interests from environment
-	registered, err := interest.RegisterFromEnvironment(a.interestMgr, nodeID, agentName)
+	registered, err := interest.RegisterFromConfig(a.interestMgr, nodeID, agentName,
+		a.config.InterestProfiles, a.config.InterestProfile)
 	if err != nil {
 		if a.logger != nil {
-			a.logger.Warn("Failed to register interests from environment", "error", err)
+			a.logger.Warn("Failed to register interests", "error", err)
 		}
 		return
 	}
 
 	if registered != nil {
 		if a.logger != nil {
-			a.logger.Info("Registered interests from environment",
+			a.logger.Info("Registered interests",
 				"agent_id", nodeID,
 				"agent_name", agentName,
+				"profile", registered.Metadata["profile"],
 				"patterns", registered.Patterns,
 				"level", registered.Level.String())
 		}
@@ -234,10 +307,21 @@ func (a *App) registerInterestsFromEnv(nodeID, nodeName string) {
 // PublishContextSharedEvent publishes a context shared event to EventRouter.
 // This is the single source of truth for publishing context events.
 func (a *App) PublishContextSharedEvent(ctx context.Context, filePath, content string, embedding []float32) {
-	if a.eventRouter == nil {
+	if a.eventRouter == nil || a.IsObserver() {
 		return
 	}
 
+	nodeID, nodeName := a.eventSource()
+	evt := event.NewContextSharedEvent(nodeID, nodeName, filePath, &event.ContextSharedPayload{
+		Content: content,
+	})
+	evt.Embedding = embedding
+
+	_ = a.eventRouter.Publish(ctx, evt)
+}
+
+// eventSource returns the source ID and name for events this node raises.
+func (a *App) eventSource() (string, string) {
 	nodeID := ""
 	nodeName := os.Getenv("AGENT_NAME")
 	if a.node != nil {
@@ -249,17 +333,19 @@ func (a *App) PublishContextSharedEvent(ctx context.Context, filePath, content s
 	if nodeName == "" {
 		nodeName = "Agent"
 	}
-
-	evt := event.NewContextSharedEvent(nodeID, nodeName, filePath, &event.ContextSharedPayload{
-		Content: content,
-	})
-	evt.Embedding = embedding
-
-	_ = a.eventRouter.Publish(ctx, evt)
+	return nodeID, nodeName
 }
 
 // CreateInviteToken creates an invite token.
 func (a *App) CreateInviteToken() (string, error) {
+	return a.CreateInviteTokenForRole("")
+}
+
+// CreateInviteTokenForRole creates an invite token that assigns role to
+// the joining node. An empty role leaves the joiner an agent. Only admins
+// can invite admins: the cluster's root signs a new grant, other admins
+// pass on their own.
+func (a *App) CreateInviteTokenForRole(role Role) (string, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
@@ -267,6 +353,20 @@ func (a *App) CreateInviteToken() (string, error) {
 		return "", fmt.Errorf("app is not initialized")
 	}
 
+	var grant *crypto.RoleCredential
+	if role == RoleAdmin {
+		if err := a.Authorize(PermOperate); err != nil {
+			return "", err
+		}
+		grant = a.config.RoleCredential
+		if a.config.ClusterRoot == a.node.ID().String() {
+			var err error
+			if grant, err = crypto.IssueRoleGrant(a.keyPair, a.config.ProjectName, string(RoleAdmin)); err != nil {
+				return "", err
+			}
+		}
+	}
+
 	addrs := a.node.Addrs()
 	addrStrs := make([]string, len(addrs))
 	for i, addr := range addrs {
@@ -279,6 +379,9 @@ func (a *App) CreateInviteToken() (string, error) {
 	if err != nil {
 		return "", err
 	}
+	token.Role = string(role)
+	token.Root = a.config.ClusterRoot
+	token.Grant = grant
 
 	return token.Encode()
 }
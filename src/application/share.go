@@ -0,0 +1,157 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"agent-collab/src/domain/token"
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// DefaultShareDedupWindow is how long an identical context share is
+// suppressed.
+const DefaultShareDedupWindow = 10 * time.Minute
+
+// ShareResult is the outcome of sharing context.
+type ShareResult struct {
+	DocumentID string
+	Embedding  []float32
+
+	// Duplicate is set when the same content was already shared for the
+	// file within the dedup window. Nothing was stored or broadcast.
+	Duplicate bool
+}
+
+// shareDedup remembers recent shares by content hash.
+type shareDedup struct {
+	mu      sync.Mutex
+	entries map[string]shareEntry
+}
+
+type shareEntry struct {
+	docID string
+	at    time.Time
+}
+
+func newShareDedup() *shareDedup {
+	return &shareDedup{entries: make(map[string]shareEntry)}
+}
+
+// shareKey hashes a (file path, content) pair.
+func shareKey(filePath, content string) string {
+	hash := sha256.Sum256([]byte(filePath + "\x00" + content))
+	return hex.EncodeToString(hash[:])
+}
+
+// lookup returns the document ID of a share of key within window.
+func (d *shareDedup) lookup(key string, window time.Duration, now time.Time) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for k, e := range d.entries {
+		if now.Sub(e.at) > window {
+			delete(d.entries, k)
+		}
+	}
+	e, ok := d.entries[key]
+	return e.docID, ok
+}
+
+// remember records a completed share.
+func (d *shareDedup) remember(key, docID string, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[key] = shareEntry{docID: docID, at: now}
+}
+
+// ShareContext embeds and stores content for a file, broadcasts it to
+// peers and publishes it for interest-based routing. Sharing the same
+// content for the same file again within the dedup window is a no-op that
+// returns the original document ID.
+func (a *App) ShareContext(ctx context.Context, filePath, content string, metadata map[string]any) (*ShareResult, error) {
+	return a.ShareDerivedContext(ctx, filePath, content, metadata, nil)
+}
+
+// ShareDerivedContext shares context like ShareContext and records in its
+// provenance the documents it was derived from.
+func (a *App) ShareDerivedContext(ctx context.Context, filePath, content string, metadata map[string]any, parentIDs []string) (*ShareResult, error) {
+	if a.IsObserver() {
+		return nil, ErrObserverMode
+	}
+
+	vectorStore := a.VectorStore()
+	embedService := a.EmbeddingService()
+	if vectorStore == nil || embedService == nil {
+		return nil, fmt.Errorf("services not initialized")
+	}
+
+	window, err := a.config.ShareDedupDuration()
+	if err != nil {
+		window = DefaultShareDedupWindow
+	}
+	key := shareKey(filePath, content)
+	if window > 0 {
+		if docID, ok := a.shares.lookup(key, window, time.Now()); ok {
+			return &ShareResult{DocumentID: docID, Duplicate: true}, nil
+		}
+	}
+
+	// A spent token budget stops the share before anything is embedded or
+	// broadcast
+	if tracker := a.TokenTracker(); tracker != nil {
+		if err := tracker.Allow(ctx); err != nil {
+			return nil, err
+		}
+		ctx = token.WithAdmission(ctx)
+	}
+
+	embedding, err := a.embedContent(ctx, "", content)
+	if err != nil {
+		return nil, fmt.Errorf("embedding failed: %w", err)
+	}
+
+	// Record the branch it was written on, so searches can filter by it
+	metadata = a.withGitHead(metadata, filePath)
+
+	sourceID, sourceName := a.eventSource()
+	provenance := &vector.Provenance{
+		SourceID:   sourceID,
+		SourceName: sourceName,
+		SharedAt:   time.Now(),
+		ParentIDs:  parentIDs,
+	}
+
+	doc := &vector.Document{
+		Content:    content,
+		Embedding:  embedding,
+		FilePath:   filePath,
+		Metadata:   metadata,
+		Provenance: provenance,
+	}
+	if err := vectorStore.Insert(doc); err != nil {
+		return nil, fmt.Errorf("insert failed: %w", err)
+	}
+	if err := vectorStore.Flush(); err != nil {
+		return nil, fmt.Errorf("flush failed: %w", err)
+	}
+
+	// Broadcast via P2P for other peers
+	if a.Node() != nil {
+		if err := a.broadcastContext(filePath, content, embedding, metadata, provenance); err != nil {
+			a.logger.Warn("failed to broadcast context", "file_path", filePath, "error", err)
+		}
+	}
+
+	// Publish to EventRouter for interest-based routing
+	a.PublishContextSharedEvent(ctx, filePath, content, embedding)
+
+	if window > 0 {
+		a.shares.remember(key, doc.ID, time.Now())
+	}
+
+	return &ShareResult{DocumentID: doc.ID, Embedding: embedding}, nil
+}
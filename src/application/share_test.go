@@ -0,0 +1,135 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"agent-collab/src/application"
+)
+
+// startSharingApp initializes and starts an app for context sharing.
+func startSharingApp(t *testing.T, dedupWindow string) *application.App {
+	t.Helper()
+
+	app, err := application.New(&application.Config{DataDir: t.TempDir(), ShareDedupWindow: dedupWindow})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(context.Background(), "share-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if err := app.Start(); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	t.Cleanup(func() { app.Stop() })
+	return app
+}
+
+// sharedCounts returns the stored document count and messages published.
+func sharedCounts(t *testing.T, app *application.App) (int64, int64) {
+	t.Helper()
+
+	var docs int64
+	// The default collection is created on first insert
+	if stats, err := app.VectorStore().GetCollectionStats("default"); err == nil {
+		docs = stats.Count
+	}
+	return docs, app.Node().Metrics().Snapshot().TotalMessagesSent
+}
+
+func TestApp_ShareContext_SkipsDuplicate(t *testing.T) {
+	app := startSharingApp(t, "")
+	ctx := context.Background()
+	docs0, sent0 := sharedCounts(t, app)
+
+	first, err := app.ShareContext(ctx, "auth/login.go", "Added rate limiting", nil)
+	if err != nil {
+		t.Fatalf("first share failed: %v", err)
+	}
+	if first.Duplicate || first.DocumentID == "" {
+		t.Fatalf("first share should be stored, got %+v", first)
+	}
+	docs, sent := sharedCounts(t, app)
+	if docs == docs0 || sent == sent0 {
+		t.Fatalf("first share should insert and broadcast, got %d docs %d messages", docs, sent)
+	}
+
+	second, err := app.ShareContext(ctx, "auth/login.go", "Added rate limiting", nil)
+	if err != nil {
+		t.Fatalf("second share failed: %v", err)
+	}
+	if !second.Duplicate || second.DocumentID != first.DocumentID {
+		t.Errorf("duplicate share should return the original document %s, got %+v", first.DocumentID, second)
+	}
+	if d, s := sharedCounts(t, app); d != docs || s != sent {
+		t.Errorf("duplicate share should not insert or broadcast: docs %d -> %d, messages %d -> %d", docs, d, sent, s)
+	}
+
+	// Different content for the same file is shared again
+	third, err := app.ShareContext(ctx, "auth/login.go", "Fixed token refresh", nil)
+	if err != nil {
+		t.Fatalf("third share failed: %v", err)
+	}
+	if third.Duplicate || third.DocumentID == first.DocumentID {
+		t.Errorf("changed content should be a new share, got %+v", third)
+	}
+	if d, _ := sharedCounts(t, app); d == docs {
+		t.Error("changed content should be stored")
+	}
+}
+
+func TestApp_ShareContext_DedupDisabled(t *testing.T) {
+	app := startSharingApp(t, "0")
+	ctx := context.Background()
+
+	var sent []int64
+	for i := 0; i < 2; i++ {
+		result, err := app.ShareContext(ctx, "auth/login.go", "Added rate limiting", nil)
+		if err != nil {
+			t.Fatalf("share %d failed: %v", i, err)
+		}
+		if result.Duplicate {
+			t.Errorf("share %d should not be a duplicate with dedup disabled", i)
+		}
+		_, s := sharedCounts(t, app)
+		sent = append(sent, s)
+	}
+	if sent[1] == sent[0] {
+		t.Error("repeated share should be broadcast again with dedup disabled")
+	}
+}
+
+func TestApp_ShareDerivedContext_RecordsProvenance(t *testing.T) {
+	app := startSharingApp(t, "")
+	ctx := context.Background()
+
+	parent, err := app.ShareContext(ctx, "auth/login.go", "Added rate limiting", nil)
+	if err != nil {
+		t.Fatalf("parent share failed: %v", err)
+	}
+	child, err := app.ShareDerivedContext(ctx, "auth/session.go", "Session refresh now respects the rate limiter", nil, []string{parent.DocumentID})
+	if err != nil {
+		t.Fatalf("derived share failed: %v", err)
+	}
+
+	chain, err := app.GetProvenance(child.DocumentID)
+	if err != nil {
+		t.Fatalf("GetProvenance failed: %v", err)
+	}
+	if len(chain) != 2 || chain[0].DocumentID != child.DocumentID || chain[1].DocumentID != parent.DocumentID {
+		t.Fatalf("expected chain [child, parent], got %+v", chain)
+	}
+	nodeID := app.Node().ID().String()
+	for _, entry := range chain {
+		if entry.SourceID != nodeID || entry.SharedAt.IsZero() || entry.Missing {
+			t.Errorf("entry should record the local node and share time, got %+v", entry)
+		}
+	}
+	if len(chain[0].ParentIDs) != 1 || chain[0].ParentIDs[0] != parent.DocumentID {
+		t.Errorf("child should list its parent, got %v", chain[0].ParentIDs)
+	}
+
+	if _, err := app.GetProvenance("unknown"); err == nil {
+		t.Error("unknown document should return an error")
+	}
+}
@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// shutdownNoticeTimeout bounds how long Stop waits to announce the shutdown.
+const shutdownNoticeTimeout = 2 * time.Second
+
+// NodeShutdownMessage tells peers a node is leaving, so they can drop its
+// locks and VPN peer right away instead of waiting for timeouts. Peers
+// that miss it still clean up once the locks expire.
+type NodeShutdownMessage struct {
+	Type   string `json:"type"` // "node_shutting_down"
+	NodeID string `json:"node_id"`
+	// WireGuardKey is the departing node's WireGuard public key, if any.
+	WireGuardKey string `json:"wireguard_key,omitempty"`
+}
+
+// announceShutdown broadcasts a shutdown notice to peers (must be called
+// with a.mu held, before the app context is cancelled).
+func (a *App) announceShutdown() {
+	if !a.running || a.node == nil || a.config.DisableShutdownNotice {
+		return
+	}
+
+	msg := NodeShutdownMessage{Type: "node_shutting_down", NodeID: a.node.ID().String()}
+	if a.wgManager != nil {
+		if kp := a.wgManager.GetKeyPair(); kp != nil {
+			msg.WireGuardKey = kp.PublicKey
+		}
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownNoticeTimeout)
+	defer cancel()
+	if err := a.publishSigned(ctx, lockTopicFor(a.topics(), data), data); err != nil {
+		a.logger.Warn("failed to announce shutdown", "error", err)
+	}
+}
+
+// handleNodeShutdown drops the state a departing peer leaves behind: its
+// locks, its agent registration and its WireGuard peer.
+func (a *App) handleNodeShutdown(msg *NodeShutdownMessage) {
+	log := a.logger.Component("lock-handler")
+
+	dropped := a.lockService.HandleRemoteNodeShutdown(msg.NodeID)
+	a.lockService.UnregisterObserver(msg.NodeID)
+	log.Info("peer shutting down",
+		"node_id", msg.NodeID,
+		"locks_dropped", len(dropped))
+
+	if a.agentRegistry != nil {
+		if ag, ok := a.agentRegistry.GetByPeer(msg.NodeID); ok {
+			_ = a.agentRegistry.Unregister(ag.Info.ID)
+		}
+	}
+
+	if a.wgManager != nil && msg.WireGuardKey != "" {
+		if err := a.wgManager.RemovePeer(msg.WireGuardKey); err != nil {
+			log.Debug("wireguard peer not removed", "node_id", msg.NodeID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"agent-collab/src/domain/agent"
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/pkg/logging"
+)
+
+func TestApp_NodeShutdownDropsPeerLocks(t *testing.T) {
+	ctx := context.Background()
+	locks := lock.NewLockService(ctx, "node-a", "A")
+	defer locks.Close()
+	registry := agent.NewRegistry(ctx)
+	defer registry.Close()
+
+	a := &App{
+		logger:        logging.New(io.Discard, "error"),
+		lockService:   locks,
+		agentRegistry: registry,
+		procMetrics:   NewProcessingMetrics(),
+	}
+
+	// node-b holds two locks and node-c one
+	for i, holder := range []string{"node-b", "node-b", "node-c"} {
+		target := &lock.SemanticTarget{Type: lock.TargetFile, FilePath: "/test/shutdown.go", StartLine: i*10 + 1, EndLine: i*10 + 5}
+		l, err := lock.NewSemanticLockSafe(target, holder, holder, "editing")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := locks.HandleRemoteLockAcquired(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := registry.Register(&agent.ConnectedAgent{Info: agent.AgentInfo{ID: "agent-b", Name: "bob"}, PeerID: "node-b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(NodeShutdownMessage{Type: "node_shutting_down", NodeID: "node-b"})
+	a.handleSingleLockMessage(data, "node-b")
+
+	if held := locks.ListLocksByHolder("node-b"); len(held) != 0 {
+		t.Errorf("departed node's locks should be dropped, %d left", len(held))
+	}
+	if held := locks.ListLocksByHolder("node-c"); len(held) != 1 {
+		t.Errorf("other nodes' locks should be kept, got %d", len(held))
+	}
+	if _, ok := registry.GetByPeer("node-b"); ok {
+		t.Error("departed node's agent should be unregistered")
+	}
+}
@@ -0,0 +1,265 @@
+// Package simcluster runs multi-node clusters entirely in memory.
+//
+// Nodes are wired over libp2p's mock network, so tests can exercise joins,
+// lock negotiation, conflicts and partitions without real sockets or root.
+// Link latency is applied by the mock network. Message loss and partitions
+// are applied when a node receives a message, with a seeded random source
+// so runs repeat.
+package simcluster
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/network/libp2p"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+)
+
+// DefaultProject is the project name simulated nodes share.
+const DefaultProject = "sim"
+
+// Options configures a simulated cluster.
+type Options struct {
+	// Nodes is the number of nodes to create.
+	Nodes int
+	// Project scopes the P2P topics (default DefaultProject).
+	Project string
+	// Latency is the default one-way latency of every link.
+	Latency time.Duration
+	// Loss is the default probability (0-1) that a message is dropped.
+	Loss float64
+	// Seed makes message loss repeatable.
+	Seed int64
+	// PresenceTTL is how long active edits stay visible (default
+	// presence.DefaultTTL).
+	PresenceTTL time.Duration
+}
+
+// LinkOptions configures a single link between two nodes.
+type LinkOptions struct {
+	Latency time.Duration
+	Loss    float64
+}
+
+// Cluster is a set of nodes connected over an in-memory network.
+type Cluster struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mn          mocknet.Mocknet
+	topics      libp2p.TopicSet
+	nodes       []*Node
+	presenceTTL time.Duration
+
+	mu          sync.Mutex
+	rng         *rand.Rand
+	defaultLoss float64
+	loss        map[linkKey]float64
+	partitioned map[linkKey]struct{}
+}
+
+// linkKey identifies an undirected link.
+type linkKey struct{ a, b peer.ID }
+
+func newLinkKey(a, b peer.ID) linkKey {
+	if b < a {
+		a, b = b, a
+	}
+	return linkKey{a, b}
+}
+
+// New creates a cluster of opts.Nodes nodes. Nodes are not connected; call
+// Join or JoinAll to form the cluster.
+func New(ctx context.Context, opts Options) (*Cluster, error) {
+	if opts.Nodes <= 0 {
+		return nil, fmt.Errorf("node count must be positive: %d", opts.Nodes)
+	}
+	if opts.Project == "" {
+		opts.Project = DefaultProject
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c := &Cluster{
+		ctx:         ctx,
+		cancel:      cancel,
+		mn:          mocknet.New(),
+		topics:      libp2p.NewTopicSet(libp2p.TopicScopeProject, opts.Project),
+		presenceTTL: opts.PresenceTTL,
+		rng:         rand.New(rand.NewSource(opts.Seed)),
+		defaultLoss: opts.Loss,
+		loss:        make(map[linkKey]float64),
+		partitioned: make(map[linkKey]struct{}),
+	}
+	c.mn.SetLinkDefaults(mocknet.LinkOptions{Latency: opts.Latency})
+
+	for i := 0; i < opts.Nodes; i++ {
+		node, err := c.addNode(i)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.nodes = append(c.nodes, node)
+	}
+
+	return c, nil
+}
+
+// Nodes returns all nodes in creation order.
+func (c *Cluster) Nodes() []*Node {
+	return c.nodes
+}
+
+// Node returns the i-th node.
+func (c *Cluster) Node(i int) *Node {
+	return c.nodes[i]
+}
+
+// Topics returns the topic names the nodes use.
+func (c *Cluster) Topics() libp2p.TopicSet {
+	return c.topics
+}
+
+// Join links and connects node i to node j, the way a node joins through a
+// bootstrap peer.
+func (c *Cluster) Join(i, j int) error {
+	a, b := c.nodes[i].ID(), c.nodes[j].ID()
+
+	if len(c.mn.LinksBetweenPeers(a, b)) == 0 {
+		if _, err := c.mn.LinkPeers(a, b); err != nil {
+			return fmt.Errorf("link %d-%d: %w", i, j, err)
+		}
+	}
+	if _, err := c.mn.ConnectPeers(a, b); err != nil {
+		return fmt.Errorf("connect %d-%d: %w", i, j, err)
+	}
+	return nil
+}
+
+// JoinAll connects every pair of nodes.
+func (c *Cluster) JoinAll() error {
+	for i := range c.nodes {
+		for j := i + 1; j < len(c.nodes); j++ {
+			if err := c.Join(i, j); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SetLink changes latency and loss of the link between nodes i and j.
+// Latency applies to links that exist now.
+func (c *Cluster) SetLink(i, j int, opts LinkOptions) {
+	a, b := c.nodes[i].ID(), c.nodes[j].ID()
+	for _, link := range c.mn.LinksBetweenPeers(a, b) {
+		link.SetOptions(mocknet.LinkOptions{Latency: opts.Latency})
+	}
+
+	c.mu.Lock()
+	c.loss[newLinkKey(a, b)] = opts.Loss
+	c.mu.Unlock()
+}
+
+// Partition splits the cluster: every message between nodes in different
+// groups is dropped until Heal. Connections stay up, because tearing down
+// mock connections can leave pubsub with stale streams that never recover;
+// dropping on receipt gives the same effect deterministically. Nodes not
+// listed in any group keep talking to everyone and can bridge the groups.
+func (c *Cluster) Partition(groups ...[]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for gi, group := range groups {
+		for _, other := range groups[gi+1:] {
+			for _, i := range group {
+				for _, j := range other {
+					c.partitioned[newLinkKey(c.nodes[i].ID(), c.nodes[j].ID())] = struct{}{}
+				}
+			}
+		}
+	}
+}
+
+// Heal ends every partition.
+func (c *Cluster) Heal() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.partitioned = make(map[linkKey]struct{})
+}
+
+// Connectivity returns the peers node i can currently reach, for feeding a
+// lock.PartitionDetector. Peers on the other side of a partition count as
+// unreachable.
+func (c *Cluster) Connectivity(i int) lock.Connectivity {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	self := c.nodes[i].ID()
+	var conn lock.Connectivity
+	for _, p := range c.nodes[i].net.ConnectedPeers() {
+		if _, cut := c.partitioned[newLinkKey(self, p)]; !cut {
+			conn.Peers = append(conn.Peers, p.String())
+		}
+	}
+	return conn
+}
+
+// drop reports whether a message forwarded from one node to another is lost.
+func (c *Cluster) drop(from, to peer.ID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := newLinkKey(from, to)
+	if _, cut := c.partitioned[key]; cut {
+		return true
+	}
+	loss, ok := c.loss[key]
+	if !ok {
+		loss = c.defaultLoss
+	}
+	return loss > 0 && c.rng.Float64() < loss
+}
+
+// WaitFor polls cond until it holds or the timeout passes.
+func (c *Cluster) WaitFor(timeout time.Duration, cond func() bool) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if cond() {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("condition not met within %s", timeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// WaitForMesh waits until every node sees at least minPeers peers on each
+// lock topic, so published lock messages reach them.
+func (c *Cluster) WaitForMesh(minPeers int, timeout time.Duration) error {
+	return c.WaitFor(timeout, func() bool {
+		for _, n := range c.nodes {
+			for _, topic := range c.topics.LockTopics() {
+				if n.topicPeers(topic) < minPeers {
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+// Close stops every node and the mock network.
+func (c *Cluster) Close() error {
+	c.cancel()
+	for _, n := range c.nodes {
+		n.close()
+	}
+	return c.mn.Close()
+}
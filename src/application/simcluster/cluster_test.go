@@ -0,0 +1,247 @@
+package simcluster_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"agent-collab/src/application/simcluster"
+	"agent-collab/src/domain/lock"
+)
+
+func newCluster(t *testing.T, opts simcluster.Options) *simcluster.Cluster {
+	t.Helper()
+
+	cluster, err := simcluster.New(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("failed to create cluster: %v", err)
+	}
+	t.Cleanup(func() { cluster.Close() })
+
+	if err := cluster.JoinAll(); err != nil {
+		t.Fatalf("failed to join nodes: %v", err)
+	}
+	if err := cluster.WaitForMesh(opts.Nodes-1, 5*time.Second); err != nil {
+		t.Fatalf("mesh did not form: %v", err)
+	}
+	return cluster
+}
+
+// seenByAll reports whether every node knows the lock.
+func seenByAll(cluster *simcluster.Cluster, lockID string) func() bool {
+	return func() bool {
+		for _, n := range cluster.Nodes() {
+			if !n.HasLock(lockID) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func TestCluster_FiveNodeConflictResolution(t *testing.T) {
+	ctx := context.Background()
+	cluster := newCluster(t, simcluster.Options{Nodes: 5, Latency: 5 * time.Millisecond, Seed: 1})
+
+	// Node 0 takes the lock and every node learns about it
+	first, err := cluster.Node(0).AcquireLock(ctx, "main.go", 1, 20, "refactor")
+	if err != nil || !first.Success {
+		t.Fatalf("node 0 should acquire the lock: %v", err)
+	}
+	if err := cluster.WaitFor(3*time.Second, seenByAll(cluster, first.Lock.ID)); err != nil {
+		t.Fatalf("lock did not reach every node: %v", err)
+	}
+
+	// Node 3 asks for an overlapping range and is refused
+	if result, err := cluster.Node(3).AcquireLock(ctx, "main.go", 10, 30, "fix bug"); err == nil && result.Success {
+		t.Fatal("node 3 should hit a conflict")
+	}
+
+	// Node 0 releases, and node 3 can then take the range
+	if err := cluster.Node(0).Locks().ReleaseLock(ctx, first.Lock.ID); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if err := cluster.WaitFor(3*time.Second, func() bool { return !cluster.Node(3).HasLock(first.Lock.ID) }); err != nil {
+		t.Fatalf("release did not reach node 3: %v", err)
+	}
+
+	second, err := cluster.Node(3).AcquireLock(ctx, "main.go", 10, 30, "fix bug")
+	if err != nil || !second.Success {
+		t.Fatalf("node 3 should acquire after release: %v", err)
+	}
+	if err := cluster.WaitFor(3*time.Second, seenByAll(cluster, second.Lock.ID)); err != nil {
+		t.Fatalf("second lock did not reach every node: %v", err)
+	}
+}
+
+func TestCluster_Partition(t *testing.T) {
+	ctx := context.Background()
+	cluster := newCluster(t, simcluster.Options{Nodes: 5, Seed: 1})
+
+	cluster.Partition([]int{0, 1}, []int{2, 3, 4})
+
+	// A lock taken in the minority side stays there
+	result, err := cluster.Node(0).AcquireLock(ctx, "api.go", 1, 5, "edit")
+	if err != nil || !result.Success {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	if err := cluster.WaitFor(3*time.Second, func() bool { return cluster.Node(1).HasLock(result.Lock.ID) }); err != nil {
+		t.Fatalf("lock did not reach node 1: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	for _, i := range []int{2, 3, 4} {
+		if cluster.Node(i).HasLock(result.Lock.ID) {
+			t.Errorf("node %d should not see a lock across the partition", i)
+		}
+	}
+
+	// The majority side can take the same range while partitioned
+	other, err := cluster.Node(2).AcquireLock(ctx, "api.go", 1, 5, "edit")
+	if err != nil || !other.Success {
+		t.Fatalf("node 2 should acquire inside its partition: %v", err)
+	}
+
+	// After healing, new messages cross again
+	cluster.Heal()
+	late, err := cluster.Node(4).AcquireLock(ctx, "late.go", 1, 5, "edit")
+	if err != nil || !late.Success {
+		t.Fatalf("acquire after heal failed: %v", err)
+	}
+	if err := cluster.WaitFor(3*time.Second, seenByAll(cluster, late.Lock.ID)); err != nil {
+		t.Fatalf("lock after heal did not reach every node: %v", err)
+	}
+}
+
+func TestCluster_LinkLoss(t *testing.T) {
+	ctx := context.Background()
+	cluster := newCluster(t, simcluster.Options{Nodes: 3, Seed: 1})
+
+	// Node 2 loses everything it receives
+	cluster.SetLink(0, 2, simcluster.LinkOptions{Loss: 1})
+	cluster.SetLink(1, 2, simcluster.LinkOptions{Loss: 1})
+
+	result, err := cluster.Node(0).AcquireLock(ctx, "lossy.go", 1, 5, "edit")
+	if err != nil || !result.Success {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	if err := cluster.WaitFor(3*time.Second, func() bool { return cluster.Node(1).HasLock(result.Lock.ID) }); err != nil {
+		t.Fatalf("lock did not reach node 1: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if cluster.Node(2).HasLock(result.Lock.ID) {
+		t.Error("node 2 should have lost the lock message")
+	}
+}
+
+func TestCluster_ActiveEditsVisibleToPeersUntilExpiry(t *testing.T) {
+	cluster := newCluster(t, simcluster.Options{Nodes: 3, Latency: 5 * time.Millisecond, Seed: 1, PresenceTTL: 500 * time.Millisecond})
+
+	if _, err := cluster.Node(0).Presence().Report("main.go", 10, 20); err != nil {
+		t.Fatalf("node 0 should report its edit: %v", err)
+	}
+
+	// Peers see the edit while it is active
+	seen := func(i int) bool { return len(cluster.Node(i).Presence().ActiveIn("main.go", 15, 15)) == 1 }
+	if err := cluster.WaitFor(3*time.Second, func() bool { return seen(1) && seen(2) }); err != nil {
+		t.Fatalf("active edit did not reach peers: %v", err)
+	}
+	if edits := cluster.Node(2).Presence().ActiveIn("main.go", 40, 50); len(edits) != 0 {
+		t.Errorf("non-overlapping region should be clear, got %+v", edits)
+	}
+
+	// Without a refresh the edit disappears everywhere
+	if err := cluster.WaitFor(3*time.Second, func() bool {
+		for _, n := range cluster.Nodes() {
+			if len(n.Presence().Active()) != 0 {
+				return false
+			}
+		}
+		return true
+	}); err != nil {
+		t.Fatalf("active edit did not expire: %v", err)
+	}
+}
+
+func TestNew_RejectsEmptyCluster(t *testing.T) {
+	if _, err := simcluster.New(context.Background(), simcluster.Options{}); err == nil {
+		t.Error("expected error for zero nodes")
+	}
+}
+
+func TestCluster_PartitionReconciliation(t *testing.T) {
+	ctx := context.Background()
+	cluster := newCluster(t, simcluster.Options{Nodes: 5, Seed: 1})
+
+	detectors := make([]*lock.PartitionDetector, 5)
+	observe := func() []*lock.PartitionEvent {
+		events := make([]*lock.PartitionEvent, 5)
+		for i, d := range detectors {
+			events[i] = d.Observe(cluster.Connectivity(i), time.Now())
+		}
+		return events
+	}
+	for i := range detectors {
+		detectors[i] = lock.NewPartitionDetector(lock.DefaultPartitionConfig())
+	}
+	observe()
+
+	// Only the minority side loses quorum
+	cluster.Partition([]int{0, 1}, []int{2, 3, 4})
+	events := observe()
+	for i, ev := range events {
+		partitioned := ev != nil && ev.State == lock.PartitionPartitioned
+		if partitioned != (i < 2) {
+			t.Errorf("node %d: unexpected partition event %+v", i, ev)
+		}
+	}
+
+	// Both sides lock the same region
+	minority, err := cluster.Node(0).AcquireLock(ctx, "shared.go", 1, 20, "refactor")
+	if err != nil || !minority.Success {
+		t.Fatalf("minority acquire failed: %v", err)
+	}
+	majority, err := cluster.Node(3).AcquireLock(ctx, "shared.go", 5, 15, "fix bug")
+	if err != nil || !majority.Success {
+		t.Fatalf("majority acquire failed: %v", err)
+	}
+	if err := cluster.WaitFor(3*time.Second, func() bool {
+		return cluster.Node(1).HasLock(minority.Lock.ID) && cluster.Node(4).HasLock(majority.Lock.ID)
+	}); err != nil {
+		t.Fatalf("locks did not spread inside their partitions: %v", err)
+	}
+
+	// On heal the minority detects it and starts reconciliation
+	cluster.Heal()
+	for i, ev := range observe() {
+		if ev == nil {
+			continue
+		}
+		if ev.State != lock.PartitionHealthy {
+			t.Fatalf("node %d: expected heal, got %+v", i, ev)
+		}
+		if err := cluster.Node(i).Locks().RequestLockState(ev.Since); err != nil {
+			t.Fatalf("node %d: request lock state: %v", i, err)
+		}
+	}
+
+	// Every node converges on the lock with the higher fencing token
+	winner, loser := majority.Lock, minority.Lock
+	if loser.FencingToken > winner.FencingToken {
+		winner, loser = loser, winner
+	}
+	if err := cluster.WaitFor(5*time.Second, func() bool {
+		for _, n := range cluster.Nodes() {
+			if !n.HasLock(winner.ID) || n.HasLock(loser.ID) {
+				return false
+			}
+		}
+		return true
+	}); err != nil {
+		t.Fatalf("cluster did not converge on a single holder: %v", err)
+	}
+	for i, n := range cluster.Nodes() {
+		if locks := n.Locks().ListLocks(); len(locks) != 1 {
+			t.Errorf("node %d: expected a single lock, got %d", i, len(locks))
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package simcluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"agent-collab/src/application"
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/domain/presence"
+	"agent-collab/src/infrastructure/network/libp2p"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// Node is a simulated cluster member: a P2P node with its own lock service
+// and presence tracker, wired the same way the application wires them.
+type Node struct {
+	Index int
+	Name  string
+
+	net      *libp2p.Node
+	locks    *lock.LockService
+	presence *presence.Tracker
+	cluster  *Cluster
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (c *Cluster) addNode(index int) (*Node, error) {
+	h, err := c.mn.GenPeer()
+	if err != nil {
+		return nil, fmt.Errorf("create peer %d: %w", index, err)
+	}
+
+	cfg := libp2p.DefaultConfig()
+	cfg.ListenAddrs = nil
+	netNode, err := libp2p.NewNodeWithHost(c.ctx, h, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create node %d: %w", index, err)
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	n := &Node{
+		Index:   index,
+		Name:    fmt.Sprintf("sim-%d", index),
+		net:     netNode,
+		cluster: c,
+		cancel:  cancel,
+	}
+	n.locks = lock.NewLockService(ctx, netNode.ID().String(), n.Name)
+	n.locks.SetBroadcastFn(n.broadcastLock)
+	n.presence = presence.NewTracker(netNode.ID().String(), n.Name, c.presenceTTL)
+	n.presence.SetBroadcastFn(n.broadcastPresence)
+
+	if err := netNode.SubscribeTopics(ctx, c.topics); err != nil {
+		n.close()
+		return nil, fmt.Errorf("subscribe node %d: %w", index, err)
+	}
+	for _, topic := range c.topics.LockTopics() {
+		n.wg.Add(1)
+		go n.receive(ctx, topic, n.handleLockMessage)
+	}
+	n.wg.Add(1)
+	go n.receive(ctx, c.topics.Presence(), n.handlePresenceMessage)
+
+	return n, nil
+}
+
+// ID returns the node's peer ID.
+func (n *Node) ID() peer.ID {
+	return n.net.ID()
+}
+
+// Net returns the underlying P2P node.
+func (n *Node) Net() *libp2p.Node {
+	return n.net
+}
+
+// Locks returns the node's lock service.
+func (n *Node) Locks() *lock.LockService {
+	return n.locks
+}
+
+// Presence returns the node's active edits tracker.
+func (n *Node) Presence() *presence.Tracker {
+	return n.presence
+}
+
+// AcquireLock acquires a lock on lines of a file.
+func (n *Node) AcquireLock(ctx context.Context, filePath string, startLine, endLine int, intention string) (*lock.LockResult, error) {
+	return n.locks.AcquireLock(ctx, &lock.AcquireLockRequest{
+		TargetType: lock.TargetFile,
+		FilePath:   filePath,
+		StartLine:  startLine,
+		EndLine:    endLine,
+		Intention:  intention,
+	})
+}
+
+// HasLock reports whether the node knows about the lock.
+func (n *Node) HasLock(lockID string) bool {
+	_, err := n.locks.GetLock(lockID)
+	return err == nil
+}
+
+// broadcastLock publishes a lock message on the matching lock topic.
+func (n *Node) broadcastLock(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	var base application.LockMessageBase
+	_ = json.Unmarshal(data, &base)
+
+	topic := n.cluster.topics.LockAcquire()
+	switch base.Type {
+	case "lock_intent":
+		topic = n.cluster.topics.LockIntent()
+	case "lock_released":
+		topic = n.cluster.topics.LockRelease()
+	}
+	return n.net.Publish(n.cluster.ctx, topic, data)
+}
+
+// broadcastPresence publishes a presence message.
+func (n *Node) broadcastPresence(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return n.net.Publish(n.cluster.ctx, n.cluster.topics.Presence(), data)
+}
+
+// receive applies messages from peers on a topic, dropping those lost on
+// the link they arrived on.
+func (n *Node) receive(ctx context.Context, topic string, handle func([]byte)) {
+	defer n.wg.Done()
+
+	sub := n.net.GetSubscription(topic)
+	if sub == nil {
+		return
+	}
+
+	for {
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			return
+		}
+		if msg.ReceivedFrom == n.ID() {
+			continue
+		}
+		if n.cluster.drop(msg.ReceivedFrom, n.ID()) {
+			continue
+		}
+
+		data, err := libp2p.DecompressMessage(msg.Data)
+		if err != nil {
+			data = msg.Data
+		}
+		handle(data)
+	}
+}
+
+// handleLockMessage mirrors the application's lock message handling.
+func (n *Node) handleLockMessage(data []byte) {
+	var base application.LockMessageBase
+	if err := json.Unmarshal(data, &base); err != nil {
+		return
+	}
+
+	switch base.Type {
+	case "lock_intent":
+		var msg application.IntentMessageWrapper
+		if json.Unmarshal(data, &msg) == nil && msg.Intent != nil {
+			_ = n.locks.HandleRemoteLockIntent(msg.Intent)
+		}
+	case "lock_acquired":
+		var msg application.AcquireMessageWrapper
+		if json.Unmarshal(data, &msg) == nil && msg.Lock != nil {
+			_ = n.locks.HandleRemoteLockAcquired(msg.Lock)
+		}
+	case "lock_released":
+		var msg application.ReleaseMessageWrapper
+		if json.Unmarshal(data, &msg) != nil {
+			return
+		}
+		if msg.Force != nil {
+			_ = n.locks.HandleRemoteForceRelease(msg.Force)
+		} else {
+			_ = n.locks.HandleRemoteLockReleased(msg.LockID)
+		}
+	case "lock_renewed":
+		var msg lock.RenewMessage
+		if json.Unmarshal(data, &msg) == nil {
+			n.locks.HandleRemoteLockRenewed(&msg)
+		}
+	case "lock_state_request":
+		var msg lock.LockStateRequest
+		if json.Unmarshal(data, &msg) == nil {
+			_, _ = n.locks.HandleLockStateRequest(&msg)
+		}
+	case "lock_state_response":
+		var msg lock.LockStateResponse
+		if json.Unmarshal(data, &msg) == nil {
+			n.locks.HandleLockStateResponse(&msg)
+		}
+	}
+}
+
+// handlePresenceMessage mirrors the application's presence handling.
+func (n *Node) handlePresenceMessage(data []byte) {
+	var msg presence.Message
+	if json.Unmarshal(data, &msg) == nil {
+		n.presence.HandleRemote(&msg)
+	}
+}
+
+// topicPeers returns how many peers the node sees on a topic.
+func (n *Node) topicPeers(topic string) int {
+	t, err := n.net.JoinTopic(topic)
+	if err != nil {
+		return 0
+	}
+	return len(t.ListPeers())
+}
+
+func (n *Node) close() {
+	n.cancel()
+	n.wg.Wait()
+	n.locks.Close()
+	n.net.Close()
+}
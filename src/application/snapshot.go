@@ -0,0 +1,321 @@
+package application
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"agent-collab/src/domain/ctxsync"
+	"agent-collab/src/domain/interest"
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// SnapshotVersion is the archive format written by Snapshot. Restore
+// rejects archives from a newer version.
+const SnapshotVersion = 1
+
+// snapshotDir is where Snapshot writes archives, under the data directory.
+const snapshotDir = "snapshots"
+
+// ClusterSnapshot is the cluster state as seen by one node.
+type ClusterSnapshot struct {
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	NodeID      string    `json:"node_id,omitempty"`
+	ProjectName string    `json:"project_name,omitempty"`
+
+	Locks        []*lock.SemanticLock       `json:"locks,omitempty"`
+	Negotiations []*lock.NegotiationSession `json:"negotiations,omitempty"`
+
+	// Checkpoint and Deltas are the sync log: the compacted state and
+	// every delta since.
+	Checkpoint *ctxsync.Checkpoint `json:"checkpoint,omitempty"`
+	Deltas     []*ctxsync.Delta    `json:"deltas,omitempty"`
+
+	Interests []*interest.Interest        `json:"interests,omitempty"`
+	Vectors   []*VectorCollectionSnapshot `json:"vectors,omitempty"`
+}
+
+// VectorCollectionSnapshot holds the documents of one vector collection.
+type VectorCollectionSnapshot struct {
+	Name      string             `json:"name"`
+	Dimension int                `json:"dimension"`
+	Documents []*vector.Document `json:"documents"`
+}
+
+// SnapshotSummary describes a snapshot archive. For Restore the counts are
+// what the archive added to this node.
+type SnapshotSummary struct {
+	Path         string    `json:"path"`
+	Version      int       `json:"version"`
+	CreatedAt    time.Time `json:"created_at"`
+	Locks        int       `json:"locks"`
+	Negotiations int       `json:"negotiations"`
+	Deltas       int       `json:"deltas"`
+	Interests    int       `json:"interests"`
+	Documents    int       `json:"documents"`
+}
+
+// Snapshot writes the locks, sync log, interest registrations and vector
+// store contents to a gzipped JSON archive in the data directory's
+// snapshots folder.
+func (a *App) Snapshot() (*SnapshotSummary, error) {
+	if a.config.DataDir == "" {
+		return nil, fmt.Errorf("no data directory configured")
+	}
+
+	snap, err := a.captureSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(a.config.DataDir, snapshotDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot dir: %w", err)
+	}
+	path := filepath.Join(dir, "snapshot-"+snap.CreatedAt.UTC().Format("20060102-150405.000")+".json.gz")
+	if err := writeSnapshot(path, snap); err != nil {
+		return nil, err
+	}
+
+	summary := &SnapshotSummary{
+		Path:         path,
+		Version:      snap.Version,
+		CreatedAt:    snap.CreatedAt,
+		Locks:        len(snap.Locks),
+		Negotiations: len(snap.Negotiations),
+		Deltas:       len(snap.Deltas),
+		Interests:    len(snap.Interests),
+	}
+	for _, coll := range snap.Vectors {
+		summary.Documents += len(coll.Documents)
+	}
+	return summary, nil
+}
+
+// captureSnapshot collects the state of every running component.
+func (a *App) captureSnapshot() (*ClusterSnapshot, error) {
+	snap := &ClusterSnapshot{
+		Version:     SnapshotVersion,
+		CreatedAt:   time.Now(),
+		ProjectName: a.config.ProjectName,
+	}
+	if a.node != nil {
+		snap.NodeID = a.node.ID().String()
+	}
+
+	if a.lockService != nil {
+		snap.Locks = a.lockService.ListLocks()
+		snap.Negotiations = a.lockService.ListActiveNegotiations()
+	}
+	if a.syncManager != nil {
+		exported := a.syncManager.Export()
+		snap.Checkpoint = exported.Checkpoint
+		snap.Deltas = exported.Deltas
+	}
+	if a.interestMgr != nil {
+		for _, i := range a.interestMgr.Snapshot() {
+			if !i.IsExpired() {
+				snap.Interests = append(snap.Interests, i)
+			}
+		}
+	}
+
+	if a.vectorStore != nil {
+		lister, ok := a.vectorStore.(vector.DocumentLister)
+		if !ok {
+			return nil, fmt.Errorf("vector store cannot list documents")
+		}
+		names, err := a.vectorStore.ListCollections()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list collections: %w", err)
+		}
+		for _, name := range names {
+			stats, err := a.vectorStore.GetCollectionStats(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read collection %s: %w", name, err)
+			}
+			docs, err := lister.ListDocuments(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read collection %s: %w", name, err)
+			}
+			snap.Vectors = append(snap.Vectors, &VectorCollectionSnapshot{
+				Name:      name,
+				Dimension: stats.Dimension,
+				Documents: docs,
+			})
+		}
+	}
+	return snap, nil
+}
+
+// Restore merges a snapshot archive into the running node. It only adds
+// state: live locks, interests and documents win over the archive, and
+// expired locks and interests are dropped.
+func (a *App) Restore(path string) (*SnapshotSummary, error) {
+	snap, err := ReadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &SnapshotSummary{
+		Path:      path,
+		Version:   snap.Version,
+		CreatedAt: snap.CreatedAt,
+	}
+
+	if a.lockService != nil {
+		summary.Locks = a.lockService.RestoreLocks(snap.Locks)
+		summary.Negotiations = a.lockService.RestoreNegotiations(snap.Negotiations)
+	}
+
+	if a.syncManager != nil && (snap.Checkpoint != nil || len(snap.Deltas) > 0) {
+		resp := &ctxsync.SyncResponse{
+			ResponderID: snap.NodeID,
+			Checkpoint:  snap.Checkpoint,
+			Deltas:      snap.Deltas,
+			Timestamp:   snap.CreatedAt,
+		}
+		// Never roll the log back to an older checkpoint
+		if current := a.syncManager.Export().Checkpoint; snap.Checkpoint != nil && current.Covers(snap.Checkpoint.VectorClock) {
+			resp.Checkpoint = nil
+		}
+		if err := a.syncManager.ApplySyncResponse(resp); err != nil {
+			return summary, fmt.Errorf("failed to restore sync log: %w", err)
+		}
+		summary.Deltas = len(snap.Deltas)
+	}
+
+	if a.interestMgr != nil {
+		summary.Interests = a.restoreInterests(snap.Interests)
+	}
+
+	if a.vectorStore != nil {
+		n, err := a.restoreVectors(snap.Vectors)
+		summary.Documents = n
+		if err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// restoreInterests adds the unexpired interests the manager does not know
+// yet. Interests registered on the snapshotted node are registered again
+// so they are announced to peers.
+func (a *App) restoreInterests(interests []*interest.Interest) int {
+	var remote []*interest.Interest
+	restored := 0
+	for _, i := range interests {
+		if i == nil || i.IsExpired() {
+			continue
+		}
+		if _, err := a.interestMgr.Get(i.ID); err == nil {
+			continue
+		}
+		if i.Remote {
+			remote = append(remote, i)
+			restored++
+			continue
+		}
+		if a.interestMgr.Register(i) == nil {
+			restored++
+		}
+	}
+	a.interestMgr.MergeRemote(remote)
+	return restored
+}
+
+// restoreVectors inserts the documents the store does not hold yet.
+func (a *App) restoreVectors(collections []*VectorCollectionSnapshot) (int, error) {
+	existing, err := a.vectorStore.ListCollections()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list collections: %w", err)
+	}
+	known := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		known[name] = true
+	}
+
+	restored := 0
+	for _, coll := range collections {
+		if !known[coll.Name] {
+			if err := a.vectorStore.CreateCollection(coll.Name, coll.Dimension); err != nil {
+				return restored, fmt.Errorf("failed to create collection %s: %w", coll.Name, err)
+			}
+		}
+
+		var missing []*vector.Document
+		for _, doc := range coll.Documents {
+			if _, err := a.vectorStore.Get(coll.Name, doc.ID); err == nil {
+				continue
+			}
+			doc.Collection = coll.Name
+			missing = append(missing, doc)
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		if err := a.vectorStore.InsertBatch(missing); err != nil {
+			return restored, fmt.Errorf("failed to restore collection %s: %w", coll.Name, err)
+		}
+		restored += len(missing)
+	}
+	return restored, nil
+}
+
+// writeSnapshot writes snap to path as gzipped JSON.
+func writeSnapshot(path string, snap *ClusterSnapshot) (err error) {
+	// #nosec G304 - path is built from the data directory and a timestamp
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	zw := gzip.NewWriter(f)
+	if err := json.NewEncoder(zw).Encode(snap); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return zw.Close()
+}
+
+// ReadSnapshot reads a snapshot archive written by Snapshot.
+func ReadSnapshot(path string) (*ClusterSnapshot, error) {
+	// #nosec G304 - path is chosen by the operator restoring a snapshot
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a snapshot archive: %w", err)
+	}
+	defer zr.Close()
+
+	var snap ClusterSnapshot
+	if err := json.NewDecoder(zr).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	switch {
+	case snap.Version == 0:
+		return nil, errors.New("snapshot has no version")
+	case snap.Version > SnapshotVersion:
+		return nil, fmt.Errorf("snapshot version %d is newer than supported version %d", snap.Version, SnapshotVersion)
+	}
+	return &snap, nil
+}
@@ -0,0 +1,122 @@
+package application_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"agent-collab/src/application"
+	"agent-collab/src/domain/interest"
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+func newSnapshotApp(t *testing.T) *application.App {
+	t.Helper()
+	app, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(context.Background(), "snapshot-test"); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	return app
+}
+
+func TestApp_SnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	source := newSnapshotApp(t)
+
+	held, err := source.LockService().AcquireLock(ctx, &lock.AcquireLockRequest{
+		TargetType: lock.TargetFile,
+		FilePath:   "auth/login.go",
+		StartLine:  10,
+		EndLine:    20,
+		Intention:  "add rate limiting",
+	})
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+	watched := interest.NewInterest("agent-a", "Agent A", []string{"auth/**"})
+	if err := source.InterestManager().Register(watched); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.VectorStore().Insert(&vector.Document{
+		ID:         "doc-1",
+		Collection: "default",
+		Content:    "func Login() error",
+		Embedding:  []float32{0.1, 0.2, 0.3},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := source.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if created.Locks != 1 || created.Interests != 1 || created.Documents != 1 {
+		t.Errorf("unexpected snapshot contents: %+v", created)
+	}
+	info, err := os.Stat(created.Path)
+	if err != nil {
+		t.Fatalf("expected the archive on disk: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the archive to be private, got %v", info.Mode().Perm())
+	}
+
+	target := newSnapshotApp(t)
+	restored, err := target.Restore(created.Path)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.Locks != 1 || restored.Interests != 1 || restored.Documents != 1 {
+		t.Errorf("unexpected restore summary: %+v", restored)
+	}
+	if _, err := target.LockService().GetLock(held.Lock.ID); err != nil {
+		t.Errorf("expected the lock after restore: %v", err)
+	}
+	if _, err := target.InterestManager().Get(watched.ID); err != nil {
+		t.Errorf("expected the interest after restore: %v", err)
+	}
+	doc, err := target.VectorStore().Get("default", "doc-1")
+	if err != nil {
+		t.Fatalf("expected the document after restore: %v", err)
+	}
+	if len(doc.Embedding) != 3 {
+		t.Errorf("expected the embedding to survive, got %v", doc.Embedding)
+	}
+
+	// Restoring again adds nothing
+	again, err := target.Restore(created.Path)
+	if err != nil {
+		t.Fatalf("second Restore: %v", err)
+	}
+	if again.Locks != 0 || again.Interests != 0 || again.Documents != 0 {
+		t.Errorf("expected a repeated restore to be a no-op, got %+v", again)
+	}
+}
+
+func TestApp_RestoreRejectsNewerVersion(t *testing.T) {
+	app := newSnapshotApp(t)
+
+	path := filepath.Join(t.TempDir(), "future.json.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(f)
+	if err := json.NewEncoder(zw).Encode(application.ClusterSnapshot{Version: application.SnapshotVersion + 1}); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	f.Close()
+
+	if _, err := app.Restore(path); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// StatusWatchInterval is how often WatchStatus samples the node status.
+const StatusWatchInterval = 2 * time.Second
+
+// WatchStatus streams status snapshots for dashboards. The current status
+// is sent right away, then a new snapshot whenever the status changes.
+// The channel is closed when ctx is done.
+func (a *App) WatchStatus(ctx context.Context) <-chan *Status {
+	return a.watchStatus(ctx, StatusWatchInterval)
+}
+
+// watchStatus is WatchStatus with a custom sampling interval.
+func (a *App) watchStatus(ctx context.Context, interval time.Duration) <-chan *Status {
+	ch := make(chan *Status, 1)
+
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last *Status
+		for {
+			if status := a.GetStatus(); last == nil || !reflect.DeepEqual(status, last) {
+				select {
+				case ch <- status:
+					last = status
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return ch
+}
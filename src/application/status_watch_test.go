@@ -0,0 +1,109 @@
+package application
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/network/libp2p"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+func receiveStatus(t *testing.T, ch <-chan *Status) *Status {
+	t.Helper()
+	select {
+	case status, ok := <-ch:
+		if !ok {
+			t.Fatal("status channel closed unexpectedly")
+		}
+		return status
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a status snapshot")
+		return nil
+	}
+}
+
+func TestApp_WatchStatusEmitsOnChange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lockService := lock.NewLockService(ctx, "node-1", "Node")
+	defer lockService.Close()
+	a := &App{config: &Config{ProjectName: "watch-test"}, lockService: lockService}
+
+	ch := a.watchStatus(ctx, 10*time.Millisecond)
+	if first := receiveStatus(t, ch); first.ProjectName != "watch-test" || first.LockCount != 0 {
+		t.Fatalf("unexpected initial status: %+v", first)
+	}
+
+	// Nothing changed, so no snapshot is sent
+	select {
+	case status := <-ch:
+		t.Fatalf("unchanged status should not be sent again, got %+v", status)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if _, err := lockService.AcquireLock(ctx, &lock.AcquireLockRequest{
+		TargetType: lock.TargetFile,
+		FilePath:   "/test/watch.go",
+		StartLine:  1,
+		EndLine:    10,
+		Intention:  "editing",
+	}); err != nil {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+	if next := receiveStatus(t, ch); next.LockCount != 1 || next.MyLockCount != 1 {
+		t.Errorf("expected snapshot with the new lock, got %+v", next)
+	}
+}
+
+func TestApp_WatchStatusClosesOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &App{config: &Config{ProjectName: "watch-test"}}
+
+	ch := a.watchStatus(ctx, 10*time.Millisecond)
+	receiveStatus(t, ch)
+	cancel()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("status channel should close after cancel")
+		}
+	}
+}
+
+func TestRegionStatuses_LocalRegionFirst(t *testing.T) {
+	clusters := []libp2p.LocalityCluster{
+		{Region: "oregon", PeerCount: 3, AverageRTT: 150 * time.Millisecond, GatewayPeer: peer.ID("gw-oregon")},
+		{Region: "seoul", PeerCount: 2, AverageRTT: 15 * time.Millisecond, GatewayPeer: peer.ID("gw-seoul")},
+		{Region: "remote", PeerCount: 1, AverageRTT: 300 * time.Millisecond},
+	}
+
+	regions := regionStatuses(clusters, "seoul")
+
+	var order []string
+	for _, r := range regions {
+		order = append(order, r.Region)
+	}
+	if !slices.Equal(order, []string{"seoul", "oregon", "remote"}) {
+		t.Fatalf("expected local region first, got %v", order)
+	}
+	if !regions[0].Local || regions[1].Local {
+		t.Errorf("only this node's region should be local: %+v", regions)
+	}
+	if regions[0].PeerCount != 2 || regions[0].AverageRTT != 15*time.Millisecond || regions[0].GatewayPeer != peer.ID("gw-seoul").String() {
+		t.Errorf("unexpected local breakdown: %+v", regions[0])
+	}
+	if regionStatuses(nil, "seoul") != nil {
+		t.Error("no locality data should leave the breakdown empty")
+	}
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"agent-collab/src/domain/ast"
+	"agent-collab/src/domain/ctxsync"
+	"agent-collab/src/domain/lock"
+)
+
+// lockTargetTypes maps the parsed symbol kinds a region lock can be
+// promoted to onto lock target types.
+var lockTargetTypes = map[ast.SymbolType]lock.TargetType{
+	ast.SymbolFunction:  lock.TargetFunction,
+	ast.SymbolMethod:    lock.TargetMethod,
+	ast.SymbolClass:     lock.TargetClass,
+	ast.SymbolStruct:    lock.TargetClass,
+	ast.SymbolInterface: lock.TargetClass,
+}
+
+// symbolResolver parses files for lock promotion, honouring the configured
+// language rules. A fresh parser is used per call so the current content is
+// always read and no cache grows with every edit.
+func symbolResolver(rules []ast.FilenameRule) (lock.SymbolResolver, error) {
+	var detector *ast.LanguageDetector
+	if len(rules) > 0 {
+		d, err := ast.NewLanguageDetector(rules)
+		if err != nil {
+			return nil, err
+		}
+		detector = d
+	}
+
+	return func(filePath string) ([]lock.SymbolRange, error) {
+		parser := ast.NewParser()
+		if detector != nil {
+			parser.SetLanguageDetector(detector)
+		}
+		result, err := parser.ParseFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		var ranges []lock.SymbolRange
+		var walk func(symbols []*ast.Symbol)
+		walk = func(symbols []*ast.Symbol) {
+			for _, sym := range symbols {
+				if targetType, ok := lockTargetTypes[sym.Type]; ok && sym.Name != "" {
+					name := sym.Name
+					if sym.Parent != "" {
+						name = sym.Parent + "." + name
+					}
+					ranges = append(ranges, lock.SymbolRange{
+						Type:      targetType,
+						Name:      name,
+						StartLine: sym.StartLine,
+						EndLine:   sym.EndLine,
+					})
+				}
+				walk(sym.Children)
+			}
+		}
+		walk(result.Symbols)
+		return ranges, nil
+	}, nil
+}
+
+// syncSymbolLocks moves this node's symbol locks in a file it just changed
+// to where their symbols now are. It sees only deltas this node broadcasts.
+func (a *App) syncSymbolLocks(delta *ctxsync.Delta) {
+	if a.config.LockSymbolPromotion <= 0 || a.lockService == nil {
+		return
+	}
+	if delta.Type != ctxsync.DeltaFileChange || delta.Payload.FilePath == "" {
+		return
+	}
+	for _, l := range a.lockService.SyncSymbolLocks(delta.Payload.FilePath) {
+		a.logger.Component("lock").Debug("symbol lock followed edit", "lock_id", l.ID, "target", l.Target.String())
+	}
+}
@@ -0,0 +1,243 @@
+package application
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"agent-collab/src/domain/ast"
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// defaultSymbolSearchLimit is how many matches SearchSymbols returns when
+// the query sets no limit.
+const defaultSymbolSearchLimit = 10
+
+// symbolSearchFanout is how many similarity hits per requested match are
+// parsed for the symbol, since most hits are about other code.
+const symbolSearchFanout = 3
+
+// SymbolQuery selects code symbols in the shared contexts.
+type SymbolQuery struct {
+	// Name is the symbol to find. It matches case-insensitively, and a bare
+	// name also matches methods qualified by their receiver
+	// ("Start" matches "Server.Start").
+	Name string `json:"name"`
+	// Type restricts matches to a symbol kind (function, method, struct, ...)
+	Type string `json:"type,omitempty"`
+	// FilePath restricts matches to a file, or to files under it when it
+	// ends with a slash
+	FilePath string `json:"file_path,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+// SymbolMatch is a symbol found in a shared context.
+type SymbolMatch struct {
+	Name      string `json:"name"`
+	Type      string `json:"type,omitempty"`
+	FilePath  string `json:"file_path"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	// AgentID and AgentName identify the agent that shared the context
+	AgentID   string `json:"agent_id,omitempty"`
+	AgentName string `json:"agent_name,omitempty"`
+	// ChangeType is how the shared change touched the symbol (added,
+	// modified, removed), when known
+	ChangeType string    `json:"change_type,omitempty"`
+	DocumentID string    `json:"document_id"`
+	SharedAt   time.Time `json:"shared_at,omitzero"`
+	// Score is 1 for symbols recorded in the shared context and the
+	// similarity of the context for symbols located by parsing its file
+	Score float32 `json:"score"`
+}
+
+// SearchSymbols finds where a symbol is defined and who changed it across
+// the shared contexts. Symbol-level contexts are matched by name; file-level
+// contexts similar to the name are parsed to locate the symbol in the file.
+func (a *App) SearchSymbols(ctx context.Context, q SymbolQuery) ([]*SymbolMatch, error) {
+	name := strings.TrimSpace(q.Name)
+	if name == "" {
+		return nil, fmt.Errorf("symbol name is required")
+	}
+	q.Name = name
+	if q.Limit <= 0 {
+		q.Limit = defaultSymbolSearchLimit
+	}
+	if a.vectorStore == nil {
+		return nil, fmt.Errorf("vector store not initialized")
+	}
+
+	var matches []*SymbolMatch
+	if lister, ok := a.vectorStore.(vector.DocumentLister); ok {
+		names, _ := a.vectorStore.ListCollections()
+		var docs []*vector.Document
+		for _, name := range names {
+			collDocs, _ := lister.ListDocuments(name)
+			docs = append(docs, collDocs...)
+		}
+		for _, doc := range docs {
+			if doc.SymbolName == "" || !q.matchesFile(doc.FilePath) {
+				continue
+			}
+			if !q.matchesSymbol(doc.SymbolName, doc.SymbolType) {
+				continue
+			}
+			match := symbolMatch(doc, 1)
+			match.Name, match.Type = doc.SymbolName, doc.SymbolType
+			match.StartLine, match.EndLine = doc.StartLine, doc.EndLine
+			matches = append(matches, match)
+		}
+	}
+
+	if a.embedService != nil {
+		located, err := a.locateSymbols(ctx, q)
+		if err != nil {
+			// Name matches still answer the query
+			a.logger.Warn("symbol similarity search failed", "symbol", name, "error", err)
+		}
+		matches = append(matches, located...)
+	}
+
+	return rankSymbolMatches(matches, q.Limit), nil
+}
+
+// locateSymbols parses the files of the contexts most similar to the
+// symbol name and returns the matching symbols defined in them.
+func (a *App) locateSymbols(ctx context.Context, q SymbolQuery) ([]*SymbolMatch, error) {
+	embedding, err := a.embedService.EmbedQuery(ctx, strings.TrimSpace(q.Type+" "+q.Name))
+	if err != nil {
+		return nil, err
+	}
+	results, err := a.vectorStore.Search(embedding, &vector.SearchOptions{
+		TopK: q.Limit * symbolSearchFanout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	parser := ast.NewParser()
+	if rules, err := a.config.LanguageRules(); err == nil && len(rules) > 0 {
+		if detector, err := ast.NewLanguageDetector(rules); err == nil {
+			parser.SetLanguageDetector(detector)
+		}
+	}
+
+	var matches []*SymbolMatch
+	for _, r := range results {
+		doc := r.Document
+		if doc.FilePath == "" || !q.matchesFile(doc.FilePath) {
+			continue
+		}
+		// The file may not exist in this checkout
+		parsed, err := parser.ParseFile(doc.FilePath)
+		if err != nil {
+			continue
+		}
+		var walk func(symbols []*ast.Symbol)
+		walk = func(symbols []*ast.Symbol) {
+			for _, sym := range symbols {
+				name := sym.Name
+				if sym.Parent != "" {
+					name = sym.Parent + "." + name
+				}
+				if sym.Name != "" && q.matchesSymbol(name, string(sym.Type)) {
+					match := symbolMatch(doc, r.Score)
+					match.Name, match.Type = name, string(sym.Type)
+					match.StartLine, match.EndLine = sym.StartLine, sym.EndLine
+					// The context's change may concern another symbol in the file
+					match.ChangeType = ""
+					matches = append(matches, match)
+				}
+				walk(sym.Children)
+			}
+		}
+		walk(parsed.Symbols)
+	}
+	return matches, nil
+}
+
+// matchesSymbol reports whether a symbol name and kind satisfy the query.
+func (q SymbolQuery) matchesSymbol(name, symbolType string) bool {
+	if q.Type != "" && !strings.EqualFold(symbolType, q.Type) {
+		return false
+	}
+	if strings.EqualFold(name, q.Name) {
+		return true
+	}
+	// "Start" finds "Server.Start"
+	_, bare, qualified := strings.Cut(name, ".")
+	return qualified && !strings.Contains(q.Name, ".") && strings.EqualFold(bare, q.Name)
+}
+
+// matchesFile reports whether a file path satisfies the query.
+func (q SymbolQuery) matchesFile(filePath string) bool {
+	switch {
+	case q.FilePath == "":
+		return true
+	case strings.HasSuffix(q.FilePath, "/"):
+		return strings.HasPrefix(filePath, q.FilePath)
+	default:
+		return filePath == q.FilePath
+	}
+}
+
+// symbolMatch fills in the file, sharing agent and change of a document.
+func symbolMatch(doc *vector.Document, score float32) *SymbolMatch {
+	match := &SymbolMatch{
+		FilePath:   doc.FilePath,
+		DocumentID: doc.ID,
+		Score:      score,
+		SharedAt:   doc.CreatedAt,
+	}
+	if p := doc.Provenance; p != nil {
+		match.AgentID, match.AgentName = p.SourceID, p.SourceName
+		if !p.SharedAt.IsZero() {
+			match.SharedAt = p.SharedAt
+		}
+	}
+	if match.AgentID == "" {
+		match.AgentID, _ = doc.Metadata["source_id"].(string)
+		match.AgentName, _ = doc.Metadata["source_name"].(string)
+	}
+	if match.AgentID == "" {
+		// Regions under an active lock
+		match.AgentID, _ = doc.Metadata["holder_id"].(string)
+		match.AgentName, _ = doc.Metadata["holder_name"].(string)
+	}
+	match.ChangeType, _ = doc.Metadata["change_type"].(string)
+	return match
+}
+
+// rankSymbolMatches orders matches best first, most recent first among
+// equals, drops repeats of the same symbol shared by the same agent and
+// caps them at limit.
+func rankSymbolMatches(matches []*SymbolMatch, limit int) []*SymbolMatch {
+	slices.SortStableFunc(matches, func(a, b *SymbolMatch) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return b.SharedAt.Compare(a.SharedAt)
+	})
+
+	type key struct {
+		file, name, agent string
+		start             int
+	}
+	seen := make(map[key]bool)
+	var ranked []*SymbolMatch
+	for _, m := range matches {
+		k := key{m.FilePath, m.Name, m.AgentID, m.StartLine}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		ranked = append(ranked, m)
+		if len(ranked) == limit {
+			break
+		}
+	}
+	return ranked
+}
@@ -0,0 +1,122 @@
+package application
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"agent-collab/src/infrastructure/embedding"
+	"agent-collab/src/infrastructure/storage/vector"
+	"agent-collab/src/pkg/logging"
+)
+
+func TestApp_SearchSymbols(t *testing.T) {
+	dir := t.TempDir()
+	store, err := vector.NewMemoryStore(dir, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	embedService := embedding.NewServiceWithProvider(embedding.NewMockProvider(&embedding.ProviderConfig{Dimension: 8}))
+	a := &App{
+		config:       &Config{},
+		logger:       logging.New(io.Discard, "error"),
+		vectorStore:  store,
+		embedService: embedService,
+	}
+	ctx := context.Background()
+
+	// A symbol-level change shared by bob
+	if err := store.Insert(&vector.Document{
+		Content:    "Symbol change: modified method Server.Start in srv/server.go from bob",
+		Embedding:  []float32{1, 0, 0, 0, 0, 0, 0, 0},
+		FilePath:   "srv/server.go",
+		StartLine:  10,
+		EndLine:    20,
+		SymbolType: "method",
+		SymbolName: "Server.Start",
+		Metadata:   map[string]any{"source_id": "node-b", "source_name": "bob", "change_type": "modified"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file-level context shared by alice, whose symbols are only known
+	// by parsing the file
+	configFile := filepath.Join(dir, "config.go")
+	source := "package app\n\n// Config holds settings.\ntype Config struct {\n\tName string\n}\n\nfunc Load() *Config {\n\treturn &Config{}\n}\n"
+	if err := os.WriteFile(configFile, []byte(source), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fileEmbedding, err := embedService.EmbedDocument(ctx, "File change: config.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.Insert(&vector.Document{
+		Content:    "File change: config.go",
+		Embedding:  fileEmbedding,
+		FilePath:   configFile,
+		Provenance: &vector.Provenance{SourceID: "node-a", SourceName: "alice", SharedAt: sharedAt},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := a.SearchSymbols(ctx, SymbolQuery{Name: "start"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected the shared method, got %+v", matches)
+	}
+	m := matches[0]
+	if m.Name != "Server.Start" || m.FilePath != "srv/server.go" || m.StartLine != 10 || m.EndLine != 20 ||
+		m.AgentName != "bob" || m.ChangeType != "modified" || m.Score != 1 {
+		t.Errorf("unexpected match %+v", m)
+	}
+
+	matches, err = a.SearchSymbols(ctx, SymbolQuery{Name: "Config", Type: "struct"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected the struct located in the shared file, got %+v", matches)
+	}
+	m = matches[0]
+	if m.FilePath != configFile || m.StartLine != 4 || m.AgentID != "node-a" || !m.SharedAt.Equal(sharedAt) {
+		t.Errorf("unexpected match %+v", m)
+	}
+
+	matches, err = a.SearchSymbols(ctx, SymbolQuery{Name: "Start", FilePath: "other/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("expected the file filter to exclude srv/server.go, got %+v", matches)
+	}
+
+	if _, err := a.SearchSymbols(ctx, SymbolQuery{Name: " "}); err == nil {
+		t.Error("expected an error for an empty symbol name")
+	}
+}
+
+func TestSymbolQuery_MatchesSymbol(t *testing.T) {
+	tests := []struct {
+		q          SymbolQuery
+		name, kind string
+		want       bool
+	}{
+		{SymbolQuery{Name: "Start"}, "Start", "function", true},
+		{SymbolQuery{Name: "start"}, "Server.Start", "method", true},
+		{SymbolQuery{Name: "Server.Start"}, "Server.Start", "method", true},
+		{SymbolQuery{Name: "Client.Start"}, "Server.Start", "method", false},
+		{SymbolQuery{Name: "Start", Type: "function"}, "Server.Start", "method", false},
+		{SymbolQuery{Name: "Sta"}, "Start", "function", false},
+	}
+	for _, tt := range tests {
+		if got := tt.q.matchesSymbol(tt.name, tt.kind); got != tt.want {
+			t.Errorf("%+v.matchesSymbol(%q, %q) = %v, want %v", tt.q, tt.name, tt.kind, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"agent-collab/src/domain/ctxsync"
+)
+
+// syncStateFile holds the compacted sync delta log so a restarted node
+// keeps its checkpoint and vector clock instead of rebuilding them from
+// peers.
+const syncStateFile = "sync_state.json"
+
+// persistSyncState writes the compacted delta log to the data directory.
+// The file is replaced atomically so a crash mid-write keeps the last one.
+func (a *App) persistSyncState(state *ctxsync.SyncResponse) error {
+	if state == nil || a.config.DataDir == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(a.config.DataDir, syncStateFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// restoreSyncState installs the delta log persisted by the last run and
+// returns how many deltas it still held.
+func (a *App) restoreSyncState() (int, error) {
+	if a.syncManager == nil || a.config.DataDir == "" {
+		return 0, nil
+	}
+
+	path := filepath.Join(a.config.DataDir, syncStateFile)
+	// #nosec G304 - path is the fixed sync state file in the data directory
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var state ctxsync.SyncResponse
+	if err := json.Unmarshal(data, &state); err != nil {
+		return 0, err
+	}
+	a.syncManager.Import(&state)
+	return len(state.Deltas), nil
+}
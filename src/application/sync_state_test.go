@@ -0,0 +1,36 @@
+package application
+
+import (
+	"reflect"
+	"testing"
+
+	"agent-collab/src/domain/ast"
+	"agent-collab/src/domain/ctxsync"
+)
+
+func TestApp_SyncStateSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	sm := ctxsync.NewSyncManager("node-a", "A")
+	clock := ctxsync.NewVectorClock()
+	clock.Increment("node-b")
+	if err := sm.ReceiveDelta(ctxsync.NewFileChangeDelta("node-b", "B", clock, "main.go", &ast.FileDiff{NewHash: "h1"})); err != nil {
+		t.Fatal(err)
+	}
+	sm.Compact()
+
+	a := &App{config: &Config{DataDir: dir}, syncManager: sm}
+	if err := a.persistSyncState(sm.Export()); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := &App{config: &Config{DataDir: dir}, syncManager: ctxsync.NewSyncManager("node-a", "A")}
+	if _, err := restarted.restoreSyncState(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := restarted.syncManager.GetState(), sm.GetState(); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored state differs:\nwant %+v\ngot  %+v", want, got)
+	}
+	if restarted.syncManager.GetStats().CompactedDeltas != 1 {
+		t.Errorf("expected the checkpoint to be restored, got %+v", restarted.syncManager.GetStats())
+	}
+}
@@ -1,5 +1,15 @@
 package application
 
+import (
+	"errors"
+	"time"
+
+	"agent-collab/src/domain/lock"
+)
+
+// ErrObserverMode is returned when an observer node attempts a participating operation.
+var ErrObserverMode = errors.New("operation not permitted in observer mode")
+
 // InitResult는 초기화 결과입니다.
 type InitResult struct {
 	ProjectName string   `json:"project_name"`
@@ -30,6 +40,8 @@ type JoinResult struct {
 type Status struct {
 	Running      bool     `json:"running"`
 	ProjectName  string   `json:"project_name"`
+	Observer     bool     `json:"observer,omitempty"`
+	Role         string   `json:"role,omitempty"`
 	NodeID       string   `json:"node_id"`
 	Addresses    []string `json:"addresses"`
 	PeerCount    int      `json:"peer_count"`
@@ -38,6 +50,9 @@ type Status struct {
 	DeltaCount   int      `json:"delta_count"`
 	WatchedFiles int      `json:"watched_files"`
 
+	// Lock negotiation health
+	Negotiations *lock.NegotiationMetrics `json:"negotiations,omitempty"`
+
 	// Token usage (Phase 3)
 	TokensToday   int64   `json:"tokens_today"`
 	TokensPerHour float64 `json:"tokens_per_hour"`
@@ -51,4 +66,18 @@ type Status struct {
 	WireGuardIP        string `json:"wireguard_ip,omitempty"`
 	WireGuardEndpoint  string `json:"wireguard_endpoint,omitempty"`
 	WireGuardPeerCount int    `json:"wireguard_peer_count,omitempty"`
+
+	// Peers broken down by locality region
+	Regions []RegionStatus `json:"regions,omitempty"`
+}
+
+// RegionStatus summarizes the connected peers in one locality region.
+type RegionStatus struct {
+	Region     string        `json:"region"`
+	PeerCount  int           `json:"peer_count"`
+	AverageRTT time.Duration `json:"average_rtt"`
+	// GatewayPeer is the lowest-RTT peer, the best route into the region.
+	GatewayPeer string `json:"gateway_peer,omitempty"`
+	// Local marks this node's own region.
+	Local bool `json:"local,omitempty"`
 }
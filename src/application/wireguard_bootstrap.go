@@ -32,8 +32,9 @@ func NewWireGuardBootstrapper(dataDir string, config *WireGuardConfig, logger *l
 // BootstrapOptions configures the WireGuard bootstrap process.
 type BootstrapOptions struct {
 	// For init mode
-	ListenPort int
-	Subnet     string
+	ListenPort    int
+	Subnet        string
+	ExpectedPeers int
 
 	// For join mode
 	CreatorPublicKey string
@@ -47,6 +48,20 @@ type BootstrapResult struct {
 	Info    *crypto.WireGuardInfo
 }
 
+// managerConfig returns the WireGuard manager configuration.
+func (b *WireGuardBootstrapper) managerConfig() *wireguard.ManagerConfig {
+	return &wireguard.ManagerConfig{
+		InterfaceName:       b.config.InterfaceName,
+		ListenPort:          b.config.ListenPort,
+		Subnet:              b.config.Subnet,
+		MTU:                 b.config.MTU,
+		PersistentKeepalive: b.config.PersistentKeepalive,
+		AutoDetectEndpoint:  true,
+		ExpectedPeers:       b.config.ExpectedPeers,
+		RetireReleasedIPs:   b.config.RetireReleasedIPs,
+	}
+}
+
 // Bootstrap initializes WireGuard and returns the manager and info.
 func (b *WireGuardBootstrapper) Bootstrap(ctx context.Context, opts *BootstrapOptions) (*BootstrapResult, error) {
 	b.config.Enabled = true
@@ -58,19 +73,13 @@ func (b *WireGuardBootstrapper) Bootstrap(ctx context.Context, opts *BootstrapOp
 	if opts.Subnet != "" {
 		b.config.Subnet = opts.Subnet
 	}
+	if opts.ExpectedPeers > 0 {
+		b.config.ExpectedPeers = opts.ExpectedPeers
+	}
 
 	// Create and initialize manager
 	mgr := wireguard.NewManager(nil)
-	mgrCfg := &wireguard.ManagerConfig{
-		InterfaceName:       b.config.InterfaceName,
-		ListenPort:          b.config.ListenPort,
-		Subnet:              b.config.Subnet,
-		MTU:                 b.config.MTU,
-		PersistentKeepalive: b.config.PersistentKeepalive,
-		AutoDetectEndpoint:  true,
-	}
-
-	if err := mgr.Initialize(ctx, mgrCfg); err != nil {
+	if err := mgr.Initialize(ctx, b.managerConfig()); err != nil {
 		return nil, fmt.Errorf("failed to initialize WireGuard manager: %w", err)
 	}
 
@@ -87,6 +96,9 @@ func (b *WireGuardBootstrapper) Bootstrap(ctx context.Context, opts *BootstrapOp
 		}
 	}
 
+	// Record the subnet actually in use after auto-sizing
+	b.config.Subnet = mgr.GetConfig().Subnet
+
 	// Start the WireGuard interface
 	if err := mgr.Start(ctx); err != nil {
 		return nil, fmt.Errorf("failed to start WireGuard: %w", err)
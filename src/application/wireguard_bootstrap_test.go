@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"agent-collab/src/infrastructure/network/wireguard"
+	"agent-collab/src/infrastructure/network/wireguard/platform"
+)
+
+func TestWireGuardBootstrapper_ReclaimsReleasedIPsByDefault(t *testing.T) {
+	// A config written before released IPs could be retired
+	data := []byte(`{"wireguard": {"enabled": true, "listen_port": 51820, "subnet": "10.100.0.0/30", "mtu": 1420, "interface_name": "wg-test"}}`)
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := wireguard.NewManager(platform.NewMockPlatform())
+	b := NewWireGuardBootstrapper(t.TempDir(), cfg.WireGuard, nil)
+	if err := mgr.Initialize(context.Background(), b.managerConfig()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A /30 has room for the local node and one peer
+	if _, err := mgr.AllocateIP("peer1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.ReleaseIP("peer1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.AllocateIP("peer2"); err != nil {
+		t.Errorf("expected the released IP to be reclaimed, got %v", err)
+	}
+
+	cfg.WireGuard.RetireReleasedIPs = true
+	mgr = wireguard.NewManager(platform.NewMockPlatform())
+	if err := mgr.Initialize(context.Background(), b.managerConfig()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.AllocateIP("peer1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.ReleaseIP("peer1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.AllocateIP("peer2"); err == nil {
+		t.Error("expected a retired IP to stay out of the free pool")
+	}
+}
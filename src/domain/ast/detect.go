@@ -0,0 +1,154 @@
+package ast
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	LangShell      Language = "shell"
+	LangDockerfile Language = "dockerfile"
+	// LangGeneric은 심볼 추출 없이 파일 전체를 하나의 심볼로 다룹니다.
+	LangGeneric Language = "generic"
+)
+
+// FilenameRule은 파일 이름 패턴을 언어에 매핑합니다.
+// Pattern은 파일 이름(경로 제외)에 대한 filepath.Match 패턴입니다.
+type FilenameRule struct {
+	Pattern  string   `json:"pattern"`
+	Language Language `json:"language"`
+}
+
+// DefaultFilenameRules는 확장자로 알 수 없는 흔한 파일의 기본 매핑입니다.
+func DefaultFilenameRules() []FilenameRule {
+	return []FilenameRule{
+		{Pattern: "Dockerfile", Language: LangDockerfile},
+		{Pattern: "Dockerfile.*", Language: LangDockerfile},
+		{Pattern: "*.dockerfile", Language: LangDockerfile},
+		{Pattern: "Containerfile", Language: LangDockerfile},
+		{Pattern: "Makefile", Language: LangGeneric},
+		{Pattern: "*.mk", Language: LangGeneric},
+		{Pattern: "*.sh", Language: LangShell},
+		{Pattern: "*.bash", Language: LangShell},
+	}
+}
+
+// ParseLanguage는 언어 이름을 검증합니다.
+func ParseLanguage(name string) (Language, error) {
+	switch lang := Language(strings.ToLower(name)); lang {
+	case LangGo, LangTypeScript, LangJavaScript, LangPython, LangRust, LangJava,
+		LangShell, LangDockerfile, LangGeneric:
+		return lang, nil
+	default:
+		return LangUnknown, fmt.Errorf("unknown language: %q", name)
+	}
+}
+
+// LanguageDetector는 파일 이름 규칙, 확장자, shebang 순으로 언어를 감지합니다.
+type LanguageDetector struct {
+	rules []FilenameRule
+}
+
+// NewLanguageDetector는 기본 규칙 앞에 rules를 더한 감지기를 생성합니다.
+// 먼저 일치하는 규칙이 우선합니다.
+func NewLanguageDetector(rules []FilenameRule) (*LanguageDetector, error) {
+	all := make([]FilenameRule, 0, len(rules)+len(DefaultFilenameRules()))
+	for _, rule := range rules {
+		if _, err := filepath.Match(rule.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid filename pattern %q: %w", rule.Pattern, err)
+		}
+		lang, err := ParseLanguage(string(rule.Language))
+		if err != nil {
+			return nil, fmt.Errorf("filename pattern %q: %w", rule.Pattern, err)
+		}
+		all = append(all, FilenameRule{Pattern: rule.Pattern, Language: lang})
+	}
+	all = append(all, DefaultFilenameRules()...)
+	return &LanguageDetector{rules: all}, nil
+}
+
+// defaultDetector는 기본 규칙만 사용하는 감지기입니다.
+func defaultDetector() *LanguageDetector {
+	return &LanguageDetector{rules: DefaultFilenameRules()}
+}
+
+// Detect는 파일 경로와 내용의 첫 줄로 언어를 감지합니다.
+// 설정된 파일 이름 규칙이 확장자보다 우선하며, 확장자가 없는
+// 스크립트는 shebang으로 감지합니다.
+func (d *LanguageDetector) Detect(filePath string, firstLine []byte) Language {
+	name := filepath.Base(filePath)
+	for _, rule := range d.rules {
+		if ok, _ := filepath.Match(rule.Pattern, name); ok {
+			return rule.Language
+		}
+	}
+	if lang := DetectLanguage(filePath); lang != LangUnknown {
+		return lang
+	}
+	if filepath.Ext(name) == "" {
+		return detectShebang(firstLine)
+	}
+	return LangUnknown
+}
+
+// DetectFile은 필요할 때만 파일의 첫 줄을 읽어 언어를 감지합니다.
+func (d *LanguageDetector) DetectFile(filePath string) Language {
+	if lang := d.Detect(filePath, nil); lang != LangUnknown {
+		return lang
+	}
+	return d.Detect(filePath, readFirstLine(filePath))
+}
+
+// readFirstLine은 파일의 첫 줄을 반환합니다 (읽을 수 없으면 nil).
+func readFirstLine(filePath string) []byte {
+	// #nosec G304 - filePath is provided by application code for code analysis, not direct user input
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	line, _ := bufio.NewReader(f).ReadSlice('\n')
+	return line
+}
+
+// detectShebang은 "#!/usr/bin/env python3" 같은 shebang에서 언어를 감지합니다.
+func detectShebang(firstLine []byte) Language {
+	if !bytes.HasPrefix(firstLine, []byte("#!")) {
+		return LangUnknown
+	}
+	fields := strings.Fields(string(firstLine[2:]))
+	if len(fields) == 0 {
+		return LangUnknown
+	}
+
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		// env의 옵션(-S 등)은 건너뜁니다
+		interpreter = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				interpreter = filepath.Base(f)
+				break
+			}
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(interpreter, "python"):
+		return LangPython
+	case interpreter == "node" || interpreter == "nodejs" || interpreter == "bun":
+		return LangJavaScript
+	case interpreter == "deno" || interpreter == "ts-node" || interpreter == "tsx":
+		return LangTypeScript
+	case interpreter == "sh" || interpreter == "bash" || interpreter == "zsh" ||
+		interpreter == "dash" || interpreter == "ksh":
+		return LangShell
+	default:
+		return LangUnknown
+	}
+}
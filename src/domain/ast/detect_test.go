@@ -0,0 +1,84 @@
+package ast
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParser_ShebangDetectsPythonScript(t *testing.T) {
+	path := writeFile(t, "deploy", "#!/usr/bin/env python3\n\ndef main():\n    print('hi')\n\nclass Runner:\n    pass\n")
+
+	result, err := NewParser().ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if result.Language != LangPython {
+		t.Fatalf("expected python, got %s", result.Language)
+	}
+	names := map[string]bool{}
+	for _, sym := range result.Symbols {
+		names[sym.Name] = true
+	}
+	if !names["main"] || !names["Runner"] {
+		t.Errorf("expected python symbols, got %+v", result.Symbols)
+	}
+
+	// A file with an unknown extension is not sniffed
+	if lang := defaultDetector().Detect("notes.txt", []byte("#!/usr/bin/env python3\n")); lang != LangUnknown {
+		t.Errorf("shebang should only apply to extensionless files, got %s", lang)
+	}
+}
+
+func TestParser_ConfiguredFilenameMapping(t *testing.T) {
+	path := writeFile(t, "handler.tmpl", "package web\n\nfunc Render() {\n}\n")
+
+	if _, err := NewParser().ParseFile(path); err == nil {
+		t.Fatal("unmapped .tmpl should be unsupported")
+	}
+
+	detector, err := NewLanguageDetector([]FilenameRule{{Pattern: "*.tmpl", Language: "Go"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := NewParser()
+	parser.SetLanguageDetector(detector)
+	result, err := parser.ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if result.Language != LangGo || len(result.Symbols) != 1 || result.Symbols[0].Name != "Render" {
+		t.Errorf("expected Go symbols via mapping, got %s %+v", result.Language, result.Symbols)
+	}
+
+	if _, err := NewLanguageDetector([]FilenameRule{{Pattern: "*.x", Language: "cobol"}}); err == nil {
+		t.Error("unknown language should be rejected")
+	}
+	if _, err := NewLanguageDetector([]FilenameRule{{Pattern: "[", Language: LangGo}}); err == nil {
+		t.Error("malformed pattern should be rejected")
+	}
+}
+
+func TestParser_DockerfileStages(t *testing.T) {
+	source := "FROM golang:1.24 AS build\nRUN go build ./...\n\nFROM alpine\nCOPY --from=build /app /app\n"
+
+	result, err := NewParser().Parse("Dockerfile", source, defaultDetector().Detect("Dockerfile", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Symbols) != 2 || result.Symbols[0].Name != "build" || result.Symbols[1].Name != "stage-1" {
+		t.Fatalf("expected two stages, got %+v", result.Symbols)
+	}
+	if result.Symbols[0].EndLine != 3 || result.Symbols[1].StartLine != 4 {
+		t.Errorf("unexpected stage ranges: %+v %+v", result.Symbols[0], result.Symbols[1])
+	}
+}
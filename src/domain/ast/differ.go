@@ -60,7 +60,7 @@ func (d *Differ) Diff(old, new *ParseResult) (*FileDiff, error) {
 	}
 
 	// 해시가 같으면 변경 없음
-	if old.Hash == new.Hash {
+	if HashesEqual(old.Hash, new.Hash) {
 		return diff, nil
 	}
 
@@ -80,8 +80,8 @@ func (d *Differ) Diff(old, new *ParseResult) (*FileDiff, error) {
 	// 추가/수정된 심볼 찾기
 	for key, newSym := range newSymbols {
 		if oldSym, exists := oldSymbols[key]; exists {
-			// 수정 확인
-			if oldSym.Hash != newSym.Hash {
+			// 수정 확인 (해시 설정이 달라도 같은 콘텐츠면 수정 아님)
+			if !HashesEqual(oldSym.Hash, newSym.Hash) {
 				diff.Diffs = append(diff.Diffs, &SymbolDiff{
 					Type:       DiffModified,
 					Symbol:     newSym,
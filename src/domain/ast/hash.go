@@ -0,0 +1,126 @@
+package ast
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// HashAlgorithm은 소스/심볼 콘텐츠 해시 알고리즘입니다.
+type HashAlgorithm string
+
+const (
+	HashSHA256 HashAlgorithm = "sha256"
+	HashSHA512 HashAlgorithm = "sha512"
+)
+
+// MinHashLength는 허용하는 최소 다이제스트 길이(바이트)입니다.
+const MinHashLength = 8
+
+// size는 알고리즘의 전체 다이제스트 길이를 반환합니다 (미지원이면 0).
+func (a HashAlgorithm) size() int {
+	switch a {
+	case HashSHA256:
+		return sha256.Size
+	case HashSHA512:
+		return sha512.Size
+	default:
+		return 0
+	}
+}
+
+// HashConfig는 콘텐츠 해시 설정입니다.
+type HashConfig struct {
+	Algorithm HashAlgorithm
+	// Length는 유지할 다이제스트 바이트 수입니다 (0이면 전체).
+	Length int
+}
+
+// DefaultHashConfig는 기본 해시 설정(SHA-256, 128비트)을 반환합니다.
+// 8바이트로 자르던 이전 방식은 심볼이 많아지면 충돌 위험이 있습니다.
+func DefaultHashConfig() HashConfig {
+	return HashConfig{
+		Algorithm: HashSHA256,
+		Length:    16,
+	}
+}
+
+// Validate는 설정을 검증합니다.
+func (c HashConfig) Validate() error {
+	size := c.Algorithm.size()
+	if size == 0 {
+		return fmt.Errorf("unsupported hash algorithm: %q", c.Algorithm)
+	}
+	if c.Length < 0 || c.Length > size {
+		return fmt.Errorf("hash length must be between %d and %d bytes for %s", MinHashLength, size, c.Algorithm)
+	}
+	if c.Length > 0 && c.Length < MinHashLength {
+		return fmt.Errorf("hash length %d is too short (min %d bytes)", c.Length, MinHashLength)
+	}
+	return nil
+}
+
+// Hasher는 설정된 알고리즘으로 콘텐츠 해시를 계산합니다.
+type Hasher struct {
+	config HashConfig
+}
+
+// NewHasher는 새 해시 계산기를 생성합니다.
+func NewHasher(config HashConfig) (*Hasher, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &Hasher{config: config}, nil
+}
+
+// Config는 해시 설정을 반환합니다.
+func (h *Hasher) Config() HashConfig {
+	return h.config
+}
+
+// Sum은 콘텐츠의 해시를 "<algorithm>:<hex>" 형식으로 반환합니다.
+func (h *Hasher) Sum(content string) string {
+	var digest []byte
+	switch h.config.Algorithm {
+	case HashSHA512:
+		sum := sha512.Sum512([]byte(content))
+		digest = sum[:]
+	default:
+		sum := sha256.Sum256([]byte(content))
+		digest = sum[:]
+	}
+
+	if h.config.Length > 0 {
+		digest = digest[:h.config.Length]
+	}
+	return string(h.config.Algorithm) + ":" + hex.EncodeToString(digest)
+}
+
+// splitHash는 해시를 알고리즘과 다이제스트로 나눕니다.
+// 접두사가 없는 해시는 이전 형식(SHA-256 앞 8바이트)으로 간주합니다.
+func splitHash(hash string) (HashAlgorithm, string) {
+	if alg, digest, ok := strings.Cut(hash, ":"); ok {
+		return HashAlgorithm(alg), digest
+	}
+	return HashSHA256, hash
+}
+
+// HashesEqual은 두 콘텐츠 해시가 같은 콘텐츠를 가리키는지 확인합니다.
+// 같은 알고리즘을 다른 길이로 자른 해시는 짧은 쪽 길이까지 비교하고,
+// 알고리즘이 다르면 같은 콘텐츠인지 알 수 없으므로 다르다고 봅니다.
+func HashesEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	algA, digestA := splitHash(a)
+	algB, digestB := splitHash(b)
+	if algA != algB || digestA == "" || digestB == "" {
+		return false
+	}
+
+	n := min(len(digestA), len(digestB))
+	return digestA[:n] == digestB[:n]
+}
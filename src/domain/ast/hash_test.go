@@ -0,0 +1,181 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHashConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  HashConfig
+		wantErr bool
+	}{
+		{"default", DefaultHashConfig(), false},
+		{"full sha256", HashConfig{Algorithm: HashSHA256}, false},
+		{"sha512 truncated", HashConfig{Algorithm: HashSHA512, Length: 24}, false},
+		{"unknown algorithm", HashConfig{Algorithm: "md5"}, true},
+		{"too short", HashConfig{Algorithm: HashSHA256, Length: 4}, true},
+		{"too long", HashConfig{Algorithm: HashSHA256, Length: 33}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasher_SumLength(t *testing.T) {
+	tests := []struct {
+		config    HashConfig
+		hexDigits int
+	}{
+		{DefaultHashConfig(), 32},
+		{HashConfig{Algorithm: HashSHA256}, 64},
+		{HashConfig{Algorithm: HashSHA512, Length: 24}, 48},
+	}
+
+	for _, tt := range tests {
+		h, err := NewHasher(tt.config)
+		if err != nil {
+			t.Fatalf("NewHasher(%+v) failed: %v", tt.config, err)
+		}
+
+		alg, digest := splitHash(h.Sum("func main() {}"))
+		if alg != tt.config.Algorithm {
+			t.Errorf("algorithm = %s, want %s", alg, tt.config.Algorithm)
+		}
+		if len(digest) != tt.hexDigits {
+			t.Errorf("%+v: digest length = %d, want %d", tt.config, len(digest), tt.hexDigits)
+		}
+	}
+}
+
+func TestHasher_NoCollisionsOnLargeCorpus(t *testing.T) {
+	h, err := NewHasher(DefaultHashConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 이름과 본문만 살짝 다른 심볼을 대량으로 생성
+	const corpusSize = 200000
+	seen := make(map[string]int, corpusSize)
+	for i := 0; i < corpusSize; i++ {
+		content := fmt.Sprintf("func Handler%d(ctx context.Context) error { return process(%d) }", i, i%97)
+		sum := h.Sum(content)
+		if prev, ok := seen[sum]; ok {
+			t.Fatalf("hash collision between symbol %d and %d: %s", prev, i, sum)
+		}
+		seen[sum] = i
+	}
+}
+
+func TestHashesEqual(t *testing.T) {
+	short, _ := NewHasher(HashConfig{Algorithm: HashSHA256, Length: 8})
+	long, _ := NewHasher(DefaultHashConfig())
+	sha512, _ := NewHasher(HashConfig{Algorithm: HashSHA512, Length: 16})
+
+	content := "type Config struct{}"
+
+	if !HashesEqual(short.Sum(content), long.Sum(content)) {
+		t.Error("truncations of the same digest should be equal")
+	}
+	if HashesEqual(long.Sum(content), long.Sum(content+" ")) {
+		t.Error("different content should not be equal")
+	}
+	if HashesEqual(long.Sum(content), sha512.Sum(content)) {
+		t.Error("hashes from different algorithms should not be equal")
+	}
+
+	// 접두사 없는 이전 형식은 SHA-256 앞 8바이트로 비교
+	legacy := strings.TrimPrefix(short.Sum(content), "sha256:")
+	if !HashesEqual(legacy, long.Sum(content)) {
+		t.Error("legacy hash should match the longer SHA-256 hash")
+	}
+	if HashesEqual("", long.Sum(content)) {
+		t.Error("empty hash should not match")
+	}
+}
+
+func TestDiffer_DetectsChangedSymbolsWithLongerHash(t *testing.T) {
+	before := `package main
+
+func Unchanged() int {
+	return 1
+}
+
+func Changed() int {
+	return 1
+}
+
+func Removed() {}
+`
+	after := `package main
+
+func Unchanged() int {
+	return 1
+}
+
+func Changed() int {
+	return 2
+}
+
+func Added() {}
+`
+
+	parser, err := NewParserWithHash(HashConfig{Algorithm: HashSHA256})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldResult, err := parser.Parse("main.go", before, LangGo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newResult, err := parser.Parse("main.go", after, LangGo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := NewDiffer().Diff(oldResult, newResult)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff.ModifiedCount != 1 || diff.AddedCount != 1 || diff.RemovedCount != 1 {
+		t.Fatalf("expected 1 modified/added/removed, got %d/%d/%d", diff.ModifiedCount, diff.AddedCount, diff.RemovedCount)
+	}
+	if modified := diff.GetModifiedSymbols(); modified[0].Symbol.Name != "Changed" {
+		t.Errorf("expected Changed to be modified, got %s", modified[0].Symbol.Name)
+	}
+}
+
+func TestDiffer_HashLengthChangeIsNotModification(t *testing.T) {
+	source := `package main
+
+func Stable() int {
+	return 1
+}
+`
+	shortParser, _ := NewParserWithHash(HashConfig{Algorithm: HashSHA256, Length: 8})
+	oldResult, err := shortParser.Parse("main.go", source, LangGo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newResult, err := NewParser().Parse("main.go", source, LangGo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := NewDiffer().Diff(oldResult, newResult)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.HasChanges() {
+		t.Errorf("re-hashing unchanged source should not report changes, got %d diffs", len(diff.Diffs))
+	}
+}
@@ -1,8 +1,6 @@
 package ast
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -24,16 +22,44 @@ const (
 
 // Parser는 AST 파서입니다.
 type Parser struct {
-	cache map[string]*ParseResult
+	cache    map[string]*ParseResult
+	hasher   *Hasher
+	detector *LanguageDetector
 }
 
-// NewParser는 새 파서를 생성합니다.
+// NewParser는 기본 해시 설정으로 새 파서를 생성합니다.
 func NewParser() *Parser {
+	hasher, _ := NewHasher(DefaultHashConfig())
 	return &Parser{
-		cache: make(map[string]*ParseResult),
+		cache:    make(map[string]*ParseResult),
+		hasher:   hasher,
+		detector: defaultDetector(),
 	}
 }
 
+// NewParserWithHash는 지정한 해시 설정으로 새 파서를 생성합니다.
+func NewParserWithHash(config HashConfig) (*Parser, error) {
+	hasher, err := NewHasher(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Parser{
+		cache:    make(map[string]*ParseResult),
+		hasher:   hasher,
+		detector: defaultDetector(),
+	}, nil
+}
+
+// SetLanguageDetector는 ParseFile이 사용할 언어 감지기를 설정합니다.
+func (p *Parser) SetLanguageDetector(detector *LanguageDetector) {
+	p.detector = detector
+}
+
+// DetectFile은 파서의 감지 규칙으로 파일의 언어를 감지합니다.
+func (p *Parser) DetectFile(filePath string) Language {
+	return p.detector.DetectFile(filePath)
+}
+
 // ParseFile은 파일을 파싱합니다.
 func (p *Parser) ParseFile(filePath string) (*ParseResult, error) {
 	// #nosec G304 - filePath is provided by application code for code analysis, not direct user input
@@ -42,7 +68,7 @@ func (p *Parser) ParseFile(filePath string) (*ParseResult, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	lang := DetectLanguage(filePath)
+	lang := p.DetectFile(filePath)
 	if lang == LangUnknown {
 		return nil, fmt.Errorf("unsupported language for file: %s", filePath)
 	}
@@ -53,25 +79,31 @@ func (p *Parser) ParseFile(filePath string) (*ParseResult, error) {
 // Parse는 소스 코드를 파싱합니다.
 func (p *Parser) Parse(filePath, source string, lang Language) (*ParseResult, error) {
 	// 캐시 확인
-	hash := computeHash(source)
+	hash := p.hasher.Sum(source)
 	cacheKey := fmt.Sprintf("%s:%s", filePath, hash)
 	if cached, ok := p.cache[cacheKey]; ok {
 		return cached, nil
 	}
 
-	// 간단한 패턴 기반 파싱 (tree-sitter 통합 전 임시 구현)
-	var symbols []*Symbol
+	// Go, TS/JS, Python, Rust는 tree-sitter로 파싱하고, 그 밖의 언어와
+	// cgo 없는 빌드는 줄 단위 패턴 파서를 씁니다
+	symbols, ok := parseTreeSitter(filePath, source, lang, p.hasher.Sum)
 	var err error
 
-	switch lang {
-	case LangGo:
-		symbols, err = parseGoSource(source)
-	case LangTypeScript, LangJavaScript:
-		symbols, err = parseJSSource(source)
-	case LangPython:
-		symbols, err = parsePythonSource(source)
+	switch {
+	case ok:
+	case lang == LangGo:
+		symbols, err = parseGoSource(source, p.hasher.Sum)
+	case lang == LangTypeScript, lang == LangJavaScript:
+		symbols, err = parseJSSource(source, p.hasher.Sum)
+	case lang == LangPython:
+		symbols, err = parsePythonSource(source, p.hasher.Sum)
+	case lang == LangShell:
+		symbols, err = parseShellSource(source, p.hasher.Sum)
+	case lang == LangDockerfile:
+		symbols, err = parseDockerfileSource(source, p.hasher.Sum)
 	default:
-		symbols, err = parseGenericSource(source)
+		symbols, err = parseGenericSource(source, p.hasher.Sum)
 	}
 
 	if err != nil {
@@ -133,6 +165,7 @@ const (
 )
 
 // DetectLanguage는 파일 확장자로 언어를 감지합니다.
+// 파일 이름 규칙과 shebang까지 보려면 LanguageDetector를 사용합니다.
 func DetectLanguage(filePath string) Language {
 	ext := strings.ToLower(filepath.Ext(filePath))
 	switch ext {
@@ -153,14 +186,8 @@ func DetectLanguage(filePath string) Language {
 	}
 }
 
-// computeHash는 소스의 해시를 계산합니다.
-func computeHash(source string) string {
-	hash := sha256.Sum256([]byte(source))
-	return hex.EncodeToString(hash[:8])
-}
-
 // parseGoSource는 Go 소스를 파싱합니다.
-func parseGoSource(source string) ([]*Symbol, error) {
+func parseGoSource(source string, computeHash func(string) string) ([]*Symbol, error) {
 	var symbols []*Symbol
 	lines := strings.Split(source, "\n")
 
@@ -318,7 +345,7 @@ func parseConstVar(line, prefix string) string {
 }
 
 // parseJSSource는 JavaScript/TypeScript 소스를 파싱합니다.
-func parseJSSource(source string) ([]*Symbol, error) {
+func parseJSSource(source string, computeHash func(string) string) ([]*Symbol, error) {
 	var symbols []*Symbol
 	lines := strings.Split(source, "\n")
 
@@ -439,7 +466,7 @@ func parseJSInterface(line string) string {
 }
 
 // parsePythonSource는 Python 소스를 파싱합니다.
-func parsePythonSource(source string) ([]*Symbol, error) {
+func parsePythonSource(source string, computeHash func(string) string) ([]*Symbol, error) {
 	var symbols []*Symbol
 	lines := strings.Split(source, "\n")
 
@@ -535,7 +562,7 @@ func parsePythonFunc(line string) string {
 }
 
 // parseGenericSource는 범용 소스를 파싱합니다.
-func parseGenericSource(source string) ([]*Symbol, error) {
+func parseGenericSource(source string, computeHash func(string) string) ([]*Symbol, error) {
 	// 최소한의 파싱: 파일 전체를 하나의 심볼로
 	lines := strings.Split(source, "\n")
 	return []*Symbol{
@@ -549,6 +576,94 @@ func parseGenericSource(source string) ([]*Symbol, error) {
 	}, nil
 }
 
+// parseShellSource는 셸 스크립트의 함수를 파싱합니다.
+func parseShellSource(source string, computeHash func(string) string) ([]*Symbol, error) {
+	var symbols []*Symbol
+	lines := strings.Split(source, "\n")
+
+	for i, line := range lines {
+		name := parseShellFunc(strings.TrimSpace(line))
+		if name == "" {
+			continue
+		}
+		endLine := findBlockEnd(lines, i)
+		content := strings.Join(lines[i:endLine], "\n")
+		symbols = append(symbols, &Symbol{
+			Type:      SymbolFunction,
+			Name:      name,
+			StartLine: i + 1,
+			EndLine:   endLine,
+			Hash:      computeHash(content),
+		})
+	}
+
+	if len(symbols) == 0 {
+		return parseGenericSource(source, computeHash)
+	}
+	return symbols, nil
+}
+
+// parseShellFunc는 "name() {" 또는 "function name {" 형태의 함수 이름을 반환합니다.
+func parseShellFunc(line string) string {
+	if rest, ok := strings.CutPrefix(line, "function "); ok {
+		name := strings.Fields(rest)
+		if len(name) == 0 {
+			return ""
+		}
+		return strings.TrimSuffix(name[0], "()")
+	}
+	if idx := strings.Index(line, "()"); idx > 0 {
+		name := strings.TrimSpace(line[:idx])
+		if !strings.ContainsAny(name, " \t=$\"'") {
+			return name
+		}
+	}
+	return ""
+}
+
+// parseDockerfileSource는 Dockerfile의 빌드 스테이지를 파싱합니다.
+// 각 스테이지는 FROM부터 다음 FROM 전까지이며 "AS" 별칭을 이름으로 씁니다.
+func parseDockerfileSource(source string, computeHash func(string) string) ([]*Symbol, error) {
+	var symbols []*Symbol
+	lines := strings.Split(source, "\n")
+
+	closeStage := func(end int) {
+		if len(symbols) == 0 {
+			return
+		}
+		last := symbols[len(symbols)-1]
+		last.EndLine = end
+		last.Hash = computeHash(strings.Join(lines[last.StartLine-1:end], "\n"))
+	}
+
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		closeStage(i)
+
+		name := fmt.Sprintf("stage-%d", len(symbols))
+		for j := 1; j+1 < len(fields); j++ {
+			if strings.EqualFold(fields[j], "AS") {
+				name = fields[j+1]
+				break
+			}
+		}
+		symbols = append(symbols, &Symbol{
+			Type:      SymbolTypeDef,
+			Name:      name,
+			StartLine: i + 1,
+		})
+	}
+	closeStage(len(lines))
+
+	if len(symbols) == 0 {
+		return parseGenericSource(source, computeHash)
+	}
+	return symbols, nil
+}
+
 // findBlockEnd는 블록의 끝을 찾습니다 (중괄호 기반).
 func findBlockEnd(lines []string, startIdx int) int {
 	depth := 0
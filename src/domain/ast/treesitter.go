@@ -0,0 +1,331 @@
+//go:build cgo
+
+package ast
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/python"
+	"github.com/smacker/go-tree-sitter/rust"
+	"github.com/smacker/go-tree-sitter/typescript/tsx"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+// treeSitterGrammar는 파일에 맞는 tree-sitter 문법을 반환합니다.
+// 문법이 없는 언어는 nil입니다.
+func treeSitterGrammar(filePath string, lang Language) *sitter.Language {
+	switch lang {
+	case LangGo:
+		return golang.GetLanguage()
+	case LangTypeScript:
+		if strings.EqualFold(filepath.Ext(filePath), ".tsx") {
+			return tsx.GetLanguage()
+		}
+		return typescript.GetLanguage()
+	case LangJavaScript:
+		return javascript.GetLanguage()
+	case LangPython:
+		return python.GetLanguage()
+	case LangRust:
+		return rust.GetLanguage()
+	default:
+		return nil
+	}
+}
+
+// parseTreeSitter는 tree-sitter 구문 트리에서 심볼을 추출합니다.
+// 문법이 없는 언어이거나 파싱에 실패하면 ok가 false입니다.
+func parseTreeSitter(filePath, source string, lang Language, computeHash func(string) string) (symbols []*Symbol, ok bool) {
+	grammar := treeSitterGrammar(filePath, lang)
+	if grammar == nil {
+		return nil, false
+	}
+
+	src := []byte(source)
+	root, err := sitter.ParseCtx(context.Background(), src, grammar)
+	if err != nil || root == nil {
+		return nil, false
+	}
+
+	x := &symbolExtractor{src: src, lines: strings.Split(source, "\n"), hash: computeHash}
+	switch lang {
+	case LangGo:
+		x.goDecls(root)
+	case LangTypeScript, LangJavaScript:
+		x.jsDecls(root)
+	case LangPython:
+		x.pythonDecls(root, "")
+	case LangRust:
+		x.rustDecls(root, "")
+	}
+	return x.symbols, true
+}
+
+// symbolExtractor는 구문 트리를 돌며 심볼을 모읍니다.
+type symbolExtractor struct {
+	src     []byte
+	lines   []string
+	hash    func(string) string
+	symbols []*Symbol
+}
+
+// add는 node가 차지하는 범위를 심볼로 추가합니다.
+func (x *symbolExtractor) add(node *sitter.Node, symType SymbolType, name, parent string) {
+	if node == nil || name == "" {
+		return
+	}
+
+	start, end := node.StartPoint(), node.EndPoint()
+	startLine := int(start.Row) + 1
+	endLine := int(end.Row) + 1
+	// 줄바꿈까지 포함한 노드는 다음 줄 0열에서 끝납니다
+	if end.Column == 0 && end.Row > start.Row {
+		endLine--
+	}
+	endLine = min(endLine, len(x.lines))
+
+	x.symbols = append(x.symbols, &Symbol{
+		Type:      symType,
+		Name:      name,
+		StartLine: startLine,
+		EndLine:   endLine,
+		StartCol:  int(start.Column),
+		EndCol:    int(end.Column),
+		Parent:    parent,
+		Hash:      x.hash(strings.Join(x.lines[startLine-1:endLine], "\n")),
+	})
+}
+
+// field는 이름 붙은 자식 노드의 텍스트를 반환합니다.
+func (x *symbolExtractor) field(node *sitter.Node, name string) string {
+	if node == nil {
+		return ""
+	}
+	child := node.ChildByFieldName(name)
+	if child == nil {
+		return ""
+	}
+	return child.Content(x.src)
+}
+
+// namedChildren은 node의 이름 있는 자식 노드를 반환합니다.
+func namedChildren(node *sitter.Node) []*sitter.Node {
+	if node == nil {
+		return nil
+	}
+	children := make([]*sitter.Node, 0, node.NamedChildCount())
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		children = append(children, node.NamedChild(i))
+	}
+	return children
+}
+
+// childrenOfType은 지정한 유형의 이름 있는 자식 노드를 반환합니다.
+func childrenOfType(node *sitter.Node, types ...string) []*sitter.Node {
+	var matched []*sitter.Node
+	for _, child := range namedChildren(node) {
+		for _, t := range types {
+			if child.Type() == t {
+				matched = append(matched, child)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// typeName은 "*Server", "Cache[K, V]", "Vec<T>" 같은 타입 표기에서
+// 기본 타입 이름만 남깁니다.
+func typeName(text string) string {
+	text = strings.TrimLeft(text, "*&")
+	if idx := strings.IndexAny(text, "[<"); idx > 0 {
+		text = text[:idx]
+	}
+	return strings.TrimSpace(text)
+}
+
+// goDecls는 Go 파일의 최상위 선언을 추출합니다. 함수 안의 함수
+// 리터럴은 감싸는 함수의 범위에 포함됩니다.
+func (x *symbolExtractor) goDecls(root *sitter.Node) {
+	for _, n := range namedChildren(root) {
+		switch n.Type() {
+		case "function_declaration":
+			x.add(n, SymbolFunction, x.field(n, "name"), "")
+
+		case "method_declaration":
+			receiver := ""
+			if params := childrenOfType(n.ChildByFieldName("receiver"), "parameter_declaration"); len(params) > 0 {
+				receiver = typeName(x.field(params[0], "type"))
+			}
+			x.add(n, SymbolMethod, x.field(n, "name"), receiver)
+
+		case "type_declaration":
+			specs := childrenOfType(n, "type_spec", "type_alias")
+			for _, spec := range specs {
+				symType := SymbolTypeDef
+				if t := spec.ChildByFieldName("type"); t != nil {
+					switch t.Type() {
+					case "struct_type":
+						symType = SymbolStruct
+					case "interface_type":
+						symType = SymbolInterface
+					}
+				}
+				// 단독 선언은 "type" 키워드부터, 묶음 선언은 각 항목만
+				span := spec
+				if len(specs) == 1 {
+					span = n
+				}
+				x.add(span, symType, x.field(spec, "name"), "")
+			}
+
+		case "const_declaration", "var_declaration":
+			symType := SymbolConstant
+			if n.Type() == "var_declaration" {
+				symType = SymbolVariable
+			}
+			specs := childrenOfType(n, "const_spec", "var_spec")
+			if list := childrenOfType(n, "var_spec_list"); len(list) > 0 {
+				specs = childrenOfType(list[0], "var_spec")
+			}
+			for _, spec := range specs {
+				span := spec
+				if len(specs) == 1 {
+					span = n
+				}
+				for i := 0; i < int(spec.ChildCount()); i++ {
+					if spec.FieldNameForChild(i) == "name" {
+						x.add(span, symType, spec.Child(i).Content(x.src), "")
+					}
+				}
+			}
+		}
+	}
+}
+
+// jsDecls는 JavaScript/TypeScript 파일의 최상위 선언과 클래스 메서드를
+// 추출합니다.
+func (x *symbolExtractor) jsDecls(root *sitter.Node) {
+	for _, n := range namedChildren(root) {
+		x.jsDecl(n, n)
+	}
+}
+
+// jsDecl은 선언 하나를 추출합니다. span은 export 문까지 포함한 범위입니다.
+func (x *symbolExtractor) jsDecl(n, span *sitter.Node) {
+	switch n.Type() {
+	case "export_statement":
+		if decl := n.ChildByFieldName("declaration"); decl != nil {
+			x.jsDecl(decl, n)
+		}
+
+	case "function_declaration", "generator_function_declaration":
+		x.add(span, SymbolFunction, x.field(n, "name"), "")
+
+	case "class_declaration", "abstract_class_declaration":
+		name := x.field(n, "name")
+		x.add(span, SymbolClass, name, "")
+		for _, m := range childrenOfType(n.ChildByFieldName("body"), "method_definition") {
+			x.add(m, SymbolMethod, x.field(m, "name"), name)
+		}
+
+	case "interface_declaration":
+		x.add(span, SymbolInterface, x.field(n, "name"), "")
+
+	case "type_alias_declaration":
+		x.add(span, SymbolTypeDef, x.field(n, "name"), "")
+
+	case "lexical_declaration", "variable_declaration":
+		declarators := childrenOfType(n, "variable_declarator")
+		for _, d := range declarators {
+			value := d.ChildByFieldName("value")
+			if value == nil {
+				continue
+			}
+			switch value.Type() {
+			case "arrow_function", "function_expression", "function", "generator_function":
+				fnSpan := d
+				if len(declarators) == 1 {
+					fnSpan = span
+				}
+				x.add(fnSpan, SymbolFunction, x.field(d, "name"), "")
+			}
+		}
+	}
+}
+
+// pythonDecls는 Python 블록의 함수와 클래스를 추출합니다. 클래스 본문의
+// 함수는 메서드가 되고, 데코레이터는 범위에 포함됩니다.
+func (x *symbolExtractor) pythonDecls(block *sitter.Node, class string) {
+	for _, n := range namedChildren(block) {
+		def := n
+		if n.Type() == "decorated_definition" {
+			def = n.ChildByFieldName("definition")
+			if def == nil {
+				continue
+			}
+		}
+
+		switch def.Type() {
+		case "function_definition":
+			symType := SymbolFunction
+			if class != "" {
+				symType = SymbolMethod
+			}
+			x.add(n, symType, x.field(def, "name"), class)
+
+		case "class_definition":
+			name := x.field(def, "name")
+			x.add(n, SymbolClass, name, class)
+			x.pythonDecls(def.ChildByFieldName("body"), name)
+		}
+	}
+}
+
+// rustDecls는 Rust 모듈의 항목을 추출합니다. impl과 trait 블록의 함수는
+// 해당 타입의 메서드가 됩니다.
+func (x *symbolExtractor) rustDecls(block *sitter.Node, owner string) {
+	for _, n := range namedChildren(block) {
+		switch n.Type() {
+		case "function_item":
+			symType := SymbolFunction
+			if owner != "" {
+				symType = SymbolMethod
+			}
+			x.add(n, symType, x.field(n, "name"), owner)
+
+		case "function_signature_item":
+			if owner != "" {
+				x.add(n, SymbolMethod, x.field(n, "name"), owner)
+			}
+
+		case "struct_item", "union_item":
+			x.add(n, SymbolStruct, x.field(n, "name"), "")
+
+		case "enum_item", "type_item":
+			x.add(n, SymbolTypeDef, x.field(n, "name"), "")
+
+		case "trait_item":
+			name := x.field(n, "name")
+			x.add(n, SymbolInterface, name, "")
+			x.rustDecls(n.ChildByFieldName("body"), name)
+
+		case "impl_item":
+			x.rustDecls(n.ChildByFieldName("body"), typeName(x.field(n, "type")))
+
+		case "const_item":
+			x.add(n, SymbolConstant, x.field(n, "name"), "")
+
+		case "static_item":
+			x.add(n, SymbolVariable, x.field(n, "name"), "")
+
+		case "mod_item":
+			x.rustDecls(n.ChildByFieldName("body"), "")
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !cgo
+
+package ast
+
+// parseTreeSitter는 cgo 없는 빌드에서 tree-sitter를 쓸 수 없으므로
+// 항상 패턴 기반 파서로 넘깁니다.
+func parseTreeSitter(filePath, source string, lang Language, computeHash func(string) string) (symbols []*Symbol, ok bool) {
+	return nil, false
+}
@@ -0,0 +1,140 @@
+//go:build cgo
+
+package ast
+
+import "testing"
+
+// symbolRanges는 파싱한 심볼의 줄 범위를 "유형 부모.이름" 키로 모읍니다.
+func symbolRanges(t *testing.T, filePath, source string, lang Language) map[string][2]int {
+	t.Helper()
+
+	result, err := NewParser().Parse(filePath, source, lang)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	ranges := make(map[string][2]int)
+	for _, sym := range result.Symbols {
+		key := string(sym.Type) + " " + sym.Name
+		if sym.Parent != "" {
+			key = string(sym.Type) + " " + sym.Parent + "." + sym.Name
+		}
+		ranges[key] = [2]int{sym.StartLine, sym.EndLine}
+	}
+	return ranges
+}
+
+func expectRanges(t *testing.T, got, want map[string][2]int) {
+	t.Helper()
+	for key, r := range want {
+		if got[key] != r {
+			t.Errorf("%s: expected lines %d-%d, got %v (symbols: %v)", key, r[0], r[1], got[key], got)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d symbols, got %d: %v", len(want), len(got), got)
+	}
+}
+
+func TestParse_TreeSitterGo(t *testing.T) {
+	source := `package cache
+
+type Cache[K comparable, V any] struct {
+	items map[K]V
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	v, ok := c.items[key]
+	return v, ok
+}
+
+func Map[T, U any](xs []T, f func(T) U) []U {
+	apply := func(x T) U {
+		return f(x)
+	}
+	out := make([]U, 0, len(xs))
+	for _, x := range xs {
+		out = append(out, apply(x))
+	}
+	return out
+}
+
+const (
+	A = 1
+	B = 2
+)
+`
+	expectRanges(t, symbolRanges(t, "cache.go", source, LangGo), map[string][2]int{
+		"struct Cache":     {3, 5},
+		"method Cache.Get": {7, 10},
+		"function Map":     {12, 21},
+		"constant A":       {24, 24},
+		"constant B":       {25, 25},
+	})
+}
+
+func TestParse_TreeSitterTypeScript(t *testing.T) {
+	source := `export interface Store<T> {
+  get(id: string): T;
+}
+
+export class MemoryStore<T> implements Store<T> {
+  private items = new Map<string, T>();
+
+  get(id: string): T {
+    return this.items.get(id)!;
+  }
+}
+
+export const load = async (id: string) => {
+  return id;
+};
+`
+	expectRanges(t, symbolRanges(t, "store.ts", source, LangTypeScript), map[string][2]int{
+		"interface Store":        {1, 3},
+		"class MemoryStore":      {5, 11},
+		"method MemoryStore.get": {8, 10},
+		"function load":          {13, 15},
+	})
+}
+
+func TestParse_TreeSitterPython(t *testing.T) {
+	source := `class Service:
+    @property
+    def name(self):
+        def inner():
+            return "x"
+        return inner()
+
+
+def main():
+    return Service()
+`
+	expectRanges(t, symbolRanges(t, "service.py", source, LangPython), map[string][2]int{
+		"class Service":       {1, 6},
+		"method Service.name": {2, 6},
+		"function main":       {9, 10},
+	})
+}
+
+func TestParse_TreeSitterRust(t *testing.T) {
+	source := `pub struct Stack<T> {
+    items: Vec<T>,
+}
+
+impl<T> Stack<T> {
+    pub fn push(&mut self, item: T) {
+        self.items.push(item);
+    }
+}
+
+pub trait Shape {
+    fn area(&self) -> f64;
+}
+`
+	expectRanges(t, symbolRanges(t, "stack.rs", source, LangRust), map[string][2]int{
+		"struct Stack":      {1, 3},
+		"method Stack.push": {6, 8},
+		"interface Shape":   {11, 13},
+		"method Shape.area": {12, 12},
+	})
+}
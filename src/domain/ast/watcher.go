@@ -64,6 +64,45 @@ func NewFileWatcher(pollInterval time.Duration) *FileWatcher {
 	}
 }
 
+// SetHashConfig는 이후 파싱에 사용할 해시 설정을 변경합니다.
+// 이미 감시 중인 파일의 이전 해시와도 HashesEqual로 비교되므로
+// 길이만 바꾼 경우 변경되지 않은 심볼이 수정으로 보고되지 않습니다.
+func (w *FileWatcher) SetHashConfig(config HashConfig) error {
+	parser, err := NewParserWithHash(config)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	parser.SetLanguageDetector(w.parser.detector)
+	w.parser = parser
+	w.mu.Unlock()
+	return nil
+}
+
+// SetLanguageRules는 확장자로 알 수 없는 파일의 언어 매핑을 설정합니다.
+// 규칙은 기본 규칙보다 먼저 적용되며 이후 파싱부터 반영됩니다.
+func (w *FileWatcher) SetLanguageRules(rules []FilenameRule) error {
+	detector, err := NewLanguageDetector(rules)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	parser, _ := NewParserWithHash(w.parser.hasher.Config())
+	parser.SetLanguageDetector(detector)
+	w.parser = parser
+	w.mu.Unlock()
+	return nil
+}
+
+// currentParser는 현재 파서를 반환합니다.
+func (w *FileWatcher) currentParser() *Parser {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.parser
+}
+
 // Watch는 파일을 감시합니다.
 func (w *FileWatcher) Watch(filePath string) error {
 	absPath, err := filepath.Abs(filePath)
@@ -76,7 +115,7 @@ func (w *FileWatcher) Watch(filePath string) error {
 		return fmt.Errorf("failed to stat file: %w", err)
 	}
 
-	result, err := w.parser.ParseFile(absPath)
+	result, err := w.currentParser().ParseFile(absPath)
 	if err != nil {
 		return fmt.Errorf("failed to parse file: %w", err)
 	}
@@ -118,7 +157,7 @@ func (w *FileWatcher) WatchDir(dirPath string, extensions []string) error {
 		}
 
 		// 지원하는 언어인지 확인
-		if DetectLanguage(path) == LangUnknown {
+		if w.currentParser().DetectFile(path) == LangUnknown {
 			return nil
 		}
 
@@ -191,7 +230,7 @@ func (w *FileWatcher) checkChanges() {
 
 		if info.ModTime().After(watched.LastModTime) {
 			// 변경됨
-			newResult, err := w.parser.ParseFile(watched.Path)
+			newResult, err := w.currentParser().ParseFile(watched.Path)
 			if err != nil {
 				continue
 			}
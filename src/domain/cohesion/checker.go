@@ -67,7 +67,7 @@ func (c *Checker) checkBefore(ctx context.Context, req *CheckRequest) (*CheckRes
 	}
 
 	// Generate embedding for the intention
-	intentionEmb, err := c.embedService.Embed(ctx, req.Intention)
+	intentionEmb, err := c.embedService.EmbedQuery(ctx, req.Intention)
 	if err != nil {
 		return nil, fmt.Errorf("failed to embed intention: %w", err)
 	}
@@ -91,7 +91,7 @@ func (c *Checker) checkAfter(ctx context.Context, req *CheckRequest) (*CheckResu
 	}
 
 	// Generate embedding for the result
-	resultEmb, err := c.embedService.Embed(ctx, req.Result)
+	resultEmb, err := c.embedService.EmbedQuery(ctx, req.Result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to embed result: %w", err)
 	}
@@ -63,6 +63,10 @@ func (m *mockEmbeddingService) Embed(ctx context.Context, text string) ([]float3
 	return embedding, nil
 }
 
+func (m *mockEmbeddingService) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	return m.Embed(ctx, text)
+}
+
 func (m *mockEmbeddingService) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	results := make([][]float32, len(texts))
 	for i := range texts {
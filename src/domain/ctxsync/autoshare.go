@@ -0,0 +1,368 @@
+package ctxsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"agent-collab/src/domain/ast"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultAutoShareDebounce는 마지막 저장 후 변경을 공유하기까지 기다리는
+// 기본 시간입니다. 에디터가 저장 한 번에 여러 이벤트를 내므로 모아서 처리합니다.
+const DefaultAutoShareDebounce = 500 * time.Millisecond
+
+// AutoShareConfig는 파일 저장 자동 공유 설정입니다.
+type AutoShareConfig struct {
+	// Root는 감시할 프로젝트 디렉토리입니다
+	Root string
+	// Ignore는 .gitignore 파일들 다음에 적용되는 .gitignore 형식의 규칙입니다
+	Ignore []string
+	// Debounce는 마지막 저장 후 변경을 공유하기까지 기다리는 시간입니다
+	Debounce time.Duration
+}
+
+// AutoWatcher는 fsnotify로 프로젝트의 파일 저장을 감지해 AST diff를 만들고
+// 변경 콜백을 호출합니다. .gitignore와 설정 규칙에 걸리는 경로, 파서가
+// 모르는 언어의 파일은 건너뜁니다.
+//
+// 파일 경로는 Root 기준 상대 경로('/' 구분자)로 보고되므로 체크아웃 위치가
+// 다른 피어와도 같은 경로로 비교됩니다.
+type AutoWatcher struct {
+	root     string
+	debounce time.Duration
+	ignore   *IgnoreMatcher
+	detector *ast.LanguageDetector
+	differ   *ast.Differ
+	fsw      *fsnotify.Watcher
+	onChange ast.ChangeCallback
+
+	mu        sync.Mutex
+	baselines map[string]*ast.ParseResult // 상대 경로 -> 마지막 파싱 결과
+	timers    map[string]*time.Timer
+	initial   []string // 시작할 때 파싱할 파일
+	closed    bool
+
+	// 같은 파일의 확인이 겹쳐 이전 결과를 덮어쓰지 않도록 직렬화
+	checkMu sync.Mutex
+}
+
+// NewAutoWatcher는 cfg.Root 아래 디렉토리를 감시하는 AutoWatcher를 생성합니다.
+// detector가 nil이면 기본 언어 규칙을 사용합니다.
+func NewAutoWatcher(cfg AutoShareConfig, detector *ast.LanguageDetector, onChange ast.ChangeCallback) (*AutoWatcher, error) {
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	ignore, err := NewIgnoreMatcher(cfg.Ignore)
+	if err != nil {
+		return nil, err
+	}
+	debounce := cfg.Debounce
+	if debounce <= 0 {
+		debounce = DefaultAutoShareDebounce
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	w := &AutoWatcher{
+		root:      root,
+		debounce:  debounce,
+		ignore:    ignore,
+		detector:  detector,
+		differ:    ast.NewDiffer(),
+		fsw:       fsw,
+		onChange:  onChange,
+		baselines: make(map[string]*ast.ParseResult),
+		timers:    make(map[string]*time.Timer),
+	}
+
+	// 디렉토리 감시는 바로 등록해 감시 한도 초과 같은 오류를 알리고,
+	// 파일 파싱은 Run에서 합니다
+	files, err := w.addTree(root)
+	if err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	w.initial = files
+	return w, nil
+}
+
+// Root는 감시 중인 프로젝트 디렉토리입니다.
+func (w *AutoWatcher) Root() string {
+	return w.root
+}
+
+// Run은 기존 파일을 파싱한 뒤 ctx가 끝나거나 Close될 때까지 파일 이벤트를
+// 처리합니다.
+func (w *AutoWatcher) Run(ctx context.Context) {
+	defer w.Close()
+
+	w.mu.Lock()
+	initial := w.initial
+	w.initial = nil
+	w.mu.Unlock()
+	for _, rel := range initial {
+		if ctx.Err() != nil {
+			return
+		}
+		if result := w.parse(rel); result != nil {
+			w.mu.Lock()
+			w.baselines[rel] = result
+			w.mu.Unlock()
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(ev)
+		case _, ok := <-w.fsw.Errors:
+			// 이벤트 큐 넘침 등은 다음 저장에서 다시 감지됩니다
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// Close는 감시를 중단합니다.
+func (w *AutoWatcher) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	for _, t := range w.timers {
+		t.Stop()
+	}
+	w.mu.Unlock()
+	return w.fsw.Close()
+}
+
+// addTree는 dir 아래 무시되지 않는 디렉토리를 감시에 등록하고, 공유 대상
+// 파일의 상대 경로를 반환합니다.
+func (w *AutoWatcher) addTree(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 그 사이 지워진 경로는 건너뜀
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		rel, err := relSlash(w.root, path)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if w.ignore.Ignored(rel, true) {
+				return filepath.SkipDir
+			}
+			if err := w.ignore.LoadGitignore(w.root, path); err != nil {
+				return err
+			}
+			if err := w.fsw.Add(path); err != nil {
+				return fmt.Errorf("failed to watch %s: %w", path, err)
+			}
+			return nil
+		}
+
+		if d.Type().IsRegular() && w.shareable(rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// shareable은 파일이 무시되지 않고 파서가 아는 언어인지 반환합니다.
+func (w *AutoWatcher) shareable(rel string) bool {
+	if w.ignore.Ignored(rel, false) {
+		return false
+	}
+	return w.newParser().DetectFile(filepath.Join(w.root, filepath.FromSlash(rel))) != ast.LangUnknown
+}
+
+// handleEvent는 fsnotify 이벤트 하나를 처리합니다.
+func (w *AutoWatcher) handleEvent(ev fsnotify.Event) {
+	rel, err := relSlash(w.root, ev.Name)
+	if err != nil || rel == "" {
+		return
+	}
+
+	if filepath.Base(ev.Name) == gitignoreFile {
+		// 이후 이벤트부터 바뀐 규칙 적용
+		_ = w.ignore.LoadGitignore(w.root, filepath.Dir(ev.Name))
+		return
+	}
+
+	switch {
+	case ev.Has(fsnotify.Create):
+		info, err := os.Stat(ev.Name)
+		if err != nil {
+			return
+		}
+		if info.IsDir() {
+			// 새 디렉토리 (복사나 이동으로 파일이 이미 있을 수 있음)
+			files, _ := w.addTree(ev.Name)
+			for _, f := range files {
+				w.schedule(f)
+			}
+			return
+		}
+		if w.shareable(rel) {
+			w.schedule(rel)
+		}
+
+	case ev.Has(fsnotify.Write):
+		if w.shareable(rel) {
+			w.schedule(rel)
+		}
+
+	case ev.Has(fsnotify.Remove), ev.Has(fsnotify.Rename):
+		// 디렉토리가 사라지면 그 아래 파일도 삭제로 보고
+		w.mu.Lock()
+		var gone []string
+		for path := range w.baselines {
+			if path == rel || strings.HasPrefix(path, rel+"/") {
+				gone = append(gone, path)
+			}
+		}
+		w.mu.Unlock()
+		for _, path := range gone {
+			w.schedule(path)
+		}
+	}
+}
+
+// schedule은 debounce 후 파일을 확인합니다. 그 전에 다시 저장되면 미룹니다.
+func (w *AutoWatcher) schedule(rel string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	if t, ok := w.timers[rel]; ok {
+		t.Reset(w.debounce)
+		return
+	}
+	w.timers[rel] = time.AfterFunc(w.debounce, func() { w.check(rel) })
+}
+
+// check는 파일을 다시 파싱해 마지막 결과와 비교하고 변경을 알립니다.
+func (w *AutoWatcher) check(rel string) {
+	w.checkMu.Lock()
+	defer w.checkMu.Unlock()
+
+	w.mu.Lock()
+	delete(w.timers, rel)
+	closed := w.closed
+	old := w.baselines[rel]
+	w.mu.Unlock()
+	if closed {
+		return
+	}
+
+	abs := filepath.Join(w.root, filepath.FromSlash(rel))
+	if _, err := os.Stat(abs); errors.Is(err, fs.ErrNotExist) {
+		if old == nil {
+			return
+		}
+		w.mu.Lock()
+		delete(w.baselines, rel)
+		w.mu.Unlock()
+		w.notify(&ast.FileChange{FilePath: rel, Type: ast.ChangeDeleted, Timestamp: time.Now()})
+		return
+	}
+
+	result := w.parse(rel)
+	if result == nil {
+		return
+	}
+
+	changeType := ast.ChangeModified
+	if old == nil {
+		// 새 파일은 모든 심볼이 추가된 것으로 비교
+		changeType = ast.ChangeCreated
+		old = &ast.ParseResult{FilePath: rel, Language: result.Language}
+	}
+	diff, err := w.differ.Diff(old, result)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.baselines[rel] = result
+	w.mu.Unlock()
+
+	if changeType == ast.ChangeModified && !diff.HasChanges() {
+		return
+	}
+	w.notify(&ast.FileChange{FilePath: rel, Type: changeType, Diff: diff, Timestamp: time.Now()})
+}
+
+// parse는 파일을 파싱합니다. 읽을 수 없거나 모르는 언어면 nil입니다.
+// 매번 새 파서를 써서 저장할 때마다 캐시가 커지지 않게 합니다.
+func (w *AutoWatcher) parse(rel string) *ast.ParseResult {
+	abs := filepath.Join(w.root, filepath.FromSlash(rel))
+	// #nosec G304 - path is a file inside the watched project
+	content, err := os.ReadFile(abs)
+	if err != nil {
+		return nil
+	}
+
+	parser := w.newParser()
+	lang := parser.DetectFile(abs)
+	if lang == ast.LangUnknown {
+		return nil
+	}
+	result, err := parser.Parse(rel, string(content), lang)
+	if err != nil {
+		return nil
+	}
+	return result
+}
+
+// newParser는 설정된 언어 규칙을 쓰는 파서를 생성합니다.
+func (w *AutoWatcher) newParser() *ast.Parser {
+	parser := ast.NewParser()
+	if w.detector != nil {
+		parser.SetLanguageDetector(w.detector)
+	}
+	return parser
+}
+
+// notify는 변경 콜백을 호출합니다.
+func (w *AutoWatcher) notify(change *ast.FileChange) {
+	if w.onChange != nil {
+		_ = w.onChange(change)
+	}
+}
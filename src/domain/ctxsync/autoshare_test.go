@@ -0,0 +1,84 @@
+package ctxsync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"agent-collab/src/domain/ast"
+)
+
+func TestSyncManager_AutoShareBroadcastsSavedFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".gitignore"), "build/\n")
+	writeFile(t, filepath.Join(root, "app", "main.go"), "package app\n\nfunc Run() {}\n")
+
+	sm := NewSyncManager("node-a", "agent-a")
+	deltas := make(chan *Delta, 16)
+	sm.SetBroadcastFn(func(d *Delta) error {
+		deltas <- d
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := sm.StartAutoShare(ctx, AutoShareConfig{Root: root, Ignore: []string{"*_gen.go"}, Debounce: 20 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(sm.Stop)
+	if err := sm.StartAutoShare(ctx, AutoShareConfig{Root: root}); err == nil {
+		t.Error("expected a second start to fail")
+	}
+	// Let the watcher take its baseline
+	time.Sleep(100 * time.Millisecond)
+
+	next := func() *Delta {
+		t.Helper()
+		select {
+		case d := <-deltas:
+			return d
+		case <-time.After(5 * time.Second):
+			t.Fatal("no delta broadcast")
+			return nil
+		}
+	}
+
+	// Ignored and unsupported files are not shared
+	writeFile(t, filepath.Join(root, "build", "out.go"), "package build\n")
+	writeFile(t, filepath.Join(root, "app", "types_gen.go"), "package app\n")
+	writeFile(t, filepath.Join(root, "app", "main.go"), "package app\n\nfunc Run() {}\n\nfunc Stop() {}\n")
+
+	d := next()
+	if d.Payload.FilePath != "app/main.go" || d.Payload.FileDiff == nil {
+		t.Fatalf("expected the modified file shared by relative path, got %+v", d.Payload)
+	}
+	if diffs := d.Payload.FileDiff.Diffs; len(diffs) != 1 || diffs[0].Type != ast.DiffAdded || diffs[0].Symbol.Name != "Stop" {
+		t.Errorf("expected Stop added, got %+v", diffs)
+	}
+
+	// New files, also in new directories, report their symbols as added
+	writeFile(t, filepath.Join(root, "lib", "util.go"), "package lib\n\nfunc Help() {}\n")
+	d = next()
+	if d.Payload.FilePath != "lib/util.go" || d.Payload.FileDiff == nil || len(d.Payload.FileDiff.Diffs) == 0 {
+		t.Fatalf("expected the new file shared with its symbols, got %+v", d.Payload)
+	}
+
+	if err := os.Remove(filepath.Join(root, "app", "main.go")); err != nil {
+		t.Fatal(err)
+	}
+	d = next()
+	if d.Payload.FilePath != "app/main.go" || d.Payload.FileDiff != nil {
+		t.Fatalf("expected the deletion shared, got %+v", d.Payload)
+	}
+
+	select {
+	case d := <-deltas:
+		t.Errorf("unexpected delta for %s", d.Payload.FilePath)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if sm.AutoShareRoot() == "" {
+		t.Error("expected the watched root reported")
+	}
+}
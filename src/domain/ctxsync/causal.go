@@ -0,0 +1,185 @@
+package ctxsync
+
+import (
+	"sync"
+	"time"
+)
+
+// Causal delivery defaults.
+const (
+	DefaultCausalBufferSize = 256
+	DefaultCausalTimeout    = 30 * time.Second
+)
+
+// CausalStats counts what a causal buffer did with incoming messages.
+type CausalStats struct {
+	Pending    int   `json:"pending"`
+	Delivered  int64 `json:"delivered"`
+	Duplicates int64 `json:"duplicates"`
+	Dropped    int64 `json:"dropped"`
+}
+
+// CausalBuffer delivers messages stamped with vector clocks in causal
+// order. A message from S stamped V is delivered once every earlier
+// message from S (V[S]-1) and everything S had seen from other nodes
+// has been delivered. Until then it is held, up to a bounded number of
+// messages for a bounded time.
+//
+// The first message seen from a source sets that source's baseline, so a
+// node joining late does not wait for history it will never receive.
+// Messages at or below what was already delivered are replays and are
+// discarded. When a held message is dropped (buffer full or timed out),
+// the gap it was waiting on is abandoned so later messages can flow.
+type CausalBuffer[T any] struct {
+	mu        sync.Mutex
+	delivered *VectorClock
+	known     map[string]bool
+	pending   []*causalEntry[T]
+	maxSize   int
+	timeout   time.Duration
+	stats     CausalStats
+}
+
+type causalEntry[T any] struct {
+	source   string
+	clock    *VectorClock
+	msg      T
+	received time.Time
+}
+
+// NewCausalBuffer creates a causal buffer holding at most maxSize messages
+// for at most timeout. Zero values use the defaults.
+func NewCausalBuffer[T any](maxSize int, timeout time.Duration) *CausalBuffer[T] {
+	if maxSize <= 0 {
+		maxSize = DefaultCausalBufferSize
+	}
+	if timeout <= 0 {
+		timeout = DefaultCausalTimeout
+	}
+	return &CausalBuffer[T]{
+		delivered: NewVectorClock(),
+		known:     make(map[string]bool),
+		maxSize:   maxSize,
+		timeout:   timeout,
+	}
+}
+
+// Receive accepts a message and returns every message that is now
+// deliverable, in causal order. Held messages that timed out are dropped
+// first; dropped reports how many.
+func (b *CausalBuffer[T]) Receive(source string, clock *VectorClock, msg T, now time.Time) (ready []T, dropped int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	dropped = b.expireLocked(now)
+
+	if !b.known[source] {
+		b.known[source] = true
+		if seq := clock.Get(source); seq > 0 && b.delivered.Get(source) < seq-1 {
+			b.delivered.Merge(FromMap(map[string]uint64{source: seq - 1}))
+		}
+	}
+
+	if clock.Get(source) <= b.delivered.Get(source) {
+		b.stats.Duplicates++
+		return b.drainLocked(), dropped
+	}
+
+	b.pending = append(b.pending, &causalEntry[T]{source: source, clock: clock, msg: msg, received: now})
+	if len(b.pending) > b.maxSize {
+		b.abandonLocked(b.pending[0])
+		b.pending = b.pending[1:]
+		dropped++
+	}
+
+	return b.drainLocked(), dropped
+}
+
+// Expire drops held messages older than the timeout and returns the
+// messages their abandoned gaps unblocked.
+func (b *CausalBuffer[T]) Expire(now time.Time) (ready []T, dropped int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	dropped = b.expireLocked(now)
+	return b.drainLocked(), dropped
+}
+
+// Delivered returns a copy of the clock of delivered messages.
+func (b *CausalBuffer[T]) Delivered() *VectorClock {
+	return b.delivered.Clone()
+}
+
+// Stats returns delivery counters.
+func (b *CausalBuffer[T]) Stats() CausalStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := b.stats
+	stats.Pending = len(b.pending)
+	return stats
+}
+
+// expireLocked drops held messages older than the timeout (must hold b.mu).
+func (b *CausalBuffer[T]) expireLocked(now time.Time) int {
+	kept := b.pending[:0]
+	dropped := 0
+	for _, e := range b.pending {
+		if now.Sub(e.received) >= b.timeout {
+			b.abandonLocked(e)
+			dropped++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	b.pending = kept
+	return dropped
+}
+
+// abandonLocked drops a held message and treats it and its dependencies
+// as delivered so messages after it are not blocked (must hold b.mu).
+func (b *CausalBuffer[T]) abandonLocked(e *causalEntry[T]) {
+	b.delivered.Merge(e.clock)
+	b.stats.Dropped++
+}
+
+// drainLocked removes and returns deliverable messages until none is
+// left (must hold b.mu).
+func (b *CausalBuffer[T]) drainLocked() []T {
+	var ready []T
+	for progress := true; progress; {
+		progress = false
+		kept := b.pending[:0]
+		for _, e := range b.pending {
+			switch {
+			case e.clock.Get(e.source) <= b.delivered.Get(e.source):
+				// Covered by an abandoned gap or delivered meanwhile
+				b.stats.Duplicates++
+			case b.deliverable(e):
+				b.delivered.Merge(FromMap(map[string]uint64{e.source: e.clock.Get(e.source)}))
+				b.stats.Delivered++
+				ready = append(ready, e.msg)
+				progress = true
+			default:
+				kept = append(kept, e)
+			}
+		}
+		b.pending = kept
+	}
+	return ready
+}
+
+// deliverable reports whether every dependency of e has been delivered.
+func (b *CausalBuffer[T]) deliverable(e *causalEntry[T]) bool {
+	for node, seq := range e.clock.ToMap() {
+		have := b.delivered.Get(node)
+		if node == e.source {
+			if seq != have+1 {
+				return false
+			}
+		} else if seq > have {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package ctxsync
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func clock(entries map[string]uint64) *VectorClock {
+	return FromMap(entries)
+}
+
+func TestCausalBuffer_DeliversInCausalOrder(t *testing.T) {
+	b := NewCausalBuffer[string](0, 0)
+	now := time.Now()
+
+	// Establish contact with both sources
+	b.Receive("a", clock(map[string]uint64{"a": 1}), "a1", now)
+	b.Receive("b", clock(map[string]uint64{"b": 1}), "b1", now)
+
+	// a2 builds on a1; b2 was sent after b saw a2; a3 follows a2.
+	// They arrive in reverse order.
+	a2 := clock(map[string]uint64{"a": 2, "b": 1})
+	b2 := clock(map[string]uint64{"a": 2, "b": 2})
+	a3 := clock(map[string]uint64{"a": 3, "b": 1})
+
+	if ready, _ := b.Receive("b", b2, "b2", now); len(ready) != 0 {
+		t.Fatalf("b2 depends on a2 and must wait, got %v", ready)
+	}
+	if ready, _ := b.Receive("a", a3, "a3", now); len(ready) != 0 {
+		t.Fatalf("a3 depends on a2 and must wait, got %v", ready)
+	}
+	if stats := b.Stats(); stats.Pending != 2 {
+		t.Errorf("expected 2 pending, got %d", stats.Pending)
+	}
+
+	ready, dropped := b.Receive("a", a2, "a2", now)
+	if dropped != 0 {
+		t.Errorf("nothing should be dropped, got %d", dropped)
+	}
+	if len(ready) != 3 || ready[0] != "a2" {
+		t.Fatalf("a2 should unblock b2 and a3 after it, got %v", ready)
+	}
+	if !(ready[1] == "b2" && ready[2] == "a3" || ready[1] == "a3" && ready[2] == "b2") {
+		t.Errorf("unexpected delivery order %v", ready)
+	}
+
+	want := map[string]uint64{"a": 3, "b": 2}
+	if got := b.Delivered().ToMap(); !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered clock = %v, want %v", got, want)
+	}
+}
+
+func TestCausalBuffer_DropsReplays(t *testing.T) {
+	b := NewCausalBuffer[string](0, 0)
+	now := time.Now()
+
+	c1 := clock(map[string]uint64{"a": 1})
+	if ready, _ := b.Receive("a", c1, "a1", now); len(ready) != 1 {
+		t.Fatalf("first share should be delivered, got %v", ready)
+	}
+	if ready, _ := b.Receive("a", c1, "a1 again", now); len(ready) != 0 {
+		t.Errorf("replayed share should not be delivered again, got %v", ready)
+	}
+	if stats := b.Stats(); stats.Duplicates != 1 || stats.Delivered != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCausalBuffer_FirstContactSetsBaseline(t *testing.T) {
+	b := NewCausalBuffer[string](0, 0)
+
+	// A node that joins late sees a peer's 5th share first
+	ready, _ := b.Receive("a", clock(map[string]uint64{"a": 5}), "a5", time.Now())
+	if len(ready) != 1 {
+		t.Errorf("first share from a source should be delivered, got %v", ready)
+	}
+}
+
+func TestCausalBuffer_TimeoutDropsAndUnblocks(t *testing.T) {
+	b := NewCausalBuffer[string](0, time.Minute)
+	start := time.Now()
+
+	b.Receive("a", clock(map[string]uint64{"a": 1}), "a1", start)
+	b.Receive("b", clock(map[string]uint64{"b": 1}), "b1", start)
+
+	// b2 depends on a2, which never arrives
+	b.Receive("b", clock(map[string]uint64{"a": 2, "b": 2}), "b2", start)
+	// b3 follows b2
+	ready, _ := b.Receive("b", clock(map[string]uint64{"a": 2, "b": 3}), "b3", start.Add(30*time.Second))
+	if len(ready) != 0 {
+		t.Fatalf("b3 must wait behind b2, got %v", ready)
+	}
+
+	ready, dropped := b.Expire(start.Add(time.Minute))
+	if dropped != 1 {
+		t.Errorf("b2 should time out, dropped %d", dropped)
+	}
+	if len(ready) != 1 || ready[0] != "b3" {
+		t.Errorf("abandoning b2 should unblock b3, got %v", ready)
+	}
+
+	// a2 showing up late is no longer applied
+	if ready, _ := b.Receive("a", clock(map[string]uint64{"a": 2}), "a2", start.Add(2*time.Minute)); len(ready) != 0 {
+		t.Errorf("share behind an abandoned gap should be discarded, got %v", ready)
+	}
+}
+
+func TestCausalBuffer_BoundedSize(t *testing.T) {
+	b := NewCausalBuffer[int](3, time.Hour)
+	now := time.Now()
+
+	b.Receive("a", clock(map[string]uint64{"a": 1}), 1, now)
+
+	// Shares 3..7 all wait for the missing share 2
+	var dropped int
+	for seq := uint64(3); seq <= 7; seq++ {
+		_, d := b.Receive("a", clock(map[string]uint64{"a": seq}), int(seq), now)
+		dropped += d
+	}
+
+	if stats := b.Stats(); stats.Pending > 3 {
+		t.Errorf("buffer should hold at most 3 shares, holds %d", stats.Pending)
+	}
+	if dropped == 0 {
+		t.Error("overflowing the buffer should drop shares")
+	}
+}
@@ -0,0 +1,125 @@
+package ctxsync
+
+import (
+	"time"
+)
+
+// CompactionConfig는 델타 로그 압축 설정입니다.
+type CompactionConfig struct {
+	// Interval은 체크포인트 생성 주기입니다. 0이면 주기적 압축을 하지 않습니다.
+	Interval time.Duration `json:"interval"`
+	// MinDeltas는 압축을 수행하기 위한 최소 델타 수입니다.
+	MinDeltas int `json:"min_deltas"`
+	// Retention은 델타를 로그에 남겨 두는 기간입니다. 지난 델타는
+	// 체크포인트로 접힙니다. 0이면 기간으로 정리하지 않습니다.
+	Retention time.Duration `json:"retention"`
+}
+
+// DefaultCompactionConfig는 기본 압축 설정을 반환합니다.
+func DefaultCompactionConfig() CompactionConfig {
+	return CompactionConfig{
+		Interval:  5 * time.Minute,
+		MinDeltas: 100,
+		Retention: DefaultDeltaRetention,
+	}
+}
+
+// LockState는 동기화된 락 상태입니다.
+type LockState struct {
+	LockID     string `json:"lock_id"`
+	HolderID   string `json:"holder_id"`
+	HolderName string `json:"holder_name"`
+	TargetDesc string `json:"target_desc,omitempty"`
+	Intention  string `json:"intention,omitempty"`
+}
+
+// SyncState는 델타를 순서대로 적용해 얻은 동기화 상태입니다.
+type SyncState struct {
+	Files  map[string]string     `json:"files"`  // 파일 경로 -> 해시
+	Locks  map[string]*LockState `json:"locks"`  // 락 ID -> 락 상태
+	Agents map[string]string     `json:"agents"` // 에이전트 ID -> 상태
+}
+
+// NewSyncState는 빈 동기화 상태를 생성합니다.
+func NewSyncState() *SyncState {
+	return &SyncState{
+		Files:  make(map[string]string),
+		Locks:  make(map[string]*LockState),
+		Agents: make(map[string]string),
+	}
+}
+
+// Apply는 델타를 상태에 반영합니다.
+func (s *SyncState) Apply(delta *Delta) {
+	if delta == nil || delta.Payload == nil {
+		return
+	}
+
+	p := delta.Payload
+	switch delta.Type {
+	case DeltaFileChange:
+		if p.FilePath != "" {
+			s.Files[p.FilePath] = p.FileHash
+		}
+	case DeltaLockAcquired:
+		s.Locks[p.LockID] = &LockState{
+			LockID:     p.LockID,
+			HolderID:   delta.SourceID,
+			HolderName: delta.SourceName,
+			TargetDesc: p.TargetDesc,
+			Intention:  p.Intention,
+		}
+	case DeltaLockReleased:
+		delete(s.Locks, p.LockID)
+	case DeltaAgentStatus:
+		if p.AgentID != "" {
+			s.Agents[p.AgentID] = p.AgentState
+		}
+	}
+}
+
+// Clone은 상태를 복제합니다.
+func (s *SyncState) Clone() *SyncState {
+	clone := NewSyncState()
+	for k, v := range s.Files {
+		clone.Files[k] = v
+	}
+	for k, v := range s.Locks {
+		lock := *v
+		clone.Locks[k] = &lock
+	}
+	for k, v := range s.Agents {
+		clone.Agents[k] = v
+	}
+	return clone
+}
+
+// Checkpoint는 압축된 델타들의 스냅샷입니다.
+// 재생은 체크포인트 상태에서 시작해 이후 델타를 적용합니다.
+type Checkpoint struct {
+	VectorClock *VectorClock `json:"vector_clock"`
+	State       *SyncState   `json:"state"`
+	DeltaCount  int          `json:"delta_count"` // 지금까지 압축된 델타 수
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// Covers는 벡터 클럭이 체크포인트에 이미 포함되어 있는지 확인합니다.
+func (cp *Checkpoint) Covers(vc *VectorClock) bool {
+	if cp == nil || vc == nil {
+		return false
+	}
+	return clockIncludes(cp.VectorClock, vc)
+}
+
+// clone은 체크포인트를 복제합니다.
+func (cp *Checkpoint) clone() *Checkpoint {
+	if cp == nil {
+		return nil
+	}
+	return &Checkpoint{
+		VectorClock: cp.VectorClock.Clone(),
+		State:       cp.State.Clone(),
+		DeltaCount:  cp.DeltaCount,
+		CreatedAt:   cp.CreatedAt,
+	}
+}
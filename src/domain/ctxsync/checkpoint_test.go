@@ -0,0 +1,217 @@
+package ctxsync
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"agent-collab/src/domain/ast"
+)
+
+// deltaSource emits deltas with an advancing vector clock for one agent.
+type deltaSource struct {
+	id    string
+	clock *VectorClock
+	seq   int
+}
+
+func newDeltaSource(id string) *deltaSource {
+	return &deltaSource{id: id, clock: NewVectorClock()}
+}
+
+func (s *deltaSource) next(build func(vc *VectorClock) *Delta) *Delta {
+	s.clock.Increment(s.id)
+	s.seq++
+	d := build(s.clock)
+	d.ID = fmt.Sprintf("%s-%d", s.id, s.seq)
+	return d
+}
+
+func (s *deltaSource) fileChange(path, hash string) *Delta {
+	return s.next(func(vc *VectorClock) *Delta {
+		return NewFileChangeDelta(s.id, s.id, vc, path, &ast.FileDiff{NewHash: hash})
+	})
+}
+
+func (s *deltaSource) lockAcquired(lockID, target string) *Delta {
+	return s.next(func(vc *VectorClock) *Delta {
+		return NewLockAcquiredDelta(s.id, s.id, vc, lockID, target, "edit")
+	})
+}
+
+func (s *deltaSource) lockReleased(lockID string) *Delta {
+	return s.next(func(vc *VectorClock) *Delta {
+		return NewLockReleasedDelta(s.id, s.id, vc, lockID)
+	})
+}
+
+func (s *deltaSource) status(state string) *Delta {
+	return s.next(func(vc *VectorClock) *Delta {
+		return NewAgentStatusDelta(s.id, s.id, vc, s.id, state)
+	})
+}
+
+func receiveAll(t *testing.T, sm *SyncManager, deltas ...*Delta) {
+	t.Helper()
+	for _, d := range deltas {
+		if err := sm.ReceiveDelta(d); err != nil {
+			t.Fatalf("ReceiveDelta(%s) failed: %v", d.ID, err)
+		}
+	}
+}
+
+func TestDeltaLog_CompactTruncatesAndReplays(t *testing.T) {
+	a := newDeltaSource("agent-a")
+	log := NewDeltaLog(100)
+
+	log.Append(a.fileChange("main.go", "h1"))
+	log.Append(a.lockAcquired("lock-1", "main.go:Run"))
+	log.Append(a.fileChange("main.go", "h2"))
+
+	before := log.Replay()
+	cp := log.Compact()
+
+	if log.Size() != 0 {
+		t.Errorf("expected empty log after compaction, got %d deltas", log.Size())
+	}
+	if cp.DeltaCount != 3 {
+		t.Errorf("DeltaCount = %d, want 3", cp.DeltaCount)
+	}
+	if !reflect.DeepEqual(log.Replay(), before) {
+		t.Errorf("replay changed after compaction: %+v vs %+v", log.Replay(), before)
+	}
+
+	log.Append(a.lockReleased("lock-1"))
+	state := log.Replay()
+	if state.Files["main.go"] != "h2" {
+		t.Errorf("expected main.go hash h2, got %q", state.Files["main.go"])
+	}
+	if len(state.Locks) != 0 {
+		t.Errorf("expected lock released after tail replay, got %v", state.Locks)
+	}
+}
+
+func TestDeltaLog_IgnoresDeltasCoveredByCheckpoint(t *testing.T) {
+	a := newDeltaSource("agent-a")
+	log := NewDeltaLog(100)
+
+	old := a.fileChange("main.go", "h1")
+	log.Append(old)
+	log.Compact()
+
+	// Redelivery of a compacted delta must not reappear in the tail
+	log.Append(old)
+	if log.Size() != 0 {
+		t.Errorf("expected compacted delta to be ignored, got %d deltas", log.Size())
+	}
+
+	log.Append(a.fileChange("main.go", "h2"))
+	if log.Size() != 1 {
+		t.Errorf("expected new delta in tail, got %d deltas", log.Size())
+	}
+}
+
+func TestSyncManager_LateJoinerAfterCompaction(t *testing.T) {
+	a := newDeltaSource("agent-a")
+	b := newDeltaSource("agent-b")
+
+	leader := NewSyncManager("leader", "Leader")
+	receiveAll(t, leader,
+		a.fileChange("auth.go", "a1"),
+		b.lockAcquired("lock-1", "auth.go:Login"),
+		a.status("busy"),
+		b.fileChange("db.go", "b1"),
+		b.lockReleased("lock-1"),
+		a.lockAcquired("lock-2", "db.go:Migrate"),
+	)
+
+	leader.Compact()
+
+	receiveAll(t, leader,
+		a.fileChange("auth.go", "a2"),
+		b.status("idle"),
+		b.lockAcquired("lock-3", "auth.go:Logout"),
+	)
+
+	if stats := leader.GetStats(); stats.TotalDeltas != 3 || stats.CompactedDeltas != 6 {
+		t.Errorf("unexpected stats after compaction: total=%d compacted=%d", stats.TotalDeltas, stats.CompactedDeltas)
+	}
+
+	joiner := NewSyncManager("joiner", "Joiner")
+	resp := leader.HandleSyncRequest(&SyncRequest{
+		RequestorID:    "joiner",
+		LastKnownClock: NewVectorClock(),
+	})
+	if resp.Checkpoint == nil {
+		t.Fatal("expected checkpoint for late joiner")
+	}
+	if len(resp.Deltas) != 3 {
+		t.Errorf("expected 3 tail deltas, got %d", len(resp.Deltas))
+	}
+
+	if err := joiner.ApplySyncResponse(resp); err != nil {
+		t.Fatalf("ApplySyncResponse failed: %v", err)
+	}
+
+	want := &SyncState{
+		Files: map[string]string{"auth.go": "a2", "db.go": "b1"},
+		Locks: map[string]*LockState{
+			"lock-2": {LockID: "lock-2", HolderID: "agent-a", HolderName: "agent-a", TargetDesc: "db.go:Migrate", Intention: "edit"},
+			"lock-3": {LockID: "lock-3", HolderID: "agent-b", HolderName: "agent-b", TargetDesc: "auth.go:Logout", Intention: "edit"},
+		},
+		Agents: map[string]string{"agent-a": "busy", "agent-b": "idle"},
+	}
+	if got := joiner.GetState(); !reflect.DeepEqual(got, want) {
+		t.Errorf("joiner state mismatch:\ngot  %+v\nwant %+v", got, want)
+	}
+	if got := leader.GetState(); !reflect.DeepEqual(got, want) {
+		t.Errorf("leader state mismatch:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestSyncManager_UpToDatePeerSkipsCheckpoint(t *testing.T) {
+	a := newDeltaSource("agent-a")
+
+	leader := NewSyncManager("leader", "Leader")
+	receiveAll(t, leader, a.fileChange("main.go", "h1"))
+	cp := leader.Compact()
+
+	tail := a.fileChange("main.go", "h2")
+	receiveAll(t, leader, tail)
+
+	resp := leader.HandleSyncRequest(&SyncRequest{
+		RequestorID:    "peer",
+		LastKnownClock: cp.VectorClock,
+	})
+	if resp.Checkpoint != nil {
+		t.Error("peer past the checkpoint should not receive it")
+	}
+	if len(resp.Deltas) != 1 || resp.Deltas[0].ID != tail.ID {
+		t.Errorf("expected only the tail delta, got %d deltas", len(resp.Deltas))
+	}
+}
+
+func TestSyncManager_ExportRestoresState(t *testing.T) {
+	a := newDeltaSource("agent-a")
+
+	leader := NewSyncManager("leader", "Leader")
+	receiveAll(t, leader,
+		a.fileChange("main.go", "h1"),
+		a.lockAcquired("lock-1", "main.go:Run"),
+	)
+	leader.Compact()
+	receiveAll(t, leader, a.fileChange("main.go", "h2"))
+
+	exported := leader.Export()
+	if exported.Checkpoint == nil || len(exported.Deltas) != 1 {
+		t.Fatalf("expected the checkpoint and one tail delta, got %+v", exported)
+	}
+
+	restored := NewSyncManager("restored", "Restored")
+	if err := restored.ApplySyncResponse(exported); err != nil {
+		t.Fatalf("ApplySyncResponse failed: %v", err)
+	}
+	if got, want := restored.GetState(), leader.GetState(); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored state mismatch:\ngot  %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,190 @@
+package ctxsync
+
+import (
+	"time"
+)
+
+// DefaultDeltaRetention은 델타를 로그에 남겨 두는 기본 기간입니다.
+const DefaultDeltaRetention = 24 * time.Hour
+
+// CompactionStats는 델타 로그 압축 통계입니다.
+type CompactionStats struct {
+	// Runs는 로그를 줄인 압축 횟수입니다.
+	Runs int `json:"runs"`
+	// SupersededDeltas는 같은 파일의 이후 변경에 대체되어 체크포인트로 접힌 델타 수입니다.
+	SupersededDeltas int `json:"superseded_deltas"`
+	// ExpiredDeltas는 보존 기간이 지나 체크포인트로 접힌 델타 수입니다.
+	ExpiredDeltas int `json:"expired_deltas"`
+	// OldestDelta는 로그에 남은 가장 오래된 델타의 시각입니다.
+	OldestDelta time.Time `json:"oldest_delta,omitzero"`
+	LastRunAt   time.Time `json:"last_run_at,omitzero"`
+}
+
+// Fold는 selected가 고른 델타를 체크포인트에 접고 로그에서 제거합니다.
+// 접은 델타의 벡터 클럭에 이미 포함된 델타도 함께 접어, 체크포인트가
+// 덮는다는 이유로 Restore나 다른 피어에서 버려지는 델타가 상태에서
+// 빠지지 않게 합니다. 접힌 델타를 로그 순서대로 반환합니다.
+func (dl *DeltaLog) Fold(selected func(*Delta) bool) []*Delta {
+	clock := NewVectorClock()
+	if dl.checkpoint != nil {
+		clock = dl.checkpoint.VectorClock.Clone()
+	}
+
+	fold := make([]bool, len(dl.deltas))
+	picked := false
+	for i, delta := range dl.deltas {
+		if selected(delta) {
+			fold[i] = true
+			clock.Merge(delta.VectorClock)
+			picked = true
+		}
+	}
+	if !picked {
+		return nil
+	}
+	// 포함된 델타는 클럭을 바꾸지 않으므로 한 번 훑으면 충분합니다
+	for i, delta := range dl.deltas {
+		if !fold[i] && clockIncludes(clock, delta.VectorClock) {
+			fold[i] = true
+		}
+	}
+
+	cp := dl.checkpoint.clone()
+	if cp == nil {
+		cp = &Checkpoint{State: NewSyncState()}
+	}
+
+	var folded []*Delta
+	kept := make([]*Delta, 0, len(dl.deltas))
+	for i, delta := range dl.deltas {
+		if fold[i] {
+			cp.State.Apply(delta)
+			folded = append(folded, delta)
+		} else {
+			kept = append(kept, delta)
+		}
+	}
+	cp.VectorClock = clock
+	cp.DeltaCount += len(folded)
+	cp.CreatedAt = time.Now()
+
+	dl.checkpoint = cp
+	dl.deltas = make([]*Delta, 0, len(kept))
+	dl.byID = make(map[string]*Delta)
+	dl.bySource = make(map[string][]*Delta)
+	for _, delta := range kept {
+		dl.deltas = append(dl.deltas, delta)
+		dl.byID[delta.ID] = delta
+		dl.bySource[delta.SourceID] = append(dl.bySource[delta.SourceID], delta)
+	}
+	return folded
+}
+
+// supersededDeltas는 같은 파일의 이후 변경이 인과적으로 뒤따르는 파일
+// 변경 델타를 반환합니다. 동시 변경은 충돌 감지를 위해 남겨 둡니다.
+func supersededDeltas(deltas []*Delta) map[string]bool {
+	byFile := make(map[string][]*Delta)
+	for _, delta := range deltas {
+		if delta.Type == DeltaFileChange && delta.Payload != nil && delta.Payload.FilePath != "" {
+			byFile[delta.Payload.FilePath] = append(byFile[delta.Payload.FilePath], delta)
+		}
+	}
+
+	superseded := make(map[string]bool)
+	for _, changes := range byFile {
+		for i, earlier := range changes {
+			for _, later := range changes[i+1:] {
+				if later.VectorClock.HappensAfter(earlier.VectorClock) {
+					superseded[earlier.ID] = true
+					break
+				}
+			}
+		}
+	}
+	return superseded
+}
+
+// RunCompaction은 대체된 파일 델타와 보존 기간이 지난 델타를 체크포인트로
+// 접고, 남은 델타가 MinDeltas 이상이면 전부 접습니다. 로그가 줄었으면
+// 압축 콜백에 압축된 로그를 넘기고 true를 반환합니다.
+func (sm *SyncManager) RunCompaction(now time.Time) bool {
+	sm.mu.Lock()
+	cfg := sm.compaction
+
+	superseded := supersededDeltas(sm.deltaLog.deltas)
+	expired := func(delta *Delta) bool {
+		return cfg.Retention > 0 && delta.Timestamp.Before(now.Add(-cfg.Retention))
+	}
+	folded := sm.deltaLog.Fold(func(delta *Delta) bool {
+		return superseded[delta.ID] || expired(delta)
+	})
+	for _, delta := range folded {
+		if expired(delta) {
+			sm.compactionStats.ExpiredDeltas++
+		} else {
+			sm.compactionStats.SupersededDeltas++
+		}
+	}
+
+	changed := len(folded) > 0
+	if cfg.MinDeltas > 0 && sm.deltaLog.Size() >= cfg.MinDeltas {
+		sm.deltaLog.Compact()
+		changed = true
+	}
+	if changed {
+		sm.compactionStats.Runs++
+		sm.compactionStats.LastRunAt = now
+	}
+	onCompacted := sm.onCompacted
+	sm.mu.Unlock()
+
+	if changed && onCompacted != nil {
+		onCompacted(sm.Export())
+	}
+	return changed
+}
+
+// SetCompactedFn은 압축 후 압축된 로그를 받을 함수를 설정합니다.
+// 재시작 후 Import로 복원할 수 있게 저장하는 데 사용합니다.
+func (sm *SyncManager) SetCompactedFn(fn func(*SyncResponse)) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.onCompacted = fn
+}
+
+// Import는 Export로 저장한 로그를 다시 설치합니다. ApplySyncResponse와
+// 달리 델타를 원격 델타로 처리하지 않으므로 자기 델타를 피어로 기록하거나
+// 충돌로 보고하지 않습니다. 벡터 클럭도 복원해, 재시작 후 만든 델타가
+// 이전 델타에 덮이지 않게 합니다.
+func (sm *SyncManager) Import(snap *SyncResponse) {
+	if snap == nil {
+		return
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if snap.Checkpoint != nil {
+		sm.deltaLog.Restore(snap.Checkpoint)
+		sm.vectorClock.Merge(snap.Checkpoint.VectorClock)
+	}
+	sm.files.MergeMap(snap.Files, time.Now())
+	for _, delta := range snap.Deltas {
+		sm.deltaLog.Append(delta)
+		sm.vectorClock.Merge(delta.VectorClock)
+	}
+	if snap.CurrentClock != nil {
+		sm.vectorClock.Merge(snap.CurrentClock)
+	}
+}
+
+// compactionStatsLocked는 압축 통계를 반환합니다. sm.mu를 잡고 호출해야 합니다.
+func (sm *SyncManager) compactionStatsLocked() CompactionStats {
+	stats := sm.compactionStats
+	for _, delta := range sm.deltaLog.deltas {
+		if stats.OldestDelta.IsZero() || delta.Timestamp.Before(stats.OldestDelta) {
+			stats.OldestDelta = delta.Timestamp
+		}
+	}
+	return stats
+}
@@ -0,0 +1,101 @@
+package ctxsync
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSyncManager_RunCompactionFoldsSupersededDeltas(t *testing.T) {
+	a := newDeltaSource("agent-a")
+	b := newDeltaSource("agent-b")
+	sm := NewSyncManager("node-local", "local")
+	sm.SetCompactionConfig(CompactionConfig{})
+
+	first := a.fileChange("main.go", "h1")
+	receiveAll(t, sm,
+		first,
+		a.lockAcquired("lock-1", "main.go:Run"),
+		a.fileChange("main.go", "h2"),
+		b.fileChange("main.go", "h3"), // concurrent with agent-a's changes
+	)
+	before := sm.GetState()
+
+	if !sm.RunCompaction(time.Now()) {
+		t.Fatal("expected the superseded change to be compacted")
+	}
+
+	stats := sm.GetStats()
+	if stats.TotalDeltas != 3 || stats.Compaction.SupersededDeltas != 1 || stats.Compaction.Runs != 1 {
+		t.Errorf("expected one superseded delta folded, got %d deltas and %+v", stats.TotalDeltas, stats.Compaction)
+	}
+	if _, ok := sm.deltaLog.Get(first.ID); ok {
+		t.Error("the superseded change should leave the log")
+	}
+	if !reflect.DeepEqual(sm.GetState(), before) {
+		t.Errorf("compaction changed the state:\nbefore %+v\nafter  %+v", before, sm.GetState())
+	}
+
+	if sm.RunCompaction(time.Now()) {
+		t.Error("a second run with nothing superseded should not compact")
+	}
+}
+
+func TestSyncManager_RunCompactionPrunesExpiredDeltas(t *testing.T) {
+	a := newDeltaSource("agent-a")
+	b := newDeltaSource("agent-b")
+	sm := NewSyncManager("node-local", "local")
+	sm.SetCompactionConfig(CompactionConfig{Retention: time.Hour})
+
+	now := time.Now()
+	old := a.lockAcquired("lock-1", "main.go:Run")
+	old.Timestamp = now.Add(-2 * time.Hour)
+	// Arrived late but happened before old, so folding old covers it
+	earlier := b.status("online")
+	a.clock.Merge(b.clock)
+	old.VectorClock.Merge(b.clock)
+	recent := a.lockReleased("lock-1")
+	receiveAll(t, sm, old, earlier, recent)
+	before := sm.GetState()
+
+	sm.RunCompaction(now)
+
+	stats := sm.GetStats()
+	if stats.TotalDeltas != 1 || stats.Compaction.ExpiredDeltas != 1 || stats.Compaction.SupersededDeltas != 1 {
+		t.Errorf("expected the expired delta and the one it covers folded, got %d deltas and %+v",
+			stats.TotalDeltas, stats.Compaction)
+	}
+	if !stats.Compaction.OldestDelta.Equal(recent.Timestamp) {
+		t.Errorf("OldestDelta = %v, want %v", stats.Compaction.OldestDelta, recent.Timestamp)
+	}
+	if !reflect.DeepEqual(sm.GetState(), before) {
+		t.Errorf("compaction changed the state:\nbefore %+v\nafter  %+v", before, sm.GetState())
+	}
+}
+
+func TestSyncManager_ImportRestoresCompactedLog(t *testing.T) {
+	a := newDeltaSource("node-local")
+	sm := NewSyncManager("node-local", "local")
+	sm.SetCompactionConfig(CompactionConfig{})
+
+	var saved *SyncResponse
+	sm.SetCompactedFn(func(snap *SyncResponse) { saved = snap })
+	receiveAll(t, sm, a.fileChange("main.go", "h1"), a.fileChange("main.go", "h2"))
+	sm.RunCompaction(time.Now())
+	if saved == nil || saved.Checkpoint == nil {
+		t.Fatal("expected the compacted log to be handed to the callback")
+	}
+
+	restarted := NewSyncManager("node-local", "local")
+	restarted.Import(saved)
+
+	if !reflect.DeepEqual(restarted.GetState(), sm.GetState()) {
+		t.Errorf("restored state differs:\nwant %+v\ngot  %+v", sm.GetState(), restarted.GetState())
+	}
+	if got, want := restarted.GetVectorClock().Get("node-local"), sm.GetVectorClock().Get("node-local"); got < want {
+		t.Errorf("restored clock %d is behind %d", got, want)
+	}
+	if len(restarted.GetPeers()) != 0 {
+		t.Errorf("importing its own log should not register peers, got %d", len(restarted.GetPeers()))
+	}
+}
@@ -0,0 +1,153 @@
+package ctxsync
+
+import (
+	"maps"
+	"time"
+)
+
+// MergeReason은 동시 버전 중 승자를 정한 기준입니다.
+type MergeReason string
+
+const (
+	// MergeByTimestamp는 더 늦은 타임스탬프가 이긴 경우입니다.
+	MergeByTimestamp MergeReason = "timestamp"
+	// MergeBySource는 타임스탬프가 같아 더 큰 소스 ID가 이긴 경우입니다.
+	MergeBySource MergeReason = "source_id"
+	// MergeByDeltaID는 소스까지 같아 더 큰 델타 ID가 이긴 경우입니다.
+	MergeByDeltaID MergeReason = "delta_id"
+)
+
+// DefaultMergeHistorySize는 보관하는 병합 기록 수입니다.
+const DefaultMergeHistorySize = 500
+
+// FileVersion은 파일 요약의 한 버전입니다.
+type FileVersion struct {
+	DeltaID     string       `json:"delta_id"`
+	SourceID    string       `json:"source_id"`
+	SourceName  string       `json:"source_name,omitempty"`
+	Hash        string       `json:"hash"`
+	VectorClock *VectorClock `json:"vector_clock"`
+	Timestamp   time.Time    `json:"timestamp"`
+}
+
+// fileVersionOf는 파일 변경 델타의 버전을 반환합니다.
+// 파일 변경이 아니면 nil입니다.
+func fileVersionOf(delta *Delta) *FileVersion {
+	if delta == nil || delta.Type != DeltaFileChange || delta.Payload == nil || delta.Payload.FilePath == "" {
+		return nil
+	}
+	vc := NewVectorClock()
+	if delta.VectorClock != nil {
+		vc = delta.VectorClock.Clone()
+	}
+	return &FileVersion{
+		DeltaID:     delta.ID,
+		SourceID:    delta.SourceID,
+		SourceName:  delta.SourceName,
+		Hash:        delta.Payload.FileHash,
+		VectorClock: vc,
+		Timestamp:   delta.Timestamp,
+	}
+}
+
+// beats는 동시 버전 v가 other를 이기는지와 그 기준을 반환합니다.
+// 모든 피어가 같은 기준을 쓰므로 수신 순서와 무관하게 같은 승자가 나옵니다.
+func (v *FileVersion) beats(other *FileVersion) (bool, MergeReason) {
+	if !v.Timestamp.Equal(other.Timestamp) {
+		return v.Timestamp.After(other.Timestamp), MergeByTimestamp
+	}
+	if v.SourceID != other.SourceID {
+		return v.SourceID > other.SourceID, MergeBySource
+	}
+	return v.DeltaID > other.DeltaID, MergeByDeltaID
+}
+
+// MergeRecord는 동시 수정을 병합한 기록입니다.
+type MergeRecord struct {
+	FilePath string       `json:"file_path"`
+	Winner   *FileVersion `json:"winner"`
+	Loser    *FileVersion `json:"loser"`
+	Reason   MergeReason  `json:"reason"`
+	MergedAt time.Time    `json:"merged_at"`
+}
+
+// FileMap은 파일 경로별 LWW(last-writer-wins) 레지스터 맵 CRDT입니다.
+// 인과적으로 뒤선 버전이 앞선 버전을 대체하고, 동시 버전은 타임스탬프,
+// 소스 ID, 델타 ID 순으로 결정적으로 고릅니다. 병합은 교환·결합·멱등
+// 법칙을 만족하므로 피어들은 델타를 어떤 순서로 받아도 같은 상태에 수렴합니다.
+type FileMap struct {
+	Versions map[string]*FileVersion `json:"versions"`
+}
+
+// NewFileMap은 빈 파일 맵을 생성합니다.
+func NewFileMap() *FileMap {
+	return &FileMap{Versions: make(map[string]*FileVersion)}
+}
+
+// Merge는 파일 버전 하나를 병합합니다. 동시 버전 사이에서 승자를
+// 골랐을 때만 병합 기록을 반환합니다.
+func (m *FileMap) Merge(filePath string, v *FileVersion, now time.Time) *MergeRecord {
+	if v == nil || v.VectorClock == nil {
+		return nil
+	}
+	current, ok := m.Versions[filePath]
+	if !ok {
+		m.Versions[filePath] = v
+		return nil
+	}
+	if current.DeltaID == v.DeltaID && current.SourceID == v.SourceID {
+		return nil
+	}
+
+	switch v.VectorClock.Compare(current.VectorClock) {
+	case 1:
+		m.Versions[filePath] = v
+		return nil
+	case -1:
+		return nil
+	}
+
+	// 동시 수정 (또는 같은 클럭의 다른 델타)
+	record := &MergeRecord{FilePath: filePath, Winner: current, Loser: v, MergedAt: now}
+	wins, reason := v.beats(current)
+	record.Reason = reason
+	if wins {
+		m.Versions[filePath] = v
+		record.Winner, record.Loser = v, current
+	}
+	return record
+}
+
+// MergeMap은 다른 파일 맵 전체를 병합합니다 (상태 기반 병합).
+func (m *FileMap) MergeMap(other *FileMap, now time.Time) []*MergeRecord {
+	if other == nil {
+		return nil
+	}
+	var records []*MergeRecord
+	for filePath, v := range other.Versions {
+		if record := m.Merge(filePath, v, now); record != nil {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// Get은 파일의 현재 버전을 반환합니다.
+func (m *FileMap) Get(filePath string) (*FileVersion, bool) {
+	v, ok := m.Versions[filePath]
+	return v, ok
+}
+
+// Hashes는 파일 경로별 현재 해시를 반환합니다.
+func (m *FileMap) Hashes() map[string]string {
+	hashes := make(map[string]string, len(m.Versions))
+	for filePath, v := range m.Versions {
+		hashes[filePath] = v.Hash
+	}
+	return hashes
+}
+
+// Clone은 파일 맵을 복제합니다. 버전은 변경되지 않으므로 공유합니다.
+func (m *FileMap) Clone() *FileMap {
+	return &FileMap{Versions: maps.Clone(m.Versions)}
+}
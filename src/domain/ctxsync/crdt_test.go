@@ -0,0 +1,135 @@
+package ctxsync
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// concurrentEdits returns three concurrent edits of main.go. b and c share
+// a timestamp so the tie-break falls through to the source ID.
+func concurrentEdits() []*Delta {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := newDeltaSource("agent-a").fileChange("main.go", "ha")
+	a.Timestamp = at
+	b := newDeltaSource("agent-b").fileChange("main.go", "hb")
+	b.Timestamp = at.Add(time.Second)
+	c := newDeltaSource("agent-c").fileChange("main.go", "hc")
+	c.Timestamp = at.Add(time.Second)
+	return []*Delta{a, b, c}
+}
+
+func TestFileMap_ConcurrentEditsConverge(t *testing.T) {
+	edits := concurrentEdits()
+	orders := [][]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+
+	for _, order := range orders {
+		sm := NewSyncManager("observer", "Observer")
+		for _, i := range order {
+			receiveAll(t, sm, edits[i])
+		}
+
+		if got := sm.GetState().Files["main.go"]; got != "hc" {
+			t.Errorf("order %v: expected hc to win, got %q", order, got)
+		}
+		if merges := sm.MergeHistory("main.go", 0); len(merges) != 2 {
+			t.Errorf("order %v: expected 2 recorded merges, got %d", order, len(merges))
+		}
+	}
+}
+
+func TestFileMap_TieBreakReasons(t *testing.T) {
+	edits := concurrentEdits()
+	m := NewFileMap()
+	now := time.Now()
+
+	m.Merge("main.go", fileVersionOf(edits[0]), now)
+	record := m.Merge("main.go", fileVersionOf(edits[1]), now)
+	if record == nil || record.Reason != MergeByTimestamp || record.Winner.SourceID != "agent-b" {
+		t.Fatalf("expected agent-b to win by timestamp, got %+v", record)
+	}
+	record = m.Merge("main.go", fileVersionOf(edits[2]), now)
+	if record == nil || record.Reason != MergeBySource || record.Winner.SourceID != "agent-c" || record.Loser.SourceID != "agent-b" {
+		t.Fatalf("expected agent-c to win by source ID, got %+v", record)
+	}
+
+	// Redelivery and causally older versions are not merges
+	if record := m.Merge("main.go", fileVersionOf(edits[2]), now); record != nil {
+		t.Errorf("redelivery should not be recorded, got %+v", record)
+	}
+}
+
+func TestFileMap_CausalSuccessorReplaces(t *testing.T) {
+	a := newDeltaSource("agent-a")
+	first := a.fileChange("main.go", "h1")
+	second := a.fileChange("main.go", "h2")
+	// A later edit wins even with an older wall clock
+	second.Timestamp = first.Timestamp.Add(-time.Hour)
+
+	sm := NewSyncManager("observer", "Observer")
+	receiveAll(t, sm, second, first)
+
+	if v, _ := sm.FileVersion("main.go"); v == nil || v.Hash != "h2" {
+		t.Errorf("expected the causally later h2, got %+v", v)
+	}
+	if merges := sm.MergeHistory("", 0); len(merges) != 0 {
+		t.Errorf("causal order is not a merge, got %d records", len(merges))
+	}
+}
+
+func TestSyncManager_ConflictCarriesResolution(t *testing.T) {
+	edits := concurrentEdits()
+	local := NewSyncManager("agent-a", "agent-a")
+	// Record agent-a's own edit as local
+	receiveAll(t, local, edits[0])
+
+	var conflicts []*Conflict
+	local.SetConflictHandler(func(c *Conflict) error {
+		conflicts = append(conflicts, c)
+		return nil
+	})
+	receiveAll(t, local, edits[1])
+
+	if len(conflicts) != 1 || conflicts[0].Resolution == nil || conflicts[0].Resolution.Winner.SourceID != "agent-b" {
+		t.Fatalf("expected one conflict resolved for agent-b, got %+v", conflicts)
+	}
+}
+
+func TestSyncManager_LateJoinerGetsCompactedFileVersions(t *testing.T) {
+	edits := concurrentEdits()
+	leader := NewSyncManager("leader", "Leader")
+	receiveAll(t, leader, edits...)
+	leader.Compact()
+
+	joiner := NewSyncManager("joiner", "Joiner")
+	// The joiner saw agent-a's edit before the leader compacted
+	receiveAll(t, joiner, edits[0])
+	resp := leader.HandleSyncRequest(&SyncRequest{RequestorID: "joiner", LastKnownClock: NewVectorClock()})
+	if resp.Files == nil {
+		t.Fatal("expected the file CRDT state with the checkpoint")
+	}
+	if err := joiner.ApplySyncResponse(resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := joiner.GetState().Files, leader.GetState().Files; !reflect.DeepEqual(got, want) {
+		t.Errorf("joiner files %v, want %v", got, want)
+	}
+	if merges := joiner.MergeHistory("main.go", 1); len(merges) != 1 || merges[0].Winner.SourceID != "agent-c" {
+		t.Errorf("expected the joiner to record merging in agent-c's version, got %+v", merges)
+	}
+}
+
+func TestSyncManager_MergeHistoryBounded(t *testing.T) {
+	sm := NewSyncManager("observer", "Observer")
+	sm.mergeLimit = 2
+	a, b := newDeltaSource("agent-a"), newDeltaSource("agent-b")
+	for _, path := range []string{"a.go", "b.go", "c.go"} {
+		receiveAll(t, sm, a.fileChange(path, "ha"), b.fileChange(path, "hb"))
+	}
+
+	merges := sm.MergeHistory("", 0)
+	if len(merges) != 2 || merges[0].FilePath != "c.go" || merges[1].FilePath != "b.go" {
+		t.Errorf("expected the 2 newest merges, newest first, got %d", len(merges))
+	}
+}
@@ -36,6 +36,8 @@ type DeltaPayload struct {
 	FilePath string        `json:"file_path,omitempty"`
 	FileDiff *ast.FileDiff `json:"file_diff,omitempty"`
 	FileHash string        `json:"file_hash,omitempty"`
+	// 큰 diff는 FileDiff 대신 요약만 전송됩니다 (SyncManager.FetchDiff로 조회)
+	DiffSummary *DiffSummary `json:"diff_summary,omitempty"`
 
 	// 락 정보
 	LockID     string `json:"lock_id,omitempty"`
@@ -114,6 +116,9 @@ type DeltaLog struct {
 	maxSize  int
 	byID     map[string]*Delta
 	bySource map[string][]*Delta
+
+	// checkpoint는 압축된 델타들의 스냅샷입니다.
+	checkpoint *Checkpoint
 }
 
 // NewDeltaLog는 새 델타 로그를 생성합니다.
@@ -136,6 +141,10 @@ func (dl *DeltaLog) Append(delta *Delta) {
 	if _, exists := dl.byID[delta.ID]; exists {
 		return
 	}
+	// Already folded into the checkpoint
+	if dl.checkpoint.Covers(delta.VectorClock) {
+		return
+	}
 
 	dl.deltas = append(dl.deltas, delta)
 	dl.byID[delta.ID] = delta
@@ -203,4 +212,70 @@ func (dl *DeltaLog) Clear() {
 	dl.deltas = make([]*Delta, 0)
 	dl.byID = make(map[string]*Delta)
 	dl.bySource = make(map[string][]*Delta)
+	dl.checkpoint = nil
+}
+
+// Compact는 현재 델타들을 체크포인트로 접고 로그에서 제거합니다.
+// 압축할 델타가 없으면 기존 체크포인트를 그대로 반환합니다.
+func (dl *DeltaLog) Compact() *Checkpoint {
+	if len(dl.deltas) == 0 {
+		return dl.checkpoint.clone()
+	}
+
+	cp := dl.checkpoint.clone()
+	if cp == nil {
+		cp = &Checkpoint{
+			VectorClock: NewVectorClock(),
+			State:       NewSyncState(),
+		}
+	}
+
+	for _, delta := range dl.deltas {
+		cp.State.Apply(delta)
+		cp.VectorClock.Merge(delta.VectorClock)
+	}
+	cp.DeltaCount += len(dl.deltas)
+	cp.CreatedAt = time.Now()
+
+	dl.deltas = make([]*Delta, 0)
+	dl.byID = make(map[string]*Delta)
+	dl.bySource = make(map[string][]*Delta)
+	dl.checkpoint = cp
+
+	return cp.clone()
+}
+
+// Checkpoint는 마지막 체크포인트를 반환합니다. 없으면 nil입니다.
+func (dl *DeltaLog) Checkpoint() *Checkpoint {
+	return dl.checkpoint.clone()
+}
+
+// Restore는 원격 체크포인트를 설치합니다.
+// 체크포인트에 포함된 델타는 로그에서 제거됩니다.
+func (dl *DeltaLog) Restore(cp *Checkpoint) {
+	if cp == nil {
+		return
+	}
+
+	dl.checkpoint = cp.clone()
+
+	tail := dl.deltas
+	dl.deltas = make([]*Delta, 0, len(tail))
+	dl.byID = make(map[string]*Delta)
+	dl.bySource = make(map[string][]*Delta)
+	for _, delta := range tail {
+		dl.Append(delta)
+	}
+}
+
+// Replay는 체크포인트 상태에 이후 델타를 적용한 현재 상태를 반환합니다.
+func (dl *DeltaLog) Replay() *SyncState {
+	state := NewSyncState()
+	if dl.checkpoint != nil {
+		state = dl.checkpoint.State.Clone()
+	}
+	for _, delta := range dl.deltas {
+		state.Apply(delta)
+	}
+	return state
 }
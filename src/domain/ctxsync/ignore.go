@@ -0,0 +1,263 @@
+package ctxsync
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// gitignoreFile는 디렉토리별 무시 규칙 파일 이름입니다.
+const gitignoreFile = ".gitignore"
+
+// ignoreRule은 .gitignore 형식의 규칙 하나입니다.
+type ignoreRule struct {
+	// base는 규칙이 적용되는 디렉토리입니다 (루트 기준 상대 경로, 루트는 "")
+	base    string
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// IgnoreMatcher는 .gitignore 형식의 규칙으로 경로를 걸러냅니다.
+// .gitignore 규칙은 얕은 디렉토리부터, 설정 규칙은 그 뒤에 적용되며
+// 마지막으로 일치한 규칙이 우선합니다 (설정에서 '!'로 다시 포함 가능).
+// 무시된 디렉토리 아래의 경로는 모두 무시됩니다.
+// 경로는 루트 기준 상대 경로이고 구분자는 '/'입니다.
+type IgnoreMatcher struct {
+	mu         sync.RWMutex
+	gitignores map[string][]ignoreRule // 디렉토리 -> 그 .gitignore 규칙
+	patterns   []ignoreRule
+	rules      []ignoreRule // 적용 순서로 합친 규칙
+}
+
+// NewIgnoreMatcher는 설정 규칙으로 매처를 생성합니다.
+func NewIgnoreMatcher(patterns []string) (*IgnoreMatcher, error) {
+	// .git 디렉토리는 항상 무시
+	rules, err := parseIgnoreRules("", append([]string{".git/"}, patterns...))
+	if err != nil {
+		return nil, err
+	}
+	m := &IgnoreMatcher{
+		gitignores: make(map[string][]ignoreRule),
+		patterns:   rules,
+	}
+	m.rebuild()
+	return m, nil
+}
+
+// LoadGitignore는 root 아래 dir의 .gitignore를 읽어 그 디렉토리의 규칙을
+// 교체합니다. 파일이 없으면 규칙을 지웁니다.
+func (m *IgnoreMatcher) LoadGitignore(root, dir string) error {
+	base, err := relSlash(root, dir)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G304 - path is a .gitignore inside the watched project
+	data, err := os.ReadFile(filepath.Join(dir, gitignoreFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	rules, err := parseIgnoreRules(base, lines)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Join(dir, gitignoreFile), err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(rules) == 0 {
+		delete(m.gitignores, base)
+	} else {
+		m.gitignores[base] = rules
+	}
+	m.rebuild()
+	return nil
+}
+
+// rebuild는 적용 순서로 규칙을 합칩니다. m.mu를 잡은 상태에서 호출합니다.
+func (m *IgnoreMatcher) rebuild() {
+	depth := func(base string) int {
+		if base == "" {
+			return -1
+		}
+		return strings.Count(base, "/")
+	}
+	bases := slices.Collect(maps.Keys(m.gitignores))
+	slices.SortFunc(bases, func(a, b string) int {
+		return cmp.Or(cmp.Compare(depth(a), depth(b)), strings.Compare(a, b))
+	})
+
+	m.rules = m.rules[:0]
+	for _, base := range bases {
+		m.rules = append(m.rules, m.gitignores[base]...)
+	}
+	m.rules = append(m.rules, m.patterns...)
+}
+
+// Ignored는 경로가 무시되는지 반환합니다. isDir은 경로가 디렉토리인지입니다.
+func (m *IgnoreMatcher) Ignored(relPath string, isDir bool) bool {
+	relPath = strings.Trim(relPath, "/")
+	if relPath == "" || relPath == "." {
+		return false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// 상위 디렉토리가 무시되면 그 아래도 무시
+	for i := range len(relPath) {
+		if relPath[i] == '/' && m.match(relPath[:i], true) {
+			return true
+		}
+	}
+	return m.match(relPath, isDir)
+}
+
+// match는 마지막으로 일치한 규칙의 결과를 반환합니다.
+func (m *IgnoreMatcher) match(relPath string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		sub := relPath
+		if r.base != "" {
+			rest, ok := strings.CutPrefix(relPath, r.base+"/")
+			if !ok {
+				continue
+			}
+			sub = rest
+		}
+		if r.re.MatchString(sub) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// parseIgnoreRules는 .gitignore 줄들을 base 디렉토리의 규칙으로 바꿉니다.
+func parseIgnoreRules(base string, lines []string) ([]ignoreRule, error) {
+	var rules []ignoreRule
+	for _, line := range lines {
+		rule, ok, err := parseIgnoreRule(base, line)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// parseIgnoreRule은 .gitignore 한 줄을 규칙으로 바꿉니다.
+// 빈 줄과 주석이면 ok가 false입니다.
+func parseIgnoreRule(base, line string) (rule ignoreRule, ok bool, err error) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false, nil
+	}
+
+	rule.base = base
+	if rest, found := strings.CutPrefix(line, "!"); found {
+		rule.negate = true
+		line = rest
+	}
+	line = strings.TrimPrefix(line, `\`)
+	if rest, found := strings.CutSuffix(line, "/"); found {
+		rule.dirOnly = true
+		line = rest
+	}
+	if line == "" {
+		return ignoreRule{}, false, nil
+	}
+
+	// 중간이나 앞에 '/'가 있으면 base 기준 경로, 없으면 어느 깊이의 이름과도 일치
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := globToRegexp(line)
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return ignoreRule{}, false, fmt.Errorf("invalid ignore pattern %q: %w", line, err)
+	}
+	rule.re = re
+	return rule, true, nil
+}
+
+// globToRegexp는 .gitignore glob을 정규식으로 바꿉니다.
+// '**'는 여러 디렉토리, '*'와 '?'는 '/'를 제외한 문자와 일치합니다.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// relSlash는 root 기준 상대 경로를 '/' 구분자로 반환합니다. 루트는 ""입니다.
+func relSlash(root, p string) (string, error) {
+	rel, err := filepath.Rel(root, p)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		return "", nil
+	}
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%s is outside %s", p, root)
+	}
+	return path.Clean(rel), nil
+}
@@ -0,0 +1,71 @@
+package ctxsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".gitignore"), "# build output\n*.log\n/dist/\nnode_modules/\ndocs/**/*.tmp\n!keep.log\n")
+	writeFile(t, filepath.Join(root, "pkg", ".gitignore"), "generated.go\n")
+
+	m, err := NewIgnoreMatcher([]string{"vendor/", "!dist/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{root, filepath.Join(root, "pkg")} {
+		if err := m.LoadGitignore(root, dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{".git", true, true},
+		{".git/config", false, true},
+		{"main.go", false, false},
+		{"app.log", false, true},
+		{"sub/deep/app.log", false, true},
+		{"keep.log", false, false},
+		{"node_modules", true, true},
+		{"web/node_modules/react/index.js", false, true},
+		{"docs/a/b/notes.tmp", false, true},
+		{"notes.tmp", false, false},
+		{"pkg/generated.go", false, true},
+		{"generated.go", false, false},
+		{"vendor/lib/lib.go", false, true},
+		// The configured pattern re-includes what .gitignore excludes
+		{"dist/app.js", false, false},
+	}
+	for _, tt := range tests {
+		if got := m.Ignored(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// Removing a .gitignore drops its rules
+	if err := os.Remove(filepath.Join(root, "pkg", ".gitignore")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.LoadGitignore(root, filepath.Join(root, "pkg")); err != nil {
+		t.Fatal(err)
+	}
+	if m.Ignored("pkg/generated.go", false) {
+		t.Error("expected the rules of the removed .gitignore dropped")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,257 @@
+package ctxsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"agent-collab/src/domain/ast"
+)
+
+// DefaultMaxDiffBytes는 델타에 전체 diff를 싣는 최대 크기입니다.
+// 이보다 큰 diff는 요약과 해시만 전송하고 필요한 피어가 따로 가져갑니다.
+const DefaultMaxDiffBytes = 64 * 1024
+
+// maxSummarySymbols는 요약에 포함하는 심볼 이름 수입니다.
+const maxSummarySymbols = 20
+
+// maxHeldDiffs는 요청에 대비해 보관하는 전체 diff 수입니다.
+const maxHeldDiffs = 64
+
+// Diff fetch message types on the context sync topic.
+const (
+	MsgDiffRequest  = "diff_request"
+	MsgDiffResponse = "diff_response"
+)
+
+// DiffSummary는 크기 제한을 넘은 diff 대신 전송되는 요약입니다.
+type DiffSummary struct {
+	DiffHash      string   `json:"diff_hash"`
+	Size          int      `json:"size"`
+	AddedCount    int      `json:"added_count"`
+	RemovedCount  int      `json:"removed_count"`
+	ModifiedCount int      `json:"modified_count"`
+	Symbols       []string `json:"symbols,omitempty"`
+	Truncated     bool     `json:"truncated,omitempty"`
+}
+
+// DiffRequest asks peers for the full diff behind a summarized delta.
+type DiffRequest struct {
+	Type        string `json:"type"`
+	RequestorID string `json:"requestor_id"`
+	DeltaID     string `json:"delta_id"`
+	DiffHash    string `json:"diff_hash"`
+}
+
+// DiffResponse carries a full diff to the peer that asked for it.
+type DiffResponse struct {
+	Type        string        `json:"type"`
+	ResponderID string        `json:"responder_id"`
+	RequestorID string        `json:"requestor_id"`
+	DiffHash    string        `json:"diff_hash"`
+	Diff        *ast.FileDiff `json:"diff"`
+}
+
+// diffHash는 인코딩된 diff의 해시입니다.
+func diffHash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// summarizeDiff는 diff가 maxBytes를 넘으면 요약을 반환합니다.
+// 넘지 않으면 nil입니다.
+func summarizeDiff(diff *ast.FileDiff, maxBytes int) (*DiffSummary, error) {
+	if diff == nil || maxBytes <= 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) <= maxBytes {
+		return nil, nil
+	}
+
+	summary := &DiffSummary{
+		DiffHash:      diffHash(data),
+		Size:          len(data),
+		AddedCount:    diff.AddedCount,
+		RemovedCount:  diff.RemovedCount,
+		ModifiedCount: diff.ModifiedCount,
+	}
+	for _, d := range diff.Diffs {
+		if d.Symbol == nil {
+			continue
+		}
+		if len(summary.Symbols) == maxSummarySymbols {
+			summary.Truncated = true
+			break
+		}
+		summary.Symbols = append(summary.Symbols, fmt.Sprintf("%s %s", d.Type, d.Symbol.Name))
+	}
+	return summary, nil
+}
+
+// SetMaxDiffBytes는 델타에 전체 diff를 싣는 최대 크기를 설정합니다.
+// 0 이하이면 항상 전체 diff를 전송합니다.
+func (sm *SyncManager) SetMaxDiffBytes(n int) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.maxDiffBytes = n
+}
+
+// SetDiffRequestFn sets the function used to send diff fetch messages
+// (both requests and responses) to peers.
+func (sm *SyncManager) SetDiffRequestFn(fn func(msg any) error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.diffRequestFn = fn
+}
+
+// shrinkDelta는 큰 diff를 요약으로 교체하고 원본을 보관합니다.
+// sm.mu를 잡은 상태에서 호출해야 합니다.
+func (sm *SyncManager) shrinkDelta(delta *Delta) error {
+	summary, err := summarizeDiff(delta.Payload.FileDiff, sm.maxDiffBytes)
+	if err != nil || summary == nil {
+		return err
+	}
+
+	sm.holdDiff(summary.DiffHash, delta.Payload.FileDiff)
+	delta.Payload.FileDiff = nil
+	delta.Payload.DiffSummary = summary
+	return nil
+}
+
+// holdDiff는 전체 diff를 보관합니다. 오래된 것부터 버립니다.
+// sm.mu를 잡은 상태에서 호출해야 합니다.
+func (sm *SyncManager) holdDiff(hash string, diff *ast.FileDiff) {
+	if _, ok := sm.heldDiffs[hash]; ok {
+		return
+	}
+	sm.heldDiffs[hash] = diff
+	sm.heldOrder = append(sm.heldOrder, hash)
+	if len(sm.heldOrder) > maxHeldDiffs {
+		delete(sm.heldDiffs, sm.heldOrder[0])
+		sm.heldOrder = sm.heldOrder[1:]
+	}
+}
+
+// FetchDiff returns the full diff behind a summarized delta, asking peers
+// for it if this node does not hold it. It blocks until a peer answers or
+// ctx is done.
+func (sm *SyncManager) FetchDiff(ctx context.Context, delta *Delta) (*ast.FileDiff, error) {
+	if delta.Payload.FileDiff != nil {
+		return delta.Payload.FileDiff, nil
+	}
+	summary := delta.Payload.DiffSummary
+	if summary == nil {
+		return nil, fmt.Errorf("delta %s has no diff", delta.ID)
+	}
+
+	sm.mu.Lock()
+	if diff, ok := sm.heldDiffs[summary.DiffHash]; ok {
+		sm.mu.Unlock()
+		return diff, nil
+	}
+	send := sm.diffRequestFn
+	if send == nil {
+		sm.mu.Unlock()
+		return nil, fmt.Errorf("diff fetch not configured")
+	}
+	ch := make(chan *ast.FileDiff, 1)
+	sm.diffWaiters[summary.DiffHash] = append(sm.diffWaiters[summary.DiffHash], ch)
+	sm.mu.Unlock()
+
+	defer sm.dropWaiter(summary.DiffHash, ch)
+
+	err := send(&DiffRequest{
+		Type:        MsgDiffRequest,
+		RequestorID: sm.nodeID,
+		DeltaID:     delta.ID,
+		DiffHash:    summary.DiffHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to request diff: %w", err)
+	}
+
+	select {
+	case diff := <-ch:
+		return diff, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("diff %s not received: %w", summary.DiffHash, ctx.Err())
+	}
+}
+
+// dropWaiter는 대기 채널을 제거합니다.
+func (sm *SyncManager) dropWaiter(hash string, ch chan *ast.FileDiff) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	waiters := sm.diffWaiters[hash]
+	for i, w := range waiters {
+		if w == ch {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(sm.diffWaiters, hash)
+	} else {
+		sm.diffWaiters[hash] = waiters
+	}
+}
+
+// HandleDiffRequest answers a peer's diff request if this node holds the
+// diff. It returns nil otherwise.
+func (sm *SyncManager) HandleDiffRequest(req *DiffRequest) *DiffResponse {
+	if req == nil || req.RequestorID == sm.nodeID {
+		return nil
+	}
+
+	sm.mu.RLock()
+	diff, ok := sm.heldDiffs[req.DiffHash]
+	sm.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	return &DiffResponse{
+		Type:        MsgDiffResponse,
+		ResponderID: sm.nodeID,
+		RequestorID: req.RequestorID,
+		DiffHash:    req.DiffHash,
+		Diff:        diff,
+	}
+}
+
+// HandleDiffResponse delivers a fetched diff to waiting callers. A diff
+// whose hash does not match the request is rejected.
+func (sm *SyncManager) HandleDiffResponse(resp *DiffResponse) error {
+	if resp == nil || resp.Diff == nil || resp.RequestorID != sm.nodeID {
+		return nil
+	}
+
+	data, err := json.Marshal(resp.Diff)
+	if err != nil {
+		return err
+	}
+	if diffHash(data) != resp.DiffHash {
+		return fmt.Errorf("diff hash mismatch from %s", resp.ResponderID)
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.holdDiff(resp.DiffHash, resp.Diff)
+	for _, ch := range sm.diffWaiters[resp.DiffHash] {
+		select {
+		case ch <- resp.Diff:
+		default:
+		}
+	}
+	delete(sm.diffWaiters, resp.DiffHash)
+	return nil
+}
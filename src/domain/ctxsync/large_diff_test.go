@@ -0,0 +1,151 @@
+package ctxsync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"agent-collab/src/domain/ast"
+)
+
+// bigDiff returns a diff with n modified symbols.
+func bigDiff(path string, n int) *ast.FileDiff {
+	diff := &ast.FileDiff{FilePath: path, NewHash: "new", ModifiedCount: n}
+	for i := 0; i < n; i++ {
+		diff.Diffs = append(diff.Diffs, &ast.SymbolDiff{
+			Type:   ast.DiffModified,
+			Symbol: &ast.Symbol{Type: ast.SymbolFunction, Name: fmt.Sprintf("Func%d", i), StartLine: i * 10, EndLine: i*10 + 9},
+		})
+	}
+	return diff
+}
+
+// linkManagers wires two sync managers so deltas and diff fetch messages
+// flow between them as they would over the context sync topic.
+func linkManagers(a, b *SyncManager) {
+	route := func(to *SyncManager) func(msg any) error {
+		return func(msg any) error {
+			switch m := msg.(type) {
+			case *DiffRequest:
+				if resp := to.HandleDiffRequest(m); resp != nil {
+					return to.diffRequestFn(resp)
+				}
+			case *DiffResponse:
+				return to.HandleDiffResponse(m)
+			}
+			return nil
+		}
+	}
+	a.SetDiffRequestFn(route(b))
+	b.SetDiffRequestFn(route(a))
+	a.SetBroadcastFn(func(d *Delta) error { return b.ReceiveDelta(d) })
+	b.SetBroadcastFn(func(d *Delta) error { return a.ReceiveDelta(d) })
+}
+
+func TestSyncManager_LargeDiffIsSummarized(t *testing.T) {
+	sm := NewSyncManager("node-a", "A")
+	sm.SetMaxDiffBytes(1024)
+
+	var sent []*Delta
+	sm.SetBroadcastFn(func(d *Delta) error {
+		sent = append(sent, d)
+		return nil
+	})
+
+	// Small change travels in full
+	small := bigDiff("small.go", 1)
+	if err := sm.handleLocalChange(&ast.FileChange{Type: ast.ChangeModified, FilePath: "small.go", Diff: small}); err != nil {
+		t.Fatal(err)
+	}
+	// Large change is summarized
+	large := bigDiff("large.go", 200)
+	if err := sm.handleLocalChange(&ast.FileChange{Type: ast.ChangeModified, FilePath: "large.go", Diff: large}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 broadcasts, got %d", len(sent))
+	}
+	if sent[0].Payload.FileDiff == nil || sent[0].Payload.DiffSummary != nil {
+		t.Error("small diff should be sent in full")
+	}
+
+	payload := sent[1].Payload
+	if payload.FileDiff != nil {
+		t.Fatal("large diff should not be sent in full")
+	}
+	summary := payload.DiffSummary
+	if summary == nil || summary.DiffHash == "" {
+		t.Fatalf("expected diff summary with hash, got %+v", summary)
+	}
+	if summary.Size <= 1024 || summary.ModifiedCount != 200 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if len(summary.Symbols) != maxSummarySymbols || !summary.Truncated {
+		t.Errorf("expected truncated symbol list, got %d symbols", len(summary.Symbols))
+	}
+	if payload.FileHash != "new" {
+		t.Errorf("file hash should still be carried, got %q", payload.FileHash)
+	}
+
+	// The sender can still resolve its own diff
+	diff, err := sm.FetchDiff(context.Background(), sent[1])
+	if err != nil || diff != large {
+		t.Errorf("sender should return the held diff, got %v", err)
+	}
+}
+
+func TestSyncManager_FetchFullDiffFromPeer(t *testing.T) {
+	alpha := NewSyncManager("node-a", "A")
+	beta := NewSyncManager("node-b", "B")
+	alpha.SetMaxDiffBytes(1024)
+	linkManagers(alpha, beta)
+
+	large := bigDiff("large.go", 200)
+	if err := alpha.handleLocalChange(&ast.FileChange{Type: ast.ChangeModified, FilePath: "large.go", Diff: large}); err != nil {
+		t.Fatal(err)
+	}
+
+	recent := beta.GetRecentDeltas(1)
+	if len(recent) != 1 || recent[0].Payload.DiffSummary == nil {
+		t.Fatal("beta should have received the summarized delta")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	diff, err := beta.FetchDiff(ctx, recent[0])
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if len(diff.Diffs) != 200 || diff.Diffs[199].Symbol.Name != "Func199" {
+		t.Errorf("fetched diff is incomplete: %d symbols", len(diff.Diffs))
+	}
+}
+
+func TestSyncManager_FetchDiffTimesOutWithoutHolder(t *testing.T) {
+	sm := NewSyncManager("node-b", "B")
+	sm.SetDiffRequestFn(func(msg any) error { return nil })
+
+	delta := NewDelta(DeltaFileChange, "node-a", "A", NewVectorClock())
+	delta.Payload.DiffSummary = &DiffSummary{DiffHash: "0123456789abcdef"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := sm.FetchDiff(ctx, delta); err == nil {
+		t.Error("expected timeout when no peer holds the diff")
+	}
+}
+
+func TestSyncManager_DiffResponseHashMismatch(t *testing.T) {
+	sm := NewSyncManager("node-b", "B")
+	err := sm.HandleDiffResponse(&DiffResponse{
+		Type:        MsgDiffResponse,
+		RequestorID: "node-b",
+		DiffHash:    "not-the-hash",
+		Diff:        bigDiff("x.go", 1),
+	})
+	if err == nil {
+		t.Error("expected error for tampered diff")
+	}
+}
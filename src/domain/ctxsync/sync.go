@@ -3,6 +3,8 @@ package ctxsync
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
@@ -19,9 +21,30 @@ type SyncManager struct {
 	peers       map[string]*PeerState
 	watcher     *ast.FileWatcher
 
+	// 델타 로그 압축
+	compaction      CompactionConfig
+	compactionStats CompactionStats
+	onCompacted     func(*SyncResponse)
+
+	// 파일 저장 자동 공유
+	detector    *ast.LanguageDetector
+	autoWatcher *AutoWatcher
+
+	// 파일 요약 CRDT와 동시 수정 병합 기록
+	files      *FileMap
+	merges     []*MergeRecord
+	mergeLimit int
+
+	// 큰 diff 처리
+	maxDiffBytes int
+	heldDiffs    map[string]*ast.FileDiff
+	heldOrder    []string
+	diffWaiters  map[string][]chan *ast.FileDiff
+
 	// 콜백
-	broadcastFn func(delta *Delta) error
-	onConflict  func(*Conflict) error
+	broadcastFn   func(delta *Delta) error
+	diffRequestFn func(msg any) error
+	onConflict    func(*Conflict) error
 }
 
 // PeerState는 피어 상태입니다.
@@ -39,6 +62,8 @@ type Conflict struct {
 	LocalDelta  *Delta    `json:"local_delta"`
 	RemoteDelta *Delta    `json:"remote_delta"`
 	DetectedAt  time.Time `json:"detected_at"`
+	// Resolution은 CRDT가 이 파일의 동시 버전을 병합한 결과입니다.
+	Resolution *MergeRecord `json:"resolution,omitempty"`
 }
 
 // NewSyncManager는 새 동기화 관리자를 생성합니다.
@@ -50,9 +75,23 @@ func NewSyncManager(nodeID, nodeName string) *SyncManager {
 		deltaLog:    NewDeltaLog(1000),
 		peers:       make(map[string]*PeerState),
 		watcher:     ast.NewFileWatcher(time.Second),
+		compaction:  DefaultCompactionConfig(),
+		files:       NewFileMap(),
+		mergeLimit:  DefaultMergeHistorySize,
+
+		maxDiffBytes: DefaultMaxDiffBytes,
+		heldDiffs:    make(map[string]*ast.FileDiff),
+		diffWaiters:  make(map[string][]chan *ast.FileDiff),
 	}
 }
 
+// SetCompactionConfig는 델타 로그 압축 설정을 변경합니다.
+func (sm *SyncManager) SetCompactionConfig(cfg CompactionConfig) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.compaction = cfg
+}
+
 // SetBroadcastFn는 브로드캐스트 함수를 설정합니다.
 func (sm *SyncManager) SetBroadcastFn(fn func(delta *Delta) error) {
 	sm.broadcastFn = fn
@@ -75,11 +114,59 @@ func (sm *SyncManager) Start(ctx context.Context) {
 
 	// 주기적 heartbeat
 	go sm.heartbeatLoop(ctx)
+
+	// 주기적 체크포인트
+	sm.mu.RLock()
+	interval := sm.compaction.Interval
+	sm.mu.RUnlock()
+	if interval > 0 {
+		go sm.compactionLoop(ctx, interval)
+	}
 }
 
 // Stop은 동기화를 중단합니다.
 func (sm *SyncManager) Stop() {
 	sm.watcher.Stop()
+
+	sm.mu.RLock()
+	autoWatcher := sm.autoWatcher
+	sm.mu.RUnlock()
+	if autoWatcher != nil {
+		autoWatcher.Close()
+	}
+}
+
+// StartAutoShare는 프로젝트 디렉토리의 파일 저장을 감시해, 에이전트가
+// share_context를 호출하지 않아도 변경을 델타로 브로드캐스트합니다.
+func (sm *SyncManager) StartAutoShare(ctx context.Context, cfg AutoShareConfig) error {
+	sm.mu.Lock()
+	if sm.autoWatcher != nil {
+		sm.mu.Unlock()
+		return fmt.Errorf("auto share is already watching %s", sm.autoWatcher.Root())
+	}
+	detector := sm.detector
+	sm.mu.Unlock()
+
+	w, err := NewAutoWatcher(cfg, detector, sm.handleLocalChange)
+	if err != nil {
+		return err
+	}
+
+	sm.mu.Lock()
+	sm.autoWatcher = w
+	sm.mu.Unlock()
+	go w.Run(ctx)
+	return nil
+}
+
+// AutoShareRoot는 자동 공유로 감시 중인 디렉토리를 반환합니다. 꺼져 있으면 ""입니다.
+func (sm *SyncManager) AutoShareRoot() string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	if sm.autoWatcher == nil {
+		return ""
+	}
+	return sm.autoWatcher.Root()
 }
 
 // WatchFile은 파일을 감시합니다.
@@ -92,6 +179,18 @@ func (sm *SyncManager) WatchDir(dirPath string, extensions []string) error {
 	return sm.watcher.WatchDir(dirPath, extensions)
 }
 
+// SetLanguageRules는 확장자로 알 수 없는 파일의 언어 매핑을 설정합니다.
+func (sm *SyncManager) SetLanguageRules(rules []ast.FilenameRule) error {
+	detector, err := ast.NewLanguageDetector(rules)
+	if err != nil {
+		return err
+	}
+	sm.mu.Lock()
+	sm.detector = detector
+	sm.mu.Unlock()
+	return sm.watcher.SetLanguageRules(rules)
+}
+
 // handleLocalChange는 로컬 변경을 처리합니다.
 func (sm *SyncManager) handleLocalChange(change *ast.FileChange) error {
 	sm.mu.Lock()
@@ -106,14 +205,18 @@ func (sm *SyncManager) handleLocalChange(change *ast.FileChange) error {
 	case ast.ChangeModified:
 		delta = NewFileChangeDelta(sm.nodeID, sm.nodeName, sm.vectorClock, change.FilePath, change.Diff)
 	case ast.ChangeCreated:
-		delta = NewFileChangeDelta(sm.nodeID, sm.nodeName, sm.vectorClock, change.FilePath, nil)
+		delta = NewFileChangeDelta(sm.nodeID, sm.nodeName, sm.vectorClock, change.FilePath, change.Diff)
 	case ast.ChangeDeleted:
 		delta = NewDelta(DeltaFileChange, sm.nodeID, sm.nodeName, sm.vectorClock)
 		delta.Payload.FilePath = change.FilePath
 	}
 
 	if delta != nil {
+		if err := sm.shrinkDelta(delta); err != nil {
+			return fmt.Errorf("failed to summarize diff: %w", err)
+		}
 		sm.deltaLog.Append(delta)
+		sm.mergeFile(delta)
 
 		// 브로드캐스트
 		if sm.broadcastFn != nil {
@@ -133,11 +236,16 @@ func (sm *SyncManager) ReceiveDelta(delta *Delta) error {
 	if _, exists := sm.deltaLog.Get(delta.ID); exists {
 		return nil
 	}
+	if sm.deltaLog.checkpoint.Covers(delta.VectorClock) {
+		return nil
+	}
 
-	// 충돌 감지
+	// 파일 요약을 CRDT로 병합한 뒤 충돌 감지
+	resolution := sm.mergeFile(delta)
 	conflicts := sm.detectConflicts(delta)
 	if len(conflicts) > 0 && sm.onConflict != nil {
 		for _, conflict := range conflicts {
+			conflict.Resolution = resolution
 			if err := sm.onConflict(conflict); err != nil {
 				return fmt.Errorf("conflict handler failed: %w", err)
 			}
@@ -157,6 +265,55 @@ func (sm *SyncManager) ReceiveDelta(delta *Delta) error {
 	return nil
 }
 
+// mergeFile은 파일 변경 델타를 파일 요약 CRDT에 병합하고, 동시
+// 버전 사이에서 승자를 골랐으면 기록합니다.
+func (sm *SyncManager) mergeFile(delta *Delta) *MergeRecord {
+	v := fileVersionOf(delta)
+	if v == nil {
+		return nil
+	}
+	record := sm.files.Merge(delta.Payload.FilePath, v, time.Now())
+	sm.recordMerges(record)
+	return record
+}
+
+// recordMerges는 병합 기록을 보관합니다. 오래된 기록부터 버립니다.
+func (sm *SyncManager) recordMerges(records ...*MergeRecord) {
+	for _, record := range records {
+		if record != nil {
+			sm.merges = append(sm.merges, record)
+		}
+	}
+	if over := len(sm.merges) - sm.mergeLimit; over > 0 {
+		sm.merges = slices.Delete(sm.merges, 0, over)
+	}
+}
+
+// MergeHistory는 동시 수정 병합 기록을 최신순으로 반환합니다.
+// filePath가 비어 있으면 모든 파일, limit이 0 이하면 전체를 반환합니다.
+func (sm *SyncManager) MergeHistory(filePath string, limit int) []*MergeRecord {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	var records []*MergeRecord
+	for i := len(sm.merges) - 1; i >= 0; i-- {
+		if limit > 0 && len(records) >= limit {
+			break
+		}
+		if filePath == "" || sm.merges[i].FilePath == filePath {
+			records = append(records, sm.merges[i])
+		}
+	}
+	return records
+}
+
+// FileVersion은 파일 요약 CRDT에서 파일의 현재 버전을 반환합니다.
+func (sm *SyncManager) FileVersion(filePath string) (*FileVersion, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.files.Get(filePath)
+}
+
 // detectConflicts는 충돌을 감지합니다.
 func (sm *SyncManager) detectConflicts(remoteDelta *Delta) []*Conflict {
 	var conflicts []*Conflict
@@ -220,6 +377,39 @@ func (sm *SyncManager) heartbeatLoop(ctx context.Context) {
 	}
 }
 
+// compactionLoop은 주기적으로 델타 로그를 압축합니다.
+func (sm *SyncManager) compactionLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			sm.RunCompaction(now)
+		}
+	}
+}
+
+// Compact는 델타 로그를 즉시 체크포인트로 압축합니다.
+func (sm *SyncManager) Compact() *Checkpoint {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.deltaLog.Compact()
+}
+
+// GetState는 체크포인트와 이후 델타로 재구성한 현재 상태를 반환합니다.
+// 파일 해시는 파일 요약 CRDT에서 가져오므로 델타 수신 순서와 무관하게
+// 모든 피어에서 같습니다.
+func (sm *SyncManager) GetState() *SyncState {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	state := sm.deltaLog.Replay()
+	maps.Copy(state.Files, sm.files.Hashes())
+	return state
+}
+
 // checkPeerHealth는 피어 상태를 확인합니다.
 func (sm *SyncManager) checkPeerHealth() {
 	sm.mu.Lock()
@@ -304,17 +494,78 @@ func (sm *SyncManager) HandleSyncRequest(req *SyncRequest) *SyncResponse {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
-	deltas := sm.deltaLog.GetSince(req.LastKnownClock)
+	resp := &SyncResponse{
+		ResponderID:   sm.nodeID,
+		ResponderName: sm.nodeName,
+		CurrentClock:  sm.vectorClock.Clone(),
+		Timestamp:     time.Now(),
+	}
+
+	// 요청자가 체크포인트 이전 상태라면 체크포인트와 이후 델타 전체를 전송
+	if cp := sm.deltaLog.Checkpoint(); cp != nil && !clockIncludes(req.LastKnownClock, cp.VectorClock) {
+		resp.Checkpoint = cp
+		resp.Files = sm.files.Clone()
+		resp.Deltas = sm.deltaLog.GetRecent(0)
+		return resp
+	}
+
+	resp.Deltas = sm.deltaLog.GetSince(req.LastKnownClock)
+	return resp
+}
+
+// Export는 체크포인트와 이후 델타 전체를 반환합니다.
+// 스냅샷에 사용되며, ApplySyncResponse로 다시 적용할 수 있습니다.
+func (sm *SyncManager) Export() *SyncResponse {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
 
 	return &SyncResponse{
 		ResponderID:   sm.nodeID,
 		ResponderName: sm.nodeName,
-		Deltas:        deltas,
+		Deltas:        slices.Clone(sm.deltaLog.GetRecent(0)),
+		Checkpoint:    sm.deltaLog.Checkpoint(),
+		Files:         sm.files.Clone(),
 		CurrentClock:  sm.vectorClock.Clone(),
 		Timestamp:     time.Now(),
 	}
 }
 
+// ApplySyncResponse는 동기화 응답을 반영합니다.
+// 체크포인트가 포함되어 있으면 먼저 설치한 뒤 이후 델타를 적용합니다.
+// 파일 요약 CRDT 상태가 포함되어 있으면 함께 병합합니다.
+func (sm *SyncManager) ApplySyncResponse(resp *SyncResponse) error {
+	if resp.Checkpoint != nil || resp.Files != nil {
+		sm.mu.Lock()
+		if resp.Checkpoint != nil {
+			sm.deltaLog.Restore(resp.Checkpoint)
+			sm.vectorClock.Merge(resp.Checkpoint.VectorClock)
+		}
+		sm.recordMerges(sm.files.MergeMap(resp.Files, time.Now())...)
+		sm.mu.Unlock()
+	}
+
+	for _, delta := range resp.Deltas {
+		if err := sm.ReceiveDelta(delta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clockIncludes는 vc가 other의 모든 이벤트를 포함하는지 확인합니다.
+func clockIncludes(vc, other *VectorClock) bool {
+	if vc == nil {
+		return false
+	}
+	clocks := vc.ToMap()
+	for nodeID, t := range other.ToMap() {
+		if clocks[nodeID] < t {
+			return false
+		}
+	}
+	return true
+}
+
 // SyncRequest는 동기화 요청입니다.
 type SyncRequest struct {
 	RequestorID    string       `json:"requestor_id"`
@@ -326,11 +577,15 @@ type SyncRequest struct {
 
 // SyncResponse는 동기화 응답입니다.
 type SyncResponse struct {
-	ResponderID   string       `json:"responder_id"`
-	ResponderName string       `json:"responder_name"`
-	Deltas        []*Delta     `json:"deltas"`
-	CurrentClock  *VectorClock `json:"current_clock"`
-	Timestamp     time.Time    `json:"timestamp"`
+	ResponderID   string      `json:"responder_id"`
+	ResponderName string      `json:"responder_name"`
+	Deltas        []*Delta    `json:"deltas"`
+	Checkpoint    *Checkpoint `json:"checkpoint,omitempty"`
+	// Files는 체크포인트로 압축된 델타의 파일 버전을 잃지 않도록
+	// 체크포인트와 함께 보내는 파일 요약 CRDT 상태입니다.
+	Files        *FileMap     `json:"files,omitempty"`
+	CurrentClock *VectorClock `json:"current_clock"`
+	Timestamp    time.Time    `json:"timestamp"`
 }
 
 // GetStats는 동기화 통계를 반환합니다.
@@ -345,20 +600,29 @@ func (sm *SyncManager) GetStats() *SyncStats {
 		}
 	}
 
+	compacted := 0
+	if cp := sm.deltaLog.Checkpoint(); cp != nil {
+		compacted = cp.DeltaCount
+	}
+
 	return &SyncStats{
-		TotalDeltas:  sm.deltaLog.Size(),
-		TotalPeers:   len(sm.peers),
-		OnlinePeers:  onlinePeers,
-		WatchedFiles: len(sm.watcher.GetWatchedFiles()),
-		VectorClock:  sm.vectorClock.ToMap(),
+		TotalDeltas:     sm.deltaLog.Size(),
+		CompactedDeltas: compacted,
+		TotalPeers:      len(sm.peers),
+		OnlinePeers:     onlinePeers,
+		WatchedFiles:    len(sm.watcher.GetWatchedFiles()),
+		VectorClock:     sm.vectorClock.ToMap(),
+		Compaction:      sm.compactionStatsLocked(),
 	}
 }
 
 // SyncStats는 동기화 통계입니다.
 type SyncStats struct {
-	TotalDeltas  int               `json:"total_deltas"`
-	TotalPeers   int               `json:"total_peers"`
-	OnlinePeers  int               `json:"online_peers"`
-	WatchedFiles int               `json:"watched_files"`
-	VectorClock  map[string]uint64 `json:"vector_clock"`
+	TotalDeltas     int               `json:"total_deltas"`
+	CompactedDeltas int               `json:"compacted_deltas"`
+	TotalPeers      int               `json:"total_peers"`
+	OnlinePeers     int               `json:"online_peers"`
+	WatchedFiles    int               `json:"watched_files"`
+	VectorClock     map[string]uint64 `json:"vector_clock"`
+	Compaction      CompactionStats   `json:"compaction"`
 }
@@ -0,0 +1,206 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BackpressurePolicy decides what happens when a subscriber's buffer is full.
+type BackpressurePolicy string
+
+const (
+	// BackpressureBlock waits for room in the buffer, up to BlockTimeout
+	// or until the publish context ends.
+	BackpressureBlock BackpressurePolicy = "block"
+	// BackpressureDropOldest discards the oldest buffered event.
+	BackpressureDropOldest BackpressurePolicy = "drop-oldest"
+	// BackpressureDropNewest discards the incoming event.
+	BackpressureDropNewest BackpressurePolicy = "drop-newest"
+	// BackpressureClose closes the subscription on overflow.
+	BackpressureClose BackpressurePolicy = "close-on-overflow"
+)
+
+// DefaultSubscriberBufferSize is the buffer size used when none is set.
+const DefaultSubscriberBufferSize = 100
+
+// SubscriberOptions configures how events are buffered for a subscriber.
+type SubscriberOptions struct {
+	BufferSize int
+	Policy     BackpressurePolicy
+	// BlockTimeout bounds how long BackpressureBlock waits (0 = no limit).
+	BlockTimeout time.Duration
+}
+
+// DefaultSubscriberOptions returns the default subscriber options, which
+// drop new events when the subscriber falls behind.
+func DefaultSubscriberOptions() SubscriberOptions {
+	return SubscriberOptions{
+		BufferSize: DefaultSubscriberBufferSize,
+		Policy:     BackpressureDropNewest,
+	}
+}
+
+// withDefaults fills in unset fields.
+func (o SubscriberOptions) withDefaults() SubscriberOptions {
+	if o.BufferSize <= 0 {
+		o.BufferSize = DefaultSubscriberBufferSize
+	}
+	switch o.Policy {
+	case BackpressureBlock, BackpressureDropOldest, BackpressureDropNewest, BackpressureClose:
+	default:
+		o.Policy = BackpressureDropNewest
+	}
+	return o
+}
+
+// SubscriberStats reports delivery counters for one subscription.
+type SubscriberStats struct {
+	AgentID    string             `json:"agent_id"`
+	Policy     BackpressurePolicy `json:"policy"`
+	BufferSize int                `json:"buffer_size"`
+	Buffered   int                `json:"buffered"`
+	Delivered  uint64             `json:"delivered"`
+	Dropped    uint64             `json:"dropped"`
+}
+
+// BackpressureStats reports delivery counters across all subscribers.
+// Closed subscribers are no longer listed but remain in the totals.
+type BackpressureStats struct {
+	Delivered         uint64            `json:"delivered"`
+	Dropped           uint64            `json:"dropped"`
+	ClosedSubscribers uint64            `json:"closed_subscribers"`
+	Subscribers       []SubscriberStats `json:"subscribers"`
+}
+
+// backpressureCounters are the router-wide delivery counters.
+type backpressureCounters struct {
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	closed    atomic.Uint64
+}
+
+// subscription is a single subscriber channel and its policy.
+type subscription struct {
+	agentID string
+	opts    SubscriberOptions
+	ch      chan *Event
+
+	// sendMu serializes senders so drop-oldest can make room safely and
+	// the channel is never closed during a send.
+	sendMu sync.Mutex
+	done   chan struct{}
+	once   sync.Once
+
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+func newSubscription(agentID string, opts SubscriberOptions) *subscription {
+	opts = opts.withDefaults()
+	return &subscription{
+		agentID: agentID,
+		opts:    opts,
+		ch:      make(chan *Event, opts.BufferSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// close closes the subscription and reports whether this call closed it.
+// A blocked sender is released first.
+func (s *subscription) close() (closed bool) {
+	s.once.Do(func() {
+		close(s.done)
+		s.sendMu.Lock()
+		close(s.ch)
+		s.sendMu.Unlock()
+		closed = true
+	})
+	return closed
+}
+
+// deliver sends event according to the subscription's policy and reports
+// whether the subscription overflowed and must be closed.
+func (s *subscription) deliver(ctx context.Context, event *Event, counters *backpressureCounters) (overflow bool) {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+
+	sent := func() {
+		s.delivered.Add(1)
+		counters.delivered.Add(1)
+	}
+	drop := func() {
+		s.dropped.Add(1)
+		counters.dropped.Add(1)
+	}
+
+	select {
+	case s.ch <- event:
+		sent()
+		return false
+	default:
+	}
+
+	switch s.opts.Policy {
+	case BackpressureBlock:
+		var timeout <-chan time.Time
+		if s.opts.BlockTimeout > 0 {
+			timer := time.NewTimer(s.opts.BlockTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case s.ch <- event:
+			sent()
+		case <-s.done:
+		case <-ctx.Done():
+			drop()
+		case <-timeout:
+			drop()
+		}
+		return false
+
+	case BackpressureDropOldest:
+		// Senders are serialized, so after taking one event out there is room
+		for {
+			select {
+			case s.ch <- event:
+				sent()
+				return false
+			default:
+			}
+			select {
+			case <-s.ch:
+				drop()
+			default:
+			}
+		}
+
+	case BackpressureClose:
+		drop()
+		return true
+
+	default: // BackpressureDropNewest
+		drop()
+		return false
+	}
+}
+
+// stats returns the subscription's counters.
+func (s *subscription) stats() SubscriberStats {
+	return SubscriberStats{
+		AgentID:    s.agentID,
+		Policy:     s.opts.Policy,
+		BufferSize: s.opts.BufferSize,
+		Buffered:   len(s.ch),
+		Delivered:  s.delivered.Load(),
+		Dropped:    s.dropped.Load(),
+	}
+}
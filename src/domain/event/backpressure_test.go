@@ -0,0 +1,221 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"agent-collab/src/domain/interest"
+)
+
+// publishN publishes n broadcast events and returns them in order.
+func publishN(t *testing.T, ctx context.Context, router *Router, n int) []*Event {
+	t.Helper()
+
+	events := make([]*Event, n)
+	for i := range events {
+		events[i] = NewEvent(EventTypeAgentJoined, "source-1", "Agent1")
+		if err := router.PublishLocal(ctx, events[i]); err != nil {
+			t.Fatalf("PublishLocal failed: %v", err)
+		}
+	}
+	return events
+}
+
+// drain reads every buffered event without blocking.
+func drain(ch <-chan *Event) (events []*Event, closed bool) {
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				return events, true
+			}
+			events = append(events, e)
+		default:
+			return events, false
+		}
+	}
+}
+
+func TestBackpressure_DropNewest(t *testing.T) {
+	router := NewRouter(interest.NewManager(), nil)
+	ch := router.SubscribeWithOptions("tui", SubscriberOptions{BufferSize: 2, Policy: BackpressureDropNewest})
+
+	sent := publishN(t, context.Background(), router, 5)
+
+	got, closed := drain(ch)
+	if closed {
+		t.Fatal("drop-newest must not close the subscription")
+	}
+	if len(got) != 2 || got[0] != sent[0] || got[1] != sent[1] {
+		t.Errorf("expected the first 2 events to be kept, got %d events", len(got))
+	}
+
+	stats := router.BackpressureStats()
+	if stats.Dropped != 3 || stats.Delivered != 2 {
+		t.Errorf("expected 2 delivered/3 dropped, got %d/%d", stats.Delivered, stats.Dropped)
+	}
+}
+
+func TestBackpressure_DropOldest(t *testing.T) {
+	router := NewRouter(interest.NewManager(), nil)
+	ch := router.SubscribeWithOptions("tui", SubscriberOptions{BufferSize: 2, Policy: BackpressureDropOldest})
+
+	sent := publishN(t, context.Background(), router, 5)
+
+	got, closed := drain(ch)
+	if closed {
+		t.Fatal("drop-oldest must not close the subscription")
+	}
+	if len(got) != 2 || got[0] != sent[3] || got[1] != sent[4] {
+		t.Errorf("expected the last 2 events to be kept, got %d events", len(got))
+	}
+
+	stats := router.BackpressureStats()
+	if stats.Dropped != 3 || stats.Delivered != 5 {
+		t.Errorf("expected 5 delivered/3 dropped, got %d/%d", stats.Delivered, stats.Dropped)
+	}
+	if len(stats.Subscribers) != 1 || stats.Subscribers[0].Dropped != 3 {
+		t.Errorf("expected per-subscriber drop count, got %+v", stats.Subscribers)
+	}
+}
+
+func TestBackpressure_CloseOnOverflow(t *testing.T) {
+	router := NewRouter(interest.NewManager(), nil)
+	slow := router.SubscribeWithOptions("tui", SubscriberOptions{BufferSize: 2, Policy: BackpressureClose})
+	healthy := router.SubscribeWithOptions("agent", SubscriberOptions{BufferSize: 10})
+
+	publishN(t, context.Background(), router, 4)
+
+	got, closed := drain(slow)
+	if !closed {
+		t.Fatal("expected slow subscriber to be closed on overflow")
+	}
+	if len(got) != 2 {
+		t.Errorf("expected buffered events to remain readable, got %d", len(got))
+	}
+
+	if got, closed := drain(healthy); closed || len(got) != 4 {
+		t.Errorf("healthy subscriber should keep receiving, got %d events (closed=%v)", len(got), closed)
+	}
+
+	stats := router.BackpressureStats()
+	if stats.ClosedSubscribers != 1 {
+		t.Errorf("expected 1 closed subscriber, got %d", stats.ClosedSubscribers)
+	}
+	if len(stats.Subscribers) != 1 || stats.Subscribers[0].AgentID != "agent" {
+		t.Errorf("closed subscriber should be removed, got %+v", stats.Subscribers)
+	}
+
+	// Unsubscribing after the overflow close must not panic
+	router.Unsubscribe("tui")
+}
+
+func TestBackpressure_BlockWaitsForRoom(t *testing.T) {
+	router := NewRouter(interest.NewManager(), nil)
+	ch := router.SubscribeWithOptions("tui", SubscriberOptions{BufferSize: 1, Policy: BackpressureBlock})
+
+	publishN(t, context.Background(), router, 1)
+
+	done := make(chan struct{})
+	second := NewEvent(EventTypeAgentJoined, "source-1", "Agent1")
+	go func() {
+		_ = router.PublishLocal(context.Background(), second)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("publish should block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	<-ch // make room
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish should resume once there is room")
+	}
+
+	if e := <-ch; e != second {
+		t.Error("expected blocked event to be delivered")
+	}
+	if stats := router.BackpressureStats(); stats.Dropped != 0 {
+		t.Errorf("block policy should not drop, got %d", stats.Dropped)
+	}
+}
+
+func TestBackpressure_BlockTimeout(t *testing.T) {
+	router := NewRouter(interest.NewManager(), nil)
+	router.SubscribeWithOptions("tui", SubscriberOptions{BufferSize: 1, Policy: BackpressureBlock, BlockTimeout: 20 * time.Millisecond})
+
+	start := time.Now()
+	publishN(t, context.Background(), router, 2)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("block timeout not honored, took %v", elapsed)
+	}
+
+	if stats := router.BackpressureStats(); stats.Dropped != 1 {
+		t.Errorf("expected timed-out event to count as dropped, got %d", stats.Dropped)
+	}
+}
+
+func TestBackpressure_BlockCancelled(t *testing.T) {
+	router := NewRouter(interest.NewManager(), nil)
+	router.SubscribeWithOptions("tui", SubscriberOptions{BufferSize: 1, Policy: BackpressureBlock})
+	publishN(t, context.Background(), router, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = router.PublishLocal(ctx, NewEvent(EventTypeAgentJoined, "source-1", "Agent1"))
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("cancelled context should release a blocked publish")
+	}
+
+	if stats := router.BackpressureStats(); stats.Dropped != 1 {
+		t.Errorf("expected cancelled event to count as dropped, got %d", stats.Dropped)
+	}
+}
+
+func TestBackpressure_UnsubscribeReleasesBlockedPublish(t *testing.T) {
+	router := NewRouter(interest.NewManager(), nil)
+	router.SubscribeWithOptions("tui", SubscriberOptions{BufferSize: 1, Policy: BackpressureBlock})
+	publishN(t, context.Background(), router, 1)
+
+	done := make(chan struct{})
+	go func() {
+		_ = router.PublishLocal(context.Background(), NewEvent(EventTypeAgentJoined, "source-1", "Agent1"))
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	router.Unsubscribe("tui")
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Unsubscribe should release a publish blocked on that subscriber")
+	}
+}
+
+func TestRouterConfig_DefaultSubscriberOptions(t *testing.T) {
+	router := NewRouter(interest.NewManager(), &RouterConfig{
+		Subscriber: SubscriberOptions{BufferSize: 3, Policy: BackpressureDropOldest},
+	})
+	router.Subscribe("agent")
+
+	stats := router.BackpressureStats()
+	if len(stats.Subscribers) != 1 {
+		t.Fatalf("expected 1 subscriber, got %d", len(stats.Subscribers))
+	}
+	if s := stats.Subscribers[0]; s.BufferSize != 3 || s.Policy != BackpressureDropOldest {
+		t.Errorf("expected configured defaults, got %+v", s)
+	}
+}
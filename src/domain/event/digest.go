@@ -0,0 +1,254 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxDigestNotes caps the notes kept per file in a digest.
+const maxDigestNotes = 3
+
+// Summarizer condenses digest text into a shorter summary.
+type Summarizer interface {
+	Summarize(ctx context.Context, text string) (string, error)
+}
+
+// Digest aggregates cluster activity since a point in time.
+type Digest struct {
+	Since       time.Time      `json:"since"`
+	Until       time.Time      `json:"until"`
+	TotalEvents int            `json:"total_events"`
+	Files       []*FileDigest  `json:"files"`
+	Agents      []*AgentDigest `json:"agents"`
+	Condensed   string         `json:"condensed,omitempty"`
+}
+
+// FileDigest summarizes activity on a single file.
+type FileDigest struct {
+	FilePath      string    `json:"file_path"`
+	Changes       int       `json:"changes"`
+	ContextShares int       `json:"context_shares"`
+	LocksAcquired int       `json:"locks_acquired"`
+	LocksReleased int       `json:"locks_released"`
+	Conflicts     int       `json:"conflicts"`
+	Agents        []string  `json:"agents"`
+	Notes         []string  `json:"notes,omitempty"`
+	LastActivity  time.Time `json:"last_activity"`
+}
+
+// Total returns the number of events recorded for the file.
+func (f *FileDigest) Total() int {
+	return f.Changes + f.ContextShares + f.LocksAcquired + f.LocksReleased + f.Conflicts
+}
+
+// AgentDigest summarizes activity by a single agent.
+type AgentDigest struct {
+	AgentID   string            `json:"agent_id"`
+	AgentName string            `json:"agent_name,omitempty"`
+	Events    int               `json:"events"`
+	ByType    map[EventType]int `json:"by_type"`
+	Files     []string          `json:"files,omitempty"`
+}
+
+// BuildDigest groups events at or after since by file and by agent.
+// Lock release events carry no file path, so they are attributed to the
+// file of the matching acquire event when one is present.
+func BuildDigest(events []*Event, since time.Time) *Digest {
+	sorted := make([]*Event, 0, len(events))
+	for _, e := range events {
+		if e != nil && !e.Timestamp.Before(since) {
+			sorted = append(sorted, e)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	d := &Digest{
+		Since:       since,
+		Until:       time.Now(),
+		TotalEvents: len(sorted),
+	}
+
+	files := make(map[string]*FileDigest)
+	agents := make(map[string]*AgentDigest)
+	lockFiles := make(map[string]string)
+
+	for _, e := range sorted {
+		agent := agents[e.SourceID]
+		if agent == nil {
+			agent = &AgentDigest{
+				AgentID:   e.SourceID,
+				AgentName: e.SourceName,
+				ByType:    make(map[EventType]int),
+			}
+			agents[e.SourceID] = agent
+		}
+		agent.Events++
+		agent.ByType[e.Type]++
+
+		filePath := e.FilePath
+		var lockPayload LockPayload
+		if isLockEvent(e.Type) {
+			_ = e.GetPayload(&lockPayload)
+			if filePath == "" {
+				filePath = lockFiles[lockPayload.LockID]
+			} else if lockPayload.LockID != "" {
+				lockFiles[lockPayload.LockID] = filePath
+			}
+		}
+		if filePath == "" {
+			continue
+		}
+
+		file := files[filePath]
+		if file == nil {
+			file = &FileDigest{FilePath: filePath}
+			files[filePath] = file
+		}
+		file.LastActivity = e.Timestamp
+		file.Agents = appendUnique(file.Agents, agentLabel(e))
+		agent.Files = appendUnique(agent.Files, filePath)
+
+		switch e.Type {
+		case EventTypeFileChange:
+			file.Changes++
+			var p FileChangePayload
+			if e.GetPayload(&p) == nil {
+				file.addNote(p.Summary)
+			}
+		case EventTypeContextShared:
+			file.ContextShares++
+			var p ContextSharedPayload
+			if e.GetPayload(&p) == nil {
+				file.addNote(firstLine(p.Content))
+			}
+		case EventTypeLockAcquired:
+			file.LocksAcquired++
+			file.addNote(lockPayload.Purpose)
+		case EventTypeLockReleased:
+			file.LocksReleased++
+		case EventTypeLockConflict:
+			file.Conflicts++
+		}
+	}
+
+	for _, f := range files {
+		d.Files = append(d.Files, f)
+	}
+	sort.Slice(d.Files, func(i, j int) bool {
+		if d.Files[i].Total() != d.Files[j].Total() {
+			return d.Files[i].Total() > d.Files[j].Total()
+		}
+		return d.Files[i].FilePath < d.Files[j].FilePath
+	})
+
+	for _, a := range agents {
+		d.Agents = append(d.Agents, a)
+	}
+	sort.Slice(d.Agents, func(i, j int) bool {
+		if d.Agents[i].Events != d.Agents[j].Events {
+			return d.Agents[i].Events > d.Agents[j].Events
+		}
+		return d.Agents[i].AgentID < d.Agents[j].AgentID
+	})
+
+	return d
+}
+
+// Condense replaces the digest's text with a summary produced by s.
+func (d *Digest) Condense(ctx context.Context, s Summarizer) error {
+	if s == nil {
+		return nil
+	}
+	summary, err := s.Summarize(ctx, d.Format())
+	if err != nil {
+		return err
+	}
+	d.Condensed = summary
+	return nil
+}
+
+// Format renders the digest as human-readable text.
+func (d *Digest) Format() string {
+	if d.TotalEvents == 0 {
+		return fmt.Sprintf("No activity since %s", d.Since.Format(time.RFC3339))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Activity since %s: %d events across %d files by %d agents\n",
+		d.Since.Format(time.RFC3339), d.TotalEvents, len(d.Files), len(d.Agents))
+
+	if len(d.Files) > 0 {
+		b.WriteString("\nBy file:\n")
+		for _, f := range d.Files {
+			fmt.Fprintf(&b, "- %s: %s (%s)\n", f.FilePath, f.countsText(), strings.Join(f.Agents, ", "))
+			for _, note := range f.Notes {
+				fmt.Fprintf(&b, "    • %s\n", note)
+			}
+		}
+	}
+
+	b.WriteString("\nBy agent:\n")
+	for _, a := range d.Agents {
+		name := a.AgentID
+		if a.AgentName != "" && a.AgentName != a.AgentID {
+			name = fmt.Sprintf("%s (%s)", a.AgentName, a.AgentID)
+		}
+		fmt.Fprintf(&b, "- %s: %d events, %d files\n", name, a.Events, len(a.Files))
+	}
+
+	return b.String()
+}
+
+func (f *FileDigest) countsText() string {
+	var parts []string
+	add := func(n int, label string) {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, label))
+		}
+	}
+	add(f.Changes, "changes")
+	add(f.ContextShares, "context shares")
+	add(f.LocksAcquired, "locks acquired")
+	add(f.LocksReleased, "locks released")
+	add(f.Conflicts, "conflicts")
+	return strings.Join(parts, ", ")
+}
+
+func (f *FileDigest) addNote(note string) {
+	if note == "" || len(f.Notes) >= maxDigestNotes {
+		return
+	}
+	f.Notes = appendUnique(f.Notes, note)
+}
+
+func agentLabel(e *Event) string {
+	if e.SourceName != "" {
+		return e.SourceName
+	}
+	return e.SourceID
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if r := []rune(s); len(r) > 80 {
+		s = string(r[:77]) + "..."
+	}
+	return s
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,142 @@
+package event
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-collab/src/domain/interest"
+)
+
+func seedDigestEvents(base time.Time) []*Event {
+	at := func(e *Event, offset time.Duration) *Event {
+		e.Timestamp = base.Add(offset)
+		return e
+	}
+
+	return []*Event{
+		at(NewContextSharedEvent("agent-a", "Alice", "auth/login.go", &ContextSharedPayload{
+			Content: "Added rate limiting to login\nDetails follow",
+		}), time.Minute),
+		at(NewLockAcquiredEvent("agent-b", "Bob", "auth/login.go", 1, 50, &LockPayload{
+			LockID:  "lock-1",
+			Purpose: "Refactor session handling",
+		}), 2*time.Minute),
+		at(NewLockReleasedEvent("agent-b", "Bob", &LockPayload{LockID: "lock-1"}), 3*time.Minute),
+		at(NewFileChangeEvent("agent-a", "Alice", "db/schema.go", &FileChangePayload{
+			ChangeType: "modify",
+			Summary:    "Add sessions table",
+		}), 4*time.Minute),
+		at(NewContextSharedEvent("agent-a", "Alice", "auth/login.go", &ContextSharedPayload{
+			Content: "Documented lockout policy",
+		}), 5*time.Minute),
+		// Before the digest window
+		at(NewFileChangeEvent("agent-c", "Carol", "old.go", &FileChangePayload{
+			Summary: "stale",
+		}), -time.Hour),
+	}
+}
+
+func TestBuildDigest_GroupsByFile(t *testing.T) {
+	since := time.Now().Add(-10 * time.Minute)
+	d := BuildDigest(seedDigestEvents(since), since)
+
+	if d.TotalEvents != 5 {
+		t.Errorf("expected 5 events in window, got %d", d.TotalEvents)
+	}
+	if len(d.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(d.Files))
+	}
+
+	login := d.Files[0]
+	if login.FilePath != "auth/login.go" {
+		t.Fatalf("expected most active file first, got %s", login.FilePath)
+	}
+	if login.ContextShares != 2 || login.LocksAcquired != 1 || login.LocksReleased != 1 {
+		t.Errorf("unexpected login.go counts: %+v", login)
+	}
+	if len(login.Agents) != 2 {
+		t.Errorf("expected 2 agents on login.go, got %v", login.Agents)
+	}
+
+	schema := d.Files[1]
+	if schema.FilePath != "db/schema.go" || schema.Changes != 1 {
+		t.Errorf("unexpected schema.go digest: %+v", schema)
+	}
+}
+
+func TestBuildDigest_PerAgentCounts(t *testing.T) {
+	since := time.Now().Add(-10 * time.Minute)
+	d := BuildDigest(seedDigestEvents(since), since)
+
+	counts := make(map[string]*AgentDigest)
+	for _, a := range d.Agents {
+		counts[a.AgentID] = a
+	}
+
+	if len(counts) != 2 {
+		t.Fatalf("expected 2 agents, got %d", len(counts))
+	}
+	if a := counts["agent-a"]; a == nil || a.Events != 3 || a.ByType[EventTypeContextShared] != 2 || len(a.Files) != 2 {
+		t.Errorf("unexpected agent-a digest: %+v", a)
+	}
+	if b := counts["agent-b"]; b == nil || b.Events != 2 || b.ByType[EventTypeLockReleased] != 1 {
+		t.Errorf("unexpected agent-b digest: %+v", b)
+	}
+	if _, ok := counts["agent-c"]; ok {
+		t.Error("events before since should be excluded")
+	}
+}
+
+func TestDigest_Format(t *testing.T) {
+	since := time.Now().Add(-10 * time.Minute)
+	text := BuildDigest(seedDigestEvents(since), since).Format()
+
+	for _, want := range []string{"auth/login.go", "2 context shares", "Refactor session handling", "Added rate limiting to login", "Alice (agent-a): 3 events"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("digest text missing %q:\n%s", want, text)
+		}
+	}
+
+	empty := BuildDigest(nil, since).Format()
+	if !strings.HasPrefix(empty, "No activity") {
+		t.Errorf("expected empty digest message, got %q", empty)
+	}
+}
+
+type stubSummarizer struct{ calls int }
+
+func (s *stubSummarizer) Summarize(ctx context.Context, text string) (string, error) {
+	s.calls++
+	return "condensed", nil
+}
+
+func TestRouter_DigestCondense(t *testing.T) {
+	router := NewRouter(interest.NewManager(), nil)
+	since := time.Now().Add(-time.Minute)
+
+	router.EventLog().Append(NewContextSharedEvent("agent-a", "Alice", "main.go", &ContextSharedPayload{Content: "hello"}))
+
+	d, err := router.Digest(context.Background(), since, nil, true)
+	if err != nil {
+		t.Fatalf("Digest failed: %v", err)
+	}
+	if d.Condensed != "" {
+		t.Error("expected no condensed text without a summarizer")
+	}
+
+	summarizer := &stubSummarizer{}
+	router.SetSummarizer(summarizer)
+
+	d, err = router.Digest(context.Background(), since, nil, true)
+	if err != nil {
+		t.Fatalf("Digest failed: %v", err)
+	}
+	if d.Condensed != "condensed" || summarizer.calls != 1 {
+		t.Errorf("expected condensed digest, got %q (calls=%d)", d.Condensed, summarizer.calls)
+	}
+	if d.TotalEvents != 1 {
+		t.Errorf("expected 1 event, got %d", d.TotalEvents)
+	}
+}
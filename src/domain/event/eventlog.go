@@ -33,7 +33,8 @@ type EventLog struct {
 	bySource map[string][]*Event
 	byFile   map[string][]*Event // Index by file path for compaction
 
-	stopCh chan struct{}
+	stopCh   chan struct{}
+	stopOnce sync.Once
 }
 
 // NewEventLog creates a new event log.
@@ -78,9 +79,9 @@ func (el *EventLog) compactionLoop() {
 	}
 }
 
-// Stop stops the compaction loop.
+// Stop stops the compaction loop. It is safe to call more than once.
 func (el *EventLog) Stop() {
-	close(el.stopCh)
+	el.stopOnce.Do(func() { close(el.stopCh) })
 }
 
 // Append adds an event to the log.
@@ -0,0 +1,70 @@
+package event
+
+import (
+	"sort"
+	"time"
+)
+
+// ConflictHotspot is one file's lock conflict history.
+type ConflictHotspot struct {
+	FilePath     string    `json:"file_path"`
+	Conflicts    int       `json:"conflicts"`
+	AgentPairs   []string  `json:"agent_pairs"`
+	LastConflict time.Time `json:"last_conflict"`
+}
+
+// BuildConflictHeatmap aggregates lock conflict events since the given
+// time per file. Files with the most conflicts come first, then those
+// contested by more agent pairs, then the most recent.
+func BuildConflictHeatmap(events []*Event, since time.Time) []*ConflictHotspot {
+	files := make(map[string]*ConflictHotspot)
+
+	for _, e := range events {
+		if e == nil || e.Type != EventTypeLockConflict || e.FilePath == "" || e.Timestamp.Before(since) {
+			continue
+		}
+
+		spot := files[e.FilePath]
+		if spot == nil {
+			spot = &ConflictHotspot{FilePath: e.FilePath}
+			files[e.FilePath] = spot
+		}
+		spot.Conflicts++
+		if e.Timestamp.After(spot.LastConflict) {
+			spot.LastConflict = e.Timestamp
+		}
+
+		var p LockConflictPayload
+		if e.GetPayload(&p) == nil && p.ConflictingHolder != "" {
+			spot.AgentPairs = appendUnique(spot.AgentPairs, agentPair(agentLabel(e), p.ConflictingHolder))
+		}
+	}
+
+	heatmap := make([]*ConflictHotspot, 0, len(files))
+	for _, spot := range files {
+		sort.Strings(spot.AgentPairs)
+		heatmap = append(heatmap, spot)
+	}
+	sort.Slice(heatmap, func(i, j int) bool {
+		a, b := heatmap[i], heatmap[j]
+		if a.Conflicts != b.Conflicts {
+			return a.Conflicts > b.Conflicts
+		}
+		if len(a.AgentPairs) != len(b.AgentPairs) {
+			return len(a.AgentPairs) > len(b.AgentPairs)
+		}
+		if !a.LastConflict.Equal(b.LastConflict) {
+			return a.LastConflict.After(b.LastConflict)
+		}
+		return a.FilePath < b.FilePath
+	})
+	return heatmap
+}
+
+// agentPair returns an order-independent label for two agents.
+func agentPair(a, b string) string {
+	if b < a {
+		a, b = b, a
+	}
+	return a + " <-> " + b
+}
@@ -0,0 +1,60 @@
+package event
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func conflictAt(requester, holder, filePath string, at time.Time) *Event {
+	e := NewLockConflictEvent(requester, requester, filePath, &LockConflictPayload{ConflictingHolder: holder})
+	e.Timestamp = at
+	return e
+}
+
+func TestBuildConflictHeatmap_RanksRepeatedConflictsFirst(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-time.Hour)
+
+	events := []*Event{
+		conflictAt("alice", "bob", "auth/login.go", now.Add(-50*time.Minute)),
+		conflictAt("carol", "dave", "db/pool.go", now.Add(-40*time.Minute)),
+		conflictAt("bob", "alice", "auth/login.go", now.Add(-30*time.Minute)),
+		conflictAt("carol", "alice", "auth/login.go", now.Add(-20*time.Minute)),
+		conflictAt("erin", "frank", "docs/api.md", now.Add(-time.Minute)),
+		// Too old to count
+		conflictAt("carol", "dave", "db/pool.go", now.Add(-2*time.Hour)),
+		// Not a conflict
+		NewFileChangeEvent("alice", "alice", "db/pool.go", &FileChangePayload{Summary: "edit"}),
+	}
+
+	heatmap := BuildConflictHeatmap(events, since)
+	if len(heatmap) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(heatmap))
+	}
+
+	top := heatmap[0]
+	if top.FilePath != "auth/login.go" || top.Conflicts != 3 {
+		t.Fatalf("auth/login.go should rank highest with 3 conflicts, got %s with %d", top.FilePath, top.Conflicts)
+	}
+	if want := []string{"alice <-> bob", "alice <-> carol"}; !reflect.DeepEqual(top.AgentPairs, want) {
+		t.Errorf("expected pairs %v, got %v", want, top.AgentPairs)
+	}
+	if !top.LastConflict.Equal(now.Add(-20 * time.Minute)) {
+		t.Errorf("unexpected last conflict time: %s", top.LastConflict)
+	}
+
+	// Ties on count go to the most recent conflict
+	if heatmap[1].FilePath != "docs/api.md" || heatmap[2].FilePath != "db/pool.go" {
+		t.Errorf("unexpected order: %s, %s", heatmap[1].FilePath, heatmap[2].FilePath)
+	}
+	if heatmap[2].Conflicts != 1 {
+		t.Errorf("old conflicts should be excluded, got %d", heatmap[2].Conflicts)
+	}
+}
+
+func TestBuildConflictHeatmap_Empty(t *testing.T) {
+	if heatmap := BuildConflictHeatmap(nil, time.Now()); len(heatmap) != 0 {
+		t.Errorf("expected empty heatmap, got %+v", heatmap)
+	}
+}
@@ -3,6 +3,7 @@ package event
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,8 +24,11 @@ type Router struct {
 	interestMgr *interest.Manager
 	eventLog    *EventLog
 	vectorStore RouterVectorStore
+	summarizer  Summarizer
 	broadcast   func(topic string, data []byte) error
-	subscribers map[string][]chan *Event
+	subscribers map[string][]*subscription
+	subOpts     SubscriberOptions
+	counters    backpressureCounters
 
 	nodeID   string
 	nodeName string
@@ -37,13 +41,16 @@ type RouterConfig struct {
 	MaxEvents   int
 	EventTTL    time.Duration
 	VectorStore RouterVectorStore
+	// Subscriber is the default backpressure setup for Subscribe.
+	Subscriber SubscriberOptions
 }
 
 // DefaultRouterConfig returns default router configuration.
 func DefaultRouterConfig() *RouterConfig {
 	return &RouterConfig{
-		MaxEvents: 10000,
-		EventTTL:  DefaultEventTTL,
+		MaxEvents:  10000,
+		EventTTL:   DefaultEventTTL,
+		Subscriber: DefaultSubscriberOptions(),
 	}
 }
 
@@ -63,12 +70,18 @@ func NewRouter(interestMgr *interest.Manager, cfg *RouterConfig) *Router {
 		interestMgr: interestMgr,
 		eventLog:    NewEventLog(logCfg),
 		vectorStore: cfg.VectorStore,
-		subscribers: make(map[string][]chan *Event),
+		subscribers: make(map[string][]*subscription),
+		subOpts:     cfg.Subscriber.withDefaults(),
 		nodeID:      cfg.NodeID,
 		nodeName:    cfg.NodeName,
 	}
 }
 
+// Stop stops the event log's background compaction.
+func (r *Router) Stop() {
+	r.eventLog.Stop()
+}
+
 // SetBroadcastFn sets the P2P broadcast function.
 func (r *Router) SetBroadcastFn(fn func(topic string, data []byte) error) {
 	r.mu.Lock()
@@ -83,11 +96,37 @@ func (r *Router) SetVectorStore(store RouterVectorStore) {
 	r.vectorStore = store
 }
 
+// SetSummarizer sets the summarizer used to condense digests.
+func (r *Router) SetSummarizer(s Summarizer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.summarizer = s
+}
+
+// Digest summarizes logged events since the given time together with any
+// extra events recorded outside the router, such as local lock activity.
+// When condense is set and a summarizer is configured, the digest text is
+// condensed as well.
+func (r *Router) Digest(ctx context.Context, since time.Time, extra []*Event, condense bool) (*Digest, error) {
+	events := append(r.eventLog.GetSince(since), extra...)
+	d := BuildDigest(events, since)
+
+	if condense {
+		r.mu.RLock()
+		s := r.summarizer
+		r.mu.RUnlock()
+		if err := d.Condense(ctx, s); err != nil {
+			return d, err
+		}
+	}
+	return d, nil
+}
+
 // Publish publishes an event to interested agents.
 func (r *Router) Publish(ctx context.Context, event *Event) error {
 	r.storeEvent(event)
 	r.storeInVectorDB(event)
-	r.routeToSubscribers(event)
+	r.routeToSubscribers(ctx, event)
 
 	return r.broadcastToCluster(ctx, event)
 }
@@ -95,7 +134,7 @@ func (r *Router) Publish(ctx context.Context, event *Event) error {
 // PublishLocal publishes an event only to local subscribers (no P2P broadcast).
 func (r *Router) PublishLocal(ctx context.Context, event *Event) error {
 	r.storeEvent(event)
-	r.routeToSubscribers(event)
+	r.routeToSubscribers(ctx, event)
 	return nil
 }
 
@@ -126,9 +165,9 @@ func (r *Router) storeInVectorDB(event *Event) {
 }
 
 // routeToSubscribers sends event to matching local subscribers.
-func (r *Router) routeToSubscribers(event *Event) {
+func (r *Router) routeToSubscribers(ctx context.Context, event *Event) {
 	targets := r.collectNotifyTargets(event)
-	r.notifySubscribers(event, targets)
+	r.notifySubscribers(ctx, event, targets)
 }
 
 // collectNotifyTargets determines which agents should receive the event.
@@ -181,25 +220,44 @@ func isLockEvent(t EventType) bool {
 		t == EventTypeLockConflict
 }
 
-// notifySubscribers sends event to specified subscribers.
-func (r *Router) notifySubscribers(event *Event, targets map[string]struct{}) {
+// notifySubscribers sends event to specified subscribers, applying each
+// subscriber's backpressure policy. The router lock is not held while
+// sending so a blocking subscriber cannot stall Subscribe/Unsubscribe.
+func (r *Router) notifySubscribers(ctx context.Context, event *Event, targets map[string]struct{}) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
-
+	var subs []*subscription
 	for agentID := range targets {
-		channels, ok := r.subscribers[agentID]
-		if !ok {
-			continue
+		subs = append(subs, r.subscribers[agentID]...)
+	}
+	r.mu.RUnlock()
+
+	for _, sub := range subs {
+		if sub.deliver(ctx, event, &r.counters) {
+			r.closeSubscription(sub)
 		}
+	}
+}
 
-		for _, ch := range channels {
-			select {
-			case ch <- event:
-			default:
-				// Channel full, skip
-			}
+// closeSubscription removes and closes a single subscription.
+func (r *Router) closeSubscription(sub *subscription) {
+	r.mu.Lock()
+	subs := r.subscribers[sub.agentID]
+	for i, s := range subs {
+		if s == sub {
+			subs = append(subs[:i:i], subs[i+1:]...)
+			break
 		}
 	}
+	if len(subs) == 0 {
+		delete(r.subscribers, sub.agentID)
+	} else {
+		r.subscribers[sub.agentID] = subs
+	}
+	r.mu.Unlock()
+
+	if sub.close() {
+		r.counters.closed.Add(1)
+	}
 }
 
 // broadcastToCluster broadcasts event to P2P network.
@@ -220,31 +278,56 @@ func (r *Router) broadcastToCluster(ctx context.Context, event *Event) error {
 	return broadcast(TopicEvents, data)
 }
 
-// Subscribe creates a subscription for an agent.
+// Subscribe creates a subscription for an agent using the router's
+// default subscriber options.
 func (r *Router) Subscribe(agentID string) <-chan *Event {
+	return r.SubscribeWithOptions(agentID, r.subOpts)
+}
+
+// SubscribeWithOptions creates a subscription for an agent with its own
+// buffer size and backpressure policy.
+func (r *Router) SubscribeWithOptions(agentID string, opts SubscriberOptions) <-chan *Event {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	ch := make(chan *Event, 100)
-	r.subscribers[agentID] = append(r.subscribers[agentID], ch)
+	sub := newSubscription(agentID, opts)
+	r.subscribers[agentID] = append(r.subscribers[agentID], sub)
 
-	return ch
+	return sub.ch
 }
 
 // Unsubscribe removes all subscriptions for an agent.
 func (r *Router) Unsubscribe(agentID string) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	subs, ok := r.subscribers[agentID]
+	delete(r.subscribers, agentID)
+	r.mu.Unlock()
 
-	channels, ok := r.subscribers[agentID]
 	if !ok {
 		return
 	}
+	for _, sub := range subs {
+		sub.close()
+	}
+}
 
-	for _, ch := range channels {
-		close(ch)
+// BackpressureStats returns delivery counters for subscribers.
+func (r *Router) BackpressureStats() BackpressureStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := BackpressureStats{
+		Delivered:         r.counters.delivered.Load(),
+		Dropped:           r.counters.dropped.Load(),
+		ClosedSubscribers: r.counters.closed.Load(),
+		Subscribers:       make([]SubscriberStats, 0, len(r.subscribers)),
 	}
-	delete(r.subscribers, agentID)
+	for _, subs := range r.subscribers {
+		for _, sub := range subs {
+			stats.Subscribers = append(stats.Subscribers, sub.stats())
+		}
+	}
+	return stats
 }
 
 // HandleRemoteEvent handles an event received from P2P network.
@@ -263,10 +346,22 @@ func (r *Router) GetEvents(agentID string, filter *EventFilter) []*Event {
 		filter = DefaultEventFilter()
 	}
 
-	events := r.eventLog.GetRecent(filter.Limit * 2)
+	// Scoping to one agent can leave few matches among recent events,
+	// so search the whole log instead.
+	window := filter.Limit * 2
+	if filter.Agent != "" {
+		window = 0
+	}
+
+	events := r.eventLog.GetRecent(window)
 	events = r.filterByInterestOrAll(events, agentID, filter.IncludeAll)
 	events = r.applyFilters(events, filter)
 
+	// Keep the agent's most recent events when searching the whole log
+	if filter.Agent != "" && filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[len(events)-filter.Limit:]
+	}
+
 	return r.applyLimit(events, filter.Limit)
 }
 
@@ -343,6 +438,9 @@ func (r *Router) passesFilters(event *Event, filter *EventFilter) bool {
 	if !r.passesSourceFilter(event, filter.SourceID) {
 		return false
 	}
+	if !r.passesAgentFilter(event, filter.Agent) {
+		return false
+	}
 	return true
 }
 
@@ -379,6 +477,13 @@ func (r *Router) passesSourceFilter(event *Event, sourceID string) bool {
 	return event.SourceID == sourceID
 }
 
+func (r *Router) passesAgentFilter(event *Event, agent string) bool {
+	if agent == "" {
+		return true
+	}
+	return event.SourceID == agent || strings.EqualFold(event.SourceName, agent)
+}
+
 // applyLimit applies the limit to the result set.
 func (r *Router) applyLimit(events []*Event, limit int) []*Event {
 	if limit <= 0 || limit >= len(events) {
@@ -246,3 +246,34 @@ func TestEvent_SetGetPayload(t *testing.T) {
 		t.Errorf("expected 10, got %d", retrieved.LinesDiff)
 	}
 }
+
+func TestRouter_GetEvents_FilterByAgent(t *testing.T) {
+	router := NewRouter(interest.NewManager(), nil)
+	ctx := context.Background()
+
+	router.Publish(ctx, NewFileChangeEvent("agent-a", "Alice", "a.go", &FileChangePayload{Summary: "a1"}))
+	router.Publish(ctx, NewContextSharedEvent("agent-b", "Bob", "b.go", &ContextSharedPayload{Content: "b1"}))
+	router.Publish(ctx, NewLockAcquiredEvent("agent-a", "Alice", "a.go", 1, 10, &LockPayload{LockID: "l1"}))
+	router.Publish(ctx, NewAgentJoinedEvent("agent-c", "Carol", &AgentPayload{AgentID: "agent-c"}))
+	router.Publish(ctx, NewWarningEvent("agent-a", "Alice", &WarningPayload{Message: "careful"}))
+
+	byID := router.GetEvents("", &EventFilter{Agent: "agent-a", Limit: 10, IncludeAll: true})
+	if len(byID) != 3 {
+		t.Fatalf("expected 3 events for agent-a, got %d", len(byID))
+	}
+	for _, e := range byID {
+		if e.SourceID != "agent-a" {
+			t.Errorf("unexpected event from %s", e.SourceID)
+		}
+	}
+
+	byName := router.GetEvents("", &EventFilter{Agent: "bob", Limit: 10, IncludeAll: true})
+	if len(byName) != 1 || byName[0].SourceID != "agent-b" {
+		t.Errorf("expected Bob's single event by case-insensitive name, got %d", len(byName))
+	}
+
+	limited := router.GetEvents("", &EventFilter{Agent: "agent-a", Limit: 1, IncludeAll: true})
+	if len(limited) != 1 || limited[0].Type != EventTypeWarning {
+		t.Errorf("expected agent-a's most recent event, got %+v", limited)
+	}
+}
@@ -11,14 +11,15 @@ import (
 type EventType string
 
 const (
-	EventTypeFileChange    EventType = "file_change"
-	EventTypeLockAcquired  EventType = "lock_acquired"
-	EventTypeLockReleased  EventType = "lock_released"
-	EventTypeLockConflict  EventType = "lock_conflict"
-	EventTypeContextShared EventType = "context_shared"
-	EventTypeAgentJoined   EventType = "agent_joined"
-	EventTypeAgentLeft     EventType = "agent_left"
-	EventTypeWarning       EventType = "warning"
+	EventTypeFileChange     EventType = "file_change"
+	EventTypeLockAcquired   EventType = "lock_acquired"
+	EventTypeLockReleased   EventType = "lock_released"
+	EventTypeLockConflict   EventType = "lock_conflict"
+	EventTypeContextShared  EventType = "context_shared"
+	EventTypeAgentJoined    EventType = "agent_joined"
+	EventTypeAgentLeft      EventType = "agent_left"
+	EventTypeWarning        EventType = "warning"
+	EventTypeBudgetExceeded EventType = "budget_exceeded"
 )
 
 // EventStatus defines the lifecycle state of an event.
@@ -231,12 +232,30 @@ func NewWarningEvent(sourceID, sourceName string, payload *WarningPayload) *Even
 	return event
 }
 
+// BudgetPayload is the payload for budget exceeded events.
+type BudgetPayload struct {
+	Scope   string `json:"scope"` // "project" or "agent"
+	AgentID string `json:"agent_id,omitempty"`
+	Period  string `json:"period"` // "day" or "month"
+	Limit   int64  `json:"limit"`
+	Used    int64  `json:"used"`
+	Action  string `json:"action"` // "reject" or "throttle"
+}
+
+// NewBudgetExceededEvent creates a new budget exceeded event.
+func NewBudgetExceededEvent(sourceID, sourceName string, payload *BudgetPayload) *Event {
+	event := NewEvent(EventTypeBudgetExceeded, sourceID, sourceName)
+	_ = event.SetPayload(payload)
+	return event
+}
+
 // EventFilter is used to filter events when querying.
 type EventFilter struct {
 	Types      []EventType `json:"types,omitempty"`
 	Since      time.Time   `json:"since,omitempty"`
 	FilePath   string      `json:"file_path,omitempty"`
 	SourceID   string      `json:"source_id,omitempty"`
+	Agent      string      `json:"agent,omitempty"` // Matches source ID or name
 	Limit      int         `json:"limit,omitempty"`
 	IncludeAll bool        `json:"include_all,omitempty"` // Ignore interest filtering
 }
@@ -42,25 +42,10 @@ func ParseLevelFromEnv() InterestLevel {
 
 // RegisterFromEnvironment creates and registers interest from environment variables.
 // Returns nil if no patterns are configured.
+//
+// Deprecated: use RegisterFromConfig, which also applies interest profiles.
 func RegisterFromEnvironment(mgr *Manager, agentID, agentName string) (*Interest, error) {
-	patterns := ParsePatternsFromEnv()
-	if len(patterns) == 0 {
-		return nil, nil
-	}
-
-	level := ParseLevelFromEnv()
-
-	interest := NewInterest(agentID, agentName, patterns)
-	interest.Level = level
-
-	// Set longer TTL for environment-based interests (they should persist)
-	interest.SetTTL(7 * 24 * 60 * 60 * 1e9) // 7 days
-
-	if err := mgr.Register(interest); err != nil {
-		return nil, err
-	}
-
-	return interest, nil
+	return RegisterFromConfig(mgr, agentID, agentName, nil, "")
 }
 
 // RegisterPatterns creates and registers interest with given patterns.
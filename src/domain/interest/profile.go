@@ -0,0 +1,181 @@
+package interest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvInterestProfile names the interest profile an agent uses.
+const EnvInterestProfile = "AGENT_COLLAB_INTEREST_PROFILE"
+
+// metadataFromConfig marks interests registered from the configuration,
+// so a reload can replace them without touching ones agents registered.
+const metadataFromConfig = "from_config"
+
+// Profile is a named set of default interest patterns, e.g. per role.
+// A profile inherits the patterns of the profiles it extends.
+type Profile struct {
+	// Extends lists parent profiles, merged in order before this one.
+	Extends []string `json:"extends,omitempty"`
+
+	// Patterns are added to the inherited ones. A pattern prefixed with
+	// "!" removes that exact pattern if it was inherited.
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Level overrides the inherited notification level ("all", "direct",
+	// "locks_only", "none"). Empty inherits.
+	Level string `json:"level,omitempty"`
+}
+
+// Profiles maps profile names to profiles.
+type Profiles map[string]*Profile
+
+// Resolve returns the effective patterns and level of the named profile
+// with everything it extends merged in. Child profiles take precedence
+// over their parents.
+func (p Profiles) Resolve(name string) ([]string, string, error) {
+	return p.resolve(name, nil)
+}
+
+func (p Profiles) resolve(name string, path []string) ([]string, string, error) {
+	for _, seen := range path {
+		if seen == name {
+			return nil, "", fmt.Errorf("interest profile cycle: %s -> %s", strings.Join(path, " -> "), name)
+		}
+	}
+	profile, ok := p[name]
+	if !ok || profile == nil {
+		return nil, "", fmt.Errorf("unknown interest profile %q", name)
+	}
+	path = append(path, name)
+
+	var patterns []string
+	var level string
+	for _, parent := range profile.Extends {
+		inherited, parentLevel, err := p.resolve(parent, path)
+		if err != nil {
+			return nil, "", err
+		}
+		patterns = MergePatterns(patterns, inherited)
+		if parentLevel != "" {
+			level = parentLevel
+		}
+	}
+
+	patterns = MergePatterns(patterns, profile.Patterns)
+	if profile.Level != "" {
+		level = profile.Level
+	}
+	return patterns, level, nil
+}
+
+// MergePatterns appends own to base without duplicates. An own pattern
+// prefixed with "!" removes that pattern from base instead.
+func MergePatterns(base, own []string) []string {
+	merged := make([]string, 0, len(base)+len(own))
+	merged = append(merged, base...)
+
+	for _, pattern := range own {
+		if excluded, ok := strings.CutPrefix(pattern, "!"); ok {
+			for i, existing := range merged {
+				if existing == excluded {
+					merged = append(merged[:i], merged[i+1:]...)
+					break
+				}
+			}
+			continue
+		}
+		if !containsPattern(merged, pattern) {
+			merged = append(merged, pattern)
+		}
+	}
+	return merged
+}
+
+func containsPattern(patterns []string, pattern string) bool {
+	for _, p := range patterns {
+		if p == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterFromConfig creates and registers an agent's interest from its
+// profile and environment. The profile is named by
+// AGENT_COLLAB_INTEREST_PROFILE, falling back to defaultProfile. The
+// agent's own AGENT_COLLAB_INTERESTS are merged on top of the profile's
+// patterns and AGENT_COLLAB_INTEREST_LEVEL overrides the profile's level.
+// Returns nil if no patterns result.
+func RegisterFromConfig(mgr *Manager, agentID, agentName string, profiles Profiles, defaultProfile string) (*Interest, error) {
+	interest, err := interestFromConfig(agentID, agentName, profiles, defaultProfile)
+	if err != nil || interest == nil {
+		return nil, err
+	}
+	if err := mgr.Register(interest); err != nil {
+		return nil, err
+	}
+	return interest, nil
+}
+
+// ReplaceFromConfig is RegisterFromConfig for a configuration that
+// changed: the agent's interests registered from the previous
+// configuration are removed first. On error nothing is changed.
+func ReplaceFromConfig(mgr *Manager, agentID, agentName string, profiles Profiles, defaultProfile string) (*Interest, error) {
+	interest, err := interestFromConfig(agentID, agentName, profiles, defaultProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, existing := range mgr.GetAgentInterests(agentID) {
+		if existing.Metadata[metadataFromConfig] != "" {
+			_ = mgr.Unregister(existing.ID)
+		}
+	}
+	if interest == nil {
+		return nil, nil
+	}
+	if err := mgr.Register(interest); err != nil {
+		return nil, err
+	}
+	return interest, nil
+}
+
+// interestFromConfig builds the interest RegisterFromConfig registers.
+func interestFromConfig(agentID, agentName string, profiles Profiles, defaultProfile string) (*Interest, error) {
+	profileName := os.Getenv(EnvInterestProfile)
+	if profileName == "" {
+		profileName = defaultProfile
+	}
+
+	var patterns []string
+	var level string
+	if profileName != "" {
+		var err error
+		patterns, level, err = profiles.Resolve(profileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	patterns = MergePatterns(patterns, ParsePatternsFromEnv())
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	if env := os.Getenv(EnvInterestLevel); env != "" {
+		level = env
+	}
+
+	interest := NewInterest(agentID, agentName, patterns)
+	interest.Level = ParseInterestLevel(level)
+	interest.Metadata[metadataFromConfig] = "true"
+	if profileName != "" {
+		interest.Metadata["profile"] = profileName
+	}
+
+	// Set longer TTL for configured interests (they should persist)
+	interest.SetTTL(7 * 24 * 60 * 60 * 1e9) // 7 days
+
+	return interest, nil
+}
@@ -0,0 +1,160 @@
+package interest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func teamProfiles() Profiles {
+	return Profiles{
+		"base": {
+			Patterns: []string{"go.mod", "shared/**"},
+			Level:    "direct",
+		},
+		"backend": {
+			Extends:  []string{"base"},
+			Patterns: []string{"api/**", "db/**"},
+		},
+		"payments": {
+			Extends:  []string{"backend"},
+			Patterns: []string{"payments/**", "!db/**"},
+			Level:    "all",
+		},
+	}
+}
+
+func TestProfiles_ResolveInheritance(t *testing.T) {
+	patterns, level, err := teamProfiles().Resolve("payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"go.mod", "shared/**", "api/**", "payments/**"}
+	if !reflect.DeepEqual(patterns, want) {
+		t.Errorf("expected %v, got %v", want, patterns)
+	}
+	if level != "all" {
+		t.Errorf("child level should override parent, got %q", level)
+	}
+
+	_, level, _ = teamProfiles().Resolve("backend")
+	if level != "direct" {
+		t.Errorf("level should be inherited from base, got %q", level)
+	}
+}
+
+func TestProfiles_ResolveErrors(t *testing.T) {
+	profiles := Profiles{
+		"a": {Extends: []string{"b"}},
+		"b": {Extends: []string{"a"}},
+		"c": {Extends: []string{"missing"}},
+	}
+
+	if _, _, err := profiles.Resolve("a"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+	if _, _, err := profiles.Resolve("c"); err == nil {
+		t.Error("expected error for unknown parent profile")
+	}
+	if _, _, err := profiles.Resolve("nope"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestRegisterFromConfig_MergesProfileWithOwnPatterns(t *testing.T) {
+	t.Setenv(EnvInterestProfile, "backend")
+	t.Setenv(EnvInterests, "api/**,tools/*.go,!shared/**")
+	t.Setenv(EnvInterestLevel, "locks_only")
+
+	mgr := NewManager()
+	got, err := RegisterFromConfig(mgr, "agent-1", "alice", teamProfiles(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"go.mod", "api/**", "db/**", "tools/*.go"}
+	if !reflect.DeepEqual(got.Patterns, want) {
+		t.Errorf("expected %v, got %v", want, got.Patterns)
+	}
+	if got.Level != InterestLevelLocksOnly {
+		t.Errorf("agent level should override profile level, got %s", got.Level)
+	}
+	if got.Metadata["profile"] != "backend" {
+		t.Errorf("expected profile metadata, got %q", got.Metadata["profile"])
+	}
+	if mgr.Count() != 1 {
+		t.Errorf("expected 1 registered interest, got %d", mgr.Count())
+	}
+}
+
+func TestRegisterFromConfig_DefaultProfile(t *testing.T) {
+	t.Setenv(EnvInterestProfile, "")
+	t.Setenv(EnvInterests, "")
+	t.Setenv(EnvInterestLevel, "")
+
+	got, err := RegisterFromConfig(NewManager(), "agent-1", "alice", teamProfiles(), "base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Patterns, []string{"go.mod", "shared/**"}) || got.Level != InterestLevelDirect {
+		t.Errorf("expected base profile, got %v at %s", got.Patterns, got.Level)
+	}
+
+	// The environment overrides the configured profile
+	t.Setenv(EnvInterestProfile, "backend")
+	got, err = RegisterFromConfig(NewManager(), "agent-1", "alice", teamProfiles(), "base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Metadata["profile"] != "backend" {
+		t.Errorf("env profile should win, got %q", got.Metadata["profile"])
+	}
+}
+
+func TestRegisterFromConfig_NoPatterns(t *testing.T) {
+	t.Setenv(EnvInterestProfile, "")
+	t.Setenv(EnvInterests, "")
+
+	got, err := RegisterFromConfig(NewManager(), "agent-1", "alice", nil, "")
+	if err != nil || got != nil {
+		t.Errorf("expected no interest, got %v, %v", got, err)
+	}
+}
+
+func TestReplaceFromConfig_KeepsAgentRegisteredInterests(t *testing.T) {
+	t.Setenv(EnvInterestProfile, "")
+	t.Setenv(EnvInterests, "")
+	t.Setenv(EnvInterestLevel, "")
+
+	mgr := NewManager()
+	if _, err := RegisterFromConfig(mgr, "agent-1", "alice", teamProfiles(), "base"); err != nil {
+		t.Fatal(err)
+	}
+	own := NewInterest("agent-1", "alice", []string{"docs/**"})
+	if err := mgr.Register(own); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unknown profile is rejected without touching the registrations
+	if _, err := ReplaceFromConfig(mgr, "agent-1", "alice", teamProfiles(), "missing"); err == nil {
+		t.Fatal("expected an unknown profile to be rejected")
+	}
+	if mgr.Count() != 2 {
+		t.Fatalf("failed replace changed the registrations: %d interests", mgr.Count())
+	}
+
+	got, err := ReplaceFromConfig(mgr, "agent-1", "alice", teamProfiles(), "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Metadata["profile"] != "backend" {
+		t.Errorf("expected the backend profile, got %q", got.Metadata["profile"])
+	}
+	if mgr.Count() != 2 {
+		t.Errorf("expected the configured interest replaced and the agent's kept, got %d interests", mgr.Count())
+	}
+	if _, err := mgr.Get(own.ID); err != nil {
+		t.Errorf("agent registered interest was removed: %v", err)
+	}
+}
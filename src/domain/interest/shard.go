@@ -0,0 +1,102 @@
+package interest
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ShardsForPath returns the shards a file path belongs to: its leading
+// directories, from the top-level one down to depth levels. For
+// "src/auth/login.go" and depth 2 they are "src" and "src/auth". A file
+// outside any directory belongs to no shard.
+func ShardsForPath(filePath string, depth int) []string {
+	dirs := pathDirs(filePath)
+	if len(dirs) > depth {
+		dirs = dirs[:depth]
+	}
+
+	shards := make([]string, 0, len(dirs))
+	for i := range dirs {
+		shards = append(shards, strings.Join(dirs[:i+1], "/"))
+	}
+	return shards
+}
+
+// Shards returns the shards that cover this node's interests, at most
+// depth levels deep, with shards nested in another one dropped. all is
+// true when the node has no interests or one is not confined to a
+// directory, e.g. "**/*.go" or one tracking dependencies, so the node
+// needs every shard.
+func (m *Manager) Shards(depth int) (shards []string, all bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := make(map[string]struct{})
+	local := 0
+	for _, interest := range m.interests {
+		if interest.Remote || interest.IsExpired() {
+			continue
+		}
+		local++
+		if interest.TrackDependencies {
+			return nil, true
+		}
+		for _, pattern := range interest.Patterns {
+			shard := patternShard(pattern, depth)
+			if shard == "" {
+				return nil, true
+			}
+			set[shard] = struct{}{}
+		}
+	}
+	if local == 0 {
+		return nil, true
+	}
+
+	for shard := range set {
+		if !coveredByParent(shard, set) {
+			shards = append(shards, shard)
+		}
+	}
+	slices.Sort(shards)
+	return shards, false
+}
+
+// patternShard returns the literal directory prefix of pattern, at most
+// depth levels deep, or "" when the pattern is not confined to one.
+func patternShard(pattern string, depth int) string {
+	var dirs []string
+	for _, seg := range pathDirs(pattern) {
+		if strings.ContainsAny(seg, `*?[\`) {
+			break
+		}
+		dirs = append(dirs, seg)
+	}
+	if len(dirs) > depth {
+		dirs = dirs[:depth]
+	}
+	return strings.Join(dirs, "/")
+}
+
+// coveredByParent reports whether an ancestor of shard is in set.
+func coveredByParent(shard string, set map[string]struct{}) bool {
+	for parent := path.Dir(shard); parent != "."; parent = path.Dir(parent) {
+		if _, ok := set[parent]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// pathDirs returns the directory segments of a slash or OS path, without
+// the final element.
+func pathDirs(p string) []string {
+	p = strings.Trim(path.Clean(filepath.ToSlash(p)), "/")
+	dir := path.Dir(p)
+	if dir == "." {
+		return nil
+	}
+	return strings.Split(dir, "/")
+}
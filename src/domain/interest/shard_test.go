@@ -0,0 +1,66 @@
+package interest
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestShardsForPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		depth int
+		want  []string
+	}{
+		{"src/auth/login.go", 1, []string{"src"}},
+		{"src/auth/login.go", 2, []string{"src", "src/auth"}},
+		{"src/auth/login.go", 5, []string{"src", "src/auth"}},
+		{"./src/main.go", 2, []string{"src"}},
+		{"main.go", 2, []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := ShardsForPath(tt.path, tt.depth); !slices.Equal(got, tt.want) {
+			t.Errorf("ShardsForPath(%q, %d) = %v, want %v", tt.path, tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestManager_Shards(t *testing.T) {
+	mgr := NewManager()
+	if _, all := mgr.Shards(1); !all {
+		t.Error("expected a node without interests to need every shard")
+	}
+
+	mgr.Register(NewInterest("agent-1", "Claude", []string{"src/auth/**", "src/api/*.go", "docs/**"}))
+	shards, all := mgr.Shards(2)
+	if all || !slices.Equal(shards, []string{"docs", "src/api", "src/auth"}) {
+		t.Errorf("expected [docs src/api src/auth], got %v (all=%v)", shards, all)
+	}
+
+	// Nested shards are covered by their parent
+	shards, _ = mgr.Shards(1)
+	if !slices.Equal(shards, []string{"docs", "src"}) {
+		t.Errorf("expected [docs src], got %v", shards)
+	}
+
+	// Remote interests do not change what this node subscribes to
+	remote := NewInterest("agent-2", "Peer", []string{"**/*.go"})
+	mgr.MergeRemote([]*Interest{remote})
+	if _, all := mgr.Shards(1); all {
+		t.Error("expected remote interests to be ignored")
+	}
+
+	broad := NewInterest("agent-3", "Claude", []string{"**/*_test.go"})
+	mgr.Register(broad)
+	if _, all := mgr.Shards(1); !all {
+		t.Error("expected a pattern outside any directory to need every shard")
+	}
+	mgr.Unregister(broad.ID)
+
+	deps := NewInterest("agent-3", "Claude", []string{"lib/**"})
+	deps.TrackDependencies = true
+	mgr.Register(deps)
+	if _, all := mgr.Shards(1); !all {
+		t.Error("expected dependency tracking to need every shard")
+	}
+}
@@ -0,0 +1,65 @@
+package lock
+
+import (
+	"sort"
+	"time"
+)
+
+// LockCheck reports whether a region could be locked right now, without
+// announcing an intent.
+type LockCheck struct {
+	Target *SemanticTarget `json:"target"`
+	// Free is true when no live lock the requested mode conflicts with
+	// overlaps the region; a read lock only conflicts with write locks.
+	Free bool `json:"free"`
+	// Conflicts are those live locks overlapping the region, soonest to
+	// expire first.
+	Conflicts []*SemanticLock `json:"conflicts,omitempty"`
+	// ExpectedWait is how long until every overlapping lock expires,
+	// assuming their holders neither renew nor release them early.
+	ExpectedWait time.Duration `json:"expected_wait"`
+	// Waiting counts callers on this node queued for regions of the file.
+	Waiting int `json:"waiting"`
+	// ConflictHint predicts contention on the file from recent activity.
+	ConflictHint *ConflictHint `json:"conflict_hint,omitempty"`
+}
+
+// CheckLock reports whether the region of req is free, who holds locks
+// overlapping it and how long they are expected to keep them. It is a dry
+// run of AcquireLock: nothing is announced, rate limited or recorded, and
+// symbol promotion is not applied.
+func (s *LockService) CheckLock(req *AcquireLockRequest) (*LockCheck, error) {
+	target, err := NewSemanticTarget(req.TargetType, req.FilePath, req.Name, req.StartLine, req.EndLine)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, err := ParseAccessMode(string(req.Mode))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	conflicts := s.store.FindConflictsFor(target, mode)
+	s.store.mu.RLock()
+	for i, conflict := range conflicts {
+		copied := *conflict
+		conflicts[i] = &copied
+	}
+	s.store.mu.RUnlock()
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].ExpiresAt.Before(conflicts[j].ExpiresAt)
+	})
+
+	check := &LockCheck{
+		Target:       target,
+		Free:         len(conflicts) == 0,
+		Conflicts:    conflicts,
+		Waiting:      s.queue.waiting(target.FilePath),
+		ConflictHint: s.PredictConflict(target.FilePath),
+	}
+	for _, conflict := range conflicts {
+		check.ExpectedWait = max(check.ExpectedWait, conflict.ExpiresAt.Sub(now))
+	}
+	return check, nil
+}
@@ -0,0 +1,63 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLockService_CheckLockIsADryRun(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-1", "Agent")
+	defer svc.Close()
+
+	var broadcasts int
+	svc.SetBroadcastFn(func(msg any) error {
+		broadcasts++
+		return nil
+	})
+
+	req := &AcquireLockRequest{TargetType: TargetFile, FilePath: "/test/check.go", StartLine: 10, EndLine: 20}
+	check, err := svc.CheckLock(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.Free || len(check.Conflicts) != 0 || check.ExpectedWait != 0 {
+		t.Fatalf("expected a free region, got %+v", check)
+	}
+
+	soon := NewSemanticLock(&SemanticTarget{Type: TargetFile, FilePath: "/test/check.go", StartLine: 1, EndLine: 12}, "node-2", "Peer", "fix imports")
+	soon.ExpiresAt = time.Now().Add(10 * time.Second)
+	late := NewSemanticLock(&SemanticTarget{Type: TargetFile, FilePath: "/test/check.go", StartLine: 18, EndLine: 30}, "node-3", "Other", "refactor")
+	late.ExpiresAt = time.Now().Add(time.Minute)
+	apart := NewSemanticLock(&SemanticTarget{Type: TargetFile, FilePath: "/test/check.go", StartLine: 40, EndLine: 50}, "node-3", "Other", "docs")
+	for _, l := range []*SemanticLock{late, soon, apart} {
+		if err := svc.HandleRemoteLockAcquired(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check, err = svc.CheckLock(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Free || len(check.Conflicts) != 2 {
+		t.Fatalf("expected the two overlapping locks, got %+v", check)
+	}
+	if check.Conflicts[0].ID != soon.ID || check.Conflicts[1].ID != late.ID {
+		t.Errorf("expected conflicts soonest to expire first, got %s, %s", check.Conflicts[0].HolderName, check.Conflicts[1].HolderName)
+	}
+	if check.ExpectedWait < 50*time.Second || check.ExpectedWait > time.Minute {
+		t.Errorf("expected to wait for the later lock, got %v", check.ExpectedWait)
+	}
+
+	if broadcasts != 0 {
+		t.Errorf("a check should not announce anything, got %d broadcasts", broadcasts)
+	}
+	if counts := svc.store.ActionCounts(); counts["conflict"] != 0 {
+		t.Errorf("a check should not record conflicts, got %v", counts)
+	}
+	if stats := svc.RateLimitStats(); len(stats.Holders) != 0 {
+		t.Errorf("a check should not count against the rate limit, got %+v", stats.Holders)
+	}
+}
@@ -3,6 +3,7 @@ package lock
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	pkgerrors "agent-collab/src/pkg/errors"
 )
@@ -24,6 +25,14 @@ var (
 	// ErrMaxRenewalsExceeded indicates the maximum renewal count was exceeded.
 	ErrMaxRenewalsExceeded = errors.New("max renewals exceeded")
 
+	// ErrMaxLeaseExceeded indicates the lock has been held for the maximum
+	// lease and cannot be renewed further.
+	ErrMaxLeaseExceeded = errors.New("max lease exceeded")
+
+	// ErrStaleFencingToken indicates the caller's fencing token is not the
+	// one of the lock it names, e.g. the lock was lost and taken again.
+	ErrStaleFencingToken = errors.New("stale fencing token")
+
 	// ErrInvalidTarget indicates the target is invalid.
 	ErrInvalidTarget = errors.New("invalid target")
 
@@ -36,13 +45,57 @@ var (
 	// ErrSessionNotFound indicates the negotiation session was not found.
 	ErrSessionNotFound = errors.New("session not found")
 
+	// ErrSessionCancelled indicates the negotiation session was cancelled.
+	ErrSessionCancelled = errors.New("session cancelled")
+
 	// ErrIntentNotFound indicates the lock intent was not found.
 	ErrIntentNotFound = errors.New("intent not found")
 
 	// ErrRateLimited indicates the request was rate limited.
 	ErrRateLimited = errors.New("rate limited: too many requests")
+
+	// ErrObserverMode indicates the operation is not permitted for observer nodes.
+	ErrObserverMode = errors.New("operation not permitted in observer mode")
+
+	// ErrUnauthorized indicates the caller is not authorized for the operation.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrReasonRequired indicates a reason must be given for the operation.
+	ErrReasonRequired = errors.New("reason required")
+
+	// ErrIntentionRequired indicates an intention must be given for the operation.
+	ErrIntentionRequired = errors.New("intention required")
 )
 
+// RateLimitError is returned when a request is rate limited. It matches
+// ErrRateLimited with errors.Is and tells the caller when to retry.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s (retry after %s)", ErrRateLimited, e.RetryAfter)
+}
+
+// Unwrap returns ErrRateLimited.
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// Category returns the retryable category.
+func (e *RateLimitError) Category() pkgerrors.Category {
+	return pkgerrors.CategoryRetryable
+}
+
+// RetryAfterHint returns the retry hint carried by a rate limit error.
+func RetryAfterHint(err error) (time.Duration, bool) {
+	var rlErr *RateLimitError
+	if errors.As(err, &rlErr) {
+		return rlErr.RetryAfter, true
+	}
+	return 0, false
+}
+
 // LockError represents a lock-related error with context and category.
 type LockError struct {
 	Code     string
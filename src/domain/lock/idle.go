@@ -0,0 +1,223 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IdleConfig configures auto-release of locks whose holder stays connected
+// but inactive. Unlike the TTL, which heartbeats keep extending, the idle
+// window only resets on activity: renewals and TouchLock/TouchFile calls.
+type IdleConfig struct {
+	// Window is how long a lock may go without activity before its holder
+	// is warned (0 = disabled).
+	Window time.Duration `json:"window"`
+	// Grace is how long after the warning the lock is released unless the
+	// holder becomes active again.
+	Grace time.Duration `json:"grace"`
+	// CheckInterval is how often locks are checked.
+	CheckInterval time.Duration `json:"check_interval"`
+}
+
+// DefaultIdleConfig returns the default idle configuration, which is
+// disabled until a window is set.
+func DefaultIdleConfig() IdleConfig {
+	return IdleConfig{
+		Grace:         time.Minute,
+		CheckInterval: 10 * time.Second,
+	}
+}
+
+// Enabled reports whether idle detection is on.
+func (c IdleConfig) Enabled() bool {
+	return c.Window > 0
+}
+
+// withDefaults fills in unset fields.
+func (c IdleConfig) withDefaults() IdleConfig {
+	def := DefaultIdleConfig()
+	if c.Grace <= 0 {
+		c.Grace = def.Grace
+	}
+	if c.CheckInterval <= 0 {
+		c.CheckInterval = def.CheckInterval
+	}
+	return c
+}
+
+// IdleNotice describes an idle lock that was warned about or released.
+type IdleNotice struct {
+	LockID     string        `json:"lock_id"`
+	HolderID   string        `json:"holder_id"`
+	HolderName string        `json:"holder_name"`
+	Target     string        `json:"target"`
+	IdleFor    time.Duration `json:"idle_for"`
+	// Released is false for the warning and true once the lock is released.
+	Released bool `json:"released"`
+	// ReleaseAt is when the lock will be released if it stays idle.
+	ReleaseAt time.Time `json:"release_at"`
+}
+
+func newIdleNotice(lock *SemanticLock, idleFor time.Duration, releaseAt time.Time, released bool) *IdleNotice {
+	return &IdleNotice{
+		LockID:     lock.ID,
+		HolderID:   lock.HolderID,
+		HolderName: lock.HolderName,
+		Target:     lock.Target.String(),
+		IdleFor:    idleFor,
+		Released:   released,
+		ReleaseAt:  releaseAt,
+	}
+}
+
+// SetIdleConfig enables, reconfigures or (with a zero window) disables idle
+// lock detection for the locks this node holds.
+func (s *LockService) SetIdleConfig(cfg IdleConfig) {
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+
+	if s.idleCancel != nil {
+		s.idleCancel()
+		s.idleCancel = nil
+	}
+	s.idleConfig = cfg.withDefaults()
+	s.idleWarned = make(map[string]time.Time)
+	if !s.idleConfig.Enabled() {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.store.ctx)
+	s.idleCancel = cancel
+	go s.watchIdleLocks(ctx, s.idleConfig.CheckInterval)
+}
+
+// IdleConfig returns the current idle configuration.
+func (s *LockService) IdleConfig() IdleConfig {
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	return s.idleConfig
+}
+
+// SetIdleHandler sets the callback invoked when an idle lock is warned
+// about and when it is released.
+func (s *LockService) SetIdleHandler(handler func(*IdleNotice)) {
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	s.onIdle = handler
+}
+
+// TouchLock records activity on a lock held by this node.
+func (s *LockService) TouchLock(lockID string) error {
+	lock, err := s.store.Get(lockID)
+	if err != nil {
+		return err
+	}
+	if lock.HolderID != s.nodeID {
+		return ErrNotLockHolder
+	}
+
+	lock.Touch()
+	s.clearIdleWarning(lockID)
+	return nil
+}
+
+// TouchFile records activity on every lock this node holds in filePath.
+func (s *LockService) TouchFile(filePath string) {
+	for _, lock := range s.store.ListByHolder(s.nodeID) {
+		if lock.Target.FilePath == filePath {
+			lock.Touch()
+			s.clearIdleWarning(lock.ID)
+		}
+	}
+}
+
+func (s *LockService) stopIdleWatcher() {
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	if s.idleCancel != nil {
+		s.idleCancel()
+		s.idleCancel = nil
+	}
+}
+
+func (s *LockService) clearIdleWarning(lockID string) {
+	s.idleMu.Lock()
+	defer s.idleMu.Unlock()
+	delete(s.idleWarned, lockID)
+}
+
+func (s *LockService) watchIdleLocks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.checkIdleLocks(ctx, now)
+		}
+	}
+}
+
+// checkIdleLocks warns about locks idle for the window and releases locks
+// still idle once the grace period after the warning has passed.
+func (s *LockService) checkIdleLocks(ctx context.Context, now time.Time) {
+	s.idleMu.Lock()
+	cfg := s.idleConfig
+	handler := s.onIdle
+	s.idleMu.Unlock()
+
+	if !cfg.Enabled() {
+		return
+	}
+
+	held := make(map[string]struct{})
+	for _, lock := range s.store.ListByHolder(s.nodeID) {
+		held[lock.ID] = struct{}{}
+
+		idleFor := lock.IdleFor(now)
+		if idleFor < cfg.Window {
+			s.clearIdleWarning(lock.ID)
+			continue
+		}
+
+		s.idleMu.Lock()
+		warnedAt, warned := s.idleWarned[lock.ID]
+		if !warned {
+			warnedAt = now
+			s.idleWarned[lock.ID] = now
+		}
+		s.idleMu.Unlock()
+
+		releaseAt := warnedAt.Add(cfg.Grace)
+		if !warned {
+			if handler != nil {
+				handler(newIdleNotice(lock, idleFor, releaseAt, false))
+			}
+			continue
+		}
+		if now.Before(releaseAt) {
+			continue
+		}
+
+		reason := fmt.Sprintf("idle for %s", idleFor.Round(time.Second))
+		if _, err := s.negotiator.ReleaseIdleLock(ctx, lock.ID, s.nodeID, reason); err != nil {
+			continue
+		}
+		s.clearIdleWarning(lock.ID)
+		if handler != nil {
+			handler(newIdleNotice(lock, idleFor, releaseAt, true))
+		}
+	}
+
+	// Forget warnings for locks that are gone
+	s.idleMu.Lock()
+	for id := range s.idleWarned {
+		if _, ok := held[id]; !ok {
+			delete(s.idleWarned, id)
+		}
+	}
+	s.idleMu.Unlock()
+}
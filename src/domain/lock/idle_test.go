@@ -0,0 +1,121 @@
+package lock
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockService_IdleActiveLockSurvives(t *testing.T) {
+	svc := newLockTestService(t)
+	var notices []*IdleNotice
+	svc.SetIdleHandler(func(n *IdleNotice) { notices = append(notices, n) })
+	svc.SetIdleConfig(IdleConfig{Window: time.Minute, Grace: 30 * time.Second, CheckInterval: time.Hour})
+	lock := acquireTestLock(t, svc, "/test/idle.go")
+	ctx := context.Background()
+
+	svc.checkIdleLocks(ctx, time.Now().Add(30*time.Second))
+
+	if len(notices) != 0 {
+		t.Errorf("active lock should not be warned, got %d notices", len(notices))
+	}
+	if _, err := svc.GetLock(lock.ID); err != nil {
+		t.Errorf("active lock should be kept: %v", err)
+	}
+}
+
+func TestLockService_IdleLockWarnedThenReleased(t *testing.T) {
+	svc := newLockTestService(t)
+	var notices []*IdleNotice
+	svc.SetIdleHandler(func(n *IdleNotice) { notices = append(notices, n) })
+	svc.SetIdleConfig(IdleConfig{Window: time.Minute, Grace: 30 * time.Second, CheckInterval: time.Hour})
+	lock := acquireTestLock(t, svc, "/test/idle.go")
+	ctx := context.Background()
+
+	var broadcasts []any
+	svc.SetBroadcastFn(func(msg any) error {
+		broadcasts = append(broadcasts, msg)
+		return nil
+	})
+
+	// Idle past the window: warned but still held
+	warnAt := time.Now().Add(2 * time.Minute)
+	svc.checkIdleLocks(ctx, warnAt)
+
+	if len(notices) != 1 || notices[0].Released {
+		t.Fatalf("expected one warning, got %+v", notices)
+	}
+	if got := notices[0].ReleaseAt; !got.Equal(warnAt.Add(30 * time.Second)) {
+		t.Errorf("expected release at warning + grace, got %v", got)
+	}
+	if _, err := svc.GetLock(lock.ID); err != nil {
+		t.Fatalf("warned lock should still be held: %v", err)
+	}
+
+	// Within the grace period: no second warning, no release
+	svc.checkIdleLocks(ctx, warnAt.Add(10*time.Second))
+	if len(notices) != 1 {
+		t.Errorf("expected a single warning, got %d notices", len(notices))
+	}
+
+	// Still idle after the grace period: released with an audit entry
+	svc.checkIdleLocks(ctx, warnAt.Add(31*time.Second))
+
+	if len(notices) != 2 || !notices[1].Released {
+		t.Fatalf("expected release notice, got %+v", notices)
+	}
+	if _, err := svc.GetLock(lock.ID); err != ErrLockNotFound {
+		t.Errorf("expected idle lock to be released, got %v", err)
+	}
+
+	history := svc.GetHistory(1)
+	if len(history) != 1 || history[0].Action != "idle_released" || history[0].LockID != lock.ID {
+		t.Fatalf("expected idle_released history entry, got %+v", history)
+	}
+	if !strings.HasPrefix(history[0].Reason, "idle for") {
+		t.Errorf("expected idle reason, got %q", history[0].Reason)
+	}
+
+	if len(broadcasts) != 1 {
+		t.Fatalf("expected release to be broadcast, got %d messages", len(broadcasts))
+	}
+	if msg, ok := broadcasts[0].(ReleaseMessage); !ok || msg.LockID != lock.ID {
+		t.Errorf("expected release message for %s, got %+v", lock.ID, broadcasts[0])
+	}
+}
+
+func TestLockService_IdleActivityCancelsWarning(t *testing.T) {
+	svc := newLockTestService(t)
+	var notices []*IdleNotice
+	svc.SetIdleHandler(func(n *IdleNotice) { notices = append(notices, n) })
+	svc.SetIdleConfig(IdleConfig{Window: time.Minute, Grace: 30 * time.Second, CheckInterval: time.Hour})
+	lock := acquireTestLock(t, svc, "/test/idle.go")
+	ctx := context.Background()
+
+	lock.LastActivity = time.Now().Add(-2 * time.Minute)
+	svc.checkIdleLocks(ctx, time.Now())
+	if len(notices) != 1 {
+		t.Fatalf("expected a warning, got %d notices", len(notices))
+	}
+
+	// The holder edits the file again
+	svc.TouchFile("/test/idle.go")
+
+	svc.checkIdleLocks(ctx, time.Now().Add(45*time.Second))
+	if len(notices) != 1 {
+		t.Errorf("active lock should not be released, got %+v", notices)
+	}
+	if _, err := svc.GetLock(lock.ID); err != nil {
+		t.Errorf("lock should survive after activity: %v", err)
+	}
+}
+
+func TestLockService_IdleDisabledByDefault(t *testing.T) {
+	svc := NewLockService(context.Background(), "node-1", "Agent")
+	defer svc.Close()
+
+	if svc.IdleConfig().Enabled() {
+		t.Error("idle detection should be disabled by default")
+	}
+}
@@ -0,0 +1,82 @@
+package lock
+
+import (
+	"context"
+	"time"
+)
+
+// RenewLeaseRequest is a request to extend the lease of a held lock.
+type RenewLeaseRequest struct {
+	LockID string `json:"lock_id"`
+	// FencingToken is the token the caller got when acquiring the lock.
+	// When set, the renewal fails if the lock now carries another token,
+	// so a caller that lost the lock cannot extend its replacement.
+	FencingToken uint64 `json:"fencing_token,omitempty"`
+	// TTL is the new lease, counted from now (default DefaultTTL, at most
+	// MaxTTL).
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// RenewMessage announces a lease renewal so peers keep the lock alive
+// instead of expiring it on the old lease.
+type RenewMessage struct {
+	Type         string    `json:"type"`
+	LockID       string    `json:"lock_id"`
+	HolderID     string    `json:"holder_id"`
+	FencingToken uint64    `json:"fencing_token"`
+	RenewedAt    time.Time `json:"renewed_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RenewCount   int       `json:"renew_count"`
+}
+
+// SetMaxLease limits how long a lock may be held through renewals,
+// measured from acquisition. Renewals are cut short at the limit and
+// refused once it is reached. 0 removes the limit.
+func (s *LockService) SetMaxLease(d time.Duration) {
+	s.maxLease.Store(int64(max(d, 0)))
+}
+
+// MaxLease returns the renewal limit (0 = no limit).
+func (s *LockService) MaxLease() time.Duration {
+	return time.Duration(s.maxLease.Load())
+}
+
+// RenewLease extends the lease of a lock held by this node and announces
+// the new expiry to peers.
+func (s *LockService) RenewLease(ctx context.Context, req *RenewLeaseRequest) (*SemanticLock, error) {
+	held, err := s.store.Get(req.LockID)
+	if err != nil {
+		return nil, err
+	}
+	if held.HolderID != s.nodeID {
+		return nil, ErrNotLockHolder
+	}
+
+	renewed, err := s.store.Renew(req.LockID, req.FencingToken, req.TTL, s.MaxLease())
+	if err != nil {
+		return nil, err
+	}
+
+	if fn := s.negotiator.broadcastFn; fn != nil {
+		_ = fn(RenewMessage{
+			Type:         "lock_renewed",
+			LockID:       renewed.ID,
+			HolderID:     renewed.HolderID,
+			FencingToken: renewed.FencingToken,
+			RenewedAt:    renewed.RenewedAt,
+			ExpiresAt:    renewed.ExpiresAt,
+			RenewCount:   renewed.RenewCount,
+		})
+	}
+	return renewed, nil
+}
+
+// HandleRemoteLockRenewed extends a peer's lock as announced by its holder.
+// It reports whether the local copy changed; unknown locks and stale
+// announcements are ignored.
+func (s *LockService) HandleRemoteLockRenewed(msg *RenewMessage) bool {
+	if msg.HolderID == s.nodeID {
+		return false
+	}
+	return s.store.ApplyRenewal(msg)
+}
@@ -0,0 +1,80 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLockService_RenewLeaseChecksFencingTokenAndBroadcasts(t *testing.T) {
+	svc := newLockTestService(t)
+	held := acquireTestLock(t, svc, "/test/lease.go")
+	ctx := context.Background()
+
+	var sent []RenewMessage
+	svc.SetBroadcastFn(func(msg any) error {
+		if m, ok := msg.(RenewMessage); ok {
+			sent = append(sent, m)
+		}
+		return nil
+	})
+
+	if _, err := svc.RenewLease(ctx, &RenewLeaseRequest{LockID: held.ID, FencingToken: held.FencingToken + 1}); err != ErrStaleFencingToken {
+		t.Fatalf("expected a stale token to be refused, got %v", err)
+	}
+	if len(sent) != 0 {
+		t.Fatalf("a refused renewal should not be announced, got %d", len(sent))
+	}
+
+	renewed, err := svc.RenewLease(ctx, &RenewLeaseRequest{LockID: held.ID, FencingToken: held.FencingToken, TTL: 2 * time.Minute})
+	if err != nil {
+		t.Fatalf("RenewLease failed: %v", err)
+	}
+	if renewed.RenewCount != 1 || renewed.TTLRemaining() < time.Minute {
+		t.Errorf("expected a two minute lease, got %v remaining after %d renewals", renewed.TTLRemaining(), renewed.RenewCount)
+	}
+	if len(sent) != 1 || sent[0].Type != "lock_renewed" || !sent[0].ExpiresAt.Equal(renewed.ExpiresAt) {
+		t.Fatalf("expected the renewal to be announced, got %+v", sent)
+	}
+
+	// A peer holding the old lease moves to the new expiry
+	peer := NewLockService(ctx, "node-2", "Peer")
+	defer peer.Close()
+	remote := *held
+	remote.ExpiresAt = held.AcquiredAt.Add(DefaultTTL)
+	remote.RenewCount = 0
+	if err := peer.HandleRemoteLockAcquired(&remote); err != nil {
+		t.Fatal(err)
+	}
+	if !peer.HandleRemoteLockRenewed(&sent[0]) {
+		t.Fatal("expected the peer to apply the renewal")
+	}
+	if got, _ := peer.GetLock(held.ID); !got.ExpiresAt.Equal(renewed.ExpiresAt) {
+		t.Errorf("expected the peer's copy to expire at %v, got %v", renewed.ExpiresAt, got.ExpiresAt)
+	}
+
+	stale := sent[0]
+	stale.FencingToken++
+	if peer.HandleRemoteLockRenewed(&stale) {
+		t.Error("a renewal for another grant of the lock should be ignored")
+	}
+}
+
+func TestLockService_RenewLeaseStopsAtMaxLease(t *testing.T) {
+	svc := newLockTestService(t)
+	held := acquireTestLock(t, svc, "/test/lease.go")
+	ctx := context.Background()
+	svc.SetMaxLease(45 * time.Second)
+
+	renewed, err := svc.RenewLease(ctx, &RenewLeaseRequest{LockID: held.ID, TTL: time.Minute})
+	if err != nil {
+		t.Fatalf("RenewLease failed: %v", err)
+	}
+	if want := held.AcquiredAt.Add(45 * time.Second); !renewed.ExpiresAt.Equal(want) {
+		t.Errorf("expected the lease to be cut at %v, got %v", want, renewed.ExpiresAt)
+	}
+
+	if _, err := svc.RenewLease(ctx, &RenewLeaseRequest{LockID: held.ID}); err != ErrMaxLeaseExceeded {
+		t.Errorf("expected renewals past the max lease to fail, got %v", err)
+	}
+}
@@ -108,6 +108,13 @@ func (s *LocalFirstLockService) AcquireLockOptimistic(ctx context.Context, req *
 		return s.AcquireLock(ctx, req)
 	}
 
+	if s.IsObserver() {
+		return &LockResult{
+			Success: false,
+			Reason:  ErrObserverMode.Error(),
+		}, ErrObserverMode
+	}
+
 	target, err := NewSemanticTarget(
 		req.TargetType,
 		req.FilePath,
@@ -122,8 +129,16 @@ func (s *LocalFirstLockService) AcquireLockOptimistic(ctx context.Context, req *
 		}, err
 	}
 
+	mode, err := ParseAccessMode(string(req.Mode))
+	if err != nil {
+		return &LockResult{
+			Success: false,
+			Reason:  err.Error(),
+		}, err
+	}
+
 	// Check for local conflicts first (< 1ms)
-	localConflicts := s.store.FindConflicts(target)
+	localConflicts := s.store.FindConflictsFor(target, mode)
 	if len(localConflicts) > 0 {
 		return &LockResult{
 			Success: false,
@@ -133,6 +148,13 @@ func (s *LocalFirstLockService) AcquireLockOptimistic(ctx context.Context, req *
 
 	// Create and store lock locally (< 1ms)
 	lock := NewSemanticLock(target, s.nodeID, s.nodeName, req.Intention)
+	lock.Mode = mode
+	if lock.ExpiresAt, err = req.ExpiresAt(lock.AcquiredAt); err != nil {
+		return &LockResult{
+			Success: false,
+			Reason:  err.Error(),
+		}, err
+	}
 	if err := s.store.Add(lock); err != nil {
 		return &LockResult{
 			Success: false,
@@ -293,7 +315,7 @@ func (s *LocalFirstLockService) HandleRemoteLockAcquired(remoteLock *SemanticLoc
 	// Check if this conflicts with any pending locks
 	s.mu.RLock()
 	for _, pending := range s.pendingLocks {
-		if pending.Status == StatusPending && pending.Lock.Target.Overlaps(remoteLock.Target) {
+		if pending.Status == StatusPending && pending.Lock.ConflictsWith(remoteLock) {
 			// Conflict detected - use NewLockConflict for proper initialization
 			conflict := NewLockConflict(pending.Lock, remoteLock)
 			select {
@@ -3,6 +3,8 @@ package lock
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
+	"strings"
 	"sync/atomic"
 	"time"
 )
@@ -16,15 +18,48 @@ const (
 
 // SemanticLock은 시맨틱 락입니다.
 type SemanticLock struct {
-	ID           string          `json:"id"`
-	Target       *SemanticTarget `json:"target"`
-	HolderID     string          `json:"holder_id"`
-	HolderName   string          `json:"holder_name"`
-	Intention    string          `json:"intention"`
-	FencingToken uint64          `json:"fencing_token"`
-	AcquiredAt   time.Time       `json:"acquired_at"`
-	ExpiresAt    time.Time       `json:"expires_at"`
-	RenewCount   int             `json:"renew_count"`
+	ID         string          `json:"id"`
+	Target     *SemanticTarget `json:"target"`
+	HolderID   string          `json:"holder_id"`
+	HolderName string          `json:"holder_name"`
+	Intention  string          `json:"intention"`
+	// Mode는 읽기(공유) 또는 쓰기(배타) 락입니다. 비어 있으면 쓰기 락입니다.
+	Mode         AccessMode `json:"mode,omitempty"`
+	FencingToken uint64     `json:"fencing_token"`
+	AcquiredAt   time.Time  `json:"acquired_at"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RenewCount   int        `json:"renew_count"`
+	RenewedAt    time.Time  `json:"renewed_at,omitzero"`
+	LastActivity time.Time  `json:"last_activity"`
+}
+
+// AccessMode는 락의 공유 방식(읽기/쓰기)입니다.
+type AccessMode string
+
+const (
+	// AccessWrite는 겹치는 다른 락과 공존하지 않는 배타 락입니다 (기본값).
+	AccessWrite AccessMode = "write"
+	// AccessRead는 겹치는 다른 읽기 락과 공존하는 공유 락입니다.
+	AccessRead AccessMode = "read"
+)
+
+// ParseAccessMode는 "read"/"shared" 또는 "write"/"exclusive"를 해석합니다.
+// 빈 값은 쓰기 락입니다.
+func ParseAccessMode(s string) (AccessMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "write", "exclusive":
+		return AccessWrite, nil
+	case "read", "shared":
+		return AccessRead, nil
+	default:
+		return "", NewValidationError("mode", fmt.Sprintf("unknown lock mode %q (want read or write)", s))
+	}
+}
+
+// compatible은 두 모드의 락이 같은 영역에 공존할 수 있는지 반환합니다.
+// 읽기 락끼리만 공존합니다.
+func compatible(a, b AccessMode) bool {
+	return a == AccessRead && b == AccessRead
 }
 
 // 전역 fencing token 카운터
@@ -63,13 +98,38 @@ func NewSemanticLockSafe(target *SemanticTarget, holderID, holderName, intention
 		HolderID:     holderID,
 		HolderName:   holderName,
 		Intention:    intention,
+		Mode:         AccessWrite,
 		FencingToken: atomic.AddUint64(&fencingTokenCounter, 1),
 		AcquiredAt:   now,
 		ExpiresAt:    now.Add(DefaultTTL),
 		RenewCount:   0,
+		LastActivity: now,
 	}, nil
 }
 
+// Shared는 읽기 락인지 반환합니다. Mode가 없는 락(이전 버전 피어)은
+// 쓰기 락으로 취급합니다.
+func (l *SemanticLock) Shared() bool {
+	return l.Mode == AccessRead
+}
+
+// EffectiveMode는 락의 모드를 반환하며, 비어 있으면 AccessWrite입니다.
+func (l *SemanticLock) EffectiveMode() AccessMode {
+	if l.Shared() {
+		return AccessRead
+	}
+	return AccessWrite
+}
+
+// ConflictsWith는 두 락이 겹치고 둘 중 하나라도 쓰기 락이면 true를
+// 반환합니다. 같은 락끼리는 충돌하지 않습니다.
+func (l *SemanticLock) ConflictsWith(other *SemanticLock) bool {
+	if l.ID == other.ID {
+		return false
+	}
+	return l.Target.Overlaps(other.Target) && !compatible(l.Mode, other.Mode)
+}
+
 // IsExpired는 락이 만료되었는지 확인합니다.
 func (l *SemanticLock) IsExpired() bool {
 	return time.Now().After(l.ExpiresAt)
@@ -86,30 +146,68 @@ func (l *SemanticLock) TTLRemaining() time.Duration {
 
 // Renew는 락을 갱신합니다.
 func (l *SemanticLock) Renew() error {
-	if l.RenewCount >= MaxRenewals {
-		return ErrMaxRenewalsExceeded
-	}
-
-	l.ExpiresAt = time.Now().Add(DefaultTTL)
-	l.RenewCount++
-	return nil
+	return l.renew(time.Now(), DefaultTTL, 0)
 }
 
 // RenewWithTTL은 지정된 TTL로 락을 갱신합니다.
 func (l *SemanticLock) RenewWithTTL(ttl time.Duration) error {
+	return l.renew(time.Now(), ttl, 0)
+}
+
+// renew는 임대를 now부터 ttl만큼 연장합니다. maxLease가 0보다 크면
+// 획득 시점부터 maxLease를 넘겨 연장하지 않습니다.
+func (l *SemanticLock) renew(now time.Time, ttl, maxLease time.Duration) error {
 	if l.RenewCount >= MaxRenewals {
 		return ErrMaxRenewalsExceeded
 	}
 
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
 	if ttl > MaxTTL {
 		ttl = MaxTTL
 	}
 
-	l.ExpiresAt = time.Now().Add(ttl)
+	expiresAt := now.Add(ttl)
+	if maxLease > 0 {
+		limit := l.AcquiredAt.Add(maxLease)
+		if !limit.After(l.ExpiresAt) {
+			return ErrMaxLeaseExceeded
+		}
+		if expiresAt.After(limit) {
+			expiresAt = limit
+		}
+	}
+
+	l.RenewedAt = now
+	l.ExpiresAt = expiresAt
 	l.RenewCount++
+	l.LastActivity = now
 	return nil
 }
 
+// LeaseStart는 현재 임대 기간의 시작 시각(마지막 갱신 또는 획득)을 반환합니다.
+func (l *SemanticLock) LeaseStart() time.Time {
+	if !l.RenewedAt.IsZero() {
+		return l.RenewedAt
+	}
+	return l.AcquiredAt
+}
+
+// Touch는 락 보유자의 활동을 기록합니다.
+func (l *SemanticLock) Touch() {
+	l.LastActivity = time.Now()
+}
+
+// IdleFor는 마지막 활동 이후 경과 시간을 반환합니다.
+func (l *SemanticLock) IdleFor(now time.Time) time.Duration {
+	last := l.LastActivity
+	if last.IsZero() {
+		last = l.AcquiredAt
+	}
+	return now.Sub(last)
+}
+
 // Lock ID prefix constant
 const lockIDPrefix = "lock-"
 
@@ -139,6 +237,12 @@ type LockResult struct {
 	Success bool
 	Lock    *SemanticLock
 	Reason  string
+	// ConflictHint는 최근 활동으로 예측한 충돌 가능성입니다.
+	ConflictHint *ConflictHint
+	// Promoted는 영역 락 요청이 심볼 락으로 승격되었는지 여부입니다.
+	Promoted bool
+	// Preempted는 충돌 정책에 따라 이 락에 밀려 해제된 락들입니다.
+	Preempted []*ForceReleaseNotice
 }
 
 // LockConflict는 락 충돌 정보입니다.
@@ -1,6 +1,11 @@
 package lock
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -8,6 +13,49 @@ import (
 	pkgerrors "agent-collab/src/pkg/errors"
 )
 
+// newLockTestService returns node-1's ("Agent") service, closed when the
+// test ends.
+func newLockTestService(t *testing.T) *LockService {
+	t.Helper()
+
+	svc := NewLockService(context.Background(), "node-1", "Agent")
+	t.Cleanup(func() { svc.Close() })
+	return svc
+}
+
+// acquireTestLock acquires lines 1-10 of filePath for node-1.
+func acquireTestLock(t *testing.T, svc *LockService, filePath string) *SemanticLock {
+	t.Helper()
+
+	result, err := svc.AcquireLock(context.Background(), &AcquireLockRequest{
+		TargetType: TargetFile,
+		FilePath:   filePath,
+		StartLine:  1,
+		EndLine:    10,
+		Intention:  "edit",
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	return result.Lock
+}
+
+// holdRemoteTestLock records node-2's ("Peer") lock on lines 1-20 of
+// filePath as announced to svc.
+func holdRemoteTestLock(t *testing.T, svc *LockService, filePath string) *SemanticLock {
+	t.Helper()
+
+	target, err := NewSemanticTarget(TargetFile, filePath, "", 1, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	held := NewSemanticLock(target, "node-2", "Peer", "refactor")
+	if err := svc.HandleRemoteLockAcquired(held); err != nil {
+		t.Fatal(err)
+	}
+	return held
+}
+
 func TestNewSemanticLockSafe_Valid(t *testing.T) {
 	target := &SemanticTarget{
 		Type:      "file",
@@ -240,3 +288,1180 @@ func TestValidationError_Category(t *testing.T) {
 		t.Error("expected validation error to have validation category")
 	}
 }
+
+func TestLockService_ObserverHoldsNoLocks(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "observer-node", "Observer")
+	defer svc.Close()
+
+	svc.SetObserver(true)
+	if !svc.IsObserver() {
+		t.Fatal("expected service to be in observer mode")
+	}
+
+	result, err := svc.AcquireLock(ctx, &AcquireLockRequest{
+		TargetType: TargetFile,
+		FilePath:   "/test/observer.go",
+		StartLine:  1,
+		EndLine:    10,
+		Intention:  "should not be allowed",
+	})
+	if !errors.Is(err, ErrObserverMode) {
+		t.Fatalf("expected ErrObserverMode, got: %v", err)
+	}
+	if result == nil || result.Success {
+		t.Error("expected unsuccessful lock result")
+	}
+	if len(svc.ListMyLocks()) != 0 {
+		t.Errorf("observer should hold no locks, got %d", len(svc.ListMyLocks()))
+	}
+	if err := svc.Vote(ctx, "neg-any", true, "observer vote"); !errors.Is(err, ErrObserverMode) {
+		t.Errorf("expected ErrObserverMode on vote, got: %v", err)
+	}
+}
+
+func TestLockStore_ReannounceKeepsHolder(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+
+	target := &SemanticTarget{Type: TargetFile, FilePath: "/test/owned.go", StartLine: 1, EndLine: 20}
+	held, _ := NewSemanticLockSafe(target, "node-a", "A", "holding")
+	if err := store.Add(held); err != nil {
+		t.Fatalf("failed to add lock: %v", err)
+	}
+
+	// Another node re-announces the lock ID as its own, over another range
+	forged := *held
+	forged.HolderID, forged.HolderName = "node-b", "B"
+	forged.Target = &SemanticTarget{Type: TargetFile, FilePath: "/test/owned.go", StartLine: 100, EndLine: 120}
+	if err := store.Add(&forged); !errors.Is(err, ErrNotLockHolder) {
+		t.Fatalf("expected ErrNotLockHolder, got %v", err)
+	}
+	// ... or over the same range
+	forged.Target = held.Target
+	if err := store.Add(&forged); !errors.Is(err, ErrNotLockHolder) {
+		t.Fatalf("expected ErrNotLockHolder, got %v", err)
+	}
+
+	current, err := store.Get(held.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.HolderID != "node-a" || current.Target.StartLine != 1 || current.Target.EndLine != 20 {
+		t.Errorf("expected node-a to keep lines 1-20, got %s at %d-%d", current.HolderID, current.Target.StartLine, current.Target.EndLine)
+	}
+
+	// The holder itself may re-announce it, e.g. after a restart
+	again := *held
+	if err := store.Add(&again); err != nil {
+		t.Errorf("expected the holder's re-announcement to be accepted, got %v", err)
+	}
+}
+
+func TestLockNegotiator_ObserverExcludedFromQuorum(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	target := &SemanticTarget{Type: TargetFile, FilePath: "/test/quorum.go", StartLine: 1, EndLine: 50}
+	held, _ := NewSemanticLockSafe(target, "agent-a", "A", "holding")
+	if err := store.Add(held); err != nil {
+		t.Fatalf("failed to add lock: %v", err)
+	}
+
+	requestedTarget := &SemanticTarget{Type: TargetFile, FilePath: "/test/quorum.go", StartLine: 10, EndLine: 20}
+	requested, _ := NewSemanticLockSafe(requestedTarget, "agent-b", "B", "requesting")
+	if _, err := n.AnnounceIntent(ctx, requested); err == nil {
+		t.Fatal("expected conflict to start a negotiation session")
+	}
+
+	sessions := n.ListActiveSessions()
+	if len(sessions) != 1 {
+		t.Fatalf("expected 1 active session, got %d", len(sessions))
+	}
+	session := sessions[0]
+
+	n.AddObserver("observer-node")
+	err := n.Vote(ctx, session.ID, &Vote{VoterID: "observer-node", Approve: true, Timestamp: time.Now()})
+	if !errors.Is(err, ErrObserverMode) {
+		t.Fatalf("expected ErrObserverMode, got: %v", err)
+	}
+	if len(session.Votes) != 0 {
+		t.Errorf("observer vote should not be counted, got %d votes", len(session.Votes))
+	}
+
+	// One participant vote is not enough to reach quorum
+	if err := n.Vote(ctx, session.ID, &Vote{VoterID: "agent-a", Approve: true, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("vote failed: %v", err)
+	}
+	if session.Resolution != nil {
+		t.Error("session should not be resolved before quorum of participants")
+	}
+
+	if err := n.Vote(ctx, session.ID, &Vote{VoterID: "agent-b", Approve: true, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("vote failed: %v", err)
+	}
+	if session.Resolution == nil {
+		t.Error("session should be resolved once participants have voted")
+	}
+}
+
+func TestLockNegotiator_ForceReleaseLock(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	var broadcast []ReleaseMessage
+	n.SetBroadcastFn(func(msg any) error {
+		if m, ok := msg.(ReleaseMessage); ok {
+			broadcast = append(broadcast, m)
+		}
+		return nil
+	})
+
+	target := &SemanticTarget{Type: TargetFile, FilePath: "/test/stuck.go", StartLine: 1, EndLine: 20}
+	held, _ := NewSemanticLockSafe(target, "crashed-agent", "Crashed", "editing")
+	if err := store.Add(held); err != nil {
+		t.Fatalf("failed to add lock: %v", err)
+	}
+
+	// Regular release by a non-holder is still refused
+	if err := n.ReleaseLock(ctx, held.ID, "operator-node"); !errors.Is(err, ErrNotLockHolder) {
+		t.Fatalf("expected ErrNotLockHolder, got: %v", err)
+	}
+
+	if _, err := n.ForceReleaseLock(ctx, held.ID, "agent crashed", ""); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized without operator, got: %v", err)
+	}
+	if _, err := n.ForceReleaseLock(ctx, held.ID, "  ", "alice"); !errors.Is(err, ErrReasonRequired) {
+		t.Fatalf("expected ErrReasonRequired, got: %v", err)
+	}
+	if _, err := store.Get(held.ID); err != nil {
+		t.Fatal("rejected force release must not remove the lock")
+	}
+
+	notice, err := n.ForceReleaseLock(ctx, held.ID, "agent crashed", "alice")
+	if err != nil {
+		t.Fatalf("force release failed: %v", err)
+	}
+	if notice.HolderID != "crashed-agent" || notice.Operator != "alice" {
+		t.Errorf("unexpected notice: %+v", notice)
+	}
+	if _, err := store.Get(held.ID); !errors.Is(err, ErrLockNotFound) {
+		t.Errorf("expected lock to be removed, got: %v", err)
+	}
+
+	history := store.GetHistory(1)
+	if len(history) != 1 || history[0].Action != "force_released" || history[0].Reason != "agent crashed" || history[0].Operator != "alice" {
+		t.Errorf("expected audit entry with reason and operator, got %+v", history)
+	}
+
+	if len(broadcast) != 1 || broadcast[0].Force == nil || broadcast[0].Force.Reason != "agent crashed" {
+		t.Errorf("expected forced release broadcast, got %+v", broadcast)
+	}
+}
+
+func TestLockService_HandleRemoteForceRelease(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "holder-node", "Holder")
+	defer svc.Close()
+
+	result, err := svc.AcquireLock(ctx, &AcquireLockRequest{
+		TargetType: TargetFile,
+		FilePath:   "/test/mine.go",
+		StartLine:  1,
+		EndLine:    10,
+		Intention:  "editing",
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+
+	// A plain remote release never removes our own lock
+	if err := svc.HandleRemoteLockReleased(result.Lock.ID); err != nil {
+		t.Fatalf("remote release failed: %v", err)
+	}
+	if len(svc.ListMyLocks()) != 1 {
+		t.Fatal("plain remote release should keep own lock")
+	}
+
+	err = svc.HandleRemoteForceRelease(&ForceReleaseNotice{
+		LockID:   result.Lock.ID,
+		HolderID: "holder-node",
+		Reason:   "stuck",
+		Operator: "alice",
+	})
+	if err != nil {
+		t.Fatalf("remote force release failed: %v", err)
+	}
+	if len(svc.ListMyLocks()) != 0 {
+		t.Error("force release should remove own lock")
+	}
+}
+
+func TestLockNegotiator_RateLimitedReturnsRetryHint(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+
+	negotiator := NewLockNegotiatorWithConfig(ctx, store, &RateLimitConfig{Rate: 20, Burst: 1, CleanupInterval: time.Minute})
+	defer negotiator.Close()
+
+	announce := func(line int) error {
+		target, _ := NewSemanticTarget(TargetFile, "/test/rate.go", "", line, line)
+		_, err := negotiator.AnnounceIntent(ctx, NewSemanticLock(target, "node-1", "Agent", "edit"))
+		return err
+	}
+
+	if err := announce(1); err != nil {
+		t.Fatalf("first announce should pass: %v", err)
+	}
+
+	err := announce(2)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got: %v", err)
+	}
+	retryAfter, ok := RetryAfterHint(err)
+	if !ok || retryAfter <= 0 || retryAfter > 50*time.Millisecond {
+		t.Fatalf("expected a positive retry hint of at most 50ms, got %v (ok=%v)", retryAfter, ok)
+	}
+
+	time.Sleep(retryAfter)
+	if err := announce(3); err != nil {
+		t.Errorf("announce after the retry hint should pass: %v", err)
+	}
+}
+
+func TestRateLimiter_RetryAfter(t *testing.T) {
+	rl := NewRateLimiter(&RateLimitConfig{Rate: 10, Burst: 1, CleanupInterval: time.Minute})
+
+	if got := rl.RetryAfter("peer"); got != 0 {
+		t.Errorf("unknown peer should not wait, got %v", got)
+	}
+	rl.Allow("peer")
+	if got := rl.RetryAfter("peer"); got <= 0 || got > 100*time.Millisecond {
+		t.Errorf("expected wait of at most 100ms, got %v", got)
+	}
+}
+
+func TestRateLimiter_SetConfig(t *testing.T) {
+	rl := NewRateLimiter(&RateLimitConfig{Rate: 10, Burst: 5, CleanupInterval: time.Minute})
+	rl.Allow("peer")
+
+	rl.SetConfig(&RateLimitConfig{Rate: 0.001, Burst: 1})
+	if stats := rl.Stats(); stats.Rate != 0.001 || stats.Burst != 1 {
+		t.Fatalf("expected rate 0.001 and burst 1, got %+v", stats)
+	}
+	// The existing bucket is capped at the new burst
+	if !rl.Allow("peer") {
+		t.Fatal("expected one token left after shrinking the burst")
+	}
+	if rl.Allow("peer") {
+		t.Error("expected the peer to be limited at the new burst")
+	}
+}
+
+func TestRateLimiter_StatsPerHolder(t *testing.T) {
+	rl := NewRateLimiter(&RateLimitConfig{Rate: 0.001, Burst: 1, CleanupInterval: time.Minute})
+	rl.Allow("peer")
+	rl.Allow("peer")
+	rl.RecordOutcome("peer", true)
+
+	got := rl.Stats().Holders["peer"]
+	if got.Allowed != 1 || got.Denied != 1 || got.Conflicts != 1 {
+		t.Errorf("expected 1 allowed, 1 denied and 1 conflict, got %+v", got)
+	}
+	if got.Rate != 0.001 || got.Burst != 1 {
+		t.Errorf("conflicts should not move the limit outside adaptive mode, got %+v", got)
+	}
+}
+
+func TestRateLimiter_AdaptiveTightensAndLoosens(t *testing.T) {
+	rl := NewRateLimiter(&RateLimitConfig{Rate: 10, Burst: 8, CleanupInterval: time.Minute, Adaptive: true, AdaptiveMin: 0.25, AdaptiveMax: 2})
+
+	for range 3 {
+		rl.RecordOutcome("noisy", true)
+	}
+	if got := rl.Stats().Holders["noisy"]; got.Rate != 2.5 || got.Burst != 2 {
+		t.Errorf("expected conflicts to tighten the limit to the minimum, got %+v", got)
+	}
+
+	for range 100 {
+		rl.RecordOutcome("clean", false)
+	}
+	if got := rl.Stats().Holders["clean"]; got.Rate != 20 || got.Burst != 16 {
+		t.Errorf("expected clean requests to loosen the limit to the maximum, got %+v", got)
+	}
+
+	// A tightened holder runs out of its smaller burst first
+	allowed := 0
+	for range 8 {
+		if rl.Allow("noisy") {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("expected the tightened burst of 2, got %d", allowed)
+	}
+
+	rl.SetConfig(&RateLimitConfig{Rate: 10, Burst: 8})
+	if got := rl.Stats().Holders["noisy"]; got.Rate != 10 || got.Burst != 8 {
+		t.Errorf("expected the base limit once adaptive mode is off, got %+v", got)
+	}
+}
+
+func TestLockNegotiator_Metrics(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	newSession := func(i int) *NegotiationSession {
+		held, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: fmt.Sprintf("/test/m%d.go", i), StartLine: 1, EndLine: 50}, "agent-a", "A", "holding")
+		requested, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: fmt.Sprintf("/test/m%d.go", i), StartLine: 10, EndLine: 20}, "agent-b", "B", "requesting")
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		return n.startNegotiationSession(requested, held)
+	}
+	// took rewinds a resolved session's start so it took d to resolve
+	took := func(s *NegotiationSession, d time.Duration) {
+		s.StartedAt = s.Resolution.ResolvedAt.Add(-d)
+	}
+
+	// Approved by vote in 1s
+	approved := newSession(1)
+	n.Vote(ctx, approved.ID, &Vote{VoterID: "agent-a", Approve: true})
+	n.Vote(ctx, approved.ID, &Vote{VoterID: "agent-b", Approve: true})
+	took(approved, time.Second)
+
+	// Rejected by vote in 3s
+	rejected := newSession(2)
+	n.Vote(ctx, rejected.ID, &Vote{VoterID: "agent-a", Approve: false})
+	n.Vote(ctx, rejected.ID, &Vote{VoterID: "agent-b", Approve: false})
+	took(rejected, 3*time.Second)
+
+	// Escalated to a human in 5s
+	escalated := newSession(3)
+	n.Negotiate(ctx, escalated.ID, &NegotiationProposal{Type: ProposalEscalate, EscalateReason: "both need it"})
+	took(escalated, 5*time.Second)
+
+	// Timed out after 7s
+	timedOut := newSession(4)
+	timedOut.ExpiresAt = time.Now().Add(-time.Second)
+	n.Negotiate(ctx, timedOut.ID, &NegotiationProposal{Type: ProposalPriority})
+	took(timedOut, 7*time.Second)
+
+	// Still negotiating
+	newSession(5)
+
+	m := n.Metrics()
+	if m.TotalSessions != 5 || m.ActiveSessions != 1 || m.ResolvedSessions != 4 {
+		t.Fatalf("unexpected session counts: %+v", m)
+	}
+	want := map[ResolutionType]int{
+		ResolutionApproved:    1,
+		ResolutionRejected:    1,
+		ResolutionHumanNeeded: 1,
+		ResolutionTimedOut:    1,
+	}
+	for rt, count := range want {
+		if m.ByResolution[rt] != count {
+			t.Errorf("expected %d %s, got %d", count, rt, m.ByResolution[rt])
+		}
+	}
+	if m.AvgTimeToResolution != 4*time.Second {
+		t.Errorf("expected 4s average, got %s", m.AvgTimeToResolution)
+	}
+	if m.EscalationRate != 0.5 {
+		t.Errorf("expected escalation rate 0.5, got %v", m.EscalationRate)
+	}
+}
+
+func TestLockNegotiator_SettleEscalation(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	held, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: "/test/esc.go", StartLine: 1, EndLine: 50}, "agent-a", "A", "holding")
+	requested, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: "/test/esc.go", StartLine: 10, EndLine: 20}, "agent-b", "B", "requesting")
+	store.Add(held)
+	n.mu.Lock()
+	session := n.startNegotiationSession(requested, held)
+	n.mu.Unlock()
+
+	if _, err := n.Negotiate(ctx, session.ID, &NegotiationProposal{Type: ProposalEscalate, EscalateReason: "both need it"}); !errors.Is(err, ErrHumanInterventionRequired) {
+		t.Fatalf("expected human intervention, got %v", err)
+	}
+	if escalated := n.ListEscalatedSessions(); len(escalated) != 1 || escalated[0].ID != session.ID {
+		t.Fatalf("expected the session to wait for a human, got %v", escalated)
+	}
+	if len(n.ListActiveSessions()) != 0 {
+		t.Error("escalated session should not be active")
+	}
+	if err := n.Vote(ctx, session.ID, &Vote{VoterID: "agent-a", Approve: true}); err == nil {
+		t.Error("expected votes on an escalated session to be refused")
+	}
+	if _, err := n.Negotiate(ctx, session.ID, &NegotiationProposal{Type: ProposalEscalate}); err == nil {
+		t.Error("expected a second escalation to be refused")
+	}
+
+	// The operator settles it long after the negotiation timeout
+	session.ExpiresAt = time.Now().Add(-time.Minute)
+	result, err := n.Negotiate(ctx, session.ID, &NegotiationProposal{Type: ProposalYield, YielderID: "agent-a"})
+	if err != nil {
+		t.Fatalf("settle escalation: %v", err)
+	}
+	if !result.Success || result.WinnerLock.HolderID != "agent-b" {
+		t.Errorf("expected agent-b to win, got %+v", result)
+	}
+	if _, err := store.Get(requested.ID); err != nil {
+		t.Errorf("expected the requested lock to be held: %v", err)
+	}
+	if len(n.ListEscalatedSessions()) != 0 {
+		t.Error("settled session should no longer wait for a human")
+	}
+	if _, err := n.Negotiate(ctx, session.ID, &NegotiationProposal{Type: ProposalPriority}); err == nil {
+		t.Error("expected proposals on a settled session to be refused")
+	}
+	if m := n.Metrics(); m.EscalationRate != 1 {
+		t.Errorf("settled escalation should still count, got rate %v", m.EscalationRate)
+	}
+}
+
+func TestLockNegotiator_MetricsEmpty(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	m := n.Metrics()
+	if m.TotalSessions != 0 || m.AvgTimeToResolution != 0 || m.EscalationRate != 0 {
+		t.Errorf("expected zero metrics, got %+v", m)
+	}
+}
+
+func TestLockNegotiator_ResolutionHistogram(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	if err := n.SetResolutionBuckets([]time.Duration{time.Second, 5 * time.Second, 10 * time.Second}); err != nil {
+		t.Fatalf("SetResolutionBuckets: %v", err)
+	}
+
+	// resolveAfter starts a session d ago and settles it by unanimous vote
+	resolveAfter := func(i int, d time.Duration, approve bool) {
+		held, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: fmt.Sprintf("/test/h%d.go", i), StartLine: 1, EndLine: 50}, "agent-a", "A", "holding")
+		requested, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: fmt.Sprintf("/test/h%d.go", i), StartLine: 10, EndLine: 20}, "agent-b", "B", "requesting")
+		n.mu.Lock()
+		session := n.startNegotiationSession(requested, held)
+		session.StartedAt = time.Now().Add(-d)
+		n.mu.Unlock()
+
+		n.Vote(ctx, session.ID, &Vote{VoterID: "agent-a", Approve: approve})
+		n.Vote(ctx, session.ID, &Vote{VoterID: "agent-b", Approve: approve})
+	}
+
+	resolveAfter(1, 500*time.Millisecond, true) // <= 1s
+	resolveAfter(2, 3*time.Second, true)        // <= 5s
+	resolveAfter(3, 20*time.Second, true)       // +Inf
+	resolveAfter(4, 7*time.Second, false)       // <= 10s
+
+	hist := n.Metrics().TimeToResolution
+	approved := hist[ResolutionApproved]
+	if approved == nil || approved.Count != 3 {
+		t.Fatalf("expected 3 approved observations, got %+v", approved)
+	}
+	wantApproved := []HistogramBucket{{"1s", 1}, {"5s", 2}, {"10s", 2}, {"+Inf", 3}}
+	if !reflect.DeepEqual(approved.Buckets, wantApproved) {
+		t.Errorf("approved buckets = %+v, want %+v", approved.Buckets, wantApproved)
+	}
+	if approved.Sum < 23*time.Second || approved.Sum > 24*time.Second {
+		t.Errorf("expected approved sum of about 23.5s, got %s", approved.Sum)
+	}
+
+	rejected := hist[ResolutionRejected]
+	wantRejected := []HistogramBucket{{"1s", 0}, {"5s", 0}, {"10s", 1}, {"+Inf", 1}}
+	if rejected == nil || !reflect.DeepEqual(rejected.Buckets, wantRejected) {
+		t.Errorf("rejected histogram = %+v, want buckets %+v", rejected, wantRejected)
+	}
+	if _, ok := hist[ResolutionTimedOut]; ok {
+		t.Error("resolution types never seen should not be exported")
+	}
+}
+
+func TestLockNegotiator_ResolutionBucketsValidated(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	for _, bounds := range [][]time.Duration{
+		nil,
+		{5 * time.Second, time.Second},
+		{0, time.Second},
+	} {
+		if err := n.SetResolutionBuckets(bounds); err == nil {
+			t.Errorf("buckets %v should be rejected", bounds)
+		}
+	}
+}
+
+func TestLockService_RemoteNodeShutdownKeepsOwnLocks(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Agent A")
+	defer svc.Close()
+
+	mine, err := svc.AcquireLock(ctx, &AcquireLockRequest{TargetType: TargetFile, FilePath: "/test/mine.go", StartLine: 1, EndLine: 5, Intention: "edit"})
+	if err != nil || !mine.Success {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	theirs, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: "/test/theirs.go", StartLine: 1, EndLine: 5}, "node-b", "B", "edit")
+	if err := svc.HandleRemoteLockAcquired(theirs); err != nil {
+		t.Fatal(err)
+	}
+
+	// A forged notice about ourselves is ignored
+	if dropped := svc.HandleRemoteNodeShutdown("node-a"); len(dropped) != 0 {
+		t.Errorf("own locks should never be dropped, got %d", len(dropped))
+	}
+
+	dropped := svc.HandleRemoteNodeShutdown("node-b")
+	if len(dropped) != 1 || dropped[0].ID != theirs.ID {
+		t.Fatalf("expected node-b's lock to be dropped, got %+v", dropped)
+	}
+	if _, err := svc.GetLock(mine.Lock.ID); err != nil {
+		t.Errorf("own lock should be kept: %v", err)
+	}
+	if history := svc.GetHistory(1); len(history) != 1 || history[0].Action != "holder_shutdown" {
+		t.Errorf("expected holder_shutdown history entry, got %+v", history)
+	}
+}
+
+func TestLockService_ReleaseLocksByIntention(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Agent A")
+	defer svc.Close()
+
+	var broadcast []string
+	svc.SetBroadcastFn(func(msg any) error {
+		if m, ok := msg.(ReleaseMessage); ok {
+			broadcast = append(broadcast, m.LockID)
+		}
+		return nil
+	})
+
+	acquire := func(file, intention string) string {
+		t.Helper()
+		result, err := svc.AcquireLock(ctx, &AcquireLockRequest{TargetType: TargetFile, FilePath: file, StartLine: 1, EndLine: 5, Intention: intention})
+		if err != nil || !result.Success {
+			t.Fatalf("AcquireLock %s failed: %v", file, err)
+		}
+		return result.Lock.ID
+	}
+	login := acquire("/test/login.go", "refactor auth: login")
+	token := acquire("/test/token.go", "refactor auth: tokens")
+	exact := acquire("/test/auth.go", "refactor auth")
+	other := acquire("/test/billing.go", "fix billing")
+
+	theirs, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: "/test/peer.go", StartLine: 1, EndLine: 5}, "node-b", "B", "refactor auth")
+	if err := svc.HandleRemoteLockAcquired(theirs); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.ReleaseLocksByIntention(ctx, "", true); !errors.Is(err, ErrIntentionRequired) {
+		t.Fatalf("expected ErrIntentionRequired, got: %v", err)
+	}
+
+	// Exact match leaves the prefixed intentions alone
+	released, err := svc.ReleaseLocksByIntention(ctx, "refactor auth", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(released) != 1 || released[0].ID != exact {
+		t.Fatalf("expected only the exact match, got %+v", released)
+	}
+
+	released, err = svc.ReleaseLocksByIntention(ctx, "refactor auth", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, l := range released {
+		ids = append(ids, l.ID)
+	}
+	slices.Sort(ids)
+	want := []string{login, token}
+	slices.Sort(want)
+	if !slices.Equal(ids, want) {
+		t.Errorf("expected prefix group %v, got %v", want, ids)
+	}
+
+	if _, err := svc.GetLock(other); err != nil {
+		t.Errorf("non-matching lock should be kept: %v", err)
+	}
+	if _, err := svc.GetLock(theirs.ID); err != nil {
+		t.Errorf("other agent's lock should be kept: %v", err)
+	}
+	if len(broadcast) != 3 {
+		t.Errorf("expected each release broadcast, got %v", broadcast)
+	}
+	if history := svc.GetHistory(1); len(history) != 1 || history[0].Action != "bulk_released" || history[0].Reason != "intention prefix: refactor auth" {
+		t.Errorf("expected bulk release audit entry, got %+v", history)
+	}
+}
+
+func TestLockService_AcquireWithDeadline(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Agent A")
+	defer svc.Close()
+
+	deadline := time.Now().Add(2 * time.Minute).Truncate(time.Second)
+	result, err := svc.AcquireLock(ctx, &AcquireLockRequest{
+		TargetType: TargetFile,
+		FilePath:   "/test/deadline.go",
+		StartLine:  1,
+		EndLine:    10,
+		Intention:  "done by the deadline",
+		Deadline:   deadline,
+	})
+	if err != nil {
+		t.Fatalf("expected lock with deadline, got: %v", err)
+	}
+	if !result.Lock.ExpiresAt.Equal(deadline) {
+		t.Errorf("expected ExpiresAt %v, got %v", deadline, result.Lock.ExpiresAt)
+	}
+}
+
+func TestLockService_AcquireWithInvalidDeadline(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Agent A")
+	defer svc.Close()
+
+	tests := []struct {
+		name string
+		req  AcquireLockRequest
+	}{
+		{"past deadline", AcquireLockRequest{Deadline: time.Now().Add(-time.Second)}},
+		{"beyond max span", AcquireLockRequest{Deadline: time.Now().Add(MaxTTL + time.Minute)}},
+		{"deadline with ttl", AcquireLockRequest{Deadline: time.Now().Add(time.Minute), TTL: time.Minute}},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.TargetType = TargetFile
+			req.FilePath = fmt.Sprintf("/test/invalid-%d.go", i)
+			req.StartLine, req.EndLine = 1, 10
+
+			result, err := svc.AcquireLock(ctx, &req)
+			var valErr *ValidationError
+			if !errors.As(err, &valErr) || valErr.Field != "deadline" {
+				t.Fatalf("expected deadline validation error, got: %v", err)
+			}
+			if result == nil || result.Success {
+				t.Error("expected unsuccessful lock result")
+			}
+		})
+	}
+
+	if n := len(svc.ListMyLocks()); n != 0 {
+		t.Errorf("rejected requests should not hold locks, got %d", n)
+	}
+}
+
+func TestAcquireLockRequest_ExpiresAt(t *testing.T) {
+	now := time.Now()
+
+	if got, _ := (&AcquireLockRequest{}).ExpiresAt(now); !got.Equal(now.Add(DefaultTTL)) {
+		t.Errorf("expected default TTL, got %v", got.Sub(now))
+	}
+	if got, _ := (&AcquireLockRequest{TTL: time.Minute}).ExpiresAt(now); !got.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected 1m TTL, got %v", got.Sub(now))
+	}
+	if got, _ := (&AcquireLockRequest{TTL: time.Hour}).ExpiresAt(now); !got.Equal(now.Add(MaxTTL)) {
+		t.Errorf("expected TTL capped at MaxTTL, got %v", got.Sub(now))
+	}
+	if got, err := (&AcquireLockRequest{Deadline: now.Add(MaxTTL)}).ExpiresAt(now); err != nil || !got.Equal(now.Add(MaxTTL)) {
+		t.Errorf("deadline exactly MaxTTL away should be accepted, got %v, %v", got.Sub(now), err)
+	}
+}
+
+// contendedSession starts a negotiation where requester wants part of a
+// region already held by another agent.
+func contendedSession(t *testing.T, n *LockNegotiator, store *LockStore, requester string) (*NegotiationSession, *SemanticLock) {
+	t.Helper()
+	ctx := context.Background()
+
+	target := &SemanticTarget{Type: TargetFile, FilePath: "/test/contended.go", StartLine: 1, EndLine: 50}
+	held, _ := NewSemanticLockSafe(target, "holder", "Holder", "holding")
+	if err := store.Add(held); err != nil {
+		t.Fatalf("failed to add lock: %v", err)
+	}
+
+	requestedTarget := &SemanticTarget{Type: TargetFile, FilePath: "/test/contended.go", StartLine: 10, EndLine: 20}
+	requested, _ := NewSemanticLockSafe(requestedTarget, requester, "Requester", "requesting")
+	if _, err := n.AnnounceIntent(ctx, requested); err == nil {
+		t.Fatal("expected conflict to start a negotiation session")
+	}
+	sessions := n.ListActiveSessions()
+	if len(sessions) != 1 {
+		t.Fatalf("expected 1 active session, got %d", len(sessions))
+	}
+	return sessions[0], held
+}
+
+func TestLockNegotiator_CancelSession(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	var broadcast []NegotiationCancelMessage
+	n.SetBroadcastFn(func(msg any) error {
+		if m, ok := msg.(NegotiationCancelMessage); ok {
+			broadcast = append(broadcast, m)
+		}
+		return nil
+	})
+
+	session, _ := contendedSession(t, n, store, "requester")
+
+	result, err := n.CancelSession(ctx, session.ID, "requester")
+	if err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+	if result.ResolutionType != ResolutionCancelled || session.State != StateCancelled {
+		t.Errorf("session should be resolved as cancelled, got %s / %s", result.ResolutionType, session.State)
+	}
+	if len(n.ListActiveSessions()) != 0 {
+		t.Error("cancelled session should no longer be active")
+	}
+	if m := n.Metrics(); m.EscalationRate != 0 || m.ByResolution[ResolutionCancelled] != 1 {
+		t.Errorf("cancel should not count as escalation, got %+v", m)
+	}
+	if len(broadcast) != 1 || broadcast[0].SessionID != session.ID || broadcast[0].RequesterID != "requester" {
+		t.Errorf("expected cancellation broadcast, got %+v", broadcast)
+	}
+
+	// Cancelled sessions take no further votes or proposals
+	if err := n.Vote(ctx, session.ID, &Vote{VoterID: "holder", Approve: true}); !errors.Is(err, ErrSessionCancelled) {
+		t.Errorf("expected ErrSessionCancelled on vote, got: %v", err)
+	}
+	if _, err := n.Negotiate(ctx, session.ID, &NegotiationProposal{Type: ProposalPriority}); !errors.Is(err, ErrSessionCancelled) {
+		t.Errorf("expected ErrSessionCancelled on negotiate, got: %v", err)
+	}
+	if _, err := n.CancelSession(ctx, session.ID, "requester"); err == nil {
+		t.Error("cancelling twice should fail")
+	}
+}
+
+func TestLockNegotiator_CancelSessionByNonOwner(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	session, _ := contendedSession(t, n, store, "requester")
+
+	for _, who := range []string{"holder", "someone-else"} {
+		if _, err := n.CancelSession(ctx, session.ID, who); !errors.Is(err, ErrNotLockHolder) {
+			t.Errorf("cancel by %s: expected ErrNotLockHolder, got: %v", who, err)
+		}
+	}
+	if session.Resolution != nil {
+		t.Error("rejected cancel must not resolve the session")
+	}
+	if _, err := n.CancelSession(ctx, "neg-missing", "requester"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound, got: %v", err)
+	}
+
+	// A forged remote cancel is ignored as well
+	if n.HandleRemoteCancel(&NegotiationCancelMessage{SessionID: session.ID, RequesterID: "holder"}) {
+		t.Error("remote cancel by non-owner should be ignored")
+	}
+	if !n.HandleRemoteCancel(&NegotiationCancelMessage{SessionID: session.ID, RequesterID: "requester"}) {
+		t.Error("remote cancel by owner should apply")
+	}
+	if session.State != StateCancelled {
+		t.Errorf("expected cancelled state, got %s", session.State)
+	}
+}
+
+func TestLockNegotiator_CancelledSessionFreesRegion(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	session, held := contendedSession(t, n, store, "requester")
+	if _, err := n.CancelSession(ctx, session.ID, "requester"); err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+	if _, err := store.Get(session.RequestedLock.ID); !errors.Is(err, ErrLockNotFound) {
+		t.Errorf("requested lock should not be held after cancel, got: %v", err)
+	}
+
+	// Once the holder is done, a third agent gets the region without negotiating
+	if err := n.ReleaseLock(ctx, held.ID, "holder"); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	target := &SemanticTarget{Type: TargetFile, FilePath: "/test/contended.go", StartLine: 10, EndLine: 20}
+	next, _ := NewSemanticLockSafe(target, "third", "Third", "editing")
+	intent, err := n.AnnounceIntent(ctx, next)
+	if err != nil {
+		t.Fatalf("region should be free after cancel: %v", err)
+	}
+	if result, err := n.AcquireLock(ctx, intent.ID); err != nil || !result.Success {
+		t.Fatalf("acquire failed: %v", err)
+	}
+}
+
+func TestLockService_RestoreLocks(t *testing.T) {
+	svc := NewLockService(context.Background(), "node-1", "Agent")
+	defer svc.Close()
+	held := holdRemoteLock(t, svc, time.Minute)
+
+	newLock := func(path string, ttl time.Duration) *SemanticLock {
+		target, err := NewSemanticTarget(TargetFile, path, "", 1, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lock := NewSemanticLock(target, "node-3", "Other", "edit")
+		lock.ExpiresAt = time.Now().Add(ttl)
+		return lock
+	}
+	overlapping := newLock("/test/queue.go", time.Minute)
+	expired := newLock("/test/expired.go", -time.Second)
+	fresh := newLock("/test/fresh.go", time.Minute)
+
+	if n := svc.RestoreLocks([]*SemanticLock{held, overlapping, expired, fresh}); n != 1 {
+		t.Fatalf("expected only the fresh lock to be restored, got %d", n)
+	}
+	if _, err := svc.GetLock(fresh.ID); err != nil {
+		t.Errorf("expected the fresh lock to be held: %v", err)
+	}
+}
+
+func TestLockService_HistoryHandlerAuditsLifecycle(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Agent A")
+	defer svc.Close()
+
+	var audited []*HistoryEntry
+	svc.SetHistoryHandler(func(e *HistoryEntry) { audited = append(audited, e) })
+
+	result, err := svc.AcquireLock(ctx, &AcquireLockRequest{
+		TargetType: TargetFile,
+		FilePath:   "/test/audit.go",
+		StartLine:  1,
+		EndLine:    10,
+		Intention:  "editing",
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+	if err := svc.ReleaseLock(ctx, result.Lock.ID); err != nil {
+		t.Fatalf("failed to release lock: %v", err)
+	}
+
+	if len(audited) != 2 || audited[0].Action != "acquired" || audited[1].Action != "released" {
+		t.Fatalf("expected acquired and released entries, got %+v", audited)
+	}
+	for _, e := range audited {
+		if e.FencingToken != result.Lock.FencingToken {
+			t.Errorf("%s: expected fencing token %d, got %d", e.Action, result.Lock.FencingToken, e.FencingToken)
+		}
+	}
+
+	svc.store.RecordNegotiation(&NegotiationSession{RequestedLock: result.Lock}, &NegotiationResult{
+		WinnerLock:     result.Lock,
+		ResolutionType: ResolutionApproved,
+		Message:        "all peers approved",
+		ResolvedAt:     time.Now(),
+	})
+	last := audited[len(audited)-1]
+	if last.Action != "negotiated" || last.Resolution != string(ResolutionApproved) || last.Winner != "Agent A" {
+		t.Errorf("expected the negotiation outcome recorded, got %+v", last)
+	}
+}
+
+func TestParseAccessMode(t *testing.T) {
+	for in, want := range map[string]AccessMode{"": AccessWrite, "exclusive": AccessWrite, "Read": AccessRead, "shared": AccessRead} {
+		if got, err := ParseAccessMode(in); err != nil || got != want {
+			t.Errorf("ParseAccessMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseAccessMode("append"); err == nil {
+		t.Error("expected an unknown mode to be rejected")
+	}
+}
+
+func TestLockService_ReadLocksShareRegion(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Agent A")
+	defer svc.Close()
+
+	req := func(mode AccessMode, start, end int) *AcquireLockRequest {
+		return &AcquireLockRequest{TargetType: TargetFile, FilePath: "/test/shared.go", StartLine: start, EndLine: end, Intention: "review", Mode: mode}
+	}
+	first, err := svc.AcquireLock(ctx, req(AccessRead, 1, 20))
+	if err != nil || !first.Success || first.Lock.Mode != AccessRead {
+		t.Fatalf("expected a read lock, got %+v, %v", first, err)
+	}
+
+	// Another reader of the same region is granted, a remote one too
+	if second, err := svc.AcquireLock(ctx, req(AccessRead, 1, 20)); err != nil || !second.Success {
+		t.Fatalf("expected a second reader to share the region, got %v", err)
+	}
+	remote, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: "/test/shared.go", StartLine: 5, EndLine: 10}, "node-b", "B", "read")
+	remote.Mode = AccessRead
+	if err := svc.HandleRemoteLockAcquired(remote); err != nil {
+		t.Fatalf("expected a remote reader to be stored, got %v", err)
+	}
+	if svc.Count() != 3 {
+		t.Fatalf("expected three readers, got %d", svc.Count())
+	}
+
+	// A writer conflicts with every reader; a check in read mode is free
+	if _, err := svc.AcquireLock(ctx, req(AccessWrite, 8, 12)); err == nil {
+		t.Error("expected a write lock over readers to conflict")
+	}
+	check, err := svc.CheckLock(req(AccessRead, 1, 20))
+	if err != nil || !check.Free {
+		t.Errorf("expected the region to be free for readers, got %+v, %v", check, err)
+	}
+	if check, _ := svc.CheckLock(req("", 1, 20)); check.Free || len(check.Conflicts) != 3 {
+		t.Errorf("expected a write check to report the readers, got %+v", check)
+	}
+
+	// Releasing one reader keeps the others findable by target
+	if err := svc.ReleaseLock(ctx, first.Lock.ID); err != nil {
+		t.Fatal(err)
+	}
+	if held, err := svc.GetLockByTarget(first.Lock.Target); err != nil || held.ID == first.Lock.ID {
+		t.Errorf("expected the other reader under the target, got %+v, %v", held, err)
+	}
+}
+
+func TestLockService_UpgradeLock(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Agent A")
+	defer svc.Close()
+
+	var broadcast []*SemanticLock
+	svc.SetBroadcastFn(func(msg any) error {
+		if m, ok := msg.(AcquireMessage); ok {
+			broadcast = append(broadcast, m.Lock)
+		}
+		return nil
+	})
+
+	read, err := svc.AcquireLock(ctx, &AcquireLockRequest{TargetType: TargetFile, FilePath: "/test/up.go", StartLine: 1, EndLine: 10, Intention: "read", Mode: AccessRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A sole reader is upgraded in place and announced again
+	result, err := svc.UpgradeLock(ctx, read.Lock.ID)
+	if err != nil || !result.Success || result.Lock.Mode != AccessWrite {
+		t.Fatalf("expected the upgrade to succeed, got %+v, %v", result, err)
+	}
+	if held, _ := svc.GetLock(read.Lock.ID); held.Shared() || held.FencingToken != read.Lock.FencingToken {
+		t.Errorf("expected the stored lock to be a write lock with its token, got %+v", held)
+	}
+	if last := broadcast[len(broadcast)-1]; last.ID != read.Lock.ID || last.Mode != AccessWrite {
+		t.Errorf("expected the upgrade to be broadcast, got %+v", last)
+	}
+	if history := svc.GetHistory(1); history[0].Action != "upgraded" {
+		t.Errorf("expected an upgraded history entry, got %+v", history[0])
+	}
+	if _, err := svc.UpgradeLock(ctx, "lock-missing"); !errors.Is(err, ErrLockNotFound) {
+		t.Errorf("expected ErrLockNotFound, got %v", err)
+	}
+}
+
+func TestLockService_UpgradeNegotiatesWithOtherReaders(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Agent A")
+	defer svc.Close()
+
+	mine, err := svc.AcquireLock(ctx, &AcquireLockRequest{TargetType: TargetFile, FilePath: "/test/up.go", StartLine: 1, EndLine: 10, Intention: "read", Mode: AccessRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	theirs, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: "/test/up.go", StartLine: 5, EndLine: 15}, "node-b", "B", "read")
+	theirs.Mode = AccessRead
+	if err := svc.HandleRemoteLockAcquired(theirs); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another reader must agree before the region becomes exclusive
+	if _, err := svc.UpgradeLock(ctx, mine.Lock.ID); !errors.Is(err, ErrLockConflict) {
+		t.Fatalf("expected the upgrade to conflict, got %v", err)
+	}
+	if held, _ := svc.GetLock(mine.Lock.ID); !held.Shared() {
+		t.Error("the lock should stay a read lock while negotiating")
+	}
+	sessions := svc.ListActiveNegotiations()
+	if len(sessions) != 1 || sessions[0].ConflictingLock.ID != theirs.ID || sessions[0].RequestedLock.Mode != AccessWrite {
+		t.Fatalf("expected a negotiation with the other reader, got %+v", sessions)
+	}
+
+	// The other reader yields, which upgrades the lock
+	if _, err := svc.Negotiate(ctx, sessions[0].ID, &NegotiationProposal{Type: ProposalYield, YielderID: "node-b"}); err != nil {
+		t.Fatal(err)
+	}
+	held, err := svc.GetLock(mine.Lock.ID)
+	if err != nil || held.Shared() {
+		t.Errorf("expected the lock to be upgraded, got %+v, %v", held, err)
+	}
+	if _, err := svc.GetLock(theirs.ID); err == nil {
+		t.Error("expected the yielding reader to lose its lock")
+	}
+}
+
+func TestLockService_UpgradeWaitsOutEveryReader(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Agent A")
+	defer svc.Close()
+
+	mine, err := svc.AcquireLock(ctx, &AcquireLockRequest{TargetType: TargetFile, FilePath: "/test/up.go", StartLine: 1, EndLine: 10, Intention: "read", Mode: AccessRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readers := make(map[string]*SemanticLock)
+	for _, holder := range []string{"node-b", "node-c"} {
+		reader, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: "/test/up.go", StartLine: 1, EndLine: 10}, holder, holder, "read")
+		reader.Mode = AccessRead
+		if err := svc.HandleRemoteLockAcquired(reader); err != nil {
+			t.Fatal(err)
+		}
+		readers[holder] = reader
+	}
+
+	if _, err := svc.UpgradeLock(ctx, mine.Lock.ID); !errors.Is(err, ErrLockConflict) {
+		t.Fatalf("expected the upgrade to conflict, got %v", err)
+	}
+	sessions := make(map[string]*NegotiationSession)
+	for _, session := range svc.ListActiveNegotiations() {
+		sessions[session.ConflictingLock.HolderID] = session
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected a negotiation with each reader, got %+v", sessions)
+	}
+
+	// One reader yielding is not enough while the other still reads
+	if _, err := svc.Negotiate(ctx, sessions["node-b"].ID, &NegotiationProposal{Type: ProposalYield, YielderID: "node-b"}); err != nil {
+		t.Fatal(err)
+	}
+	if held, _ := svc.GetLock(mine.Lock.ID); !held.Shared() {
+		t.Error("the lock should stay a read lock while another reader holds the target")
+	}
+	if _, err := svc.GetLock(readers["node-c"].ID); err != nil {
+		t.Errorf("the remaining reader should keep its lock, got %v", err)
+	}
+
+	if _, err := svc.Negotiate(ctx, sessions["node-c"].ID, &NegotiationProposal{Type: ProposalYield, YielderID: "node-c"}); err != nil {
+		t.Fatal(err)
+	}
+	if held, _ := svc.GetLock(mine.Lock.ID); held.Shared() {
+		t.Error("expected the lock to be upgraded once every reader yielded")
+	}
+	if conflicts := svc.FindConflicts(mine.Lock.Target); len(conflicts) != 1 || conflicts[0].ID != mine.Lock.ID {
+		t.Errorf("expected the write lock alone on the target, got %+v", conflicts)
+	}
+}
+
+func TestLockService_LostUpgradeKeepsReaders(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Agent A")
+	defer svc.Close()
+
+	mine, err := svc.AcquireLock(ctx, &AcquireLockRequest{TargetType: TargetFile, FilePath: "/test/up.go", StartLine: 1, EndLine: 10, Intention: "read", Mode: AccessRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	readers := make(map[string]*SemanticLock)
+	for _, holder := range []string{"node-b", "node-c"} {
+		reader, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: "/test/up.go", StartLine: 1, EndLine: 10}, holder, holder, "read")
+		reader.Mode = AccessRead
+		if err := svc.HandleRemoteLockAcquired(reader); err != nil {
+			t.Fatal(err)
+		}
+		readers[holder] = reader
+	}
+	if _, err := svc.UpgradeLock(ctx, mine.Lock.ID); !errors.Is(err, ErrLockConflict) {
+		t.Fatalf("expected the upgrade to conflict, got %v", err)
+	}
+	var lost *NegotiationSession
+	for _, session := range svc.ListActiveNegotiations() {
+		if session.ConflictingLock.HolderID == "node-b" {
+			lost = session
+		}
+	}
+
+	// The requester gives way to one reader, which abandons the upgrade
+	if _, err := svc.Negotiate(ctx, lost.ID, &NegotiationProposal{Type: ProposalYield, YielderID: "node-a"}); err != nil {
+		t.Fatal(err)
+	}
+	if active := svc.ListActiveNegotiations(); len(active) != 0 {
+		t.Errorf("expected the other upgrade session to be cancelled, got %+v", active)
+	}
+	if held, err := svc.GetLock(mine.Lock.ID); err != nil || !held.Shared() {
+		t.Errorf("expected the requester to keep its read lock, got %+v, %v", held, err)
+	}
+	for holder, reader := range readers {
+		if _, err := svc.GetLock(reader.ID); err != nil {
+			t.Errorf("expected %s to keep its read lock, got %v", holder, err)
+		}
+	}
+}
+
+func TestLockNegotiator_FailedGrantRestoresConflictingLock(t *testing.T) {
+	ctx := context.Background()
+	store := NewLockStore(ctx)
+	defer store.Close()
+	n := NewLockNegotiator(ctx, store)
+	defer n.Close()
+
+	target := &SemanticTarget{Type: TargetFile, FilePath: "/test/grant.go", StartLine: 1, EndLine: 10}
+	held, _ := NewSemanticLockSafe(target, "node-b", "B", "edit")
+	if err := store.Add(held); err != nil {
+		t.Fatal(err)
+	}
+	// Another write lock on the very same target makes storing the
+	// requested lock fail
+	blocking, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: "/test/grant.go", StartLine: 1, EndLine: 10}, "node-c", "C", "edit")
+	store.mu.Lock()
+	store.locks[blocking.ID] = blocking
+	store.byTarget[blocking.Target.ID()] = blocking.ID
+	store.mu.Unlock()
+
+	requested, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFile, FilePath: "/test/grant.go", StartLine: 1, EndLine: 10}, "node-a", "A", "edit")
+	session := n.startNegotiationSession(requested, held)
+	if _, err := n.Negotiate(ctx, session.ID, &NegotiationProposal{Type: ProposalYield, YielderID: "node-b"}); !errors.Is(err, ErrLockConflict) {
+		t.Fatalf("expected the grant to fail, got %v", err)
+	}
+	if _, err := store.Get(held.ID); err != nil {
+		t.Errorf("expected the conflicting lock to be restored, got %v", err)
+	}
+	if _, err := store.Get(requested.ID); err == nil {
+		t.Error("the requested lock should not be stored")
+	}
+}
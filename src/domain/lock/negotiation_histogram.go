@@ -0,0 +1,143 @@
+package lock
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultResolutionBuckets are the default upper bounds of the
+// time-to-resolution histogram, spanning instant resolutions up to the
+// negotiation timeout.
+var DefaultResolutionBuckets = []time.Duration{
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	NegotiationTimeout,
+	time.Minute,
+}
+
+// HistogramBucket is a cumulative histogram bucket: Count is the number of
+// observations less than or equal to LE, as in Prometheus.
+type HistogramBucket struct {
+	LE    string `json:"le"` // upper bound, "+Inf" for the last bucket
+	Count int64  `json:"count"`
+}
+
+// HistogramSnapshot is a point-in-time view of a time-to-resolution
+// histogram for one resolution type.
+type HistogramSnapshot struct {
+	Count   int64             `json:"count"`
+	Sum     time.Duration     `json:"sum"`
+	Buckets []HistogramBucket `json:"buckets"`
+}
+
+// resolutionHistogram records time-to-resolution per resolution type.
+// Unlike NegotiationMetrics, which only sees retained sessions, it counts
+// every resolution since the negotiator started.
+type resolutionHistogram struct {
+	mu     sync.Mutex
+	bounds []time.Duration
+	byType map[ResolutionType]*histogramCounts
+}
+
+type histogramCounts struct {
+	count   int64
+	sum     time.Duration
+	buckets []int64 // one per bound plus +Inf
+}
+
+func newResolutionHistogram(bounds []time.Duration) *resolutionHistogram {
+	return &resolutionHistogram{
+		bounds: bounds,
+		byType: make(map[ResolutionType]*histogramCounts),
+	}
+}
+
+// observe records one resolution of type rt that took d.
+func (h *resolutionHistogram) observe(rt ResolutionType, d time.Duration) {
+	d = max(d, 0)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	c, ok := h.byType[rt]
+	if !ok {
+		c = &histogramCounts{buckets: make([]int64, len(h.bounds)+1)}
+		h.byType[rt] = c
+	}
+	c.count++
+	c.sum += d
+	i := sort.Search(len(h.bounds), func(i int) bool { return d <= h.bounds[i] })
+	c.buckets[i]++
+}
+
+// snapshot returns cumulative histograms keyed by resolution type.
+func (h *resolutionHistogram) snapshot() map[ResolutionType]*HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.byType) == 0 {
+		return nil
+	}
+	out := make(map[ResolutionType]*HistogramSnapshot, len(h.byType))
+	for rt, c := range h.byType {
+		snap := &HistogramSnapshot{
+			Count:   c.count,
+			Sum:     c.sum,
+			Buckets: make([]HistogramBucket, len(c.buckets)),
+		}
+		var cumulative int64
+		for i, n := range c.buckets {
+			cumulative += n
+			le := "+Inf"
+			if i < len(h.bounds) {
+				le = h.bounds[i].String()
+			}
+			snap.Buckets[i] = HistogramBucket{LE: le, Count: cumulative}
+		}
+		out[rt] = snap
+	}
+	return out
+}
+
+// ValidateResolutionBuckets checks that bucket bounds are positive and
+// strictly increasing.
+func ValidateResolutionBuckets(bounds []time.Duration) error {
+	if len(bounds) == 0 {
+		return fmt.Errorf("at least one bucket is required")
+	}
+	for i, b := range bounds {
+		if b <= 0 {
+			return fmt.Errorf("bucket %s must be positive", b)
+		}
+		if i > 0 && b <= bounds[i-1] {
+			return fmt.Errorf("buckets must be increasing: %s after %s", b, bounds[i-1])
+		}
+	}
+	return nil
+}
+
+// SetResolutionBuckets replaces the time-to-resolution histogram buckets.
+// Observations recorded so far are discarded.
+func (n *LockNegotiator) SetResolutionBuckets(bounds []time.Duration) error {
+	if err := ValidateResolutionBuckets(bounds); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.resolutions = newResolutionHistogram(slices.Clone(bounds))
+	return nil
+}
+
+// resolve records the outcome of a session and its time to resolution.
+func (n *LockNegotiator) resolve(session *NegotiationSession, result *NegotiationResult) {
+	session.Resolution = result
+	n.resolutions.observe(result.ResolutionType, result.ResolvedAt.Sub(session.StartedAt))
+	n.store.RecordNegotiation(session, result)
+}
@@ -0,0 +1,63 @@
+package lock
+
+import "time"
+
+// NegotiationMetrics summarizes negotiation health across the sessions
+// the negotiator still remembers (resolved sessions are kept for
+// ResolvedSessionRetention).
+type NegotiationMetrics struct {
+	TotalSessions    int `json:"total_sessions"`
+	ActiveSessions   int `json:"active_sessions"`
+	ResolvedSessions int `json:"resolved_sessions"`
+
+	// ByResolution counts resolved sessions per resolution type.
+	ByResolution map[ResolutionType]int `json:"by_resolution"`
+
+	// AvgTimeToResolution is the mean time from session start to
+	// resolution over resolved sessions.
+	AvgTimeToResolution time.Duration `json:"avg_time_to_resolution"`
+
+	// EscalationRate is the share of resolved sessions that ended
+	// escalated (needing a human or timed out), from 0 to 1. Escalations
+	// an operator has since settled still count.
+	EscalationRate float64 `json:"escalation_rate"`
+
+	// TimeToResolution holds cumulative time-to-resolution histograms per
+	// resolution type, covering every resolution since startup.
+	TimeToResolution map[ResolutionType]*HistogramSnapshot `json:"time_to_resolution,omitempty"`
+}
+
+// Metrics computes aggregate negotiation metrics from session history.
+func (n *LockNegotiator) Metrics() *NegotiationMetrics {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	m := &NegotiationMetrics{
+		TotalSessions:    len(n.sessions),
+		ByResolution:     make(map[ResolutionType]int),
+		TimeToResolution: n.resolutions.snapshot(),
+	}
+
+	var total time.Duration
+	escalated := 0
+	for _, session := range n.sessions {
+		if session.Resolution == nil {
+			m.ActiveSessions++
+			continue
+		}
+		m.ResolvedSessions++
+		m.ByResolution[session.Resolution.ResolutionType]++
+		if d := session.Resolution.ResolvedAt.Sub(session.StartedAt); d > 0 {
+			total += d
+		}
+		if !session.EscalatedAt.IsZero() {
+			escalated++
+		}
+	}
+
+	if m.ResolvedSessions > 0 {
+		m.AvgTimeToResolution = total / time.Duration(m.ResolvedSessions)
+		m.EscalationRate = float64(escalated) / float64(m.ResolvedSessions)
+	}
+	return m
+}
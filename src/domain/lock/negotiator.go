@@ -3,6 +3,7 @@ package lock
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -18,6 +19,7 @@ const (
 	StateRejected        NegotiationState = "rejected"
 	StateNegotiating     NegotiationState = "negotiating"
 	StateEscalated       NegotiationState = "escalated"
+	StateCancelled       NegotiationState = "cancelled"
 )
 
 // Timeout constants for negotiation.
@@ -49,6 +51,12 @@ type NegotiationSession struct {
 	StartedAt       time.Time          `json:"started_at"`
 	ExpiresAt       time.Time          `json:"expires_at"`
 	Resolution      *NegotiationResult `json:"resolution,omitempty"`
+	// EscalatedAt is when the session was handed to a human; it stays set
+	// after an operator settles the escalation.
+	EscalatedAt time.Time `json:"escalated_at,omitzero"`
+	// Upgrade marks a session upgrading the requester's read lock; one is
+	// opened with every other reader of the target.
+	Upgrade bool `json:"upgrade,omitempty"`
 }
 
 // NegotiationResult is the negotiation result.
@@ -70,6 +78,8 @@ const (
 	ResolutionNegotiated  ResolutionType = "negotiated"
 	ResolutionTimedOut    ResolutionType = "timed_out"
 	ResolutionHumanNeeded ResolutionType = "human_needed"
+	ResolutionCancelled   ResolutionType = "cancelled"
+	ResolutionPolicy      ResolutionType = "policy"
 )
 
 // LockNegotiator is the lock negotiator.
@@ -81,9 +91,18 @@ type LockNegotiator struct {
 	ctx         context.Context
 	cancel      context.CancelFunc
 
+	// Observer nodes are excluded from quorum
+	observers map[string]struct{}
+
 	// Rate limiting
 	rateLimiter *RateLimiter
 
+	// Time-to-resolution per resolution type
+	resolutions *resolutionHistogram
+
+	// Decides conflicts without negotiation (nil = negotiate)
+	policy ConflictPolicy
+
 	// Callbacks
 	onConflict  func(*LockConflict) error
 	onEscalate  func(*NegotiationSession) error
@@ -97,6 +116,11 @@ type LockIntent struct {
 	AnnouncedAt  time.Time       `json:"announced_at"`
 	ExpiresAt    time.Time       `json:"expires_at"`
 	Acknowledged map[string]bool `json:"acknowledged"`
+	// ConflictHint predicts contention on the file from recent activity.
+	ConflictHint *ConflictHint `json:"conflict_hint,omitempty"`
+	// Preempted lists the locks the conflict policy released in favour of
+	// this one.
+	Preempted []*ForceReleaseNotice `json:"preempted,omitempty"`
 }
 
 // NewLockNegotiator creates a new lock negotiator.
@@ -106,9 +130,11 @@ func NewLockNegotiator(ctx context.Context, store *LockStore) *LockNegotiator {
 		store:       store,
 		sessions:    make(map[string]*NegotiationSession),
 		intentQueue: make(map[string]*LockIntent),
+		observers:   make(map[string]struct{}),
 		ctx:         ctx,
 		cancel:      cancel,
 		rateLimiter: NewRateLimiter(DefaultRateLimitConfig()),
+		resolutions: newResolutionHistogram(DefaultResolutionBuckets),
 	}
 
 	go n.cleanupExpiredSessions()
@@ -123,9 +149,11 @@ func NewLockNegotiatorWithConfig(ctx context.Context, store *LockStore, rlConfig
 		store:       store,
 		sessions:    make(map[string]*NegotiationSession),
 		intentQueue: make(map[string]*LockIntent),
+		observers:   make(map[string]struct{}),
 		ctx:         ctx,
 		cancel:      cancel,
 		rateLimiter: NewRateLimiter(rlConfig),
+		resolutions: newResolutionHistogram(DefaultResolutionBuckets),
 	}
 
 	go n.cleanupExpiredSessions()
@@ -133,12 +161,44 @@ func NewLockNegotiatorWithConfig(ctx context.Context, store *LockStore, rlConfig
 	return n
 }
 
+// SetRateLimit changes the per-peer lock request rate limit.
+func (n *LockNegotiator) SetRateLimit(config *RateLimitConfig) {
+	n.rateLimiter.SetConfig(config)
+}
+
+// RateLimitStats returns the lock request rate limiter statistics.
+func (n *LockNegotiator) RateLimitStats() RateLimiterStats {
+	return n.rateLimiter.Stats()
+}
+
 // Close stops the cleanup goroutine and releases resources.
 func (n *LockNegotiator) Close() error {
 	n.cancel()
 	return nil
 }
 
+// AddObserver marks a node as an observer so its votes are not counted.
+func (n *LockNegotiator) AddObserver(nodeID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.observers[nodeID] = struct{}{}
+}
+
+// RemoveObserver removes a node from the observer set.
+func (n *LockNegotiator) RemoveObserver(nodeID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.observers, nodeID)
+}
+
+// IsObserver checks if a node is registered as an observer.
+func (n *LockNegotiator) IsObserver(nodeID string) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	_, ok := n.observers[nodeID]
+	return ok
+}
+
 // SetConflictHandler sets the conflict handler.
 func (n *LockNegotiator) SetConflictHandler(handler func(*LockConflict) error) {
 	n.onConflict = handler
@@ -158,18 +218,21 @@ func (n *LockNegotiator) SetBroadcastFn(fn func(msg any) error) {
 func (n *LockNegotiator) AnnounceIntent(ctx context.Context, lock *SemanticLock) (*LockIntent, error) {
 	// Rate limit check before acquiring lock
 	if !n.rateLimiter.Allow(lock.HolderID) {
-		return nil, ErrRateLimited
+		return nil, &RateLimitError{RetryAfter: n.rateLimiter.RetryAfter(lock.HolderID)}
 	}
 
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
 	// Check for conflicts
-	conflicts := n.store.FindConflicts(lock.Target)
+	conflicts := n.store.FindConflictsFor(lock.Target, lock.Mode)
+	n.rateLimiter.RecordOutcome(lock.HolderID, len(conflicts) > 0)
+	var preempted []*ForceReleaseNotice
 	if len(conflicts) > 0 {
 		// Start negotiation for conflicts
 		for _, conflicting := range conflicts {
 			conflict := NewLockConflict(lock, conflicting)
+			n.store.RecordConflict(conflict)
 			if n.onConflict != nil {
 				if err := n.onConflict(conflict); err != nil {
 					return nil, fmt.Errorf("conflict handler failed: %w", err)
@@ -177,9 +240,19 @@ func (n *LockNegotiator) AnnounceIntent(ctx context.Context, lock *SemanticLock)
 			}
 		}
 
-		// Start negotiation session for first conflict
-		session := n.startNegotiationSession(lock, conflicts[0])
-		return nil, fmt.Errorf("conflict detected, negotiation session started: %s", session.ID)
+		// Let the conflict policy decide before anyone has to negotiate
+		var granted, decided bool
+		if n.policy != nil {
+			granted, preempted, decided = n.resolveByPolicy(lock, conflicts)
+		}
+		switch {
+		case !decided:
+			// Start negotiation session for first conflict
+			session := n.startNegotiationSession(lock, conflicts[0])
+			return nil, fmt.Errorf("conflict detected, negotiation session started: %s", session.ID)
+		case !granted:
+			return nil, fmt.Errorf("%w: %s keeps the lock under the %s policy", ErrLockConflict, conflicts[0].HolderName, n.policy.Name())
+		}
 	}
 
 	// Register intent
@@ -190,6 +263,9 @@ func (n *LockNegotiator) AnnounceIntent(ctx context.Context, lock *SemanticLock)
 		AnnouncedAt:  now,
 		ExpiresAt:    now.Add(IntentTimeout),
 		Acknowledged: make(map[string]bool),
+		ConflictHint: PredictConflict(n.store.GetHistory(0), n.store.List(),
+			lock.HolderID, lock.Target.FilePath, now, PredictionWindow),
+		Preempted: preempted,
 	}
 
 	n.intentQueue[intent.ID] = intent
@@ -231,7 +307,7 @@ func (n *LockNegotiator) AcquireLock(ctx context.Context, intentID string) (*Loc
 	}
 
 	// Check for conflicts again (new locks may have been acquired after intent announcement)
-	conflicts := n.store.FindConflicts(intent.Lock.Target)
+	conflicts := n.store.FindConflictsFor(intent.Lock.Target, intent.Lock.Mode)
 	if len(conflicts) > 0 {
 		delete(n.intentQueue, intentID)
 		return &LockResult{
@@ -268,6 +344,92 @@ func (n *LockNegotiator) AcquireLock(ctx context.Context, intentID string) (*Loc
 	}, nil
 }
 
+// UpgradeLock turns a read lock held by holderID into a write lock. With
+// no other lock overlapping it the lock is upgraded in place and announced
+// again; otherwise a negotiation session is started with every other
+// reader, and the lock is upgraded once all of them have resolved in its
+// favour. Losing any of them abandons the upgrade and keeps the read lock.
+func (n *LockNegotiator) UpgradeLock(ctx context.Context, lockID, holderID string) (*LockResult, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	held, err := n.store.Get(lockID)
+	if err != nil {
+		return &LockResult{
+			Success: false,
+			Reason:  err.Error(),
+		}, err
+	}
+	if held.HolderID != holderID {
+		return &LockResult{
+			Success: false,
+			Reason:  ErrNotLockHolder.Error(),
+		}, ErrNotLockHolder
+	}
+
+	n.store.mu.RLock()
+	upgraded := *held
+	n.store.mu.RUnlock()
+	if !upgraded.Shared() {
+		return &LockResult{
+			Success: true,
+			Lock:    &upgraded,
+			Reason:  "lock is already a write lock",
+		}, nil
+	}
+	upgraded.Mode = AccessWrite
+
+	var readers []*SemanticLock
+	for _, other := range n.store.FindConflictsFor(upgraded.Target, AccessWrite) {
+		if other.ID != upgraded.ID {
+			readers = append(readers, other)
+		}
+	}
+	if len(readers) > 0 {
+		for _, reader := range readers {
+			conflict := NewLockConflict(&upgraded, reader)
+			n.store.RecordConflict(conflict)
+			if n.onConflict != nil {
+				if err := n.onConflict(conflict); err != nil {
+					return nil, fmt.Errorf("conflict handler failed: %w", err)
+				}
+			}
+		}
+		ids := make([]string, len(readers))
+		for i, reader := range readers {
+			ids[i] = n.startUpgradeSession(&upgraded, reader).ID
+		}
+		err := fmt.Errorf("%w: shared with %d readers, negotiation sessions started: %s", ErrLockConflict, len(readers), strings.Join(ids, ", "))
+		return &LockResult{
+			Success: false,
+			Reason:  err.Error(),
+		}, err
+	}
+
+	if err := n.store.Add(&upgraded); err != nil {
+		return &LockResult{
+			Success: false,
+			Reason:  err.Error(),
+		}, err
+	}
+
+	// Broadcast
+	if n.broadcastFn != nil {
+		if err := n.broadcastFn(AcquireMessage{
+			Type: "lock_acquired",
+			Lock: &upgraded,
+		}); err != nil {
+			fmt.Printf("broadcast upgrade failed: %v\n", err)
+		}
+	}
+
+	return &LockResult{
+		Success: true,
+		Lock:    &upgraded,
+		Reason:  "lock upgraded to write",
+	}, nil
+}
+
 // ReleaseLock releases a lock (Phase 3).
 func (n *LockNegotiator) ReleaseLock(ctx context.Context, lockID, holderID string) error {
 	n.mu.Lock()
@@ -299,6 +461,197 @@ func (n *LockNegotiator) ReleaseLock(ctx context.Context, lockID, holderID strin
 	return nil
 }
 
+// ReleaseIdleLock releases a lock whose holder has been inactive for too
+// long. The release is recorded in the lock history with the reason.
+func (n *LockNegotiator) ReleaseIdleLock(ctx context.Context, lockID, holderID, reason string) (*SemanticLock, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	lock, err := n.store.Get(lockID)
+	if err != nil {
+		return nil, err
+	}
+	if lock.HolderID != holderID {
+		return nil, ErrNotLockHolder
+	}
+
+	if _, err := n.store.RemoveIdle(lockID, reason); err != nil {
+		return nil, err
+	}
+
+	if n.broadcastFn != nil {
+		if err := n.broadcastFn(ReleaseMessage{
+			Type:   "lock_released",
+			LockID: lockID,
+		}); err != nil {
+			fmt.Printf("broadcast idle release failed: %v\n", err)
+		}
+	}
+
+	return lock, nil
+}
+
+// ReleaseLocksByIntention releases every lock held by holderID whose
+// intention equals intention, or starts with it when prefix is set. Each
+// release is broadcast and recorded in the history as a bulk release.
+func (n *LockNegotiator) ReleaseLocksByIntention(ctx context.Context, holderID, intention string, prefix bool) ([]*SemanticLock, error) {
+	if intention == "" {
+		return nil, ErrIntentionRequired
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	reason := "intention: " + intention
+	if prefix {
+		reason = "intention prefix: " + intention
+	}
+
+	var released []*SemanticLock
+	for _, lock := range n.store.ListByHolder(holderID) {
+		matches := lock.Intention == intention
+		if prefix {
+			matches = strings.HasPrefix(lock.Intention, intention)
+		}
+		if !matches {
+			continue
+		}
+
+		if _, err := n.store.RemoveBulk(lock.ID, reason); err != nil {
+			continue
+		}
+		released = append(released, lock)
+
+		if n.broadcastFn != nil {
+			if err := n.broadcastFn(ReleaseMessage{
+				Type:   "lock_released",
+				LockID: lock.ID,
+			}); err != nil {
+				fmt.Printf("broadcast bulk release failed: %v\n", err)
+			}
+		}
+	}
+
+	return released, nil
+}
+
+// ReconcilePartitionConflicts settles a lock learned from a peer after a
+// partition heals against the local locks it overlaps. Each pair gets a
+// negotiation session resolved by fencing-token priority. The remote lock
+// is adopted only if it outranks every local lock; those are then removed,
+// and the ones held by nodeID are announced as released.
+func (n *LockNegotiator) ReconcilePartitionConflicts(remote *SemanticLock, conflicts []*SemanticLock, nodeID string) ([]*NegotiationSession, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	sessions := make([]*NegotiationSession, 0, len(conflicts))
+	remoteWins := true
+
+	for _, local := range conflicts {
+		winner, loser := local, remote
+		if outranks(remote, local) {
+			winner, loser = remote, local
+		} else {
+			remoteWins = false
+		}
+
+		session := &NegotiationSession{
+			ID:              fmt.Sprintf("neg-%s-%s", strings.TrimPrefix(remote.ID, lockIDPrefix), strings.TrimPrefix(local.ID, lockIDPrefix)),
+			RequestedLock:   remote,
+			ConflictingLock: local,
+			State:           StateAcquired,
+			Votes:           make(map[string]*Vote),
+			StartedAt:       now,
+			ExpiresAt:       now,
+		}
+		n.resolve(session, &NegotiationResult{
+			Success:        true,
+			WinnerLock:     winner,
+			LoserLock:      loser,
+			ResolutionType: ResolutionNegotiated,
+			Message:        fmt.Sprintf("partition reconciliation: fencing token %d over %d", winner.FencingToken, loser.FencingToken),
+			ResolvedAt:     now,
+		})
+		n.sessions[session.ID] = session
+		sessions = append(sessions, session)
+	}
+
+	if !remoteWins {
+		return sessions, false
+	}
+
+	reason := fmt.Sprintf("lost partition reconciliation to %s (fencing token %d)", remote.HolderName, remote.FencingToken)
+	for _, local := range conflicts {
+		if _, err := n.store.RemoveReconciled(local.ID, reason); err != nil {
+			continue
+		}
+		if local.HolderID == nodeID && n.broadcastFn != nil {
+			if err := n.broadcastFn(ReleaseMessage{
+				Type:   "lock_released",
+				LockID: local.ID,
+			}); err != nil {
+				fmt.Printf("broadcast reconciled release failed: %v\n", err)
+			}
+		}
+	}
+	_ = n.store.Add(remote)
+
+	return sessions, true
+}
+
+// outranks reports whether lock a has priority over lock b: the higher
+// fencing token wins, and the lock ID breaks ties so every node agrees.
+func outranks(a, b *SemanticLock) bool {
+	if a.FencingToken != b.FencingToken {
+		return a.FencingToken > b.FencingToken
+	}
+	return a.ID < b.ID
+}
+
+// ForceReleaseLock releases a lock regardless of its holder.
+// It is reserved for operators breaking stuck locks, so both the operator
+// identity and a reason are required and recorded in the lock history.
+func (n *LockNegotiator) ForceReleaseLock(ctx context.Context, lockID, reason, operator string) (*ForceReleaseNotice, error) {
+	if operator == "" {
+		return nil, ErrUnauthorized
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrReasonRequired
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	lock, err := n.store.ForceRemove(lockID, reason, operator)
+	if err != nil {
+		return nil, err
+	}
+
+	notice := &ForceReleaseNotice{
+		LockID:     lock.ID,
+		HolderID:   lock.HolderID,
+		HolderName: lock.HolderName,
+		Target:     lock.Target.String(),
+		Reason:     reason,
+		Operator:   operator,
+		ReleasedAt: time.Now(),
+	}
+
+	// Broadcast so that peers, including the original holder, drop the lock
+	if n.broadcastFn != nil {
+		if err := n.broadcastFn(ReleaseMessage{
+			Type:   "lock_released",
+			LockID: lockID,
+			Force:  notice,
+		}); err != nil {
+			fmt.Printf("broadcast force release failed: %v\n", err)
+		}
+	}
+
+	return notice, nil
+}
+
 // Negotiate negotiates a conflict.
 func (n *LockNegotiator) Negotiate(ctx context.Context, sessionID string, proposal *NegotiationProposal) (*NegotiationResult, error) {
 	n.mu.Lock()
@@ -308,16 +661,28 @@ func (n *LockNegotiator) Negotiate(ctx context.Context, sessionID string, propos
 	if !exists {
 		return nil, fmt.Errorf("session not found: %s", sessionID)
 	}
+	if session.State == StateCancelled {
+		return nil, ErrSessionCancelled
+	}
 
-	if time.Now().After(session.ExpiresAt) {
+	// An escalated session waits for a human, who settles it with a yield,
+	// split or priority proposal however long that takes
+	if session.State == StateEscalated {
+		if proposal.Type == ProposalEscalate {
+			return nil, fmt.Errorf("session already escalated: %s", sessionID)
+		}
+	} else if session.Resolution != nil {
+		return nil, fmt.Errorf("session already resolved: %s", session.Resolution.ResolutionType)
+	} else if time.Now().After(session.ExpiresAt) {
 		session.State = StateEscalated
+		session.EscalatedAt = time.Now()
 		result := &NegotiationResult{
 			Success:        false,
 			ResolutionType: ResolutionTimedOut,
 			Message:        "negotiation timed out",
 			ResolvedAt:     time.Now(),
 		}
-		session.Resolution = result
+		n.resolve(session, result)
 
 		if n.onEscalate != nil {
 			n.onEscalate(session)
@@ -350,16 +715,103 @@ func (n *LockNegotiator) Vote(ctx context.Context, sessionID string, vote *Vote)
 		return fmt.Errorf("session not found: %s", sessionID)
 	}
 
+	if session.State == StateCancelled {
+		return ErrSessionCancelled
+	}
+	if session.Resolution != nil {
+		return fmt.Errorf("session already resolved: %s", session.Resolution.ResolutionType)
+	}
+
+	// Observers never count towards quorum
+	if _, ok := n.observers[vote.VoterID]; ok {
+		return ErrObserverMode
+	}
+
 	session.Votes[vote.VoterID] = vote
 
 	// Check if voting is complete
 	if len(session.Votes) >= session.RequiredVotes {
-		n.resolveByVotes(session)
+		return n.resolveByVotes(session)
 	}
 
 	return nil
 }
 
+// CancelSession cancels a negotiation the requester no longer needs, e.g.
+// because its agent abandoned the change. Only the holder of the requested
+// lock may cancel. The session is resolved as cancelled rather than
+// escalated, the requester's pending intent and any provisional lock are
+// dropped, and the cancellation is broadcast so peers stop voting on it.
+func (n *LockNegotiator) CancelSession(ctx context.Context, sessionID, requesterID string) (*NegotiationResult, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	session, exists := n.sessions[sessionID]
+	if !exists {
+		return nil, ErrSessionNotFound
+	}
+	if session.RequestedLock == nil || session.RequestedLock.HolderID != requesterID {
+		return nil, ErrNotLockHolder
+	}
+	if session.Resolution != nil {
+		return nil, fmt.Errorf("session already resolved: %s", session.Resolution.ResolutionType)
+	}
+
+	result := n.cancelSession(session, "cancelled by requester")
+
+	if n.broadcastFn != nil {
+		if err := n.broadcastFn(NegotiationCancelMessage{
+			Type:        "negotiation_cancelled",
+			SessionID:   session.ID,
+			LockID:      session.RequestedLock.ID,
+			RequesterID: requesterID,
+		}); err != nil {
+			fmt.Printf("broadcast negotiation cancel failed: %v\n", err)
+		}
+	}
+
+	return result, nil
+}
+
+// HandleRemoteCancel applies a cancellation broadcast by the requester's
+// node. Unknown or already resolved sessions are ignored.
+func (n *LockNegotiator) HandleRemoteCancel(msg *NegotiationCancelMessage) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	session, exists := n.sessions[msg.SessionID]
+	if !exists || session.Resolution != nil {
+		return false
+	}
+	if session.RequestedLock == nil || session.RequestedLock.HolderID != msg.RequesterID {
+		return false
+	}
+	n.cancelSession(session, "cancelled by requester")
+	return true
+}
+
+// cancelSession resolves a session as cancelled and drops the requester's
+// provisional state; an upgrade keeps its read lock. The caller must hold n.mu.
+func (n *LockNegotiator) cancelSession(session *NegotiationSession, message string) *NegotiationResult {
+	requested := session.RequestedLock
+	delete(n.intentQueue, requested.ID)
+	if held, err := n.store.Get(requested.ID); err == nil && held.HolderID == requested.HolderID && !session.Upgrade {
+		_ = n.store.Remove(requested.ID)
+	}
+
+	result := &NegotiationResult{
+		Success:        false,
+		WinnerLock:     session.ConflictingLock,
+		LoserLock:      requested,
+		ResolutionType: ResolutionCancelled,
+		Message:        message,
+		ResolvedAt:     time.Now(),
+	}
+	session.State = StateCancelled
+	n.resolve(session, result)
+	return result
+}
+
 // GetSession retrieves a negotiation session.
 func (n *LockNegotiator) GetSession(sessionID string) (*NegotiationSession, error) {
 	n.mu.RLock()
@@ -388,6 +840,50 @@ func (n *LockNegotiator) ListActiveSessions() []*NegotiationSession {
 	return sessions
 }
 
+// ListEscalatedSessions lists sessions escalated to a human and not yet
+// settled by one.
+func (n *LockNegotiator) ListEscalatedSessions() []*NegotiationSession {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	var sessions []*NegotiationSession
+	for _, session := range n.sessions {
+		if session.State == StateEscalated {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions
+}
+
+// RestoreSessions adds negotiation sessions that were pending when the
+// node last shut down. Resolved, expired and already known sessions are
+// skipped. It returns the number restored.
+func (n *LockNegotiator) RestoreSessions(sessions []*NegotiationSession) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	restored := 0
+	for _, session := range sessions {
+		if session == nil || session.Resolution != nil || now.After(session.ExpiresAt) {
+			continue
+		}
+		if session.RequestedLock == nil || session.ConflictingLock == nil {
+			continue
+		}
+		if _, exists := n.sessions[session.ID]; exists {
+			continue
+		}
+		if session.Votes == nil {
+			session.Votes = make(map[string]*Vote)
+		}
+		n.sessions[session.ID] = session
+		restored++
+	}
+	return restored
+}
+
 // startNegotiationSession starts a negotiation session.
 func (n *LockNegotiator) startNegotiationSession(requested, conflicting *SemanticLock) *NegotiationSession {
 	now := time.Now()
@@ -407,9 +903,87 @@ func (n *LockNegotiator) startNegotiationSession(requested, conflicting *Semanti
 	return session
 }
 
+// startUpgradeSession starts a session negotiating an upgrade with one of
+// the target's other readers.
+func (n *LockNegotiator) startUpgradeSession(requested, reader *SemanticLock) *NegotiationSession {
+	session := n.startNegotiationSession(requested, reader)
+	delete(n.sessions, session.ID)
+	session.ID = fmt.Sprintf("neg-%s-%s", strings.TrimPrefix(requested.ID, lockIDPrefix), strings.TrimPrefix(reader.ID, lockIDPrefix))
+	session.Upgrade = true
+	n.sessions[session.ID] = session
+	return session
+}
+
+// grant hands a negotiated target to the session's requester: the
+// conflicting lock is removed and the requested lock stored. An upgrade
+// waits out every reader, so its write lock is stored and announced only
+// once no other lock conflicts with it; pending counts the locks still in
+// the way. If the requested lock cannot be stored the conflicting lock is
+// put back. Must be called with n.mu held.
+func (n *LockNegotiator) grant(session *NegotiationSession) (pending int, err error) {
+	requested := session.RequestedLock
+	removed, removeErr := n.store.Get(session.ConflictingLock.ID)
+	if removeErr == nil {
+		removeErr = n.store.Remove(removed.ID)
+	}
+
+	if session.Upgrade {
+		for _, other := range n.store.FindConflictsFor(requested.Target, requested.Mode) {
+			if other.ID != requested.ID {
+				pending++
+			}
+		}
+		if pending > 0 {
+			return pending, nil
+		}
+	}
+
+	if err := n.store.Add(requested); err != nil {
+		if removeErr == nil {
+			n.store.reinstate(removed)
+		}
+		return 0, fmt.Errorf("failed to grant %s: %w", requested.ID, err)
+	}
+
+	if session.Upgrade && n.broadcastFn != nil {
+		if err := n.broadcastFn(AcquireMessage{
+			Type: "lock_acquired",
+			Lock: requested,
+		}); err != nil {
+			fmt.Printf("broadcast upgrade failed: %v\n", err)
+		}
+	}
+	return 0, nil
+}
+
+// abandonUpgrade cancels the other sessions of an upgrade the requester
+// lost, so the remaining readers keep their locks. Must be called with
+// n.mu held.
+func (n *LockNegotiator) abandonUpgrade(lost *NegotiationSession) {
+	if !lost.Upgrade {
+		return
+	}
+	for _, session := range n.sessions {
+		if session != lost && session.Upgrade && session.Resolution == nil &&
+			session.RequestedLock.ID == lost.RequestedLock.ID {
+			n.cancelSession(session, "upgrade lost to "+lost.ConflictingLock.HolderName)
+		}
+	}
+}
+
+// grantMessage describes a won session, noting the readers an upgrade is
+// still waiting for.
+func grantMessage(message string, pending int) string {
+	if pending > 0 {
+		return fmt.Sprintf("%s; waiting for %d more readers", message, pending)
+	}
+	return message
+}
+
 // handleYieldProposal handles a yield proposal.
 func (n *LockNegotiator) handleYieldProposal(session *NegotiationSession, proposal *NegotiationProposal) (*NegotiationResult, error) {
 	var winner, loser *SemanticLock
+	pending := 0
 
 	if proposal.YielderID == session.RequestedLock.HolderID {
 		winner = session.ConflictingLock
@@ -419,8 +993,10 @@ func (n *LockNegotiator) handleYieldProposal(session *NegotiationSession, propos
 		loser = session.ConflictingLock
 
 		// Remove existing lock and add new lock
-		n.store.Remove(session.ConflictingLock.ID)
-		n.store.Add(session.RequestedLock)
+		var err error
+		if pending, err = n.grant(session); err != nil {
+			return nil, err
+		}
 	}
 
 	result := &NegotiationResult{
@@ -428,12 +1004,15 @@ func (n *LockNegotiator) handleYieldProposal(session *NegotiationSession, propos
 		WinnerLock:     winner,
 		LoserLock:      loser,
 		ResolutionType: ResolutionNegotiated,
-		Message:        fmt.Sprintf("%s yielded to %s", loser.HolderName, winner.HolderName),
+		Message:        grantMessage(fmt.Sprintf("%s yielded to %s", loser.HolderName, winner.HolderName), pending),
 		ResolvedAt:     time.Now(),
 	}
 
 	session.State = StateAcquired
-	session.Resolution = result
+	n.resolve(session, result)
+	if winner != session.RequestedLock {
+		n.abandonUpgrade(session)
+	}
 
 	return result, nil
 }
@@ -443,6 +1022,9 @@ func (n *LockNegotiator) handleSplitProposal(session *NegotiationSession, propos
 	// Split the target so each party gets their own region
 	// This implementation assumes line-range based splitting
 
+	if session.Upgrade {
+		return nil, fmt.Errorf("split is not supported when upgrading a read lock")
+	}
 	if proposal.SplitPoint <= session.RequestedLock.Target.StartLine ||
 		proposal.SplitPoint >= session.RequestedLock.Target.EndLine {
 		return nil, fmt.Errorf("invalid split point: %d", proposal.SplitPoint)
@@ -465,7 +1047,7 @@ func (n *LockNegotiator) handleSplitProposal(session *NegotiationSession, propos
 	}
 
 	session.State = StateAcquired
-	session.Resolution = result
+	n.resolve(session, result)
 
 	return result, nil
 }
@@ -474,12 +1056,15 @@ func (n *LockNegotiator) handleSplitProposal(session *NegotiationSession, propos
 func (n *LockNegotiator) handlePriorityProposal(session *NegotiationSession) (*NegotiationResult, error) {
 	// Priority based on fencing token
 	var winner, loser *SemanticLock
+	pending := 0
 
 	if session.RequestedLock.FencingToken > session.ConflictingLock.FencingToken {
 		winner = session.RequestedLock
 		loser = session.ConflictingLock
-		n.store.Remove(session.ConflictingLock.ID)
-		n.store.Add(session.RequestedLock)
+		var err error
+		if pending, err = n.grant(session); err != nil {
+			return nil, err
+		}
 	} else {
 		winner = session.ConflictingLock
 		loser = session.RequestedLock
@@ -490,12 +1075,15 @@ func (n *LockNegotiator) handlePriorityProposal(session *NegotiationSession) (*N
 		WinnerLock:     winner,
 		LoserLock:      loser,
 		ResolutionType: ResolutionNegotiated,
-		Message:        fmt.Sprintf("priority: fencing token %d > %d", winner.FencingToken, loser.FencingToken),
+		Message:        grantMessage(fmt.Sprintf("priority: fencing token %d > %d", winner.FencingToken, loser.FencingToken), pending),
 		ResolvedAt:     time.Now(),
 	}
 
 	session.State = StateAcquired
-	session.Resolution = result
+	n.resolve(session, result)
+	if winner != session.RequestedLock {
+		n.abandonUpgrade(session)
+	}
 
 	return result, nil
 }
@@ -503,6 +1091,7 @@ func (n *LockNegotiator) handlePriorityProposal(session *NegotiationSession) (*N
 // handleEscalateProposal handles an escalation proposal.
 func (n *LockNegotiator) handleEscalateProposal(session *NegotiationSession, proposal *NegotiationProposal) (*NegotiationResult, error) {
 	session.State = StateEscalated
+	session.EscalatedAt = time.Now()
 
 	result := &NegotiationResult{
 		Success:        false,
@@ -511,7 +1100,7 @@ func (n *LockNegotiator) handleEscalateProposal(session *NegotiationSession, pro
 		ResolvedAt:     time.Now(),
 	}
 
-	session.Resolution = result
+	n.resolve(session, result)
 
 	if n.onEscalate != nil {
 		n.onEscalate(session)
@@ -520,8 +1109,9 @@ func (n *LockNegotiator) handleEscalateProposal(session *NegotiationSession, pro
 	return result, ErrHumanInterventionRequired
 }
 
-// resolveByVotes resolves by votes.
-func (n *LockNegotiator) resolveByVotes(session *NegotiationSession) {
+// resolveByVotes resolves by votes. If an approved lock cannot be
+// granted the session stays open.
+func (n *LockNegotiator) resolveByVotes(session *NegotiationSession) error {
 	approves := 0
 	for _, vote := range session.Votes {
 		if vote.Approve {
@@ -532,15 +1122,17 @@ func (n *LockNegotiator) resolveByVotes(session *NegotiationSession) {
 	var result *NegotiationResult
 	if approves > len(session.Votes)/2 {
 		// Approved by majority
-		n.store.Remove(session.ConflictingLock.ID)
-		n.store.Add(session.RequestedLock)
+		pending, err := n.grant(session)
+		if err != nil {
+			return err
+		}
 
 		result = &NegotiationResult{
 			Success:        true,
 			WinnerLock:     session.RequestedLock,
 			LoserLock:      session.ConflictingLock,
 			ResolutionType: ResolutionApproved,
-			Message:        fmt.Sprintf("approved by vote: %d/%d", approves, len(session.Votes)),
+			Message:        grantMessage(fmt.Sprintf("approved by vote: %d/%d", approves, len(session.Votes)), pending),
 			ResolvedAt:     time.Now(),
 		}
 		session.State = StateAcquired
@@ -556,7 +1148,11 @@ func (n *LockNegotiator) resolveByVotes(session *NegotiationSession) {
 		session.State = StateRejected
 	}
 
-	session.Resolution = result
+	n.resolve(session, result)
+	if !result.Success {
+		n.abandonUpgrade(session)
+	}
+	return nil
 }
 
 // cleanupExpiredSessions cleans up expired sessions.
@@ -623,6 +1219,30 @@ type AcquireMessage struct {
 
 // ReleaseMessage is a release message.
 type ReleaseMessage struct {
-	Type   string `json:"type"`
-	LockID string `json:"lock_id"`
+	Type   string              `json:"type"`
+	LockID string              `json:"lock_id"`
+	Force  *ForceReleaseNotice `json:"force,omitempty"` // set for operator force releases
+}
+
+// NegotiationCancelMessage announces that a requester cancelled its
+// negotiation session.
+type NegotiationCancelMessage struct {
+	Type        string `json:"type"`
+	SessionID   string `json:"session_id"`
+	LockID      string `json:"lock_id"`
+	RequesterID string `json:"requester_id"`
+}
+
+// ForceReleaseNotice describes a lock broken by an operator.
+type ForceReleaseNotice struct {
+	LockID     string    `json:"lock_id"`
+	HolderID   string    `json:"holder_id"`
+	HolderName string    `json:"holder_name"`
+	Target     string    `json:"target"`
+	Reason     string    `json:"reason"`
+	Operator   string    `json:"operator"`
+	ReleasedAt time.Time `json:"released_at"`
+	// PreemptedBy is the lock a conflict policy released this one for;
+	// nil for operator force releases.
+	PreemptedBy *SemanticLock `json:"preempted_by,omitempty"`
 }
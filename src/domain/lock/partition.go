@@ -0,0 +1,144 @@
+package lock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PartitionState is whether this node can reach a quorum of the cluster.
+type PartitionState string
+
+const (
+	PartitionHealthy     PartitionState = "healthy"
+	PartitionPartitioned PartitionState = "partitioned"
+)
+
+// PartitionConfig configures partition detection.
+type PartitionConfig struct {
+	// MemberTTL is how long a disconnected peer still counts as a cluster
+	// member for quorum. Peers gone longer are assumed to have left.
+	MemberTTL time.Duration `json:"member_ttl"`
+	// CheckInterval is how often connectivity is observed.
+	CheckInterval time.Duration `json:"check_interval"`
+}
+
+// DefaultPartitionConfig returns the default partition detection configuration.
+func DefaultPartitionConfig() PartitionConfig {
+	return PartitionConfig{
+		MemberTTL:     10 * time.Minute,
+		CheckInterval: 5 * time.Second,
+	}
+}
+
+// Connectivity is a snapshot of the peers this node can reach.
+type Connectivity struct {
+	// Peers are the currently connected peers.
+	Peers []string
+	// Leaf is true when this node reaches the cluster through super peers.
+	Leaf bool
+	// SuperPeers are the super peers a leaf is currently connected to.
+	SuperPeers []string
+}
+
+// PartitionEvent reports that this node lost or regained quorum.
+type PartitionEvent struct {
+	State PartitionState `json:"state"`
+	// Since is when the partition began. On heal it bounds the window in
+	// which conflicting locks may have been acquired.
+	Since     time.Time `json:"since"`
+	At        time.Time `json:"at"`
+	Reachable int       `json:"reachable"` // members reachable, including this node
+	Members   int       `json:"members"`   // known members, including this node
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// PartitionDetector tracks cluster membership and detects the sudden loss
+// of quorum or of every super peer, and the heal that follows.
+type PartitionDetector struct {
+	mu            sync.Mutex
+	config        PartitionConfig
+	lastSeen      map[string]time.Time // peer -> last time it was connected
+	hadSuperPeers bool
+	state         PartitionState
+	since         time.Time
+}
+
+// NewPartitionDetector creates a partition detector in the healthy state.
+func NewPartitionDetector(config PartitionConfig) *PartitionDetector {
+	def := DefaultPartitionConfig()
+	if config.MemberTTL <= 0 {
+		config.MemberTTL = def.MemberTTL
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = def.CheckInterval
+	}
+	return &PartitionDetector{
+		config:   config,
+		lastSeen: make(map[string]time.Time),
+		state:    PartitionHealthy,
+	}
+}
+
+// Config returns the detector configuration.
+func (d *PartitionDetector) Config() PartitionConfig {
+	return d.config
+}
+
+// State returns the current partition state.
+func (d *PartitionDetector) State() PartitionState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state
+}
+
+// Observe records a connectivity snapshot and returns an event when the
+// node becomes partitioned or heals, or nil when nothing changed.
+func (d *PartitionDetector) Observe(c Connectivity, now time.Time) *PartitionEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, p := range c.Peers {
+		d.lastSeen[p] = now
+	}
+	for p, seen := range d.lastSeen {
+		if now.Sub(seen) > d.config.MemberTTL {
+			delete(d.lastSeen, p)
+		}
+	}
+
+	members := len(d.lastSeen) + 1
+	reachable := len(c.Peers) + 1
+
+	// A leaf that had super peers and lost all of them is cut off even if
+	// it still sees a few other leaves.
+	superLost := c.Leaf && d.hadSuperPeers && len(c.SuperPeers) == 0
+	if c.Leaf {
+		d.hadSuperPeers = d.hadSuperPeers || len(c.SuperPeers) > 0
+	} else {
+		d.hadSuperPeers = false
+	}
+
+	var reason string
+	switch {
+	case reachable*2 <= members:
+		reason = fmt.Sprintf("lost quorum: %d of %d members reachable", reachable, members)
+	case superLost:
+		reason = "lost connectivity to every super peer"
+	}
+
+	event := &PartitionEvent{At: now, Reachable: reachable, Members: members, Reason: reason}
+	switch {
+	case reason != "" && d.state == PartitionHealthy:
+		d.state = PartitionPartitioned
+		d.since = now
+	case reason == "" && d.state == PartitionPartitioned:
+		d.state = PartitionHealthy
+	default:
+		return nil
+	}
+
+	event.State = d.state
+	event.Since = d.since
+	return event
+}
@@ -0,0 +1,77 @@
+package lock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionDetector_QuorumLossAndHeal(t *testing.T) {
+	d := NewPartitionDetector(PartitionConfig{MemberTTL: time.Hour})
+	now := time.Now()
+
+	all := Connectivity{Peers: []string{"b", "c", "d", "e"}}
+	if ev := d.Observe(all, now); ev != nil {
+		t.Fatalf("healthy cluster should not emit an event, got %+v", ev)
+	}
+
+	// Cut off from three of four peers: 2 of 5 members reachable
+	cut := Connectivity{Peers: []string{"b"}}
+	ev := d.Observe(cut, now.Add(time.Second))
+	if ev == nil || ev.State != PartitionPartitioned {
+		t.Fatalf("expected partition event, got %+v", ev)
+	}
+	if ev.Reachable != 2 || ev.Members != 5 {
+		t.Errorf("expected 2 of 5 reachable, got %d of %d", ev.Reachable, ev.Members)
+	}
+	if ev := d.Observe(cut, now.Add(2*time.Second)); ev != nil {
+		t.Errorf("partition should be reported once, got %+v", ev)
+	}
+
+	healed := d.Observe(all, now.Add(time.Minute))
+	if healed == nil || healed.State != PartitionHealthy {
+		t.Fatalf("expected heal event, got %+v", healed)
+	}
+	if !healed.Since.Equal(now.Add(time.Second)) {
+		t.Errorf("heal should carry partition start, got %v", healed.Since)
+	}
+}
+
+func TestPartitionDetector_MajorityStaysHealthy(t *testing.T) {
+	d := NewPartitionDetector(PartitionConfig{MemberTTL: time.Hour})
+	now := time.Now()
+
+	d.Observe(Connectivity{Peers: []string{"b", "c", "d", "e"}}, now)
+	// Losing two of four peers still leaves 3 of 5
+	if ev := d.Observe(Connectivity{Peers: []string{"b", "c"}}, now.Add(time.Second)); ev != nil {
+		t.Errorf("majority side should stay healthy, got %+v", ev)
+	}
+}
+
+func TestPartitionDetector_SuperPeerLoss(t *testing.T) {
+	d := NewPartitionDetector(PartitionConfig{MemberTTL: time.Hour})
+	now := time.Now()
+
+	peers := []string{"s1", "l1", "l2", "l3"}
+	d.Observe(Connectivity{Peers: peers, Leaf: true, SuperPeers: []string{"s1"}}, now)
+
+	ev := d.Observe(Connectivity{Peers: []string{"l1", "l2", "l3"}, Leaf: true}, now.Add(time.Second))
+	if ev == nil || ev.State != PartitionPartitioned {
+		t.Fatalf("leaf losing every super peer should be partitioned, got %+v", ev)
+	}
+}
+
+func TestPartitionDetector_DepartedPeersExpire(t *testing.T) {
+	d := NewPartitionDetector(PartitionConfig{MemberTTL: time.Minute})
+	now := time.Now()
+
+	d.Observe(Connectivity{Peers: []string{"b", "c"}}, now)
+	if ev := d.Observe(Connectivity{}, now.Add(time.Second)); ev == nil {
+		t.Fatal("expected partition after losing every peer")
+	}
+
+	// Peers gone longer than the TTL are assumed to have left
+	ev := d.Observe(Connectivity{}, now.Add(2*time.Minute))
+	if ev == nil || ev.State != PartitionHealthy {
+		t.Errorf("expected heal once departed peers expire, got %+v", ev)
+	}
+}
@@ -0,0 +1,147 @@
+package lock
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// LockPersister stores the locks this node holds so they survive a
+// restart. Implementations decide where and how, e.g. a JSON file in the
+// data directory or an embedded database.
+type LockPersister interface {
+	// Save replaces the persisted locks with locks.
+	Save(locks []*SemanticLock) error
+	// Load returns the locks saved last.
+	Load() ([]*SemanticLock, error)
+}
+
+// SetPersister saves this node's locks through p every time the lock table
+// changes, until ctx is done or the service is closed; the last state is
+// saved on the way out. A nil persister stops persisting. Save errors are
+// passed to onError when it is set.
+func (s *LockService) SetPersister(ctx context.Context, p LockPersister, onError func(error)) {
+	s.stopPersister()
+	if p == nil {
+		return
+	}
+
+	dirty := make(chan struct{}, 1)
+	s.store.SetChangedHandler(func() {
+		select {
+		case dirty <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	s.persistMu.Lock()
+	s.persistCancel = cancel
+	s.persistDone = done
+	s.persistMu.Unlock()
+
+	save := func() {
+		if err := p.Save(s.persistedLocks()); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-ctx.Done():
+				save()
+				return
+			case <-dirty:
+				save()
+			}
+		}
+	}()
+}
+
+// stopPersister stops the persister, waiting for its final save.
+func (s *LockService) stopPersister() {
+	s.persistMu.Lock()
+	cancel, done := s.persistCancel, s.persistDone
+	s.persistCancel, s.persistDone = nil, nil
+	s.persistMu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	s.store.SetChangedHandler(nil)
+	cancel()
+	<-done
+}
+
+// persistedLocks returns copies of the live locks this node holds.
+func (s *LockService) persistedLocks() []*SemanticLock {
+	held := s.store.ListByHolder(s.nodeID)
+	locks := make([]*SemanticLock, 0, len(held))
+	s.store.mu.RLock()
+	for _, lock := range held {
+		copied := *lock
+		locks = append(locks, &copied)
+	}
+	s.store.mu.RUnlock()
+	return locks
+}
+
+// RehydrateLocks restores this node's locks saved by p in an earlier run.
+// Locks of other holders, expired locks and locks overlapping a region
+// already held are skipped. Restored locks keep their fencing tokens, and
+// new locks are issued tokens above them. It returns the restored locks,
+// which peers learn about through AnnounceLocks.
+func (s *LockService) RehydrateLocks(p LockPersister) ([]*SemanticLock, error) {
+	saved, err := p.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var restored []*SemanticLock
+	for _, lock := range saved {
+		if lock == nil || lock.HolderID != s.nodeID {
+			continue
+		}
+		if s.RestoreLocks([]*SemanticLock{lock}) == 0 {
+			continue
+		}
+		raiseFencingToken(lock.FencingToken)
+		restored = append(restored, lock)
+	}
+	return restored, nil
+}
+
+// AnnounceLocks re-broadcasts locks this node still holds, e.g. after
+// RehydrateLocks, so peers that dropped them on restart track them again.
+// It returns how many were announced.
+func (s *LockService) AnnounceLocks(locks []*SemanticLock) (int, error) {
+	fn := s.negotiator.broadcastFn
+	if fn == nil {
+		return 0, nil
+	}
+
+	announced := 0
+	for _, held := range locks {
+		lock, err := s.store.Get(held.ID)
+		if err != nil || lock.HolderID != s.nodeID {
+			continue
+		}
+		if err := fn(AcquireMessage{Type: "lock_acquired", Lock: lock}); err != nil {
+			return announced, err
+		}
+		announced++
+	}
+	return announced, nil
+}
+
+// raiseFencingToken makes sure the fencing token counter is at least
+// token, so a restarted node never issues a token older than one it holds.
+func raiseFencingToken(token uint64) {
+	for {
+		current := atomic.LoadUint64(&fencingTokenCounter)
+		if current >= token || atomic.CompareAndSwapUint64(&fencingTokenCounter, current, token) {
+			return
+		}
+	}
+}
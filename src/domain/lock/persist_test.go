@@ -0,0 +1,124 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryPersister keeps saved locks in memory.
+type memoryPersister struct {
+	mu    sync.Mutex
+	locks []*SemanticLock
+	saves chan struct{}
+}
+
+func newMemoryPersister() *memoryPersister {
+	return &memoryPersister{saves: make(chan struct{}, 16)}
+}
+
+func (p *memoryPersister) Save(locks []*SemanticLock) error {
+	p.mu.Lock()
+	p.locks = locks
+	p.mu.Unlock()
+	select {
+	case p.saves <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (p *memoryPersister) Load() ([]*SemanticLock, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.locks, nil
+}
+
+func TestLockService_PersisterSavesHeldLocks(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-1", "Agent")
+	defer svc.Close()
+	p := newMemoryPersister()
+	svc.SetPersister(ctx, p, nil)
+
+	result, err := svc.AcquireLock(ctx, &AcquireLockRequest{
+		TargetType: TargetFile,
+		FilePath:   "/test/persist.go",
+		StartLine:  1,
+		EndLine:    10,
+		Intention:  "edit",
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	remote := NewSemanticLock(&SemanticTarget{Type: TargetFile, FilePath: "/test/other.go", StartLine: 1, EndLine: 5}, "node-2", "Peer", "edit")
+	if err := svc.HandleRemoteLockAcquired(remote); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := p.Load()
+	if len(saved) != 1 || saved[0].ID != result.Lock.ID || saved[0].FencingToken != result.Lock.FencingToken {
+		t.Fatalf("expected only the held lock to be saved, got %+v", saved)
+	}
+}
+
+func TestLockService_RehydrateLocksKeepsFencingTokens(t *testing.T) {
+	ctx := context.Background()
+	target := &SemanticTarget{Type: TargetFile, FilePath: "/test/persist.go", StartLine: 1, EndLine: 10}
+	held := NewSemanticLock(target, "node-1", "Agent", "edit")
+	held.FencingToken = atomic.LoadUint64(&fencingTokenCounter) + 1000
+	held.ExpiresAt = time.Now().Add(time.Minute)
+	expired := NewSemanticLock(&SemanticTarget{Type: TargetFile, FilePath: "/test/old.go", StartLine: 1, EndLine: 5}, "node-1", "Agent", "edit")
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	foreign := NewSemanticLock(&SemanticTarget{Type: TargetFile, FilePath: "/test/peer.go", StartLine: 1, EndLine: 5}, "node-2", "Peer", "edit")
+
+	p := newMemoryPersister()
+	_ = p.Save([]*SemanticLock{held, expired, foreign})
+
+	svc := NewLockService(ctx, "node-1", "Agent")
+	defer svc.Close()
+	restored, err := svc.RehydrateLocks(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 1 || restored[0].ID != held.ID {
+		t.Fatalf("expected only the live held lock to be restored, got %+v", restored)
+	}
+	if got, err := svc.GetLock(held.ID); err != nil || got.FencingToken != held.FencingToken {
+		t.Fatalf("expected the restored lock with its fencing token, got %+v (%v)", got, err)
+	}
+
+	next := NewSemanticLock(&SemanticTarget{Type: TargetFile, FilePath: "/test/new.go", StartLine: 1, EndLine: 5}, "node-1", "Agent", "edit")
+	if next.FencingToken <= held.FencingToken {
+		t.Errorf("expected new tokens above %d, got %d", held.FencingToken, next.FencingToken)
+	}
+
+	var announced []AcquireMessage
+	svc.SetBroadcastFn(func(msg any) error {
+		if m, ok := msg.(AcquireMessage); ok {
+			announced = append(announced, m)
+		}
+		return nil
+	})
+	if n, err := svc.AnnounceLocks(restored); err != nil || n != 1 {
+		t.Fatalf("AnnounceLocks = %d, %v", n, err)
+	}
+	if announced[0].Lock.FencingToken != held.FencingToken {
+		t.Errorf("expected the original fencing token to be announced, got %d", announced[0].Lock.FencingToken)
+	}
+
+	// A peer that never dropped the lock accepts the re-announcement
+	peer := NewLockService(ctx, "node-2", "Peer")
+	defer peer.Close()
+	if err := peer.HandleRemoteLockAcquired(held); err != nil {
+		t.Fatal(err)
+	}
+	if err := peer.HandleRemoteLockAcquired(announced[0].Lock); err != nil {
+		t.Errorf("expected a re-announced lock to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+package lock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PolicyType names a conflict resolution policy.
+type PolicyType string
+
+const (
+	// PolicyManual leaves conflicts to negotiation between the agents;
+	// unresolved ones escalate to a human. This is the default.
+	PolicyManual PolicyType = "manual"
+	// PolicyFIFO keeps the lock with whoever took it first.
+	PolicyFIFO PolicyType = "fifo"
+	// PolicyPriorityWins lets a higher priority agent take over a lock
+	// held by a lower priority one.
+	PolicyPriorityWins PolicyType = "priority_wins"
+	// PolicyCostAware yields while this node is short on token budget and
+	// otherwise decides by priority.
+	PolicyCostAware PolicyType = "cost_aware"
+)
+
+// DefaultBudgetThreshold is the share of the daily token budget after which
+// PolicyCostAware yields.
+const DefaultBudgetThreshold = 0.8
+
+// ConflictPolicy decides lock conflicts without a human.
+type ConflictPolicy interface {
+	// Name identifies the policy in resolutions and lock history.
+	Name() PolicyType
+	// Winner returns requested or held, whichever should hold the target,
+	// or nil to leave the conflict to negotiation.
+	Winner(requested, held *SemanticLock) *SemanticLock
+}
+
+// PolicyConfig selects and configures a conflict policy.
+type PolicyConfig struct {
+	Policy PolicyType `json:"policy"`
+	// Priorities ranks agents by node ID or name; higher wins and unlisted
+	// agents rank 0.
+	Priorities map[string]int `json:"priorities,omitempty"`
+	// BudgetThreshold is the share of the daily token budget (0-1) after
+	// which PolicyCostAware yields (default DefaultBudgetThreshold).
+	BudgetThreshold float64 `json:"budget_threshold,omitempty"`
+}
+
+// NewConflictPolicy builds the configured policy. budgetUsed reports the
+// share of this node's daily token budget used so far and is only needed
+// for PolicyCostAware. It returns nil for PolicyManual.
+func NewConflictPolicy(cfg PolicyConfig, budgetUsed func() float64) (ConflictPolicy, error) {
+	for agent, priority := range cfg.Priorities {
+		if strings.TrimSpace(agent) == "" {
+			return nil, fmt.Errorf("conflict priority %d has no agent", priority)
+		}
+	}
+
+	switch cfg.Policy {
+	case "", PolicyManual:
+		return nil, nil
+	case PolicyFIFO:
+		return FIFOPolicy{}, nil
+	case PolicyPriorityWins:
+		return PriorityPolicy{Priorities: cfg.Priorities}, nil
+	case PolicyCostAware:
+		threshold := cfg.BudgetThreshold
+		if threshold == 0 {
+			threshold = DefaultBudgetThreshold
+		}
+		if threshold < 0 || threshold > 1 {
+			return nil, fmt.Errorf("invalid budget threshold %v: must be between 0 and 1", threshold)
+		}
+		if budgetUsed == nil {
+			return nil, fmt.Errorf("%s policy needs token usage", PolicyCostAware)
+		}
+		return CostAwarePolicy{
+			BudgetUsed: budgetUsed,
+			Threshold:  threshold,
+			Fallback:   PriorityPolicy{Priorities: cfg.Priorities},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown conflict policy %q", cfg.Policy)
+	}
+}
+
+// FIFOPolicy keeps the lock with its current holder.
+type FIFOPolicy struct{}
+
+// Name implements ConflictPolicy.
+func (FIFOPolicy) Name() PolicyType { return PolicyFIFO }
+
+// Winner implements ConflictPolicy.
+func (FIFOPolicy) Winner(requested, held *SemanticLock) *SemanticLock {
+	return held
+}
+
+// PriorityPolicy gives the lock to the higher priority agent. On a tie the
+// holder keeps it, so agents of equal rank never preempt each other.
+type PriorityPolicy struct {
+	Priorities map[string]int
+}
+
+// Name implements ConflictPolicy.
+func (PriorityPolicy) Name() PolicyType { return PolicyPriorityWins }
+
+// Winner implements ConflictPolicy.
+func (p PriorityPolicy) Winner(requested, held *SemanticLock) *SemanticLock {
+	if p.priority(requested) > p.priority(held) {
+		return requested
+	}
+	return held
+}
+
+// priority looks the holder up by node ID, then by name.
+func (p PriorityPolicy) priority(lock *SemanticLock) int {
+	if priority, ok := p.Priorities[lock.HolderID]; ok {
+		return priority
+	}
+	return p.Priorities[lock.HolderName]
+}
+
+// CostAwarePolicy yields to the holder once this node has used Threshold
+// of its daily token budget, rather than spend more tokens contending for
+// the lock and redoing work. Below it, Fallback decides.
+type CostAwarePolicy struct {
+	BudgetUsed func() float64
+	Threshold  float64
+	Fallback   ConflictPolicy
+}
+
+// Name implements ConflictPolicy.
+func (CostAwarePolicy) Name() PolicyType { return PolicyCostAware }
+
+// Winner implements ConflictPolicy.
+func (p CostAwarePolicy) Winner(requested, held *SemanticLock) *SemanticLock {
+	if p.BudgetUsed() >= p.Threshold {
+		return held
+	}
+	if p.Fallback == nil {
+		return nil
+	}
+	return p.Fallback.Winner(requested, held)
+}
+
+// SetPolicy sets the policy deciding conflicts when an intent is
+// announced; nil leaves them to negotiation.
+func (n *LockNegotiator) SetPolicy(policy ConflictPolicy) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.policy = policy
+}
+
+// resolveByPolicy settles a requested lock's conflicts under the policy.
+// The requester is granted the target only if it wins every conflict; the
+// held locks are then released as if by an operator so their holders
+// learn they lost them. decided is false when the policy left any
+// conflict open. Must be called with n.mu held.
+func (n *LockNegotiator) resolveByPolicy(requested *SemanticLock, conflicts []*SemanticLock) (granted bool, preempted []*ForceReleaseNotice, decided bool) {
+	winners := make([]*SemanticLock, len(conflicts))
+	granted = true
+	for i, held := range conflicts {
+		winners[i] = n.policy.Winner(requested, held)
+		switch winners[i] {
+		case requested:
+		case held:
+			granted = false
+		default:
+			return false, nil, false
+		}
+	}
+
+	name := n.policy.Name()
+	now := time.Now()
+	for i, held := range conflicts {
+		winner, loser := held, requested
+		state := StateRejected
+		if winners[i] == requested {
+			winner, loser = requested, held
+			state = StateAcquired
+		}
+
+		session := &NegotiationSession{
+			ID:              fmt.Sprintf("neg-%s-%s", strings.TrimPrefix(requested.ID, lockIDPrefix), strings.TrimPrefix(held.ID, lockIDPrefix)),
+			RequestedLock:   requested,
+			ConflictingLock: held,
+			State:           state,
+			Votes:           make(map[string]*Vote),
+			StartedAt:       now,
+			ExpiresAt:       now,
+		}
+		n.resolve(session, &NegotiationResult{
+			Success:        winner == requested,
+			WinnerLock:     winner,
+			LoserLock:      loser,
+			ResolutionType: ResolutionPolicy,
+			Message:        fmt.Sprintf("%s policy: %s keeps %s over %s", name, winner.HolderName, held.Target, loser.HolderName),
+			ResolvedAt:     now,
+		})
+		n.sessions[session.ID] = session
+	}
+
+	if !granted {
+		return false, nil, true
+	}
+
+	operator := "policy:" + string(name)
+	reason := fmt.Sprintf("preempted by %s under the %s conflict policy", requested.HolderName, name)
+	for _, held := range conflicts {
+		lock, err := n.store.ForceRemove(held.ID, reason, operator)
+		if err != nil {
+			continue
+		}
+		notice := &ForceReleaseNotice{
+			LockID:      lock.ID,
+			HolderID:    lock.HolderID,
+			HolderName:  lock.HolderName,
+			Target:      lock.Target.String(),
+			Reason:      reason,
+			Operator:    operator,
+			ReleasedAt:  now,
+			PreemptedBy: requested,
+		}
+		preempted = append(preempted, notice)
+
+		if n.broadcastFn != nil {
+			if err := n.broadcastFn(ReleaseMessage{
+				Type:   "lock_released",
+				LockID: lock.ID,
+				Force:  notice,
+			}); err != nil {
+				fmt.Printf("broadcast policy release failed: %v\n", err)
+			}
+		}
+	}
+	return true, preempted, true
+}
+
+// CheckPreemption reports whether this node's conflict policy agrees with
+// a policy release broadcast by a peer: the released lock must conflict
+// with notice.PreemptedBy and lose the target to it. Token budgets are
+// the requester's own, so cost-aware preemptions are checked against the
+// fallback policy. A lock this node no longer holds passes.
+func (n *LockNegotiator) CheckPreemption(notice *ForceReleaseNotice) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	requested := notice.PreemptedBy
+	if requested == nil || requested.Target == nil {
+		return fmt.Errorf("%w: no preempting lock", ErrUnauthorized)
+	}
+	held, err := n.store.Get(notice.LockID)
+	if err != nil {
+		return nil
+	}
+
+	policy := n.policy
+	if cost, ok := policy.(CostAwarePolicy); ok {
+		policy = cost.Fallback
+	}
+	if policy == nil || !requested.ConflictsWith(held) || policy.Winner(requested, held) != requested {
+		return fmt.Errorf("%w: %s does not preempt %s", ErrUnauthorized, requested.ID, held.ID)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// policyTestRequest asks for lines 5-10 of the file node-2 holds 1-20 of.
+var policyTestRequest = AcquireLockRequest{
+	TargetType: TargetFile,
+	FilePath:   "/test/policy.go",
+	StartLine:  5,
+	EndLine:    10,
+	Intention:  "fix bug",
+}
+
+func TestLockService_FIFOPolicyDeniesRequester(t *testing.T) {
+	svc := newLockTestService(t)
+	svc.SetConflictPolicy(FIFOPolicy{})
+	held := holdRemoteTestLock(t, svc, "/test/policy.go")
+
+	_, err := svc.AcquireLock(context.Background(), &policyTestRequest)
+	if !errors.Is(err, ErrLockConflict) {
+		t.Fatalf("expected the requester to be denied, got %v", err)
+	}
+	if _, err := svc.GetLock(held.ID); err != nil {
+		t.Errorf("the holder should keep its lock: %v", err)
+	}
+	if active := svc.ListActiveNegotiations(); len(active) != 0 {
+		t.Errorf("a decided conflict should not leave a negotiation open, got %d", len(active))
+	}
+}
+
+func TestLockService_PriorityPolicyPreemptsLowerPriorityHolder(t *testing.T) {
+	svc := newLockTestService(t)
+	var sent []any
+	svc.SetBroadcastFn(func(msg any) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	svc.SetConflictPolicy(PriorityPolicy{Priorities: map[string]int{"Agent": 2, "node-2": 1}})
+	held := holdRemoteTestLock(t, svc, "/test/policy.go")
+
+	result, err := svc.AcquireLock(context.Background(), &policyTestRequest)
+	if err != nil || !result.Success {
+		t.Fatalf("expected the higher priority agent to get the lock, got %v", err)
+	}
+	if len(result.Preempted) != 1 || result.Preempted[0].LockID != held.ID || result.Preempted[0].Operator != "policy:priority_wins" {
+		t.Fatalf("expected the held lock to be preempted, got %+v", result.Preempted)
+	}
+	if _, err := svc.GetLock(held.ID); !errors.Is(err, ErrLockNotFound) {
+		t.Errorf("the preempted lock should be gone, got %v", err)
+	}
+
+	var forced bool
+	for _, msg := range sent {
+		if release, ok := msg.(ReleaseMessage); ok && release.Force != nil && release.LockID == held.ID {
+			forced = true
+		}
+	}
+	if !forced {
+		t.Error("expected the preemption to be broadcast as a force release so the holder learns of it")
+	}
+}
+
+func TestLockService_CostAwarePolicyYieldsOverBudget(t *testing.T) {
+	used := 0.9
+	policy, err := NewConflictPolicy(PolicyConfig{
+		Policy:     PolicyCostAware,
+		Priorities: map[string]int{"Agent": 1},
+	}, func() float64 { return used })
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := newLockTestService(t)
+	svc.SetConflictPolicy(policy)
+	holdRemoteTestLock(t, svc, "/test/policy.go")
+
+	if _, err := svc.AcquireLock(context.Background(), &policyTestRequest); !errors.Is(err, ErrLockConflict) {
+		t.Fatalf("expected to yield over budget, got %v", err)
+	}
+
+	used = 0.1
+	if result, err := svc.AcquireLock(context.Background(), &policyTestRequest); err != nil || !result.Success {
+		t.Fatalf("expected priority to decide under budget, got %v", err)
+	}
+}
+
+func TestNewConflictPolicy(t *testing.T) {
+	if policy, err := NewConflictPolicy(PolicyConfig{}, nil); err != nil || policy != nil {
+		t.Errorf("expected no policy by default, got %v, %v", policy, err)
+	}
+	if _, err := NewConflictPolicy(PolicyConfig{Policy: "coin_flip"}, nil); err == nil {
+		t.Error("expected an unknown policy to be rejected")
+	}
+	if _, err := NewConflictPolicy(PolicyConfig{Policy: PolicyCostAware, BudgetThreshold: 1.5}, func() float64 { return 0 }); err == nil {
+		t.Error("expected a budget threshold above 1 to be rejected")
+	}
+}
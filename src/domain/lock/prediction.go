@@ -0,0 +1,107 @@
+package lock
+
+import (
+	"sort"
+	"time"
+)
+
+// PredictionWindow is how far back lock history counts towards a
+// conflict prediction.
+const PredictionWindow = 30 * time.Minute
+
+// Contention scores at which a prediction becomes medium or high.
+const (
+	mediumContentionScore = 1
+	highContentionScore   = 4
+)
+
+// ConflictLikelihood rates how likely a new lock is to run into other
+// agents' work soon.
+type ConflictLikelihood string
+
+const (
+	LikelihoodLow    ConflictLikelihood = "low"
+	LikelihoodMedium ConflictLikelihood = "medium"
+	LikelihoodHigh   ConflictLikelihood = "high"
+)
+
+// ConflictHint predicts contention on a file from recent lock activity by
+// other agents. It is advisory: the lock is granted or refused as usual.
+type ConflictHint struct {
+	FilePath   string             `json:"file_path"`
+	Likelihood ConflictLikelihood `json:"likelihood"`
+	Score      int                `json:"score"`
+	// Acquisitions counts locks other agents took on the file in the window.
+	Acquisitions int `json:"acquisitions"`
+	// Conflicts counts lock conflicts on the file in the window.
+	Conflicts int `json:"conflicts"`
+	// ActiveLocks counts other agents' locks currently held in the file.
+	ActiveLocks int      `json:"active_locks"`
+	Agents      []string `json:"agents,omitempty"`
+	Window      string   `json:"window"`
+}
+
+// Elevated reports whether the hint is worth warning about.
+func (h *ConflictHint) Elevated() bool {
+	return h != nil && h.Likelihood != LikelihoodLow
+}
+
+// PredictConflict scores contention on filePath for holderID from lock
+// history since now-window and the locks currently held. Conflicts and
+// locks held right now weigh twice as much as past acquisitions.
+func PredictConflict(history []*HistoryEntry, active []*SemanticLock, holderID, filePath string, now time.Time, window time.Duration) *ConflictHint {
+	hint := &ConflictHint{FilePath: filePath, Window: window.String()}
+	agents := make(map[string]struct{})
+	since := now.Add(-window)
+
+	for _, entry := range history {
+		if entry.FilePath != filePath || entry.Timestamp.Before(since) {
+			continue
+		}
+		switch entry.Action {
+		case "conflict":
+			hint.Conflicts++
+		case "acquired":
+			if entry.HolderID == holderID {
+				continue
+			}
+			hint.Acquisitions++
+		default:
+			continue
+		}
+		if entry.HolderID != holderID {
+			agents[entry.HolderName] = struct{}{}
+		}
+	}
+	for _, l := range active {
+		if l.HolderID == holderID || l.Target == nil || l.Target.FilePath != filePath {
+			continue
+		}
+		hint.ActiveLocks++
+		agents[l.HolderName] = struct{}{}
+	}
+
+	for name := range agents {
+		if name != "" {
+			hint.Agents = append(hint.Agents, name)
+		}
+	}
+	sort.Strings(hint.Agents)
+
+	hint.Score = hint.Acquisitions + 2*hint.Conflicts + 2*hint.ActiveLocks
+	switch {
+	case hint.Score >= highContentionScore:
+		hint.Likelihood = LikelihoodHigh
+	case hint.Score >= mediumContentionScore:
+		hint.Likelihood = LikelihoodMedium
+	default:
+		hint.Likelihood = LikelihoodLow
+	}
+	return hint
+}
+
+// PredictConflict predicts contention on filePath for a lock this node is
+// about to take.
+func (s *LockService) PredictConflict(filePath string) *ConflictHint {
+	return PredictConflict(s.store.GetHistory(0), s.store.List(), s.nodeID, filePath, time.Now(), PredictionWindow)
+}
@@ -0,0 +1,63 @@
+package lock
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPredictConflict_HeavyContentionIsHigh(t *testing.T) {
+	now := time.Now()
+	entry := func(action, holder string, ago time.Duration) *HistoryEntry {
+		return &HistoryEntry{Timestamp: now.Add(-ago), Action: action, HolderID: holder, HolderName: holder, FilePath: "auth/login.go"}
+	}
+	history := []*HistoryEntry{
+		entry("acquired", "alice", 20*time.Minute),
+		entry("released", "alice", 18*time.Minute),
+		entry("acquired", "bob", 10*time.Minute),
+		entry("conflict", "bob", 9*time.Minute),
+		entry("acquired", "alice", 2*time.Minute),
+		// Outside the window and our own locks do not count
+		entry("acquired", "carol", 2*time.Hour),
+		entry("acquired", "me", time.Minute),
+	}
+	target, _ := NewSemanticTarget(TargetFile, "auth/login.go", "", 100, 120)
+	active := []*SemanticLock{NewSemanticLock(target, "alice", "alice", "add rate limiting")}
+
+	hint := PredictConflict(history, active, "me", "auth/login.go", now, PredictionWindow)
+	if hint.Likelihood != LikelihoodHigh || !hint.Elevated() {
+		t.Fatalf("expected high likelihood, got %+v", hint)
+	}
+	if hint.Acquisitions != 3 || hint.Conflicts != 1 || hint.ActiveLocks != 1 {
+		t.Errorf("unexpected counts: %+v", hint)
+	}
+	if !slices.Equal(hint.Agents, []string{"alice", "bob"}) {
+		t.Errorf("expected alice and bob, got %v", hint.Agents)
+	}
+
+	quiet := PredictConflict(history, active, "me", "docs/README.md", now, PredictionWindow)
+	if quiet.Likelihood != LikelihoodLow || quiet.Elevated() || quiet.Score != 0 {
+		t.Errorf("expected low likelihood for a quiet file, got %+v", quiet)
+	}
+}
+
+func TestLockService_AcquireAnnotatesConflictHint(t *testing.T) {
+	ctx := context.Background()
+	service := NewLockService(ctx, "node-1", "Node 1")
+	defer service.Close()
+
+	result, err := service.AcquireLock(ctx, &AcquireLockRequest{TargetType: TargetFile, FilePath: "quiet.go", StartLine: 1, EndLine: 10, Intention: "docs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ConflictHint == nil || result.ConflictHint.Likelihood != LikelihoodLow {
+		t.Fatalf("expected a low hint on a quiet file, got %+v", result.ConflictHint)
+	}
+
+	// A refused overlapping request is recorded as contention on the file
+	_, _ = service.AcquireLock(ctx, &AcquireLockRequest{TargetType: TargetFile, FilePath: "quiet.go", StartLine: 5, EndLine: 8, Intention: "fix"})
+	if hint := service.PredictConflict("quiet.go"); !hint.Elevated() || hint.Conflicts != 1 {
+		t.Errorf("expected the conflict to raise the prediction, got %+v", hint)
+	}
+}
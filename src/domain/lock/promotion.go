@@ -0,0 +1,158 @@
+package lock
+
+import (
+	"sync"
+	"time"
+)
+
+// PromotionWindow is how long a region lock counts towards promoting
+// later locks on the same symbol.
+const PromotionWindow = 30 * time.Minute
+
+// SymbolRange is a parsed symbol a region lock can be promoted to.
+type SymbolRange struct {
+	Type      TargetType
+	Name      string
+	StartLine int
+	EndLine   int
+}
+
+// SymbolResolver returns the symbols parsed from a file.
+type SymbolResolver func(filePath string) ([]SymbolRange, error)
+
+// promotionState tracks region locks per enclosing symbol.
+type promotionState struct {
+	mu        sync.Mutex
+	threshold int
+	resolve   SymbolResolver
+	seen      map[string][]time.Time // file/type/name -> region lock times
+}
+
+// SetSymbolPromotion makes the threshold-th region lock this node takes
+// inside the same parsed symbol within PromotionWindow a lock on the
+// symbol itself, whose range then follows the symbol through
+// SyncSymbolLocks. A threshold of zero disables promotion.
+func (s *LockService) SetSymbolPromotion(threshold int, resolve SymbolResolver) {
+	p := &s.promotion
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.threshold = threshold
+	p.resolve = resolve
+	p.seen = make(map[string][]time.Time)
+}
+
+// enclosingSymbol returns the innermost symbol containing the region a
+// file lock request covers, if promotion is enabled.
+func (s *LockService) enclosingSymbol(req *AcquireLockRequest) (*SymbolRange, bool) {
+	p := &s.promotion
+	p.mu.Lock()
+	threshold, resolve := p.threshold, p.resolve
+	p.mu.Unlock()
+
+	if threshold <= 0 || resolve == nil || req.TargetType != TargetFile || req.Name != "" {
+		return nil, false
+	}
+	symbols, err := resolve(req.FilePath)
+	if err != nil {
+		return nil, false
+	}
+
+	var best *SymbolRange
+	for i := range symbols {
+		sym := &symbols[i]
+		if sym.StartLine > req.StartLine || sym.EndLine < req.EndLine {
+			continue
+		}
+		if best == nil || sym.EndLine-sym.StartLine < best.EndLine-best.StartLine {
+			best = sym
+		}
+	}
+	return best, best != nil
+}
+
+func promotionKey(filePath string, sym *SymbolRange) string {
+	return filePath + "\x00" + string(sym.Type) + "\x00" + sym.Name
+}
+
+// promotionDue reports whether enough recent region locks fell inside sym
+// for the next one to be promoted, and resets the count if so.
+func (s *LockService) promotionDue(filePath string, sym *SymbolRange, now time.Time) bool {
+	p := &s.promotion
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := promotionKey(filePath, sym)
+	recent := p.seen[key][:0]
+	for _, at := range p.seen[key] {
+		if now.Sub(at) < PromotionWindow {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent)+1 < p.threshold {
+		p.seen[key] = recent
+		return false
+	}
+	delete(p.seen, key)
+	return true
+}
+
+// recordRegionLock counts a region lock granted inside sym.
+func (s *LockService) recordRegionLock(filePath string, sym *SymbolRange, now time.Time) {
+	p := &s.promotion
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.seen != nil {
+		key := promotionKey(filePath, sym)
+		p.seen[key] = append(p.seen[key], now)
+	}
+}
+
+// SyncSymbolLocks moves this node's symbol locks in filePath to where
+// their symbols are now, after the file changed, and announces the new
+// ranges to peers. It returns the locks that moved.
+func (s *LockService) SyncSymbolLocks(filePath string) []*SemanticLock {
+	p := &s.promotion
+	p.mu.Lock()
+	resolve := p.resolve
+	p.mu.Unlock()
+	if resolve == nil {
+		return nil
+	}
+
+	var symbolLocks []*SemanticLock
+	for _, l := range s.store.ListByHolder(s.nodeID) {
+		if l.Target.FilePath == filePath && l.Target.Type != TargetFile && l.Target.Name != "" {
+			symbolLocks = append(symbolLocks, l)
+		}
+	}
+	if len(symbolLocks) == 0 {
+		return nil
+	}
+	symbols, err := resolve(filePath)
+	if err != nil {
+		return nil
+	}
+
+	var moved []*SemanticLock
+	for _, l := range symbolLocks {
+		for _, sym := range symbols {
+			if sym.Type != l.Target.Type || sym.Name != l.Target.Name {
+				continue
+			}
+			if sym.StartLine == l.Target.StartLine && sym.EndLine == l.Target.EndLine {
+				break
+			}
+			if updated, err := s.store.Retarget(l.ID, s.nodeID, sym.StartLine, sym.EndLine); err == nil {
+				moved = append(moved, updated)
+			}
+			break
+		}
+	}
+
+	if fn := s.negotiator.broadcastFn; fn != nil {
+		for _, l := range moved {
+			_ = fn(AcquireMessage{Type: "lock_acquired", Lock: l})
+		}
+	}
+	return moved
+}
@@ -0,0 +1,138 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// fakeSymbols is a SymbolResolver whose symbols the test moves around.
+type fakeSymbols struct {
+	mu      sync.Mutex
+	symbols []SymbolRange
+}
+
+func (f *fakeSymbols) resolve(string) ([]SymbolRange, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]SymbolRange(nil), f.symbols...), nil
+}
+
+func (f *fakeSymbols) set(symbols ...SymbolRange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.symbols = symbols
+}
+
+func TestLockService_PromotesRepeatedRegionLocksToSymbolLock(t *testing.T) {
+	ctx := context.Background()
+	service := NewLockService(ctx, "node-1", "Node 1")
+	defer service.Close()
+
+	symbols := &fakeSymbols{}
+	symbols.set(
+		SymbolRange{Type: TargetClass, Name: "Server", StartLine: 5, EndLine: 80},
+		SymbolRange{Type: TargetFunction, Name: "Login", StartLine: 10, EndLine: 30},
+	)
+	service.SetSymbolPromotion(2, symbols.resolve)
+
+	region := func(start, end int) *AcquireLockRequest {
+		return &AcquireLockRequest{TargetType: TargetFile, FilePath: "auth.go", StartLine: start, EndLine: end, Intention: "fix login"}
+	}
+
+	first, err := service.AcquireLock(ctx, region(12, 15))
+	if err != nil || !first.Success {
+		t.Fatalf("first acquire failed: %v %+v", err, first)
+	}
+	if first.Promoted || first.Lock.Target.Type != TargetFile {
+		t.Fatalf("first region lock should not be promoted, got %+v", first.Lock.Target)
+	}
+	if err := service.ReleaseLock(ctx, first.Lock.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := service.AcquireLock(ctx, region(20, 22))
+	if err != nil || !second.Success {
+		t.Fatalf("second acquire failed: %v %+v", err, second)
+	}
+	target := second.Lock.Target
+	if !second.Promoted || target.Type != TargetFunction || target.Name != "Login" {
+		t.Fatalf("expected promotion to the innermost symbol, got %+v", target)
+	}
+	if target.StartLine != 10 || target.EndLine != 30 {
+		t.Errorf("expected the symbol's range 10-30, got %d-%d", target.StartLine, target.EndLine)
+	}
+
+	// Lines inserted above the function shift it down
+	symbols.set(
+		SymbolRange{Type: TargetClass, Name: "Server", StartLine: 5, EndLine: 84},
+		SymbolRange{Type: TargetFunction, Name: "Login", StartLine: 14, EndLine: 34},
+	)
+	moved := service.SyncSymbolLocks("auth.go")
+	if len(moved) != 1 || moved[0].ID != second.Lock.ID {
+		t.Fatalf("expected the symbol lock to move, got %v", moved)
+	}
+
+	current, err := service.GetLock(second.Lock.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Target.StartLine != 14 || current.Target.EndLine != 34 {
+		t.Errorf("expected the lock to track the shift to 14-34, got %d-%d", current.Target.StartLine, current.Target.EndLine)
+	}
+
+	if again := service.SyncSymbolLocks("auth.go"); len(again) != 0 {
+		t.Errorf("an unchanged symbol should not move again, got %v", again)
+	}
+}
+
+func TestLockService_RemoteSymbolLockMovesOnlyThroughRetarget(t *testing.T) {
+	ctx := context.Background()
+	service := NewLockService(ctx, "node-1", "Node 1")
+	defer service.Close()
+
+	login, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFunction, FilePath: "auth.go", Name: "Login", StartLine: 10, EndLine: 30}, "node-2", "Node 2", "fix login")
+	if err := service.HandleRemoteLockAcquired(login); err != nil {
+		t.Fatal(err)
+	}
+	logout, _ := NewSemanticLockSafe(&SemanticTarget{Type: TargetFunction, FilePath: "auth.go", Name: "Logout", StartLine: 50, EndLine: 60}, "node-3", "Node 3", "fix logout")
+	if err := service.HandleRemoteLockAcquired(logout); err != nil {
+		t.Fatal(err)
+	}
+
+	announced := func(holderID string, start, end int) *SemanticLock {
+		moved := *login
+		target := *login.Target
+		target.StartLine, target.EndLine = start, end
+		moved.Target = &target
+		moved.HolderID = holderID
+		return &moved
+	}
+
+	// A plain Add never moves an existing lock
+	if err := service.store.Add(announced("node-2", 14, 34)); !errors.Is(err, ErrLockConflict) {
+		t.Fatalf("expected Add to refuse moving the lock, got %v", err)
+	}
+
+	// The holder's re-announcement after an edit moves it
+	if err := service.HandleRemoteLockAcquired(announced("node-2", 14, 34)); err != nil {
+		t.Fatalf("expected the holder to move its lock, got %v", err)
+	}
+
+	// Another node cannot move it, nor can it be moved onto a held range
+	if err := service.HandleRemoteLockAcquired(announced("node-3", 70, 90)); !errors.Is(err, ErrNotLockHolder) {
+		t.Errorf("expected ErrNotLockHolder, got %v", err)
+	}
+	if err := service.HandleRemoteLockAcquired(announced("node-2", 45, 55)); !errors.Is(err, ErrLockConflict) {
+		t.Errorf("expected a move onto Logout to conflict, got %v", err)
+	}
+
+	current, err := service.GetLock(login.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.HolderID != "node-2" || current.Target.StartLine != 14 || current.Target.EndLine != 34 {
+		t.Errorf("expected node-2's lock at 14-34, got %s at %d-%d", current.HolderID, current.Target.StartLine, current.Target.EndLine)
+	}
+}
@@ -0,0 +1,196 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// WaitOrder orders the callers on this node waiting for overlapping
+// regions.
+type WaitOrder string
+
+const (
+	// WaitFIFO serves waiters in arrival order. This is the default.
+	WaitFIFO WaitOrder = "fifo"
+	// WaitPriority serves higher priority waiters first and equal
+	// priorities in arrival order.
+	WaitPriority WaitOrder = "priority"
+)
+
+// ParseWaitOrder parses a wait order name; empty selects WaitFIFO.
+func ParseWaitOrder(s string) (WaitOrder, error) {
+	switch WaitOrder(s) {
+	case "", WaitFIFO:
+		return WaitFIFO, nil
+	case WaitPriority:
+		return WaitPriority, nil
+	default:
+		return "", fmt.Errorf("unknown wait order %q", s)
+	}
+}
+
+// lockWaiter is a caller waiting for a region to come free.
+type lockWaiter struct {
+	target   *SemanticTarget
+	priority int
+}
+
+// waitQueue keeps the callers waiting for locked regions, per file. A
+// waiter may try to take its region once no waiter ahead of it wants an
+// overlapping one, so waiters for unrelated regions do not hold each
+// other up.
+type waitQueue struct {
+	mu      sync.Mutex
+	order   WaitOrder
+	byFile  map[string][]*lockWaiter
+	changed chan struct{} // closed and replaced when a waiter leaves
+}
+
+func newWaitQueue() *waitQueue {
+	return &waitQueue{
+		order:   WaitFIFO,
+		byFile:  make(map[string][]*lockWaiter),
+		changed: make(chan struct{}),
+	}
+}
+
+// enqueue adds a waiter behind everyone it should not overtake.
+func (q *waitQueue) enqueue(target *SemanticTarget, priority int) *lockWaiter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	w := &lockWaiter{target: target, priority: priority}
+	waiters := q.byFile[target.FilePath]
+	i := len(waiters)
+	if q.order == WaitPriority {
+		for i > 0 && waiters[i-1].priority < priority {
+			i--
+		}
+	}
+	q.byFile[target.FilePath] = slices.Insert(waiters, i, w)
+	return w
+}
+
+// leave removes a waiter and wakes those behind it.
+func (q *waitQueue) leave(w *lockWaiter) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	waiters := q.byFile[w.target.FilePath]
+	if i := slices.Index(waiters, w); i >= 0 {
+		waiters = slices.Delete(waiters, i, i+1)
+	}
+	if len(waiters) == 0 {
+		delete(q.byFile, w.target.FilePath)
+	} else {
+		q.byFile[w.target.FilePath] = waiters
+	}
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// turn reports whether w may try to take its region now, and returns a
+// channel closed when that may have changed.
+func (q *waitQueue) turn(w *lockWaiter) (bool, <-chan struct{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, other := range q.byFile[w.target.FilePath] {
+		if other == w {
+			return true, q.changed
+		}
+		if other.target.Overlaps(w.target) {
+			return false, q.changed
+		}
+	}
+	return true, q.changed
+}
+
+// waiting returns how many callers wait for regions of filePath.
+func (q *waitQueue) waiting(filePath string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.byFile[filePath])
+}
+
+// SetWaitOrder sets how AcquireLockWait orders callers waiting for
+// overlapping regions.
+func (s *LockService) SetWaitOrder(order WaitOrder) {
+	s.queue.mu.Lock()
+	defer s.queue.mu.Unlock()
+	s.queue.order = order
+}
+
+// WaitingFor returns how many callers on this node wait for regions of
+// filePath.
+func (s *LockService) WaitingFor(filePath string) int {
+	return s.queue.waiting(filePath)
+}
+
+// AcquireLockWait is AcquireLock for callers that would rather wait than
+// fail: while the region is locked it queues behind the other waiters on
+// this node and takes the lock as soon as the holder lets go or the lock
+// expires. Waiters never negotiate or preempt. It gives up when ctx is
+// done.
+func (s *LockService) AcquireLockWait(ctx context.Context, req *AcquireLockRequest) (*LockResult, error) {
+	target, err := NewSemanticTarget(req.TargetType, req.FilePath, req.Name, req.StartLine, req.EndLine)
+	if err != nil {
+		return &LockResult{
+			Success: false,
+			Reason:  err.Error(),
+		}, err
+	}
+	mode, err := ParseAccessMode(string(req.Mode))
+	if err != nil {
+		return &LockResult{
+			Success: false,
+			Reason:  err.Error(),
+		}, err
+	}
+
+	waiter := s.queue.enqueue(target, req.Priority)
+	defer s.queue.leave(waiter)
+
+	for {
+		// Take the signals first so a release in between is not missed
+		released := s.store.releasedSignal()
+		myTurn, queueChanged := s.queue.turn(waiter)
+
+		var expiry <-chan time.Time
+		if myTurn {
+			conflicts := s.store.FindConflictsFor(target, mode)
+			if len(conflicts) == 0 {
+				result, err := s.AcquireLock(ctx, req)
+				if !errors.Is(err, ErrLockConflict) {
+					return result, err
+				}
+				continue
+			}
+
+			// Expired locks stop conflicting before the store drops them
+			next := conflicts[0].ExpiresAt
+			for _, held := range conflicts[1:] {
+				if held.ExpiresAt.Before(next) {
+					next = held.ExpiresAt
+				}
+			}
+			expiry = time.After(time.Until(next))
+		}
+
+		select {
+		case <-ctx.Done():
+			err := fmt.Errorf("%w: gave up waiting for %s: %w", ErrLockConflict, target, ctx.Err())
+			return &LockResult{
+				Success: false,
+				Reason:  err.Error(),
+			}, err
+		case <-released:
+		case <-queueChanged:
+		case <-expiry:
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// holdRemoteLock makes node-2 hold /test/queue.go:1-20 as seen by svc.
+func holdRemoteLock(t *testing.T, svc *LockService, ttl time.Duration) *SemanticLock {
+	t.Helper()
+	target, err := NewSemanticTarget(TargetFile, "/test/queue.go", "", 1, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	held := NewSemanticLock(target, "node-2", "Peer", "refactor")
+	held.ExpiresAt = time.Now().Add(ttl)
+	if err := svc.HandleRemoteLockAcquired(held); err != nil {
+		t.Fatal(err)
+	}
+	return held
+}
+
+func queueRequest(priority int) *AcquireLockRequest {
+	return &AcquireLockRequest{
+		TargetType: TargetFile,
+		FilePath:   "/test/queue.go",
+		StartLine:  5,
+		EndLine:    10,
+		Intention:  "fix bug",
+		Priority:   priority,
+	}
+}
+
+// waitForWaiters polls until n callers wait for /test/queue.go.
+func waitForWaiters(t *testing.T, svc *LockService, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for svc.WaitingFor("/test/queue.go") != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d waiters, got %d", n, svc.WaitingFor("/test/queue.go"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLockService_AcquireLockWaitGetsReleasedLock(t *testing.T) {
+	svc := NewLockService(context.Background(), "node-1", "Agent")
+	defer svc.Close()
+	held := holdRemoteLock(t, svc, time.Minute)
+
+	done := make(chan *LockResult, 1)
+	go func() {
+		result, err := svc.AcquireLockWait(context.Background(), queueRequest(0))
+		if err != nil {
+			t.Errorf("AcquireLockWait failed: %v", err)
+		}
+		done <- result
+	}()
+	waitForWaiters(t, svc, 1)
+
+	select {
+	case <-done:
+		t.Fatal("expected to wait while the region is locked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := svc.HandleRemoteLockReleased(held.ID); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case result := <-done:
+		if result == nil || !result.Success {
+			t.Fatalf("expected the lock once released, got %+v", result)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("waiter was not woken by the release")
+	}
+	if n := svc.WaitingFor("/test/queue.go"); n != 0 {
+		t.Errorf("expected the queue to be empty, got %d", n)
+	}
+}
+
+func TestLockService_AcquireLockWaitGetsExpiredLock(t *testing.T) {
+	svc := NewLockService(context.Background(), "node-1", "Agent")
+	defer svc.Close()
+	holdRemoteLock(t, svc, 100*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	result, err := svc.AcquireLockWait(ctx, queueRequest(0))
+	if err != nil || !result.Success {
+		t.Fatalf("expected the lock once the holder's lease ran out, got %v", err)
+	}
+}
+
+func TestLockService_AcquireLockWaitGivesUp(t *testing.T) {
+	svc := NewLockService(context.Background(), "node-1", "Agent")
+	defer svc.Close()
+	holdRemoteLock(t, svc, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := svc.AcquireLockWait(ctx, queueRequest(0))
+	if !errors.Is(err, ErrLockConflict) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a conflict once the wait timed out, got %v", err)
+	}
+	if n := svc.WaitingFor("/test/queue.go"); n != 0 {
+		t.Errorf("expected the waiter to leave the queue, got %d", n)
+	}
+}
+
+func TestLockService_AcquireLockWaitPriorityOrder(t *testing.T) {
+	svc := NewLockService(context.Background(), "node-1", "Agent")
+	defer svc.Close()
+	svc.SetWaitOrder(WaitPriority)
+	held := holdRemoteLock(t, svc, time.Minute)
+
+	granted := make(chan int, 2)
+	wait := func(priority int) {
+		result, err := svc.AcquireLockWait(context.Background(), queueRequest(priority))
+		if err != nil {
+			t.Errorf("AcquireLockWait failed: %v", err)
+			return
+		}
+		granted <- priority
+		time.Sleep(20 * time.Millisecond)
+		svc.ReleaseLock(context.Background(), result.Lock.ID)
+	}
+	go wait(1)
+	waitForWaiters(t, svc, 1)
+	go wait(5)
+	waitForWaiters(t, svc, 2)
+
+	if err := svc.HandleRemoteLockReleased(held.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []int{5, 1} {
+		select {
+		case got := <-granted:
+			if got != want {
+				t.Fatalf("expected priority %d to be served next, got %d", want, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("priority %d was never served", want)
+		}
+	}
+}
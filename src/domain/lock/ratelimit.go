@@ -5,13 +5,33 @@ import (
 	"time"
 )
 
+// Adaptive rate limiting steps: a conflict halves a peer's scale, and
+// each request that runs into no conflict raises it by 2%.
+const (
+	adaptiveTighten = 0.5
+	adaptiveLoosen  = 1.02
+)
+
 // RateLimiter implements a per-peer rate limiter using token bucket algorithm.
 type RateLimiter struct {
 	mu      sync.RWMutex
 	buckets map[string]*tokenBucket
-	rate    float64       // tokens per second
-	burst   int           // maximum tokens
-	cleanup time.Duration // cleanup interval for idle buckets
+	holders map[string]*holderRate // kept across bucket cleanup
+	rate    float64                // tokens per second
+	burst   int                    // maximum tokens
+	cleanup time.Duration          // cleanup interval for idle buckets
+
+	adaptive    bool
+	adaptiveMin float64
+	adaptiveMax float64
+}
+
+// holderRate tracks a peer's requests and its adaptive scale.
+type holderRate struct {
+	allowed   int64
+	denied    int64
+	conflicts int64
+	scale     float64
 }
 
 // tokenBucket represents a token bucket for a single peer.
@@ -25,6 +45,13 @@ type RateLimitConfig struct {
 	Rate            float64       // requests per second per peer
 	Burst           int           // maximum burst size
 	CleanupInterval time.Duration // interval to clean up idle buckets
+
+	// Adaptive scales each peer's rate and burst by its conflict record:
+	// conflicts tighten the limit down to AdaptiveMin times the base,
+	// requests without conflicts loosen it up to AdaptiveMax times.
+	Adaptive    bool
+	AdaptiveMin float64
+	AdaptiveMax float64
 }
 
 // DefaultRateLimitConfig returns default rate limit configuration.
@@ -33,9 +60,30 @@ func DefaultRateLimitConfig() *RateLimitConfig {
 		Rate:            10.0,            // 10 requests per second
 		Burst:           20,              // burst of 20
 		CleanupInterval: 5 * time.Minute, // cleanup every 5 minutes
+		AdaptiveMin:     0.25,
+		AdaptiveMax:     2,
 	}
 }
 
+// adaptiveBounds returns the adaptive scale bounds, defaulting unset ones.
+func (c *RateLimitConfig) adaptiveBounds() (float64, float64) {
+	def := DefaultRateLimitConfig()
+	lo, hi := c.AdaptiveMin, c.AdaptiveMax
+	if lo <= 0 {
+		lo = def.AdaptiveMin
+	}
+	if hi <= 0 {
+		hi = def.AdaptiveMax
+	}
+	if lo > 1 {
+		lo = 1
+	}
+	if hi < 1 {
+		hi = 1
+	}
+	return lo, hi
+}
+
 // NewRateLimiter creates a new rate limiter.
 func NewRateLimiter(config *RateLimitConfig) *RateLimiter {
 	if config == nil {
@@ -43,15 +91,82 @@ func NewRateLimiter(config *RateLimitConfig) *RateLimiter {
 	}
 
 	rl := &RateLimiter{
-		buckets: make(map[string]*tokenBucket),
-		rate:    config.Rate,
-		burst:   config.Burst,
-		cleanup: config.CleanupInterval,
+		buckets:  make(map[string]*tokenBucket),
+		holders:  make(map[string]*holderRate),
+		rate:     config.Rate,
+		burst:    config.Burst,
+		cleanup:  config.CleanupInterval,
+		adaptive: config.Adaptive,
 	}
+	rl.adaptiveMin, rl.adaptiveMax = config.adaptiveBounds()
 
 	return rl
 }
 
+// holder returns the request record of a peer (must be called with lock held).
+func (rl *RateLimiter) holder(peerID string) *holderRate {
+	h, exists := rl.holders[peerID]
+	if !exists {
+		h = &holderRate{scale: 1}
+		rl.holders[peerID] = h
+	}
+	return h
+}
+
+// limits returns the rate and burst that apply to a peer (must be called
+// with lock held).
+func (rl *RateLimiter) limits(peerID string) (float64, int) {
+	if !rl.adaptive {
+		return rl.rate, rl.burst
+	}
+	scale := 1.0
+	if h, exists := rl.holders[peerID]; exists {
+		scale = h.scale
+	}
+	burst := int(float64(rl.burst)*scale + 0.5)
+	if burst < 1 {
+		burst = 1
+	}
+	return rl.rate * scale, burst
+}
+
+// count records whether a peer's request was allowed and returns allowed
+// (must be called with lock held).
+func (rl *RateLimiter) count(peerID string, allowed bool) bool {
+	h := rl.holder(peerID)
+	if allowed {
+		h.allowed++
+	} else {
+		h.denied++
+	}
+	return allowed
+}
+
+// RecordOutcome feeds the result of an allowed request back into the
+// adaptive limit: a conflict tightens the peer's limit, a clean request
+// loosens it. It only counts conflicts when adaptive mode is off.
+func (rl *RateLimiter) RecordOutcome(peerID string, conflicted bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	h := rl.holder(peerID)
+	if conflicted {
+		h.conflicts++
+	}
+	if !rl.adaptive {
+		return
+	}
+	if conflicted {
+		h.scale = max(h.scale*adaptiveTighten, rl.adaptiveMin)
+	} else {
+		h.scale = min(h.scale*adaptiveLoosen, rl.adaptiveMax)
+	}
+	if bucket, exists := rl.buckets[peerID]; exists {
+		_, burst := rl.limits(peerID)
+		bucket.tokens = min(bucket.tokens, float64(burst))
+	}
+}
+
 // Allow checks if a request from the given peer is allowed.
 // Returns true if allowed, false if rate limited.
 func (rl *RateLimiter) Allow(peerID string) bool {
@@ -59,32 +174,33 @@ func (rl *RateLimiter) Allow(peerID string) bool {
 	defer rl.mu.Unlock()
 
 	now := time.Now()
+	rate, burst := rl.limits(peerID)
 	bucket, exists := rl.buckets[peerID]
 
 	if !exists {
 		// New peer, create bucket with full tokens
 		rl.buckets[peerID] = &tokenBucket{
-			tokens:     float64(rl.burst) - 1, // consume one token
+			tokens:     float64(burst) - 1, // consume one token
 			lastUpdate: now,
 		}
-		return true
+		return rl.count(peerID, true)
 	}
 
 	// Refill tokens based on elapsed time
 	elapsed := now.Sub(bucket.lastUpdate).Seconds()
-	bucket.tokens += elapsed * rl.rate
-	if bucket.tokens > float64(rl.burst) {
-		bucket.tokens = float64(rl.burst)
+	bucket.tokens += elapsed * rate
+	if bucket.tokens > float64(burst) {
+		bucket.tokens = float64(burst)
 	}
 	bucket.lastUpdate = now
 
 	// Try to consume a token
 	if bucket.tokens >= 1 {
 		bucket.tokens--
-		return true
+		return rl.count(peerID, true)
 	}
 
-	return false
+	return rl.count(peerID, false)
 }
 
 // AllowN checks if n requests from the given peer are allowed.
@@ -93,34 +209,83 @@ func (rl *RateLimiter) AllowN(peerID string, n int) bool {
 	defer rl.mu.Unlock()
 
 	now := time.Now()
+	rate, burst := rl.limits(peerID)
 	bucket, exists := rl.buckets[peerID]
 
 	if !exists {
-		if n > rl.burst {
-			return false
+		if n > burst {
+			return rl.count(peerID, false)
 		}
 		rl.buckets[peerID] = &tokenBucket{
-			tokens:     float64(rl.burst - n),
+			tokens:     float64(burst - n),
 			lastUpdate: now,
 		}
-		return true
+		return rl.count(peerID, true)
 	}
 
 	// Refill tokens
 	elapsed := now.Sub(bucket.lastUpdate).Seconds()
-	bucket.tokens += elapsed * rl.rate
-	if bucket.tokens > float64(rl.burst) {
-		bucket.tokens = float64(rl.burst)
+	bucket.tokens += elapsed * rate
+	if bucket.tokens > float64(burst) {
+		bucket.tokens = float64(burst)
 	}
 	bucket.lastUpdate = now
 
 	// Try to consume tokens
 	if bucket.tokens >= float64(n) {
 		bucket.tokens -= float64(n)
-		return true
+		return rl.count(peerID, true)
+	}
+
+	return rl.count(peerID, false)
+}
+
+// RetryAfter returns how long the given peer must wait until its next token
+// is available. It returns 0 if a request would be allowed now.
+func (rl *RateLimiter) RetryAfter(peerID string) time.Duration {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+
+	rate, _ := rl.limits(peerID)
+	bucket, exists := rl.buckets[peerID]
+	if !exists || rate <= 0 {
+		return 0
+	}
+
+	tokens := bucket.tokens + time.Since(bucket.lastUpdate).Seconds()*rate
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / rate * float64(time.Second))
+}
+
+// SetConfig changes the rate, burst and adaptive mode in place. Existing
+// buckets keep their tokens, capped at the new burst, and peers keep their
+// adaptive scale, clamped to the new bounds.
+func (rl *RateLimiter) SetConfig(config *RateLimitConfig) {
+	if config == nil {
+		config = DefaultRateLimitConfig()
 	}
 
-	return false
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.rate = config.Rate
+	rl.burst = config.Burst
+	if config.CleanupInterval > 0 {
+		rl.cleanup = config.CleanupInterval
+	}
+	rl.adaptive = config.Adaptive
+	rl.adaptiveMin, rl.adaptiveMax = config.adaptiveBounds()
+	for _, h := range rl.holders {
+		h.scale = min(max(h.scale, rl.adaptiveMin), rl.adaptiveMax)
+	}
+	for peerID, bucket := range rl.buckets {
+		_, burst := rl.limits(peerID)
+		if bucket.tokens > float64(burst) {
+			bucket.tokens = float64(burst)
+		}
+	}
 }
 
 // Reset resets the rate limit for a peer.
@@ -148,11 +313,26 @@ func (rl *RateLimiter) Stats() RateLimiterStats {
 	rl.mu.RLock()
 	defer rl.mu.RUnlock()
 
-	return RateLimiterStats{
+	stats := RateLimiterStats{
 		ActivePeers: len(rl.buckets),
 		Rate:        rl.rate,
 		Burst:       rl.burst,
+		Adaptive:    rl.adaptive,
 	}
+	if len(rl.holders) > 0 {
+		stats.Holders = make(map[string]HolderRateStats, len(rl.holders))
+		for peerID, h := range rl.holders {
+			rate, burst := rl.limits(peerID)
+			stats.Holders[peerID] = HolderRateStats{
+				Allowed:   h.allowed,
+				Denied:    h.denied,
+				Conflicts: h.conflicts,
+				Rate:      rate,
+				Burst:     burst,
+			}
+		}
+	}
+	return stats
 }
 
 // RateLimiterStats holds rate limiter statistics.
@@ -160,4 +340,18 @@ type RateLimiterStats struct {
 	ActivePeers int     `json:"active_peers"`
 	Rate        float64 `json:"rate"`
 	Burst       int     `json:"burst"`
+	Adaptive    bool    `json:"adaptive"`
+	// Holders holds per-peer counts since startup, keyed by holder ID.
+	Holders map[string]HolderRateStats `json:"holders,omitempty"`
+}
+
+// HolderRateStats holds the rate limit statistics of one lock holder.
+type HolderRateStats struct {
+	Allowed   int64 `json:"allowed"`
+	Denied    int64 `json:"denied"`
+	Conflicts int64 `json:"conflicts"`
+	// Rate and Burst are the limits currently applied to the holder,
+	// which differ from the base ones in adaptive mode.
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
 }
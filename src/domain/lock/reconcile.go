@@ -0,0 +1,106 @@
+package lock
+
+import (
+	"time"
+)
+
+// ReconcileResult summarizes one anti-entropy pass over a peer's locks.
+type ReconcileResult struct {
+	PeerID string `json:"peer_id"`
+	// Adopted are locks this node missed while partitioned.
+	Adopted []*SemanticLock `json:"adopted,omitempty"`
+	// Sessions are the conflicts settled by fencing-token priority.
+	Sessions []*NegotiationSession `json:"sessions,omitempty"`
+	// Lost are locks held by this node that lost a conflict and were released.
+	Lost []*SemanticLock `json:"lost,omitempty"`
+}
+
+// Changed reports whether the pass changed anything.
+func (r *ReconcileResult) Changed() bool {
+	return len(r.Adopted) > 0 || len(r.Sessions) > 0
+}
+
+// RequestLockState starts anti-entropy after a partition heals: it
+// broadcasts the locks this node holds and asks peers for theirs.
+func (s *LockService) RequestLockState(since time.Time) error {
+	if s.negotiator.broadcastFn == nil {
+		return nil
+	}
+	return s.negotiator.broadcastFn(&LockStateRequest{
+		Type:        "lock_state_request",
+		RequestorID: s.nodeID,
+		Since:       since,
+		Locks:       s.store.ListByHolder(s.nodeID),
+		Timestamp:   time.Now(),
+	})
+}
+
+// HandleLockStateRequest reconciles the requestor's locks and answers with
+// the locks this node holds. Nodes that never noticed the partition take
+// part this way.
+func (s *LockService) HandleLockStateRequest(req *LockStateRequest) (*ReconcileResult, error) {
+	result := s.ReconcileLocks(req.RequestorID, req.Locks)
+
+	if s.negotiator.broadcastFn == nil {
+		return result, nil
+	}
+	err := s.negotiator.broadcastFn(&LockStateResponse{
+		Type:        "lock_state_response",
+		ResponderID: s.nodeID,
+		Locks:       s.store.ListByHolder(s.nodeID),
+		Timestamp:   time.Now(),
+	})
+	return result, err
+}
+
+// HandleLockStateResponse reconciles the locks a peer reported.
+func (s *LockService) HandleLockStateResponse(resp *LockStateResponse) *ReconcileResult {
+	return s.ReconcileLocks(resp.ResponderID, resp.Locks)
+}
+
+// ReconcileLocks merges locks reported by a peer into the local store.
+// Locks that overlap nothing are adopted; overlapping locks, which can only
+// have been acquired on opposite sides of a partition, are routed into
+// negotiation sessions and resolved by fencing-token priority, so every node
+// converges on the same holder.
+func (s *LockService) ReconcileLocks(peerID string, locks []*SemanticLock) *ReconcileResult {
+	result := &ReconcileResult{PeerID: peerID}
+
+	for _, remote := range locks {
+		// This node is authoritative for its own locks
+		if remote == nil || remote.Target == nil || remote.IsExpired() || remote.HolderID == s.nodeID {
+			continue
+		}
+		if _, err := s.store.Get(remote.ID); err == nil {
+			continue
+		}
+
+		var conflicts []*SemanticLock
+		for _, local := range s.store.FindConflictsFor(remote.Target, remote.Mode) {
+			if local.ID != remote.ID {
+				conflicts = append(conflicts, local)
+			}
+		}
+
+		if len(conflicts) == 0 {
+			if err := s.store.Add(remote); err == nil {
+				result.Adopted = append(result.Adopted, remote)
+			}
+			continue
+		}
+
+		sessions, adopted := s.negotiator.ReconcilePartitionConflicts(remote, conflicts, s.nodeID)
+		result.Sessions = append(result.Sessions, sessions...)
+		if !adopted {
+			continue
+		}
+		for _, local := range conflicts {
+			if local.HolderID == s.nodeID {
+				s.clearIdleWarning(local.ID)
+				result.Lost = append(result.Lost, local)
+			}
+		}
+	}
+
+	return result
+}
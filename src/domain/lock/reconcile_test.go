@@ -0,0 +1,125 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// captureLockState returns a broadcast function that keeps the last lock
+// state request sent.
+func captureLockState(req **LockStateRequest) func(msg any) error {
+	return func(msg any) error {
+		if r, ok := msg.(*LockStateRequest); ok {
+			*req = r
+		}
+		return nil
+	}
+}
+
+func TestLockService_ReconcileAfterPartition(t *testing.T) {
+	ctx := context.Background()
+
+	alpha := NewLockService(ctx, "node-a", "Alpha")
+	defer alpha.Close()
+	beta := NewLockService(ctx, "node-b", "Beta")
+	defer beta.Close()
+
+	var alphaReq, betaReq *LockStateRequest
+	var alphaReleases []ReleaseMessage
+	alpha.SetBroadcastFn(func(msg any) error {
+		if m, ok := msg.(ReleaseMessage); ok {
+			alphaReleases = append(alphaReleases, m)
+		}
+		return captureLockState(&alphaReq)(msg)
+	})
+	beta.SetBroadcastFn(captureLockState(&betaReq))
+
+	// While partitioned both sides lock the same region
+	req := &AcquireLockRequest{TargetType: TargetFile, FilePath: "/src/api.go", StartLine: 1, EndLine: 20, Intention: "edit"}
+	first, err := alpha.AcquireLock(ctx, req)
+	if err != nil || !first.Success {
+		t.Fatalf("alpha acquire failed: %v", err)
+	}
+	second, err := beta.AcquireLock(ctx, req)
+	if err != nil || !second.Success {
+		t.Fatalf("beta acquire failed: %v", err)
+	}
+	if second.Lock.FencingToken <= first.Lock.FencingToken {
+		t.Fatalf("expected beta to hold the higher fencing token")
+	}
+
+	// On heal both sides exchange their locks
+	since := time.Now().Add(-time.Minute)
+	if err := alpha.RequestLockState(since); err != nil {
+		t.Fatal(err)
+	}
+	if err := beta.RequestLockState(since); err != nil {
+		t.Fatal(err)
+	}
+
+	alphaResult, _ := alpha.HandleLockStateRequest(betaReq)
+	betaResult, _ := beta.HandleLockStateRequest(alphaReq)
+
+	// Both converge on beta's lock, which has the higher fencing token
+	for name, svc := range map[string]*LockService{"alpha": alpha, "beta": beta} {
+		locks := svc.ListLocks()
+		if len(locks) != 1 || locks[0].ID != second.Lock.ID {
+			t.Errorf("%s: expected only beta's lock, got %+v", name, locks)
+		}
+	}
+
+	if len(alphaResult.Sessions) != 1 {
+		t.Fatalf("expected one negotiation session on alpha, got %d", len(alphaResult.Sessions))
+	}
+	session := alphaResult.Sessions[0]
+	if session.Resolution.ResolutionType != ResolutionNegotiated || session.Resolution.WinnerLock.ID != second.Lock.ID {
+		t.Errorf("expected beta to win by fencing token, got %+v", session.Resolution)
+	}
+	if _, err := alpha.GetNegotiationSession(session.ID); err != nil {
+		t.Errorf("reconciliation session should be recorded: %v", err)
+	}
+	if len(alphaResult.Lost) != 1 || alphaResult.Lost[0].ID != first.Lock.ID {
+		t.Errorf("alpha should report its lost lock, got %+v", alphaResult.Lost)
+	}
+	if len(betaResult.Lost) != 0 {
+		t.Errorf("beta should keep its lock, got %+v", betaResult.Lost)
+	}
+
+	if len(alphaReleases) != 1 || alphaReleases[0].LockID != first.Lock.ID {
+		t.Errorf("alpha should announce the release of its lost lock, got %+v", alphaReleases)
+	}
+	// Newest first: beta's lock adopted after alpha's was removed
+	history := alpha.GetHistory(2)
+	if len(history) != 2 || history[0].Action != "acquired" || history[0].LockID != second.Lock.ID {
+		t.Fatalf("expected adoption of beta's lock last, got %+v", history)
+	}
+	if history[1].Action != "partition_lost" || history[1].LockID != first.Lock.ID {
+		t.Errorf("expected partition_lost history entry, got %+v", history[1])
+	}
+}
+
+func TestLockService_ReconcileAdoptsMissedLocks(t *testing.T) {
+	ctx := context.Background()
+	svc := NewLockService(ctx, "node-a", "Alpha")
+	defer svc.Close()
+
+	target, err := NewSemanticTarget(TargetFile, "/src/other.go", "", 1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := NewSemanticLock(target, "node-b", "Beta", "edit")
+	result := svc.ReconcileLocks("node-b", []*SemanticLock{remote})
+
+	if len(result.Adopted) != 1 || len(result.Sessions) != 0 {
+		t.Fatalf("expected missed lock to be adopted, got %+v", result)
+	}
+	if _, err := svc.GetLock(remote.ID); err != nil {
+		t.Errorf("adopted lock should be stored: %v", err)
+	}
+
+	// A second pass with the same state changes nothing
+	if again := svc.ReconcileLocks("node-b", []*SemanticLock{remote}); again.Changed() {
+		t.Errorf("reconciliation should be idempotent, got %+v", again)
+	}
+}
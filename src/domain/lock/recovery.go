@@ -85,6 +85,7 @@ func (rm *RecoveryManager) StartRecovery(ctx context.Context, partitionStart tim
 	// Request lock state from all peers
 	if rm.broadcastFn != nil {
 		msg := &LockStateRequest{
+			Type:        "lock_state_request",
 			RequestorID: rm.nodeID,
 			Since:       partitionStart,
 			Timestamp:   time.Now(),
@@ -164,7 +165,7 @@ func (rm *RecoveryManager) FinishRecovery(ctx context.Context) (*RecoveryResult,
 		}
 
 		// Remote lock doesn't exist locally - check for overlaps
-		localConflicts := rm.store.FindConflicts(remoteLock.Target)
+		localConflicts := rm.store.FindConflictsFor(remoteLock.Target, remoteLock.Mode)
 		if len(localConflicts) > 0 {
 			for _, localLock := range localConflicts {
 				conflict := rm.resolveOverlapConflict(localLock, remoteLock)
@@ -287,14 +288,19 @@ func (rm *RecoveryManager) IsRecovering() bool {
 }
 
 // LockStateRequest is sent to request lock state from peers.
+// Locks carries the requestor's own locks so that peers which never noticed
+// the partition can reconcile against them too.
 type LockStateRequest struct {
-	RequestorID string    `json:"requestor_id"`
-	Since       time.Time `json:"since"`
-	Timestamp   time.Time `json:"timestamp"`
+	Type        string          `json:"type"`
+	RequestorID string          `json:"requestor_id"`
+	Since       time.Time       `json:"since"`
+	Locks       []*SemanticLock `json:"locks,omitempty"`
+	Timestamp   time.Time       `json:"timestamp"`
 }
 
 // LockStateResponse is the response containing lock state.
 type LockStateResponse struct {
+	Type        string          `json:"type"`
 	ResponderID string          `json:"responder_id"`
 	Locks       []*SemanticLock `json:"locks"`
 	Timestamp   time.Time       `json:"timestamp"`
@@ -318,6 +324,7 @@ func (rm *RecoveryManager) PrepareStateResponse(req *LockStateRequest) *LockStat
 	})
 
 	return &LockStateResponse{
+		Type:        "lock_state_response",
 		ResponderID: rm.nodeID,
 		Locks:       filtered,
 		Timestamp:   time.Now(),
@@ -0,0 +1,164 @@
+package lock
+
+import (
+	"context"
+	"time"
+)
+
+// RenewalConfig configures lease renewal reminders for the locks this node
+// holds. A reminder fires once per lease when the given fraction of the
+// lease has elapsed, so the holder can renew before the lock expires.
+type RenewalConfig struct {
+	// Threshold is the fraction of the lease that must have elapsed before
+	// the holder is reminded, e.g. 0.8 (0 = disabled).
+	Threshold float64 `json:"threshold"`
+	// CheckInterval is how often locks are checked.
+	CheckInterval time.Duration `json:"check_interval"`
+}
+
+// DefaultRenewalConfig returns the default renewal reminder configuration,
+// which is disabled until a threshold is set.
+func DefaultRenewalConfig() RenewalConfig {
+	return RenewalConfig{
+		CheckInterval: 5 * time.Second,
+	}
+}
+
+// Enabled reports whether renewal reminders are on.
+func (c RenewalConfig) Enabled() bool {
+	return c.Threshold > 0 && c.Threshold < 1
+}
+
+// withDefaults fills in unset fields.
+func (c RenewalConfig) withDefaults() RenewalConfig {
+	if c.CheckInterval <= 0 {
+		c.CheckInterval = DefaultRenewalConfig().CheckInterval
+	}
+	return c
+}
+
+// RenewalNotice reminds the holder that a lock's lease is about to expire.
+type RenewalNotice struct {
+	LockID     string        `json:"lock_id"`
+	HolderID   string        `json:"holder_id"`
+	HolderName string        `json:"holder_name"`
+	Target     string        `json:"target"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	Remaining  time.Duration `json:"remaining"`
+}
+
+// SetRenewalConfig enables, reconfigures or (with a zero threshold)
+// disables lease renewal reminders.
+func (s *LockService) SetRenewalConfig(cfg RenewalConfig) {
+	s.renewalMu.Lock()
+	defer s.renewalMu.Unlock()
+
+	if s.renewalCancel != nil {
+		s.renewalCancel()
+		s.renewalCancel = nil
+	}
+	s.renewalConfig = cfg.withDefaults()
+	s.reminded = make(map[string]time.Time)
+	if !s.renewalConfig.Enabled() {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.store.ctx)
+	s.renewalCancel = cancel
+	go s.watchRenewals(ctx, s.renewalConfig.CheckInterval)
+}
+
+// RenewalConfig returns the current renewal reminder configuration.
+func (s *LockService) RenewalConfig() RenewalConfig {
+	s.renewalMu.Lock()
+	defer s.renewalMu.Unlock()
+	return s.renewalConfig
+}
+
+// SetRenewalHandler sets the callback invoked when a lock held by this node
+// is due for renewal.
+func (s *LockService) SetRenewalHandler(handler func(*RenewalNotice)) {
+	s.renewalMu.Lock()
+	defer s.renewalMu.Unlock()
+	s.onRenewal = handler
+}
+
+func (s *LockService) stopRenewalWatcher() {
+	s.renewalMu.Lock()
+	defer s.renewalMu.Unlock()
+	if s.renewalCancel != nil {
+		s.renewalCancel()
+		s.renewalCancel = nil
+	}
+}
+
+func (s *LockService) watchRenewals(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.checkRenewals(now)
+		}
+	}
+}
+
+// checkRenewals reminds the holder of every lock whose lease has passed the
+// threshold. Each lease is reminded about once: a renewal moves ExpiresAt
+// and starts a new lease.
+func (s *LockService) checkRenewals(now time.Time) {
+	s.renewalMu.Lock()
+	cfg := s.renewalConfig
+	handler := s.onRenewal
+	s.renewalMu.Unlock()
+
+	if !cfg.Enabled() {
+		return
+	}
+
+	held := make(map[string]struct{})
+	for _, lock := range s.store.ListByHolder(s.nodeID) {
+		held[lock.ID] = struct{}{}
+
+		lease := lock.ExpiresAt.Sub(lock.LeaseStart())
+		if lease <= 0 || !now.Before(lock.ExpiresAt) {
+			continue
+		}
+		if now.Sub(lock.LeaseStart()) < time.Duration(float64(lease)*cfg.Threshold) {
+			continue
+		}
+
+		s.renewalMu.Lock()
+		remindedFor, reminded := s.reminded[lock.ID]
+		if !reminded || !remindedFor.Equal(lock.ExpiresAt) {
+			s.reminded[lock.ID] = lock.ExpiresAt
+		}
+		s.renewalMu.Unlock()
+		if reminded && remindedFor.Equal(lock.ExpiresAt) {
+			continue
+		}
+
+		if handler != nil {
+			handler(&RenewalNotice{
+				LockID:     lock.ID,
+				HolderID:   lock.HolderID,
+				HolderName: lock.HolderName,
+				Target:     lock.Target.String(),
+				ExpiresAt:  lock.ExpiresAt,
+				Remaining:  lock.ExpiresAt.Sub(now),
+			})
+		}
+	}
+
+	// Forget reminders for locks that are gone
+	s.renewalMu.Lock()
+	for id := range s.reminded {
+		if _, ok := held[id]; !ok {
+			delete(s.reminded, id)
+		}
+	}
+	s.renewalMu.Unlock()
+}
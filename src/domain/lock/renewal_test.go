@@ -0,0 +1,80 @@
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLockService_RenewalReminderFiresOnceAtThreshold(t *testing.T) {
+	svc := newLockTestService(t)
+	var notices []*RenewalNotice
+	svc.SetRenewalHandler(func(n *RenewalNotice) { notices = append(notices, n) })
+	svc.SetRenewalConfig(RenewalConfig{Threshold: 0.8, CheckInterval: time.Hour})
+	lock := acquireTestLock(t, svc, "/test/renew.go")
+	lease := lock.ExpiresAt.Sub(lock.AcquiredAt)
+	at := func(fraction float64) time.Time {
+		return lock.AcquiredAt.Add(time.Duration(float64(lease) * fraction))
+	}
+
+	// Before the threshold: nothing
+	svc.checkRenewals(at(0.5))
+	if len(notices) != 0 {
+		t.Fatalf("expected no reminder at 50%% of the lease, got %d", len(notices))
+	}
+
+	// At the threshold: one reminder for the holder
+	svc.checkRenewals(at(0.8))
+	if len(notices) != 1 {
+		t.Fatalf("expected one reminder at 80%% of the lease, got %d", len(notices))
+	}
+	n := notices[0]
+	if n.LockID != lock.ID || n.HolderID != "node-1" || !n.ExpiresAt.Equal(lock.ExpiresAt) {
+		t.Errorf("unexpected reminder: %+v", n)
+	}
+	if want := lock.ExpiresAt.Sub(at(0.8)); n.Remaining != want {
+		t.Errorf("expected %v remaining, got %v", want, n.Remaining)
+	}
+
+	// Not again before expiry
+	svc.checkRenewals(at(0.9))
+	svc.checkRenewals(at(0.99))
+	if len(notices) != 1 {
+		t.Errorf("expected a single reminder per lease, got %d", len(notices))
+	}
+}
+
+func TestLockService_RenewalStartsNewLease(t *testing.T) {
+	svc := newLockTestService(t)
+	var notices []*RenewalNotice
+	svc.SetRenewalHandler(func(n *RenewalNotice) { notices = append(notices, n) })
+	svc.SetRenewalConfig(RenewalConfig{Threshold: 0.8, CheckInterval: time.Hour})
+	lock := acquireTestLock(t, svc, "/test/renew.go")
+
+	svc.checkRenewals(lock.ExpiresAt.Add(-time.Second))
+	if len(notices) != 1 {
+		t.Fatalf("expected one reminder, got %d", len(notices))
+	}
+
+	if err := svc.RenewLock(context.Background(), lock.ID); err != nil {
+		t.Fatalf("RenewLock failed: %v", err)
+	}
+	svc.checkRenewals(time.Now())
+	if len(notices) != 1 {
+		t.Fatalf("fresh lease should not be reminded, got %d", len(notices))
+	}
+
+	svc.checkRenewals(lock.ExpiresAt.Add(-time.Second))
+	if len(notices) != 2 {
+		t.Errorf("renewed lease should be reminded again, got %d", len(notices))
+	}
+}
+
+func TestLockService_RenewalDisabledByDefault(t *testing.T) {
+	svc := NewLockService(context.Background(), "node-1", "Agent")
+	defer svc.Close()
+
+	if svc.RenewalConfig().Enabled() {
+		t.Error("renewal reminders should be disabled by default")
+	}
+}
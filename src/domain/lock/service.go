@@ -3,6 +3,8 @@ package lock
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,6 +14,39 @@ type LockService struct {
 	negotiator *LockNegotiator
 	nodeID     string
 	nodeName   string
+	observer   atomic.Bool
+
+	// Idle lock detection
+	idleMu     sync.Mutex
+	idleConfig IdleConfig
+	idleCancel context.CancelFunc
+	idleWarned map[string]time.Time // lockID -> warning time
+	onIdle     func(*IdleNotice)
+
+	// Lease renewal reminders
+	renewalMu     sync.Mutex
+	renewalConfig RenewalConfig
+	renewalCancel context.CancelFunc
+	reminded      map[string]time.Time // lockID -> ExpiresAt of the reminded lease
+	onRenewal     func(*RenewalNotice)
+
+	// Lock lifecycle callbacks
+	acquiredMu sync.Mutex
+	onAcquired func(*SemanticLock)
+
+	// Region to symbol lock promotion
+	promotion promotionState
+
+	// Longest a lock may be held through renewals (0 = no limit)
+	maxLease atomic.Int64
+
+	// Callers waiting for locked regions
+	queue *waitQueue
+
+	// Lock persistence across restarts
+	persistMu     sync.Mutex
+	persistCancel context.CancelFunc
+	persistDone   chan struct{}
 }
 
 // NewLockService creates a new lock service.
@@ -24,17 +59,55 @@ func NewLockService(ctx context.Context, nodeID, nodeName string) *LockService {
 		negotiator: negotiator,
 		nodeID:     nodeID,
 		nodeName:   nodeName,
+		idleWarned: make(map[string]time.Time),
+		reminded:   make(map[string]time.Time),
+		queue:      newWaitQueue(),
 	}
 }
 
 // Close stops background goroutines and releases resources.
 func (s *LockService) Close() error {
+	s.stopIdleWatcher()
+	s.stopRenewalWatcher()
+	s.stopPersister()
 	if err := s.negotiator.Close(); err != nil {
 		return err
 	}
 	return s.store.Close()
 }
 
+// SetObserver enables or disables observer mode.
+// Observer nodes never hold locks and are excluded from negotiation quorum.
+func (s *LockService) SetObserver(observer bool) {
+	s.observer.Store(observer)
+	if observer {
+		s.negotiator.AddObserver(s.nodeID)
+	} else {
+		s.negotiator.RemoveObserver(s.nodeID)
+	}
+}
+
+// IsObserver returns whether this node runs in observer mode.
+func (s *LockService) IsObserver() bool {
+	return s.observer.Load()
+}
+
+// RegisterObserver marks a remote node as an observer so its votes are ignored.
+func (s *LockService) RegisterObserver(nodeID string) {
+	s.negotiator.AddObserver(nodeID)
+}
+
+// UnregisterObserver forgets that a remote node is an observer, e.g. once
+// it leaves the cluster.
+func (s *LockService) UnregisterObserver(nodeID string) {
+	s.negotiator.RemoveObserver(nodeID)
+}
+
+// IsRegisteredObserver reports whether a node is known to be an observer.
+func (s *LockService) IsRegisteredObserver(nodeID string) bool {
+	return s.negotiator.IsObserver(nodeID)
+}
+
 // SetBroadcastFn sets the broadcast function.
 func (s *LockService) SetBroadcastFn(fn func(msg any) error) {
 	s.negotiator.SetBroadcastFn(fn)
@@ -45,13 +118,64 @@ func (s *LockService) SetConflictHandler(handler func(*LockConflict) error) {
 	s.negotiator.SetConflictHandler(handler)
 }
 
+// SetConflictPolicy sets the policy that decides lock conflicts without
+// negotiation; nil leaves them to the agents.
+func (s *LockService) SetConflictPolicy(policy ConflictPolicy) {
+	s.negotiator.SetPolicy(policy)
+}
+
+// SetRateLimit changes the per-peer lock request rate limit.
+func (s *LockService) SetRateLimit(config *RateLimitConfig) {
+	s.negotiator.SetRateLimit(config)
+}
+
+// RateLimitStats returns the lock request rate limiter statistics.
+func (s *LockService) RateLimitStats() RateLimiterStats {
+	return s.negotiator.RateLimitStats()
+}
+
 // SetEscalateHandler sets the escalation handler.
 func (s *LockService) SetEscalateHandler(handler func(*NegotiationSession) error) {
 	s.negotiator.SetEscalateHandler(handler)
 }
 
+// SetAcquiredHandler sets the callback invoked after this node acquires a
+// lock through AcquireLock.
+func (s *LockService) SetAcquiredHandler(handler func(*SemanticLock)) {
+	s.acquiredMu.Lock()
+	defer s.acquiredMu.Unlock()
+	s.onAcquired = handler
+}
+
+// SetReleasedHandler sets the callback invoked after any lock, local or
+// remote, leaves the store: released, force released, idle released,
+// reconciled away or expired.
+func (s *LockService) SetReleasedHandler(handler func(*SemanticLock)) {
+	s.store.SetRemovedHandler(handler)
+}
+
 // AcquireLock acquires a lock.
 func (s *LockService) AcquireLock(ctx context.Context, req *AcquireLockRequest) (*LockResult, error) {
+	if s.IsObserver() {
+		return &LockResult{
+			Success: false,
+			Reason:  ErrObserverMode.Error(),
+		}, ErrObserverMode
+	}
+
+	// Repeated region locks inside one symbol become a lock on the symbol
+	now := time.Now()
+	symbol, inSymbol := s.enclosingSymbol(req)
+	promoted := inSymbol && s.promotionDue(req.FilePath, symbol, now)
+	if promoted {
+		symbolReq := *req
+		symbolReq.TargetType = symbol.Type
+		symbolReq.Name = symbol.Name
+		symbolReq.StartLine = symbol.StartLine
+		symbolReq.EndLine = symbol.EndLine
+		req = &symbolReq
+	}
+
 	target, err := NewSemanticTarget(
 		req.TargetType,
 		req.FilePath,
@@ -66,7 +190,22 @@ func (s *LockService) AcquireLock(ctx context.Context, req *AcquireLockRequest)
 		}, err
 	}
 
+	mode, err := ParseAccessMode(string(req.Mode))
+	if err != nil {
+		return &LockResult{
+			Success: false,
+			Reason:  err.Error(),
+		}, err
+	}
+
 	lock := NewSemanticLock(target, s.nodeID, s.nodeName, req.Intention)
+	lock.Mode = mode
+	if lock.ExpiresAt, err = req.ExpiresAt(lock.AcquiredAt); err != nil {
+		return &LockResult{
+			Success: false,
+			Reason:  err.Error(),
+		}, err
+	}
 
 	// Phase 1: Announce intent
 	intent, err := s.negotiator.AnnounceIntent(ctx, lock)
@@ -79,44 +218,70 @@ func (s *LockService) AcquireLock(ctx context.Context, req *AcquireLockRequest)
 
 	// Phase 2: Acquire lock
 	result, err := s.negotiator.AcquireLock(ctx, intent.ID)
+	if result != nil {
+		result.ConflictHint = intent.ConflictHint
+		result.Preempted = intent.Preempted
+	}
 	if err != nil {
 		return result, err
 	}
 
+	if result.Success && result.Lock != nil {
+		result.Promoted = promoted
+		if inSymbol && !promoted {
+			s.recordRegionLock(req.FilePath, symbol, now)
+		}
+
+		s.acquiredMu.Lock()
+		onAcquired := s.onAcquired
+		s.acquiredMu.Unlock()
+		if onAcquired != nil {
+			onAcquired(result.Lock)
+		}
+	}
+
 	return result, nil
 }
 
+// UpgradeLock turns one of this node's read locks into a write lock,
+// negotiating with the other readers of its region if there are any.
+func (s *LockService) UpgradeLock(ctx context.Context, lockID string) (*LockResult, error) {
+	if s.IsObserver() {
+		return &LockResult{
+			Success: false,
+			Reason:  ErrObserverMode.Error(),
+		}, ErrObserverMode
+	}
+	return s.negotiator.UpgradeLock(ctx, lockID, s.nodeID)
+}
+
 // ReleaseLock releases a lock.
 func (s *LockService) ReleaseLock(ctx context.Context, lockID string) error {
 	return s.negotiator.ReleaseLock(ctx, lockID, s.nodeID)
 }
 
-// RenewLock renews a lock.
-func (s *LockService) RenewLock(ctx context.Context, lockID string) error {
-	lock, err := s.store.Get(lockID)
-	if err != nil {
-		return err
-	}
+// ReleaseLocksByIntention releases this node's locks acquired under an
+// intention (exact match, or prefix match when prefix is set).
+func (s *LockService) ReleaseLocksByIntention(ctx context.Context, intention string, prefix bool) ([]*SemanticLock, error) {
+	return s.negotiator.ReleaseLocksByIntention(ctx, s.nodeID, intention, prefix)
+}
 
-	if lock.HolderID != s.nodeID {
-		return ErrNotLockHolder
-	}
+// ForceReleaseLock releases a lock regardless of its holder.
+// The operator is the authenticated identity breaking the lock.
+func (s *LockService) ForceReleaseLock(ctx context.Context, lockID, reason, operator string) (*ForceReleaseNotice, error) {
+	return s.negotiator.ForceReleaseLock(ctx, lockID, reason, operator)
+}
 
-	return lock.Renew()
+// RenewLock renews a lock.
+func (s *LockService) RenewLock(ctx context.Context, lockID string) error {
+	_, err := s.RenewLease(ctx, &RenewLeaseRequest{LockID: lockID})
+	return err
 }
 
 // RenewLockWithTTL renews a lock with specified TTL.
 func (s *LockService) RenewLockWithTTL(ctx context.Context, lockID string, ttl time.Duration) error {
-	lock, err := s.store.Get(lockID)
-	if err != nil {
-		return err
-	}
-
-	if lock.HolderID != s.nodeID {
-		return ErrNotLockHolder
-	}
-
-	return lock.RenewWithTTL(ttl)
+	_, err := s.RenewLease(ctx, &RenewLeaseRequest{LockID: lockID, TTL: ttl})
+	return err
 }
 
 // GetLock retrieves a lock.
@@ -161,6 +326,10 @@ func (s *LockService) Negotiate(ctx context.Context, sessionID string, proposal
 
 // Vote votes on lock acquisition.
 func (s *LockService) Vote(ctx context.Context, sessionID string, approve bool, reason string) error {
+	if s.IsObserver() {
+		return ErrObserverMode
+	}
+
 	vote := &Vote{
 		VoterID:   s.nodeID,
 		VoterName: s.nodeName,
@@ -172,19 +341,72 @@ func (s *LockService) Vote(ctx context.Context, sessionID string, approve bool,
 	return s.negotiator.Vote(ctx, sessionID, vote)
 }
 
+// CancelNegotiation cancels a negotiation session requested by this node.
+func (s *LockService) CancelNegotiation(ctx context.Context, sessionID string) (*NegotiationResult, error) {
+	return s.negotiator.CancelSession(ctx, sessionID, s.nodeID)
+}
+
+// HandleRemoteNegotiationCancelled handles a negotiation cancelled by the
+// requester on another node.
+func (s *LockService) HandleRemoteNegotiationCancelled(msg *NegotiationCancelMessage) bool {
+	return s.negotiator.HandleRemoteCancel(msg)
+}
+
 // GetNegotiationSession retrieves a negotiation session.
 func (s *LockService) GetNegotiationSession(sessionID string) (*NegotiationSession, error) {
 	return s.negotiator.GetSession(sessionID)
 }
 
+// RestoreNegotiations adds negotiation sessions persisted at shutdown.
+func (s *LockService) RestoreNegotiations(sessions []*NegotiationSession) int {
+	return s.negotiator.RestoreSessions(sessions)
+}
+
+// RestoreLocks adds locks from a snapshot. Expired locks, locks already
+// known and locks overlapping a held region are skipped, so a restore
+// never overrides the live state. It returns how many were added.
+func (s *LockService) RestoreLocks(locks []*SemanticLock) int {
+	restored := 0
+	for _, lock := range locks {
+		if lock == nil || lock.Target == nil || lock.IsExpired() {
+			continue
+		}
+		if _, err := s.store.Get(lock.ID); err == nil {
+			continue
+		}
+		if len(s.store.FindConflictsFor(lock.Target, lock.Mode)) > 0 {
+			continue
+		}
+		if s.store.Add(lock) == nil {
+			restored++
+		}
+	}
+	return restored
+}
+
+// NegotiationMetrics returns aggregate negotiation metrics.
+func (s *LockService) NegotiationMetrics() *NegotiationMetrics {
+	return s.negotiator.Metrics()
+}
+
+// SetNegotiationBuckets sets the time-to-resolution histogram buckets.
+func (s *LockService) SetNegotiationBuckets(bounds []time.Duration) error {
+	return s.negotiator.SetResolutionBuckets(bounds)
+}
+
 // ListActiveNegotiations lists active negotiation sessions.
 func (s *LockService) ListActiveNegotiations() []*NegotiationSession {
 	return s.negotiator.ListActiveSessions()
 }
 
+// ListEscalatedNegotiations lists negotiations waiting for a human.
+func (s *LockService) ListEscalatedNegotiations() []*NegotiationSession {
+	return s.negotiator.ListEscalatedSessions()
+}
+
 // HandleRemoteLockIntent handles a remote lock intent.
 func (s *LockService) HandleRemoteLockIntent(intent *LockIntent) error {
-	conflicts := s.store.FindConflicts(intent.Lock.Target)
+	conflicts := s.store.FindConflictsFor(intent.Lock.Target, intent.Lock.Mode)
 	if len(conflicts) > 0 {
 		// Notify if conflict with my lock
 		for _, conflict := range conflicts {
@@ -198,6 +420,17 @@ func (s *LockService) HandleRemoteLockIntent(intent *LockIntent) error {
 
 // HandleRemoteLockAcquired handles a remote lock acquisition.
 func (s *LockService) HandleRemoteLockAcquired(lock *SemanticLock) error {
+	// A lock announced again under a new range moved with its symbol
+	if held, err := s.store.Get(lock.ID); err == nil && held.Target.ID() != lock.Target.ID() {
+		moved := *held.Target
+		moved.StartLine, moved.EndLine = lock.Target.StartLine, lock.Target.EndLine
+		if moved.ID() != lock.Target.ID() {
+			return ErrLockConflict
+		}
+		_, err := s.store.Retarget(lock.ID, lock.HolderID, moved.StartLine, moved.EndLine)
+		return err
+	}
+
 	// Store remote lock info (read-only)
 	return s.store.Add(lock)
 }
@@ -217,6 +450,38 @@ func (s *LockService) HandleRemoteLockReleased(lockID string) error {
 	return nil
 }
 
+// HandleRemoteNodeShutdown drops every lock held by a node that announced
+// it is shutting down, instead of waiting for them to expire. It returns
+// the dropped locks.
+func (s *LockService) HandleRemoteNodeShutdown(nodeID string) []*SemanticLock {
+	if nodeID == "" || nodeID == s.nodeID {
+		return nil
+	}
+
+	var dropped []*SemanticLock
+	for _, held := range s.store.ListByHolder(nodeID) {
+		if lock, err := s.store.RemoveDeparted(held.ID, "holder shut down"); err == nil {
+			dropped = append(dropped, lock)
+		}
+	}
+	return dropped
+}
+
+// CheckPreemption reports whether the conflict policy lets
+// notice.PreemptedBy take the lock a peer released for it.
+func (s *LockService) CheckPreemption(notice *ForceReleaseNotice) error {
+	return s.negotiator.CheckPreemption(notice)
+}
+
+// HandleRemoteForceRelease handles a force release broadcast by another node.
+// Unlike a regular release, the lock is removed even when this node holds it.
+func (s *LockService) HandleRemoteForceRelease(notice *ForceReleaseNotice) error {
+	if _, err := s.store.ForceRemove(notice.LockID, notice.Reason, notice.Operator); err != nil && err != ErrLockNotFound {
+		return err
+	}
+	return nil
+}
+
 // GetStats returns lock statistics.
 func (s *LockService) GetStats() *LockStats {
 	locks := s.store.List()
@@ -238,6 +503,7 @@ func (s *LockService) GetStats() *LockStats {
 		MyLocks:            len(myLocks),
 		ActiveNegotiations: len(sessions),
 		AverageTTL:         avgTTL,
+		Actions:            s.store.ActionCounts(),
 	}
 }
 
@@ -249,6 +515,42 @@ type AcquireLockRequest struct {
 	StartLine  int        `json:"start_line"`
 	EndLine    int        `json:"end_line"`
 	Intention  string     `json:"intention"`
+	// Mode requests a shared read lock, which other read locks may
+	// overlap, or an exclusive write lock (the default).
+	Mode AccessMode `json:"mode,omitempty"`
+
+	// TTL overrides DefaultTTL for the new lock and is capped at MaxTTL.
+	TTL time.Duration `json:"ttl,omitempty"`
+	// Deadline sets the lock's expiry to an absolute time instead of a TTL.
+	// It must be in the future and at most MaxTTL away; it cannot be
+	// combined with TTL.
+	Deadline time.Time `json:"deadline,omitzero"`
+
+	// Priority orders this request among the callers waiting in
+	// AcquireLockWait under WaitPriority; higher goes first.
+	Priority int `json:"priority,omitempty"`
+}
+
+// ExpiresAt returns when a lock acquired at now with this request expires.
+func (r *AcquireLockRequest) ExpiresAt(now time.Time) (time.Time, error) {
+	switch {
+	case !r.Deadline.IsZero() && r.TTL != 0:
+		return time.Time{}, NewValidationError("deadline", "cannot be combined with ttl")
+	case !r.Deadline.IsZero():
+		if !r.Deadline.After(now) {
+			return time.Time{}, NewValidationError("deadline", "must be in the future")
+		}
+		if r.Deadline.Sub(now) > MaxTTL {
+			return time.Time{}, NewValidationError("deadline", fmt.Sprintf("must be within %s", MaxTTL))
+		}
+		return r.Deadline, nil
+	case r.TTL < 0:
+		return time.Time{}, NewValidationError("ttl", "cannot be negative")
+	case r.TTL > 0:
+		return now.Add(min(r.TTL, MaxTTL)), nil
+	default:
+		return now.Add(DefaultTTL), nil
+	}
 }
 
 // LockStats is lock statistics.
@@ -257,16 +559,33 @@ type LockStats struct {
 	MyLocks            int           `json:"my_locks"`
 	ActiveNegotiations int           `json:"active_negotiations"`
 	AverageTTL         time.Duration `json:"average_ttl"`
+
+	// Actions counts lock history entries by action (acquired, conflict,
+	// released, ...) since startup, for this node's and its peers' locks.
+	Actions map[string]int64 `json:"actions,omitempty"`
 }
 
 // HistoryEntry is a lock history entry.
 type HistoryEntry struct {
-	Timestamp  time.Time `json:"timestamp"`
-	Action     string    `json:"action"` // acquired, released, conflict, expired
-	LockID     string    `json:"lock_id"`
-	HolderID   string    `json:"holder_id"`
-	HolderName string    `json:"holder_name"`
-	Target     string    `json:"target"`
+	Timestamp    time.Time `json:"timestamp"`
+	Action       string    `json:"action"` // acquired, upgraded, released, force_released, idle_released, partition_lost, holder_shutdown, conflict, negotiated, expired
+	LockID       string    `json:"lock_id"`
+	HolderID     string    `json:"holder_id"`
+	HolderName   string    `json:"holder_name"`
+	Target       string    `json:"target"`
+	FilePath     string    `json:"file_path,omitempty"`
+	FencingToken uint64    `json:"fencing_token,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	Operator     string    `json:"operator,omitempty"`
+	// Resolution and Winner are set on negotiation outcomes
+	Resolution string `json:"resolution,omitempty"`
+	Winner     string `json:"winner,omitempty"`
+}
+
+// SetHistoryHandler sets the callback invoked for every lock history
+// entry. It runs with the lock store locked, so it must not block.
+func (s *LockService) SetHistoryHandler(handler func(*HistoryEntry)) {
+	s.store.SetHistoryHandler(handler)
 }
 
 // GetHistory returns recent lock history.
@@ -16,6 +16,11 @@ type LockStore struct {
 	byTarget   map[string]string        // targetID -> lockID
 	history    []*HistoryEntry          // recent lock history
 	maxHistory int
+	actions    map[string]int64 // history action -> entries since startup
+	onRemoved  func(*SemanticLock)
+	onHistory  func(*HistoryEntry)
+	onChanged  func()
+	released   chan struct{} // closed and replaced when a lock leaves or moves
 	ctx        context.Context
 	cancel     context.CancelFunc
 }
@@ -28,6 +33,8 @@ func NewLockStore(ctx context.Context) *LockStore {
 		byTarget:   make(map[string]string),
 		history:    make([]*HistoryEntry, 0, 100),
 		maxHistory: 100,
+		actions:    make(map[string]int64),
+		released:   make(chan struct{}),
 		ctx:        ctx,
 		cancel:     cancel,
 	}
@@ -48,30 +55,161 @@ func (s *LockStore) Add(lock *SemanticLock) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Check if target already has a lock
+	// Check if target already has a lock; read locks share their target
 	targetID := lock.Target.ID()
-	if existingID, exists := s.byTarget[targetID]; exists {
-		if existingLock, ok := s.locks[existingID]; ok && !existingLock.IsExpired() {
+	if existingID, exists := s.byTarget[targetID]; exists && existingID != lock.ID {
+		if existingLock, ok := s.locks[existingID]; ok && !existingLock.IsExpired() && !compatible(lock.Mode, existingLock.Mode) {
 			return ErrLockConflict
 		}
 	}
 
+	// A lock announced again under its ID is only taken back by its
+	// holder: after a restart, or upgraded from a read lock. Moving it to
+	// another target goes through Retarget.
+	action := "acquired"
+	if previous, exists := s.locks[lock.ID]; exists {
+		switch {
+		case previous.HolderID != lock.HolderID:
+			return ErrNotLockHolder
+		case previous.Target.ID() != targetID:
+			return ErrLockConflict
+		case previous.Shared() && !lock.Shared():
+			action = "upgraded"
+		default:
+			action = "restored"
+		}
+	}
+
 	s.locks[lock.ID] = lock
-	s.byTarget[targetID] = lock.ID
+	s.index(lock)
 
 	// Record history
-	s.addHistory(&HistoryEntry{
-		Timestamp:  time.Now(),
-		Action:     "acquired",
-		LockID:     lock.ID,
-		HolderID:   lock.HolderID,
-		HolderName: lock.HolderName,
-		Target:     lock.Target.String(),
-	})
+	s.addHistory(lockHistoryEntry(action, lock))
+	s.changed()
 
 	return nil
 }
 
+// RecordConflict records a lock request that ran into a held lock.
+func (s *LockStore) RecordConflict(conflict *LockConflict) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requested, held := conflict.RequestedLock, conflict.ConflictingLock
+	entry := lockHistoryEntry("conflict", requested)
+	entry.Reason = "held by " + held.HolderName
+	s.addHistory(entry)
+}
+
+// RecordNegotiation records the outcome of a negotiation session over
+// the requested lock.
+func (s *LockStore) RecordNegotiation(session *NegotiationSession, result *NegotiationResult) {
+	if session.RequestedLock == nil || session.RequestedLock.Target == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := lockHistoryEntry("negotiated", session.RequestedLock)
+	entry.Timestamp = result.ResolvedAt
+	entry.Resolution = string(result.ResolutionType)
+	entry.Reason = result.Message
+	if result.WinnerLock != nil {
+		entry.Winner = result.WinnerLock.HolderName
+	}
+	s.addHistory(entry)
+}
+
+// SetRemovedHandler sets the callback invoked after a lock leaves the
+// store, whether released, broken, reconciled away or expired.
+func (s *LockStore) SetRemovedHandler(handler func(*SemanticLock)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onRemoved = handler
+}
+
+// Retarget moves holderID's lock to a new line range, e.g. after the
+// symbol it covers shifted in the file. The new range must not overlap a
+// live lock the lock cannot coexist with.
+func (s *LockStore) Retarget(lockID, holderID string, startLine, endLine int) (*SemanticLock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, exists := s.locks[lockID]
+	if !exists {
+		return nil, ErrLockNotFound
+	}
+	if lock.HolderID != holderID {
+		return nil, ErrNotLockHolder
+	}
+
+	target := *lock.Target
+	target.StartLine = startLine
+	target.EndLine = endLine
+	for _, other := range s.locks {
+		if other.ID != lock.ID && !other.IsExpired() && other.Target.Overlaps(&target) && !compatible(lock.Mode, other.Mode) {
+			return nil, ErrLockConflict
+		}
+	}
+
+	s.unindex(lock)
+	lock.Target = &target
+	s.index(lock)
+
+	s.addHistory(lockHistoryEntry("retargeted", lock))
+	s.signalReleased()
+	s.changed()
+	return lock, nil
+}
+
+// Renew extends the lease of a live lock and returns a copy of it. A
+// non-zero fencing token must match the lock's; maxLease caps the lease
+// measured from acquisition (0 = no cap).
+func (s *LockStore) Renew(lockID string, fencingToken uint64, ttl, maxLease time.Duration) (*SemanticLock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, exists := s.locks[lockID]
+	if !exists {
+		return nil, ErrLockNotFound
+	}
+	if lock.IsExpired() {
+		return nil, ErrLockExpired
+	}
+	if fencingToken != 0 && fencingToken != lock.FencingToken {
+		return nil, ErrStaleFencingToken
+	}
+
+	if err := lock.renew(time.Now(), ttl, maxLease); err != nil {
+		return nil, err
+	}
+	s.changed()
+	renewed := *lock
+	return &renewed, nil
+}
+
+// ApplyRenewal extends a remote lock's lease as announced by its holder.
+// It returns false when the lock is unknown, belongs to another holder or
+// fencing token, or the announced lease is not newer.
+func (s *LockStore) ApplyRenewal(msg *RenewMessage) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, exists := s.locks[msg.LockID]
+	if !exists || lock.HolderID != msg.HolderID || lock.FencingToken != msg.FencingToken {
+		return false
+	}
+	if !msg.ExpiresAt.After(lock.ExpiresAt) {
+		return false
+	}
+
+	lock.RenewedAt = msg.RenewedAt
+	lock.ExpiresAt = msg.ExpiresAt
+	lock.RenewCount = msg.RenewCount
+	lock.LastActivity = msg.RenewedAt
+	return true
+}
+
 // Get retrieves a lock.
 func (s *LockStore) Get(lockID string) (*SemanticLock, error) {
 	s.mu.RLock()
@@ -113,32 +251,98 @@ func (s *LockStore) GetByTarget(target *SemanticTarget) (*SemanticLock, error) {
 
 // Remove removes a lock.
 func (s *LockStore) Remove(lockID string) error {
+	_, err := s.remove(lockID, HistoryEntry{Action: "released"})
+	return err
+}
+
+// reinstate puts back a lock removed for a grant that then failed. The lock
+// coexisted with the others before, so it skips the conflict check.
+func (s *LockStore) reinstate(lock *SemanticLock) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.locks[lock.ID] = lock
+	s.index(lock)
+	s.addHistory(lockHistoryEntry("restored", lock))
+	s.changed()
+}
+
+// ForceRemove removes a lock regardless of holder and records the reason
+// and operator in the history.
+func (s *LockStore) ForceRemove(lockID, reason, operator string) (*SemanticLock, error) {
+	return s.remove(lockID, HistoryEntry{Action: "force_released", Reason: reason, Operator: operator})
+}
+
+// RemoveIdle removes a lock released for inactivity and records the reason
+// in the history.
+func (s *LockStore) RemoveIdle(lockID, reason string) (*SemanticLock, error) {
+	return s.removeWithReason(lockID, "idle_released", reason)
+}
+
+// RemoveReconciled removes a lock that lost a partition reconciliation and
+// records the reason in the history.
+func (s *LockStore) RemoveReconciled(lockID, reason string) (*SemanticLock, error) {
+	return s.removeWithReason(lockID, "partition_lost", reason)
+}
+
+// RemoveDeparted removes a lock whose holder announced it is shutting down
+// and records the reason in the history.
+func (s *LockStore) RemoveDeparted(lockID, reason string) (*SemanticLock, error) {
+	return s.removeWithReason(lockID, "holder_shutdown", reason)
+}
+
+// RemoveBulk removes a lock released as part of a bulk release and records
+// the reason in the history.
+func (s *LockStore) RemoveBulk(lockID, reason string) (*SemanticLock, error) {
+	return s.removeWithReason(lockID, "bulk_released", reason)
+}
+
+func (s *LockStore) removeWithReason(lockID, action, reason string) (*SemanticLock, error) {
+	return s.remove(lockID, HistoryEntry{Action: action, Reason: reason})
+}
+
+// remove deletes a lock, records entry in the history and notifies the
+// removal handler once the store is unlocked.
+func (s *LockStore) remove(lockID string, entry HistoryEntry) (*SemanticLock, error) {
+	s.mu.Lock()
 	lock, exists := s.locks[lockID]
 	if !exists {
-		return ErrLockNotFound
+		s.mu.Unlock()
+		return nil, ErrLockNotFound
 	}
 
 	delete(s.locks, lockID)
-	delete(s.byTarget, lock.Target.ID())
-
-	// Record history
-	s.addHistory(&HistoryEntry{
-		Timestamp:  time.Now(),
-		Action:     "released",
-		LockID:     lock.ID,
-		HolderID:   lock.HolderID,
-		HolderName: lock.HolderName,
-		Target:     lock.Target.String(),
-	})
-
-	return nil
+	s.unindex(lock)
+
+	entry.Timestamp = time.Now()
+	entry.LockID = lock.ID
+	entry.HolderID = lock.HolderID
+	entry.HolderName = lock.HolderName
+	entry.Target = lock.Target.String()
+	entry.FilePath = lock.Target.FilePath
+	entry.FencingToken = lock.FencingToken
+	s.addHistory(&entry)
+	s.signalReleased()
+	s.changed()
+	onRemoved := s.onRemoved
+	s.mu.Unlock()
+
+	if onRemoved != nil {
+		onRemoved(lock)
+	}
+	return lock, nil
 }
 
-// FindConflicts finds conflicting locks.
+// FindConflicts finds the live locks overlapping target, i.e. those a
+// write lock on it conflicts with.
 func (s *LockStore) FindConflicts(target *SemanticTarget) []*SemanticLock {
+	return s.FindConflictsFor(target, AccessWrite)
+}
+
+// FindConflictsFor finds the live locks a lock on target in mode cannot
+// coexist with: every overlapping lock for a write lock, the overlapping
+// write locks for a read lock.
+func (s *LockStore) FindConflictsFor(target *SemanticTarget, mode AccessMode) []*SemanticLock {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -149,7 +353,7 @@ func (s *LockStore) FindConflicts(target *SemanticTarget) []*SemanticLock {
 			continue
 		}
 
-		if lock.Target.Overlaps(target) {
+		if lock.Target.Overlaps(target) && !compatible(mode, lock.Mode) {
 			conflicts = append(conflicts, lock)
 		}
 	}
@@ -157,6 +361,34 @@ func (s *LockStore) FindConflicts(target *SemanticTarget) []*SemanticLock {
 	return conflicts
 }
 
+// index makes lock the lock found by its target, unless another live lock
+// it shares the target with already is. Must be called with s.mu held.
+func (s *LockStore) index(lock *SemanticLock) {
+	targetID := lock.Target.ID()
+	if existingID, exists := s.byTarget[targetID]; exists && existingID != lock.ID {
+		if existing, ok := s.locks[existingID]; ok && !existing.IsExpired() {
+			return
+		}
+	}
+	s.byTarget[targetID] = lock.ID
+}
+
+// unindex removes lock from the target index, handing its target to
+// another read lock on it if there is one. Must be called with s.mu held.
+func (s *LockStore) unindex(lock *SemanticLock) {
+	targetID := lock.Target.ID()
+	if s.byTarget[targetID] != lock.ID {
+		return
+	}
+	delete(s.byTarget, targetID)
+	for _, other := range s.locks {
+		if other.ID != lock.ID && other.Target.ID() == targetID {
+			s.byTarget[targetID] = other.ID
+			return
+		}
+	}
+}
+
 // List returns all active locks.
 func (s *LockStore) List() []*SemanticLock {
 	s.mu.RLock()
@@ -214,33 +446,110 @@ func (s *LockStore) cleanupExpired() {
 		case <-s.ctx.Done():
 			return
 		case <-ticker.C:
+			var expired []*SemanticLock
 			s.mu.Lock()
 			for id, lock := range s.locks {
 				if lock.IsExpired() {
+					expired = append(expired, lock)
 					delete(s.locks, id)
-					delete(s.byTarget, lock.Target.ID())
+					s.unindex(lock)
 					// Record expiration in history
-					s.history = append(s.history, &HistoryEntry{
-						Timestamp:  time.Now(),
-						Action:     "expired",
-						LockID:     lock.ID,
-						HolderID:   lock.HolderID,
-						HolderName: lock.HolderName,
-						Target:     lock.Target.String(),
-					})
+					s.addHistory(lockHistoryEntry("expired", lock))
 				}
 			}
+			if len(expired) > 0 {
+				s.signalReleased()
+				s.changed()
+			}
+			onRemoved := s.onRemoved
 			s.mu.Unlock()
+
+			if onRemoved != nil {
+				for _, lock := range expired {
+					onRemoved(lock)
+				}
+			}
 		}
 	}
 }
 
+// releasedSignal returns a channel closed the next time a lock leaves the
+// store or moves, when a region someone waits for may have come free.
+func (s *LockStore) releasedSignal() <-chan struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.released
+}
+
+// signalReleased wakes everyone waiting on releasedSignal. Must be called
+// with s.mu held.
+func (s *LockStore) signalReleased() {
+	close(s.released)
+	s.released = make(chan struct{})
+}
+
+// lockHistoryEntry returns a history entry for an action on a lock.
+func lockHistoryEntry(action string, lock *SemanticLock) *HistoryEntry {
+	return &HistoryEntry{
+		Timestamp:    time.Now(),
+		Action:       action,
+		LockID:       lock.ID,
+		HolderID:     lock.HolderID,
+		HolderName:   lock.HolderName,
+		Target:       lock.Target.String(),
+		FilePath:     lock.Target.FilePath,
+		FencingToken: lock.FencingToken,
+	}
+}
+
+// SetHistoryHandler sets the callback invoked for every history entry,
+// e.g. to persist an audit log. It runs with the store locked, so it must
+// not block or call back into the store.
+func (s *LockStore) SetHistoryHandler(handler func(*HistoryEntry)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onHistory = handler
+}
+
+// SetChangedHandler sets the callback invoked whenever a lock is added,
+// moved, renewed or removed, e.g. to persist the lock table. It runs with
+// the store locked, so it must not block or call back into the store.
+func (s *LockStore) SetChangedHandler(handler func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChanged = handler
+}
+
+// changed notifies the change handler (must be called with lock held).
+func (s *LockStore) changed() {
+	if s.onChanged != nil {
+		s.onChanged()
+	}
+}
+
 // addHistory adds an entry to the history (must be called with lock held).
 func (s *LockStore) addHistory(entry *HistoryEntry) {
+	s.actions[entry.Action]++
 	s.history = append(s.history, entry)
 	if len(s.history) > s.maxHistory {
 		s.history = s.history[1:]
 	}
+	if s.onHistory != nil {
+		s.onHistory(entry)
+	}
+}
+
+// ActionCounts returns how many history entries of each action were
+// recorded since startup, including those no longer kept in the history.
+func (s *LockStore) ActionCounts() map[string]int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int64, len(s.actions))
+	for action, n := range s.actions {
+		counts[action] = n
+	}
+	return counts
 }
 
 // GetHistory returns recent lock history entries.
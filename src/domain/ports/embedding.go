@@ -8,6 +8,10 @@ type EmbeddingService interface {
 	// Embed generates an embedding for a single text.
 	Embed(ctx context.Context, text string) ([]float32, error)
 
+	// EmbedQuery generates an embedding for a search query, which
+	// asymmetric models embed differently from stored documents.
+	EmbedQuery(ctx context.Context, text string) ([]float32, error)
+
 	// EmbedBatch generates embeddings for multiple texts.
 	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
 
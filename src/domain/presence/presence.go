@@ -0,0 +1,210 @@
+// Package presence tracks where agents are currently editing.
+//
+// Presence is lighter than a lock: it claims nothing, is never persisted and
+// simply expires unless the agent keeps reporting. Peers use it to steer
+// clear of regions someone is typing in before a conflict happens.
+package presence
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long an active edit stays visible without a refresh.
+const DefaultTTL = 30 * time.Second
+
+// MessageType is the type of presence messages on the wire.
+const MessageType = "presence"
+
+// ActiveEdit is an agent's current editing position.
+type ActiveEdit struct {
+	AgentID   string    `json:"agent_id"`
+	AgentName string    `json:"agent_name"`
+	FilePath  string    `json:"file_path"`
+	StartLine int       `json:"start_line,omitempty"`
+	EndLine   int       `json:"end_line,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Region returns "path" or "path:start-end".
+func (e *ActiveEdit) Region() string {
+	if e.StartLine <= 0 {
+		return e.FilePath
+	}
+	return fmt.Sprintf("%s:%d-%d", e.FilePath, e.StartLine, e.EndLine)
+}
+
+// Overlaps reports whether the edit touches lines start-end of filePath.
+// A zero range means the whole file.
+func (e *ActiveEdit) Overlaps(filePath string, start, end int) bool {
+	if e.FilePath != filePath {
+		return false
+	}
+	if e.StartLine <= 0 || start <= 0 {
+		return true
+	}
+	return e.StartLine <= end && start <= e.EndLine
+}
+
+// Message announces or clears an active edit.
+type Message struct {
+	Type string      `json:"type"`
+	Edit *ActiveEdit `json:"edit"`
+	// TTLMs is how long the receiver keeps the edit, so expiry does not
+	// depend on the sender's clock.
+	TTLMs   int64 `json:"ttl_ms"`
+	Cleared bool  `json:"cleared,omitempty"`
+}
+
+// Tracker holds the active edits of this node and its peers.
+type Tracker struct {
+	mu        sync.RWMutex
+	nodeID    string
+	nodeName  string
+	ttl       time.Duration
+	edits     map[string]*ActiveEdit // agentID|filePath -> edit
+	now       func() time.Time
+	broadcast func(msg any) error
+}
+
+// NewTracker creates a tracker for the local node. A non-positive ttl uses
+// DefaultTTL.
+func NewTracker(nodeID, nodeName string, ttl time.Duration) *Tracker {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Tracker{
+		nodeID:   nodeID,
+		nodeName: nodeName,
+		ttl:      ttl,
+		edits:    make(map[string]*ActiveEdit),
+		now:      time.Now,
+	}
+}
+
+// SetBroadcastFn sets the function used to announce local edits to peers.
+func (t *Tracker) SetBroadcastFn(fn func(msg any) error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.broadcast = fn
+}
+
+// TTL returns how long edits stay visible without a refresh.
+func (t *Tracker) TTL() time.Duration {
+	return t.ttl
+}
+
+// Report records that this node is editing a region of a file and
+// announces it to peers. Reporting again refreshes the TTL.
+func (t *Tracker) Report(filePath string, startLine, endLine int) (*ActiveEdit, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+	if startLine > 0 && endLine < startLine {
+		return nil, fmt.Errorf("invalid region: %d-%d", startLine, endLine)
+	}
+
+	now := t.now()
+	edit := &ActiveEdit{
+		AgentID:   t.nodeID,
+		AgentName: t.nodeName,
+		FilePath:  filePath,
+		StartLine: startLine,
+		EndLine:   endLine,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(t.ttl),
+	}
+
+	t.mu.Lock()
+	t.edits[key(edit.AgentID, filePath)] = edit
+	broadcast := t.broadcast
+	t.mu.Unlock()
+
+	if broadcast != nil {
+		msg := Message{Type: MessageType, Edit: edit, TTLMs: t.ttl.Milliseconds()}
+		if err := broadcast(msg); err != nil {
+			return edit, err
+		}
+	}
+	return edit, nil
+}
+
+// Clear removes this node's edit on a file and tells peers.
+func (t *Tracker) Clear(filePath string) error {
+	t.mu.Lock()
+	edit, ok := t.edits[key(t.nodeID, filePath)]
+	delete(t.edits, key(t.nodeID, filePath))
+	broadcast := t.broadcast
+	t.mu.Unlock()
+
+	if !ok || broadcast == nil {
+		return nil
+	}
+	return broadcast(Message{Type: MessageType, Edit: edit, Cleared: true})
+}
+
+// HandleRemote applies a presence message from a peer.
+func (t *Tracker) HandleRemote(msg *Message) {
+	if msg == nil || msg.Edit == nil || msg.Edit.AgentID == t.nodeID {
+		return
+	}
+	k := key(msg.Edit.AgentID, msg.Edit.FilePath)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if msg.Cleared {
+		delete(t.edits, k)
+		return
+	}
+
+	ttl := time.Duration(msg.TTLMs) * time.Millisecond
+	if ttl <= 0 {
+		ttl = t.ttl
+	}
+	edit := *msg.Edit
+	edit.ExpiresAt = t.now().Add(ttl)
+	t.edits[k] = &edit
+}
+
+// Active returns the unexpired edits, most recent first, and forgets the
+// expired ones.
+func (t *Tracker) Active() []*ActiveEdit {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	edits := make([]*ActiveEdit, 0, len(t.edits))
+	for k, edit := range t.edits {
+		if !now.Before(edit.ExpiresAt) {
+			delete(t.edits, k)
+			continue
+		}
+		edits = append(edits, edit)
+	}
+
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].UpdatedAt.After(edits[j].UpdatedAt)
+	})
+	return edits
+}
+
+// ActiveIn returns the unexpired edits by other agents overlapping lines
+// start-end of filePath (0 for the whole file).
+func (t *Tracker) ActiveIn(filePath string, startLine, endLine int) []*ActiveEdit {
+	var edits []*ActiveEdit
+	for _, edit := range t.Active() {
+		if edit.AgentID != t.nodeID && edit.Overlaps(filePath, startLine, endLine) {
+			edits = append(edits, edit)
+		}
+	}
+	return edits
+}
+
+func key(agentID, filePath string) string {
+	return agentID + "|" + filePath
+}
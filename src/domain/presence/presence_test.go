@@ -0,0 +1,117 @@
+package presence
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestTracker returns a tracker whose clock is driven by the returned
+// pointer.
+func newTestTracker(nodeID string, ttl time.Duration) (*Tracker, *time.Time) {
+	now := time.Now()
+	t := NewTracker(nodeID, nodeID+"-name", ttl)
+	t.now = func() time.Time { return now }
+	return t, &now
+}
+
+func TestTracker_ReportExpiresAfterTTL(t *testing.T) {
+	tracker, now := newTestTracker("node-a", 10*time.Second)
+
+	if _, err := tracker.Report("main.go", 10, 20); err != nil {
+		t.Fatal(err)
+	}
+	if got := tracker.Active(); len(got) != 1 || got[0].Region() != "main.go:10-20" {
+		t.Fatalf("expected active edit on main.go:10-20, got %+v", got)
+	}
+
+	*now = now.Add(9 * time.Second)
+	if len(tracker.Active()) != 1 {
+		t.Fatal("edit should still be active before the TTL")
+	}
+
+	*now = now.Add(time.Second)
+	if got := tracker.Active(); len(got) != 0 {
+		t.Errorf("edit should expire after the TTL, got %+v", got)
+	}
+}
+
+func TestTracker_ReportRefreshesTTL(t *testing.T) {
+	tracker, now := newTestTracker("node-a", 10*time.Second)
+
+	tracker.Report("main.go", 1, 5)
+	*now = now.Add(8 * time.Second)
+	tracker.Report("main.go", 3, 8)
+	*now = now.Add(8 * time.Second)
+
+	got := tracker.Active()
+	if len(got) != 1 || got[0].StartLine != 3 {
+		t.Errorf("refreshed edit should replace the old one, got %+v", got)
+	}
+}
+
+func TestTracker_RemoteEditsVisibleUntilExpiry(t *testing.T) {
+	alpha, _ := newTestTracker("node-a", 10*time.Second)
+	beta, betaNow := newTestTracker("node-b", time.Minute)
+
+	var sent []any
+	alpha.SetBroadcastFn(func(msg any) error {
+		sent = append(sent, msg)
+		return nil
+	})
+
+	alpha.Report("api.go", 40, 60)
+	if len(sent) != 1 {
+		t.Fatalf("expected one presence broadcast, got %d", len(sent))
+	}
+	msg := sent[0].(Message)
+	beta.HandleRemote(&msg)
+
+	edits := beta.ActiveIn("api.go", 50, 55)
+	if len(edits) != 1 || edits[0].AgentID != "node-a" {
+		t.Fatalf("beta should see alpha editing api.go, got %+v", edits)
+	}
+	if others := beta.ActiveIn("api.go", 100, 120); len(others) != 0 {
+		t.Errorf("non-overlapping region should be clear, got %+v", others)
+	}
+
+	// The sender's TTL applies on the receiver, not the receiver's own
+	*betaNow = betaNow.Add(11 * time.Second)
+	if got := beta.Active(); len(got) != 0 {
+		t.Errorf("remote edit should expire after the sender's TTL, got %+v", got)
+	}
+}
+
+func TestTracker_ClearRemovesEditOnPeers(t *testing.T) {
+	alpha, _ := newTestTracker("node-a", 10*time.Second)
+	beta, _ := newTestTracker("node-b", 10*time.Second)
+	alpha.SetBroadcastFn(func(msg any) error {
+		m := msg.(Message)
+		beta.HandleRemote(&m)
+		return nil
+	})
+
+	alpha.Report("api.go", 1, 10)
+	if len(beta.Active()) != 1 {
+		t.Fatal("beta should see alpha's edit")
+	}
+
+	if err := alpha.Clear("api.go"); err != nil {
+		t.Fatal(err)
+	}
+	if len(alpha.Active()) != 0 || len(beta.Active()) != 0 {
+		t.Error("cleared edit should disappear everywhere")
+	}
+}
+
+func TestTracker_RejectsInvalidRegion(t *testing.T) {
+	tracker := NewTracker("node-a", "A", 0)
+	if _, err := tracker.Report("", 0, 0); err == nil {
+		t.Error("expected error for empty file path")
+	}
+	if _, err := tracker.Report("main.go", 10, 5); err == nil {
+		t.Error("expected error for inverted region")
+	}
+	if tracker.TTL() != DefaultTTL {
+		t.Errorf("expected default TTL, got %s", tracker.TTL())
+	}
+}
@@ -0,0 +1,346 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned when a token budget blocks a call.
+var ErrBudgetExceeded = errors.New("token budget exceeded")
+
+// BudgetAction decides what happens to calls once a budget is spent.
+type BudgetAction string
+
+const (
+	// BudgetReject rejects every call until the period ends. This is the
+	// default.
+	BudgetReject BudgetAction = "reject"
+	// BudgetThrottle lets one call through per throttle interval.
+	BudgetThrottle BudgetAction = "throttle"
+)
+
+// DefaultThrottleInterval is how often a throttled budget lets a call through.
+const DefaultThrottleInterval = time.Minute
+
+// BudgetLimits are token limits for one scope; 0 means unlimited.
+type BudgetLimits struct {
+	Daily   int64 `json:"daily,omitempty"`
+	Monthly int64 `json:"monthly,omitempty"`
+}
+
+// BudgetConfig configures hard token limits.
+type BudgetConfig struct {
+	// Project limits all usage recorded on this node.
+	Project BudgetLimits
+	// Agents limits agents by node ID or name; "*" applies to agents
+	// without an entry of their own.
+	Agents map[string]BudgetLimits
+	// Action is BudgetReject or BudgetThrottle.
+	Action BudgetAction
+	// ThrottleInterval is the gap between calls let through under
+	// BudgetThrottle (default DefaultThrottleInterval).
+	ThrottleInterval time.Duration
+}
+
+// Enabled reports whether any limit is set.
+func (c BudgetConfig) Enabled() bool {
+	if c.Project.Daily > 0 || c.Project.Monthly > 0 {
+		return true
+	}
+	for _, limits := range c.Agents {
+		if limits.Daily > 0 || limits.Monthly > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the configuration.
+func (c BudgetConfig) Validate() error {
+	switch c.Action {
+	case "", BudgetReject, BudgetThrottle:
+	default:
+		return fmt.Errorf("unknown budget action %q", c.Action)
+	}
+	if c.ThrottleInterval < 0 {
+		return fmt.Errorf("throttle interval must not be negative")
+	}
+	check := func(scope string, limits BudgetLimits) error {
+		if limits.Daily < 0 || limits.Monthly < 0 {
+			return fmt.Errorf("%s budget must not be negative", scope)
+		}
+		return nil
+	}
+	if err := check("project", c.Project); err != nil {
+		return err
+	}
+	for agent, limits := range c.Agents {
+		if err := check("agent "+agent, limits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BudgetExceeded describes a spent budget.
+type BudgetExceeded struct {
+	// Scope is "project" or "agent".
+	Scope   string `json:"scope"`
+	AgentID string `json:"agent_id,omitempty"`
+	// Period is "day" or "month".
+	Period string       `json:"period"`
+	Limit  int64        `json:"limit"`
+	Used   int64        `json:"used"`
+	Action BudgetAction `json:"action"`
+	// RetryAfter is when a throttled call may go through.
+	RetryAfter time.Time `json:"retry_after,omitzero"`
+}
+
+// Error implements error.
+func (e *BudgetExceeded) Error() string {
+	scope := e.Scope
+	if e.AgentID != "" {
+		scope += " " + e.AgentID
+	}
+	msg := fmt.Sprintf("%s: %s %s budget of %d tokens used up (%d used)", ErrBudgetExceeded, scope, e.Period, e.Limit, e.Used)
+	if !e.RetryAfter.IsZero() {
+		msg += fmt.Sprintf(", throttled until %s", e.RetryAfter.Format(time.RFC3339))
+	}
+	return msg
+}
+
+// Unwrap makes errors.Is(err, ErrBudgetExceeded) hold.
+func (e *BudgetExceeded) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// budgetUsage is the usage of one scope in the current day and month.
+type budgetUsage struct {
+	day   int64
+	month int64
+}
+
+// Budget enforces hard token limits per project and per agent. Usage
+// counts towards the calendar day and month in local time.
+type Budget struct {
+	mu sync.Mutex
+
+	cfg BudgetConfig
+	now func() time.Time
+
+	day     string
+	month   string
+	project budgetUsage
+	agents  map[string]*budgetUsage
+
+	// Limits already reported in this period, and when a throttled
+	// scope last let a call through
+	notified    map[string]bool
+	lastAllowed map[string]time.Time
+
+	onExceeded func(*BudgetExceeded)
+}
+
+// NewBudget creates a budget.
+func NewBudget(cfg BudgetConfig) *Budget {
+	if cfg.Action == "" {
+		cfg.Action = BudgetReject
+	}
+	if cfg.ThrottleInterval == 0 {
+		cfg.ThrottleInterval = DefaultThrottleInterval
+	}
+	return &Budget{
+		cfg:         cfg,
+		now:         time.Now,
+		agents:      make(map[string]*budgetUsage),
+		notified:    make(map[string]bool),
+		lastAllowed: make(map[string]time.Time),
+	}
+}
+
+// OnExceeded sets a callback run once per limit and period when usage
+// first reaches the limit.
+func (b *Budget) OnExceeded(fn func(*BudgetExceeded)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onExceeded = fn
+}
+
+// Seed adds usage recorded before the budget was created, e.g. by a
+// previous run. It does not report exceeded limits.
+func (b *Budget) Seed(agentID, agentName string, today, thisMonth int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollover()
+
+	b.project.day += today
+	b.project.month += thisMonth
+	usage := b.agentUsage(agentID)
+	usage.day += today
+	usage.month += thisMonth
+
+	// Limits already spent stay quiet until the next period
+	for _, exceeded := range b.exceededLocked(agentID, agentName) {
+		b.notified[exceededKey(exceeded)] = true
+	}
+}
+
+// Record counts tokens used by an agent and reports limits it reaches.
+func (b *Budget) Record(agentID, agentName string, tokens int64) {
+	b.mu.Lock()
+	b.rollover()
+
+	b.project.day += tokens
+	b.project.month += tokens
+	usage := b.agentUsage(agentID)
+	usage.day += tokens
+	usage.month += tokens
+
+	var reached []*BudgetExceeded
+	for _, exceeded := range b.exceededLocked(agentID, agentName) {
+		key := exceededKey(exceeded)
+		if !b.notified[key] {
+			b.notified[key] = true
+			reached = append(reached, exceeded)
+		}
+	}
+	fn := b.onExceeded
+	b.mu.Unlock()
+
+	if fn != nil {
+		for _, exceeded := range reached {
+			fn(exceeded)
+		}
+	}
+}
+
+// Allow returns a *BudgetExceeded error when a spent budget blocks the
+// agent's next call. Under BudgetThrottle one call per interval still
+// goes through.
+func (b *Budget) Allow(agentID, agentName string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollover()
+
+	exceeded := b.exceededLocked(agentID, agentName)
+	if len(exceeded) == 0 {
+		return nil
+	}
+	first := exceeded[0]
+	if b.cfg.Action != BudgetThrottle {
+		return first
+	}
+
+	now := b.now()
+	key := exceededKey(first)
+	next := b.lastAllowed[key].Add(b.cfg.ThrottleInterval)
+	if now.Before(next) {
+		throttled := *first
+		throttled.RetryAfter = next
+		return &throttled
+	}
+	b.lastAllowed[key] = now
+	return nil
+}
+
+// Exceeded returns the limits spent in the current period for an agent,
+// including the project's.
+func (b *Budget) Exceeded(agentID, agentName string) []*BudgetExceeded {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rollover()
+	return b.exceededLocked(agentID, agentName)
+}
+
+// exceededLocked lists the spent limits, project first.
+func (b *Budget) exceededLocked(agentID, agentName string) []*BudgetExceeded {
+	var exceeded []*BudgetExceeded
+	check := func(scope, agent string, limits BudgetLimits, usage budgetUsage) {
+		if limits.Daily > 0 && usage.day >= limits.Daily {
+			exceeded = append(exceeded, &BudgetExceeded{Scope: scope, AgentID: agent, Period: "day", Limit: limits.Daily, Used: usage.day, Action: b.cfg.Action})
+		}
+		if limits.Monthly > 0 && usage.month >= limits.Monthly {
+			exceeded = append(exceeded, &BudgetExceeded{Scope: scope, AgentID: agent, Period: "month", Limit: limits.Monthly, Used: usage.month, Action: b.cfg.Action})
+		}
+	}
+
+	check("project", "", b.cfg.Project, b.project)
+	if limits, ok := b.agentLimits(agentID, agentName); ok {
+		var usage budgetUsage
+		if u, ok := b.agents[agentID]; ok {
+			usage = *u
+		}
+		check("agent", agentID, limits, usage)
+	}
+	return exceeded
+}
+
+// agentLimits returns the limits for an agent by node ID, then name, then "*".
+func (b *Budget) agentLimits(agentID, agentName string) (BudgetLimits, bool) {
+	for _, key := range []string{agentID, agentName, "*"} {
+		if key == "" {
+			continue
+		}
+		if limits, ok := b.cfg.Agents[key]; ok {
+			return limits, true
+		}
+	}
+	return BudgetLimits{}, false
+}
+
+func (b *Budget) agentUsage(agentID string) *budgetUsage {
+	usage, ok := b.agents[agentID]
+	if !ok {
+		usage = &budgetUsage{}
+		b.agents[agentID] = usage
+	}
+	return usage
+}
+
+// rollover starts a new day or month when the calendar moved on.
+func (b *Budget) rollover() {
+	now := b.now()
+	day, month := now.Format("2006-01-02"), now.Format("2006-01")
+
+	if month != b.month {
+		b.month = month
+		b.project.month = 0
+		for _, usage := range b.agents {
+			usage.month = 0
+		}
+		b.resetPeriod("month")
+	}
+	if day != b.day {
+		b.day = day
+		b.project.day = 0
+		for _, usage := range b.agents {
+			usage.day = 0
+		}
+		b.resetPeriod("day")
+	}
+}
+
+// resetPeriod forgets notifications and throttling for a period.
+func (b *Budget) resetPeriod(period string) {
+	for key := range b.notified {
+		if periodOf(key) == period {
+			delete(b.notified, key)
+		}
+	}
+	for key := range b.lastAllowed {
+		if periodOf(key) == period {
+			delete(b.lastAllowed, key)
+		}
+	}
+}
+
+func exceededKey(e *BudgetExceeded) string {
+	return e.Scope + "/" + e.AgentID + "/" + e.Period
+}
+
+func periodOf(key string) string {
+	return key[strings.LastIndexByte(key, '/')+1:]
+}
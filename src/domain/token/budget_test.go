@@ -0,0 +1,160 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestBudget returns a budget whose clock the test moves.
+func newTestBudget(cfg BudgetConfig) (*Budget, *time.Time) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.Local)
+	b := NewBudget(cfg)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBudget_RejectsOnceProjectLimitIsSpent(t *testing.T) {
+	b, _ := newTestBudget(BudgetConfig{Project: BudgetLimits{Daily: 100}})
+
+	b.Record("node-1", "agent", 99)
+	if err := b.Allow("node-1", "agent"); err != nil {
+		t.Fatalf("expected calls below the limit to pass, got %v", err)
+	}
+
+	b.Record("node-1", "agent", 1)
+	err := b.Allow("node-1", "agent")
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+	var exceeded *BudgetExceeded
+	if !errors.As(err, &exceeded) || exceeded.Scope != "project" || exceeded.Period != "day" || exceeded.Used != 100 {
+		t.Errorf("unexpected exceeded budget: %+v", exceeded)
+	}
+}
+
+func TestBudget_AgentLimitsFallBackToWildcard(t *testing.T) {
+	b, _ := newTestBudget(BudgetConfig{Agents: map[string]BudgetLimits{
+		"reviewer": {Daily: 1000},
+		"*":        {Daily: 10},
+	}})
+
+	b.Record("node-1", "reviewer", 50)
+	if err := b.Allow("node-1", "reviewer"); err != nil {
+		t.Errorf("expected the named limit to win over *, got %v", err)
+	}
+
+	b.Record("node-2", "builder", 50)
+	err := b.Allow("node-2", "builder")
+	var exceeded *BudgetExceeded
+	if !errors.As(err, &exceeded) || exceeded.Scope != "agent" || exceeded.AgentID != "node-2" {
+		t.Errorf("expected node-2 to hit the * limit, got %v", err)
+	}
+}
+
+func TestBudget_ThrottleLetsOneCallThroughPerInterval(t *testing.T) {
+	b, now := newTestBudget(BudgetConfig{
+		Project:          BudgetLimits{Daily: 10},
+		Action:           BudgetThrottle,
+		ThrottleInterval: time.Minute,
+	})
+	b.Record("node-1", "agent", 10)
+
+	if err := b.Allow("node-1", "agent"); err != nil {
+		t.Fatalf("expected the first throttled call to pass, got %v", err)
+	}
+	err := b.Allow("node-1", "agent")
+	var exceeded *BudgetExceeded
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("expected the second call to be throttled, got %v", err)
+	}
+	if want := now.Add(time.Minute); !exceeded.RetryAfter.Equal(want) {
+		t.Errorf("expected retry after %s, got %s", want, exceeded.RetryAfter)
+	}
+
+	*now = now.Add(time.Minute)
+	if err := b.Allow("node-1", "agent"); err != nil {
+		t.Errorf("expected a call after the interval to pass, got %v", err)
+	}
+}
+
+func TestBudget_RollsOverAtDayAndMonth(t *testing.T) {
+	b, now := newTestBudget(BudgetConfig{Project: BudgetLimits{Daily: 10, Monthly: 15}})
+	b.Record("node-1", "agent", 10)
+
+	*now = now.Add(12 * time.Hour) // April 1st
+	if err := b.Allow("node-1", "agent"); err != nil {
+		t.Errorf("expected a new month to reset both limits, got %v", err)
+	}
+
+	b.Record("node-1", "agent", 8)
+	*now = now.Add(24 * time.Hour)
+	b.Record("node-1", "agent", 8)
+	err := b.Allow("node-1", "agent")
+	var exceeded *BudgetExceeded
+	if !errors.As(err, &exceeded) || exceeded.Period != "month" || exceeded.Used != 16 {
+		t.Errorf("expected the monthly limit to carry over days, got %v", err)
+	}
+}
+
+func TestBudget_ReportsEachLimitOncePerPeriod(t *testing.T) {
+	b, now := newTestBudget(BudgetConfig{Project: BudgetLimits{Daily: 10}})
+	var reported []*BudgetExceeded
+	b.OnExceeded(func(e *BudgetExceeded) { reported = append(reported, e) })
+
+	b.Record("node-1", "agent", 10)
+	b.Record("node-1", "agent", 5)
+	if len(reported) != 1 {
+		t.Fatalf("expected one report, got %d", len(reported))
+	}
+
+	*now = now.Add(24 * time.Hour)
+	b.Record("node-1", "agent", 10)
+	if len(reported) != 2 {
+		t.Errorf("expected a new report the next day, got %d", len(reported))
+	}
+}
+
+func TestBudget_SeedDoesNotReport(t *testing.T) {
+	b, _ := newTestBudget(BudgetConfig{Project: BudgetLimits{Daily: 10}})
+	reported := 0
+	b.OnExceeded(func(*BudgetExceeded) { reported++ })
+
+	b.Seed("node-1", "agent", 20, 20)
+	b.Record("node-1", "agent", 1)
+	if reported != 0 {
+		t.Errorf("expected usage from a previous run to stay quiet, got %d reports", reported)
+	}
+	if err := b.Allow("node-1", "agent"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected seeded usage to count, got %v", err)
+	}
+}
+
+func TestTracker_AllowSkipsAdmittedContext(t *testing.T) {
+	tracker := NewTracker("node-1", "agent")
+	defer tracker.Close()
+	tracker.SetBudget(NewBudget(BudgetConfig{Project: BudgetLimits{Daily: 10}}))
+
+	if err := tracker.RecordEmbedding(10, "mock"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Allow(context.Background()); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected the spent budget to block, got %v", err)
+	}
+	if err := tracker.Allow(WithAdmission(context.Background())); err != nil {
+		t.Errorf("expected an admitted context to pass, got %v", err)
+	}
+}
+
+func TestBudgetConfig_Validate(t *testing.T) {
+	if err := (BudgetConfig{Action: "drop"}).Validate(); err == nil {
+		t.Error("expected an unknown action to fail")
+	}
+	if err := (BudgetConfig{Agents: map[string]BudgetLimits{"*": {Daily: -1}}}).Validate(); err == nil {
+		t.Error("expected a negative limit to fail")
+	}
+	if err := (BudgetConfig{Project: BudgetLimits{Monthly: 5}, Action: BudgetThrottle}).Validate(); err != nil {
+		t.Errorf("expected a valid config, got %v", err)
+	}
+}
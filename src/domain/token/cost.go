@@ -1,5 +1,7 @@
 package token
 
+import "strings"
+
 // Model pricing (cost per 1M tokens in USD)
 // Based on common LLM pricing as of 2024
 var modelPricing = map[string]float64{
@@ -21,26 +23,36 @@ var modelPricing = map[string]float64{
 	"text-embedding-3-large": 0.13,
 	"text-embedding-ada-002": 0.10,
 
+	// Local embedding models (Ollama) and the mock provider cost nothing
+	"nomic-embed-text":       0,
+	"mxbai-embed-large":      0,
+	"all-minilm":             0,
+	"snowflake-arctic-embed": 0,
+	"mock-embedding":         0,
+
 	// Default fallback
 	"default": 1.0,
 }
 
 // EstimateCost estimates the cost for a given number of tokens and model.
 func EstimateCost(tokens int64, model string) float64 {
-	price, ok := modelPricing[model]
-	if !ok {
-		price = modelPricing["default"]
-	}
+	price := GetModelPrice(model)
 
 	// Cost = tokens * (price per 1M tokens) / 1,000,000
 	return float64(tokens) * price / 1_000_000
 }
 
 // GetModelPrice returns the price per 1M tokens for a model.
+// Ollama tags such as "nomic-embed-text:latest" are priced by base name.
 func GetModelPrice(model string) float64 {
 	if price, ok := modelPricing[model]; ok {
 		return price
 	}
+	if base, _, ok := strings.Cut(model, ":"); ok {
+		if price, ok := modelPricing[base]; ok {
+			return price
+		}
+	}
 	return modelPricing["default"]
 }
 
@@ -1,6 +1,8 @@
 package token
 
 import (
+	"cmp"
+	"slices"
 	"sync"
 	"time"
 )
@@ -13,17 +15,33 @@ const (
 	CategorySync        UsageCategory = "sync"
 	CategoryNegotiation UsageCategory = "negotiation"
 	CategoryQuery       UsageCategory = "query"
-	CategoryOther       UsageCategory = "other"
+	// CategorySummarization counts model tokens spent condensing
+	// documents before they are embedded.
+	CategorySummarization UsageCategory = "summarization"
+	CategoryOther         UsageCategory = "other"
 )
 
 // UsageRecord represents a single token usage event.
 type UsageRecord struct {
-	ID        string         `json:"id"`
-	Category  UsageCategory  `json:"category"`
-	Tokens    int64          `json:"tokens"`
-	Model     string         `json:"model"`
-	Timestamp time.Time      `json:"timestamp"`
-	Metadata  map[string]any `json:"metadata,omitempty"`
+	ID       string        `json:"id"`
+	Category UsageCategory `json:"category"`
+	// Tokens is the total, including OutputTokens
+	Tokens       int64          `json:"tokens"`
+	OutputTokens int64          `json:"output_tokens,omitempty"`
+	Provider     string         `json:"provider,omitempty"`
+	Model        string         `json:"model"`
+	Cost         float64        `json:"cost,omitempty"`
+	Timestamp    time.Time      `json:"timestamp"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+}
+
+// ModelUsage is the usage and cost of one model today.
+type ModelUsage struct {
+	Provider     string  `json:"provider,omitempty"`
+	Model        string  `json:"model"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	Cost         float64 `json:"cost"`
 }
 
 // HourlyBucket aggregates usage for one hour.
@@ -45,6 +63,9 @@ type UsageMetrics struct {
 	// Breakdown by category
 	ByCategory map[UsageCategory]int64 `json:"by_category"`
 
+	// Breakdown by provider and model today, keyed "provider/model"
+	ByModel map[string]*ModelUsage `json:"by_model,omitempty"`
+
 	// Hourly data for trends (last 24 hours)
 	HourlyData []*HourlyBucket `json:"hourly_data"`
 
@@ -59,6 +80,11 @@ type UsageMetrics struct {
 	// Limits
 	DailyLimit int64 `json:"daily_limit"`
 
+	// Work dropped under overload today, and its estimated token cost
+	ShedToday       int64                   `json:"shed_today"`
+	ShedTokensToday int64                   `json:"shed_tokens_today"`
+	ShedByCategory  map[UsageCategory]int64 `json:"shed_by_category,omitempty"`
+
 	// Last updated
 	LastUpdated time.Time `json:"last_updated"`
 }
@@ -66,9 +92,11 @@ type UsageMetrics struct {
 // NewUsageMetrics creates a new UsageMetrics instance.
 func NewUsageMetrics() *UsageMetrics {
 	return &UsageMetrics{
-		ByCategory: make(map[UsageCategory]int64),
-		HourlyData: make([]*HourlyBucket, 0, 24),
-		DailyLimit: 200000, // Default 200K tokens per day
+		ByCategory:     make(map[UsageCategory]int64),
+		ByModel:        make(map[string]*ModelUsage),
+		HourlyData:     make([]*HourlyBucket, 0, 24),
+		DailyLimit:     200000, // Default 200K tokens per day
+		ShedByCategory: make(map[UsageCategory]int64),
 	}
 }
 
@@ -103,6 +131,24 @@ func (m *UsageMetrics) GetBreakdown() []CategoryBreakdown {
 	return breakdown
 }
 
+// ModelBreakdown returns today's usage per model, most expensive first.
+func (m *UsageMetrics) ModelBreakdown() []ModelUsage {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	breakdown := make([]ModelUsage, 0, len(m.ByModel))
+	for _, usage := range m.ByModel {
+		breakdown = append(breakdown, *usage)
+	}
+	slices.SortFunc(breakdown, func(a, b ModelUsage) int {
+		if c := cmp.Compare(b.Cost, a.Cost); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.InputTokens+b.OutputTokens, a.InputTokens+a.OutputTokens)
+	})
+	return breakdown
+}
+
 // GetHourlyTrend returns hourly token counts for charting.
 func (m *UsageMetrics) GetHourlyTrend() []float64 {
 	m.mu.RLock()
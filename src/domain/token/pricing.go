@@ -0,0 +1,103 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ModelPrice is what a model costs in USD per 1M tokens.
+type ModelPrice struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output,omitempty"`
+}
+
+// Cost returns the cost of input and output tokens at this price.
+func (p ModelPrice) Cost(input, output int64) float64 {
+	return (float64(input)*p.Input + float64(output)*p.Output) / 1_000_000
+}
+
+// Pricing maps models to their prices. Keys are model names, optionally
+// qualified by provider ("openai/text-embedding-3-small") to price the
+// same model differently per provider; "default" prices unlisted models.
+type Pricing map[string]ModelPrice
+
+// DefaultPricing returns the built-in prices, which charge output tokens
+// at the input rate.
+func DefaultPricing() Pricing {
+	pricing := make(Pricing, len(modelPricing))
+	for model, price := range modelPricing {
+		pricing[model] = ModelPrice{Input: price, Output: price}
+	}
+	return pricing
+}
+
+// ParsePricing parses a pricing table from JSON, e.g.
+// {"gpt-4o": {"input": 2.5, "output": 10}}.
+func ParsePricing(data []byte) (Pricing, error) {
+	var pricing Pricing
+	if err := json.Unmarshal(data, &pricing); err != nil {
+		return nil, fmt.Errorf("invalid pricing table: %w", err)
+	}
+	if err := pricing.Validate(); err != nil {
+		return nil, err
+	}
+	return pricing, nil
+}
+
+// Validate rejects negative prices.
+func (p Pricing) Validate() error {
+	for model, price := range p {
+		if model == "" {
+			return fmt.Errorf("invalid pricing table: empty model name")
+		}
+		if price.Input < 0 || price.Output < 0 {
+			return fmt.Errorf("invalid price for %s: must not be negative", model)
+		}
+	}
+	return nil
+}
+
+// Merge returns a copy of p with the prices of overrides taking precedence.
+func (p Pricing) Merge(overrides Pricing) Pricing {
+	merged := make(Pricing, len(p)+len(overrides))
+	for model, price := range p {
+		merged[model] = price
+	}
+	for model, price := range overrides {
+		merged[model] = price
+	}
+	return merged
+}
+
+// Lookup returns the price of a model served by provider, which may be
+// empty. It tries provider/model, then model, each also without an Ollama
+// style ":tag", and falls back to "default".
+func (p Pricing) Lookup(provider, model string) ModelPrice {
+	base, _, tagged := strings.Cut(model, ":")
+	var keys []string
+	if provider != "" {
+		keys = append(keys, provider+"/"+model)
+		if tagged {
+			keys = append(keys, provider+"/"+base)
+		}
+	}
+	keys = append(keys, model)
+	if tagged {
+		keys = append(keys, base)
+	}
+	for _, key := range keys {
+		if price, ok := p[key]; ok {
+			return price
+		}
+	}
+	if price, ok := p["default"]; ok {
+		return price
+	}
+	return ModelPrice{Input: modelPricing["default"], Output: modelPricing["default"]}
+}
+
+// Cost returns the cost of input and output tokens of a model.
+func (p Pricing) Cost(provider, model string, input, output int64) float64 {
+	return p.Lookup(provider, model).Cost(input, output)
+}
@@ -0,0 +1,87 @@
+package token
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPricing_LookupPrefersProviderThenModelThenDefault(t *testing.T) {
+	pricing := DefaultPricing().Merge(Pricing{
+		"gpt-4o":                        {Input: 2.5, Output: 10},
+		"azure/gpt-4o":                  {Input: 3, Output: 12},
+		"nomic-embed-text":              {Input: 0},
+		"default":                       {Input: 0.5, Output: 0.5},
+		"openai/text-embedding-3-large": {Input: 0.2},
+	})
+
+	tests := []struct {
+		provider, model string
+		want            ModelPrice
+	}{
+		{"azure", "gpt-4o", ModelPrice{Input: 3, Output: 12}},
+		{"openai", "gpt-4o", ModelPrice{Input: 2.5, Output: 10}},
+		{"", "gpt-4o", ModelPrice{Input: 2.5, Output: 10}},
+		{"ollama", "nomic-embed-text:latest", ModelPrice{}},
+		{"openai", "text-embedding-3-small", ModelPrice{Input: 0.02, Output: 0.02}},
+		{"openai", "text-embedding-3-large", ModelPrice{Input: 0.2}},
+		{"", "unknown-model", ModelPrice{Input: 0.5, Output: 0.5}},
+	}
+	for _, tt := range tests {
+		if got := pricing.Lookup(tt.provider, tt.model); got != tt.want {
+			t.Errorf("Lookup(%q, %q) = %+v, want %+v", tt.provider, tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestParsePricing_RejectsNegativePrices(t *testing.T) {
+	if _, err := ParsePricing([]byte(`{"gpt-4o": {"input": 2.5, "output": -1}}`)); err == nil {
+		t.Error("expected a negative price to be rejected")
+	}
+	if _, err := ParsePricing([]byte(`["gpt-4o"]`)); err == nil {
+		t.Error("expected a malformed table to be rejected")
+	}
+	pricing, err := ParsePricing([]byte(`{"gpt-4o": {"input": 2.5, "output": 10}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pricing.Cost("", "gpt-4o", 1_000_000, 100_000); math.Abs(got-3.5) > 1e-9 {
+		t.Errorf("expected $3.50, got %v", got)
+	}
+}
+
+func TestTracker_RecordUsageBreaksDownCostByModel(t *testing.T) {
+	tracker := NewTracker("node-1", "agent")
+	defer tracker.Close()
+	tracker.SetPricing(Pricing{
+		"gpt-4o":  {Input: 2.5, Output: 10},
+		"embed":   {Input: 0.1},
+		"default": {Input: 1, Output: 1},
+	})
+
+	if err := tracker.RecordUsage(CategorySync, "openai", "gpt-4o", 1_000_000, 100_000, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.RecordUsage(CategoryEmbedding, "ollama", "embed", 2_000_000, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Record(CategoryEmbedding, 1_000_000, "embed", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	metrics := tracker.GetMetrics()
+	if metrics.TokensToday != 4_100_000 {
+		t.Errorf("expected 4.1M tokens today, got %d", metrics.TokensToday)
+	}
+	if math.Abs(metrics.CostToday-3.8) > 1e-9 {
+		t.Errorf("expected $3.80 today, got %v", metrics.CostToday)
+	}
+
+	breakdown := metrics.ModelBreakdown()
+	if len(breakdown) != 3 {
+		t.Fatalf("expected 3 models, got %+v", breakdown)
+	}
+	first := breakdown[0]
+	if first.Provider != "openai" || first.Model != "gpt-4o" || first.InputTokens != 1_000_000 || first.OutputTokens != 100_000 || math.Abs(first.Cost-3.5) > 1e-9 {
+		t.Errorf("expected gpt-4o first, got %+v", first)
+	}
+}
@@ -29,6 +29,12 @@ type Tracker struct {
 	// Persistence callback
 	persistFn func(*UsageRecord) error
 
+	// Hard limits, if configured
+	budget *Budget
+
+	// Prices used to cost recorded usage
+	pricing Pricing
+
 	// Background cleanup
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -42,6 +48,7 @@ func NewTracker(nodeID, nodeName string) *Tracker {
 		nodeID:     nodeID,
 		nodeName:   nodeName,
 		metrics:    NewUsageMetrics(),
+		pricing:    DefaultPricing(),
 		records:    make([]*UsageRecord, 1000),
 		maxRecords: 1000,
 		ctx:        ctx,
@@ -72,19 +79,109 @@ func (t *Tracker) SetPersistFn(fn func(*UsageRecord) error) {
 	t.persistFn = fn
 }
 
-// Record records a token usage event.
+// SetBudget enforces hard token limits on this node's usage. Pass nil to
+// remove them.
+func (t *Tracker) SetBudget(budget *Budget) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.budget = budget
+}
+
+// Budget returns the configured budget, or nil.
+func (t *Tracker) Budget() *Budget {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.budget
+}
+
+// Allow returns an error wrapping ErrBudgetExceeded when the budget
+// blocks this node's next token-consuming call. Calls made under a context
+// from WithAdmission are not checked again.
+func (t *Tracker) Allow(ctx context.Context) error {
+	budget := t.Budget()
+	if budget == nil || admitted(ctx) {
+		return nil
+	}
+	return budget.Allow(t.nodeID, t.nodeName)
+}
+
+type admissionKey struct{}
+
+// WithAdmission marks ctx as let through by the budget, so an operation
+// making several token-consuming calls, such as sharing context, is
+// checked and throttled once rather than per call.
+func WithAdmission(ctx context.Context) context.Context {
+	return context.WithValue(ctx, admissionKey{}, true)
+}
+
+func admitted(ctx context.Context) bool {
+	ok, _ := ctx.Value(admissionKey{}).(bool)
+	return ok
+}
+
+// BudgetExceeded returns the limits this node has spent in the current
+// period.
+func (t *Tracker) BudgetExceeded() []*BudgetExceeded {
+	budget := t.Budget()
+	if budget == nil {
+		return nil
+	}
+	return budget.Exceeded(t.nodeID, t.nodeName)
+}
+
+// SeedBudget counts usage persisted before the budget was set, e.g. by a
+// previous run, towards this node's budget.
+func (t *Tracker) SeedBudget(today, thisMonth int64) {
+	if budget := t.Budget(); budget != nil {
+		budget.Seed(t.nodeID, t.nodeName, today, thisMonth)
+	}
+}
+
+// SetPricing sets the prices used to cost usage recorded from now on. Pass
+// nil to go back to the built-in prices.
+func (t *Tracker) SetPricing(pricing Pricing) {
+	if pricing == nil {
+		pricing = DefaultPricing()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pricing = pricing
+}
+
+// Pricing returns the prices in use.
+func (t *Tracker) Pricing() Pricing {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.pricing
+}
+
+// Record records a token usage event. The tokens are costed as input.
 func (t *Tracker) Record(category UsageCategory, tokens int64, model string, metadata map[string]any) error {
+	return t.RecordUsage(category, "", model, tokens, 0, metadata)
+}
+
+// RecordUsage records input and output tokens of a model served by
+// provider, which may be empty, costing them at the model's rates.
+func (t *Tracker) RecordUsage(category UsageCategory, provider, model string, input, output int64, metadata map[string]any) error {
+	tokens := input + output
+	if budget := t.Budget(); budget != nil {
+		budget.Record(t.nodeID, t.nodeName, tokens)
+	}
+
 	record := &UsageRecord{
-		ID:        generateRecordID(),
-		Category:  category,
-		Tokens:    tokens,
-		Model:     model,
-		Timestamp: time.Now(),
-		Metadata:  metadata,
+		ID:           generateRecordID(),
+		Category:     category,
+		Tokens:       tokens,
+		OutputTokens: output,
+		Provider:     provider,
+		Model:        model,
+		Timestamp:    time.Now(),
+		Metadata:     metadata,
 	}
 
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	record.Cost = t.pricing.Cost(provider, model, input, output)
 
 	// Add to ring buffer
 	t.records[t.recordsHead] = record
@@ -98,10 +195,19 @@ func (t *Tracker) Record(category UsageCategory, tokens int64, model string, met
 	t.metrics.LastUpdated = time.Now()
 
 	// Update costs
-	cost := EstimateCost(tokens, model)
-	t.metrics.CostToday += cost
-	t.metrics.CostWeek += cost
-	t.metrics.CostMonth += cost
+	t.metrics.CostToday += record.Cost
+	t.metrics.CostWeek += record.Cost
+	t.metrics.CostMonth += record.Cost
+
+	key := provider + "/" + model
+	usage, ok := t.metrics.ByModel[key]
+	if !ok {
+		usage = &ModelUsage{Provider: provider, Model: model}
+		t.metrics.ByModel[key] = usage
+	}
+	usage.InputTokens += input
+	usage.OutputTokens += output
+	usage.Cost += record.Cost
 
 	// Update hourly bucket
 	now := time.Now()
@@ -151,6 +257,18 @@ func (t *Tracker) RecordQuery(tokens int64, model string) error {
 	return t.Record(CategoryQuery, tokens, model, nil)
 }
 
+// RecordShed records work dropped under overload. The estimated tokens
+// were not spent, so they count towards shed totals rather than usage.
+func (t *Tracker) RecordShed(category UsageCategory, estimatedTokens int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.metrics.ShedToday++
+	t.metrics.ShedTokensToday += estimatedTokens
+	t.metrics.ShedByCategory[category]++
+	t.metrics.LastUpdated = time.Now()
+}
+
 // GetMetrics returns a copy of current metrics.
 func (t *Tracker) GetMetrics() *UsageMetrics {
 	t.mu.RLock()
@@ -158,22 +276,33 @@ func (t *Tracker) GetMetrics() *UsageMetrics {
 
 	// Create a copy
 	copy := &UsageMetrics{
-		TokensToday:   t.metrics.TokensToday,
-		TokensWeek:    t.metrics.TokensWeek,
-		TokensMonth:   t.metrics.TokensMonth,
-		CostToday:     t.metrics.CostToday,
-		CostWeek:      t.metrics.CostWeek,
-		CostMonth:     t.metrics.CostMonth,
-		TokensPerHour: t.metrics.TokensPerHour,
-		DailyLimit:    t.metrics.DailyLimit,
-		LastUpdated:   t.metrics.LastUpdated,
-		ByCategory:    make(map[UsageCategory]int64),
-		HourlyData:    make([]*HourlyBucket, len(t.metrics.HourlyData)),
+		TokensToday:     t.metrics.TokensToday,
+		TokensWeek:      t.metrics.TokensWeek,
+		TokensMonth:     t.metrics.TokensMonth,
+		CostToday:       t.metrics.CostToday,
+		CostWeek:        t.metrics.CostWeek,
+		CostMonth:       t.metrics.CostMonth,
+		TokensPerHour:   t.metrics.TokensPerHour,
+		DailyLimit:      t.metrics.DailyLimit,
+		LastUpdated:     t.metrics.LastUpdated,
+		ShedToday:       t.metrics.ShedToday,
+		ShedTokensToday: t.metrics.ShedTokensToday,
+		ByCategory:      make(map[UsageCategory]int64),
+		ByModel:         make(map[string]*ModelUsage),
+		ShedByCategory:  make(map[UsageCategory]int64),
+		HourlyData:      make([]*HourlyBucket, len(t.metrics.HourlyData)),
 	}
 
 	for k, v := range t.metrics.ByCategory {
 		copy.ByCategory[k] = v
 	}
+	for k, v := range t.metrics.ByModel {
+		usage := *v
+		copy.ByModel[k] = &usage
+	}
+	for k, v := range t.metrics.ShedByCategory {
+		copy.ShedByCategory[k] = v
+	}
 
 	for i, bucket := range t.metrics.HourlyData {
 		bucketCopy := &HourlyBucket{
@@ -227,6 +356,10 @@ func (t *Tracker) Reset(period string) {
 		t.metrics.TokensToday = 0
 		t.metrics.CostToday = 0
 		t.metrics.ByCategory = make(map[UsageCategory]int64)
+		t.metrics.ByModel = make(map[string]*ModelUsage)
+		t.metrics.ShedToday = 0
+		t.metrics.ShedTokensToday = 0
+		t.metrics.ShedByCategory = make(map[UsageCategory]int64)
 	case "week":
 		t.metrics.TokensWeek = 0
 		t.metrics.CostWeek = 0
@@ -274,6 +407,10 @@ func (t *Tracker) aggregationLoop() {
 				t.metrics.TokensToday = 0
 				t.metrics.CostToday = 0
 				t.metrics.ByCategory = make(map[UsageCategory]int64)
+				t.metrics.ByModel = make(map[string]*ModelUsage)
+				t.metrics.ShedToday = 0
+				t.metrics.ShedTokensToday = 0
+				t.metrics.ShedByCategory = make(map[UsageCategory]int64)
 
 				// Reset weekly on Monday
 				if now.Weekday() == time.Monday {
@@ -0,0 +1,110 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// ErrInvalidGrant is returned when a role grant or its proof does not verify.
+var ErrInvalidGrant = errors.New("invalid role grant")
+
+// RoleGrant assigns a role in a cluster. It is signed by the cluster's
+// founding node and names a grant key; a node shows it holds the role by
+// signing its own peer ID with that key, so a grant copied off the wire
+// cannot be replayed by another node.
+type RoleGrant struct {
+	Project   string `json:"project"`
+	Role      string `json:"role"`
+	Issuer    string `json:"issuer"`
+	Key       []byte `json:"key"`
+	IssuedAt  int64  `json:"issued"`
+	Signature []byte `json:"sig,omitempty"`
+}
+
+// RoleCredential is a role grant together with its private grant key.
+// Invite tokens carry it to the joining node, which keeps it in its config.
+type RoleCredential struct {
+	Grant *RoleGrant `json:"grant"`
+	Key   []byte     `json:"key"`
+}
+
+// IssueRoleGrant creates a credential for role in project, signed by the
+// founding node's key.
+func IssueRoleGrant(issuer *KeyPair, project, role string) (*RoleCredential, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate grant key: %w", err)
+	}
+
+	grant := &RoleGrant{
+		Project:  project,
+		Role:     role,
+		Issuer:   issuer.PeerID.String(),
+		Key:      pub,
+		IssuedAt: time.Now().Unix(),
+	}
+	data, err := grant.signedBytes()
+	if err != nil {
+		return nil, err
+	}
+	if grant.Signature, err = issuer.PrivateKey.Sign(data); err != nil {
+		return nil, fmt.Errorf("failed to sign grant: %w", err)
+	}
+	return &RoleCredential{Grant: grant, Key: priv}, nil
+}
+
+// Prove signs peerID with the grant key, binding the grant to that node.
+func (c *RoleCredential) Prove(peerID string) ([]byte, error) {
+	if len(c.Key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("%w: malformed grant key", ErrInvalidGrant)
+	}
+	return ed25519.Sign(ed25519.PrivateKey(c.Key), []byte(peerID)), nil
+}
+
+// Verify checks that the grant was issued by root, the cluster's founding node.
+func (g *RoleGrant) Verify(root string) error {
+	if root == "" || g.Issuer != root {
+		return fmt.Errorf("%w: not issued by the cluster's founding node", ErrInvalidGrant)
+	}
+	issuer, err := peer.Decode(g.Issuer)
+	if err != nil {
+		return fmt.Errorf("%w: bad issuer: %v", ErrInvalidGrant, err)
+	}
+	pub, err := issuer.ExtractPublicKey()
+	if err != nil {
+		return fmt.Errorf("%w: bad issuer: %v", ErrInvalidGrant, err)
+	}
+	data, err := g.signedBytes()
+	if err != nil {
+		return err
+	}
+	if ok, err := pub.Verify(data, g.Signature); err != nil || !ok {
+		return fmt.Errorf("%w: bad signature", ErrInvalidGrant)
+	}
+	return nil
+}
+
+// VerifyHolder checks that proof binds the grant to peerID.
+func (g *RoleGrant) VerifyHolder(peerID string, proof []byte) error {
+	if len(g.Key) != ed25519.PublicKeySize || !ed25519.Verify(ed25519.PublicKey(g.Key), []byte(peerID), proof) {
+		return fmt.Errorf("%w: not held by %s", ErrInvalidGrant, peerID)
+	}
+	return nil
+}
+
+// signedBytes returns the grant as signed by the issuer.
+func (g *RoleGrant) signedBytes() ([]byte, error) {
+	unsigned := *g
+	unsigned.Signature = nil
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal grant: %w", err)
+	}
+	return data, nil
+}
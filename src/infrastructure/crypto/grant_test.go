@@ -0,0 +1,58 @@
+package crypto_test
+
+import (
+	"errors"
+	"testing"
+
+	"agent-collab/src/infrastructure/crypto"
+)
+
+func TestRoleGrant_Verify(t *testing.T) {
+	root, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cred, err := crypto.IssueRoleGrant(root, "proj", "admin")
+	if err != nil {
+		t.Fatalf("Failed to issue grant: %v", err)
+	}
+	if err := cred.Grant.Verify(root.PeerID.String()); err != nil {
+		t.Errorf("expected the grant to verify against its issuer, got %v", err)
+	}
+	if err := cred.Grant.Verify(other.PeerID.String()); !errors.Is(err, crypto.ErrInvalidGrant) {
+		t.Errorf("expected a grant from another root to be rejected, got %v", err)
+	}
+
+	tampered := *cred.Grant
+	tampered.Role = "observer"
+	if err := tampered.Verify(root.PeerID.String()); !errors.Is(err, crypto.ErrInvalidGrant) {
+		t.Errorf("expected a tampered grant to be rejected, got %v", err)
+	}
+}
+
+func TestRoleGrant_VerifyHolder(t *testing.T) {
+	root, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := crypto.IssueRoleGrant(root, "proj", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proof, err := cred.Prove("peer-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cred.Grant.VerifyHolder("peer-a", proof); err != nil {
+		t.Errorf("expected the proof to bind the grant to peer-a, got %v", err)
+	}
+	if err := cred.Grant.VerifyHolder("peer-b", proof); !errors.Is(err, crypto.ErrInvalidGrant) {
+		t.Errorf("expected a replayed proof to be rejected, got %v", err)
+	}
+}
@@ -76,12 +76,14 @@ func DecodeToken(encoded string) (*InviteToken, error) {
 	return &token, nil
 }
 
-// IsExpired는 토큰이 만료되었는지 확인합니다.
+// IsExpired는 DefaultClockSkew를 허용하여 토큰이 만료되었는지 확인합니다.
 func (t *InviteToken) IsExpired() bool {
-	if t.ExpiresAt == 0 {
-		return false // 만료 없음
-	}
-	return time.Now().Unix() > t.ExpiresAt
+	return t.ExpiredAt(time.Now(), DefaultClockSkew)
+}
+
+// ExpiredAt은 now 기준으로 skew만큼의 시계 오차를 허용하여 만료 여부를 확인합니다.
+func (t *InviteToken) ExpiredAt(now time.Time, skew time.Duration) bool {
+	return expiredAt(t.ExpiresAt, now, skew)
 }
 
 // SetExpiry는 만료 시간을 설정합니다.
@@ -101,6 +103,22 @@ func generateRandomID(length int) (string, error) {
 // DefaultTokenTTL is the default token expiration duration.
 const DefaultTokenTTL = 24 * time.Hour
 
+// DefaultClockSkew is how far past its expiry a token is still accepted,
+// so small clock differences between the creating and joining nodes do
+// not reject fresh tokens.
+const DefaultClockSkew = 30 * time.Second
+
+// expiredAt reports whether a token expiring at expiresAt (Unix seconds,
+// 0 = never) has expired at now, allowing skew of clock difference.
+// Expiry comes from another node's wall clock, so there is no monotonic
+// reading to compare against; the skew window absorbs the difference.
+func expiredAt(expiresAt int64, now time.Time, skew time.Duration) bool {
+	if expiresAt == 0 {
+		return false
+	}
+	return now.After(time.Unix(expiresAt, 0).Add(max(skew, 0)))
+}
+
 // SimpleInviteToken is a simple invite token.
 type SimpleInviteToken struct {
 	Addresses   []string `json:"addrs"`
@@ -108,6 +126,14 @@ type SimpleInviteToken struct {
 	CreatorID   string   `json:"creator"`
 	CreatedAt   int64    `json:"created"`
 	ExpiresAt   int64    `json:"expires,omitempty"`
+	// Role is the role assigned to the joining node: admin, agent or
+	// observer. Empty means agent. Admin is honoured only with a Grant.
+	Role string `json:"role,omitempty"`
+	// Root is the cluster's founding node, which signs role grants.
+	// Empty means CreatorID.
+	Root string `json:"root,omitempty"`
+	// Grant binds an admin role to the token, signed by Root.
+	Grant *RoleCredential `json:"grant,omitempty"`
 }
 
 // NewInviteToken creates a new simple invite token with default expiration.
@@ -134,12 +160,14 @@ func NewInviteTokenWithTTL(addresses []string, projectName, creatorID string, tt
 	}, nil
 }
 
-// IsExpired checks if the token has expired.
+// IsExpired checks if the token has expired, allowing DefaultClockSkew.
 func (t *SimpleInviteToken) IsExpired() bool {
-	if t.ExpiresAt == 0 {
-		return false // No expiration set
-	}
-	return time.Now().Unix() > t.ExpiresAt
+	return t.ExpiredAt(time.Now(), DefaultClockSkew)
+}
+
+// ExpiredAt checks if the token has expired at now, allowing skew.
+func (t *SimpleInviteToken) ExpiredAt(now time.Time, skew time.Duration) bool {
+	return expiredAt(t.ExpiresAt, now, skew)
 }
 
 // Encode encodes the token to a base64 string.
@@ -185,6 +213,10 @@ type WireGuardToken struct {
 	CreatorID   string   `json:"creator"`
 	CreatedAt   int64    `json:"created"`
 	ExpiresAt   int64    `json:"expires,omitempty"`
+	Role        string   `json:"role,omitempty"`
+	Root        string   `json:"root,omitempty"`
+
+	Grant *RoleCredential `json:"grant,omitempty"`
 
 	// WireGuard extension
 	WireGuard *WireGuardInfo `json:"wg,omitempty"`
@@ -230,12 +262,14 @@ func (t *WireGuardToken) HasWireGuard() bool {
 	return t.WireGuard != nil && t.WireGuard.CreatorPublicKey != ""
 }
 
-// IsExpired checks if the token has expired.
+// IsExpired checks if the token has expired, allowing DefaultClockSkew.
 func (t *WireGuardToken) IsExpired() bool {
-	if t.ExpiresAt == 0 {
-		return false
-	}
-	return time.Now().Unix() > t.ExpiresAt
+	return t.ExpiredAt(time.Now(), DefaultClockSkew)
+}
+
+// ExpiredAt checks if the token has expired at now, allowing skew.
+func (t *WireGuardToken) ExpiredAt(now time.Time, skew time.Duration) bool {
+	return expiredAt(t.ExpiresAt, now, skew)
 }
 
 // Encode encodes the token to a base64 string.
@@ -255,6 +289,9 @@ func (t *WireGuardToken) ToSimpleToken() *SimpleInviteToken {
 		CreatorID:   t.CreatorID,
 		CreatedAt:   t.CreatedAt,
 		ExpiresAt:   t.ExpiresAt,
+		Role:        t.Role,
+		Root:        t.Root,
+		Grant:       t.Grant,
 	}
 }
 
@@ -273,6 +310,14 @@ func DecodeWireGuardToken(encoded string) (*WireGuardToken, error) {
 	return &token, nil
 }
 
+// RootID returns the cluster's founding node.
+func (t *WireGuardToken) RootID() string {
+	if t.Root != "" {
+		return t.Root
+	}
+	return t.CreatorID
+}
+
 // DecodeAnyToken attempts to decode a token as WireGuardToken first,
 // then falls back to SimpleInviteToken if no WireGuard info is present.
 // Returns the token and a boolean indicating if it has WireGuard support.
@@ -291,6 +336,9 @@ func DecodeAnyToken(encoded string) (*WireGuardToken, bool, error) {
 			CreatorID:   simpleToken.CreatorID,
 			CreatedAt:   simpleToken.CreatedAt,
 			ExpiresAt:   simpleToken.ExpiresAt,
+			Role:        simpleToken.Role,
+			Root:        simpleToken.Root,
+			Grant:       simpleToken.Grant,
 			WireGuard:   nil,
 		}, false, nil
 	}
@@ -101,6 +101,37 @@ func TestSimpleInviteToken_Expiration(t *testing.T) {
 	}
 }
 
+func TestInviteTokens_ClockSkew(t *testing.T) {
+	expiresAt := time.Now().Truncate(time.Second)
+	simple := &crypto.SimpleInviteToken{ExpiresAt: expiresAt.Unix()}
+	wg := &crypto.WireGuardToken{ExpiresAt: expiresAt.Unix()}
+	invite := &crypto.InviteToken{ExpiresAt: expiresAt.Unix()}
+
+	tokens := map[string]interface {
+		ExpiredAt(now time.Time, skew time.Duration) bool
+	}{"simple": simple, "wireguard": wg, "invite": invite}
+
+	for name, tok := range tokens {
+		// A node whose clock runs 20s ahead still accepts the token
+		if tok.ExpiredAt(expiresAt.Add(20*time.Second), 30*time.Second) {
+			t.Errorf("%s: token within the skew window should be accepted", name)
+		}
+		// Beyond the window it is rejected
+		if !tok.ExpiredAt(expiresAt.Add(31*time.Second), 30*time.Second) {
+			t.Errorf("%s: token beyond the skew window should be rejected", name)
+		}
+		// Without skew the expiry is exact
+		if !tok.ExpiredAt(expiresAt.Add(time.Second), 0) {
+			t.Errorf("%s: token past expiry should be rejected without skew", name)
+		}
+	}
+
+	// Tokens without an expiry never expire
+	if (&crypto.SimpleInviteToken{}).ExpiredAt(time.Now().Add(1000*time.Hour), 0) {
+		t.Error("token without expiry should never expire")
+	}
+}
+
 func TestWireGuardToken_EncodeDecode(t *testing.T) {
 	addresses := []string{"/ip4/127.0.0.1/tcp/4001"}
 	projectName := "wg-test-project"
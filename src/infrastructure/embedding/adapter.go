@@ -21,6 +21,11 @@ func (a *PortsAdapter) Embed(ctx context.Context, text string) ([]float32, error
 	return a.service.Embed(ctx, text)
 }
 
+// EmbedQuery generates an embedding for a search query.
+func (a *PortsAdapter) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	return a.service.EmbedQuery(ctx, text)
+}
+
 // EmbedBatch generates embeddings for multiple texts.
 func (a *PortsAdapter) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	return a.service.EmbedBatch(ctx, texts)
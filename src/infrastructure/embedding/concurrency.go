@@ -0,0 +1,214 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"agent-collab/src/domain/token"
+)
+
+// Default embedding concurrency bounds.
+const (
+	DefaultMaxInFlight = 8
+	DefaultMaxQueued   = 64
+)
+
+// OverloadPolicy decides what happens to embeddings beyond the in-flight
+// limit.
+type OverloadPolicy string
+
+const (
+	// OverloadQueue waits for a free slot, shedding only when MaxQueued
+	// calls are already waiting.
+	OverloadQueue OverloadPolicy = "queue"
+	// OverloadShed fails immediately when every slot is busy.
+	OverloadShed OverloadPolicy = "shed"
+)
+
+// ErrEmbeddingShed is returned when an embedding is dropped under overload.
+var ErrEmbeddingShed = errors.New("embedding shed: too many embeddings in flight")
+
+// ParseOverloadPolicy parses an overload policy name. Empty means queue.
+func ParseOverloadPolicy(s string) (OverloadPolicy, error) {
+	switch OverloadPolicy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", OverloadQueue:
+		return OverloadQueue, nil
+	case OverloadShed:
+		return OverloadShed, nil
+	default:
+		return "", fmt.Errorf("unknown embedding overload policy %q (want queue or shed)", s)
+	}
+}
+
+// ConcurrencyLimit bounds concurrent provider calls.
+type ConcurrencyLimit struct {
+	// MaxInFlight is the number of concurrent provider calls. 0 is unbounded.
+	MaxInFlight int            `json:"max_in_flight"`
+	Policy      OverloadPolicy `json:"policy"`
+	// MaxQueued caps waiting calls under the queue policy. 0 is unbounded.
+	MaxQueued int `json:"max_queued"`
+}
+
+// DefaultConcurrencyLimit returns the default embedding concurrency bounds.
+func DefaultConcurrencyLimit() ConcurrencyLimit {
+	return ConcurrencyLimit{
+		MaxInFlight: DefaultMaxInFlight,
+		Policy:      OverloadQueue,
+		MaxQueued:   DefaultMaxQueued,
+	}
+}
+
+// ConcurrencyStats reports in-flight, queued and shed embeddings.
+type ConcurrencyStats struct {
+	ConcurrencyLimit
+	Active     int   `json:"active"`
+	Queued     int   `json:"queued"`
+	PeakActive int   `json:"peak_active"`
+	Shed       int64 `json:"shed"`
+}
+
+// limiter is a semaphore over provider calls.
+type limiter struct {
+	mu     sync.Mutex
+	limit  ConcurrencyLimit
+	slots  chan struct{}
+	active int
+	queued int
+	peak   int
+	shed   int64
+}
+
+func newLimiter(limit ConcurrencyLimit) *limiter {
+	l := &limiter{}
+	l.set(limit)
+	return l
+}
+
+// set replaces the limit. Calls in flight finish against the old slots.
+func (l *limiter) set(limit ConcurrencyLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit.Policy == "" {
+		limit.Policy = OverloadQueue
+	}
+	l.limit = limit
+	l.slots = nil
+	if limit.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+}
+
+// acquire takes a slot, waiting or shedding per policy. The returned
+// function releases the slot.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	l.mu.Lock()
+	slots := l.slots
+	if slots == nil {
+		l.started()
+		l.mu.Unlock()
+		return l.done(nil), nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		l.started()
+		l.mu.Unlock()
+		return l.done(slots), nil
+	default:
+	}
+
+	if l.limit.Policy == OverloadShed || (l.limit.MaxQueued > 0 && l.queued >= l.limit.MaxQueued) {
+		l.shed++
+		l.mu.Unlock()
+		return nil, ErrEmbeddingShed
+	}
+	l.queued++
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		l.mu.Lock()
+		l.queued--
+		l.started()
+		l.mu.Unlock()
+		return l.done(slots), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.queued--
+		l.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// started counts a call taking a slot (must hold l.mu).
+func (l *limiter) started() {
+	l.active++
+	if l.active > l.peak {
+		l.peak = l.active
+	}
+}
+
+// done returns the release function for a slot.
+func (l *limiter) done(slots chan struct{}) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			l.active--
+			l.mu.Unlock()
+			if slots != nil {
+				<-slots
+			}
+		})
+	}
+}
+
+func (l *limiter) stats() ConcurrencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return ConcurrencyStats{
+		ConcurrencyLimit: l.limit,
+		Active:           l.active,
+		Queued:           l.queued,
+		PeakActive:       l.peak,
+		Shed:             l.shed,
+	}
+}
+
+// SetConcurrencyLimit changes the bound on concurrent provider calls.
+func (s *Service) SetConcurrencyLimit(limit ConcurrencyLimit) {
+	s.limiter.set(limit)
+}
+
+// ConcurrencyStats returns in-flight, queued and shed embedding counts.
+func (s *Service) ConcurrencyStats() ConcurrencyStats {
+	return s.limiter.stats()
+}
+
+// acquireSlot takes a provider slot for texts. Shed work is recorded with
+// the token tracker by its estimated size.
+func (s *Service) acquireSlot(ctx context.Context, texts []string) (func(), error) {
+	release, err := s.limiter.acquire(ctx)
+	if errors.Is(err, ErrEmbeddingShed) {
+		s.mu.RLock()
+		tracker := s.tokenTracker
+		s.mu.RUnlock()
+		if tracker != nil {
+			tracker.RecordShed(token.CategoryEmbedding, estimateTokens(texts))
+		}
+	}
+	return release, err
+}
+
+// estimateTokens approximates the token count of texts (~4 bytes a token).
+func estimateTokens(texts []string) int64 {
+	var n int64
+	for _, t := range texts {
+		n += int64(len(t)+3) / 4
+	}
+	return n
+}
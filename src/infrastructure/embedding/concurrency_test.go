@@ -0,0 +1,168 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"agent-collab/src/domain/token"
+)
+
+// slowProvider embeds after a delay and records peak concurrency.
+type slowProvider struct {
+	delay   time.Duration
+	current atomic.Int32
+	peak    atomic.Int32
+}
+
+func (p *slowProvider) Name() Provider { return ProviderMock }
+func (p *slowProvider) Dimension() int { return 4 }
+func (p *slowProvider) Model() string  { return "slow" }
+
+func (p *slowProvider) SupportsModel(model string) bool { return model == "slow" }
+
+func (p *slowProvider) Embed(ctx context.Context, texts []string) ([][]float32, int, error) {
+	n := p.current.Add(1)
+	defer p.current.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	time.Sleep(p.delay)
+	result := make([][]float32, len(texts))
+	for i := range texts {
+		result[i] = make([]float32, 4)
+	}
+	return result, len(texts), nil
+}
+
+// embedConcurrently embeds n distinct texts at once and returns the errors.
+func embedConcurrently(svc *Service, n int) []error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = svc.Embed(context.Background(), fmt.Sprintf("text %d", i))
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestService_ConcurrencyNeverExceedsBound(t *testing.T) {
+	provider := &slowProvider{delay: 20 * time.Millisecond}
+	svc := NewServiceWithProvider(provider)
+	svc.SetConcurrencyLimit(ConcurrencyLimit{MaxInFlight: 3, Policy: OverloadQueue})
+
+	for i, err := range embedConcurrently(svc, 20) {
+		if err != nil {
+			t.Errorf("embedding %d should have queued, got %v", i, err)
+		}
+	}
+
+	if peak := provider.peak.Load(); peak > 3 {
+		t.Errorf("provider saw %d concurrent calls, bound is 3", peak)
+	}
+	stats := svc.ConcurrencyStats()
+	if stats.PeakActive != 3 || stats.Active != 0 || stats.Queued != 0 || stats.Shed != 0 {
+		t.Errorf("unexpected stats after queued run: %+v", stats)
+	}
+}
+
+func TestService_ShedsUnderOverload(t *testing.T) {
+	tracker := token.NewTracker("node-a", "A")
+	defer tracker.Close()
+
+	provider := &slowProvider{delay: 50 * time.Millisecond}
+	svc := NewServiceWithProvider(provider)
+	svc.SetTokenTracker(tracker)
+	svc.SetConcurrencyLimit(ConcurrencyLimit{MaxInFlight: 2, Policy: OverloadShed})
+
+	var shed int
+	for _, err := range embedConcurrently(svc, 10) {
+		if errors.Is(err, ErrEmbeddingShed) {
+			shed++
+		} else if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if shed == 0 {
+		t.Fatal("expected some embeddings to be shed")
+	}
+	if peak := provider.peak.Load(); peak > 2 {
+		t.Errorf("provider saw %d concurrent calls, bound is 2", peak)
+	}
+	if stats := svc.ConcurrencyStats(); stats.Shed != int64(shed) {
+		t.Errorf("expected %d shed in stats, got %d", shed, stats.Shed)
+	}
+
+	metrics := tracker.GetMetrics()
+	if metrics.ShedToday != int64(shed) || metrics.ShedByCategory[token.CategoryEmbedding] != int64(shed) {
+		t.Errorf("token tracker should count %d shed embeddings, got %d", shed, metrics.ShedToday)
+	}
+	if metrics.ShedTokensToday == 0 {
+		t.Error("shed work should carry an estimated token count")
+	}
+}
+
+func TestService_QueueShedsBeyondMaxQueued(t *testing.T) {
+	provider := &slowProvider{delay: 50 * time.Millisecond}
+	svc := NewServiceWithProvider(provider)
+	svc.SetConcurrencyLimit(ConcurrencyLimit{MaxInFlight: 1, Policy: OverloadQueue, MaxQueued: 2})
+
+	var shed, ok int
+	for _, err := range embedConcurrently(svc, 10) {
+		switch {
+		case errors.Is(err, ErrEmbeddingShed):
+			shed++
+		case err == nil:
+			ok++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	// One runs, at most two wait, the rest are shed
+	if ok > 3 || shed < 7 {
+		t.Errorf("expected at most 3 embeddings to run, got %d run and %d shed", ok, shed)
+	}
+}
+
+func TestService_QueuedEmbeddingHonorsContext(t *testing.T) {
+	provider := &slowProvider{delay: 200 * time.Millisecond}
+	svc := NewServiceWithProvider(provider)
+	svc.SetConcurrencyLimit(ConcurrencyLimit{MaxInFlight: 1, Policy: OverloadQueue})
+
+	go svc.Embed(context.Background(), "busy")
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := svc.Embed(ctx, "waiting"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded while queued, got %v", err)
+	}
+	if stats := svc.ConcurrencyStats(); stats.Queued != 0 {
+		t.Errorf("cancelled call should leave the queue, got %d queued", stats.Queued)
+	}
+}
+
+func TestParseOverloadPolicy(t *testing.T) {
+	for in, want := range map[string]OverloadPolicy{"": OverloadQueue, "queue": OverloadQueue, "SHED": OverloadShed} {
+		if got, err := ParseOverloadPolicy(in); err != nil || got != want {
+			t.Errorf("ParseOverloadPolicy(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseOverloadPolicy("drop"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
@@ -0,0 +1,115 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultHealthTimeout bounds a single provider probe.
+const DefaultHealthTimeout = 5 * time.Second
+
+// HealthChecker is implemented by providers that can be probed without
+// generating embeddings (and so without spending tokens).
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthStatus describes the health of the embedding provider.
+type HealthStatus struct {
+	Provider    Provider  `json:"provider"`
+	Model       string    `json:"model"`
+	Healthy     bool      `json:"healthy"`
+	Probed      bool      `json:"probed"` // false when based on the last request only
+	Message     string    `json:"message,omitempty"`
+	LatencyMs   int64     `json:"latency_ms,omitempty"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	CheckedAt   time.Time `json:"checked_at"`
+}
+
+// HealthCheck reports whether the embedding provider is usable.
+// Providers with a free metadata endpoint are probed directly; others are
+// judged by the outcome of the most recent embedding request.
+func (s *Service) HealthCheck(ctx context.Context) *HealthStatus {
+	s.mu.RLock()
+	provider := s.provider
+	lastSuccess := s.lastSuccess
+	lastErr := s.lastErr
+	lastErrAt := s.lastErrAt
+	s.mu.RUnlock()
+
+	status := &HealthStatus{
+		Provider:    provider.Name(),
+		Model:       provider.Model(),
+		LastSuccess: lastSuccess,
+		CheckedAt:   time.Now(),
+	}
+	if lastErr != nil {
+		status.LastError = lastErr.Error()
+	}
+
+	if checker, ok := provider.(HealthChecker); ok {
+		probeCtx, cancel := context.WithTimeout(ctx, DefaultHealthTimeout)
+		defer cancel()
+
+		start := time.Now()
+		err := checker.HealthCheck(probeCtx)
+		status.Probed = true
+		status.LatencyMs = time.Since(start).Milliseconds()
+		if err != nil {
+			status.Message = err.Error()
+			return status
+		}
+		status.Healthy = true
+		return status
+	}
+
+	// No free probe available: fall back to the last request outcome
+	if lastErr != nil && lastErrAt.After(lastSuccess) {
+		status.Message = "last embedding request failed"
+		return status
+	}
+	status.Healthy = true
+	if lastSuccess.IsZero() {
+		status.Message = "not probed; no requests yet"
+	}
+	return status
+}
+
+// recordResult remembers the outcome of a provider call for health reporting.
+func (s *Service) recordResult(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.lastErr = err
+		s.lastErrAt = time.Now()
+		return
+	}
+	s.lastSuccess = time.Now()
+}
+
+// probeURL issues a GET request and treats any 2xx response as healthy.
+func probeURL(ctx context.Context, client *http.Client, url string, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := client.Do(req) // #nosec G704 - URL is from trusted embedding config
+	if err != nil {
+		return fmt.Errorf("provider unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("provider returned %d - %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestService_HealthCheck_HealthyProvider(t *testing.T) {
+	var embedCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/models/text-embedding-3-small":
+			if r.Header.Get("Authorization") != "Bearer test-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"id":"text-embedding-3-small"}`))
+		case "/embeddings":
+			embedCalls++
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	svc := NewServiceWithProvider(NewOpenAIProvider(&ProviderConfig{
+		Provider: ProviderOpenAI,
+		APIKey:   "test-key",
+		BaseURL:  srv.URL,
+		Model:    "text-embedding-3-small",
+	}))
+
+	status := svc.HealthCheck(context.Background())
+	if !status.Healthy {
+		t.Fatalf("expected healthy provider, got message %q", status.Message)
+	}
+	if !status.Probed {
+		t.Error("expected provider to be probed")
+	}
+	if embedCalls != 0 {
+		t.Errorf("health check should not request embeddings, got %d calls", embedCalls)
+	}
+}
+
+func TestService_HealthCheck_UnreachableProvider(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	baseURL := srv.URL
+	srv.Close()
+
+	svc := NewServiceWithProvider(NewOllamaProvider(&ProviderConfig{
+		Provider: ProviderOllama,
+		BaseURL:  baseURL,
+	}))
+
+	status := svc.HealthCheck(context.Background())
+	if status.Healthy {
+		t.Fatal("expected unreachable provider to be unhealthy")
+	}
+	if !status.Probed || status.Message == "" {
+		t.Errorf("expected probe failure message, got %+v", status)
+	}
+}
+
+func TestService_HealthCheck_MissingAPIKey(t *testing.T) {
+	svc := NewServiceWithProvider(NewOpenAIProvider(&ProviderConfig{
+		Provider: ProviderOpenAI,
+		BaseURL:  "http://127.0.0.1:0",
+	}))
+	// Guard against OPENAI_API_KEY leaking in from the environment
+	svc.GetProvider().(*OpenAIProvider).config.APIKey = ""
+
+	if status := svc.HealthCheck(context.Background()); status.Healthy {
+		t.Error("expected provider without API key to be unhealthy")
+	}
+}
+
+func TestService_HealthCheck_MockUsesLastResult(t *testing.T) {
+	svc := NewServiceWithProvider(NewMockProvider(&ProviderConfig{Provider: ProviderMock}))
+
+	status := svc.HealthCheck(context.Background())
+	if !status.Healthy || status.Probed {
+		t.Errorf("expected unprobed healthy mock, got %+v", status)
+	}
+
+	if _, err := svc.Embed(context.Background(), "hello"); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if status := svc.HealthCheck(context.Background()); status.LastSuccess.IsZero() {
+		t.Error("expected last success to be recorded")
+	}
+}
+
+type failingProvider struct{ MockProvider }
+
+func (p *failingProvider) Embed(ctx context.Context, texts []string) ([][]float32, int, error) {
+	return nil, 0, errors.New("quota exceeded")
+}
+
+func TestService_HealthCheck_LastRequestFailed(t *testing.T) {
+	svc := NewServiceWithProvider(&failingProvider{*NewMockProvider(&ProviderConfig{Provider: ProviderMock})})
+
+	if _, err := svc.Embed(context.Background(), "hello"); err == nil {
+		t.Fatal("expected embed to fail")
+	}
+
+	status := svc.HealthCheck(context.Background())
+	if status.Healthy {
+		t.Error("expected unhealthy status after failed request")
+	}
+	if status.LastError != "quota exceeded" {
+		t.Errorf("LastError = %q, want quota exceeded", status.LastError)
+	}
+}
@@ -0,0 +1,133 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Mode selects whether queries and documents are embedded the same way.
+type Mode string
+
+const (
+	// ModeSymmetric embeds queries and documents identically (default).
+	ModeSymmetric Mode = "symmetric"
+	// ModeAsymmetric prefixes queries and documents with per-mode
+	// instructions, as asymmetric retrieval models expect.
+	ModeAsymmetric Mode = "asymmetric"
+)
+
+// ParseMode parses a mode name; empty means symmetric.
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case "", ModeSymmetric:
+		return ModeSymmetric, nil
+	case ModeAsymmetric:
+		return ModeAsymmetric, nil
+	default:
+		return "", fmt.Errorf("unknown embedding mode %q (want %q or %q)", s, ModeSymmetric, ModeAsymmetric)
+	}
+}
+
+// InstructionPrefixes are prepended to texts before embedding in
+// asymmetric mode.
+type InstructionPrefixes struct {
+	Query    string `json:"query,omitempty"`
+	Document string `json:"document,omitempty"`
+}
+
+// knownPrefixes lists the instructions documented for asymmetric models,
+// keyed by model name prefix.
+var knownPrefixes = []struct {
+	model    string
+	prefixes InstructionPrefixes
+}{
+	{"nomic-embed-text", InstructionPrefixes{Query: "search_query: ", Document: "search_document: "}},
+	{"mxbai-embed-large", InstructionPrefixes{Query: "Represent this sentence for searching relevant passages: "}},
+	{"e5-", InstructionPrefixes{Query: "query: ", Document: "passage: "}},
+	{"multilingual-e5-", InstructionPrefixes{Query: "query: ", Document: "passage: "}},
+	{"bge-", InstructionPrefixes{Query: "Represent this sentence for searching relevant passages: "}},
+}
+
+// DefaultInstructionPrefixes returns the instructions a model expects in
+// asymmetric mode, or empty prefixes for models without any.
+func DefaultInstructionPrefixes(model string) InstructionPrefixes {
+	name := model
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, known := range knownPrefixes {
+		if strings.HasPrefix(name, known.model) {
+			return known.prefixes
+		}
+	}
+	return InstructionPrefixes{}
+}
+
+// prefixes returns the query and document prefixes for the configured mode.
+func (s *Service) prefixes() InstructionPrefixes {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.config.Mode != ModeAsymmetric {
+		return InstructionPrefixes{}
+	}
+	p := DefaultInstructionPrefixes(s.config.Model)
+	if s.config.QueryPrefix != "" {
+		p.Query = s.config.QueryPrefix
+	}
+	if s.config.DocumentPrefix != "" {
+		p.Document = s.config.DocumentPrefix
+	}
+	return p
+}
+
+// SetMode changes the embedding mode and, optionally, the instruction
+// prefixes (empty prefixes fall back to the model's defaults).
+func (s *Service) SetMode(mode Mode, prefixes InstructionPrefixes) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config.Mode = mode
+	s.config.QueryPrefix = prefixes.Query
+	s.config.DocumentPrefix = prefixes.Document
+}
+
+// Mode returns the embedding mode.
+func (s *Service) Mode() Mode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.config.Mode == "" {
+		return ModeSymmetric
+	}
+	return s.config.Mode
+}
+
+// EmbedQuery embeds a search query. In symmetric mode it equals Embed.
+func (s *Service) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	return s.Embed(ctx, s.prefixes().Query+text)
+}
+
+// EmbedDocument embeds content to be stored for search, condensed first
+// when a summary stage is configured. In symmetric mode without one it
+// equals Embed.
+func (s *Service) EmbedDocument(ctx context.Context, text string) ([]float32, error) {
+	text, err := s.summarize(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return s.Embed(ctx, s.prefixes().Document+text)
+}
+
+// EmbedDocuments embeds several documents in batches.
+func (s *Service) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	prefix := s.prefixes().Document
+	prepared := make([]string, len(texts))
+	for i, text := range texts {
+		summary, err := s.summarize(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		prepared[i] = prefix + summary
+	}
+	return s.EmbedBatch(ctx, prepared)
+}
@@ -0,0 +1,111 @@
+package embedding
+
+import (
+	"context"
+	"testing"
+)
+
+// recordingProvider records the texts it is asked to embed.
+type recordingProvider struct {
+	MockProvider
+	texts []string
+}
+
+func newRecordingProvider(model string) *recordingProvider {
+	return &recordingProvider{MockProvider: *NewMockProvider(&ProviderConfig{Provider: ProviderMock, Model: model})}
+}
+
+func (p *recordingProvider) Embed(ctx context.Context, texts []string) ([][]float32, int, error) {
+	p.texts = append(p.texts, texts...)
+	return p.MockProvider.Embed(ctx, texts)
+}
+
+func TestService_SymmetricModePreservesEmbed(t *testing.T) {
+	ctx := context.Background()
+	provider := newRecordingProvider("nomic-embed-text")
+	svc := NewServiceWithProvider(provider)
+
+	if svc.Mode() != ModeSymmetric {
+		t.Fatalf("expected symmetric by default, got %q", svc.Mode())
+	}
+
+	plain, err := svc.Embed(ctx, "lock handling")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, _ := svc.EmbedQuery(ctx, "lock handling")
+	doc, _ := svc.EmbedDocument(ctx, "lock handling")
+
+	if len(provider.texts) != 1 || provider.texts[0] != "lock handling" {
+		t.Errorf("symmetric mode should embed the raw text once, got %q", provider.texts)
+	}
+	for i := range plain {
+		if plain[i] != query[i] || plain[i] != doc[i] {
+			t.Fatal("symmetric query and document embeddings should equal Embed")
+		}
+	}
+}
+
+func TestService_AsymmetricModeUsesModelPrefixes(t *testing.T) {
+	ctx := context.Background()
+	provider := newRecordingProvider("nomic-embed-text")
+	svc := NewServiceWithProvider(provider)
+	svc.SetMode(ModeAsymmetric, InstructionPrefixes{})
+
+	if _, err := svc.EmbedQuery(ctx, "who holds main.go"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.EmbedDocuments(ctx, []string{"refactored main.go", "fixed api.go"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"search_query: who holds main.go",
+		"search_document: refactored main.go",
+		"search_document: fixed api.go",
+	}
+	if len(provider.texts) != len(want) {
+		t.Fatalf("expected %d provider calls, got %q", len(want), provider.texts)
+	}
+	for i, w := range want {
+		if provider.texts[i] != w {
+			t.Errorf("text %d: expected %q, got %q", i, w, provider.texts[i])
+		}
+	}
+}
+
+func TestService_AsymmetricModeCustomPrefixes(t *testing.T) {
+	ctx := context.Background()
+	provider := newRecordingProvider("custom-model")
+	svc := NewServiceWithProvider(provider)
+	svc.SetMode(ModeAsymmetric, InstructionPrefixes{Query: "Q: ", Document: "D: "})
+
+	query, _ := svc.EmbedQuery(ctx, "same text")
+	doc, _ := svc.EmbedDocument(ctx, "same text")
+
+	if len(provider.texts) != 2 || provider.texts[0] != "Q: same text" || provider.texts[1] != "D: same text" {
+		t.Fatalf("expected configured prefixes, got %q", provider.texts)
+	}
+	same := true
+	for i := range query {
+		if query[i] != doc[i] {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Error("query and document embeddings should differ in asymmetric mode")
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	if m, err := ParseMode(""); err != nil || m != ModeSymmetric {
+		t.Errorf("empty should default to symmetric, got %q, %v", m, err)
+	}
+	if _, err := ParseMode("sideways"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+	if p := DefaultInstructionPrefixes("intfloat/e5-large-v2"); p.Query != "query: " || p.Document != "passage: " {
+		t.Errorf("unexpected e5 prefixes: %+v", p)
+	}
+}
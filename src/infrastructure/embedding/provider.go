@@ -114,6 +114,41 @@ func DefaultProviderConfigs() map[Provider]*ProviderConfig {
 	}
 }
 
+// modelDimensions lists the output size of well-known embedding models, so
+// choosing one of them needs no explicit dimension.
+var modelDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+	"nomic-embed-text":       768,
+	"mxbai-embed-large":      1024,
+	"all-minilm":             384,
+	"snowflake-arctic-embed": 1024,
+}
+
+// KnownDimension returns the embedding dimension of a well-known model.
+// Ollama tags such as "nomic-embed-text:latest" match their base name.
+func KnownDimension(model string) (int, bool) {
+	base, _, _ := strings.Cut(model, ":")
+	dim, ok := modelDimensions[base]
+	return dim, ok
+}
+
+// ParseProvider parses a configured provider name. Empty selects the mock
+// provider and "auto" detects one from the environment.
+func ParseProvider(s string) (Provider, error) {
+	switch p := Provider(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return ProviderMock, nil
+	case "auto":
+		return DetectAvailableProvider(), nil
+	case ProviderOpenAI, ProviderAnthropic, ProviderGoogle, ProviderOllama, ProviderMock:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown embedding provider %q: want openai, ollama, anthropic, google, mock or auto", s)
+	}
+}
+
 // GetAPIKeyEnvVar returns the environment variable name for a provider's API key.
 func GetAPIKeyEnvVar(provider Provider) string {
 	switch provider {
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -117,8 +116,7 @@ func (p *AnthropicProvider) Embed(ctx context.Context, texts []string) ([][]floa
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, 0, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+		return nil, 0, newAPIError(ProviderAnthropic, resp)
 	}
 
 	var embResp voyageEmbeddingResponse
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -88,6 +87,16 @@ type googleBatchEmbedResponse struct {
 	} `json:"embeddings"`
 }
 
+// HealthCheck looks up the configured model, which costs no tokens.
+func (p *GoogleProvider) HealthCheck(ctx context.Context) error {
+	if p.config.APIKey == "" {
+		return fmt.Errorf("google API key not set (set GOOGLE_API_KEY environment variable)")
+	}
+	header := http.Header{}
+	header.Set("x-goog-api-key", p.config.APIKey)
+	return probeURL(ctx, p.client, fmt.Sprintf("%s/models/%s", p.config.BaseURL, p.config.Model), header)
+}
+
 func (p *GoogleProvider) Embed(ctx context.Context, texts []string) ([][]float32, int, error) {
 	if p.config.APIKey == "" {
 		return nil, 0, fmt.Errorf("google API key not set (set GOOGLE_API_KEY environment variable)")
@@ -127,8 +136,7 @@ func (p *GoogleProvider) Embed(ctx context.Context, texts []string) ([][]float32
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, 0, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+		return nil, 0, newAPIError(ProviderGoogle, resp)
 	}
 
 	var embResp googleBatchEmbedResponse
@@ -4,11 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,6 +17,9 @@ import (
 type OllamaProvider struct {
 	config *ProviderConfig
 	client *http.Client
+
+	// legacy is set once the server turned out to lack /api/embed
+	legacy atomic.Bool
 }
 
 // NewOllamaProvider creates a new Ollama embedding provider.
@@ -70,6 +74,19 @@ func (p *OllamaProvider) SupportsModel(model string) bool {
 	return true
 }
 
+// ollamaEmbedRequest embeds a batch through /api/embed (Ollama 0.3+).
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings      [][]float32 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+}
+
+// ollamaEmbeddingRequest embeds one text through the legacy
+// /api/embeddings endpoint.
 type ollamaEmbeddingRequest struct {
 	Model  string `json:"model"`
 	Prompt string `json:"prompt"`
@@ -79,11 +96,24 @@ type ollamaEmbeddingResponse struct {
 	Embedding []float32 `json:"embedding"`
 }
 
+// HealthCheck lists local models to confirm the Ollama server is up.
+func (p *OllamaProvider) HealthCheck(ctx context.Context) error {
+	return probeURL(ctx, p.client, p.config.BaseURL+"/api/tags", nil)
+}
+
 func (p *OllamaProvider) Embed(ctx context.Context, texts []string) ([][]float32, int, error) {
+	if !p.legacy.Load() {
+		embeddings, tokens, err := p.embedBatch(ctx, texts)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return embeddings, tokens, err
+		}
+		// Servers before /api/embed only embed one text at a time
+		p.legacy.Store(true)
+	}
+
 	embeddings := make([][]float32, len(texts))
 	totalTokens := 0
-
-	// Ollama processes one text at a time
 	for i, text := range texts {
 		embedding, tokens, err := p.embedSingle(ctx, text)
 		if err != nil {
@@ -96,43 +126,57 @@ func (p *OllamaProvider) Embed(ctx context.Context, texts []string) ([][]float32
 	return embeddings, totalTokens, nil
 }
 
+// embedBatch embeds all texts in one request. The token count is the
+// prompt evaluation count Ollama reports.
+func (p *OllamaProvider) embedBatch(ctx context.Context, texts []string) ([][]float32, int, error) {
+	var embResp ollamaEmbedResponse
+	if err := p.post(ctx, "/api/embed", ollamaEmbedRequest{Model: p.config.Model, Input: texts}, &embResp); err != nil {
+		return nil, 0, err
+	}
+	if len(embResp.Embeddings) != len(texts) {
+		return nil, 0, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embResp.Embeddings))
+	}
+	return embResp.Embeddings, embResp.PromptEvalCount, nil
+}
+
 func (p *OllamaProvider) embedSingle(ctx context.Context, text string) ([]float32, int, error) {
-	reqBody := ollamaEmbeddingRequest{
-		Model:  p.config.Model,
-		Prompt: text,
+	var embResp ollamaEmbeddingResponse
+	if err := p.post(ctx, "/api/embeddings", ollamaEmbeddingRequest{Model: p.config.Model, Prompt: text}, &embResp); err != nil {
+		return nil, 0, err
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	// The legacy endpoint reports no usage; estimate tokens
+	tokens := len(text) / 4
+
+	return embResp.Embedding, tokens, nil
+}
+
+// post sends a JSON request to the Ollama server and decodes the response.
+func (p *OllamaProvider) post(ctx context.Context, path string, body, out any) error {
+	jsonData, err := json.Marshal(body)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to marshal request: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := p.config.BaseURL + "/api/embeddings"
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+path, bytes.NewReader(jsonData))
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := p.client.Do(req) // #nosec G704 - URL is from trusted embedding config
 	if err != nil {
-		return nil, 0, fmt.Errorf("request failed (is Ollama running?): %w", err)
+		return fmt.Errorf("request failed (is Ollama running?): %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, 0, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+		return newAPIError(ProviderOllama, resp)
 	}
 
-	var embResp ollamaEmbeddingResponse
-	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
-		return nil, 0, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	// Estimate tokens
-	tokens := len(text) / 4
-
-	return embResp.Embedding, tokens, nil
+	return nil
 }
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -82,6 +81,16 @@ type openAIEmbeddingResponse struct {
 	} `json:"usage"`
 }
 
+// HealthCheck looks up the configured model, which costs no tokens.
+func (p *OpenAIProvider) HealthCheck(ctx context.Context) error {
+	if p.config.APIKey == "" {
+		return fmt.Errorf("OpenAI API key not set (set OPENAI_API_KEY environment variable)")
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+p.config.APIKey)
+	return probeURL(ctx, p.client, p.config.BaseURL+"/models/"+p.config.Model, header)
+}
+
 func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, int, error) {
 	if p.config.APIKey == "" {
 		return nil, 0, fmt.Errorf("OpenAI API key not set (set OPENAI_API_KEY environment variable)")
@@ -113,8 +122,7 @@ func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, 0, fmt.Errorf("API error: %d - %s", resp.StatusCode, string(body))
+		return nil, 0, newAPIError(ProviderOpenAI, resp)
 	}
 
 	var embResp openAIEmbeddingResponse
@@ -0,0 +1,108 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Backoff between retried provider calls: it starts at retryBaseDelay and
+// doubles per attempt up to retryMaxDelay, unless the provider asks for a
+// longer wait.
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+// APIError is an error response from an embedding provider's API.
+type APIError struct {
+	Provider   Provider
+	StatusCode int
+	Body       string
+	// RetryAfter is the wait the provider asked for, if any.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %d - %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the request may succeed if sent again: the
+// provider was rate limiting or failed on its side.
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// newAPIError reads a failed response into an APIError.
+func newAPIError(provider Provider, resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	apiErr := &APIError{Provider: provider, StatusCode: resp.StatusCode, Body: string(body)}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
+
+// isRetryable reports whether a failed provider call is worth retrying:
+// transport failures and retryable API errors are, cancellations and
+// client errors are not.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// retryDelay is the wait before retry number attempt (starting at 1).
+func retryDelay(attempt int, err error) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+	return delay
+}
+
+// embedWithRetry calls the provider within the concurrency limit, retrying
+// transient failures up to the configured number of times. The slot is
+// released while waiting between attempts.
+func (s *Service) embedWithRetry(ctx context.Context, provider EmbeddingProvider, texts []string) ([][]float32, int, error) {
+	s.mu.RLock()
+	maxRetries := s.config.MaxRetries
+	s.mu.RUnlock()
+
+	for attempt := 0; ; attempt++ {
+		release, err := s.acquireSlot(ctx, texts)
+		if err != nil {
+			return nil, 0, err
+		}
+		embeddings, tokensUsed, err := provider.Embed(ctx, texts)
+		release()
+		if err == nil || attempt >= maxRetries || !isRetryable(err) || ctx.Err() != nil {
+			s.recordResult(err)
+			return embeddings, tokensUsed, err
+		}
+
+		timer := time.NewTimer(retryDelay(attempt+1, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.recordResult(err)
+			return nil, 0, err
+		case <-timer.C:
+		}
+	}
+}
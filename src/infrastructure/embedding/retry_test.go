@@ -0,0 +1,120 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"agent-collab/src/domain/token"
+)
+
+func TestService_RetriesTransientProviderErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":[{"index":0,"embedding":[0.1,0.2]}],"usage":{"total_tokens":7}}`))
+	}))
+	defer srv.Close()
+
+	cfg := ConfigFor(ProviderOpenAI)
+	cfg.APIKey = "test-key"
+	cfg.BaseURL = srv.URL
+	svc := NewService(cfg)
+	tracker := token.NewTracker("node-1", "Node 1")
+	defer tracker.Close()
+	svc.SetTokenTracker(tracker)
+
+	embedding, err := svc.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if len(embedding) != 2 || calls.Load() != 2 {
+		t.Errorf("expected one retry, got %d calls and embedding %v", calls.Load(), embedding)
+	}
+	if got := tracker.GetMetrics().ByCategory[token.CategoryEmbedding]; got != 7 {
+		t.Errorf("expected 7 embedding tokens recorded, got %d", got)
+	}
+}
+
+func TestService_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	cfg := ConfigFor(ProviderOpenAI)
+	cfg.APIKey = "bad-key"
+	cfg.BaseURL = srv.URL
+	svc := NewService(cfg)
+
+	_, err := svc.Embed(context.Background(), "hello")
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a 401 APIError, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("client errors should not be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestOllamaProvider_BatchesAndFallsBack(t *testing.T) {
+	var batchCalls, singleCalls atomic.Int32
+	var legacy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/embed":
+			if legacy.Load() {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			batchCalls.Add(1)
+			var req ollamaEmbedRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			resp := ollamaEmbedResponse{PromptEvalCount: 12}
+			for range req.Input {
+				resp.Embeddings = append(resp.Embeddings, []float32{1, 0})
+			}
+			json.NewEncoder(w).Encode(resp)
+		case "/api/embeddings":
+			singleCalls.Add(1)
+			w.Write([]byte(`{"embedding":[0,1]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	texts := []string{"first text", "second text", "third text"}
+
+	provider := NewOllamaProvider(&ProviderConfig{Provider: ProviderOllama, BaseURL: srv.URL})
+	embeddings, tokens, err := provider.Embed(ctx, texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(embeddings) != 3 || batchCalls.Load() != 1 || singleCalls.Load() != 0 {
+		t.Errorf("expected one batch request, got %d batch and %d single", batchCalls.Load(), singleCalls.Load())
+	}
+	if tokens != 12 {
+		t.Errorf("expected the reported prompt eval count, got %d", tokens)
+	}
+
+	// An older server without /api/embed is asked one text at a time
+	legacy.Store(true)
+	old := NewOllamaProvider(&ProviderConfig{Provider: ProviderOllama, BaseURL: srv.URL})
+	embeddings, _, err = old.Embed(ctx, texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(embeddings) != 3 || embeddings[2][1] != 1 || singleCalls.Load() != 3 {
+		t.Errorf("expected a legacy request per text, got %d", singleCalls.Load())
+	}
+}
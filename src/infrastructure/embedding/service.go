@@ -21,24 +21,46 @@ type Config struct {
 	Timeout    time.Duration `json:"timeout"`
 	BatchSize  int           `json:"batch_size"`
 	MaxRetries int           `json:"max_retries"`
+
+	// Mode selects symmetric (default) or asymmetric query/document
+	// embedding. The prefixes override the model's default instructions.
+	Mode           Mode   `json:"mode,omitempty"`
+	QueryPrefix    string `json:"query_prefix,omitempty"`
+	DocumentPrefix string `json:"document_prefix,omitempty"`
+
+	// Concurrency bounds concurrent provider calls.
+	Concurrency ConcurrencyLimit `json:"concurrency"`
+
+	// Summary condenses long documents before they are embedded.
+	Summary SummaryConfig `json:"summary,omitempty"`
 }
 
 // DefaultConfig returns default configuration.
 func DefaultConfig() *Config {
 	// Auto-detect available provider
-	provider := DetectAvailableProvider()
+	return ConfigFor(DetectAvailableProvider())
+}
+
+// ConfigFor returns the default configuration for a provider, with its API
+// key taken from the environment.
+func ConfigFor(provider Provider) *Config {
 	defaults := DefaultProviderConfigs()
-	cfg := defaults[provider]
+	cfg, ok := defaults[provider]
+	if !ok {
+		cfg = &ProviderConfig{}
+	}
 
 	return &Config{
-		Provider:   provider,
-		Model:      cfg.Model,
-		Dimension:  cfg.Dimension,
-		BaseURL:    cfg.BaseURL,
-		APIKey:     GetAPIKeyFromEnv(provider),
-		Timeout:    30 * time.Second,
-		BatchSize:  100,
-		MaxRetries: 3,
+		Provider:    provider,
+		Model:       cfg.Model,
+		Dimension:   cfg.Dimension,
+		BaseURL:     cfg.BaseURL,
+		APIKey:      GetAPIKeyFromEnv(provider),
+		Timeout:     30 * time.Second,
+		BatchSize:   100,
+		MaxRetries:  3,
+		Mode:        ModeSymmetric,
+		Concurrency: DefaultConcurrencyLimit(),
 	}
 }
 
@@ -49,8 +71,20 @@ type Service struct {
 	provider EmbeddingProvider
 	cache    map[string][]float32 // content hash -> embedding
 
+	// Summarization stage before documents are embedded, nil when off
+	summarizer Summarizer
+	summaries  map[string]string // content hash -> summary
+
 	// Token tracking
 	tokenTracker *token.Tracker
+
+	// Bounds concurrent provider calls
+	limiter *limiter
+
+	// Outcome of the most recent provider call, for health reporting
+	lastSuccess time.Time
+	lastErr     error
+	lastErrAt   time.Time
 }
 
 // NewService creates a new embedding service.
@@ -59,31 +93,82 @@ func NewService(cfg *Config) *Service {
 		cfg = DefaultConfig()
 	}
 
+	provider, err := createProvider(cfg)
+	if err != nil {
+		// Fall back to mock provider
+		provider = NewMockProvider(providerConfig(cfg))
+	}
+	summarizer, err := NewSummarizer(cfg.Summary)
+	if err != nil {
+		// Embed documents as they are
+		cfg.Summary.Mode = SummaryOff
+	}
+
+	return &Service{
+		config:     cfg,
+		provider:   provider,
+		cache:      make(map[string][]float32),
+		summarizer: summarizer,
+		summaries:  make(map[string]string),
+		limiter:    newLimiter(cfg.Concurrency),
+	}
+}
+
+// createProvider fills in cfg's API key and batch size defaults and
+// creates its provider.
+func createProvider(cfg *Config) (EmbeddingProvider, error) {
 	// Get API key from environment if not set
 	if cfg.APIKey == "" {
 		cfg.APIKey = GetAPIKeyFromEnv(cfg.Provider)
 	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return CreateProvider(providerConfig(cfg))
+}
 
-	// Create provider
-	providerCfg := &ProviderConfig{
+func providerConfig(cfg *Config) *ProviderConfig {
+	return &ProviderConfig{
 		Provider:  cfg.Provider,
 		APIKey:    cfg.APIKey,
 		BaseURL:   cfg.BaseURL,
 		Model:     cfg.Model,
 		Dimension: cfg.Dimension,
 	}
+}
 
-	provider, err := CreateProvider(providerCfg)
+// Reconfigure switches the service to cfg while it is in use. Unlike
+// NewService it does not fall back to the mock provider: an unknown
+// provider or summary setting is an error and leaves the service
+// unchanged. Cached embeddings are dropped when the provider, model or
+// summary setting changes.
+func (s *Service) Reconfigure(cfg *Config) error {
+	if cfg == nil {
+		return fmt.Errorf("embedding config is nil")
+	}
+	provider, err := createProvider(cfg)
 	if err != nil {
-		// Fall back to mock provider
-		provider = NewMockProvider(providerCfg)
+		return err
+	}
+	summarizer, err := NewSummarizer(cfg.Summary)
+	if err != nil {
+		return err
 	}
 
-	return &Service{
-		config:   cfg,
-		provider: provider,
-		cache:    make(map[string][]float32),
+	s.mu.Lock()
+	if s.provider == nil || s.provider.Name() != provider.Name() || s.provider.Model() != provider.Model() {
+		s.cache = make(map[string][]float32)
 	}
+	if s.config.Summary != cfg.Summary {
+		s.summaries = make(map[string]string)
+	}
+	s.config = cfg
+	s.provider = provider
+	s.summarizer = summarizer
+	s.mu.Unlock()
+
+	s.limiter.set(cfg.Concurrency)
+	return nil
 }
 
 // NewServiceWithProvider creates a new embedding service with a specific provider.
@@ -95,8 +180,10 @@ func NewServiceWithProvider(provider EmbeddingProvider) *Service {
 			Dimension: provider.Dimension(),
 			BatchSize: 100,
 		},
-		provider: provider,
-		cache:    make(map[string][]float32),
+		provider:  provider,
+		cache:     make(map[string][]float32),
+		summaries: make(map[string]string),
+		limiter:   newLimiter(DefaultConcurrencyLimit()),
 	}
 }
 
@@ -139,9 +226,16 @@ func (s *Service) Embed(ctx context.Context, text string) ([]float32, error) {
 	s.mu.RLock()
 	provider := s.provider
 	model := s.config.Model
+	tracker := s.tokenTracker
 	s.mu.RUnlock()
 
-	embeddings, tokensUsed, err := provider.Embed(ctx, []string{text})
+	if tracker != nil {
+		if err := tracker.Allow(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	embeddings, tokensUsed, err := s.embedWithRetry(ctx, provider, []string{text})
 	if err != nil {
 		return nil, err
 	}
@@ -151,12 +245,8 @@ func (s *Service) Embed(ctx context.Context, text string) ([]float32, error) {
 	}
 
 	// Record token usage
-	s.mu.RLock()
-	tracker := s.tokenTracker
-	s.mu.RUnlock()
-
 	if tracker != nil && tokensUsed > 0 {
-		tracker.RecordEmbedding(int64(tokensUsed), model)
+		tracker.RecordUsage(token.CategoryEmbedding, string(provider.Name()), model, int64(tokensUsed), 0, nil)
 	}
 
 	// Cache result
@@ -191,11 +281,17 @@ func (s *Service) EmbedBatch(ctx context.Context, texts []string) ([][]float32,
 	provider := s.provider
 	model := s.config.Model
 	batchSize := s.config.BatchSize
+	tracker := s.tokenTracker
 	s.mu.RUnlock()
 
 	if len(uncachedTexts) == 0 {
 		return results, nil
 	}
+	if tracker != nil {
+		if err := tracker.Allow(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	// Generate embeddings for uncached texts in batches
 	var totalTokens int
@@ -206,7 +302,7 @@ func (s *Service) EmbedBatch(ctx context.Context, texts []string) ([][]float32,
 		}
 
 		batch := uncachedTexts[i:end]
-		embeddings, tokensUsed, err := provider.Embed(ctx, batch)
+		embeddings, tokensUsed, err := s.embedWithRetry(ctx, provider, batch)
 		if err != nil {
 			return nil, err
 		}
@@ -224,12 +320,8 @@ func (s *Service) EmbedBatch(ctx context.Context, texts []string) ([][]float32,
 	}
 
 	// Record token usage
-	s.mu.RLock()
-	tracker := s.tokenTracker
-	s.mu.RUnlock()
-
 	if tracker != nil && totalTokens > 0 {
-		tracker.RecordEmbedding(int64(totalTokens), model)
+		tracker.RecordUsage(token.CategoryEmbedding, string(provider.Name()), model, int64(totalTokens), 0, nil)
 	}
 
 	return results, nil
@@ -256,11 +348,12 @@ func (s *Service) Provider() Provider {
 	return s.provider.Name()
 }
 
-// ClearCache clears the embedding cache.
+// ClearCache clears the embedding and summary caches.
 func (s *Service) ClearCache() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.cache = make(map[string][]float32)
+	s.summaries = make(map[string]string)
 }
 
 // CacheSize returns the number of cached embeddings.
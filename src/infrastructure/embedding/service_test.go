@@ -0,0 +1,39 @@
+package embedding
+
+import (
+	"context"
+	"testing"
+)
+
+func TestService_Reconfigure(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(&Config{Provider: ProviderMock, Model: "mock-a", Dimension: 8, Concurrency: DefaultConcurrencyLimit()})
+	if _, err := svc.Embed(ctx, "lock handling"); err != nil {
+		t.Fatal(err)
+	}
+	if svc.CacheSize() != 1 {
+		t.Fatalf("expected one cached embedding, got %d", svc.CacheSize())
+	}
+
+	// An unknown provider is rejected and leaves the service as it was
+	if err := svc.Reconfigure(&Config{Provider: "unknown", Model: "x"}); err == nil {
+		t.Fatal("expected an unknown provider to be rejected")
+	}
+	if svc.Model() != "mock-a" || svc.CacheSize() != 1 {
+		t.Errorf("failed reconfigure changed the service: model %q, cache %d", svc.Model(), svc.CacheSize())
+	}
+
+	limit := ConcurrencyLimit{MaxInFlight: 2, MaxQueued: 4, Policy: OverloadQueue}
+	if err := svc.Reconfigure(&Config{Provider: ProviderMock, Model: "mock-b", Dimension: 8, Concurrency: limit}); err != nil {
+		t.Fatal(err)
+	}
+	if svc.Model() != "mock-b" {
+		t.Errorf("expected model mock-b, got %q", svc.Model())
+	}
+	if svc.CacheSize() != 0 {
+		t.Errorf("a model change should drop cached embeddings, got %d", svc.CacheSize())
+	}
+	if stats := svc.ConcurrencyStats(); stats.MaxInFlight != 2 {
+		t.Errorf("expected the new concurrency limit, got %+v", stats)
+	}
+}
@@ -0,0 +1,414 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"agent-collab/src/domain/token"
+)
+
+// SummaryMode selects how documents are condensed before they are
+// embedded.
+type SummaryMode string
+
+const (
+	// SummaryOff embeds documents as they are (default).
+	SummaryOff SummaryMode = "off"
+	// SummaryTemplate keeps the header, declarations and headings of a
+	// document locally, without calling a model.
+	SummaryTemplate SummaryMode = "template"
+	// SummaryLLM asks a chat model to summarize the document.
+	SummaryLLM SummaryMode = "llm"
+)
+
+// DefaultSummaryTokens is the size documents are condensed to when no
+// limit is configured.
+const DefaultSummaryTokens = 256
+
+// ParseSummaryMode parses a summary mode name; empty means off.
+func ParseSummaryMode(s string) (SummaryMode, error) {
+	switch SummaryMode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", SummaryOff:
+		return SummaryOff, nil
+	case SummaryTemplate:
+		return SummaryTemplate, nil
+	case SummaryLLM:
+		return SummaryLLM, nil
+	default:
+		return "", fmt.Errorf("unknown embedding summary mode %q (want %q, %q or %q)", s, SummaryOff, SummaryTemplate, SummaryLLM)
+	}
+}
+
+// SummaryConfig configures the summarization stage of the embedding
+// pipeline. Documents longer than MaxTokens are condensed to about
+// MaxTokens before they are embedded; the stored document keeps its full
+// content.
+type SummaryConfig struct {
+	Mode      SummaryMode `json:"mode,omitempty"`
+	MaxTokens int         `json:"max_tokens,omitempty"`
+
+	// Provider, Model, APIKey and BaseURL select the chat model of
+	// SummaryLLM. The API key falls back to the provider's environment
+	// variable.
+	Provider Provider `json:"provider,omitempty"`
+	Model    string   `json:"model,omitempty"`
+	APIKey   string   `json:"-"`
+	BaseURL  string   `json:"base_url,omitempty"`
+}
+
+// SummaryUsage is the model usage of one summarization.
+type SummaryUsage struct {
+	Provider     Provider
+	Model        string
+	InputTokens  int
+	OutputTokens int
+}
+
+// Summarizer condenses a document before it is embedded.
+type Summarizer interface {
+	// Summarize returns text condensed to about maxTokens tokens and the
+	// model tokens it used.
+	Summarize(ctx context.Context, text string, maxTokens int) (string, SummaryUsage, error)
+}
+
+// summaryModels are the chat models used by SummaryLLM per provider.
+var summaryModels = map[Provider]struct{ model, baseURL string }{
+	ProviderOpenAI:    {"gpt-4o-mini", "https://api.openai.com/v1"},
+	ProviderAnthropic: {"claude-3-5-haiku-latest", "https://api.anthropic.com/v1"},
+	ProviderOllama:    {"llama3.2", "http://localhost:11434"},
+	ProviderMock:      {"mock-summary", ""},
+}
+
+// NewSummarizer returns the summarizer of cfg, or nil when summarization
+// is off.
+func NewSummarizer(cfg SummaryConfig) (Summarizer, error) {
+	switch cfg.Mode {
+	case "", SummaryOff:
+		return nil, nil
+	case SummaryTemplate:
+		return TemplateSummarizer{}, nil
+	case SummaryLLM:
+	default:
+		return nil, fmt.Errorf("unknown embedding summary mode %q", cfg.Mode)
+	}
+
+	defaults, ok := summaryModels[cfg.Provider]
+	if !ok {
+		return nil, fmt.Errorf("embedding summary provider %q has no chat model: want openai, anthropic, ollama or mock", cfg.Provider)
+	}
+	if cfg.Provider == ProviderMock {
+		return mockSummarizer{}, nil
+	}
+	if cfg.Model == "" {
+		cfg.Model = defaults.model
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaults.baseURL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.APIKey == "" {
+		cfg.APIKey = GetAPIKeyFromEnv(cfg.Provider)
+	}
+	return &chatSummarizer{
+		config: cfg,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// estimateTextTokens approximates the tokens of text at four bytes each.
+func estimateTextTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// declarationPattern matches lines worth keeping in a template summary:
+// declarations in common languages and markdown headings.
+var declarationPattern = regexp.MustCompile(`^\s*(#{1,6}\s|(export\s+)?(async\s+)?(func|type|class|def|interface|struct|enum|trait|impl|fn|function|const|package|module|public|private|protected)\b)`)
+
+// TemplateSummarizer condenses documents locally: it keeps the first
+// line, which names the change, then declarations and headings, then other
+// lines in order while they fit. It uses no model tokens.
+type TemplateSummarizer struct{}
+
+// Summarize implements Summarizer.
+func (TemplateSummarizer) Summarize(_ context.Context, text string, maxTokens int) (string, SummaryUsage, error) {
+	return templateSummary(text, maxTokens), SummaryUsage{}, nil
+}
+
+func templateSummary(text string, maxTokens int) string {
+	if estimateTextTokens(text) <= maxTokens {
+		return text
+	}
+	budget := maxTokens * 4
+
+	lines := strings.Split(text, "\n")
+	type candidate struct {
+		index, rank int
+	}
+	var candidates []candidate
+	for i, line := range lines {
+		switch {
+		case strings.TrimSpace(line) == "":
+			continue
+		case i == 0:
+			candidates = append(candidates, candidate{i, 0})
+		case declarationPattern.MatchString(line):
+			candidates = append(candidates, candidate{i, 1})
+		default:
+			candidates = append(candidates, candidate{i, 2})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int { return a.rank - b.rank })
+
+	var kept []int
+	used := 0
+	for _, c := range candidates {
+		line := strings.TrimSpace(lines[c.index])
+		if used+len(line)+1 > budget {
+			if len(kept) == 0 {
+				// A single line longer than the budget is cut
+				return strings.ToValidUTF8(line[:budget], "")
+			}
+			continue
+		}
+		used += len(line) + 1
+		kept = append(kept, c.index)
+	}
+	slices.Sort(kept)
+
+	out := make([]string, len(kept))
+	for i, index := range kept {
+		out[i] = strings.TrimSpace(lines[index])
+	}
+	return strings.Join(out, "\n")
+}
+
+// mockSummarizer summarizes like TemplateSummarizer but reports the
+// tokens a model would have used, for tests and the mock provider.
+type mockSummarizer struct{}
+
+func (mockSummarizer) Summarize(_ context.Context, text string, maxTokens int) (string, SummaryUsage, error) {
+	summary := templateSummary(text, maxTokens)
+	return summary, SummaryUsage{
+		Provider:     ProviderMock,
+		Model:        summaryModels[ProviderMock].model,
+		InputTokens:  estimateTextTokens(text),
+		OutputTokens: estimateTextTokens(summary),
+	}, nil
+}
+
+// chatSummarizer asks a chat model for the summary.
+type chatSummarizer struct {
+	config SummaryConfig
+	client *http.Client
+}
+
+// summaryPrompt instructs the model to keep what search needs.
+const summaryPrompt = `Summarize the following shared code context in at most %d tokens for semantic search.
+Keep file paths, symbol names, what changed and why. Reply with the summary only.
+
+%s`
+
+// Summarize implements Summarizer.
+func (s *chatSummarizer) Summarize(ctx context.Context, text string, maxTokens int) (string, SummaryUsage, error) {
+	usage := SummaryUsage{Provider: s.config.Provider, Model: s.config.Model}
+	if s.config.APIKey == "" && s.config.Provider != ProviderOllama {
+		return "", usage, fmt.Errorf("%s API key not set for embedding summaries (set %s)", s.config.Provider, GetAPIKeyEnvVar(s.config.Provider))
+	}
+	prompt := fmt.Sprintf(summaryPrompt, maxTokens, text)
+
+	var (
+		path    string
+		body    any
+		headers = http.Header{}
+	)
+	switch s.config.Provider {
+	case ProviderOpenAI:
+		path = "/chat/completions"
+		body = map[string]any{
+			"model":      s.config.Model,
+			"max_tokens": maxTokens,
+			"messages":   []map[string]string{{"role": "user", "content": prompt}},
+		}
+		headers.Set("Authorization", "Bearer "+s.config.APIKey)
+	case ProviderAnthropic:
+		path = "/messages"
+		body = map[string]any{
+			"model":      s.config.Model,
+			"max_tokens": maxTokens,
+			"messages":   []map[string]string{{"role": "user", "content": prompt}},
+		}
+		headers.Set("x-api-key", s.config.APIKey)
+		headers.Set("anthropic-version", "2023-06-01")
+	case ProviderOllama:
+		path = "/api/generate"
+		body = map[string]any{
+			"model":   s.config.Model,
+			"prompt":  prompt,
+			"stream":  false,
+			"options": map[string]any{"num_predict": maxTokens},
+		}
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return "", usage, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.config.BaseURL+path, bytes.NewReader(jsonData))
+	if err != nil {
+		return "", usage, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = headers
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req) // #nosec G704 - URL is from trusted embedding config
+	if err != nil {
+		return "", usage, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", usage, newAPIError(s.config.Provider, resp)
+	}
+
+	var out struct {
+		// OpenAI
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		// Anthropic
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		// Ollama
+		Response        string `json:"response"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
+
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			InputTokens      int `json:"input_tokens"`
+			OutputTokens     int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", usage, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	var summary string
+	switch s.config.Provider {
+	case ProviderOpenAI:
+		if len(out.Choices) > 0 {
+			summary = out.Choices[0].Message.Content
+		}
+		usage.InputTokens, usage.OutputTokens = out.Usage.PromptTokens, out.Usage.CompletionTokens
+	case ProviderAnthropic:
+		for _, c := range out.Content {
+			summary += c.Text
+		}
+		usage.InputTokens, usage.OutputTokens = out.Usage.InputTokens, out.Usage.OutputTokens
+	case ProviderOllama:
+		summary = out.Response
+		usage.InputTokens, usage.OutputTokens = out.PromptEvalCount, out.EvalCount
+	}
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", usage, fmt.Errorf("%s returned an empty summary", s.config.Provider)
+	}
+	return summary, usage, nil
+}
+
+// SetSummary replaces the summarization stage. Documents are condensed
+// before EmbedDocument and EmbedDocuments embed them.
+func (s *Service) SetSummary(cfg SummaryConfig) error {
+	summarizer, err := NewSummarizer(cfg)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config.Summary = cfg
+	s.summarizer = summarizer
+	s.summaries = make(map[string]string)
+	return nil
+}
+
+// summarize condenses a document longer than the configured limit and
+// records the model tokens it took under token.CategorySummarization.
+// Documents the model fails to summarize fall back to the template
+// summary, so sharing does not fail with the summarizer.
+func (s *Service) summarize(ctx context.Context, text string) (string, error) {
+	s.mu.RLock()
+	summarizer := s.summarizer
+	maxTokens := s.config.Summary.MaxTokens
+	tracker := s.tokenTracker
+	s.mu.RUnlock()
+
+	if summarizer == nil {
+		return text, nil
+	}
+	if maxTokens <= 0 {
+		maxTokens = DefaultSummaryTokens
+	}
+	if estimateTextTokens(text) <= maxTokens {
+		return text, nil
+	}
+
+	hash := computeHash(text)
+	s.mu.RLock()
+	summary, ok := s.summaries[hash]
+	s.mu.RUnlock()
+	if ok {
+		return summary, nil
+	}
+
+	if _, local := summarizer.(TemplateSummarizer); !local && tracker != nil {
+		if err := tracker.Allow(ctx); err != nil {
+			return "", err
+		}
+	}
+	summary, usage, err := summarizer.Summarize(ctx, text, maxTokens)
+	if tracker != nil && usage.InputTokens+usage.OutputTokens > 0 {
+		tracker.RecordUsage(token.CategorySummarization, string(usage.Provider), usage.Model,
+			int64(usage.InputTokens), int64(usage.OutputTokens), nil)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", err
+		}
+		// Not cached, so the model is asked again next time
+		return templateSummary(text, maxTokens), nil
+	}
+
+	s.mu.Lock()
+	s.summaries[hash] = summary
+	s.mu.Unlock()
+	return summary, nil
+}
+
+// Summarize condenses text longer than the configured limit the way
+// documents are condensed before embedding. When summarization is off the
+// local template summary is used, so callers such as activity digests
+// always get a condensed text.
+func (s *Service) Summarize(ctx context.Context, text string) (string, error) {
+	s.mu.RLock()
+	off := s.summarizer == nil
+	maxTokens := s.config.Summary.MaxTokens
+	s.mu.RUnlock()
+
+	if off {
+		if maxTokens <= 0 {
+			maxTokens = DefaultSummaryTokens
+		}
+		return templateSummary(text, maxTokens), nil
+	}
+	return s.summarize(ctx, text)
+}
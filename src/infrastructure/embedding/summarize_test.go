@@ -0,0 +1,133 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"agent-collab/src/domain/token"
+)
+
+// longDocument returns a document well over maxTokens with its
+// declarations buried in body text.
+func longDocument() string {
+	var b strings.Builder
+	b.WriteString("// auth/session.go: rotate session tokens\n")
+	for i := 0; i < 40; i++ {
+		b.WriteString("\tx := compute(value, other, more, arguments, here)\n")
+		if i == 20 {
+			b.WriteString("func RotateSession(ctx context.Context) error {\n")
+		}
+	}
+	return b.String()
+}
+
+func TestParseSummaryMode(t *testing.T) {
+	for in, want := range map[string]SummaryMode{"": SummaryOff, "off": SummaryOff, "Template": SummaryTemplate, "llm": SummaryLLM} {
+		got, err := ParseSummaryMode(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSummaryMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseSummaryMode("abstract"); err == nil {
+		t.Error("expected an unknown summary mode to be rejected")
+	}
+}
+
+func TestNewSummarizer_RejectsProviderWithoutChatModel(t *testing.T) {
+	if _, err := NewSummarizer(SummaryConfig{Mode: SummaryLLM, Provider: ProviderGoogle}); err == nil {
+		t.Error("expected google to be rejected as a summary provider")
+	}
+	if s, err := NewSummarizer(SummaryConfig{}); s != nil || err != nil {
+		t.Errorf("expected no summarizer when off, got %v, %v", s, err)
+	}
+}
+
+func TestTemplateSummary_KeepsHeaderAndDeclarations(t *testing.T) {
+	doc := longDocument()
+	summary := templateSummary(doc, 32)
+
+	if estimateTextTokens(summary) > 32 {
+		t.Errorf("summary exceeds its budget: %d tokens", estimateTextTokens(summary))
+	}
+	lines := strings.Split(summary, "\n")
+	if lines[0] != "// auth/session.go: rotate session tokens" {
+		t.Errorf("expected the header first, got %q", lines[0])
+	}
+	if !strings.Contains(summary, "func RotateSession") {
+		t.Errorf("expected the declaration to be kept, got %q", summary)
+	}
+
+	if short := "func A() {}"; templateSummary(short, 32) != short {
+		t.Error("a document within budget should be kept as is")
+	}
+}
+
+func TestService_EmbedDocumentSummarizesAndTracksTokens(t *testing.T) {
+	ctx := context.Background()
+	provider := newRecordingProvider("mock-a")
+	svc := NewServiceWithProvider(provider)
+	tracker := token.NewTracker("node", "test")
+	svc.SetTokenTracker(tracker)
+	if err := svc.SetSummary(SummaryConfig{Mode: SummaryLLM, Provider: ProviderMock, MaxTokens: 32}); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := longDocument()
+	if _, err := svc.EmbedDocument(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.texts) != 1 || provider.texts[0] != templateSummary(doc, 32) {
+		t.Fatalf("expected the summary to be embedded, got %q", provider.texts)
+	}
+
+	metrics := tracker.GetMetrics()
+	if metrics.ByCategory[token.CategorySummarization] == 0 {
+		t.Error("expected summarization tokens to be tracked")
+	}
+	if metrics.ByCategory[token.CategoryEmbedding] == 0 {
+		t.Error("expected embedding tokens to be tracked separately")
+	}
+
+	// Short documents and queries are embedded as they are
+	if _, err := svc.EmbedDocuments(ctx, []string{"short note"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.EmbedQuery(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	if provider.texts[1] != "short note" || provider.texts[2] != doc {
+		t.Errorf("expected unsummarized texts, got %q", provider.texts[1:])
+	}
+}
+
+func TestChatSummarizer_OpenAI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": " Rotates session tokens. "}}},
+			"usage":   map[string]int{"prompt_tokens": 120, "completion_tokens": 6},
+		})
+	}))
+	defer server.Close()
+
+	s, err := NewSummarizer(SummaryConfig{Mode: SummaryLLM, Provider: ProviderOpenAI, APIKey: "key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, usage, err := s.Summarize(context.Background(), longDocument(), 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != "Rotates session tokens." {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	if usage.InputTokens != 120 || usage.OutputTokens != 6 || usage.Model != "gpt-4o-mini" {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+}
@@ -0,0 +1,110 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Change is a range of lines a diff changes in one file, numbered as in
+// the new version of the file. Lines that were only removed are numbered
+// as in the old version, which is how a lock taken before the edit names
+// them.
+type Change struct {
+	FilePath  string `json:"file_path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// ParseDiff returns the changed line ranges of a unified diff with
+// repository-relative paths, as produced by git diff --unified=0. Binary
+// files have no line ranges and are left out.
+func ParseDiff(diff string) []Change {
+	var changes []Change
+	var oldPath, newPath string
+	for line := range strings.SplitSeq(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			oldPath, newPath = "", ""
+		case strings.HasPrefix(line, "--- "):
+			oldPath = diffPath(line[len("--- "):], "a/")
+		case strings.HasPrefix(line, "+++ "):
+			newPath = diffPath(line[len("+++ "):], "b/")
+		case strings.HasPrefix(line, "@@ "):
+			path := newPath
+			if path == "" {
+				path = oldPath
+			}
+			if path == "" {
+				continue
+			}
+			if change, ok := parseHunk(line, path); ok {
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes
+}
+
+// diffPath returns the path of a ---/+++ header, or "" for /dev/null.
+func diffPath(header, prefix string) string {
+	// Paths with spaces may be followed by a tab
+	header, _, _ = strings.Cut(header, "\t")
+	if strings.HasPrefix(header, `"`) {
+		if unquoted, err := strconv.Unquote(header); err == nil {
+			header = unquoted
+		}
+	}
+	if header == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(header, prefix)
+}
+
+// parseHunk parses a hunk header such as "@@ -10,2 +10,3 @@ func f()".
+func parseHunk(header, path string) (Change, bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return Change{}, false
+	}
+	oldStart, oldCount, ok := parseRange(fields[1], "-")
+	if !ok {
+		return Change{}, false
+	}
+	newStart, newCount, ok := parseRange(fields[2], "+")
+	if !ok {
+		return Change{}, false
+	}
+
+	change := Change{FilePath: path, StartLine: newStart, EndLine: newStart + newCount - 1}
+	if newCount == 0 {
+		// Only removed lines
+		change.StartLine, change.EndLine = oldStart, oldStart+oldCount-1
+	}
+	if change.StartLine < 1 {
+		change.StartLine = 1
+	}
+	if change.EndLine < change.StartLine {
+		change.EndLine = change.StartLine
+	}
+	return change, true
+}
+
+// parseRange parses "-start,count" or "+start", where count defaults to 1.
+func parseRange(field, sign string) (start, count int, ok bool) {
+	field, found := strings.CutPrefix(field, sign)
+	if !found {
+		return 0, 0, false
+	}
+	startStr, countStr, hasCount := strings.Cut(field, ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
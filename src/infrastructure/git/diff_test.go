@@ -0,0 +1,46 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseDiff_ReturnsChangedRanges(t *testing.T) {
+	diff := `diff --git a/src/auth/login.go b/src/auth/login.go
+index 3b18e51..a4c2d1f 100644
+--- a/src/auth/login.go
++++ b/src/auth/login.go
+@@ -10,2 +10,3 @@ func Login() {
++	validate()
+@@ -40 +41 @@ func Logout() {
+-	old()
++	logout()
+@@ -55,2 +55,0 @@ func Refresh() {
+-	a()
+-	b()
+diff --git a/docs/new file.md b/docs/new file.md
+new file mode 100644
+--- /dev/null
++++ b/docs/new file.md	
+@@ -0,0 +1,4 @@
++# New
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,7 +0,0 @@
+-package old
+diff --git a/logo.png b/logo.png
+Binary files a/logo.png and b/logo.png differ
+`
+	want := []Change{
+		{FilePath: "src/auth/login.go", StartLine: 10, EndLine: 12},
+		{FilePath: "src/auth/login.go", StartLine: 41, EndLine: 41},
+		{FilePath: "src/auth/login.go", StartLine: 55, EndLine: 56},
+		{FilePath: "docs/new file.md", StartLine: 1, EndLine: 4},
+		{FilePath: "old.go", StartLine: 1, EndLine: 7},
+	}
+	if got := ParseDiff(diff); !slices.Equal(got, want) {
+		t.Errorf("ParseDiff() =\n%v\nwant\n%v", got, want)
+	}
+}
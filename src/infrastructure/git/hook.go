@@ -0,0 +1,53 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker identifies a pre-commit hook written by InstallHook.
+const hookMarker = "# agent-collab lock-aware pre-commit hook"
+
+// ErrHookExists is returned when a pre-commit hook not written by
+// agent-collab is already installed.
+var ErrHookExists = errors.New("a pre-commit hook is already installed")
+
+// InstallHook writes a pre-commit hook that runs "<executable> git
+// pre-commit" and returns its path. An existing hook written by
+// InstallHook is replaced; any other is kept unless force is set.
+func (r *Repo) InstallHook(executable string, force bool) (string, error) {
+	dir, err := r.HooksDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "pre-commit")
+
+	if existing, err := os.ReadFile(path); err == nil {
+		if !force && !strings.Contains(string(existing), hookMarker) {
+			return "", fmt.Errorf("%w: %s", ErrHookExists, path)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	script := fmt.Sprintf("#!/bin/sh\n%s\nexec %s git pre-commit\n", hookMarker, shellQuote(filepath.ToSlash(executable)))
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		return "", err
+	}
+	// WriteFile keeps the mode of a hook it replaces
+	if err := os.Chmod(path, 0755); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"agent-collab/src/domain/lock"
+)
+
+// Violation is a changed range the committing agent does not hold locks
+// for.
+type Violation struct {
+	Change
+	// HolderName is the agent holding an overlapping lock, if any
+	HolderName string `json:"holder_name,omitempty"`
+	LockID     string `json:"lock_id,omitempty"`
+}
+
+func (v Violation) String() string {
+	region := fmt.Sprintf("%s:%d-%d", v.FilePath, v.StartLine, v.EndLine)
+	if v.HolderName != "" {
+		return fmt.Sprintf("%s is locked by %s (%s)", region, v.HolderName, v.LockID)
+	}
+	return region + " is not locked"
+}
+
+// CheckLocks returns the changes holderID may not commit: those
+// overlapping another holder's lock and those not fully covered by its
+// own locks. Lock paths are matched relative to root.
+func CheckLocks(changes []Change, locks []*lock.SemanticLock, holderID, root string) []Violation {
+	byFile := make(map[string][]*lock.SemanticLock)
+	for _, l := range locks {
+		if l.Target == nil {
+			continue
+		}
+		path, ok := repoPath(l.Target.FilePath, root)
+		if !ok {
+			continue
+		}
+		byFile[path] = append(byFile[path], l)
+	}
+
+	var violations []Violation
+	for _, change := range changes {
+		var own [][2]int
+		var other *lock.SemanticLock
+		for _, l := range byFile[change.FilePath] {
+			if l.Target.EndLine < change.StartLine || l.Target.StartLine > change.EndLine {
+				continue
+			}
+			if l.HolderID == holderID {
+				own = append(own, [2]int{l.Target.StartLine, l.Target.EndLine})
+			} else if other == nil {
+				other = l
+			}
+		}
+		switch {
+		case other != nil:
+			violations = append(violations, Violation{Change: change, HolderName: other.HolderName, LockID: other.ID})
+		case !covers(own, change.StartLine, change.EndLine):
+			violations = append(violations, Violation{Change: change})
+		}
+	}
+	return violations
+}
+
+// covers reports whether the line ranges together cover start to end.
+func covers(ranges [][2]int, start, end int) bool {
+	slices.SortFunc(ranges, func(a, b [2]int) int { return a[0] - b[0] })
+	next := start
+	for _, r := range ranges {
+		if r[0] > next {
+			return false
+		}
+		if r[1] >= next {
+			next = r[1] + 1
+		}
+		if next > end {
+			return true
+		}
+	}
+	return next > end
+}
+
+// repoPath returns a lock's file path relative to root, with forward
+// slashes as in git diffs. ok is false for paths outside root.
+func repoPath(path, root string) (string, bool) {
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", false
+		}
+		path = rel
+	}
+	return filepath.ToSlash(filepath.Clean(path)), true
+}
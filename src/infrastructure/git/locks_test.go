@@ -0,0 +1,47 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"agent-collab/src/domain/lock"
+)
+
+func testLock(id, holder, path string, start, end int) *lock.SemanticLock {
+	return &lock.SemanticLock{
+		ID:         id,
+		HolderID:   holder,
+		HolderName: holder + "-agent",
+		Target:     &lock.SemanticTarget{Type: lock.TargetFunction, FilePath: path, StartLine: start, EndLine: end},
+	}
+}
+
+func TestCheckLocks_RequiresOwnLocksCoveringEveryChange(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "work", "repo")
+	locks := []*lock.SemanticLock{
+		testLock("lock-1", "me", filepath.Join(root, "src", "auth.go"), 1, 20),
+		testLock("lock-2", "me", "src/auth.go", 21, 30),
+		testLock("lock-3", "peer", "src/db.go", 5, 10),
+		testLock("lock-4", "me", filepath.Join(string(filepath.Separator), "elsewhere", "x.go"), 1, 100),
+	}
+	changes := []Change{
+		{FilePath: "src/auth.go", StartLine: 15, EndLine: 25}, // covered by two own locks
+		{FilePath: "src/auth.go", StartLine: 28, EndLine: 35}, // runs past own lock
+		{FilePath: "src/db.go", StartLine: 8, EndLine: 8},     // peer's lock
+		{FilePath: "x.go", StartLine: 1, EndLine: 1},          // lock outside the repo
+	}
+
+	violations := CheckLocks(changes, locks, "me", root)
+	if len(violations) != 3 {
+		t.Fatalf("expected 3 violations, got %v", violations)
+	}
+	if v := violations[0]; v.FilePath != "src/auth.go" || v.StartLine != 28 || v.HolderName != "" {
+		t.Errorf("expected the partly locked change to be unlocked, got %+v", v)
+	}
+	if v := violations[1]; v.FilePath != "src/db.go" || v.HolderName != "peer-agent" || v.LockID != "lock-3" {
+		t.Errorf("expected the peer's lock to be reported, got %+v", v)
+	}
+	if v := violations[2]; v.FilePath != "x.go" || v.String() != "x.go:1-1 is not locked" {
+		t.Errorf("expected x.go to be unlocked, got %v", v)
+	}
+}
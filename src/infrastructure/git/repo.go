@@ -0,0 +1,113 @@
+// Package git connects semantic locks and shared context to the git
+// repository agents work in: it reads the current branch and commit, the
+// line ranges a commit changes, and installs the lock-aware pre-commit
+// hook.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotRepository is returned when a directory is not inside a git work
+// tree.
+var ErrNotRepository = errors.New("not a git repository")
+
+// commandTimeout bounds each git invocation.
+const commandTimeout = 10 * time.Second
+
+// Repo is a git work tree.
+type Repo struct {
+	root string
+}
+
+// Open returns the repository containing dir.
+func Open(dir string) (*Repo, error) {
+	out, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotRepository, dir)
+	}
+	return &Repo{root: filepath.FromSlash(out)}, nil
+}
+
+// Root returns the top-level directory of the work tree.
+func (r *Repo) Root() string {
+	return r.root
+}
+
+// Head is the checked-out branch and commit.
+type Head struct {
+	// Branch is empty when HEAD is detached
+	Branch string `json:"branch,omitempty"`
+	// Commit is empty before the first commit
+	Commit string `json:"commit,omitempty"`
+}
+
+// Head returns the checked-out branch and commit.
+func (r *Repo) Head() Head {
+	var head Head
+	if branch, err := r.git("symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		head.Branch = branch
+	}
+	if commit, err := r.git("rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		head.Commit = commit
+	}
+	return head
+}
+
+// StagedChanges returns the line ranges changed by the staged diff.
+func (r *Repo) StagedChanges() ([]Change, error) {
+	out, err := r.git("-c", "core.quotePath=false", "diff", "--cached",
+		"--unified=0", "--no-color", "--no-ext-diff", "--no-renames")
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiff(out), nil
+}
+
+// HooksDir returns the directory git runs hooks from, honouring
+// core.hooksPath.
+func (r *Repo) HooksDir() (string, error) {
+	dir, err := r.git("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.FromSlash(dir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.root, dir)
+	}
+	return dir, nil
+}
+
+// git runs a git command in the work tree.
+func (r *Repo) git(args ...string) (string, error) {
+	return run(r.root, args...)
+}
+
+// run runs git in dir and returns its trimmed output.
+func run(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
@@ -0,0 +1,104 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a repository with one commit on main.
+func initRepo(t *testing.T) *Repo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"config", "user.email", "dev@example.com"},
+		{"config", "user.name", "dev"},
+	} {
+		if _, err := run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, dir, "main.go", "package main\n\nfunc main() {\n}\n")
+	for _, args := range [][]string{{"add", "."}, {"commit", "--quiet", "-m", "init"}} {
+		if _, err := run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepo_HeadAndStagedChanges(t *testing.T) {
+	repo := initRepo(t)
+
+	head := repo.Head()
+	if head.Branch != "main" || len(head.Commit) != 40 {
+		t.Errorf("unexpected head: %+v", head)
+	}
+
+	writeFile(t, repo.Root(), "main.go", "package main\n\nfunc main() {\n\tprintln(1)\n}\n")
+	if _, err := repo.git("add", "main.go"); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := repo.StagedChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{{FilePath: "main.go", StartLine: 4, EndLine: 4}}
+	if !slices.Equal(changes, want) {
+		t.Errorf("expected %v, got %v", want, changes)
+	}
+
+	if _, err := Open(t.TempDir()); !errors.Is(err, ErrNotRepository) {
+		t.Errorf("expected ErrNotRepository outside a repository, got %v", err)
+	}
+}
+
+func TestRepo_InstallHookKeepsForeignHooks(t *testing.T) {
+	repo := initRepo(t)
+
+	path, err := repo.InstallHook("/usr/local/bin/agent-collab", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "exec '/usr/local/bin/agent-collab' git pre-commit") {
+		t.Errorf("unexpected hook:\n%s", data)
+	}
+	// Reinstalling our own hook is fine
+	if _, err := repo.InstallHook("/usr/local/bin/agent-collab", false); err != nil {
+		t.Errorf("expected reinstalling to succeed, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nlint\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.InstallHook("/usr/local/bin/agent-collab", false); !errors.Is(err, ErrHookExists) {
+		t.Errorf("expected ErrHookExists, got %v", err)
+	}
+	if _, err := repo.InstallHook("/usr/local/bin/agent-collab", true); err != nil {
+		t.Errorf("expected --force to replace the hook, got %v", err)
+	}
+}
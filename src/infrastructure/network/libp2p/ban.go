@@ -0,0 +1,455 @@
+package libp2p
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// BanConfig configures peer bans. Operators ban peers explicitly; peers
+// that misbehave are banned automatically once their misbehavior score
+// reaches AutoBanThreshold.
+type BanConfig struct {
+	// AutoBanThreshold is the misbehavior score at which a peer is banned
+	// automatically (0 disables automatic bans)
+	AutoBanThreshold float64
+	// AutoBanDuration is how long automatic bans last
+	AutoBanDuration time.Duration
+	// ScoreHalfLife is how long it takes a misbehavior score to halve, so
+	// occasional faults are forgiven
+	ScoreHalfLife time.Duration
+	// MessageRate is the sustained number of pubsub messages per second a
+	// peer may publish; messages beyond it are dropped and count as
+	// misbehavior (0 disables the limit)
+	MessageRate float64
+	// MessageBurst is how many messages a peer may publish at once
+	MessageBurst int
+}
+
+// DefaultBanConfig returns the default configuration
+func DefaultBanConfig() BanConfig {
+	return BanConfig{
+		AutoBanThreshold: 100,
+		AutoBanDuration:  time.Hour,
+		ScoreHalfLife:    10 * time.Minute,
+		MessageRate:      50,
+		MessageBurst:     200,
+	}
+}
+
+// Misbehavior is a kind of fault a peer is penalized for.
+type Misbehavior string
+
+const (
+	// MisbehaviorMalformed is a pubsub message that cannot be decoded
+	MisbehaviorMalformed Misbehavior = "malformed_message"
+	// MisbehaviorRateLimit is a pubsub message beyond the peer's rate limit
+	MisbehaviorRateLimit Misbehavior = "rate_limit"
+)
+
+// misbehaviorWeights is how much each misbehavior adds to a peer's score.
+// A flood has to exceed the rate limit many times to get a peer banned,
+// while a handful of malformed messages is enough.
+var misbehaviorWeights = map[Misbehavior]float64{
+	MisbehaviorMalformed: 20,
+	MisbehaviorRateLimit: 1,
+}
+
+// Ban is a peer this node refuses to talk to.
+type Ban struct {
+	PeerID peer.ID `json:"peer_id"`
+	Reason string  `json:"reason"`
+	// Operator is who banned the peer; empty for automatic bans
+	Operator string `json:"operator,omitempty"`
+	// Auto is set when the peer was banned for misbehaving
+	Auto     bool      `json:"auto,omitempty"`
+	BannedAt time.Time `json:"banned_at"`
+	// ExpiresAt is when the ban lifts; zero means never
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// Active reports whether the ban is in effect at t.
+func (b *Ban) Active(t time.Time) bool {
+	return b.ExpiresAt.IsZero() || t.Before(b.ExpiresAt)
+}
+
+// misbehaviorScore is a peer's decaying misbehavior score.
+type misbehaviorScore struct {
+	value   float64
+	updated time.Time
+}
+
+// messageBucket is a token bucket limiting a peer's message rate.
+type messageBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// BanList tracks banned peers and the misbehavior scores that lead to
+// automatic bans. It is the host's connection gater, so banned peers can
+// neither dial nor be dialed, and the pubsub blacklist, so their messages
+// are dropped even over connections opened before the ban.
+type BanList struct {
+	mu      sync.Mutex
+	config  BanConfig
+	bans    map[peer.ID]*Ban
+	scores  map[peer.ID]*misbehaviorScore
+	buckets map[peer.ID]*messageBucket
+	pruned  time.Time
+
+	onBan func(*Ban)
+	now   func() time.Time
+}
+
+// NewBanList creates an empty ban list
+func NewBanList(config BanConfig) *BanList {
+	return &BanList{
+		config:  config,
+		bans:    make(map[peer.ID]*Ban),
+		scores:  make(map[peer.ID]*misbehaviorScore),
+		buckets: make(map[peer.ID]*messageBucket),
+		now:     time.Now,
+	}
+}
+
+// SetBanHandler sets the callback invoked whenever a peer is banned,
+// explicitly or automatically.
+func (b *BanList) SetBanHandler(fn func(*Ban)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onBan = fn
+}
+
+// Ban bans a peer for duration (0 bans it until it is unbanned),
+// replacing any existing ban.
+func (b *BanList) Ban(id peer.ID, reason, operator string, duration time.Duration) *Ban {
+	now := b.now()
+	ban := &Ban{PeerID: id, Reason: reason, Operator: operator, BannedAt: now}
+	if duration > 0 {
+		ban.ExpiresAt = now.Add(duration)
+	}
+
+	b.mu.Lock()
+	b.bans[id] = ban
+	handler := b.onBan
+	b.mu.Unlock()
+
+	if handler != nil {
+		handler(ban)
+	}
+	return ban
+}
+
+// Unban lifts a peer's ban and clears its misbehavior score. It reports
+// whether the peer was banned.
+func (b *BanList) Unban(id peer.ID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ban, ok := b.bans[id]
+	delete(b.bans, id)
+	delete(b.scores, id)
+	return ok && ban.Active(b.now())
+}
+
+// IsBanned reports whether a peer is banned.
+func (b *BanList) IsBanned(id peer.ID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.bannedLocked(id, b.now())
+}
+
+func (b *BanList) bannedLocked(id peer.ID, now time.Time) bool {
+	ban, ok := b.bans[id]
+	if !ok {
+		return false
+	}
+	if !ban.Active(now) {
+		delete(b.bans, id)
+		return false
+	}
+	return true
+}
+
+// Bans returns the bans in effect, oldest first.
+func (b *BanList) Bans() []*Ban {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	bans := make([]*Ban, 0, len(b.bans))
+	for id, ban := range b.bans {
+		if b.bannedLocked(id, now) {
+			bans = append(bans, ban)
+		}
+	}
+	slices.SortFunc(bans, func(x, y *Ban) int { return x.BannedAt.Compare(y.BannedAt) })
+	return bans
+}
+
+// Restore reinstates saved bans, skipping expired ones, and returns how
+// many were restored. The ban handler is not called.
+func (b *BanList) Restore(bans []*Ban) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	restored := 0
+	for _, ban := range bans {
+		if ban == nil || ban.PeerID == "" || !ban.Active(now) {
+			continue
+		}
+		b.bans[ban.PeerID] = ban
+		restored++
+	}
+	return restored
+}
+
+// Score returns a peer's current misbehavior score.
+func (b *BanList) Score(id peer.ID) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.scores[id]
+	if !ok {
+		return 0
+	}
+	return b.decayed(s, b.now())
+}
+
+func (b *BanList) decayed(s *misbehaviorScore, now time.Time) float64 {
+	if b.config.ScoreHalfLife <= 0 {
+		return s.value
+	}
+	halves := float64(now.Sub(s.updated)) / float64(b.config.ScoreHalfLife)
+	return s.value * math.Pow(0.5, halves)
+}
+
+// Report penalizes a peer for misbehaving. It returns the ban when the
+// peer's score reaches the auto ban threshold, or nil.
+func (b *BanList) Report(id peer.ID, kind Misbehavior) *Ban {
+	b.mu.Lock()
+	ban := b.reportLocked(id, kind)
+	handler := b.onBan
+	b.mu.Unlock()
+
+	if ban != nil && handler != nil {
+		handler(ban)
+	}
+	return ban
+}
+
+func (b *BanList) reportLocked(id peer.ID, kind Misbehavior) *Ban {
+	now := b.now()
+	if b.bannedLocked(id, now) {
+		return nil
+	}
+
+	s, ok := b.scores[id]
+	if !ok {
+		s = &misbehaviorScore{}
+		b.scores[id] = s
+	}
+	s.value = b.decayed(s, now) + misbehaviorWeights[kind]
+	s.updated = now
+
+	if b.config.AutoBanThreshold <= 0 || s.value < b.config.AutoBanThreshold {
+		return nil
+	}
+	ban := &Ban{PeerID: id, Reason: string(kind), Auto: true, BannedAt: now}
+	if b.config.AutoBanDuration > 0 {
+		ban.ExpiresAt = now.Add(b.config.AutoBanDuration)
+	}
+	b.bans[id] = ban
+	delete(b.scores, id)
+	return ban
+}
+
+// AllowMessage takes one message from a peer's rate limit. A message
+// beyond the limit counts as misbehavior.
+func (b *BanList) AllowMessage(id peer.ID) bool {
+	b.mu.Lock()
+	if b.config.MessageRate <= 0 {
+		b.mu.Unlock()
+		return true
+	}
+
+	now := b.now()
+	b.pruneLocked(now)
+	burst := float64(max(b.config.MessageBurst, 1))
+	bucket, ok := b.buckets[id]
+	if !ok {
+		bucket = &messageBucket{tokens: burst, updated: now}
+		b.buckets[id] = bucket
+	}
+	bucket.tokens = min(burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*b.config.MessageRate)
+	bucket.updated = now
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		b.mu.Unlock()
+		return true
+	}
+
+	ban := b.reportLocked(id, MisbehaviorRateLimit)
+	handler := b.onBan
+	b.mu.Unlock()
+
+	if ban != nil && handler != nil {
+		handler(ban)
+	}
+	return false
+}
+
+// pruneLocked drops rate limit buckets that have refilled and scores that
+// have decayed away, at most once per score half-life.
+func (b *BanList) pruneLocked(now time.Time) {
+	interval := b.config.ScoreHalfLife
+	if interval <= 0 {
+		interval = DefaultBanConfig().ScoreHalfLife
+	}
+	if now.Sub(b.pruned) < interval {
+		return
+	}
+	b.pruned = now
+
+	burst := float64(max(b.config.MessageBurst, 1))
+	for id, bucket := range b.buckets {
+		if bucket.tokens+now.Sub(bucket.updated).Seconds()*b.config.MessageRate >= burst {
+			delete(b.buckets, id)
+		}
+	}
+	for id, s := range b.scores {
+		if b.decayed(s, now) < 1 {
+			delete(b.scores, id)
+		}
+	}
+}
+
+// InterceptPeerDial refuses to dial banned peers.
+func (b *BanList) InterceptPeerDial(p peer.ID) bool {
+	return !b.IsBanned(p)
+}
+
+// InterceptAddrDial refuses to dial banned peers.
+func (b *BanList) InterceptAddrDial(p peer.ID, _ multiaddr.Multiaddr) bool {
+	return !b.IsBanned(p)
+}
+
+// InterceptAccept accepts every inbound connection; the peer is not
+// known until the connection is secured.
+func (b *BanList) InterceptAccept(network.ConnMultiaddrs) bool {
+	return true
+}
+
+// InterceptSecured refuses connections from banned peers.
+func (b *BanList) InterceptSecured(_ network.Direction, p peer.ID, _ network.ConnMultiaddrs) bool {
+	return !b.IsBanned(p)
+}
+
+// InterceptUpgraded refuses connections from banned peers.
+func (b *BanList) InterceptUpgraded(c network.Conn) (bool, control.DisconnectReason) {
+	return !b.IsBanned(c.RemotePeer()), 0
+}
+
+// pubsubBlacklist makes pubsub drop messages from banned peers.
+type pubsubBlacklist struct {
+	bans *BanList
+}
+
+// Add bans a peer pubsub blacklisted until it is unbanned.
+func (bl pubsubBlacklist) Add(p peer.ID) bool {
+	bl.bans.Ban(p, "blacklisted by pubsub", "", 0)
+	return true
+}
+
+// Contains reports whether a peer is banned.
+func (bl pubsubBlacklist) Contains(p peer.ID) bool {
+	return bl.bans.IsBanned(p)
+}
+
+// messageValidator drops pubsub messages from peers over their rate limit
+// and rejects messages that cannot be decoded, penalizing their author.
+// Messages on encrypted topics are only rate limited, since they cannot
+// be decoded before decryption.
+func (n *Node) messageValidator(topicName string) pubsub.ValidatorEx {
+	return func(_ context.Context, _ peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+		author := msg.GetFrom()
+		if author == n.host.ID() {
+			return pubsub.ValidationAccept
+		}
+		if !n.bans.AllowMessage(author) {
+			n.metrics.RecordError("message_rate_limited")
+			return pubsub.ValidationIgnore
+		}
+		if n.aclMgr != nil {
+			if acl := n.aclMgr.GetACL(topicName); acl != nil && acl.encrypted() {
+				return pubsub.ValidationAccept
+			}
+		}
+		if !wellFormed(msg.Data) {
+			n.metrics.RecordError("message_malformed")
+			n.bans.Report(author, MisbehaviorMalformed)
+			return pubsub.ValidationReject
+		}
+		return pubsub.ValidationAccept
+	}
+}
+
+// wellFormed reports whether a pubsub payload, compressed or raw, holds a
+// JSON message or a batch of them.
+func wellFormed(data []byte) bool {
+	if decompressed, err := DecompressMessage(data); err == nil {
+		data = decompressed
+	}
+	messages, err := UnbatchMessage(data)
+	if err != nil {
+		return false
+	}
+	for _, m := range messages {
+		if !json.Valid(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// BanPeer bans a peer for duration (0 until unbanned) and disconnects it.
+func (n *Node) BanPeer(id peer.ID, reason, operator string, duration time.Duration) *Ban {
+	return n.bans.Ban(id, reason, operator, duration)
+}
+
+// UnbanPeer lifts a peer's ban. It reports whether the peer was banned.
+func (n *Node) UnbanPeer(id peer.ID) bool {
+	return n.bans.Unban(id)
+}
+
+// SetBanHandler sets the callback invoked when a peer is banned,
+// explicitly or for misbehaving.
+func (n *Node) SetBanHandler(fn func(*Ban)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.onBan = fn
+}
+
+// handleBan disconnects a banned peer and calls the ban handler.
+func (n *Node) handleBan(ban *Ban) {
+	n.metrics.RecordError("peer_banned")
+	// Bans can come from pubsub validation; do not block it on closing
+	go n.host.Network().ClosePeer(ban.PeerID)
+
+	n.mu.RLock()
+	handler := n.onBan
+	n.mu.RUnlock()
+	if handler != nil {
+		handler(ban)
+	}
+}
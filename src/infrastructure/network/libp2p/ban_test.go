@@ -0,0 +1,216 @@
+package libp2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+)
+
+// fakeClock returns a ban list whose clock only moves when advanced.
+func fakeClock(bans *BanList) func(time.Duration) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	bans.now = func() time.Time { return now }
+	return func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestBanList_BanExpiresAndUnbans(t *testing.T) {
+	bans := NewBanList(DefaultBanConfig())
+	advance := fakeClock(bans)
+
+	var banned []*Ban
+	bans.SetBanHandler(func(b *Ban) { banned = append(banned, b) })
+
+	bans.Ban("peer-a", "flooding", "alice", time.Hour)
+	bans.Ban("peer-b", "compromised", "alice", 0)
+	if !bans.IsBanned("peer-a") || !bans.IsBanned("peer-b") {
+		t.Fatal("expected both peers banned")
+	}
+	if len(banned) != 2 {
+		t.Errorf("expected the handler called for each ban, got %d", len(banned))
+	}
+	if bans.InterceptPeerDial("peer-a") {
+		t.Error("gater should refuse to dial a banned peer")
+	}
+
+	advance(2 * time.Hour)
+	if bans.IsBanned("peer-a") {
+		t.Error("expected the timed ban to expire")
+	}
+	if got := bans.Bans(); len(got) != 1 || got[0].PeerID != "peer-b" {
+		t.Errorf("expected only the permanent ban left, got %v", got)
+	}
+
+	if !bans.Unban("peer-b") || bans.IsBanned("peer-b") {
+		t.Error("expected peer-b unbanned")
+	}
+	if bans.Unban("peer-b") {
+		t.Error("unbanning a peer that is not banned should report false")
+	}
+}
+
+func TestBanList_AutoBanOnMalformedMessages(t *testing.T) {
+	cfg := DefaultBanConfig()
+	cfg.AutoBanThreshold = 50
+	bans := NewBanList(cfg)
+	advance := fakeClock(bans)
+
+	bans.Report("peer-a", MisbehaviorMalformed)
+	bans.Report("peer-a", MisbehaviorMalformed)
+	// The score decays, so faults spread out are forgiven
+	advance(cfg.ScoreHalfLife)
+	if ban := bans.Report("peer-a", MisbehaviorMalformed); ban != nil {
+		t.Fatalf("expected the decayed score to stay under the threshold, got %v", bans.Score("peer-a"))
+	}
+
+	ban := bans.Report("peer-a", MisbehaviorMalformed)
+	if ban == nil || !ban.Auto || ban.Reason != string(MisbehaviorMalformed) {
+		t.Fatalf("expected an automatic ban, got %+v", ban)
+	}
+	if !ban.ExpiresAt.Equal(ban.BannedAt.Add(cfg.AutoBanDuration)) {
+		t.Errorf("expected the ban to expire after %s", cfg.AutoBanDuration)
+	}
+	if bans.Score("peer-a") != 0 {
+		t.Error("expected the score reset once banned")
+	}
+}
+
+func TestBanList_RateLimitAbuseBans(t *testing.T) {
+	cfg := BanConfig{AutoBanThreshold: 5, AutoBanDuration: time.Minute, MessageRate: 10, MessageBurst: 3}
+	bans := NewBanList(cfg)
+	advance := fakeClock(bans)
+
+	for i := range 3 {
+		if !bans.AllowMessage("peer-a") {
+			t.Fatalf("message %d within the burst was refused", i)
+		}
+	}
+	if bans.AllowMessage("peer-a") {
+		t.Fatal("expected the message beyond the burst refused")
+	}
+	advance(100 * time.Millisecond)
+	if !bans.AllowMessage("peer-a") {
+		t.Fatal("expected the bucket to refill at the message rate")
+	}
+
+	for range 4 {
+		bans.AllowMessage("peer-a")
+	}
+	if !bans.IsBanned("peer-a") {
+		t.Errorf("expected the flooding peer banned, score %v", bans.Score("peer-a"))
+	}
+	if bans.IsBanned("peer-b") {
+		t.Error("other peers should be unaffected")
+	}
+}
+
+func TestBanList_RestoreSkipsExpired(t *testing.T) {
+	bans := NewBanList(DefaultBanConfig())
+	now := time.Now()
+
+	restored := bans.Restore([]*Ban{
+		{PeerID: "peer-a", BannedAt: now.Add(-time.Hour)},
+		{PeerID: "peer-b", BannedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)},
+		{PeerID: "peer-c", BannedAt: now, ExpiresAt: now.Add(time.Hour)},
+	})
+	if restored != 2 || !bans.IsBanned("peer-a") || bans.IsBanned("peer-b") || !bans.IsBanned("peer-c") {
+		t.Errorf("expected the 2 active bans restored, got %d", restored)
+	}
+}
+
+func TestWellFormed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"raw json", []byte(`{"type":"lock_intent"}`), true},
+		{"compressed json", CompressMessageAbove([]byte(`{"type":"delta"}`), 0), true},
+		{"batch", []byte(`{"type":"batch","messages":[{"type":"a"},{"type":"b"}]}`), true},
+		{"garbage", []byte("not json"), false},
+		{"compressed garbage", CompressMessageAbove([]byte("not json"), 0), false},
+	}
+	for _, tt := range tests {
+		if got := wellFormed(tt.data); got != tt.want {
+			t.Errorf("%s: wellFormed = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNode_AutoBansPeerSendingMalformedMessages(t *testing.T) {
+	ctx := context.Background()
+	mn := mocknet.New()
+	t.Cleanup(func() { mn.Close() })
+
+	hosts := make([]*Node, 2)
+	for i := range hosts {
+		h, err := mn.GenPeer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg := DefaultConfig()
+		banConfig := DefaultBanConfig()
+		banConfig.AutoBanThreshold = 30
+		cfg.BanConfig = &banConfig
+		node, err := NewNodeWithHost(ctx, h, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { node.Close() })
+		hosts[i] = node
+	}
+	receiver, sender := hosts[0], hosts[1]
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mn.ConnectPeers(receiver.ID(), sender.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	banned := make(chan *Ban, 1)
+	receiver.SetBanHandler(func(b *Ban) { banned <- b })
+
+	const topic = "/agent-collab/test/bans"
+	for _, n := range hosts {
+		if _, err := n.Subscribe(topic); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitForTopicPeer(t, sender, topic, receiver.ID())
+
+	for range 2 {
+		if err := sender.PublishRaw(ctx, topic, []byte("not json")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case ban := <-banned:
+		if ban.PeerID != sender.ID() || !ban.Auto {
+			t.Errorf("expected the sender auto-banned, got %+v", ban)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("sender was not banned, score %v", receiver.BanList().Score(sender.ID()))
+	}
+
+	if !receiver.UnbanPeer(sender.ID()) || receiver.BanList().IsBanned(sender.ID()) {
+		t.Error("expected the operator to lift the ban")
+	}
+}
+
+// waitForTopicPeer waits until n sees other in topic's mesh.
+func waitForTopicPeer(t *testing.T, n *Node, topic string, other peer.ID) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, p := range n.pubsub.ListPeers(topic) {
+			if p == other {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("peer %s did not join topic %s", other, topic)
+}
@@ -15,8 +15,8 @@ const (
 	CompressionZstd CompressionType = 0x01
 )
 
-// compressionThreshold is the minimum size for compression (1KB)
-const compressionThreshold = 1024
+// DefaultCompressionThreshold is the minimum size for compression (1KB)
+const DefaultCompressionThreshold = 1024
 
 // compressionRatio is the minimum compression ratio to apply compression (20% reduction)
 const compressionRatio = 0.8
@@ -48,8 +48,15 @@ type CompressedMessage struct {
 
 // CompressMessage compresses data if it's large enough and compression is beneficial
 func CompressMessage(data []byte) []byte {
+	return CompressMessageAbove(data, DefaultCompressionThreshold)
+}
+
+// CompressMessageAbove compresses data of at least threshold bytes when it
+// helps. Smaller messages are sent with a CompressionNone header. A negative
+// threshold disables compression.
+func CompressMessageAbove(data []byte, threshold int) []byte {
 	// Don't compress small messages
-	if len(data) < compressionThreshold {
+	if threshold < 0 || len(data) < threshold {
 		return wrapUncompressed(data)
 	}
 
@@ -2,8 +2,10 @@ package libp2p
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestCompressMessage_SmallData(t *testing.T) {
@@ -75,6 +77,82 @@ func TestCompressMessage_LargeIncompressibleData(t *testing.T) {
 	}
 }
 
+func TestCompressMessageAbove_Threshold(t *testing.T) {
+	payload := []byte(strings.Repeat(`{"type":"delta","file_path":"main.go"}`, 20)) // ~780 bytes
+
+	tests := []struct {
+		name      string
+		threshold int
+		want      CompressionType
+	}{
+		{"below threshold", 4096, CompressionNone},
+		{"above threshold", 256, CompressionZstd},
+		{"disabled", -1, CompressionNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wire := CompressMessageAbove(payload, tt.threshold)
+			if CompressionType(wire[0]) != tt.want {
+				t.Errorf("expected codec %d, got %d", tt.want, wire[0])
+			}
+			got, err := DecompressMessage(wire)
+			if err != nil {
+				t.Fatalf("round trip failed: %v", err)
+			}
+			if !bytes.Equal(got, payload) {
+				t.Error("payload changed in round trip")
+			}
+		})
+	}
+}
+
+func TestNode_PublishAppliesCompressionThreshold(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cfg := DefaultConfig()
+	cfg.ListenAddrs = []string{"/ip4/127.0.0.1/tcp/0"}
+	cfg.CompressionThreshold = 512
+	node, err := NewNode(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	defer node.Close()
+
+	const topic = "/agent-collab/test/compression"
+	sub, err := node.Subscribe(topic)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	small := []byte(`{"type":"presence"}`)
+	large := []byte(strings.Repeat(`{"type":"delta","file_path":"main.go"}`, 50))
+
+	for _, tc := range []struct {
+		payload []byte
+		want    CompressionType
+	}{
+		{small, CompressionNone},
+		{large, CompressionZstd},
+	} {
+		if err := node.Publish(ctx, topic, tc.payload); err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			t.Fatalf("did not receive own message: %v", err)
+		}
+		if CompressionType(msg.Data[0]) != tc.want {
+			t.Errorf("%d-byte payload: expected codec %d, got %d", len(tc.payload), tc.want, msg.Data[0])
+		}
+		got, err := DecompressMessage(msg.Data)
+		if err != nil || !bytes.Equal(got, tc.payload) {
+			t.Errorf("%d-byte payload did not round trip: %v", len(tc.payload), err)
+		}
+	}
+}
+
 func TestDecompressMessage_InvalidData(t *testing.T) {
 	// Too short
 	_, err := DecompressMessage([]byte{0x00})
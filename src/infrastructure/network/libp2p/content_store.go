@@ -28,6 +28,9 @@ type ContentStore struct {
 	maxSize  int64 // Maximum total storage size
 	curSize  int64 // Current storage size
 	ttl      time.Duration
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
 }
 
 // ContentMetadata holds metadata about stored content
@@ -67,6 +70,7 @@ func NewContentStore(config ContentStoreConfig) *ContentStore {
 		metadata: make(map[ContentID]*ContentMetadata),
 		maxSize:  config.MaxSize,
 		ttl:      config.TTL,
+		stopCh:   make(chan struct{}),
 	}
 
 	// Start cleanup goroutine
@@ -276,11 +280,21 @@ func (cs *ContentStore) cleanupLoop() {
 	ticker := time.NewTicker(time.Minute)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		cs.cleanup()
+	for {
+		select {
+		case <-ticker.C:
+			cs.cleanup()
+		case <-cs.stopCh:
+			return
+		}
 	}
 }
 
+// Close stops the cleanup goroutine. It is safe to call more than once.
+func (cs *ContentStore) Close() {
+	cs.stopOnce.Do(func() { close(cs.stopCh) })
+}
+
 func (cs *ContentStore) cleanup() {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
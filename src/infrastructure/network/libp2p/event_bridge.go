@@ -23,6 +23,9 @@ type EventBridge struct {
 	// Optional interest manager for sync
 	interestMgr *interest.Manager
 
+	// Topic names for the configured scope
+	topics TopicSet
+
 	// Subscription for events topic
 	eventSub *pubsub.Subscription
 
@@ -41,6 +44,7 @@ func NewEventBridge(node *Node, router *event.Router) *EventBridge {
 	bridge := &EventBridge{
 		node:      node,
 		router:    router,
+		topics:    GlobalTopicSet(),
 		connected: true,
 	}
 
@@ -62,6 +66,20 @@ func (b *EventBridge) SetInterestManager(mgr *interest.Manager) {
 	}
 }
 
+// SetTopics sets the topic scope used for events. Call before Start.
+func (b *EventBridge) SetTopics(topics TopicSet) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.topics = topics
+}
+
+// eventsTopic returns the events topic for the configured scope.
+func (b *EventBridge) eventsTopic() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.topics.Events()
+}
+
 // Start starts the event bridge.
 func (b *EventBridge) Start(ctx context.Context) error {
 	b.mu.Lock()
@@ -75,7 +93,7 @@ func (b *EventBridge) Start(ctx context.Context) error {
 	b.mu.Unlock()
 
 	// Subscribe to events topic
-	sub, err := b.node.Subscribe(TopicEvents)
+	sub, err := b.node.Subscribe(b.eventsTopic())
 	if err != nil {
 		b.mu.Lock()
 		b.running = false
@@ -126,8 +144,10 @@ func (b *EventBridge) IsRunning() bool {
 	return b.running
 }
 
-// broadcast publishes an event to the P2P network.
-func (b *EventBridge) broadcast(topic string, data []byte) error {
+// broadcast publishes an event to the P2P network. The router always
+// names the global events topic; the bridge publishes to the events topic
+// of its own scope instead so both ends stay in the same scope.
+func (b *EventBridge) broadcast(_ string, data []byte) error {
 	b.mu.RLock()
 	running := b.running
 	b.mu.RUnlock()
@@ -137,7 +157,7 @@ func (b *EventBridge) broadcast(topic string, data []byte) error {
 		return nil
 	}
 
-	return b.node.Publish(b.ctx, topic, data)
+	return b.node.Publish(b.ctx, b.eventsTopic(), data)
 }
 
 // handleMessages handles incoming messages from the P2P network.
@@ -171,7 +191,7 @@ func (b *EventBridge) HandleIncomingMessage(ctx context.Context, data []byte) {
 	}
 
 	// Decrypt if needed
-	decompressed, err = b.node.DecryptMessage(TopicEvents, decompressed)
+	decompressed, err = b.node.DecryptMessage(b.eventsTopic(), decompressed)
 	if err != nil {
 		return
 	}
@@ -222,7 +242,7 @@ func (b *EventBridge) broadcastInterestChange(change interest.InterestChange) {
 		return
 	}
 
-	b.broadcast(TopicEvents, data)
+	b.broadcast(b.eventsTopic(), data)
 }
 
 // SyncInterests synchronizes interests with connected peers.
@@ -257,5 +277,5 @@ func (b *EventBridge) SyncInterests(ctx context.Context) error {
 		return err
 	}
 
-	return b.broadcast(TopicEvents, data)
+	return b.broadcast(b.eventsTopic(), data)
 }
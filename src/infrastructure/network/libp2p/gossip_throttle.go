@@ -0,0 +1,457 @@
+package libp2p
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// GossipRelayProtocolID carries batches of throttled topic messages to a
+// gateway peer in a far region.
+const GossipRelayProtocolID = protocol.ID("/agent-collab/gossip-relay/1.0.0")
+
+// maxRelayBatchBytes bounds a relay batch, before and after decompression.
+const maxRelayBatchBytes = 4 * 1024 * 1024
+
+// relayInboxSize is how many relayed messages per topic may wait for the
+// message processor before new ones are dropped.
+const relayInboxSize = 256
+
+// GossipThrottleConfig configures locality-aware gossip for high-volume
+// topics.
+type GossipThrottleConfig struct {
+	// Topics are the throttled topics, matched by name suffix so that
+	// project scoped topics match as well (default: context/sync)
+	Topics []string
+	// BatchDelay is how long a message waits for others before the batch
+	// is sent to the remote gateways (default: 500ms)
+	BatchDelay time.Duration
+	// MaxBatchBytes sends a batch early once it holds this many bytes
+	// (default: 256KB)
+	MaxBatchBytes int
+	// SeenTTL is how long relayed messages are remembered to drop copies
+	// arriving over another path (default: 5m)
+	SeenTTL time.Duration
+	// SendTimeout bounds opening a relay stream and writing a batch
+	// (default: 10s)
+	SendTimeout time.Duration
+}
+
+// DefaultGossipThrottleConfig returns the default configuration
+func DefaultGossipThrottleConfig() GossipThrottleConfig {
+	return GossipThrottleConfig{
+		Topics:        []string{"context/sync"},
+		BatchDelay:    500 * time.Millisecond,
+		MaxBatchBytes: 256 * 1024,
+		SeenTTL:       5 * time.Minute,
+		SendTimeout:   10 * time.Second,
+	}
+}
+
+// withGossipThrottleDefaults fills unset fields of config with the defaults
+func withGossipThrottleDefaults(config GossipThrottleConfig) GossipThrottleConfig {
+	def := DefaultGossipThrottleConfig()
+	if len(config.Topics) == 0 {
+		config.Topics = def.Topics
+	}
+	if config.BatchDelay <= 0 {
+		config.BatchDelay = def.BatchDelay
+	}
+	if config.MaxBatchBytes <= 0 {
+		config.MaxBatchBytes = def.MaxBatchBytes
+	}
+	if config.SeenTTL <= 0 {
+		config.SeenTTL = def.SeenTTL
+	}
+	if config.SendTimeout <= 0 {
+		config.SendTimeout = def.SendTimeout
+	}
+	return config
+}
+
+// GossipThrottleStats measures the WAN traffic of throttled topics
+type GossipThrottleStats struct {
+	// RelayedMessages and RelayBatches count what was sent to gateways
+	RelayedMessages int64 `json:"relayed_messages"`
+	RelayBatches    int64 `json:"relay_batches"`
+	// RelayBytes is what crossed the WAN to gateways, after compression
+	RelayBytes int64 `json:"relay_bytes"`
+	// FloodBytes is what publishing this node's messages to every remote
+	// peer directly would have sent
+	FloodBytes int64 `json:"flood_bytes"`
+	// SavedBytes is FloodBytes minus RelayBytes
+	SavedBytes int64 `json:"saved_bytes"`
+	// ReceivedMessages were relayed to this node and delivered locally;
+	// DuplicateMessages had already arrived over another path
+	ReceivedMessages  int64 `json:"received_messages"`
+	DuplicateMessages int64 `json:"duplicate_messages"`
+	DroppedMessages   int64 `json:"dropped_messages"`
+	FailedSends       int64 `json:"failed_sends"`
+	// Gateways is how many far regions the last batch was sent to
+	Gateways int `json:"gateways"`
+}
+
+// relayBatch is the wire format of a relay stream
+type relayBatch struct {
+	Messages []relayedMessage `json:"messages"`
+}
+
+// relayedMessage is a message as published on its topic
+type relayedMessage struct {
+	Topic string `json:"topic"`
+	Data  []byte `json:"data"`
+}
+
+// GossipThrottle keeps high-volume topics off the WAN. Peers in far
+// regions that speak the relay protocol are left out of the gossip mesh
+// of throttled topics; instead, messages are batched, compressed and sent
+// to one gateway per far region, which publishes them to its local mesh.
+type GossipThrottle struct {
+	host   host.Host
+	config GossipThrottleConfig
+	bans   *BanList
+
+	mu        sync.Mutex
+	locality  *LocalityManager
+	publish   func(ctx context.Context, topic string, data []byte) error
+	pending   []relayedMessage
+	pendingSz int
+	timer     *time.Timer
+	seen      map[[32]byte]time.Time
+	inbox     map[string]chan []byte
+	gateways  int
+
+	relayedMessages   atomic.Int64
+	relayBatches      atomic.Int64
+	relayBytes        atomic.Int64
+	floodBytes        atomic.Int64
+	receivedMessages  atomic.Int64
+	duplicateMessages atomic.Int64
+	droppedMessages   atomic.Int64
+	failedSends       atomic.Int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewGossipThrottle creates a gossip throttle and registers the relay
+// stream handler. Relayed messages from banned peers are dropped.
+func NewGossipThrottle(h host.Host, config GossipThrottleConfig, bans *BanList) *GossipThrottle {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &GossipThrottle{
+		host:   h,
+		config: withGossipThrottleDefaults(config),
+		bans:   bans,
+		seen:   make(map[[32]byte]time.Time),
+		inbox:  make(map[string]chan []byte),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	h.SetStreamHandler(GossipRelayProtocolID, t.handleStream)
+	return t
+}
+
+// SetLocality sets the locality manager that classifies peers
+func (t *GossipThrottle) SetLocality(lm *LocalityManager) {
+	t.mu.Lock()
+	t.locality = lm
+	t.mu.Unlock()
+}
+
+// SetPublisher sets how relayed messages are published to the local mesh
+func (t *GossipThrottle) SetPublisher(fn func(ctx context.Context, topic string, data []byte) error) {
+	t.mu.Lock()
+	t.publish = fn
+	t.mu.Unlock()
+}
+
+// Close stops the throttle; pending messages are dropped
+func (t *GossipThrottle) Close() {
+	t.cancel()
+	t.host.RemoveStreamHandler(GossipRelayProtocolID)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.pending = nil
+}
+
+// Throttles reports whether topic is a throttled topic
+func (t *GossipThrottle) Throttles(topic string) bool {
+	for _, name := range t.config.Topics {
+		name = strings.TrimPrefix(name, "/")
+		if topic == name || strings.HasSuffix(topic, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowPeer is the pubsub peer filter: far peers that can receive relays
+// take no part in the gossip of throttled topics.
+func (t *GossipThrottle) AllowPeer(id peer.ID, topic string) bool {
+	if !t.Throttles(topic) {
+		return true
+	}
+	return !t.relayed(id)
+}
+
+// relayed reports whether id is a far peer reached through a gateway
+func (t *GossipThrottle) relayed(id peer.ID) bool {
+	t.mu.Lock()
+	lm := t.locality
+	t.mu.Unlock()
+	return lm != nil && lm.IsRemote(id) && t.speaksRelay(id)
+}
+
+// speaksRelay reports whether a peer supports the relay protocol
+func (t *GossipThrottle) speaksRelay(id peer.ID) bool {
+	protos, err := t.host.Peerstore().SupportsProtocols(id, GossipRelayProtocolID)
+	return err == nil && len(protos) > 0
+}
+
+// Relayed returns the messages relayed to this node on a throttled topic.
+// Pubsub does not deliver them to the local subscription, since this node
+// publishes them to its mesh itself.
+func (t *GossipThrottle) Relayed(topic string) <-chan []byte {
+	if !t.Throttles(topic) {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.inboxLocked(topic)
+}
+
+// inboxLocked returns the relay inbox of a topic, creating it if needed
+func (t *GossipThrottle) inboxLocked(topic string) chan []byte {
+	ch, ok := t.inbox[topic]
+	if !ok {
+		ch = make(chan []byte, relayInboxSize)
+		t.inbox[topic] = ch
+	}
+	return ch
+}
+
+// Relay queues a message this node published on a throttled topic for
+// the far regions.
+func (t *GossipThrottle) Relay(topic string, data []byte) {
+	if !t.Throttles(topic) {
+		return
+	}
+
+	t.mu.Lock()
+	lm := t.locality
+	t.markSeenLocked(topic, data)
+	t.mu.Unlock()
+	if lm == nil {
+		return
+	}
+
+	remote := 0
+	for _, id := range lm.GetRemotePeers() {
+		if t.speaksRelay(id) && t.host.Network().Connectedness(id) == network.Connected {
+			remote++
+		}
+	}
+	if remote == 0 {
+		return
+	}
+	t.floodBytes.Add(int64(len(data) * remote))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, relayedMessage{Topic: topic, Data: data})
+	t.pendingSz += len(data)
+	if t.pendingSz >= t.config.MaxBatchBytes {
+		t.flushLocked()
+		return
+	}
+	if t.timer == nil {
+		t.timer = time.AfterFunc(t.config.BatchDelay, t.Flush)
+	}
+}
+
+// Flush sends the pending messages to the gateways now
+func (t *GossipThrottle) Flush() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.flushLocked()
+}
+
+// flushLocked hands the pending batch to a sender goroutine
+func (t *GossipThrottle) flushLocked() {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	if len(t.pending) == 0 || t.locality == nil {
+		t.pending, t.pendingSz = nil, 0
+		return
+	}
+
+	batch := t.pending
+	t.pending, t.pendingSz = nil, 0
+	gateways := t.locality.RemoteGateways(func(id peer.ID) bool {
+		return t.speaksRelay(id) && t.host.Network().Connectedness(id) == network.Connected
+	})
+	t.gateways = len(gateways)
+	t.pruneSeenLocked(time.Now())
+
+	for _, gw := range gateways {
+		go t.send(gw, batch)
+	}
+}
+
+// send writes one compressed batch to a gateway
+func (t *GossipThrottle) send(gw peer.ID, msgs []relayedMessage) {
+	data, err := json.Marshal(relayBatch{Messages: msgs})
+	if err != nil {
+		t.failedSends.Add(1)
+		return
+	}
+	payload := CompressMessage(data)
+
+	ctx, cancel := context.WithTimeout(t.ctx, t.config.SendTimeout)
+	defer cancel()
+	s, err := t.host.NewStream(ctx, gw, GossipRelayProtocolID)
+	if err != nil {
+		t.failedSends.Add(1)
+		return
+	}
+	defer s.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.SetWriteDeadline(deadline)
+	}
+	if _, err := s.Write(payload); err != nil {
+		_ = s.Reset()
+		t.failedSends.Add(1)
+		return
+	}
+
+	t.relayBatches.Add(1)
+	t.relayedMessages.Add(int64(len(msgs)))
+	t.relayBytes.Add(int64(len(payload)))
+}
+
+// handleStream delivers a relayed batch locally and publishes it to the
+// local mesh. The origin relays to every far region itself, so batches are
+// not forwarded further.
+func (t *GossipThrottle) handleStream(s network.Stream) {
+	defer s.Close()
+
+	from := s.Conn().RemotePeer()
+	if t.bans != nil && t.bans.IsBanned(from) {
+		_ = s.Reset()
+		return
+	}
+
+	_ = s.SetReadDeadline(time.Now().Add(t.config.SendTimeout))
+	data, err := io.ReadAll(io.LimitReader(s, maxRelayBatchBytes))
+	if err != nil {
+		_ = s.Reset()
+		return
+	}
+	batch, err := decodeRelayBatch(data)
+	if err != nil {
+		_ = s.Reset()
+		return
+	}
+
+	for _, msg := range batch.Messages {
+		if !t.Throttles(msg.Topic) {
+			continue
+		}
+		t.mu.Lock()
+		fresh := t.markSeenLocked(msg.Topic, msg.Data)
+		var inbox chan []byte
+		if fresh {
+			inbox = t.inboxLocked(msg.Topic)
+		}
+		publish := t.publish
+		t.mu.Unlock()
+
+		if !fresh {
+			t.duplicateMessages.Add(1)
+			continue
+		}
+		select {
+		case inbox <- msg.Data:
+			t.receivedMessages.Add(1)
+		default:
+			t.droppedMessages.Add(1)
+		}
+		if publish != nil {
+			_ = publish(t.ctx, msg.Topic, msg.Data)
+		}
+	}
+}
+
+// decodeRelayBatch decompresses and decodes a relay batch
+func decodeRelayBatch(data []byte) (*relayBatch, error) {
+	if len(data) >= 5 && binary.BigEndian.Uint32(data[1:5]) > maxRelayBatchBytes {
+		return nil, fmt.Errorf("relay batch too large")
+	}
+	raw, err := DecompressMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	var batch relayBatch
+	if err := json.Unmarshal(raw, &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// markSeenLocked records a message and reports whether it was new
+func (t *GossipThrottle) markSeenLocked(topic string, data []byte) bool {
+	key := sha256.Sum256(append([]byte(topic+"\x00"), data...))
+	if _, ok := t.seen[key]; ok {
+		return false
+	}
+	t.seen[key] = time.Now()
+	return true
+}
+
+// pruneSeenLocked forgets messages seen longer than SeenTTL ago
+func (t *GossipThrottle) pruneSeenLocked(now time.Time) {
+	for key, at := range t.seen {
+		if now.Sub(at) > t.config.SeenTTL {
+			delete(t.seen, key)
+		}
+	}
+}
+
+// Stats returns the throttle counters
+func (t *GossipThrottle) Stats() GossipThrottleStats {
+	t.mu.Lock()
+	gateways := t.gateways
+	t.mu.Unlock()
+
+	stats := GossipThrottleStats{
+		RelayedMessages:   t.relayedMessages.Load(),
+		RelayBatches:      t.relayBatches.Load(),
+		RelayBytes:        t.relayBytes.Load(),
+		FloodBytes:        t.floodBytes.Load(),
+		ReceivedMessages:  t.receivedMessages.Load(),
+		DuplicateMessages: t.duplicateMessages.Load(),
+		DroppedMessages:   t.droppedMessages.Load(),
+		FailedSends:       t.failedSends.Load(),
+		Gateways:          gateways,
+	}
+	stats.SavedBytes = max(stats.FloodBytes-stats.RelayBytes, 0)
+	return stats
+}
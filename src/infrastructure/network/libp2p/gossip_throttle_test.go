@@ -0,0 +1,153 @@
+package libp2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+)
+
+func TestGossipThrottle_Throttles(t *testing.T) {
+	mn := mocknet.New()
+	t.Cleanup(func() { mn.Close() })
+	h, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	throttle := NewGossipThrottle(h, GossipThrottleConfig{}, nil)
+	t.Cleanup(throttle.Close)
+
+	for topic, want := range map[string]bool{
+		TopicContextSync:                 true,
+		"/agent-collab/p/context/sync":   true,
+		TopicLockIntent:                  false,
+		"/agent-collab/p/context/syncer": false,
+	} {
+		if got := throttle.Throttles(topic); got != want {
+			t.Errorf("Throttles(%q) = %v, want %v", topic, got, want)
+		}
+	}
+}
+
+// throttleCluster links an origin in seoul to two frankfurt peers and one
+// virginia peer, all speaking the relay protocol.
+func throttleCluster(t *testing.T) ([]host.Host, []*GossipThrottle) {
+	t.Helper()
+
+	mn := mocknet.New()
+	t.Cleanup(func() { mn.Close() })
+
+	hosts := make([]host.Host, 4)
+	throttles := make([]*GossipThrottle, 4)
+	for i := range hosts {
+		h, err := mn.GenPeer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hosts[i] = h
+		throttles[i] = NewGossipThrottle(h, GossipThrottleConfig{BatchDelay: time.Hour}, nil)
+		t.Cleanup(throttles[i].Close)
+	}
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultLocalityConfig()
+	config.MyRegion = "seoul"
+	lm := NewLocalityManager(hosts[0], config)
+	for i, region := range []string{"frankfurt", "frankfurt", "virginia"} {
+		id := hosts[i+1].ID()
+		if _, err := mn.ConnectPeers(hosts[0].ID(), id); err != nil {
+			t.Fatal(err)
+		}
+		if err := hosts[0].Peerstore().AddProtocols(id, GossipRelayProtocolID); err != nil {
+			t.Fatal(err)
+		}
+		lm.RegisterPeer(id, &PeerLocality{PeerID: id, Region: region, RTT: time.Duration(150+10*i) * time.Millisecond})
+	}
+	throttles[0].SetLocality(lm)
+	return hosts, throttles
+}
+
+func TestGossipThrottle_AllowPeer(t *testing.T) {
+	hosts, throttles := throttleCluster(t)
+
+	if throttles[0].AllowPeer(hosts[1].ID(), TopicContextSync) {
+		t.Error("a far peer speaking the relay protocol should be left out of the context sync mesh")
+	}
+	if !throttles[0].AllowPeer(hosts[1].ID(), TopicLockIntent) {
+		t.Error("topics that are not throttled should keep every peer")
+	}
+	if !throttles[0].AllowPeer(peer.ID("unknown"), TopicContextSync) {
+		t.Error("peers without locality information should be kept")
+	}
+}
+
+func TestGossipThrottle_RelaysBatchToOneGatewayPerRegion(t *testing.T) {
+	_, throttles := throttleCluster(t)
+
+	published := make(chan string, 4)
+	for _, throttle := range throttles[1:] {
+		throttle.SetPublisher(func(_ context.Context, topic string, _ []byte) error {
+			published <- topic
+			return nil
+		})
+	}
+
+	msg := make([]byte, 1000)
+	throttles[0].Relay(TopicContextSync, msg)
+	throttles[0].Relay(TopicContextSync, msg[:500])
+	throttles[0].Relay(TopicLockIntent, msg)
+	throttles[0].Flush()
+
+	for _, i := range []int{1, 3} {
+		inbox := throttles[i].Relayed(TopicContextSync)
+		for range 2 {
+			select {
+			case <-inbox:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("gateway %d did not receive the batch", i)
+			}
+		}
+	}
+	select {
+	case <-throttles[2].Relayed(TopicContextSync):
+		t.Error("only the lowest-RTT peer of a region should receive the batch")
+	case <-time.After(100 * time.Millisecond):
+	}
+	for range 4 {
+		if topic := <-published; topic != TopicContextSync {
+			t.Errorf("gateway republished on %q", topic)
+		}
+	}
+
+	stats := throttles[0].Stats()
+	if stats.RelayBatches != 2 || stats.RelayedMessages != 4 || stats.Gateways != 2 {
+		t.Errorf("unexpected relay stats: %+v", stats)
+	}
+	if stats.FloodBytes != 3*1500 {
+		t.Errorf("FloodBytes = %d, want %d", stats.FloodBytes, 3*1500)
+	}
+	if stats.RelayBytes == 0 || stats.SavedBytes != stats.FloodBytes-stats.RelayBytes {
+		t.Errorf("expected the relay to save bytes: %+v", stats)
+	}
+}
+
+func TestGossipThrottle_DropsDuplicateRelays(t *testing.T) {
+	hosts, throttles := throttleCluster(t)
+	gateway := throttles[1]
+
+	throttles[0].send(hosts[1].ID(), []relayedMessage{{Topic: TopicContextSync, Data: []byte("delta")}})
+	throttles[0].send(hosts[1].ID(), []relayedMessage{{Topic: TopicContextSync, Data: []byte("delta")}})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for gateway.Stats().DuplicateMessages == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if stats := gateway.Stats(); stats.ReceivedMessages != 1 || stats.DuplicateMessages != 1 {
+		t.Errorf("expected one delivery and one duplicate, got %+v", stats)
+	}
+}
@@ -0,0 +1,133 @@
+package libp2p
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// DefaultLatencySLAWindow is how long a peer must stay above its latency
+// SLA before it is marked degraded.
+const DefaultLatencySLAWindow = 2 * time.Minute
+
+// LatencySLAConfig sets the RTT peers are expected to stay under.
+type LatencySLAConfig struct {
+	// Default applies to peers without their own threshold. 0 disables it.
+	Default time.Duration
+	// Peers overrides the threshold per peer.
+	Peers map[peer.ID]time.Duration
+	// Window is how long every sample must breach the SLA before an alert
+	// fires, so transient spikes are ignored.
+	Window time.Duration
+}
+
+// LatencyAlert reports a peer crossing its latency SLA.
+type LatencyAlert struct {
+	PeerID peer.ID
+	RTT    time.Duration // latest sample
+	SLA    time.Duration
+	Since  time.Time // start of the breach
+
+	// Degraded is true when the peer breached the SLA for the whole window
+	// and false when a degraded peer came back under it.
+	Degraded bool
+}
+
+// LatencyAlertHandler is called when a peer is marked degraded or recovers.
+type LatencyAlertHandler func(alert LatencyAlert)
+
+// OnLatencyAlert registers a handler for latency SLA alerts.
+func (m *PeerQualityMonitor) OnLatencyAlert(handler LatencyAlertHandler) {
+	m.mu.Lock()
+	m.alertHandlers = append(m.alertHandlers, handler)
+	m.mu.Unlock()
+}
+
+// SetLatencySLA replaces the latency SLA settings.
+func (m *PeerQualityMonitor) SetLatencySLA(cfg LatencySLAConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config.LatencySLA = cfg
+}
+
+// LatencySLA returns the RTT threshold for a peer, or 0 if none applies.
+func (m *PeerQualityMonitor) LatencySLA(id peer.ID) time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.slaFor(id)
+}
+
+// IsDegraded reports whether a peer is breaching its latency SLA.
+func (m *PeerQualityMonitor) IsDegraded(id peer.ID) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	q, ok := m.peers[id]
+	return ok && q.Degraded
+}
+
+// GetDegradedPeers returns peers breaching their latency SLA.
+func (m *PeerQualityMonitor) GetDegradedPeers() []peer.ID {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []peer.ID
+	for id, q := range m.peers {
+		if q.Degraded {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// slaFor returns the RTT threshold for a peer (must hold m.mu).
+func (m *PeerQualityMonitor) slaFor(id peer.ID) time.Duration {
+	if sla, ok := m.config.LatencySLA.Peers[id]; ok {
+		return sla
+	}
+	return m.config.LatencySLA.Default
+}
+
+// checkLatencySLA tracks a new RTT sample against the peer's SLA and
+// returns an alert when the peer becomes degraded or recovers. A breach
+// starts with the first sample over the SLA and is reset by any sample
+// within it. Must hold m.mu.
+func (m *PeerQualityMonitor) checkLatencySLA(q *PeerQuality, rtt time.Duration, now time.Time) *LatencyAlert {
+	sla := m.slaFor(q.PeerID)
+	if sla <= 0 {
+		// SLA removed: a degraded peer no longer is
+		q.SLABreachSince = time.Time{}
+		if q.Degraded {
+			q.Degraded = false
+			return &LatencyAlert{PeerID: q.PeerID, RTT: rtt}
+		}
+		return nil
+	}
+	if rtt <= 0 {
+		return nil // no measurement
+	}
+
+	if rtt <= sla {
+		since := q.SLABreachSince
+		q.SLABreachSince = time.Time{}
+		if q.Degraded {
+			q.Degraded = false
+			return &LatencyAlert{PeerID: q.PeerID, RTT: rtt, SLA: sla, Since: since}
+		}
+		return nil
+	}
+
+	if q.SLABreachSince.IsZero() {
+		q.SLABreachSince = now
+	}
+	window := m.config.LatencySLA.Window
+	if window <= 0 {
+		window = DefaultLatencySLAWindow
+	}
+	if q.Degraded || now.Sub(q.SLABreachSince) < window {
+		return nil
+	}
+
+	q.Degraded = true
+	return &LatencyAlert{PeerID: q.PeerID, RTT: rtt, SLA: sla, Since: q.SLABreachSince, Degraded: true}
+}
@@ -321,6 +321,93 @@ func (lm *LocalityManager) SelectPeersForMesh(meshSize int) []peer.ID {
 	return result
 }
 
+// RecommendPeers returns up to n peers worth connecting to, nearest first.
+// Local and regional peers are preferred by RTT, but the lowest-RTT remote
+// peers still fill MinRemotePeers slots so the mesh keeps partition
+// tolerance. Peers without an RTT measurement, or breaching their latency
+// SLA, are not recommended.
+func (lm *LocalityManager) RecommendPeers(n int) []peer.ID {
+	if n <= 0 {
+		return nil
+	}
+
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	qm := lm.qualityMonitor
+	measured := func(ids []peer.ID) []peer.ID {
+		result := ids[:0]
+		for _, id := range ids {
+			if qm != nil && qm.IsDegraded(id) {
+				continue
+			}
+			if id != lm.nodeID && lm.peers[id].RTT > 0 {
+				result = append(result, id)
+			}
+		}
+		return result
+	}
+
+	near := append(measured(lm.getSortedPeersByRTT(lm.myRegion)), measured(lm.getSortedPeersByRTT("regional"))...)
+	remote := measured(lm.getRemotePeersSorted())
+
+	remoteTarget := min(lm.config.MinRemotePeers, len(remote), n)
+	nearTarget := min(n-remoteTarget, len(near))
+
+	result := make([]peer.ID, 0, n)
+	result = append(result, near[:nearTarget]...)
+	result = append(result, remote[:remoteTarget]...)
+
+	// Not enough nearby peers: fall back to the remaining remote peers
+	for i := remoteTarget; i < len(remote) && len(result) < n; i++ {
+		result = append(result, remote[i])
+	}
+
+	return result
+}
+
+// IsRemote reports whether a peer is in a far region, neither local nor
+// regional. Peers without locality information are not remote.
+func (lm *LocalityManager) IsRemote(id peer.ID) bool {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	loc, ok := lm.peers[id]
+	return ok && loc.Region != lm.myRegion && loc.Region != "regional"
+}
+
+// RemoteGateways returns one gateway per far region: the lowest-RTT peer
+// of the region that eligible accepts. Regions without an eligible peer
+// are skipped.
+func (lm *LocalityManager) RemoteGateways(eligible func(peer.ID) bool) []peer.ID {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	regions := make(map[string]bool)
+	for _, loc := range lm.peers {
+		if loc.Region != lm.myRegion && loc.Region != "regional" {
+			regions[loc.Region] = true
+		}
+	}
+
+	names := make([]string, 0, len(regions))
+	for region := range regions {
+		names = append(names, region)
+	}
+	sort.Strings(names)
+
+	var gateways []peer.ID
+	for _, region := range names {
+		for _, id := range lm.getSortedPeersByRTT(region) {
+			if id != lm.nodeID && eligible(id) {
+				gateways = append(gateways, id)
+				break
+			}
+		}
+	}
+	return gateways
+}
+
 // getSortedPeersByRTT returns peers in a region sorted by RTT
 func (lm *LocalityManager) getSortedPeersByRTT(region string) []peer.ID {
 	type peerRTT struct {
@@ -391,6 +478,21 @@ func (lm *LocalityManager) GetAllClusters() map[string]*LocalityCluster {
 	return result
 }
 
+// Regions returns a copy of every known cluster sorted by region name
+func (lm *LocalityManager) Regions() []LocalityCluster {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	result := make([]LocalityCluster, 0, len(lm.clusters))
+	for _, cluster := range lm.clusters {
+		result = append(result, *cluster)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Region < result[j].Region
+	})
+	return result
+}
+
 // GetGatewayPeer returns the best peer to reach a remote region
 func (lm *LocalityManager) GetGatewayPeer(region string) peer.ID {
 	lm.mu.RLock()
@@ -468,15 +570,8 @@ func (lm *LocalityManager) probeLoop() {
 	}
 }
 
-// probePeers probes RTT for all connected peers
+// probePeers probes RTT for all peers the quality monitor has measured
 func (lm *LocalityManager) probePeers() {
-	lm.mu.RLock()
-	peers := make([]peer.ID, 0, len(lm.peers))
-	for id := range lm.peers {
-		peers = append(peers, id)
-	}
-	lm.mu.RUnlock()
-
 	// Get RTT from quality monitor if available
 	lm.mu.RLock()
 	qm := lm.qualityMonitor
@@ -486,9 +581,10 @@ func (lm *LocalityManager) probePeers() {
 		return
 	}
 
-	for _, id := range peers {
-		quality := qm.GetQuality(id)
-		if quality != nil && quality.RTT > 0 {
+	// Newly measured peers are registered as well, so recommendations
+	// cover every peer we have talked to, not only pre-registered ones
+	for id, quality := range qm.GetAllQualities() {
+		if quality.RTT > 0 {
 			lm.UpdatePeerRTT(id, quality.RTT)
 		}
 	}
@@ -1,6 +1,7 @@
 package libp2p
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -178,6 +179,52 @@ func TestLocalityManager_GetPeersByRegion(t *testing.T) {
 	}
 }
 
+func TestLocalityManager_Regions(t *testing.T) {
+	config := DefaultLocalityConfig()
+	config.MyRegion = "seoul"
+
+	lm := &LocalityManager{
+		nodeID:   peer.ID("local-node"),
+		myRegion: config.MyRegion,
+		config:   config,
+		peers:    make(map[peer.ID]*PeerLocality),
+		clusters: make(map[string]*LocalityCluster),
+	}
+
+	seed := []struct {
+		id     string
+		region string
+		rtt    time.Duration
+	}{
+		{"seoul-1", "seoul", 10 * time.Millisecond},
+		{"seoul-2", "seoul", 20 * time.Millisecond},
+		{"oregon-1", "oregon", 150 * time.Millisecond},
+		{"oregon-2", "oregon", 130 * time.Millisecond},
+		{"oregon-3", "oregon", 170 * time.Millisecond},
+	}
+	for _, p := range seed {
+		lm.RegisterPeer(peer.ID(p.id), &PeerLocality{PeerID: peer.ID(p.id), Region: p.region, RTT: p.rtt})
+	}
+
+	regions := lm.Regions()
+	if len(regions) != 2 {
+		t.Fatalf("expected 2 regions, got %+v", regions)
+	}
+	oregon, seoul := regions[0], regions[1]
+	if oregon.Region != "oregon" || oregon.PeerCount != 3 || oregon.AverageRTT != 150*time.Millisecond || oregon.GatewayPeer != "oregon-2" {
+		t.Errorf("unexpected oregon breakdown: %+v", oregon)
+	}
+	if seoul.Region != "seoul" || seoul.PeerCount != 2 || seoul.AverageRTT != 15*time.Millisecond || seoul.GatewayPeer != "seoul-1" {
+		t.Errorf("unexpected seoul breakdown: %+v", seoul)
+	}
+
+	// Returned clusters are copies
+	regions[0].PeerCount = 99
+	if lm.GetCluster("oregon").PeerCount != 3 {
+		t.Error("Regions should not expose internal state")
+	}
+}
+
 func TestLocalityManager_SelectPeersForMesh(t *testing.T) {
 	config := DefaultLocalityConfig()
 	config.MyRegion = "local"
@@ -400,3 +447,180 @@ func TestLocalityManager_OnClusterChangeCallback(t *testing.T) {
 		t.Errorf("New gateway = %s, want better-peer", capturedEvent.PeerID)
 	}
 }
+
+func newRecommendTestManager(minRemote int, rtts map[string]time.Duration) *LocalityManager {
+	config := DefaultLocalityConfig()
+	config.MyRegion = "local"
+	config.MinRemotePeers = minRemote
+
+	lm := &LocalityManager{
+		nodeID:   peer.ID("local-node"),
+		myRegion: config.MyRegion,
+		config:   config,
+		peers:    make(map[peer.ID]*PeerLocality),
+		clusters: make(map[string]*LocalityCluster),
+	}
+	for id, rtt := range rtts {
+		lm.UpdatePeerRTT(peer.ID(id), rtt)
+	}
+	return lm
+}
+
+func TestLocalityManager_RecommendPeers_FavorsLowRTT(t *testing.T) {
+	lm := newRecommendTestManager(1, map[string]time.Duration{
+		"local-b":    20 * time.Millisecond,
+		"local-a":    5 * time.Millisecond,
+		"regional-a": 60 * time.Millisecond,
+		"regional-b": 90 * time.Millisecond,
+		"remote-a":   300 * time.Millisecond,
+		"remote-b":   150 * time.Millisecond,
+	})
+
+	got := lm.RecommendPeers(4)
+	want := []peer.ID{"local-a", "local-b", "regional-a", "remote-b"}
+
+	if len(got) != len(want) {
+		t.Fatalf("RecommendPeers(4) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("RecommendPeers(4)[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLocalityManager_RecommendPeers_SkipsDegradedPeers(t *testing.T) {
+	lm := newRecommendTestManager(1, map[string]time.Duration{
+		"local-a":  5 * time.Millisecond,
+		"local-b":  20 * time.Millisecond,
+		"remote-a": 150 * time.Millisecond,
+	})
+
+	config := DefaultPeerQualityConfig()
+	config.LatencySLA = LatencySLAConfig{Default: 10 * time.Millisecond, Window: time.Minute}
+	qm := &PeerQualityMonitor{config: config, peers: make(map[peer.ID]*PeerQuality)}
+	start := time.Now()
+	qm.updateQualityAt("local-b", 20*time.Millisecond, 0, start)
+	qm.updateQualityAt("local-b", 20*time.Millisecond, 0, start.Add(time.Minute))
+	lm.SetQualityMonitor(qm)
+
+	for _, id := range lm.RecommendPeers(3) {
+		if id == "local-b" {
+			t.Error("degraded peer should not be recommended")
+		}
+	}
+}
+
+func TestLocalityManager_RecommendPeers_PreservesRemoteCoverage(t *testing.T) {
+	rtts := map[string]time.Duration{
+		"remote-a": 200 * time.Millisecond,
+		"remote-b": 250 * time.Millisecond,
+		"remote-c": 400 * time.Millisecond,
+	}
+	for i := 0; i < 6; i++ {
+		rtts["local-"+string(rune('a'+i))] = time.Duration(i+1) * time.Millisecond
+	}
+	lm := newRecommendTestManager(2, rtts)
+
+	got := lm.RecommendPeers(4)
+	if len(got) != 4 {
+		t.Fatalf("expected 4 peers, got %v", got)
+	}
+
+	remote := 0
+	for _, id := range got {
+		if lm.GetLocality(id).Region == "remote" {
+			remote++
+		}
+		if id == "remote-c" {
+			t.Error("slowest remote peer should not be recommended")
+		}
+	}
+	if remote != 2 {
+		t.Errorf("expected 2 remote peers for partition tolerance, got %d (%v)", remote, got)
+	}
+	if got[0] != "local-a" || got[1] != "local-b" {
+		t.Errorf("expected lowest-RTT local peers first, got %v", got)
+	}
+}
+
+func TestLocalityManager_RecommendPeers_FallsBackToRemote(t *testing.T) {
+	lm := newRecommendTestManager(1, map[string]time.Duration{
+		"local-a":  10 * time.Millisecond,
+		"remote-a": 200 * time.Millisecond,
+		"remote-b": 300 * time.Millisecond,
+		"remote-c": 500 * time.Millisecond,
+	})
+
+	got := lm.RecommendPeers(3)
+	want := []peer.ID{"local-a", "remote-a", "remote-b"}
+	if len(got) != len(want) {
+		t.Fatalf("RecommendPeers(3) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("RecommendPeers(3)[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	if all := lm.RecommendPeers(10); len(all) != 4 {
+		t.Errorf("expected every measured peer when n exceeds peers, got %v", all)
+	}
+	if none := lm.RecommendPeers(0); len(none) != 0 {
+		t.Errorf("expected no peers for n=0, got %v", none)
+	}
+}
+
+func TestLocalityManager_RecommendPeers_SkipsUnmeasured(t *testing.T) {
+	lm := newRecommendTestManager(0, map[string]time.Duration{
+		"local-a": 10 * time.Millisecond,
+	})
+	lm.RegisterPeer("local-b", &PeerLocality{PeerID: "local-b", Region: "local"})
+
+	got := lm.RecommendPeers(2)
+	if len(got) != 1 || got[0] != "local-a" {
+		t.Errorf("expected only measured peer, got %v", got)
+	}
+}
+
+func TestLocalityManager_RemoteGateways(t *testing.T) {
+	config := DefaultLocalityConfig()
+	config.MyRegion = "seoul"
+
+	lm := &LocalityManager{
+		nodeID:   peer.ID("local-node"),
+		myRegion: config.MyRegion,
+		config:   config,
+		peers:    make(map[peer.ID]*PeerLocality),
+		clusters: make(map[string]*LocalityCluster),
+	}
+
+	register := func(id, region string, rtt time.Duration) {
+		lm.RegisterPeer(peer.ID(id), &PeerLocality{PeerID: peer.ID(id), Region: region, RTT: rtt})
+	}
+	register("seoul-1", "seoul", 5*time.Millisecond)
+	register("tokyo-1", "regional", 40*time.Millisecond)
+	register("virginia-1", "virginia", 180*time.Millisecond)
+	register("frankfurt-slow", "frankfurt", 250*time.Millisecond)
+	register("frankfurt-fast", "frankfurt", 200*time.Millisecond)
+
+	if lm.IsRemote(peer.ID("seoul-1")) || lm.IsRemote(peer.ID("tokyo-1")) || lm.IsRemote(peer.ID("unknown")) {
+		t.Error("local, regional and unknown peers should not be remote")
+	}
+	if !lm.IsRemote(peer.ID("virginia-1")) {
+		t.Error("virginia-1 should be remote")
+	}
+
+	all := func(peer.ID) bool { return true }
+	want := []peer.ID{"frankfurt-fast", "virginia-1"}
+	if got := lm.RemoteGateways(all); !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoteGateways = %v, want %v", got, want)
+	}
+
+	// An ineligible gateway falls back to the next peer of its region
+	notFast := func(id peer.ID) bool { return id != "frankfurt-fast" && id != "virginia-1" }
+	want = []peer.ID{"frankfurt-slow"}
+	if got := lm.RemoteGateways(notFast); !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoteGateways = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,481 @@
+package libp2p
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// LockPushProtocolID carries lock messages straight to a peer, ahead of
+// gossip.
+const LockPushProtocolID = protocol.ID("/agent-collab/lock-push/1.0.0")
+
+// maxLockPushBytes bounds a pushed message.
+const maxLockPushBytes = 1024 * 1024
+
+// lockPushAck is written back once a pushed message has been read.
+const lockPushAck = byte(1)
+
+// LockPushConfig configures direct delivery of lock messages.
+type LockPushConfig struct {
+	// Topics are the pushed topics, matched by name suffix so that project
+	// scoped topics match as well (default: the lock topics)
+	Topics []string
+	// Attempts is how many times a message is sent to a peer before it is
+	// dropped for that peer (default: 4)
+	Attempts int
+	// RetryBackoff is the wait before the first retry, doubling after each
+	// failed one (default: 250ms)
+	RetryBackoff time.Duration
+	// SendTimeout bounds one send, including the peer's acknowledgement
+	// (default: 2s)
+	SendTimeout time.Duration
+	// SeenTTL is how long messages are remembered to drop the copy arriving
+	// over gossip (default: 5m)
+	SeenTTL time.Duration
+}
+
+// DefaultLockPushConfig returns the default configuration
+func DefaultLockPushConfig() LockPushConfig {
+	return LockPushConfig{
+		Topics:       []string{"locks/intent", "locks/acquire", "locks/release"},
+		Attempts:     4,
+		RetryBackoff: 250 * time.Millisecond,
+		SendTimeout:  2 * time.Second,
+		SeenTTL:      5 * time.Minute,
+	}
+}
+
+// withLockPushDefaults fills unset fields of config with the defaults
+func withLockPushDefaults(config LockPushConfig) LockPushConfig {
+	def := DefaultLockPushConfig()
+	if len(config.Topics) == 0 {
+		config.Topics = def.Topics
+	}
+	if config.Attempts <= 0 {
+		config.Attempts = def.Attempts
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = def.RetryBackoff
+	}
+	if config.SendTimeout <= 0 {
+		config.SendTimeout = def.SendTimeout
+	}
+	if config.SeenTTL <= 0 {
+		config.SeenTTL = def.SeenTTL
+	}
+	return config
+}
+
+// PeerDeliveryStats measures how reliably lock messages reach one peer
+type PeerDeliveryStats struct {
+	// Delivered messages were acknowledged, after Retries extra sends in
+	// total; Dropped ones were not after every attempt
+	Delivered int64 `json:"delivered"`
+	Retries   int64 `json:"retries"`
+	Dropped   int64 `json:"dropped"`
+	// RTT is the moving average of the send-to-acknowledgement time
+	RTT        time.Duration `json:"rtt"`
+	LastUpdate time.Time     `json:"last_update"`
+}
+
+// DropRate returns the share of messages that were dropped
+func (s PeerDeliveryStats) DropRate() float64 {
+	total := s.Delivered + s.Dropped
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Dropped) / float64(total)
+}
+
+// LockPushStats counts directly delivered lock messages
+type LockPushStats struct {
+	// Pushed counts messages this node pushed; Delivered, Retries and
+	// Dropped count the sends to each peer
+	Pushed    int64 `json:"pushed"`
+	Delivered int64 `json:"delivered"`
+	Retries   int64 `json:"retries"`
+	Dropped   int64 `json:"dropped"`
+	// ReceivedMessages were pushed to this node and delivered locally;
+	// DuplicateMessages had already arrived over another path
+	ReceivedMessages  int64 `json:"received_messages"`
+	DuplicateMessages int64 `json:"duplicate_messages"`
+	DroppedMessages   int64 `json:"dropped_messages"`
+}
+
+// pushedMessage is the wire format of a lock push stream
+type pushedMessage struct {
+	Topic string `json:"topic"`
+	Data  []byte `json:"data"`
+}
+
+// LockPush sends lock messages directly to every peer that speaks the push
+// protocol, highest quality score first, so lock intents and releases do
+// not wait on gossip. Sends that fail are retried with backoff, which
+// mostly happens toward flaky peers; each outcome is fed back into the
+// quality monitor. Receivers drop the gossip copy of a pushed message.
+type LockPush struct {
+	host    host.Host
+	config  LockPushConfig
+	bans    *BanList
+	quality *PeerQualityMonitor
+
+	mu    sync.Mutex
+	skip  func(peer.ID) bool
+	seen  map[[32]byte]time.Time
+	inbox map[string]chan []byte
+	peers map[peer.ID]*PeerDeliveryStats
+
+	pushed            atomic.Int64
+	delivered         atomic.Int64
+	retries           atomic.Int64
+	dropped           atomic.Int64
+	receivedMessages  atomic.Int64
+	duplicateMessages atomic.Int64
+	droppedMessages   atomic.Int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewLockPush creates a lock push and registers its stream handler.
+// Pushes from banned peers are refused.
+func NewLockPush(h host.Host, config LockPushConfig, quality *PeerQualityMonitor, bans *BanList) *LockPush {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &LockPush{
+		host:    h,
+		config:  withLockPushDefaults(config),
+		bans:    bans,
+		quality: quality,
+		seen:    make(map[[32]byte]time.Time),
+		inbox:   make(map[string]chan []byte),
+		peers:   make(map[peer.ID]*PeerDeliveryStats),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	h.SetStreamHandler(LockPushProtocolID, p.handleStream)
+	return p
+}
+
+// Close stops the lock push; pending retries are abandoned
+func (p *LockPush) Close() {
+	p.cancel()
+	p.host.RemoveStreamHandler(LockPushProtocolID)
+}
+
+// SetSkip sets a filter for peers that should not be pushed to, such as
+// observer nodes; they still receive lock messages by gossip.
+func (p *LockPush) SetSkip(skip func(peer.ID) bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.skip = skip
+}
+
+// Pushes reports whether topic is a pushed topic
+func (p *LockPush) Pushes(topic string) bool {
+	for _, name := range p.config.Topics {
+		name = strings.TrimPrefix(name, "/")
+		if topic == name || strings.HasSuffix(topic, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pushed returns the messages pushed to this node on a topic, or nil if
+// the topic is not pushed.
+func (p *LockPush) Pushed(topic string) <-chan []byte {
+	if !p.Pushes(topic) {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.inboxLocked(topic)
+}
+
+// inboxLocked returns the push inbox of a topic, creating it if needed
+func (p *LockPush) inboxLocked(topic string) chan []byte {
+	ch, ok := p.inbox[topic]
+	if !ok {
+		ch = make(chan []byte, relayInboxSize)
+		p.inbox[topic] = ch
+	}
+	return ch
+}
+
+// FirstSeen records a message received on a pushed topic and reports
+// whether it had not arrived before, over either path. Messages on other
+// topics are always new.
+func (p *LockPush) FirstSeen(topic string, data []byte) bool {
+	if !p.Pushes(topic) {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.markSeenLocked(topic, data)
+}
+
+// Push sends a message this node published on a pushed topic to its
+// peers in the background.
+func (p *LockPush) Push(topic string, data []byte) {
+	if !p.Pushes(topic) {
+		return
+	}
+	p.mu.Lock()
+	p.markSeenLocked(topic, data)
+	p.mu.Unlock()
+
+	peers := p.orderedPeers()
+	if len(peers) == 0 {
+		return
+	}
+	payload, err := json.Marshal(pushedMessage{Topic: topic, Data: data})
+	if err != nil {
+		return
+	}
+	p.pushed.Add(1)
+	go p.deliver(peers, payload)
+}
+
+// orderedPeers returns the connected peers speaking the push protocol,
+// highest quality score first.
+func (p *LockPush) orderedPeers() []peer.ID {
+	p.mu.Lock()
+	skip := p.skip
+	p.mu.Unlock()
+
+	var peers []peer.ID
+	scores := make(map[peer.ID]float64)
+	for _, id := range p.host.Network().Peers() {
+		if p.bans != nil && p.bans.IsBanned(id) {
+			continue
+		}
+		if skip != nil && skip(id) {
+			continue
+		}
+		if protos, err := p.host.Peerstore().SupportsProtocols(id, LockPushProtocolID); err != nil || len(protos) == 0 {
+			continue
+		}
+		peers = append(peers, id)
+		scores[id] = 0.5
+		if p.quality != nil {
+			scores[id] = p.quality.GetScore(id)
+		}
+	}
+	slices.SortStableFunc(peers, func(a, b peer.ID) int {
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		}
+		return strings.Compare(string(a), string(b))
+	})
+	return peers
+}
+
+// deliver sends payload to each peer in order, leaving the peers it fails
+// to reach to be retried concurrently.
+func (p *LockPush) deliver(peers []peer.ID, payload []byte) {
+	for _, id := range peers {
+		if p.ctx.Err() != nil {
+			return
+		}
+		if !p.send(id, payload) {
+			go p.retry(id, payload)
+		}
+	}
+}
+
+// retry resends payload to a peer with exponential backoff until it is
+// acknowledged or the attempts run out.
+func (p *LockPush) retry(id peer.ID, payload []byte) {
+	backoff := p.config.RetryBackoff
+	for attempt := 1; attempt < p.config.Attempts; attempt++ {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		p.retries.Add(1)
+		p.updatePeer(id, func(s *PeerDeliveryStats) { s.Retries++ })
+		if p.host.Network().Connectedness(id) != network.Connected {
+			continue
+		}
+		if p.send(id, payload) {
+			return
+		}
+	}
+	p.dropped.Add(1)
+	p.updatePeer(id, func(s *PeerDeliveryStats) { s.Dropped++ })
+}
+
+// send writes payload to a peer and waits for its acknowledgement
+func (p *LockPush) send(id peer.ID, payload []byte) bool {
+	ctx, cancel := context.WithTimeout(p.ctx, p.config.SendTimeout)
+	defer cancel()
+
+	start := time.Now()
+	ok := p.exchange(ctx, id, payload)
+	rtt := time.Since(start)
+
+	if p.quality != nil {
+		p.quality.RecordDelivery(id, rtt, ok)
+	}
+	if !ok {
+		return false
+	}
+	p.host.Peerstore().RecordLatency(id, rtt)
+	p.delivered.Add(1)
+	p.updatePeer(id, func(s *PeerDeliveryStats) {
+		s.Delivered++
+		if s.RTT == 0 {
+			s.RTT = rtt
+		} else {
+			s.RTT = time.Duration(float64(s.RTT)*0.7 + float64(rtt)*0.3)
+		}
+	})
+	return true
+}
+
+// exchange runs one push stream
+func (p *LockPush) exchange(ctx context.Context, id peer.ID, payload []byte) bool {
+	s, err := p.host.NewStream(ctx, id, LockPushProtocolID)
+	if err != nil {
+		return false
+	}
+	defer s.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.SetDeadline(deadline)
+	}
+	if _, err := s.Write(payload); err != nil {
+		_ = s.Reset()
+		return false
+	}
+	if err := s.CloseWrite(); err != nil {
+		_ = s.Reset()
+		return false
+	}
+	ack := make([]byte, 1)
+	if _, err := io.ReadFull(s, ack); err != nil || ack[0] != lockPushAck {
+		_ = s.Reset()
+		return false
+	}
+	return true
+}
+
+// updatePeer applies fn to a peer's delivery stats
+func (p *LockPush) updatePeer(id peer.ID, fn func(*PeerDeliveryStats)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.peers[id]
+	if !ok {
+		s = &PeerDeliveryStats{}
+		p.peers[id] = s
+	}
+	fn(s)
+	s.LastUpdate = time.Now()
+}
+
+// handleStream acknowledges a pushed message and delivers it locally
+// unless it already arrived over gossip.
+func (p *LockPush) handleStream(s network.Stream) {
+	defer s.Close()
+
+	from := s.Conn().RemotePeer()
+	if p.bans != nil && p.bans.IsBanned(from) {
+		_ = s.Reset()
+		return
+	}
+
+	_ = s.SetDeadline(time.Now().Add(p.config.SendTimeout))
+	data, err := io.ReadAll(io.LimitReader(s, maxLockPushBytes))
+	if err != nil {
+		_ = s.Reset()
+		return
+	}
+	var msg pushedMessage
+	if err := json.Unmarshal(data, &msg); err != nil || !p.Pushes(msg.Topic) {
+		_ = s.Reset()
+		return
+	}
+	if _, err := s.Write([]byte{lockPushAck}); err != nil {
+		_ = s.Reset()
+		return
+	}
+
+	p.mu.Lock()
+	fresh := p.markSeenLocked(msg.Topic, msg.Data)
+	var inbox chan []byte
+	if fresh {
+		inbox = p.inboxLocked(msg.Topic)
+	}
+	p.mu.Unlock()
+
+	if !fresh {
+		p.duplicateMessages.Add(1)
+		return
+	}
+	select {
+	case inbox <- msg.Data:
+		p.receivedMessages.Add(1)
+	default:
+		p.droppedMessages.Add(1)
+	}
+}
+
+// markSeenLocked records a message and reports whether it was new,
+// forgetting messages older than SeenTTL as the set grows
+func (p *LockPush) markSeenLocked(topic string, data []byte) bool {
+	key := sha256.Sum256(append([]byte(topic+"\x00"), data...))
+	if _, ok := p.seen[key]; ok {
+		return false
+	}
+	now := time.Now()
+	if len(p.seen) >= 1024 {
+		for k, at := range p.seen {
+			if now.Sub(at) > p.config.SeenTTL {
+				delete(p.seen, k)
+			}
+		}
+	}
+	p.seen[key] = now
+	return true
+}
+
+// PeerStats returns the delivery stats of every peer pushed to
+func (p *LockPush) PeerStats() map[peer.ID]PeerDeliveryStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make(map[peer.ID]PeerDeliveryStats, len(p.peers))
+	for id, s := range p.peers {
+		stats[id] = *s
+	}
+	return stats
+}
+
+// Stats returns the lock push counters
+func (p *LockPush) Stats() LockPushStats {
+	return LockPushStats{
+		Pushed:            p.pushed.Load(),
+		Delivered:         p.delivered.Load(),
+		Retries:           p.retries.Load(),
+		Dropped:           p.dropped.Load(),
+		ReceivedMessages:  p.receivedMessages.Load(),
+		DuplicateMessages: p.duplicateMessages.Load(),
+		DroppedMessages:   p.droppedMessages.Load(),
+	}
+}
@@ -0,0 +1,148 @@
+package libp2p
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+)
+
+// lockPushCluster connects an origin to two peers, all pushing lock
+// messages.
+func lockPushCluster(t *testing.T, config LockPushConfig) ([]host.Host, []*LockPush, *PeerQualityMonitor) {
+	t.Helper()
+
+	mn := mocknet.New()
+	t.Cleanup(func() { mn.Close() })
+
+	hosts := make([]host.Host, 3)
+	for i := range hosts {
+		h, err := mn.GenPeer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hosts[i] = h
+	}
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	quality := NewPeerQualityMonitor(hosts[0], DefaultPeerQualityConfig())
+	pushes := make([]*LockPush, 3)
+	for i, h := range hosts {
+		var qm *PeerQualityMonitor
+		if i == 0 {
+			qm = quality
+		}
+		pushes[i] = NewLockPush(h, config, qm, nil)
+		t.Cleanup(pushes[i].Close)
+	}
+	for _, h := range hosts[1:] {
+		if _, err := mn.ConnectPeers(hosts[0].ID(), h.ID()); err != nil {
+			t.Fatal(err)
+		}
+		if err := hosts[0].Peerstore().AddProtocols(h.ID(), LockPushProtocolID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return hosts, pushes, quality
+}
+
+func TestLockPush_OrdersPeersByQuality(t *testing.T) {
+	hosts, pushes, quality := lockPushCluster(t, LockPushConfig{})
+
+	for range DefaultPeerQualityConfig().MinSamples {
+		quality.updateQuality(hosts[1].ID(), 400*time.Millisecond, 0.5)
+		quality.updateQuality(hosts[2].ID(), 10*time.Millisecond, 0)
+	}
+
+	want := []string{hosts[2].ID().String(), hosts[1].ID().String()}
+	var got []string
+	for _, id := range pushes[0].orderedPeers() {
+		got = append(got, id.String())
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected the high quality peer first, got %v", got)
+	}
+	if pushes[0].Pushes(TopicContextSync) || !pushes[0].Pushes("/agent-collab/p/locks/release") {
+		t.Error("expected only lock topics to be pushed")
+	}
+}
+
+func TestLockPush_SkipsFilteredPeers(t *testing.T) {
+	hosts, pushes, _ := lockPushCluster(t, LockPushConfig{})
+
+	pushes[0].SetSkip(func(id peer.ID) bool { return id == hosts[1].ID() })
+	if got := pushes[0].orderedPeers(); !slices.Equal(got, []peer.ID{hosts[2].ID()}) {
+		t.Errorf("expected the skipped peer to be left out, got %v", got)
+	}
+}
+
+func TestLockPush_DeliversAndDropsGossipCopy(t *testing.T) {
+	hosts, pushes, _ := lockPushCluster(t, LockPushConfig{})
+
+	msg := []byte("intent")
+	pushes[0].Push(TopicLockIntent, msg)
+
+	for i := 1; i < 3; i++ {
+		select {
+		case got := <-pushes[i].Pushed(TopicLockIntent):
+			if string(got) != "intent" {
+				t.Errorf("peer %d received %q", i, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("peer %d did not receive the pushed message", i)
+		}
+		if pushes[i].FirstSeen(TopicLockIntent, msg) {
+			t.Errorf("peer %d should drop the gossip copy of a pushed message", i)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for pushes[0].Stats().Delivered < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected two acknowledged sends, got %+v", pushes[0].Stats())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stats := pushes[0].PeerStats()[hosts[1].ID()]
+	if stats.Delivered != 1 || stats.RTT <= 0 {
+		t.Errorf("unexpected delivery stats: %+v", stats)
+	}
+	if hosts[0].Peerstore().LatencyEWMA(hosts[1].ID()) <= 0 {
+		t.Error("expected the acknowledgement round trip to be recorded as latency")
+	}
+}
+
+func TestLockPush_RetriesThenDropsUnreachablePeer(t *testing.T) {
+	hosts, pushes, quality := lockPushCluster(t, LockPushConfig{
+		Attempts:     3,
+		RetryBackoff: 10 * time.Millisecond,
+		SendTimeout:  500 * time.Millisecond,
+	})
+	// The peer no longer answers pushes
+	pushes[2].Close()
+
+	pushes[0].Push(TopicLockRelease, []byte("release"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for pushes[0].Stats().Dropped == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the unreachable peer to be dropped, got %+v", pushes[0].Stats())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stats := pushes[0].PeerStats()[hosts[2].ID()]
+	if stats.Retries != 2 || stats.Dropped != 1 || stats.DropRate() != 1 {
+		t.Errorf("unexpected delivery stats: %+v", stats)
+	}
+	if q := quality.GetQuality(hosts[2].ID()); q == nil || q.PacketLoss == 0 {
+		t.Errorf("expected failed sends to count as packet loss, got %+v", q)
+	}
+	if got := pushes[0].PeerStats()[hosts[1].ID()]; got.Delivered != 1 {
+		t.Errorf("expected the healthy peer to be delivered to, got %+v", got)
+	}
+}
@@ -161,6 +161,12 @@ type MetricsSnapshot struct {
 
 	// Error counts
 	Errors map[string]int64 `json:"errors"`
+
+	// Cross-region gossip throttling (nil if disabled)
+	GossipThrottle *GossipThrottleStats `json:"gossip_throttle,omitempty"`
+
+	// Lock messages pushed directly to peers (nil if disabled)
+	LockPush *LockPushStats `json:"lock_push,omitempty"`
 }
 
 // Snapshot returns a point-in-time snapshot of metrics
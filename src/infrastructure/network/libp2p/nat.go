@@ -0,0 +1,162 @@
+package libp2p
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/host/autorelay"
+	"github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/proto"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// NATConfig configures NAT traversal. Port mapping, AutoNAT, hole
+// punching (DCUtR) and dialing through circuit relay v2 are always on; a
+// node that AutoNAT finds unreachable reserves slots on relays so peers
+// behind symmetric NAT can still reach it, and upgrades relayed
+// connections to direct ones by hole punching where it can.
+type NATConfig struct {
+	// DisableAutoRelay stops reserving relay slots when the node is not
+	// publicly reachable
+	DisableAutoRelay bool
+	// DisableRelayService stops the node relaying for others once it is
+	// publicly reachable
+	DisableRelayService bool
+	// StaticRelays are tried as relays before super peers and other
+	// relay-capable peers
+	StaticRelays []peer.AddrInfo
+	// BootDelay is how long auto relay gathers candidates before picking
+	// relays
+	BootDelay time.Duration
+}
+
+// DefaultNATConfig returns the default configuration
+func DefaultNATConfig() NATConfig {
+	return NATConfig{
+		BootDelay: 30 * time.Second,
+	}
+}
+
+// natOptions returns the host options for cfg. Relay candidates come from
+// source, which is only called once the host is running.
+func natOptions(cfg NATConfig, source autorelay.PeerSource) []libp2p.Option {
+	opts := []libp2p.Option{
+		libp2p.NATPortMap(),
+		libp2p.EnableAutoNATv2(),
+		libp2p.EnableHolePunching(),
+		libp2p.EnableRelay(),
+	}
+	if !cfg.DisableRelayService {
+		opts = append(opts, libp2p.EnableRelayService())
+	}
+	if !cfg.DisableAutoRelay {
+		var relayOpts []autorelay.Option
+		if cfg.BootDelay > 0 {
+			relayOpts = append(relayOpts, autorelay.WithBootDelay(cfg.BootDelay))
+		}
+		opts = append(opts, libp2p.EnableAutoRelayWithPeerSource(source, relayOpts...))
+	}
+	return opts
+}
+
+// relayPeerSource feeds auto relay the node's relay candidates.
+func (n *Node) relayPeerSource(static []peer.AddrInfo) autorelay.PeerSource {
+	return func(ctx context.Context, num int) <-chan peer.AddrInfo {
+		candidates := n.relayCandidates(static, num)
+		ch := make(chan peer.AddrInfo, len(candidates))
+		for _, c := range candidates {
+			ch <- c
+		}
+		close(ch)
+		return ch
+	}
+}
+
+// relayCandidates returns up to num relays: static relays first, then the
+// super peers the topology manager elected, then connected peers that
+// offer a relay service, lowest latency first.
+func (n *Node) relayCandidates(static []peer.AddrInfo, num int) []peer.AddrInfo {
+	var candidates []peer.AddrInfo
+	seen := map[peer.ID]bool{n.host.ID(): true}
+	add := func(info peer.AddrInfo) {
+		if len(candidates) >= num || seen[info.ID] || len(info.Addrs) == 0 {
+			return
+		}
+		seen[info.ID] = true
+		candidates = append(candidates, info)
+	}
+
+	for _, info := range static {
+		add(info)
+	}
+
+	if tm := n.TopologyManager(); tm != nil {
+		for _, id := range append(tm.GetMySuperPeers(), tm.GetSuperPeers()...) {
+			add(n.host.Peerstore().PeerInfo(id))
+		}
+	}
+
+	var hops []peer.ID
+	for _, id := range n.host.Network().Peers() {
+		if supported, err := n.host.Peerstore().SupportsProtocols(id, proto.ProtoIDv2Hop); err == nil && len(supported) > 0 {
+			hops = append(hops, id)
+		}
+	}
+	slices.SortFunc(hops, func(a, b peer.ID) int {
+		return cmp.Compare(n.Latency(a), n.Latency(b))
+	})
+	for _, id := range hops {
+		add(n.host.Peerstore().PeerInfo(id))
+	}
+	return candidates
+}
+
+// TransportType is how a peer connection is carried.
+type TransportType string
+
+const (
+	TransportTCP   TransportType = "tcp"
+	TransportQUIC  TransportType = "quic"
+	TransportRelay TransportType = "relay"
+	TransportOther TransportType = "other"
+)
+
+// connTransport classifies a connection by its remote address.
+func connTransport(c network.Conn) TransportType {
+	return addrTransport(c.RemoteMultiaddr())
+}
+
+func addrTransport(addr multiaddr.Multiaddr) TransportType {
+	transport := TransportOther
+	for _, c := range addr {
+		switch c.Protocol().Code {
+		case multiaddr.P_CIRCUIT:
+			return TransportRelay
+		case multiaddr.P_QUIC_V1, multiaddr.P_QUIC:
+			transport = TransportQUIC
+		case multiaddr.P_TCP:
+			if transport == TransportOther {
+				transport = TransportTCP
+			}
+		}
+	}
+	return transport
+}
+
+// Transport returns how the node is connected to a peer, preferring a
+// direct connection when there are several. It is empty when the peer is
+// not connected.
+func (n *Node) Transport(id peer.ID) TransportType {
+	var best TransportType
+	for _, c := range n.host.Network().ConnsToPeer(id) {
+		t := connTransport(c)
+		if best == "" || best == TransportRelay {
+			best = t
+		}
+	}
+	return best
+}
@@ -0,0 +1,93 @@
+package libp2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/proto"
+	"github.com/multiformats/go-multiaddr"
+)
+
+func TestAddrTransport(t *testing.T) {
+	tests := []struct {
+		addr string
+		want TransportType
+	}{
+		{"/ip4/203.0.113.7/tcp/4001", TransportTCP},
+		{"/ip4/203.0.113.7/udp/4001/quic-v1", TransportQUIC},
+		{"/ip4/203.0.113.7/tcp/4001/p2p/12D3KooWDpJ7As7BWAwRMfu1VU2WCqNjvq387JEYKDBj4kx6nXTN/p2p-circuit", TransportRelay},
+		{"/ip4/203.0.113.7/udp/4001/quic-v1/p2p/12D3KooWDpJ7As7BWAwRMfu1VU2WCqNjvq387JEYKDBj4kx6nXTN/p2p-circuit", TransportRelay},
+		{"/ip4/203.0.113.7/udp/4001", TransportOther},
+	}
+	for _, tt := range tests {
+		if got := addrTransport(multiaddr.StringCast(tt.addr)); got != tt.want {
+			t.Errorf("addrTransport(%s) = %s, want %s", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestNode_RelayCandidatesPreferStaticThenHops(t *testing.T) {
+	mn := mocknet.New()
+	t.Cleanup(func() { mn.Close() })
+
+	self, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hop, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the hop peer offers a relay service
+	hop.SetStreamHandler(proto.ProtoIDv2Hop, func(s network.Stream) { s.Reset() })
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	for _, other := range []peer.ID{plain.ID(), hop.ID()} {
+		if _, err := mn.ConnectPeers(self.ID(), other); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Wait for identify to learn the hop peer's protocols
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		supported, _ := self.Peerstore().SupportsProtocols(hop.ID(), proto.ProtoIDv2Hop)
+		if len(supported) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("identify did not report the hop protocol")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	node, err := NewNodeWithHost(context.Background(), self, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { node.Close() })
+
+	static := peer.AddrInfo{
+		ID:    "static-relay",
+		Addrs: []multiaddr.Multiaddr{multiaddr.StringCast("/ip4/198.51.100.1/tcp/4001")},
+	}
+	got := node.relayCandidates([]peer.AddrInfo{static, {ID: "no-addrs"}}, 4)
+	if len(got) != 2 || got[0].ID != static.ID || got[1].ID != hop.ID() {
+		t.Fatalf("expected the static relay then the hop peer, got %v", got)
+	}
+
+	if got := node.relayCandidates([]peer.AddrInfo{static}, 1); len(got) != 1 {
+		t.Errorf("expected candidates capped at 1, got %d", len(got))
+	}
+	if transport := node.Transport(hop.ID()); transport == "" || transport == TransportRelay {
+		t.Errorf("expected a direct transport to a directly connected peer, got %q", transport)
+	}
+}
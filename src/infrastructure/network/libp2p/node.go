@@ -11,8 +11,10 @@ import (
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/p2p/net/connmgr"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
 	"github.com/libp2p/go-libp2p/p2p/security/noise"
 	"github.com/multiformats/go-multiaddr"
 )
@@ -26,8 +28,9 @@ type Node struct {
 	subs   map[string]*pubsub.Subscription
 
 	// Phase 1: Compression, Batching, Metrics
-	batcher *MessageBatcher
-	metrics *NetworkMetrics
+	compressionThreshold int
+	batcher              *MessageBatcher
+	metrics              *NetworkMetrics
 
 	// Phase 2: Quality, Topology, Locality, ACL
 	qualityMonitor *PeerQualityMonitor
@@ -35,12 +38,27 @@ type Node struct {
 	localityMgr    *LocalityManager
 	aclMgr         *ACLManager
 
+	// 지역성 기반으로 유지 중인 메시 피어
+	meshSize  int
+	meshPeers map[peer.ID]struct{}
+
 	// Phase 3: Tracing
 	tracer *Tracer
 
 	// Phase 2: Content Store
 	contentStore *ContentStore
 
+	// 피어 교환 (nil이면 비활성화)
+	pex *PeerExchange
+
+	// 차단된 피어와 오동작 점수
+	bans  *BanList
+	onBan func(*Ban)
+
+	// 원거리 지역 가십 스로틀 (nil이면 비활성화)
+	throttle *GossipThrottle
+	lockPush *LockPush
+
 	mu sync.RWMutex
 }
 
@@ -59,6 +77,10 @@ type Config struct {
 	LowWater  int
 	HighWater int
 
+	// Phase 1: 이 크기(바이트) 미만의 메시지는 압축하지 않음
+	// (0이면 DefaultCompressionThreshold, 음수면 압축 비활성화)
+	CompressionThreshold int
+
 	// Phase 1: 메시지 배칭 설정 (nil이면 배칭 비활성화)
 	BatchConfig *BatchConfig
 
@@ -82,6 +104,23 @@ type Config struct {
 
 	// Phase 3: 분산 트레이싱 (nil이면 비활성화)
 	TracerConfig *TracerConfig
+
+	// 피어 교환 (PEX) 설정 (nil이면 비활성화)
+	PEXConfig *PEXConfig
+
+	// NAT 통과 및 릴레이 설정 (nil이면 DefaultNATConfig)
+	NATConfig *NATConfig
+
+	// 피어 차단 및 자동 차단 설정 (nil이면 DefaultBanConfig)
+	BanConfig *BanConfig
+
+	// 원거리 지역 가십 스로틀 설정 (nil이면 비활성화)
+	// 설정하면 LocalityConfig가 nil이어도 기본 지역성 클러스터링을 사용합니다.
+	GossipThrottle *GossipThrottleConfig
+
+	// 락 메시지 직접 전송 설정 (nil이면 비활성화)
+	// 품질 점수가 높은 피어부터 보내고, 실패한 피어에는 재시도합니다.
+	LockPush *LockPushConfig
 }
 
 // DefaultConfig는 기본 설정을 반환합니다.
@@ -132,27 +171,84 @@ func NewNode(ctx context.Context, cfg *Config) (*Node, error) {
 		listenAddrs = append(listenAddrs, ma)
 	}
 
+	natConfig := DefaultNATConfig()
+	if cfg.NATConfig != nil {
+		natConfig = *cfg.NATConfig
+	}
+
+	// 차단 목록은 연결 게이터로도 쓰이므로 호스트보다 먼저 생성
+	bans := newBanList(cfg)
+
+	// 릴레이 후보는 노드가 만들어진 뒤부터 제공
+	var node *Node
+	var nodeMu sync.Mutex
+	relaySource := func(ctx context.Context, num int) <-chan peer.AddrInfo {
+		nodeMu.Lock()
+		n := node
+		nodeMu.Unlock()
+		if n == nil {
+			ch := make(chan peer.AddrInfo)
+			close(ch)
+			return ch
+		}
+		return n.relayPeerSource(natConfig.StaticRelays)(ctx, num)
+	}
+
 	// libp2p 호스트 생성
-	h, err := libp2p.New(
+	opts := []libp2p.Option{
 		libp2p.Identity(privKey),
 		libp2p.ListenAddrs(listenAddrs...),
 
 		// 보안
 		libp2p.Security(noise.ID, noise.New),
 
-		// NAT 통과
-		libp2p.NATPortMap(),
-		libp2p.EnableAutoNATv2(),
-		libp2p.EnableHolePunching(),
-		libp2p.EnableRelayService(),
-
 		// 연결 관리
 		libp2p.ConnectionManager(connMgr),
-	)
+
+		// 차단된 피어 연결 거부
+		libp2p.ConnectionGater(bans),
+	}
+	// NAT 통과 (AutoNAT, 홀 펀칭, 릴레이)
+	opts = append(opts, natOptions(natConfig, relaySource)...)
+
+	h, err := libp2p.New(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("호스트 생성 실패: %w", err)
 	}
 
+	n, err := newNodeWithHost(ctx, h, cfg, bans)
+	if err != nil {
+		return nil, err
+	}
+	nodeMu.Lock()
+	node = n
+	nodeMu.Unlock()
+	return n, nil
+}
+
+// NewNodeWithHost는 이미 만들어진 호스트로 노드를 생성합니다.
+// 인메모리 시뮬레이션처럼 전송 계층을 직접 구성할 때 사용하며,
+// 노드가 호스트를 소유하므로 Close 시 호스트도 닫힙니다.
+func NewNodeWithHost(ctx context.Context, h host.Host, cfg *Config) (*Node, error) {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	return newNodeWithHost(ctx, h, cfg, newBanList(cfg))
+}
+
+// newBanList는 설정에 따른 차단 목록을 생성합니다.
+func newBanList(cfg *Config) *BanList {
+	banConfig := DefaultBanConfig()
+	if cfg != nil && cfg.BanConfig != nil {
+		banConfig = *cfg.BanConfig
+	}
+	return NewBanList(banConfig)
+}
+
+// newNodeWithHost는 호스트와 차단 목록으로 노드를 생성합니다.
+// 호스트에 연결 게이터가 없으면 차단은 PubSub 블랙리스트와 연결 종료로만 적용됩니다.
+func newNodeWithHost(ctx context.Context, h host.Host, cfg *Config, bans *BanList) (*Node, error) {
+
 	// DHT 초기화
 	kadDHT, err := dht.New(ctx, h,
 		dht.Mode(dht.ModeAutoServer),
@@ -172,6 +268,18 @@ func NewNode(ctx context.Context, cfg *Config) (*Node, error) {
 	if cfg.GossipConfig != nil {
 		gossipOpts = cfg.GossipConfig.ToOptions()
 	}
+	// 차단된 피어의 메시지 무시
+	gossipOpts = append(gossipOpts, pubsub.WithBlacklist(pubsubBlacklist{bans: bans}))
+
+	// 스로틀 토픽은 원거리 피어에 직접 보내지 않고 게이트웨이로 중계
+	var throttle *GossipThrottle
+	if cfg.GossipThrottle != nil {
+		throttle = NewGossipThrottle(h, *cfg.GossipThrottle, bans)
+		gossipOpts = append(gossipOpts,
+			pubsub.WithFloodPublish(false),
+			pubsub.WithPeerFilter(throttle.AllowPeer),
+		)
+	}
 
 	ps, err := pubsub.NewGossipSub(ctx, h, gossipOpts...)
 	if err != nil {
@@ -187,8 +295,24 @@ func NewNode(ctx context.Context, cfg *Config) (*Node, error) {
 		topics:  make(map[string]*pubsub.Topic),
 		subs:    make(map[string]*pubsub.Subscription),
 		metrics: NewNetworkMetrics(),
+		bans:    bans,
+
+		throttle: throttle,
+
+		meshSize:  pubsub.GossipSubD,
+		meshPeers: make(map[peer.ID]struct{}),
+
+		compressionThreshold: DefaultCompressionThreshold,
+	}
+	if cfg.CompressionThreshold != 0 {
+		node.compressionThreshold = cfg.CompressionThreshold
+	}
+	if cfg.GossipConfig != nil && cfg.GossipConfig.Params.D > 0 {
+		node.meshSize = cfg.GossipConfig.Params.D
 	}
 
+	bans.SetBanHandler(node.handleBan)
+
 	// Phase 1: Initialize batcher if configured
 	if cfg.BatchConfig != nil {
 		node.batcher = NewMessageBatcher(*cfg.BatchConfig, node.publishDirect)
@@ -214,11 +338,20 @@ func NewNode(ctx context.Context, cfg *Config) (*Node, error) {
 	}
 
 	// Phase 2: Initialize locality manager
-	if cfg.LocalityConfig != nil {
-		node.localityMgr = NewLocalityManager(h, *cfg.LocalityConfig)
+	localityConfig := cfg.LocalityConfig
+	if localityConfig == nil && throttle != nil {
+		def := DefaultLocalityConfig()
+		localityConfig = &def
+	}
+	if localityConfig != nil {
+		node.localityMgr = NewLocalityManager(h, *localityConfig)
 		node.localityMgr.SetQualityMonitor(node.qualityMonitor)
 		node.localityMgr.Start()
 	}
+	if throttle != nil {
+		throttle.SetLocality(node.localityMgr)
+		throttle.SetPublisher(node.PublishRaw)
+	}
 
 	// Phase 2: Initialize ACL manager
 	node.aclMgr = NewACLManager(cfg.ACLPolicy)
@@ -235,6 +368,17 @@ func NewNode(ctx context.Context, cfg *Config) (*Node, error) {
 		node.tracer = NewTracer(*cfg.TracerConfig)
 	}
 
+	// 락 메시지를 품질 순으로 피어에 직접 전송
+	if cfg.LockPush != nil {
+		node.lockPush = NewLockPush(h, *cfg.LockPush, node.qualityMonitor, bans)
+	}
+
+	// 연결된 피어와 알려진 피어 목록 교환
+	if cfg.PEXConfig != nil {
+		node.pex = NewPeerExchange(h, *cfg.PEXConfig)
+		node.pex.Start()
+	}
+
 	return node, nil
 }
 
@@ -254,7 +398,91 @@ func (n *Node) Bootstrap(ctx context.Context, peers []peer.AddrInfo) error {
 	wg.Wait()
 
 	// DHT bootstrap
-	return n.dht.Bootstrap(ctx)
+	if err := n.dht.Bootstrap(ctx); err != nil {
+		return err
+	}
+
+	// 지역성 관리자가 있으면 RTT가 낮은 피어 쪽으로 메시 개선
+	if n.localityMgr != nil {
+		n.improveMesh(ctx)
+	}
+	return nil
+}
+
+const (
+	// localityTag marks connections kept for a low-RTT mesh
+	localityTag       = "locality"
+	localityTagWeight = 50
+
+	meshProbeTimeout = 5 * time.Second
+)
+
+// improveMesh measures RTT to connected peers, connects to the peers the
+// locality manager recommends and tags them so the connection manager
+// keeps them when trimming. Peers no longer recommended lose the tag.
+func (n *Node) improveMesh(ctx context.Context) {
+	n.probeConnectedPeers(ctx)
+
+	recommended := n.localityMgr.RecommendPeers(n.meshSize)
+	cm := n.host.ConnManager()
+
+	next := make(map[peer.ID]struct{}, len(recommended))
+	for _, id := range recommended {
+		next[id] = struct{}{}
+		cm.TagPeer(id, localityTag, localityTagWeight)
+
+		if n.host.Network().Connectedness(id) == network.Connected {
+			continue
+		}
+		pi := n.host.Peerstore().PeerInfo(id)
+		if len(pi.Addrs) == 0 {
+			continue
+		}
+		if err := n.host.Connect(ctx, pi); err != nil {
+			fmt.Printf("지역 피어 연결 실패 (%s): %v\n", id, err)
+		}
+	}
+
+	n.mu.Lock()
+	for id := range n.meshPeers {
+		if _, ok := next[id]; !ok {
+			cm.UntagPeer(id, localityTag)
+		}
+	}
+	n.meshPeers = next
+	n.mu.Unlock()
+}
+
+// probeConnectedPeers pings every connected peer once and feeds the RTTs
+// to the locality manager, so recommendations right after bootstrap do
+// not have to wait for the next quality probe.
+func (n *Node) probeConnectedPeers(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, meshProbeTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, id := range n.host.Network().Peers() {
+		wg.Add(1)
+		go func(id peer.ID) {
+			defer wg.Done()
+
+			// Ping keeps pinging until its context ends; one sample is enough
+			pctx, pcancel := context.WithCancel(ctx)
+			defer pcancel()
+
+			select {
+			case res := <-ping.Ping(pctx, n.host, id):
+				if res.Error == nil && res.RTT > 0 {
+					n.localityMgr.UpdatePeerRTT(id, res.RTT)
+				}
+			case <-ctx.Done():
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	// 품질 모니터가 이미 측정한 피어도 반영
+	n.localityMgr.probePeers()
 }
 
 // ID는 노드 ID를 반환합니다.
@@ -285,6 +513,11 @@ func (n *Node) JoinTopic(topicName string) (*pubsub.Topic, error) {
 	if err != nil {
 		return nil, err
 	}
+	// 속도 제한 초과, 디코딩할 수 없는 메시지 차단
+	if err := n.pubsub.RegisterTopicValidator(topicName, n.messageValidator(topicName)); err != nil {
+		topic.Close()
+		return nil, err
+	}
 
 	n.topics[topicName] = topic
 	return topic, nil
@@ -320,6 +553,18 @@ func (n *Node) Subscribe(topicName string) (*pubsub.Subscription, error) {
 	return sub, nil
 }
 
+// Unsubscribe는 토픽 구독을 취소합니다.
+// 토픽 참여는 유지되어 이후 발행과 재구독에 재사용됩니다.
+func (n *Node) Unsubscribe(topicName string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if sub, exists := n.subs[topicName]; exists {
+		sub.Cancel()
+		delete(n.subs, topicName)
+	}
+}
+
 // Publish는 토픽에 메시지를 발행합니다.
 // ACL 체크, 암호화, 배칭, 압축을 순차적으로 적용합니다.
 func (n *Node) Publish(ctx context.Context, topicName string, data []byte) error {
@@ -376,7 +621,7 @@ func (n *Node) publishDirect(ctx context.Context, topicName string, data []byte)
 
 	// Apply compression
 	originalSize := len(data)
-	compressed := CompressMessage(data)
+	compressed := CompressMessageAbove(data, n.compressionThreshold)
 	compressedSize := len(compressed)
 
 	// Record metrics
@@ -386,8 +631,15 @@ func (n *Node) publishDirect(ctx context.Context, topicName string, data []byte)
 	err = topic.Publish(ctx, compressed)
 	if err != nil {
 		n.metrics.RecordError("publish_failed")
+		return err
+	}
+	if n.throttle != nil {
+		n.throttle.Relay(topicName, compressed)
 	}
-	return err
+	if n.lockPush != nil {
+		n.lockPush.Push(topicName, compressed)
+	}
+	return nil
 }
 
 // PublishRaw는 압축/배칭 없이 메시지를 발행합니다.
@@ -443,6 +695,15 @@ func (n *Node) Close() error {
 	}
 
 	// Phase 2: Stop managers
+	if n.throttle != nil {
+		n.throttle.Close()
+	}
+	if n.lockPush != nil {
+		n.lockPush.Close()
+	}
+	if n.pex != nil {
+		n.pex.Stop()
+	}
 	if n.topologyMgr != nil {
 		n.topologyMgr.Stop()
 	}
@@ -452,6 +713,9 @@ func (n *Node) Close() error {
 	if n.qualityMonitor != nil {
 		n.qualityMonitor.Stop()
 	}
+	if n.contentStore != nil {
+		n.contentStore.Close()
+	}
 
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -482,7 +746,12 @@ func (n *Node) GlobalTopics() []string {
 
 // SubscribeGlobalTopics subscribes to all global topics.
 func (n *Node) SubscribeGlobalTopics(ctx context.Context) error {
-	for _, topicName := range n.GlobalTopics() {
+	return n.SubscribeTopics(ctx, GlobalTopicSet())
+}
+
+// SubscribeTopics subscribes to the core topics of the given topic set.
+func (n *Node) SubscribeTopics(ctx context.Context, topics TopicSet) error {
+	for _, topicName := range topics.Core() {
 		if _, err := n.Subscribe(topicName); err != nil {
 			return fmt.Errorf("토픽 구독 실패 (%s): %w", topicName, err)
 		}
@@ -519,7 +788,53 @@ func (n *Node) GetMetricsSnapshot() MetricsSnapshot {
 	n.metrics.peersConnected = len(n.host.Network().Peers())
 	n.metrics.mu.Unlock()
 
-	return n.metrics.Snapshot()
+	snap := n.metrics.Snapshot()
+	if n.throttle != nil {
+		stats := n.throttle.Stats()
+		snap.GossipThrottle = &stats
+	}
+	if n.lockPush != nil {
+		stats := n.lockPush.Stats()
+		snap.LockPush = &stats
+	}
+	return snap
+}
+
+// RelayedMessages returns the messages relayed to this node by a gateway
+// on a throttled topic, or nil if the topic is not throttled.
+func (n *Node) RelayedMessages(topicName string) <-chan []byte {
+	if n.throttle == nil {
+		return nil
+	}
+	return n.throttle.Relayed(topicName)
+}
+
+// PushedMessages returns the lock messages pushed to this node on a
+// topic, or nil if the topic is not pushed.
+func (n *Node) PushedMessages(topicName string) <-chan []byte {
+	if n.lockPush == nil {
+		return nil
+	}
+	return n.lockPush.Pushed(topicName)
+}
+
+// FirstDelivery reports whether a message received on a topic has not
+// already arrived by lock push; it is always true for other topics.
+func (n *Node) FirstDelivery(topicName string, data []byte) bool {
+	if n.lockPush == nil {
+		return true
+	}
+	return n.lockPush.FirstSeen(topicName, data)
+}
+
+// LockPush returns the lock push (nil if disabled).
+func (n *Node) LockPush() *LockPush {
+	return n.lockPush
+}
+
+// GossipThrottle returns the gossip throttle (nil if disabled).
+func (n *Node) GossipThrottle() *GossipThrottle {
+	return n.throttle
 }
 
 // Phase 2-3 Manager Accessors
@@ -544,6 +859,16 @@ func (n *Node) ACLManager() *ACLManager {
 	return n.aclMgr
 }
 
+// BanList returns the peer ban list
+func (n *Node) BanList() *BanList {
+	return n.bans
+}
+
+// PeerExchange returns the peer exchange (nil if disabled).
+func (n *Node) PeerExchange() *PeerExchange {
+	return n.pex
+}
+
 // ContentStore returns the content store
 func (n *Node) ContentStore() *ContentStore {
 	return n.contentStore
@@ -19,6 +19,10 @@ type PeerQuality struct {
 	Score       float64       `json:"score"`
 	LastUpdate  time.Time     `json:"last_update"`
 	SampleCount int           `json:"sample_count"`
+
+	// Degraded is set while the peer breaches its latency SLA
+	Degraded       bool      `json:"degraded,omitempty"`
+	SLABreachSince time.Time `json:"sla_breach_since,omitempty"`
 }
 
 // PeerQualityMonitor monitors peer connection quality
@@ -30,6 +34,8 @@ type PeerQualityMonitor struct {
 	ctx      context.Context
 	cancel   context.CancelFunc
 	handlers []QualityChangeHandler
+
+	alertHandlers []LatencyAlertHandler
 }
 
 // PeerQualityConfig configures the quality monitor
@@ -48,6 +54,8 @@ type PeerQualityConfig struct {
 	MaxRTT time.Duration
 	// TargetRTT is the ideal RTT (below this, RTT score is 1)
 	TargetRTT time.Duration
+	// LatencySLA marks peers degraded when their RTT stays above a threshold
+	LatencySLA LatencySLAConfig
 }
 
 // QualityChangeHandler is called when peer quality changes significantly
@@ -63,6 +71,7 @@ func DefaultPeerQualityConfig() PeerQualityConfig {
 		HighScoreThreshold: 0.7,
 		MaxRTT:             500 * time.Millisecond,
 		TargetRTT:          50 * time.Millisecond,
+		LatencySLA:         LatencySLAConfig{Window: DefaultLatencySLAWindow},
 	}
 }
 
@@ -218,8 +227,29 @@ func (m *PeerQualityMonitor) measurePeer(id peer.ID) {
 	m.updateQuality(id, rtt, packetLoss)
 }
 
+// RecordDelivery feeds the outcome of a message sent to a peer into its
+// quality: the round trip of a delivered one as an RTT sample, an
+// undelivered one as a lost packet.
+func (m *PeerQualityMonitor) RecordDelivery(id peer.ID, rtt time.Duration, delivered bool) {
+	if delivered {
+		m.updateQuality(id, rtt, 0)
+		return
+	}
+	m.mu.RLock()
+	if q, ok := m.peers[id]; ok {
+		rtt = q.RTT
+	}
+	m.mu.RUnlock()
+	m.updateQuality(id, rtt, 1)
+}
+
 // updateQuality updates a peer's quality metrics
 func (m *PeerQualityMonitor) updateQuality(id peer.ID, rtt time.Duration, packetLoss float64) {
+	m.updateQualityAt(id, rtt, packetLoss, time.Now())
+}
+
+// updateQualityAt updates a peer's quality metrics with a sample taken at now
+func (m *PeerQualityMonitor) updateQualityAt(id peer.ID, rtt time.Duration, packetLoss float64, now time.Time) {
 	m.mu.Lock()
 
 	q, exists := m.peers[id]
@@ -252,15 +282,24 @@ func (m *PeerQualityMonitor) updateQuality(id peer.ID, rtt time.Duration, packet
 	}
 
 	q.SampleCount++
-	q.LastUpdate = time.Now()
+	q.LastUpdate = now
+
+	alert := m.checkLatencySLA(q, rtt, now)
 
 	// Calculate score
 	oldScore := q.Score
 	q.Score = m.calculateScore(q)
 
 	handlers := m.handlers
+	alertHandlers := m.alertHandlers
 	m.mu.Unlock()
 
+	if alert != nil {
+		for _, h := range alertHandlers {
+			h(*alert)
+		}
+	}
+
 	// Notify handlers if score changed significantly
 	if q.SampleCount >= m.config.MinSamples {
 		scoreDiff := q.Score - oldScore
@@ -317,6 +356,11 @@ func (m *PeerQualityMonitor) calculateScore(q *PeerQuality) float64 {
 		score = 1
 	}
 
+	// Peers breaching their latency SLA rank as low quality
+	if q.Degraded {
+		score = min(score, m.config.LowScoreThreshold/2)
+	}
+
 	return score
 }
 
@@ -343,6 +387,7 @@ type PeerQualityStats struct {
 	HighQuality   int           `json:"high_quality"`
 	MediumQuality int           `json:"medium_quality"`
 	LowQuality    int           `json:"low_quality"`
+	Degraded      int           `json:"degraded"`
 	AverageRTT    time.Duration `json:"average_rtt"`
 	AverageScore  float64       `json:"average_score"`
 }
@@ -363,6 +408,9 @@ func (m *PeerQualityMonitor) Stats() PeerQualityStats {
 
 		stats.TotalPeers++
 		totalRTT += q.RTT
+		if q.Degraded {
+			stats.Degraded++
+		}
 		totalScore += q.Score
 
 		if q.Score >= m.config.HighScoreThreshold {
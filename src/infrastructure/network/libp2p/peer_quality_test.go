@@ -309,3 +309,125 @@ func TestPeerQuality_GetQuality(t *testing.T) {
 		t.Error("Unknown peer should return nil")
 	}
 }
+
+// newSLATestMonitor returns a monitor with a 100ms SLA held for a minute.
+func newSLATestMonitor() (*PeerQualityMonitor, *[]LatencyAlert) {
+	config := DefaultPeerQualityConfig()
+	config.MinSamples = 1
+	config.LatencySLA = LatencySLAConfig{Default: 100 * time.Millisecond, Window: time.Minute}
+
+	m := &PeerQualityMonitor{
+		config: config,
+		peers:  make(map[peer.ID]*PeerQuality),
+	}
+	var alerts []LatencyAlert
+	m.OnLatencyAlert(func(a LatencyAlert) { alerts = append(alerts, a) })
+	return m, &alerts
+}
+
+func TestPeerQuality_LatencySLA_SustainedBreachAlerts(t *testing.T) {
+	m, alerts := newSLATestMonitor()
+	id := peer.ID("slow")
+	start := time.Now()
+
+	// Over the SLA, but not for the whole window yet
+	for i := 0; i < 6; i++ {
+		m.updateQualityAt(id, 250*time.Millisecond, 0, start.Add(time.Duration(i)*10*time.Second))
+	}
+	if len(*alerts) != 0 || m.IsDegraded(id) {
+		t.Fatalf("no alert expected before the window passes, got %+v", *alerts)
+	}
+
+	m.updateQualityAt(id, 250*time.Millisecond, 0, start.Add(time.Minute))
+	if len(*alerts) != 1 {
+		t.Fatalf("expected 1 alert after a sustained breach, got %d", len(*alerts))
+	}
+	alert := (*alerts)[0]
+	if !alert.Degraded || alert.PeerID != id || alert.SLA != 100*time.Millisecond || !alert.Since.Equal(start) {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+	if !m.IsDegraded(id) {
+		t.Error("peer should be marked degraded")
+	}
+	if score := m.GetScore(id); score >= m.config.LowScoreThreshold {
+		t.Errorf("degraded peer should score as low quality, got %f", score)
+	}
+	if stats := m.Stats(); stats.Degraded != 1 {
+		t.Errorf("expected 1 degraded peer in stats, got %d", stats.Degraded)
+	}
+
+	// Still breaching: no repeated alert
+	m.updateQualityAt(id, 250*time.Millisecond, 0, start.Add(2*time.Minute))
+	if len(*alerts) != 1 {
+		t.Errorf("alert should fire once per breach, got %d", len(*alerts))
+	}
+
+	// Back within the SLA
+	m.updateQualityAt(id, 40*time.Millisecond, 0, start.Add(3*time.Minute))
+	if len(*alerts) != 2 || (*alerts)[1].Degraded {
+		t.Fatalf("expected a recovery alert, got %+v", *alerts)
+	}
+	if m.IsDegraded(id) {
+		t.Error("recovered peer should no longer be degraded")
+	}
+}
+
+func TestPeerQuality_LatencySLA_TransientSpikesIgnored(t *testing.T) {
+	m, alerts := newSLATestMonitor()
+	id := peer.ID("spiky")
+	start := time.Now()
+
+	// Spikes over the SLA for several minutes, each followed by a good sample
+	for i := 0; i < 20; i++ {
+		rtt := 40 * time.Millisecond
+		if i%2 == 0 {
+			rtt = 400 * time.Millisecond
+		}
+		m.updateQualityAt(id, rtt, 0, start.Add(time.Duration(i)*30*time.Second))
+	}
+
+	if len(*alerts) != 0 || m.IsDegraded(id) {
+		t.Errorf("transient spikes should not alert, got %+v", *alerts)
+	}
+}
+
+func TestPeerQuality_LatencySLA_PerPeerThreshold(t *testing.T) {
+	m, alerts := newSLATestMonitor()
+	remote := peer.ID("remote")
+	m.SetLatencySLA(LatencySLAConfig{
+		Default: 100 * time.Millisecond,
+		Peers:   map[peer.ID]time.Duration{remote: 500 * time.Millisecond},
+		Window:  time.Minute,
+	})
+	start := time.Now()
+
+	for i := 0; i <= 6; i++ {
+		at := start.Add(time.Duration(i) * 10 * time.Second)
+		m.updateQualityAt(remote, 250*time.Millisecond, 0, at)
+		m.updateQualityAt("local", 250*time.Millisecond, 0, at)
+	}
+
+	if m.LatencySLA(remote) != 500*time.Millisecond {
+		t.Errorf("expected per-peer SLA, got %v", m.LatencySLA(remote))
+	}
+	if m.IsDegraded(remote) {
+		t.Error("remote peer is within its own SLA")
+	}
+	if !m.IsDegraded("local") || len(*alerts) != 1 {
+		t.Errorf("local peer should breach the default SLA, got %+v", *alerts)
+	}
+}
+
+func TestPeerQuality_LatencySLA_DisabledByDefault(t *testing.T) {
+	m := &PeerQualityMonitor{
+		config: DefaultPeerQualityConfig(),
+		peers:  make(map[peer.ID]*PeerQuality),
+	}
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		m.updateQualityAt("slow", 2*time.Second, 0, start.Add(time.Duration(i)*time.Minute))
+	}
+	if m.IsDegraded("slow") {
+		t.Error("no peer should be degraded without an SLA")
+	}
+}
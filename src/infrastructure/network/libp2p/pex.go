@@ -0,0 +1,362 @@
+package libp2p
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// PEXProtocolID is the stream protocol peers use to exchange known peers.
+const PEXProtocolID = protocol.ID("/agent-collab/pex/1.0.0")
+
+// maxPEXResponseSize bounds how much a peer exchange response may read.
+const maxPEXResponseSize = 64 * 1024
+
+// PEXConfig configures peer exchange
+type PEXConfig struct {
+	// Interval is how often to exchange peers with connected peers
+	Interval time.Duration
+	// SampleSize is the maximum number of peers shared per exchange
+	SampleSize int
+	// Fanout is how many connected peers to exchange with each round
+	Fanout int
+	// MaxNewPeers is the maximum number of new peers dialed per exchange
+	MaxNewPeers int
+	// RetryAfter is how long before a learned peer that could not be
+	// dialed is tried again
+	RetryAfter time.Duration
+	// DialTimeout bounds each exchange and dial
+	DialTimeout time.Duration
+}
+
+// DefaultPEXConfig returns the default configuration
+func DefaultPEXConfig() PEXConfig {
+	return PEXConfig{
+		Interval:    30 * time.Second,
+		SampleSize:  16,
+		Fanout:      3,
+		MaxNewPeers: 8,
+		RetryAfter:  5 * time.Minute,
+		DialTimeout: 10 * time.Second,
+	}
+}
+
+// PEXStats counts peer exchange activity
+type PEXStats struct {
+	Exchanges  int64 `json:"exchanges"`
+	Served     int64 `json:"served"`
+	Discovered int64 `json:"discovered"`
+	Connected  int64 `json:"connected"`
+	Failed     int64 `json:"failed"`
+}
+
+// pexResponse is what a peer returns when asked for its known peers
+type pexResponse struct {
+	Peers []peer.AddrInfo `json:"peers"`
+}
+
+// PeerExchange lets connected peers share samples of the peers they know,
+// so a node bootstrapped to a single peer quickly finds the rest of the
+// cluster instead of waiting for gossip to reach it.
+type PeerExchange struct {
+	mu     sync.Mutex
+	host   host.Host
+	config atomic.Pointer[PEXConfig]
+	// reconfigured wakes the exchange loop when the interval changes
+	reconfigured chan struct{}
+
+	// attempted records when a learned peer was last dialed (dedup)
+	attempted map[peer.ID]time.Time
+	// exchanged records when we last asked a peer for its peers
+	exchanged map[peer.ID]time.Time
+
+	exchanges  atomic.Int64
+	served     atomic.Int64
+	discovered atomic.Int64
+	connected  atomic.Int64
+	failed     atomic.Int64
+
+	notifiee *network.NotifyBundle
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// NewPeerExchange creates a peer exchange and registers its stream handler
+func NewPeerExchange(h host.Host, config PEXConfig) *PeerExchange {
+	ctx, cancel := context.WithCancel(context.Background())
+	px := &PeerExchange{
+		host:         h,
+		reconfigured: make(chan struct{}, 1),
+		attempted:    make(map[peer.ID]time.Time),
+		exchanged:    make(map[peer.ID]time.Time),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	px.config.Store(withPEXDefaults(config))
+	h.SetStreamHandler(PEXProtocolID, px.handleStream)
+	return px
+}
+
+// withPEXDefaults fills unset fields of config with the defaults
+func withPEXDefaults(config PEXConfig) *PEXConfig {
+	def := DefaultPEXConfig()
+	if config.Interval <= 0 {
+		config.Interval = def.Interval
+	}
+	if config.SampleSize <= 0 {
+		config.SampleSize = def.SampleSize
+	}
+	if config.Fanout <= 0 {
+		config.Fanout = def.Fanout
+	}
+	if config.MaxNewPeers <= 0 {
+		config.MaxNewPeers = def.MaxNewPeers
+	}
+	if config.RetryAfter <= 0 {
+		config.RetryAfter = def.RetryAfter
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = def.DialTimeout
+	}
+	return &config
+}
+
+// Config returns the current configuration
+func (px *PeerExchange) Config() PEXConfig {
+	return *px.config.Load()
+}
+
+// SetConfig replaces the configuration while running; unset fields take
+// the defaults. A new interval applies from the next round.
+func (px *PeerExchange) SetConfig(config PEXConfig) {
+	px.config.Store(withPEXDefaults(config))
+	select {
+	case px.reconfigured <- struct{}{}:
+	default:
+	}
+}
+
+// Start starts periodic exchanges and exchanges with every newly
+// connected peer
+func (px *PeerExchange) Start() {
+	px.notifiee = &network.NotifyBundle{
+		ConnectedF: func(_ network.Network, conn network.Conn) {
+			go px.exchangeIfDue(conn.RemotePeer())
+		},
+	}
+	px.host.Network().Notify(px.notifiee)
+	go px.exchangeLoop()
+}
+
+// Stop stops peer exchange
+func (px *PeerExchange) Stop() {
+	px.cancel()
+	if px.notifiee != nil {
+		px.host.Network().StopNotify(px.notifiee)
+	}
+	px.host.RemoveStreamHandler(PEXProtocolID)
+}
+
+// Stats returns peer exchange counters
+func (px *PeerExchange) Stats() PEXStats {
+	return PEXStats{
+		Exchanges:  px.exchanges.Load(),
+		Served:     px.served.Load(),
+		Discovered: px.discovered.Load(),
+		Connected:  px.connected.Load(),
+		Failed:     px.failed.Load(),
+	}
+}
+
+// Exchange asks a connected peer for a sample of its peers and dials the
+// new ones. It returns how many new peers were connected.
+func (px *PeerExchange) Exchange(ctx context.Context, id peer.ID) (int, error) {
+	px.mu.Lock()
+	px.exchanged[id] = time.Now()
+	px.mu.Unlock()
+	px.exchanges.Add(1)
+
+	peers, err := px.request(ctx, id)
+	if err != nil {
+		px.failed.Add(1)
+		return 0, err
+	}
+	return px.connectNew(ctx, peers), nil
+}
+
+// exchangeLoop exchanges with a few random connected peers each interval
+func (px *PeerExchange) exchangeLoop() {
+	interval := px.Config().Interval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-px.ctx.Done():
+			return
+		case <-px.reconfigured:
+			if next := px.Config().Interval; next != interval {
+				interval = next
+				ticker.Reset(interval)
+			}
+		case <-ticker.C:
+			px.exchangeRound()
+		}
+	}
+}
+
+// exchangeRound exchanges with up to Fanout random connected peers
+func (px *PeerExchange) exchangeRound() {
+	config := px.Config()
+	peers := px.host.Network().Peers()
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+	if len(peers) > config.Fanout {
+		peers = peers[:config.Fanout]
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range peers {
+		wg.Add(1)
+		go func(id peer.ID) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(px.ctx, config.DialTimeout)
+			defer cancel()
+			_, _ = px.Exchange(ctx, id)
+		}(id)
+	}
+	wg.Wait()
+}
+
+// exchangeIfDue exchanges with a newly connected peer unless we already
+// did within the last interval
+func (px *PeerExchange) exchangeIfDue(id peer.ID) {
+	config := px.Config()
+	px.mu.Lock()
+	last, ok := px.exchanged[id]
+	px.mu.Unlock()
+	if ok && time.Since(last) < config.Interval {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(px.ctx, config.DialTimeout)
+	defer cancel()
+	_, _ = px.Exchange(ctx, id)
+}
+
+// request fetches a peer sample from id
+func (px *PeerExchange) request(ctx context.Context, id peer.ID) ([]peer.AddrInfo, error) {
+	s, err := px.host.NewStream(ctx, id, PEXProtocolID)
+	if err != nil {
+		return nil, fmt.Errorf("open pex stream: %w", err)
+	}
+	defer s.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.SetReadDeadline(deadline)
+	}
+
+	var resp pexResponse
+	if err := json.NewDecoder(io.LimitReader(s, maxPEXResponseSize)).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("read pex response: %w", err)
+	}
+	if sampleSize := px.Config().SampleSize; len(resp.Peers) > sampleSize {
+		resp.Peers = resp.Peers[:sampleSize]
+	}
+	return resp.Peers, nil
+}
+
+// handleStream answers a peer exchange request with a sample of our peers
+func (px *PeerExchange) handleStream(s network.Stream) {
+	defer s.Close()
+
+	resp := pexResponse{Peers: px.sample(s.Conn().RemotePeer())}
+	if err := json.NewEncoder(s).Encode(resp); err != nil {
+		_ = s.Reset()
+		return
+	}
+	px.served.Add(1)
+}
+
+// sample returns up to SampleSize random connected peers with known
+// addresses, excluding the requester
+func (px *PeerExchange) sample(requester peer.ID) []peer.AddrInfo {
+	sampleSize := px.Config().SampleSize
+	peers := px.host.Network().Peers()
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+
+	out := make([]peer.AddrInfo, 0, min(len(peers), sampleSize))
+	for _, id := range peers {
+		if len(out) >= sampleSize {
+			break
+		}
+		if id == requester {
+			continue
+		}
+		addrs := px.host.Peerstore().Addrs(id)
+		if len(addrs) == 0 {
+			continue
+		}
+		out = append(out, peer.AddrInfo{ID: id, Addrs: addrs})
+	}
+	return out
+}
+
+// connectNew dials learned peers that are new to us, skipping ourselves,
+// peers already connected and peers dialed within RetryAfter
+func (px *PeerExchange) connectNew(ctx context.Context, peers []peer.AddrInfo) int {
+	config := px.Config()
+	self := px.host.ID()
+	now := time.Now()
+
+	var candidates []peer.AddrInfo
+	px.mu.Lock()
+	for _, pi := range peers {
+		if len(candidates) >= config.MaxNewPeers {
+			break
+		}
+		if pi.ID == self || pi.ID == "" || len(pi.Addrs) == 0 {
+			continue
+		}
+		if px.host.Network().Connectedness(pi.ID) == network.Connected {
+			continue
+		}
+		if last, ok := px.attempted[pi.ID]; ok && now.Sub(last) < config.RetryAfter {
+			continue
+		}
+		px.attempted[pi.ID] = now
+		candidates = append(candidates, pi)
+	}
+	px.mu.Unlock()
+
+	px.discovered.Add(int64(len(candidates)))
+
+	var wg sync.WaitGroup
+	var connected atomic.Int64
+	for _, pi := range candidates {
+		wg.Add(1)
+		go func(pi peer.AddrInfo) {
+			defer wg.Done()
+			dctx, cancel := context.WithTimeout(ctx, config.DialTimeout)
+			defer cancel()
+			if err := px.host.Connect(dctx, pi); err != nil {
+				px.failed.Add(1)
+				return
+			}
+			connected.Add(1)
+		}(pi)
+	}
+	wg.Wait()
+
+	px.connected.Add(connected.Load())
+	return int(connected.Load())
+}
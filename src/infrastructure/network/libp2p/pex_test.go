@@ -0,0 +1,164 @@
+package libp2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/libp2p/go-libp2p/p2p/protocol/identify"
+)
+
+// pexCluster creates n linked hosts where host 1 is connected to every
+// other host except host 0, which knows nobody yet.
+func pexCluster(t *testing.T, n int, config PEXConfig) ([]host.Host, []*PeerExchange) {
+	t.Helper()
+
+	mn := mocknet.New()
+	t.Cleanup(func() { mn.Close() })
+
+	hosts := make([]host.Host, n)
+	exchanges := make([]*PeerExchange, n)
+	for i := range hosts {
+		h, err := mn.GenPeer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hosts[i] = h
+		exchanges[i] = NewPeerExchange(h, config)
+		t.Cleanup(exchanges[i].Stop)
+	}
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 2; i < n; i++ {
+		if _, err := mn.ConnectPeers(hosts[1].ID(), hosts[i].ID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitIdentified(hosts[1])
+	return hosts, exchanges
+}
+
+// connectPEX connects a to b and waits until both have identified each
+// other. The exchanges are not started, so no exchange runs on connect.
+func connectPEX(t *testing.T, a, b host.Host) {
+	t.Helper()
+
+	if err := a.Connect(context.Background(), b.Peerstore().PeerInfo(b.ID())); err != nil {
+		t.Fatal(err)
+	}
+	waitIdentified(a)
+	waitIdentified(b)
+}
+
+// waitIdentified waits until identify finished on every connection of h.
+// Identify rewrites the addresses of h's peers in its peerstore, so until
+// then a sample may briefly skip peers that have no addresses.
+func waitIdentified(h host.Host) {
+	ids := h.(interface{ IDService() identify.IDService }).IDService()
+	for _, conn := range h.Network().Conns() {
+		<-ids.IdentifyWait(conn)
+	}
+}
+
+func connectedCount(h host.Host) int {
+	n := 0
+	for _, id := range h.Network().Peers() {
+		if h.Network().Connectedness(id) == network.Connected {
+			n++
+		}
+	}
+	return n
+}
+
+func TestPeerExchange_DiscoversPeersThroughSinglePeer(t *testing.T) {
+	hosts, exchanges := pexCluster(t, 5, DefaultPEXConfig())
+	ctx := context.Background()
+
+	// Node 0 bootstraps to node 1 only
+	connectPEX(t, hosts[0], hosts[1])
+
+	connected, err := exchanges[0].Exchange(ctx, hosts[1].ID())
+	if err != nil {
+		t.Fatalf("exchange failed: %v", err)
+	}
+	if connected != 3 {
+		t.Errorf("expected to connect to 3 new peers, got %d", connected)
+	}
+	for _, h := range hosts[2:] {
+		if hosts[0].Network().Connectedness(h.ID()) != network.Connected {
+			t.Errorf("node 0 should have discovered %s", h.ID())
+		}
+	}
+
+	// Already connected peers are not dialed again
+	if connected, _ := exchanges[0].Exchange(ctx, hosts[1].ID()); connected != 0 {
+		t.Errorf("second exchange should find nothing new, got %d", connected)
+	}
+	if stats := exchanges[0].Stats(); stats.Discovered != 3 || stats.Exchanges != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats := exchanges[1].Stats(); stats.Served != 2 {
+		t.Errorf("node 1 should have served 2 exchanges, got %d", stats.Served)
+	}
+}
+
+func TestPeerExchange_BoundedSample(t *testing.T) {
+	config := DefaultPEXConfig()
+	config.SampleSize = 2
+	hosts, exchanges := pexCluster(t, 7, config)
+	ctx := context.Background()
+
+	connectPEX(t, hosts[0], hosts[1])
+	if sample := exchanges[1].sample(hosts[0].ID()); len(sample) != 2 {
+		t.Errorf("sample should hold at most 2 peers, got %d", len(sample))
+	}
+	for _, pi := range exchanges[1].sample(hosts[0].ID()) {
+		if pi.ID == hosts[0].ID() {
+			t.Error("sample should not include the requester")
+		}
+	}
+
+	if connected, _ := exchanges[0].Exchange(ctx, hosts[1].ID()); connected != 2 {
+		t.Errorf("expected 2 new peers from a bounded sample, got %d", connected)
+	}
+}
+
+func TestPeerExchange_SetConfig(t *testing.T) {
+	hosts, exchanges := pexCluster(t, 5, DefaultPEXConfig())
+	connectPEX(t, hosts[0], hosts[1])
+
+	exchanges[1].SetConfig(PEXConfig{Interval: time.Minute, SampleSize: 1})
+	config := exchanges[1].Config()
+	if config.Interval != time.Minute || config.SampleSize != 1 {
+		t.Errorf("expected the new interval and sample size, got %+v", config)
+	}
+	if config.Fanout != DefaultPEXConfig().Fanout {
+		t.Errorf("unset fields should take the defaults, got fanout %d", config.Fanout)
+	}
+	if sample := exchanges[1].sample(hosts[0].ID()); len(sample) != 1 {
+		t.Errorf("sample should follow the new size, got %d peers", len(sample))
+	}
+}
+
+func TestPeerExchange_ExchangesOnConnect(t *testing.T) {
+	hosts, exchanges := pexCluster(t, 5, DefaultPEXConfig())
+	for _, px := range exchanges {
+		px.Start()
+	}
+
+	if err := hosts[0].Connect(context.Background(), hosts[1].Peerstore().PeerInfo(hosts[1].ID())); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for connectedCount(hosts[0]) < 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("node 0 should discover all 4 peers, connected to %d", connectedCount(hosts[0]))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,107 @@
+package libp2p
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+func generateSigningKey(t *testing.T) (crypto.PrivKey, peer.ID) {
+	t.Helper()
+	key, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, id
+}
+
+func TestSignMessage_RoundTripThroughBatch(t *testing.T) {
+	key, id := generateSigningKey(t)
+	msg := []byte(`{"type":"lock_released","lock_id":"lock-1","note":"<a & b>"}`)
+
+	signed, err := SignMessage(key, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Batching and compression re-encode the envelope
+	batch, err := json.Marshal(&BatchedMessage{Type: "batch", Messages: []json.RawMessage{signed}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := DecompressMessage(CompressMessageAbove(batch, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages, err := UnbatchMessage(decompressed)
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("unbatch failed: %v", err)
+	}
+
+	payload, signer, err := OpenSignedMessage(messages[0])
+	if err != nil {
+		t.Fatalf("expected the signature to verify, got %v", err)
+	}
+	if signer != id {
+		t.Errorf("expected signer %s, got %s", id, signer)
+	}
+	var got, want map[string]any
+	json.Unmarshal(payload, &got)
+	json.Unmarshal(msg, &want)
+	if got["lock_id"] != want["lock_id"] || got["note"] != want["note"] {
+		t.Errorf("payload changed: %s", payload)
+	}
+}
+
+func TestOpenSignedMessage_Unsigned(t *testing.T) {
+	msg := []byte(`{"type":"lock_released","lock_id":"lock-1"}`)
+	payload, _, err := OpenSignedMessage(msg)
+	if !errors.Is(err, ErrUnsignedMessage) {
+		t.Fatalf("expected ErrUnsignedMessage, got %v", err)
+	}
+	if !bytes.Equal(payload, msg) {
+		t.Errorf("expected the unsigned message back, got %s", payload)
+	}
+}
+
+func TestOpenSignedMessage_RejectsTampering(t *testing.T) {
+	key, _ := generateSigningKey(t)
+	_, other := generateSigningKey(t)
+
+	signed, err := SignMessage(key, []byte(`{"type":"lock_released","lock_id":"lock-1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]func(env *SignedMessage){
+		"payload": func(env *SignedMessage) {
+			env.Payload = json.RawMessage(`{"type":"lock_released","lock_id":"lock-2"}`)
+		},
+		"signer": func(env *SignedMessage) { env.Signer = other.String() },
+		"signature": func(env *SignedMessage) {
+			env.Signature = append([]byte(nil), env.Signature...)
+			env.Signature[0] ^= 0xff
+		},
+	}
+	for name, tamper := range tests {
+		t.Run(name, func(t *testing.T) {
+			var env SignedMessage
+			if err := json.Unmarshal(signed, &env); err != nil {
+				t.Fatal(err)
+			}
+			tamper(&env)
+			data, _ := json.Marshal(&env)
+			if _, _, err := OpenSignedMessage(data); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
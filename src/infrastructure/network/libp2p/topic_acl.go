@@ -227,6 +227,13 @@ func (acl *TopicACL) Decrypt(ciphertext []byte) ([]byte, error) {
 	return plaintext, nil
 }
 
+// encrypted reports whether messages on the topic are encrypted
+func (acl *TopicACL) encrypted() bool {
+	acl.mu.RLock()
+	defer acl.mu.RUnlock()
+	return acl.EncryptionEnabled && acl.encryptionKey != nil
+}
+
 // SetEncryptionKey sets or updates the encryption key
 func (acl *TopicACL) SetEncryptionKey(key []byte) error {
 	if len(key) != 32 {
@@ -1,5 +1,7 @@
 package libp2p
 
+import "fmt"
+
 // Global topic constants for P2P communication.
 // These topics are cluster-wide (no projectID scoping).
 const (
@@ -21,6 +23,9 @@ const (
 
 	// Interest synchronization (share interests across cluster)
 	TopicInterestSync = "/agent-collab/interest/sync"
+
+	// Ephemeral presence (where agents are currently editing)
+	TopicPresence = "/agent-collab/presence"
 )
 
 // AllGlobalTopics returns all global topics for subscription.
@@ -35,6 +40,7 @@ func AllGlobalTopics() []string {
 		TopicLockRelease,
 		TopicContextSync,
 		TopicInterestSync,
+		TopicPresence,
 	}
 }
 
@@ -46,6 +52,7 @@ func CoreTopics() []string {
 		TopicLockAcquire,
 		TopicLockRelease,
 		TopicContextSync,
+		TopicPresence,
 	}
 }
 
@@ -57,3 +64,91 @@ func ClusterTopics() []string {
 		TopicClusterPing,
 	}
 }
+
+// TopicScope selects whether application topics are shared by every node
+// in the cluster or limited to a single project.
+type TopicScope string
+
+const (
+	// TopicScopeGlobal uses the cluster-wide topics above.
+	TopicScopeGlobal TopicScope = "global"
+	// TopicScopeProject prefixes every topic with the project name.
+	TopicScopeProject TopicScope = "project"
+)
+
+// ParseTopicScope parses a scope name. An empty name selects the global scope.
+func ParseTopicScope(s string) (TopicScope, error) {
+	switch TopicScope(s) {
+	case "", TopicScopeGlobal:
+		return TopicScopeGlobal, nil
+	case TopicScopeProject:
+		return TopicScopeProject, nil
+	default:
+		return "", fmt.Errorf("unknown topic scope %q (want %q or %q)", s, TopicScopeGlobal, TopicScopeProject)
+	}
+}
+
+// TopicSet resolves the application topic names for one scope, so that
+// publishers, subscribers and message handlers always agree on them.
+// In the global scope the names equal the Topic* constants.
+type TopicSet struct {
+	scope  TopicScope
+	prefix string
+}
+
+// NewTopicSet creates the topic set for scope. The project scope needs a
+// project name; without one it falls back to the global scope.
+func NewTopicSet(scope TopicScope, project string) TopicSet {
+	if scope == TopicScopeProject && project != "" {
+		return TopicSet{scope: TopicScopeProject, prefix: "/agent-collab/" + project + "/"}
+	}
+	return TopicSet{scope: TopicScopeGlobal, prefix: "/agent-collab/"}
+}
+
+// GlobalTopicSet returns the cluster-wide topic set.
+func GlobalTopicSet() TopicSet {
+	return NewTopicSet(TopicScopeGlobal, "")
+}
+
+// Scope returns the scope the topics belong to.
+func (t TopicSet) Scope() TopicScope { return t.scope }
+
+// Events returns the events topic.
+func (t TopicSet) Events() string { return t.prefix + "events" }
+
+// LockIntent returns the lock intent topic.
+func (t TopicSet) LockIntent() string { return t.prefix + "locks/intent" }
+
+// LockAcquire returns the lock acquire topic.
+func (t TopicSet) LockAcquire() string { return t.prefix + "locks/acquire" }
+
+// LockRelease returns the lock release topic.
+func (t TopicSet) LockRelease() string { return t.prefix + "locks/release" }
+
+// ContextSync returns the context synchronization topic.
+func (t TopicSet) ContextSync() string { return t.prefix + "context/sync" }
+
+// ContextShard returns the topic carrying context for the files under a
+// directory shard such as "src" or "src/auth".
+func (t TopicSet) ContextShard(shard string) string { return t.prefix + "context/shard/" + shard }
+
+// ContextAll returns the topic carrying the context of every shard, for
+// nodes whose interests are not confined to directories.
+func (t TopicSet) ContextAll() string { return t.prefix + "context/all" }
+
+// InterestSync returns the interest synchronization topic.
+func (t TopicSet) InterestSync() string { return t.prefix + "interest/sync" }
+
+// Presence returns the ephemeral presence topic.
+func (t TopicSet) Presence() string { return t.prefix + "presence" }
+
+// LockTopics returns every lock topic.
+func (t TopicSet) LockTopics() []string {
+	return []string{t.LockIntent(), t.LockAcquire(), t.LockRelease()}
+}
+
+// Core returns the minimum set of topics for basic operation, matching
+// CoreTopics in the global scope.
+func (t TopicSet) Core() []string {
+	return append([]string{t.Events()}, append(t.LockTopics(), t.ContextSync(), t.Presence())...)
+}
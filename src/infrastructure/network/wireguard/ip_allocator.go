@@ -8,29 +8,67 @@ import (
 )
 
 // IPAllocator manages IP address allocation within a subnet.
+// Addresses are handed out lowest-free-first.
 type IPAllocator struct {
 	mu        sync.Mutex
 	subnet    *net.IPNet
-	allocated map[string]string // IP -> peerID
-	peerToIP  map[string]string // peerID -> IP
-	nextIndex uint32
+	allocated map[string]string   // IP -> peerID
+	peerToIP  map[string]string   // peerID -> IP
+	retired   map[string]struct{} // released IPs held out of the free pool
+	reclaim   bool
 }
 
-// NewIPAllocator creates a new IP allocator for the given subnet.
+// NewIPAllocator creates a new IP allocator for the given IPv4 subnet.
+// Released IPs are reclaimed into the free pool by default.
 func NewIPAllocator(subnetCIDR string) (*IPAllocator, error) {
 	_, subnet, err := net.ParseCIDR(subnetCIDR)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidSubnet, err)
 	}
+	if subnet.IP.To4() == nil {
+		return nil, fmt.Errorf("%w: %s is not an IPv4 subnet", ErrInvalidSubnet, subnetCIDR)
+	}
 
 	return &IPAllocator{
 		subnet:    subnet,
 		allocated: make(map[string]string),
 		peerToIP:  make(map[string]string),
-		nextIndex: 1, // Start at .1
+		retired:   make(map[string]struct{}),
+		reclaim:   true,
 	}, nil
 }
 
+// SetReclaim controls whether released IPs return to the free pool.
+// When disabled, released IPs stay retired until ReclaimRetired is called,
+// so a departed peer's address is never handed to a newcomer.
+func (a *IPAllocator) SetReclaim(reclaim bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.reclaim = reclaim
+}
+
+// ReclaimRetired returns all retired IPs to the free pool.
+// Returns the number of IPs reclaimed.
+func (a *IPAllocator) ReclaimRetired() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	n := len(a.retired)
+	a.retired = make(map[string]struct{})
+	return n
+}
+
+// Capacity returns the number of usable host addresses in the subnet.
+func (a *IPAllocator) Capacity() int {
+	return int(usableHosts(a.subnet))
+}
+
+// Available returns the number of addresses that can still be allocated.
+func (a *IPAllocator) Available() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.Capacity() - len(a.allocated) - len(a.retired)
+}
+
 // Allocate allocates an IP address for the given peer ID.
 // Returns the allocated IP with CIDR notation (e.g., "10.100.0.1/24").
 func (a *IPAllocator) Allocate(peerID string) (string, error) {
@@ -82,6 +120,7 @@ func (a *IPAllocator) AllocateSpecific(peerID, ipCIDR string) error {
 
 	a.allocated[ipStr] = peerID
 	a.peerToIP[peerID] = ipCIDR
+	delete(a.retired, ipStr)
 
 	return nil
 }
@@ -99,6 +138,9 @@ func (a *IPAllocator) Release(peerID string) error {
 	ip, _, _ := net.ParseCIDR(ipCIDR)
 	delete(a.allocated, ip.String())
 	delete(a.peerToIP, peerID)
+	if !a.reclaim {
+		a.retired[ip.String()] = struct{}{}
+	}
 
 	return nil
 }
@@ -151,30 +193,63 @@ func (a *IPAllocator) Subnet() string {
 	return a.subnet.String()
 }
 
-// findNextAvailable finds the next available IP in the subnet.
+// findNextAvailable finds the lowest free IP in the subnet.
 func (a *IPAllocator) findNextAvailable() (net.IP, error) {
-	ones, bits := a.subnet.Mask.Size()
-	maxHosts := uint32(1<<(bits-ones)) - 2 // Exclude network and broadcast
-
-	// Get base IP as uint32
+	maxHosts := usableHosts(a.subnet)
 	baseIP := binary.BigEndian.Uint32(a.subnet.IP.To4())
 
-	// Try to find an available IP
-	for i := uint32(0); i < maxHosts; i++ {
-		idx := (a.nextIndex+i-1)%maxHosts + 1 // 1 to maxHosts
+	for idx := uint64(1); idx <= maxHosts; idx++ {
 		candidateIP := make(net.IP, 4)
-		binary.BigEndian.PutUint32(candidateIP, baseIP+idx)
-
-		if _, ok := a.allocated[candidateIP.String()]; !ok {
-			a.nextIndex = idx + 1
-			if a.nextIndex > maxHosts {
-				a.nextIndex = 1
-			}
-			return candidateIP, nil
+		binary.BigEndian.PutUint32(candidateIP, baseIP+uint32(idx))
+
+		key := candidateIP.String()
+		if _, ok := a.allocated[key]; ok {
+			continue
 		}
+		if _, ok := a.retired[key]; ok {
+			continue
+		}
+		return candidateIP, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s has %d usable addresses (%d allocated, %d retired)",
+		ErrSubnetExhausted, a.subnet, maxHosts, len(a.allocated), len(a.retired))
+}
+
+// usableHosts returns the host count excluding network and broadcast addresses.
+func usableHosts(subnet *net.IPNet) uint64 {
+	ones, bits := subnet.Mask.Size()
+	hostBits := bits - ones
+	if hostBits < 2 {
+		return 0
+	}
+	return (uint64(1) << hostBits) - 2
+}
+
+// PrefixForHosts returns the longest IPv4 prefix whose subnet has at least
+// the given number of usable host addresses.
+func PrefixForHosts(hosts int) int {
+	for prefix := 30; prefix > 0; prefix-- {
+		if (uint64(1)<<(32-prefix))-2 >= uint64(hosts) {
+			return prefix
+		}
+	}
+	return 0
+}
+
+// ResizeSubnet returns the subnet starting at the base address of
+// subnetCIDR with the given prefix length.
+func ResizeSubnet(subnetCIDR string, prefix int) (string, error) {
+	ip, _, err := net.ParseCIDR(subnetCIDR)
+	if err != nil || ip.To4() == nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidSubnet, subnetCIDR)
+	}
+	if prefix < 1 || prefix > 30 {
+		return "", fmt.Errorf("%w: prefix /%d out of range 1-30", ErrInvalidSubnet, prefix)
 	}
 
-	return nil, ErrSubnetExhausted
+	mask := net.CIDRMask(prefix, 32)
+	return (&net.IPNet{IP: ip.To4().Mask(mask), Mask: mask}).String(), nil
 }
 
 // ListAllocations returns all current allocations.
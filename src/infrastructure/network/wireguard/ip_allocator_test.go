@@ -1,6 +1,8 @@
 package wireguard
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -199,3 +201,120 @@ func TestIPAllocatorGetIP(t *testing.T) {
 		t.Errorf("GetIP() = %v, %v; want %v, true", ip, ok, allocated)
 	}
 }
+
+func TestIPAllocatorExhaustionAndReclaim(t *testing.T) {
+	// /29 has 6 usable IPs
+	alloc, err := NewIPAllocator("10.100.0.0/29")
+	if err != nil {
+		t.Fatalf("NewIPAllocator() error = %v", err)
+	}
+	if alloc.Capacity() != 6 {
+		t.Fatalf("Capacity() = %d, want 6", alloc.Capacity())
+	}
+
+	for i := 1; i <= 6; i++ {
+		if _, err := alloc.Allocate(fmt.Sprintf("peer%d", i)); err != nil {
+			t.Fatalf("Allocate(peer%d) error = %v", i, err)
+		}
+	}
+
+	_, err = alloc.Allocate("peer7")
+	if !errors.Is(err, ErrSubnetExhausted) {
+		t.Fatalf("Allocate(peer7) error = %v, want ErrSubnetExhausted", err)
+	}
+	if !strings.Contains(err.Error(), "10.100.0.0/29") {
+		t.Errorf("exhaustion error should name the subnet: %v", err)
+	}
+
+	// Free two addresses; the lowest one is reused first
+	if err := alloc.Release("peer4"); err != nil {
+		t.Fatalf("Release(peer4) error = %v", err)
+	}
+	if err := alloc.Release("peer2"); err != nil {
+		t.Fatalf("Release(peer2) error = %v", err)
+	}
+
+	ip, err := alloc.Allocate("peer7")
+	if err != nil {
+		t.Fatalf("Allocate(peer7) after release error = %v", err)
+	}
+	if ip != "10.100.0.2/29" {
+		t.Errorf("Allocate(peer7) = %v, want lowest free 10.100.0.2/29", ip)
+	}
+}
+
+func TestIPAllocatorRetainReleasedIPs(t *testing.T) {
+	alloc, err := NewIPAllocator("10.100.0.0/30")
+	if err != nil {
+		t.Fatalf("NewIPAllocator() error = %v", err)
+	}
+	alloc.SetReclaim(false)
+
+	alloc.Allocate("peer1")
+	alloc.Allocate("peer2")
+	if err := alloc.Release("peer1"); err != nil {
+		t.Fatalf("Release(peer1) error = %v", err)
+	}
+
+	// Retired IP is not handed out again
+	if _, err := alloc.Allocate("peer3"); !errors.Is(err, ErrSubnetExhausted) {
+		t.Fatalf("Allocate(peer3) error = %v, want ErrSubnetExhausted", err)
+	}
+	if alloc.Available() != 0 {
+		t.Errorf("Available() = %d, want 0", alloc.Available())
+	}
+
+	if n := alloc.ReclaimRetired(); n != 1 {
+		t.Errorf("ReclaimRetired() = %d, want 1", n)
+	}
+
+	ip, err := alloc.Allocate("peer3")
+	if err != nil {
+		t.Fatalf("Allocate(peer3) after reclaim error = %v", err)
+	}
+	if ip != "10.100.0.1/30" {
+		t.Errorf("Allocate(peer3) = %v, want reclaimed 10.100.0.1/30", ip)
+	}
+}
+
+func TestPrefixForHosts(t *testing.T) {
+	tests := []struct {
+		hosts int
+		want  int
+	}{
+		{1, 30},
+		{2, 30},
+		{3, 29},
+		{254, 24},
+		{255, 23},
+		{1000, 22},
+	}
+
+	for _, tt := range tests {
+		if got := PrefixForHosts(tt.hosts); got != tt.want {
+			t.Errorf("PrefixForHosts(%d) = %d, want %d", tt.hosts, got, tt.want)
+		}
+	}
+}
+
+func TestManagerConfigEffectiveSubnet(t *testing.T) {
+	cfg := DefaultManagerConfig()
+
+	subnet, err := cfg.EffectiveSubnet()
+	if err != nil || subnet != "10.100.0.0/24" {
+		t.Errorf("EffectiveSubnet() = %v, %v; want 10.100.0.0/24", subnet, err)
+	}
+
+	cfg.ExpectedPeers = 300
+	subnet, err = cfg.EffectiveSubnet()
+	if err != nil || subnet != "10.100.0.0/23" {
+		t.Errorf("EffectiveSubnet() with 300 peers = %v, %v; want 10.100.0.0/23", subnet, err)
+	}
+
+	// A subnet that is already large enough is kept
+	cfg.Subnet = "10.100.0.0/16"
+	subnet, err = cfg.EffectiveSubnet()
+	if err != nil || subnet != "10.100.0.0/16" {
+		t.Errorf("EffectiveSubnet() = %v, %v; want 10.100.0.0/16", subnet, err)
+	}
+}
@@ -69,11 +69,17 @@ func (m *WireGuardManager) Initialize(ctx context.Context, cfg *ManagerConfig) e
 	}
 	m.keyPair = keyPair
 
+	subnet, err := cfg.EffectiveSubnet()
+	if err != nil {
+		return fmt.Errorf("failed to size subnet: %w", err)
+	}
+
 	// Create IP allocator
-	allocator, err := NewIPAllocator(cfg.Subnet)
+	allocator, err := NewIPAllocator(subnet)
 	if err != nil {
 		return fmt.Errorf("failed to create IP allocator: %w", err)
 	}
+	allocator.SetReclaim(!cfg.RetireReleasedIPs)
 	m.ipAllocator = allocator
 
 	// Allocate IP for self (first node gets .1)
@@ -100,7 +106,7 @@ func (m *WireGuardManager) Initialize(ctx context.Context, cfg *ManagerConfig) e
 		PublicKey:  keyPair.PublicKey,
 		ListenPort: cfg.ListenPort,
 		LocalIP:    localIP,
-		Subnet:     cfg.Subnet,
+		Subnet:     subnet,
 		MTU:        cfg.MTU,
 		Peers:      make([]*Peer, 0),
 	}
@@ -138,6 +144,7 @@ func (m *WireGuardManager) InitializeWithConfig(ctx context.Context, cfg *Config
 	if err != nil {
 		return fmt.Errorf("failed to create IP allocator: %w", err)
 	}
+	allocator.SetReclaim(!mgrCfg.RetireReleasedIPs)
 	m.ipAllocator = allocator
 
 	// Reserve local IP
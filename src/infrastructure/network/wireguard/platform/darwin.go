@@ -7,23 +7,14 @@ import (
 	"net"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"golang.zx2c4.com/wireguard/ipc"
 	"golang.zx2c4.com/wireguard/wgctrl"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 )
 
-const (
-	// wireguard-go uses /var/run/wireguard/ for Unix sockets on macOS
-	wgSocketDir = "/var/run/wireguard"
-	// Maximum time to wait for wireguard-go to be ready
-	maxWaitTime = 5 * time.Second
-	// Poll interval for checking socket readiness
-	pollInterval = 100 * time.Millisecond
-)
-
 func getPlatformImpl() Platform {
 	return &darwinPlatform{}
 }
@@ -35,15 +26,8 @@ func (p *darwinPlatform) Name() string {
 }
 
 func (p *darwinPlatform) IsSupported() bool {
-	// Check if wireguard-go is installed
-	if _, err := exec.LookPath("wireguard-go"); err == nil {
-		return true
-	}
-	// Check if wg-quick is available (from wireguard-tools)
-	if _, err := exec.LookPath("wg-quick"); err == nil {
-		return true
-	}
-	return false
+	// WireGuard runs in-process on the kernel's utun devices
+	return true
 }
 
 func (p *darwinPlatform) RequiresRoot() bool {
@@ -55,173 +39,58 @@ func (p *darwinPlatform) CreateInterface(name string) (Device, error) {
 		return nil, fmt.Errorf("wireguard: root privileges required")
 	}
 
-	// Step 1: Clean up any existing wireguard-go processes and sockets
-	p.cleanupExisting(name)
-
-	// Step 2: Ensure socket directory exists
-	if err := os.MkdirAll(wgSocketDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create socket directory: %w", err)
-	}
-
-	// Step 3: macOS requires utun interface names
-	// wireguard-go will auto-allocate utun device when given "utun"
-	utunName := "utun"
-
-	// Create a temp file to capture the actual interface name
-	nameFile := fmt.Sprintf("/tmp/wg-%s.name", name)
-
-	// Step 4: Start wireguard-go in userspace mode
-	cmd := exec.Command("wireguard-go", utunName)
-	cmd.Env = append(os.Environ(), "WG_TUN_NAME_FILE="+nameFile)
-
-	if output, err := cmd.CombinedOutput(); err != nil {
-		// Check if already running
-		if !strings.Contains(string(output), "already exists") {
-			return nil, fmt.Errorf("failed to create interface: %s: %w", output, err)
-		}
-	}
-
-	// Step 5: Read the actual interface name from the name file (with retry)
-	actualName, err := p.waitForNameFile(nameFile, maxWaitTime)
+	// macOS requires utun interface names; "utun" picks the next free one
+	tunnel, err := startTunnel(name, "utun")
 	if err != nil {
-		p.cleanupExisting(name)
-		return nil, fmt.Errorf("failed to get interface name: %w", err)
+		return nil, fmt.Errorf("failed to create interface: %w", err)
 	}
 
-	// Clean up name file
-	os.Remove(nameFile)
-
-	// Step 6: Wait for the Unix socket to be ready
-	socketPath := filepath.Join(wgSocketDir, actualName+".sock")
-	if err := p.waitForSocket(socketPath, maxWaitTime); err != nil {
-		p.cleanupExisting(name)
-		return nil, fmt.Errorf("wireguard-go socket not ready: %w", err)
-	}
-
-	// Step 7: Open wgctrl client with retry
-	client, err := p.createClientWithRetry(3)
+	client, err := wgctrl.New()
 	if err != nil {
-		p.cleanupExisting(name)
+		stopTunnel(name)
 		return nil, fmt.Errorf("failed to create wgctrl client: %w", err)
 	}
 
-	// Step 8: Verify device is accessible
-	if _, err := client.Device(actualName); err != nil {
+	// Verify device is accessible through its UAPI socket
+	if _, err := client.Device(tunnel.name); err != nil {
 		client.Close()
-		p.cleanupExisting(name)
+		stopTunnel(name)
 		return nil, fmt.Errorf("device not accessible: %w", err)
 	}
 
 	return &darwinDevice{
-		name:       actualName,
+		name:       tunnel.name,
 		configName: name,
 		client:     client,
 	}, nil
 }
 
-// cleanupExisting kills any existing wireguard-go processes and removes sockets
-func (p *darwinPlatform) cleanupExisting(name string) {
-	// Kill wireguard-go processes
-	exec.Command("pkill", "-9", "-f", "wireguard-go").Run()
-
-	// Wait a bit for processes to terminate
-	time.Sleep(200 * time.Millisecond)
-
-	// Remove any existing sockets
-	if files, err := filepath.Glob(filepath.Join(wgSocketDir, "*.sock")); err == nil {
-		for _, f := range files {
-			os.Remove(f)
-		}
-	}
-
-	// Remove name file
-	os.Remove(fmt.Sprintf("/tmp/wg-%s.name", name))
-}
-
-// waitForNameFile waits for the name file to be written and returns its contents
-func (p *darwinPlatform) waitForNameFile(path string, timeout time.Duration) (string, error) {
-	deadline := time.Now().Add(timeout)
-
-	for time.Now().Before(deadline) {
-		if data, err := os.ReadFile(path); err == nil {
-			name := strings.TrimSpace(string(data))
-			if name != "" {
-				return name, nil
-			}
-		}
-		time.Sleep(pollInterval)
-	}
-
-	return "", fmt.Errorf("timeout waiting for name file")
+func (p *darwinPlatform) DeleteInterface(name string) error {
+	// The utun interface goes away with the tunnel
+	stopTunnel(name)
+	return nil
 }
 
-// waitForSocket waits for the Unix socket to be created and accessible
-func (p *darwinPlatform) waitForSocket(path string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-
-	for time.Now().Before(deadline) {
-		if info, err := os.Stat(path); err == nil {
-			// Check if it's a socket
-			if info.Mode()&os.ModeSocket != 0 {
-				return nil
+func (p *darwinPlatform) GetExternalIP() (string, error) {
+	// Prefer the address of the default route's interface
+	if output, err := exec.Command("/sbin/route", "-n", "get", "default").Output(); err == nil {
+		if iface, ok := parseRouteGetInterface(string(output)); ok {
+			if ip, err := interfaceIPv4(iface); err == nil {
+				return ip, nil
 			}
 		}
-		time.Sleep(pollInterval)
-	}
-
-	return fmt.Errorf("timeout waiting for socket: %s", path)
-}
-
-// createClientWithRetry creates a wgctrl client with retry logic
-func (p *darwinPlatform) createClientWithRetry(maxAttempts int) (*wgctrl.Client, error) {
-	var lastErr error
-
-	for i := 0; i < maxAttempts; i++ {
-		client, err := wgctrl.New()
-		if err == nil {
-			return client, nil
-		}
-		lastErr = err
-
-		// Wait before retry
-		time.Sleep(500 * time.Millisecond)
 	}
-
-	return nil, lastErr
+	return outboundIP()
 }
 
-func (p *darwinPlatform) DeleteInterface(name string) error {
-	// On macOS, we need to kill the wireguard-go process
-	// This will automatically remove the utun interface
-
-	// Find and kill wireguard-go processes
-	exec.Command("pkill", "-9", "-f", "wireguard-go").Run()
-
-	// Wait for cleanup
-	time.Sleep(200 * time.Millisecond)
-
-	// Clean up socket
-	if files, err := filepath.Glob(filepath.Join(wgSocketDir, "*.sock")); err == nil {
-		for _, f := range files {
-			os.Remove(f)
-		}
-	}
-
-	// Clean up name file
-	os.Remove(fmt.Sprintf("/tmp/wg-%s.name", name))
-
-	return nil
-}
-
-func (p *darwinPlatform) GetExternalIP() (string, error) {
-	conn, err := net.Dial("udp", "8.8.8.8:80")
+// uapiListen serves the UAPI socket in /var/run/wireguard that wgctrl
+// looks for.
+func uapiListen(name string) (net.Listener, error) {
+	file, err := ipc.UAPIOpen(name)
 	if err != nil {
-		return "", fmt.Errorf("failed to detect external IP: %w", err)
+		return nil, err
 	}
-	defer conn.Close()
-
-	localAddr := conn.LocalAddr().(*net.UDPAddr)
-	return localAddr.IP.String(), nil
+	return ipc.UAPIListen(name, file)
 }
 
 type darwinDevice struct {
@@ -380,7 +249,9 @@ func (d *darwinDevice) GetConfig() (*DeviceConfig, error) {
 }
 
 func (d *darwinDevice) Close() error {
-	return d.client.Close()
+	err := d.client.Close()
+	stopTunnel(d.configName)
+	return err
 }
 
 // ipNetMask converts an IPNet mask to dotted decimal format
@@ -0,0 +1,97 @@
+package platform
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// outboundIP returns the local address the OS picks for outbound traffic.
+// It sends nothing; dialing UDP only selects a route.
+func outboundIP() (string, error) {
+	conn, err := net.Dial("udp", "8.8.8.8:80")
+	if err != nil {
+		return "", fmt.Errorf("failed to detect external IP: %w", err)
+	}
+	defer conn.Close()
+
+	localAddr := conn.LocalAddr().(*net.UDPAddr)
+	return localAddr.IP.String(), nil
+}
+
+// interfaceIPv4 returns the first global unicast IPv4 address of an
+// interface.
+func interfaceIPv4(name string) (string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ip := ipNet.IP.To4(); ip != nil && ip.IsGlobalUnicast() {
+			return ip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no IPv4 address on %s", name)
+}
+
+// parseIPRouteSrc returns the source address from `ip -4 route get`
+// output (Linux).
+func parseIPRouteSrc(output string) (string, bool) {
+	fields := strings.Fields(output)
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == "src" && net.ParseIP(fields[i+1]) != nil {
+			return fields[i+1], true
+		}
+	}
+	return "", false
+}
+
+// parseRouteGetInterface returns the interface from `route -n get default`
+// output (macOS).
+func parseRouteGetInterface(output string) (string, bool) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if ok && key == "interface" {
+			if name := strings.TrimSpace(value); name != "" {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseRoutePrintDefault returns the interface address of the lowest
+// metric default route in `route print -4 0.0.0.0` output (Windows).
+func parseRoutePrintDefault(output string) (string, bool) {
+	best, bestMetric := "", -1
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		// Network Destination, Netmask, Gateway, Interface, Metric
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 5 || fields[0] != "0.0.0.0" || fields[1] != "0.0.0.0" {
+			continue
+		}
+		if net.ParseIP(fields[3]) == nil {
+			continue
+		}
+		metric, err := strconv.Atoi(fields[4])
+		if err != nil {
+			continue
+		}
+		if bestMetric < 0 || metric < bestMetric {
+			best, bestMetric = fields[3], metric
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,49 @@
+package platform
+
+import "testing"
+
+func TestParseIPRouteSrc(t *testing.T) {
+	out := "1.1.1.1 via 192.168.1.1 dev eth0 src 192.168.1.23 uid 1000 \n    cache \n"
+	if got, ok := parseIPRouteSrc(out); !ok || got != "192.168.1.23" {
+		t.Errorf("parseIPRouteSrc() = %q, %v, want 192.168.1.23", got, ok)
+	}
+	if _, ok := parseIPRouteSrc("RTNETLINK answers: Network is unreachable"); ok {
+		t.Error("parseIPRouteSrc() found a source in an error")
+	}
+}
+
+func TestParseRouteGetInterface(t *testing.T) {
+	out := `   route to: default
+destination: default
+       mask: default
+    gateway: 192.168.1.1
+  interface: en0
+      flags: <UP,GATEWAY,DONE,STATIC,PRCLONING>
+`
+	if got, ok := parseRouteGetInterface(out); !ok || got != "en0" {
+		t.Errorf("parseRouteGetInterface() = %q, %v, want en0", got, ok)
+	}
+	if _, ok := parseRouteGetInterface("route: writing to routing socket: not in table"); ok {
+		t.Error("parseRouteGetInterface() found an interface in an error")
+	}
+}
+
+func TestParseRoutePrintDefault(t *testing.T) {
+	out := `===========================================================================
+IPv4 Route Table
+===========================================================================
+Active Routes:
+Network Destination        Netmask          Gateway       Interface  Metric
+          0.0.0.0          0.0.0.0      10.0.0.1        10.0.0.57     55
+          0.0.0.0          0.0.0.0   192.168.1.1     192.168.1.40     25
+===========================================================================
+Persistent Routes:
+  None
+`
+	if got, ok := parseRoutePrintDefault(out); !ok || got != "192.168.1.40" {
+		t.Errorf("parseRoutePrintDefault() = %q, %v, want 192.168.1.40", got, ok)
+	}
+	if _, ok := parseRoutePrintDefault("Active Routes:\n  None\n"); ok {
+		t.Error("parseRoutePrintDefault() found a route in an empty table")
+	}
+}
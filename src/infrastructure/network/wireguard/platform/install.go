@@ -31,8 +31,8 @@ func GetInstallInstructions() *InstallInfo {
 	case "windows":
 		info.Instructions = getWindowsInstructions(runtime.GOARCH)
 		info.URLs = []string{
+			"https://www.wintun.net",
 			"https://www.wireguard.com/install/",
-			"https://download.wireguard.com/windows-client/",
 		}
 	default:
 		info.Instructions = []string{
@@ -46,41 +46,14 @@ func GetInstallInstructions() *InstallInfo {
 }
 
 func getDarwinInstructions(arch string) []string {
-	instructions := []string{
-		"WireGuard를 설치하려면 다음 명령어를 실행하세요:",
+	// WireGuard runs in-process on utun, so nothing needs installing
+	return []string{
+		"macOS에서는 WireGuard가 내장 utun 장치 위에서 프로세스 내부로 실행됩니다.",
+		"별도 설치 없이 sudo로 다시 시도하세요.",
 		"",
+		"  # 선택 사항: 터널 상태 확인용 wg 명령어",
+		"  brew install wireguard-tools",
 	}
-
-	switch arch {
-	case "arm64":
-		instructions = append(instructions,
-			"  # Homebrew 사용 (Apple Silicon)",
-			"  brew install wireguard-go wireguard-tools",
-			"",
-			"  # 또는 MacPorts 사용",
-			"  sudo port install wireguard-go wireguard-tools",
-		)
-	case "amd64":
-		instructions = append(instructions,
-			"  # Homebrew 사용 (Intel)",
-			"  brew install wireguard-go wireguard-tools",
-			"",
-			"  # 또는 MacPorts 사용",
-			"  sudo port install wireguard-go wireguard-tools",
-		)
-	default:
-		instructions = append(instructions,
-			"  # Homebrew 사용",
-			"  brew install wireguard-go wireguard-tools",
-		)
-	}
-
-	instructions = append(instructions,
-		"",
-		"설치 후 다시 시도하세요.",
-	)
-
-	return instructions
 }
 
 func getLinuxInstructions(arch string) []string {
@@ -133,47 +106,20 @@ func getLinuxInstructions(arch string) []string {
 }
 
 func getWindowsInstructions(arch string) []string {
-	instructions := []string{
-		"WireGuard를 설치하려면:",
-		"",
+	// wintun.dll ships per architecture in the wintun release zip
+	dllArch := arch
+	if arch == "386" {
+		dllArch = "x86"
 	}
 
-	switch arch {
-	case "amd64":
-		instructions = append(instructions,
-			"  # 방법 1: 공식 설치 프로그램 (권장)",
-			"  # https://download.wireguard.com/windows-client/wireguard-installer.exe",
-			"",
-			"  # 방법 2: winget 사용",
-			"  winget install WireGuard.WireGuard",
-			"",
-			"  # 방법 3: Chocolatey 사용",
-			"  choco install wireguard",
-			"",
-			"  # 방법 4: Scoop 사용",
-			"  scoop install wireguard",
-		)
-	case "arm64":
-		instructions = append(instructions,
-			"  # Windows ARM64용 WireGuard",
-			"  # https://download.wireguard.com/windows-client/ 에서 ARM64 버전 다운로드",
-			"",
-			"  # 또는 winget 사용",
-			"  winget install WireGuard.WireGuard",
-		)
-	default:
-		instructions = append(instructions,
-			"  # 공식 설치 프로그램 다운로드",
-			"  # https://download.wireguard.com/windows-client/wireguard-installer.exe",
-		)
-	}
-
-	instructions = append(instructions,
+	return []string{
+		"WireGuard는 프로세스 내부에서 wintun 어댑터로 실행되며 wintun.dll이 필요합니다:",
+		"",
+		"  # 1. https://www.wintun.net 에서 wintun 압축 파일 다운로드",
+		fmt.Sprintf("  # 2. wintun\\bin\\%s\\wintun.dll 을 agent-collab.exe와 같은 폴더(또는 System32)에 복사", dllArch),
 		"",
 		"설치 후 관리자 권한으로 다시 시도하세요.",
-	)
-
-	return instructions
+	}
 }
 
 // FormatInstallInstructions returns a formatted string of installation instructions.
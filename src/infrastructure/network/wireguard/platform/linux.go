@@ -80,15 +80,13 @@ func (p *linuxPlatform) DeleteInterface(name string) error {
 }
 
 func (p *linuxPlatform) GetExternalIP() (string, error) {
-	// Try to detect external IP by checking default route interface
-	conn, err := net.Dial("udp", "8.8.8.8:80")
-	if err != nil {
-		return "", fmt.Errorf("failed to detect external IP: %w", err)
+	// Prefer the source address of the default route
+	if output, err := exec.Command("ip", "-4", "route", "get", "1.1.1.1").Output(); err == nil {
+		if ip, ok := parseIPRouteSrc(string(output)); ok {
+			return ip, nil
+		}
 	}
-	defer conn.Close()
-
-	localAddr := conn.LocalAddr().(*net.UDPAddr)
-	return localAddr.IP.String(), nil
+	return outboundIP()
 }
 
 type linuxDevice struct {
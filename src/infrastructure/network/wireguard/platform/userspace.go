@@ -0,0 +1,83 @@
+//go:build darwin || windows
+
+package platform
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"golang.zx2c4.com/wireguard/conn"
+	"golang.zx2c4.com/wireguard/device"
+	"golang.zx2c4.com/wireguard/tun"
+)
+
+// userspaceMTU is the tunnel MTU, wireguard-go's default.
+const userspaceMTU = device.DefaultMTU
+
+// userspaceTunnel is wireguard-go running inside this process on a native
+// TUN device: utun on macOS, wintun on Windows. It serves the UAPI socket
+// wgctrl configures it through, so no wireguard-go binary is needed.
+type userspaceTunnel struct {
+	name   string // Actual interface name (e.g., utun5)
+	device *device.Device
+	uapi   net.Listener
+}
+
+var (
+	tunnelsMu sync.Mutex
+	// Running tunnels by requested interface name
+	tunnels = make(map[string]*userspaceTunnel)
+)
+
+// startTunnel creates a TUN device named tunName and runs wireguard-go on
+// it, replacing any tunnel already running under the requested name.
+func startTunnel(requested, tunName string) (*userspaceTunnel, error) {
+	stopTunnel(requested)
+
+	tunDev, err := tun.CreateTUN(tunName, userspaceMTU)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TUN device: %w", err)
+	}
+	name, err := tunDev.Name()
+	if err != nil {
+		tunDev.Close()
+		return nil, fmt.Errorf("failed to get interface name: %w", err)
+	}
+
+	// Closing the device also closes the TUN device
+	dev := device.NewDevice(tunDev, conn.NewDefaultBind(), device.NewLogger(device.LogLevelError, fmt.Sprintf("wireguard(%s): ", name)))
+	uapi, err := uapiListen(name)
+	if err != nil {
+		dev.Close()
+		return nil, fmt.Errorf("failed to listen on UAPI socket: %w", err)
+	}
+	go func() {
+		for {
+			c, err := uapi.Accept()
+			if err != nil {
+				return
+			}
+			go dev.IpcHandle(c)
+		}
+	}()
+
+	t := &userspaceTunnel{name: name, device: dev, uapi: uapi}
+	tunnelsMu.Lock()
+	tunnels[requested] = t
+	tunnelsMu.Unlock()
+	return t, nil
+}
+
+// stopTunnel tears down the tunnel started under the requested name, if any.
+func stopTunnel(requested string) {
+	tunnelsMu.Lock()
+	t, ok := tunnels[requested]
+	delete(tunnels, requested)
+	tunnelsMu.Unlock()
+
+	if ok {
+		t.uapi.Close()
+		t.device.Close()
+	}
+}
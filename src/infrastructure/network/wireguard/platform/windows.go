@@ -5,11 +5,12 @@ package platform
 import (
 	"fmt"
 	"net"
-	"os"
 	"os/exec"
 	"strings"
 	"time"
 
+	"golang.org/x/sys/windows"
+	"golang.zx2c4.com/wireguard/ipc"
 	"golang.zx2c4.com/wireguard/wgctrl"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 )
@@ -25,22 +26,20 @@ func (p *windowsPlatform) Name() string {
 }
 
 func (p *windowsPlatform) IsSupported() bool {
-	// Check if WireGuard is installed
-	if _, err := exec.LookPath("wireguard.exe"); err == nil {
-		return true
-	}
-	// Check common installation path
-	if _, err := os.Stat("C:\\Program Files\\WireGuard\\wireguard.exe"); err == nil {
-		return true
+	// WireGuard runs in-process on a wintun adapter, which needs
+	// wintun.dll next to the executable or in System32
+	dll, err := windows.LoadLibraryEx("wintun.dll", 0,
+		windows.LOAD_LIBRARY_SEARCH_APPLICATION_DIR|windows.LOAD_LIBRARY_SEARCH_SYSTEM32)
+	if err != nil {
+		return false
 	}
-	return false
+	windows.FreeLibrary(dll)
+	return true
 }
 
 func (p *windowsPlatform) RequiresRoot() bool {
-	// On Windows, we need administrator privileges
-	// Check if we're running as admin by trying to open a privileged handle
-	_, err := os.Open("\\\\.\\PHYSICALDRIVE0")
-	return err != nil
+	// Creating a wintun adapter needs an elevated (administrator) process
+	return !windows.GetCurrentProcessToken().IsElevated()
 }
 
 func (p *windowsPlatform) CreateInterface(name string) (Device, error) {
@@ -48,58 +47,56 @@ func (p *windowsPlatform) CreateInterface(name string) (Device, error) {
 		return nil, fmt.Errorf("wireguard: administrator privileges required")
 	}
 
-	// Windows uses wintun driver
-	// WireGuard for Windows manages this automatically
-	// We'll use wireguard-go for userspace implementation
-
-	cmd := exec.Command("wireguard-go.exe", name)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		if !strings.Contains(string(output), "already exists") {
-			return nil, fmt.Errorf("failed to create interface: %s: %w", output, err)
-		}
+	tunnel, err := startTunnel(name, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create interface: %w", err)
 	}
 
-	// Wait for interface to be ready
-	time.Sleep(1 * time.Second)
-
-	// Open wgctrl client
 	client, err := wgctrl.New()
 	if err != nil {
+		stopTunnel(name)
 		return nil, fmt.Errorf("failed to create wgctrl client: %w", err)
 	}
 
+	// Verify device is accessible through its UAPI named pipe
+	if _, err := client.Device(tunnel.name); err != nil {
+		client.Close()
+		stopTunnel(name)
+		return nil, fmt.Errorf("device not accessible: %w", err)
+	}
+
 	return &windowsDevice{
-		name:   name,
-		client: client,
+		name:       tunnel.name,
+		configName: name,
+		client:     client,
 	}, nil
 }
 
 func (p *windowsPlatform) DeleteInterface(name string) error {
-	// On Windows, stop the WireGuard tunnel service
-	cmd := exec.Command("net", "stop", "WireGuardTunnel$"+name)
-	_ = cmd.Run() // Ignore error if service doesn't exist
-
-	// Kill wireguard-go process if running
-	cmd = exec.Command("taskkill", "/F", "/IM", "wireguard-go.exe")
-	_ = cmd.Run()
-
+	// The wintun adapter is removed with the tunnel
+	stopTunnel(name)
 	return nil
 }
 
 func (p *windowsPlatform) GetExternalIP() (string, error) {
-	conn, err := net.Dial("udp", "8.8.8.8:80")
-	if err != nil {
-		return "", fmt.Errorf("failed to detect external IP: %w", err)
+	// Prefer the interface address of the lowest metric default route
+	if output, err := exec.Command("route", "print", "-4", "0.0.0.0").Output(); err == nil {
+		if ip, ok := parseRoutePrintDefault(string(output)); ok {
+			return ip, nil
+		}
 	}
-	defer conn.Close()
+	return outboundIP()
+}
 
-	localAddr := conn.LocalAddr().(*net.UDPAddr)
-	return localAddr.IP.String(), nil
+// uapiListen serves the UAPI named pipe wgctrl looks for.
+func uapiListen(name string) (net.Listener, error) {
+	return ipc.UAPIListen(name)
 }
 
 type windowsDevice struct {
-	name   string
-	client *wgctrl.Client
+	name       string // Actual adapter name
+	configName string // Original requested name for reference
+	client     *wgctrl.Client
 }
 
 func (d *windowsDevice) Name() string {
@@ -248,7 +245,9 @@ func (d *windowsDevice) GetConfig() (*DeviceConfig, error) {
 }
 
 func (d *windowsDevice) Close() error {
-	return d.client.Close()
+	err := d.client.Close()
+	stopTunnel(d.configName)
+	return err
 }
 
 func ipNetMaskWindows(ipNet *net.IPNet) string {
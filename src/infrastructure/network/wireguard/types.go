@@ -2,6 +2,7 @@
 package wireguard
 
 import (
+	"fmt"
 	"net"
 	"time"
 )
@@ -138,6 +139,11 @@ type ManagerConfig struct {
 	MTU                 int    `json:"mtu"`
 	PersistentKeepalive int    `json:"persistent_keepalive"`
 	AutoDetectEndpoint  bool   `json:"auto_detect_endpoint"`
+	// ExpectedPeers widens Subnet when it is too small to fit this many peers
+	ExpectedPeers int `json:"expected_peers,omitempty"`
+	// RetireReleasedIPs keeps IPs of departed peers out of the free pool
+	// instead of reclaiming them (the default)
+	RetireReleasedIPs bool `json:"retire_released_ips,omitempty"`
 }
 
 // DefaultManagerConfig returns a default manager configuration.
@@ -151,3 +157,23 @@ func DefaultManagerConfig() *ManagerConfig {
 		AutoDetectEndpoint:  true,
 	}
 }
+
+// EffectiveSubnet returns Subnet, widened if needed to fit ExpectedPeers
+// plus the local node.
+func (c *ManagerConfig) EffectiveSubnet() (string, error) {
+	if c.ExpectedPeers <= 0 {
+		return c.Subnet, nil
+	}
+
+	_, subnet, err := net.ParseCIDR(c.Subnet)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSubnet, err)
+	}
+
+	ones, _ := subnet.Mask.Size()
+	prefix := PrefixForHosts(c.ExpectedPeers + 1)
+	if prefix >= ones {
+		return c.Subnet, nil
+	}
+	return ResizeSubnet(c.Subnet, prefix)
+}
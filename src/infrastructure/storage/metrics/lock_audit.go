@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"agent-collab/src/domain/lock"
+)
+
+// lockAuditPrefix names the daily lock audit files, e.g.
+// "locks_2024-01-15.jsonl". It has the same length as "usage_" so
+// Cleanup can parse both.
+const lockAuditPrefix = "locks_"
+
+// LockHistoryQuery filters the lock audit log. Zero fields match
+// everything.
+type LockHistoryQuery struct {
+	// FilePath matches entries for this file, or for files under it when
+	// it ends with a slash
+	FilePath string
+	HolderID string
+	Action   string
+	Since    time.Time
+	Until    time.Time
+	// Limit caps the number of entries returned (0 returns all)
+	Limit int
+}
+
+// matches reports whether an entry passes the filter.
+func (q LockHistoryQuery) matches(entry *lock.HistoryEntry) bool {
+	if q.FilePath != "" {
+		if strings.HasSuffix(q.FilePath, "/") {
+			if !strings.HasPrefix(entry.FilePath, q.FilePath) {
+				return false
+			}
+		} else if entry.FilePath != q.FilePath {
+			return false
+		}
+	}
+	if q.HolderID != "" && entry.HolderID != q.HolderID {
+		return false
+	}
+	if q.Action != "" && entry.Action != q.Action {
+		return false
+	}
+	if !q.Since.IsZero() && entry.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && entry.Timestamp.After(q.Until) {
+		return false
+	}
+	return true
+}
+
+// SaveLockEntry appends a lock history entry to the audit log. Unlike
+// usage records it is written straight away, so the log survives a crash.
+func (s *Store) SaveLockEntry(entry *lock.HistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.dataDir, lockAuditPrefix+entry.Timestamp.Format("2006-01-02")+".jsonl")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open lock audit file: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// LockHistory returns the audited lock history entries matching q,
+// newest first.
+func (s *Store) LockHistory(q LockHistoryQuery) ([]*lock.HistoryEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	days, err := s.lockAuditDays()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*lock.HistoryEntry
+	for _, day := range days {
+		if !q.Since.IsZero() && day.AddDate(0, 0, 1).Before(q.Since) {
+			break
+		}
+		if !q.Until.IsZero() && day.After(q.Until) {
+			continue
+		}
+
+		entries, err := s.loadLockDay(day)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range slices.Backward(entries) {
+			if !q.matches(entry) {
+				continue
+			}
+			result = append(result, entry)
+			if q.Limit > 0 && len(result) >= q.Limit {
+				return result, nil
+			}
+		}
+	}
+	return result, nil
+}
+
+// lockAuditDays returns the days that have a lock audit file, newest
+// first.
+func (s *Store) lockAuditDays() ([]time.Time, error) {
+	entries, err := os.ReadDir(s.dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []time.Time
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, lockAuditPrefix) || !strings.HasSuffix(name, ".jsonl") {
+			continue
+		}
+		day, err := time.ParseInLocation("2006-01-02", strings.TrimSuffix(strings.TrimPrefix(name, lockAuditPrefix), ".jsonl"), time.Local)
+		if err != nil {
+			continue
+		}
+		days = append(days, day)
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return b.Compare(a) })
+	return days, nil
+}
+
+// loadLockDay loads the lock audit entries of a day, oldest first.
+func (s *Store) loadLockDay(day time.Time) ([]*lock.HistoryEntry, error) {
+	path := filepath.Join(s.dataDir, lockAuditPrefix+day.Format("2006-01-02")+".jsonl")
+	// #nosec G304 - path is constructed from s.dataDir (app data directory) and a date-based filename
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []*lock.HistoryEntry
+	for _, line := range splitLines(data) {
+		var entry lock.HistoryEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
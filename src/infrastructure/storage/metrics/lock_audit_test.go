@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"agent-collab/src/domain/lock"
+)
+
+func TestStore_LockHistory(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	entries := []*lock.HistoryEntry{
+		{LockID: "l1", Action: "acquired", HolderID: "a", FilePath: "src/auth/handler.go", FencingToken: 1, Timestamp: now.AddDate(0, 0, -2)},
+		{LockID: "l1", Action: "released", HolderID: "a", FilePath: "src/auth/handler.go", FencingToken: 1, Timestamp: now.Add(-time.Hour)},
+		{LockID: "l2", Action: "acquired", HolderID: "b", FilePath: "src/auth/token.go", FencingToken: 2, Timestamp: now.Add(-time.Minute)},
+		{LockID: "l3", Action: "acquired", HolderID: "b", FilePath: "src/db/conn.go", FencingToken: 3, Timestamp: now},
+	}
+	for _, e := range entries {
+		if err := store.SaveLockEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		q    LockHistoryQuery
+		want []string // "lockID/action", newest first
+	}{
+		{"all", LockHistoryQuery{}, []string{"l3/acquired", "l2/acquired", "l1/released", "l1/acquired"}},
+		{"file", LockHistoryQuery{FilePath: "src/auth/handler.go"}, []string{"l1/released", "l1/acquired"}},
+		{"directory", LockHistoryQuery{FilePath: "src/auth/"}, []string{"l2/acquired", "l1/released", "l1/acquired"}},
+		{"holder and action", LockHistoryQuery{HolderID: "b", Action: "acquired"}, []string{"l3/acquired", "l2/acquired"}},
+		{"since", LockHistoryQuery{Since: now.Add(-24 * time.Hour)}, []string{"l3/acquired", "l2/acquired", "l1/released"}},
+		{"limit", LockHistoryQuery{Limit: 2}, []string{"l3/acquired", "l2/acquired"}},
+	}
+	for _, tt := range tests {
+		got, err := store.LockHistory(tt.q)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var ids []string
+		for _, e := range got {
+			ids = append(ids, e.LockID+"/"+e.Action)
+		}
+		if len(ids) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, ids, tt.want)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, ids, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -149,10 +149,13 @@ func (s *Store) AggregateDay(date time.Time) (*DailyAggregate, error) {
 		agg.ByCategory[r.Category] += r.Tokens
 		agg.ByModel[r.Model] += r.Tokens
 		agg.RecordCount++
+		if r.Cost > 0 {
+			agg.EstimatedCost += r.Cost
+		} else {
+			agg.EstimatedCost += token.EstimateCost(r.Tokens, r.Model)
+		}
 	}
 
-	agg.EstimatedCost = token.EstimateCost(agg.TotalTokens, "")
-
 	return agg, nil
 }
 
@@ -190,7 +193,7 @@ func (s *Store) Cleanup(retentionDays int) error {
 
 		// Parse date from filename
 		name := entry.Name()
-		if len(name) < 15 || name[:6] != "usage_" {
+		if len(name) < 16 || (name[:6] != "usage_" && name[:6] != lockAuditPrefix) {
 			continue
 		}
 
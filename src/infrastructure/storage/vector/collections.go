@@ -0,0 +1,62 @@
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// DefaultCollection is the collection documents go to when they name none.
+const DefaultCollection = "default"
+
+// collectionNamePattern keeps collection names safe to use as file names.
+var collectionNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// ErrDimensionMismatch is returned when an embedding does not have the
+// dimension of its collection.
+var ErrDimensionMismatch = errors.New("embedding dimension does not match collection")
+
+// ValidateCollectionName reports whether name can be used for a collection:
+// 1-64 letters, digits, '.', '_' or '-', starting with a letter or digit.
+func ValidateCollectionName(name string) error {
+	if !collectionNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid collection name %q: use 1-64 letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
+
+// ExpiringStore is implemented by stores that expire the documents of a
+// collection once they are older than its TTL.
+type ExpiringStore interface {
+	// SetCollectionTTL sets how long documents in a collection are kept
+	// after their last update. 0 keeps them until deleted.
+	SetCollectionTTL(name string, ttl time.Duration) error
+	// ExpireDocuments removes the documents whose TTL has passed at now and
+	// returns how many were removed from each collection.
+	ExpireDocuments(now time.Time) (map[string]int, error)
+}
+
+// GetFromAny returns the document with id from whichever collection holds
+// it, trying the default collection first.
+func GetFromAny(store Store, id string) (*Document, error) {
+	doc, err := store.Get(DefaultCollection, id)
+	if err == nil {
+		return doc, nil
+	}
+	names, listErr := store.ListCollections()
+	if listErr != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if name == DefaultCollection {
+			continue
+		}
+		if doc, err := store.Get(name, id); err == nil {
+			return doc, nil
+		}
+	}
+	return nil, fmt.Errorf("document not found: %s", id)
+}
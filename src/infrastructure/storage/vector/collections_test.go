@@ -0,0 +1,131 @@
+package vector
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateCollectionName(t *testing.T) {
+	for _, name := range []string{"default", "file-deltas", "arch_notes.v2"} {
+		if err := ValidateCollectionName(name); err != nil {
+			t.Errorf("expected %q to be valid, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../etc", "a/b", ".hidden", "with space"} {
+		if err := ValidateCollectionName(name); err == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestMemoryStore_DeletedCollectionStaysDeleted(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewMemoryStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateCollection("notes", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteCollection("notes"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewMemoryStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names, _ := reopened.ListCollections(); len(names) != 0 {
+		t.Errorf("expected no collections after reload, got %v", names)
+	}
+}
+
+func TestMemoryStore_EnforcesCollectionDimension(t *testing.T) {
+	store, err := NewMemoryStore(t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateCollection("wide", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Insert(&Document{Collection: "wide", Content: "x", Embedding: []float32{1, 0}})
+	if !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("expected ErrDimensionMismatch, got %v", err)
+	}
+	if err := store.Insert(&Document{Collection: "wide", Content: "x", Embedding: []float32{1, 0, 0}}); err != nil {
+		t.Errorf("expected a matching embedding to be stored, got %v", err)
+	}
+}
+
+func TestMemoryStore_ExpireDocuments(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewMemoryStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateCollection("deltas", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetCollectionTTL("deltas", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	docs := []*Document{
+		{ID: "delta", Collection: "deltas", Content: "delta", Embedding: []float32{1, 0}},
+		{ID: "note", Content: "note", Embedding: []float32{0, 1}},
+	}
+	if err := store.InsertBatch(docs); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := store.ExpireDocuments(time.Now().Add(30 * time.Minute))
+	if err != nil || len(expired) != 0 {
+		t.Fatalf("nothing should expire within the TTL, got %v (%v)", expired, err)
+	}
+
+	expired, err = store.ExpireDocuments(time.Now().Add(2 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired["deltas"] != 1 || len(expired) != 1 {
+		t.Errorf("expected one expired delta, got %v", expired)
+	}
+	if _, err := store.Get("deltas", "delta"); err == nil {
+		t.Error("expired delta should be removed")
+	}
+	if _, err := store.Get(DefaultCollection, "note"); err != nil {
+		t.Errorf("collections without a TTL keep their documents, got %v", err)
+	}
+
+	// The TTL survives a reload
+	reopened, err := NewMemoryStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := reopened.GetCollectionStats("deltas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TTL != time.Hour || stats.Count != 0 {
+		t.Errorf("expected an empty collection with a 1h TTL, got %+v", stats)
+	}
+}
+
+func TestGetFromAny(t *testing.T) {
+	store, err := NewMemoryStore(t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Insert(&Document{ID: "delta", Collection: "deltas", Content: "delta", Embedding: []float32{1, 0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := GetFromAny(store, "delta")
+	if err != nil || doc.ID != "delta" {
+		t.Fatalf("expected the document from the deltas collection, got %v (%v)", doc, err)
+	}
+	if _, err := GetFromAny(store, "missing"); err == nil {
+		t.Error("expected an error for a missing document")
+	}
+}
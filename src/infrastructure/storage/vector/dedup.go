@@ -0,0 +1,129 @@
+package vector
+
+import "sort"
+
+// contentEntry is an embedding shared by every document in a collection
+// with the same content. It lives as long as at least one document
+// references it.
+type contentEntry struct {
+	Embedding []float32 `json:"embedding"`
+
+	refs map[string]struct{} // document IDs
+}
+
+// retain points doc at the pooled embedding for its content and records
+// the reference. A document without an embedding picks up the pooled one.
+func (c *collection) retain(doc *Document) {
+	if c.Contents == nil {
+		c.Contents = make(map[string]*contentEntry)
+	}
+
+	key := computeHash(doc.Content)
+	entry, exists := c.Contents[key]
+	if !exists {
+		if len(doc.Embedding) == 0 {
+			return
+		}
+		entry = &contentEntry{Embedding: doc.Embedding, refs: make(map[string]struct{})}
+		c.Contents[key] = entry
+	}
+
+	switch {
+	case len(doc.Embedding) == 0 || len(doc.Embedding) == len(entry.Embedding):
+		doc.Embedding = entry.Embedding
+	default:
+		// The embedding model changed; move every reference to the new vector
+		entry.Embedding = doc.Embedding
+		for id := range entry.refs {
+			if other, ok := c.Documents[id]; ok {
+				other.Embedding = entry.Embedding
+			}
+		}
+	}
+	entry.refs[doc.ID] = struct{}{}
+}
+
+// release drops doc's reference to its content, discarding the embedding
+// once the last document is gone.
+func (c *collection) release(doc *Document) {
+	key := computeHash(doc.Content)
+	entry, exists := c.Contents[key]
+	if !exists {
+		return
+	}
+	delete(entry.refs, doc.ID)
+	if len(entry.refs) == 0 {
+		delete(c.Contents, key)
+	}
+}
+
+// rebuildContents restores the pool after loading. Documents saved
+// without an embedding get it back from the pool; files written before
+// deduplication build the pool from their documents.
+func (c *collection) rebuildContents() {
+	saved := c.Contents
+	c.Contents = make(map[string]*contentEntry)
+	for _, doc := range c.Documents {
+		if len(doc.Embedding) == 0 {
+			if entry, ok := saved[computeHash(doc.Content)]; ok {
+				doc.Embedding = entry.Embedding
+			}
+		}
+		c.retain(doc)
+	}
+}
+
+// stored returns the collection as written to disk: pooled embeddings are
+// kept once in Contents instead of on every document.
+func (c *collection) stored() *collection {
+	out := *c
+	out.Documents = make(map[string]*Document, len(c.Documents))
+	for id, doc := range c.Documents {
+		if _, pooled := c.Contents[computeHash(doc.Content)]; pooled {
+			stripped := *doc
+			stripped.Embedding = nil
+			doc = &stripped
+		}
+		out.Documents[id] = doc
+	}
+	return &out
+}
+
+// SharedEmbedding returns the stored embedding for content, so callers
+// can skip embedding content the collection already holds.
+func (s *MemoryStore) SharedEmbedding(collectionName, content string) ([]float32, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	coll, exists := s.collections[collectionName]
+	if !exists {
+		return nil, false
+	}
+	entry, exists := coll.Contents[computeHash(content)]
+	if !exists {
+		return nil, false
+	}
+	return entry.Embedding, true
+}
+
+// ContentRefs returns the sorted IDs of the documents sharing the
+// embedding of content.
+func (s *MemoryStore) ContentRefs(collectionName, content string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	coll, exists := s.collections[collectionName]
+	if !exists {
+		return nil
+	}
+	entry, exists := coll.Contents[computeHash(content)]
+	if !exists {
+		return nil
+	}
+	ids := make([]string, 0, len(entry.refs))
+	for id := range entry.refs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
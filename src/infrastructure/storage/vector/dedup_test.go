@@ -0,0 +1,107 @@
+package vector
+
+import (
+	"slices"
+	"testing"
+)
+
+// insertFiles stores the same content under two file paths.
+func insertFiles(t *testing.T, store *MemoryStore, content string, paths ...string) []*Document {
+	t.Helper()
+
+	docs := make([]*Document, 0, len(paths))
+	for _, path := range paths {
+		doc := &Document{Content: content, FilePath: path, Embedding: []float32{1, 0}}
+		if err := store.Insert(doc); err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+func TestMemoryStore_IdenticalContentSharesEmbedding(t *testing.T) {
+	store, err := NewMemoryStore(t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := insertFiles(t, store, "func Add(a, b int) int { return a + b }", "a/math.go", "b/math.go")
+
+	if docs[0].ID == docs[1].ID {
+		t.Fatal("identical content in two files should be two documents")
+	}
+	stats, err := store.GetCollectionStats("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Count != 2 || stats.UniqueEmbeddings != 1 {
+		t.Errorf("expected 2 documents backed by 1 embedding, got %+v", stats)
+	}
+	refs := store.ContentRefs("default", docs[0].Content)
+	if !slices.Equal(refs, slices.Sorted(slices.Values([]string{docs[0].ID, docs[1].ID}))) {
+		t.Errorf("expected both files to reference the embedding, got %v", refs)
+	}
+
+	// A document without an embedding picks up the shared one
+	copied := &Document{Content: docs[0].Content, FilePath: "c/math.go"}
+	if err := store.Insert(copied); err != nil {
+		t.Fatal(err)
+	}
+	if len(copied.Embedding) != 2 {
+		t.Errorf("expected shared embedding to be reused, got %v", copied.Embedding)
+	}
+}
+
+func TestMemoryStore_SharedEmbeddingLivesUntilLastReference(t *testing.T) {
+	store, err := NewMemoryStore(t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := insertFiles(t, store, "shared body", "one.go", "two.go")
+
+	if err := store.Delete("default", docs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.SharedEmbedding("default", "shared body"); !ok {
+		t.Fatal("embedding should survive while another file references it")
+	}
+	if refs := store.ContentRefs("default", "shared body"); !slices.Equal(refs, []string{docs[1].ID}) {
+		t.Errorf("expected only the remaining file, got %v", refs)
+	}
+
+	if _, err := store.DeleteByFilter("default", map[string]any{"file_path": "two.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.SharedEmbedding("default", "shared body"); ok {
+		t.Error("embedding should be dropped with its last reference")
+	}
+}
+
+func TestMemoryStore_SharedEmbeddingSurvivesReload(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewMemoryStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := insertFiles(t, store, "persisted body", "x.go", "y.go")
+	if err := store.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewMemoryStore(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range docs {
+		got, err := reopened.Get("default", doc.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got.Embedding, []float32{1, 0}) {
+			t.Errorf("%s: embedding not restored, got %v", doc.FilePath, got.Embedding)
+		}
+	}
+	if refs := reopened.ContentRefs("default", "persisted body"); len(refs) != 2 {
+		t.Errorf("expected 2 references after reload, got %v", refs)
+	}
+}
@@ -0,0 +1,63 @@
+package vector
+
+import "maps"
+
+// Metadata keys for relevance feedback.
+const (
+	MetaUsefulCount    = "feedback_useful"
+	MetaNotUsefulCount = "feedback_not_useful"
+	MetaRelevanceBoost = "relevance_boost"
+)
+
+const (
+	// MaxRelevanceBoost bounds how far feedback moves a document's
+	// ranking score in either direction.
+	MaxRelevanceBoost = 0.1
+	// feedbackLearningRate is how far each rating moves the boost toward
+	// MaxRelevanceBoost (useful) or -MaxRelevanceBoost (not useful).
+	feedbackLearningRate = 0.25
+)
+
+// RelevanceBoost returns the learned ranking boost of a document, or 0
+// when it has no feedback.
+func RelevanceBoost(doc *Document) float32 {
+	boost, _ := metaFloat(doc.Metadata, MetaRelevanceBoost)
+	return float32(boost)
+}
+
+// WithFeedback returns a copy of doc with one relevance rating applied:
+// the rating is counted and the boost moves a step toward the maximum
+// boost or penalty. doc itself is left untouched, as stored documents are
+// shared with concurrent searches.
+func WithFeedback(doc *Document, useful bool) *Document {
+	rated := *doc
+	rated.Metadata = maps.Clone(doc.Metadata)
+	if rated.Metadata == nil {
+		rated.Metadata = make(map[string]any)
+	}
+
+	key, target := MetaNotUsefulCount, -MaxRelevanceBoost
+	if useful {
+		key, target = MetaUsefulCount, MaxRelevanceBoost
+	}
+	count, _ := metaFloat(rated.Metadata, key)
+	rated.Metadata[key] = count + 1
+
+	boost, _ := metaFloat(rated.Metadata, MetaRelevanceBoost)
+	rated.Metadata[MetaRelevanceBoost] = boost + feedbackLearningRate*(target-boost)
+	return &rated
+}
+
+// metaFloat reads a numeric metadata value. Values round-trip through
+// JSON as float64, but may be ints when set in memory.
+func metaFloat(meta map[string]any, key string) (float64, bool) {
+	switch v := meta[key].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
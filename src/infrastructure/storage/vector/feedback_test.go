@@ -0,0 +1,94 @@
+package vector
+
+import (
+	"slices"
+	"testing"
+)
+
+// newTiedStore returns a store with three documents equally similar to
+// the query vector {1, 0}.
+func newTiedStore(t *testing.T) *MemoryStore {
+	t.Helper()
+
+	store, err := NewMemoryStore(t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := store.Insert(&Document{ID: id, Content: id, Embedding: []float32{1, 0}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func rate(t *testing.T, store *MemoryStore, id string, useful bool) {
+	t.Helper()
+	doc, err := store.Get("default", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Insert(WithFeedback(doc, useful)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearch_FeedbackReranksEqualMatches(t *testing.T) {
+	store := newTiedStore(t)
+	rate(t, store, "c", true)
+	rate(t, store, "a", false)
+
+	results, err := store.Search([]float32{1, 0}, &SearchOptions{TopK: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := resultIDs(results); !slices.Equal(ids, []string{"c", "b", "a"}) {
+		t.Errorf("expected useful document first and unhelpful last, got %v", ids)
+	}
+	for _, r := range results {
+		if r.Score < 0.999 {
+			t.Errorf("feedback should not change the similarity score, %s got %v", r.Document.ID, r.Score)
+		}
+	}
+	if results[0].Boost <= 0 || results[2].Boost >= 0 {
+		t.Errorf("expected positive and negative boosts, got %v and %v", results[0].Boost, results[2].Boost)
+	}
+}
+
+func TestSearch_FeedbackDoesNotOverrideRelevance(t *testing.T) {
+	store := newScoredStore(t)
+	for range 20 {
+		rate(t, store, "none", true)
+	}
+
+	results, err := store.Search([]float32{1, 0}, &SearchOptions{TopK: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Document.ID != "exact" {
+		t.Errorf("feedback should only nudge ranking, got %s first", results[0].Document.ID)
+	}
+	doc, _ := store.Get("default", "none")
+	if boost := RelevanceBoost(doc); boost > MaxRelevanceBoost {
+		t.Errorf("boost %v exceeds maximum %v", boost, MaxRelevanceBoost)
+	}
+}
+
+func TestWithFeedback_CountsRatingsAndKeepsOriginal(t *testing.T) {
+	doc := &Document{ID: "d", Metadata: map[string]any{"source_id": "peer"}}
+
+	rated := WithFeedback(WithFeedback(WithFeedback(doc, true), true), false)
+
+	if len(doc.Metadata) != 1 {
+		t.Errorf("original document should be untouched, got %v", doc.Metadata)
+	}
+	if rated.Metadata[MetaUsefulCount] != 2.0 || rated.Metadata[MetaNotUsefulCount] != 1.0 {
+		t.Errorf("unexpected counts: %v", rated.Metadata)
+	}
+	if rated.Metadata["source_id"] != "peer" {
+		t.Error("existing metadata should be kept")
+	}
+	if RelevanceBoost(rated) <= 0 {
+		t.Errorf("two useful ratings over one should boost, got %v", RelevanceBoost(rated))
+	}
+}
@@ -28,6 +28,14 @@ type collection struct {
 	Documents map[string]*Document `json:"documents"`
 	CreatedAt time.Time            `json:"created_at"`
 	UpdatedAt time.Time            `json:"updated_at"`
+
+	// TTL is how long documents are kept after their last update; 0 keeps
+	// them until deleted.
+	TTL time.Duration `json:"ttl,omitempty"`
+
+	// Contents holds one embedding per distinct content, keyed by content
+	// hash and shared by every document with that content.
+	Contents map[string]*contentEntry `json:"contents,omitempty"`
 }
 
 // NewMemoryStore creates a new in-memory vector store.
@@ -64,6 +72,10 @@ func (s *MemoryStore) SetEmbeddingFunction(fn func(text string) ([]float32, erro
 
 // CreateCollection creates a new collection.
 func (s *MemoryStore) CreateCollection(name string, dimension int) error {
+	if err := ValidateCollectionName(name); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -96,9 +108,60 @@ func (s *MemoryStore) DeleteCollection(name string) error {
 	}
 
 	delete(s.collections, name)
+	// Otherwise the collection comes back on the next load
+	if err := os.Remove(filepath.Join(s.dataDir, name+".json")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove collection %s: %w", name, err)
+	}
+	return s.persist()
+}
+
+// SetCollectionTTL sets how long documents in a collection are kept after
+// their last update. 0 keeps them until deleted.
+func (s *MemoryStore) SetCollectionTTL(name string, ttl time.Duration) error {
+	if ttl < 0 {
+		return fmt.Errorf("ttl must not be negative: %s", ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	coll, exists := s.collections[name]
+	if !exists {
+		return fmt.Errorf("collection not found: %s", name)
+	}
+	coll.TTL = ttl
 	return s.persist()
 }
 
+// ExpireDocuments removes the documents whose collection TTL has passed at
+// now and returns how many were removed from each collection.
+func (s *MemoryStore) ExpireDocuments(now time.Time) (map[string]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expired := make(map[string]int)
+	for name, coll := range s.collections {
+		if coll.TTL <= 0 {
+			continue
+		}
+		cutoff := now.Add(-coll.TTL)
+		for id, doc := range coll.Documents {
+			if doc.UpdatedAt.Before(cutoff) {
+				coll.release(doc)
+				delete(coll.Documents, id)
+				expired[name]++
+			}
+		}
+		if expired[name] > 0 {
+			coll.UpdatedAt = now
+		}
+	}
+	if len(expired) == 0 {
+		return expired, nil
+	}
+	return expired, s.persist()
+}
+
 // ListCollections returns all collection names.
 func (s *MemoryStore) ListCollections() ([]string, error) {
 	s.mu.RLock()
@@ -121,20 +184,27 @@ func (s *MemoryStore) GetCollectionStats(name string) (*CollectionStats, error)
 		return nil, fmt.Errorf("collection not found: %s", name)
 	}
 
-	// Estimate size
+	// Estimate size, counting each shared embedding once
 	var sizeBytes int64
 	for _, doc := range coll.Documents {
 		sizeBytes += int64(len(doc.Content))
-		sizeBytes += int64(len(doc.Embedding) * 4) // float32 = 4 bytes
+		if _, pooled := coll.Contents[computeHash(doc.Content)]; !pooled {
+			sizeBytes += int64(len(doc.Embedding) * 4) // float32 = 4 bytes
+		}
+	}
+	for _, entry := range coll.Contents {
+		sizeBytes += int64(len(entry.Embedding) * 4)
 	}
 
 	return &CollectionStats{
-		Name:      name,
-		Count:     int64(len(coll.Documents)),
-		Dimension: coll.Dimension,
-		SizeBytes: sizeBytes,
-		CreatedAt: coll.CreatedAt,
-		UpdatedAt: coll.UpdatedAt,
+		Name:             name,
+		Count:            int64(len(coll.Documents)),
+		UniqueEmbeddings: int64(len(coll.Contents)),
+		Dimension:        coll.Dimension,
+		TTL:              coll.TTL,
+		SizeBytes:        sizeBytes,
+		CreatedAt:        coll.CreatedAt,
+		UpdatedAt:        coll.UpdatedAt,
 	}, nil
 }
 
@@ -145,11 +215,17 @@ func (s *MemoryStore) Insert(doc *Document) error {
 
 	collName := doc.Collection
 	if collName == "" {
-		collName = "default"
+		collName = DefaultCollection
 	}
 
 	coll, exists := s.collections[collName]
+	if exists && coll.Dimension > 0 && len(doc.Embedding) > 0 && len(doc.Embedding) != coll.Dimension {
+		return fmt.Errorf("%w: %s has %d, got %d", ErrDimensionMismatch, collName, coll.Dimension, len(doc.Embedding))
+	}
 	if !exists {
+		if err := ValidateCollectionName(collName); err != nil {
+			return err
+		}
 		// Auto-create collection
 		coll = &collection{
 			Name:      collName,
@@ -162,7 +238,7 @@ func (s *MemoryStore) Insert(doc *Document) error {
 
 	// Generate ID if not provided
 	if doc.ID == "" {
-		doc.ID = generateDocID(doc.Content)
+		doc.ID = generateDocID(doc.FilePath, doc.Content)
 	}
 
 	// Set timestamps
@@ -177,6 +253,10 @@ func (s *MemoryStore) Insert(doc *Document) error {
 		doc.Hash = computeHash(doc.Content)
 	}
 
+	if old, exists := coll.Documents[doc.ID]; exists {
+		coll.release(old)
+	}
+	coll.retain(doc)
 	coll.Documents[doc.ID] = doc
 	coll.UpdatedAt = now
 
@@ -211,6 +291,26 @@ func (s *MemoryStore) Get(collectionName, id string) (*Document, error) {
 	return doc, nil
 }
 
+// ListDocuments returns copies of the documents in a collection, sorted by
+// ID.
+func (s *MemoryStore) ListDocuments(collectionName string) ([]*Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	coll, exists := s.collections[collectionName]
+	if !exists {
+		return nil, fmt.Errorf("collection not found: %s", collectionName)
+	}
+
+	docs := make([]*Document, 0, len(coll.Documents))
+	for _, doc := range coll.Documents {
+		cp := *doc
+		docs = append(docs, &cp)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	return docs, nil
+}
+
 // Delete removes a document.
 func (s *MemoryStore) Delete(collectionName, id string) error {
 	s.mu.Lock()
@@ -221,7 +321,10 @@ func (s *MemoryStore) Delete(collectionName, id string) error {
 		return fmt.Errorf("collection not found: %s", collectionName)
 	}
 
-	delete(coll.Documents, id)
+	if doc, exists := coll.Documents[id]; exists {
+		coll.release(doc)
+		delete(coll.Documents, id)
+	}
 	coll.UpdatedAt = time.Now()
 
 	return nil
@@ -240,6 +343,7 @@ func (s *MemoryStore) DeleteByFilter(collectionName string, filter map[string]an
 	var deleted int64
 	for id, doc := range coll.Documents {
 		if matchesFilter(doc, filter) {
+			coll.release(doc)
 			delete(coll.Documents, id)
 			deleted++
 		}
@@ -257,6 +361,9 @@ func (s *MemoryStore) Search(embedding []float32, opts *SearchOptions) ([]*Searc
 	if opts == nil {
 		opts = DefaultSearchOptions()
 	}
+	if opts.MinScore < -1 || opts.MinScore > 1 {
+		return nil, fmt.Errorf("min score must be between -1 and 1: %v", opts.MinScore)
+	}
 
 	var results []*SearchResult
 
@@ -296,13 +403,14 @@ func (s *MemoryStore) Search(embedding []float32, opts *SearchOptions) ([]*Searc
 				Document: doc,
 				Score:    score,
 				Distance: 1 - score,
+				Boost:    RelevanceBoost(doc),
 			})
 		}
 	}
 
-	// Sort by score descending
+	// Sort by feedback-adjusted score descending
 	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
+		return results[i].Score+results[i].Boost > results[j].Score+results[j].Boost
 	})
 
 	// Limit to top-K
@@ -343,15 +451,21 @@ func (s *MemoryStore) Close() error {
 	return s.Flush()
 }
 
-// persist saves all collections to disk.
+// persist saves all collections to disk. Each file is replaced
+// atomically so a crash mid-write keeps the previous version instead of a
+// truncated file load would skip.
 func (s *MemoryStore) persist() error {
 	for name, coll := range s.collections {
 		path := filepath.Join(s.dataDir, name+".json")
-		data, err := json.MarshalIndent(coll, "", "  ")
+		data, err := json.MarshalIndent(coll.stored(), "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal collection %s: %w", name, err)
 		}
-		if err := os.WriteFile(path, data, 0600); err != nil {
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0600); err != nil {
+			return fmt.Errorf("failed to write collection %s: %w", name, err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
 			return fmt.Errorf("failed to write collection %s: %w", name, err)
 		}
 	}
@@ -385,6 +499,7 @@ func (s *MemoryStore) load() error {
 			continue
 		}
 
+		coll.rebuildContents()
 		s.collections[coll.Name] = &coll
 	}
 
@@ -432,23 +547,21 @@ func matchesFilter(doc *Document, filter map[string]any) bool {
 				return false
 			}
 		default:
-			// Check metadata
-			if doc.Metadata != nil {
-				if metaVal, exists := doc.Metadata[key]; exists {
-					if metaVal != value {
-						return false
-					}
-				} else {
-					return false
-				}
+			// Check metadata; documents without the key never match
+			if metaVal, exists := doc.Metadata[key]; !exists || metaVal != value {
+				return false
 			}
 		}
 	}
 	return true
 }
 
-// generateDocID generates a document ID from content.
-func generateDocID(content string) string {
+// generateDocID generates a document ID from a document's file and
+// content, so identical content in two files stays two documents.
+func generateDocID(filePath, content string) string {
+	if filePath != "" {
+		content = filePath + "\x00" + content
+	}
 	hash := sha256.Sum256([]byte(content))
 	return "doc-" + hex.EncodeToString(hash[:8])
 }
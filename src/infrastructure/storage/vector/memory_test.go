@@ -0,0 +1,81 @@
+package vector
+
+import (
+	"testing"
+)
+
+// newScoredStore returns a store whose documents have known cosine
+// similarities to the query vector {1, 0}.
+func newScoredStore(t *testing.T) *MemoryStore {
+	t.Helper()
+
+	store, err := NewMemoryStore(t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := []*Document{
+		{ID: "exact", Content: "exact", Embedding: []float32{1, 0}},     // 1.0
+		{ID: "close", Content: "close", Embedding: []float32{0.9, 0.1}}, // ~0.99
+		{ID: "weak", Content: "weak", Embedding: []float32{0.3, 1}},     // ~0.29
+		{ID: "none", Content: "none", Embedding: []float32{0, 1}},       // 0.0
+	}
+	if err := store.InsertBatch(docs); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func resultIDs(results []*SearchResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.Document.ID
+	}
+	return ids
+}
+
+func TestMemoryStore_SearchMinScorePrunesWeakResults(t *testing.T) {
+	store := newScoredStore(t)
+	query := []float32{1, 0}
+
+	all, err := store.Search(query, &SearchOptions{TopK: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("expected every document without a threshold, got %v", resultIDs(all))
+	}
+
+	strong, err := store.Search(query, &SearchOptions{TopK: 10, MinScore: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := resultIDs(strong)
+	if len(ids) != 2 || ids[0] != "exact" || ids[1] != "close" {
+		t.Errorf("expected only strong matches, got %v", ids)
+	}
+	for _, r := range strong {
+		if r.Score < 0.5 {
+			t.Errorf("result %s below threshold: %v", r.Document.ID, r.Score)
+		}
+	}
+}
+
+func TestMemoryStore_SearchMinScoreNoRelevantResults(t *testing.T) {
+	store := newScoredStore(t)
+
+	results, err := store.Search([]float32{-1, 0}, &SearchOptions{TopK: 10, MinScore: 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results when nothing is relevant, got %v", resultIDs(results))
+	}
+}
+
+func TestMemoryStore_SearchRejectsInvalidMinScore(t *testing.T) {
+	store := newScoredStore(t)
+
+	if _, err := store.Search([]float32{1, 0}, &SearchOptions{TopK: 10, MinScore: 1.5}); err == nil {
+		t.Error("expected error for min score above 1")
+	}
+}
@@ -23,6 +23,18 @@ type Document struct {
 	Hash       string         `json:"hash"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
+
+	// Provenance records who shared the document and what it derives from.
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// Provenance describes the origin of a shared document.
+type Provenance struct {
+	SourceID   string    `json:"source_id"`
+	SourceName string    `json:"source_name,omitempty"`
+	SharedAt   time.Time `json:"shared_at"`
+	// ParentIDs are the documents this one was derived from.
+	ParentIDs []string `json:"parent_ids,omitempty"`
 }
 
 // SearchResult represents a search result with similarity score.
@@ -30,13 +42,16 @@ type SearchResult struct {
 	Document *Document `json:"document"`
 	Score    float32   `json:"score"` // Similarity score (0-1, higher is better)
 	Distance float32   `json:"distance"`
+	// Boost is the relevance feedback adjustment; results are ranked by
+	// Score + Boost.
+	Boost float32 `json:"boost,omitempty"`
 }
 
 // SearchOptions configures vector search behavior.
 type SearchOptions struct {
 	Collection string         `json:"collection,omitempty"`
 	TopK       int            `json:"top_k"`
-	MinScore   float32        `json:"min_score,omitempty"`
+	MinScore   float32        `json:"min_score,omitempty"` // drop results below this cosine similarity (-1 to 1)
 	Filters    map[string]any `json:"filters,omitempty"`
 	FilePath   string         `json:"file_path,omitempty"`
 	Language   string         `json:"language,omitempty"`
@@ -52,12 +67,31 @@ func DefaultSearchOptions() *SearchOptions {
 
 // CollectionStats holds statistics for a collection.
 type CollectionStats struct {
-	Name      string    `json:"name"`
-	Count     int64     `json:"count"`
-	Dimension int       `json:"dimension"`
-	SizeBytes int64     `json:"size_bytes"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+	// UniqueEmbeddings is how many distinct embeddings back the documents.
+	UniqueEmbeddings int64 `json:"unique_embeddings"`
+	Dimension        int   `json:"dimension"`
+	// TTL is how long documents are kept after their last update; 0 keeps
+	// them until deleted.
+	TTL       time.Duration `json:"ttl,omitempty"`
+	SizeBytes int64         `json:"size_bytes"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// EmbeddingCache is implemented by stores that share one embedding between
+// documents with identical content.
+type EmbeddingCache interface {
+	// SharedEmbedding returns the stored embedding for content, if any.
+	SharedEmbedding(collection, content string) ([]float32, bool)
+}
+
+// DocumentLister is implemented by stores that can enumerate the documents
+// of a collection, e.g. for snapshots.
+type DocumentLister interface {
+	// ListDocuments returns copies of every document in collection.
+	ListDocuments(collection string) ([]*Document, error)
 }
 
 // Store is the interface for vector storage backends.
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var collectionsCmd = &cobra.Command{
+	Use:   "collections",
+	Short: "벡터 컬렉션 관리",
+	Long: `벡터 저장소의 컬렉션을 조회, 생성, 삭제하고 TTL을 설정합니다.
+아키텍처 노트처럼 오래 둘 문서와 파일 델타처럼 금방 낡는 문서를
+다른 컬렉션에 두고, 델타 컬렉션은 TTL로 자동 만료시킬 수 있습니다.
+
+사용 예시:
+  agent-collab collections list                        컬렉션 목록
+  agent-collab collections create arch-notes           컬렉션 생성
+  agent-collab collections create file-deltas --ttl 72h  72시간 뒤 만료되는 컬렉션 생성
+  agent-collab collections ttl file-deltas 24h         TTL 변경 (0이면 만료 안 함)
+  agent-collab collections delete arch-notes           컬렉션과 문서 삭제`,
+}
+
+var collectionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "컬렉션 목록",
+	Args:  cobra.NoArgs,
+	RunE:  runCollectionsList,
+}
+
+var collectionsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "컬렉션 생성",
+	Long: `새 벡터 컬렉션을 만듭니다. --dimension을 생략하면 임베딩 제공자의
+차원을 사용하며, 차원이 다른 임베딩은 저장되지 않습니다.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCollectionsCreate,
+}
+
+var collectionsTTLCmd = &cobra.Command{
+	Use:   "ttl <name> <duration>",
+	Short: "컬렉션 TTL 설정",
+	Long:  `마지막 수정 후 duration이 지난 문서를 자동으로 삭제합니다. 0이면 만료하지 않습니다.`,
+	Args:  cobra.ExactArgs(2),
+	RunE:  runCollectionsTTL,
+}
+
+var collectionsDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "컬렉션 삭제",
+	Long:  `컬렉션과 그 문서를 삭제합니다. default 컬렉션과 델타 컬렉션은 삭제할 수 없습니다.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCollectionsDelete,
+}
+
+var (
+	collectionsJSON      bool
+	collectionsDimension int
+	collectionsTTL       string
+)
+
+func init() {
+	rootCmd.AddCommand(collectionsCmd)
+
+	collectionsCmd.AddCommand(collectionsListCmd)
+	collectionsCmd.AddCommand(collectionsCreateCmd)
+	collectionsCmd.AddCommand(collectionsTTLCmd)
+	collectionsCmd.AddCommand(collectionsDeleteCmd)
+
+	collectionsCmd.PersistentFlags().BoolVar(&collectionsJSON, "json", false, "JSON 형식으로 출력")
+	collectionsCreateCmd.Flags().IntVar(&collectionsDimension, "dimension", 0, "임베딩 차원 (기본: 임베딩 제공자 차원)")
+	collectionsCreateCmd.Flags().StringVar(&collectionsTTL, "ttl", "", "문서 만료 시간 (예: 72h)")
+}
+
+func collectionsClient() (*daemon.Client, error) {
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return nil, fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+	return client, nil
+}
+
+func runCollectionsList(cmd *cobra.Command, args []string) error {
+	client, err := collectionsClient()
+	if err != nil {
+		return err
+	}
+
+	collections, err := client.ListCollections()
+	if err != nil {
+		return fmt.Errorf("컬렉션 조회 실패: %w", err)
+	}
+	if collectionsJSON {
+		return printCollectionsJSON(collections)
+	}
+
+	if len(collections) == 0 {
+		fmt.Println("컬렉션이 없습니다.")
+		return nil
+	}
+	fmt.Printf("%-24s %8s %6s %s\n", "이름", "문서", "차원", "TTL")
+	for _, c := range collections {
+		ttl := c.TTL
+		if ttl == "" {
+			ttl = "-"
+		}
+		fmt.Printf("%-24s %8d %6d %s\n", c.Name, c.Count, c.Dimension, ttl)
+	}
+	return nil
+}
+
+func runCollectionsCreate(cmd *cobra.Command, args []string) error {
+	client, err := collectionsClient()
+	if err != nil {
+		return err
+	}
+
+	stats, err := client.CreateCollection(daemon.CreateCollectionRequest{
+		Name:      args[0],
+		Dimension: collectionsDimension,
+		TTL:       collectionsTTL,
+	})
+	if err != nil {
+		return fmt.Errorf("컬렉션 생성 실패: %w", err)
+	}
+	return printCollection("✅ 컬렉션을 만들었습니다", stats)
+}
+
+func runCollectionsTTL(cmd *cobra.Command, args []string) error {
+	client, err := collectionsClient()
+	if err != nil {
+		return err
+	}
+
+	ttl := args[1]
+	if ttl == "0" {
+		ttl = ""
+	}
+	stats, err := client.SetCollectionTTL(args[0], ttl)
+	if err != nil {
+		return fmt.Errorf("TTL 설정 실패: %w", err)
+	}
+	return printCollection("⏱️  TTL을 설정했습니다", stats)
+}
+
+func runCollectionsDelete(cmd *cobra.Command, args []string) error {
+	client, err := collectionsClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteCollection(args[0]); err != nil {
+		return fmt.Errorf("컬렉션 삭제 실패: %w", err)
+	}
+	fmt.Printf("🗑️  컬렉션 %s을(를) 삭제했습니다\n", args[0])
+	return nil
+}
+
+func printCollection(title string, stats *daemon.CollectionStats) error {
+	if stats == nil {
+		fmt.Println(title)
+		return nil
+	}
+	if collectionsJSON {
+		return printCollectionsJSON(stats)
+	}
+
+	ttl := stats.TTL
+	if ttl == "" {
+		ttl = "없음"
+	}
+	fmt.Println(title)
+	fmt.Printf("  이름: %s\n", stats.Name)
+	fmt.Printf("  문서: %d\n", stats.Count)
+	fmt.Printf("  차원: %d\n", stats.Dimension)
+	fmt.Printf("  TTL:  %s\n", ttl)
+	return nil
+}
+
+func printCollectionsJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "설정 관리",
+	Long: `실행 중인 데몬의 설정을 관리합니다.
+
+사용 예시:
+  agent-collab config reload  config.json 변경 사항을 재시작 없이 적용`,
+}
+
+var configReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "설정 다시 읽기",
+	Long: `데몬이 config.json을 다시 읽고 재시작 없이 적용할 수 있는 설정을 바로 적용합니다.
+락을 잃지 않고 바꿀 수 있는 설정:
+  - 락 요청 속도 제한 (lock_rate_limit, lock_rate_burst)
+  - 관심사 프로필 (interest_profiles, interest_profile)
+  - 임베딩 프로바이더와 모델 (차원이 같은 경우), 동시 호출 제한
+  - 토폴로지 파라미터 (peer_latency_sla*, peer_exchange_*)
+
+그 밖의 변경은 데몬을 재시작해야 적용됩니다. 새 설정에 오류가 있으면 아무것도
+적용하지 않습니다. 데몬은 config.json 변경을 감지해 자동으로 다시 읽으며,
+Unix에서는 SIGHUP으로도 다시 읽을 수 있습니다.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigReload,
+}
+
+var configJSON bool
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+
+	configCmd.AddCommand(configReloadCmd)
+
+	configCmd.PersistentFlags().BoolVar(&configJSON, "json", false, "JSON 형식으로 출력")
+}
+
+func runConfigReload(cmd *cobra.Command, args []string) error {
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+
+	result, err := client.ReloadConfig()
+	if err != nil {
+		return fmt.Errorf("설정 다시 읽기 실패: %w", err)
+	}
+
+	if configJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if !result.Changed() {
+		fmt.Println("변경된 설정이 없습니다.")
+		return nil
+	}
+	fmt.Println("🔄 설정을 다시 읽었습니다")
+	if len(result.Applied) > 0 {
+		fmt.Printf("  적용됨:      %s\n", strings.Join(result.Applied, ", "))
+	}
+	if len(result.RestartRequired) > 0 {
+		fmt.Printf("  재시작 필요: %s\n", strings.Join(result.RestartRequired, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"agent-collab/src/domain/event"
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "파일별 락 충돌 히트맵",
+	Long: `락 충돌이 잦은 파일을 충돌 횟수 순으로 보여줍니다.
+같은 파일에서 충돌이 반복되면 작업 분담을 다시 볼 때입니다.
+
+사용 예시:
+  agent-collab conflicts               최근 24시간 기준
+  agent-collab conflicts --since 7d    최근 7일 기준
+  agent-collab conflicts --json        JSON 형식으로 출력`,
+	RunE: runConflicts,
+}
+
+var (
+	conflictsSince string
+	conflictsJSON  bool
+)
+
+func init() {
+	rootCmd.AddCommand(conflictsCmd)
+
+	conflictsCmd.Flags().StringVar(&conflictsSince, "since", "24h", "집계 기간 (예: 4h, 7d)")
+	conflictsCmd.Flags().BoolVar(&conflictsJSON, "json", false, "JSON 형식으로 출력")
+}
+
+func runConflicts(cmd *cobra.Command, args []string) error {
+	window, err := parseWindow(conflictsSince)
+	if err != nil {
+		return fmt.Errorf("잘못된 기간: %w", err)
+	}
+
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+
+	heatmap, err := client.ConflictHeatmap(time.Now().Add(-window))
+	if err != nil {
+		return fmt.Errorf("충돌 히트맵 조회 실패: %w", err)
+	}
+
+	if conflictsJSON {
+		data, err := json.MarshalIndent(heatmap, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printConflictHeatmap(os.Stdout, heatmap.Hotspots)
+	return nil
+}
+
+// parseWindow parses a duration, also accepting whole days such as "7d".
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		if _, err := fmt.Sscanf(days, "%d", &n); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
+
+// printConflictHeatmap renders hotspots with a bar scaled to the hottest file.
+func printConflictHeatmap(w io.Writer, hotspots []*event.ConflictHotspot) {
+	if len(hotspots) == 0 {
+		fmt.Fprintln(w, "락 충돌이 없습니다.")
+		return
+	}
+
+	const barWidth = 20
+	hottest := hotspots[0].Conflicts
+	for _, h := range hotspots {
+		bar := strings.Repeat("█", max(1, h.Conflicts*barWidth/hottest))
+		fmt.Fprintf(w, "%-40s %3d  %s (%s ago)\n",
+			h.FilePath, h.Conflicts, bar, time.Since(h.LastConflict).Round(time.Minute))
+		for _, pair := range h.AgentPairs {
+			fmt.Fprintf(w, "  ⚔ %s\n", pair)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"agent-collab/src/application"
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "공유 컨텍스트 코퍼스 관리",
+	Long: `벡터 저장소에 쌓인 공유 컨텍스트 문서를 임베딩, 메타데이터와 함께
+JSON Lines 파일로 내보내고 가져옵니다. 새 클러스터나 새로 합류한 팀원의
+노드에 지금까지 쌓인 프로젝트 지식을 채워 넣을 때 사용합니다.
+
+사용 예시:
+  agent-collab context export --out corpus.jsonl  코퍼스 내보내기
+  agent-collab context import corpus.jsonl        코퍼스 가져오기`,
+}
+
+var contextExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "코퍼스 내보내기",
+	Long: `공유 컨텍스트 문서를 JSON Lines 파일로 내보냅니다.
+첫 줄에는 임베딩 모델 정보가, 이후 각 줄에는 문서 하나가 기록됩니다.
+이미 있는 파일은 덮어쓰지 않습니다.`,
+	Args: cobra.NoArgs,
+	RunE: runContextExport,
+}
+
+var contextImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "코퍼스 가져오기",
+	Long: `코퍼스 파일의 문서를 실행 중인 노드에 추가합니다.
+이미 있는 문서는 건너뜁니다. 코퍼스의 임베딩 모델이나 차원이
+이 노드와 다르면 문서를 이 노드의 모델로 다시 임베딩합니다.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContextImport,
+}
+
+var (
+	contextJSON       bool
+	contextOut        string
+	contextCollection string
+	contextReembed    bool
+)
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+
+	contextCmd.AddCommand(contextExportCmd)
+	contextCmd.AddCommand(contextImportCmd)
+
+	contextCmd.PersistentFlags().BoolVar(&contextJSON, "json", false, "JSON 형식으로 출력")
+	contextExportCmd.Flags().StringVarP(&contextOut, "out", "o", "corpus.jsonl", "내보낼 파일 경로")
+	contextExportCmd.Flags().StringVar(&contextCollection, "collection", "", "이 컬렉션만 내보내기 (기본: 전체)")
+	contextImportCmd.Flags().BoolVar(&contextReembed, "reembed", false, "모델이 같아도 모든 문서를 다시 임베딩")
+}
+
+func runContextExport(cmd *cobra.Command, args []string) error {
+	// The daemon may run in another directory
+	path, err := filepath.Abs(contextOut)
+	if err != nil {
+		return err
+	}
+
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+
+	summary, err := client.ExportCorpus(path, contextCollection)
+	if err != nil {
+		return fmt.Errorf("코퍼스 내보내기 실패: %w", err)
+	}
+	return printCorpusSummary("📤 코퍼스를 내보냈습니다", summary)
+}
+
+func runContextImport(cmd *cobra.Command, args []string) error {
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+
+	summary, err := client.ImportCorpus(path, contextReembed)
+	if err != nil {
+		return fmt.Errorf("코퍼스 가져오기 실패: %w", err)
+	}
+	return printCorpusSummary("📥 코퍼스를 가져왔습니다", summary)
+}
+
+func printCorpusSummary(title string, summary *application.CorpusSummary) error {
+	if contextJSON {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Println(title)
+	fmt.Printf("  파일:      %s\n", summary.Path)
+	fmt.Printf("  생성 시각: %s\n", summary.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	if summary.Model != "" {
+		fmt.Printf("  모델:      %s\n", summary.Model)
+	}
+	fmt.Printf("  컬렉션:    %d\n", summary.Collections)
+	fmt.Printf("  문서:      %d\n", summary.Documents)
+	if summary.Skipped > 0 {
+		fmt.Printf("  건너뜀:    %d (이미 있음)\n", summary.Skipped)
+	}
+	if summary.Reembedded > 0 {
+		fmt.Printf("  재임베딩:  %d\n", summary.Reembedded)
+	}
+	return nil
+}
@@ -3,9 +3,14 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"agent-collab/src/application"
@@ -17,16 +22,33 @@ import (
 var daemonCmd = &cobra.Command{
 	Use:   "daemon",
 	Short: "데몬 관리",
-	Long:  `agent-collab 백그라운드 데몬을 관리합니다.`,
+	Long: `agent-collab 백그라운드 데몬을 관리합니다.
+
+--supervise로 시작하면 감독 프로세스가 데몬을 실행하고, 데몬이 비정상 종료되면
+설정, 락, 벡터 저장소를 복원하고 부트스트랩 피어에 다시 연결하도록 재시작합니다.
+재시작 간격은 1초부터 두 배씩 늘어 최대 1분이며, 10분 안에 --max-restarts번을
+넘게 재시작하면 포기합니다. 재시작된 데몬은 daemon.recovered 이벤트를 발행합니다.
+
+사용 예시:
+  agent-collab daemon --supervise          감독 모드로 데몬 시작
+  agent-collab daemon start --supervise -f 포그라운드에서 감독 모드로 실행`,
+	Args: cobra.NoArgs,
+	RunE: runDaemon,
 }
 
 var daemonStartCmd = &cobra.Command{
 	Use:   "start",
 	Short: "데몬 시작",
-	Long: `백그라운드에서 agent-collab 데몬을 시작합니다.
+	Long: `agent-collab 데몬을 시작합니다.
 
 데몬이 실행되면 MCP 서버와 다른 CLI 명령이
-동일한 클러스터 연결을 공유할 수 있습니다.`,
+동일한 클러스터 연결을 공유할 수 있습니다.
+
+실행 모드:
+  --daemon        백그라운드에서 실행 (기본값)
+  --foreground    터미널에 연결된 채 실행, 로그를 stdout으로 출력 (디버깅용)
+
+작업 하나만 수행하고 종료하려면 'agent-collab once'를 사용하세요.`,
 	RunE: runDaemonStart,
 }
 
@@ -50,8 +72,11 @@ var daemonRunCmd = &cobra.Command{
 }
 
 var (
-	daemonForeground bool
-	daemonStopAll    bool
+	daemonBackground  bool
+	daemonForeground  bool
+	daemonStopAll     bool
+	daemonSupervise   bool
+	daemonMaxRestarts int
 )
 
 func init() {
@@ -62,7 +87,12 @@ func init() {
 	daemonCmd.AddCommand(daemonStatusCmd)
 	daemonCmd.AddCommand(daemonRunCmd)
 
-	daemonStartCmd.Flags().BoolVarP(&daemonForeground, "foreground", "f", false, "포그라운드에서 실행")
+	daemonCmd.PersistentFlags().BoolVar(&daemonSupervise, "supervise", false, "비정상 종료 시 데몬 자동 재시작")
+	daemonCmd.PersistentFlags().IntVar(&daemonMaxRestarts, "max-restarts", daemon.DefaultSupervisorConfig().MaxRestarts, "10분 안에 허용하는 최대 재시작 횟수")
+
+	daemonStartCmd.Flags().BoolVarP(&daemonBackground, "daemon", "d", false, "백그라운드에서 실행 (기본값)")
+	daemonStartCmd.Flags().BoolVarP(&daemonForeground, "foreground", "f", false, "포그라운드에서 실행 (로그를 stdout으로 출력)")
+	daemonStartCmd.MarkFlagsMutuallyExclusive("daemon", "foreground")
 	daemonStopCmd.Flags().BoolVar(&daemonStopAll, "all", false, "모든 agent-collab 데몬 프로세스 종료")
 }
 
@@ -90,8 +120,13 @@ func startDaemonBackground() error {
 		return fmt.Errorf("실행 파일 경로를 찾을 수 없습니다: %w", err)
 	}
 
+	args := []string{"daemon", "run"}
+	if daemonSupervise {
+		args = append(args, "--supervise", "--max-restarts", strconv.Itoa(daemonMaxRestarts))
+	}
+
 	// #nosec G204 - executable is from os.Executable(), not user input
-	daemonProcess := exec.Command(executable, "daemon", "run")
+	daemonProcess := exec.Command(executable, args...)
 	daemonProcess.Stdout = nil
 	daemonProcess.Stderr = nil
 	daemonProcess.Stdin = nil
@@ -117,6 +152,13 @@ func startDaemonBackground() error {
 	return fmt.Errorf("데몬 시작 시간 초과")
 }
 
+func runDaemon(cmd *cobra.Command, args []string) error {
+	if !daemonSupervise {
+		return cmd.Help()
+	}
+	return runDaemonStart(cmd, args)
+}
+
 func runDaemonStart(cmd *cobra.Command, args []string) error {
 	client := daemon.NewClient()
 
@@ -148,7 +190,10 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 	}
 
 	if daemonForeground {
-		return runDaemonRun(cmd, args)
+		if daemonSupervise {
+			return runDaemonSupervised(os.Stdout)
+		}
+		return runDaemonForeground(os.Stdout)
 	}
 
 	return startDaemonBackground()
@@ -160,9 +205,16 @@ func runDaemonStop(cmd *cobra.Command, args []string) error {
 		return stopAllDaemons()
 	}
 
+	// 감독 프로세스가 중지를 크래시로 보고 재시작하지 않도록 먼저 종료
+	supervised := stopSupervisor()
+
 	client := daemon.NewClient()
 
 	if !client.IsRunning() {
+		if supervised {
+			fmt.Println("✓ 감독 프로세스를 중지했습니다.")
+			return nil
+		}
 		fmt.Println("데몬이 실행 중이 아닙니다.")
 		return nil
 	}
@@ -223,11 +275,93 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  %-16s: %d\n", "연결된 Agent", status.AgentCount)
 	fmt.Printf("  %-16s: %d\n", "이벤트 구독자", status.EventSubscribers)
 	fmt.Printf("  %-16s: %s\n", "Embedding 제공자", status.EmbeddingProvider)
+	if status.Observer {
+		fmt.Printf("  %-16s: %s\n", "모드", "observer")
+	}
+	if status.Role != "" {
+		fmt.Printf("  %-16s: %s\n", "역할", status.Role)
+	}
+	if pid, ok := supervisorPID(); ok {
+		fmt.Printf("  %-16s: PID %d\n", "감독 프로세스", pid)
+	}
+	if neg := status.Negotiations; neg != nil && neg.TotalSessions > 0 {
+		fmt.Printf("  %-16s: %d (에스컬레이션 %.0f%%, 평균 %s)\n", "락 협상",
+			neg.TotalSessions, neg.EscalationRate*100, neg.AvgTimeToResolution.Round(time.Millisecond))
+	}
 
 	return nil
 }
 
 func runDaemonRun(cmd *cobra.Command, args []string) error {
+	if daemonSupervise {
+		return runDaemonSupervised(os.Stderr)
+	}
+	return runDaemonForeground(os.Stderr)
+}
+
+// runDaemonSupervised runs the daemon as a child process, restarting it
+// whenever it crashes, until interrupted or the daemon is stopped.
+func runDaemonSupervised(out io.Writer) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("실행 파일 경로를 찾을 수 없습니다: %w", err)
+	}
+
+	pidFile := daemon.DefaultSupervisorPIDFile()
+	if err := os.MkdirAll(filepath.Dir(pidFile), 0700); err != nil {
+		return fmt.Errorf("감독 PID 파일 생성 실패: %w", err)
+	}
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())), 0600); err != nil {
+		return fmt.Errorf("감독 PID 파일 생성 실패: %w", err)
+	}
+	defer os.Remove(pidFile)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	config := daemon.DefaultSupervisorConfig()
+	config.MaxRestarts = daemonMaxRestarts
+	supervisor := daemon.NewSupervisor(config, func() *exec.Cmd {
+		// #nosec G204 - executable is from os.Executable(), not user input
+		cmd := exec.Command(executable, "daemon", "run")
+		cmd.Stdout = out
+		cmd.Stderr = out
+		return cmd
+	}, out)
+
+	fmt.Fprintf(out, "Supervising daemon (PID: %d)\n", os.Getpid())
+	if err := supervisor.Run(ctx); err != nil {
+		return fmt.Errorf("데몬 감독 중단: %w", err)
+	}
+	return nil
+}
+
+// supervisorPID returns the PID of a running supervisor process.
+func supervisorPID() (int, bool) {
+	data, err := os.ReadFile(daemon.DefaultSupervisorPIDFile())
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || !isProcessRunning(pid) {
+		return 0, false
+	}
+	return pid, true
+}
+
+// stopSupervisor asks a running supervisor to stop its daemon and exit,
+// and reports whether there was one.
+func stopSupervisor() bool {
+	pid, ok := supervisorPID()
+	if !ok {
+		return false
+	}
+	return signalTerm(pid) == nil
+}
+
+// runDaemonForeground runs the daemon attached to the terminal until
+// interrupted, writing lifecycle messages to out.
+func runDaemonForeground(out io.Writer) error {
 	// Create application
 	app, err := application.New(nil)
 	if err != nil {
@@ -239,7 +373,7 @@ func runDaemonRun(cmd *cobra.Command, args []string) error {
 	if err := app.LoadFromConfig(ctx); err != nil {
 		// Config doesn't exist - daemon starts without a cluster
 		// init/join commands will trigger through daemon API
-		fmt.Fprintf(os.Stderr, "No existing config found, daemon starting without cluster\n")
+		fmt.Fprintf(out, "No existing config found, daemon starting without cluster\n")
 	}
 
 	// Create and start daemon server
@@ -252,14 +386,45 @@ func runDaemonRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("데몬 시작 실패: %w", err)
 	}
 
-	fmt.Fprintf(os.Stderr, "Daemon started (PID: %d)\n", os.Getpid())
+	fmt.Fprintf(out, "Daemon started (PID: %d)\n", os.Getpid())
+
+	// Report a restart after a crash once the persisted state is restored
+	if recovery, err := daemon.RecoveryFromEnv(); err != nil {
+		fmt.Fprintf(out, "Ignoring recovery info: %v\n", err)
+	} else if recovery != nil {
+		data := server.Recovered(recovery)
+		fmt.Fprintf(out, "Daemon recovered after crash #%d (%s): %d locks, %d deltas, %d documents restored, rejoining %d bootstrap peers\n",
+			data.Restart, data.Exit, data.RestoredLocks, data.RestoredDeltas, data.Documents, data.BootstrapPeers)
+	}
 
 	// Handle shutdown signals
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	// Reload the configuration on SIGHUP (Unix only)
+	if len(reloadSignals) > 0 {
+		reloadCh := make(chan os.Signal, 1)
+		signal.Notify(reloadCh, reloadSignals...)
+		go func() {
+			for range reloadCh {
+				result, err := app.ReloadConfig()
+				if err != nil {
+					fmt.Fprintf(out, "Config reload failed: %v\n", err)
+					continue
+				}
+				fmt.Fprintf(out, "Config reloaded (applied: %v, restart required: %v)\n", result.Applied, result.RestartRequired)
+			}
+		}()
+	}
 
-	<-sigCh
-	fmt.Fprintf(os.Stderr, "Shutting down daemon...\n")
+	// Also exit when stopped through the shutdown endpoint, which a
+	// supervisor must not take for a crash
+	select {
+	case <-sigCh:
+	case <-server.Done():
+		return nil
+	}
+	fmt.Fprintf(out, "Shutting down daemon...\n")
 
 	server.Stop()
 	return nil
@@ -14,6 +14,9 @@ import (
 	"time"
 )
 
+// reloadSignals make a foreground daemon reload its configuration
+var reloadSignals = []os.Signal{syscall.SIGHUP}
+
 // setSysProcAttr sets Unix-specific process attributes for daemonization
 func setSysProcAttr(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{
@@ -14,6 +14,9 @@ import (
 	"time"
 )
 
+// reloadSignals is empty: Windows has no SIGHUP, so use 'config reload'
+var reloadSignals []os.Signal
+
 // setSysProcAttr sets Windows-specific process attributes for daemonization
 func setSysProcAttr(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{
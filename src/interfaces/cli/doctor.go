@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"agent-collab/src/application"
+	"agent-collab/src/infrastructure/embedding"
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "환경 및 의존성 상태 진단",
+	Long: `데몬과 임베딩 제공자의 상태를 진단합니다.
+
+유료 임베딩 제공자는 토큰을 소모하지 않는 방식으로 확인합니다.
+
+사용 예시:
+  agent-collab doctor              상태 진단
+  agent-collab doctor --json       JSON 형식으로 출력`,
+	RunE: runDoctor,
+}
+
+var doctorJSON bool
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "JSON 형식으로 출력")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	report := &daemon.HealthResponse{}
+
+	client := daemon.NewClient()
+	if client.IsRunning() {
+		health, err := client.HealthVerbose()
+		if err != nil {
+			return fmt.Errorf("daemon 상태 조회 실패: %w", err)
+		}
+		report = health
+	} else {
+		// 데몬 없이 로컬 설정으로 임베딩 제공자만 확인
+		health, err := localEmbeddingHealth(context.Background(), application.DefaultConfig().DataDir)
+		if err != nil {
+			return fmt.Errorf("설정 확인 실패: %w", err)
+		}
+		report.Embedding = health
+	}
+
+	if doctorJSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Println("=== agent-collab doctor ===")
+	fmt.Println()
+
+	if report.Running {
+		fmt.Println("✓ 데몬: 실행 중")
+	} else {
+		fmt.Println("✗ 데몬: 실행 중이 아님")
+	}
+
+	if e := report.Embedding; e != nil {
+		mode := "마지막 요청 기준"
+		if e.Probed {
+			mode = fmt.Sprintf("probe %dms", e.LatencyMs)
+		}
+		if e.Healthy {
+			fmt.Printf("✓ 임베딩: %s (%s) - %s\n", e.Provider, e.Model, mode)
+		} else {
+			fmt.Printf("✗ 임베딩: %s (%s) - %s\n", e.Provider, e.Model, mode)
+		}
+		if e.Message != "" {
+			fmt.Printf("  %s\n", e.Message)
+		}
+		if e.LastError != "" {
+			fmt.Printf("  마지막 오류: %s\n", e.LastError)
+		}
+	}
+
+	if len(report.Subsystems) > 0 {
+		fmt.Println()
+		fmt.Println("하위 시스템:")
+		for _, sub := range report.Subsystems {
+			mark := "✓"
+			switch sub.State {
+			case application.SubsystemSlow:
+				mark = "⚠"
+			case application.SubsystemDown:
+				mark = "✗"
+			}
+			line := fmt.Sprintf("  %s %s: %s (%dms)", mark, sub.Name, sub.State, sub.LatencyMs)
+			if !sub.LastSuccess.IsZero() {
+				line += fmt.Sprintf(", 마지막 성공 %s", sub.LastSuccess.Format("15:04:05"))
+			}
+			fmt.Println(line)
+			if sub.Message != "" {
+				fmt.Printf("    %s\n", sub.Message)
+			}
+		}
+	}
+
+	if report.Running && !report.Ready {
+		fmt.Println()
+		fmt.Println("⚠ 데몬이 요청을 처리할 준비가 되지 않았습니다")
+		for _, check := range report.Checks {
+			if !check.Ready {
+				fmt.Printf("  ✗ %s: %s\n", check.Name, check.Message)
+			}
+		}
+	}
+
+	return nil
+}
+
+// localEmbeddingHealth checks the embedding provider the daemon would use:
+// the one in the config saved under dataDir, or the default provider when
+// no cluster has been set up yet.
+func localEmbeddingHealth(ctx context.Context, dataDir string) (*embedding.HealthStatus, error) {
+	cfg, err := application.LoadConfig(dataDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		cfg = &application.Config{DataDir: dataDir}
+	} else if err != nil {
+		return nil, err
+	}
+
+	embedConfig, err := cfg.EmbeddingServiceConfig()
+	if err != nil {
+		return nil, err
+	}
+	return embedding.NewService(embedConfig).HealthCheck(ctx), nil
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"agent-collab/src/infrastructure/embedding"
+)
+
+// BDD Tests for doctor without a running daemon
+
+// Feature: 데몬 없이 임베딩 제공자 진단
+// 데몬이 실행 중이 아닐 때 doctor는 저장된 설정의 임베딩 제공자를 확인한다.
+
+func TestDoctor_GivenSavedOllamaConfig_WhenDaemonNotRunning_ThenConfiguredProviderChecked(t *testing.T) {
+	// Given: ollama 제공자를 쓰는 설정이 저장되어 있음
+	probed := false
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/tags" {
+			probed = true
+		}
+		w.Write([]byte(`{"models": []}`))
+	}))
+	defer ollama.Close()
+
+	tmpDir := t.TempDir()
+	config := `{"project_name": "test", "embedding_provider": "ollama", "embedding_base_url": "` + ollama.URL + `"}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// When: 로컬 설정으로 임베딩 제공자 확인
+	health, err := localEmbeddingHealth(context.Background(), tmpDir)
+
+	// Then: 설정된 ollama 서버가 확인됨
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if health.Provider != embedding.ProviderOllama {
+		t.Errorf("Expected the configured ollama provider, got %q", health.Provider)
+	}
+	if !probed || !health.Probed || !health.Healthy {
+		t.Errorf("Expected the ollama server to be probed and healthy, got %+v", health)
+	}
+}
+
+func TestDoctor_GivenNoConfig_WhenDaemonNotRunning_ThenDefaultProviderChecked(t *testing.T) {
+	// Given: 클러스터가 설정되지 않음
+	tmpDir := t.TempDir()
+
+	// When: 로컬 설정으로 임베딩 제공자 확인
+	health, err := localEmbeddingHealth(context.Background(), tmpDir)
+
+	// Then: 데몬과 같은 기본 제공자가 확인됨
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if health.Provider != embedding.ProviderMock {
+		t.Errorf("Expected the default mock provider, got %q", health.Provider)
+	}
+}
+
+func TestDoctor_GivenInvalidProvider_WhenDaemonNotRunning_ThenErrorReported(t *testing.T) {
+	// Given: 알 수 없는 제공자가 설정됨
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(`{"embedding_provider": "cohere"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// When/Then: 설정 오류가 보고됨
+	if _, err := localEmbeddingHealth(context.Background(), tmpDir); err == nil {
+		t.Error("Expected an unknown provider to be reported")
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"agent-collab/src/infrastructure/git"
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Git 연동",
+	Long: `시맨틱 락과 git 커밋을 연결합니다.
+
+pre-commit 훅은 커밋하는 에이전트가 변경된 모든 줄에 대한 락을 보유하고
+있는지 확인하고, 다른 에이전트가 락을 잡은 영역이나 락 없이 수정한 영역이
+있으면 커밋을 막습니다. 공유 컨텍스트에는 현재 브랜치와 커밋이 기록되어
+search_similar에서 브랜치별로 검색할 수 있습니다.
+
+사용 예시:
+  agent-collab git install-hook    현재 저장소에 pre-commit 훅 설치
+  agent-collab git pre-commit      스테이징된 변경의 락 보유 여부 확인`,
+}
+
+var gitInstallHookCmd = &cobra.Command{
+	Use:   "install-hook",
+	Short: "락 확인 pre-commit 훅 설치",
+	Long: `현재 저장소에 락 확인 pre-commit 훅을 설치합니다.
+core.hooksPath 설정을 따르며, 다른 도구가 설치한 훅은 --force 없이 덮어쓰지 않습니다.`,
+	Args: cobra.NoArgs,
+	RunE: runGitInstallHook,
+}
+
+var gitPreCommitCmd = &cobra.Command{
+	Use:   "pre-commit",
+	Short: "스테이징된 변경의 락 보유 여부 확인",
+	Long: `스테이징된 변경의 각 줄 범위가 이 노드가 보유한 락에 포함되는지 확인합니다.
+다른 에이전트의 락과 겹치거나 락 없이 수정한 범위가 있으면 실패합니다.
+데몬이 실행 중이 아니면 경고만 출력하고 통과합니다.
+검사를 건너뛰려면 git commit --no-verify를 사용하세요.`,
+	Args: cobra.NoArgs,
+	RunE: runGitPreCommit,
+}
+
+var gitHookForce bool
+
+func init() {
+	rootCmd.AddCommand(gitCmd)
+	gitCmd.AddCommand(gitInstallHookCmd)
+	gitCmd.AddCommand(gitPreCommitCmd)
+
+	gitInstallHookCmd.Flags().BoolVar(&gitHookForce, "force", false, "기존 pre-commit 훅 덮어쓰기")
+}
+
+func runGitInstallHook(cmd *cobra.Command, args []string) error {
+	repo, err := git.Open(".")
+	if err != nil {
+		return err
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("실행 파일 경로를 찾을 수 없습니다: %w", err)
+	}
+
+	path, err := repo.InstallHook(executable, gitHookForce)
+	if errors.Is(err, git.ErrHookExists) {
+		return fmt.Errorf("%w (덮어쓰려면 --force)", err)
+	}
+	if err != nil {
+		return fmt.Errorf("훅 설치 실패: %w", err)
+	}
+
+	fmt.Printf("✓ pre-commit 훅을 설치했습니다: %s\n", path)
+	return nil
+}
+
+func runGitPreCommit(cmd *cobra.Command, args []string) error {
+	repo, err := git.Open(".")
+	if err != nil {
+		return err
+	}
+	changes, err := repo.StagedChanges()
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		fmt.Fprintln(os.Stderr, "⚠️  데몬이 실행 중이 아니어서 락 확인을 건너뜁니다")
+		return nil
+	}
+	status, err := client.Status()
+	if err != nil {
+		return fmt.Errorf("상태 조회 실패: %w", err)
+	}
+	locks, err := client.ListLocks()
+	if err != nil {
+		return fmt.Errorf("락 목록 조회 실패: %w", err)
+	}
+
+	violations := git.CheckLocks(changes, locks.Locks, status.NodeID, repo.Root())
+	if len(violations) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "🔒 락 없이 변경된 영역이 있습니다 (%d)\n", len(violations))
+	for _, v := range violations {
+		fmt.Fprintf(os.Stderr, "  %s\n", v)
+	}
+	fmt.Fprintln(os.Stderr, "락을 획득한 뒤 다시 커밋하거나, git commit --no-verify로 검사를 건너뛰세요.")
+	return fmt.Errorf("커밋이 차단되었습니다")
+}
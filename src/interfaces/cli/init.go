@@ -38,6 +38,7 @@ var (
 	enableWireGuard bool
 	wgPort          int
 	wgSubnet        string
+	wgExpectedPeers int
 	initForeground  bool
 	initForce       bool
 )
@@ -52,6 +53,7 @@ func init() {
 	initCmd.Flags().BoolVarP(&enableWireGuard, "wireguard", "w", false, "WireGuard VPN 활성화 (관리자 권한 필요)")
 	initCmd.Flags().IntVar(&wgPort, "wg-port", 51820, "WireGuard 포트")
 	initCmd.Flags().StringVar(&wgSubnet, "wg-subnet", "10.100.0.0/24", "VPN 서브넷")
+	initCmd.Flags().IntVar(&wgExpectedPeers, "wg-expected-peers", 0, "예상 피어 수 (서브넷이 작으면 자동 확장)")
 
 	// Foreground flag
 	initCmd.Flags().BoolVarP(&initForeground, "foreground", "f", false, "포그라운드에서 실행 (데몬 없이)")
@@ -123,6 +125,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 		EnableWireGuard: enableWireGuard,
 		WireGuardPort:   wgPort,
 		Subnet:          wgSubnet,
+		ExpectedPeers:   wgExpectedPeers,
 	}
 
 	// 초기화
@@ -41,6 +41,7 @@ var (
 	displayName    string
 	joinForeground bool
 	joinRetry      bool
+	joinObserver   bool
 )
 
 func init() {
@@ -49,6 +50,7 @@ func init() {
 	joinCmd.Flags().StringVarP(&displayName, "name", "n", "", "표시 이름 (선택)")
 	joinCmd.Flags().BoolVarP(&joinForeground, "foreground", "f", false, "포그라운드에서 실행 (데몬 없이)")
 	joinCmd.Flags().BoolVar(&joinRetry, "retry", true, "Bootstrap peer 연결 실패 시 자동 재시도 (기본: 활성화)")
+	joinCmd.Flags().BoolVar(&joinObserver, "observer", false, "옵저버 모드로 참여 (락/컨텍스트 공유 없이 이벤트만 수신)")
 }
 
 func runJoin(cmd *cobra.Command, args []string) error {
@@ -79,6 +81,9 @@ func runJoin(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// 옵저버 모드 설정 (config에 저장되어 데몬이 다시 로드)
+		app.Config().Observer = joinObserver
+
 		// 타임아웃 컨텍스트
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 
@@ -111,6 +116,9 @@ func runJoin(cmd *cobra.Command, args []string) error {
 	fmt.Printf("✓ 프로젝트 '%s' 참여 설정 완료\n", result.ProjectName)
 	fmt.Printf("✓ 노드 ID: %s\n", result.NodeID)
 	fmt.Printf("✓ Bootstrap peer: %s\n", result.BootstrapPeer)
+	if joinObserver {
+		fmt.Println("✓ 옵저버 모드: 락 획득, 투표, 컨텍스트 공유에 참여하지 않습니다")
+	}
 
 	// WireGuard 정보 출력
 	if result.WireGuardEnabled {
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"agent-collab/src/infrastructure/storage/metrics"
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var lockCmd = &cobra.Command{
+	Use:     "lock",
+	Aliases: []string{"locks"},
+	Short:   "락 관리",
+	Long:    `클러스터의 락을 관리합니다.`,
+}
+
+var forceReleaseCmd = &cobra.Command{
+	Use:   "force-release <lock-id>",
+	Short: "멈춘 락 강제 해제 (운영자 전용)",
+	Long: `비정상 종료된 에이전트가 보유한 락을 소유자와 관계없이 해제합니다.
+
+운영자 토큰이 필요합니다. 데몬과 이 명령 모두 ` + daemon.OperatorTokenEnv + `
+환경 변수에 같은 토큰이 설정되어 있어야 합니다.
+해제 사유와 운영자는 락 기록에 남고, 원래 소유자에게 알림이 전송됩니다.
+
+사용 예시:
+  agent-collab lock force-release lock-abc123 --reason "agent crashed" --operator alice`,
+	Args: cobra.ExactArgs(1),
+	RunE: runForceRelease,
+}
+
+var lockHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "락 감사 로그 조회",
+	Long: `모든 락 획득, 해제, 충돌, 협상 결과를 기록한 감사 로그를 최신순으로 조회합니다.
+각 기록에는 보유자, 대상 영역, fencing token, 협상 결과가 포함되어
+회귀가 생겼을 때 어떤 에이전트가 언제 어느 영역을 수정했는지 추적할 수 있습니다.
+
+감사 로그는 데이터 디렉토리의 metrics/locks_<날짜>.jsonl에 저장됩니다.
+
+사용 예시:
+  agent-collab lock history --file src/auth/handler.go
+  agent-collab locks history --file src/auth/ --since 72h
+  agent-collab lock history --action negotiated --json`,
+	Args: cobra.NoArgs,
+	RunE: runLockHistory,
+}
+
+var (
+	forceReleaseReason   string
+	forceReleaseOperator string
+
+	historyFile   string
+	historyHolder string
+	historyAction string
+	historySince  time.Duration
+	historyLimit  int
+	historyJSON   bool
+)
+
+func init() {
+	rootCmd.AddCommand(lockCmd)
+	lockCmd.AddCommand(forceReleaseCmd)
+	lockCmd.AddCommand(lockHistoryCmd)
+
+	forceReleaseCmd.Flags().StringVar(&forceReleaseReason, "reason", "", "강제 해제 사유 (필수)")
+	forceReleaseCmd.Flags().StringVar(&forceReleaseOperator, "operator", os.Getenv("USER"), "운영자 식별자")
+	_ = forceReleaseCmd.MarkFlagRequired("reason")
+
+	lockHistoryCmd.Flags().StringVar(&historyFile, "file", "", "파일 경로 (/로 끝나면 디렉토리 아래 전체)")
+	lockHistoryCmd.Flags().StringVar(&historyHolder, "holder", "", "보유자 노드 ID")
+	lockHistoryCmd.Flags().StringVar(&historyAction, "action", "", "동작 (acquired, released, conflict, negotiated, ...)")
+	lockHistoryCmd.Flags().DurationVar(&historySince, "since", 0, "최근 기간만 조회 (예: 24h)")
+	lockHistoryCmd.Flags().IntVar(&historyLimit, "limit", 50, "최대 기록 수")
+	lockHistoryCmd.Flags().BoolVar(&historyJSON, "json", false, "JSON 형식으로 출력")
+}
+
+func runForceRelease(cmd *cobra.Command, args []string) error {
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+
+	client.SetOperator(forceReleaseOperator, os.Getenv(daemon.OperatorTokenEnv))
+
+	notice, err := client.ForceReleaseLock(args[0], forceReleaseReason)
+	if err != nil {
+		return fmt.Errorf("강제 해제 실패: %w", err)
+	}
+
+	fmt.Printf("✓ 락 강제 해제: %s\n", notice.LockID)
+	fmt.Printf("  소유자: %s (%s)\n", notice.HolderName, notice.HolderID)
+	fmt.Printf("  대상: %s\n", notice.Target)
+	fmt.Printf("  사유: %s\n", notice.Reason)
+	return nil
+}
+
+func runLockHistory(cmd *cobra.Command, args []string) error {
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+
+	q := metrics.LockHistoryQuery{
+		FilePath: historyFile,
+		HolderID: historyHolder,
+		Action:   historyAction,
+		Limit:    historyLimit,
+	}
+	if historySince > 0 {
+		q.Since = time.Now().Add(-historySince)
+	}
+	entries, err := client.LockHistory(q)
+	if err != nil {
+		return fmt.Errorf("락 이력 조회 실패: %w", err)
+	}
+
+	if historyJSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("기록된 락 이력이 없습니다.")
+		return nil
+	}
+	fmt.Printf("📜 락 이력 (%d)\n", len(entries))
+	for _, e := range entries {
+		fmt.Printf("  %s  %-16s %s  %s",
+			e.Timestamp.Local().Format(time.DateTime), e.Action, e.HolderName, e.Target)
+		if e.FencingToken > 0 {
+			fmt.Printf("  #%d", e.FencingToken)
+		}
+		fmt.Println()
+		if e.Resolution != "" {
+			fmt.Printf("      결과: %s", e.Resolution)
+			if e.Winner != "" {
+				fmt.Printf(" (승자: %s)", e.Winner)
+			}
+			fmt.Println()
+		}
+		if e.Reason != "" {
+			fmt.Printf("      사유: %s\n", e.Reason)
+		}
+		if e.Operator != "" {
+			fmt.Printf("      운영자: %s\n", e.Operator)
+		}
+	}
+	return nil
+}
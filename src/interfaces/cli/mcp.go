@@ -59,6 +59,7 @@ var mcpCallCmd = &cobra.Command{
   agent-collab mcp call list_locks '{}'
   agent-collab mcp call share_context '{"file_path":"main.go","content":"Added error handling"}'
   agent-collab mcp call search_similar '{"query":"authentication","limit":5}'
+  agent-collab mcp call search_symbols '{"name":"HandleLogin","type":"function"}'
   agent-collab mcp call get_events '{"limit":10}'
   agent-collab mcp call get_warnings '{}'
   agent-collab mcp call cluster_status '{}'
@@ -66,10 +67,16 @@ var mcpCallCmd = &cobra.Command{
 사용 가능한 도구:
   acquire_lock   - 코드 영역에 락 획득
   release_lock   - 락 해제
+  release_locks_by_intention - 같은 의도로 잡은 락 일괄 해제
+  renew_lock     - 락 임대 기간 연장
+  upgrade_lock   - 읽기 락을 쓰기 락으로 승격
   list_locks     - 활성 락 목록
   share_context  - 컨텍스트 공유
+  get_provenance - 컨텍스트 출처 추적
+  rate_context   - 검색 결과 유용성 평가
   embed_text     - 텍스트 임베딩 생성
   search_similar - 유사 콘텐츠 검색
+  search_symbols - 심볼 정의 및 변경 에이전트 검색
   cluster_status - 클러스터 상태
   list_agents    - 연결된 에이전트 목록
   get_events     - 최근 이벤트 조회
@@ -79,7 +86,10 @@ var mcpCallCmd = &cobra.Command{
 	RunE: runMCPCall,
 }
 
-var mcpStandalone bool
+var (
+	mcpStandalone bool
+	mcpGrants     []string
+)
 
 func init() {
 	rootCmd.AddCommand(mcpCmd)
@@ -88,6 +98,23 @@ func init() {
 	mcpCmd.AddCommand(mcpCallCmd)
 
 	mcpServeCmd.Flags().BoolVar(&mcpStandalone, "standalone", false, "데몬 없이 독립 모드로 실행")
+	mcpServeCmd.Flags().StringSliceVar(&mcpGrants, "grant", nil, "연결된 에이전트에 부여할 권한 (lock:admin, config:admin)")
+}
+
+// grantMCPPermissions grants the --grant permissions to the connected agent.
+func grantMCPPermissions(server *mcp.Server) error {
+	perms := make([]mcp.Permission, 0, len(mcpGrants))
+	for _, name := range mcpGrants {
+		perm, err := mcp.ParsePermission(name)
+		if err != nil {
+			return err
+		}
+		perms = append(perms, perm)
+	}
+	if len(perms) > 0 {
+		server.GrantPermissions(mcp.AnyAgent, perms...)
+	}
+	return nil
 }
 
 func runMCPServe(cmd *cobra.Command, args []string) error {
@@ -127,6 +154,9 @@ func runMCPWithDaemon(ctx context.Context, client *daemon.Client) error {
 
 	// Register daemon-connected tools (includes event tools that query daemon's event history)
 	mcp.RegisterDaemonTools(server, client)
+	if err := grantMCPPermissions(server); err != nil {
+		return err
+	}
 
 	// Note: We don't use RegisterEventTools here because MCP runs in stdio mode
 	// where each request is a new process, so EventHandler can't accumulate events.
@@ -148,6 +178,9 @@ func runMCPStandalone(ctx context.Context) error {
 
 	// Register tools
 	mcp.RegisterDefaultTools(server, app)
+	if err := grantMCPPermissions(server); err != nil {
+		return err
+	}
 
 	// Serve on stdio
 	return server.ServeStdio(ctx)
@@ -182,7 +215,20 @@ func runMCPCall(cmd *cobra.Command, args []string) error {
 		startLine, _ := toolArgs["start_line"].(float64)
 		endLine, _ := toolArgs["end_line"].(float64)
 		intention, _ := toolArgs["intention"].(string)
-		result, err = client.AcquireLock(filePath, int(startLine), int(endLine), intention)
+		waitForLock, _ := toolArgs["wait_for_lock"].(bool)
+		waitTimeout, _ := toolArgs["wait_timeout_seconds"].(float64)
+		priority, _ := toolArgs["priority"].(float64)
+		mode, _ := toolArgs["mode"].(string)
+		result, err = client.AcquireLockRequest("", daemon.LockRequest{
+			FilePath:           filePath,
+			StartLine:          int(startLine),
+			EndLine:            int(endLine),
+			Intention:          intention,
+			Mode:               mode,
+			WaitForLock:        waitForLock,
+			WaitTimeoutSeconds: int(waitTimeout),
+			Priority:           int(priority),
+		})
 
 	case "release_lock":
 		lockID, _ := toolArgs["lock_id"].(string)
@@ -191,6 +237,25 @@ func runMCPCall(cmd *cobra.Command, args []string) error {
 			result = map[string]any{"success": true, "message": "Lock released"}
 		}
 
+	case "upgrade_lock":
+		lockID, _ := toolArgs["lock_id"].(string)
+		result, err = client.UpgradeLock(lockID)
+
+	case "release_locks_by_intention":
+		intention, _ := toolArgs["intention"].(string)
+		prefix, _ := toolArgs["prefix"].(bool)
+		result, err = client.ReleaseLocksByIntention(intention, prefix)
+
+	case "renew_lock":
+		lockID, _ := toolArgs["lock_id"].(string)
+		fencingToken, _ := toolArgs["fencing_token"].(float64)
+		ttlSeconds, _ := toolArgs["ttl_seconds"].(float64)
+		result, err = client.RenewLease(daemon.RenewLockRequest{
+			LockID:       lockID,
+			FencingToken: uint64(fencingToken),
+			TTLSeconds:   int(ttlSeconds),
+		})
+
 	case "list_locks":
 		result, err = client.ListLocks()
 
@@ -198,7 +263,24 @@ func runMCPCall(cmd *cobra.Command, args []string) error {
 		filePath, _ := toolArgs["file_path"].(string)
 		content, _ := toolArgs["content"].(string)
 		metadata, _ := toolArgs["metadata"].(map[string]any)
-		result, err = client.ShareContext(filePath, content, metadata)
+		var parentIDs []string
+		if ids, ok := toolArgs["parent_ids"].([]any); ok {
+			for _, id := range ids {
+				if s, ok := id.(string); ok {
+					parentIDs = append(parentIDs, s)
+				}
+			}
+		}
+		result, err = client.ShareDerivedContext(filePath, content, metadata, parentIDs)
+
+	case "get_provenance":
+		documentID, _ := toolArgs["document_id"].(string)
+		result, err = client.GetProvenance(documentID)
+
+	case "rate_context":
+		documentID, _ := toolArgs["document_id"].(string)
+		useful, _ := toolArgs["useful"].(bool)
+		result, err = client.RateContext(documentID, useful)
 
 	case "embed_text":
 		text, _ := toolArgs["text"].(string)
@@ -210,7 +292,24 @@ func runMCPCall(cmd *cobra.Command, args []string) error {
 		if l, ok := toolArgs["limit"].(float64); ok {
 			limit = int(l)
 		}
-		result, err = client.Search(query, limit)
+		minScore, _ := toolArgs["min_score"].(float64)
+		branch, _ := toolArgs["branch"].(string)
+		result, err = client.SearchWith(daemon.SearchRequest{
+			Query:    query,
+			Limit:    limit,
+			MinScore: float32(minScore),
+			Branch:   branch,
+		})
+
+	case "search_symbols":
+		q := application.SymbolQuery{}
+		q.Name, _ = toolArgs["name"].(string)
+		q.Type, _ = toolArgs["type"].(string)
+		q.FilePath, _ = toolArgs["file_path"].(string)
+		if l, ok := toolArgs["limit"].(float64); ok {
+			q.Limit = int(l)
+		}
+		result, err = client.SearchSymbols(q)
 
 	case "cluster_status":
 		result, err = client.Status()
@@ -224,8 +323,9 @@ func runMCPCall(cmd *cobra.Command, args []string) error {
 			limit = int(l)
 		}
 		eventType, _ := toolArgs["type"].(string)
+		agent, _ := toolArgs["agent"].(string)
 		includeAll, _ := toolArgs["include_all"].(bool)
-		result, err = client.ListEvents(limit, eventType, includeAll)
+		result, err = client.ListEventsByAgent(limit, eventType, agent, includeAll)
 
 	case "get_warnings":
 		// Get recent events that might be warnings (includeAll=true to see all cluster events)
@@ -306,10 +406,16 @@ func runMCPInfo(cmd *cobra.Command, args []string) error {
 	fmt.Println("Available Tools:")
 	fmt.Println("  - acquire_lock    : Acquire a semantic lock on a code region")
 	fmt.Println("  - release_lock    : Release a previously acquired lock")
+	fmt.Println("  - release_locks_by_intention : Release all your locks acquired under an intention")
+	fmt.Println("  - renew_lock      : Extend the lease of a held lock")
+	fmt.Println("  - upgrade_lock    : Upgrade a held read lock to a write lock")
 	fmt.Println("  - list_locks      : List all active locks in the cluster")
 	fmt.Println("  - share_context   : Share context with other agents")
+	fmt.Println("  - get_provenance  : Trace who shared a context and what it was derived from")
+	fmt.Println("  - rate_context    : Mark a search result as useful or not to tune ranking")
 	fmt.Println("  - embed_text      : Generate embeddings for text")
 	fmt.Println("  - search_similar  : Search for similar content")
+	fmt.Println("  - search_symbols  : Find where a symbol is defined and who changed it")
 	fmt.Println("  - cluster_status  : Get cluster status")
 	fmt.Println("  - list_agents     : List connected agents")
 	fmt.Println()
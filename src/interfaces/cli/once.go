@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"agent-collab/src/application"
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var onceCmd = &cobra.Command{
+	Use:   "once",
+	Short: "일회성 실행 (시작 → 작업 → 종료)",
+	Long: `데몬 없이 앱을 시작하여 작업 하나를 수행한 뒤 깔끔하게 종료합니다.
+
+스크립트나 CI처럼 상주 프로세스를 남기지 않아야 할 때 사용합니다.
+데몬이 이미 실행 중이면 같은 노드 키를 사용하므로 실행할 수 없습니다.
+
+사용 예시:
+  agent-collab once status             피어 연결 후 상태 출력
+  agent-collab once locks --wait 10s   10초간 동기화 후 락 목록 출력`,
+}
+
+var onceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "클러스터 상태 확인 후 종료",
+	RunE:  runOnceStatus,
+}
+
+var onceLocksCmd = &cobra.Command{
+	Use:   "locks",
+	Short: "활성 락 목록 출력 후 종료",
+	RunE:  runOnceLocks,
+}
+
+var (
+	onceWait       time.Duration
+	onceStatusJSON bool
+)
+
+func init() {
+	rootCmd.AddCommand(onceCmd)
+	onceCmd.AddCommand(onceStatusCmd)
+	onceCmd.AddCommand(onceLocksCmd)
+
+	onceCmd.PersistentFlags().DurationVar(&onceWait, "wait", 3*time.Second, "작업 전 피어 연결을 기다리는 시간")
+	onceStatusCmd.Flags().BoolVar(&onceStatusJSON, "json", false, "JSON 형식으로 출력")
+}
+
+// runOnce runs action against a freshly started app and stops it afterwards.
+func runOnce(cmd *cobra.Command, action func(ctx context.Context, app *application.App) error) error {
+	if daemon.NewClient().IsRunning() {
+		return fmt.Errorf("데몬이 실행 중입니다. 일회성 실행 대신 일반 명령을 사용하거나 'agent-collab daemon stop'을 실행하세요")
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return application.RunOnce(ctx, nil, func(ctx context.Context, app *application.App) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(onceWait):
+		}
+		return action(ctx, app)
+	})
+}
+
+func runOnceStatus(cmd *cobra.Command, args []string) error {
+	return runOnce(cmd, func(ctx context.Context, app *application.App) error {
+		return printEnhancedStatus(&EnhancedStatus{Status: app.GetStatus()}, onceStatusJSON)
+	})
+}
+
+func runOnceLocks(cmd *cobra.Command, args []string) error {
+	return runOnce(cmd, func(ctx context.Context, app *application.App) error {
+		locks := app.LockService().ListLocks()
+		if len(locks) == 0 {
+			fmt.Println("활성 락 없음")
+			return nil
+		}
+
+		fmt.Printf("🔒 활성 락 (%d개)\n", len(locks))
+		for _, l := range locks {
+			fmt.Printf("  %s  %s  %s (TTL %s)\n", l.ID, l.HolderName, l.Target.String(), l.TTLRemaining().Round(time.Second))
+			if l.Intention != "" {
+				fmt.Printf("      %s\n", l.Intention)
+			}
+		}
+		return nil
+	})
+}
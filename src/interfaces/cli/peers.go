@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var peersCmd = &cobra.Command{
+	Use:   "peers",
+	Short: "피어 관리",
+	Long: `클러스터 피어를 관리합니다.
+
+사용 예시:
+  agent-collab peers bans                                  차단된 피어 목록
+  agent-collab peers ban 12D3KooW... --reason "flooding"   피어 차단
+  agent-collab peers unban 12D3KooW...                     차단 해제`,
+}
+
+var peersBanCmd = &cobra.Command{
+	Use:   "ban <peer-id>",
+	Short: "피어 차단 (운영자 전용)",
+	Long: `피어를 차단합니다. 차단된 피어와의 연결은 즉시 끊기고, 다시 연결할 수 없으며
+그 피어의 메시지는 버려집니다. 차단 목록은 데몬을 재시작해도 유지됩니다.
+
+--duration을 지정하지 않으면 해제할 때까지 차단합니다.
+잘못된 형식의 메시지를 보내거나 메시지 속도 제한을 계속 넘기는 피어는
+자동으로 차단됩니다 (auto_ban_threshold, auto_ban_duration).
+
+운영자 토큰이 필요합니다. 데몬과 이 명령 모두 ` + daemon.OperatorTokenEnv + `
+환경 변수에 같은 토큰이 설정되어 있어야 합니다.
+
+사용 예시:
+  agent-collab peers ban 12D3KooW... --reason "runaway agent" --duration 24h`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPeersBan,
+}
+
+var peersUnbanCmd = &cobra.Command{
+	Use:   "unban <peer-id>",
+	Short: "피어 차단 해제 (운영자 전용)",
+	Long: `피어의 차단을 해제합니다. 자동 차단도 해제할 수 있습니다.
+
+운영자 토큰이 필요합니다 (` + daemon.OperatorTokenEnv + `).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPeersUnban,
+}
+
+var peersBansCmd = &cobra.Command{
+	Use:   "bans",
+	Short: "차단된 피어 목록",
+	Long:  `현재 차단된 피어와 차단 사유, 만료 시각을 표시합니다.`,
+	Args:  cobra.NoArgs,
+	RunE:  runPeersBans,
+}
+
+var (
+	banReason   string
+	banDuration time.Duration
+	banOperator string
+	peersJSON   bool
+)
+
+func init() {
+	rootCmd.AddCommand(peersCmd)
+	peersCmd.AddCommand(peersBanCmd)
+	peersCmd.AddCommand(peersUnbanCmd)
+	peersCmd.AddCommand(peersBansCmd)
+
+	peersBanCmd.Flags().StringVar(&banReason, "reason", "", "차단 사유 (필수)")
+	peersBanCmd.Flags().DurationVar(&banDuration, "duration", 0, "차단 기간 (예: 24h, 0이면 해제할 때까지)")
+	_ = peersBanCmd.MarkFlagRequired("reason")
+	for _, cmd := range []*cobra.Command{peersBanCmd, peersUnbanCmd} {
+		cmd.Flags().StringVar(&banOperator, "operator", os.Getenv("USER"), "운영자 식별자")
+	}
+	peersBansCmd.Flags().BoolVar(&peersJSON, "json", false, "JSON 형식으로 출력")
+}
+
+// peersClient returns a client for a running daemon.
+func peersClient() (*daemon.Client, error) {
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return nil, fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+	client.SetOperator(banOperator, os.Getenv(daemon.OperatorTokenEnv))
+	return client, nil
+}
+
+func runPeersBan(cmd *cobra.Command, args []string) error {
+	if banDuration < 0 {
+		return fmt.Errorf("--duration은 음수일 수 없습니다")
+	}
+	client, err := peersClient()
+	if err != nil {
+		return err
+	}
+
+	ban, err := client.BanPeer(args[0], banReason, banDuration)
+	if err != nil {
+		return fmt.Errorf("피어 차단 실패: %w", err)
+	}
+
+	fmt.Printf("⛔ 피어 차단: %s\n", ban.PeerID)
+	fmt.Printf("  사유: %s\n", ban.Reason)
+	fmt.Printf("  만료: %s\n", banExpiry(ban.ExpiresAt))
+	return nil
+}
+
+func runPeersUnban(cmd *cobra.Command, args []string) error {
+	client, err := peersClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.UnbanPeer(args[0]); err != nil {
+		return fmt.Errorf("차단 해제 실패: %w", err)
+	}
+	fmt.Printf("✓ 차단 해제: %s\n", args[0])
+	return nil
+}
+
+func runPeersBans(cmd *cobra.Command, args []string) error {
+	client, err := peersClient()
+	if err != nil {
+		return err
+	}
+
+	bans, err := client.ListBans()
+	if err != nil {
+		return fmt.Errorf("차단 목록 조회 실패: %w", err)
+	}
+
+	if peersJSON {
+		data, err := json.MarshalIndent(bans, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(bans) == 0 {
+		fmt.Println("차단된 피어가 없습니다.")
+		return nil
+	}
+	fmt.Printf("⛔ 차단된 피어 (%d)\n", len(bans))
+	for _, ban := range bans {
+		by := ban.Operator
+		if ban.Auto {
+			by = "자동"
+		}
+		fmt.Printf("  %s\n", ban.PeerID)
+		fmt.Printf("    사유: %s (%s)  차단: %s  만료: %s\n",
+			ban.Reason, by, ban.BannedAt.Local().Format(time.DateTime), banExpiry(ban.ExpiresAt))
+	}
+	return nil
+}
+
+// banExpiry formats when a ban lifts.
+func banExpiry(expiresAt time.Time) string {
+	if expiresAt.IsZero() {
+		return "해제할 때까지"
+	}
+	return expiresAt.Local().Format(time.DateTime)
+}
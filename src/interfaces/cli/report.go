@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "클러스터 리포트 내보내기 (장애 분석용)",
+	Long: `데몬에서 클러스터 상태를 모아 하나의 번들로 저장합니다.
+
+번들에는 상태, 피어(품질/지역성 포함), 활성 락, 최근 이벤트,
+토큰 사용량, 토폴로지가 JSON으로 담기고 토폴로지는 DOT 파일로도 저장됩니다.
+토큰, API 키 등 민감한 값은 저장 전에 가려집니다.
+
+사용 예시:
+  agent-collab report --output ./reports
+  dot -Tpng ./reports/agent-collab-report-*/topology.dot -o topology.png`,
+	RunE: runReport,
+}
+
+var (
+	reportOutput string
+	reportEvents int
+)
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", ".", "리포트를 저장할 디렉토리")
+	reportCmd.Flags().IntVar(&reportEvents, "events", 200, "포함할 최근 이벤트 수")
+}
+
+// clusterReport is the bundle written by the report command. Sections that
+// could not be collected are left empty and explained in Errors.
+type clusterReport struct {
+	GeneratedAt time.Time                  `json:"generated_at"`
+	Status      *daemon.StatusResponse     `json:"status"`
+	Peers       []daemon.PeerInfo          `json:"peers"`
+	Locks       []*lock.SemanticLock       `json:"locks"`
+	Events      []daemon.Event             `json:"events"`
+	TokenUsage  *daemon.TokenUsageResponse `json:"token_usage"`
+	Topology    *daemon.TopologyResponse   `json:"topology"`
+	Errors      map[string]string          `json:"errors,omitempty"`
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+
+	report := collectReport(client, reportEvents)
+	dir, err := writeReport(reportOutput, report)
+	if err != nil {
+		return fmt.Errorf("리포트 저장 실패: %w", err)
+	}
+
+	fmt.Printf("✓ 클러스터 리포트 저장: %s\n", dir)
+	for section, msg := range report.Errors {
+		fmt.Printf("  ⚠ %s 수집 실패: %s\n", section, msg)
+	}
+	return nil
+}
+
+// collectReport gathers every report section from the daemon. A failing
+// section does not abort the report; during an incident partial data is
+// still useful.
+func collectReport(client *daemon.Client, eventLimit int) *clusterReport {
+	report := &clusterReport{
+		GeneratedAt: time.Now().UTC(),
+		Errors:      make(map[string]string),
+	}
+	fail := func(section string, err error) {
+		report.Errors[section] = err.Error()
+	}
+
+	if status, err := client.Status(); err != nil {
+		fail("status", err)
+	} else {
+		report.Status = status
+	}
+
+	if peers, err := client.ListPeers(); err != nil {
+		fail("peers", err)
+	} else {
+		report.Peers = peers.Peers
+	}
+
+	if locks, err := client.ListLocks(); err != nil {
+		fail("locks", err)
+	} else {
+		report.Locks = locks.Locks
+	}
+
+	if events, err := client.ListEvents(eventLimit, "", true); err != nil {
+		fail("events", err)
+	} else {
+		report.Events = events.Events
+	}
+
+	if usage, err := client.TokenUsage(); err != nil {
+		fail("token_usage", err)
+	} else {
+		report.TokenUsage = usage
+	}
+
+	if topology, err := client.Topology(); err != nil {
+		fail("topology", err)
+	} else {
+		report.Topology = topology
+	}
+
+	return report
+}
+
+// writeReport writes report.json and topology.dot into a new timestamped
+// directory under outputDir and returns that directory.
+func writeReport(outputDir string, report *clusterReport) (string, error) {
+	dir := filepath.Join(outputDir, "agent-collab-report-"+report.GeneratedAt.Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+
+	data, err := marshalRedacted(report)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "report.json"), data, 0o600); err != nil {
+		return "", err
+	}
+
+	if report.Topology != nil {
+		dot := redactString(topologyDOT(report.Topology), secretValues())
+		if err := os.WriteFile(filepath.Join(dir, "topology.dot"), []byte(dot), 0o600); err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// topologyDOT renders the topology graph in Graphviz DOT format.
+func topologyDOT(t *daemon.TopologyResponse) string {
+	var b strings.Builder
+	b.WriteString("digraph cluster {\n")
+	b.WriteString("  node [shape=ellipse];\n")
+
+	for _, n := range t.Nodes {
+		label := shortPeerID(n.ID) + "\\n" + n.Role
+		if n.Region != "" {
+			label += "\\n" + n.Region
+		}
+		attrs := fmt.Sprintf("label=%q", label)
+		if n.Self {
+			attrs += ", shape=doublecircle"
+		}
+		if n.Role == "super" {
+			attrs += ", style=bold"
+		}
+		fmt.Fprintf(&b, "  %q [%s];\n", n.ID, attrs)
+	}
+
+	for _, e := range t.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=\"%dms\"];\n", e.From, e.To, e.Latency)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// shortPeerID shortens a peer ID for graph labels.
+func shortPeerID(id string) string {
+	if len(id) <= 12 {
+		return id
+	}
+	return id[:6] + "…" + id[len(id)-6:]
+}
+
+const redacted = "[REDACTED]"
+
+// secretKeyParts are substrings of JSON keys whose values are always redacted.
+var secretKeyParts = []string{"secret", "password", "passwd", "api_key", "apikey", "private_key", "privkey", "credential", "authorization"}
+
+// secretEnvVars hold secrets that must never appear in a report verbatim.
+var secretEnvVars = []string{daemon.OperatorTokenEnv, "OPENAI_API_KEY", "ANTHROPIC_API_KEY", "GOOGLE_API_KEY"}
+
+// isSecretKey reports whether a JSON key names a secret. Token counters
+// such as tokens_today are not secrets, but invite_token or token are.
+func isSecretKey(key string) bool {
+	k := strings.ToLower(key)
+	if k == "token" || strings.HasSuffix(k, "_token") || strings.HasSuffix(k, "token_hash") {
+		return true
+	}
+	for _, part := range secretKeyParts {
+		if strings.Contains(k, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretValues returns the secret values currently set in the environment.
+func secretValues() []string {
+	var values []string
+	for _, name := range secretEnvVars {
+		if v := os.Getenv(name); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// marshalRedacted encodes v as indented JSON with secrets redacted.
+func marshalRedacted(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(redactValue(generic, secretValues()), "", "  ")
+}
+
+// redactValue walks a decoded JSON value and redacts secret fields and any
+// string containing a known secret value.
+func redactValue(v any, secrets []string) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if isSecretKey(k) {
+				if child != nil && child != "" {
+					val[k] = redacted
+				}
+				continue
+			}
+			val[k] = redactValue(child, secrets)
+		}
+		return val
+	case []any:
+		for i, child := range val {
+			val[i] = redactValue(child, secrets)
+		}
+		return val
+	case string:
+		return redactString(val, secrets)
+	default:
+		return v
+	}
+}
+
+// redactString replaces every occurrence of a secret value in s.
+func redactString(s string, secrets []string) string {
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/network/libp2p"
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// BDD-style tests for report command
+// Feature: Cluster Report
+// As an operator
+// I want to export a single bundle of cluster state
+// So that I can review incidents after the fact
+
+func seedReportServer(t *testing.T, server *mockStatusServer) {
+	t.Helper()
+
+	// Quality/locality carry real peer IDs, which must decode on the client
+	_, pub, err := crypto.GenerateEd25519Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peerID, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.SetHandler("/status", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(daemon.StatusResponse{
+			Running:     true,
+			ProjectName: "test-project",
+			NodeID:      "12D3KooWSelfNode",
+			PeerCount:   1,
+			LockCount:   1,
+		})
+	})
+	server.SetHandler("/peers/list", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(daemon.ListPeersResponse{
+			Peers: []daemon.PeerInfo{{
+				ID:        "12D3KooWPeer1",
+				Latency:   12,
+				Connected: true,
+				Quality:   &libp2p.PeerQuality{PeerID: peerID, RTT: 12 * time.Millisecond, Score: 0.9},
+				Locality:  &libp2p.PeerLocality{PeerID: peerID, Region: "ap-northeast-2", RTT: 12 * time.Millisecond},
+			}},
+		})
+	})
+	server.SetHandler("/lock/list", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(daemon.ListLocksResponse{
+			Locks: []*lock.SemanticLock{{ID: "lock-1", HolderName: "agent-a", Intention: "refactor"}},
+		})
+	})
+	server.SetHandler("/events/list", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(daemon.ListEventsResponse{
+			Events: []daemon.Event{
+				daemon.NewEvent(daemon.EventLockAcquired, map[string]string{"lock_id": "lock-1"}),
+				daemon.NewEvent(daemon.EventType("cluster.joined"), map[string]string{
+					"invite_token": "join-secret-value",
+					"note":         "operator used op-secret-123",
+				}),
+			},
+			Count: 2,
+		})
+	})
+	server.SetHandler("/tokens/usage", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(daemon.TokenUsageResponse{TokensToday: 2500, DailyLimit: 200000})
+	})
+	server.SetHandler("/topology", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(daemon.TopologyResponse{
+			Nodes: []daemon.TopologyNode{
+				{ID: "12D3KooWSelfNode", Role: "super", Self: true},
+				{ID: "12D3KooWPeer1", Role: "leaf", Region: "ap-northeast-2"},
+			},
+			Edges: []daemon.TopologyEdge{{From: "12D3KooWSelfNode", To: "12D3KooWPeer1", Latency: 12}},
+		})
+	})
+}
+
+// Scenario: Export a report from a seeded daemon
+func TestFeature_Report_Scenario_ContainsAllSections(t *testing.T) {
+	t.Run("Given a daemon with peers, locks, events, usage and topology", func(t *testing.T) {
+		t.Setenv(daemon.OperatorTokenEnv, "op-secret-123")
+
+		server := newMockStatusServer(t)
+		defer server.Close()
+		seedReportServer(t, server)
+
+		t.Run("When I export a report", func(t *testing.T) {
+			report := collectReport(server.Client(), 50)
+			dir, err := writeReport(t.TempDir(), report)
+			if err != nil {
+				t.Fatalf("writeReport failed: %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "report.json"))
+			if err != nil {
+				t.Fatalf("report.json missing: %v", err)
+			}
+
+			var sections map[string]json.RawMessage
+			if err := json.Unmarshal(data, &sections); err != nil {
+				t.Fatalf("report.json is not valid JSON: %v", err)
+			}
+
+			t.Run("Then every section should be present", func(t *testing.T) {
+				for _, section := range []string{"generated_at", "status", "peers", "locks", "events", "token_usage", "topology"} {
+					raw, ok := sections[section]
+					if !ok || string(raw) == "null" {
+						t.Errorf("expected section %q in report", section)
+					}
+				}
+				if _, ok := sections["errors"]; ok {
+					t.Errorf("expected no collection errors, got %s", sections["errors"])
+				}
+			})
+
+			t.Run("And peers should include quality and locality", func(t *testing.T) {
+				if !strings.Contains(string(sections["peers"]), `"score": 0.9`) {
+					t.Error("expected peer quality score in report")
+				}
+				if !strings.Contains(string(sections["peers"]), "ap-northeast-2") {
+					t.Error("expected peer region in report")
+				}
+			})
+
+			t.Run("And secrets should be redacted", func(t *testing.T) {
+				if strings.Contains(string(data), "join-secret-value") {
+					t.Error("invite token leaked into report")
+				}
+				if strings.Contains(string(data), "op-secret-123") {
+					t.Error("operator token leaked into report")
+				}
+				if !strings.Contains(string(data), redacted) {
+					t.Error("expected redaction marker in report")
+				}
+				if !strings.Contains(string(sections["token_usage"]), "2500") {
+					t.Error("token usage counters must not be redacted")
+				}
+			})
+
+			t.Run("And the topology should be written as DOT", func(t *testing.T) {
+				dot, err := os.ReadFile(filepath.Join(dir, "topology.dot"))
+				if err != nil {
+					t.Fatalf("topology.dot missing: %v", err)
+				}
+				if !strings.HasPrefix(string(dot), "digraph cluster {") {
+					t.Errorf("unexpected DOT header: %q", string(dot))
+				}
+				if !strings.Contains(string(dot), `"12D3KooWSelfNode" -> "12D3KooWPeer1" [label="12ms"]`) {
+					t.Errorf("expected edge in DOT output, got:\n%s", dot)
+				}
+			})
+		})
+	})
+}
+
+// Scenario: A failing section does not abort the report
+func TestFeature_Report_Scenario_PartialFailure(t *testing.T) {
+	t.Run("Given a daemon without a topology endpoint", func(t *testing.T) {
+		server := newMockStatusServer(t)
+		defer server.Close()
+		seedReportServer(t, server)
+		server.SetHandler("/topology", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(daemon.TopologyResponse{Error: "node not initialized"})
+		})
+
+		t.Run("When I export a report", func(t *testing.T) {
+			report := collectReport(server.Client(), 50)
+			dir, err := writeReport(t.TempDir(), report)
+			if err != nil {
+				t.Fatalf("writeReport failed: %v", err)
+			}
+
+			t.Run("Then the other sections should still be collected", func(t *testing.T) {
+				if report.Status == nil || len(report.Locks) != 1 || len(report.Events) != 2 {
+					t.Errorf("expected remaining sections, got %+v", report)
+				}
+			})
+
+			t.Run("And the failure should be recorded", func(t *testing.T) {
+				if report.Errors["topology"] == "" {
+					t.Error("expected topology error to be recorded")
+				}
+				if _, err := os.Stat(filepath.Join(dir, "topology.dot")); !os.IsNotExist(err) {
+					t.Error("expected no DOT file without topology data")
+				}
+			})
+		})
+	})
+}
@@ -41,7 +41,7 @@ TUI 단축키:
   i           Init (새 클러스터)
   J           Join (클러스터 참여)
   L           Leave (클러스터 탈퇴)
-  1-5         탭 전환
+  1-7         탭 전환
   ↑↓/jk       항목 선택
   q           종료`,
 	RunE: runRoot,
@@ -103,7 +103,7 @@ func init() {
 
 	// TUI 옵션 (루트 명령에도 추가)
 	rootCmd.Flags().StringVarP(&startTab, "tab", "t", "cluster",
-		"시작 탭 (cluster|context|locks|tokens|peers)")
+		"시작 탭 (cluster|context|locks|tokens|peers|negotiations|events)")
 
 	// viper 바인딩
 	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
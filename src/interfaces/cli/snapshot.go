@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"agent-collab/src/application"
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "클러스터 상태 스냅샷",
+	Long: `락, 동기화 델타, 관심사 등록, 벡터 저장소 내용을 하나의 아카이브로
+저장하고 복원합니다. 장애 복구나 부트스트랩 노드를 새 장비로 옮길 때 사용합니다.
+
+사용 예시:
+  agent-collab snapshot create                    데이터 디렉토리에 스냅샷 저장
+  agent-collab snapshot restore snapshot.json.gz  스냅샷을 실행 중인 노드에 복원`,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "스냅샷 생성",
+	Long:  `데이터 디렉토리의 snapshots 폴더에 현재 클러스터 상태를 저장합니다.`,
+	Args:  cobra.NoArgs,
+	RunE:  runSnapshotCreate,
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "스냅샷 복원",
+	Long: `스냅샷 아카이브를 실행 중인 노드에 병합합니다.
+이미 있는 락, 관심사, 문서는 그대로 두고 없는 것만 추가하며,
+만료된 락과 관심사는 복원하지 않습니다.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSnapshotRestore,
+}
+
+var snapshotJSON bool
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+
+	snapshotCmd.PersistentFlags().BoolVar(&snapshotJSON, "json", false, "JSON 형식으로 출력")
+}
+
+func runSnapshotCreate(cmd *cobra.Command, args []string) error {
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+
+	summary, err := client.CreateSnapshot()
+	if err != nil {
+		return fmt.Errorf("스냅샷 생성 실패: %w", err)
+	}
+	return printSnapshotSummary("📦 스냅샷을 저장했습니다", summary)
+}
+
+func runSnapshotRestore(cmd *cobra.Command, args []string) error {
+	// The daemon may run in another directory
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+
+	summary, err := client.RestoreSnapshot(path)
+	if err != nil {
+		return fmt.Errorf("스냅샷 복원 실패: %w", err)
+	}
+	return printSnapshotSummary("♻️  스냅샷을 복원했습니다", summary)
+}
+
+func printSnapshotSummary(title string, summary *application.SnapshotSummary) error {
+	if snapshotJSON {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Println(title)
+	fmt.Printf("  파일:      %s\n", summary.Path)
+	fmt.Printf("  생성 시각: %s\n", summary.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("  락:        %d (협상 %d)\n", summary.Locks, summary.Negotiations)
+	fmt.Printf("  델타:      %d\n", summary.Deltas)
+	fmt.Printf("  관심사:    %d\n", summary.Interests)
+	fmt.Printf("  문서:      %d\n", summary.Documents)
+	return nil
+}
@@ -1,8 +1,12 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"agent-collab/src/application"
@@ -21,13 +25,20 @@ peers, wireguard, token 명령어가 status에 통합되었습니다.
 사용 예시:
   agent-collab status              클러스터 상태 확인
   agent-collab status --json       JSON 형식으로 출력
-  agent-collab status --watch      실시간 갱신`,
+  agent-collab status --watch      실시간 갱신
+  agent-collab status --watch --output json
+                                   상태 변경마다 JSON 한 줄씩 출력 (대시보드용)
+  agent-collab status --watch --output json --coalesce 500ms
+                                   500ms 안의 연속 변경은 마지막 상태만 출력`,
 	RunE: runStatus,
 }
 
 var (
-	statusJSON  bool
-	statusWatch bool
+	statusJSON   bool
+	statusWatch  bool
+	statusOutput string
+
+	statusCoalesce time.Duration
 )
 
 func init() {
@@ -35,6 +46,20 @@ func init() {
 
 	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "JSON 형식으로 출력")
 	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "실시간 갱신")
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", "text", "출력 형식 (text, json)")
+	statusCmd.Flags().DurationVar(&statusCoalesce, "coalesce", 0, "데몬 스트림에서 이 시간 안의 연속 상태 변경을 하나로 합침 (예: 500ms)")
+}
+
+// statusOutputJSON reports whether status should be printed as JSON.
+func statusOutputJSON() (bool, error) {
+	switch statusOutput {
+	case "", "text":
+		return statusJSON, nil
+	case "json":
+		return true, nil
+	default:
+		return false, fmt.Errorf("지원하지 않는 출력 형식: %s (text, json)", statusOutput)
+	}
 }
 
 // EnhancedStatus contains extended status information
@@ -48,6 +73,12 @@ type EnhancedStatus struct {
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
+	asJSON, err := statusOutputJSON()
+	if err != nil {
+		return err
+	}
+	statusJSON = asJSON
+
 	// Check if daemon is running first
 	client := daemon.NewClient()
 	if client.IsRunning() {
@@ -62,12 +93,17 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	status := app.GetStatus()
 
+	if statusWatch && statusJSON {
+		ctx, stop := watchContext()
+		defer stop()
+		return streamStatusJSON(app.WatchStatus(ctx))
+	}
 	if statusWatch {
 		return runStatusWatch(app)
 	}
 
 	enhanced := &EnhancedStatus{Status: status}
-	return printEnhancedStatus(enhanced)
+	return printEnhancedStatus(enhanced, statusJSON)
 }
 
 func runStatusFromDaemon(client *daemon.Client) error {
@@ -77,13 +113,7 @@ func runStatusFromDaemon(client *daemon.Client) error {
 	}
 
 	// Convert daemon status to app status format
-	status := &application.Status{
-		Running:     true,
-		ProjectName: daemonStatus.ProjectName,
-		NodeID:      daemonStatus.NodeID,
-		PeerCount:   daemonStatus.PeerCount,
-		LockCount:   daemonStatus.LockCount,
-	}
+	status := statusFromDaemon(daemonStatus)
 
 	enhanced := &EnhancedStatus{Status: status}
 
@@ -105,15 +135,79 @@ func runStatusFromDaemon(client *daemon.Client) error {
 		enhanced.Events = eventsResp.Events
 	}
 
+	if statusWatch && statusJSON {
+		return runStatusWatchDaemonJSON(client)
+	}
 	if statusWatch {
 		return runStatusWatchDaemon(client)
 	}
 
-	return printEnhancedStatus(enhanced)
+	return printEnhancedStatus(enhanced, statusJSON)
+}
+
+// statusFromDaemon converts a daemon status response to the app status format.
+func statusFromDaemon(daemonStatus *daemon.StatusResponse) *application.Status {
+	return &application.Status{
+		Running:      true,
+		ProjectName:  daemonStatus.ProjectName,
+		Observer:     daemonStatus.Observer,
+		Role:         daemonStatus.Role,
+		NodeID:       daemonStatus.NodeID,
+		PeerCount:    daemonStatus.PeerCount,
+		LockCount:    daemonStatus.LockCount,
+		Negotiations: daemonStatus.Negotiations,
+		Regions:      daemonStatus.Regions,
+	}
+}
+
+// watchContext returns a context cancelled on Ctrl+C or SIGTERM.
+func watchContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 }
 
-func printEnhancedStatus(enhanced *EnhancedStatus) error {
-	if statusJSON {
+// runStatusWatchDaemonJSON streams status changes from the daemon event
+// stream as JSON lines.
+func runStatusWatchDaemonJSON(client *daemon.Client) error {
+	ctx, stop := watchContext()
+	defer stop()
+
+	if statusCoalesce != 0 {
+		opts := daemon.WatchOptions{Coalesce: statusCoalesce.String()}
+		if _, err := opts.CoalesceWindow(); err != nil {
+			return err
+		}
+		client.SetEventCoalescing(statusCoalesce)
+	}
+
+	updates, err := client.WatchStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("상태 스트림 연결 실패: %w", err)
+	}
+
+	statuses := make(chan *application.Status)
+	go func() {
+		defer close(statuses)
+		for update := range updates {
+			statuses <- statusFromDaemon(update)
+		}
+	}()
+	return streamStatusJSON(statuses)
+}
+
+// streamStatusJSON prints each status snapshot as one JSON line until the
+// channel is closed, so dashboards can consume the output line by line.
+func streamStatusJSON(statuses <-chan *application.Status) error {
+	encoder := json.NewEncoder(os.Stdout)
+	for status := range statuses {
+		if err := encoder.Encode(status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printEnhancedStatus(enhanced *EnhancedStatus, asJSON bool) error {
+	if asJSON {
 		data, err := json.MarshalIndent(enhanced, "", "  ")
 		if err != nil {
 			return err
@@ -164,6 +258,17 @@ func printEnhancedStatus(enhanced *EnhancedStatus) error {
 			fmt.Printf("     - %s\n", addr)
 		}
 	}
+	if len(status.Regions) > 0 {
+		fmt.Println("   리전:")
+		for _, r := range status.Regions {
+			local := ""
+			if r.Local {
+				local = " (로컬)"
+			}
+			fmt.Printf("     - %s%s: 피어 %d, 평균 RTT %s, 게이트웨이 %s\n",
+				r.Region, local, r.PeerCount, r.AverageRTT.Round(time.Millisecond), shortPeerID(r.GatewayPeer))
+		}
+	}
 	fmt.Println()
 
 	// 피어 목록 (--peers 플래그 또는 피어 수가 적을 때)
@@ -190,6 +295,11 @@ func printEnhancedStatus(enhanced *EnhancedStatus) error {
 	// 락 정보
 	fmt.Println("🔒 락")
 	fmt.Printf("   전체: %d | 내 락: %d\n", status.LockCount, status.MyLockCount)
+	if neg := status.Negotiations; neg != nil && neg.TotalSessions > 0 {
+		fmt.Printf("   협상: %d (진행 중 %d) | 평균 해결: %s | 에스컬레이션: %.0f%%\n",
+			neg.TotalSessions, neg.ActiveSessions,
+			neg.AvgTimeToResolution.Round(time.Millisecond), neg.EscalationRate*100)
+	}
 	fmt.Println()
 
 	// 토큰 사용량
@@ -199,6 +309,10 @@ func printEnhancedStatus(enhanced *EnhancedStatus) error {
 			formatTokenCount(enhanced.TokenUsage.TokensToday),
 			enhanced.TokenUsage.UsagePercent)
 		fmt.Printf("   비용: $%.4f\n", enhanced.TokenUsage.CostToday)
+		if enhanced.TokenUsage.ShedToday > 0 {
+			fmt.Printf("   과부하로 버린 작업: %d (~%s tokens)\n",
+				enhanced.TokenUsage.ShedToday, formatTokenCount(enhanced.TokenUsage.ShedTokensToday))
+		}
 		fmt.Println()
 	} else if status.TokensToday > 0 {
 		fmt.Println("💰 토큰 사용량")
@@ -238,12 +352,12 @@ func runStatusWatch(app *application.App) error {
 	// 초기 출력
 	fmt.Print("\033[2J\033[H") // 화면 클리어
 	enhanced := &EnhancedStatus{Status: app.GetStatus()}
-	printEnhancedStatus(enhanced)
+	printEnhancedStatus(enhanced, statusJSON)
 
 	for range ticker.C {
 		fmt.Print("\033[2J\033[H") // 화면 클리어
 		enhanced := &EnhancedStatus{Status: app.GetStatus()}
-		printEnhancedStatus(enhanced)
+		printEnhancedStatus(enhanced, statusJSON)
 	}
 
 	return nil
@@ -275,13 +389,7 @@ func printDaemonStatus(client *daemon.Client) error {
 		return fmt.Errorf("daemon 상태 조회 실패: %w", err)
 	}
 
-	status := &application.Status{
-		Running:     true,
-		ProjectName: daemonStatus.ProjectName,
-		NodeID:      daemonStatus.NodeID,
-		PeerCount:   daemonStatus.PeerCount,
-		LockCount:   daemonStatus.LockCount,
-	}
+	status := statusFromDaemon(daemonStatus)
 
 	enhanced := &EnhancedStatus{Status: status}
 
@@ -302,5 +410,5 @@ func printDaemonStatus(client *daemon.Client) error {
 		enhanced.Events = eventsResp.Events
 	}
 
-	return printEnhancedStatus(enhanced)
+	return printEnhancedStatus(enhanced, statusJSON)
 }
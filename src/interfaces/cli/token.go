@@ -38,6 +38,7 @@ var tokenUsageCmd = &cobra.Command{
 var (
 	usagePeriod string
 	usageJSON   bool
+	tokenRole   string
 )
 
 func init() {
@@ -47,6 +48,10 @@ func init() {
 	tokenCmd.AddCommand(tokenRefreshCmd)
 	tokenCmd.AddCommand(tokenUsageCmd)
 
+	for _, cmd := range []*cobra.Command{tokenShowCmd, tokenRefreshCmd} {
+		cmd.Flags().StringVar(&tokenRole, "role", "", "참여 노드의 역할 (admin|agent|observer, 기본 agent)")
+	}
+
 	tokenUsageCmd.Flags().StringVar(&usagePeriod, "period", "day", "기간 (day|week|month)")
 	tokenUsageCmd.Flags().BoolVar(&usageJSON, "json", false, "JSON 형식으로 출력")
 }
@@ -70,7 +75,7 @@ func runTokenShow(cmd *cobra.Command, args []string) error {
 	}
 	defer app.Stop()
 
-	tokenStr, err := app.CreateInviteToken()
+	tokenStr, err := createInviteToken(app)
 	if err != nil {
 		return fmt.Errorf("토큰 생성 실패: %w", err)
 	}
@@ -101,7 +106,7 @@ func runTokenRefresh(cmd *cobra.Command, args []string) error {
 	fmt.Println("🔄 토큰 갱신 중...")
 	fmt.Println()
 
-	tokenStr, err := app.CreateInviteToken()
+	tokenStr, err := createInviteToken(app)
 	if err != nil {
 		return fmt.Errorf("토큰 생성 실패: %w", err)
 	}
@@ -174,11 +179,12 @@ func runTokenUsage(cmd *cobra.Command, args []string) error {
 	// Build breakdown from metrics
 	var breakdown []UsageBreakdown
 	categoryNames := map[token.UsageCategory]string{
-		token.CategoryEmbedding:   "Embedding Generation",
-		token.CategorySync:        "Context Synchronization",
-		token.CategoryNegotiation: "Lock Negotiation",
-		token.CategoryQuery:       "Query Processing",
-		token.CategoryOther:       "Other",
+		token.CategoryEmbedding:     "Embedding Generation",
+		token.CategorySync:          "Context Synchronization",
+		token.CategoryNegotiation:   "Lock Negotiation",
+		token.CategoryQuery:         "Query Processing",
+		token.CategorySummarization: "Context Summarization",
+		token.CategoryOther:         "Other",
 	}
 
 	for cat, tokens := range metrics.ByCategory {
@@ -271,3 +277,15 @@ func formatTokenCount(n int64) string {
 	}
 	return fmt.Sprintf("%d", n)
 }
+
+// createInviteToken creates an invite token carrying the --role claim.
+func createInviteToken(app *application.App) (string, error) {
+	var role application.Role
+	if tokenRole != "" {
+		var err error
+		if role, err = application.ParseRole(tokenRole); err != nil {
+			return "", err
+		}
+	}
+	return app.CreateInviteTokenForRole(role)
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"agent-collab/src/interfaces/daemon"
+
+	"github.com/spf13/cobra"
+)
+
+var workCmd = &cobra.Command{
+	Use:   "work",
+	Short: "에이전트별 작업 현황 (누가 무엇을 하는지)",
+	Long: `활성 에이전트마다 현재 보유한 락과 최근 컨텍스트를 공유한 파일을 보여줍니다.
+
+사용 예시:
+  agent-collab work                최근 1시간 기준
+  agent-collab work --since 4h     최근 4시간 기준
+  agent-collab work --json         JSON 형식으로 출력`,
+	RunE: runWork,
+}
+
+var (
+	workSince string
+	workJSON  bool
+)
+
+func init() {
+	rootCmd.AddCommand(workCmd)
+
+	workCmd.Flags().StringVar(&workSince, "since", "1h", "최근 공유로 볼 기간 (예: 30m, 4h)")
+	workCmd.Flags().BoolVar(&workJSON, "json", false, "JSON 형식으로 출력")
+}
+
+func runWork(cmd *cobra.Command, args []string) error {
+	window, err := time.ParseDuration(workSince)
+	if err != nil {
+		return fmt.Errorf("잘못된 기간: %w", err)
+	}
+
+	client := daemon.NewClient()
+	if !client.IsRunning() {
+		return fmt.Errorf("데몬이 실행 중이 아닙니다. 'agent-collab daemon start'를 실행하세요")
+	}
+
+	workMap, err := client.WorkMap(time.Now().Add(-window))
+	if err != nil {
+		return fmt.Errorf("작업 현황 조회 실패: %w", err)
+	}
+
+	if workJSON {
+		data, err := json.MarshalIndent(workMap, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printWorkMap(os.Stdout, workMap)
+	return nil
+}
+
+// printWorkMap renders the work map as one block per agent.
+func printWorkMap(w io.Writer, workMap *daemon.WorkMapResponse) {
+	if len(workMap.Agents) == 0 {
+		fmt.Fprintln(w, "작업 중인 에이전트가 없습니다.")
+		return
+	}
+
+	for i, agent := range workMap.Agents {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		name := agent.AgentName
+		if name == "" {
+			name = agent.AgentID
+		}
+		fmt.Fprintf(w, "● %s (%d files)\n", name, len(agent.Files))
+
+		for _, l := range agent.Locks {
+			region := l.FilePath
+			if l.StartLine > 0 {
+				region = fmt.Sprintf("%s:%d-%d", l.FilePath, l.StartLine, l.EndLine)
+			}
+			fmt.Fprintf(w, "  🔒 %-40s %s\n", region, l.Intention)
+		}
+		for _, s := range agent.Shares {
+			fmt.Fprintf(w, "  📝 %-40s %s (%d, %s ago)\n",
+				s.FilePath, s.Summary, s.Shares, time.Since(s.LastShared).Round(time.Minute))
+		}
+	}
+}
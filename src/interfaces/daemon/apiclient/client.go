@@ -0,0 +1,155 @@
+// Package apiclient is a typed client for the daemon's v1 API served on
+// api_addr. The operation methods are generated from the OpenAPI document
+// in client_gen.go; this file holds the transport they share.
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"agent-collab/src/interfaces/daemon"
+)
+
+// Client calls the v1 API of one daemon.
+type Client struct {
+	baseURL       string
+	httpClient    *http.Client
+	operatorID    string
+	operatorToken string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithOperator sets the identity and token sent with every request. Writes
+// always need the token; reads need it when the daemon has one configured.
+func WithOperator(id, token string) Option {
+	return func(c *Client) {
+		c.operatorID = id
+		c.operatorToken = token
+	}
+}
+
+// New returns a client for the daemon whose api_addr is reachable at
+// baseURL, e.g. "http://127.0.0.1:7700".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error is a failed API call. The decoded response, when there is one, is
+// returned alongside it so fields such as retry_after_ms stay available.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode == http.StatusOK {
+		return e.Message
+	}
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// idempotencyKey is the context key of the Idempotency-Key to send.
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a context whose requests carry key, so a
+// retried write is applied once.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// do sends a request with body encoded as JSON, when not nil, and decodes
+// the JSON response into out.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.operatorToken != "" {
+		req.Header.Set(daemon.OperatorTokenHeader, c.operatorToken)
+	}
+	if c.operatorID != "" {
+		req.Header.Set(daemon.OperatorIDHeader, c.operatorID)
+	}
+	if key, ok := ctx.Value(idempotencyKey{}).(string); ok && key != "" {
+		req.Header.Set(daemon.IdempotencyKeyHeader, key)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+		}
+		return fmt.Errorf("decode %s %s response: %w", method, path, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return &Error{StatusCode: resp.StatusCode, Message: responseError(out, resp.Status)}
+	}
+	return nil
+}
+
+// check turns a decoded response that did not succeed into an Error
+// carrying its error field.
+func check(ok bool, msg string) error {
+	if ok {
+		return nil
+	}
+	if msg == "" {
+		msg = "request failed"
+	}
+	return &Error{StatusCode: http.StatusOK, Message: msg}
+}
+
+// responseError returns the error field of a decoded response, or fallback.
+func responseError(out any, fallback string) string {
+	var envelope struct {
+		Error string `json:"error"`
+	}
+	if data, err := json.Marshal(out); err == nil {
+		json.Unmarshal(data, &envelope)
+	}
+	if envelope.Error != "" {
+		return envelope.Error
+	}
+	return fallback
+}
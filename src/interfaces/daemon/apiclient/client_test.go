@@ -0,0 +1,104 @@
+package apiclient_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"agent-collab/src/application"
+	"agent-collab/src/interfaces/daemon"
+	"agent-collab/src/interfaces/daemon/apiclient"
+)
+
+func startAPIDaemon(t *testing.T, ctx context.Context) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	// Reserve a free port for the API listener
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiAddr := l.Addr().String()
+	l.Close()
+
+	app, err := application.New(&application.Config{DataDir: t.TempDir(), APIAddr: apiAddr})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(ctx, "apiclient-test-project"); err != nil {
+		t.Fatalf("Failed to initialize app: %v", err)
+	}
+	server := daemon.NewServer(app)
+	server.SetAuthenticator(daemon.NewTokenAuthenticator("secret"))
+	if err := server.Start(ctx); err != nil {
+		t.Fatalf("Failed to start daemon: %v", err)
+	}
+	t.Cleanup(func() { server.Stop() })
+	return "http://" + apiAddr
+}
+
+func TestClient_LockLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client := apiclient.New(startAPIDaemon(t, ctx), apiclient.WithOperator("alice", "secret"))
+
+	acquired, err := client.AcquireLock(ctx, daemon.LockRequest{
+		FilePath:  "auth/login.go",
+		StartLine: 10,
+		EndLine:   20,
+		Intention: "add rate limiting",
+	})
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+	if acquired.LockID == "" {
+		t.Fatalf("expected a lock ID, got %+v", acquired)
+	}
+
+	check, err := client.CheckLock(ctx, daemon.LockRequest{FilePath: "auth/login.go", StartLine: 15, EndLine: 18})
+	if err != nil {
+		t.Fatalf("CheckLock: %v", err)
+	}
+	if check.Free || len(check.Conflicts) != 1 {
+		t.Errorf("expected the region to be held, got %+v", check.LockCheck)
+	}
+
+	renewed, err := client.RenewLock(ctx, acquired.LockID, daemon.RenewLockRequest{TTLSeconds: 60})
+	if err != nil {
+		t.Fatalf("RenewLock: %v", err)
+	}
+	if renewed.Lock == nil || renewed.Lock.ID != acquired.LockID {
+		t.Errorf("expected lock %s renewed, got %+v", acquired.LockID, renewed.Lock)
+	}
+
+	locks, err := client.ListLocks(ctx)
+	if err != nil {
+		t.Fatalf("ListLocks: %v", err)
+	}
+	if len(locks.Locks) != 1 {
+		t.Errorf("expected 1 lock, got %d", len(locks.Locks))
+	}
+
+	if _, err := client.ReleaseLock(ctx, acquired.LockID); err != nil {
+		t.Fatalf("ReleaseLock: %v", err)
+	}
+	if _, err := client.ReleaseLock(ctx, acquired.LockID); err == nil {
+		t.Error("expected releasing a released lock to fail")
+	}
+}
+
+func TestClient_WritesNeedOperatorToken(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client := apiclient.New(startAPIDaemon(t, ctx), apiclient.WithOperator("alice", "wrong"))
+
+	_, err := client.AcquireLock(ctx, daemon.LockRequest{FilePath: "auth/login.go", StartLine: 1, EndLine: 2})
+	var apiErr *apiclient.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %v", err)
+	}
+}
@@ -0,0 +1,437 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"agent-collab/src/application"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// wsQueueSize is how many events a dashboard connection may fall
+	// behind by before the oldest queued events are dropped.
+	wsQueueSize = 256
+
+	// wsWriteTimeout is how long a frame may take to write before the
+	// connection is closed as a slow consumer.
+	wsWriteTimeout = 10 * time.Second
+
+	// wsPingInterval is how often idle connections are pinged; a peer that
+	// does not answer within wsPongTimeout is disconnected.
+	wsPingInterval = 30 * time.Second
+	wsPongTimeout  = 60 * time.Second
+)
+
+// startAPIListener serves the dashboard API over TCP when api_addr is
+// configured, since browsers cannot reach the Unix sockets.
+func (s *Server) startAPIListener() error {
+	cfg := s.app.Config()
+	if cfg == nil || cfg.APIAddr == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", cfg.APIAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on api_addr: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/ws", s.handleEventWebSocket)
+	mux.HandleFunc("GET /api/v1/openapi.json", handleOpenAPIDocument)
+	handlers := s.apiHandlers()
+	for _, op := range APIOperations() {
+		handler, ok := handlers[op.ID]
+		if !ok {
+			listener.Close()
+			return fmt.Errorf("no handler for API operation %s", op.ID)
+		}
+		mux.HandleFunc(op.Method+" "+op.Path, handler)
+	}
+	s.apiServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := s.apiServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "API server error: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// stopAPIListener lets WebSocket streams flush their queued events,
+// including daemon.shutdown, and waits for them until ctx expires.
+func (s *Server) stopAPIListener(ctx context.Context) {
+	if s.apiServer == nil {
+		return
+	}
+	s.apiDrainOnce.Do(func() { close(s.apiDraining) })
+	s.apiServer.Shutdown(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		s.apiStreams.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// apiAuthorized requires the operator token for read-only API endpoints
+// when one is configured, since api_addr may be reachable from other hosts.
+func (s *Server) apiAuthorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.auth == nil {
+			next(w, r)
+			return
+		}
+		s.authenticated(next)(w, r)
+	}
+}
+
+// apiHandlers maps the operations of APIOperations to their handlers.
+// Writes need the operator token since api_addr may be reachable from
+// other hosts.
+func (s *Server) apiHandlers() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"listLocks":        s.apiAuthorized(s.handleListLocks),
+		"acquireLock":      s.permitted(application.PermWrite, s.authenticated(s.idempotent(s.handleAcquireLock))),
+		"checkLock":        s.apiAuthorized(s.handleCheckLock),
+		"renewLock":        s.permitted(application.PermWrite, s.authenticated(s.handleAPIRenewLock)),
+		"upgradeLock":      s.permitted(application.PermWrite, s.authenticated(s.idempotent(s.handleAPIUpgradeLock))),
+		"releaseLock":      s.permitted(application.PermWrite, s.authenticated(s.idempotent(s.handleAPIReleaseLock))),
+		"shareContext":     s.permitted(application.PermWrite, s.authenticated(s.idempotent(s.handleShareContext))),
+		"searchContexts":   s.apiAuthorized(s.handleSearch),
+		"listAgents":       s.apiAuthorized(s.handleListAgents),
+		"listCollections":  s.apiAuthorized(s.handleListCollections),
+		"createCollection": s.permitted(application.PermWrite, s.authenticated(s.handleCreateCollection)),
+		"setCollectionTTL": s.permitted(application.PermOperate, s.authenticated(s.handleAPICollectionTTL)),
+		"deleteCollection": s.permitted(application.PermOperate, s.authenticated(s.handleAPIDeleteCollection)),
+	}
+}
+
+// handleOpenAPIDocument serves the OpenAPI document of the v1 endpoints.
+func handleOpenAPIDocument(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPIDocument)
+}
+
+// handleAPIRenewLock renews the lock named in the path.
+func (s *Server) handleAPIRenewLock(w http.ResponseWriter, r *http.Request) {
+	var req RenewLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(RenewLockResponse{Error: err.Error()})
+		return
+	}
+	req.LockID = r.PathValue("id")
+	s.handleRenewLock(w, requestWithBody(r, req))
+}
+
+// handleAPIUpgradeLock upgrades the lock named in the path.
+func (s *Server) handleAPIUpgradeLock(w http.ResponseWriter, r *http.Request) {
+	s.handleUpgradeLock(w, requestWithBody(r, UpgradeLockRequest{LockID: r.PathValue("id")}))
+}
+
+// handleAPIReleaseLock releases the lock named in the path.
+func (s *Server) handleAPIReleaseLock(w http.ResponseWriter, r *http.Request) {
+	s.handleReleaseLock(w, requestWithBody(r, ReleaseLockRequest{LockID: r.PathValue("id")}))
+}
+
+// handleAPICollectionTTL sets the TTL of the collection named in the path
+// from a {"ttl": "72h"} body.
+func (s *Server) handleAPICollectionTTL(w http.ResponseWriter, r *http.Request) {
+	var req CollectionTTLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(CollectionResponse{Error: err.Error()})
+		return
+	}
+	req.Name = r.PathValue("name")
+	s.handleCollectionTTL(w, requestWithBody(r, req))
+}
+
+// handleAPIDeleteCollection deletes the collection named in the path.
+func (s *Server) handleAPIDeleteCollection(w http.ResponseWriter, r *http.Request) {
+	s.handleDeleteCollection(w, requestWithBody(r, DeleteCollectionRequest{Name: r.PathValue("name")}))
+}
+
+// requestWithBody returns a copy of r whose body is v encoded as JSON, so
+// REST routes can reuse the socket API handlers.
+func requestWithBody(r *http.Request, v any) *http.Request {
+	data, _ := json.Marshal(v)
+	r = r.Clone(r.Context())
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	r.ContentLength = int64(len(data))
+	return r
+}
+
+// handleEventWebSocket streams daemon events to a dashboard as JSON text
+// frames. ?types=lock.*,peer.connected limits the stream to those event
+// types; a trailing ".*" matches every type in the group.
+func (s *Server) handleEventWebSocket(w http.ResponseWriter, r *http.Request) {
+	if s.auth != nil {
+		// Browsers cannot set headers on a WebSocket handshake
+		if token := r.URL.Query().Get("token"); token != "" && r.Header.Get(OperatorTokenHeader) == "" {
+			r = r.Clone(r.Context())
+			r.Header.Set(OperatorTokenHeader, token)
+		}
+		if _, err := s.auth.Authenticate(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	filter := parseEventTypeFilter(r.URL.Query().Get("types"))
+
+	var origins []string
+	if cfg := s.app.Config(); cfg != nil {
+		origins = cfg.APIAllowedOrigins
+	}
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return originAllowed(r, origins) },
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has already replied
+	}
+
+	s.apiStreams.Add(1)
+	defer s.apiStreams.Done()
+
+	clientID := "ws-" + uuid.NewString()
+	events := s.eventBus.Subscribe(clientID)
+	defer s.eventBus.Unsubscribe(clientID)
+
+	stream := newWSStream(conn, filter)
+	stream.run(events, s.apiDraining)
+}
+
+// eventTypeFilter selects the event types a stream forwards. An empty
+// filter forwards everything.
+type eventTypeFilter struct {
+	exact    map[EventType]bool
+	prefixes []string
+}
+
+// parseEventTypeFilter parses a comma-separated list of event types, where
+// "lock.*" selects every lock event.
+func parseEventTypeFilter(raw string) eventTypeFilter {
+	f := eventTypeFilter{exact: make(map[EventType]bool)}
+	for t := range strings.SplitSeq(raw, ",") {
+		t = strings.TrimSpace(t)
+		switch {
+		case t == "" || t == "*":
+		case strings.HasSuffix(t, ".*"):
+			f.prefixes = append(f.prefixes, strings.TrimSuffix(t, "*"))
+		default:
+			f.exact[EventType(t)] = true
+		}
+	}
+	return f
+}
+
+// matches reports whether events of type t pass the filter. Shutdown is
+// always forwarded so dashboards know the stream is ending.
+func (f eventTypeFilter) matches(t EventType) bool {
+	if len(f.exact) == 0 && len(f.prefixes) == 0 {
+		return true
+	}
+	if t == EventDaemonShutdown || f.exact[t] {
+		return true
+	}
+	return slices.ContainsFunc(f.prefixes, func(p string) bool {
+		return strings.HasPrefix(string(t), p)
+	})
+}
+
+// originAllowed accepts handshakes without an Origin (non-browser clients),
+// from the API's own host, or from a configured dashboard origin.
+func originAllowed(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if slices.Contains(allowed, "*") || slices.Contains(allowed, origin) {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// wsStream forwards events to one WebSocket connection. Events queue up
+// while the connection is slow; when the queue is full the oldest are
+// dropped and the client is told how many it missed.
+type wsStream struct {
+	conn   *websocket.Conn
+	filter eventTypeFilter
+
+	mu      sync.Mutex
+	queue   []Event
+	dropped int
+	wake    chan struct{}
+}
+
+func newWSStream(conn *websocket.Conn, filter eventTypeFilter) *wsStream {
+	return &wsStream{
+		conn:   conn,
+		filter: filter,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// push queues an event for the writer, dropping the oldest when full.
+func (ws *wsStream) push(e Event) {
+	ws.mu.Lock()
+	if len(ws.queue) >= wsQueueSize {
+		ws.queue = ws.queue[1:]
+		ws.dropped++
+	}
+	ws.queue = append(ws.queue, e)
+	ws.mu.Unlock()
+
+	select {
+	case ws.wake <- struct{}{}:
+	default:
+	}
+}
+
+// take returns the queued events and how many were dropped since the
+// last call.
+func (ws *wsStream) take() ([]Event, int) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	queue, dropped := ws.queue, ws.dropped
+	ws.queue, ws.dropped = nil, 0
+	return queue, dropped
+}
+
+// run streams events until the client goes away, writes stall, the event
+// channel closes or the server drains.
+func (ws *wsStream) run(events <-chan Event, draining <-chan struct{}) {
+	defer ws.conn.Close()
+
+	// Read control frames so pongs and the client's close are handled
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		ws.conn.SetReadLimit(512)
+		ws.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		ws.conn.SetPongHandler(func(string) error {
+			return ws.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		})
+		for {
+			if _, _, err := ws.conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Move events off the bus right away so a slow client drops its own
+	// events rather than the bus dropping them silently
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			select {
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				if ws.filter.matches(e.Type) {
+					ws.push(e)
+				}
+			case <-draining:
+				// Shutdown was published before the drain began, so it is
+				// already buffered
+				for {
+					select {
+					case e, ok := <-events:
+						if !ok {
+							return
+						}
+						if ws.filter.matches(e.Type) {
+							ws.push(e)
+						}
+					default:
+						return
+					}
+				}
+			case <-gone:
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ws.wake:
+			if !ws.flush() {
+				return
+			}
+		case <-ping.C:
+			if ws.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)) != nil {
+				return
+			}
+		case <-closed:
+			if ws.flush() {
+				ws.close(websocket.CloseGoingAway, "event stream closed")
+			}
+			return
+		case <-gone:
+			return
+		}
+	}
+}
+
+// flush writes the queued events, preceded by a warning when some were
+// dropped. It reports false when the connection should be closed.
+func (ws *wsStream) flush() bool {
+	queue, dropped := ws.take()
+	if dropped > 0 {
+		warning := NewEvent(EventWarning, map[string]any{
+			"message": "event stream fell behind; oldest events dropped",
+			"dropped": dropped,
+		})
+		if !ws.write(warning) {
+			return false
+		}
+	}
+	for _, e := range queue {
+		if !ws.write(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// write sends one event frame; a write that stalls closes the connection.
+func (ws *wsStream) write(e Event) bool {
+	ws.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return ws.conn.WriteJSON(e) == nil
+}
+
+// close sends a close frame.
+func (ws *wsStream) close(code int, reason string) {
+	ws.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+}
@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func startTestWebSocket(t *testing.T, query string) (*EventBus, *websocket.Conn, chan struct{}) {
+	t.Helper()
+
+	bus := NewEventBus()
+	draining := make(chan struct{})
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		events := bus.Subscribe("ws-test")
+		defer bus.Unsubscribe("ws-test")
+		newWSStream(conn, parseEventTypeFilter(r.URL.Query().Get("types"))).run(events, draining)
+	}))
+	t.Cleanup(srv.Close)
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/ws" + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// Wait for the subscription before publishing
+	deadline := time.Now().Add(2 * time.Second)
+	for bus.SubscriberCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return bus, conn, draining
+}
+
+func TestEventWebSocket_FiltersEventTypes(t *testing.T) {
+	bus, conn, _ := startTestWebSocket(t, "?types=lock.*,peer.connected")
+
+	bus.Publish(NewEvent(EventContextUpdated, ContextEventData{FilePath: "main.go"}))
+	bus.Publish(NewEvent(EventLockAcquired, LockEventData{LockID: "lock-1"}))
+	bus.Publish(NewEvent(EventPeerDisconnected, PeerEventData{PeerID: "peer-1"}))
+	bus.Publish(NewEvent(EventPeerConnected, PeerEventData{PeerID: "peer-1"}))
+
+	var got []EventType
+	for range 2 {
+		var e Event
+		if err := conn.ReadJSON(&e); err != nil {
+			t.Fatalf("failed to read event: %v", err)
+		}
+		got = append(got, e.Type)
+	}
+	if got[0] != EventLockAcquired || got[1] != EventPeerConnected {
+		t.Errorf("expected lock.acquired then peer.connected, got %v", got)
+	}
+}
+
+func TestEventWebSocket_DrainSendsShutdownAndCloses(t *testing.T) {
+	bus, conn, draining := startTestWebSocket(t, "?types=lock.*")
+
+	bus.Publish(NewEvent(EventLockReleased, LockEventData{LockID: "lock-1"}))
+	bus.Publish(NewEvent(EventDaemonShutdown, nil))
+	close(draining)
+
+	var got []EventType
+	for {
+		var e Event
+		if err := conn.ReadJSON(&e); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+				t.Fatalf("expected a going-away close, got %v", err)
+			}
+			break
+		}
+		got = append(got, e.Type)
+	}
+	if len(got) != 2 || got[0] != EventLockReleased || got[1] != EventDaemonShutdown {
+		t.Errorf("expected the queued event and shutdown before close, got %v", got)
+	}
+}
+
+func TestWSStream_DropsOldestWhenFull(t *testing.T) {
+	ws := newWSStream(nil, eventTypeFilter{})
+	for range wsQueueSize + 3 {
+		ws.push(NewEvent(EventLockAcquired, nil))
+	}
+
+	queue, dropped := ws.take()
+	if len(queue) != wsQueueSize || dropped != 3 {
+		t.Errorf("expected %d queued and 3 dropped, got %d and %d", wsQueueSize, len(queue), dropped)
+	}
+	if queue, dropped := ws.take(); len(queue) != 0 || dropped != 0 {
+		t.Errorf("expected take to reset the queue, got %d and %d", len(queue), dropped)
+	}
+}
+
+func TestEventTypeFilter_Matches(t *testing.T) {
+	tests := []struct {
+		raw  string
+		t    EventType
+		want bool
+	}{
+		{"", EventContextUpdated, true},
+		{"*", EventPeerBanned, true},
+		{"lock.*", EventLockAcquired, true},
+		{"lock.*", EventContextUpdated, false},
+		{"lock.*, agent.joined", EventAgentJoined, true},
+		{"lock.*", EventDaemonShutdown, true},
+		{"peer.connected", EventPeerDisconnected, false},
+	}
+	for _, tt := range tests {
+		if got := parseEventTypeFilter(tt.raw).matches(tt.t); got != tt.want {
+			t.Errorf("filter %q matches(%s) = %v, want %v", tt.raw, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestEventWebSocket_RequiresOperatorToken(t *testing.T) {
+	s := &Server{auth: NewTokenAuthenticator("secret"), eventBus: NewEventBus()}
+
+	w := httptest.NewRecorder()
+	s.handleEventWebSocket(w, httptest.NewRequest(http.MethodGet, "/api/v1/ws?token=wrong", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a wrong token, got %d", w.Code)
+	}
+}
+
+func TestOriginAllowed(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:9465/api/v1/ws", nil)
+	if !originAllowed(r, nil) {
+		t.Error("expected requests without an Origin to be allowed")
+	}
+	r.Header.Set("Origin", "http://127.0.0.1:9465")
+	if !originAllowed(r, nil) {
+		t.Error("expected the API's own origin to be allowed")
+	}
+	r.Header.Set("Origin", "http://localhost:3000")
+	if originAllowed(r, nil) {
+		t.Error("expected a foreign origin to be rejected")
+	}
+	if !originAllowed(r, []string{"http://localhost:3000"}) {
+		t.Error("expected a configured origin to be allowed")
+	}
+}
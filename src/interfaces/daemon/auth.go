@@ -0,0 +1,103 @@
+package daemon
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"agent-collab/src/application"
+)
+
+const (
+	// OperatorTokenEnv holds the token that authorizes operator-only endpoints.
+	OperatorTokenEnv = "AGENT_COLLAB_OPERATOR_TOKEN"
+
+	// OperatorTokenHeader carries the operator token on requests.
+	OperatorTokenHeader = "X-Operator-Token"
+
+	// OperatorIDHeader carries the operator identity recorded in audit entries.
+	OperatorIDHeader = "X-Operator-ID"
+)
+
+// ErrUnauthorized is returned when a request fails authentication.
+var ErrUnauthorized = errors.New("unauthorized: valid operator token required")
+
+// Authenticator verifies requests to operator-only endpoints and returns
+// the identity of the caller.
+type Authenticator interface {
+	Authenticate(r *http.Request) (string, error)
+}
+
+// TokenAuthenticator authenticates operators with a shared token.
+type TokenAuthenticator struct {
+	token string
+}
+
+// NewTokenAuthenticator creates an authenticator for the given token.
+func NewTokenAuthenticator(token string) *TokenAuthenticator {
+	return &TokenAuthenticator{token: token}
+}
+
+// Authenticate checks the operator token and returns the operator identity.
+func (a *TokenAuthenticator) Authenticate(r *http.Request) (string, error) {
+	got := r.Header.Get(OperatorTokenHeader)
+	if a.token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(a.token)) != 1 {
+		return "", ErrUnauthorized
+	}
+
+	operator := r.Header.Get(OperatorIDHeader)
+	if operator == "" {
+		operator = "operator"
+	}
+	return operator, nil
+}
+
+type operatorKey struct{}
+
+// operatorFromContext returns the operator set by the authenticated middleware.
+func operatorFromContext(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorKey{}).(string)
+	return operator
+}
+
+// SetAuthenticator sets the authenticator for operator-only endpoints.
+// Without one, those endpoints reject every request. Call before Start.
+func (s *Server) SetAuthenticator(auth Authenticator) {
+	s.auth = auth
+}
+
+// authenticated wraps a handler so that only authenticated operators reach it.
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth := s.auth
+		if auth == nil {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(GenericResponse{Error: "operator endpoints disabled: set " + OperatorTokenEnv})
+			return
+		}
+
+		operator, err := auth.Authenticate(r)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), operatorKey{}, operator)))
+	}
+}
+
+// permitted wraps a handler so that it only runs when the node's role
+// grants p.
+func (s *Server) permitted(p application.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := s.app.Authorize(p); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
+			return
+		}
+		next(w, r)
+	}
+}
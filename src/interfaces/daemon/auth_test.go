@@ -0,0 +1,137 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"agent-collab/src/application"
+	"agent-collab/src/infrastructure/crypto"
+)
+
+func newOperatorRequest(token, operator string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/lock/force-release", strings.NewReader(`{"lock_id":"lock-1","reason":"crashed"}`))
+	if token != "" {
+		r.Header.Set(OperatorTokenHeader, token)
+	}
+	if operator != "" {
+		r.Header.Set(OperatorIDHeader, operator)
+	}
+	return r
+}
+
+func TestServer_AuthenticatedRejectsWithoutAuthenticator(t *testing.T) {
+	s := &Server{}
+
+	called := false
+	handler := s.authenticated(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	w := httptest.NewRecorder()
+	handler(w, newOperatorRequest("secret", "alice"))
+
+	if called {
+		t.Error("handler must not run without an authenticator")
+	}
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestServer_AuthenticatedRejectsBadToken(t *testing.T) {
+	s := &Server{}
+	s.SetAuthenticator(NewTokenAuthenticator("secret"))
+
+	called := false
+	handler := s.authenticated(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	for _, token := range []string{"", "wrong"} {
+		w := httptest.NewRecorder()
+		handler(w, newOperatorRequest(token, "alice"))
+
+		if called {
+			t.Fatalf("handler must not run with token %q", token)
+		}
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("token %q: expected 401, got %d", token, w.Code)
+		}
+
+		var resp GenericResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil || resp.Error == "" {
+			t.Errorf("token %q: expected error response, got %+v (%v)", token, resp, err)
+		}
+	}
+}
+
+func TestServer_AuthenticatedPassesOperator(t *testing.T) {
+	s := &Server{}
+	s.SetAuthenticator(NewTokenAuthenticator("secret"))
+
+	var operator string
+	handler := s.authenticated(func(w http.ResponseWriter, r *http.Request) {
+		operator = operatorFromContext(r.Context())
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, newOperatorRequest("secret", "alice"))
+	if operator != "alice" {
+		t.Errorf("expected operator alice, got %q", operator)
+	}
+
+	handler(httptest.NewRecorder(), newOperatorRequest("secret", ""))
+	if operator != "operator" {
+		t.Errorf("expected default operator identity, got %q", operator)
+	}
+}
+
+func TestServer_PermittedByRole(t *testing.T) {
+	root, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	grant, err := crypto.IssueRoleGrant(root, "proj", string(application.RoleAdmin))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		role    application.Role
+		grant   *crypto.RoleCredential
+		perm    application.Permission
+		allowed bool
+	}{
+		{application.RoleObserver, nil, application.PermRead, true},
+		{application.RoleObserver, nil, application.PermWrite, false},
+		{application.RoleAgent, nil, application.PermWrite, true},
+		{application.RoleAgent, nil, application.PermOperate, false},
+		{application.RoleAdmin, grant, application.PermOperate, true},
+		// A self-asserted admin role without a grant is an agent
+		{application.RoleAdmin, nil, application.PermOperate, false},
+	}
+	for _, tc := range cases {
+		app, err := application.New(&application.Config{
+			DataDir:        t.TempDir(),
+			ProjectName:    "proj",
+			Role:           string(tc.role),
+			ClusterRoot:    root.PeerID.String(),
+			RoleCredential: tc.grant,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := &Server{app: app}
+
+		called := false
+		handler := s.permitted(tc.perm, func(w http.ResponseWriter, r *http.Request) { called = true })
+		w := httptest.NewRecorder()
+		handler(w, newOperatorRequest("", ""))
+
+		if called != tc.allowed {
+			t.Errorf("%s/%s: expected allowed=%v", tc.role, tc.perm, tc.allowed)
+		}
+		if !tc.allowed && w.Code != http.StatusForbidden {
+			t.Errorf("%s/%s: expected 403, got %d", tc.role, tc.perm, w.Code)
+		}
+	}
+}
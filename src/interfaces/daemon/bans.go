@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"agent-collab/src/infrastructure/network/libp2p"
+)
+
+// BanPeerRequest is an operator request to ban a peer.
+type BanPeerRequest struct {
+	PeerID string `json:"peer_id"`
+	Reason string `json:"reason"`
+	// Duration is how long the ban lasts, e.g. "24h"; empty bans the peer
+	// until it is unbanned.
+	Duration string `json:"duration,omitempty"`
+}
+
+// BanPeerResponse is the response to a ban request.
+type BanPeerResponse struct {
+	Success bool        `json:"success"`
+	Ban     *libp2p.Ban `json:"ban,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// UnbanPeerRequest is an operator request to lift a peer's ban.
+type UnbanPeerRequest struct {
+	PeerID string `json:"peer_id"`
+}
+
+// ListBansResponse lists the peer bans in effect.
+type ListBansResponse struct {
+	Bans  []*libp2p.Ban `json:"bans"`
+	Error string        `json:"error,omitempty"`
+}
+
+// handleBanPeer handles the /peers/ban endpoint.
+func (s *Server) handleBanPeer(w http.ResponseWriter, r *http.Request) {
+	var req BanPeerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(BanPeerResponse{Error: err.Error()})
+		return
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			json.NewEncoder(w).Encode(BanPeerResponse{Error: "invalid duration: " + err.Error()})
+			return
+		}
+		duration = d
+	}
+
+	// The ban event is published by the app's ban handler
+	ban, err := s.app.BanPeer(req.PeerID, req.Reason, operatorFromContext(r.Context()), duration)
+	if err != nil {
+		json.NewEncoder(w).Encode(BanPeerResponse{Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(BanPeerResponse{Success: true, Ban: ban})
+}
+
+// handleUnbanPeer handles the /peers/unban endpoint.
+func (s *Server) handleUnbanPeer(w http.ResponseWriter, r *http.Request) {
+	var req UnbanPeerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
+		return
+	}
+
+	if err := s.app.UnbanPeer(req.PeerID); err != nil {
+		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
+		return
+	}
+	s.PublishEvent(NewEvent(EventPeerUnbanned, PeerEventData{PeerID: req.PeerID}))
+	json.NewEncoder(w).Encode(GenericResponse{Success: true})
+}
+
+// handleListBans handles the /peers/bans endpoint.
+func (s *Server) handleListBans(w http.ResponseWriter, _ *http.Request) {
+	bans := s.app.ListBans()
+	if bans == nil {
+		bans = []*libp2p.Ban{}
+	}
+	json.NewEncoder(w).Encode(ListBansResponse{Bans: bans})
+}
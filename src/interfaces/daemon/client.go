@@ -8,16 +8,48 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"time"
+
+	"agent-collab/src/application"
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/network/libp2p"
+	"agent-collab/src/infrastructure/storage/metrics"
+
+	"github.com/google/uuid"
 )
 
+// RetryPolicy controls how idempotent requests are retried after transient failures.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential backoff.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the default retry policy.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
 // Client is a client for communicating with the daemon.
 type Client struct {
 	socketPath  string
 	httpClient  *http.Client
 	eventClient *EventClient
+	retry       RetryPolicy
+
+	// Credentials for operator-only endpoints
+	operatorID    string
+	operatorToken string
 }
 
 // NewClient creates a new daemon client.
@@ -38,6 +70,7 @@ func NewClient() *Client {
 			Transport: transport,
 			Timeout:   30 * time.Second,
 		},
+		retry: DefaultRetryPolicy(),
 	}
 }
 
@@ -50,9 +83,26 @@ func NewClientWithTransport(transport *http.Transport, socketPath string) *Clien
 			Transport: transport,
 			Timeout:   5 * time.Second,
 		},
+		retry: DefaultRetryPolicy(),
 	}
 }
 
+// SocketPath returns the Unix socket path the client dials.
+func (c *Client) SocketPath() string {
+	return c.socketPath
+}
+
+// SetRetryPolicy sets the retry policy for idempotent requests.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// SetOperator sets the identity and token sent to operator-only endpoints.
+func (c *Client) SetOperator(id, token string) {
+	c.operatorID = id
+	c.operatorToken = token
+}
+
 // SubscribeEvents connects to the event stream and returns event/error channels.
 func (c *Client) SubscribeEvents(ctx context.Context) (<-chan Event, <-chan error, error) {
 	if err := c.eventClient.Connect(ctx); err != nil {
@@ -67,6 +117,79 @@ func (c *Client) SubscribeEventsWithRetry(ctx context.Context) <-chan Event {
 	return c.eventClient.Events()
 }
 
+// SetEventCoalescing asks the daemon to collapse status updates arriving
+// within window into the latest one on this client's event stream. It
+// applies to subscriptions made afterwards.
+func (c *Client) SetEventCoalescing(window time.Duration) {
+	c.eventClient.updateWatchOptions(func(opts *WatchOptions) {
+		opts.Coalesce = window.String()
+	})
+}
+
+// SetEventReplay asks the daemon to send the recent events at or after
+// since (an RFC 3339 timestamp or a duration ago such as "30m"), at most
+// last of them, before live events when this client subscribes. The
+// backlog ends with an EventReplayDone event.
+func (c *Client) SetEventReplay(since string, last int) {
+	c.eventClient.updateWatchOptions(func(opts *WatchOptions) {
+		opts.ReplaySince = since
+		opts.ReplayLast = last
+	})
+}
+
+// WatchStatus streams daemon status snapshots: the current status first,
+// then one for every change the daemon publishes on the event stream.
+// The channel is closed when ctx is done or the event stream ends.
+func (c *Client) WatchStatus(ctx context.Context) (<-chan *StatusResponse, error) {
+	events, errs, err := c.SubscribeEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *StatusResponse, 1)
+	send := func(status *StatusResponse) bool {
+		select {
+		case ch <- status:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(ch)
+		defer c.CloseEvents()
+
+		if status, err := c.Status(); err == nil && !send(status) {
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-errs:
+				return
+			case event := <-events:
+				switch event.Type {
+				case EventDaemonShutdown:
+					return
+				case EventStatusUpdated:
+					var status StatusResponse
+					if err := json.Unmarshal(event.Data, &status); err != nil {
+						continue
+					}
+					if !send(&status) {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
 // CloseEvents closes the event subscription.
 func (c *Client) CloseEvents() error {
 	return c.eventClient.Close()
@@ -110,6 +233,34 @@ func (c *Client) Status() (*StatusResponse, error) {
 	return &status, nil
 }
 
+// Health returns daemon readiness and embedding provider health.
+func (c *Client) Health() (*HealthResponse, error) {
+	return c.health("/health")
+}
+
+// HealthVerbose is Health with every subsystem probed and reported with
+// its latency and last success.
+func (c *Client) HealthVerbose() (*HealthResponse, error) {
+	return c.health("/health?verbose=true")
+}
+
+func (c *Client) health(path string) (*HealthResponse, error) {
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result HealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
 // Init initializes a new cluster.
 func (c *Client) Init(projectName string) (*InitResponse, error) {
 	resp, err := c.post("/init", InitRequest{ProjectName: projectName})
@@ -147,13 +298,70 @@ func (c *Client) Join(token string) (*JoinResponse, error) {
 }
 
 // AcquireLock acquires a lock.
+// Acquisition is not idempotent, so the request is only resent when the
+// daemon rate limited it.
 func (c *Client) AcquireLock(filePath string, startLine, endLine int, intention string) (*LockResponse, error) {
-	resp, err := c.post("/lock/acquire", LockRequest{
+	return c.AcquireLockWithKey("", filePath, startLine, endLine, intention)
+}
+
+// AcquireLockWithKey acquires a lock, retrying on transient failures when an
+// idempotency key is given. Retries with the same key never acquire twice.
+// A rate limited request was never executed, so it is retried after the
+// daemon's retry hint, up to the retry policy's attempt limit.
+func (c *Client) AcquireLockWithKey(idempotencyKey, filePath string, startLine, endLine int, intention string) (*LockResponse, error) {
+	return c.AcquireLockRequest(idempotencyKey, LockRequest{
 		FilePath:  filePath,
 		StartLine: startLine,
 		EndLine:   endLine,
 		Intention: intention,
 	})
+}
+
+// AcquireLockRequest acquires a lock described by req, which may carry a
+// TTL or an absolute deadline. Retries behave as in AcquireLockWithKey.
+func (c *Client) AcquireLockRequest(idempotencyKey string, req LockRequest) (*LockResponse, error) {
+	attempts := max(c.retry.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		result, err := c.acquireLockOnce(idempotencyKey, req)
+		if err != nil || result.RetryAfterMs <= 0 || attempt >= attempts {
+			return result, err
+		}
+		time.Sleep(result.RetryAfter())
+	}
+}
+
+func (c *Client) acquireLockOnce(idempotencyKey string, req LockRequest) (*LockResponse, error) {
+	if req.WaitForLock {
+		// The daemon answers once the lock is free or the wait timed out
+		c = c.withTimeout(c.httpClient.Timeout + req.WaitTimeout())
+	}
+	resp, err := c.postIdempotent("/lock/acquire", idempotencyKey, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result LockResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// withTimeout returns a copy of the client whose requests time out after d.
+func (c *Client) withTimeout(d time.Duration) *Client {
+	httpClient := *c.httpClient
+	httpClient.Timeout = d
+	copied := *c
+	copied.httpClient = &httpClient
+	return &copied
+}
+
+// UpgradeLock turns a read lock held by this node into a write lock. While
+// other agents read the region the response carries the negotiation the
+// daemon started with them.
+func (c *Client) UpgradeLock(lockID string) (*LockResponse, error) {
+	resp, err := c.postIdempotent("/lock/upgrade", uuid.NewString(), UpgradeLockRequest{LockID: lockID})
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +376,7 @@ func (c *Client) AcquireLock(filePath string, startLine, endLine int, intention
 
 // ReleaseLock releases a lock.
 func (c *Client) ReleaseLock(lockID string) error {
-	resp, err := c.post("/lock/release", ReleaseLockRequest{LockID: lockID})
+	resp, err := c.postIdempotent("/lock/release", uuid.NewString(), ReleaseLockRequest{LockID: lockID})
 	if err != nil {
 		return err
 	}
@@ -184,267 +392,889 @@ func (c *Client) ReleaseLock(lockID string) error {
 	return nil
 }
 
-// ListLocks returns all active locks.
-func (c *Client) ListLocks() (*ListLocksResponse, error) {
-	resp, err := c.get("/lock/list")
+// ReleaseLocksByIntention releases this node's locks acquired under an
+// intention, or under any intention starting with it when prefix is set.
+func (c *Client) ReleaseLocksByIntention(intention string, prefix bool) ([]*lock.SemanticLock, error) {
+	resp, err := c.postIdempotent("/lock/release-by-intention", uuid.NewString(), ReleaseLocksByIntentionRequest{
+		Intention: intention,
+		Prefix:    prefix,
+	})
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result ListLocksResponse
+	var result ReleaseLocksByIntentionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
-	return &result, nil
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return result.Released, nil
 }
 
-// Embed generates embeddings for text.
-func (c *Client) Embed(text string) (*EmbedResponse, error) {
-	resp, err := c.post("/embed", EmbedRequest{Text: text})
+// RenewLock extends the lease of a lock held by this node by the default TTL.
+func (c *Client) RenewLock(lockID string) (*lock.SemanticLock, error) {
+	return c.RenewLease(RenewLockRequest{LockID: lockID})
+}
+
+// RenewLease extends the lease of a lock held by this node, checking the
+// fencing token and applying the TTL when given.
+func (c *Client) RenewLease(req RenewLockRequest) (*lock.SemanticLock, error) {
+	resp, err := c.post("/lock/renew", req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result EmbedResponse
+	var result RenewLockResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
-	return &result, nil
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return result.Lock, nil
 }
 
-// Search searches for similar content.
-func (c *Client) Search(query string, limit int) (*SearchResponse, error) {
-	resp, err := c.post("/search", SearchRequest{Query: query, Limit: limit})
+// ForceReleaseLock breaks a lock regardless of its holder.
+// Requires operator credentials set with SetOperator.
+func (c *Client) ForceReleaseLock(lockID, reason string) (*lock.ForceReleaseNotice, error) {
+	resp, err := c.postIdempotentWithHeader("/lock/force-release", uuid.NewString(), ForceReleaseLockRequest{
+		LockID: lockID,
+		Reason: reason,
+	}, c.operatorHeader())
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result SearchResponse
+	var result ForceReleaseLockResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
-	return &result, nil
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return result.Notice, nil
 }
 
-// ListAgents returns connected agents.
-func (c *Client) ListAgents() (*ListAgentsResponse, error) {
-	resp, err := c.get("/agents/list")
+// ListLocks returns all active locks.
+func (c *Client) ListLocks() (*ListLocksResponse, error) {
+	resp, err := c.get("/lock/list")
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result ListAgentsResponse
+	var result ListLocksResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-// ListPeers returns connected peers.
-func (c *Client) ListPeers() (*ListPeersResponse, error) {
-	resp, err := c.get("/peers/list")
+// CheckLock reports whether a region is free, who holds overlapping locks
+// and how long they are expected to keep them, without announcing an
+// intent to lock it. mode is the lock mode to check for ("" for write).
+func (c *Client) CheckLock(filePath string, startLine, endLine int, mode string) (*lock.LockCheck, error) {
+	resp, err := c.post("/lock/check", LockRequest{
+		FilePath:  filePath,
+		StartLine: startLine,
+		EndLine:   endLine,
+		Mode:      mode,
+	})
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result ListPeersResponse
+	var result CheckLockResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
-	return &result, nil
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return result.LockCheck, nil
 }
 
-// WatchFile starts watching a file.
-func (c *Client) WatchFile(filePath string) error {
-	resp, err := c.post("/context/watch", WatchFileRequest{FilePath: filePath})
+// LockHistory queries the daemon's lock audit log, newest first.
+func (c *Client) LockHistory(q metrics.LockHistoryQuery) ([]*lock.HistoryEntry, error) {
+	resp, err := c.get("/lock/history?" + lockHistoryValues(q).Encode())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result GenericResponse
+	var result LockHistoryResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return err
+		return nil, err
 	}
 	if result.Error != "" {
-		return fmt.Errorf("%s", result.Error)
+		return nil, fmt.Errorf("%s", result.Error)
 	}
-	return nil
+	return result.Entries, nil
 }
 
-// ListEventsResponse is the response for listing events.
-type ListEventsResponse struct {
-	Events []Event `json:"events"`
-	Count  int     `json:"count"`
-}
+// ListCollections returns the vector collections with their stats.
+func (c *Client) ListCollections() ([]CollectionStats, error) {
+	resp, err := c.get("/collections/list")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-// ListEvents returns recent events from the daemon.
-func (c *Client) ListEvents(limit int, eventType string, includeAll bool) (*ListEventsResponse, error) {
-	path := fmt.Sprintf("/events/list?limit=%d", limit)
-	if eventType != "" {
-		path += "&type=" + eventType
+	var result ListCollectionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
 	}
-	if includeAll {
-		path += "&include_all=true"
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
 	}
+	return result.Collections, nil
+}
 
-	resp, err := c.get(path)
+// CreateCollection creates a vector collection.
+func (c *Client) CreateCollection(req CreateCollectionRequest) (*CollectionStats, error) {
+	resp, err := c.postIdempotent("/collections/create", uuid.NewString(), req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result ListEventsResponse
+	var result CollectionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
-	return &result, nil
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return result.Collection, nil
 }
 
-// ShareContext shares context content with the cluster and stores in vector DB.
-func (c *Client) ShareContext(filePath, content string, metadata map[string]any) (*ShareContextResponse, error) {
-	resp, err := c.post("/context/share", ShareContextRequest{
-		FilePath: filePath,
-		Content:  content,
-		Metadata: metadata,
-	})
+// SetCollectionTTL changes how long a collection keeps documents; an empty
+// ttl keeps them until deleted.
+func (c *Client) SetCollectionTTL(name, ttl string) (*CollectionStats, error) {
+	resp, err := c.postIdempotent("/collections/ttl", uuid.NewString(), CollectionTTLRequest{Name: name, TTL: ttl})
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result ShareContextResponse
+	var result CollectionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 	if result.Error != "" {
 		return nil, fmt.Errorf("%s", result.Error)
 	}
-	return &result, nil
+	return result.Collection, nil
 }
 
-// Shutdown shuts down the daemon.
-func (c *Client) Shutdown() error {
-	resp, err := c.post("/shutdown", nil)
+// DeleteCollection deletes a vector collection and its documents.
+func (c *Client) DeleteCollection(name string) error {
+	resp, err := c.postIdempotent("/collections/delete", uuid.NewString(), DeleteCollectionRequest{Name: name})
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	var result GenericResponse
-	json.NewDecoder(resp.Body).Decode(&result)
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.Error != "" {
+		return fmt.Errorf("%s", result.Error)
+	}
 	return nil
 }
 
-// Leave initiates graceful cluster leave.
-func (c *Client) Leave() (*LeaveResponse, error) {
-	resp, err := c.post("/leave", LeaveRequest{})
+// ListNegotiations returns the open lock negotiations, including those
+// escalated to a human.
+func (c *Client) ListNegotiations() ([]*lock.NegotiationSession, error) {
+	resp, err := c.get("/negotiations/list")
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result LeaveResponse
+	var result ListNegotiationsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 	if result.Error != "" {
-		return &result, fmt.Errorf("%s", result.Error)
+		return nil, fmt.Errorf("%s", result.Error)
 	}
-	return &result, nil
+	return result.Sessions, nil
 }
 
-// LeaveStatus returns the current leave process status.
-func (c *Client) LeaveStatus() (*LeaveStatusResponse, error) {
-	resp, err := c.get("/leave/status")
+// VoteNegotiation casts this node's vote in a lock negotiation.
+func (c *Client) VoteNegotiation(sessionID string, approve bool, reason string) error {
+	resp, err := c.postIdempotent("/negotiations/vote", uuid.NewString(), NegotiationVoteRequest{
+		SessionID: sessionID,
+		Approve:   approve,
+		Reason:    reason,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result GenericResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.Error != "" {
+		return fmt.Errorf("%s", result.Error)
+	}
+	return nil
+}
+
+// ProposeNegotiation proposes a resolution (yield, split, priority or
+// escalate) for a lock negotiation.
+func (c *Client) ProposeNegotiation(req NegotiationProposeRequest) (*lock.NegotiationResult, error) {
+	resp, err := c.postIdempotent("/negotiations/propose", uuid.NewString(), req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result LeaveStatusResponse
+	var result NegotiationProposeResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
-	return &result, nil
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return result.Result, nil
 }
 
-// TokenUsage returns token usage statistics.
-func (c *Client) TokenUsage() (*TokenUsageResponse, error) {
-	resp, err := c.get("/tokens/usage")
+// Embed generates embeddings for text.
+func (c *Client) Embed(text string) (*EmbedResponse, error) {
+	resp, err := c.post("/embed", EmbedRequest{Text: text})
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result TokenUsageResponse
+	var result EmbedResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-// ContextStats returns context and vector store statistics.
-func (c *Client) ContextStats() (*ContextStatsResponse, error) {
-	resp, err := c.get("/context/stats")
+// Search searches for similar content. Results scoring below minScore are
+// dropped; 0 keeps every positively similar result.
+func (c *Client) Search(query string, limit int, minScore float32) (*SearchResponse, error) {
+	return c.SearchWith(SearchRequest{Query: query, Limit: limit, MinScore: minScore})
+}
+
+// SearchWith searches shared context with every option of req, such as
+// the collection or git branch.
+func (c *Client) SearchWith(req SearchRequest) (*SearchResponse, error) {
+	resp, err := c.post("/search", req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result ContextStatsResponse
+	var result SearchResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
 	return &result, nil
 }
 
-// Metrics returns system and network metrics.
-func (c *Client) Metrics() (map[string]interface{}, error) {
-	resp, err := c.get("/metrics")
+// ListAgents returns connected agents.
+func (c *Client) ListAgents() (*ListAgentsResponse, error) {
+	resp, err := c.get("/agents/list")
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result map[string]interface{}
+	var result ListAgentsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
-	return result, nil
+	return &result, nil
 }
 
-// CheckCohesion checks if work aligns with existing team context.
-func (c *Client) CheckCohesion(checkType, intention, result string, filesChanged []string) (*CheckCohesionResponse, error) {
-	resp, err := c.post("/cohesion/check", CheckCohesionRequest{
-		Type:         checkType,
-		Intention:    intention,
-		Result:       result,
-		FilesChanged: filesChanged,
-	})
+// ListPeers returns connected peers.
+func (c *Client) ListPeers() (*ListPeersResponse, error) {
+	resp, err := c.get("/peers/list")
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var response CheckCohesionResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	var result ListPeersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
-	if response.Error != "" {
-		return nil, fmt.Errorf("%s", response.Error)
-	}
-	return &response, nil
+	return &result, nil
 }
 
-func (c *Client) get(path string) (*http.Response, error) {
-	return c.httpClient.Get("http://unix" + path)
-}
+// Topology returns the cluster topology graph as seen from the daemon's node.
+func (c *Client) Topology() (*TopologyResponse, error) {
+	resp, err := c.get("/topology")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result TopologyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// WatchFile starts watching a file.
+func (c *Client) WatchFile(filePath string) error {
+	resp, err := c.post("/context/watch", WatchFileRequest{FilePath: filePath})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result GenericResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.Error != "" {
+		return fmt.Errorf("%s", result.Error)
+	}
+	return nil
+}
+
+// ReportActiveEdit announces where this agent is editing. It returns the
+// other agents editing an overlapping region.
+func (c *Client) ReportActiveEdit(filePath string, startLine, endLine int) (*ReportActiveEditResponse, error) {
+	return c.reportActiveEdit(ReportActiveEditRequest{FilePath: filePath, StartLine: startLine, EndLine: endLine})
+}
+
+// ClearActiveEdit withdraws this agent's active edit on a file.
+func (c *Client) ClearActiveEdit(filePath string) error {
+	_, err := c.reportActiveEdit(ReportActiveEditRequest{FilePath: filePath, Clear: true})
+	return err
+}
+
+func (c *Client) reportActiveEdit(req ReportActiveEditRequest) (*ReportActiveEditResponse, error) {
+	resp, err := c.post("/presence/report", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ReportActiveEditResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// ActiveEdits returns the unexpired active edits, optionally for one file.
+func (c *Client) ActiveEdits(filePath string) (*ListActiveEditsResponse, error) {
+	path := "/presence/list"
+	if filePath != "" {
+		path += "?file_path=" + url.QueryEscape(filePath)
+	}
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ListActiveEditsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WorkMap returns each active agent's current locks and the files it
+// shared context about since the given time (zero for the last hour).
+func (c *Client) WorkMap(since time.Time) (*WorkMapResponse, error) {
+	path := "/work"
+	if !since.IsZero() {
+		path += "?since=" + url.QueryEscape(since.Format(time.RFC3339))
+	}
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result WorkMapResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// ConflictHeatmap returns files ranked by lock conflicts since the given time.
+// A zero time uses the daemon default (last 24 hours).
+func (c *Client) ConflictHeatmap(since time.Time) (*ConflictHeatmapResponse, error) {
+	path := "/conflicts/heatmap"
+	if !since.IsZero() {
+		path += "?since=" + url.QueryEscape(since.Format(time.RFC3339))
+	}
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ConflictHeatmapResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// ListEventsResponse is the response for listing events.
+type ListEventsResponse struct {
+	Events []Event `json:"events"`
+	Count  int     `json:"count"`
+}
+
+// ListEvents returns recent events from the daemon.
+func (c *Client) ListEvents(limit int, eventType string, includeAll bool) (*ListEventsResponse, error) {
+	return c.ListEventsByAgent(limit, eventType, "", includeAll)
+}
+
+// ListEventsByAgent returns recent events scoped to one agent ID or name.
+func (c *Client) ListEventsByAgent(limit int, eventType, agent string, includeAll bool) (*ListEventsResponse, error) {
+	path := fmt.Sprintf("/events/list?limit=%d", limit)
+	if eventType != "" {
+		path += "&type=" + eventType
+	}
+	if agent != "" {
+		path += "&agent=" + url.QueryEscape(agent)
+	}
+	if includeAll {
+		path += "&include_all=true"
+	}
+
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ListEventsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Digest returns a summary of cluster activity since the given time.
+func (c *Client) Digest(since time.Time, condense bool) (*DigestResponse, error) {
+	path := "/events/digest?since=" + url.QueryEscape(since.Format(time.RFC3339))
+	if condense {
+		path += "&condense=true"
+	}
+
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result DigestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// ShareContext shares context content with the cluster and stores in vector DB.
+func (c *Client) ShareContext(filePath, content string, metadata map[string]any) (*ShareContextResponse, error) {
+	return c.ShareDerivedContext(filePath, content, metadata, nil)
+}
+
+// ShareDerivedContext shares context derived from the given documents.
+func (c *Client) ShareDerivedContext(filePath, content string, metadata map[string]any, parentIDs []string) (*ShareContextResponse, error) {
+	resp, err := c.postIdempotent("/context/share", uuid.NewString(), ShareContextRequest{
+		FilePath:  filePath,
+		Content:   content,
+		Metadata:  metadata,
+		ParentIDs: parentIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ShareContextResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// Shutdown shuts down the daemon.
+func (c *Client) Shutdown() error {
+	resp, err := c.post("/shutdown", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result GenericResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	return nil
+}
+
+// Leave initiates graceful cluster leave.
+func (c *Client) Leave() (*LeaveResponse, error) {
+	resp, err := c.post("/leave", LeaveRequest{})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result LeaveResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return &result, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// LeaveStatus returns the current leave process status.
+func (c *Client) LeaveStatus() (*LeaveStatusResponse, error) {
+	resp, err := c.get("/leave/status")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result LeaveStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreateSnapshot writes a snapshot archive of the cluster state.
+func (c *Client) CreateSnapshot() (*application.SnapshotSummary, error) {
+	return c.withTimeout(SnapshotTimeout).snapshot("/snapshot/create", struct{}{})
+}
+
+// RestoreSnapshot merges the snapshot archive at path, on the daemon's
+// host, into the running node.
+func (c *Client) RestoreSnapshot(path string) (*application.SnapshotSummary, error) {
+	return c.withTimeout(SnapshotTimeout).snapshot("/snapshot/restore", RestoreSnapshotRequest{Path: path})
+}
+
+func (c *Client) snapshot(path string, req any) (*application.SnapshotSummary, error) {
+	resp, err := c.post(path, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SnapshotResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return result.Summary, fmt.Errorf("%s", result.Error)
+	}
+	return result.Summary, nil
+}
+
+// ExportCorpus writes the shared context corpus to path, on the daemon's
+// host. An empty collection exports every collection.
+func (c *Client) ExportCorpus(path, collection string) (*application.CorpusSummary, error) {
+	return c.withTimeout(SnapshotTimeout).corpus("/context/export", ExportCorpusRequest{Path: path, Collection: collection})
+}
+
+// ImportCorpus loads the corpus at path, on the daemon's host, into the
+// running node.
+func (c *Client) ImportCorpus(path string, reembed bool) (*application.CorpusSummary, error) {
+	return c.withTimeout(SnapshotTimeout).corpus("/context/import", ImportCorpusRequest{Path: path, Reembed: reembed})
+}
+
+func (c *Client) corpus(path string, req any) (*application.CorpusSummary, error) {
+	resp, err := c.post(path, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result CorpusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return result.Summary, fmt.Errorf("%s", result.Error)
+	}
+	return result.Summary, nil
+}
+
+// ReloadConfig makes the daemon re-read its configuration and apply the
+// settings that can change without a restart.
+func (c *Client) ReloadConfig() (*application.ReloadResult, error) {
+	resp, err := c.post("/config/reload", struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ReloadConfigResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return result.Result, nil
+}
+
+// TokenUsage returns token usage statistics.
+func (c *Client) TokenUsage() (*TokenUsageResponse, error) {
+	resp, err := c.get("/tokens/usage")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result TokenUsageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ContextStats returns context and vector store statistics.
+func (c *Client) ContextStats() (*ContextStatsResponse, error) {
+	resp, err := c.get("/context/stats")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ContextStatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetProvenance returns the provenance chain of a shared document.
+func (c *Client) GetProvenance(documentID string) (*ProvenanceResponse, error) {
+	resp, err := c.get("/context/provenance?document_id=" + url.QueryEscape(documentID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ProvenanceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// BanPeer bans a peer for duration (0 until it is unbanned). It needs
+// operator credentials, see SetOperator.
+func (c *Client) BanPeer(peerID, reason string, duration time.Duration) (*libp2p.Ban, error) {
+	req := BanPeerRequest{PeerID: peerID, Reason: reason}
+	if duration > 0 {
+		req.Duration = duration.String()
+	}
+	resp, err := c.postIdempotentWithHeader("/peers/ban", uuid.NewString(), req, c.operatorHeader())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result BanPeerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return result.Ban, nil
+}
+
+// UnbanPeer lifts a peer's ban. It needs operator credentials, see
+// SetOperator.
+func (c *Client) UnbanPeer(peerID string) error {
+	resp, err := c.postIdempotentWithHeader("/peers/unban", uuid.NewString(), UnbanPeerRequest{PeerID: peerID}, c.operatorHeader())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result GenericResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.Error != "" {
+		return fmt.Errorf("%s", result.Error)
+	}
+	return nil
+}
+
+// ListBans returns the peer bans in effect, oldest first.
+func (c *Client) ListBans() ([]*libp2p.Ban, error) {
+	resp, err := c.get("/peers/bans")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ListBansResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return result.Bans, nil
+}
+
+// ContextMerges returns the most recent merges of concurrent context
+// edits, newest first. An empty filePath returns merges for all files.
+func (c *Client) ContextMerges(filePath string, limit int) (*ContextMergesResponse, error) {
+	path := fmt.Sprintf("/context/merges?file=%s&limit=%d", url.QueryEscape(filePath), limit)
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ContextMergesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// SearchSymbols finds where a symbol is defined and who changed it across
+// the shared contexts.
+func (c *Client) SearchSymbols(q application.SymbolQuery) (*SymbolSearchResponse, error) {
+	resp, err := c.post("/search/symbols", q)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SymbolSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// RateContext records whether a search result was useful.
+func (c *Client) RateContext(documentID string, useful bool) (*RateContextResponse, error) {
+	resp, err := c.post("/context/rate", RateContextRequest{DocumentID: documentID, Useful: useful})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result RateContextResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &result, nil
+}
+
+// Metrics returns system and network metrics.
+func (c *Client) Metrics() (map[string]interface{}, error) {
+	resp, err := c.get("/metrics")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CheckCohesion checks if work aligns with existing team context.
+func (c *Client) CheckCohesion(checkType, intention, result string, filesChanged []string) (*CheckCohesionResponse, error) {
+	resp, err := c.post("/cohesion/check", CheckCohesionRequest{
+		Type:         checkType,
+		Intention:    intention,
+		Result:       result,
+		FilesChanged: filesChanged,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response CheckCohesionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s", response.Error)
+	}
+	return &response, nil
+}
+
+// operatorHeader returns the headers carrying the operator credentials.
+func (c *Client) operatorHeader() http.Header {
+	header := http.Header{}
+	header.Set(OperatorIDHeader, c.operatorID)
+	header.Set(OperatorTokenHeader, c.operatorToken)
+	return header
+}
+
+func (c *Client) get(path string) (*http.Response, error) {
+	return c.httpClient.Get("http://unix" + path)
+}
 
 func (c *Client) post(path string, body any) (*http.Response, error) {
 	var reader io.Reader
@@ -457,3 +1287,59 @@ func (c *Client) post(path string, body any) (*http.Response, error) {
 	}
 	return c.httpClient.Post("http://unix"+path, "application/json", reader)
 }
+
+// postIdempotent sends a POST carrying an idempotency key and retries it with
+// exponential backoff on transport errors or 5xx responses.
+// Without a key the request is sent once.
+func (c *Client) postIdempotent(path, key string, body any) (*http.Response, error) {
+	return c.postIdempotentWithHeader(path, key, body, nil)
+}
+
+// postIdempotentWithHeader is postIdempotent with extra request headers.
+func (c *Client) postIdempotentWithHeader(path, key string, body any, header http.Header) (*http.Response, error) {
+	if key == "" {
+		return c.post(path, body)
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := max(c.retry.MaxAttempts, 1)
+	backoff := c.retry.InitialBackoff
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+			if c.retry.MaxBackoff > 0 {
+				backoff = min(backoff, c.retry.MaxBackoff)
+			}
+		}
+
+		req, err := http.NewRequest(http.MethodPost, "http://unix"+path, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(IdempotencyKeyHeader, key)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("daemon returned %s", resp.Status)
+			continue
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,103 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MaxCoalesceWindow caps how long a stream may hold back state updates.
+const MaxCoalesceWindow = 10 * time.Second
+
+// WatchOptions tune one event stream. A client sends them as a JSON line
+// on the event socket; the daemon answers with an EventWatchOptions event
+// carrying the options in effect.
+type WatchOptions struct {
+	// Coalesce collapses repeated state updates for the same object within
+	// this window, e.g. "250ms", into the latest one. Empty or "0" sends
+	// every update as it happens.
+	Coalesce string `json:"coalesce,omitempty"`
+
+	// ReplaySince replays recent events at or after this point before
+	// live events: an RFC 3339 timestamp or a duration ago such as "30m".
+	ReplaySince string `json:"replay_since,omitempty"`
+	// ReplayLast replays at most this many recent events (all retained
+	// events if only ReplaySince is set).
+	ReplayLast int `json:"replay_last,omitempty"`
+}
+
+// CoalesceWindow parses the coalescing window.
+func (o WatchOptions) CoalesceWindow() (time.Duration, error) {
+	if o.Coalesce == "" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(o.Coalesce)
+	if err != nil {
+		return 0, fmt.Errorf("invalid coalesce window: %w", err)
+	}
+	if window < 0 || window > MaxCoalesceWindow {
+		return 0, fmt.Errorf("coalesce window must be between 0 and %s: %s", MaxCoalesceWindow, window)
+	}
+	return window, nil
+}
+
+// coalesceKey returns the object a state snapshot event describes. Only
+// snapshots supersede earlier events and may be collapsed; events saying
+// something happened (a lock acquired, an agent left, ...) are always
+// delivered.
+func coalesceKey(e Event) (string, bool) {
+	switch e.Type {
+	case EventStatusUpdated:
+		return string(e.Type), true
+	default:
+		return "", false
+	}
+}
+
+// coalescer holds back state updates for one stream, keeping the latest
+// per object in the order the objects first changed.
+type coalescer struct {
+	pending []Event
+	index   map[string]int
+}
+
+// add stores e as the latest state of key. It reports whether e is the
+// first update held since the last drain.
+func (c *coalescer) add(key string, e Event) bool {
+	if c.index == nil {
+		c.index = make(map[string]int)
+	}
+	if i, ok := c.index[key]; ok {
+		c.pending[i] = e
+		return false
+	}
+	c.index[key] = len(c.pending)
+	c.pending = append(c.pending, e)
+	return len(c.pending) == 1
+}
+
+// drain returns the held updates and empties the buffer.
+func (c *coalescer) drain() []Event {
+	events := c.pending
+	c.pending = nil
+	clear(c.index)
+	return events
+}
+
+// readWatchOptions forwards options sent by a stream client until the
+// connection closes.
+func readWatchOptions(r io.Reader, ch chan<- WatchOptions, done <-chan struct{}) {
+	decoder := json.NewDecoder(r)
+	for {
+		var opts WatchOptions
+		if err := decoder.Decode(&opts); err != nil {
+			return
+		}
+		select {
+		case ch <- opts:
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"agent-collab/src/infrastructure/storage/vector"
+)
+
+// ListCollectionsResponse lists the vector collections by name.
+type ListCollectionsResponse struct {
+	Collections []CollectionStats `json:"collections"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// CreateCollectionRequest creates a vector collection.
+type CreateCollectionRequest struct {
+	Name string `json:"name"`
+	// Dimension is the embedding dimension; 0 uses the embedding provider's
+	Dimension int `json:"dimension,omitempty"`
+	// TTL expires documents not updated for this long, e.g. "72h"
+	TTL string `json:"ttl,omitempty"`
+}
+
+// CollectionTTLRequest changes a collection's TTL; "0" or empty keeps
+// documents until they are deleted.
+type CollectionTTLRequest struct {
+	Name string `json:"name"`
+	TTL  string `json:"ttl"`
+}
+
+// DeleteCollectionRequest deletes a collection and its documents.
+type DeleteCollectionRequest struct {
+	Name string `json:"name"`
+}
+
+// CollectionResponse is the state of a collection after a change.
+type CollectionResponse struct {
+	Success    bool             `json:"success"`
+	Collection *CollectionStats `json:"collection,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// collectionStats converts vector store stats for the API.
+func collectionStats(stats *vector.CollectionStats) CollectionStats {
+	out := CollectionStats{
+		Name:      stats.Name,
+		Count:     stats.Count,
+		Dimension: stats.Dimension,
+		SizeBytes: stats.SizeBytes,
+	}
+	if stats.TTL > 0 {
+		out.TTL = stats.TTL.String()
+	}
+	return out
+}
+
+// parseCollectionTTL parses a TTL; empty means none.
+func parseCollectionTTL(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid ttl: %q", raw)
+	}
+	return ttl, nil
+}
+
+// handleListCollections handles the /collections/list endpoint.
+func (s *Server) handleListCollections(w http.ResponseWriter, _ *http.Request) {
+	collections, err := s.app.ListCollections()
+	if err != nil {
+		json.NewEncoder(w).Encode(ListCollectionsResponse{Error: err.Error()})
+		return
+	}
+
+	resp := ListCollectionsResponse{Collections: make([]CollectionStats, 0, len(collections))}
+	for _, stats := range collections {
+		resp.Collections = append(resp.Collections, collectionStats(stats))
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleCreateCollection handles the /collections/create endpoint.
+func (s *Server) handleCreateCollection(w http.ResponseWriter, r *http.Request) {
+	var req CreateCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(CollectionResponse{Error: err.Error()})
+		return
+	}
+	ttl, err := parseCollectionTTL(req.TTL)
+	if err != nil {
+		json.NewEncoder(w).Encode(CollectionResponse{Error: err.Error()})
+		return
+	}
+
+	stats, err := s.app.CreateCollection(req.Name, req.Dimension, ttl)
+	if err != nil {
+		json.NewEncoder(w).Encode(CollectionResponse{Error: err.Error()})
+		return
+	}
+	out := collectionStats(stats)
+	json.NewEncoder(w).Encode(CollectionResponse{Success: true, Collection: &out})
+}
+
+// handleCollectionTTL handles the /collections/ttl endpoint.
+func (s *Server) handleCollectionTTL(w http.ResponseWriter, r *http.Request) {
+	var req CollectionTTLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(CollectionResponse{Error: err.Error()})
+		return
+	}
+	ttl, err := parseCollectionTTL(req.TTL)
+	if err != nil {
+		json.NewEncoder(w).Encode(CollectionResponse{Error: err.Error()})
+		return
+	}
+
+	if err := s.app.SetCollectionTTL(req.Name, ttl); err != nil {
+		json.NewEncoder(w).Encode(CollectionResponse{Error: err.Error()})
+		return
+	}
+	resp := CollectionResponse{Success: true}
+	if stats, err := s.app.VectorStore().GetCollectionStats(req.Name); err == nil {
+		out := collectionStats(stats)
+		resp.Collection = &out
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleDeleteCollection handles the /collections/delete endpoint.
+func (s *Server) handleDeleteCollection(w http.ResponseWriter, r *http.Request) {
+	var req DeleteCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
+		return
+	}
+
+	if err := s.app.DeleteCollection(req.Name); err != nil {
+		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(GenericResponse{Success: true, Message: "Collection deleted"})
+}
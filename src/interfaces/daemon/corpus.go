@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"agent-collab/src/application"
+)
+
+// ExportCorpusRequest is a request to export the shared context corpus.
+type ExportCorpusRequest struct {
+	// Path is the file to write on the daemon's host.
+	Path string `json:"path"`
+
+	// Collection limits the export to one collection.
+	Collection string `json:"collection,omitempty"`
+}
+
+// ImportCorpusRequest is a request to import a shared context corpus.
+type ImportCorpusRequest struct {
+	// Path is the corpus on the daemon's host.
+	Path string `json:"path"`
+
+	// Reembed embeds every document again with the daemon's model.
+	Reembed bool `json:"reembed,omitempty"`
+}
+
+// CorpusResponse is the response to exporting or importing a corpus.
+type CorpusResponse struct {
+	Success bool                       `json:"success"`
+	Summary *application.CorpusSummary `json:"summary,omitempty"`
+	Error   string                     `json:"error,omitempty"`
+}
+
+// handleExportCorpus handles the /context/export endpoint.
+func (s *Server) handleExportCorpus(w http.ResponseWriter, r *http.Request) {
+	var req ExportCorpusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(CorpusResponse{Error: err.Error()})
+		return
+	}
+	if req.Path == "" {
+		json.NewEncoder(w).Encode(CorpusResponse{Error: "path is required"})
+		return
+	}
+
+	summary, err := s.app.ExportCorpus(req.Path, req.Collection)
+	if err != nil {
+		json.NewEncoder(w).Encode(CorpusResponse{Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(CorpusResponse{Success: true, Summary: summary})
+}
+
+// handleImportCorpus handles the /context/import endpoint.
+func (s *Server) handleImportCorpus(w http.ResponseWriter, r *http.Request) {
+	var req ImportCorpusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(CorpusResponse{Error: err.Error()})
+		return
+	}
+	if req.Path == "" {
+		json.NewEncoder(w).Encode(CorpusResponse{Error: "path is required"})
+		return
+	}
+
+	summary, err := s.app.ImportCorpus(r.Context(), req.Path, req.Reembed)
+	if err != nil {
+		json.NewEncoder(w).Encode(CorpusResponse{Summary: summary, Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(CorpusResponse{Success: true, Summary: summary})
+}
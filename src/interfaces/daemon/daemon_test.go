@@ -79,6 +79,11 @@ func TestDaemonStartStop(t *testing.T) {
 	if err := server.Stop(); err != nil {
 		t.Errorf("Failed to stop daemon: %v", err)
 	}
+	select {
+	case <-server.Done():
+	default:
+		t.Error("Done should be closed after stop")
+	}
 
 	// Wait for cleanup
 	time.Sleep(100 * time.Millisecond)
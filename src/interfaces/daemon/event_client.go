@@ -27,6 +27,11 @@ type EventClient struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
+	// Options sent to the daemon on every connect
+	watchOptions *WatchOptions
+	// Latest event seen, so a reconnect replays only what was missed
+	lastEventAt time.Time
+
 	// Reconnection settings
 	reconnectInterval time.Duration
 	maxReconnectDelay time.Duration
@@ -54,6 +59,10 @@ func (c *EventClient) Connect(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to connect to event socket: %w", err)
 	}
+	if err := c.sendWatchOptions(conn); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to send watch options: %w", err)
+	}
 
 	c.conn = conn
 	c.reader = bufio.NewReader(conn)
@@ -110,6 +119,10 @@ func (c *EventClient) connect() error {
 	if err != nil {
 		return err
 	}
+	if err := c.sendWatchOptions(conn); err != nil {
+		conn.Close()
+		return err
+	}
 
 	c.conn = conn
 	c.reader = bufio.NewReader(conn)
@@ -119,6 +132,41 @@ func (c *EventClient) connect() error {
 	return nil
 }
 
+// SetWatchOptions sets the stream options sent to the daemon when the
+// client connects.
+func (c *EventClient) SetWatchOptions(opts WatchOptions) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watchOptions = &opts
+}
+
+// updateWatchOptions changes the stream options sent on connect.
+func (c *EventClient) updateWatchOptions(update func(*WatchOptions)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var opts WatchOptions
+	if c.watchOptions != nil {
+		opts = *c.watchOptions
+	}
+	update(&opts)
+	c.watchOptions = &opts
+}
+
+// sendWatchOptions writes the configured options to a new connection
+// (must be called with c.mu held). After a reconnect a replay resumes
+// from the last event received instead of repeating it.
+func (c *EventClient) sendWatchOptions(conn net.Conn) error {
+	if c.watchOptions == nil {
+		return nil
+	}
+	opts := *c.watchOptions
+	if opts.WantsReplay() && !c.lastEventAt.IsZero() {
+		opts.ReplaySince = c.lastEventAt.Add(time.Nanosecond).Format(time.RFC3339Nano)
+	}
+	return json.NewEncoder(conn).Encode(opts)
+}
+
 // Close closes the connection.
 func (c *EventClient) Close() error {
 	c.mu.Lock()
@@ -171,6 +219,12 @@ func (c *EventClient) readLoop() {
 			return
 		}
 
+		c.mu.Lock()
+		if event.Timestamp.After(c.lastEventAt) {
+			c.lastEventAt = event.Timestamp
+		}
+		c.mu.Unlock()
+
 		select {
 		case c.eventCh <- event:
 		default:
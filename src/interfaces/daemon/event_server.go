@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -33,6 +34,11 @@ type EventServer struct {
 	running atomic.Bool
 	ctx     context.Context
 	cancel  context.CancelFunc
+
+	// Draining lets clients finish their streams before Stop
+	draining  chan struct{}
+	drainOnce sync.Once
+	wg        sync.WaitGroup
 }
 
 // NewEventServer creates a new event server.
@@ -41,6 +47,7 @@ func NewEventServer(bus *EventBus) *EventServer {
 		socketPath: DefaultEventSocketPath(),
 		bus:        bus,
 		clients:    make(map[string]net.Conn),
+		draining:   make(chan struct{}),
 	}
 }
 
@@ -108,6 +115,35 @@ func (s *EventServer) Stop() error {
 	return nil
 }
 
+// Drain stops accepting clients and lets connected ones finish: each is
+// sent the events already queued for it and a final shutdown event before
+// its stream is closed. It returns when all clients are done or ctx
+// expires; Stop closes any that remain.
+func (s *EventServer) Drain(ctx context.Context) error {
+	s.running.Store(false)
+
+	s.mu.Lock()
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	s.mu.Unlock()
+
+	s.drainOnce.Do(func() { close(s.draining) })
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // ClientCount returns the number of connected clients.
 func (s *EventServer) ClientCount() int {
 	s.clientsMu.RLock()
@@ -130,20 +166,22 @@ func (s *EventServer) acceptLoop() {
 		s.clients[clientID] = conn
 		s.clientsMu.Unlock()
 
+		s.wg.Add(1)
 		go s.handleClient(clientID, conn)
 	}
 }
 
 func (s *EventServer) handleClient(clientID string, conn net.Conn) {
 	defer func() {
+		s.wg.Done()
 		conn.Close()
 		s.clientsMu.Lock()
 		delete(s.clients, clientID)
 		s.clientsMu.Unlock()
 	}()
 
-	// Subscribe to events
-	eventCh := s.bus.Subscribe(clientID)
+	// Subscribe to events, keeping what came before for a replay
+	eventCh, backlog := s.bus.SubscribeWithHistory(clientID)
 	defer s.bus.Unsubscribe(clientID)
 
 	// Send ready event
@@ -153,18 +191,132 @@ func (s *EventServer) handleClient(clientID string, conn net.Conn) {
 		return
 	}
 
+	// Watch options sent by the client
+	done := make(chan struct{})
+	defer close(done)
+	optsCh := make(chan WatchOptions)
+	go readWatchOptions(conn, optsCh, done)
+
+	var (
+		window  time.Duration
+		held    coalescer
+		flushAt <-chan time.Time
+		// Live events wait for the options sent on connect
+		live      <-chan Event
+		liveAfter = time.After(initialOptionsGrace)
+	)
+	startLive := func() {
+		live, liveAfter, backlog = eventCh, nil, nil
+	}
+	flush := func() bool {
+		flushAt = nil
+		for _, event := range held.drain() {
+			if err := encoder.Encode(event); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+
 	// Stream events to client
 	for {
 		select {
 		case <-s.ctx.Done():
 			return
+		case <-s.draining:
+			if flush() {
+				finishStream(encoder, eventCh)
+			}
+			return
+		case <-liveAfter:
+			startLive()
+		case opts := <-optsCh:
+			w, err := opts.CoalesceWindow()
+			var replay []Event
+			if err == nil && opts.WantsReplay() {
+				if live != nil {
+					err = fmt.Errorf("replay must be requested when connecting")
+				} else {
+					replay, err = opts.ReplayEvents(backlog, time.Now())
+				}
+			}
+			if err != nil {
+				if encoder.Encode(NewEvent(EventError, map[string]string{"error": err.Error()})) != nil {
+					return
+				}
+				if live == nil {
+					startLive()
+				}
+				continue
+			}
+			window = w
+			if window == 0 && !flush() {
+				return
+			}
+			if encoder.Encode(NewEvent(EventWatchOptions, opts)) != nil {
+				return
+			}
+			if live == nil {
+				if opts.WantsReplay() {
+					for _, event := range replay {
+						if encoder.Encode(event) != nil {
+							return
+						}
+					}
+					if encoder.Encode(NewEvent(EventReplayDone, ReplayDoneData{Count: len(replay)})) != nil {
+						return
+					}
+				}
+				startLive()
+			}
+		case <-flushAt:
+			if !flush() {
+				return
+			}
+		case event, ok := <-live:
+			if !ok {
+				flush()
+				return
+			}
+			if key, ok := coalesceKey(event); ok && window > 0 {
+				if held.add(key, event) {
+					flushAt = time.After(window)
+				}
+				continue
+			}
+			// Held updates go out first so the stream keeps its order
+			if !flush() {
+				return
+			}
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+			if event.Type == EventDaemonShutdown {
+				return
+			}
+		}
+	}
+}
+
+// finishStream sends the events still queued for a client, then a final
+// shutdown event unless one was already among them.
+func finishStream(encoder *json.Encoder, eventCh <-chan Event) {
+	for {
+		select {
 		case event, ok := <-eventCh:
 			if !ok {
+				encoder.Encode(NewEvent(EventDaemonShutdown, nil))
 				return
 			}
 			if err := encoder.Encode(event); err != nil {
 				return
 			}
+			if event.Type == EventDaemonShutdown {
+				return
+			}
+		default:
+			encoder.Encode(NewEvent(EventDaemonShutdown, nil))
+			return
 		}
 	}
 }
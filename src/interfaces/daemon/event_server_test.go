@@ -0,0 +1,327 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func startTestEventServer(t *testing.T) (*EventBus, *EventServer) {
+	t.Helper()
+
+	bus := NewEventBus()
+	server := NewEventServer(bus)
+	// Keep it short to stay under UNIX socket path length limits
+	server.socketPath = filepath.Join(os.TempDir(), fmt.Sprintf("events-test-%d.sock", time.Now().UnixNano()%100000))
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start event server: %v", err)
+	}
+	t.Cleanup(func() { server.Stop() })
+	return bus, server
+}
+
+func readEvent(t *testing.T, scanner *bufio.Scanner) (Event, bool) {
+	t.Helper()
+	if !scanner.Scan() {
+		return Event{}, false
+	}
+	var e Event
+	if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+		t.Fatalf("bad event: %v", err)
+	}
+	return e, true
+}
+
+func TestEventServer_DrainSendsQueuedEventsAndCloseEvent(t *testing.T) {
+	bus, server := startTestEventServer(t)
+
+	conn, err := net.Dial("unix", server.socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	scanner := bufio.NewScanner(conn)
+
+	if e, ok := readEvent(t, scanner); !ok || e.Type != EventDaemonReady {
+		t.Fatalf("expected ready event, got %v", e.Type)
+	}
+
+	bus.Publish(NewEvent(EventLockAcquired, LockEventData{LockID: "lock-1"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := server.Drain(ctx); err != nil {
+		t.Fatalf("drain did not finish: %v", err)
+	}
+
+	var types []EventType
+	for {
+		e, ok := readEvent(t, scanner)
+		if !ok {
+			break
+		}
+		types = append(types, e.Type)
+	}
+
+	if len(types) != 2 || types[0] != EventLockAcquired || types[1] != EventDaemonShutdown {
+		t.Fatalf("expected queued event then shutdown before close, got %v", types)
+	}
+
+	// No new watchers are accepted while draining
+	if c, err := net.Dial("unix", server.socketPath); err == nil {
+		c.Close()
+		t.Error("new connections should be refused after drain")
+	}
+}
+
+func TestEventServer_DrainForwardsPublishedShutdownOnce(t *testing.T) {
+	bus, server := startTestEventServer(t)
+
+	conn, err := net.Dial("unix", server.socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	scanner := bufio.NewScanner(conn)
+	readEvent(t, scanner) // ready
+
+	// The daemon publishes its own shutdown event before draining
+	bus.Publish(NewEvent(EventDaemonShutdown, nil))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := server.Drain(ctx); err != nil {
+		t.Fatalf("drain did not finish: %v", err)
+	}
+
+	shutdowns := 0
+	for {
+		e, ok := readEvent(t, scanner)
+		if !ok {
+			break
+		}
+		if e.Type == EventDaemonShutdown {
+			shutdowns++
+		}
+	}
+	if shutdowns != 1 {
+		t.Errorf("expected exactly one shutdown event, got %d", shutdowns)
+	}
+}
+
+func TestEventServer_StreamsStatusUpdatesWithoutHistory(t *testing.T) {
+	bus, server := startTestEventServer(t)
+
+	conn, err := net.Dial("unix", server.socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	scanner := bufio.NewScanner(conn)
+
+	if e, ok := readEvent(t, scanner); !ok || e.Type != EventDaemonReady {
+		t.Fatalf("expected ready event, got %v", e.Type)
+	}
+
+	bus.Publish(NewEvent(EventStatusUpdated, StatusResponse{Running: true, LockCount: 2}))
+
+	e, ok := readEvent(t, scanner)
+	if !ok || e.Type != EventStatusUpdated {
+		t.Fatalf("expected status update, got %v", e.Type)
+	}
+	var status StatusResponse
+	if err := json.Unmarshal(e.Data, &status); err != nil || status.LockCount != 2 {
+		t.Errorf("unexpected status payload: %s (%v)", e.Data, err)
+	}
+
+	// Snapshots are streamed but do not crowd out recent events
+	if recent := bus.GetRecentEvents(0); len(recent) != 0 {
+		t.Errorf("status updates should not be kept in history, got %d events", len(recent))
+	}
+}
+
+// watchWithOptions connects to the event server and applies opts.
+func watchWithOptions(t *testing.T, server *EventServer, opts WatchOptions) *bufio.Scanner {
+	t.Helper()
+
+	conn, err := net.Dial("unix", server.socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	scanner := bufio.NewScanner(conn)
+	readEvent(t, scanner) // ready
+
+	if err := json.NewEncoder(conn).Encode(opts); err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := readEvent(t, scanner); !ok || e.Type != EventWatchOptions {
+		t.Fatalf("expected options to be acknowledged, got %v", e.Type)
+	}
+	return scanner
+}
+
+func TestEventServer_CoalescesRapidStatusUpdates(t *testing.T) {
+	bus, server := startTestEventServer(t)
+	scanner := watchWithOptions(t, server, WatchOptions{Coalesce: "500ms"})
+
+	for i := 1; i <= 3; i++ {
+		bus.Publish(NewEvent(EventStatusUpdated, StatusResponse{Running: true, LockCount: i}))
+	}
+	bus.Publish(NewEvent(EventAgentJoined, nil))
+
+	// The held update goes out before the event that followed it
+	e, ok := readEvent(t, scanner)
+	if !ok || e.Type != EventStatusUpdated {
+		t.Fatalf("expected coalesced status update, got %v", e.Type)
+	}
+	var status StatusResponse
+	if err := json.Unmarshal(e.Data, &status); err != nil || status.LockCount != 3 {
+		t.Errorf("expected final state, got %s (%v)", e.Data, err)
+	}
+	if e, ok := readEvent(t, scanner); !ok || e.Type != EventAgentJoined {
+		t.Fatalf("expected agent event next, got %v", e.Type)
+	}
+
+	// Without a following event the window flushes the latest update
+	bus.Publish(NewEvent(EventStatusUpdated, StatusResponse{LockCount: 4}))
+	bus.Publish(NewEvent(EventStatusUpdated, StatusResponse{LockCount: 5}))
+	e, ok = readEvent(t, scanner)
+	if !ok || e.Type != EventStatusUpdated {
+		t.Fatalf("expected flushed status update, got %v", e.Type)
+	}
+	if err := json.Unmarshal(e.Data, &status); err != nil || status.LockCount != 5 {
+		t.Errorf("expected final state after window, got %s (%v)", e.Data, err)
+	}
+}
+
+func TestEventServer_CoalescingKeepsLifecycleEvents(t *testing.T) {
+	bus, server := startTestEventServer(t)
+	scanner := watchWithOptions(t, server, WatchOptions{Coalesce: "1s"})
+
+	bus.Publish(NewEvent(EventLockAcquired, LockEventData{LockID: "lock-1"}))
+	bus.Publish(NewEvent(EventLockAcquired, LockEventData{LockID: "lock-2"}))
+	bus.Publish(NewEvent(EventLockReleased, LockEventData{LockID: "lock-1"}))
+
+	want := []EventType{EventLockAcquired, EventLockAcquired, EventLockReleased}
+	for i, typ := range want {
+		if e, ok := readEvent(t, scanner); !ok || e.Type != typ {
+			t.Fatalf("event %d: expected %s, got %v", i, typ, e.Type)
+		}
+	}
+}
+
+func TestWatchOptions_CoalesceWindow(t *testing.T) {
+	for _, bad := range []string{"soon", "-1s", "1m"} {
+		if _, err := (WatchOptions{Coalesce: bad}).CoalesceWindow(); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+	if w, err := (WatchOptions{}).CoalesceWindow(); err != nil || w != 0 {
+		t.Errorf("empty window should disable coalescing, got %v, %v", w, err)
+	}
+}
+
+func TestEventServer_ReplaysBacklogBeforeLiveEvents(t *testing.T) {
+	bus, server := startTestEventServer(t)
+	for _, id := range []string{"lock-1", "lock-2", "lock-3"} {
+		bus.Publish(NewEvent(EventLockAcquired, LockEventData{LockID: id}))
+	}
+
+	scanner := watchWithOptions(t, server, WatchOptions{ReplayLast: 2})
+	bus.Publish(NewEvent(EventLockReleased, LockEventData{LockID: "lock-2"}))
+
+	var got []string
+	for range 4 {
+		e, ok := readEvent(t, scanner)
+		if !ok {
+			t.Fatalf("stream ended early after %v", got)
+		}
+		switch e.Type {
+		case EventReplayDone:
+			var done ReplayDoneData
+			if err := json.Unmarshal(e.Data, &done); err != nil || done.Count != 2 {
+				t.Errorf("unexpected replay summary: %s", e.Data)
+			}
+			got = append(got, "done")
+		default:
+			var data LockEventData
+			json.Unmarshal(e.Data, &data)
+			got = append(got, string(e.Type)+" "+data.LockID)
+		}
+	}
+
+	want := []string{"lock.acquired lock-2", "lock.acquired lock-3", "done", "lock.released lock-2"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected backlog then live events %v, got %v", want, got)
+	}
+}
+
+func TestEventServer_ReplaySinceTimestamp(t *testing.T) {
+	bus, server := startTestEventServer(t)
+	bus.Publish(NewEvent(EventAgentJoined, nil))
+	cutoff := time.Now()
+	bus.Publish(NewEvent(EventLockAcquired, LockEventData{LockID: "lock-1"}))
+
+	scanner := watchWithOptions(t, server, WatchOptions{ReplaySince: cutoff.Format(time.RFC3339Nano)})
+	if e, ok := readEvent(t, scanner); !ok || e.Type != EventLockAcquired {
+		t.Fatalf("expected only the event after the cutoff, got %v", e.Type)
+	}
+	if e, ok := readEvent(t, scanner); !ok || e.Type != EventReplayDone {
+		t.Fatalf("expected end of replay, got %v", e.Type)
+	}
+}
+
+func TestEventServer_LiveEventsWithoutOptions(t *testing.T) {
+	bus, server := startTestEventServer(t)
+	bus.Publish(NewEvent(EventAgentJoined, nil))
+
+	conn, err := net.Dial("unix", server.socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	scanner := bufio.NewScanner(conn)
+	readEvent(t, scanner) // ready
+
+	// No backlog unless asked for; live events follow the grace period
+	bus.Publish(NewEvent(EventLockAcquired, LockEventData{LockID: "lock-1"}))
+	if e, ok := readEvent(t, scanner); !ok || e.Type != EventLockAcquired {
+		t.Fatalf("expected live event, got %v", e.Type)
+	}
+
+	// A replay asked for mid-stream would arrive out of order
+	json.NewEncoder(conn).Encode(WatchOptions{ReplayLast: 5})
+	if e, ok := readEvent(t, scanner); !ok || e.Type != EventError {
+		t.Fatalf("expected late replay to be rejected, got %v", e.Type)
+	}
+}
+
+func TestWatchOptions_ReplayEvents(t *testing.T) {
+	if _, err := (WatchOptions{ReplaySince: "yesterday"}).ReplayEvents(nil, time.Now()); err == nil {
+		t.Error("expected invalid since to be rejected")
+	}
+	if _, err := (WatchOptions{ReplayLast: -1}).ReplayEvents(nil, time.Now()); err == nil {
+		t.Error("expected negative count to be rejected")
+	}
+
+	now := time.Now()
+	history := []Event{
+		{Type: EventAgentJoined, Timestamp: now.Add(-time.Hour)},
+		{Type: EventLockAcquired, Timestamp: now.Add(-10 * time.Minute)},
+	}
+	events, err := (WatchOptions{ReplaySince: "30m"}).ReplayEvents(history, now)
+	if err != nil || len(events) != 1 || events[0].Type != EventLockAcquired {
+		t.Errorf("expected the event within 30m, got %v (%v)", events, err)
+	}
+}
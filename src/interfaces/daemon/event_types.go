@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -15,6 +16,23 @@ const (
 	EventLockConflict EventType = "lock.conflict"
 	EventLockExpired  EventType = "lock.expired"
 
+	EventLockForceReleased EventType = "lock.force_released"
+	EventLockIdleWarning   EventType = "lock.idle_warning"
+	EventLockIdleReleased  EventType = "lock.idle_released"
+	EventLockRenewalDue    EventType = "lock.renewal_due"
+	// EventLockRenewed carries the lock.SemanticLock with its new lease.
+	EventLockRenewed    EventType = "lock.renewed"
+	EventLockReconciled EventType = "lock.reconciled"
+	// EventLockUpgraded carries the LockEventData of a read lock that
+	// became a write lock.
+	EventLockUpgraded EventType = "lock.upgraded"
+	// EventLockConflictPredicted carries a lock.ConflictHint for a lock
+	// taken on a file with heavy recent contention.
+	EventLockConflictPredicted EventType = "lock.conflict_predicted"
+	// EventNegotiationResolved carries the lock.NegotiationSession a
+	// proposal made through the daemon resolved, escalated or settled.
+	EventNegotiationResolved EventType = "lock.negotiation_resolved"
+
 	// Agent events
 	EventAgentJoined EventType = "agent.joined"
 	EventAgentLeft   EventType = "agent.left"
@@ -26,15 +44,36 @@ const (
 	// Peer events
 	EventPeerConnected    EventType = "peer.connected"
 	EventPeerDisconnected EventType = "peer.disconnected"
+	// EventPeerBanned carries the libp2p.Ban of a peer banned by an
+	// operator or for misbehaving.
+	EventPeerBanned   EventType = "peer.banned"
+	EventPeerUnbanned EventType = "peer.unbanned"
+
+	// Partition events
+	EventPartitionDetected EventType = "partition.detected"
+	EventPartitionHealed   EventType = "partition.healed"
 
 	// System events
 	EventDaemonReady    EventType = "daemon.ready"
 	EventDaemonShutdown EventType = "daemon.shutdown"
+	// EventDaemonRecovered carries the RecoveredData of a daemon a
+	// supervisor restarted after a crash.
+	EventDaemonRecovered EventType = "daemon.recovered"
+	EventStatusUpdated   EventType = "status.updated"
+	EventWatchOptions    EventType = "watch.options"
+	EventReplayDone      EventType = "replay.done"
+	// EventConfigReloaded carries the application.ReloadResult of a
+	// configuration reload that changed settings.
+	EventConfigReloaded EventType = "config.reloaded"
 
 	// Interest events
 	EventInterestRegistered   EventType = "interest.registered"
 	EventInterestUnregistered EventType = "interest.unregistered"
 
+	// Token events
+	// EventBudgetExceeded carries the token.BudgetExceeded this node spent.
+	EventBudgetExceeded EventType = "budget.exceeded"
+
 	// Warning/Error events
 	EventWarning EventType = "warning"
 	EventError   EventType = "error"
@@ -60,6 +99,31 @@ func NewEvent(eventType EventType, data any) Event {
 	}
 }
 
+// MatchesAgent reports whether the event was produced by or concerns the
+// given agent, matched against agent IDs and names in the event data.
+func (e Event) MatchesAgent(agent string) bool {
+	if agent == "" {
+		return true
+	}
+
+	var fields struct {
+		AgentID     string `json:"agent_id"`
+		Name        string `json:"name"`
+		HolderID    string `json:"holder_id"`
+		RequesterID string `json:"requester_id"`
+	}
+	if err := json.Unmarshal(e.Data, &fields); err != nil {
+		return false
+	}
+
+	for _, v := range []string{fields.AgentID, fields.HolderID, fields.RequesterID} {
+		if v == agent {
+			return true
+		}
+	}
+	return fields.Name != "" && strings.EqualFold(fields.Name, agent)
+}
+
 // LockEventData contains data for lock-related events.
 type LockEventData struct {
 	LockID    string `json:"lock_id,omitempty"`
@@ -68,6 +132,7 @@ type LockEventData struct {
 	EndLine   int    `json:"end_line,omitempty"`
 	AgentID   string `json:"agent_id"`
 	Intention string `json:"intention,omitempty"`
+	Mode      string `json:"mode,omitempty"`
 }
 
 // LockConflictData contains data for lock conflict events.
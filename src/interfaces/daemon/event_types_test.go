@@ -0,0 +1,57 @@
+package daemon
+
+import "testing"
+
+func TestEvent_MatchesAgent(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		agent string
+		want  bool
+	}{
+		{"lock by agent", NewEvent(EventLockAcquired, LockEventData{AgentID: "agent-a"}), "agent-a", true},
+		{"lock by other", NewEvent(EventLockAcquired, LockEventData{AgentID: "agent-b"}), "agent-a", false},
+		{"conflict requester", NewEvent(EventLockConflict, LockConflictData{RequesterID: "agent-a", HolderID: "agent-b"}), "agent-a", true},
+		{"agent name", NewEvent(EventAgentJoined, AgentEventData{AgentID: "id-1", Name: "Alice"}), "alice", true},
+		{"no data", NewEvent(EventDaemonReady, nil), "agent-a", false},
+		{"empty filter", NewEvent(EventDaemonReady, nil), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.MatchesAgent(tt.agent); got != tt.want {
+				t.Errorf("MatchesAgent(%q) = %v, want %v", tt.agent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_ListBusEvents_FilterByAgent(t *testing.T) {
+	s := &Server{eventBus: NewEventBus()}
+
+	s.eventBus.Publish(NewEvent(EventLockAcquired, LockEventData{FilePath: "a.go", AgentID: "agent-a"}))
+	s.eventBus.Publish(NewEvent(EventContextUpdated, ContextEventData{FilePath: "b.go", AgentID: "agent-b"}))
+	s.eventBus.Publish(NewEvent(EventLockReleased, LockEventData{FilePath: "a.go", AgentID: "agent-a"}))
+	s.eventBus.Publish(NewEvent(EventPeerConnected, PeerEventData{PeerID: "peer-1"}))
+	s.eventBus.Publish(NewEvent(EventContextUpdated, ContextEventData{FilePath: "c.go", AgentID: "agent-a"}))
+
+	events := s.listBusEvents("", "agent-a", 10)
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events for agent-a, got %d", len(events))
+	}
+	for _, e := range events {
+		if !e.MatchesAgent("agent-a") {
+			t.Errorf("unexpected event in agent-a feed: %s", e.Type)
+		}
+	}
+
+	typed := s.listBusEvents(string(EventContextUpdated), "agent-a", 10)
+	if len(typed) != 1 {
+		t.Errorf("expected 1 context event for agent-a, got %d", len(typed))
+	}
+
+	limited := s.listBusEvents("", "agent-a", 1)
+	if len(limited) != 1 || limited[0].Type != EventContextUpdated {
+		t.Errorf("expected agent-a's most recent event, got %+v", limited)
+	}
+}
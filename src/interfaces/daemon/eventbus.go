@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"sync"
+	"time"
 )
 
 // EventBus is a simple publish-subscribe event bus.
@@ -40,6 +41,22 @@ func (eb *EventBus) Subscribe(clientID string) <-chan Event {
 	return ch
 }
 
+// SubscribeWithHistory subscribes like Subscribe and returns the events
+// published before the subscription, so a client can replay them without
+// gaps or duplicates.
+func (eb *EventBus) SubscribeWithHistory(clientID string) (<-chan Event, []Event) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if existing, ok := eb.subscribers[clientID]; ok {
+		close(existing)
+	}
+
+	ch := make(chan Event, eb.bufferSize)
+	eb.subscribers[clientID] = ch
+	return ch, append([]Event(nil), eb.history...)
+}
+
 // Unsubscribe removes a subscription.
 func (eb *EventBus) Unsubscribe(clientID string) {
 	eb.mu.Lock()
@@ -57,10 +74,12 @@ func (eb *EventBus) Publish(event Event) {
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
-	// Store in history
-	eb.history = append(eb.history, event)
-	if len(eb.history) > eb.maxHistory {
-		eb.history = eb.history[1:]
+	// Store in history; status snapshots are state, not history
+	if event.Type != EventStatusUpdated {
+		eb.history = append(eb.history, event)
+		if len(eb.history) > eb.maxHistory {
+			eb.history = eb.history[1:]
+		}
 	}
 
 	// Notify subscribers
@@ -114,6 +133,20 @@ func (eb *EventBus) GetEventsByType(eventType EventType, limit int) []Event {
 	return result
 }
 
+// GetEventsSince returns events at or after since in chronological order.
+func (eb *EventBus) GetEventsSince(since time.Time) []Event {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	result := make([]Event, 0)
+	for _, e := range eb.history {
+		if !e.Timestamp.Before(since) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
 // SubscriberCount returns the number of active subscribers.
 func (eb *EventBus) SubscriberCount() int {
 	eb.mu.RLock()
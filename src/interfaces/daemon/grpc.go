@@ -0,0 +1,510 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"agent-collab/src/application"
+	"agent-collab/src/domain/agent"
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/domain/token"
+	"agent-collab/src/interfaces/daemon/daemonpb"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultGRPCSocketPath returns the default Unix socket path for the gRPC API.
+func DefaultGRPCSocketPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".agent-collab", "grpc.sock")
+}
+
+// grpcService serves the daemon API over gRPC. It goes through the same
+// Server methods as the HTTP handlers, so both APIs publish the same
+// events.
+type grpcService struct {
+	daemonpb.UnimplementedDaemonServer
+	s *Server
+}
+
+// startGRPC listens on the gRPC socket and serves in the background.
+func (s *Server) startGRPC() error {
+	// Remove existing socket
+	os.Remove(s.grpcSocketPath)
+
+	listener, err := net.Listen("unix", s.grpcSocketPath)
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC socket: %w", err)
+	}
+	os.Chmod(s.grpcSocketPath, 0600)
+
+	s.grpcServer = grpc.NewServer()
+	daemonpb.RegisterDaemonServer(s.grpcServer, &grpcService{s: s})
+
+	go func() {
+		if err := s.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			fmt.Fprintf(os.Stderr, "gRPC server error: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// stopGRPC ends open watch streams and waits for in-flight calls until ctx
+// expires, then closes whatever remains.
+func (s *Server) stopGRPC(ctx context.Context) {
+	if s.grpcServer == nil {
+		return
+	}
+	s.grpcDrainOnce.Do(func() { close(s.grpcDraining) })
+
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+	}
+	os.Remove(s.grpcSocketPath)
+}
+
+// grpcError maps a daemon error onto a gRPC status.
+func grpcError(err error) error {
+	if retryAfter, ok := lock.RetryAfterHint(err); ok {
+		st, detailErr := status.New(codes.ResourceExhausted, err.Error()).
+			WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+		if detailErr != nil {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return st.Err()
+	}
+
+	code := codes.Unknown
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, errLockServiceUnavailable), errors.Is(err, errSearchUnavailable):
+		code = codes.Unavailable
+	case errors.Is(err, errContentRequired), errors.Is(err, lock.ErrInvalidTarget), errors.Is(err, lock.ErrIntentionRequired):
+		code = codes.InvalidArgument
+	case errors.Is(err, lock.ErrLockNotFound):
+		code = codes.NotFound
+	case errors.Is(err, lock.ErrNotLockHolder), errors.Is(err, lock.ErrObserverMode), errors.Is(err, application.ErrObserverMode),
+		errors.Is(err, application.ErrPermissionDenied):
+		code = codes.PermissionDenied
+	case errors.Is(err, lock.ErrLockExpired), errors.Is(err, lock.ErrMaxRenewalsExceeded),
+		errors.Is(err, lock.ErrMaxLeaseExceeded), errors.Is(err, lock.ErrStaleFencingToken):
+		code = codes.FailedPrecondition
+	case errors.Is(err, token.ErrBudgetExceeded):
+		code = codes.ResourceExhausted
+	}
+	return status.Error(code, err.Error())
+}
+
+func (g *grpcService) Status(ctx context.Context, _ *daemonpb.StatusRequest) (*daemonpb.StatusResponse, error) {
+	return statusToProto(g.s.statusResponse(g.s.app.GetStatus())), nil
+}
+
+func (g *grpcService) Health(ctx context.Context, req *daemonpb.HealthRequest) (*daemonpb.HealthResponse, error) {
+	health := g.s.health(ctx, req.GetVerbose())
+	resp := &daemonpb.HealthResponse{Ready: health.Ready, Running: health.Running}
+	for _, c := range health.Checks {
+		resp.Checks = append(resp.Checks, &daemonpb.ReadinessCheck{Name: c.Name, Ready: c.Ready, Message: c.Message})
+	}
+	for _, sub := range health.Subsystems {
+		resp.Subsystems = append(resp.Subsystems, &daemonpb.SubsystemHealth{
+			Name:        sub.Name,
+			State:       sub.State,
+			LatencyMs:   sub.LatencyMs,
+			LastSuccess: timestampOrNil(sub.LastSuccess),
+			Message:     sub.Message,
+		})
+	}
+	return resp, nil
+}
+
+func (g *grpcService) AcquireLock(ctx context.Context, req *daemonpb.AcquireLockRequest) (*daemonpb.AcquireLockResponse, error) {
+	lockReq := LockRequest{
+		FilePath:   req.GetFilePath(),
+		StartLine:  int(req.GetStartLine()),
+		EndLine:    int(req.GetEndLine()),
+		Intention:  req.GetIntention(),
+		TTLSeconds: int(req.GetTtlSeconds()),
+
+		WaitForLock:        req.GetWaitForLock(),
+		WaitTimeoutSeconds: int(req.GetWaitTimeoutSeconds()),
+		Priority:           int(req.GetPriority()),
+	}
+	if req.GetDeadline() != nil {
+		lockReq.Deadline = req.GetDeadline().AsTime()
+	}
+
+	result, err := g.s.acquireLock(ctx, lockReq)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &daemonpb.AcquireLockResponse{
+		Granted:      result.Success,
+		Reason:       result.Reason,
+		ConflictHint: conflictHintToProto(result.ConflictHint),
+	}
+	if result.Lock != nil {
+		resp.Lock = lockToProto(result.Lock)
+		if result.Promoted {
+			resp.PromotedTo = result.Lock.Target.String()
+		}
+	}
+	return resp, nil
+}
+
+func (g *grpcService) ReleaseLock(ctx context.Context, req *daemonpb.ReleaseLockRequest) (*daemonpb.ReleaseLockResponse, error) {
+	if err := g.s.releaseLock(req.GetLockId()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &daemonpb.ReleaseLockResponse{}, nil
+}
+
+func (g *grpcService) RenewLock(ctx context.Context, req *daemonpb.RenewLockRequest) (*daemonpb.RenewLockResponse, error) {
+	renewed, err := g.s.renewLock(RenewLockRequest{
+		LockID:       req.GetLockId(),
+		FencingToken: req.GetFencingToken(),
+		TTLSeconds:   int(req.GetTtlSeconds()),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &daemonpb.RenewLockResponse{Lock: lockToProto(renewed)}, nil
+}
+
+func (g *grpcService) ListLocks(ctx context.Context, _ *daemonpb.ListLocksRequest) (*daemonpb.ListLocksResponse, error) {
+	resp := &daemonpb.ListLocksResponse{}
+	if lockService := g.s.app.LockService(); lockService != nil {
+		for _, l := range lockService.ListLocks() {
+			resp.Locks = append(resp.Locks, lockToProto(l))
+		}
+	}
+	return resp, nil
+}
+
+func (g *grpcService) ShareContext(ctx context.Context, req *daemonpb.ShareContextRequest) (*daemonpb.ShareContextResponse, error) {
+	var metadata map[string]any
+	if len(req.GetMetadata()) > 0 {
+		metadata = make(map[string]any, len(req.GetMetadata()))
+		for k, v := range req.GetMetadata() {
+			metadata[k] = v
+		}
+	}
+
+	result, err := g.s.shareContext(ShareContextRequest{
+		FilePath:  req.GetFilePath(),
+		Content:   req.GetContent(),
+		Metadata:  metadata,
+		ParentIDs: req.GetParentIds(),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &daemonpb.ShareContextResponse{
+		DocumentId: result.DocumentID,
+		Duplicate:  result.Duplicate,
+		Message:    result.Message,
+	}, nil
+}
+
+func (g *grpcService) SearchContext(ctx context.Context, req *daemonpb.SearchContextRequest) (*daemonpb.SearchContextResponse, error) {
+	results, err := g.s.search(SearchRequest{
+		Query:    req.GetQuery(),
+		Limit:    int(req.GetLimit()),
+		MinScore: req.GetMinScore(),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &daemonpb.SearchContextResponse{}
+	for _, r := range results {
+		result := &daemonpb.SearchResult{Id: r.ID, Content: r.Content, Score: r.Score}
+		if len(r.Metadata) > 0 {
+			result.MetadataJson, _ = json.Marshal(r.Metadata)
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+func (g *grpcService) ListAgents(ctx context.Context, _ *daemonpb.ListAgentsRequest) (*daemonpb.ListAgentsResponse, error) {
+	resp := &daemonpb.ListAgentsResponse{}
+	if registry := g.s.app.AgentRegistry(); registry != nil {
+		for _, a := range registry.List() {
+			resp.Agents = append(resp.Agents, agentToProto(a))
+		}
+	}
+	return resp, nil
+}
+
+// WatchEvents streams events like the events socket does, with the watch
+// options given up front.
+func (g *grpcService) WatchEvents(req *daemonpb.WatchEventsRequest, stream grpc.ServerStreamingServer[daemonpb.Event]) error {
+	opts := WatchOptions{
+		Coalesce:    req.GetCoalesce(),
+		ReplaySince: req.GetReplaySince(),
+		ReplayLast:  int(req.GetReplayLast()),
+	}
+	window, err := opts.CoalesceWindow()
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Subscribe to events, keeping what came before for a replay
+	clientID := "grpc-" + uuid.New().String()
+	eventCh, backlog := g.s.eventBus.SubscribeWithHistory(clientID)
+	defer g.s.eventBus.Unsubscribe(clientID)
+
+	var replay []Event
+	if opts.WantsReplay() {
+		if replay, err = opts.ReplayEvents(backlog, time.Now()); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	send := func(e Event) error {
+		return stream.Send(eventToProto(e))
+	}
+	// Stream framing events are sent whatever the type filter says
+	if err := send(NewEvent(EventDaemonReady, nil)); err != nil {
+		return err
+	}
+	if err := send(NewEvent(EventWatchOptions, opts)); err != nil {
+		return err
+	}
+	if opts.WantsReplay() {
+		for _, event := range replay {
+			if !matchesEventTypes(req.GetTypes(), event.Type) {
+				continue
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+		if err := send(NewEvent(EventReplayDone, ReplayDoneData{Count: len(replay)})); err != nil {
+			return err
+		}
+	}
+
+	var (
+		held    coalescer
+		flushAt <-chan time.Time
+	)
+	flush := func() error {
+		flushAt = nil
+		for _, event := range held.drain() {
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-g.s.grpcDraining:
+			if err := flush(); err != nil {
+				return err
+			}
+			return g.finishWatch(req.GetTypes(), eventCh, send)
+		case <-flushAt:
+			if err := flush(); err != nil {
+				return err
+			}
+		case event, ok := <-eventCh:
+			if !ok {
+				return flush()
+			}
+			if event.Type != EventDaemonShutdown && !matchesEventTypes(req.GetTypes(), event.Type) {
+				continue
+			}
+			if key, ok := coalesceKey(event); ok && window > 0 {
+				if held.add(key, event) {
+					flushAt = time.After(window)
+				}
+				continue
+			}
+			// Held updates go out first so the stream keeps its order
+			if err := flush(); err != nil {
+				return err
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+			if event.Type == EventDaemonShutdown {
+				return nil
+			}
+		}
+	}
+}
+
+// finishWatch sends the events still queued for a watch stream, then a
+// final shutdown event unless one was already among them.
+func (g *grpcService) finishWatch(types []string, eventCh <-chan Event, send func(Event) error) error {
+	for {
+		select {
+		case event, ok := <-eventCh:
+			if !ok {
+				return send(NewEvent(EventDaemonShutdown, nil))
+			}
+			if event.Type != EventDaemonShutdown && !matchesEventTypes(types, event.Type) {
+				continue
+			}
+			if err := send(event); err != nil || event.Type == EventDaemonShutdown {
+				return err
+			}
+		default:
+			return send(NewEvent(EventDaemonShutdown, nil))
+		}
+	}
+}
+
+// WatchStatus streams the current status and every change to it.
+func (g *grpcService) WatchStatus(_ *daemonpb.WatchStatusRequest, stream grpc.ServerStreamingServer[daemonpb.StatusResponse]) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-g.s.grpcDraining:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for st := range g.s.app.WatchStatus(ctx) {
+		if err := stream.Send(statusToProto(g.s.statusResponse(st))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchesEventTypes reports whether an event type passes a watch filter.
+// An entry ending in ".*" matches every type in its category; an empty
+// filter matches everything.
+func matchesEventTypes(patterns []string, eventType EventType) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if category, ok := strings.CutSuffix(pattern, ".*"); ok {
+			if strings.HasPrefix(string(eventType), category+".") {
+				return true
+			}
+		} else if string(eventType) == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func timestampOrNil(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func statusToProto(st StatusResponse) *daemonpb.StatusResponse {
+	return &daemonpb.StatusResponse{
+		Running:           st.Running,
+		Pid:               int32(st.PID),
+		StartedAt:         timestampOrNil(st.StartedAt),
+		ProjectName:       st.ProjectName,
+		NodeId:            st.NodeID,
+		PeerCount:         int32(st.PeerCount),
+		LockCount:         int32(st.LockCount),
+		AgentCount:        int32(st.AgentCount),
+		EmbeddingProvider: st.EmbeddingProvider,
+		EventSubscribers:  int32(st.EventSubscribers),
+		Observer:          st.Observer,
+	}
+}
+
+func lockToProto(l *lock.SemanticLock) *daemonpb.Lock {
+	if l == nil {
+		return nil
+	}
+	resp := &daemonpb.Lock{
+		Id:           l.ID,
+		HolderId:     l.HolderID,
+		HolderName:   l.HolderName,
+		Intention:    l.Intention,
+		FencingToken: l.FencingToken,
+		AcquiredAt:   timestampOrNil(l.AcquiredAt),
+		ExpiresAt:    timestampOrNil(l.ExpiresAt),
+		RenewCount:   int32(l.RenewCount),
+	}
+	if t := l.Target; t != nil {
+		resp.Target = &daemonpb.Target{
+			Type:      string(t.Type),
+			FilePath:  t.FilePath,
+			Name:      t.Name,
+			StartLine: int32(t.StartLine),
+			EndLine:   int32(t.EndLine),
+		}
+	}
+	return resp
+}
+
+func conflictHintToProto(h *lock.ConflictHint) *daemonpb.ConflictHint {
+	if h == nil {
+		return nil
+	}
+	return &daemonpb.ConflictHint{
+		FilePath:     h.FilePath,
+		Likelihood:   string(h.Likelihood),
+		Score:        int32(h.Score),
+		Acquisitions: int32(h.Acquisitions),
+		Conflicts:    int32(h.Conflicts),
+		ActiveLocks:  int32(h.ActiveLocks),
+		Agents:       h.Agents,
+		Window:       h.Window,
+	}
+}
+
+func agentToProto(a *agent.ConnectedAgent) *daemonpb.Agent {
+	return &daemonpb.Agent{
+		Id:          a.Info.ID,
+		Name:        a.Info.Name,
+		Provider:    string(a.Info.Provider),
+		Model:       a.Info.Model,
+		PeerId:      a.PeerID,
+		Status:      string(a.Status),
+		ConnectedAt: timestampOrNil(a.ConnectedAt),
+		LastSeenAt:  timestampOrNil(a.LastSeenAt),
+	}
+}
+
+func eventToProto(e Event) *daemonpb.Event {
+	return &daemonpb.Event{
+		Type:      string(e.Type),
+		Timestamp: timestamppb.New(e.Timestamp),
+		Data:      e.Data,
+	}
+}
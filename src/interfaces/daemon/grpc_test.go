@@ -0,0 +1,105 @@
+package daemon_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"agent-collab/src/application"
+	"agent-collab/src/interfaces/daemon"
+	"agent-collab/src/interfaces/daemon/daemonpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestDaemonGRPC_LocksAndWatch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	app, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(ctx, "grpc-test-project"); err != nil {
+		t.Fatalf("Failed to initialize app: %v", err)
+	}
+	server := daemon.NewServer(app)
+	if err := server.Start(ctx); err != nil {
+		t.Fatalf("Failed to start daemon: %v", err)
+	}
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("unix://"+daemon.DefaultGRPCSocketPath(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial gRPC socket: %v", err)
+	}
+	defer conn.Close()
+	client := daemonpb.NewDaemonClient(conn)
+
+	st, err := client.Status(ctx, &daemonpb.StatusRequest{})
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if st.GetProjectName() != "grpc-test-project" || !st.GetRunning() {
+		t.Errorf("unexpected status: %v", st)
+	}
+
+	// Only lock events, so status updates do not get in the way
+	watch, err := client.WatchEvents(ctx, &daemonpb.WatchEventsRequest{Types: []string{"lock.*"}})
+	if err != nil {
+		t.Fatalf("WatchEvents: %v", err)
+	}
+	for _, want := range []daemon.EventType{daemon.EventDaemonReady, daemon.EventWatchOptions} {
+		event, err := watch.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if event.GetType() != string(want) {
+			t.Fatalf("expected %s, got %s", want, event.GetType())
+		}
+	}
+
+	acquired, err := client.AcquireLock(ctx, &daemonpb.AcquireLockRequest{
+		FilePath:  "auth/login.go",
+		StartLine: 10,
+		EndLine:   20,
+		Intention: "add rate limiting",
+	})
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+	if !acquired.GetGranted() || acquired.GetLock().GetTarget().GetFilePath() != "auth/login.go" {
+		t.Fatalf("expected the lock to be granted, got %v", acquired)
+	}
+
+	event, err := watch.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if event.GetType() != string(daemon.EventLockAcquired) {
+		t.Errorf("expected %s, got %s", daemon.EventLockAcquired, event.GetType())
+	}
+
+	locks, err := client.ListLocks(ctx, &daemonpb.ListLocksRequest{})
+	if err != nil {
+		t.Fatalf("ListLocks: %v", err)
+	}
+	if len(locks.GetLocks()) != 1 || locks.GetLocks()[0].GetId() != acquired.GetLock().GetId() {
+		t.Errorf("expected the acquired lock to be listed, got %v", locks.GetLocks())
+	}
+
+	if _, err := client.ReleaseLock(ctx, &daemonpb.ReleaseLockRequest{LockId: acquired.GetLock().GetId()}); err != nil {
+		t.Fatalf("ReleaseLock: %v", err)
+	}
+	if _, err := client.ReleaseLock(ctx, &daemonpb.ReleaseLockRequest{LockId: "lock-missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound releasing an unknown lock, got %v", err)
+	}
+
+	if _, err := client.ShareContext(ctx, &daemonpb.ShareContextRequest{FilePath: "auth/login.go"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument sharing empty content, got %v", err)
+	}
+}
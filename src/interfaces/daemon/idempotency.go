@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader carries a client-chosen key that lets the daemon
+// replay the original response when the same request is retried.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// DefaultIdempotencyTTL is how long a completed response is kept for replay.
+const DefaultIdempotencyTTL = 10 * time.Minute
+
+// idempotencyCache remembers responses by idempotency key.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	done      chan struct{}
+	status    int
+	header    http.Header
+	body      []byte
+	expiresAt time.Time
+}
+
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:     ttl,
+		entries: make(map[string]*idempotencyEntry),
+	}
+}
+
+// begin returns the entry for key and whether the caller owns it.
+// The owner must call finish; everyone else waits on entry.done.
+func (c *idempotencyCache) begin(key string) (*idempotencyEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if !e.expiresAt.IsZero() && now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+
+	if entry, ok := c.entries[key]; ok {
+		return entry, false
+	}
+
+	entry := &idempotencyEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	return entry, true
+}
+
+// finish records the response for an owned entry and releases waiters.
+// Rate limited responses are not kept: the request was never executed, so a
+// retry with the same key must run it.
+func (c *idempotencyCache) finish(key string, entry *idempotencyEntry, rec *bufferedResponse) {
+	c.mu.Lock()
+	entry.status = rec.status
+	entry.header = rec.header.Clone()
+	entry.body = rec.body.Bytes()
+	entry.expiresAt = time.Now().Add(c.ttl)
+	if rec.status == http.StatusTooManyRequests && c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+	close(entry.done)
+}
+
+// bufferedResponse captures a handler's response so it can be replayed.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+func writeReplay(w http.ResponseWriter, status int, header http.Header, body []byte) {
+	for k, v := range header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// idempotent wraps a handler so that requests carrying an Idempotency-Key
+// are executed at most once; retries receive the original response.
+// Requests without a key pass straight through.
+func (s *Server) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+
+		cacheKey := r.URL.Path + "\x00" + key
+		entry, owner := s.idempotency.begin(cacheKey)
+		if !owner {
+			select {
+			case <-entry.done:
+			case <-r.Context().Done():
+				return
+			}
+			writeReplay(w, entry.status, entry.header, entry.body)
+			return
+		}
+
+		rec := newBufferedResponse()
+		next(rec, r)
+		s.idempotency.finish(cacheKey, entry, rec)
+		writeReplay(w, rec.status, rec.header, rec.body.Bytes())
+	}
+}
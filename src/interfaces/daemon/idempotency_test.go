@@ -0,0 +1,204 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startFlakyServer serves handler on a Unix socket. The first request is
+// processed but its connection is dropped before the response is sent.
+func startFlakyServer(t *testing.T, path string, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	var dropped atomic.Bool
+	return startTestServer(t, path, func(w http.ResponseWriter, r *http.Request) {
+		if dropped.CompareAndSwap(false, true) {
+			handler(newBufferedResponse(), r)
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// startTestServer serves handler on a Unix socket.
+func startTestServer(t *testing.T, path string, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	socketPath := filepath.Join(t.TempDir(), "d.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, handler)
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(listener)
+	t.Cleanup(func() { srv.Close() })
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return net.Dial("unix", socketPath)
+		},
+	}
+	client := NewClientWithTransport(transport, socketPath)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+	return client
+}
+
+func TestClient_ReleaseLockRetriedOnce(t *testing.T) {
+	s := &Server{idempotency: newIdempotencyCache(DefaultIdempotencyTTL)}
+
+	var releases atomic.Int32
+	client := startFlakyServer(t, "/lock/release", s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		releases.Add(1)
+		json.NewEncoder(w).Encode(GenericResponse{Success: true, Message: "Lock released"})
+	}))
+
+	if err := client.ReleaseLock("lock-1"); err != nil {
+		t.Fatalf("ReleaseLock failed: %v", err)
+	}
+
+	if got := releases.Load(); got != 1 {
+		t.Errorf("expected release to run once, ran %d times", got)
+	}
+}
+
+func TestClient_ShareContextRetriedOnce(t *testing.T) {
+	s := &Server{idempotency: newIdempotencyCache(DefaultIdempotencyTTL)}
+
+	var shares atomic.Int32
+	client := startFlakyServer(t, "/context/share", s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		n := shares.Add(1)
+		json.NewEncoder(w).Encode(ShareContextResponse{Success: true, DocumentID: fmt.Sprintf("doc-%d", n)})
+	}))
+
+	result, err := client.ShareContext("main.go", "content", nil)
+	if err != nil {
+		t.Fatalf("ShareContext failed: %v", err)
+	}
+
+	if got := shares.Load(); got != 1 {
+		t.Errorf("expected share to run once, ran %d times", got)
+	}
+	if result.DocumentID != "doc-1" {
+		t.Errorf("expected replayed document doc-1, got %s", result.DocumentID)
+	}
+}
+
+func TestClient_AcquireLockSingleShotWithoutKey(t *testing.T) {
+	s := &Server{idempotency: newIdempotencyCache(DefaultIdempotencyTTL)}
+
+	var acquires atomic.Int32
+	client := startFlakyServer(t, "/lock/acquire", s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		acquires.Add(1)
+		json.NewEncoder(w).Encode(LockResponse{Success: true, LockID: "lock-1"})
+	}))
+
+	if _, err := client.AcquireLock("main.go", 1, 10, "edit"); err == nil {
+		t.Error("expected transient failure to surface without an idempotency key")
+	}
+	if got := acquires.Load(); got != 1 {
+		t.Errorf("expected a single attempt, got %d", got)
+	}
+}
+
+func TestClient_AcquireLockWithKeyRetriedOnce(t *testing.T) {
+	s := &Server{idempotency: newIdempotencyCache(DefaultIdempotencyTTL)}
+
+	var acquires atomic.Int32
+	client := startFlakyServer(t, "/lock/acquire", s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		acquires.Add(1)
+		json.NewEncoder(w).Encode(LockResponse{Success: true, LockID: "lock-1"})
+	}))
+
+	result, err := client.AcquireLockWithKey("acquire-1", "main.go", 1, 10, "edit")
+	if err != nil {
+		t.Fatalf("AcquireLockWithKey failed: %v", err)
+	}
+	if !result.Success || result.LockID != "lock-1" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if got := acquires.Load(); got != 1 {
+		t.Errorf("expected acquire to run once, ran %d times", got)
+	}
+}
+
+func TestClient_AcquireLockHonorsRetryAfter(t *testing.T) {
+	s := &Server{idempotency: newIdempotencyCache(DefaultIdempotencyTTL)}
+
+	const hint = 50 * time.Millisecond
+	var acquires atomic.Int32
+	var firstAt, secondAt time.Time
+	client := startTestServer(t, "/lock/acquire", s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		if acquires.Add(1) == 1 {
+			firstAt = time.Now()
+			writeRateLimited(w, hint)
+			json.NewEncoder(w).Encode(LockResponse{Error: "rate limited", RetryAfterMs: hint.Milliseconds()})
+			return
+		}
+		secondAt = time.Now()
+		json.NewEncoder(w).Encode(LockResponse{Success: true, LockID: "lock-1"})
+	}))
+
+	// The same key is reused, so the rate limited response must not be replayed
+	result, err := client.AcquireLockWithKey("acquire-1", "main.go", 1, 10, "edit")
+	if err != nil {
+		t.Fatalf("AcquireLockWithKey failed: %v", err)
+	}
+	if !result.Success || result.LockID != "lock-1" {
+		t.Errorf("expected retry after the hint to succeed, got %+v", result)
+	}
+	if got := acquires.Load(); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+	if waited := secondAt.Sub(firstAt); waited < hint {
+		t.Errorf("expected client to wait at least %v, waited %v", hint, waited)
+	}
+}
+
+func TestClient_AcquireLockRetryAfterExhausted(t *testing.T) {
+	s := &Server{idempotency: newIdempotencyCache(DefaultIdempotencyTTL)}
+
+	var acquires atomic.Int32
+	client := startTestServer(t, "/lock/acquire", s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		acquires.Add(1)
+		writeRateLimited(w, time.Millisecond)
+		json.NewEncoder(w).Encode(LockResponse{Error: "rate limited", RetryAfterMs: 1})
+	}))
+
+	result, err := client.AcquireLock("main.go", 1, 10, "edit")
+	if err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	if result.Success || result.RetryAfter() <= 0 {
+		t.Errorf("expected rate limited result with a retry hint, got %+v", result)
+	}
+	if got := acquires.Load(); got != 3 {
+		t.Errorf("expected retry policy to cap attempts at 3, got %d", got)
+	}
+}
+
+func TestWriteRateLimited(t *testing.T) {
+	rec := newBufferedResponse()
+	writeRateLimited(rec, 1500*time.Millisecond)
+
+	if rec.status != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %d", rec.status)
+	}
+	if got := rec.header.Get("Retry-After"); got != "2" {
+		t.Errorf("expected Retry-After rounded up to 2s, got %q", got)
+	}
+}
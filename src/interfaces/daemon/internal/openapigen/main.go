@@ -0,0 +1,39 @@
+// Command openapigen writes the OpenAPI document of the daemon's v1 API and
+// the typed client generated from the same operation table. It is run by
+// go generate in the daemon package.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"agent-collab/src/interfaces/daemon"
+)
+
+func main() {
+	specPath := flag.String("spec", "openapi.json", "where to write the OpenAPI document")
+	clientPath := flag.String("client", "apiclient/client_gen.go", "where to write the typed client")
+	flag.Parse()
+
+	if err := run(*specPath, *clientPath); err != nil {
+		fmt.Fprintf(os.Stderr, "openapigen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, clientPath string) error {
+	spec, err := daemon.OpenAPISpec()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(specPath, spec, 0600); err != nil {
+		return err
+	}
+
+	client, err := daemon.GenerateAPIClient()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(clientPath, client, 0600)
+}
@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"agent-collab/src/domain/lock"
+	"agent-collab/src/infrastructure/storage/metrics"
+)
+
+// defaultLockHistoryLimit is how many audit entries /lock/history returns
+// without a limit.
+const defaultLockHistoryLimit = 50
+
+// lockHistoryValues encodes an audit log filter as query parameters.
+func lockHistoryValues(q metrics.LockHistoryQuery) url.Values {
+	v := url.Values{}
+	if q.FilePath != "" {
+		v.Set("file", q.FilePath)
+	}
+	if q.HolderID != "" {
+		v.Set("holder", q.HolderID)
+	}
+	if q.Action != "" {
+		v.Set("action", q.Action)
+	}
+	if !q.Since.IsZero() {
+		v.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		v.Set("until", q.Until.Format(time.RFC3339))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return v
+}
+
+// LockHistoryResponse is the audited lock history, newest first.
+type LockHistoryResponse struct {
+	Entries []*lock.HistoryEntry `json:"entries"`
+	Error   string               `json:"error,omitempty"`
+}
+
+// parseLockHistoryQuery reads the audit log filter from query parameters.
+// since and until are RFC 3339 times; since may also be a duration back
+// from now, e.g. "24h".
+func parseLockHistoryQuery(v url.Values) (metrics.LockHistoryQuery, error) {
+	q := metrics.LockHistoryQuery{
+		FilePath: v.Get("file"),
+		HolderID: v.Get("holder"),
+		Action:   v.Get("action"),
+		Limit:    defaultLockHistoryLimit,
+	}
+	if s := v.Get("since"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil {
+			q.Since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, s); err == nil {
+			q.Since = t
+		} else {
+			return q, fmt.Errorf("invalid since %q: want a duration or RFC 3339 time", s)
+		}
+	}
+	if s := v.Get("until"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return q, fmt.Errorf("invalid until %q: %w", s, err)
+		}
+		q.Until = t
+	}
+	if s := v.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			return q, fmt.Errorf("invalid limit %q", s)
+		}
+		q.Limit = limit
+	}
+	return q, nil
+}
+
+// handleLockHistory handles the /lock/history endpoint.
+func (s *Server) handleLockHistory(w http.ResponseWriter, r *http.Request) {
+	q, err := parseLockHistoryQuery(r.URL.Query())
+	if err != nil {
+		json.NewEncoder(w).Encode(LockHistoryResponse{Error: err.Error()})
+		return
+	}
+
+	entries, err := s.app.LockHistory(q)
+	if err != nil {
+		json.NewEncoder(w).Encode(LockHistoryResponse{Error: err.Error()})
+		return
+	}
+	if entries == nil {
+		entries = []*lock.HistoryEntry{}
+	}
+	json.NewEncoder(w).Encode(LockHistoryResponse{Entries: entries})
+}
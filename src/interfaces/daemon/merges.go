@@ -0,0 +1,38 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"agent-collab/src/domain/ctxsync"
+)
+
+// ContextMergesResponse is the audit trail of concurrent context edits
+// merged by the sync CRDT, newest first.
+type ContextMergesResponse struct {
+	Merges []*ctxsync.MergeRecord `json:"merges"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// handleContextMerges handles the /context/merges endpoint. The file
+// query parameter filters by file path; limit caps the number of records.
+func (s *Server) handleContextMerges(w http.ResponseWriter, r *http.Request) {
+	sm := s.app.SyncManager()
+	if sm == nil {
+		json.NewEncoder(w).Encode(ContextMergesResponse{Error: "sync manager not initialized"})
+		return
+	}
+
+	limit := 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	merges := sm.MergeHistory(r.URL.Query().Get("file"), limit)
+	if merges == nil {
+		merges = []*ctxsync.MergeRecord{}
+	}
+	json.NewEncoder(w).Encode(ContextMergesResponse{Merges: merges})
+}
@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"agent-collab/src/domain/lock"
+)
+
+// ListNegotiationsResponse lists the lock negotiations still open: those
+// being negotiated and those escalated to a human, oldest first.
+type ListNegotiationsResponse struct {
+	Sessions []*lock.NegotiationSession `json:"sessions"`
+	Error    string                     `json:"error,omitempty"`
+}
+
+// NegotiationVoteRequest casts this node's vote in a negotiation.
+type NegotiationVoteRequest struct {
+	SessionID string `json:"session_id"`
+	Approve   bool   `json:"approve"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// NegotiationProposeRequest proposes a resolution for a negotiation. An
+// operator settles an escalated session with a yield, split or priority
+// proposal.
+type NegotiationProposeRequest struct {
+	SessionID string            `json:"session_id"`
+	Type      lock.ProposalType `json:"type"`
+	// YielderID is the holder giving way in a yield proposal
+	YielderID string `json:"yielder_id,omitempty"`
+	// SplitPoint is the first line the requester gets in a split proposal
+	SplitPoint int `json:"split_point,omitempty"`
+	// Reason explains an escalation
+	Reason string `json:"reason,omitempty"`
+}
+
+// NegotiationProposeResponse is the outcome of a proposal.
+type NegotiationProposeResponse struct {
+	Success bool                    `json:"success"`
+	Result  *lock.NegotiationResult `json:"result,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+}
+
+// handleListNegotiations handles the /negotiations/list endpoint.
+func (s *Server) handleListNegotiations(w http.ResponseWriter, _ *http.Request) {
+	lockService := s.app.LockService()
+	if lockService == nil {
+		json.NewEncoder(w).Encode(ListNegotiationsResponse{Error: errLockServiceUnavailable.Error()})
+		return
+	}
+
+	sessions := append(lockService.ListActiveNegotiations(), lockService.ListEscalatedNegotiations()...)
+	slices.SortFunc(sessions, func(a, b *lock.NegotiationSession) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	if sessions == nil {
+		sessions = []*lock.NegotiationSession{}
+	}
+	json.NewEncoder(w).Encode(ListNegotiationsResponse{Sessions: sessions})
+}
+
+// handleNegotiationVote handles the /negotiations/vote endpoint.
+func (s *Server) handleNegotiationVote(w http.ResponseWriter, r *http.Request) {
+	var req NegotiationVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
+		return
+	}
+
+	lockService := s.app.LockService()
+	if lockService == nil {
+		json.NewEncoder(w).Encode(GenericResponse{Error: errLockServiceUnavailable.Error()})
+		return
+	}
+
+	if err := lockService.Vote(s.ctx, req.SessionID, req.Approve, req.Reason); err != nil {
+		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
+		return
+	}
+
+	// The last vote resolves the session
+	if session, err := lockService.GetNegotiationSession(req.SessionID); err == nil && session.Resolution != nil {
+		s.PublishEvent(NewEvent(EventNegotiationResolved, session))
+	}
+	json.NewEncoder(w).Encode(GenericResponse{Success: true, Message: "Vote recorded"})
+}
+
+// handleNegotiationPropose handles the /negotiations/propose endpoint.
+func (s *Server) handleNegotiationPropose(w http.ResponseWriter, r *http.Request) {
+	var req NegotiationProposeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(NegotiationProposeResponse{Error: err.Error()})
+		return
+	}
+
+	lockService := s.app.LockService()
+	if lockService == nil {
+		json.NewEncoder(w).Encode(NegotiationProposeResponse{Error: errLockServiceUnavailable.Error()})
+		return
+	}
+
+	result, err := lockService.Negotiate(s.ctx, req.SessionID, &lock.NegotiationProposal{
+		Type:           req.Type,
+		YielderID:      req.YielderID,
+		SplitPoint:     req.SplitPoint,
+		EscalateReason: req.Reason,
+	})
+	// Escalating hands the session to a human, which is what was asked for
+	if err != nil && !errors.Is(err, lock.ErrHumanInterventionRequired) {
+		json.NewEncoder(w).Encode(NegotiationProposeResponse{Result: result, Error: err.Error()})
+		return
+	}
+
+	if session, err := lockService.GetNegotiationSession(req.SessionID); err == nil {
+		s.PublishEvent(NewEvent(EventNegotiationResolved, session))
+	}
+	json.NewEncoder(w).Encode(NegotiationProposeResponse{Success: true, Result: result})
+}
@@ -0,0 +1,339 @@
+package daemon
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+//go:generate go run ./internal/openapigen -spec openapi.json -client apiclient/client_gen.go
+
+// openAPIDocument is the OpenAPI document generated from APIOperations by
+// go generate; a test keeps it and the generated client in step with the
+// operation table.
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// APIOperation describes one v1 resource endpoint served on api_addr. The
+// table drives routing, the OpenAPI document and the generated client.
+type APIOperation struct {
+	// ID is the OpenAPI operationId; the generated client method is named
+	// after it.
+	ID      string
+	Method  string
+	Path    string
+	Tag     string
+	Summary string
+	// Request is the JSON body type, nil when the operation takes none.
+	// Fields also bound from the path are overridden by the path value.
+	Request any
+	// Response is the JSON response type.
+	Response any
+	// Write marks operations that change state; they always need the
+	// operator token, while reads only need it when one is configured.
+	Write bool
+}
+
+// PathParams returns the names of the {params} in the operation path.
+func (op APIOperation) PathParams() []string {
+	var params []string
+	for seg := range strings.SplitSeq(op.Path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params = append(params, strings.Trim(seg, "{}"))
+		}
+	}
+	return params
+}
+
+// APIOperations returns the v1 resource endpoints in a stable order.
+func APIOperations() []APIOperation {
+	return []APIOperation{
+		{ID: "listLocks", Method: http.MethodGet, Path: "/api/v1/locks", Tag: "Locks",
+			Summary: "List the locks known to this node", Response: ListLocksResponse{}},
+		{ID: "acquireLock", Method: http.MethodPost, Path: "/api/v1/locks", Tag: "Locks",
+			Summary: "Acquire a lock on a file region", Request: LockRequest{}, Response: LockResponse{}, Write: true},
+		{ID: "checkLock", Method: http.MethodPost, Path: "/api/v1/locks/check", Tag: "Locks",
+			Summary: "Check whether a file region is free without locking it", Request: LockRequest{}, Response: CheckLockResponse{}},
+		{ID: "renewLock", Method: http.MethodPost, Path: "/api/v1/locks/{id}/renew", Tag: "Locks",
+			Summary: "Extend the lease of a held lock", Request: RenewLockRequest{}, Response: RenewLockResponse{}, Write: true},
+		{ID: "upgradeLock", Method: http.MethodPost, Path: "/api/v1/locks/{id}/upgrade", Tag: "Locks",
+			Summary: "Upgrade a held read lock to a write lock", Response: LockResponse{}, Write: true},
+		{ID: "releaseLock", Method: http.MethodDelete, Path: "/api/v1/locks/{id}", Tag: "Locks",
+			Summary: "Release a held lock", Response: GenericResponse{}, Write: true},
+		{ID: "shareContext", Method: http.MethodPost, Path: "/api/v1/contexts", Tag: "Contexts",
+			Summary: "Share context with the cluster", Request: ShareContextRequest{}, Response: ShareContextResponse{}, Write: true},
+		{ID: "searchContexts", Method: http.MethodPost, Path: "/api/v1/contexts/search", Tag: "Contexts",
+			Summary: "Search shared context by similarity", Request: SearchRequest{}, Response: SearchResponse{}},
+		{ID: "listAgents", Method: http.MethodGet, Path: "/api/v1/agents", Tag: "Agents",
+			Summary: "List the agents connected to the cluster", Response: ListAgentsResponse{}},
+		{ID: "listCollections", Method: http.MethodGet, Path: "/api/v1/collections", Tag: "Collections",
+			Summary: "List vector collections", Response: ListCollectionsResponse{}},
+		{ID: "createCollection", Method: http.MethodPost, Path: "/api/v1/collections", Tag: "Collections",
+			Summary: "Create a vector collection", Request: CreateCollectionRequest{}, Response: CollectionResponse{}, Write: true},
+		{ID: "setCollectionTTL", Method: http.MethodPut, Path: "/api/v1/collections/{name}/ttl", Tag: "Collections",
+			Summary: "Change how long a collection keeps documents", Request: CollectionTTLRequest{}, Response: CollectionResponse{}, Write: true},
+		{ID: "deleteCollection", Method: http.MethodDelete, Path: "/api/v1/collections/{name}", Tag: "Collections",
+			Summary: "Delete a collection and its documents", Response: CollectionResponse{}, Write: true},
+	}
+}
+
+// OpenAPISpec builds the OpenAPI 3.0 document of the v1 resource endpoints
+// from APIOperations, deriving schemas from the Go request and response
+// types.
+func OpenAPISpec() ([]byte, error) {
+	schemas := newSchemaSet()
+	paths := make(map[string]map[string]any)
+	for _, op := range APIOperations() {
+		operation := map[string]any{
+			"operationId": op.ID,
+			"summary":     op.Summary,
+			"tags":        []string{op.Tag},
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "The result; failures set error",
+					"content":     jsonContent(schemas.schemaFor(reflect.TypeOf(op.Response))),
+				},
+				"401": map[string]any{"description": "Missing or invalid operator token"},
+				"403": map[string]any{"description": "Not permitted for the node's role"},
+			},
+		}
+		if params := op.PathParams(); len(params) > 0 {
+			var parameters []any
+			for _, name := range params {
+				parameters = append(parameters, map[string]any{
+					"name":     name,
+					"in":       "path",
+					"required": true,
+					"schema":   map[string]any{"type": "string"},
+				})
+			}
+			operation["parameters"] = parameters
+		}
+		if op.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(schemas.schemaFor(reflect.TypeOf(op.Request))),
+			}
+		}
+		if op.Write {
+			operation["security"] = []any{map[string]any{"operatorToken": []string{}}}
+		} else {
+			operation["security"] = []any{map[string]any{}, map[string]any{"operatorToken": []string{}}}
+		}
+
+		if paths[op.Path] == nil {
+			paths[op.Path] = make(map[string]any)
+		}
+		paths[op.Path][strings.ToLower(op.Method)] = operation
+	}
+
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "agent-collab daemon API",
+			"version":     "v1",
+			"description": "Resource endpoints served on api_addr. Reads need the operator token when one is configured; writes always do.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas.defs,
+			"securitySchemes": map[string]any{
+				"operatorToken": map[string]any{"type": "apiKey", "in": "header", "name": OperatorTokenHeader},
+			},
+		},
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// jsonContent is an OpenAPI content map for a JSON body.
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// schemaSet collects the named struct schemas referenced by the document.
+type schemaSet struct {
+	defs map[string]any
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{defs: make(map[string]any)}
+}
+
+// schemaFor returns the schema of t, registering named structs under
+// components/schemas and referring to them.
+func (s *schemaSet) schemaFor(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]any{"type": "integer", "format": "int64", "description": "Duration in nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Uint:
+		return map[string]any{"type": "integer"}
+	case reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]any{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": s.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		name := t.Name()
+		if _, ok := s.defs[name]; !ok {
+			s.defs[name] = map[string]any{} // placeholder for recursive types
+			s.defs[name] = s.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema is the object schema of a struct's JSON fields. Embedded
+// structs are flattened the way encoding/json does.
+func (s *schemaSet) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	s.addFields(t, properties)
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+func (s *schemaSet) addFields(t reflect.Type, properties map[string]any) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.addFields(embedded, properties)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = s.schemaFor(field.Type)
+	}
+}
+
+// GenerateAPIClient renders the methods of the typed client in package
+// apiclient, one per operation of APIOperations.
+func GenerateAPIClient() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by openapigen from the daemon API operations. DO NOT EDIT.\n\n")
+	b.WriteString("package apiclient\n\n")
+	b.WriteString("import (\n\t\"context\"\n\t\"net/http\"\n\t\"net/url\"\n\n\t\"agent-collab/src/interfaces/daemon\"\n)\n")
+
+	daemonPkg := reflect.TypeOf(APIOperation{}).PkgPath()
+	typeName := func(v any) (string, error) {
+		t := reflect.TypeOf(v)
+		if t.PkgPath() != daemonPkg {
+			return "", fmt.Errorf("type %s is not in package daemon", t)
+		}
+		return "daemon." + t.Name(), nil
+	}
+
+	for _, op := range APIOperations() {
+		method := string(unicode.ToUpper(rune(op.ID[0]))) + op.ID[1:]
+		params := []string{"ctx context.Context"}
+		path := `"` + op.Path + `"`
+		for _, name := range op.PathParams() {
+			params = append(params, name+" string")
+			path = strings.Replace(path, "{"+name+"}", `" + url.PathEscape(`+name+`) + "`, 1)
+		}
+		path = strings.ReplaceAll(path, ` + ""`, "")
+		body := "nil"
+		if op.Request != nil {
+			name, err := typeName(op.Request)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, "req "+name)
+			body = "req"
+		}
+		out, err := typeName(op.Response)
+		if err != nil {
+			return nil, err
+		}
+		// Responses with a success flag may carry a reason in error even
+		// when they succeed
+		outcome := ""
+		if _, ok := reflect.TypeOf(op.Response).FieldByName("Success"); ok {
+			outcome = "out.Success, out.Error"
+		} else if _, ok := reflect.TypeOf(op.Response).FieldByName("Error"); ok {
+			outcome = `out.Error == "", out.Error`
+		}
+
+		fmt.Fprintf(&b, "\n// %s %s (%s %s).\n", method, clientVerb(op.Summary), op.Method, op.Path)
+		fmt.Fprintf(&b, "func (c *Client) %s(%s) (*%s, error) {\n", method, strings.Join(params, ", "), out)
+		fmt.Fprintf(&b, "\tvar out %s\n", out)
+		fmt.Fprintf(&b, "\terr := c.do(ctx, http.Method%s, %s, %s, &out)\n", methodConst(op.Method), path, body)
+		if outcome != "" {
+			fmt.Fprintf(&b, "\tif err == nil {\n\t\terr = check(%s)\n\t}\n", outcome)
+		}
+		b.WriteString("\treturn &out, err\n}\n")
+	}
+	return format.Source(b.Bytes())
+}
+
+// clientVerb turns an imperative summary ("List locks") into the third
+// person ("lists locks") for a doc comment.
+func clientVerb(summary string) string {
+	verb, rest, _ := strings.Cut(summary, " ")
+	verb = strings.ToLower(verb)
+	if strings.HasSuffix(verb, "s") || strings.HasSuffix(verb, "sh") || strings.HasSuffix(verb, "ch") {
+		verb += "es"
+	} else {
+		verb += "s"
+	}
+	return verb + " " + rest
+}
+
+// methodConst is the net/http constant suffix of an HTTP method.
+func methodConst(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestOpenAPI_GeneratedFilesUpToDate(t *testing.T) {
+	spec, err := OpenAPISpec()
+	if err != nil {
+		t.Fatalf("OpenAPISpec: %v", err)
+	}
+	if !bytes.Equal(spec, openAPIDocument) {
+		t.Error("openapi.json is stale: run go generate ./interfaces/daemon")
+	}
+
+	client, err := GenerateAPIClient()
+	if err != nil {
+		t.Fatalf("GenerateAPIClient: %v", err)
+	}
+	committed, err := os.ReadFile("apiclient/client_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(client, committed) {
+		t.Error("apiclient/client_gen.go is stale: run go generate ./interfaces/daemon")
+	}
+}
+
+func TestOpenAPI_EveryOperationHasHandler(t *testing.T) {
+	handlers := (&Server{}).apiHandlers()
+	seen := make(map[string]bool)
+	for _, op := range APIOperations() {
+		if seen[op.ID] {
+			t.Errorf("duplicate operation %s", op.ID)
+		}
+		seen[op.ID] = true
+		if handlers[op.ID] == nil {
+			t.Errorf("no handler for operation %s", op.ID)
+		}
+	}
+	if len(handlers) != len(seen) {
+		t.Errorf("expected %d handlers, got %d", len(seen), len(handlers))
+	}
+}
+
+func TestOpenAPI_DocumentDescribesOperations(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleOpenAPIDocument(w, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+
+	var doc struct {
+		OpenAPI string                               `json:"openapi"`
+		Paths   map[string]map[string]map[string]any `json:"paths"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("expected OpenAPI 3.0.3, got %q", doc.OpenAPI)
+	}
+	op := doc.Paths["/api/v1/locks/{id}/renew"]["post"]
+	if op["operationId"] != "renewLock" {
+		t.Errorf("expected renewLock, got %v", op["operationId"])
+	}
+	if _, ok := op["requestBody"]; !ok {
+		t.Error("expected a request body for renewLock")
+	}
+}
@@ -0,0 +1,243 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"agent-collab/src/application"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsNamespace prefixes every exported Prometheus metric.
+const metricsNamespace = "agent_collab"
+
+func newDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", name), help, labels, nil)
+}
+
+var (
+	descPeersConnected = newDesc("peers_connected",
+		"Peers this node is connected to.")
+	descPeerDisconnects = newDesc("peer_disconnects_total",
+		"Peer disconnections since startup.")
+	descPubsubMessages = newDesc("pubsub_messages_total",
+		"Pubsub messages sent or received since startup.", "direction")
+	descPubsubTopicMessages = newDesc("pubsub_topic_messages_total",
+		"Pubsub messages sent and received per topic since startup.", "topic")
+	descPubsubBytes = newDesc("pubsub_bytes_total",
+		"Pubsub bytes sent or received since startup.", "direction")
+	descGossipRelayBytes = newDesc("gossip_relay_bytes_total",
+		"Throttled gossip bytes by kind: relayed to far-region gateways, or the flooding they replaced.", "kind")
+	descGossipSavedBytes = newDesc("gossip_saved_bytes",
+		"WAN bytes saved by relaying throttled gossip through gateways.")
+	descLocksHeld = newDesc("locks_held",
+		"Locks currently held in the cluster, as seen by this node.")
+	descLocksHeldLocal = newDesc("locks_held_local",
+		"Locks currently held by this node.")
+	descLockEvents = newDesc("lock_events_total",
+		"Lock events since startup by action: acquired, conflict, released, expired, ...", "action")
+	descNegotiationsActive = newDesc("negotiations_active",
+		"Lock negotiations waiting for a resolution.")
+	descNegotiationResolution = newDesc("negotiation_resolution_seconds",
+		"Time from the start of a lock negotiation to its resolution.", "resolution")
+	descLockRateRequests = newDesc("lock_rate_limited_requests_total",
+		"Lock requests checked by the rate limiter since startup, by holder and result: allowed or denied.", "holder", "result")
+	descLockRateConflicts = newDesc("lock_rate_limit_conflicts_total",
+		"Lock requests that ran into a conflict since startup, by holder.", "holder")
+	descLockRateLimit = newDesc("lock_rate_limit_rate",
+		"Lock requests per second currently allowed per holder; adaptive mode moves it with the holder's conflicts.", "holder")
+	descVectorDocuments = newDesc("vector_documents",
+		"Documents in the vector store.", "collection")
+	descVectorBytes = newDesc("vector_size_bytes",
+		"Size of the vector store.", "collection")
+	descTokensToday = newDesc("tokens_today",
+		"Tokens used today.")
+	descCategoryTokensToday = newDesc("category_tokens_today",
+		"Tokens used today per category: embedding, sync, negotiation, ...", "category")
+	descTokenDailyLimit = newDesc("token_daily_limit",
+		"Daily token budget.")
+	descTokenCostToday = newDesc("token_cost_today_dollars",
+		"Estimated cost of today's token usage.")
+)
+
+// prometheusCollector exports the daemon's metrics in the Prometheus format.
+// Values are read from the app on every scrape, so the collector keeps no
+// state of its own and components not running yet are simply left out.
+type prometheusCollector struct {
+	app *application.App
+}
+
+// newMetricsHandler serves the app's metrics to Prometheus.
+func newMetricsHandler(app *application.App) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(&prometheusCollector{app: app})
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Describe implements prometheus.Collector.
+func (c *prometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		descPeersConnected, descPeerDisconnects, descPubsubMessages,
+		descPubsubTopicMessages, descPubsubBytes, descGossipRelayBytes,
+		descGossipSavedBytes, descLocksHeld,
+		descLocksHeldLocal, descLockEvents, descNegotiationsActive,
+		descNegotiationResolution, descLockRateRequests, descLockRateConflicts,
+		descLockRateLimit, descVectorDocuments, descVectorBytes,
+		descTokensToday, descCategoryTokensToday, descTokenDailyLimit, descTokenCostToday,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *prometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectNetwork(ch)
+	c.collectLocks(ch)
+	c.collectVectors(ch)
+	c.collectTokens(ch)
+}
+
+func (c *prometheusCollector) collectNetwork(ch chan<- prometheus.Metric) {
+	node := c.app.Node()
+	if node == nil {
+		return
+	}
+	snap := node.GetMetricsSnapshot()
+
+	ch <- prometheus.MustNewConstMetric(descPeersConnected, prometheus.GaugeValue, float64(snap.PeersConnected))
+	ch <- prometheus.MustNewConstMetric(descPeerDisconnects, prometheus.CounterValue, float64(snap.TotalDisconnects))
+	ch <- prometheus.MustNewConstMetric(descPubsubMessages, prometheus.CounterValue, float64(snap.TotalMessagesSent), "sent")
+	ch <- prometheus.MustNewConstMetric(descPubsubMessages, prometheus.CounterValue, float64(snap.TotalMessagesReceived), "received")
+	ch <- prometheus.MustNewConstMetric(descPubsubBytes, prometheus.CounterValue, float64(snap.BytesSent), "sent")
+	ch <- prometheus.MustNewConstMetric(descPubsubBytes, prometheus.CounterValue, float64(snap.BytesReceived), "received")
+	for topic, count := range snap.MessagesByTopic {
+		ch <- prometheus.MustNewConstMetric(descPubsubTopicMessages, prometheus.CounterValue, float64(count), topic)
+	}
+	if t := snap.GossipThrottle; t != nil {
+		ch <- prometheus.MustNewConstMetric(descGossipRelayBytes, prometheus.CounterValue, float64(t.RelayBytes), "relayed")
+		ch <- prometheus.MustNewConstMetric(descGossipRelayBytes, prometheus.CounterValue, float64(t.FloodBytes), "flood")
+		ch <- prometheus.MustNewConstMetric(descGossipSavedBytes, prometheus.GaugeValue, float64(t.SavedBytes))
+	}
+}
+
+func (c *prometheusCollector) collectLocks(ch chan<- prometheus.Metric) {
+	lockService := c.app.LockService()
+	if lockService == nil {
+		return
+	}
+	stats := lockService.GetStats()
+
+	ch <- prometheus.MustNewConstMetric(descLocksHeld, prometheus.GaugeValue, float64(stats.TotalLocks))
+	ch <- prometheus.MustNewConstMetric(descLocksHeldLocal, prometheus.GaugeValue, float64(stats.MyLocks))
+	ch <- prometheus.MustNewConstMetric(descNegotiationsActive, prometheus.GaugeValue, float64(stats.ActiveNegotiations))
+	for action, count := range stats.Actions {
+		ch <- prometheus.MustNewConstMetric(descLockEvents, prometheus.CounterValue, float64(count), action)
+	}
+
+	for holder, rate := range lockService.RateLimitStats().Holders {
+		ch <- prometheus.MustNewConstMetric(descLockRateRequests, prometheus.CounterValue, float64(rate.Allowed), holder, "allowed")
+		ch <- prometheus.MustNewConstMetric(descLockRateRequests, prometheus.CounterValue, float64(rate.Denied), holder, "denied")
+		ch <- prometheus.MustNewConstMetric(descLockRateConflicts, prometheus.CounterValue, float64(rate.Conflicts), holder)
+		ch <- prometheus.MustNewConstMetric(descLockRateLimit, prometheus.GaugeValue, rate.Rate, holder)
+	}
+
+	for resolution, hist := range lockService.NegotiationMetrics().TimeToResolution {
+		buckets := make(map[float64]uint64, len(hist.Buckets))
+		for _, bucket := range hist.Buckets {
+			le, err := time.ParseDuration(bucket.LE)
+			if err != nil {
+				continue // +Inf is implied by the count
+			}
+			buckets[le.Seconds()] = uint64(bucket.Count)
+		}
+		ch <- prometheus.MustNewConstHistogram(descNegotiationResolution,
+			uint64(hist.Count), hist.Sum.Seconds(), buckets, string(resolution))
+	}
+}
+
+func (c *prometheusCollector) collectVectors(ch chan<- prometheus.Metric) {
+	store := c.app.VectorStore()
+	if store == nil {
+		return
+	}
+	collections, err := store.ListCollections()
+	if err != nil {
+		return
+	}
+	for _, name := range collections {
+		stats, err := store.GetCollectionStats(name)
+		if err != nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(descVectorDocuments, prometheus.GaugeValue, float64(stats.Count), name)
+		ch <- prometheus.MustNewConstMetric(descVectorBytes, prometheus.GaugeValue, float64(stats.SizeBytes), name)
+	}
+}
+
+func (c *prometheusCollector) collectTokens(ch chan<- prometheus.Metric) {
+	tracker := c.app.TokenTracker()
+	if tracker == nil {
+		return
+	}
+	usage := tracker.GetMetrics()
+
+	ch <- prometheus.MustNewConstMetric(descTokensToday, prometheus.GaugeValue, float64(usage.TokensToday))
+	for category, tokens := range usage.ByCategory {
+		ch <- prometheus.MustNewConstMetric(descCategoryTokensToday, prometheus.GaugeValue, float64(tokens), string(category))
+	}
+	ch <- prometheus.MustNewConstMetric(descTokenDailyLimit, prometheus.GaugeValue, float64(usage.DailyLimit))
+	ch <- prometheus.MustNewConstMetric(descTokenCostToday, prometheus.GaugeValue, usage.CostToday)
+}
+
+// startMetricsListener serves the Prometheus metrics over TCP when
+// metrics_addr is configured, since scrapers cannot reach the Unix socket.
+func (s *Server) startMetricsListener() error {
+	cfg := s.app.Config()
+	if cfg == nil || cfg.MetricsAddr == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", cfg.MetricsAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on metrics_addr: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", s.metricsHandler)
+	s.metricsServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := s.metricsServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "Metrics server error: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// stopMetricsListener waits for in-flight scrapes until ctx expires.
+func (s *Server) stopMetricsListener(ctx context.Context) {
+	if s.metricsServer != nil {
+		s.metricsServer.Shutdown(ctx)
+	}
+}
+
+// wantsPrometheus reports whether a /metrics request asks for the
+// Prometheus text format rather than the JSON the CLI reads: scrapers
+// send an Accept header naming it, and ?format=prometheus forces it.
+func wantsPrometheus(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "prometheus" {
+		return true
+	}
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "text/plain") || strings.Contains(accept, "application/openmetrics-text")
+}
@@ -0,0 +1,80 @@
+package daemon_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-collab/src/application"
+	"agent-collab/src/interfaces/daemon"
+)
+
+func TestDaemon_PrometheusMetrics(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Reserve a free port for the metrics listener
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metricsAddr := l.Addr().String()
+	l.Close()
+
+	app, err := application.New(&application.Config{DataDir: t.TempDir(), MetricsAddr: metricsAddr})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	if _, err := app.Initialize(ctx, "metrics-test-project"); err != nil {
+		t.Fatalf("Failed to initialize app: %v", err)
+	}
+	server := daemon.NewServer(app)
+	if err := server.Start(ctx); err != nil {
+		t.Fatalf("Failed to start daemon: %v", err)
+	}
+	defer server.Stop()
+
+	client := daemon.NewClient()
+	if _, err := client.AcquireLock("auth/login.go", 10, 20, "add rate limiting"); err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+
+	resp, err := http.Get("http://" + metricsAddr + "/metrics")
+	if err != nil {
+		t.Fatalf("scrape failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("expected the Prometheus text format, got %q", resp.Header.Get("Content-Type"))
+	}
+	for _, want := range []string{
+		"agent_collab_peers_connected 0",
+		`agent_collab_lock_events_total{action="acquired"} 1`,
+		"agent_collab_locks_held_local 1",
+		`result="allowed"} 1`,
+		`agent_collab_pubsub_messages_total{direction="sent"}`,
+		"agent_collab_tokens_today",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected %q in scrape:\n%s", want, body)
+		}
+	}
+
+	// The CLI keeps getting JSON from the socket
+	metrics, err := client.Metrics()
+	if err != nil {
+		t.Fatalf("Metrics: %v", err)
+	}
+	if _, ok := metrics["peers_connected"]; !ok {
+		t.Errorf("expected JSON metrics, got %v", metrics)
+	}
+}
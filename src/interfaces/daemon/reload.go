@@ -0,0 +1,25 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"agent-collab/src/application"
+)
+
+// ReloadConfigResponse is the response to a configuration reload.
+type ReloadConfigResponse struct {
+	Success bool                      `json:"success"`
+	Result  *application.ReloadResult `json:"result,omitempty"`
+	Error   string                    `json:"error,omitempty"`
+}
+
+// handleReloadConfig handles the /config/reload endpoint.
+func (s *Server) handleReloadConfig(w http.ResponseWriter, _ *http.Request) {
+	result, err := s.app.ReloadConfig()
+	if err != nil {
+		json.NewEncoder(w).Encode(ReloadConfigResponse{Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(ReloadConfigResponse{Success: true, Result: result})
+}
@@ -0,0 +1,51 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+)
+
+// initialOptionsGrace is how long a new stream holds back live events for
+// the options a client sends when connecting, so a requested replay comes
+// before them. Clients that send no options get live events after it.
+const initialOptionsGrace = 100 * time.Millisecond
+
+// ReplayDoneData marks the end of a replayed backlog.
+type ReplayDoneData struct {
+	Count int `json:"count"`
+}
+
+// WantsReplay reports whether the options ask for a backlog.
+func (o WatchOptions) WantsReplay() bool {
+	return o.ReplaySince != "" || o.ReplayLast != 0
+}
+
+// ReplayEvents selects the backlog the options ask for from history, in
+// chronological order.
+func (o WatchOptions) ReplayEvents(history []Event, now time.Time) ([]Event, error) {
+	if o.ReplayLast < 0 {
+		return nil, fmt.Errorf("replay_last must not be negative: %d", o.ReplayLast)
+	}
+
+	var since time.Time
+	if o.ReplaySince != "" {
+		if d, err := time.ParseDuration(o.ReplaySince); err == nil {
+			since = now.Add(-d)
+		} else if t, err := time.Parse(time.RFC3339Nano, o.ReplaySince); err == nil {
+			since = t
+		} else {
+			return nil, fmt.Errorf("invalid replay_since %q: want a duration or RFC 3339 timestamp", o.ReplaySince)
+		}
+	}
+
+	events := make([]Event, 0, len(history))
+	for _, e := range history {
+		if !e.Timestamp.Before(since) {
+			events = append(events, e)
+		}
+	}
+	if o.ReplayLast > 0 && len(events) > o.ReplayLast {
+		events = events[len(events)-o.ReplayLast:]
+	}
+	return events, nil
+}
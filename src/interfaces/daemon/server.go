@@ -3,7 +3,9 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"os"
@@ -17,7 +19,12 @@ import (
 	"agent-collab/src/domain/event"
 	"agent-collab/src/domain/interest"
 	"agent-collab/src/domain/lock"
+	"agent-collab/src/domain/token"
+	"agent-collab/src/infrastructure/network/libp2p"
 	"agent-collab/src/infrastructure/storage/vector"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"google.golang.org/grpc"
 )
 
 // Server is the daemon server that manages the agent-collab instance.
@@ -35,10 +42,49 @@ type Server struct {
 	eventBus    *EventBus
 	eventServer *EventServer
 
+	// The same API over gRPC, on its own socket
+	grpcServer     *grpc.Server
+	grpcSocketPath string
+	grpcDraining   chan struct{}
+	grpcDrainOnce  sync.Once
+
+	// Prometheus metrics, also served over TCP when metrics_addr is set
+	metricsHandler http.Handler
+	metricsServer  *http.Server
+
+	// Dashboard API over TCP when api_addr is set
+	apiServer    *http.Server
+	apiStreams   sync.WaitGroup
+	apiDraining  chan struct{}
+	apiDrainOnce sync.Once
+
+	// Responses replayed for retried requests
+	idempotency *idempotencyCache
+
+	// Authenticates operator-only endpoints
+	auth Authenticator
+
+	// How long Stop waits for requests and event streams to finish
+	drainTimeout time.Duration
+
 	ctx    context.Context
 	cancel context.CancelFunc
+
+	// Closed once Stop has stopped the app
+	done     chan struct{}
+	doneOnce sync.Once
 }
 
+// Errors shared by the HTTP and gRPC APIs.
+var (
+	errLockServiceUnavailable = errors.New("lock service not initialized")
+	errSearchUnavailable      = errors.New("services not initialized")
+	errContentRequired        = errors.New("content is required")
+)
+
+// DefaultDrainTimeout bounds the drain phase of Stop.
+const DefaultDrainTimeout = 5 * time.Second
+
 // DefaultSocketPath returns the default Unix socket path.
 func DefaultSocketPath() string {
 	home, _ := os.UserHomeDir()
@@ -54,13 +100,70 @@ func DefaultPIDFile() string {
 // NewServer creates a new daemon server.
 func NewServer(app *application.App) *Server {
 	eventBus := NewEventBus()
-	return &Server{
-		app:         app,
-		socketPath:  DefaultSocketPath(),
-		pidFile:     DefaultPIDFile(),
-		eventBus:    eventBus,
-		eventServer: NewEventServer(eventBus),
-	}
+	s := &Server{
+		app:            app,
+		socketPath:     DefaultSocketPath(),
+		pidFile:        DefaultPIDFile(),
+		eventBus:       eventBus,
+		eventServer:    NewEventServer(eventBus),
+		grpcSocketPath: DefaultGRPCSocketPath(),
+		grpcDraining:   make(chan struct{}),
+		apiDraining:    make(chan struct{}),
+		idempotency:    newIdempotencyCache(DefaultIdempotencyTTL),
+		drainTimeout:   DefaultDrainTimeout,
+		metricsHandler: newMetricsHandler(app),
+		done:           make(chan struct{}),
+	}
+	if token := os.Getenv(OperatorTokenEnv); token != "" {
+		s.auth = NewTokenAuthenticator(token)
+	}
+
+	// Notify local agents when a peer breaks one of the cluster's locks
+	app.SetForceReleaseHandler(func(notice *lock.ForceReleaseNotice) {
+		s.PublishEvent(NewEvent(EventLockForceReleased, notice))
+	})
+	// Warn local agents before an idle lock is released, then report the release
+	app.SetLockIdleHandler(func(notice *lock.IdleNotice) {
+		if notice.Released {
+			s.PublishEvent(NewEvent(EventLockIdleReleased, notice))
+		} else {
+			s.PublishEvent(NewEvent(EventLockIdleWarning, notice))
+		}
+	})
+	// Remind local agents to renew a lock before its lease runs out
+	app.SetLockRenewalHandler(func(notice *lock.RenewalNotice) {
+		s.PublishEvent(NewEvent(EventLockRenewalDue, notice))
+	})
+	// Report partitions and tell local agents which locks reconciliation took away
+	app.SetPartitionHandler(func(event *lock.PartitionEvent) {
+		if event.State == lock.PartitionPartitioned {
+			s.PublishEvent(NewEvent(EventPartitionDetected, event))
+		} else {
+			s.PublishEvent(NewEvent(EventPartitionHealed, event))
+		}
+	})
+	app.SetLockReconciledHandler(func(result *lock.ReconcileResult) {
+		s.PublishEvent(NewEvent(EventLockReconciled, result))
+	})
+	// Tell local agents when embedding and sharing hit a token budget
+	app.SetBudgetExceededHandler(func(exceeded *token.BudgetExceeded) {
+		s.PublishEvent(NewEvent(EventBudgetExceeded, exceeded))
+	})
+	app.SetConfigReloadedHandler(func(result *application.ReloadResult) {
+		s.PublishEvent(NewEvent(EventConfigReloaded, result))
+	})
+	app.SetPeerBannedHandler(func(ban *libp2p.Ban) {
+		s.PublishEvent(NewEvent(EventPeerBanned, ban))
+	})
+	return s
+}
+
+// SetDrainTimeout sets how long Stop waits for in-flight requests and
+// event streams before closing them.
+func (s *Server) SetDrainTimeout(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drainTimeout = d
 }
 
 // Start starts the daemon server.
@@ -121,19 +224,50 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 	}()
 
+	// Serve the gRPC API next to it
+	if err := s.startGRPC(); err != nil {
+		return err
+	}
+
+	if err := s.startMetricsListener(); err != nil {
+		return err
+	}
+	if err := s.startAPIListener(); err != nil {
+		return err
+	}
+
 	// Publish ready event
 	s.PublishEvent(NewEvent(EventDaemonReady, nil))
 
+	go s.publishStatus(s.ctx)
+	go s.publishPeerChanges(s.ctx, application.StatusWatchInterval)
+
 	return nil
 }
 
-// Stop stops the daemon server.
+// Stop drains and stops the daemon server. New requests are refused,
+// in-flight requests and event streams get up to the drain timeout to
+// finish, then the app is stopped.
 func (s *Server) Stop() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Publish shutdown event
+	drainCtx, cancel := context.WithTimeout(context.Background(), s.drainTimeout)
+	defer cancel()
+
+	// Drain: stop accepting requests and let in-flight ones finish
+	if s.server != nil {
+		s.server.Shutdown(drainCtx)
+	}
+
+	// Publish shutdown event and let event streams flush it
 	s.eventBus.Publish(NewEvent(EventDaemonShutdown, nil))
+	if s.eventServer != nil {
+		s.eventServer.Drain(drainCtx)
+	}
+	s.stopGRPC(drainCtx)
+	s.stopMetricsListener(drainCtx)
+	s.stopAPIListener(drainCtx)
 
 	if s.cancel != nil {
 		s.cancel()
@@ -149,12 +283,6 @@ func (s *Server) Stop() error {
 		s.eventBus.Close()
 	}
 
-	if s.server != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		s.server.Shutdown(ctx)
-	}
-
 	if s.listener != nil {
 		s.listener.Close()
 	}
@@ -167,6 +295,9 @@ func (s *Server) Stop() error {
 	os.Remove(s.socketPath)
 	os.Remove(s.pidFile)
 
+	if s.done != nil {
+		s.doneOnce.Do(func() { close(s.done) })
+	}
 	return nil
 }
 
@@ -175,6 +306,12 @@ func (s *Server) Wait() {
 	<-s.ctx.Done()
 }
 
+// Done returns a channel closed once Stop has finished, including a stop
+// requested through the shutdown endpoint.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
 // EventBus returns the event bus.
 func (s *Server) EventBus() *EventBus {
 	return s.eventBus
@@ -187,38 +324,123 @@ func (s *Server) PublishEvent(event Event) {
 
 func (s *Server) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleReadyz)
 	mux.HandleFunc("/init", s.handleInit)
 	mux.HandleFunc("/join", s.handleJoin)
 	mux.HandleFunc("/leave", s.handleLeave)
 	mux.HandleFunc("/leave/status", s.handleLeaveStatus)
-	mux.HandleFunc("/lock/acquire", s.handleAcquireLock)
-	mux.HandleFunc("/lock/release", s.handleReleaseLock)
+	mux.HandleFunc("/lock/acquire", s.idempotent(s.handleAcquireLock))
+	mux.HandleFunc("/lock/release", s.idempotent(s.handleReleaseLock))
+	mux.HandleFunc("/lock/release-by-intention", s.permitted(application.PermWrite, s.idempotent(s.handleReleaseLocksByIntention)))
+	mux.HandleFunc("/lock/renew", s.handleRenewLock)
+	mux.HandleFunc("/lock/upgrade", s.idempotent(s.handleUpgradeLock))
+	mux.HandleFunc("/lock/force-release", s.permitted(application.PermOperate, s.authenticated(s.idempotent(s.handleForceReleaseLock))))
 	mux.HandleFunc("/lock/list", s.handleListLocks)
+	mux.HandleFunc("/lock/check", s.handleCheckLock)
+	mux.HandleFunc("/lock/history", s.handleLockHistory)
+	mux.HandleFunc("/negotiations/list", s.handleListNegotiations)
+	mux.HandleFunc("/negotiations/vote", s.permitted(application.PermWrite, s.idempotent(s.handleNegotiationVote)))
+	mux.HandleFunc("/negotiations/propose", s.permitted(application.PermWrite, s.idempotent(s.handleNegotiationPropose)))
+	mux.HandleFunc("/presence/report", s.permitted(application.PermWrite, s.handleReportActiveEdit))
+	mux.HandleFunc("/presence/list", s.handleListActiveEdits)
 	mux.HandleFunc("/peers/list", s.handleListPeers)
+	mux.HandleFunc("/peers/ban", s.permitted(application.PermOperate, s.authenticated(s.idempotent(s.handleBanPeer))))
+	mux.HandleFunc("/peers/unban", s.permitted(application.PermOperate, s.authenticated(s.idempotent(s.handleUnbanPeer))))
+	mux.HandleFunc("/peers/bans", s.handleListBans)
+	mux.HandleFunc("/topology", s.handleTopology)
 	mux.HandleFunc("/embed", s.handleEmbed)
 	mux.HandleFunc("/search", s.handleSearch)
+	mux.HandleFunc("/search/symbols", s.handleSearchSymbols)
 	mux.HandleFunc("/agents/list", s.handleListAgents)
-	mux.HandleFunc("/context/watch", s.handleWatchFile)
-	mux.HandleFunc("/context/share", s.handleShareContext)
+	mux.HandleFunc("/context/watch", s.permitted(application.PermWrite, s.handleWatchFile))
+	mux.HandleFunc("/context/share", s.idempotent(s.handleShareContext))
 	mux.HandleFunc("/context/stats", s.handleContextStats)
+	mux.HandleFunc("/context/provenance", s.handleProvenance)
+	mux.HandleFunc("/context/merges", s.handleContextMerges)
+	mux.HandleFunc("/context/rate", s.permitted(application.PermWrite, s.handleRateContext))
+	mux.HandleFunc("/collections/list", s.handleListCollections)
+	mux.HandleFunc("/collections/create", s.permitted(application.PermWrite, s.idempotent(s.handleCreateCollection)))
+	mux.HandleFunc("/collections/ttl", s.permitted(application.PermOperate, s.idempotent(s.handleCollectionTTL)))
+	mux.HandleFunc("/collections/delete", s.permitted(application.PermOperate, s.idempotent(s.handleDeleteCollection)))
 	mux.HandleFunc("/cohesion/check", s.handleCheckCohesion)
 	mux.HandleFunc("/events/list", s.handleListEvents)
+	mux.HandleFunc("/events/digest", s.handleDigest)
+	mux.HandleFunc("/work", s.handleWorkMap)
+	mux.HandleFunc("/conflicts/heatmap", s.handleConflictHeatmap)
 	mux.HandleFunc("/metrics", s.handleMetrics)
 	mux.HandleFunc("/tokens/usage", s.handleTokenUsage)
+	mux.HandleFunc("/snapshot/create", s.permitted(application.PermOperate, s.handleCreateSnapshot))
+	mux.HandleFunc("/snapshot/restore", s.permitted(application.PermOperate, s.handleRestoreSnapshot))
+	mux.HandleFunc("/context/export", s.permitted(application.PermOperate, s.handleExportCorpus))
+	mux.HandleFunc("/context/import", s.permitted(application.PermOperate, s.handleImportCorpus))
+	mux.HandleFunc("/config/reload", s.permitted(application.PermOperate, s.handleReloadConfig))
 	mux.HandleFunc("/shutdown", s.handleShutdown)
 }
 
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
-	status := s.app.GetStatus()
+	json.NewEncoder(w).Encode(s.statusResponse(s.app.GetStatus()))
+}
 
+// publishStatus streams status changes to event subscribers so dashboards
+// can watch the daemon without polling.
+func (s *Server) publishStatus(ctx context.Context) {
+	for status := range s.app.WatchStatus(ctx) {
+		s.PublishEvent(NewEvent(EventStatusUpdated, s.statusResponse(status)))
+	}
+}
+
+// publishPeerChanges samples the connected peers every interval and
+// publishes peer.connected and peer.disconnected events for the
+// difference. Peers connected at the first sample are not reported.
+func (s *Server) publishPeerChanges(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var known map[peer.ID]bool
+	for {
+		if node := s.app.Node(); node != nil {
+			current := make(map[peer.ID]bool)
+			for _, id := range node.ConnectedPeers() {
+				current[id] = true
+				if known != nil && !known[id] {
+					data := PeerEventData{PeerID: id.String()}
+					if addrs := node.PeerInfo(id).Addrs; len(addrs) > 0 {
+						data.Addr = addrs[0].String()
+					}
+					s.PublishEvent(NewEvent(EventPeerConnected, data))
+				}
+			}
+			for id := range known {
+				if !current[id] {
+					s.PublishEvent(NewEvent(EventPeerDisconnected, PeerEventData{PeerID: id.String()}))
+				}
+			}
+			known = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// statusResponse converts an app status into the daemon status response.
+func (s *Server) statusResponse(status *application.Status) StatusResponse {
 	resp := StatusResponse{
-		Running:     status.Running,
-		PID:         os.Getpid(),
-		StartedAt:   s.startedAt,
-		ProjectName: status.ProjectName,
-		NodeID:      status.NodeID,
-		PeerCount:   status.PeerCount,
-		LockCount:   status.LockCount,
+		Running:      status.Running,
+		PID:          os.Getpid(),
+		StartedAt:    s.startedAt,
+		ProjectName:  status.ProjectName,
+		NodeID:       status.NodeID,
+		PeerCount:    status.PeerCount,
+		LockCount:    status.LockCount,
+		Observer:     status.Observer,
+		Role:         status.Role,
+		Negotiations: status.Negotiations,
+		Regions:      status.Regions,
 	}
 
 	if s.app.AgentRegistry() != nil {
@@ -232,9 +454,41 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	// Add event subscriber count
 	resp.EventSubscribers = s.eventServer.ClientCount()
 
+	return resp
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(s.health(r.Context(), r.URL.Query().Get("verbose") == "true"))
+}
+
+// handleReadyz is handleHealth for orchestrators: it answers 503 until
+// every readiness condition passes.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	resp := s.health(r.Context(), r.URL.Query().Get("verbose") == "true")
+	if !resp.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
 	json.NewEncoder(w).Encode(resp)
 }
 
+// health evaluates the app's readiness conditions. Verbose also probes
+// each subsystem and reports its latency.
+func (s *Server) health(ctx context.Context, verbose bool) HealthResponse {
+	var readiness *application.Readiness
+	if verbose {
+		readiness = s.app.CheckReadinessVerbose(ctx)
+	} else {
+		readiness = s.app.CheckReadiness(ctx)
+	}
+	return HealthResponse{
+		Ready:      readiness.Ready,
+		Running:    s.app.GetStatus().Running,
+		Embedding:  readiness.Embedding,
+		Checks:     readiness.Checks,
+		Subsystems: readiness.Subsystems,
+	}
+}
+
 func (s *Server) handleInit(w http.ResponseWriter, r *http.Request) {
 	var req InitRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -266,6 +520,10 @@ func (s *Server) handleJoin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Observer {
+		s.app.Config().Observer = true
+	}
+
 	result, err := s.app.Join(s.ctx, req.Token)
 	if err != nil {
 		json.NewEncoder(w).Encode(JoinResponse{Error: err.Error()})
@@ -290,38 +548,96 @@ func (s *Server) handleAcquireLock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	result, err := s.acquireLock(r.Context(), req)
+	if retryAfter, ok := lock.RetryAfterHint(err); ok {
+		writeRateLimited(w, retryAfter)
+		json.NewEncoder(w).Encode(LockResponse{
+			Error:        err.Error(),
+			RetryAfterMs: max(retryAfter.Milliseconds(), 1),
+		})
+		return
+	}
+	if err != nil {
+		json.NewEncoder(w).Encode(LockResponse{Error: err.Error()})
+		return
+	}
+
+	lockID := ""
+	promotedTo := ""
+	var expiresAt time.Time
+	var fencingToken uint64
+	if result.Lock != nil {
+		lockID = result.Lock.ID
+		expiresAt = result.Lock.ExpiresAt
+		fencingToken = result.Lock.FencingToken
+		if result.Promoted {
+			promotedTo = result.Lock.Target.String()
+		}
+	}
+
+	json.NewEncoder(w).Encode(LockResponse{
+		Success:      result.Success,
+		LockID:       lockID,
+		ExpiresAt:    expiresAt,
+		FencingToken: fencingToken,
+		Error:        result.Reason,
+		ConflictHint: result.ConflictHint,
+		PromotedTo:   promotedTo,
+		Preempted:    result.Preempted,
+	})
+}
+
+// acquireLock takes a lock for a local agent and publishes the outcome.
+// A waiting request gives up when ctx, the caller's request, is done.
+// Shared by the HTTP and gRPC APIs.
+func (s *Server) acquireLock(ctx context.Context, req LockRequest) (*lock.LockResult, error) {
+	if err := s.app.Authorize(application.PermWrite); err != nil {
+		return nil, err
+	}
 	lockService := s.app.LockService()
 	if lockService == nil {
-		json.NewEncoder(w).Encode(LockResponse{Error: "lock service not initialized"})
-		return
+		return nil, errLockServiceUnavailable
 	}
 
-	result, err := lockService.AcquireLock(s.ctx, &lock.AcquireLockRequest{
+	lockReq := &lock.AcquireLockRequest{
 		TargetType: lock.TargetFile,
 		FilePath:   req.FilePath,
 		StartLine:  req.StartLine,
 		EndLine:    req.EndLine,
 		Intention:  req.Intention,
-	})
-
+		Mode:       lock.AccessMode(req.Mode),
+		TTL:        time.Duration(req.TTLSeconds) * time.Second,
+		Deadline:   req.Deadline,
+		Priority:   req.Priority,
+	}
+	var result *lock.LockResult
+	var err error
+	if req.WaitForLock {
+		waitCtx, cancel := context.WithTimeout(ctx, req.WaitTimeout())
+		defer cancel()
+		result, err = lockService.AcquireLockWait(waitCtx, lockReq)
+	} else {
+		result, err = lockService.AcquireLock(s.ctx, lockReq)
+	}
 	if err != nil {
-		json.NewEncoder(w).Encode(LockResponse{Error: err.Error()})
-		return
+		return nil, err
 	}
 
-	lockID := ""
 	if result.Lock != nil {
-		lockID = result.Lock.ID
-
-		// Publish lock acquired event
+		// Publish lock acquired event with the range actually locked,
+		// which differs from the request when it was promoted
 		s.PublishEvent(NewEvent(EventLockAcquired, LockEventData{
-			LockID:    lockID,
+			LockID:    result.Lock.ID,
 			FilePath:  req.FilePath,
-			StartLine: req.StartLine,
-			EndLine:   req.EndLine,
+			StartLine: result.Lock.Target.StartLine,
+			EndLine:   result.Lock.Target.EndLine,
 			AgentID:   result.Lock.HolderID,
 			Intention: req.Intention,
+			Mode:      string(result.Lock.EffectiveMode()),
 		}))
+		for _, notice := range result.Preempted {
+			s.PublishEvent(NewEvent(EventLockForceReleased, notice))
+		}
 	} else if !result.Success {
 		// Publish lock conflict event
 		s.PublishEvent(NewEvent(EventLockConflict, LockConflictData{
@@ -331,14 +647,69 @@ func (s *Server) handleAcquireLock(w http.ResponseWriter, r *http.Request) {
 			Intention:   req.Intention,
 		}))
 	}
+	if result.ConflictHint.Elevated() {
+		s.PublishEvent(NewEvent(EventLockConflictPredicted, result.ConflictHint))
+	}
+	return result, nil
+}
+
+// writeRateLimited sets the 429 status and a Retry-After header, which
+// carries whole seconds; the response body holds the precise hint.
+func writeRateLimited(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.FormatInt(max(seconds, 1), 10))
+	w.WriteHeader(http.StatusTooManyRequests)
+}
+
+func (s *Server) handleUpgradeLock(w http.ResponseWriter, r *http.Request) {
+	var req UpgradeLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(LockResponse{Error: err.Error()})
+		return
+	}
 
+	upgraded, err := s.upgradeLock(req.LockID)
+	if err != nil {
+		json.NewEncoder(w).Encode(LockResponse{LockID: req.LockID, Error: err.Error()})
+		return
+	}
 	json.NewEncoder(w).Encode(LockResponse{
-		Success: result.Success,
-		LockID:  lockID,
-		Error:   result.Reason,
+		Success:      true,
+		LockID:       upgraded.ID,
+		ExpiresAt:    upgraded.ExpiresAt,
+		FencingToken: upgraded.FencingToken,
 	})
 }
 
+// upgradeLock turns one of this node's read locks into a write lock and
+// publishes the upgrade. While other agents read the region it fails with
+// lock.ErrLockConflict and a negotiation with them is started instead.
+func (s *Server) upgradeLock(lockID string) (*lock.SemanticLock, error) {
+	if err := s.app.Authorize(application.PermWrite); err != nil {
+		return nil, err
+	}
+	lockService := s.app.LockService()
+	if lockService == nil {
+		return nil, errLockServiceUnavailable
+	}
+
+	result, err := lockService.UpgradeLock(s.ctx, lockID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.PublishEvent(NewEvent(EventLockUpgraded, LockEventData{
+		LockID:    result.Lock.ID,
+		FilePath:  result.Lock.Target.FilePath,
+		StartLine: result.Lock.Target.StartLine,
+		EndLine:   result.Lock.Target.EndLine,
+		AgentID:   result.Lock.HolderID,
+		Intention: result.Lock.Intention,
+		Mode:      string(result.Lock.EffectiveMode()),
+	}))
+	return result.Lock, nil
+}
+
 func (s *Server) handleReleaseLock(w http.ResponseWriter, r *http.Request) {
 	var req ReleaseLockRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -346,23 +717,130 @@ func (s *Server) handleReleaseLock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := s.releaseLock(req.LockID); err != nil {
+		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
+		return
+	}
+
+	json.NewEncoder(w).Encode(GenericResponse{Success: true, Message: "Lock released"})
+}
+
+// releaseLock releases one of this node's locks and publishes the release.
+func (s *Server) releaseLock(lockID string) error {
+	if err := s.app.Authorize(application.PermWrite); err != nil {
+		return err
+	}
 	lockService := s.app.LockService()
 	if lockService == nil {
-		json.NewEncoder(w).Encode(GenericResponse{Error: "lock service not initialized"})
-		return
+		return errLockServiceUnavailable
 	}
 
-	if err := lockService.ReleaseLock(s.ctx, req.LockID); err != nil {
-		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
-		return
+	if err := lockService.ReleaseLock(s.ctx, lockID); err != nil {
+		return err
 	}
 
 	// Publish lock released event
 	s.PublishEvent(NewEvent(EventLockReleased, LockEventData{
-		LockID: req.LockID,
+		LockID: lockID,
 	}))
+	return nil
+}
 
-	json.NewEncoder(w).Encode(GenericResponse{Success: true, Message: "Lock released"})
+func (s *Server) handleReleaseLocksByIntention(w http.ResponseWriter, r *http.Request) {
+	var req ReleaseLocksByIntentionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(ReleaseLocksByIntentionResponse{Error: err.Error()})
+		return
+	}
+
+	lockService := s.app.LockService()
+	if lockService == nil {
+		json.NewEncoder(w).Encode(ReleaseLocksByIntentionResponse{Error: "lock service not initialized"})
+		return
+	}
+
+	released, err := lockService.ReleaseLocksByIntention(s.ctx, req.Intention, req.Prefix)
+	if err != nil {
+		json.NewEncoder(w).Encode(ReleaseLocksByIntentionResponse{Error: err.Error()})
+		return
+	}
+
+	for _, l := range released {
+		s.PublishEvent(NewEvent(EventLockReleased, LockEventData{
+			LockID:    l.ID,
+			FilePath:  l.Target.FilePath,
+			StartLine: l.Target.StartLine,
+			EndLine:   l.Target.EndLine,
+			Intention: l.Intention,
+		}))
+	}
+
+	json.NewEncoder(w).Encode(ReleaseLocksByIntentionResponse{Success: true, Released: released})
+}
+
+func (s *Server) handleRenewLock(w http.ResponseWriter, r *http.Request) {
+	var req RenewLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(RenewLockResponse{Error: err.Error()})
+		return
+	}
+
+	renewed, err := s.renewLock(req)
+	if err != nil {
+		json.NewEncoder(w).Encode(RenewLockResponse{Error: err.Error()})
+		return
+	}
+
+	json.NewEncoder(w).Encode(RenewLockResponse{Success: true, Lock: renewed})
+}
+
+// renewLock extends a lock's lease, announces it to local subscribers and
+// returns the renewed lock. Shared by the HTTP and gRPC APIs.
+func (s *Server) renewLock(req RenewLockRequest) (*lock.SemanticLock, error) {
+	if err := s.app.Authorize(application.PermWrite); err != nil {
+		return nil, err
+	}
+	lockService := s.app.LockService()
+	if lockService == nil {
+		return nil, errLockServiceUnavailable
+	}
+
+	renewed, err := lockService.RenewLease(s.ctx, &lock.RenewLeaseRequest{
+		LockID:       req.LockID,
+		FencingToken: req.FencingToken,
+		TTL:          time.Duration(req.TTLSeconds) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.PublishEvent(NewEvent(EventLockRenewed, renewed))
+	return renewed, nil
+}
+
+func (s *Server) handleForceReleaseLock(w http.ResponseWriter, r *http.Request) {
+	var req ForceReleaseLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(ForceReleaseLockResponse{Error: err.Error()})
+		return
+	}
+
+	lockService := s.app.LockService()
+	if lockService == nil {
+		json.NewEncoder(w).Encode(ForceReleaseLockResponse{Error: "lock service not initialized"})
+		return
+	}
+
+	notice, err := lockService.ForceReleaseLock(s.ctx, req.LockID, req.Reason, operatorFromContext(r.Context()))
+	if err != nil {
+		json.NewEncoder(w).Encode(ForceReleaseLockResponse{Error: err.Error()})
+		return
+	}
+
+	// Notify local agents, including the original holder
+	s.PublishEvent(NewEvent(EventLockForceReleased, notice))
+
+	json.NewEncoder(w).Encode(ForceReleaseLockResponse{Success: true, Notice: notice})
 }
 
 func (s *Server) handleListLocks(w http.ResponseWriter, r *http.Request) {
@@ -376,6 +854,91 @@ func (s *Server) handleListLocks(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(ListLocksResponse{Locks: locks})
 }
 
+// handleCheckLock reports whether a region is free without announcing an
+// intent to lock it.
+func (s *Server) handleCheckLock(w http.ResponseWriter, r *http.Request) {
+	var req LockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(CheckLockResponse{Error: err.Error()})
+		return
+	}
+
+	lockService := s.app.LockService()
+	if lockService == nil {
+		json.NewEncoder(w).Encode(CheckLockResponse{Error: errLockServiceUnavailable.Error()})
+		return
+	}
+
+	check, err := lockService.CheckLock(&lock.AcquireLockRequest{
+		TargetType: lock.TargetFile,
+		FilePath:   req.FilePath,
+		StartLine:  req.StartLine,
+		EndLine:    req.EndLine,
+		Mode:       lock.AccessMode(req.Mode),
+	})
+	if err != nil {
+		json.NewEncoder(w).Encode(CheckLockResponse{Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(CheckLockResponse{LockCheck: check})
+}
+
+func (s *Server) handleReportActiveEdit(w http.ResponseWriter, r *http.Request) {
+	var req ReportActiveEditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(ReportActiveEditResponse{Error: err.Error()})
+		return
+	}
+
+	tracker := s.app.Presence()
+	if tracker == nil {
+		json.NewEncoder(w).Encode(ReportActiveEditResponse{Error: "presence not initialized"})
+		return
+	}
+
+	if req.Clear {
+		if err := tracker.Clear(req.FilePath); err != nil {
+			json.NewEncoder(w).Encode(ReportActiveEditResponse{Error: err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(ReportActiveEditResponse{Success: true})
+		return
+	}
+
+	edit, err := tracker.Report(req.FilePath, req.StartLine, req.EndLine)
+	if err != nil && edit == nil {
+		json.NewEncoder(w).Encode(ReportActiveEditResponse{Error: err.Error()})
+		return
+	}
+	// A failed broadcast still leaves the edit recorded locally; peers pick
+	// it up on the next report.
+	json.NewEncoder(w).Encode(ReportActiveEditResponse{
+		Success: true,
+		Edit:    edit,
+		Others:  tracker.ActiveIn(req.FilePath, req.StartLine, req.EndLine),
+	})
+}
+
+func (s *Server) handleListActiveEdits(w http.ResponseWriter, r *http.Request) {
+	tracker := s.app.Presence()
+	if tracker == nil {
+		json.NewEncoder(w).Encode(ListActiveEditsResponse{})
+		return
+	}
+
+	edits := tracker.Active()
+	if filePath := r.URL.Query().Get("file_path"); filePath != "" {
+		filtered := edits[:0]
+		for _, edit := range edits {
+			if edit.FilePath == filePath {
+				filtered = append(filtered, edit)
+			}
+		}
+		edits = filtered
+	}
+	json.NewEncoder(w).Encode(ListActiveEditsResponse{Edits: edits, TTL: tracker.TTL().String()})
+}
+
 func (s *Server) handleListPeers(w http.ResponseWriter, r *http.Request) {
 	node := s.app.Node()
 	if node == nil {
@@ -386,6 +949,15 @@ func (s *Server) handleListPeers(w http.ResponseWriter, r *http.Request) {
 	connectedPeers := node.ConnectedPeers()
 	peers := make([]PeerInfo, 0, len(connectedPeers))
 
+	var qualities map[peer.ID]*libp2p.PeerQuality
+	if qm := node.QualityMonitor(); qm != nil {
+		qualities = qm.GetAllQualities()
+	}
+	var deliveries map[peer.ID]libp2p.PeerDeliveryStats
+	if lp := node.LockPush(); lp != nil {
+		deliveries = lp.PeerStats()
+	}
+
 	for _, peerID := range connectedPeers {
 		info := node.PeerInfo(peerID)
 		addrs := make([]string, len(info.Addrs))
@@ -394,18 +966,87 @@ func (s *Server) handleListPeers(w http.ResponseWriter, r *http.Request) {
 		}
 
 		latency := node.Latency(peerID)
+		delivery, pushed := deliveries[peerID]
+		// Fall back to measured round trips before the peerstore has a sample
+		if q := qualities[peerID]; latency == 0 && q != nil {
+			latency = q.RTT
+		}
+		if latency == 0 && pushed {
+			latency = delivery.RTT
+		}
 
-		peers = append(peers, PeerInfo{
+		pi := PeerInfo{
 			ID:        peerID.String(),
 			Addresses: addrs,
 			Latency:   latency.Milliseconds(),
 			Connected: true,
-		})
+			Transport: string(node.Transport(peerID)),
+		}
+		pi.Quality = qualities[peerID]
+		if lm := node.LocalityManager(); lm != nil {
+			pi.Locality = lm.GetLocality(peerID)
+		}
+		if pushed {
+			pi.Delivery = &delivery
+		}
+		peers = append(peers, pi)
 	}
 
 	json.NewEncoder(w).Encode(ListPeersResponse{Peers: peers})
 }
 
+func (s *Server) handleTopology(w http.ResponseWriter, r *http.Request) {
+	node := s.app.Node()
+	if node == nil {
+		json.NewEncoder(w).Encode(TopologyResponse{Error: "node not initialized"})
+		return
+	}
+
+	tm := node.TopologyManager()
+	lm := node.LocalityManager()
+
+	superPeers := make(map[string]bool)
+	selfRole := "peer"
+	if tm != nil {
+		selfRole = tm.GetRole().String()
+		for _, id := range tm.GetSuperPeers() {
+			superPeers[id.String()] = true
+		}
+	}
+
+	self := node.ID()
+	resp := TopologyResponse{
+		Nodes: []TopologyNode{{ID: self.String(), Role: selfRole, Self: true}},
+		Edges: []TopologyEdge{},
+	}
+	if lm != nil {
+		resp.Nodes[0].Region = lm.GetMyRegion()
+	}
+
+	for _, id := range node.ConnectedPeers() {
+		n := TopologyNode{ID: id.String(), Role: "peer"}
+		if tm != nil {
+			n.Role = "leaf"
+			if superPeers[id.String()] {
+				n.Role = "super"
+			}
+		}
+		if lm != nil {
+			if loc := lm.GetLocality(id); loc != nil {
+				n.Region = loc.Region
+			}
+		}
+		resp.Nodes = append(resp.Nodes, n)
+		resp.Edges = append(resp.Edges, TopologyEdge{
+			From:    self.String(),
+			To:      id.String(),
+			Latency: node.Latency(id).Milliseconds(),
+		})
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
 func (s *Server) handleEmbed(w http.ResponseWriter, r *http.Request) {
 	var req EmbedRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -440,18 +1081,27 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	searchResults, err := s.search(req)
+	if err != nil {
+		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
+		return
+	}
+
+	json.NewEncoder(w).Encode(SearchResponse{Results: searchResults})
+}
+
+// search finds shared context similar to the query.
+func (s *Server) search(req SearchRequest) ([]SearchResult, error) {
 	vectorStore := s.app.VectorStore()
 	embedService := s.app.EmbeddingService()
 	if vectorStore == nil || embedService == nil {
-		json.NewEncoder(w).Encode(GenericResponse{Error: "services not initialized"})
-		return
+		return nil, errSearchUnavailable
 	}
 
 	// Generate embedding for query
-	embedding, err := embedService.Embed(s.ctx, req.Query)
+	embedding, err := embedService.EmbedQuery(s.ctx, req.Query)
 	if err != nil {
-		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
-		return
+		return nil, err
 	}
 
 	limit := req.Limit
@@ -460,12 +1110,13 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	results, err := vectorStore.Search(embedding, &vector.SearchOptions{
-		Collection: "default",
+		Collection: req.Collection,
 		TopK:       limit,
+		MinScore:   req.MinScore,
+		Filters:    application.BranchFilter(req.Branch),
 	})
 	if err != nil {
-		json.NewEncoder(w).Encode(GenericResponse{Error: err.Error()})
-		return
+		return nil, err
 	}
 
 	// Convert results
@@ -480,8 +1131,7 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 			searchResults[i].Metadata = r.Document.Metadata
 		}
 	}
-
-	json.NewEncoder(w).Encode(SearchResponse{Results: searchResults})
+	return searchResults, nil
 }
 
 func (s *Server) handleListAgents(w http.ResponseWriter, r *http.Request) {
@@ -495,6 +1145,33 @@ func (s *Server) handleListAgents(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(ListAgentsResponse{Agents: agents})
 }
 
+// handleRateContext records relevance feedback on a search result.
+func (s *Server) handleRateContext(w http.ResponseWriter, r *http.Request) {
+	var req RateContextRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(RateContextResponse{Error: err.Error()})
+		return
+	}
+
+	rating, err := s.app.RateContext(req.DocumentID, req.Useful)
+	if err != nil {
+		json.NewEncoder(w).Encode(RateContextResponse{Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(RateContextResponse{Rating: rating})
+}
+
+// handleProvenance returns the provenance chain of a shared document.
+func (s *Server) handleProvenance(w http.ResponseWriter, r *http.Request) {
+	documentID := r.URL.Query().Get("document_id")
+	chain, err := s.app.GetProvenance(documentID)
+	if err != nil {
+		json.NewEncoder(w).Encode(ProvenanceResponse{DocumentID: documentID, Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(ProvenanceResponse{DocumentID: documentID, Chain: chain})
+}
+
 func (s *Server) handleWatchFile(w http.ResponseWriter, r *http.Request) {
 	var req WatchFileRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -528,48 +1205,40 @@ func (s *Server) handleShareContext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Content == "" {
-		json.NewEncoder(w).Encode(ShareContextResponse{Error: "content is required"})
-		return
-	}
-
-	vectorStore := s.app.VectorStore()
-	embedService := s.app.EmbeddingService()
-	if vectorStore == nil || embedService == nil {
-		json.NewEncoder(w).Encode(ShareContextResponse{Error: "services not initialized"})
-		return
-	}
-
-	// Generate embedding for the content
-	embedding, err := embedService.Embed(s.ctx, req.Content)
+	resp, err := s.shareContext(req)
 	if err != nil {
-		json.NewEncoder(w).Encode(ShareContextResponse{Error: fmt.Sprintf("embedding failed: %v", err)})
+		json.NewEncoder(w).Encode(ShareContextResponse{Error: err.Error()})
 		return
 	}
+	json.NewEncoder(w).Encode(resp)
+}
 
-	// Create document
-	doc := &vector.Document{
-		Content:   req.Content,
-		Embedding: embedding,
-		FilePath:  req.FilePath,
-		Metadata:  req.Metadata,
+// shareContext stores and broadcasts context shared by a local agent.
+func (s *Server) shareContext(req ShareContextRequest) (ShareContextResponse, error) {
+	if req.Content == "" {
+		return ShareContextResponse{}, errContentRequired
 	}
 
-	// Insert into vector store
-	if err := vectorStore.Insert(doc); err != nil {
-		json.NewEncoder(w).Encode(ShareContextResponse{Error: fmt.Sprintf("insert failed: %v", err)})
-		return
+	if err := s.app.Authorize(application.PermWrite); err != nil {
+		return ShareContextResponse{}, err
 	}
 
-	// Flush to persist
-	if err := vectorStore.Flush(); err != nil {
-		json.NewEncoder(w).Encode(ShareContextResponse{Error: fmt.Sprintf("flush failed: %v", err)})
-		return
+	result, err := s.app.ShareDerivedContext(s.ctx, req.FilePath, req.Content, req.Metadata, req.ParentIDs)
+	if err != nil {
+		return ShareContextResponse{}, err
+	}
+	if result.Duplicate {
+		return ShareContextResponse{
+			Success:    true,
+			DocumentID: result.DocumentID,
+			Duplicate:  true,
+			Message:    "Identical context was already shared; nothing stored or broadcast",
+		}, nil
 	}
 
-	// Broadcast via P2P for other peers
-	if err := s.app.BroadcastContext(req.FilePath, req.Content, embedding, req.Metadata); err != nil {
-		fmt.Printf("Warning: failed to broadcast context: %v\n", err)
+	// Sharing context on a file counts as activity on our locks there
+	if lockService := s.app.LockService(); lockService != nil && req.FilePath != "" {
+		lockService.TouchFile(req.FilePath)
 	}
 
 	// Also watch the file if it exists (for future changes)
@@ -584,14 +1253,11 @@ func (s *Server) handleShareContext(w http.ResponseWriter, r *http.Request) {
 		Content:  req.Content,
 	}))
 
-	// Publish to EventRouter for Interest-based routing
-	s.publishToEventRouter(req.FilePath, req.Content, embedding)
-
-	json.NewEncoder(w).Encode(ShareContextResponse{
+	return ShareContextResponse{
 		Success:    true,
-		DocumentID: doc.ID,
-		Message:    fmt.Sprintf("Context shared and stored (embedding: %d dims)", len(embedding)),
-	})
+		DocumentID: result.DocumentID,
+		Message:    fmt.Sprintf("Context shared and stored (embedding: %d dims)", len(result.Embedding)),
+	}, nil
 }
 
 func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
@@ -603,7 +1269,9 @@ func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
 	}
 
 	eventType := r.URL.Query().Get("type")
-	includeAll := r.URL.Query().Get("include_all") == "true"
+	agent := r.URL.Query().Get("agent")
+	// Observers have no interests of their own, so they always see the full feed
+	includeAll := r.URL.Query().Get("include_all") == "true" || s.app.IsObserver()
 
 	// Try EventRouter first for Interest-based filtering
 	eventRouter := s.app.EventRouter()
@@ -612,6 +1280,7 @@ func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
 		filter := &event.EventFilter{
 			Limit:      limit,
 			IncludeAll: includeAll,
+			Agent:      agent,
 		}
 		if eventType != "" {
 			filter.Types = []event.EventType{event.EventType(eventType)}
@@ -637,12 +1306,7 @@ func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fallback to local eventBus
-	var events []Event
-	if eventType != "" {
-		events = s.eventBus.GetEventsByType(EventType(eventType), limit)
-	} else {
-		events = s.eventBus.GetRecentEvents(limit)
-	}
+	events := s.listBusEvents(eventType, agent, limit)
 
 	json.NewEncoder(w).Encode(map[string]any{
 		"events": events,
@@ -650,7 +1314,131 @@ func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// listBusEvents returns recent EventBus events, optionally scoped by type and agent.
+func (s *Server) listBusEvents(eventType, agent string, limit int) []Event {
+	if agent == "" {
+		if eventType != "" {
+			return s.eventBus.GetEventsByType(EventType(eventType), limit)
+		}
+		return s.eventBus.GetRecentEvents(limit)
+	}
+
+	var events []Event
+	for _, e := range s.eventBus.GetRecentEvents(0) {
+		if eventType != "" && e.Type != EventType(eventType) {
+			continue
+		}
+		if e.MatchesAgent(agent) {
+			events = append(events, e)
+		}
+	}
+	if len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events
+}
+
+func (s *Server) handleDigest(w http.ResponseWriter, r *http.Request) {
+	since := time.Now().Add(-24 * time.Hour)
+	if v := r.URL.Query().Get("since"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			json.NewEncoder(w).Encode(DigestResponse{Error: fmt.Sprintf("invalid since: %v", err)})
+			return
+		}
+		since = parsed
+	}
+	condense := r.URL.Query().Get("condense") == "true"
+
+	// Lock activity only lives on the local EventBus
+	lockEvents := lockEventsForDigest(s.eventBus.GetEventsSince(since))
+
+	var digest *event.Digest
+	eventRouter := s.app.EventRouter()
+	if eventRouter != nil {
+		var err error
+		digest, err = eventRouter.Digest(s.ctx, since, lockEvents, condense)
+		if err != nil {
+			fmt.Printf("Warning: failed to condense digest: %v\n", err)
+		}
+	} else {
+		digest = event.BuildDigest(lockEvents, since)
+	}
+
+	summary := digest.Condensed
+	if summary == "" {
+		summary = digest.Format()
+	}
+
+	json.NewEncoder(w).Encode(DigestResponse{
+		Digest:  digest,
+		Summary: summary,
+	})
+}
+
+func (s *Server) handleConflictHeatmap(w http.ResponseWriter, r *http.Request) {
+	since := time.Now().Add(-24 * time.Hour)
+	if v := r.URL.Query().Get("since"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			json.NewEncoder(w).Encode(ConflictHeatmapResponse{Error: fmt.Sprintf("invalid since: %v", err)})
+			return
+		}
+		since = parsed
+	}
+
+	events := lockEventsForDigest(s.eventBus.GetEventsSince(since))
+	if eventRouter := s.app.EventRouter(); eventRouter != nil {
+		events = append(events, eventRouter.EventLog().GetSince(since)...)
+	}
+
+	json.NewEncoder(w).Encode(ConflictHeatmapResponse{
+		Since:    since,
+		Hotspots: event.BuildConflictHeatmap(events, since),
+	})
+}
+
+// lockEventsForDigest converts local lock events into domain events.
+func lockEventsForDigest(events []Event) []*event.Event {
+	var result []*event.Event
+	for _, e := range events {
+		switch e.Type {
+		case EventLockAcquired, EventLockReleased:
+			var data LockEventData
+			if err := json.Unmarshal(e.Data, &data); err != nil {
+				continue
+			}
+			var de *event.Event
+			payload := &event.LockPayload{LockID: data.LockID, HolderID: data.AgentID, Purpose: data.Intention}
+			if e.Type == EventLockAcquired {
+				de = event.NewLockAcquiredEvent(data.AgentID, "", data.FilePath, data.StartLine, data.EndLine, payload)
+			} else {
+				de = event.NewLockReleasedEvent(data.AgentID, "", payload)
+				de.FilePath = data.FilePath
+			}
+			de.Timestamp = e.Timestamp
+			result = append(result, de)
+		case EventLockConflict:
+			var data LockConflictData
+			if err := json.Unmarshal(e.Data, &data); err != nil {
+				continue
+			}
+			de := event.NewLockConflictEvent(data.RequesterID, "", data.FilePath, &event.LockConflictPayload{
+				ConflictingHolder: data.HolderID,
+			})
+			de.Timestamp = e.Timestamp
+			result = append(result, de)
+		}
+	}
+	return result
+}
+
 func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if wantsPrometheus(r) {
+		s.metricsHandler.ServeHTTP(w, r)
+		return
+	}
+
 	node := s.app.Node()
 	if node == nil {
 		json.NewEncoder(w).Encode(map[string]any{
@@ -659,8 +1447,22 @@ func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	snapshot := node.GetMetricsSnapshot()
-	json.NewEncoder(w).Encode(snapshot)
+	resp := MetricsResponse{MetricsSnapshot: node.GetMetricsSnapshot()}
+	if pm := s.app.ProcessingMetrics(); pm != nil {
+		resp.Processing = pm.Snapshot()
+	}
+	if router := s.app.EventRouter(); router != nil {
+		stats := router.BackpressureStats()
+		resp.Events = &stats
+	}
+	if lockService := s.app.LockService(); lockService != nil {
+		resp.Negotiations = lockService.NegotiationMetrics()
+	}
+	if embedService := s.app.EmbeddingService(); embedService != nil {
+		stats := embedService.ConcurrencyStats()
+		resp.Embeddings = &stats
+	}
+	json.NewEncoder(w).Encode(resp)
 }
 
 func (s *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
@@ -699,7 +1501,7 @@ func (s *Server) handleCheckCohesion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate embedding for query
-	embedding, err := embedService.Embed(s.ctx, queryText)
+	embedding, err := embedService.EmbedQuery(s.ctx, queryText)
 	if err != nil {
 		json.NewEncoder(w).Encode(CheckCohesionResponse{Error: fmt.Sprintf("embedding failed: %v", err)})
 		return
@@ -707,8 +1509,7 @@ func (s *Server) handleCheckCohesion(w http.ResponseWriter, r *http.Request) {
 
 	// Search for similar contexts
 	results, err := vectorStore.Search(embedding, &vector.SearchOptions{
-		Collection: "default",
-		TopK:       10,
+		TopK: 10,
 	})
 	if err != nil {
 		json.NewEncoder(w).Encode(CheckCohesionResponse{Error: fmt.Sprintf("search failed: %v", err)})
@@ -851,12 +1652,8 @@ func (s *Server) getAgentID() string {
 	return ""
 }
 
-// publishToEventRouter publishes context shared event to EventRouter for Interest-based routing.
-func (s *Server) publishToEventRouter(filePath, content string, embedding []float32) {
-	s.app.PublishContextSharedEvent(s.ctx, filePath, content, embedding)
-}
-
-// registerInterestsFromEnv registers interests from AGENT_COLLAB_INTERESTS environment variable.
+// registerInterestsFromEnv registers interests from the configured interest
+// profile and the AGENT_COLLAB_INTERESTS environment variable.
 func (s *Server) registerInterestsFromEnv(agentID string) {
 	interestMgr := s.app.InterestManager()
 	if interestMgr == nil {
@@ -869,8 +1666,13 @@ func (s *Server) registerInterestsFromEnv(agentID string) {
 		agentName = "Agent-" + agentID[:8]
 	}
 
-	// Register interests from environment
-	registered, err := interest.RegisterFromEnvironment(interestMgr, agentID, agentName)
+	// Register interests from profile and environment
+	var profiles interest.Profiles
+	var profile string
+	if cfg := s.app.Config(); cfg != nil {
+		profiles, profile = cfg.InterestProfiles, cfg.InterestProfile
+	}
+	registered, err := interest.RegisterFromConfig(interestMgr, agentID, agentName, profiles, profile)
 	if err != nil {
 		s.PublishEvent(NewEvent(EventWarning, map[string]string{
 			"message": fmt.Sprintf("Failed to register interests: %v", err),
@@ -884,6 +1686,7 @@ func (s *Server) registerInterestsFromEnv(agentID string) {
 			"agent_name": agentName,
 			"patterns":   registered.Patterns,
 			"level":      registered.Level.String(),
+			"profile":    registered.Metadata["profile"],
 		}))
 	}
 }
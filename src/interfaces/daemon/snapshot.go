@@ -0,0 +1,57 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"agent-collab/src/application"
+)
+
+// SnapshotTimeout bounds snapshot requests, which write or read the whole
+// vector store.
+const SnapshotTimeout = 5 * time.Minute
+
+// RestoreSnapshotRequest is a request to restore a snapshot archive.
+type RestoreSnapshotRequest struct {
+	// Path is the archive on the daemon's host.
+	Path string `json:"path"`
+}
+
+// SnapshotResponse is the response to creating or restoring a snapshot.
+type SnapshotResponse struct {
+	Success bool                         `json:"success"`
+	Summary *application.SnapshotSummary `json:"summary,omitempty"`
+	Error   string                       `json:"error,omitempty"`
+}
+
+// handleCreateSnapshot handles the /snapshot/create endpoint.
+func (s *Server) handleCreateSnapshot(w http.ResponseWriter, _ *http.Request) {
+	summary, err := s.app.Snapshot()
+	if err != nil {
+		json.NewEncoder(w).Encode(SnapshotResponse{Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(SnapshotResponse{Success: true, Summary: summary})
+}
+
+// handleRestoreSnapshot handles the /snapshot/restore endpoint. Restores
+// only add state the node does not have, so they need no operator token.
+func (s *Server) handleRestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	var req RestoreSnapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(SnapshotResponse{Error: err.Error()})
+		return
+	}
+	if req.Path == "" {
+		json.NewEncoder(w).Encode(SnapshotResponse{Error: "path is required"})
+		return
+	}
+
+	summary, err := s.app.Restore(req.Path)
+	if err != nil {
+		json.NewEncoder(w).Encode(SnapshotResponse{Summary: summary, Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(SnapshotResponse{Success: true, Summary: summary})
+}
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+
+	"agent-collab/src/domain/ctxsync"
+	"agent-collab/src/domain/token"
 )
 
 // TokenUsageResponse represents token usage statistics.
@@ -19,17 +22,26 @@ type TokenUsageResponse struct {
 	UsagePercent  float64 `json:"usage_percent"`
 	Provider      string  `json:"provider,omitempty"`
 	Model         string  `json:"model,omitempty"`
+
+	// Work shed under overload today
+	ShedToday       int64 `json:"shed_today,omitempty"`
+	ShedTokensToday int64 `json:"shed_tokens_today,omitempty"`
+
+	// Today's usage and cost per model, most expensive first
+	ByModel []token.ModelUsage `json:"by_model,omitempty"`
 }
 
 // ContextStatsResponse represents context statistics.
 type ContextStatsResponse struct {
-	TotalDocuments  int64             `json:"total_documents"`
-	TotalEmbeddings int64             `json:"total_embeddings"`
-	SharedContexts  int64             `json:"shared_contexts"`
-	WatchedFiles    int               `json:"watched_files"`
-	PendingDeltas   int               `json:"pending_deltas"`
-	Collections     []CollectionStats `json:"collections,omitempty"`
-	RecentActivity  []ContextActivity `json:"recent_activity,omitempty"`
+	TotalDocuments  int64                    `json:"total_documents"`
+	TotalEmbeddings int64                    `json:"total_embeddings"`
+	SharedContexts  int64                    `json:"shared_contexts"`
+	WatchedFiles    int                      `json:"watched_files"`
+	PendingDeltas   int                      `json:"pending_deltas"`
+	CompactedDeltas int                      `json:"compacted_deltas,omitempty"`
+	Compaction      *ctxsync.CompactionStats `json:"compaction,omitempty"`
+	Collections     []CollectionStats        `json:"collections,omitempty"`
+	RecentActivity  []ContextActivity        `json:"recent_activity,omitempty"`
 }
 
 // CollectionStats represents stats for a single collection.
@@ -37,6 +49,10 @@ type CollectionStats struct {
 	Name      string `json:"name"`
 	Count     int64  `json:"count"`
 	Dimension int    `json:"dimension"`
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+	// TTL is how long documents are kept after their last update, e.g.
+	// "72h0m0s"; empty keeps them until deleted
+	TTL string `json:"ttl,omitempty"`
 }
 
 // ContextActivity represents recent context activity.
@@ -58,15 +74,18 @@ func (s *Server) handleTokenUsage(w http.ResponseWriter, _ *http.Request) {
 	metrics := tokenTracker.GetMetrics()
 
 	resp := TokenUsageResponse{
-		TokensToday:   metrics.TokensToday,
-		TokensWeek:    metrics.TokensWeek,
-		TokensMonth:   metrics.TokensMonth,
-		TokensPerHour: metrics.TokensPerHour,
-		CostToday:     metrics.CostToday,
-		CostWeek:      metrics.CostWeek,
-		CostMonth:     metrics.CostMonth,
-		DailyLimit:    metrics.DailyLimit,
-		UsagePercent:  metrics.UsagePercent(),
+		TokensToday:     metrics.TokensToday,
+		TokensWeek:      metrics.TokensWeek,
+		TokensMonth:     metrics.TokensMonth,
+		TokensPerHour:   metrics.TokensPerHour,
+		CostToday:       metrics.CostToday,
+		CostWeek:        metrics.CostWeek,
+		CostMonth:       metrics.CostMonth,
+		DailyLimit:      metrics.DailyLimit,
+		UsagePercent:    metrics.UsagePercent(),
+		ShedToday:       metrics.ShedToday,
+		ShedTokensToday: metrics.ShedTokensToday,
+		ByModel:         metrics.ModelBreakdown(),
 	}
 
 	// Add provider info if embedding service is available
@@ -87,16 +106,11 @@ func (s *Server) handleContextStats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get vector store stats
-	vectorStore := s.app.VectorStore()
-	if vectorStore != nil {
-		if stats, err := vectorStore.GetCollectionStats("default"); err == nil {
-			resp.TotalDocuments = stats.Count
-			resp.TotalEmbeddings = stats.Count
-			resp.Collections = append(resp.Collections, CollectionStats{
-				Name:      stats.Name,
-				Count:     stats.Count,
-				Dimension: stats.Dimension,
-			})
+	if collections, err := s.app.ListCollections(); err == nil {
+		for _, stats := range collections {
+			resp.TotalDocuments += stats.Count
+			resp.TotalEmbeddings += stats.Count
+			resp.Collections = append(resp.Collections, collectionStats(stats))
 		}
 	}
 
@@ -106,6 +120,8 @@ func (s *Server) handleContextStats(w http.ResponseWriter, r *http.Request) {
 		syncStats := syncManager.GetStats()
 		resp.WatchedFiles = syncStats.WatchedFiles
 		resp.PendingDeltas = syncStats.TotalDeltas
+		resp.CompactedDeltas = syncStats.CompactedDeltas
+		resp.Compaction = &syncStats.Compaction
 	}
 
 	// Get recent events for activity
@@ -0,0 +1,249 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// RecoveryEnv carries the JSON Recovery of a crash to the daemon a
+// Supervisor restarts after it.
+const RecoveryEnv = "AGENT_COLLAB_RECOVERY"
+
+// ErrTooManyRestarts is returned by Supervisor.Run when the daemon keeps
+// crashing.
+var ErrTooManyRestarts = errors.New("daemon crashed too many times")
+
+// DefaultSupervisorPIDFile returns the PID file of a supervising process.
+func DefaultSupervisorPIDFile() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".agent-collab", "supervisor.pid")
+}
+
+// SupervisorConfig configures how a crashed daemon is restarted.
+type SupervisorConfig struct {
+	// InitialBackoff is the wait before the first restart, doubling for
+	// each further crash up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// MaxRestarts is how many restarts are allowed within RestartWindow
+	// before the supervisor gives up. A daemon that ran longer than
+	// RestartWindow before crashing starts over at InitialBackoff.
+	MaxRestarts   int
+	RestartWindow time.Duration
+
+	// StopTimeout is how long a daemon gets to shut down once the
+	// supervisor is stopped before it is killed
+	StopTimeout time.Duration
+}
+
+// DefaultSupervisorConfig returns the default restart policy.
+func DefaultSupervisorConfig() SupervisorConfig {
+	return SupervisorConfig{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		MaxRestarts:    5,
+		RestartWindow:  10 * time.Minute,
+		StopTimeout:    10 * time.Second,
+	}
+}
+
+// Recovery describes the crash a supervised daemon was restarted after.
+type Recovery struct {
+	// Restart counts the restarts since the supervisor started
+	Restart   int           `json:"restart"`
+	Exit      string        `json:"exit"`
+	CrashedAt time.Time     `json:"crashed_at"`
+	Uptime    time.Duration `json:"uptime"`
+	Backoff   time.Duration `json:"backoff"`
+}
+
+// RecoveryFromEnv returns the Recovery set by a supervisor, or nil when
+// the daemon was not restarted after a crash.
+func RecoveryFromEnv() (*Recovery, error) {
+	value := os.Getenv(RecoveryEnv)
+	if value == "" {
+		return nil, nil
+	}
+	var r Recovery
+	if err := json.Unmarshal([]byte(value), &r); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", RecoveryEnv, err)
+	}
+	return &r, nil
+}
+
+// RecoveredData is the data of a daemon.recovered event: the crash and
+// the state the restarted daemon restored.
+type RecoveredData struct {
+	Recovery
+	RestoredLocks  int   `json:"restored_locks"`
+	RestoredDeltas int   `json:"restored_deltas"`
+	Documents      int64 `json:"documents"`
+	BootstrapPeers int   `json:"bootstrap_peers"`
+}
+
+// Recovered publishes a daemon.recovered event for a daemon restarted
+// after a crash, once it has restored its state and started rejoining
+// its bootstrap peers.
+func (s *Server) Recovered(r *Recovery) RecoveredData {
+	data := RecoveredData{Recovery: *r}
+	if status := s.app.GetStatus(); status != nil {
+		data.RestoredLocks = status.LockCount
+		data.RestoredDeltas = status.DeltaCount
+		data.Documents = status.EmbeddingCount
+	}
+	if config := s.app.Config(); config != nil {
+		data.BootstrapPeers = len(config.Bootstrap)
+	}
+	s.PublishEvent(NewEvent(EventDaemonRecovered, data))
+	return data
+}
+
+// Supervisor runs a daemon process and restarts it when it crashes.
+type Supervisor struct {
+	config  SupervisorConfig
+	command func() *exec.Cmd
+	out     io.Writer
+}
+
+// NewSupervisor returns a supervisor starting daemons built by command
+// and reporting restarts to out.
+func NewSupervisor(config SupervisorConfig, command func() *exec.Cmd, out io.Writer) *Supervisor {
+	def := DefaultSupervisorConfig()
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = def.InitialBackoff
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = max(def.MaxBackoff, config.InitialBackoff)
+	}
+	if config.MaxRestarts <= 0 {
+		config.MaxRestarts = def.MaxRestarts
+	}
+	if config.RestartWindow <= 0 {
+		config.RestartWindow = def.RestartWindow
+	}
+	if config.StopTimeout <= 0 {
+		config.StopTimeout = def.StopTimeout
+	}
+	return &Supervisor{
+		config:  config,
+		command: command,
+		out:     out,
+	}
+}
+
+// Run starts the daemon and restarts it with exponential backoff each
+// time it crashes, until it exits cleanly or ctx is cancelled, which
+// stops it. It fails with ErrTooManyRestarts when the daemon crashes
+// more than MaxRestarts times within RestartWindow.
+func (s *Supervisor) Run(ctx context.Context) error {
+	var (
+		recovery *Recovery
+		restarts []time.Time
+		backoff  time.Duration
+	)
+	for n := 0; ; n++ {
+		started := time.Now()
+		err := s.runOnce(ctx, recovery)
+		if ctx.Err() != nil {
+			return nil
+		}
+		var exitErr *exec.ExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			return fmt.Errorf("failed to start daemon: %w", err)
+		}
+		if err == nil {
+			return nil
+		}
+
+		crashed := time.Now()
+		uptime := crashed.Sub(started)
+		if uptime >= s.config.RestartWindow || backoff == 0 {
+			backoff = s.config.InitialBackoff
+		} else {
+			backoff = min(backoff*2, s.config.MaxBackoff)
+		}
+		recent := restarts[:0]
+		for _, at := range restarts {
+			if crashed.Sub(at) < s.config.RestartWindow {
+				recent = append(recent, at)
+			}
+		}
+		restarts = recent
+		if len(restarts) >= s.config.MaxRestarts {
+			return fmt.Errorf("%w: %d restarts within %s, last %s", ErrTooManyRestarts,
+				len(restarts), s.config.RestartWindow, exitErr)
+		}
+		restarts = append(restarts, crashed)
+
+		recovery = &Recovery{
+			Restart:   n + 1,
+			Exit:      exitErr.Error(),
+			CrashedAt: crashed,
+			Uptime:    uptime.Round(time.Millisecond),
+			Backoff:   backoff,
+		}
+		fmt.Fprintf(s.out, "Daemon crashed (%s) after %s, restarting in %s (%d/%d)\n",
+			exitErr, recovery.Uptime, backoff, len(restarts), s.config.MaxRestarts)
+		if !sleepContext(ctx, backoff) {
+			return nil
+		}
+	}
+}
+
+// runOnce runs one daemon process until it exits, stopping it when ctx
+// is cancelled.
+func (s *Supervisor) runOnce(ctx context.Context, recovery *Recovery) error {
+	cmd := s.command()
+	if recovery != nil {
+		data, err := json.Marshal(recovery)
+		if err != nil {
+			return err
+		}
+		cmd.Env = append(cmd.Environ(), RecoveryEnv+"="+string(data))
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Ask the daemon to shut down, then kill it if it does not in time
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		cmd.Process.Kill()
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(s.config.StopTimeout):
+		cmd.Process.Kill()
+		return <-done
+	}
+}
+
+// sleepContext waits for d and reports whether ctx is still active.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
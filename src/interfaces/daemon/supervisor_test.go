@@ -0,0 +1,166 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"agent-collab/src/application"
+)
+
+// TestSupervisorHelperProcess stands in for a daemon started by a
+// supervisor. It logs each start, crashes until it has been restarted
+// SUPERVISOR_HELPER_CRASHES times, then exits cleanly, or with
+// SUPERVISOR_HELPER_CRASHES=wait runs until terminated.
+func TestSupervisorHelperProcess(t *testing.T) {
+	crashes := os.Getenv("SUPERVISOR_HELPER_CRASHES")
+	if crashes == "" {
+		return
+	}
+
+	recovery, err := RecoveryFromEnv()
+	if err != nil {
+		os.Exit(10)
+	}
+	line := "start\n"
+	if recovery != nil {
+		line = fmt.Sprintf("restart %d %s %s\n", recovery.Restart, recovery.Backoff, recovery.Exit)
+	}
+	f, err := os.OpenFile(os.Getenv("SUPERVISOR_HELPER_LOG"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		os.Exit(10)
+	}
+	f.WriteString(line)
+	f.Close()
+
+	if crashes == "wait" {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGTERM)
+		<-sigCh
+		os.Exit(0)
+	}
+	n, _ := strconv.Atoi(crashes)
+	if recovery == nil || recovery.Restart < n {
+		os.Exit(3)
+	}
+	os.Exit(0)
+}
+
+// helperSupervisor returns a supervisor of the helper process and the
+// file it logs its starts to.
+func helperSupervisor(t *testing.T, crashes string, config SupervisorConfig) (*Supervisor, string) {
+	t.Helper()
+	log := filepath.Join(t.TempDir(), "starts.log")
+	return NewSupervisor(config, func() *exec.Cmd {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestSupervisorHelperProcess$")
+		cmd.Env = append(os.Environ(),
+			"SUPERVISOR_HELPER_CRASHES="+crashes,
+			"SUPERVISOR_HELPER_LOG="+log)
+		return cmd
+	}, io.Discard), log
+}
+
+func readStarts(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestSupervisor_RestartsCrashedDaemonWithBackoff(t *testing.T) {
+	s, log := helperSupervisor(t, "3", SupervisorConfig{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     3 * time.Millisecond,
+	})
+
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatalf("expected the daemon to recover, got %v", err)
+	}
+
+	want := []string{
+		"start",
+		"restart 1 1ms exit status 3",
+		"restart 2 2ms exit status 3",
+		"restart 3 3ms exit status 3",
+	}
+	got := readStarts(t, log)
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected starts %q, got %q", want, got)
+	}
+}
+
+func TestSupervisor_GivesUpAfterMaxRestarts(t *testing.T) {
+	s, log := helperSupervisor(t, "100", SupervisorConfig{
+		InitialBackoff: time.Millisecond,
+		MaxRestarts:    2,
+	})
+
+	err := s.Run(context.Background())
+	if !errors.Is(err, ErrTooManyRestarts) {
+		t.Fatalf("expected ErrTooManyRestarts, got %v", err)
+	}
+	if got := readStarts(t, log); len(got) != 3 {
+		t.Errorf("expected the first run and 2 restarts, got %q", got)
+	}
+}
+
+func TestSupervisor_StopsDaemonWhenCancelled(t *testing.T) {
+	s, log := helperSupervisor(t, "wait", SupervisorConfig{StopTimeout: 5 * time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(log); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("daemon did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected a clean stop, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not stop the daemon")
+	}
+	if got := readStarts(t, log); len(got) != 1 {
+		t.Errorf("expected no restart after a stop, got %q", got)
+	}
+}
+
+func TestServer_RecoveredPublishesRestoredState(t *testing.T) {
+	app, err := application.New(&application.Config{DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{app: app, eventBus: NewEventBus()}
+
+	data := s.Recovered(&Recovery{Restart: 2, Exit: "exit status 2"})
+	if data.Restart != 2 || data.Exit != "exit status 2" {
+		t.Errorf("unexpected recovery data: %+v", data)
+	}
+	events := s.eventBus.GetEventsByType(EventDaemonRecovered, 10)
+	if len(events) != 1 {
+		t.Fatalf("expected one daemon.recovered event, got %d", len(events))
+	}
+}
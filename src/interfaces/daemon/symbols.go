@@ -0,0 +1,34 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"agent-collab/src/application"
+)
+
+// SymbolSearchResponse lists the symbols found in shared contexts, best
+// match first.
+type SymbolSearchResponse struct {
+	Matches []*application.SymbolMatch `json:"matches"`
+	Error   string                     `json:"error,omitempty"`
+}
+
+// handleSearchSymbols handles the /search/symbols endpoint.
+func (s *Server) handleSearchSymbols(w http.ResponseWriter, r *http.Request) {
+	var q application.SymbolQuery
+	if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+		json.NewEncoder(w).Encode(SymbolSearchResponse{Error: err.Error()})
+		return
+	}
+
+	matches, err := s.app.SearchSymbols(s.ctx, q)
+	if err != nil {
+		json.NewEncoder(w).Encode(SymbolSearchResponse{Error: err.Error()})
+		return
+	}
+	if matches == nil {
+		matches = []*application.SymbolMatch{}
+	}
+	json.NewEncoder(w).Encode(SymbolSearchResponse{Matches: matches})
+}
@@ -3,8 +3,13 @@ package daemon
 import (
 	"time"
 
+	"agent-collab/src/application"
 	"agent-collab/src/domain/agent"
+	"agent-collab/src/domain/event"
 	"agent-collab/src/domain/lock"
+	"agent-collab/src/domain/presence"
+	"agent-collab/src/infrastructure/embedding"
+	"agent-collab/src/infrastructure/network/libp2p"
 )
 
 // Request/Response types for daemon RPC
@@ -21,6 +26,13 @@ type StatusResponse struct {
 	AgentCount        int       `json:"agent_count"`
 	EmbeddingProvider string    `json:"embedding_provider"`
 	EventSubscribers  int       `json:"event_subscribers"`
+	Observer          bool      `json:"observer,omitempty"`
+	Role              string    `json:"role,omitempty"`
+
+	Negotiations *lock.NegotiationMetrics `json:"negotiations,omitempty"`
+
+	// Regions breaks the connected peers down by locality region.
+	Regions []application.RegionStatus `json:"regions,omitempty"`
 }
 
 // LockRequest is a request to acquire a lock.
@@ -29,13 +41,62 @@ type LockRequest struct {
 	StartLine int    `json:"start_line"`
 	EndLine   int    `json:"end_line"`
 	Intention string `json:"intention"`
+	// Mode is "read" for a shared lock other readers may overlap, or
+	// "write" (default) for an exclusive one.
+	Mode string `json:"mode,omitempty"`
+	// TTLSeconds overrides the default lock TTL.
+	TTLSeconds int `json:"ttl_seconds,omitempty"`
+	// Deadline sets an absolute expiry instead of a TTL.
+	Deadline time.Time `json:"deadline,omitzero"`
+	// WaitForLock waits for a locked region to come free instead of
+	// failing, for up to WaitTimeoutSeconds (default DefaultLockWait, at
+	// most MaxLockWait). Priority orders waiters under the "priority"
+	// lock_wait_order.
+	WaitForLock        bool `json:"wait_for_lock,omitempty"`
+	WaitTimeoutSeconds int  `json:"wait_timeout_seconds,omitempty"`
+	Priority           int  `json:"priority,omitempty"`
+}
+
+// Bounds of a wait_for_lock request.
+const (
+	DefaultLockWait = time.Minute
+	MaxLockWait     = 10 * time.Minute
+)
+
+// WaitTimeout returns how long the request waits for a locked region.
+func (r LockRequest) WaitTimeout() time.Duration {
+	if r.WaitTimeoutSeconds <= 0 {
+		return DefaultLockWait
+	}
+	return min(time.Duration(r.WaitTimeoutSeconds)*time.Second, MaxLockWait)
 }
 
 // LockResponse is the response to a lock request.
 type LockResponse struct {
-	Success bool   `json:"success"`
-	LockID  string `json:"lock_id,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Success   bool      `json:"success"`
+	LockID    string    `json:"lock_id,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+	Error     string    `json:"error,omitempty"`
+	// FencingToken identifies this grant of the lock; pass it when
+	// renewing so a lost and re-taken lock is not extended by mistake.
+	FencingToken uint64 `json:"fencing_token,omitempty"`
+	// RetryAfterMs is set when the request was rate limited and tells the
+	// caller how long to wait before retrying.
+	RetryAfterMs int64 `json:"retry_after_ms,omitempty"`
+	// ConflictHint predicts contention on the file from recent lock
+	// activity by other agents.
+	ConflictHint *lock.ConflictHint `json:"conflict_hint,omitempty"`
+	// PromotedTo describes the symbol lock the region request was promoted
+	// to, if it was.
+	PromotedTo string `json:"promoted_to,omitempty"`
+	// Preempted lists the locks the conflict policy took from other
+	// agents to grant this one.
+	Preempted []*lock.ForceReleaseNotice `json:"preempted,omitempty"`
+}
+
+// RetryAfter returns the retry hint as a duration.
+func (r *LockResponse) RetryAfter() time.Duration {
+	return time.Duration(r.RetryAfterMs) * time.Millisecond
 }
 
 // ReleaseLockRequest is a request to release a lock.
@@ -43,11 +104,77 @@ type ReleaseLockRequest struct {
 	LockID string `json:"lock_id"`
 }
 
+// UpgradeLockRequest is a request to turn a read lock into a write lock.
+type UpgradeLockRequest struct {
+	LockID string `json:"lock_id"`
+}
+
+// ReleaseLocksByIntentionRequest is a request to release this node's locks
+// acquired under an intention.
+type ReleaseLocksByIntentionRequest struct {
+	Intention string `json:"intention"`
+	// Prefix matches every intention starting with Intention.
+	Prefix bool `json:"prefix,omitempty"`
+}
+
+// ReleaseLocksByIntentionResponse lists the locks a bulk release freed.
+type ReleaseLocksByIntentionResponse struct {
+	Success  bool                 `json:"success"`
+	Released []*lock.SemanticLock `json:"released,omitempty"`
+	Error    string               `json:"error,omitempty"`
+}
+
+// RenewLockRequest is a request to extend a lock's lease.
+type RenewLockRequest struct {
+	LockID string `json:"lock_id"`
+	// FencingToken, when set, must match the lock's current token.
+	FencingToken uint64 `json:"fencing_token,omitempty"`
+	// TTLSeconds sets the new lease from now (default 30, max 300).
+	TTLSeconds int `json:"ttl_seconds,omitempty"`
+}
+
+// RenewLockResponse is the response to a renew request.
+type RenewLockResponse struct {
+	Success bool               `json:"success"`
+	Lock    *lock.SemanticLock `json:"lock,omitempty"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// MetricsResponse contains network metrics, message processing latency
+// percentiles keyed by stage (e.g. "lock.apply", "context.embed") and
+// event subscriber backpressure counters.
+type MetricsResponse struct {
+	libp2p.MetricsSnapshot
+	Processing   map[string]application.LatencySnapshot `json:"processing,omitempty"`
+	Events       *event.BackpressureStats               `json:"events,omitempty"`
+	Negotiations *lock.NegotiationMetrics               `json:"negotiations,omitempty"`
+	Embeddings   *embedding.ConcurrencyStats            `json:"embeddings,omitempty"`
+}
+
+// ForceReleaseLockRequest is an operator request to break a lock.
+type ForceReleaseLockRequest struct {
+	LockID string `json:"lock_id"`
+	Reason string `json:"reason"`
+}
+
+// ForceReleaseLockResponse is the response to a force release request.
+type ForceReleaseLockResponse struct {
+	Success bool                     `json:"success"`
+	Notice  *lock.ForceReleaseNotice `json:"notice,omitempty"`
+	Error   string                   `json:"error,omitempty"`
+}
+
 // ListLocksResponse contains the list of active locks.
 type ListLocksResponse struct {
 	Locks []*lock.SemanticLock `json:"locks"`
 }
 
+// CheckLockResponse is the response to a dry-run lock check.
+type CheckLockResponse struct {
+	*lock.LockCheck
+	Error string `json:"error,omitempty"`
+}
+
 // EmbedRequest is a request to generate embeddings.
 type EmbedRequest struct {
 	Text string `json:"text"`
@@ -63,8 +190,13 @@ type EmbedResponse struct {
 
 // SearchRequest is a request to search similar content.
 type SearchRequest struct {
-	Query string `json:"query"`
-	Limit int    `json:"limit"`
+	Query    string  `json:"query"`
+	Limit    int     `json:"limit"`
+	MinScore float32 `json:"min_score,omitempty"`
+	// Collection limits the search to one collection; empty searches all
+	Collection string `json:"collection,omitempty"`
+	// Branch limits the search to context shared on this git branch
+	Branch string `json:"branch,omitempty"`
 }
 
 // SearchResult is a single search result.
@@ -78,6 +210,7 @@ type SearchResult struct {
 // SearchResponse contains search results.
 type SearchResponse struct {
 	Results []SearchResult `json:"results"`
+	Error   string         `json:"error,omitempty"`
 }
 
 // ListAgentsResponse contains connected agents.
@@ -90,6 +223,28 @@ type WatchFileRequest struct {
 	FilePath string `json:"file_path"`
 }
 
+// ReportActiveEditRequest announces (or clears) where this agent is editing.
+type ReportActiveEditRequest struct {
+	FilePath  string `json:"file_path"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	Clear     bool   `json:"clear,omitempty"`
+}
+
+// ReportActiveEditResponse is the response for reporting an active edit.
+type ReportActiveEditResponse struct {
+	Success bool                   `json:"success"`
+	Edit    *presence.ActiveEdit   `json:"edit,omitempty"`
+	Others  []*presence.ActiveEdit `json:"others,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// ListActiveEditsResponse contains the unexpired active edits.
+type ListActiveEditsResponse struct {
+	Edits []*presence.ActiveEdit `json:"edits"`
+	TTL   string                 `json:"ttl,omitempty"`
+}
+
 // GenericResponse is a generic success/error response.
 type GenericResponse struct {
 	Success bool   `json:"success"`
@@ -113,7 +268,8 @@ type InitResponse struct {
 
 // JoinRequest is a request to join a cluster.
 type JoinRequest struct {
-	Token string `json:"token"`
+	Token    string `json:"token"`
+	Observer bool   `json:"observer,omitempty"`
 }
 
 // JoinResponse is the response after joining.
@@ -131,6 +287,12 @@ type PeerInfo struct {
 	Addresses []string `json:"addresses"`
 	Latency   int64    `json:"latency_ms"`
 	Connected bool     `json:"connected"`
+	// Transport is tcp, quic or relay (a circuit relay connection)
+	Transport string               `json:"transport,omitempty"`
+	Quality   *libp2p.PeerQuality  `json:"quality,omitempty"`
+	Locality  *libp2p.PeerLocality `json:"locality,omitempty"`
+	// Delivery counts lock messages pushed to the peer
+	Delivery *libp2p.PeerDeliveryStats `json:"delivery,omitempty"`
 }
 
 // ListPeersResponse contains the list of connected peers.
@@ -138,19 +300,93 @@ type ListPeersResponse struct {
 	Peers []PeerInfo `json:"peers"`
 }
 
+// TopologyNode is a node in the cluster topology graph.
+type TopologyNode struct {
+	ID     string `json:"id"`
+	Role   string `json:"role"`
+	Region string `json:"region,omitempty"`
+	Self   bool   `json:"self,omitempty"`
+}
+
+// TopologyEdge is a connection between two nodes.
+type TopologyEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Latency int64  `json:"latency_ms"`
+}
+
+// TopologyResponse is the topology graph as seen from this node.
+type TopologyResponse struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+	Error string         `json:"error,omitempty"`
+}
+
+// HealthResponse reports daemon readiness and dependency health.
+type HealthResponse struct {
+	Ready     bool                    `json:"ready"`
+	Running   bool                    `json:"running"`
+	Embedding *embedding.HealthStatus `json:"embedding,omitempty"`
+	// Checks lists each readiness condition and whether it passed.
+	Checks []application.ReadinessCheck `json:"checks,omitempty"`
+	// Subsystems details each dependency with its probe latency; only
+	// filled for verbose requests.
+	Subsystems []application.SubsystemHealth `json:"subsystems,omitempty"`
+	Error      string                        `json:"error,omitempty"`
+}
+
+// DigestResponse contains a summary of activity since a timestamp.
+type DigestResponse struct {
+	Digest  *event.Digest `json:"digest,omitempty"`
+	Summary string        `json:"summary"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// ConflictHeatmapResponse ranks files by lock conflicts since a timestamp.
+type ConflictHeatmapResponse struct {
+	Since    time.Time                `json:"since"`
+	Hotspots []*event.ConflictHotspot `json:"hotspots"`
+	Error    string                   `json:"error,omitempty"`
+}
+
 // ShareContextRequest is a request to share context with peers.
 type ShareContextRequest struct {
 	FilePath string         `json:"file_path"`
 	Content  string         `json:"content"`
 	Metadata map[string]any `json:"metadata,omitempty"`
+	// ParentIDs are the documents this share was derived from.
+	ParentIDs []string `json:"parent_ids,omitempty"`
 }
 
 // ShareContextResponse is the response after sharing context.
 type ShareContextResponse struct {
 	Success    bool   `json:"success"`
 	DocumentID string `json:"document_id,omitempty"`
-	Message    string `json:"message,omitempty"`
-	Error      string `json:"error,omitempty"`
+	// Duplicate is set when identical content was already shared for the
+	// file recently and this share was skipped.
+	Duplicate bool   `json:"duplicate,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ProvenanceResponse is the provenance chain of a document, starting with
+// the document itself.
+type ProvenanceResponse struct {
+	DocumentID string                        `json:"document_id"`
+	Chain      []application.ProvenanceEntry `json:"chain,omitempty"`
+	Error      string                        `json:"error,omitempty"`
+}
+
+// RateContextRequest records whether a search result was useful.
+type RateContextRequest struct {
+	DocumentID string `json:"document_id"`
+	Useful     bool   `json:"useful"`
+}
+
+// RateContextResponse is the feedback recorded for a document.
+type RateContextResponse struct {
+	Rating *application.ContextRating `json:"rating,omitempty"`
+	Error  string                     `json:"error,omitempty"`
 }
 
 // CheckCohesionRequest is a request to check cohesion with existing context.
@@ -0,0 +1,173 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"agent-collab/src/domain/event"
+	"agent-collab/src/domain/lock"
+)
+
+// defaultWorkMapWindow is how far back context shares count as recent.
+const defaultWorkMapWindow = time.Hour
+
+// WorkMapResponse maps each active agent to the files it is working on.
+type WorkMapResponse struct {
+	Since  time.Time    `json:"since"`
+	Agents []*AgentWork `json:"agents"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// AgentWork is what one agent currently locks and recently shared.
+type AgentWork struct {
+	AgentID   string         `json:"agent_id"`
+	AgentName string         `json:"agent_name,omitempty"`
+	Locks     []*WorkLock    `json:"locks,omitempty"`
+	Shares    []*WorkedFile  `json:"shares,omitempty"`
+	Files     []string       `json:"files"`
+	byFile    map[string]int // file -> index in Shares
+}
+
+// WorkLock is a lock held by an agent.
+type WorkLock struct {
+	LockID     string    `json:"lock_id"`
+	FilePath   string    `json:"file_path"`
+	StartLine  int       `json:"start_line,omitempty"`
+	EndLine    int       `json:"end_line,omitempty"`
+	Intention  string    `json:"intention,omitempty"`
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+// WorkedFile is a file an agent recently shared context about.
+type WorkedFile struct {
+	FilePath   string    `json:"file_path"`
+	Shares     int       `json:"shares"`
+	LastShared time.Time `json:"last_shared"`
+	Summary    string    `json:"summary,omitempty"`
+}
+
+// buildWorkMap groups current locks and context shares since the given
+// time by agent. Agents are sorted by name, then ID.
+func buildWorkMap(locks []*lock.SemanticLock, events []*event.Event, since time.Time) []*AgentWork {
+	agents := make(map[string]*AgentWork)
+	agentFor := func(id, name string) *AgentWork {
+		w := agents[id]
+		if w == nil {
+			w = &AgentWork{AgentID: id, byFile: make(map[string]int)}
+			agents[id] = w
+		}
+		if w.AgentName == "" {
+			w.AgentName = name
+		}
+		return w
+	}
+
+	for _, l := range locks {
+		if l == nil || l.Target == nil {
+			continue
+		}
+		w := agentFor(l.HolderID, l.HolderName)
+		w.Locks = append(w.Locks, &WorkLock{
+			LockID:     l.ID,
+			FilePath:   l.Target.FilePath,
+			StartLine:  l.Target.StartLine,
+			EndLine:    l.Target.EndLine,
+			Intention:  l.Intention,
+			AcquiredAt: l.AcquiredAt,
+		})
+	}
+
+	for _, e := range events {
+		if e == nil || e.Type != event.EventTypeContextShared || e.FilePath == "" || e.Timestamp.Before(since) {
+			continue
+		}
+		w := agentFor(e.SourceID, e.SourceName)
+
+		i, ok := w.byFile[e.FilePath]
+		if !ok {
+			i = len(w.Shares)
+			w.byFile[e.FilePath] = i
+			w.Shares = append(w.Shares, &WorkedFile{FilePath: e.FilePath})
+		}
+		file := w.Shares[i]
+		file.Shares++
+		if !e.Timestamp.Before(file.LastShared) {
+			file.LastShared = e.Timestamp
+			var p event.ContextSharedPayload
+			if e.GetPayload(&p) == nil {
+				file.Summary = summaryLine(p.Content)
+			}
+		}
+	}
+
+	result := make([]*AgentWork, 0, len(agents))
+	for _, w := range agents {
+		sort.Slice(w.Locks, func(i, j int) bool { return w.Locks[i].AcquiredAt.Before(w.Locks[j].AcquiredAt) })
+		sort.Slice(w.Shares, func(i, j int) bool { return w.Shares[i].LastShared.After(w.Shares[j].LastShared) })
+
+		seen := make(map[string]bool)
+		for _, l := range w.Locks {
+			seen[l.FilePath] = true
+		}
+		for _, s := range w.Shares {
+			seen[s.FilePath] = true
+		}
+		for f := range seen {
+			w.Files = append(w.Files, f)
+		}
+		sort.Strings(w.Files)
+		result = append(result, w)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AgentName != result[j].AgentName {
+			return result[i].AgentName < result[j].AgentName
+		}
+		return result[i].AgentID < result[j].AgentID
+	})
+	return result
+}
+
+// summaryLine returns the first line of s, trimmed to a short summary.
+func summaryLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if r := []rune(s); len(r) > 80 {
+		s = string(r[:77]) + "..."
+	}
+	return s
+}
+
+// handleWorkMap handles the /work endpoint.
+func (s *Server) handleWorkMap(w http.ResponseWriter, r *http.Request) {
+	since := time.Now().Add(-defaultWorkMapWindow)
+	if v := r.URL.Query().Get("since"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			json.NewEncoder(w).Encode(WorkMapResponse{Error: fmt.Sprintf("invalid since: %v", err)})
+			return
+		}
+		since = parsed
+	}
+
+	var locks []*lock.SemanticLock
+	if lockService := s.app.LockService(); lockService != nil {
+		locks = lockService.ListLocks()
+	}
+
+	var events []*event.Event
+	if eventRouter := s.app.EventRouter(); eventRouter != nil {
+		events = eventRouter.EventLog().GetSince(since)
+	}
+
+	json.NewEncoder(w).Encode(WorkMapResponse{
+		Since:  since,
+		Agents: buildWorkMap(locks, events, since),
+	})
+}
@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"agent-collab/src/domain/event"
+	"agent-collab/src/domain/lock"
+)
+
+func seedLock(t *testing.T, holderID, holderName, filePath string, start, end int, acquiredAt time.Time) *lock.SemanticLock {
+	t.Helper()
+	target, err := lock.NewSemanticTarget(lock.TargetFile, filePath, "", start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := lock.NewSemanticLockSafe(target, holderID, holderName, "edit "+filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.AcquiredAt = acquiredAt
+	return l
+}
+
+func seedShare(sourceID, sourceName, filePath, content string, at time.Time) *event.Event {
+	e := event.NewContextSharedEvent(sourceID, sourceName, filePath, &event.ContextSharedPayload{Content: content})
+	e.Timestamp = at
+	return e
+}
+
+func TestBuildWorkMap_GroupsLocksAndSharesByAgent(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-time.Hour)
+
+	locks := []*lock.SemanticLock{
+		seedLock(t, "node-a", "alice", "auth/login.go", 10, 40, now.Add(-2*time.Minute)),
+		seedLock(t, "node-b", "bob", "db/pool.go", 1, 20, now.Add(-time.Minute)),
+		seedLock(t, "node-a", "alice", "auth/session.go", 5, 15, now.Add(-time.Minute)),
+	}
+	events := []*event.Event{
+		seedShare("node-a", "alice", "auth/login.go", "Added rate limiting\nDetails...", now.Add(-30*time.Minute)),
+		seedShare("node-a", "alice", "auth/login.go", "Fixed token refresh", now.Add(-10*time.Minute)),
+		seedShare("node-c", "carol", "docs/api.md", "Documented the lock API", now.Add(-5*time.Minute)),
+		// Too old to count
+		seedShare("node-b", "bob", "db/old.go", "Ancient change", now.Add(-2*time.Hour)),
+		// Not a share
+		event.NewFileChangeEvent("node-b", "bob", "db/pool.go", &event.FileChangePayload{Summary: "edit"}),
+	}
+
+	agents := buildWorkMap(locks, events, since)
+	if len(agents) != 3 {
+		t.Fatalf("expected 3 agents, got %d", len(agents))
+	}
+
+	alice, bob, carol := agents[0], agents[1], agents[2]
+	if alice.AgentName != "alice" || bob.AgentName != "bob" || carol.AgentName != "carol" {
+		t.Fatalf("agents should be sorted by name, got %s, %s, %s", alice.AgentName, bob.AgentName, carol.AgentName)
+	}
+
+	if len(alice.Locks) != 2 || alice.Locks[0].FilePath != "auth/login.go" || alice.Locks[0].StartLine != 10 {
+		t.Errorf("alice should hold both auth locks, oldest first: %+v", alice.Locks)
+	}
+	if len(alice.Shares) != 1 || alice.Shares[0].Shares != 2 || alice.Shares[0].Summary != "Fixed token refresh" {
+		t.Errorf("alice's shares should be merged per file with the latest summary: %+v", alice.Shares)
+	}
+	if want := []string{"auth/login.go", "auth/session.go"}; !reflect.DeepEqual(alice.Files, want) {
+		t.Errorf("alice files: expected %v, got %v", want, alice.Files)
+	}
+
+	if want := []string{"db/pool.go"}; !reflect.DeepEqual(bob.Files, want) || len(bob.Shares) != 0 {
+		t.Errorf("bob should only have his lock, got files %v shares %+v", bob.Files, bob.Shares)
+	}
+
+	if len(carol.Locks) != 0 || len(carol.Shares) != 1 || carol.Files[0] != "docs/api.md" {
+		t.Errorf("carol should only have her share, got %+v", carol)
+	}
+}
+
+func TestBuildWorkMap_Empty(t *testing.T) {
+	if agents := buildWorkMap(nil, nil, time.Now()); len(agents) != 0 {
+		t.Errorf("expected no agents, got %+v", agents)
+	}
+}
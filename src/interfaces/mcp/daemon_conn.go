@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"agent-collab/src/interfaces/daemon"
+)
+
+// DaemonConn hands daemon tools a live daemon client. When the daemon has
+// restarted, the old client may hold dead connections, so each tool call
+// health-checks the client first and redials with a bounded backoff.
+type DaemonConn struct {
+	mu     sync.Mutex
+	client *daemon.Client
+	dial   func() *daemon.Client
+	policy daemon.RetryPolicy
+}
+
+// NewDaemonConn creates a connection that starts with client and uses dial
+// to create a fresh client after the daemon goes away.
+func NewDaemonConn(client *daemon.Client, dial func() *daemon.Client) *DaemonConn {
+	return &DaemonConn{
+		client: client,
+		dial:   dial,
+		policy: daemon.DefaultRetryPolicy(),
+	}
+}
+
+// SetRetryPolicy sets how many times and how patiently Client reconnects.
+func (d *DaemonConn) SetRetryPolicy(policy daemon.RetryPolicy) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.policy = policy
+}
+
+// Client returns a client whose daemon answered a status check, redialing
+// if the current one is dead. It fails with a clear error once the retry
+// policy is exhausted.
+func (d *DaemonConn) Client(ctx context.Context) (*daemon.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.client.Status(); err == nil {
+		return d.client, nil
+	}
+
+	attempts := d.policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := d.policy.InitialBackoff
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if d.policy.MaxBackoff > 0 && backoff > d.policy.MaxBackoff {
+				backoff = d.policy.MaxBackoff
+			}
+		}
+
+		client := d.dial()
+		if _, err := client.Status(); err != nil {
+			lastErr = err
+			continue
+		}
+		d.client = client
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("daemon is not reachable at %s after %d attempts (%v); run 'agent-collab daemon start'",
+		d.client.SocketPath(), attempts, lastErr)
+}
+
+// daemonToolHandler is a tool handler that talks to the daemon.
+type daemonToolHandler func(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error)
+
+// registerDaemonTool registers a tool whose handler receives a live client.
+func registerDaemonTool(server *Server, conn *DaemonConn, tool Tool, handler daemonToolHandler) {
+	server.RegisterTool(tool, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+		client, err := conn.Client(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, client, args)
+	})
+}
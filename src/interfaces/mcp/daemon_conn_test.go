@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-collab/src/interfaces/daemon"
+)
+
+// startFakeDaemon serves /status and /lock/list on socketPath until the
+// returned stop function is called.
+func startFakeDaemon(t *testing.T, socketPath string) (stop func()) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(daemon.StatusResponse{Running: true})
+	})
+	mux.HandleFunc("/lock/list", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(daemon.ListLocksResponse{})
+	})
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+
+	return func() {
+		server.Close()
+		os.Remove(socketPath)
+	}
+}
+
+// dialer returns a factory for clients bound to socketPath.
+func dialer(socketPath string) func() *daemon.Client {
+	return func() *daemon.Client {
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return net.Dial("unix", socketPath)
+			},
+		}
+		return daemon.NewClientWithTransport(transport, socketPath)
+	}
+}
+
+func testSocketPath(t *testing.T) string {
+	t.Helper()
+	// Keep it short to stay under UNIX socket path length limits
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("mcp-conn-%d.sock", time.Now().UnixNano()%100000))
+	t.Cleanup(func() { os.Remove(path) })
+	return path
+}
+
+func callListLocks(t *testing.T, server *Server) (*ToolCallResult, error) {
+	t.Helper()
+	handler, ok := server.tools["list_locks"]
+	if !ok {
+		t.Fatal("list_locks not registered")
+	}
+	return handler(context.Background(), nil)
+}
+
+func TestDaemonConn_FailsClearlyWhenDaemonIsDown(t *testing.T) {
+	socketPath := testSocketPath(t)
+	dial := dialer(socketPath)
+
+	conn := NewDaemonConn(dial(), dial)
+	conn.SetRetryPolicy(daemon.RetryPolicy{MaxAttempts: 2, InitialBackoff: 10 * time.Millisecond})
+
+	server := NewServer("test", "1.0.0", nil)
+	RegisterDaemonToolsWithConn(server, conn)
+
+	_, err := callListLocks(t, server)
+	if err == nil {
+		t.Fatal("expected error when daemon is down")
+	}
+	if !strings.Contains(err.Error(), socketPath) || !strings.Contains(err.Error(), "daemon start") {
+		t.Errorf("error should name the socket and how to start the daemon: %v", err)
+	}
+}
+
+func TestDaemonConn_ReconnectsWhenDaemonComesUp(t *testing.T) {
+	socketPath := testSocketPath(t)
+	dial := dialer(socketPath)
+
+	conn := NewDaemonConn(dial(), dial)
+	conn.SetRetryPolicy(daemon.RetryPolicy{MaxAttempts: 20, InitialBackoff: 20 * time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+
+	server := NewServer("test", "1.0.0", nil)
+	RegisterDaemonToolsWithConn(server, conn)
+
+	// The daemon starts while the tool call is still retrying
+	started := make(chan func(), 1)
+	go func() {
+		time.Sleep(60 * time.Millisecond)
+		started <- startFakeDaemon(t, socketPath)
+	}()
+	defer func() { (<-started)() }()
+
+	result, err := callListLocks(t, server)
+	if err != nil {
+		t.Fatalf("tool call should succeed after reconnect: %v", err)
+	}
+	if result.Content[0].Text != "No active locks" {
+		t.Errorf("unexpected result: %q", result.Content[0].Text)
+	}
+}
+
+func TestDaemonConn_SurvivesDaemonRestart(t *testing.T) {
+	socketPath := testSocketPath(t)
+	dial := dialer(socketPath)
+
+	stop := startFakeDaemon(t, socketPath)
+	conn := NewDaemonConn(dial(), dial)
+	conn.SetRetryPolicy(daemon.RetryPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond})
+
+	server := NewServer("test", "1.0.0", nil)
+	RegisterDaemonToolsWithConn(server, conn)
+
+	if _, err := callListLocks(t, server); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+
+	// Restart the daemon
+	stop()
+	stop = startFakeDaemon(t, socketPath)
+	defer stop()
+
+	if _, err := callListLocks(t, server); err != nil {
+		t.Fatalf("call after restart failed: %v", err)
+	}
+}
+
+func TestDaemonConn_ReplacesDeadClient(t *testing.T) {
+	socketPath := testSocketPath(t)
+	stop := startFakeDaemon(t, socketPath)
+	defer stop()
+
+	// A client stuck on a socket that no longer exists
+	dead := dialer(filepath.Join(os.TempDir(), "mcp-conn-gone.sock"))()
+	conn := NewDaemonConn(dead, dialer(socketPath))
+	conn.SetRetryPolicy(daemon.RetryPolicy{MaxAttempts: 2, InitialBackoff: 10 * time.Millisecond})
+
+	server := NewServer("test", "1.0.0", nil)
+	RegisterDaemonToolsWithConn(server, conn)
+
+	if _, err := callListLocks(t, server); err != nil {
+		t.Fatalf("tool call should succeed with a fresh client: %v", err)
+	}
+	if conn.client == dead {
+		t.Error("dead client should have been replaced")
+	}
+}
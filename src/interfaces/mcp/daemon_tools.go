@@ -4,16 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"agent-collab/src/domain/lock"
 	"agent-collab/src/interfaces/daemon"
 )
 
 // RegisterDaemonTools registers tools that connect to the daemon.
+// Tools reconnect to a restarted daemon through a DaemonConn.
 func RegisterDaemonTools(server *Server, client *daemon.Client) {
+	RegisterDaemonToolsWithConn(server, NewDaemonConn(client, daemon.NewClient))
+}
+
+// RegisterDaemonToolsWithConn registers daemon tools that obtain their
+// client from conn on every call.
+func RegisterDaemonToolsWithConn(server *Server, conn *DaemonConn) {
 	// Lock management tools
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "acquire_lock",
-		Description: "IMPORTANT: Call this BEFORE modifying any file to prevent conflicts with other agents. If lock acquisition fails, another agent is working on that area - wait or work on something else.",
+		Description: "IMPORTANT: Call this BEFORE modifying any file to prevent conflicts with other agents. If lock acquisition fails, another agent is working on that area - work on something else, or set wait_for_lock to wait for it.",
 		InputSchema: InputSchema{
 			Type: "object",
 			Properties: map[string]Property{
@@ -33,14 +42,33 @@ func RegisterDaemonTools(server *Server, client *daemon.Client) {
 					Type:        "string",
 					Description: "Brief description of what you plan to do (e.g., 'Add error handling to login function')",
 				},
+				"mode": lockModeProperty,
+				"ttl_seconds": {
+					Type:        "integer",
+					Description: "Optional lock lifetime in seconds (default 30, max 300)",
+				},
+				"deadline": {
+					Type:        "string",
+					Description: "Optional RFC 3339 time by which you will be done; the lock expires then. Cannot be combined with ttl_seconds",
+				},
+				"wait_for_lock": {
+					Type:        "boolean",
+					Description: "If the region is locked, wait in line until it is free instead of failing",
+				},
+				"wait_timeout_seconds": {
+					Type:        "integer",
+					Description: "How long to wait with wait_for_lock (default 60, max 600)",
+				},
+				"priority": {
+					Type:        "integer",
+					Description: "Optional place in line with wait_for_lock when the daemon orders waiters by priority; higher goes first",
+				},
 			},
 			Required: []string{"file_path", "start_line", "end_line", "intention"},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonAcquireLock(ctx, client, args)
-	})
+	}, handleDaemonAcquireLock)
 
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "release_lock",
 		Description: "Release a previously acquired lock",
 		InputSchema: InputSchema{
@@ -53,23 +81,142 @@ func RegisterDaemonTools(server *Server, client *daemon.Client) {
 			},
 			Required: []string{"lock_id"},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonReleaseLock(ctx, client, args)
-	})
+	}, handleDaemonReleaseLock)
+
+	registerDaemonTool(server, conn, Tool{
+		Name:        "release_locks_by_intention",
+		Description: "Release all locks you acquired under one intention, e.g. when the task is done",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"intention": {
+					Type:        "string",
+					Description: "Intention the locks were acquired with",
+				},
+				"prefix": {
+					Type:        "boolean",
+					Description: "Also release locks whose intention starts with this text (default false)",
+				},
+			},
+			Required: []string{"intention"},
+		},
+	}, handleDaemonReleaseLocksByIntention)
+
+	registerDaemonTool(server, conn, Tool{
+		Name:        "renew_lock",
+		Description: "Extend the lease of a lock you hold before it expires",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"lock_id": {
+					Type:        "string",
+					Description: "ID of the lock to renew",
+				},
+				"fencing_token": {
+					Type:        "integer",
+					Description: "Fencing token from acquire_lock; the renewal fails if the lock was lost and taken again",
+				},
+				"ttl_seconds": {
+					Type:        "integer",
+					Description: "Optional new lease in seconds from now (default 30, max 300)",
+				},
+			},
+			Required: []string{"lock_id"},
+		},
+	}, handleDaemonRenewLock)
+
+	registerDaemonTool(server, conn, Tool{
+		Name:        "upgrade_lock",
+		Description: upgradeLockDescription,
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"lock_id": {
+					Type:        "string",
+					Description: "ID of the read lock to upgrade",
+				},
+			},
+			Required: []string{"lock_id"},
+		},
+	}, handleDaemonUpgradeLock)
+
+	registerDaemonTool(server, conn, Tool{
+		Name:        "check_lock",
+		Description: "Check whether a region is free to lock without announcing an intent: reports who holds overlapping locks and the expected wait. Use it to plan the order of your work; acquire_lock still decides.",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"file_path": {
+					Type:        "string",
+					Description: "Path to the file you want to modify",
+				},
+				"start_line": {
+					Type:        "integer",
+					Description: "Start line of the region (use 1 for entire file)",
+				},
+				"end_line": {
+					Type:        "integer",
+					Description: "End line of the region (use -1 for entire file)",
+				},
+				"mode": checkModeProperty,
+			},
+			Required: []string{"file_path", "start_line", "end_line"},
+		},
+	}, handleDaemonCheckLock)
 
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "list_locks",
 		Description: "List all active locks in the cluster",
 		InputSchema: InputSchema{
 			Type:       "object",
 			Properties: map[string]Property{},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonListLocks(ctx, client, args)
-	})
+	}, handleDaemonListLocks)
+
+	// Presence tools
+	registerDaemonTool(server, conn, Tool{
+		Name:        "report_active_edit",
+		Description: "Announce the file region you are currently editing so other agents can steer clear. Lighter than a lock: it claims nothing and expires unless you report again. Returns other agents editing an overlapping region.",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"file_path": {
+					Type:        "string",
+					Description: "Path to the file you are editing",
+				},
+				"start_line": {
+					Type:        "integer",
+					Description: "Approximate start line of the region (omit for the whole file)",
+				},
+				"end_line": {
+					Type:        "integer",
+					Description: "Approximate end line of the region",
+				},
+				"clear": {
+					Type:        "boolean",
+					Description: "If true, withdraw your active edit on the file (default false)",
+				},
+			},
+			Required: []string{"file_path"},
+		},
+	}, handleDaemonReportActiveEdit)
+
+	registerDaemonTool(server, conn, Tool{
+		Name:        "get_active_edits",
+		Description: "List where other agents are editing right now. Check this before touching a file to avoid stepping on someone mid-edit.",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"file_path": {
+					Type:        "string",
+					Description: "Only show edits on this file (optional)",
+				},
+			},
+		},
+	}, handleDaemonGetActiveEdits)
 
 	// Context synchronization tools
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "share_context",
 		Description: "IMPORTANT: Call this after completing any code changes to share your work with other agents. This broadcasts what you changed and why, enabling other agents to avoid conflicts and build on your work. Always share context after modifying files.",
 		InputSchema: InputSchema{
@@ -87,15 +234,51 @@ func RegisterDaemonTools(server *Server, client *daemon.Client) {
 					Type:        "object",
 					Description: "Additional metadata (e.g., related_files, breaking_changes)",
 				},
+				"parent_ids": {
+					Type:        "array",
+					Description: "Document IDs of earlier shares this builds on (from search_similar or a previous share_context)",
+				},
 			},
 			Required: []string{"file_path", "content"},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonShareContext(ctx, client, args)
-	})
+	}, handleDaemonShareContext)
+
+	registerDaemonTool(server, conn, Tool{
+		Name:        "get_provenance",
+		Description: "Show where a piece of shared context came from: who shared it, when, and the earlier shares it was derived from",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"document_id": {
+					Type:        "string",
+					Description: "Document ID returned by share_context or search_similar",
+				},
+			},
+			Required: []string{"document_id"},
+		},
+	}, handleDaemonGetProvenance)
+
+	registerDaemonTool(server, conn, Tool{
+		Name:        "rate_context",
+		Description: "Tell the cluster whether a search_similar result was useful, so future searches rank it higher or lower",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"document_id": {
+					Type:        "string",
+					Description: "Document ID of a search_similar result",
+				},
+				"useful": {
+					Type:        "boolean",
+					Description: "Whether the result helped with the task",
+				},
+			},
+			Required: []string{"document_id", "useful"},
+		},
+	}, handleDaemonRateContext)
 
 	// Embedding tools
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "embed_text",
 		Description: "Generate embeddings for text using the configured provider",
 		InputSchema: InputSchema{
@@ -108,11 +291,9 @@ func RegisterDaemonTools(server *Server, client *daemon.Client) {
 			},
 			Required: []string{"text"},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonEmbedText(ctx, client, args)
-	})
+	}, handleDaemonEmbedText)
 
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "search_similar",
 		Description: "IMPORTANT: Call this before starting work to find relevant context shared by other agents. Search for code patterns, file names, or concepts to discover what others have done and avoid duplicating work or creating conflicts.",
 		InputSchema: InputSchema{
@@ -126,38 +307,67 @@ func RegisterDaemonTools(server *Server, client *daemon.Client) {
 					Type:        "integer",
 					Description: "Maximum number of results (default 10)",
 				},
+				"min_score": {
+					Type:        "number",
+					Description: "Drop results with similarity below this score, 0-1 (default 0). Raise it (e.g. 0.7) to get only strong matches",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Only return context shared on this git branch (default: all branches)",
+				},
 			},
 			Required: []string{"query"},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonSearchSimilar(ctx, client, args)
-	})
+	}, handleDaemonSearchSimilar)
+
+	registerDaemonTool(server, conn, Tool{
+		Name:        "search_symbols",
+		Description: "Find where a function, type or method is defined and which agents changed it, across the contexts shared in the cluster. Returns file paths, line ranges and the sharing agent. Use this instead of search_similar when you know the symbol name.",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"name": {
+					Type:        "string",
+					Description: "Symbol name, e.g. 'HandleLogin' or 'Server.Start'. A bare method name also matches it on any receiver",
+				},
+				"type": {
+					Type:        "string",
+					Description: "Only symbols of this kind (optional): function, method, struct, class, interface, type, variable, constant",
+				},
+				"file_path": {
+					Type:        "string",
+					Description: "Only symbols in this file, or under this directory when it ends with '/' (optional)",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Maximum number of results (default 10)",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}, handleDaemonSearchSymbols)
 
 	// Cluster status tools
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "cluster_status",
 		Description: "Get the current status of the agent cluster",
 		InputSchema: InputSchema{
 			Type:       "object",
 			Properties: map[string]Property{},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonClusterStatus(ctx, client, args)
-	})
+	}, handleDaemonClusterStatus)
 
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "list_agents",
 		Description: "List all connected agents in the cluster",
 		InputSchema: InputSchema{
 			Type:       "object",
 			Properties: map[string]Property{},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonListAgents(ctx, client, args)
-	})
+	}, handleDaemonListAgents)
 
 	// Event notification tools
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "get_events",
 		Description: "View recent cluster activity - see what other agents have been doing (file changes, lock acquisitions, context shares). Call this periodically during long tasks to stay aware of changes.",
 		InputSchema: InputSchema{
@@ -167,6 +377,10 @@ func RegisterDaemonTools(server *Server, client *daemon.Client) {
 					Type:        "string",
 					Description: "Filter by event type (optional): context.updated (file changes), lock.acquired, lock.conflict",
 				},
+				"agent": {
+					Type:        "string",
+					Description: "Only show events from this agent ID or name (optional)",
+				},
 				"limit": {
 					Type:        "integer",
 					Description: "Maximum number of events to return (default 10)",
@@ -177,23 +391,37 @@ func RegisterDaemonTools(server *Server, client *daemon.Client) {
 				},
 			},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonGetEvents(ctx, client, args)
-	})
+	}, handleDaemonGetEvents)
 
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "get_warnings",
 		Description: "IMPORTANT: Call this at the START of every task to check for conflicts or relevant updates from other agents. Shows lock conflicts, new context shares, and agent activity that may affect your work.",
 		InputSchema: InputSchema{
 			Type:       "object",
 			Properties: map[string]Property{},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonGetWarnings(ctx, client, args)
-	})
+	}, handleDaemonGetWarnings)
+
+	registerDaemonTool(server, conn, Tool{
+		Name:        "digest",
+		Description: "Summarize what happened while you were away. Call this at the START of a session to get recent context shares, file changes, and lock activity grouped by file and agent.",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"since": {
+					Type:        "string",
+					Description: "Start of the window: RFC3339 timestamp or duration ago such as '2h' (default 24h)",
+				},
+				"condense": {
+					Type:        "boolean",
+					Description: "If true, condense the digest with the configured summarizer (default false)",
+				},
+			},
+		},
+	}, handleDaemonDigest)
 
 	// Cohesion checking tool
-	server.RegisterTool(Tool{
+	registerDaemonTool(server, conn, Tool{
 		Name:        "check_cohesion",
 		Description: "Check if your intended work or completed result aligns with existing team context. Use type='before' with 'intention' parameter when starting work, or type='after' with 'result' parameter when done. Returns verdict (cohesive/conflict/uncertain), related contexts, and suggestions.",
 		InputSchema: InputSchema{
@@ -219,9 +447,7 @@ func RegisterDaemonTools(server *Server, client *daemon.Client) {
 			},
 			Required: []string{"type"},
 		},
-	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
-		return handleDaemonCheckCohesion(ctx, client, args)
-	})
+	}, handleDaemonCheckCohesion)
 }
 
 func handleDaemonAcquireLock(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
@@ -229,17 +455,63 @@ func handleDaemonAcquireLock(ctx context.Context, client *daemon.Client, args ma
 	startLine, _ := args["start_line"].(float64)
 	endLine, _ := args["end_line"].(float64)
 	intention, _ := args["intention"].(string)
+	ttlSeconds, _ := args["ttl_seconds"].(float64)
+	waitForLock, _ := args["wait_for_lock"].(bool)
+	waitTimeout, _ := args["wait_timeout_seconds"].(float64)
+	priority, _ := args["priority"].(float64)
+	mode, _ := args["mode"].(string)
+
+	req := daemon.LockRequest{
+		FilePath:           filePath,
+		StartLine:          int(startLine),
+		EndLine:            int(endLine),
+		Intention:          intention,
+		Mode:               mode,
+		TTLSeconds:         int(ttlSeconds),
+		WaitForLock:        waitForLock,
+		WaitTimeoutSeconds: int(waitTimeout),
+		Priority:           int(priority),
+	}
+	if deadline, _ := args["deadline"].(string); deadline != "" {
+		t, err := time.Parse(time.RFC3339, deadline)
+		if err != nil {
+			return textResult(fmt.Sprintf("Error: invalid deadline %q, expected RFC 3339 (e.g. 2025-01-02T15:04:05Z)", deadline)), nil
+		}
+		req.Deadline = t
+	}
 
-	result, err := client.AcquireLock(filePath, int(startLine), int(endLine), intention)
+	result, err := client.AcquireLockRequest("", req)
 	if err != nil {
 		return textResult(fmt.Sprintf("Error acquiring lock: %v", err)), nil
 	}
 
+	if result.RetryAfterMs > 0 {
+		return textResult(fmt.Sprintf("Lock denied: %s. Retry after %s.", result.Error, result.RetryAfter())), nil
+	}
 	if !result.Success {
 		return textResult(fmt.Sprintf("Lock denied: %s", result.Error)), nil
 	}
 
-	return textResult(fmt.Sprintf("Lock acquired successfully. Lock ID: %s", result.LockID)), nil
+	text := fmt.Sprintf("Lock acquired successfully. Lock ID: %s", result.LockID)
+	if result.FencingToken != 0 {
+		text += fmt.Sprintf(", fencing token: %d", result.FencingToken)
+	}
+	if !result.ExpiresAt.IsZero() {
+		text += fmt.Sprintf(" (expires %s)", result.ExpiresAt.Format(time.RFC3339))
+	}
+	if m, _ := lock.ParseAccessMode(mode); m == lock.AccessRead {
+		text += "\nThis is a read lock other agents may share; call upgrade_lock before modifying the region."
+	}
+	if result.PromotedTo != "" {
+		text += fmt.Sprintf("\nPromoted to a symbol lock on %s; its range follows your edits.", result.PromotedTo)
+	}
+	for _, notice := range result.Preempted {
+		text += fmt.Sprintf("\nTook over %s from %s (%s).", notice.Target, notice.HolderName, notice.Reason)
+	}
+	if result.ConflictHint.Elevated() {
+		text += "\n⚠️ " + conflictHintText(result.ConflictHint)
+	}
+	return textResult(text), nil
 }
 
 func handleDaemonReleaseLock(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
@@ -252,6 +524,49 @@ func handleDaemonReleaseLock(ctx context.Context, client *daemon.Client, args ma
 	return textResult(fmt.Sprintf("Lock %s released successfully", lockID)), nil
 }
 
+func handleDaemonUpgradeLock(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
+	lockID, _ := args["lock_id"].(string)
+
+	result, err := client.UpgradeLock(lockID)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error upgrading lock: %v", err)), nil
+	}
+	if !result.Success {
+		return textResult(fmt.Sprintf("Upgrade denied: %s", result.Error)), nil
+	}
+
+	return textResult(fmt.Sprintf("Lock %s upgraded to a write lock, fencing token %d", lockID, result.FencingToken)), nil
+}
+
+func handleDaemonReleaseLocksByIntention(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
+	intention, _ := args["intention"].(string)
+	prefix, _ := args["prefix"].(bool)
+
+	released, err := client.ReleaseLocksByIntention(intention, prefix)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error releasing locks: %v", err)), nil
+	}
+
+	return textResult(releasedText(intention, released)), nil
+}
+
+func handleDaemonRenewLock(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
+	lockID, _ := args["lock_id"].(string)
+	fencingToken, _ := args["fencing_token"].(float64)
+	ttlSeconds, _ := args["ttl_seconds"].(float64)
+
+	renewed, err := client.RenewLease(daemon.RenewLockRequest{
+		LockID:       lockID,
+		FencingToken: uint64(fencingToken),
+		TTLSeconds:   int(ttlSeconds),
+	})
+	if err != nil {
+		return textResult(fmt.Sprintf("Error renewing lock: %v", err)), nil
+	}
+
+	return textResult(fmt.Sprintf("Lock %s renewed until %s", lockID, renewed.ExpiresAt.Format(time.RFC3339))), nil
+}
+
 func handleDaemonListLocks(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
 	result, err := client.ListLocks()
 	if err != nil {
@@ -266,6 +581,65 @@ func handleDaemonListLocks(ctx context.Context, client *daemon.Client, args map[
 	return textResult(string(data)), nil
 }
 
+func handleDaemonCheckLock(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
+	filePath, _ := args["file_path"].(string)
+	startLine, _ := args["start_line"].(float64)
+	endLine, _ := args["end_line"].(float64)
+
+	mode, _ := args["mode"].(string)
+
+	check, err := client.CheckLock(filePath, int(startLine), int(endLine), mode)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error checking lock: %v", err)), nil
+	}
+	return textResult(lockCheckText(check)), nil
+}
+
+func handleDaemonReportActiveEdit(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
+	filePath, _ := args["file_path"].(string)
+	startLine, _ := args["start_line"].(float64)
+	endLine, _ := args["end_line"].(float64)
+	clearEdit, _ := args["clear"].(bool)
+
+	if filePath == "" {
+		return textResult("Error: file_path is required"), nil
+	}
+
+	if clearEdit {
+		if err := client.ClearActiveEdit(filePath); err != nil {
+			return textResult(fmt.Sprintf("Error clearing active edit: %v", err)), nil
+		}
+		return textResult(fmt.Sprintf("Active edit on %s cleared", filePath)), nil
+	}
+
+	result, err := client.ReportActiveEdit(filePath, int(startLine), int(endLine))
+	if err != nil {
+		return textResult(fmt.Sprintf("Error reporting active edit: %v", err)), nil
+	}
+
+	msg := fmt.Sprintf("Reported active edit on %s (expires %s)", result.Edit.Region(), result.Edit.ExpiresAt.Format(time.RFC3339))
+	for _, other := range result.Others {
+		msg += fmt.Sprintf("\nWarning: %s is also editing %s", other.AgentName, other.Region())
+	}
+	return textResult(msg), nil
+}
+
+func handleDaemonGetActiveEdits(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
+	filePath, _ := args["file_path"].(string)
+
+	result, err := client.ActiveEdits(filePath)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error listing active edits: %v", err)), nil
+	}
+
+	if len(result.Edits) == 0 {
+		return textResult("No active edits"), nil
+	}
+
+	data, _ := json.MarshalIndent(result.Edits, "", "  ")
+	return textResult(string(data)), nil
+}
+
 func handleDaemonShareContext(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
 	filePath, _ := args["file_path"].(string)
 	content, _ := args["content"].(string)
@@ -281,15 +655,48 @@ func handleDaemonShareContext(ctx context.Context, client *daemon.Client, args m
 	}
 
 	// Share context via daemon (stores in VectorDB and broadcasts to peers)
-	result, err := client.ShareContext(filePath, content, metadata)
+	result, err := client.ShareDerivedContext(filePath, content, metadata, parentIDsArg(args))
 	if err != nil {
 		return textResult(fmt.Sprintf("Error sharing context: %v", err)), nil
 	}
+	if result.Duplicate {
+		return textResult(fmt.Sprintf("Already shared, skipped (Document ID: %s)", result.DocumentID)), nil
+	}
 
 	return textResult(fmt.Sprintf("Context shared successfully. %s (Document ID: %s)",
 		result.Message, result.DocumentID)), nil
 }
 
+func handleDaemonGetProvenance(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
+	documentID, _ := args["document_id"].(string)
+	if documentID == "" {
+		return textResult("Error: document_id is required"), nil
+	}
+
+	result, err := client.GetProvenance(documentID)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error getting provenance: %v", err)), nil
+	}
+
+	data, _ := json.MarshalIndent(result.Chain, "", "  ")
+	return textResult(string(data)), nil
+}
+
+func handleDaemonRateContext(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
+	documentID, _ := args["document_id"].(string)
+	useful, ok := args["useful"].(bool)
+	if documentID == "" || !ok {
+		return textResult("Error: document_id and useful are required"), nil
+	}
+
+	result, err := client.RateContext(documentID, useful)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error rating context: %v", err)), nil
+	}
+
+	return textResult(ratingText(result.Rating)), nil
+}
+
 func handleDaemonEmbedText(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
 	text, _ := args["text"].(string)
 
@@ -308,8 +715,15 @@ func handleDaemonSearchSimilar(ctx context.Context, client *daemon.Client, args
 	if l, ok := args["limit"].(float64); ok {
 		limit = int(l)
 	}
-
-	result, err := client.Search(query, limit)
+	minScore, _ := args["min_score"].(float64)
+	branch, _ := args["branch"].(string)
+
+	result, err := client.SearchWith(daemon.SearchRequest{
+		Query:    query,
+		Limit:    limit,
+		MinScore: float32(minScore),
+		Branch:   branch,
+	})
 	if err != nil {
 		return textResult(fmt.Sprintf("Error searching: %v", err)), nil
 	}
@@ -322,6 +736,20 @@ func handleDaemonSearchSimilar(ctx context.Context, client *daemon.Client, args
 	return textResult(string(data)), nil
 }
 
+func handleDaemonSearchSymbols(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
+	result, err := client.SearchSymbols(symbolQuery(args))
+	if err != nil {
+		return textResult(fmt.Sprintf("Error searching symbols: %v", err)), nil
+	}
+
+	if len(result.Matches) == 0 {
+		return textResult("No matching symbols found in shared contexts"), nil
+	}
+
+	data, _ := json.MarshalIndent(result.Matches, "", "  ")
+	return textResult(string(data)), nil
+}
+
 func handleDaemonClusterStatus(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
 	status, err := client.Status()
 	if err != nil {
@@ -357,12 +785,14 @@ func handleDaemonGetEvents(ctx context.Context, client *daemon.Client, args map[
 		eventType = t
 	}
 
+	agent, _ := args["agent"].(string)
+
 	includeAll := false
 	if ia, ok := args["include_all"].(bool); ok {
 		includeAll = ia
 	}
 
-	result, err := client.ListEvents(limit, eventType, includeAll)
+	result, err := client.ListEventsByAgent(limit, eventType, agent, includeAll)
 	if err != nil {
 		return textResult(fmt.Sprintf("Error getting events: %v", err)), nil
 	}
@@ -446,6 +876,16 @@ func handleDaemonGetWarnings(ctx context.Context, client *daemon.Client, args ma
 			if err := json.Unmarshal(event.Data, &data); err == nil {
 				warnings = append(warnings, fmt.Sprintf("🔒 Lock acquired on %s by %s: %s", data.FilePath, data.AgentID, data.Intention))
 			}
+		case daemon.EventLockConflictPredicted:
+			var data lock.ConflictHint
+			if err := json.Unmarshal(event.Data, &data); err == nil {
+				warnings = append(warnings, "🔮 "+conflictHintText(&data))
+			}
+		case daemon.EventLockRenewalDue:
+			var data lock.RenewalNotice
+			if err := json.Unmarshal(event.Data, &data); err == nil {
+				warnings = append(warnings, fmt.Sprintf("⏰ Lock %s on %s expires in %s: call renew_lock to keep it", data.LockID, data.Target, data.Remaining.Round(time.Second)))
+			}
 		case daemon.EventAgentJoined:
 			var data daemon.AgentEventData
 			if err := json.Unmarshal(event.Data, &data); err == nil {
@@ -484,6 +924,37 @@ func handleDaemonGetWarnings(ctx context.Context, client *daemon.Client, args ma
 	return textResult(output), nil
 }
 
+func handleDaemonDigest(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
+	since, err := parseSince(args["since"])
+	if err != nil {
+		return textResult(fmt.Sprintf("Error: %v", err)), nil
+	}
+
+	condense, _ := args["condense"].(bool)
+
+	result, err := client.Digest(since, condense)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error building digest: %v", err)), nil
+	}
+	return textResult(result.Summary), nil
+}
+
+// parseSince accepts an RFC3339 timestamp or a duration ago, defaulting to 24h.
+func parseSince(v any) (time.Time, error) {
+	s, _ := v.(string)
+	if s == "" {
+		return time.Now().Add(-24 * time.Hour), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q: use RFC3339 or a duration like 2h", s)
+	}
+	return time.Now().Add(-d), nil
+}
+
 func handleDaemonCheckCohesion(ctx context.Context, client *daemon.Client, args map[string]any) (*ToolCallResult, error) {
 	checkType, _ := args["type"].(string)
 	intention, _ := args["intention"].(string)
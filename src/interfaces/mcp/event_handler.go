@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"sync"
 
+	"agent-collab/src/domain/lock"
 	"agent-collab/src/interfaces/daemon"
 )
 
@@ -31,6 +32,14 @@ func NewEventHandler(client *daemon.Client) *EventHandler {
 	}
 }
 
+// SetReplay makes the handler catch up on connect: the daemon replays
+// recent events at or after since (an RFC 3339 timestamp or a duration
+// ago such as "30m"), at most last of them, before live events. Call it
+// before Start.
+func (h *EventHandler) SetReplay(since string, last int) {
+	h.client.SetEventReplay(since, last)
+}
+
 // Start starts listening for events.
 func (h *EventHandler) Start(ctx context.Context) {
 	h.ctx, h.cancel = context.WithCancel(ctx)
@@ -84,6 +93,11 @@ func (h *EventHandler) handleEvent(event daemon.Event) {
 			h.warnings = append(h.warnings,
 				"🔒 Lock acquired on "+data.FilePath+" by "+data.AgentID+": "+data.Intention)
 		}
+	case daemon.EventLockConflictPredicted:
+		var data lock.ConflictHint
+		if err := json.Unmarshal(event.Data, &data); err == nil {
+			h.warnings = append(h.warnings, "🔮 "+conflictHintText(&data))
+		}
 	case daemon.EventAgentJoined:
 		var data daemon.AgentEventData
 		if err := json.Unmarshal(event.Data, &data); err == nil {
@@ -175,6 +189,10 @@ func RegisterEventTools(server *Server, handler *EventHandler) {
 					Type:        "integer",
 					Description: "Maximum number of events to return (default 10)",
 				},
+				"agent": {
+					Type:        "string",
+					Description: "Only show events from this agent ID or name (optional)",
+				},
 			},
 		},
 	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
@@ -207,6 +225,17 @@ func handleGetEvents(handler *EventHandler, args map[string]any) (*ToolCallResul
 		events = filtered
 	}
 
+	// Filter by agent if specified
+	if agent, ok := args["agent"].(string); ok && agent != "" {
+		filtered := make([]daemon.Event, 0)
+		for _, e := range events {
+			if e.MatchesAgent(agent) {
+				filtered = append(filtered, e)
+			}
+		}
+		events = filtered
+	}
+
 	// Apply limit
 	limit := 10
 	if l, ok := args["limit"].(float64); ok && l > 0 {
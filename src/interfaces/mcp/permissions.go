@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Permission is an elevated right a tool can require from the calling agent.
+type Permission string
+
+const (
+	// PermissionLockAdmin allows breaking locks held by other agents.
+	PermissionLockAdmin Permission = "lock:admin"
+	// PermissionConfigAdmin allows changing runtime settings such as log levels.
+	PermissionConfigAdmin Permission = "config:admin"
+)
+
+// AnyAgent grants permissions to every agent that connects.
+const AnyAgent = "*"
+
+// knownPermissions lists the permissions tools may require.
+var knownPermissions = []Permission{PermissionLockAdmin, PermissionConfigAdmin}
+
+// ParsePermission parses a permission name.
+func ParsePermission(name string) (Permission, error) {
+	for _, p := range knownPermissions {
+		if string(p) == name {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown permission %q (known: %s)", name, joinPermissions(knownPermissions))
+}
+
+// PermissionDeniedError is returned when the calling agent lacks a
+// permission a tool requires.
+type PermissionDeniedError struct {
+	Tool    string
+	Agent   string
+	Missing []Permission
+}
+
+func (e *PermissionDeniedError) Error() string {
+	agent := e.Agent
+	if agent == "" {
+		agent = "unidentified agent"
+	}
+	return fmt.Sprintf("permission denied: tool %q requires %s, which %s has not been granted",
+		e.Tool, joinPermissions(e.Missing), agent)
+}
+
+// GrantPermissions grants permissions to an agent, identified by the client
+// name it sends on initialize. Use AnyAgent to grant them to every agent.
+func (s *Server) GrantPermissions(agent string, perms ...Permission) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	granted, ok := s.grants[agent]
+	if !ok {
+		granted = make(map[Permission]bool)
+		s.grants[agent] = granted
+	}
+	for _, p := range perms {
+		granted[p] = true
+	}
+}
+
+// authorize checks that the connected agent holds every permission the
+// tool requires.
+func (s *Server) authorize(toolName string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	required := s.permissions[toolName]
+	if len(required) == 0 {
+		return nil
+	}
+
+	agent := s.clientInfo.Name
+	var missing []Permission
+	for _, p := range required {
+		if !s.grants[agent][p] && !s.grants[AnyAgent][p] {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		return &PermissionDeniedError{Tool: toolName, Agent: agent, Missing: missing}
+	}
+	return nil
+}
+
+func joinPermissions(perms []Permission) string {
+	names := make([]string, len(perms))
+	for i, p := range perms {
+		names[i] = string(p)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// callAs initializes a session as the named agent, calls a tool and
+// returns the tool result.
+func callAs(t *testing.T, server *Server, agent, tool string) ToolCallResult {
+	t.Helper()
+
+	var in bytes.Buffer
+	for _, req := range []JSONRPCRequest{
+		{JSONRPC: "2.0", ID: 1, Method: "initialize", Params: json.RawMessage(`{"protocolVersion":"2024-11-05","clientInfo":{"name":"` + agent + `","version":"1.0"}}`)},
+		{JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: json.RawMessage(`{"name":"` + tool + `"}`)},
+	} {
+		data, _ := json.Marshal(req)
+		in.Write(append(data, '\n'))
+	}
+
+	var out bytes.Buffer
+	if err := server.Serve(context.Background(), &in, &out); err != nil {
+		t.Fatalf("serve failed: %v", err)
+	}
+
+	scanner := bufio.NewScanner(&out)
+	var last []byte
+	for scanner.Scan() {
+		last = append([]byte(nil), scanner.Bytes()...)
+	}
+	var resp struct {
+		Result ToolCallResult `json:"result"`
+		Error  *JSONRPCError  `json:"error"`
+	}
+	if err := json.Unmarshal(last, &resp); err != nil {
+		t.Fatalf("bad response %s: %v", last, err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected JSON-RPC error: %+v", resp.Error)
+	}
+	return resp.Result
+}
+
+func newPermissionServer() *Server {
+	server := NewServer("test", "1.0.0", nil)
+	server.RegisterTool(Tool{Name: "list_locks"}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+		return textResult("listed"), nil
+	})
+	server.RegisterTool(Tool{Name: "force_release_lock", Permissions: []Permission{PermissionLockAdmin}}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+		return textResult("released"), nil
+	})
+	return server
+}
+
+func TestServer_ElevatedToolDeniedWithoutPermission(t *testing.T) {
+	server := newPermissionServer()
+	server.GrantPermissions("other-agent", PermissionLockAdmin)
+
+	result := callAs(t, server, "cursor", "force_release_lock")
+	if !result.IsError {
+		t.Fatalf("unprivileged agent should be denied, got %+v", result)
+	}
+	text := result.Content[0].Text
+	if !strings.Contains(text, "permission denied") || !strings.Contains(text, string(PermissionLockAdmin)) || !strings.Contains(text, "cursor") {
+		t.Errorf("denial should name the tool, permission and agent: %q", text)
+	}
+
+	// Tools without requirements stay open to everyone
+	if result := callAs(t, newPermissionServer(), "cursor", "list_locks"); result.IsError {
+		t.Errorf("unrestricted tool should be allowed, got %+v", result)
+	}
+}
+
+func TestServer_ElevatedToolAllowedWithPermission(t *testing.T) {
+	server := newPermissionServer()
+	server.GrantPermissions("claude-code", PermissionLockAdmin)
+	if result := callAs(t, server, "claude-code", "force_release_lock"); result.IsError || result.Content[0].Text != "released" {
+		t.Errorf("privileged agent should be allowed, got %+v", result)
+	}
+
+	server = newPermissionServer()
+	server.GrantPermissions(AnyAgent, PermissionLockAdmin)
+	if result := callAs(t, server, "cursor", "force_release_lock"); result.IsError {
+		t.Errorf("permission granted to any agent should allow the call, got %+v", result)
+	}
+}
+
+func TestParsePermission(t *testing.T) {
+	if p, err := ParsePermission("lock:admin"); err != nil || p != PermissionLockAdmin {
+		t.Errorf("expected lock:admin, got %q (%v)", p, err)
+	}
+	if _, err := ParsePermission("root"); err == nil {
+		t.Error("unknown permission should be rejected")
+	}
+}
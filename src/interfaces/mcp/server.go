@@ -24,6 +24,10 @@ type Server struct {
 	tools    map[string]ToolHandler
 	toolList []Tool
 
+	// Permissions required per tool and granted per agent
+	permissions map[string][]Permission
+	grants      map[string]map[Permission]bool
+
 	// Resource handlers
 	resources    map[string]ResourceHandler
 	resourceList []Resource
@@ -52,22 +56,28 @@ type ResourceHandler func(ctx context.Context, uri string) (*ReadResourceResult,
 // NewServer creates a new MCP server.
 func NewServer(name, version string, registry *agent.Registry) *Server {
 	return &Server{
-		name:      name,
-		version:   version,
-		tools:     make(map[string]ToolHandler),
-		toolList:  make([]Tool, 0),
-		resources: make(map[string]ResourceHandler),
-		registry:  registry,
+		name:        name,
+		version:     version,
+		tools:       make(map[string]ToolHandler),
+		toolList:    make([]Tool, 0),
+		permissions: make(map[string][]Permission),
+		grants:      make(map[string]map[Permission]bool),
+		resources:   make(map[string]ResourceHandler),
+		registry:    registry,
 	}
 }
 
-// RegisterTool registers a tool.
+// RegisterTool registers a tool. Calls are refused unless the calling
+// agent has been granted every permission in tool.Permissions.
 func (s *Server) RegisterTool(tool Tool, handler ToolHandler) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.tools[tool.Name] = handler
 	s.toolList = append(s.toolList, tool)
+	if len(tool.Permissions) > 0 {
+		s.permissions[tool.Name] = tool.Permissions
+	}
 }
 
 // RegisterResource registers a resource.
@@ -222,6 +232,13 @@ func (s *Server) handleCallTool(req *JSONRPCRequest) error {
 		return s.sendError(req.ID, ErrorCodeMethodNotFound, "Tool not found", nil)
 	}
 
+	if err := s.authorize(params.Name); err != nil {
+		return s.sendResult(req.ID, ToolCallResult{
+			Content: []Content{{Type: "text", Text: err.Error()}},
+			IsError: true,
+		})
+	}
+
 	result, err := handler(s.ctx, params.Arguments)
 	if err != nil {
 		return s.sendResult(req.ID, ToolCallResult{
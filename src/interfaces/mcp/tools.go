@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"agent-collab/src/application"
 	"agent-collab/src/domain/cohesion"
+	"agent-collab/src/domain/lock"
 	"agent-collab/src/infrastructure/embedding"
 	"agent-collab/src/infrastructure/storage/vector"
 )
@@ -36,6 +39,7 @@ func RegisterDefaultTools(server *Server, app *application.App) {
 					Type:        "string",
 					Description: "What you intend to do with this region",
 				},
+				"mode": lockModeProperty,
 			},
 			Required: []string{"file_path", "start_line", "end_line", "intention"},
 		},
@@ -60,6 +64,70 @@ func RegisterDefaultTools(server *Server, app *application.App) {
 		return handleReleaseLock(ctx, app, args)
 	})
 
+	server.RegisterTool(Tool{
+		Name:        "upgrade_lock",
+		Description: upgradeLockDescription,
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"lock_id": {
+					Type:        "string",
+					Description: "ID of the read lock to upgrade",
+				},
+			},
+			Required: []string{"lock_id"},
+		},
+	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+		return handleUpgradeLock(ctx, app, args)
+	})
+
+	server.RegisterTool(Tool{
+		Name:        "release_locks_by_intention",
+		Description: "Release all locks you acquired under one intention, e.g. when the task is done",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"intention": {
+					Type:        "string",
+					Description: "Intention the locks were acquired with",
+				},
+				"prefix": {
+					Type:        "boolean",
+					Description: "Also release locks whose intention starts with this text (default false)",
+				},
+			},
+			Required: []string{"intention"},
+		},
+	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+		return handleReleaseLocksByIntention(ctx, app, args)
+	})
+
+	server.RegisterTool(Tool{
+		Name:        "check_lock",
+		Description: "Check whether a region is free to lock without announcing an intent: reports who holds overlapping locks and the expected wait. Use it to plan the order of your work; acquire_lock still decides.",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"file_path": {
+					Type:        "string",
+					Description: "Path to the file you want to modify",
+				},
+				"start_line": {
+					Type:        "integer",
+					Description: "Start line of the region (use 1 for entire file)",
+				},
+				"end_line": {
+					Type:        "integer",
+					Description: "End line of the region (use -1 for entire file)",
+				},
+				"mode": checkModeProperty,
+			},
+			Required: []string{"file_path", "start_line", "end_line"},
+		},
+	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+		return handleCheckLock(ctx, app, args)
+	})
+
 	server.RegisterTool(Tool{
 		Name:        "list_locks",
 		Description: "List all active locks in the cluster",
@@ -90,6 +158,10 @@ func RegisterDefaultTools(server *Server, app *application.App) {
 					Type:        "object",
 					Description: "Additional metadata",
 				},
+				"parent_ids": {
+					Type:        "array",
+					Description: "Document IDs of earlier shares this builds on",
+				},
 			},
 			Required: []string{"file_path", "content"},
 		},
@@ -97,6 +169,44 @@ func RegisterDefaultTools(server *Server, app *application.App) {
 		return handleShareContext(ctx, app, args)
 	})
 
+	server.RegisterTool(Tool{
+		Name:        "get_provenance",
+		Description: "Show who shared a document and the earlier shares it was derived from",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"document_id": {
+					Type:        "string",
+					Description: "Document ID of the shared context",
+				},
+			},
+			Required: []string{"document_id"},
+		},
+	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+		return handleGetProvenance(ctx, app, args)
+	})
+
+	server.RegisterTool(Tool{
+		Name:        "rate_context",
+		Description: "Record whether a search result was useful, so future searches rank it higher or lower",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"document_id": {
+					Type:        "string",
+					Description: "Document ID of a search_similar result",
+				},
+				"useful": {
+					Type:        "boolean",
+					Description: "Whether the result helped with the task",
+				},
+			},
+			Required: []string{"document_id", "useful"},
+		},
+	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+		return handleRateContext(ctx, app, args)
+	})
+
 	// Embedding tools
 	server.RegisterTool(Tool{
 		Name:        "embed_text",
@@ -129,6 +239,18 @@ func RegisterDefaultTools(server *Server, app *application.App) {
 					Type:        "integer",
 					Description: "Maximum number of results (default 10)",
 				},
+				"min_score": {
+					Type:        "number",
+					Description: "Drop results with similarity below this score, 0-1 (default 0). Raise it (e.g. 0.7) to get only strong matches",
+				},
+				"collection": {
+					Type:        "string",
+					Description: "Search only this collection, e.g. \"default\" for shared notes (default: all collections)",
+				},
+				"branch": {
+					Type:        "string",
+					Description: "Only return context shared on this git branch (default: all branches)",
+				},
 			},
 			Required: []string{"query"},
 		},
@@ -136,6 +258,35 @@ func RegisterDefaultTools(server *Server, app *application.App) {
 		return handleSearchSimilar(ctx, app, args)
 	})
 
+	server.RegisterTool(Tool{
+		Name:        "search_symbols",
+		Description: "Find where a function, type or method is defined and which agents changed it, across the contexts shared in the cluster. Returns file paths, line ranges and the sharing agent. Use this instead of search_similar when you know the symbol name.",
+		InputSchema: InputSchema{
+			Type: "object",
+			Properties: map[string]Property{
+				"name": {
+					Type:        "string",
+					Description: "Symbol name, e.g. 'HandleLogin' or 'Server.Start'. A bare method name also matches it on any receiver",
+				},
+				"type": {
+					Type:        "string",
+					Description: "Only symbols of this kind (optional): function, method, struct, class, interface, type, variable, constant",
+				},
+				"file_path": {
+					Type:        "string",
+					Description: "Only symbols in this file, or under this directory when it ends with '/' (optional)",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Maximum number of results (default 10)",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}, func(ctx context.Context, args map[string]any) (*ToolCallResult, error) {
+		return handleSearchSymbols(ctx, app, args)
+	})
+
 	// Cluster status tools
 	server.RegisterTool(Tool{
 		Name:        "cluster_status",
@@ -190,6 +341,22 @@ func RegisterDefaultTools(server *Server, app *application.App) {
 	})
 }
 
+// lockModeProperty is the mode parameter of acquire_lock.
+var lockModeProperty = Property{
+	Type:        "string",
+	Description: "\"read\" if you only need the region to stay unchanged while you read it: other readers may share it, writers wait. \"write\" (default) to modify it exclusively",
+	Enum:        []string{string(lock.AccessRead), string(lock.AccessWrite)},
+}
+
+// checkModeProperty is the mode parameter of check_lock.
+var checkModeProperty = Property{
+	Type:        "string",
+	Description: "Mode you would lock the region in (default write); a read check only reports write locks",
+	Enum:        []string{string(lock.AccessRead), string(lock.AccessWrite)},
+}
+
+const upgradeLockDescription = "Turn a read lock you hold into a write lock before modifying the region. If other agents also read it, a negotiation with them is started instead and the lock stays a read lock until they yield."
+
 func handleAcquireLock(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
 	lockService := app.LockService()
 	if lockService == nil {
@@ -200,12 +367,30 @@ func handleAcquireLock(ctx context.Context, app *application.App, args map[strin
 	startLine, _ := args["start_line"].(float64)
 	endLine, _ := args["end_line"].(float64)
 	intention, _ := args["intention"].(string)
+	modeArg, _ := args["mode"].(string)
+	mode, err := lock.ParseAccessMode(modeArg)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error: %v", err)), nil
+	}
 
 	// Note: This is a simplified version. In production, you'd use the full lock request.
-	result := fmt.Sprintf("Lock requested for %s lines %d-%d: %s", filePath, int(startLine), int(endLine), intention)
+	result := fmt.Sprintf("Lock requested for %s lines %d-%d (%s): %s", filePath, int(startLine), int(endLine), mode, intention)
+	if hint := lockService.PredictConflict(filePath); hint.Elevated() {
+		result += "\n" + conflictHintText(hint)
+	}
 	return textResult(result), nil
 }
 
+// conflictHintText describes a conflict prediction in one line.
+func conflictHintText(h *lock.ConflictHint) string {
+	text := fmt.Sprintf("Conflict likelihood on %s: %s (%d locks, %d conflicts, %d held now in the last %s)",
+		h.FilePath, h.Likelihood, h.Acquisitions, h.Conflicts, h.ActiveLocks, h.Window)
+	if len(h.Agents) > 0 {
+		text += " by " + strings.Join(h.Agents, ", ")
+	}
+	return text
+}
+
 func handleReleaseLock(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
 	lockService := app.LockService()
 	if lockService == nil {
@@ -220,6 +405,106 @@ func handleReleaseLock(ctx context.Context, app *application.App, args map[strin
 	return textResult(fmt.Sprintf("Lock %s released successfully", lockID)), nil
 }
 
+func handleUpgradeLock(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
+	lockService := app.LockService()
+	if lockService == nil {
+		return textResult("Error: Lock service not initialized"), nil
+	}
+
+	lockID, _ := args["lock_id"].(string)
+	if _, err := lockService.UpgradeLock(ctx, lockID); err != nil {
+		return textResult(fmt.Sprintf("Upgrade denied: %v", err)), nil
+	}
+
+	return textResult(fmt.Sprintf("Lock %s upgraded to a write lock", lockID)), nil
+}
+
+func handleReleaseLocksByIntention(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
+	lockService := app.LockService()
+	if lockService == nil {
+		return textResult("Error: Lock service not initialized"), nil
+	}
+
+	intention, _ := args["intention"].(string)
+	prefix, _ := args["prefix"].(bool)
+	released, err := lockService.ReleaseLocksByIntention(ctx, intention, prefix)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error releasing locks: %v", err)), nil
+	}
+
+	return textResult(releasedText(intention, released)), nil
+}
+
+// releasedText lists the locks freed by a bulk release.
+func releasedText(intention string, released []*lock.SemanticLock) string {
+	if len(released) == 0 {
+		return fmt.Sprintf("No locks held with intention %q", intention)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Released %d locks:", len(released))
+	for _, l := range released {
+		fmt.Fprintf(&b, "\n- %s %s:%d-%d (%s)", l.ID, l.Target.FilePath, l.Target.StartLine, l.Target.EndLine, l.Intention)
+	}
+	return b.String()
+}
+
+func handleCheckLock(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
+	lockService := app.LockService()
+	if lockService == nil {
+		return textResult("Error: Lock service not initialized"), nil
+	}
+
+	filePath, _ := args["file_path"].(string)
+	startLine, _ := args["start_line"].(float64)
+	endLine, _ := args["end_line"].(float64)
+
+	mode, _ := args["mode"].(string)
+
+	check, err := lockService.CheckLock(&lock.AcquireLockRequest{
+		TargetType: lock.TargetFile,
+		FilePath:   filePath,
+		StartLine:  int(startLine),
+		EndLine:    int(endLine),
+		Mode:       lock.AccessMode(mode),
+	})
+	if err != nil {
+		return textResult(fmt.Sprintf("Error checking lock: %v", err)), nil
+	}
+	return textResult(lockCheckText(check)), nil
+}
+
+// lockCheckText describes a dry-run lock check: whether the region is
+// free, and otherwise who holds it and until when.
+func lockCheckText(c *lock.LockCheck) string {
+	region := func(t *lock.SemanticTarget) string {
+		return fmt.Sprintf("%s:%d-%d", t.FilePath, t.StartLine, t.EndLine)
+	}
+
+	var b strings.Builder
+	if c.Free {
+		fmt.Fprintf(&b, "%s is free to lock", region(c.Target))
+	} else {
+		fmt.Fprintf(&b, "%s is locked; expected wait %s unless the holders renew or release early",
+			region(c.Target), c.ExpectedWait.Round(time.Second))
+		for _, l := range c.Conflicts {
+			verb := "holds"
+			if l.Shared() {
+				verb = "reads"
+			}
+			fmt.Fprintf(&b, "\n- %s %s %s (%s), lock %s, expires in %s",
+				l.HolderName, verb, region(l.Target), l.Intention, l.ID, l.TTLRemaining().Round(time.Second))
+		}
+	}
+	if c.Waiting > 0 {
+		fmt.Fprintf(&b, "\n%d agent(s) on this node are waiting for regions of this file", c.Waiting)
+	}
+	if c.ConflictHint.Elevated() {
+		b.WriteString("\n" + conflictHintText(c.ConflictHint))
+	}
+	return b.String()
+}
+
 func handleListLocks(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
 	lockService := app.LockService()
 	if lockService == nil {
@@ -249,47 +534,58 @@ func handleShareContext(ctx context.Context, app *application.App, args map[stri
 		return textResult("Error: content is required for sharing context"), nil
 	}
 
-	vectorStore := app.VectorStore()
-	embedService := app.EmbeddingService()
-	if vectorStore == nil || embedService == nil {
-		return textResult("Error: Vector store or embedding service not initialized"), nil
-	}
-
-	// Generate embedding for the content
-	embedding, err := embedService.Embed(ctx, content)
+	result, err := app.ShareDerivedContext(ctx, filePath, content, metadata, parentIDsArg(args))
 	if err != nil {
-		return textResult(fmt.Sprintf("Error generating embedding: %v", err)), nil
+		return textResult(fmt.Sprintf("Error sharing context: %v", err)), nil
+	}
+	if result.Duplicate {
+		return textResult(fmt.Sprintf("Already shared, skipped (Document ID: %s)", result.DocumentID)), nil
 	}
 
-	// Create document
-	doc := &vector.Document{
-		Content:   content,
-		Embedding: embedding,
-		FilePath:  filePath,
-		Metadata:  metadata,
+	// Also watch the file for future changes if syncManager is available
+	syncManager := app.SyncManager()
+	if syncManager != nil && filePath != "" {
+		syncManager.WatchFile(filePath)
 	}
 
-	// Insert into vector store
-	if err := vectorStore.Insert(doc); err != nil {
-		return textResult(fmt.Sprintf("Error storing context: %v", err)), nil
+	return textResult(fmt.Sprintf("Context shared successfully (Document ID: %s, embedding: %d dims)",
+		result.DocumentID, len(result.Embedding))), nil
+}
+
+func handleGetProvenance(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
+	documentID, _ := args["document_id"].(string)
+	if documentID == "" {
+		return textResult("Error: document_id is required"), nil
 	}
 
-	// Flush to persist
-	if err := vectorStore.Flush(); err != nil {
-		return textResult(fmt.Sprintf("Error persisting context: %v", err)), nil
+	chain, err := app.GetProvenance(documentID)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error getting provenance: %v", err)), nil
 	}
 
-	// Publish to EventRouter for interest-based routing
-	app.PublishContextSharedEvent(ctx, filePath, content, embedding)
+	data, _ := json.MarshalIndent(chain, "", "  ")
+	return textResult(string(data)), nil
+}
 
-	// Also watch the file for future changes if syncManager is available
-	syncManager := app.SyncManager()
-	if syncManager != nil && filePath != "" {
-		syncManager.WatchFile(filePath)
+func handleRateContext(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
+	documentID, _ := args["document_id"].(string)
+	useful, ok := args["useful"].(bool)
+	if documentID == "" || !ok {
+		return textResult("Error: document_id and useful are required"), nil
 	}
 
-	return textResult(fmt.Sprintf("Context shared successfully (Document ID: %s, embedding: %d dims)",
-		doc.ID, len(embedding))), nil
+	rating, err := app.RateContext(documentID, useful)
+	if err != nil {
+		return textResult(fmt.Sprintf("Error rating context: %v", err)), nil
+	}
+
+	return textResult(ratingText(rating)), nil
+}
+
+// ratingText summarizes the feedback recorded for a document.
+func ratingText(r *application.ContextRating) string {
+	return fmt.Sprintf("Feedback recorded for %s (%d useful, %d not useful, ranking boost %+.3f)",
+		r.DocumentID, r.Useful, r.NotUseful, r.Boost)
 }
 
 func handleEmbedText(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
@@ -319,17 +615,22 @@ func handleSearchSimilar(ctx context.Context, app *application.App, args map[str
 	if l, ok := args["limit"].(float64); ok {
 		limit = int(l)
 	}
+	minScore, _ := args["min_score"].(float64)
+	collection, _ := args["collection"].(string)
+	branch, _ := args["branch"].(string)
 
 	// Generate embedding for query
-	embedding, err := embedService.Embed(ctx, query)
+	embedding, err := embedService.EmbedQuery(ctx, query)
 	if err != nil {
 		return textResult(fmt.Sprintf("Error generating embedding: %v", err)), nil
 	}
 
 	// Search using the embedding
 	results, err := vectorStore.Search(embedding, &vector.SearchOptions{
-		Collection: "default",
+		Collection: collection,
 		TopK:       limit,
+		MinScore:   float32(minScore),
+		Filters:    application.BranchFilter(branch),
 	})
 	if err != nil {
 		return textResult(fmt.Sprintf("Error searching: %v", err)), nil
@@ -343,6 +644,32 @@ func handleSearchSimilar(ctx context.Context, app *application.App, args map[str
 	return textResult(string(data)), nil
 }
 
+func handleSearchSymbols(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
+	matches, err := app.SearchSymbols(ctx, symbolQuery(args))
+	if err != nil {
+		return textResult(fmt.Sprintf("Error searching symbols: %v", err)), nil
+	}
+
+	if len(matches) == 0 {
+		return textResult("No matching symbols found in shared contexts"), nil
+	}
+
+	data, _ := json.MarshalIndent(matches, "", "  ")
+	return textResult(string(data)), nil
+}
+
+// symbolQuery reads the search_symbols arguments.
+func symbolQuery(args map[string]any) application.SymbolQuery {
+	q := application.SymbolQuery{}
+	q.Name, _ = args["name"].(string)
+	q.Type, _ = args["type"].(string)
+	q.FilePath, _ = args["file_path"].(string)
+	if l, ok := args["limit"].(float64); ok {
+		q.Limit = int(l)
+	}
+	return q
+}
+
 func handleClusterStatus(ctx context.Context, app *application.App, args map[string]any) (*ToolCallResult, error) {
 	status := app.GetStatus()
 	data, _ := json.MarshalIndent(status, "", "  ")
@@ -420,6 +747,19 @@ func handleCheckCohesion(ctx context.Context, app *application.App, args map[str
 	return textResult(string(data)), nil
 }
 
+// parentIDsArg parses the optional parent_ids argument of share_context.
+func parentIDsArg(args map[string]any) []string {
+	var parentIDs []string
+	if ids, ok := args["parent_ids"].([]any); ok {
+		for _, id := range ids {
+			if s, ok := id.(string); ok && s != "" {
+				parentIDs = append(parentIDs, s)
+			}
+		}
+	}
+	return parentIDs
+}
+
 func textResult(text string) *ToolCallResult {
 	return &ToolCallResult{
 		Content: []Content{{Type: "text", Text: text}},
@@ -87,6 +87,10 @@ type Tool struct {
 	Name        string      `json:"name"`
 	Description string      `json:"description,omitempty"`
 	InputSchema InputSchema `json:"inputSchema"`
+
+	// Permissions the calling agent must hold; enforced by the server and
+	// not sent to clients.
+	Permissions []Permission `json:"-"`
 }
 
 type InputSchema struct {
@@ -27,6 +27,10 @@ func WithStartTab(tab string) Option {
 			m.activeTab = TabTokens
 		case "peers":
 			m.activeTab = TabPeers
+		case "negotiations":
+			m.activeTab = TabNegotiations
+		case "events":
+			m.activeTab = TabEvents
 		}
 	}
 }
@@ -165,6 +169,11 @@ func defaultCommandHints() []CommandHint {
 				{Command: "reset", Description: "설정 초기화", Args: ""},
 			},
 		},
+		{
+			Command:     "snapshot",
+			Description: "화면 상태를 JSON으로 저장",
+			Args:        "[path]",
+		},
 		{
 			Command:     "help",
 			Description: "도움말",
@@ -233,5 +242,6 @@ func (m Model) fetchInitialData() tea.Cmd {
 		},
 		m.fetchPeers(),
 		m.fetchLocks(),
+		m.fetchNegotiations(),
 	)
 }
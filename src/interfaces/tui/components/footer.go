@@ -62,7 +62,7 @@ func (f *Footer) View() string {
 	}{
 		{"q", "Quit"},
 		{"r", "Refresh"},
-		{"1-5", "Tab"},
+		{"1-7", "Tab"},
 		{"↑↓", "Navigate"},
 		{"?", "Help"},
 	}
@@ -10,6 +10,9 @@ import (
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+
+	"agent-collab/src/domain/lock"
 	"agent-collab/src/interfaces/daemon"
 )
 
@@ -275,6 +278,168 @@ func TestFeature_TUIExecute_Scenario_ReleaseLock(t *testing.T) {
 	})
 }
 
+// Scenario: Settle an escalated lock negotiation
+func TestFeature_TUIExecute_Scenario_SettleNegotiation(t *testing.T) {
+	t.Run("Given a TUI model showing an escalated negotiation", func(t *testing.T) {
+		server := newMockTUIDaemonServer(t)
+		defer server.Close()
+
+		var proposal daemon.NegotiationProposeRequest
+		server.SetHandler("/negotiations/propose", func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&proposal)
+			json.NewEncoder(w).Encode(daemon.NegotiationProposeResponse{
+				Success: true,
+				Result:  &lock.NegotiationResult{Success: true, Message: "alice yielded to bob"},
+			})
+		})
+		voteCalled := false
+		server.SetHandler("/negotiations/vote", func(w http.ResponseWriter, r *http.Request) {
+			voteCalled = true
+		})
+
+		now := time.Now()
+		m := NewModelWithClient(server.Client())
+		m.negotiationsData.Sessions = negotiationInfos([]*lock.NegotiationSession{{
+			ID:              "neg-1",
+			State:           lock.StateEscalated,
+			RequestedLock:   &lock.SemanticLock{HolderID: "node-b", HolderName: "bob", Target: &lock.SemanticTarget{FilePath: "auth.go", StartLine: 10, EndLine: 20}},
+			ConflictingLock: &lock.SemanticLock{HolderID: "node-a", HolderName: "alice", Target: &lock.SemanticTarget{FilePath: "auth.go", StartLine: 1, EndLine: 50}},
+			Votes:           map[string]*lock.Vote{"node-a": {Approve: true}},
+			RequiredVotes:   2,
+			ExpiresAt:       now.Add(-time.Minute),
+			Resolution:      &lock.NegotiationResult{ResolutionType: lock.ResolutionHumanNeeded, Message: "escalated: both need it"},
+		}}, now)
+
+		t.Run("Then the session should be shown as waiting for a human", func(t *testing.T) {
+			s := m.negotiationsData.SelectedSession()
+			if s == nil || !s.Escalated || s.HeldRange != "1-50" || s.Approvals != 1 || s.Resolution != "escalated: both need it" {
+				t.Fatalf("unexpected negotiation info: %+v", s)
+			}
+		})
+
+		t.Run("When I vote on it", func(t *testing.T) {
+			err := m.executeVoteNegotiation(*m.negotiationsData.SelectedSession(), true)
+
+			t.Run("Then the vote should be refused without calling the daemon", func(t *testing.T) {
+				if err == nil || voteCalled {
+					t.Errorf("expected a local refusal, got err=%v called=%v", err, voteCalled)
+				}
+			})
+		})
+
+		t.Run("When I have the holder yield", func(t *testing.T) {
+			err := m.executeYieldNegotiation("neg-1", true)
+
+			t.Run("Then a yield by the holder should be proposed", func(t *testing.T) {
+				if err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+				if proposal.SessionID != "neg-1" || proposal.Type != lock.ProposalYield || proposal.YielderID != "node-a" {
+					t.Errorf("unexpected proposal: %+v", proposal)
+				}
+			})
+
+			t.Run("And the outcome should be reported", func(t *testing.T) {
+				if m.lastResult != "협상 alice 양보: alice yielded to bob" {
+					t.Errorf("unexpected result: %q", m.lastResult)
+				}
+			})
+		})
+	})
+}
+
+// Scenario: Tail cluster events in the Events tab
+func TestFeature_TUIExecute_Scenario_TailEvents(t *testing.T) {
+	t.Run("Given a TUI model on the Events tab", func(t *testing.T) {
+		m := *NewApp(WithStartTab("events"))
+		m.locksData.Locks = []LockInfo{{ID: "lock-1", Holder: "alice", Target: "auth.go"}}
+		m.peersData.Peers = []PeerInfo{{ID: "peer-b", Name: "bob"}}
+
+		now := time.Now()
+		receive := func(e daemon.Event) {
+			next, _ := m.Update(ClusterEventMsg{Event: e})
+			m = next.(Model)
+		}
+		press := func(keys string) {
+			next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(keys)})
+			m = next.(Model)
+		}
+
+		t.Run("When lock, context and peer events arrive", func(t *testing.T) {
+			acquired := daemon.NewEvent(daemon.EventLockAcquired, daemon.LockEventData{LockID: "lock-1", FilePath: "auth.go", AgentID: "alice", Intention: "refactor"})
+			acquired.Timestamp = now
+			status := daemon.NewEvent(daemon.EventStatusUpdated, daemon.StatusResponse{})
+			status.Timestamp = now.Add(time.Second)
+			shared := daemon.NewEvent(daemon.EventContextUpdated, daemon.ContextEventData{FilePath: "api.go", AgentID: "bob"})
+			shared.Timestamp = now.Add(2 * time.Second)
+			receive(acquired)
+			receive(status)
+			receive(shared)
+			// 재연결 후 다시 받은 이벤트
+			receive(acquired)
+
+			t.Run("Then they should be listed once, newest selected", func(t *testing.T) {
+				events := m.eventsData.Visible()
+				if len(events) != 2 || events[0].Summary != "auth.go - refactor" || events[1].Agent != "bob" {
+					t.Fatalf("unexpected events: %+v", events)
+				}
+				if m.eventsData.SelectedIndex != 1 {
+					t.Errorf("expected the newest event selected, got %d", m.eventsData.SelectedIndex)
+				}
+			})
+		})
+
+		t.Run("When I pause the feed", func(t *testing.T) {
+			press("p")
+			joined := daemon.NewEvent(daemon.EventPeerConnected, daemon.PeerEventData{PeerID: "peer-b"})
+			joined.Timestamp = now.Add(3 * time.Second)
+			receive(joined)
+
+			t.Run("Then new events should wait until I resume", func(t *testing.T) {
+				if len(m.eventsData.Events) != 2 || len(m.eventsData.Pending) != 1 {
+					t.Fatalf("expected one pending event, got %+v", m.eventsData)
+				}
+				press("p")
+				if len(m.eventsData.Events) != 3 || m.eventsData.SelectedIndex != 2 {
+					t.Errorf("expected the pending event added and selected, got %+v", m.eventsData)
+				}
+			})
+		})
+
+		t.Run("When I filter by agent", func(t *testing.T) {
+			press("f")
+
+			t.Run("Then only that agent's events should be shown", func(t *testing.T) {
+				if m.eventsData.AgentFilter != "alice" || len(m.eventsData.Visible()) != 1 {
+					t.Fatalf("expected alice's event only, got filter %q and %+v", m.eventsData.AgentFilter, m.eventsData.Visible())
+				}
+			})
+
+			t.Run("And Enter should jump to the related lock", func(t *testing.T) {
+				next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+				m = next.(Model)
+				if m.activeTab != TabLocks || m.locksData.SelectedIndex != 0 {
+					t.Errorf("expected the Locks tab with lock-1 selected, got tab %v", m.activeTab)
+				}
+			})
+		})
+
+		t.Run("When I jump from a peer event", func(t *testing.T) {
+			m.activeTab = TabEvents
+			press("F")
+			m.eventsData.SelectedIndex = 2
+			next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			m = next.(Model)
+
+			t.Run("Then the peer should be selected", func(t *testing.T) {
+				if m.activeTab != TabPeers || m.peersData.SelectedIndex != 0 {
+					t.Errorf("expected the Peers tab with peer-b selected, got tab %v", m.activeTab)
+				}
+			})
+		})
+	})
+}
+
 // Scenario: Handle daemon error gracefully
 func TestFeature_TUIExecute_Scenario_HandleDaemonError(t *testing.T) {
 	t.Run("Given a TUI model with daemon returning errors", func(t *testing.T) {
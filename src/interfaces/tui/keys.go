@@ -8,6 +8,8 @@ type KeyMap struct {
 	Quit    key.Binding
 	Help    key.Binding
 	Refresh key.Binding
+	// 스냅샷
+	Snapshot key.Binding
 
 	// 탭 전환 (숫자키만 사용)
 	Tab1    key.Binding
@@ -15,6 +17,8 @@ type KeyMap struct {
 	Tab3    key.Binding
 	Tab4    key.Binding
 	Tab5    key.Binding
+	Tab6    key.Binding
+	Tab7    key.Binding
 	NextTab key.Binding
 	PrevTab key.Binding
 
@@ -37,6 +41,19 @@ type KeyMap struct {
 	// 컨텍스트 액션
 	Delete key.Binding
 
+	// 협상 액션 (Negotiations 탭)
+	VoteApprove  key.Binding
+	VoteReject   key.Binding
+	YieldRequest key.Binding
+	YieldHolder  key.Binding
+	Split        key.Binding
+	Escalate     key.Binding
+
+	// 이벤트 피드 액션 (Events 탭)
+	Pause       key.Binding
+	FilterAgent key.Binding
+	ClearFilter key.Binding
+
 	// 확인 대화상자
 	Yes key.Binding
 	No  key.Binding
@@ -58,6 +75,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("r"),
 			key.WithHelp("r", "새로고침"),
 		),
+		Snapshot: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "스냅샷 저장"),
+		),
 
 		// 탭 전환 (숫자키만)
 		Tab1: key.NewBinding(
@@ -80,6 +101,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("5"),
 			key.WithHelp("5", "Peers"),
 		),
+		Tab6: key.NewBinding(
+			key.WithKeys("6"),
+			key.WithHelp("6", "Negotiations"),
+		),
+		Tab7: key.NewBinding(
+			key.WithKeys("7"),
+			key.WithHelp("7", "Events"),
+		),
 		NextTab: key.NewBinding(
 			key.WithKeys("tab"),
 			key.WithHelp("Tab", "다음 탭"),
@@ -141,6 +170,46 @@ func DefaultKeyMap() KeyMap {
 			key.WithHelp("d", "삭제"),
 		),
 
+		// 협상 액션
+		VoteApprove: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "찬성 투표"),
+		),
+		VoteReject: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "반대 투표"),
+		),
+		YieldRequest: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "요청자 양보"),
+		),
+		YieldHolder: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", "보유자 양보"),
+		),
+		Split: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "영역 분할"),
+		),
+		Escalate: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "에스컬레이션"),
+		),
+
+		// 이벤트 피드 액션
+		Pause: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "일시정지/재개"),
+		),
+		FilterAgent: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "에이전트 필터"),
+		),
+		ClearFilter: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "필터 해제"),
+		),
+
 		// 확인 대화상자
 		Yes: key.NewBinding(
 			key.WithKeys("y", "Y"),
@@ -161,9 +230,11 @@ func (k KeyMap) ShortHelp() []key.Binding {
 // FullHelp는 전체 도움말을 반환합니다.
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Quit, k.Refresh, k.CommandMode, k.Help},
-		{k.Tab1, k.Tab2, k.Tab3, k.Tab4, k.Tab5},
+		{k.Quit, k.Refresh, k.Snapshot, k.CommandMode, k.Help},
+		{k.Tab1, k.Tab2, k.Tab3, k.Tab4, k.Tab5, k.Tab6, k.Tab7},
 		{k.ActionInit, k.ActionJoin, k.ActionLeave},
+		{k.VoteApprove, k.VoteReject, k.YieldRequest, k.YieldHolder, k.Split, k.Escalate},
+		{k.Pause, k.FilterAgent, k.ClearFilter},
 		{k.Up, k.Down, k.Enter, k.Escape},
 	}
 }
@@ -1,6 +1,13 @@
 package tui
 
-import "time"
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"agent-collab/src/application"
+	"agent-collab/src/interfaces/daemon"
+)
 
 // TickMsg는 주기적 갱신 메시지입니다.
 type TickMsg time.Time
@@ -11,6 +18,7 @@ type InitialDataMsg struct {
 	NodeID      string
 	PeerCount   int
 	SyncHealth  float64
+	Regions     []application.RegionStatus
 }
 
 // MetricsMsg는 메트릭 업데이트 메시지입니다.
@@ -34,12 +42,18 @@ type PeerInfo struct {
 	Status    string
 	Latency   int
 	Transport string
-	SyncPct   float64
+	// LossPct는 ping과 락 전송 실패로 측정한 손실률(%)입니다.
+	LossPct float64
+	// Score는 피어 품질 점수(0-1)이며, 측정 전이면 음수입니다.
+	Score   float64
+	Drops   int64
+	Retries int64
 }
 
 // LocksMsg는 락 목록 업데이트 메시지입니다.
 type LocksMsg struct {
-	Locks []LockInfo
+	Locks       []LockInfo
+	ActiveEdits []ActiveEditInfo
 }
 
 // LockInfo는 락 정보입니다.
@@ -48,7 +62,72 @@ type LockInfo struct {
 	Holder    string
 	Target    string
 	Intention string
-	TTL       int
+	// Mode는 "read" 또는 "write"입니다.
+	Mode string
+	TTL  int
+}
+
+// ActiveEditInfo는 다른 에이전트의 현재 편집 위치입니다.
+type ActiveEditInfo struct {
+	Agent     string
+	Region    string
+	ExpiresIn int
+}
+
+// NegotiationsMsg는 락 협상 목록 업데이트 메시지입니다.
+type NegotiationsMsg struct {
+	Sessions []NegotiationInfo
+}
+
+// NegotiationInfo는 진행 중이거나 사람의 결정을 기다리는 락 협상입니다.
+type NegotiationInfo struct {
+	ID          string
+	State       string
+	File        string
+	Requester   string
+	RequesterID string
+	Holder      string
+	HolderID    string
+	// 요청된 영역과 보유 중인 영역 (분할 지점은 요청 영역 안이어야 함)
+	StartLine  int
+	EndLine    int
+	HeldRange  string
+	Approvals  int
+	VotesCast  int
+	VotesNeed  int
+	ExpiresIn  int
+	Escalated  bool
+	Resolution string // 에스컬레이션 사유
+}
+
+// EventStreamMsg는 클러스터 이벤트 구독 결과 메시지입니다.
+// Events가 nil이면 구독하지 못했거나 연결이 끊긴 것입니다.
+type EventStreamMsg struct {
+	Events <-chan daemon.Event
+	Errors <-chan error
+}
+
+// ClusterEventMsg는 이벤트 스트림에서 받은 이벤트 메시지입니다.
+type ClusterEventMsg struct {
+	Event daemon.Event
+}
+
+// EventInfo는 Events 탭에 표시하는 클러스터 이벤트입니다.
+type EventInfo struct {
+	Time    time.Time
+	Type    string
+	Agent   string   // 표시용 대표 에이전트
+	Agents  []string // 이벤트와 관련된 에이전트 ID와 이름 (필터용)
+	Summary string
+	// 관련 락과 피어 (Enter로 이동)
+	LockID string
+	File   string
+	PeerID string
+}
+
+// Involves는 이벤트가 에이전트와 관련 있는지 확인합니다.
+func (e EventInfo) Involves(agent string) bool {
+	return slices.ContainsFunc(e.Agents, func(a string) bool { return strings.EqualFold(a, agent) })
 }
 
 // ContextMsg는 컨텍스트 상태 업데이트 메시지입니다.
@@ -57,6 +136,18 @@ type ContextMsg struct {
 	DatabaseSize    int64
 	SyncProgress    map[string]float64
 	RecentDeltas    []DeltaInfo
+	DeltaLog        DeltaLogInfo
+}
+
+// DeltaLogInfo는 델타 로그 압축 상태입니다.
+type DeltaLogInfo struct {
+	Pending    int
+	Compacted  int
+	Superseded int
+	Expired    int
+	Runs       int
+	Oldest     time.Time
+	LastRun    time.Time
 }
 
 // DeltaInfo는 Delta 정보입니다.
@@ -79,6 +170,7 @@ type TokensMsg struct {
 	CostMonth   float64
 	TokensWeek  int64
 	TokensMonth int64
+	ByModel     []ModelCost
 }
 
 // TokenBreakdown은 토큰 사용량 상세입니다.
@@ -89,6 +181,30 @@ type TokenBreakdown struct {
 	Cost     float64
 }
 
+// ModelCost는 모델별 오늘 사용량과 비용입니다.
+type ModelCost struct {
+	Provider     string
+	Model        string
+	InputTokens  int64
+	OutputTokens int64
+	Cost         float64
+}
+
+// modelCosts는 데몬 응답의 모델별 사용량을 변환합니다.
+func modelCosts(usage *daemon.TokenUsageResponse) []ModelCost {
+	costs := make([]ModelCost, 0, len(usage.ByModel))
+	for _, u := range usage.ByModel {
+		costs = append(costs, ModelCost{
+			Provider:     u.Provider,
+			Model:        u.Model,
+			InputTokens:  u.InputTokens,
+			OutputTokens: u.OutputTokens,
+			Cost:         u.Cost,
+		})
+	}
+	return costs
+}
+
 // CommandResultMsg는 명령 실행 결과 메시지입니다.
 type CommandResultMsg struct {
 	Result string
@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/sahilm/fuzzy"
 
+	"agent-collab/src/application"
 	"agent-collab/src/interfaces/daemon"
 	"agent-collab/src/interfaces/tui/mode"
 )
@@ -20,6 +21,8 @@ const (
 	TabLocks
 	TabTokens
 	TabPeers
+	TabNegotiations
+	TabEvents
 )
 
 // ConfirmAction은 확인 대화상자 액션 타입입니다.
@@ -29,6 +32,8 @@ const (
 	ConfirmNone ConfirmAction = iota
 	ConfirmLeave
 	ConfirmReleaseLock
+	ConfirmYieldRequester // 협상 요청자가 양보
+	ConfirmYieldHolder    // 기존 락 보유자가 양보
 )
 
 // Model은 TUI 메인 모델입니다.
@@ -81,18 +86,27 @@ type Model struct {
 	startTime   time.Time
 
 	// 뷰 데이터 (직접 저장)
-	clusterData ClusterData
-	contextData ContextData
-	locksData   LocksData
-	tokensData  TokensData
-	peersData   PeersData
+	clusterData      ClusterData
+	contextData      ContextData
+	locksData        LocksData
+	tokensData       TokensData
+	peersData        PeersData
+	negotiationsData NegotiationsData
+	eventsData       EventsData
 
 	// 뷰 크기
-	clusterView ViewSize
-	contextView ViewSize
-	locksView   ViewSize
-	tokensView  ViewSize
-	peersView   ViewSize
+	clusterView      ViewSize
+	contextView      ViewSize
+	locksView        ViewSize
+	tokensView       ViewSize
+	peersView        ViewSize
+	negotiationsView ViewSize
+	eventsView       ViewSize
+
+	// 클러스터 이벤트 스트림 (nil이면 미구독)
+	eventStream       <-chan daemon.Event
+	eventErrors       <-chan error
+	eventsSubscribing bool
 
 	// 메트릭
 	cpuUsage    float64
@@ -154,6 +168,7 @@ type ClusterData struct {
 	PendingSyncs   int
 	AvgLatency     int
 	MessagesPerSec float64
+	Regions        []application.RegionStatus
 }
 
 // ContextData는 컨텍스트 데이터입니다.
@@ -161,11 +176,13 @@ type ContextData struct {
 	TotalEmbeddings int
 	DatabaseSize    int64
 	SyncProgress    map[string]float64
+	DeltaLog        DeltaLogInfo
 }
 
 // LocksData는 락 데이터입니다.
 type LocksData struct {
 	Locks         []LockInfo
+	ActiveEdits   []ActiveEditInfo
 	SelectedIndex int
 }
 
@@ -180,6 +197,7 @@ type TokensData struct {
 	CostMonth   float64
 	TokensWeek  int64
 	TokensMonth int64
+	ByModel     []ModelCost
 }
 
 // PeersData는 피어 데이터입니다.
@@ -188,8 +206,151 @@ type PeersData struct {
 	SelectedIndex int
 }
 
+// NegotiationsData는 락 협상 데이터입니다.
+type NegotiationsData struct {
+	Sessions      []NegotiationInfo
+	SelectedIndex int
+}
+
+// SelectedSession은 선택된 협상을 반환합니다. 없으면 nil입니다.
+func (d NegotiationsData) SelectedSession() *NegotiationInfo {
+	if d.SelectedIndex < 0 || d.SelectedIndex >= len(d.Sessions) {
+		return nil
+	}
+	return &d.Sessions[d.SelectedIndex]
+}
+
+// maxEvents는 Events 탭이 보관하는 최대 이벤트 수입니다.
+const maxEvents = 500
+
+// EventsData는 클러스터 이벤트 피드 데이터입니다.
+type EventsData struct {
+	Events []EventInfo
+	// 일시정지 중 도착한 이벤트 (재개하면 피드에 추가)
+	Pending       []EventInfo
+	Paused        bool
+	AgentFilter   string
+	SelectedIndex int // Visible() 기준 인덱스
+}
+
+// Visible은 에이전트 필터를 통과한 이벤트를 반환합니다.
+func (d EventsData) Visible() []EventInfo {
+	if d.AgentFilter == "" {
+		return d.Events
+	}
+	var visible []EventInfo
+	for _, e := range d.Events {
+		if e.Involves(d.AgentFilter) {
+			visible = append(visible, e)
+		}
+	}
+	return visible
+}
+
+// SelectedEvent는 선택된 이벤트를 반환합니다. 없으면 nil입니다.
+func (d EventsData) SelectedEvent() *EventInfo {
+	visible := d.Visible()
+	if d.SelectedIndex < 0 || d.SelectedIndex >= len(visible) {
+		return nil
+	}
+	return &visible[d.SelectedIndex]
+}
+
+// Add는 이벤트를 피드에 추가합니다. 일시정지 중이면 보류하고, 마지막
+// 이벤트를 보고 있었으면 새 이벤트로 선택을 옮깁니다.
+func (d *EventsData) Add(e EventInfo) {
+	if d.Paused {
+		d.Pending = append(d.Pending, e)
+		if len(d.Pending) > maxEvents {
+			d.Pending = d.Pending[len(d.Pending)-maxEvents:]
+		}
+		return
+	}
+
+	following := d.SelectedIndex >= len(d.Visible())-1
+	d.Events = append(d.Events, e)
+	if len(d.Events) > maxEvents {
+		dropped := d.Events[:len(d.Events)-maxEvents]
+		d.Events = d.Events[len(d.Events)-maxEvents:]
+		for _, old := range dropped {
+			if d.AgentFilter == "" || old.Involves(d.AgentFilter) {
+				d.SelectedIndex--
+			}
+		}
+	}
+	if following {
+		d.SelectedIndex = len(d.Visible()) - 1
+	}
+	d.SelectedIndex = max(d.SelectedIndex, 0)
+}
+
+// seen은 같은 이벤트가 이미 피드에 있는지 확인합니다. 재연결하면 데몬이
+// 최근 이벤트를 다시 보내므로 마지막으로 받은 이벤트보다 오래된 것은 건너뜁니다.
+func (d EventsData) seen(e EventInfo) bool {
+	last := d.Events
+	if len(d.Pending) > 0 {
+		last = d.Pending
+	}
+	if len(last) == 0 {
+		return false
+	}
+	newest := last[len(last)-1]
+	return e.Time.Before(newest.Time) || (e.Time.Equal(newest.Time) && e.Type == newest.Type && e.Summary == newest.Summary)
+}
+
+// TogglePause는 피드를 멈추거나, 멈춘 동안 보류한 이벤트를 추가하며 재개합니다.
+func (d *EventsData) TogglePause() {
+	if !d.Paused {
+		d.Paused = true
+		return
+	}
+	d.Paused = false
+	pending := d.Pending
+	d.Pending = nil
+	for _, e := range pending {
+		d.Add(e)
+	}
+}
+
+// Agents는 피드에 등장한 에이전트를 처음 등장한 순서대로 반환합니다.
+func (d EventsData) Agents() []string {
+	var agents []string
+	seen := make(map[string]bool)
+	for _, e := range d.Events {
+		if e.Agent != "" && !seen[e.Agent] {
+			seen[e.Agent] = true
+			agents = append(agents, e.Agent)
+		}
+	}
+	return agents
+}
+
+// CycleAgentFilter는 필터를 다음 에이전트로 바꾸고, 마지막 에이전트 다음에는 해제합니다.
+func (d *EventsData) CycleAgentFilter() {
+	agents := d.Agents()
+	next := ""
+	if d.AgentFilter == "" {
+		if len(agents) > 0 {
+			next = agents[0]
+		}
+	} else {
+		for i, agent := range agents {
+			if agent == d.AgentFilter && i+1 < len(agents) {
+				next = agents[i+1]
+			}
+		}
+	}
+	d.SetAgentFilter(next)
+}
+
+// SetAgentFilter는 에이전트 필터를 설정하고 가장 최근 이벤트를 선택합니다.
+func (d *EventsData) SetAgentFilter(agent string) {
+	d.AgentFilter = agent
+	d.SelectedIndex = max(len(d.Visible())-1, 0)
+}
+
 // TabNames는 탭 이름 목록입니다.
-var TabNames = []string{"Cluster", "Context", "Locks", "Tokens", "Peers"}
+var TabNames = []string{"Cluster", "Context", "Locks", "Tokens", "Peers", "Negotiations", "Events"}
 
 // GetTabName은 탭 이름을 반환합니다.
 func (t Tab) String() string {
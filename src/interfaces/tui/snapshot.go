@@ -0,0 +1,228 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"agent-collab/src/interfaces/tui/mode"
+)
+
+// Snapshot은 렌더링에 쓰이는 Model 상태를 JSON으로 직렬화한 것입니다.
+// 버그 리포트에 첨부하고, 테스트에서 같은 화면을 재현하는 데 사용합니다.
+type Snapshot struct {
+	CapturedAt time.Time `json:"captured_at"`
+
+	// 크기와 상태
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	ActiveTab string `json:"active_tab"`
+	Mode      string `json:"mode"`
+
+	// 모드별 오버레이
+	CommandInput   string `json:"command_input,omitempty"`
+	InputPrompt    string `json:"input_prompt,omitempty"`
+	InputError     string `json:"input_error,omitempty"`
+	ConfirmPrompt  string `json:"confirm_prompt,omitempty"`
+	ConfirmYesText string `json:"confirm_yes_text,omitempty"`
+	ConfirmNoText  string `json:"confirm_no_text,omitempty"`
+
+	// 실행 결과
+	LastResult string `json:"last_result,omitempty"`
+	LastError  string `json:"last_error,omitempty"`
+	ShowResult bool   `json:"show_result"`
+
+	// 헤더
+	ProjectName string        `json:"project_name"`
+	NodeID      string        `json:"node_id"`
+	PeerCount   int           `json:"peer_count"`
+	SyncHealth  float64       `json:"sync_health"`
+	Uptime      time.Duration `json:"uptime"`
+
+	// 탭 데이터
+	SelectedIndex int              `json:"selected_index"`
+	Cluster       ClusterData      `json:"cluster"`
+	Context       ContextData      `json:"context"`
+	Locks         LocksData        `json:"locks"`
+	Tokens        TokensData       `json:"tokens"`
+	Peers         PeersData        `json:"peers"`
+	Negotiations  NegotiationsData `json:"negotiations"`
+	Events        EventsData       `json:"events"`
+
+	// 메트릭
+	CPUUsage    float64 `json:"cpu_usage"`
+	MemUsage    int64   `json:"mem_usage"`
+	NetUpload   int64   `json:"net_upload"`
+	NetDownload int64   `json:"net_download"`
+	TokensRate  int64   `json:"tokens_rate"`
+}
+
+// Snapshot은 현재 모델 상태를 캡처합니다.
+func (m Model) Snapshot() Snapshot {
+	s := Snapshot{
+		CapturedAt:     time.Now(),
+		Width:          m.width,
+		Height:         m.height,
+		ActiveTab:      m.activeTab.String(),
+		Mode:           m.mode.String(),
+		InputPrompt:    m.inputPrompt,
+		InputError:     m.inputError,
+		ConfirmPrompt:  m.confirmPrompt,
+		ConfirmYesText: m.confirmYesText,
+		ConfirmNoText:  m.confirmNoText,
+		LastResult:     m.lastResult,
+		ShowResult:     m.showResult,
+		ProjectName:    m.projectName,
+		NodeID:         m.nodeID,
+		PeerCount:      m.peerCount,
+		SyncHealth:     m.syncHealth,
+		Uptime:         m.uptime,
+		SelectedIndex:  m.selectedIndex,
+		Cluster:        m.clusterData,
+		Context:        m.contextData,
+		Locks:          m.locksData,
+		Tokens:         m.tokensData,
+		Peers:          m.peersData,
+		Negotiations:   m.negotiationsData,
+		Events:         m.eventsData,
+		CPUUsage:       m.cpuUsage,
+		MemUsage:       m.memUsage,
+		NetUpload:      m.netUpload,
+		NetDownload:    m.netDownload,
+		TokensRate:     m.tokensRate,
+	}
+	if m.mode == mode.Command || m.mode == mode.Input {
+		s.CommandInput = m.commandInput.Value()
+	}
+	if m.lastError != nil {
+		s.LastError = m.lastError.Error()
+	}
+	return s
+}
+
+// WriteSnapshot은 현재 모델 상태를 JSON 파일로 저장합니다.
+func (m Model) WriteSnapshot(path string) error {
+	data, err := json.MarshalIndent(m.Snapshot(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("스냅샷 직렬화 실패: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("스냅샷 저장 실패: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot은 JSON 파일에서 스냅샷을 읽습니다.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("스냅샷 읽기 실패: %w", err)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("스냅샷 파싱 실패: %w", err)
+	}
+	return &s, nil
+}
+
+// WithSnapshot은 스냅샷 상태로 모델을 복원합니다.
+// 복원된 모델은 데몬 데이터 없이도 캡처 당시와 같은 화면을 렌더링합니다.
+func WithSnapshot(s *Snapshot) Option {
+	return func(m *Model) {
+		m.width = s.Width
+		m.height = s.Height
+		m.ready = s.Width > 0 && s.Height > 0
+		m.updateViewSizes()
+		m.commandInput.Width = m.width - 10
+
+		m.activeTab = parseTab(s.ActiveTab)
+
+		m.inputPrompt = s.InputPrompt
+		m.confirmPrompt = s.ConfirmPrompt
+		m.confirmYesText = s.ConfirmYesText
+		m.confirmNoText = s.ConfirmNoText
+
+		m.mode = parseMode(s.Mode)
+		switch m.mode {
+		case mode.Command:
+			m.commandInput.SetValue(s.CommandInput)
+			m.commandInput.Focus()
+			m.UpdateFilteredHints()
+		case mode.Input:
+			m.commandInput.SetValue(s.CommandInput)
+			m.commandInput.Focus()
+		}
+		m.inputError = s.InputError
+
+		m.lastResult = s.LastResult
+		m.lastError = nil
+		if s.LastError != "" {
+			m.lastError = errors.New(s.LastError)
+		}
+		m.showResult = s.ShowResult
+
+		m.projectName = s.ProjectName
+		m.nodeID = s.NodeID
+		m.peerCount = s.PeerCount
+		m.syncHealth = s.SyncHealth
+		m.uptime = s.Uptime
+
+		m.selectedIndex = s.SelectedIndex
+		m.clusterData = s.Cluster
+		m.contextData = s.Context
+		m.locksData = s.Locks
+		m.tokensData = s.Tokens
+		m.peersData = s.Peers
+		m.negotiationsData = s.Negotiations
+		m.eventsData = s.Events
+
+		m.cpuUsage = s.CPUUsage
+		m.memUsage = s.MemUsage
+		m.netUpload = s.NetUpload
+		m.netDownload = s.NetDownload
+		m.tokensRate = s.TokensRate
+	}
+}
+
+// defaultSnapshotPath는 스냅샷 기본 파일 이름을 반환합니다.
+func defaultSnapshotPath(now time.Time) string {
+	return "agent-collab-tui-" + now.Format("20060102-150405") + ".json"
+}
+
+// parseTab은 탭 이름을 Tab으로 변환합니다.
+func parseTab(name string) Tab {
+	for i, n := range TabNames {
+		if n == name {
+			return Tab(i)
+		}
+	}
+	return TabCluster
+}
+
+// parseMode는 모드 이름을 Mode로 변환합니다.
+func parseMode(name string) mode.Mode {
+	for _, md := range []mode.Mode{mode.Normal, mode.Command, mode.Input, mode.Confirm, mode.Help} {
+		if md.String() == name {
+			return md
+		}
+	}
+	return mode.Normal
+}
+
+// saveSnapshot은 현재 상태를 파일로 저장하고 결과 메시지를 반환하는 명령입니다.
+// path가 비어 있으면 현재 디렉토리에 타임스탬프 이름으로 저장합니다.
+func (m Model) saveSnapshot(path string) tea.Cmd {
+	if path == "" {
+		path = defaultSnapshotPath(time.Now())
+	}
+	return func() tea.Msg {
+		if err := m.WriteSnapshot(path); err != nil {
+			return CommandResultMsg{Err: err}
+		}
+		return CommandResultMsg{Result: "스냅샷 저장: " + path}
+	}
+}
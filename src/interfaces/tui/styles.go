@@ -110,7 +110,7 @@ func StatusIcon(status string) string {
 	switch status {
 	case "online", "connected", "active":
 		return StatusOnlineStyle.Render("●")
-	case "offline", "disconnected":
+	case "offline", "disconnected", "escalated":
 		return StatusOfflineStyle.Render("○")
 	case "syncing", "connecting":
 		return StatusSyncingStyle.Render("◐")
@@ -2,8 +2,12 @@ package tui
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,6 +15,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"agent-collab/src/application"
+	"agent-collab/src/domain/lock"
 	"agent-collab/src/interfaces/daemon"
 	"agent-collab/src/interfaces/tui/mode"
 )
@@ -56,6 +61,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// 주기적으로 데이터 갱신 (metrics, peers, status)
 		cmds = append(cmds, m.tick(), m.fetchMetrics(), m.fetchPeers(), m.fetchStatus())
+		// 협상은 수십 초 안에 끝나므로 보고 있을 때는 매번 갱신
+		if m.activeTab == TabNegotiations {
+			cmds = append(cmds, m.fetchNegotiations())
+		}
+		// 이벤트 스트림이 없으면 (데몬이 늦게 시작된 경우 등) 다시 구독
+		if m.eventStream == nil && !m.eventsSubscribing {
+			m.eventsSubscribing = true
+			cmds = append(cmds, m.subscribeEvents())
+		}
+
+	case EventStreamMsg:
+		m.eventsSubscribing = false
+		m.eventStream, m.eventErrors = msg.Events, msg.Errors
+		if msg.Events != nil {
+			cmds = append(cmds, waitForEvent(msg.Events, msg.Errors))
+		}
+
+	case ClusterEventMsg:
+		if info, ok := eventInfo(msg.Event); ok && !m.eventsData.seen(info) {
+			m.eventsData.Add(info)
+		}
+		cmds = append(cmds, waitForEvent(m.eventStream, m.eventErrors))
 
 	case CommandResultMsg:
 		m.SetResult(msg.Result, msg.Err)
@@ -66,6 +93,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.nodeID = msg.NodeID
 		m.peerCount = msg.PeerCount
 		m.syncHealth = msg.SyncHealth
+		m.clusterData.Regions = msg.Regions
 		m.startTime = time.Now()
 
 	case MetricsMsg:
@@ -78,14 +106,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case PeersMsg:
 		m.peerCount = len(msg.Peers)
 		m.peersData.Peers = msg.Peers
+		m.clusterData.AvgLatency = averageLatency(msg.Peers)
 
 	case LocksMsg:
 		m.locksData.Locks = msg.Locks
+		m.locksData.ActiveEdits = msg.ActiveEdits
+
+	case NegotiationsMsg:
+		m.negotiationsData.Sessions = msg.Sessions
+		// 선택된 협상이 끝나 목록이 줄어도 범위 안에 유지
+		if m.negotiationsData.SelectedIndex >= len(msg.Sessions) {
+			m.negotiationsData.SelectedIndex = max(len(msg.Sessions)-1, 0)
+		}
 
 	case ContextMsg:
 		m.contextData.TotalEmbeddings = msg.TotalEmbeddings
 		m.contextData.DatabaseSize = msg.DatabaseSize
 		m.contextData.SyncProgress = msg.SyncProgress
+		m.contextData.DeltaLog = msg.DeltaLog
 
 	case TokensMsg:
 		m.tokensData.TodayUsed = msg.TodayUsed
@@ -97,6 +135,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.tokensData.CostMonth = msg.CostMonth
 		m.tokensData.TokensWeek = msg.TokensWeek
 		m.tokensData.TokensMonth = msg.TokensMonth
+		m.tokensData.ByModel = msg.ByModel
 	}
 
 	return m, tea.Batch(cmds...)
@@ -127,16 +166,25 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.activeTab = TabTokens
 	case key.Matches(msg, m.keys.Tab5):
 		m.activeTab = TabPeers
+	case key.Matches(msg, m.keys.Tab6):
+		m.activeTab = TabNegotiations
+		cmds = append(cmds, m.fetchNegotiations())
+	case key.Matches(msg, m.keys.Tab7):
+		m.activeTab = TabEvents
 
 	case key.Matches(msg, m.keys.NextTab):
-		m.activeTab = Tab((int(m.activeTab) + 1) % 5)
+		m.activeTab = Tab((int(m.activeTab) + 1) % len(TabNames))
 	case key.Matches(msg, m.keys.PrevTab):
-		m.activeTab = Tab((int(m.activeTab) + 4) % 5)
+		m.activeTab = Tab((int(m.activeTab) + len(TabNames) - 1) % len(TabNames))
 
 	// 새로고침
 	case key.Matches(msg, m.keys.Refresh):
 		cmds = append(cmds, m.fetchAllData())
 
+	// 스냅샷 저장
+	case key.Matches(msg, m.keys.Snapshot):
+		cmds = append(cmds, m.saveSnapshot(""))
+
 	// 액션 단축키
 	case key.Matches(msg, m.keys.ActionInit):
 		m.EnterInputMode("프로젝트 이름", func(name string) error {
@@ -154,6 +202,61 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.EnterConfirmMode("클러스터에서 탈퇴하시겠습니까?", ConfirmLeave, "")
 		return m, nil
 
+	// 협상 액션 (Negotiations 탭)
+	case m.activeTab == TabNegotiations && key.Matches(msg, m.keys.VoteApprove, m.keys.VoteReject):
+		if session := m.negotiationsData.SelectedSession(); session != nil {
+			approve := key.Matches(msg, m.keys.VoteApprove)
+			if err := m.executeVoteNegotiation(*session, approve); err != nil {
+				m.SetResult("", err)
+			}
+			cmds = append(cmds, m.fetchNegotiations())
+		}
+
+	case m.activeTab == TabNegotiations && key.Matches(msg, m.keys.YieldRequest):
+		if session := m.negotiationsData.SelectedSession(); session != nil {
+			m.EnterConfirmMode("요청자 '"+session.Requester+"'가 양보하고 보유자가 락을 유지합니까?", ConfirmYieldRequester, session.ID)
+			return m, nil
+		}
+
+	case m.activeTab == TabNegotiations && key.Matches(msg, m.keys.YieldHolder):
+		if session := m.negotiationsData.SelectedSession(); session != nil {
+			m.EnterConfirmMode("보유자 '"+session.Holder+"'가 양보하고 요청자에게 락을 넘깁니까?", ConfirmYieldHolder, session.ID)
+			return m, nil
+		}
+
+	case m.activeTab == TabNegotiations && key.Matches(msg, m.keys.Split):
+		if session := m.negotiationsData.SelectedSession(); session != nil {
+			id := session.ID
+			prompt := fmt.Sprintf("분할 라인 (%d-%d, 요청자가 이 라인부터 가짐)", session.StartLine+1, session.EndLine-1)
+			m.EnterInputMode(prompt, func(value string) error {
+				line, err := strconv.Atoi(strings.TrimSpace(value))
+				if err != nil {
+					return errors.New("라인 번호를 입력해주세요")
+				}
+				return m.executeSplitNegotiation(id, line)
+			})
+			return m, nil
+		}
+
+	case m.activeTab == TabNegotiations && key.Matches(msg, m.keys.Escalate):
+		if session := m.negotiationsData.SelectedSession(); session != nil {
+			id := session.ID
+			m.EnterInputMode("에스컬레이션 사유", func(reason string) error {
+				return m.executeEscalateNegotiation(id, reason)
+			})
+			return m, nil
+		}
+
+	// 이벤트 피드 액션 (Events 탭)
+	case m.activeTab == TabEvents && key.Matches(msg, m.keys.Pause):
+		m.eventsData.TogglePause()
+
+	case m.activeTab == TabEvents && key.Matches(msg, m.keys.FilterAgent):
+		m.eventsData.CycleAgentFilter()
+
+	case m.activeTab == TabEvents && key.Matches(msg, m.keys.ClearFilter):
+		m.eventsData.SetAgentFilter("")
+
 	// 도움말
 	case key.Matches(msg, m.keys.Help):
 		m.EnterHelpMode()
@@ -270,6 +373,11 @@ func (m Model) updateConfirmMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if err := m.executeReleaseLock(targetID); err != nil {
 				m.SetResult("", err)
 			}
+		case ConfirmYieldRequester, ConfirmYieldHolder:
+			if err := m.executeYieldNegotiation(targetID, actionType == ConfirmYieldHolder); err != nil {
+				m.SetResult("", err)
+			}
+			return m, m.fetchNegotiations()
 		}
 		return m, nil
 
@@ -302,6 +410,14 @@ func (m *Model) navigateUp() {
 		if m.peersData.SelectedIndex > 0 {
 			m.peersData.SelectedIndex--
 		}
+	case TabNegotiations:
+		if m.negotiationsData.SelectedIndex > 0 {
+			m.negotiationsData.SelectedIndex--
+		}
+	case TabEvents:
+		if m.eventsData.SelectedIndex > 0 {
+			m.eventsData.SelectedIndex--
+		}
 	}
 }
 
@@ -315,6 +431,14 @@ func (m *Model) navigateDown() {
 		if m.peersData.SelectedIndex < len(m.peersData.Peers)-1 {
 			m.peersData.SelectedIndex++
 		}
+	case TabNegotiations:
+		if m.negotiationsData.SelectedIndex < len(m.negotiationsData.Sessions)-1 {
+			m.negotiationsData.SelectedIndex++
+		}
+	case TabEvents:
+		if m.eventsData.SelectedIndex < len(m.eventsData.Visible())-1 {
+			m.eventsData.SelectedIndex++
+		}
 	}
 }
 
@@ -330,10 +454,44 @@ func (m *Model) executeSelectedAction() tea.Cmd {
 			peer := m.peersData.Peers[m.peersData.SelectedIndex]
 			m.SetResult("Peer: "+peer.Name+" ("+peer.ID+")", nil)
 		}
+	case TabNegotiations:
+		if session := m.negotiationsData.SelectedSession(); session != nil {
+			m.SetResult("Negotiation: "+session.ID+" ("+session.Requester+" → "+session.File+")", nil)
+		}
+	case TabEvents:
+		if event := m.eventsData.SelectedEvent(); event != nil {
+			if err := m.jumpToEvent(*event); err != nil {
+				m.SetResult("", err)
+			}
+		}
 	}
 	return nil
 }
 
+// jumpToEvent는 이벤트와 관련된 락이나 피어를 해당 탭에서 선택합니다.
+func (m *Model) jumpToEvent(event EventInfo) error {
+	if event.LockID != "" || event.File != "" {
+		for i, l := range m.locksData.Locks {
+			if (event.LockID != "" && l.ID == event.LockID) || (event.LockID == "" && l.Target == event.File) {
+				m.activeTab = TabLocks
+				m.locksData.SelectedIndex = i
+				return nil
+			}
+		}
+	}
+	for i, p := range m.peersData.Peers {
+		if (event.PeerID != "" && p.ID == event.PeerID) || (event.Agent != "" && (p.Name == event.Agent || p.ID == event.Agent)) {
+			m.activeTab = TabPeers
+			m.peersData.SelectedIndex = i
+			return nil
+		}
+	}
+	if event.LockID != "" {
+		return errors.New("락 '" + event.LockID + "'이 이미 해제되었습니다")
+	}
+	return errors.New("이벤트와 관련된 락이나 피어가 없습니다")
+}
+
 // 명령 실행
 
 func (m *Model) executeCommand(input string) tea.Cmd {
@@ -406,8 +564,15 @@ func (m *Model) executeCommand(input string) tea.Cmd {
 		case "config":
 			result = "설정 표시"
 
+		case "snapshot":
+			path := ""
+			if len(args) >= 1 {
+				path = args[0]
+			}
+			return m.saveSnapshot(path)()
+
 		case "help":
-			result = "도움말: q(종료), i(init), j(join), l(leave), 1-5(탭 전환), :(명령)"
+			result = "도움말: q(종료), i(init), j(join), l(leave), s(스냅샷), 1-7(탭 전환), :(명령)"
 
 		default:
 			result = "알 수 없는 명령: " + cmd
@@ -578,6 +743,85 @@ func (m *Model) executeReleaseLockWithClient(lockID string) error {
 	return nil
 }
 
+func (m *Model) executeVoteNegotiation(session NegotiationInfo, approve bool) error {
+	if session.Escalated {
+		return errors.New("에스컬레이션된 협상은 투표 대신 양보(y/Y)나 분할(p)로 결정합니다")
+	}
+	client := m.getClient()
+	if err := client.VoteNegotiation(session.ID, approve, "TUI 운영자 투표"); err != nil {
+		return err
+	}
+	vote := "반대"
+	if approve {
+		vote = "찬성"
+	}
+	m.SetResult("협상 '"+session.ID+"'에 "+vote+" 투표", nil)
+	return nil
+}
+
+// executeYieldNegotiation은 선택한 쪽이 양보하도록 협상을 결정합니다.
+// holderYields가 false면 요청자가 양보합니다.
+func (m *Model) executeYieldNegotiation(sessionID string, holderYields bool) error {
+	var session *NegotiationInfo
+	for i := range m.negotiationsData.Sessions {
+		if m.negotiationsData.Sessions[i].ID == sessionID {
+			session = &m.negotiationsData.Sessions[i]
+		}
+	}
+	if session == nil {
+		return errors.New("협상 '" + sessionID + "'이 이미 끝났습니다")
+	}
+
+	yielder, yielderName := session.RequesterID, session.Requester
+	if holderYields {
+		yielder, yielderName = session.HolderID, session.Holder
+	}
+	result, err := m.getClient().ProposeNegotiation(daemon.NegotiationProposeRequest{
+		SessionID: sessionID,
+		Type:      lock.ProposalYield,
+		YielderID: yielder,
+	})
+	if err != nil {
+		return err
+	}
+	m.SetResult(negotiationOutcome(result, yielderName+" 양보"), nil)
+	return nil
+}
+
+func (m *Model) executeSplitNegotiation(sessionID string, line int) error {
+	result, err := m.getClient().ProposeNegotiation(daemon.NegotiationProposeRequest{
+		SessionID:  sessionID,
+		Type:       lock.ProposalSplit,
+		SplitPoint: line,
+	})
+	if err != nil {
+		return err
+	}
+	m.SetResult(negotiationOutcome(result, fmt.Sprintf("라인 %d에서 분할", line)), nil)
+	return nil
+}
+
+func (m *Model) executeEscalateNegotiation(sessionID, reason string) error {
+	result, err := m.getClient().ProposeNegotiation(daemon.NegotiationProposeRequest{
+		SessionID: sessionID,
+		Type:      lock.ProposalEscalate,
+		Reason:    reason,
+	})
+	if err != nil {
+		return err
+	}
+	m.SetResult(negotiationOutcome(result, "에스컬레이션"), nil)
+	return nil
+}
+
+// negotiationOutcome은 협상 결과 메시지를 만듭니다.
+func negotiationOutcome(result *lock.NegotiationResult, action string) string {
+	if result == nil || result.Message == "" {
+		return "협상 " + action + " 완료"
+	}
+	return "협상 " + action + ": " + result.Message
+}
+
 // fetchTokenUsageWithClient fetches token usage from daemon.
 func (m *Model) fetchTokenUsageWithClient() (*TokensMsg, error) {
 	client := m.getClient()
@@ -593,6 +837,7 @@ func (m *Model) fetchTokenUsageWithClient() (*TokensMsg, error) {
 		CostMonth:   usage.CostMonth,
 		TokensWeek:  int64(usage.TokensWeek),
 		TokensMonth: int64(usage.TokensMonth),
+		ByModel:     modelCosts(usage),
 	}, nil
 }
 
@@ -620,6 +865,8 @@ func (m *Model) updateViewSizes() {
 	m.locksView = ViewSize{Width: contentWidth, Height: contentHeight}
 	m.tokensView = ViewSize{Width: contentWidth, Height: contentHeight}
 	m.peersView = ViewSize{Width: contentWidth, Height: contentHeight}
+	m.negotiationsView = ViewSize{Width: contentWidth, Height: contentHeight}
+	m.eventsView = ViewSize{Width: contentWidth, Height: contentHeight}
 }
 
 // fetchAllData는 모든 데이터를 가져옵니다.
@@ -628,6 +875,7 @@ func (m Model) fetchAllData() tea.Cmd {
 		m.fetchMetrics(),
 		m.fetchPeers(),
 		m.fetchLocks(),
+		m.fetchNegotiations(),
 		m.fetchContext(),
 		m.fetchTokens(),
 	)
@@ -696,6 +944,7 @@ func (m Model) fetchStatus() tea.Cmd {
 			NodeID:      status.NodeID,
 			PeerCount:   status.PeerCount,
 			SyncHealth:  100,
+			Regions:     status.Regions,
 		}
 	}
 }
@@ -715,9 +964,9 @@ func (m Model) fetchPeers() tea.Cmd {
 
 		peers := make([]PeerInfo, len(resp.Peers))
 		for i, p := range resp.Peers {
-			addr := ""
-			if len(p.Addresses) > 0 {
-				addr = p.Addresses[0]
+			transport := p.Transport
+			if transport == "" {
+				transport = "-"
 			}
 			name := p.ID
 			if len(p.ID) > 12 {
@@ -728,7 +977,16 @@ func (m Model) fetchPeers() tea.Cmd {
 				Name:      name,
 				Status:    "connected",
 				Latency:   int(p.Latency),
-				Transport: addr,
+				Transport: transport,
+				Score:     -1,
+			}
+			if p.Quality != nil {
+				peers[i].LossPct = p.Quality.PacketLoss * 100
+				peers[i].Score = p.Quality.Score
+			}
+			if p.Delivery != nil {
+				peers[i].Drops = p.Delivery.Dropped
+				peers[i].Retries = p.Delivery.Retries
 			}
 		}
 
@@ -736,6 +994,21 @@ func (m Model) fetchPeers() tea.Cmd {
 	}
 }
 
+// averageLatency는 지연 시간이 측정된 피어들의 평균 지연(ms)을 반환합니다.
+func averageLatency(peers []PeerInfo) int {
+	total, count := 0, 0
+	for _, p := range peers {
+		if p.Latency > 0 {
+			total += p.Latency
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / count
+}
+
 // fetchLocks는 락 목록을 가져옵니다.
 func (m Model) fetchLocks() tea.Cmd {
 	return func() tea.Msg {
@@ -760,12 +1033,187 @@ func (m Model) fetchLocks() tea.Cmd {
 				Holder:    l.HolderName,
 				Target:    target,
 				Intention: l.Intention,
+				Mode:      string(l.EffectiveMode()),
 				TTL:       int(l.ExpiresAt.Sub(l.AcquiredAt).Seconds()),
 			}
 		}
 
-		return LocksMsg{Locks: locks}
+		// 현재 편집 위치 (presence)
+		var edits []ActiveEditInfo
+		if presence, err := client.ActiveEdits(""); err == nil {
+			for _, e := range presence.Edits {
+				edits = append(edits, ActiveEditInfo{
+					Agent:     e.AgentName,
+					Region:    e.Region(),
+					ExpiresIn: int(time.Until(e.ExpiresAt).Seconds()),
+				})
+			}
+		}
+
+		return LocksMsg{Locks: locks, ActiveEdits: edits}
+	}
+}
+
+// fetchNegotiations는 진행 중이거나 에스컬레이션된 락 협상을 가져옵니다.
+func (m Model) fetchNegotiations() tea.Cmd {
+	return func() tea.Msg {
+		client := m.getClient()
+		if !client.IsRunning() {
+			return NegotiationsMsg{Sessions: []NegotiationInfo{}}
+		}
+
+		sessions, err := client.ListNegotiations()
+		if err != nil {
+			return NegotiationsMsg{Sessions: []NegotiationInfo{}}
+		}
+		return NegotiationsMsg{Sessions: negotiationInfos(sessions, time.Now())}
+	}
+}
+
+// negotiationInfos는 협상 세션을 화면 표시용으로 변환합니다.
+func negotiationInfos(sessions []*lock.NegotiationSession, now time.Time) []NegotiationInfo {
+	infos := make([]NegotiationInfo, 0, len(sessions))
+	for _, s := range sessions {
+		if s.RequestedLock == nil || s.ConflictingLock == nil {
+			continue
+		}
+		info := NegotiationInfo{
+			ID:          s.ID,
+			State:       string(s.State),
+			Requester:   s.RequestedLock.HolderName,
+			RequesterID: s.RequestedLock.HolderID,
+			Holder:      s.ConflictingLock.HolderName,
+			HolderID:    s.ConflictingLock.HolderID,
+			VotesCast:   len(s.Votes),
+			VotesNeed:   s.RequiredVotes,
+			Escalated:   s.State == lock.StateEscalated,
+		}
+		if t := s.RequestedLock.Target; t != nil {
+			info.File = t.FilePath
+			info.StartLine, info.EndLine = t.StartLine, t.EndLine
+		}
+		if t := s.ConflictingLock.Target; t != nil {
+			info.HeldRange = fmt.Sprintf("%d-%d", t.StartLine, t.EndLine)
+		}
+		for _, v := range s.Votes {
+			if v.Approve {
+				info.Approvals++
+			}
+		}
+		if !info.Escalated {
+			info.ExpiresIn = max(int(s.ExpiresAt.Sub(now).Seconds()), 0)
+		}
+		if s.Resolution != nil {
+			info.Resolution = s.Resolution.Message
+		}
+		infos = append(infos, info)
 	}
+	return infos
+}
+
+// eventBacklog는 구독할 때 데몬에서 다시 받는 최근 이벤트 수입니다.
+const eventBacklog = 100
+
+// subscribeEvents는 최근 이벤트와 함께 클러스터 이벤트 스트림을 구독합니다.
+func (m Model) subscribeEvents() tea.Cmd {
+	return func() tea.Msg {
+		client := m.getClient()
+		if !client.IsRunning() {
+			return EventStreamMsg{}
+		}
+		client.SetEventReplay("", eventBacklog)
+		events, errs, err := client.SubscribeEvents(context.Background())
+		if err != nil {
+			return EventStreamMsg{}
+		}
+		return EventStreamMsg{Events: events, Errors: errs}
+	}
+}
+
+// waitForEvent는 이벤트 스트림의 다음 이벤트를 기다립니다. 연결이 끊기면
+// 빈 EventStreamMsg를 보내 다음 틱에 다시 구독하게 합니다.
+func waitForEvent(events <-chan daemon.Event, errs <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case event := <-events:
+			return ClusterEventMsg{Event: event}
+		case <-errs:
+			return EventStreamMsg{}
+		}
+	}
+}
+
+// hiddenEventTypes는 피드에 표시하지 않는 스트림 제어용 이벤트입니다.
+var hiddenEventTypes = map[daemon.EventType]bool{
+	daemon.EventWatchOptions:  true,
+	daemon.EventReplayDone:    true,
+	daemon.EventStatusUpdated: true,
+	daemon.EventDaemonReady:   true,
+}
+
+// eventInfo는 데몬 이벤트를 피드 표시용으로 변환합니다. 표시하지 않는
+// 이벤트면 false를 반환합니다.
+func eventInfo(e daemon.Event) (EventInfo, bool) {
+	if hiddenEventTypes[e.Type] {
+		return EventInfo{}, false
+	}
+
+	var fields map[string]any
+	_ = json.Unmarshal(e.Data, &fields)
+	str := func(key string) string {
+		v, _ := fields[key].(string)
+		return v
+	}
+
+	info := EventInfo{
+		Time:   e.Timestamp,
+		Type:   string(e.Type),
+		LockID: str("lock_id"),
+		File:   str("file_path"),
+		PeerID: str("peer_id"),
+	}
+	// 알림의 대상은 "파일:라인" 형식의 문자열
+	if target := str("target"); target != "" && info.File == "" {
+		info.File, _, _ = strings.Cut(target, ":")
+	}
+	for _, key := range []string{"holder_name", "name", "agent_id", "requester_id", "holder_id"} {
+		if v := str(key); v != "" && !info.Involves(v) {
+			info.Agents = append(info.Agents, v)
+		}
+	}
+	if len(info.Agents) > 0 {
+		info.Agent = info.Agents[0]
+	}
+
+	switch e.Type {
+	case daemon.EventLockConflict:
+		info.Agent = str("requester_id")
+		info.Summary = fmt.Sprintf("%s: %s 요청, %s 보유", info.File, str("requester_id"), str("holder_id"))
+	case daemon.EventLockAcquired, daemon.EventLockReleased:
+		info.Summary = info.File
+		if intention := str("intention"); intention != "" {
+			info.Summary += " - " + intention
+		}
+	case daemon.EventContextUpdated, daemon.EventContextSynced:
+		info.Summary = info.File + " 공유"
+	case daemon.EventAgentJoined, daemon.EventAgentLeft:
+		info.Summary = strings.TrimSpace(str("name") + " " + str("provider"))
+	case daemon.EventPeerConnected, daemon.EventPeerDisconnected, daemon.EventPeerBanned, daemon.EventPeerUnbanned:
+		info.Summary = strings.TrimSpace(info.PeerID + " " + str("addr") + str("reason"))
+	case daemon.EventDaemonRecovered:
+		var data daemon.RecoveredData
+		_ = json.Unmarshal(e.Data, &data)
+		info.Summary = fmt.Sprintf("재시작 #%d (%s), 락 %d개 복원", data.Restart, data.Exit, data.RestoredLocks)
+	default:
+		info.Summary = str("target")
+		for _, key := range []string{"reason", "message", "error"} {
+			if v := str(key); v != "" {
+				info.Summary = strings.TrimSpace(info.Summary + " " + v)
+				break
+			}
+		}
+	}
+	return info, true
 }
 
 // fetchContext는 컨텍스트 상태를 가져옵니다.
@@ -781,10 +1229,23 @@ func (m Model) fetchContext() tea.Cmd {
 			return ContextMsg{SyncProgress: map[string]float64{}}
 		}
 
+		deltaLog := DeltaLogInfo{
+			Pending:   stats.PendingDeltas,
+			Compacted: stats.CompactedDeltas,
+		}
+		if c := stats.Compaction; c != nil {
+			deltaLog.Superseded = c.SupersededDeltas
+			deltaLog.Expired = c.ExpiredDeltas
+			deltaLog.Runs = c.Runs
+			deltaLog.Oldest = c.OldestDelta
+			deltaLog.LastRun = c.LastRunAt
+		}
+
 		return ContextMsg{
 			TotalEmbeddings: int(stats.TotalEmbeddings),
 			DatabaseSize:    0, // Not provided by API yet
 			SyncProgress:    map[string]float64{},
+			DeltaLog:        deltaLog,
 		}
 	}
 }
@@ -820,6 +1281,7 @@ func (m Model) fetchTokens() tea.Cmd {
 			CostMonth:   usage.CostMonth,
 			TokensWeek:  usage.TokensWeek,
 			TokensMonth: usage.TokensMonth,
+			ByModel:     modelCosts(usage),
 		}
 	}
 }
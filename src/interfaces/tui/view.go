@@ -316,7 +316,7 @@ func (m Model) renderHelpOverlayFullscreen(base string) string {
 			keyStyle.Render("r"), descStyle.Render("새로고침"),
 			keyStyle.Render("?"), descStyle.Render("도움말")))
 		lines = append(lines, fmt.Sprintf("%s %s  %s %s",
-			keyStyle.Render("1-5"), descStyle.Render("탭"),
+			keyStyle.Render("1-7"), descStyle.Render("탭"),
 			keyStyle.Render("Tab"), descStyle.Render("탭이동")))
 		lines = append(lines, fmt.Sprintf("%s %s  %s %s  %s %s",
 			keyStyle.Render("i"), descStyle.Render("Init"),
@@ -333,12 +333,13 @@ func (m Model) renderHelpOverlayFullscreen(base string) string {
 		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("q"), descStyle.Render("종료")))
 		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render(":"), descStyle.Render("명령 팔레트 열기")))
 		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("r"), descStyle.Render("데이터 새로고침")))
+		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("s"), descStyle.Render("화면 상태 스냅샷 저장 (버그 리포트용)")))
 		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("?"), descStyle.Render("도움말 표시")))
 		lines = append(lines, "")
 
 		// 탭 전환
 		lines = append(lines, sectionStyle.Render("탭 전환"))
-		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("1-5"), descStyle.Render("탭 선택 (Cluster/Context/Locks/Tokens/Peers)")))
+		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("1-7"), descStyle.Render("탭 선택 (Cluster/Context/Locks/Tokens/Peers/Negotiations/Events)")))
 		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("Tab"), descStyle.Render("다음 탭")))
 		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("S-Tab"), descStyle.Render("이전 탭")))
 		lines = append(lines, "")
@@ -356,6 +357,21 @@ func (m Model) renderHelpOverlayFullscreen(base string) string {
 		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("↓/j"), descStyle.Render("아래로 이동")))
 		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("Enter"), descStyle.Render("선택/실행")))
 		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("d"), descStyle.Render("삭제 (Locks 탭에서 락 해제)")))
+		lines = append(lines, "")
+
+		// 락 협상
+		lines = append(lines, sectionStyle.Render("락 협상 (Negotiations 탭)"))
+		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("a/x"), descStyle.Render("찬성/반대 투표")))
+		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("y/Y"), descStyle.Render("요청자/보유자 양보로 결정")))
+		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("p"), descStyle.Render("라인 기준 영역 분할")))
+		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("e"), descStyle.Render("사람에게 에스컬레이션")))
+		lines = append(lines, "")
+
+		// 이벤트 피드
+		lines = append(lines, sectionStyle.Render("이벤트 피드 (Events 탭)"))
+		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("p"), descStyle.Render("일시정지/재개")))
+		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("f/F"), descStyle.Render("에이전트별 필터/해제")))
+		lines = append(lines, fmt.Sprintf("  %s %s", keyStyle.Render("Enter"), descStyle.Render("관련 락/피어로 이동")))
 	}
 
 	lines = append(lines, "")
@@ -515,6 +531,10 @@ func (m Model) renderContent() string {
 		content = m.renderTokensView()
 	case TabPeers:
 		content = m.renderPeersView()
+	case TabNegotiations:
+		content = m.renderNegotiationsView()
+	case TabEvents:
+		content = m.renderEventsView()
 	}
 
 	return style.Render(content)
@@ -617,9 +637,29 @@ func (m Model) renderClusterView() string {
 	lines = append(lines, fmt.Sprintf("  Total Peers      : %d", m.peerCount))
 	lines = append(lines, fmt.Sprintf("  Active Locks     : %d", len(m.locksData.Locks)))
 	lines = append(lines, fmt.Sprintf("  Pending Syncs    : %d", 0))
-	lines = append(lines, fmt.Sprintf("  Avg Latency      : %dms", 42))
+	lines = append(lines, fmt.Sprintf("  Avg Latency      : %dms", m.clusterData.AvgLatency))
 	lines = append(lines, fmt.Sprintf("  Messages/sec     : %.1f", 12.4))
 
+	// 리전별 피어 분포
+	if len(m.clusterData.Regions) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, BoxTitleStyle.Render("Regions"))
+		lines = append(lines, TableHeaderStyle.Render(
+			fmt.Sprintf("  %-20s %-6s %-9s %s", "REGION", "PEERS", "AVG RTT", "GATEWAY")))
+		for _, r := range m.clusterData.Regions {
+			name := r.Region
+			if r.Local {
+				name += " (local)"
+			}
+			gateway := r.GatewayPeer
+			if len(gateway) > 16 {
+				gateway = gateway[:6] + "…" + gateway[len(gateway)-6:]
+			}
+			lines = append(lines, fmt.Sprintf("  %-20s %-6d %-9s %s",
+				name, r.PeerCount, r.AverageRTT.Round(time.Millisecond), gateway))
+		}
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -635,6 +675,14 @@ func (m Model) renderContextView() string {
 	lines = append(lines, "└─ Last Updated     : 2 seconds ago")
 	lines = append(lines, "")
 
+	deltaLog := m.contextData.DeltaLog
+	lines = append(lines, "Delta Log")
+	lines = append(lines, fmt.Sprintf("├─ Pending Deltas   : %d", deltaLog.Pending))
+	lines = append(lines, fmt.Sprintf("├─ Oldest Delta     : %s", formatCompactionTime(deltaLog.Oldest)))
+	lines = append(lines, fmt.Sprintf("├─ Checkpointed     : %d (대체 %d, 만료 %d)", deltaLog.Compacted, deltaLog.Superseded, deltaLog.Expired))
+	lines = append(lines, fmt.Sprintf("└─ Last Compaction  : %s (%d회)", formatCompactionTime(deltaLog.LastRun), deltaLog.Runs))
+	lines = append(lines, "")
+
 	lines = append(lines, BoxTitleStyle.Render("Sync Progress"))
 	for name, pct := range m.contextData.SyncProgress {
 		status := "synced"
@@ -647,6 +695,14 @@ func (m Model) renderContextView() string {
 	return strings.Join(lines, "\n")
 }
 
+// formatCompactionTime은 압축 시각을 경과 시간으로 표시합니다.
+func formatCompactionTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return formatDurationReal(time.Since(t)) + " ago"
+}
+
 func (m Model) renderLocksView() string {
 	var lines []string
 
@@ -657,7 +713,7 @@ func (m Model) renderLocksView() string {
 
 	// 테이블 헤더
 	lines = append(lines, TableHeaderStyle.Render(
-		fmt.Sprintf("  %-10s %-30s %-15s %s", "HOLDER", "TARGET", "INTENTION", "TTL")))
+		fmt.Sprintf("  %-10s %-30s %-5s %-15s %s", "HOLDER", "TARGET", "MODE", "INTENTION", "TTL")))
 	lines = append(lines, strings.Repeat("─", 70))
 
 	// 락 목록
@@ -670,8 +726,8 @@ func (m Model) renderLocksView() string {
 			style = TableSelectedStyle
 		}
 
-		line := fmt.Sprintf("%s%s %-10s %-30s %-15s %ds",
-			prefix, StatusIcon("active"), l.Holder, l.Target, l.Intention, l.TTL)
+		line := fmt.Sprintf("%s%s %-10s %-30s %-5s %-15s %ds",
+			prefix, StatusIcon("active"), l.Holder, l.Target, l.Mode, l.Intention, l.TTL)
 		lines = append(lines, style.Render(line))
 	}
 
@@ -679,6 +735,129 @@ func (m Model) renderLocksView() string {
 		lines = append(lines, MutedStyle.Render("  활성 락이 없습니다."))
 	}
 
+	// 현재 편집 중인 위치 (락 아님, TTL 후 사라짐)
+	if len(m.locksData.ActiveEdits) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, BoxTitleStyle.Render(fmt.Sprintf("Active Edits: %d", len(m.locksData.ActiveEdits))))
+		for _, e := range m.locksData.ActiveEdits {
+			lines = append(lines, fmt.Sprintf("  %s %-10s %-30s %ds",
+				StatusIcon("syncing"), e.Agent, e.Region, e.ExpiresIn))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderNegotiationsView() string {
+	var lines []string
+
+	escalated := 0
+	for _, s := range m.negotiationsData.Sessions {
+		if s.Escalated {
+			escalated++
+		}
+	}
+
+	lines = append(lines, BoldStyle.Render("Lock Negotiations"))
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf("Open: %d  Escalated: %d  (a/x 투표, y/Y 양보, p 분할, e 에스컬레이션)",
+		len(m.negotiationsData.Sessions), escalated))
+	lines = append(lines, "")
+
+	// 테이블 헤더
+	lines = append(lines, TableHeaderStyle.Render(
+		fmt.Sprintf("    %-11s %-28s %-16s %-16s %-7s %s", "STATE", "FILE", "REQUESTER", "HOLDER", "VOTES", "EXPIRES")))
+	lines = append(lines, strings.Repeat("─", 90))
+
+	for i, s := range m.negotiationsData.Sessions {
+		prefix := "  "
+		style := lipgloss.NewStyle()
+		if i == m.negotiationsData.SelectedIndex {
+			prefix = "▸ "
+			style = TableSelectedStyle
+		}
+
+		icon := StatusIcon("syncing")
+		expires := fmt.Sprintf("%ds", s.ExpiresIn)
+		if s.Escalated {
+			icon = StatusIcon("escalated")
+			expires = "결정 필요"
+		}
+		requester := fmt.Sprintf("%s %d-%d", s.Requester, s.StartLine, s.EndLine)
+		holder := s.Holder + " " + s.HeldRange
+		votes := fmt.Sprintf("%d/%d", s.Approvals, s.VotesNeed)
+
+		line := fmt.Sprintf("%s%s %-11s %-28s %-16s %-16s %-7s %s",
+			prefix, icon, s.State, s.File, requester, holder, votes, expires)
+		lines = append(lines, style.Render(line))
+	}
+
+	if len(m.negotiationsData.Sessions) == 0 {
+		lines = append(lines, MutedStyle.Render("  진행 중인 협상이 없습니다."))
+	}
+
+	// 선택된 에스컬레이션의 사유
+	if s := m.negotiationsData.SelectedSession(); s != nil && s.Escalated && s.Resolution != "" {
+		lines = append(lines, "")
+		lines = append(lines, BoxTitleStyle.Render("Escalation"))
+		lines = append(lines, "  "+s.Resolution)
+		lines = append(lines, MutedStyle.Render("  y/Y 양보나 p 분할로 결정하세요."))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderEventsView() string {
+	var lines []string
+	d := m.eventsData
+	visible := d.Visible()
+
+	lines = append(lines, BoldStyle.Render("Cluster Events"))
+	lines = append(lines, "")
+
+	state := StatusIcon("online") + " Live"
+	if d.Paused {
+		state = StatusIcon("syncing") + fmt.Sprintf(" 일시정지 (새 이벤트 %d개)", len(d.Pending))
+	}
+	filter := "전체"
+	if d.AgentFilter != "" {
+		filter = d.AgentFilter
+	}
+	lines = append(lines, fmt.Sprintf("%s  Events: %d  Agent: %s  (p 일시정지, f/F 필터, Enter 이동)",
+		state, len(visible), filter))
+	lines = append(lines, "")
+
+	// 테이블 헤더
+	lines = append(lines, TableHeaderStyle.Render(
+		fmt.Sprintf("  %-8s %-24s %-16s %s", "TIME", "TYPE", "AGENT", "SUMMARY")))
+	lines = append(lines, strings.Repeat("─", 90))
+
+	if len(visible) == 0 {
+		lines = append(lines, MutedStyle.Render("  아직 이벤트가 없습니다."))
+		return strings.Join(lines, "\n")
+	}
+
+	// 선택된 이벤트가 보이도록 스크롤 (기본은 최신 이벤트)
+	rows := max(m.eventsView.Height-8, 5)
+	start := max(len(visible)-rows, 0)
+	if d.SelectedIndex < start {
+		start = d.SelectedIndex
+	}
+	end := min(start+rows, len(visible))
+
+	for i := start; i < end; i++ {
+		e := visible[i]
+		prefix := "  "
+		style := lipgloss.NewStyle()
+		if i == d.SelectedIndex {
+			prefix = "▸ "
+			style = TableSelectedStyle
+		}
+		line := fmt.Sprintf("%s%-8s %-24s %-16s %s",
+			prefix, e.Time.Format("15:04:05"), e.Type, e.Agent, e.Summary)
+		lines = append(lines, style.Render(line))
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -707,6 +886,22 @@ func (m Model) renderTokensView() string {
 	}
 	lines = append(lines, "")
 
+	// 모델별 비용
+	if len(m.tokensData.ByModel) > 0 {
+		lines = append(lines, BoxTitleStyle.Render("Cost by Model"))
+		lines = append(lines, "")
+		for _, c := range m.tokensData.ByModel {
+			name := c.Model
+			if c.Provider != "" {
+				name = c.Provider + "/" + c.Model
+			}
+			lines = append(lines, fmt.Sprintf("  %-32s in %s  out %s  $%.4f",
+				name, formatNumber(c.InputTokens),
+				formatNumber(c.OutputTokens), c.Cost))
+		}
+		lines = append(lines, "")
+	}
+
 	// 요약
 	lines = append(lines, BoxTitleStyle.Render("Period Summary"))
 	lines = append(lines, fmt.Sprintf("  Today      : %s tokens     Est. $%.2f",
@@ -737,9 +932,9 @@ func (m Model) renderPeersView() string {
 
 	// 테이블 헤더
 	lines = append(lines, TableHeaderStyle.Render(
-		fmt.Sprintf("  %-8s %-10s %-15s %-10s %8s  %s",
-			"STATUS", "NAME", "PEER ID", "TRANSPORT", "LATENCY", "SYNC")))
-	lines = append(lines, strings.Repeat("─", 70))
+		fmt.Sprintf("  %-8s %-10s %-15s %-10s %8s %6s %5s  %s",
+			"STATUS", "NAME", "PEER ID", "TRANSPORT", "LATENCY", "LOSS", "SCORE", "DROP/RETRY")))
+	lines = append(lines, strings.Repeat("─", 84))
 
 	// Peer 목록
 	for i, p := range m.peersData.Peers {
@@ -751,8 +946,12 @@ func (m Model) renderPeersView() string {
 			style = TableSelectedStyle
 		}
 
-		line := fmt.Sprintf("%s%s    %-10s %-15s %-10s %6dms  %.0f%%",
-			prefix, StatusIcon(p.Status), p.Name, p.ID, p.Transport, p.Latency, p.SyncPct)
+		score := "-"
+		if p.Score >= 0 {
+			score = fmt.Sprintf("%.2f", p.Score)
+		}
+		line := fmt.Sprintf("%s%s    %-10s %-15s %-10s %6dms %5.1f%% %5s  %d/%d",
+			prefix, StatusIcon(p.Status), p.Name, p.ID, p.Transport, p.Latency, p.LossPct, score, p.Drops, p.Retries)
 		lines = append(lines, style.Render(line))
 	}
 